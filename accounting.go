@@ -0,0 +1,195 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/roasbeef/btcutil"
+)
+
+// The types of the entries within the accounting report.
+const (
+	accountingInvoice = "invoice"
+	accountingPayment = "payment"
+	accountingForward = "forward"
+	accountingOnChain = "onchain"
+)
+
+// accountingEntry is a single entry within the accounting report, recording a
+// change to the node's balance. The principal moved and the fees involved are
+// kept apart, so the fees may be booked separately.
+type accountingEntry struct {
+	// timestamp is the time the change to the balance took effect.
+	timestamp time.Time
+
+	// entryType is one of accountingInvoice, accountingPayment,
+	// accountingForward, or accountingOnChain.
+	entryType string
+
+	// amount is the change to the node's balance, excluding fees. It's
+	// positive for funds received, and negative for funds sent.
+	amount btcutil.Amount
+
+	// fee is the fee earned by the node if positive, or paid by the node
+	// if negative.
+	fee btcutil.Amount
+
+	// reference identifies the record the entry is drawn from: the payment
+	// hash of invoices, payments, and forwards, or the txid of on-chain
+	// transactions.
+	reference string
+
+	// description is a human readable description of the entry, such as
+	// the memo of an invoice.
+	description string
+}
+
+// accountingRecords are the records of the node from which the accounting
+// report is drawn.
+type accountingRecords struct {
+	invoices []*channeldb.Invoice
+	payments []*channeldb.OutgoingPayment
+	forwards []*channeldb.ForwardingEvent
+	txns     []*lnwallet.TransactionDetail
+}
+
+// fetchAccountingRecords fetches the records the accounting report is drawn
+// from out of the database and the wallet.
+func fetchAccountingRecords(db *channeldb.DB,
+	wallet *lnwallet.LightningWallet) (*accountingRecords, error) {
+
+	var (
+		records = &accountingRecords{}
+		err     error
+	)
+
+	records.invoices, err = db.FetchAllInvoices(false)
+	if err != nil && err != channeldb.ErrNoInvoicesCreated {
+		return nil, err
+	}
+	records.payments, err = db.FetchAllPayments()
+	if err != nil && err != channeldb.ErrNoPaymentsCreated {
+		return nil, err
+	}
+	records.forwards, err = db.FetchForwardingLog()
+	if err != nil {
+		return nil, err
+	}
+	records.txns, err = wallet.ListTransactionDetails()
+	if err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+// accountingReport draws the accounting report for the passed time range from
+// the node's records, ordered by time. Either bound of the range may be zero,
+// leaving the range unbounded on that side. The report covers settled
+// invoices, completed payments, the fees earned by forwards, and confirmed
+// on-chain transactions of the wallet along with the fees they paid.
+func accountingReport(records *accountingRecords,
+	start, end time.Time) []*accountingEntry {
+
+	inRange := func(t time.Time) bool {
+		return (start.IsZero() || !t.Before(start)) &&
+			(end.IsZero() || !t.After(end))
+	}
+
+	var entries []*accountingEntry
+	for _, invoice := range records.invoices {
+		if !invoice.Terms.Settled {
+			continue
+		}
+
+		// Invoices settled before settle dates were recorded are
+		// booked at the time they were created.
+		timestamp := invoice.SettleDate
+		if timestamp.IsZero() {
+			timestamp = invoice.CreationDate
+		}
+		if !inRange(timestamp) {
+			continue
+		}
+
+		paymentHash := sha256.Sum256(invoice.Terms.PaymentPreimage[:])
+		entries = append(entries, &accountingEntry{
+			timestamp:   timestamp,
+			entryType:   accountingInvoice,
+			amount:      invoice.Terms.Value,
+			reference:   hex.EncodeToString(paymentHash[:]),
+			description: string(invoice.Memo),
+		})
+	}
+
+	for _, payment := range records.payments {
+		if !inRange(payment.CreationDate) {
+			continue
+		}
+
+		entries = append(entries, &accountingEntry{
+			timestamp:   payment.CreationDate,
+			entryType:   accountingPayment,
+			amount:      -payment.Terms.Value,
+			fee:         -payment.Fee,
+			reference:   hex.EncodeToString(payment.PaymentHash[:]),
+			description: string(payment.Memo),
+		})
+	}
+
+	for _, event := range records.forwards {
+		if !inRange(event.Timestamp) {
+			continue
+		}
+
+		entries = append(entries, &accountingEntry{
+			timestamp: event.Timestamp,
+			entryType: accountingForward,
+			fee:       event.AmtIn - event.AmtOut,
+			reference: hex.EncodeToString(event.PaymentHash[:]),
+			description: fmt.Sprintf("%v -> %v",
+				event.IncomingChanPoint, event.OutgoingChanPoint),
+		})
+	}
+
+	for _, txn := range records.txns {
+		if txn.NumConfirmations == 0 {
+			continue
+		}
+		timestamp := time.Unix(txn.Timestamp, 0)
+		if !inRange(timestamp) {
+			continue
+		}
+
+		// The wallet's balance changes by the principal along with the
+		// fee, which is only known, and only paid by us, if the wallet
+		// funded all of the transaction's inputs.
+		fee := btcutil.Amount(txn.TotalFees)
+		entries = append(entries, &accountingEntry{
+			timestamp: timestamp,
+			entryType: accountingOnChain,
+			amount:    txn.Value + fee,
+			fee:       -fee,
+			reference: txn.Hash.String(),
+		})
+	}
+
+	sort.Stable(accountingEntriesByTime(entries))
+
+	return entries
+}
+
+// accountingEntriesByTime implements sort.Interface to order accounting
+// entries from the earliest to the latest.
+type accountingEntriesByTime []*accountingEntry
+
+func (a accountingEntriesByTime) Len() int      { return len(a) }
+func (a accountingEntriesByTime) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
+func (a accountingEntriesByTime) Less(i, j int) bool {
+	return a[i].timestamp.Before(a[j].timestamp)
+}
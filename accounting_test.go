@@ -0,0 +1,126 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcutil"
+)
+
+// TestAccountingReport tests that the accounting report splits each record
+// into the principal moved and the fee earned or paid, skips unsettled and
+// unconfirmed records, and only covers the requested time range.
+func TestAccountingReport(t *testing.T) {
+	base := time.Unix(1500000000, 0)
+	at := func(hours int) time.Time {
+		return base.Add(time.Duration(hours) * time.Hour)
+	}
+
+	records := &accountingRecords{
+		invoices: []*channeldb.Invoice{
+			{
+				Memo:         []byte("coffee"),
+				CreationDate: at(0),
+				SettleDate:   at(2),
+				Terms: channeldb.ContractTerm{
+					Value:   1000,
+					Settled: true,
+				},
+			},
+			// An invoice settled before settle dates were
+			// recorded is booked at its creation.
+			{
+				CreationDate: at(1),
+				Terms: channeldb.ContractTerm{
+					Value:   2000,
+					Settled: true,
+				},
+			},
+			{
+				CreationDate: at(1),
+				Terms: channeldb.ContractTerm{
+					Value: 3000,
+				},
+			},
+		},
+		payments: []*channeldb.OutgoingPayment{
+			{
+				Invoice: channeldb.Invoice{
+					CreationDate: at(3),
+					Terms: channeldb.ContractTerm{
+						Value: 5000,
+					},
+				},
+				Fee: 10,
+			},
+		},
+		forwards: []*channeldb.ForwardingEvent{
+			{
+				Timestamp: at(4),
+				AmtIn:     10005,
+				AmtOut:    10000,
+			},
+		},
+		txns: []*lnwallet.TransactionDetail{
+			{
+				Hash:             chainhash.Hash{1},
+				Value:            -20300,
+				NumConfirmations: 1,
+				Timestamp:        at(5).Unix(),
+				TotalFees:        300,
+			},
+			{
+				Hash:      chainhash.Hash{2},
+				Value:     40000,
+				Timestamp: at(5).Unix(),
+			},
+		},
+	}
+
+	type expectedEntry struct {
+		entryType string
+		amount    btcutil.Amount
+		fee       btcutil.Amount
+	}
+	expected := []expectedEntry{
+		{accountingInvoice, 2000, 0},
+		{accountingInvoice, 1000, 0},
+		{accountingPayment, -5000, -10},
+		{accountingForward, 0, 5},
+		{accountingOnChain, -20000, -300},
+	}
+
+	entries := accountingReport(records, time.Time{}, time.Time{})
+	if len(entries) != len(expected) {
+		t.Fatalf("expected %v entries, got %v", len(expected),
+			len(entries))
+	}
+	for i, entry := range entries {
+		exp := expected[i]
+		if entry.entryType != exp.entryType ||
+			entry.amount != exp.amount || entry.fee != exp.fee {
+
+			t.Fatalf("entry #%v: expected %v, got %v", i, exp,
+				*entry)
+		}
+	}
+	if entries[1].description != "coffee" {
+		t.Fatalf("expected invoice memo as description, got %q",
+			entries[1].description)
+	}
+
+	// Restricting the range should only leave the entries within it.
+	entries = accountingReport(records, at(2), at(4))
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries within range, got %v",
+			len(entries))
+	}
+	for _, entry := range entries {
+		if entry.timestamp.Before(at(2)) || entry.timestamp.After(at(4)) {
+			t.Fatalf("entry outside of range: %v", *entry)
+		}
+	}
+}
@@ -0,0 +1,357 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcutil"
+	"golang.org/x/crypto/ripemd160"
+)
+
+const (
+	// asyncPaymentsFeature is the name of the local feature advertised by
+	// nodes which either hold HTLCs for offline recipients, or are
+	// recipients of asynchronous payments themselves.
+	asyncPaymentsFeature = "async-payments"
+
+	// asyncPaymentsFeatureIndex is the index of asyncPaymentsFeature
+	// within our local feature vector.
+	asyncPaymentsFeatureIndex = 4
+
+	// asyncExpiryDelta is the number of blocks before the expiry of a
+	// held HTLC at which it's failed back, leaving the upstream peers
+	// time to fail their own HTLCs before they expire.
+	asyncExpiryDelta = 6
+
+	// asyncCheckInterval is how often held HTLCs are checked for expiry.
+	asyncCheckInterval = 30 * time.Second
+)
+
+// asyncPaymentConfig defines the options of asynchronous payments, which
+// allow payments to recipients which are temporarily offline.
+type asyncPaymentConfig struct {
+	Hold        bool          `long:"hold" description:"Hold HTLCs destined to peers which have asked us to while they're offline, forwarding them once the peer reconnects"`
+	HoldTimeout time.Duration `long:"holdtimeout" description:"The longest an HTLC is held for an offline recipient before it's failed back"`
+	MaxHeld     int           `long:"maxheld" description:"The most HTLCs held for a single offline recipient at once"`
+
+	Holders []string `long:"holder" description:"The hex encoded public key of a peer which should hold HTLCs destined to us while we're offline. May be specified multiple times"`
+
+	// holderKeys are the parsed public keys of Holders.
+	holderKeys []*btcec.PublicKey
+}
+
+// validate checks the async payment options for consistency, and parses the
+// designated holders.
+func (c *asyncPaymentConfig) validate() error {
+	if c.Hold {
+		if c.HoldTimeout <= 0 {
+			return fmt.Errorf("async.holdtimeout must be positive")
+		}
+		if c.MaxHeld <= 0 {
+			return fmt.Errorf("async.maxheld must be positive")
+		}
+	}
+
+	c.holderKeys = make([]*btcec.PublicKey, 0, len(c.Holders))
+	for _, holder := range c.Holders {
+		keyBytes, err := hex.DecodeString(holder)
+		if err != nil {
+			return fmt.Errorf("invalid async.holder %v: %v", holder,
+				err)
+		}
+		key, err := btcec.ParsePubKey(keyBytes, btcec.S256())
+		if err != nil {
+			return fmt.Errorf("invalid async.holder %v: %v", holder,
+				err)
+		}
+		c.holderKeys = append(c.holderKeys, key)
+	}
+
+	return nil
+}
+
+// enabled returns true if we either hold HTLCs for others, or ask others to
+// hold HTLCs for us.
+func (c *asyncPaymentConfig) enabled() bool {
+	return c.Hold || len(c.holderKeys) != 0
+}
+
+// isHolder returns true if the passed peer was designated to hold HTLCs on
+// our behalf.
+func (c *asyncPaymentConfig) isHolder(peer *btcec.PublicKey) bool {
+	for _, key := range c.holderKeys {
+		if key.IsEqual(peer) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// heldHTLC is an HTLC held on behalf of an offline recipient.
+type heldHTLC struct {
+	pkt *htlcPacket
+
+	// deadline is the time after which the HTLC is failed back if the
+	// recipient hasn't reconnected.
+	deadline time.Time
+
+	// expiryHeight is the height at which the HTLC is failed back if the
+	// recipient hasn't reconnected, in order to fail it back before it
+	// expires.
+	expiryHeight int32
+}
+
+// asyncPaymentHolder holds the HTLCs destined to offline recipients which
+// have asked us to, rather than failing them immediately. Once a recipient
+// reconnects and releases its HTLCs, they're handed back to the switch to be
+// forwarded. HTLCs are failed back if the recipient fails to return before
+// the hold timeout, or the HTLC nears its expiry.
+//
+// NOTE: Held HTLCs aren't persisted, so any HTLCs held at shutdown are
+// dropped. As the circuit was never created, the upstream peer fails the
+// HTLC once it expires.
+type asyncPaymentHolder struct {
+	started uint32 // To be used atomically.
+	stopped uint32 // To be used atomically.
+
+	cfg *asyncPaymentConfig
+
+	// bestHeight returns the height of the current best block.
+	bestHeight func() (int32, error)
+
+	// resubmit hands a released HTLC back to the switch to be forwarded.
+	resubmit func(*htlcPacket)
+
+	// failBack fails a held HTLC back to the link it arrived over,
+	// returning false if the link isn't currently active.
+	failBack func(*htlcPacket, lnwire.FailCode) bool
+
+	// recipients is the set of peers, identified by the hash160 of their
+	// public key as used within onion packets, which have asked us to
+	// hold HTLCs on their behalf.
+	recipients map[[ripemd160.Size]byte]struct{}
+
+	// held maps each recipient to the HTLCs currently held for it.
+	held map[[ripemd160.Size]byte][]*heldHTLC
+
+	sync.Mutex
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newAsyncPaymentHolder creates a new holder of HTLCs destined to offline
+// recipients.
+func newAsyncPaymentHolder(cfg *asyncPaymentConfig,
+	bestHeight func() (int32, error), resubmit func(*htlcPacket),
+	failBack func(*htlcPacket, lnwire.FailCode) bool) *asyncPaymentHolder {
+
+	return &asyncPaymentHolder{
+		cfg:        cfg,
+		bestHeight: bestHeight,
+		resubmit:   resubmit,
+		failBack:   failBack,
+		recipients: make(map[[ripemd160.Size]byte]struct{}),
+		held:       make(map[[ripemd160.Size]byte][]*heldHTLC),
+		quit:       make(chan struct{}),
+	}
+}
+
+// Start launches the expiry checks of held HTLCs, if holding is enabled.
+func (a *asyncPaymentHolder) Start() error {
+	if !atomic.CompareAndSwapUint32(&a.started, 0, 1) {
+		return nil
+	}
+
+	if a.cfg.Hold {
+		a.wg.Add(1)
+		go a.expiryChecker()
+	}
+
+	return nil
+}
+
+// Stop halts the expiry checks of held HTLCs.
+func (a *asyncPaymentHolder) Stop() error {
+	if !atomic.CompareAndSwapUint32(&a.stopped, 0, 1) {
+		return nil
+	}
+
+	close(a.quit)
+	a.wg.Wait()
+
+	return nil
+}
+
+// onionID returns the identifier of the passed peer as used within onion
+// packets.
+func onionID(peer *btcec.PublicKey) [ripemd160.Size]byte {
+	var id [ripemd160.Size]byte
+	copy(id[:], btcutil.Hash160(peer.SerializeCompressed()))
+	return id
+}
+
+// releaseHeld handles a request from the passed recipient to release the
+// HTLCs held on its behalf. The recipient is also registered so HTLCs are
+// held for it once it goes offline again.
+func (a *asyncPaymentHolder) releaseHeld(recipient *btcec.PublicKey) {
+	if !a.cfg.Hold {
+		hswcLog.Debugf("Ignoring request of %x to release held HTLCs, "+
+			"as holding is disabled",
+			recipient.SerializeCompressed())
+		return
+	}
+
+	id := onionID(recipient)
+
+	a.Lock()
+	a.recipients[id] = struct{}{}
+	released := a.held[id]
+	delete(a.held, id)
+	a.Unlock()
+
+	if len(released) == 0 {
+		return
+	}
+
+	hswcLog.Infof("Releasing %v held HTLCs to %x", len(released),
+		recipient.SerializeCompressed())
+
+	for _, htlc := range released {
+		a.resubmit(htlc.pkt)
+	}
+}
+
+// hold attempts to hold the passed HTLC, which couldn't be forwarded as the
+// next hop is offline. It returns false if the next hop hasn't asked us to
+// hold HTLCs for it, or the HTLC can't be held, in which case it should be
+// failed as usual.
+func (a *asyncPaymentHolder) hold(nextHop [ripemd160.Size]byte,
+	pkt *htlcPacket) bool {
+
+	if !a.cfg.Hold {
+		return false
+	}
+
+	a.Lock()
+	defer a.Unlock()
+
+	if _, ok := a.recipients[nextHop]; !ok {
+		return false
+	}
+	if len(a.held[nextHop]) >= a.cfg.MaxHeld {
+		hswcLog.Warnf("Already holding %v HTLCs for %x, not holding "+
+			"any more", len(a.held[nextHop]), nextHop)
+		return false
+	}
+
+	// The HTLC must be failed back a safe distance before it expires, so
+	// one which is about to expire isn't held at all.
+	htlc := pkt.msg.(*lnwire.UpdateAddHTLC)
+	if htlc.Expiry <= asyncExpiryDelta {
+		return false
+	}
+	height, err := a.bestHeight()
+	if err != nil {
+		hswcLog.Errorf("unable to fetch best height: %v", err)
+		return false
+	}
+
+	a.held[nextHop] = append(a.held[nextHop], &heldHTLC{
+		pkt:          pkt,
+		deadline:     time.Now().Add(a.cfg.HoldTimeout),
+		expiryHeight: height + int32(htlc.Expiry) - asyncExpiryDelta,
+	})
+
+	hswcLog.Infof("Holding HTLC %x of %v for offline recipient %x",
+		htlc.PaymentHash[:], htlc.Amount, nextHop)
+
+	return true
+}
+
+// isHeld returns true if an HTLC with the passed payment hash is currently
+// held on behalf of an offline recipient.
+func (a *asyncPaymentHolder) isHeld(rHash [32]byte) bool {
+	a.Lock()
+	defer a.Unlock()
+
+	for _, htlcs := range a.held {
+		for _, htlc := range htlcs {
+			add := htlc.pkt.msg.(*lnwire.UpdateAddHTLC)
+			if add.PaymentHash == rHash {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// expiryChecker periodically fails back any held HTLCs which have passed
+// their deadline or expiry height.
+//
+// NOTE: This MUST be run as a goroutine.
+func (a *asyncPaymentHolder) expiryChecker() {
+	defer a.wg.Done()
+
+	ticker := time.NewTicker(asyncCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			height, err := a.bestHeight()
+			if err != nil {
+				hswcLog.Errorf("unable to fetch best height: %v",
+					err)
+				continue
+			}
+			a.failExpired(time.Now(), height)
+
+		case <-a.quit:
+			return
+		}
+	}
+}
+
+// failExpired fails back each held HTLC which has passed its deadline or
+// expiry height, given the current time and height.
+func (a *asyncPaymentHolder) failExpired(now time.Time, height int32) {
+	a.Lock()
+	defer a.Unlock()
+
+	for id, htlcs := range a.held {
+		remaining := htlcs[:0]
+		for _, htlc := range htlcs {
+			if now.Before(htlc.deadline) &&
+				height < htlc.expiryHeight {
+
+				remaining = append(remaining, htlc)
+				continue
+			}
+
+			// If the HTLC's incoming link is currently inactive,
+			// then we'll retry once it's back.
+			if !a.failBack(htlc.pkt, lnwire.UpstreamTimeout) {
+				remaining = append(remaining, htlc)
+				continue
+			}
+
+			payHash := htlc.pkt.msg.(*lnwire.UpdateAddHTLC).PaymentHash
+			hswcLog.Infof("Recipient %x didn't return in time, "+
+				"failed back held HTLC %x", id, payHash[:])
+		}
+
+		if len(remaining) == 0 {
+			delete(a.held, id)
+		} else {
+			a.held[id] = remaining
+		}
+	}
+}
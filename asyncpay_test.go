@@ -0,0 +1,115 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/btcec"
+)
+
+func TestAsyncPaymentHolder(t *testing.T) {
+	priv, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+	recipient := priv.PubKey()
+	nextHop := onionID(recipient)
+
+	var (
+		height      int32 = 100
+		resubmitted []*htlcPacket
+		failed      []lnwire.FailCode
+		linkActive  = true
+	)
+	cfg := &asyncPaymentConfig{
+		Hold:        true,
+		HoldTimeout: time.Hour,
+		MaxHeld:     2,
+	}
+	holder := newAsyncPaymentHolder(cfg,
+		func() (int32, error) {
+			return height, nil
+		},
+		func(pkt *htlcPacket) {
+			resubmitted = append(resubmitted, pkt)
+		},
+		func(pkt *htlcPacket, code lnwire.FailCode) bool {
+			if !linkActive {
+				return false
+			}
+			failed = append(failed, code)
+			return true
+		},
+	)
+
+	newPkt := func(expiry uint32) *htlcPacket {
+		return &htlcPacket{
+			msg: &lnwire.UpdateAddHTLC{
+				Amount: 1000,
+				Expiry: expiry,
+			},
+		}
+	}
+
+	// HTLCs aren't held for a peer which hasn't asked us to.
+	if holder.hold(nextHop, newPkt(144)) {
+		t.Fatalf("HTLC held for unregistered recipient")
+	}
+
+	// Once the recipient connects and releases its HTLCs, it's
+	// registered, so HTLCs are held for it up to the limit. An HTLC too
+	// close to its expiry isn't held.
+	holder.releaseHeld(recipient)
+	if holder.hold(nextHop, newPkt(asyncExpiryDelta)) {
+		t.Fatalf("HTLC about to expire was held")
+	}
+	for i := 0; i < cfg.MaxHeld; i++ {
+		if !holder.hold(nextHop, newPkt(144)) {
+			t.Fatalf("HTLC #%v wasn't held", i)
+		}
+	}
+	if holder.hold(nextHop, newPkt(144)) {
+		t.Fatalf("HTLC held beyond the limit")
+	}
+
+	// Upon the recipient's return, the held HTLCs are resubmitted to the
+	// switch.
+	holder.releaseHeld(recipient)
+	if len(resubmitted) != cfg.MaxHeld {
+		t.Fatalf("expected %v resubmitted HTLCs, got %v", cfg.MaxHeld,
+			len(resubmitted))
+	}
+
+	// An HTLC nearing its expiry should be failed back, but only once its
+	// incoming link is active.
+	if !holder.hold(nextHop, newPkt(20)) {
+		t.Fatalf("HTLC wasn't held")
+	}
+	holder.failExpired(time.Now(), height+10)
+	if len(failed) != 0 {
+		t.Fatalf("HTLC failed back before its expiry")
+	}
+	linkActive = false
+	holder.failExpired(time.Now(), height+20-asyncExpiryDelta)
+	if len(failed) != 0 {
+		t.Fatalf("HTLC failed back over inactive link")
+	}
+	linkActive = true
+	holder.failExpired(time.Now(), height+20-asyncExpiryDelta)
+	if len(failed) != 1 || failed[0] != lnwire.UpstreamTimeout {
+		t.Fatalf("expected HTLC to be failed back, got %v", failed)
+	}
+
+	// Likewise, an HTLC held beyond the hold timeout is failed back.
+	if !holder.hold(nextHop, newPkt(144)) {
+		t.Fatalf("HTLC wasn't held")
+	}
+	holder.failExpired(time.Now().Add(2*cfg.HoldTimeout), height)
+	if len(failed) != 2 {
+		t.Fatalf("expected HTLC to be failed back after timeout")
+	}
+	if len(holder.held) != 0 {
+		t.Fatalf("expected no held HTLCs, got %v", len(holder.held))
+	}
+}
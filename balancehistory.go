@@ -0,0 +1,151 @@
+package main
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/roasbeef/btcutil"
+)
+
+// balanceHistory periodically records a snapshot of the node's on-chain and
+// channel balances within the database, so the node's equity can be charted
+// over time without polling it externally.
+type balanceHistory struct {
+	started uint32 // To be used atomically.
+	stopped uint32 // To be used atomically.
+
+	// interval is the time between snapshots. A value of zero disables
+	// the snapshots.
+	interval time.Duration
+
+	db     *channeldb.DB
+	wallet *lnwallet.LightningWallet
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newBalanceHistory creates a new balanceHistory which records a snapshot of
+// the balances of the passed wallet and database each interval.
+func newBalanceHistory(interval time.Duration, db *channeldb.DB,
+	wallet *lnwallet.LightningWallet) *balanceHistory {
+
+	return &balanceHistory{
+		interval: interval,
+		db:       db,
+		wallet:   wallet,
+		quit:     make(chan struct{}),
+	}
+}
+
+// Start launches the snapshot scheduler, if the operator has enabled it.
+func (b *balanceHistory) Start() error {
+	if !atomic.CompareAndSwapUint32(&b.started, 0, 1) {
+		return nil
+	}
+
+	if b.interval != 0 {
+		ltndLog.Infof("Recording balance snapshots every %v",
+			b.interval)
+
+		b.wg.Add(1)
+		go b.scheduler()
+	}
+
+	return nil
+}
+
+// Stop halts the snapshot scheduler.
+func (b *balanceHistory) Stop() error {
+	if !atomic.CompareAndSwapUint32(&b.stopped, 0, 1) {
+		return nil
+	}
+
+	close(b.quit)
+	b.wg.Wait()
+
+	return nil
+}
+
+// scheduler records a snapshot at start up, then each time the interval
+// passes.
+//
+// NOTE: This MUST be run as a goroutine.
+func (b *balanceHistory) scheduler() {
+	defer b.wg.Done()
+
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := b.recordSnapshot(); err != nil {
+			ltndLog.Errorf("unable to record balance snapshot: %v",
+				err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-b.quit:
+			return
+		}
+	}
+}
+
+// recordSnapshot records the node's current balances within the database.
+func (b *balanceHistory) recordSnapshot() error {
+	snapshot, err := b.snapshot()
+	if err != nil {
+		return err
+	}
+
+	ltndLog.Debugf("Recording balance snapshot: on_chain=%v, "+
+		"unconfirmed=%v, local=%v, remote=%v, pending_open=%v",
+		snapshot.OnChainBalance, snapshot.UnconfirmedBalance,
+		snapshot.LocalBalance, snapshot.RemoteBalance,
+		snapshot.PendingOpenBalance)
+
+	return b.db.AddBalanceSnapshot(snapshot)
+}
+
+// snapshot gathers the node's current balances.
+func (b *balanceHistory) snapshot() (*channeldb.BalanceSnapshot, error) {
+	confirmed, err := b.wallet.ConfirmedBalance(1, false)
+	if err != nil {
+		return nil, err
+	}
+	total, err := b.wallet.ConfirmedBalance(0, false)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &channeldb.BalanceSnapshot{
+		Timestamp:          time.Now(),
+		OnChainBalance:     confirmed,
+		UnconfirmedBalance: total - confirmed,
+	}
+
+	channels, err := b.db.FetchAllChannels()
+	if err != nil && err != channeldb.ErrNoActiveChannels {
+		return nil, err
+	}
+	for _, channel := range channels {
+		if channel.IsPending {
+			snapshot.PendingOpenBalance += channel.OurBalance
+			continue
+		}
+
+		snapshot.LocalBalance += channel.OurBalance
+		snapshot.RemoteBalance += channel.TheirBalance
+	}
+
+	return snapshot, nil
+}
+
+// totalEquity returns the node's total balance within the passed snapshot.
+func totalEquity(s *channeldb.BalanceSnapshot) btcutil.Amount {
+	return s.OnChainBalance + s.UnconfirmedBalance + s.LocalBalance +
+		s.PendingOpenBalance
+}
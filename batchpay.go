@@ -0,0 +1,123 @@
+package main
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/lightningnetwork/lnd/routing"
+	"github.com/roasbeef/btcutil"
+)
+
+const (
+	// defaultBatchConcurrency is the number of payments within a batch
+	// which are dispatched concurrently if the client doesn't specify a
+	// limit.
+	defaultBatchConcurrency = 4
+
+	// maxBatchConcurrency is the maximum number of payments within a batch
+	// which may be dispatched concurrently. Each payment occupies an HTLC
+	// slot within our channels while it's in flight, so large batches are
+	// throttled to avoid exhausting them.
+	maxBatchConcurrency = 16
+)
+
+// errFeeBudgetExhausted is returned when a payment within a batch can't be
+// dispatched as the fees of prior payments have consumed the entire fee
+// budget of the batch.
+var errFeeBudgetExhausted = errors.New("fee budget of batch exhausted")
+
+// batchConcurrency returns the number of payments within a batch to dispatch
+// concurrently, given the limit requested by the client.
+func batchConcurrency(requested uint32) int {
+	switch {
+	case requested == 0:
+		return defaultBatchConcurrency
+	case requested > maxBatchConcurrency:
+		return maxBatchConcurrency
+	default:
+		return int(requested)
+	}
+}
+
+// feeBudget tracks the fees which remain available to the payments of a batch.
+// Each payment reserves an equal share of the remaining budget before it's
+// dispatched, and any portion of its share it didn't spend is returned to the
+// budget once it completes, to be shared among the payments which follow. A
+// nil budget places no limit on the fees of the batch.
+type feeBudget struct {
+	sync.Mutex
+
+	// remaining is the portion of the budget which is neither spent, nor
+	// reserved by a payment in flight.
+	remaining btcutil.Amount
+
+	// unscheduled is the number of payments yet to reserve their share.
+	unscheduled int
+}
+
+// newFeeBudget creates a budget of the passed total to be shared between the
+// passed number of payments.
+func newFeeBudget(total btcutil.Amount, numPayments int) *feeBudget {
+	return &feeBudget{
+		remaining:   total,
+		unscheduled: numPayments,
+	}
+}
+
+// reserve sets aside the share of the budget of the next payment to be
+// dispatched. As a fee limit of zero places no limit at all on a payment,
+// the share is at least a single satoshi, and errFeeBudgetExhausted is
+// returned if not even that remains.
+func (b *feeBudget) reserve() (btcutil.Amount, error) {
+	if b == nil {
+		return 0, nil
+	}
+
+	b.Lock()
+	defer b.Unlock()
+
+	b.unscheduled--
+	if b.remaining <= 0 {
+		return 0, errFeeBudgetExhausted
+	}
+
+	share := b.remaining / btcutil.Amount(b.unscheduled+1)
+	if share == 0 {
+		share = 1
+	}
+	b.remaining -= share
+
+	return share, nil
+}
+
+// release returns the unspent portion of a payment's reserved share to the
+// budget.
+func (b *feeBudget) release(reserved, spent btcutil.Amount) {
+	if b == nil {
+		return
+	}
+
+	b.Lock()
+	defer b.Unlock()
+
+	b.remaining += reserved - spent
+}
+
+// applyBatchLimits restricts the passed payment to the fee share it reserved
+// from its batch's budget, and to the batch's time lock limit, unless the
+// payment's own limits are already tighter. A zero value for either leaves
+// the respective limit of the payment in place.
+func applyBatchLimits(payment *routing.LightningPayment, feeShare btcutil.Amount,
+	cltvLimit uint32) {
+
+	if feeShare != 0 &&
+		(payment.FeeLimit == 0 || feeShare < payment.FeeLimit) {
+
+		payment.FeeLimit = feeShare
+	}
+	if cltvLimit != 0 &&
+		(payment.CltvLimit == 0 || cltvLimit < payment.CltvLimit) {
+
+		payment.CltvLimit = cltvLimit
+	}
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/lightningnetwork/lnd/routing"
+	"github.com/roasbeef/btcutil"
+)
+
+// TestFeeBudget tests that the payments of a batch each reserve an equal share
+// of the remaining fee budget, with any unspent portion of a share made
+// available to the payments which follow.
+func TestFeeBudget(t *testing.T) {
+	budget := newFeeBudget(900, 3)
+
+	// The first two payments are dispatched concurrently, each reserving
+	// a third of the budget.
+	first, err := budget.reserve()
+	if err != nil {
+		t.Fatalf("unable to reserve share: %v", err)
+	}
+	second, err := budget.reserve()
+	if err != nil {
+		t.Fatalf("unable to reserve share: %v", err)
+	}
+	if first != 300 || second != 300 {
+		t.Fatalf("expected shares of 300, got %v and %v", first, second)
+	}
+
+	// The first payment only spends 100 of its share, so the remainder is
+	// available to the final payment.
+	budget.release(first, 100)
+	third, err := budget.reserve()
+	if err != nil {
+		t.Fatalf("unable to reserve share: %v", err)
+	}
+	if third != 500 {
+		t.Fatalf("expected share of 500, got %v", third)
+	}
+
+	// Once the budget has been entirely spent, further payments are
+	// refused rather than being dispatched without a fee limit.
+	budget = newFeeBudget(1, 2)
+	share, err := budget.reserve()
+	if err != nil {
+		t.Fatalf("unable to reserve share: %v", err)
+	}
+	if share != 1 {
+		t.Fatalf("expected share of 1, got %v", share)
+	}
+	budget.release(share, 1)
+	if _, err := budget.reserve(); err != errFeeBudgetExhausted {
+		t.Fatalf("expected errFeeBudgetExhausted, got: %v", err)
+	}
+
+	// A nil budget places no limit on fees.
+	var unlimited *feeBudget
+	share, err = unlimited.reserve()
+	if err != nil || share != 0 {
+		t.Fatalf("expected no limit, got share %v: %v", share, err)
+	}
+}
+
+// TestApplyBatchLimits tests that the limits of a batch only override the
+// limits of a payment when they're tighter.
+func TestApplyBatchLimits(t *testing.T) {
+	tests := []struct {
+		feeLimit  btcutil.Amount
+		cltvLimit uint32
+		feeShare  btcutil.Amount
+		batchCltv uint32
+
+		expectedFee  btcutil.Amount
+		expectedCltv uint32
+	}{
+		// Without limits of its own, the payment takes on those of
+		// the batch.
+		{0, 0, 100, 144, 100, 144},
+
+		// A batch without limits leaves the payment's in place.
+		{50, 432, 0, 0, 50, 432},
+
+		// The tighter of each limit applies.
+		{50, 144, 100, 432, 50, 144},
+		{200, 1008, 100, 432, 100, 432},
+	}
+
+	for i, test := range tests {
+		payment := &routing.LightningPayment{
+			FeeLimit:  test.feeLimit,
+			CltvLimit: test.cltvLimit,
+		}
+		applyBatchLimits(payment, test.feeShare, test.batchCltv)
+
+		if payment.FeeLimit != test.expectedFee {
+			t.Fatalf("test #%v: expected fee limit %v, got %v", i,
+				test.expectedFee, payment.FeeLimit)
+		}
+		if payment.CltvLimit != test.expectedCltv {
+			t.Fatalf("test #%v: expected cltv limit %v, got %v", i,
+				test.expectedCltv, payment.CltvLimit)
+		}
+	}
+}
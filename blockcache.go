@@ -0,0 +1,153 @@
+package main
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// cacheEntry is a single block or transaction held within the chainCache.
+type cacheEntry struct {
+	hash  chainhash.Hash
+	block *wire.MsgBlock
+	tx    *wire.MsgTx
+	size  int64
+}
+
+// chainCache is an implementation of the lnwallet.BlockChainIO interface
+// which places a least recently used cache of blocks and transactions in
+// front of the chain backend. Blocks and transactions are immutable once
+// identified by their hash, so repeated historical lookups, such as fetching
+// the funding block of each announced channel, are served from memory rather
+// than the backend's RPC interface. Queries which depend on the current state
+// of the chain are always passed through to the backend.
+type chainCache struct {
+	hits   uint64 // atomic
+	misses uint64 // atomic
+
+	lnwallet.BlockChainIO
+
+	// maxSize is the maximum total serialized size in bytes of the cached
+	// blocks and transactions.
+	maxSize int64
+
+	sync.Mutex
+	size    int64
+	entries map[chainhash.Hash]*list.Element
+	lru     *list.List
+}
+
+// A compile time check to ensure that chainCache implements the BlockChainIO
+// interface.
+var _ lnwallet.BlockChainIO = (*chainCache)(nil)
+
+// newChainCache creates a new chainCache in front of the passed backend,
+// holding at most maxSize bytes of blocks and transactions.
+func newChainCache(backend lnwallet.BlockChainIO, maxSize int64) *chainCache {
+	return &chainCache{
+		BlockChainIO: backend,
+		maxSize:      maxSize,
+		entries:      make(map[chainhash.Hash]*list.Element),
+		lru:          list.New(),
+	}
+}
+
+// GetBlock returns the block in the main chain identified by the given hash,
+// from the cache if possible.
+//
+// This method is a part of the lnwallet.BlockChainIO interface.
+func (c *chainCache) GetBlock(blockHash *chainhash.Hash) (*wire.MsgBlock, error) {
+	if entry := c.lookup(blockHash); entry != nil && entry.block != nil {
+		return entry.block, nil
+	}
+
+	block, err := c.BlockChainIO.GetBlock(blockHash)
+	if err != nil {
+		return nil, err
+	}
+
+	c.add(&cacheEntry{
+		hash:  *blockHash,
+		block: block,
+		size:  int64(block.SerializeSize()),
+	})
+
+	return block, nil
+}
+
+// GetTransaction returns the full transaction identified by the passed
+// transaction ID, from the cache if possible.
+//
+// This method is a part of the lnwallet.BlockChainIO interface.
+func (c *chainCache) GetTransaction(txid *chainhash.Hash) (*wire.MsgTx, error) {
+	if entry := c.lookup(txid); entry != nil && entry.tx != nil {
+		return entry.tx, nil
+	}
+
+	tx, err := c.BlockChainIO.GetTransaction(txid)
+	if err != nil {
+		return nil, err
+	}
+
+	c.add(&cacheEntry{
+		hash: *txid,
+		tx:   tx,
+		size: int64(tx.SerializeSize()),
+	})
+
+	return tx, nil
+}
+
+// lookup returns the cached entry for the passed hash, marking it as the most
+// recently used, or nil if the hash isn't cached.
+func (c *chainCache) lookup(hash *chainhash.Hash) *cacheEntry {
+	c.Lock()
+	defer c.Unlock()
+
+	elem, ok := c.entries[*hash]
+	if !ok {
+		atomic.AddUint64(&c.misses, 1)
+		return nil
+	}
+
+	atomic.AddUint64(&c.hits, 1)
+	c.lru.MoveToFront(elem)
+
+	return elem.Value.(*cacheEntry)
+}
+
+// add inserts the passed entry into the cache, evicting the least recently
+// used entries until the cache is within its size bound. Entries larger than
+// the entire cache aren't cached.
+func (c *chainCache) add(entry *cacheEntry) {
+	if entry.size > c.maxSize {
+		return
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	if _, ok := c.entries[entry.hash]; ok {
+		return
+	}
+
+	c.entries[entry.hash] = c.lru.PushFront(entry)
+	c.size += entry.size
+
+	for c.size > c.maxSize {
+		oldest := c.lru.Back()
+		evicted := c.lru.Remove(oldest).(*cacheEntry)
+		delete(c.entries, evicted.hash)
+		c.size -= evicted.size
+	}
+}
+
+// stats returns the number of lookups served from the cache, and the number
+// passed through to the backend.
+func (c *chainCache) stats() (uint64, uint64) {
+	return atomic.LoadUint64(&c.hits), atomic.LoadUint64(&c.misses)
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// mockChainIO is a chain backend which serves a fixed set of blocks, and
+// counts the lookups made of it.
+type mockChainIO struct {
+	lnwallet.BlockChainIO
+
+	blocks  map[chainhash.Hash]*wire.MsgBlock
+	lookups int
+}
+
+func (m *mockChainIO) GetBlock(hash *chainhash.Hash) (*wire.MsgBlock, error) {
+	m.lookups++
+
+	block, ok := m.blocks[*hash]
+	if !ok {
+		return nil, fmt.Errorf("block %v not found", hash)
+	}
+	return block, nil
+}
+
+// TestChainCache tests that repeated lookups of blocks are served from the
+// cache, and that the least recently used blocks are evicted once the cache
+// exceeds its size bound.
+func TestChainCache(t *testing.T) {
+	backend := &mockChainIO{
+		blocks: make(map[chainhash.Hash]*wire.MsgBlock),
+	}
+	hashes := make([]chainhash.Hash, 3)
+	for i := range hashes {
+		block := &wire.MsgBlock{
+			Header: wire.BlockHeader{Nonce: uint32(i)},
+		}
+		hashes[i] = block.BlockHash()
+		backend.blocks[hashes[i]] = block
+	}
+	blockSize := int64(backend.blocks[hashes[0]].SerializeSize())
+
+	// The cache has room for exactly two blocks.
+	cache := newChainCache(backend, blockSize*2)
+
+	fetch := func(i int) {
+		block, err := cache.GetBlock(&hashes[i])
+		if err != nil {
+			t.Fatalf("unable to fetch block: %v", err)
+		}
+		if block != backend.blocks[hashes[i]] {
+			t.Fatalf("wrong block returned for %v", hashes[i])
+		}
+	}
+	assertStats := func(hits, misses uint64, lookups int) {
+		h, m := cache.stats()
+		if h != hits || m != misses {
+			t.Fatalf("expected %v hits and %v misses, got %v and %v",
+				hits, misses, h, m)
+		}
+		if backend.lookups != lookups {
+			t.Fatalf("expected %v backend lookups, got %v", lookups,
+				backend.lookups)
+		}
+	}
+
+	// Only the first lookup of each block reaches the backend.
+	fetch(0)
+	fetch(1)
+	fetch(0)
+	assertStats(1, 2, 2)
+
+	// Adding a third block evicts the least recently used one, which is
+	// then fetched from the backend once more.
+	fetch(2)
+	fetch(0)
+	assertStats(2, 3, 3)
+	fetch(1)
+	assertStats(2, 4, 4)
+
+	// Failed lookups aren't cached.
+	var unknown chainhash.Hash
+	if _, err := cache.GetBlock(&unknown); err == nil {
+		t.Fatalf("expected lookup of unknown block to fail")
+	}
+	if _, err := cache.GetBlock(&unknown); err == nil {
+		t.Fatalf("expected lookup of unknown block to fail")
+	}
+	assertStats(2, 6, 6)
+}
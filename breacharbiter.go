@@ -27,6 +27,11 @@ type breachArbiter struct {
 	db         *channeldb.DB
 	notifier   chainntnfs.ChainNotifier
 	htlcSwitch *htlcSwitch
+	outbox     *notificationOutbox
+
+	// chanHistory records the outcome of each attempt to exact justice
+	// within the timeline of the breached channel.
+	chanHistory *channelHistory
 
 	// breachObservers is a map which tracks all the active breach
 	// observers we're currently managing. The key of the map is the
@@ -62,13 +67,16 @@ type breachArbiter struct {
 // newBreachArbiter creates a new instance of a breachArbiter initialized with
 // its dependent objects.
 func newBreachArbiter(wallet *lnwallet.LightningWallet, db *channeldb.DB,
-	notifier chainntnfs.ChainNotifier, h *htlcSwitch) *breachArbiter {
+	notifier chainntnfs.ChainNotifier, h *htlcSwitch,
+	outbox *notificationOutbox, chanHistory *channelHistory) *breachArbiter {
 
 	return &breachArbiter{
-		wallet:     wallet,
-		db:         db,
-		notifier:   notifier,
-		htlcSwitch: h,
+		wallet:      wallet,
+		db:          db,
+		notifier:    notifier,
+		htlcSwitch:  h,
+		outbox:      outbox,
+		chanHistory: chanHistory,
 
 		breachObservers:   make(map[wire.OutPoint]chan struct{}),
 		breachedContracts: make(chan *retributionInfo),
@@ -283,6 +291,7 @@ func (b *breachArbiter) exactRetribution(confChan *chainntnfs.ConfirmationEvent,
 	justiceTx, err := b.createJusticeTx(breachInfo)
 	if err != nil {
 		brarLog.Errorf("unable to create justice tx: %v", err)
+		b.chanHistory.justiceFailed(breachInfo.contract, err)
 		return
 	}
 
@@ -295,6 +304,7 @@ func (b *breachArbiter) exactRetribution(confChan *chainntnfs.ConfirmationEvent,
 	if err := b.wallet.PublishTransaction(justiceTx); err != nil {
 		brarLog.Errorf("unable to broadcast "+
 			"justice tx: %v", err)
+		b.chanHistory.justiceFailed(breachInfo.contract, err)
 		return
 	}
 
@@ -325,6 +335,9 @@ func (b *breachArbiter) exactRetribution(confChan *chainntnfs.ConfirmationEvent,
 			"have been claimed", breachInfo.chanPoint,
 			revokedFunds, totalFunds)
 
+		b.chanHistory.justiceServed(breachInfo.contract, &justiceTXID,
+			totalFunds)
+
 		// TODO(roasbeef): add peer to blacklist?
 
 		// TODO(roasbeef): close other active channels with offending peer
@@ -368,12 +381,14 @@ func (b *breachArbiter) breachObserver(contract *lnwallet.LightningChannel,
 			"broadcast, REMOTE PEER IS DOING SOMETHING "+
 			"SKETCHY!!!", breachInfo.RevokedStateNum,
 			chanPoint)
+		b.outbox.breachDetected(contract, breachInfo.BreachTransaction,
+			breachInfo.RevokedStateNum)
 
 		// Immediately notify the HTLC switch that this link has been
 		// breached in order to ensure any incoming or outgoing
 		// multi-hop HTLCs aren't sent over this link, nor any other
 		// links associated with this peer.
-		b.htlcSwitch.CloseLink(chanPoint, CloseBreach)
+		b.htlcSwitch.CloseLink(chanPoint, CloseBreach, 0)
 		if err := contract.DeleteState(); err != nil {
 			brarLog.Errorf("unable to delete channel state: %v", err)
 		}
@@ -417,6 +432,7 @@ func (b *breachArbiter) breachObserver(contract *lnwallet.LightningChannel,
 		b.breachedContracts <- &retributionInfo{
 			commitHash: breachInfo.BreachTransaction.TxHash(),
 			chanPoint:  *chanPoint,
+			contract:   contract,
 
 			selfOutput: &breachedOutput{
 				amt:         btcutil.Amount(localSignDesc.Output.Value),
@@ -458,6 +474,10 @@ type retributionInfo struct {
 	commitHash chainhash.Hash
 	chanPoint  wire.OutPoint
 
+	// contract is the breached channel, retained in order to record the
+	// outcome within its timeline.
+	contract *lnwallet.LightningChannel
+
 	selfOutput *breachedOutput
 
 	revokedOutput *breachedOutput
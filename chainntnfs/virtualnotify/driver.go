@@ -0,0 +1,40 @@
+package virtualnotify
+
+import (
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/virtualchain"
+)
+
+// createNewNotifier creates a new instance of the ChainNotifier interface
+// implemented by VirtualNotifier.
+func createNewNotifier(args ...interface{}) (chainntnfs.ChainNotifier, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("incorrect number of arguments to .New(...), "+
+			"expected 1, instead passed %v", len(args))
+	}
+
+	backend, ok := args[0].(virtualchain.Backend)
+	if !ok {
+		return nil, fmt.Errorf("first argument to virtualnotify.New is " +
+			"incorrect, expected a virtualchain.Backend")
+	}
+
+	return New(backend), nil
+}
+
+// init registers a driver for the VirtualNotifier concrete implementation of
+// the chainntnfs.ChainNotifier interface.
+func init() {
+	// Register the driver.
+	notifier := &chainntnfs.NotifierDriver{
+		NotifierType: notifierType,
+		New:          createNewNotifier,
+	}
+
+	if err := chainntnfs.RegisterNotifier(notifier); err != nil {
+		panic(fmt.Sprintf("failed to register notifier driver '%s': %v",
+			notifierType, err))
+	}
+}
@@ -0,0 +1,509 @@
+package virtualnotify
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/virtualchain"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
+)
+
+const (
+	// notifierType uniquely identifies this concrete implementation of the
+	// ChainNotifier interface.
+	notifierType = "virtual"
+
+	// retryInterval is the time we wait before polling the backend for
+	// events again after a failure.
+	retryInterval = time.Second
+)
+
+var (
+	// ErrChainNotifierShuttingDown is returned when a notification is
+	// registered while the notifier is shutting down.
+	ErrChainNotifierShuttingDown = errors.New("chainntnfs: system " +
+		"interrupt while attempting to register for notification")
+)
+
+// confClient is a client waiting for a transaction to reach a number of
+// confirmations.
+type confClient struct {
+	numConfs uint32
+	finConf  chan *chainntnfs.TxConfirmation
+}
+
+// epochClient is a client receiving a notification for each connected block.
+// Unlike btcd's notifier, no notification is ever dropped, as blocks may be
+// mined in large batches within a simulation. Pending notifications are
+// queued, and delivered by a goroutine dedicated to the client.
+type epochClient struct {
+	epochChan chan *chainntnfs.BlockEpoch
+
+	mtx    sync.Mutex
+	queue  []*chainntnfs.BlockEpoch
+	signal chan struct{}
+
+	cancel     chan struct{}
+	cancelOnce sync.Once
+}
+
+// VirtualNotifier implements the ChainNotifier interface on top of a virtual
+// chain. It follows the chain's stream of events, indexing the confirmation
+// of each transaction, and the spend of each output, so notifications for
+// past events are dispatched as soon as they're registered.
+type VirtualNotifier struct {
+	clientCounter uint64 // To be used atomically.
+
+	started int32 // To be used atomically.
+	stopped int32 // To be used atomically.
+
+	backend virtualchain.Backend
+
+	mtx sync.Mutex
+
+	// nextEvent is the index of the next event of the chain to process,
+	// and bestHeight the height of the last block processed.
+	nextEvent  uint64
+	bestHeight int32
+
+	// mempool holds the unconfirmed transactions of the chain.
+	mempool map[chainhash.Hash]*wire.MsgTx
+
+	// confs holds the confirmation of each confirmed transaction, while
+	// spends holds the spend of each output spent, either by a confirmed
+	// transaction, or by one within the mempool.
+	confs  map[chainhash.Hash]*chainntnfs.TxConfirmation
+	spends map[wire.OutPoint]*chainntnfs.SpendDetail
+
+	confClients  map[chainhash.Hash][]*confClient
+	spendClients map[wire.OutPoint]map[uint64]chan *chainntnfs.SpendDetail
+	epochClients map[uint64]*epochClient
+
+	wg   sync.WaitGroup
+	quit chan struct{}
+}
+
+// Ensure VirtualNotifier implements the ChainNotifier interface at compile
+// time.
+var _ chainntnfs.ChainNotifier = (*VirtualNotifier)(nil)
+
+// New returns a new VirtualNotifier following the passed virtual chain.
+func New(backend virtualchain.Backend) *VirtualNotifier {
+	return &VirtualNotifier{
+		backend:      backend,
+		mempool:      make(map[chainhash.Hash]*wire.MsgTx),
+		confs:        make(map[chainhash.Hash]*chainntnfs.TxConfirmation),
+		spends:       make(map[wire.OutPoint]*chainntnfs.SpendDetail),
+		confClients:  make(map[chainhash.Hash][]*confClient),
+		spendClients: make(map[wire.OutPoint]map[uint64]chan *chainntnfs.SpendDetail),
+		epochClients: make(map[uint64]*epochClient),
+		quit:         make(chan struct{}),
+	}
+}
+
+// Start catches up with the current state of the virtual chain, then launches
+// the goroutine following its events.
+func (v *VirtualNotifier) Start() error {
+	// Already started?
+	if atomic.AddInt32(&v.started, 1) != 1 {
+		return nil
+	}
+
+	// A closed channel makes the backend return the pending events
+	// without waiting for new ones.
+	noWait := make(chan struct{})
+	close(noWait)
+	for {
+		events, err := v.backend.Events(v.nextEvent, noWait)
+		if err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			break
+		}
+
+		v.processEvents(events)
+	}
+
+	v.wg.Add(1)
+	go v.eventHandler()
+
+	return nil
+}
+
+// Stop shuts down the VirtualNotifier, closing the channels of all pending
+// notifications.
+func (v *VirtualNotifier) Stop() error {
+	// Already shutting down?
+	if atomic.AddInt32(&v.stopped, 1) != 1 {
+		return nil
+	}
+
+	close(v.quit)
+	v.wg.Wait()
+
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+
+	for _, clients := range v.spendClients {
+		for _, spendChan := range clients {
+			close(spendChan)
+		}
+	}
+	for _, clients := range v.confClients {
+		for _, client := range clients {
+			close(client.finConf)
+		}
+	}
+	for _, client := range v.epochClients {
+		close(client.epochChan)
+	}
+
+	return nil
+}
+
+// eventHandler follows the events of the virtual chain, dispatching the
+// notifications they trigger.
+//
+// NOTE: This MUST be run as a goroutine.
+func (v *VirtualNotifier) eventHandler() {
+	defer v.wg.Done()
+
+	for {
+		v.mtx.Lock()
+		next := v.nextEvent
+		v.mtx.Unlock()
+
+		events, err := v.backend.Events(next, v.quit)
+		if err != nil {
+			chainntnfs.Log.Errorf("Unable to fetch events of "+
+				"virtual chain: %v", err)
+
+			select {
+			case <-time.After(retryInterval):
+				continue
+			case <-v.quit:
+				return
+			}
+		}
+
+		select {
+		case <-v.quit:
+			return
+		default:
+		}
+
+		v.processEvents(events)
+	}
+}
+
+// processEvents updates our indexes with the passed events, then dispatches
+// the notifications they trigger.
+func (v *VirtualNotifier) processEvents(events []*virtualchain.Event) {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+
+	for _, event := range events {
+		v.nextEvent++
+
+		switch {
+		case event.Accepted != nil:
+			tx := event.Accepted
+			v.mempool[tx.TxHash()] = tx
+			v.addSpends(tx, 0)
+
+		case event.Evicted != nil:
+			v.removeSpends(*event.Evicted)
+
+		case event.Block != nil:
+			v.connectBlock(event.Block)
+		}
+	}
+}
+
+// addSpends records the spends of the passed transaction, confirmed at the
+// passed height, or within the mempool if the height is zero, and dispatches
+// the spend notifications they trigger.
+//
+// NOTE: The notifier's mutex MUST be held when calling this method.
+func (v *VirtualNotifier) addSpends(tx *wire.MsgTx, height int32) {
+	txid := tx.TxHash()
+	for i, txIn := range tx.TxIn {
+		outpoint := txIn.PreviousOutPoint
+		if spend, ok := v.spends[outpoint]; ok {
+			spend.SpendingHeight = height
+			continue
+		}
+
+		spend := &chainntnfs.SpendDetail{
+			SpentOutPoint:     &outpoint,
+			SpenderTxHash:     &txid,
+			SpendingTx:        tx,
+			SpenderInputIndex: uint32(i),
+			SpendingHeight:    height,
+		}
+		v.spends[outpoint] = spend
+
+		for _, spendChan := range v.spendClients[outpoint] {
+			spendChan <- spend
+		}
+		delete(v.spendClients, outpoint)
+	}
+}
+
+// removeSpends forgets the spends of the passed transaction, which was
+// evicted from the mempool.
+//
+// NOTE: The notifier's mutex MUST be held when calling this method.
+func (v *VirtualNotifier) removeSpends(txid chainhash.Hash) {
+	tx, ok := v.mempool[txid]
+	if !ok {
+		return
+	}
+	delete(v.mempool, txid)
+
+	for _, txIn := range tx.TxIn {
+		spend, ok := v.spends[txIn.PreviousOutPoint]
+		if ok && *spend.SpenderTxHash == txid {
+			delete(v.spends, txIn.PreviousOutPoint)
+		}
+	}
+}
+
+// connectBlock records the confirmations and spends of the passed block,
+// then dispatches the notifications it triggers.
+//
+// NOTE: The notifier's mutex MUST be held when calling this method.
+func (v *VirtualNotifier) connectBlock(block *wire.MsgBlock) {
+	v.bestHeight++
+	blockHash := block.BlockHash()
+
+	for i, tx := range block.Transactions {
+		txid := tx.TxHash()
+		delete(v.mempool, txid)
+
+		v.confs[txid] = &chainntnfs.TxConfirmation{
+			BlockHash:   &blockHash,
+			BlockHeight: uint32(v.bestHeight),
+			TxIndex:     uint32(i),
+		}
+
+		// The coinbase spends no outputs.
+		if i != 0 {
+			v.addSpends(tx, v.bestHeight)
+		}
+	}
+
+	// Dispatch the confirmation notifications which have now reached
+	// their number of confirmations.
+	for txid, clients := range v.confClients {
+		conf, ok := v.confs[txid]
+		if !ok {
+			continue
+		}
+
+		var pending []*confClient
+		for _, client := range clients {
+			if !v.dispatchConf(client, conf) {
+				pending = append(pending, client)
+			}
+		}
+
+		if len(pending) == 0 {
+			delete(v.confClients, txid)
+		} else {
+			v.confClients[txid] = pending
+		}
+	}
+
+	epoch := &chainntnfs.BlockEpoch{
+		Hash:   &blockHash,
+		Height: v.bestHeight,
+	}
+	for _, client := range v.epochClients {
+		client.mtx.Lock()
+		client.queue = append(client.queue, epoch)
+		client.mtx.Unlock()
+
+		select {
+		case client.signal <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// dispatchConf sends the passed confirmation to the passed client if the
+// transaction has reached the client's number of confirmations. It returns
+// true if the confirmation was sent.
+//
+// NOTE: The notifier's mutex MUST be held when calling this method.
+func (v *VirtualNotifier) dispatchConf(client *confClient,
+	conf *chainntnfs.TxConfirmation) bool {
+
+	numConfs := client.numConfs
+	if numConfs == 0 {
+		numConfs = 1
+	}
+	if int64(conf.BlockHeight)+int64(numConfs)-1 > int64(v.bestHeight) {
+		return false
+	}
+
+	client.finConf <- conf
+	return true
+}
+
+// RegisterConfirmationsNtfn registers a notification which will be triggered
+// once the txid reaches numConfs number of confirmations.
+//
+// This is part of the chainntnfs.ChainNotifier interface.
+func (v *VirtualNotifier) RegisterConfirmationsNtfn(txid *chainhash.Hash,
+	numConfs uint32) (*chainntnfs.ConfirmationEvent, error) {
+
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+
+	select {
+	case <-v.quit:
+		return nil, ErrChainNotifierShuttingDown
+	default:
+	}
+
+	client := &confClient{
+		numConfs: numConfs,
+		finConf:  make(chan *chainntnfs.TxConfirmation, 1),
+	}
+
+	conf, ok := v.confs[*txid]
+	if !ok || !v.dispatchConf(client, conf) {
+		v.confClients[*txid] = append(v.confClients[*txid], client)
+	}
+
+	// As the virtual chain is never re-orged, no negative confirmation is
+	// ever sent.
+	return &chainntnfs.ConfirmationEvent{
+		Confirmed:    client.finConf,
+		NegativeConf: make(chan int32, 1),
+	}, nil
+}
+
+// RegisterSpendNtfn registers a notification which will be triggered once the
+// target outpoint is spent, either within the mempool, or within a block.
+//
+// This is part of the chainntnfs.ChainNotifier interface.
+func (v *VirtualNotifier) RegisterSpendNtfn(
+	outpoint *wire.OutPoint) (*chainntnfs.SpendEvent, error) {
+
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+
+	select {
+	case <-v.quit:
+		return nil, ErrChainNotifierShuttingDown
+	default:
+	}
+
+	spendChan := make(chan *chainntnfs.SpendDetail, 1)
+	spendID := atomic.AddUint64(&v.clientCounter, 1)
+
+	if spend, ok := v.spends[*outpoint]; ok {
+		spendChan <- spend
+	} else {
+		clients, ok := v.spendClients[*outpoint]
+		if !ok {
+			clients = make(map[uint64]chan *chainntnfs.SpendDetail)
+			v.spendClients[*outpoint] = clients
+		}
+		clients[spendID] = spendChan
+	}
+
+	return &chainntnfs.SpendEvent{
+		Spend: spendChan,
+		Cancel: func() {
+			v.mtx.Lock()
+			defer v.mtx.Unlock()
+
+			if clients, ok := v.spendClients[*outpoint]; ok {
+				delete(clients, spendID)
+			}
+		},
+	}, nil
+}
+
+// RegisterBlockEpochNtfn returns a BlockEpochEvent which subscribes the
+// caller to receive a notification of each new block connected to the chain.
+//
+// This is part of the chainntnfs.ChainNotifier interface.
+func (v *VirtualNotifier) RegisterBlockEpochNtfn() (*chainntnfs.BlockEpochEvent, error) {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+
+	select {
+	case <-v.quit:
+		return nil, ErrChainNotifierShuttingDown
+	default:
+	}
+
+	client := &epochClient{
+		epochChan: make(chan *chainntnfs.BlockEpoch, 20),
+		signal:    make(chan struct{}, 1),
+		cancel:    make(chan struct{}),
+	}
+	epochID := atomic.AddUint64(&v.clientCounter, 1)
+	v.epochClients[epochID] = client
+
+	v.wg.Add(1)
+	go v.epochDispatcher(client)
+
+	return &chainntnfs.BlockEpochEvent{
+		Epochs: client.epochChan,
+		Cancel: func() {
+			v.mtx.Lock()
+			delete(v.epochClients, epochID)
+			v.mtx.Unlock()
+
+			client.cancelOnce.Do(func() {
+				close(client.cancel)
+			})
+		},
+	}, nil
+}
+
+// epochDispatcher delivers the queued block notifications of the passed
+// client in order, until the client cancels its registration, or the
+// notifier shuts down.
+//
+// NOTE: This MUST be run as a goroutine.
+func (v *VirtualNotifier) epochDispatcher(client *epochClient) {
+	defer v.wg.Done()
+
+	for {
+		client.mtx.Lock()
+		if len(client.queue) == 0 {
+			client.mtx.Unlock()
+
+			select {
+			case <-client.signal:
+				continue
+			case <-client.cancel:
+				return
+			case <-v.quit:
+				return
+			}
+		}
+		epoch := client.queue[0]
+		client.queue = client.queue[1:]
+		client.mtx.Unlock()
+
+		select {
+		case client.epochChan <- epoch:
+		case <-client.cancel:
+			return
+		case <-v.quit:
+			return
+		}
+	}
+}
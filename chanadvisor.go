@@ -0,0 +1,529 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+const (
+	// rebalanceAction recommends shifting the balance of a lopsided but
+	// busy channel back towards the middle. As the router can't yet
+	// find circular routes back to ourselves, rebalances are advisory,
+	// and must be carried out manually.
+	rebalanceAction = "rebalance"
+
+	// closeAction recommends cooperatively closing a channel which hasn't
+	// forwarded any HTLCs within the lookback period.
+	closeAction = "close"
+
+	// openAction recommends opening an additional channel to the peer of
+	// a channel whose outgoing volume exceeds its capacity.
+	openAction = "open"
+
+	// skewedBalanceRatio is the share of a channel's capacity which, once
+	// held by either side, marks the channel as lopsided.
+	skewedBalanceRatio = 0.9
+)
+
+// advisorConfig defines the options of the channel advisor.
+type advisorConfig struct {
+	Lookback    time.Duration `long:"lookback" description:"The period of the forwarding log analyzed when recommending channel rebalances, closes and opens"`
+	AutoExecute []string      `long:"autoexecute" description:"Automatically execute recommendations of the given action, either close or open. Rebalances can't be executed automatically"`
+	Interval    time.Duration `long:"interval" description:"How often recommendations are evaluated for automatic execution"`
+}
+
+// validate checks the advisor options for consistency.
+func (a *advisorConfig) validate() error {
+	if a.Lookback <= 0 || a.Interval <= 0 {
+		return fmt.Errorf("advisor.lookback and advisor.interval must " +
+			"be positive")
+	}
+
+	for _, action := range a.AutoExecute {
+		if action != closeAction && action != openAction {
+			return fmt.Errorf("invalid advisor.autoexecute action "+
+				"%q, must be either %v or %v", action,
+				closeAction, openAction)
+		}
+	}
+
+	return nil
+}
+
+// channelRecommendation is a single action suggested by the channel advisor.
+type channelRecommendation struct {
+	// id identifies the recommendation. It's derived from the action and
+	// channel, so it's stable across queries for as long as the
+	// recommendation stands.
+	id string
+
+	// action is one of rebalanceAction, closeAction, or openAction.
+	action string
+
+	// chanPoint is the channel the recommendation concerns. For an open,
+	// it's the existing channel whose demand warrants another.
+	chanPoint wire.OutPoint
+
+	// remotePub is the identity key of the channel's peer.
+	remotePub *btcec.PublicKey
+
+	// amount is the amount to rebalance, the local balance of a channel
+	// to close, or the capacity of a channel to open.
+	amount btcutil.Amount
+
+	// reason is a human readable explanation of the recommendation.
+	reason string
+}
+
+// recommendationID derives the ID of a recommendation of the passed action on
+// the passed channel.
+func recommendationID(action string, chanPoint *wire.OutPoint) string {
+	h := sha256.Sum256([]byte(action + ":" + chanPoint.String()))
+	return hex.EncodeToString(h[:8])
+}
+
+// recommendationsByChannel implements sort.Interface to order recommendations
+// by their channel, then their action.
+type recommendationsByChannel []*channelRecommendation
+
+func (r recommendationsByChannel) Len() int      { return len(r) }
+func (r recommendationsByChannel) Swap(i, j int) { r[i], r[j] = r[j], r[i] }
+func (r recommendationsByChannel) Less(i, j int) bool {
+	ci, cj := r[i].chanPoint.String(), r[j].chanPoint.String()
+	if ci != cj {
+		return ci < cj
+	}
+	return r[i].action < r[j].action
+}
+
+// channelFlow tallies the HTLCs forwarded through a single channel.
+type channelFlow struct {
+	numIncoming int
+	numOutgoing int
+	outgoingAmt btcutil.Amount
+}
+
+// recommendChannelActions analyzes the forwards completed within lookback of
+// now along with the current state of each channel, and returns the actions
+// recommended for them. openTimes holds the time each channel was opened, if
+// known. A channel is considered busy if it has forwarded at least one HTLC
+// per day on average.
+func recommendChannelActions(channels []*channeldb.OpenChannel,
+	forwards []*channeldb.ForwardingEvent,
+	openTimes map[wire.OutPoint]time.Time, now time.Time,
+	lookback time.Duration) []*channelRecommendation {
+
+	start := now.Add(-lookback)
+
+	flows := make(map[wire.OutPoint]*channelFlow)
+	flowOf := func(chanPoint wire.OutPoint) *channelFlow {
+		flow, ok := flows[chanPoint]
+		if !ok {
+			flow = &channelFlow{}
+			flows[chanPoint] = flow
+		}
+		return flow
+	}
+	for _, fwd := range forwards {
+		if fwd.Timestamp.Before(start) {
+			continue
+		}
+
+		flowOf(fwd.IncomingChanPoint).numIncoming++
+		outgoing := flowOf(fwd.OutgoingChanPoint)
+		outgoing.numOutgoing++
+		outgoing.outgoingAmt += fwd.AmtOut
+	}
+
+	busyThreshold := int(lookback / (time.Hour * 24))
+	if busyThreshold < 1 {
+		busyThreshold = 1
+	}
+
+	var recs []*channelRecommendation
+	for _, channel := range channels {
+		if channel.IsPending || channel.Capacity == 0 {
+			continue
+		}
+
+		chanPoint := *channel.ChanID
+		flow := flowOf(chanPoint)
+		numForwards := flow.numIncoming + flow.numOutgoing
+		localRatio := float64(channel.OurBalance) /
+			float64(channel.Capacity)
+
+		newRec := func(action string, amt btcutil.Amount,
+			reason string) *channelRecommendation {
+
+			return &channelRecommendation{
+				id:        recommendationID(action, &chanPoint),
+				action:    action,
+				chanPoint: chanPoint,
+				remotePub: channel.IdentityPub,
+				amount:    amt,
+				reason:    reason,
+			}
+		}
+
+		switch {
+		// A channel which hasn't forwarded anything for the entire
+		// lookback period is tying up capital which could be better
+		// used elsewhere. Channels opened within the period haven't
+		// had the chance to prove themselves yet.
+		case numForwards == 0:
+			opened, ok := openTimes[chanPoint]
+			if !ok || !opened.Before(start) {
+				continue
+			}
+
+			reason := fmt.Sprintf("channel to %x hasn't forwarded "+
+				"any HTLCs within the past %v, close it to "+
+				"free %v", channel.IdentityPub.SerializeCompressed(),
+				lookback, channel.OurBalance)
+			recs = append(recs, newRec(closeAction,
+				channel.OurBalance, reason))
+
+		// A busy channel whose balance rests almost entirely on
+		// either side can only forward in one direction, so its
+		// balance should be brought back towards the middle.
+		case numForwards >= busyThreshold &&
+			(localRatio >= skewedBalanceRatio ||
+				localRatio <= 1-skewedBalanceRatio):
+
+			half := channel.Capacity / 2
+			amt := channel.OurBalance - half
+			direction := "out of"
+			if amt < 0 {
+				amt = -amt
+				direction = "into"
+			}
+
+			reason := fmt.Sprintf("channel to %x is %d%% outbound "+
+				"and forwarded %v HTLCs within the past %v, "+
+				"rebalance %v %v it",
+				channel.IdentityPub.SerializeCompressed(),
+				int(localRatio*100+0.5), numForwards, lookback, amt,
+				direction)
+			recs = append(recs, newRec(rebalanceAction, amt, reason))
+		}
+
+		// If the demand for outgoing liquidity through the channel
+		// exceeds what it can carry, and it's been all but drained,
+		// then another channel to the same peer would be put to use.
+		if flow.outgoingAmt > channel.Capacity &&
+			localRatio <= 1-skewedBalanceRatio {
+
+			reason := fmt.Sprintf("channel to %x forwarded %v "+
+				"outgoing within the past %v, exceeding its "+
+				"capacity of %v, open another channel of %v",
+				channel.IdentityPub.SerializeCompressed(),
+				flow.outgoingAmt, lookback, channel.Capacity,
+				channel.Capacity)
+			recs = append(recs, newRec(openAction, channel.Capacity,
+				reason))
+		}
+	}
+
+	sort.Sort(recommendationsByChannel(recs))
+
+	return recs
+}
+
+// channelAdvisor analyzes the forwarding log and the balances of the node's
+// channels in order to recommend rebalances, closes and opens. Recommendations
+// may be executed on request, or automatically for the actions the operator
+// has opted into.
+type channelAdvisor struct {
+	started uint32 // To be used atomically.
+	stopped uint32 // To be used atomically.
+
+	cfg *advisorConfig
+	db  *channeldb.DB
+
+	// closeChannel begins the cooperative closure of the passed channel.
+	closeChannel func(*wire.OutPoint) error
+
+	// openChannel begins the funding of a new channel of the passed
+	// amount with the passed peer.
+	openChannel func(*btcec.PublicKey, btcutil.Amount) error
+
+	// executed is the set of IDs of the recommendations which have
+	// already been executed, so they aren't executed again while the
+	// action is still in progress.
+	executed    map[string]struct{}
+	executedMtx sync.Mutex
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newChannelAdvisor creates a new channel advisor.
+func newChannelAdvisor(cfg *advisorConfig, db *channeldb.DB,
+	closeChannel func(*wire.OutPoint) error,
+	openChannel func(*btcec.PublicKey, btcutil.Amount) error) *channelAdvisor {
+
+	return &channelAdvisor{
+		cfg:          cfg,
+		db:           db,
+		closeChannel: closeChannel,
+		openChannel:  openChannel,
+		executed:     make(map[string]struct{}),
+		quit:         make(chan struct{}),
+	}
+}
+
+// Start launches the automatic execution of recommendations, if the operator
+// has opted into it.
+func (a *channelAdvisor) Start() error {
+	if !atomic.CompareAndSwapUint32(&a.started, 0, 1) {
+		return nil
+	}
+
+	if len(a.cfg.AutoExecute) != 0 {
+		advrLog.Infof("Automatically executing %v recommendations "+
+			"every %v", a.cfg.AutoExecute, a.cfg.Interval)
+
+		a.wg.Add(1)
+		go a.autoExecutor()
+	}
+
+	return nil
+}
+
+// Stop halts the automatic execution of recommendations.
+func (a *channelAdvisor) Stop() error {
+	if !atomic.CompareAndSwapUint32(&a.stopped, 0, 1) {
+		return nil
+	}
+
+	close(a.quit)
+	a.wg.Wait()
+
+	return nil
+}
+
+// recommendations returns the actions currently recommended for the node's
+// channels.
+func (a *channelAdvisor) recommendations() ([]*channelRecommendation, error) {
+	channels, err := a.db.FetchAllChannels()
+	if err != nil && err != channeldb.ErrNoActiveChannels {
+		return nil, err
+	}
+	forwards, err := a.db.FetchForwardingLog()
+	if err != nil {
+		return nil, err
+	}
+
+	// The time each channel was opened is taken from the first opened
+	// event within its timeline.
+	openTimes := make(map[wire.OutPoint]time.Time)
+	for _, channel := range channels {
+		events, err := a.db.FetchChannelEvents(channel.ChanID,
+			time.Time{}, time.Time{})
+		if err != nil {
+			return nil, err
+		}
+		for _, event := range events {
+			if event.Type == channeldb.ChannelOpenedEvent {
+				openTimes[*channel.ChanID] = event.Timestamp
+				break
+			}
+		}
+	}
+
+	return recommendChannelActions(channels, forwards, openTimes,
+		time.Now(), a.cfg.Lookback), nil
+}
+
+// execute carries out the recommendation with the passed ID. The
+// recommendation must still stand at the time of the call. If authorize is
+// non-nil, then it's passed the recommendation's action, and the
+// recommendation is only carried out if it returns nil.
+func (a *channelAdvisor) execute(id string,
+	authorize func(action string) error) error {
+
+	recs, err := a.recommendations()
+	if err != nil {
+		return err
+	}
+
+	var rec *channelRecommendation
+	for _, r := range recs {
+		if r.id == id {
+			rec = r
+			break
+		}
+	}
+	if rec == nil {
+		return fmt.Errorf("no recommendation with id %v", id)
+	}
+	if authorize != nil {
+		if err := authorize(rec.action); err != nil {
+			return err
+		}
+	}
+
+	a.executedMtx.Lock()
+	defer a.executedMtx.Unlock()
+
+	if _, ok := a.executed[id]; ok {
+		return fmt.Errorf("recommendation %v has already been "+
+			"executed", id)
+	}
+
+	switch rec.action {
+	case closeAction:
+		err = a.closeChannel(&rec.chanPoint)
+	case openAction:
+		err = a.openChannel(rec.remotePub, rec.amount)
+	default:
+		return fmt.Errorf("%v recommendations can't be executed "+
+			"automatically, and must be carried out manually",
+			rec.action)
+	}
+	if err != nil {
+		return err
+	}
+
+	advrLog.Infof("Executed recommendation %v: %v", id, rec.reason)
+	a.executed[id] = struct{}{}
+
+	return nil
+}
+
+// autoExecutor periodically executes the recommendations of the actions the
+// operator has opted into.
+//
+// NOTE: This MUST be run as a goroutine.
+func (a *channelAdvisor) autoExecutor() {
+	defer a.wg.Done()
+
+	autoActions := make(map[string]struct{})
+	for _, action := range a.cfg.AutoExecute {
+		autoActions[action] = struct{}{}
+	}
+
+	ticker := time.NewTicker(a.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			recs, err := a.recommendations()
+			if err != nil {
+				advrLog.Errorf("unable to compute "+
+					"recommendations: %v", err)
+				continue
+			}
+
+			for _, rec := range recs {
+				if _, ok := autoActions[rec.action]; !ok {
+					continue
+				}
+
+				a.executedMtx.Lock()
+				_, done := a.executed[rec.id]
+				a.executedMtx.Unlock()
+				if done {
+					continue
+				}
+
+				if err := a.execute(rec.id, nil); err != nil {
+					advrLog.Errorf("unable to execute "+
+						"recommendation %v: %v", rec.id,
+						err)
+				}
+			}
+
+		case <-a.quit:
+			return
+		}
+	}
+}
+
+// advisorCloseChannel begins the cooperative closure of the passed channel on
+// behalf of the channel advisor. It returns once the closing transaction has
+// been broadcast, while the remainder of the closure proceeds in the
+// background.
+func (s *server) advisorCloseChannel(chanPoint *wire.OutPoint) error {
+	updates, errChan := s.htlcSwitch.CloseLink(chanPoint, CloseRegular, 0)
+	select {
+	case err := <-errChan:
+		return err
+	case <-updates:
+	case <-s.quit:
+		return fmt.Errorf("server shutting down")
+	}
+
+	// The remaining updates are drained, as the closure blocks until
+	// each of them has been delivered.
+	go func() {
+		for {
+			select {
+			case update := <-updates:
+				switch update.Update.(type) {
+				case *lnrpc.CloseStatusUpdate_ChanClose:
+					return
+				}
+			case err := <-errChan:
+				advrLog.Errorf("unable to close ChannelPoint(%v): "+
+					"%v", chanPoint, err)
+				return
+			case <-s.quit:
+				return
+			}
+		}
+	}()
+
+	return nil
+}
+
+// advisorOpenChannel begins the funding of a new channel of the passed amount
+// with the passed peer on behalf of the channel advisor. It returns once the
+// funding transaction has been broadcast, while the remainder of the funding
+// proceeds in the background.
+func (s *server) advisorOpenChannel(nodeKey *btcec.PublicKey,
+	amt btcutil.Amount) error {
+
+	updates, errChan := s.OpenChannel(0, nodeKey, amt, 0, 0, 1)
+	select {
+	case err := <-errChan:
+		return err
+	case <-updates:
+	case <-s.quit:
+		return fmt.Errorf("server shutting down")
+	}
+
+	// The remaining updates are drained, as the funding blocks until each
+	// of them has been delivered.
+	go func() {
+		for {
+			select {
+			case update := <-updates:
+				switch update.Update.(type) {
+				case *lnrpc.OpenStatusUpdate_ChanOpen:
+					return
+				}
+			case err := <-errChan:
+				advrLog.Errorf("unable to open channel to %x: %v",
+					nodeKey.SerializeCompressed(), err)
+				return
+			case <-s.quit:
+				return
+			}
+		}
+	}()
+
+	return nil
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+// newAdvisorTestChannel creates a channel of the passed capacity and local
+// balance, funded by the transaction with the passed txid.
+func newAdvisorTestChannel(t *testing.T, txid byte, capacity,
+	local btcutil.Amount) *channeldb.OpenChannel {
+
+	priv, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to create key: %v", err)
+	}
+
+	return &channeldb.OpenChannel{
+		IdentityPub:  priv.PubKey(),
+		ChanID:       wire.NewOutPoint(&chainhash.Hash{txid}, 0),
+		Capacity:     capacity,
+		OurBalance:   local,
+		TheirBalance: capacity - local,
+	}
+}
+
+func TestChannelRecommendations(t *testing.T) {
+	now := time.Unix(1490000000, 0)
+	lookback := time.Hour * 24 * 2
+
+	// The channels are:
+	//  * busy, holding 95% of its balance locally.
+	//  * busy, and drained by outgoing volume well beyond its capacity.
+	//  * idle since before the lookback period.
+	//  * idle, but only opened within the lookback period.
+	//  * busy, and evenly balanced.
+	skewed := newAdvisorTestChannel(t, 1, 1000000, 950000)
+	drained := newAdvisorTestChannel(t, 2, 100000, 5000)
+	idle := newAdvisorTestChannel(t, 3, 200000, 150000)
+	young := newAdvisorTestChannel(t, 4, 200000, 150000)
+	balanced := newAdvisorTestChannel(t, 5, 200000, 100000)
+	channels := []*channeldb.OpenChannel{
+		balanced, young, idle, drained, skewed,
+	}
+
+	openTimes := map[wire.OutPoint]time.Time{
+		*idle.ChanID:  now.Add(-lookback * 2),
+		*young.ChanID: now.Add(-time.Hour),
+	}
+
+	forward := func(from, to *channeldb.OpenChannel, amt btcutil.Amount,
+		age time.Duration) *channeldb.ForwardingEvent {
+
+		return &channeldb.ForwardingEvent{
+			Timestamp:         now.Add(-age),
+			IncomingChanPoint: *from.ChanID,
+			OutgoingChanPoint: *to.ChanID,
+			AmtIn:             amt + 1,
+			AmtOut:            amt,
+		}
+	}
+	forwards := []*channeldb.ForwardingEvent{
+		// Forwards before the lookback period should be ignored, so
+		// the idle channel should still be recommended for closure.
+		forward(idle, balanced, 1000, lookback*2),
+
+		forward(skewed, balanced, 1000, time.Hour),
+		forward(skewed, balanced, 1000, time.Hour*2),
+		forward(balanced, drained, 60000, time.Hour),
+		forward(balanced, drained, 60000, time.Hour*2),
+	}
+
+	recs := recommendChannelActions(channels, forwards, openTimes, now,
+		lookback)
+
+	type expectedRec struct {
+		action    string
+		chanPoint *wire.OutPoint
+		amount    btcutil.Amount
+	}
+	expected := []expectedRec{
+		{rebalanceAction, skewed.ChanID, 450000},
+		{openAction, drained.ChanID, 100000},
+		{rebalanceAction, drained.ChanID, 45000},
+		{closeAction, idle.ChanID, 150000},
+	}
+	if len(recs) != len(expected) {
+		t.Fatalf("expected %v recommendations, got %v: %v",
+			len(expected), len(recs), recs)
+	}
+
+	// The recommendations are ordered by channel point, which is
+	// displayed with its txid reversed, so the txids above sort in order.
+	for i, want := range expected {
+		rec := recs[i]
+		if rec.action != want.action || rec.chanPoint != *want.chanPoint ||
+			rec.amount != want.amount {
+
+			t.Fatalf("recommendation %v: expected %v of %v on %v, "+
+				"got %v of %v on %v", i, want.action,
+				want.amount, want.chanPoint, rec.action,
+				rec.amount, rec.chanPoint)
+		}
+		if rec.id != recommendationID(want.action, want.chanPoint) {
+			t.Fatalf("recommendation %v has unexpected id %v", i,
+				rec.id)
+		}
+	}
+}
+
+func TestAdvisorConfigValidation(t *testing.T) {
+	cfg := &advisorConfig{
+		Lookback:    time.Hour,
+		Interval:    time.Hour,
+		AutoExecute: []string{closeAction, openAction},
+	}
+	if err := cfg.validate(); err != nil {
+		t.Fatalf("unable to validate config: %v", err)
+	}
+
+	// Rebalances can't be executed automatically.
+	cfg.AutoExecute = []string{rebalanceAction}
+	if err := cfg.validate(); err == nil {
+		t.Fatalf("expected automatic rebalances to be rejected")
+	}
+}
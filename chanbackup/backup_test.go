@@ -0,0 +1,245 @@
+package chanbackup
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/wire"
+)
+
+var (
+	testKey, _ = btcec.NewPrivateKey(btcec.S256())
+)
+
+func makeTestSingle(t *testing.T, index uint32) Single {
+	remoteKey, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to create key: %v", err)
+	}
+	multiSigKey, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to create key: %v", err)
+	}
+
+	return Single{
+		ChanPoint: wire.OutPoint{
+			Hash:  [32]byte{byte(index)},
+			Index: index,
+		},
+		RemoteNodePub:  remoteKey.PubKey(),
+		Capacity:       1000000,
+		IsInitiator:    true,
+		LocalCsvDelay:  144,
+		RemoteCsvDelay: 288,
+		OurMultiSigKey: multiSigKey.PubKey(),
+	}
+}
+
+// mockChannelSource is a ChannelSource backed by an in-memory set of
+// channels.
+type mockChannelSource struct {
+	channels []*channeldb.OpenChannel
+	closed   map[wire.OutPoint]struct{}
+}
+
+func (m *mockChannelSource) FetchAllChannels() ([]*channeldb.OpenChannel, error) {
+	return m.channels, nil
+}
+
+func (m *mockChannelSource) IsChannelClosed(chanPoint *wire.OutPoint) (bool, error) {
+	_, ok := m.closed[*chanPoint]
+	return ok, nil
+}
+
+func TestMultiPackUnpack(t *testing.T) {
+	multi := &Multi{
+		StaticBackups: []Single{
+			makeTestSingle(t, 0),
+			makeTestSingle(t, 1),
+		},
+	}
+
+	key := DeriveBackupKey(testKey)
+
+	var b bytes.Buffer
+	if err := multi.PackToWriter(&b, key); err != nil {
+		t.Fatalf("unable to pack multi: %v", err)
+	}
+	packed := b.Bytes()
+
+	var unpacked Multi
+	if err := unpacked.UnpackFromReader(bytes.NewReader(packed), key); err != nil {
+		t.Fatalf("unable to unpack multi: %v", err)
+	}
+	for i := range unpacked.StaticBackups {
+		unpacked.StaticBackups[i].RemoteNodePub.Curve = nil
+		unpacked.StaticBackups[i].OurMultiSigKey.Curve = nil
+		multi.StaticBackups[i].RemoteNodePub.Curve = nil
+		multi.StaticBackups[i].OurMultiSigKey.Curve = nil
+	}
+	if !reflect.DeepEqual(multi, &unpacked) {
+		t.Fatalf("multis don't match: expected %v got %v",
+			spew.Sdump(multi), spew.Sdump(unpacked))
+	}
+
+	// A backup encrypted by another node shouldn't be decryptable with
+	// our key.
+	otherKey, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to create key: %v", err)
+	}
+	err = unpacked.UnpackFromReader(bytes.NewReader(packed),
+		DeriveBackupKey(otherKey))
+	if err != ErrDecryptFailed {
+		t.Fatalf("expected ErrDecryptFailed, got %v", err)
+	}
+}
+
+// TestMultiVersions tests that Multis written prior to the introduction of
+// Single versions are still read as legacy channels, and that a Multi
+// containing a channel of a type we can't recover is rejected outright.
+func TestMultiVersions(t *testing.T) {
+	key := DeriveBackupKey(testKey)
+	single := makeTestSingle(t, 0)
+
+	// packPlaintext encrypts a hand-crafted Multi containing the single,
+	// serialized by the passed closure.
+	packPlaintext := func(version byte,
+		serialize func(*bytes.Buffer) error) []byte {
+
+		var plaintext bytes.Buffer
+		plaintext.WriteByte(version)
+		plaintext.Write([]byte{0, 0, 0, 1})
+		if err := serialize(&plaintext); err != nil {
+			t.Fatalf("unable to serialize single: %v", err)
+		}
+
+		var b bytes.Buffer
+		err := encryptPayloadToWriter(plaintext.Bytes(), &b, key)
+		if err != nil {
+			t.Fatalf("unable to encrypt multi: %v", err)
+		}
+		return b.Bytes()
+	}
+
+	// Legacy Multis don't prefix each Single with its version, so we strip
+	// the version from the serialization of the single.
+	legacy := packPlaintext(legacyMultiVersion, func(b *bytes.Buffer) error {
+		var versioned bytes.Buffer
+		if err := single.Serialize(&versioned); err != nil {
+			return err
+		}
+		_, err := b.Write(versioned.Bytes()[1:])
+		return err
+	})
+
+	var multi Multi
+	if err := multi.UnpackFromReader(bytes.NewReader(legacy), key); err != nil {
+		t.Fatalf("unable to unpack legacy multi: %v", err)
+	}
+	if len(multi.StaticBackups) != 1 {
+		t.Fatalf("expected 1 backup, got %v", len(multi.StaticBackups))
+	}
+	unpacked := multi.StaticBackups[0]
+	if unpacked.Version != LegacyVersion {
+		t.Fatalf("expected legacy version, got %v", unpacked.Version)
+	}
+	if unpacked.ChanPoint != single.ChanPoint ||
+		unpacked.Capacity != single.Capacity {
+
+		t.Fatalf("legacy backup doesn't match: expected %v got %v",
+			spew.Sdump(single), spew.Sdump(unpacked))
+	}
+
+	// A Multi containing a channel type we can't recover must fail to
+	// unpack, rather than the channel being recovered as another type.
+	for _, version := range []SingleBackupVersion{
+		AnchorsVersion, TaprootVersion, 99,
+	} {
+		packed := packPlaintext(multiVersion, func(b *bytes.Buffer) error {
+			if err := single.Serialize(b); err != nil {
+				return err
+			}
+			b.Bytes()[5] = byte(version)
+			return nil
+		})
+
+		err := multi.UnpackFromReader(bytes.NewReader(packed), key)
+		if err == nil {
+			t.Fatalf("expected multi containing %v channel to be "+
+				"rejected", version)
+		}
+	}
+
+	// Likewise, a Multi of a version we don't know must be rejected.
+	unknown := packPlaintext(multiVersion+1, func(b *bytes.Buffer) error {
+		return single.Serialize(b)
+	})
+	if err := multi.UnpackFromReader(bytes.NewReader(unknown), key); err == nil {
+		t.Fatalf("expected multi of unknown version to be rejected")
+	}
+}
+
+func TestVerifyMulti(t *testing.T) {
+	open := makeTestSingle(t, 0)
+	closed := makeTestSingle(t, 1)
+	unknown := makeTestSingle(t, 2)
+	mismatch := makeTestSingle(t, 3)
+
+	liveChannel := func(s Single) *channeldb.OpenChannel {
+		chanPoint := s.ChanPoint
+		return &channeldb.OpenChannel{
+			ChanID:         &chanPoint,
+			IdentityPub:    s.RemoteNodePub,
+			Capacity:       s.Capacity,
+			IsInitiator:    s.IsInitiator,
+			LocalCsvDelay:  s.LocalCsvDelay,
+			RemoteCsvDelay: s.RemoteCsvDelay,
+			OurMultiSigKey: s.OurMultiSigKey,
+		}
+	}
+
+	// The live channel for the mismatched backup has a larger capacity
+	// than the backup records.
+	mismatchChan := liveChannel(mismatch)
+	mismatchChan.Capacity *= 2
+
+	source := &mockChannelSource{
+		channels: []*channeldb.OpenChannel{
+			liveChannel(open),
+			mismatchChan,
+		},
+		closed: map[wire.OutPoint]struct{}{
+			closed.ChanPoint: {},
+		},
+	}
+
+	multi := &Multi{
+		StaticBackups: []Single{open, closed, unknown, mismatch},
+	}
+	reports, err := VerifyMulti(multi, source)
+	if err != nil {
+		t.Fatalf("unable to verify multi: %v", err)
+	}
+
+	expected := []ChannelStatus{
+		StatusOpen, StatusClosed, StatusUnknown, StatusMismatch,
+	}
+	if len(reports) != len(expected) {
+		t.Fatalf("expected %v reports, got %v", len(expected),
+			len(reports))
+	}
+	for i, report := range reports {
+		if report.Status != expected[i] {
+			t.Fatalf("report #%v: expected status %v, got %v", i,
+				expected[i], report.Status)
+		}
+	}
+	if reports[3].Problem == "" {
+		t.Fatalf("mismatched channel has no problem description")
+	}
+}
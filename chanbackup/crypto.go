@@ -0,0 +1,81 @@
+package chanbackup
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"io"
+	"io/ioutil"
+
+	"github.com/roasbeef/btcd/btcec"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+// backupKeyTag is mixed into the node's identity key in order to derive the
+// key used to encrypt backups.
+var backupKeyTag = []byte("chanbackup")
+
+// ErrDecryptFailed is returned when a backup can't be decrypted, either
+// because it was encrypted with another node's key or has been corrupted.
+var ErrDecryptFailed = errors.New("unable to decrypt backup: wrong key " +
+	"or corrupted backup")
+
+// DeriveBackupKey derives the symmetric key used to encrypt backups from the
+// node's identity private key. As a result, only the node which created a
+// backup is able to decrypt it.
+func DeriveBackupKey(idKey *btcec.PrivateKey) [32]byte {
+	h := sha256.New()
+	h.Write(idKey.Serialize())
+	h.Write(backupKeyTag)
+
+	var key [32]byte
+	copy(key[:], h.Sum(nil))
+	return key
+}
+
+// encryptPayloadToWriter encrypts the payload using the passed key, writing
+// the random nonce followed by the ciphertext to w.
+func encryptPayloadToWriter(payload []byte, w io.Writer, key [32]byte) error {
+	cipher, err := chacha20poly1305.New(key[:])
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, cipher.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+
+	if _, err := w.Write(nonce); err != nil {
+		return err
+	}
+	_, err = w.Write(cipher.Seal(nil, nonce, payload, nil))
+	return err
+}
+
+// decryptPayloadFromReader reads a nonce and ciphertext from r, returning the
+// plaintext payload once decrypted with the passed key.
+func decryptPayloadFromReader(r io.Reader, key [32]byte) ([]byte, error) {
+	cipher, err := chacha20poly1305.New(key[:])
+	if err != nil {
+		return nil, err
+	}
+
+	packed, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(packed) < cipher.NonceSize()+cipher.Overhead() {
+		return nil, ErrDecryptFailed
+	}
+
+	nonce := packed[:cipher.NonceSize()]
+	ciphertext := packed[cipher.NonceSize():]
+
+	plaintext, err := cipher.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, ErrDecryptFailed
+	}
+
+	return plaintext, nil
+}
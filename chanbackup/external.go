@@ -0,0 +1,260 @@
+package chanbackup
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/shachain"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/chaincfg"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+// ExternalChannel is the recovery data of a single channel as exported by
+// another Lightning implementation. It contains the minimal information
+// required to reconstruct a channel which can then be cooperatively closed
+// with the remote party.
+type ExternalChannel struct {
+	// FundingOutpoint is the funding outpoint of the channel, encoded as
+	// txid:index.
+	FundingOutpoint string `json:"funding_outpoint"`
+
+	// RemoteNodePub is the hex encoded identity public key of the remote
+	// node.
+	RemoteNodePub string `json:"remote_node_pub"`
+
+	// RemoteAddr is the host:port the remote node can be reached at.
+	RemoteAddr string `json:"remote_addr"`
+
+	// Capacity is the total capacity of the channel in satoshis.
+	Capacity int64 `json:"capacity"`
+
+	// LocalBalance and RemoteBalance are the settled balances of each
+	// party as of the latest state. If the remote balance is omitted, it
+	// is assumed to be the remainder of the channel's capacity.
+	LocalBalance  int64 `json:"local_balance"`
+	RemoteBalance int64 `json:"remote_balance,omitempty"`
+
+	// IsInitiator denotes whether we funded the channel.
+	IsInitiator bool `json:"is_initiator"`
+
+	// FundingKey is our WIF encoded private key within the funding
+	// output's multi-sig script. Implementations which derive their
+	// funding keys from a seed must export the derived key.
+	FundingKey string `json:"funding_key"`
+
+	// RemoteFundingPub is the hex encoded public key of the remote party
+	// within the funding output's multi-sig script.
+	RemoteFundingPub string `json:"remote_funding_pub"`
+
+	// LocalDeliveryScript and RemoteDeliveryScript are the hex encoded
+	// scripts each party's balance is paid to upon a cooperative close.
+	// If our delivery script is omitted, a fresh one is used.
+	LocalDeliveryScript  string `json:"local_delivery_script,omitempty"`
+	RemoteDeliveryScript string `json:"remote_delivery_script"`
+}
+
+// ParseExternalChannels parses a JSON array of external channel recovery
+// data.
+func ParseExternalChannels(data []byte) ([]ExternalChannel, error) {
+	var channels []ExternalChannel
+	if err := json.Unmarshal(data, &channels); err != nil {
+		return nil, fmt.Errorf("invalid recovery data: %v", err)
+	}
+
+	return channels, nil
+}
+
+// RecoveredChannel is a channel reconstructed from external recovery data.
+type RecoveredChannel struct {
+	// Channel is the minimal channel record, which may only be used to
+	// cooperatively close the channel.
+	Channel *channeldb.OpenChannel
+
+	// FundingKey is our private key within the funding output, which must
+	// be imported into the wallet in order to sign the closure
+	// transaction.
+	FundingKey *btcec.PrivateKey
+
+	// RemoteAddr is the address the remote node can be reached at.
+	RemoteAddr *net.TCPAddr
+}
+
+// Recover validates the recovery data, and reconstructs a minimal channel
+// record of type channeldb.RecoveredChannel from it. The passed delivery
+// script is used as our own if the recovery data doesn't include one.
+//
+// NOTE: The returned channel hasn't yet been written to the database.
+func (e *ExternalChannel) Recover(cdb *channeldb.DB, netParams *chaincfg.Params,
+	deliveryScript []byte) (*RecoveredChannel, error) {
+
+	chanPoint, err := parseOutPoint(e.FundingOutpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	nodePub, err := parseHexPubKey(e.RemoteNodePub)
+	if err != nil {
+		return nil, fmt.Errorf("invalid remote node key: %v", err)
+	}
+	theirFundingKey, err := parseHexPubKey(e.RemoteFundingPub)
+	if err != nil {
+		return nil, fmt.Errorf("invalid remote funding key: %v", err)
+	}
+
+	wif, err := btcutil.DecodeWIF(e.FundingKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid funding key: %v", err)
+	}
+	if !wif.IsForNet(netParams) {
+		return nil, fmt.Errorf("funding key is for the wrong network")
+	}
+	ourFundingKey := wif.PrivKey.PubKey()
+
+	remoteAddr, err := net.ResolveTCPAddr("tcp", e.RemoteAddr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid remote address: %v", err)
+	}
+
+	capacity := btcutil.Amount(e.Capacity)
+	ourBalance := btcutil.Amount(e.LocalBalance)
+	theirBalance := btcutil.Amount(e.RemoteBalance)
+	if theirBalance == 0 {
+		theirBalance = capacity - ourBalance
+	}
+	switch {
+	case capacity <= 0:
+		return nil, fmt.Errorf("capacity must be positive")
+	case ourBalance < 0 || theirBalance < 0:
+		return nil, fmt.Errorf("balances must not be negative")
+	case ourBalance+theirBalance > capacity:
+		return nil, fmt.Errorf("balances exceed the channel capacity")
+	}
+
+	theirDeliveryScript, err := hex.DecodeString(e.RemoteDeliveryScript)
+	if err != nil || len(theirDeliveryScript) == 0 {
+		return nil, fmt.Errorf("invalid remote delivery script")
+	}
+	ourDeliveryScript := deliveryScript
+	if e.LocalDeliveryScript != "" {
+		ourDeliveryScript, err = hex.DecodeString(e.LocalDeliveryScript)
+		if err != nil {
+			return nil, fmt.Errorf("invalid local delivery "+
+				"script: %v", err)
+		}
+	}
+
+	channel, err := newRecoveredChannel(cdb, &PeerChannel{
+		Single: Single{
+			ChanPoint:      *chanPoint,
+			RemoteNodePub:  nodePub,
+			Capacity:       capacity,
+			IsInitiator:    e.IsInitiator,
+			OurMultiSigKey: ourFundingKey,
+		},
+		TheirMultiSigKey:    theirFundingKey,
+		OurBalance:          ourBalance,
+		TheirBalance:        theirBalance,
+		OurDeliveryScript:   ourDeliveryScript,
+		TheirDeliveryScript: theirDeliveryScript,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &RecoveredChannel{
+		Channel:    channel,
+		FundingKey: wif.PrivKey,
+		RemoteAddr: remoteAddr,
+	}, nil
+}
+
+// newRecoveredChannel reconstructs a minimal channel record of type
+// channeldb.RecoveredChannel from the passed backup.
+func newRecoveredChannel(cdb *channeldb.DB,
+	c *PeerChannel) (*channeldb.OpenChannel, error) {
+
+	chanPoint := c.ChanPoint
+	witnessScript, _, err := lnwallet.GenFundingPkScript(
+		c.OurMultiSigKey.SerializeCompressed(),
+		c.TheirMultiSigKey.SerializeCompressed(), int64(c.Capacity))
+	if err != nil {
+		return nil, err
+	}
+
+	// As the commitment state of the channel is unknown, the fields
+	// describing it are populated with placeholders. The channel type
+	// ensures they're never used, as only a cooperative close may be
+	// negotiated.
+	var root chainhash.Hash
+	if _, err := rand.Read(root[:]); err != nil {
+		return nil, err
+	}
+	commitTx := wire.NewMsgTx(2)
+	commitTx.AddTxIn(wire.NewTxIn(&chanPoint, nil, nil))
+
+	return &channeldb.OpenChannel{
+		IdentityPub:            c.RemoteNodePub,
+		ChanID:                 &chanPoint,
+		FundingOutpoint:        &chanPoint,
+		ChanType:               channeldb.RecoveredChannel,
+		IsInitiator:            c.IsInitiator,
+		Capacity:               c.Capacity,
+		OurBalance:             c.OurBalance,
+		TheirBalance:           c.TheirBalance,
+		OurMultiSigKey:         c.OurMultiSigKey,
+		TheirMultiSigKey:       c.TheirMultiSigKey,
+		FundingWitnessScript:   witnessScript,
+		OurCommitKey:           c.OurMultiSigKey,
+		TheirCommitKey:         c.TheirMultiSigKey,
+		TheirCurrentRevocation: c.TheirMultiSigKey,
+		LocalCsvDelay:          c.LocalCsvDelay,
+		RemoteCsvDelay:         c.RemoteCsvDelay,
+		OurCommitTx:            commitTx,
+		OurDeliveryScript:      c.OurDeliveryScript,
+		TheirDeliveryScript:    c.TheirDeliveryScript,
+		RevocationProducer:     shachain.NewRevocationProducer(root),
+		RevocationStore:        shachain.NewRevocationStore(),
+		CreationTime:           time.Now(),
+		Db:                     cdb,
+	}, nil
+}
+
+// parseOutPoint parses an outpoint encoded as txid:index.
+func parseOutPoint(s string) (*wire.OutPoint, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid outpoint %v", s)
+	}
+
+	hash, err := chainhash.NewHashFromStr(parts[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid outpoint %v: %v", s, err)
+	}
+	index, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid outpoint %v: %v", s, err)
+	}
+
+	return wire.NewOutPoint(hash, uint32(index)), nil
+}
+
+// parseHexPubKey parses a hex encoded public key.
+func parseHexPubKey(s string) (*btcec.PublicKey, error) {
+	keyBytes, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return btcec.ParsePubKey(keyBytes, btcec.S256())
+}
@@ -0,0 +1,125 @@
+package chanbackup
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/chaincfg"
+	"github.com/roasbeef/btcutil"
+)
+
+func makeTestExternalChannel(t *testing.T) ExternalChannel {
+	keys := make([]*btcec.PrivateKey, 3)
+	for i := range keys {
+		key, err := btcec.NewPrivateKey(btcec.S256())
+		if err != nil {
+			t.Fatalf("unable to create key: %v", err)
+		}
+		keys[i] = key
+	}
+
+	wif, err := btcutil.NewWIF(keys[0], &chaincfg.TestNet3Params, true)
+	if err != nil {
+		t.Fatalf("unable to encode key: %v", err)
+	}
+
+	return ExternalChannel{
+		FundingOutpoint: "a1b2c3d4e5f60718293a4b5c6d7e8f90a1b2c3d4e5f60718" +
+			"293a4b5c6d7e8f90:1",
+		RemoteNodePub: hex.EncodeToString(
+			keys[1].PubKey().SerializeCompressed()),
+		RemoteAddr:   "127.0.0.1:10011",
+		Capacity:     1000000,
+		LocalBalance: 400000,
+		IsInitiator:  true,
+		FundingKey:   wif.String(),
+		RemoteFundingPub: hex.EncodeToString(
+			keys[2].PubKey().SerializeCompressed()),
+		RemoteDeliveryScript: "0014" +
+			"000102030405060708090a0b0c0d0e0f10111213",
+	}
+}
+
+func TestExternalChannelRecover(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "chanbackup")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	cdb, err := channeldb.Open(tempDir)
+	if err != nil {
+		t.Fatalf("unable to open db: %v", err)
+	}
+	defer cdb.Close()
+
+	external := makeTestExternalChannel(t)
+	deliveryScript := []byte{0x00, 0x14, 0x01}
+
+	recovered, err := external.Recover(cdb, &chaincfg.TestNet3Params,
+		deliveryScript)
+	if err != nil {
+		t.Fatalf("unable to recover channel: %v", err)
+	}
+
+	channel := recovered.Channel
+	if channel.TheirBalance != 600000 {
+		t.Fatalf("expected remote balance of 600000, got %v",
+			channel.TheirBalance)
+	}
+	if !recovered.FundingKey.PubKey().IsEqual(channel.OurMultiSigKey) {
+		t.Fatalf("funding key doesn't match multi-sig key")
+	}
+
+	// The recovered channel should survive a round trip through the
+	// database as an open channel.
+	if err := channel.SyncPending(recovered.RemoteAddr); err != nil {
+		t.Fatalf("unable to write channel: %v", err)
+	}
+	if err := cdb.MarkChannelAsOpen(channel.ChanID); err != nil {
+		t.Fatalf("unable to mark channel open: %v", err)
+	}
+	channels, err := cdb.FetchOpenChannels(channel.IdentityPub)
+	if err != nil {
+		t.Fatalf("unable to fetch channels: %v", err)
+	}
+	if len(channels) != 1 {
+		t.Fatalf("expected 1 channel, got %v", len(channels))
+	}
+	if channels[0].ChanType != channeldb.RecoveredChannel {
+		t.Fatalf("expected recovered channel type, got %v",
+			channels[0].ChanType)
+	}
+	if *channels[0].ChanID != *channel.ChanID {
+		t.Fatalf("channel points don't match: %v vs %v",
+			channels[0].ChanID, channel.ChanID)
+	}
+	if string(channels[0].OurDeliveryScript) != string(deliveryScript) {
+		t.Fatalf("fresh delivery script wasn't used")
+	}
+
+	// Recovery data which is inconsistent, or for another network should
+	// be rejected.
+	invalid := []func(e *ExternalChannel){
+		func(e *ExternalChannel) { e.LocalBalance = 2000000 },
+		func(e *ExternalChannel) { e.FundingOutpoint = "a1b2c3d4" },
+		func(e *ExternalChannel) { e.RemoteDeliveryScript = "" },
+		func(e *ExternalChannel) { e.RemoteFundingPub = "02" },
+	}
+	for i, modify := range invalid {
+		external := makeTestExternalChannel(t)
+		modify(&external)
+		if _, err := external.Recover(cdb, &chaincfg.TestNet3Params,
+			deliveryScript); err == nil {
+			t.Fatalf("#%v: invalid recovery data accepted", i)
+		}
+	}
+	if _, err := external.Recover(cdb, &chaincfg.MainNetParams,
+		deliveryScript); err == nil {
+		t.Fatalf("funding key for wrong network accepted")
+	}
+}
@@ -0,0 +1,92 @@
+package chanbackup
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+)
+
+// tempFileSuffix is appended to the name of a MultiFile to form the name of
+// the temporary file a new backup is written to before it replaces the
+// previous one.
+const tempFileSuffix = ".tmp"
+
+// MultiFile is a file on disk holding a packed Multi. Each update replaces the
+// entire file, which is done atomically so the file always contains a
+// complete backup, even if the update is interrupted.
+type MultiFile struct {
+	// fileName is the path of the backup file.
+	fileName string
+
+	// tempFileName is the path of the temporary file each new backup is
+	// written to before being swapped into place.
+	tempFileName string
+}
+
+// NewMultiFile creates a new MultiFile backed by the file at the passed path.
+func NewMultiFile(fileName string) *MultiFile {
+	return &MultiFile{
+		fileName:     fileName,
+		tempFileName: fileName + tempFileSuffix,
+	}
+}
+
+// UpdateAndSwap packs the passed Multi with the passed key, then replaces the
+// current contents of the backup file with it. The new backup is first
+// written and synced to a temporary file, which is then renamed over the
+// backup file.
+func (f *MultiFile) UpdateAndSwap(multi *Multi, key [32]byte) error {
+	var b bytes.Buffer
+	if err := multi.PackToWriter(&b, key); err != nil {
+		return err
+	}
+
+	return writeFileAtomic(f.fileName, f.tempFileName, b.Bytes())
+}
+
+// ReadPacked returns the packed, encrypted Multi within the backup file.
+func (f *MultiFile) ReadPacked() ([]byte, error) {
+	return ioutil.ReadFile(f.fileName)
+}
+
+// ExtractMulti reads the backup file, then decrypts and unpacks the Multi
+// within it using the passed key.
+func (f *MultiFile) ExtractMulti(key [32]byte) (*Multi, error) {
+	packed, err := f.ReadPacked()
+	if err != nil {
+		return nil, err
+	}
+
+	var multi Multi
+	if err := multi.UnpackFromReader(bytes.NewReader(packed), key); err != nil {
+		return nil, err
+	}
+
+	return &multi, nil
+}
+
+// writeFileAtomic replaces the contents of the named file with the passed
+// data. The data is first written and synced to the temporary file, which is
+// then renamed over the file, so the file is never left partially written.
+func writeFileAtomic(fileName, tempFileName string, data []byte) error {
+	tempFile, err := os.OpenFile(tempFileName,
+		os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("unable to create temporary backup file: "+
+			"%v", err)
+	}
+	if _, err := tempFile.Write(data); err != nil {
+		tempFile.Close()
+		return err
+	}
+	if err := tempFile.Sync(); err != nil {
+		tempFile.Close()
+		return err
+	}
+	if err := tempFile.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tempFileName, fileName)
+}
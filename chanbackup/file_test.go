@@ -0,0 +1,59 @@
+package chanbackup
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestMultiFileUpdateAndSwap tests that each update of a MultiFile replaces
+// the backup within it, without leaving the temporary file behind.
+func TestMultiFileUpdateAndSwap(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "chanbackup")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	fileName := filepath.Join(tempDir, "channel.backup")
+	multiFile := NewMultiFile(fileName)
+	key := DeriveBackupKey(testKey)
+
+	// Until the first update, there's no backup to extract.
+	if _, err := multiFile.ExtractMulti(key); !os.IsNotExist(err) {
+		t.Fatalf("expected missing file, got %v", err)
+	}
+
+	for i := uint32(1); i <= 2; i++ {
+		multi := &Multi{}
+		for j := uint32(0); j < i; j++ {
+			multi.StaticBackups = append(multi.StaticBackups,
+				makeTestSingle(t, j))
+		}
+		if err := multiFile.UpdateAndSwap(multi, key); err != nil {
+			t.Fatalf("unable to update backup file: %v", err)
+		}
+
+		extracted, err := multiFile.ExtractMulti(key)
+		if err != nil {
+			t.Fatalf("unable to extract backup: %v", err)
+		}
+		if len(extracted.StaticBackups) != int(i) {
+			t.Fatalf("expected %v backups, got %v", i,
+				len(extracted.StaticBackups))
+		}
+		for j, single := range extracted.StaticBackups {
+			expected := multi.StaticBackups[j]
+			if single.ChanPoint != expected.ChanPoint {
+				t.Fatalf("backup #%v: expected ChannelPoint(%v), "+
+					"got ChannelPoint(%v)", j,
+					expected.ChanPoint, single.ChanPoint)
+			}
+		}
+
+		if _, err := os.Stat(fileName + tempFileSuffix); !os.IsNotExist(err) {
+			t.Fatalf("temporary backup file wasn't removed: %v", err)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package chanbackup
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+)
+
+const (
+	// multiVersion is the current version of the Multi serialization
+	// format, within which each Single is prefixed by its version.
+	multiVersion = 1
+
+	// legacyMultiVersion is the version of the Multi serialization format
+	// prior to the introduction of Single versions, within which all
+	// Singles are legacy channels without a version prefix. Multis of this
+	// version are still read, but no longer written.
+	legacyMultiVersion = 0
+)
+
+// Multi is a collection of static backups, one for each of our channels. A
+// Multi is always stored encrypted, see PackToWriter and UnpackFromReader.
+type Multi struct {
+	// StaticBackups is the set of channel backups within the Multi.
+	StaticBackups []Single
+}
+
+// FetchMulti creates a Multi containing a backup of every channel
+// currently stored within the database, including pending channels.
+func FetchMulti(cdb *channeldb.DB) (*Multi, error) {
+	channels, err := cdb.FetchAllChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	multi := &Multi{
+		StaticBackups: make([]Single, len(channels)),
+	}
+	for i, channel := range channels {
+		multi.StaticBackups[i] = NewSingle(channel)
+	}
+
+	return multi, nil
+}
+
+// PackToWriter serializes the Multi, then encrypts it with the passed key,
+// writing the result to w.
+func (m *Multi) PackToWriter(w io.Writer, key [32]byte) error {
+	var b bytes.Buffer
+
+	var scratch [4]byte
+	b.WriteByte(multiVersion)
+	byteOrder.PutUint32(scratch[:], uint32(len(m.StaticBackups)))
+	b.Write(scratch[:])
+
+	for _, single := range m.StaticBackups {
+		if err := single.Serialize(&b); err != nil {
+			return err
+		}
+	}
+
+	return encryptPayloadToWriter(b.Bytes(), w, key)
+}
+
+// UnpackFromReader decrypts an encrypted Multi read from r using the passed
+// key, then deserializes it.
+func (m *Multi) UnpackFromReader(r io.Reader, key [32]byte) error {
+	plaintext, err := decryptPayloadFromReader(r, key)
+	if err != nil {
+		return err
+	}
+	b := bytes.NewReader(plaintext)
+
+	version, err := b.ReadByte()
+	if err != nil {
+		return err
+	}
+	if version != multiVersion && version != legacyMultiVersion {
+		return fmt.Errorf("unknown backup version: %v, a newer "+
+			"version of lnd is required to restore it", version)
+	}
+
+	var scratch [4]byte
+	if _, err := io.ReadFull(b, scratch[:]); err != nil {
+		return err
+	}
+	numBackups := byteOrder.Uint32(scratch[:])
+
+	// Rather than trusting the count to size the slice up front, we
+	// append each backup as it's read so a corrupted count simply results
+	// in a read error.
+	m.StaticBackups = nil
+	for i := uint32(0); i < numBackups; i++ {
+		var single Single
+		if version == legacyMultiVersion {
+			err = single.deserializeLegacy(b)
+		} else {
+			err = single.Deserialize(b)
+		}
+		if err != nil {
+			return err
+		}
+
+		m.StaticBackups = append(m.StaticBackups, single)
+	}
+
+	return nil
+}
@@ -0,0 +1,241 @@
+package chanbackup
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+const (
+	// peerBlobVersion is the current version of the PeerBlob
+	// serialization format, within which each channel's Single is
+	// prefixed by its version.
+	peerBlobVersion = 1
+
+	// legacyPeerBlobVersion is the version of the PeerBlob serialization
+	// format prior to the introduction of Single versions. Blobs of this
+	// version, which peers may still hold, are read but no longer
+	// written.
+	legacyPeerBlobVersion = 0
+
+	// maxDeliveryScriptLen is the maximum length of a delivery script
+	// within a PeerChannel. The largest standard script is that of a
+	// pay-to-witness-script-hash output.
+	maxDeliveryScriptLen = 34
+)
+
+// PeerChannel is a backup of a single channel which, unlike a Single, also
+// records the latest settled balances and delivery scripts of the channel.
+// This is sufficient to reconstruct a channel which can be cooperatively
+// closed with the remote party, even without any other record of the
+// channel.
+type PeerChannel struct {
+	Single
+
+	// TheirMultiSigKey is the remote party's key within the funding
+	// output's multi-sig script.
+	TheirMultiSigKey *btcec.PublicKey
+
+	// NumUpdates is the number of state updates the channel had gone
+	// through at the time of the backup.
+	NumUpdates uint64
+
+	// OurBalance and TheirBalance are the settled balances of each party
+	// as of the backed up state.
+	OurBalance   btcutil.Amount
+	TheirBalance btcutil.Amount
+
+	// OurDeliveryScript and TheirDeliveryScript are the scripts each
+	// party's balance is paid to upon a cooperative close.
+	OurDeliveryScript   []byte
+	TheirDeliveryScript []byte
+}
+
+// NewPeerChannel creates a backup of the latest state of the passed channel.
+func NewPeerChannel(channel *channeldb.OpenChannel) PeerChannel {
+	return PeerChannel{
+		Single:              NewSingle(channel),
+		TheirMultiSigKey:    channel.TheirMultiSigKey,
+		NumUpdates:          channel.NumUpdates,
+		OurBalance:          channel.OurBalance,
+		TheirBalance:        channel.TheirBalance,
+		OurDeliveryScript:   channel.OurDeliveryScript,
+		TheirDeliveryScript: channel.TheirDeliveryScript,
+	}
+}
+
+// Serialize writes the binary serialization of the backup to w.
+func (c *PeerChannel) Serialize(w io.Writer) error {
+	if err := c.Single.Serialize(w); err != nil {
+		return err
+	}
+
+	if _, err := w.Write(c.TheirMultiSigKey.SerializeCompressed()); err != nil {
+		return err
+	}
+
+	var scratch [8]byte
+	for _, n := range []uint64{
+		c.NumUpdates, uint64(c.OurBalance), uint64(c.TheirBalance),
+	} {
+		byteOrder.PutUint64(scratch[:], n)
+		if _, err := w.Write(scratch[:]); err != nil {
+			return err
+		}
+	}
+
+	if err := wire.WriteVarBytes(w, 0, c.OurDeliveryScript); err != nil {
+		return err
+	}
+	return wire.WriteVarBytes(w, 0, c.TheirDeliveryScript)
+}
+
+// Deserialize reads a backup from its binary serialization within r.
+func (c *PeerChannel) Deserialize(r io.Reader) error {
+	if err := c.Single.Deserialize(r); err != nil {
+		return err
+	}
+
+	return c.deserializeState(r)
+}
+
+// deserializeState reads the portion of the backup following its Single from
+// r.
+func (c *PeerChannel) deserializeState(r io.Reader) error {
+	var err error
+	if c.TheirMultiSigKey, err = readPubKey(r); err != nil {
+		return err
+	}
+
+	var scratch [8]byte
+	if _, err := io.ReadFull(r, scratch[:]); err != nil {
+		return err
+	}
+	c.NumUpdates = byteOrder.Uint64(scratch[:])
+	if _, err := io.ReadFull(r, scratch[:]); err != nil {
+		return err
+	}
+	c.OurBalance = btcutil.Amount(byteOrder.Uint64(scratch[:]))
+	if _, err := io.ReadFull(r, scratch[:]); err != nil {
+		return err
+	}
+	c.TheirBalance = btcutil.Amount(byteOrder.Uint64(scratch[:]))
+
+	c.OurDeliveryScript, err = wire.ReadVarBytes(r, 0,
+		maxDeliveryScriptLen, "delivery script")
+	if err != nil {
+		return err
+	}
+	c.TheirDeliveryScript, err = wire.ReadVarBytes(r, 0,
+		maxDeliveryScriptLen, "delivery script")
+	return err
+}
+
+// Recover reconstructs a minimal channel record of type
+// channeldb.RecoveredChannel from the backup, which may only be used to
+// cooperatively close the channel.
+//
+// NOTE: The returned channel hasn't yet been written to the database.
+func (c *PeerChannel) Recover(cdb *channeldb.DB) (*channeldb.OpenChannel, error) {
+	switch {
+	case c.Capacity <= 0:
+		return nil, fmt.Errorf("capacity must be positive")
+	case c.OurBalance < 0 || c.TheirBalance < 0:
+		return nil, fmt.Errorf("balances must not be negative")
+	case c.OurBalance+c.TheirBalance > c.Capacity:
+		return nil, fmt.Errorf("balances exceed the channel capacity")
+	case len(c.OurDeliveryScript) == 0 || len(c.TheirDeliveryScript) == 0:
+		return nil, fmt.Errorf("delivery scripts are missing")
+	}
+
+	return newRecoveredChannel(cdb, c)
+}
+
+// PeerBlob is the backup of each of our channels with a single peer, which
+// is stored with the peer itself. A PeerBlob is always exchanged encrypted,
+// see PackToWriter and UnpackFromReader.
+type PeerBlob struct {
+	// Channels is the set of channel backups within the blob.
+	Channels []PeerChannel
+}
+
+// NewPeerBlob creates a PeerBlob containing a backup of the latest state of
+// each of the passed channels.
+func NewPeerBlob(channels []*channeldb.OpenChannel) *PeerBlob {
+	blob := &PeerBlob{
+		Channels: make([]PeerChannel, len(channels)),
+	}
+	for i, channel := range channels {
+		blob.Channels[i] = NewPeerChannel(channel)
+	}
+
+	return blob
+}
+
+// PackToWriter serializes the PeerBlob, then encrypts it with the passed key,
+// writing the result to w.
+func (p *PeerBlob) PackToWriter(w io.Writer, key [32]byte) error {
+	var b bytes.Buffer
+
+	var scratch [4]byte
+	b.WriteByte(peerBlobVersion)
+	byteOrder.PutUint32(scratch[:], uint32(len(p.Channels)))
+	b.Write(scratch[:])
+
+	for _, channel := range p.Channels {
+		if err := channel.Serialize(&b); err != nil {
+			return err
+		}
+	}
+
+	return encryptPayloadToWriter(b.Bytes(), w, key)
+}
+
+// UnpackFromReader decrypts an encrypted PeerBlob read from r using the
+// passed key, then deserializes it.
+func (p *PeerBlob) UnpackFromReader(r io.Reader, key [32]byte) error {
+	plaintext, err := decryptPayloadFromReader(r, key)
+	if err != nil {
+		return err
+	}
+	b := bytes.NewReader(plaintext)
+
+	version, err := b.ReadByte()
+	if err != nil {
+		return err
+	}
+	if version != peerBlobVersion && version != legacyPeerBlobVersion {
+		return fmt.Errorf("unknown peer backup version: %v", version)
+	}
+
+	var scratch [4]byte
+	if _, err := io.ReadFull(b, scratch[:]); err != nil {
+		return err
+	}
+	numChannels := byteOrder.Uint32(scratch[:])
+
+	p.Channels = nil
+	for i := uint32(0); i < numChannels; i++ {
+		var channel PeerChannel
+		if version == legacyPeerBlobVersion {
+			err = channel.Single.deserializeLegacy(b)
+			if err == nil {
+				err = channel.deserializeState(b)
+			}
+		} else {
+			err = channel.Deserialize(b)
+		}
+		if err != nil {
+			return err
+		}
+
+		p.Channels = append(p.Channels, channel)
+	}
+
+	return nil
+}
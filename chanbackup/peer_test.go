@@ -0,0 +1,116 @@
+package chanbackup
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/roasbeef/btcd/btcec"
+)
+
+func makeTestPeerChannel(t *testing.T, index uint32) PeerChannel {
+	theirKey, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to create key: %v", err)
+	}
+
+	return PeerChannel{
+		Single:           makeTestSingle(t, index),
+		TheirMultiSigKey: theirKey.PubKey(),
+		NumUpdates:       42,
+		OurBalance:       400000,
+		TheirBalance:     590000,
+		OurDeliveryScript: append([]byte{0x00, 0x14},
+			bytes.Repeat([]byte{0x01}, 20)...),
+		TheirDeliveryScript: append([]byte{0x00, 0x14},
+			bytes.Repeat([]byte{0x02}, 20)...),
+	}
+}
+
+func TestPeerBlobPackUnpack(t *testing.T) {
+	blob := &PeerBlob{
+		Channels: []PeerChannel{
+			makeTestPeerChannel(t, 0),
+			makeTestPeerChannel(t, 1),
+		},
+	}
+
+	key := DeriveBackupKey(testKey)
+
+	var b bytes.Buffer
+	if err := blob.PackToWriter(&b, key); err != nil {
+		t.Fatalf("unable to pack blob: %v", err)
+	}
+	packed := b.Bytes()
+
+	var unpacked PeerBlob
+	if err := unpacked.UnpackFromReader(bytes.NewReader(packed), key); err != nil {
+		t.Fatalf("unable to unpack blob: %v", err)
+	}
+	for i := range unpacked.Channels {
+		for _, c := range []*PeerChannel{
+			&unpacked.Channels[i], &blob.Channels[i],
+		} {
+			c.RemoteNodePub.Curve = nil
+			c.OurMultiSigKey.Curve = nil
+			c.TheirMultiSigKey.Curve = nil
+		}
+	}
+	if !reflect.DeepEqual(blob, &unpacked) {
+		t.Fatalf("blobs don't match: expected %v got %v",
+			spew.Sdump(blob), spew.Sdump(unpacked))
+	}
+
+	// The peer storing the blob, or anyone else, shouldn't be able to
+	// decrypt it.
+	otherKey, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to create key: %v", err)
+	}
+	err = unpacked.UnpackFromReader(bytes.NewReader(packed),
+		DeriveBackupKey(otherKey))
+	if err != ErrDecryptFailed {
+		t.Fatalf("expected ErrDecryptFailed, got %v", err)
+	}
+}
+
+func TestPeerChannelRecover(t *testing.T) {
+	backup := makeTestPeerChannel(t, 0)
+
+	channel, err := backup.Recover(&channeldb.DB{})
+	if err != nil {
+		t.Fatalf("unable to recover channel: %v", err)
+	}
+	if channel.ChanType != channeldb.RecoveredChannel {
+		t.Fatalf("expected recovered channel type, got %v",
+			channel.ChanType)
+	}
+	if *channel.ChanID != backup.ChanPoint {
+		t.Fatalf("channel points don't match: %v vs %v",
+			channel.ChanID, backup.ChanPoint)
+	}
+	if channel.OurBalance != backup.OurBalance ||
+		channel.TheirBalance != backup.TheirBalance {
+
+		t.Fatalf("balances weren't restored")
+	}
+	if !bytes.Equal(channel.TheirDeliveryScript, backup.TheirDeliveryScript) {
+		t.Fatalf("remote delivery script wasn't restored")
+	}
+
+	// Backups which are inconsistent should be rejected.
+	invalid := []func(c *PeerChannel){
+		func(c *PeerChannel) { c.OurBalance = 2000000 },
+		func(c *PeerChannel) { c.Capacity = 0 },
+		func(c *PeerChannel) { c.TheirDeliveryScript = nil },
+	}
+	for i, modify := range invalid {
+		backup := makeTestPeerChannel(t, 0)
+		modify(&backup)
+		if _, err := backup.Recover(&channeldb.DB{}); err == nil {
+			t.Fatalf("#%v: invalid backup accepted", i)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+// Package chanbackup implements static channel backups: a compact, encrypted
+// summary of each of our channels containing the minimal information needed
+// to locate the channel's funds on-chain and contact the remote party in
+// order to recover them.
+package chanbackup
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+// byteOrder is the byte order used to serialize all integers within a
+// backup.
+var byteOrder = binary.BigEndian
+
+// SingleBackupVersion denotes the type of the channel a Single backs up. The
+// procedure used to recover a channel's funds depends on its type, so the
+// version determines how a restore of the channel must proceed.
+type SingleBackupVersion byte
+
+const (
+	// LegacyVersion is the version of channels whose commitments derive
+	// their revocations from a shachain (formerly elkrem), and don't carry
+	// anchor outputs. All channels created by this version of lnd are of
+	// this type.
+	LegacyVersion SingleBackupVersion = 0
+
+	// AnchorsVersion is the version of channels whose commitments carry
+	// anchor outputs, allowing either party to bump the fee of a
+	// commitment by spending its anchor. This version of lnd can't
+	// recover channels of this type.
+	AnchorsVersion SingleBackupVersion = 1
+
+	// TaprootVersion is the version of channels whose funding output is a
+	// taproot output. This version of lnd can't recover channels of this
+	// type.
+	TaprootVersion SingleBackupVersion = 2
+)
+
+// String returns a human readable version of the SingleBackupVersion.
+func (v SingleBackupVersion) String() string {
+	switch v {
+	case LegacyVersion:
+		return "legacy"
+	case AnchorsVersion:
+		return "anchors"
+	case TaprootVersion:
+		return "taproot"
+	default:
+		return fmt.Sprintf("unknown(%d)", byte(v))
+	}
+}
+
+// errUnsupportedVersion returns the error for a backup of a channel whose type
+// this version of lnd doesn't know how to recover. Recovering the channel
+// using the procedure of another type could forfeit its funds, so the backup
+// must instead be restored by a version of lnd supporting the type.
+func errUnsupportedVersion(version SingleBackupVersion) error {
+	return fmt.Errorf("backup contains a channel of type %v, which this "+
+		"version of lnd can't recover, a newer version of lnd is "+
+		"required to restore it", version)
+}
+
+// Single is a static backup of a single channel. It only contains the
+// information which remains fixed over the lifetime of the channel, meaning
+// it doesn't need to be updated with each state transition.
+type Single struct {
+	// Version is the type of the backed up channel, which determines the
+	// procedure used to recover it.
+	Version SingleBackupVersion
+
+	// ChanPoint is the funding outpoint of the channel.
+	ChanPoint wire.OutPoint
+
+	// RemoteNodePub is the identity public key of the remote node the
+	// channel is open with.
+	RemoteNodePub *btcec.PublicKey
+
+	// Capacity is the total capacity of the channel.
+	Capacity btcutil.Amount
+
+	// IsInitiator denotes whether we funded the channel.
+	IsInitiator bool
+
+	// LocalCsvDelay is the relative delay applied to outputs paying to us
+	// within our commitment transaction.
+	LocalCsvDelay uint32
+
+	// RemoteCsvDelay is the relative delay applied to outputs paying to
+	// the remote party within their commitment transaction.
+	RemoteCsvDelay uint32
+
+	// OurMultiSigKey is our key within the funding output's multi-sig
+	// script. The wallet indexes its keys by public key, so this is
+	// sufficient to locate the key required to spend the funding output.
+	OurMultiSigKey *btcec.PublicKey
+}
+
+// NewSingle creates a static backup of the passed channel.
+func NewSingle(channel *channeldb.OpenChannel) Single {
+	return Single{
+		Version:        LegacyVersion,
+		ChanPoint:      *channel.ChanID,
+		RemoteNodePub:  channel.IdentityPub,
+		Capacity:       channel.Capacity,
+		IsInitiator:    channel.IsInitiator,
+		LocalCsvDelay:  channel.LocalCsvDelay,
+		RemoteCsvDelay: channel.RemoteCsvDelay,
+		OurMultiSigKey: channel.OurMultiSigKey,
+	}
+}
+
+// Serialize writes the binary serialization of the backup to w, prefixed by
+// its version.
+func (s *Single) Serialize(w io.Writer) error {
+	if s.Version != LegacyVersion {
+		return errUnsupportedVersion(s.Version)
+	}
+	if _, err := w.Write([]byte{byte(s.Version)}); err != nil {
+		return err
+	}
+
+	var scratch [8]byte
+
+	if _, err := w.Write(s.ChanPoint.Hash[:]); err != nil {
+		return err
+	}
+	byteOrder.PutUint32(scratch[:4], s.ChanPoint.Index)
+	if _, err := w.Write(scratch[:4]); err != nil {
+		return err
+	}
+
+	if _, err := w.Write(s.RemoteNodePub.SerializeCompressed()); err != nil {
+		return err
+	}
+
+	byteOrder.PutUint64(scratch[:], uint64(s.Capacity))
+	if _, err := w.Write(scratch[:]); err != nil {
+		return err
+	}
+
+	var initiator [1]byte
+	if s.IsInitiator {
+		initiator[0] = 1
+	}
+	if _, err := w.Write(initiator[:]); err != nil {
+		return err
+	}
+
+	byteOrder.PutUint32(scratch[:4], s.LocalCsvDelay)
+	if _, err := w.Write(scratch[:4]); err != nil {
+		return err
+	}
+	byteOrder.PutUint32(scratch[:4], s.RemoteCsvDelay)
+	if _, err := w.Write(scratch[:4]); err != nil {
+		return err
+	}
+
+	_, err := w.Write(s.OurMultiSigKey.SerializeCompressed())
+	return err
+}
+
+// Deserialize reads a backup from its binary serialization within r. If the
+// backup is of a channel type this version of lnd can't recover, then an
+// error is returned.
+func (s *Single) Deserialize(r io.Reader) error {
+	var version [1]byte
+	if _, err := io.ReadFull(r, version[:]); err != nil {
+		return err
+	}
+
+	s.Version = SingleBackupVersion(version[0])
+	if s.Version != LegacyVersion {
+		return errUnsupportedVersion(s.Version)
+	}
+
+	return s.deserializeLegacy(r)
+}
+
+// deserializeLegacy reads the serialization of a legacy backup within r,
+// without a version prefix. Backups were serialized this way prior to the
+// introduction of versions, when all channels were legacy channels.
+func (s *Single) deserializeLegacy(r io.Reader) error {
+	s.Version = LegacyVersion
+
+	var scratch [8]byte
+
+	if _, err := io.ReadFull(r, s.ChanPoint.Hash[:]); err != nil {
+		return err
+	}
+	if _, err := io.ReadFull(r, scratch[:4]); err != nil {
+		return err
+	}
+	s.ChanPoint.Index = byteOrder.Uint32(scratch[:4])
+
+	var err error
+	if s.RemoteNodePub, err = readPubKey(r); err != nil {
+		return err
+	}
+
+	if _, err := io.ReadFull(r, scratch[:]); err != nil {
+		return err
+	}
+	s.Capacity = btcutil.Amount(byteOrder.Uint64(scratch[:]))
+
+	if _, err := io.ReadFull(r, scratch[:1]); err != nil {
+		return err
+	}
+	s.IsInitiator = scratch[0] == 1
+
+	if _, err := io.ReadFull(r, scratch[:4]); err != nil {
+		return err
+	}
+	s.LocalCsvDelay = byteOrder.Uint32(scratch[:4])
+	if _, err := io.ReadFull(r, scratch[:4]); err != nil {
+		return err
+	}
+	s.RemoteCsvDelay = byteOrder.Uint32(scratch[:4])
+
+	s.OurMultiSigKey, err = readPubKey(r)
+	return err
+}
+
+// readPubKey reads a compressed public key from r.
+func readPubKey(r io.Reader) (*btcec.PublicKey, error) {
+	var keyBytes [33]byte
+	if _, err := io.ReadFull(r, keyBytes[:]); err != nil {
+		return nil, err
+	}
+
+	return btcec.ParsePubKey(keyBytes[:], btcec.S256())
+}
@@ -0,0 +1,101 @@
+package chanbackup
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+// UploadFileName is the name of the copy of the backup file held by each
+// upload target, within the directory or prefix the target specifies.
+const UploadFileName = "channel.backup"
+
+// UploadTarget is a destination off the machine, such as a remote server or
+// object store, which holds a copy of the backup file. As the backup is
+// encrypted, the target needn't be trusted with the channels' secrets.
+type UploadTarget interface {
+	// Upload replaces the copy of the backup held by the target with the
+	// passed packed Multi.
+	Upload(packedMulti []byte) error
+
+	// String returns a description of the target which is safe to log,
+	// as it omits any credentials.
+	String() string
+}
+
+// ParseUploadTarget parses an upload target from its URL. The supported
+// targets are:
+//
+//   - file:///path/to/dir, a local directory, typically the mount point of
+//     removable or network storage.
+//   - sftp://user@host[:port]/path/to/dir?identity=key_file[&knownhosts=file],
+//     a directory on an SFTP server, authenticated with the private key
+//     within identity. The server's host key must be present in knownhosts,
+//     which defaults to ~/.ssh/known_hosts.
+//   - s3://bucket[/prefix][?endpoint=host[:port]][&region=region], a bucket
+//     within S3 compatible object storage, which defaults to Amazon S3
+//     within us-east-1. The credentials are read from the
+//     AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY environment variables.
+func ParseUploadTarget(target string) (UploadTarget, error) {
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil, fmt.Errorf("invalid upload target %q: %v", target,
+			err)
+	}
+
+	switch u.Scheme {
+	case "file":
+		if u.Path == "" || !filepath.IsAbs(u.Path) {
+			return nil, fmt.Errorf("invalid upload target %q: path "+
+				"must be absolute", target)
+		}
+		return NewDirTarget(u.Path), nil
+
+	case "sftp":
+		return newSFTPTarget(u)
+
+	case "s3":
+		return newS3Target(u)
+
+	default:
+		return nil, fmt.Errorf("invalid upload target %q: unsupported "+
+			"scheme %q", target, u.Scheme)
+	}
+}
+
+// DirTarget is an UploadTarget which copies the backup into a local
+// directory.
+type DirTarget struct {
+	dir string
+}
+
+// NewDirTarget creates a new DirTarget copying the backup into the passed
+// directory.
+func NewDirTarget(dir string) *DirTarget {
+	return &DirTarget{
+		dir: dir,
+	}
+}
+
+// Upload atomically replaces the copy of the backup within the directory.
+//
+// NOTE: This is part of the UploadTarget interface.
+func (d *DirTarget) Upload(packedMulti []byte) error {
+	// The directory is likely a mount point, so we don't create it, as
+	// the backup would otherwise silently be written to the local disk
+	// while the storage is unmounted.
+	if _, err := os.Stat(d.dir); err != nil {
+		return err
+	}
+
+	fileName := filepath.Join(d.dir, UploadFileName)
+	return writeFileAtomic(fileName, fileName+tempFileSuffix, packedMulti)
+}
+
+// String returns the URL of the target.
+//
+// NOTE: This is part of the UploadTarget interface.
+func (d *DirTarget) String() string {
+	return (&url.URL{Scheme: "file", Path: d.dir}).String()
+}
@@ -0,0 +1,211 @@
+package chanbackup
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultS3Endpoint and defaultS3Region locate the object store
+	// uploaded to when an S3 target doesn't specify one.
+	defaultS3Endpoint = "s3.amazonaws.com"
+	defaultS3Region   = "us-east-1"
+
+	// s3UploadTimeout is the time an upload to an S3 target has to
+	// complete before it's considered failed.
+	s3UploadTimeout = time.Minute
+
+	// s3DateFormat is the format of the timestamp included within each
+	// signed request.
+	s3DateFormat = "20060102T150405Z"
+)
+
+// S3Target is an UploadTarget which stores the backup as an object within a
+// bucket of S3 compatible object storage. Requests are authenticated using
+// version 4 of the AWS signature scheme, which is supported by every major
+// S3 compatible store.
+type S3Target struct {
+	endpoint string
+	bucket   string
+	key      string
+	region   string
+
+	accessKey string
+	secretKey string
+
+	client *http.Client
+}
+
+// newS3Target creates a new S3Target from its URL, reading the credentials
+// from the environment.
+func newS3Target(u *url.URL) (*S3Target, error) {
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid upload target %v: bucket "+
+			"must be specified", u)
+	}
+
+	accessKey := os.Getenv("AWS_ACCESS_KEY_ID")
+	secretKey := os.Getenv("AWS_SECRET_ACCESS_KEY")
+	if accessKey == "" || secretKey == "" {
+		return nil, fmt.Errorf("upload target %v requires the "+
+			"AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY environment "+
+			"variables to be set", u)
+	}
+
+	query := u.Query()
+	endpoint := query.Get("endpoint")
+	if endpoint == "" {
+		endpoint = defaultS3Endpoint
+	}
+	region := query.Get("region")
+	if region == "" {
+		region = defaultS3Region
+	}
+
+	prefix := strings.Trim(u.Path, "/")
+	return &S3Target{
+		endpoint:  endpoint,
+		bucket:    u.Host,
+		key:       path.Join(prefix, UploadFileName),
+		region:    region,
+		accessKey: accessKey,
+		secretKey: secretKey,
+		client: &http.Client{
+			Timeout: s3UploadTimeout,
+		},
+	}, nil
+}
+
+// Upload puts the backup to its object within the bucket, replacing the
+// previous copy.
+//
+// NOTE: This is part of the UploadTarget interface.
+func (s *S3Target) Upload(packedMulti []byte) error {
+	// The bucket is addressed within the path rather than the host name,
+	// as not every S3 compatible store supports virtual hosted buckets.
+	objectURL := &url.URL{
+		Scheme: "https",
+		Host:   s.endpoint,
+		Path:   "/" + s.bucket + "/" + s.key,
+	}
+	req, err := http.NewRequest(
+		"PUT", objectURL.String(), bytes.NewReader(packedMulti),
+	)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	payloadHash := sha256.Sum256(packedMulti)
+	s.signRequest(req, hex.EncodeToString(payloadHash[:]), time.Now())
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := ioutil.ReadAll(resp.Body)
+		return fmt.Errorf("status %v: %s", resp.Status,
+			bytes.TrimSpace(body))
+	}
+
+	return nil
+}
+
+// signRequest signs the passed request, whose payload has the passed hex
+// encoded SHA-256 hash, using version 4 of the AWS signature scheme. Every
+// header of the request, along with its host, is covered by the signature.
+func (s *S3Target) signRequest(req *http.Request, payloadHash string,
+	now time.Time) {
+
+	now = now.UTC()
+	amzDate := now.Format(s3DateFormat)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	headers := map[string]string{
+		"host": host,
+	}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(
+			strings.Join(values, ","),
+		)
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders bytes.Buffer
+	for _, name := range names {
+		fmt.Fprintf(&canonicalHeaders, "%s:%s\n", name, headers[name])
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		strings.Replace(req.URL.Query().Encode(), "+", "%20", -1),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	canonicalHash := sha256.Sum256([]byte(canonicalRequest))
+
+	date := now.Format("20060102")
+	scope := strings.Join([]string{date, s.region, "s3", "aws4_request"},
+		"/")
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		hex.EncodeToString(canonicalHash[:]),
+	}, "\n")
+
+	// The signing key is derived by successively keying each component
+	// of the scope with the secret key.
+	signingKey := []byte("AWS4" + s.secretKey)
+	for _, component := range []string{date, s.region, "s3",
+		"aws4_request"} {
+
+		signingKey = hmacSHA256(signingKey, component)
+	}
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 "+
+		"Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKey, scope, signedHeaders, signature))
+}
+
+// hmacSHA256 returns the HMAC-SHA256 of the passed data under the passed key.
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// String returns the URL of the target, without its credentials.
+//
+// NOTE: This is part of the UploadTarget interface.
+func (s *S3Target) String() string {
+	return fmt.Sprintf("s3://%s/%s (%s, %s)", s.bucket, s.key,
+		s.endpoint, s.region)
+}
@@ -0,0 +1,152 @@
+package chanbackup
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/url"
+	"os/user"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+const (
+	// defaultSFTPPort is the port SFTP servers are connected to when the
+	// target doesn't specify one.
+	defaultSFTPPort = "22"
+
+	// sftpDialTimeout is the time we wait for the connection to an SFTP
+	// server, including the SSH handshake, to be established.
+	sftpDialTimeout = 30 * time.Second
+)
+
+// SFTPTarget is an UploadTarget which copies the backup into a directory on
+// an SFTP server. A new connection is made for each upload, as uploads are
+// infrequent.
+type SFTPTarget struct {
+	addr string
+	dir  string
+
+	sshConfig *ssh.ClientConfig
+}
+
+// newSFTPTarget creates a new SFTPTarget from its URL, loading the private
+// key and known hosts the URL specifies.
+func newSFTPTarget(u *url.URL) (*SFTPTarget, error) {
+	if u.User == nil || u.User.Username() == "" || u.Hostname() == "" {
+		return nil, fmt.Errorf("invalid upload target %v: user and "+
+			"host must be specified", u)
+	}
+	if _, ok := u.User.Password(); ok {
+		return nil, fmt.Errorf("invalid upload target %v: password "+
+			"authentication isn't supported, use identity", u)
+	}
+
+	query := u.Query()
+	identity := query.Get("identity")
+	if identity == "" {
+		return nil, fmt.Errorf("invalid upload target %v: identity "+
+			"must be specified", u)
+	}
+	keyBytes, err := ioutil.ReadFile(identity)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read identity of upload "+
+			"target %v: %v", u, err)
+	}
+	signer, err := ssh.ParsePrivateKey(keyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse identity of upload "+
+			"target %v: %v", u, err)
+	}
+
+	knownHostsFile := query.Get("knownhosts")
+	if knownHostsFile == "" {
+		usr, err := user.Current()
+		if err != nil {
+			return nil, err
+		}
+		knownHostsFile = filepath.Join(usr.HomeDir, ".ssh",
+			"known_hosts")
+	}
+	hostKeyCallback, err := knownhosts.New(knownHostsFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load known hosts of upload "+
+			"target %v: %v", u, err)
+	}
+
+	port := u.Port()
+	if port == "" {
+		port = defaultSFTPPort
+	}
+
+	dir := u.Path
+	if dir == "" {
+		dir = "."
+	}
+
+	return &SFTPTarget{
+		addr: net.JoinHostPort(u.Hostname(), port),
+		dir:  dir,
+		sshConfig: &ssh.ClientConfig{
+			User:            u.User.Username(),
+			Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
+			HostKeyCallback: hostKeyCallback,
+			Timeout:         sftpDialTimeout,
+		},
+	}, nil
+}
+
+// Upload writes the backup to a temporary file within the directory on the
+// server, which is then renamed over the previous copy.
+//
+// NOTE: This is part of the UploadTarget interface.
+func (s *SFTPTarget) Upload(packedMulti []byte) error {
+	sshClient, err := ssh.Dial("tcp", s.addr, s.sshConfig)
+	if err != nil {
+		return err
+	}
+	defer sshClient.Close()
+
+	client, err := sftp.NewClient(sshClient)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	fileName := path.Join(s.dir, UploadFileName)
+	tempFileName := fileName + tempFileSuffix
+
+	tempFile, err := client.Create(tempFileName)
+	if err != nil {
+		return err
+	}
+	if _, err := tempFile.Write(packedMulti); err != nil {
+		tempFile.Close()
+		return err
+	}
+	if err := tempFile.Close(); err != nil {
+		return err
+	}
+
+	// A plain SFTP rename fails if the destination exists, so we rely on
+	// the POSIX rename extension, which replaces it atomically.
+	return client.PosixRename(tempFileName, fileName)
+}
+
+// String returns the URL of the target, without its query.
+//
+// NOTE: This is part of the UploadTarget interface.
+func (s *SFTPTarget) String() string {
+	u := &url.URL{
+		Scheme: "sftp",
+		User:   url.User(s.sshConfig.User),
+		Host:   s.addr,
+		Path:   s.dir,
+	}
+	return u.String()
+}
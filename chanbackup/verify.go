@@ -0,0 +1,174 @@
+package chanbackup
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// ChannelSource is the source of live channel state that backups are
+// verified against. It's satisfied by *channeldb.DB.
+type ChannelSource interface {
+	// FetchAllChannels returns all open and pending channels.
+	FetchAllChannels() ([]*channeldb.OpenChannel, error)
+
+	// IsChannelClosed returns true if the channel with the passed
+	// funding outpoint has been closed.
+	IsChannelClosed(chanPoint *wire.OutPoint) (bool, error)
+}
+
+// ChannelStatus describes the state of a backed up channel with respect to
+// our live channel state.
+type ChannelStatus uint8
+
+const (
+	// StatusOpen indicates that the channel is still open, and the backup
+	// matches its live state.
+	StatusOpen ChannelStatus = iota
+
+	// StatusPending indicates that the channel's funding transaction has
+	// yet to confirm, and the backup matches its live state.
+	StatusPending
+
+	// StatusClosed indicates that the channel has already been closed.
+	StatusClosed
+
+	// StatusUnknown indicates that we hold no record of the channel, as
+	// is the case when restoring onto a fresh node.
+	StatusUnknown
+
+	// StatusMismatch indicates that the channel is still open, but the
+	// backup disagrees with its live state.
+	StatusMismatch
+)
+
+// String returns a human readable version of the ChannelStatus.
+func (c ChannelStatus) String() string {
+	switch c {
+	case StatusOpen:
+		return "open"
+	case StatusPending:
+		return "pending"
+	case StatusClosed:
+		return "closed"
+	case StatusUnknown:
+		return "unknown"
+	case StatusMismatch:
+		return "mismatch"
+	default:
+		return "<unknown>"
+	}
+}
+
+// ChannelReport is the result of verifying a single channel backup against
+// our live channel state.
+type ChannelReport struct {
+	// Backup is the channel backup that was verified.
+	Backup *Single
+
+	// Status is the state of the backed up channel.
+	Status ChannelStatus
+
+	// Problem describes the discrepancy between the backup and the live
+	// channel if the Status is StatusMismatch.
+	Problem string
+}
+
+// RecoveryAction describes what a restore of the backup would do for the
+// channel.
+func (c *ChannelReport) RecoveryAction() string {
+	switch c.Status {
+	case StatusOpen, StatusPending:
+		return "skip: channel is still active"
+	case StatusClosed:
+		return "skip: channel has already been closed"
+	case StatusMismatch:
+		return "skip: backup doesn't match live channel state"
+	default:
+		return fmt.Sprintf("recover %v channel: connect to %x and "+
+			"request a force close, which pays our balance to "+
+			"our wallet", c.Backup.Version,
+			c.Backup.RemoteNodePub.SerializeCompressed())
+	}
+}
+
+// VerifyMulti checks each backup within the passed Multi against the live
+// channel state returned by the source, producing a report for each.
+func VerifyMulti(multi *Multi, source ChannelSource) ([]*ChannelReport, error) {
+	channels, err := source.FetchAllChannels()
+	if err != nil {
+		return nil, err
+	}
+	liveChans := make(map[wire.OutPoint]*channeldb.OpenChannel)
+	for _, channel := range channels {
+		liveChans[*channel.ChanID] = channel
+	}
+
+	reports := make([]*ChannelReport, len(multi.StaticBackups))
+	for i := range multi.StaticBackups {
+		backup := &multi.StaticBackups[i]
+		report := &ChannelReport{
+			Backup: backup,
+		}
+		reports[i] = report
+
+		channel, ok := liveChans[backup.ChanPoint]
+		if !ok {
+			closed, err := source.IsChannelClosed(&backup.ChanPoint)
+			if err != nil {
+				return nil, err
+			}
+
+			if closed {
+				report.Status = StatusClosed
+			} else {
+				report.Status = StatusUnknown
+			}
+			continue
+		}
+
+		if problem := compareChannel(backup, channel); problem != "" {
+			report.Status = StatusMismatch
+			report.Problem = problem
+			continue
+		}
+
+		if channel.IsPending {
+			report.Status = StatusPending
+		} else {
+			report.Status = StatusOpen
+		}
+	}
+
+	return reports, nil
+}
+
+// compareChannel returns a description of the first discrepancy found
+// between the backup and the live channel, or an empty string if they match.
+func compareChannel(backup *Single, channel *channeldb.OpenChannel) string {
+	live := NewSingle(channel)
+
+	switch {
+	case backup.Version != live.Version:
+		return fmt.Sprintf("channel type mismatch: backup has %v, "+
+			"channel has %v", backup.Version, live.Version)
+	case !bytes.Equal(backup.RemoteNodePub.SerializeCompressed(),
+		live.RemoteNodePub.SerializeCompressed()):
+		return "remote node doesn't match"
+	case backup.Capacity != live.Capacity:
+		return fmt.Sprintf("capacity mismatch: backup has %v, "+
+			"channel has %v", backup.Capacity, live.Capacity)
+	case backup.IsInitiator != live.IsInitiator:
+		return "initiator mismatch"
+	case backup.LocalCsvDelay != live.LocalCsvDelay ||
+		backup.RemoteCsvDelay != live.RemoteCsvDelay:
+		return "csv delay mismatch"
+	case !bytes.Equal(backup.OurMultiSigKey.SerializeCompressed(),
+		live.OurMultiSigKey.SerializeCompressed()):
+		return "multi-sig key doesn't match"
+	}
+
+	return ""
+}
@@ -0,0 +1,289 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/chanbackup"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/roasbeef/btcd/btcec"
+)
+
+const (
+	// minBackupUploadBackoff and maxBackupUploadBackoff bound the delay
+	// between attempts to upload the backup to a failing target. The
+	// delay doubles after each failed attempt.
+	minBackupUploadBackoff = time.Second
+	maxBackupUploadBackoff = 10 * time.Minute
+)
+
+// backupUploadConfig defines the targets the backup file is uploaded to.
+type backupUploadConfig struct {
+	Targets []string `long:"target" description:"Upload the channel backup file to the given target each time it's updated, so a copy is held off the machine. Failed uploads are retried until they succeed. Targets are URLs of the form file:///path/to/dir for a local directory such as a mount point, sftp://user@host[:port]/path/to/dir?identity=key_file[&knownhosts=file] for an SFTP server, or s3://bucket[/prefix][?endpoint=host[:port]][&region=region] for S3 compatible object storage, whose credentials are read from the AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY environment variables. May be specified multiple times"`
+
+	// targets are the upload targets, as parsed from Targets.
+	targets []chanbackup.UploadTarget
+}
+
+// validate parses each of the upload targets.
+func (b *backupUploadConfig) validate() error {
+	for _, target := range b.Targets {
+		uploadTarget, err := chanbackup.ParseUploadTarget(target)
+		if err != nil {
+			return err
+		}
+		b.targets = append(b.targets, uploadTarget)
+	}
+
+	return nil
+}
+
+// backupUpload tracks the uploads of the backup file to a single target.
+type backupUpload struct {
+	target chanbackup.UploadTarget
+
+	// updates is signalled each time the backup file is rewritten.
+	updates chan struct{}
+
+	// mtx guards the fields below.
+	mtx sync.Mutex
+
+	// version is incremented each time the backup file is rewritten, and
+	// uploadedVersion is the version last uploaded to the target.
+	version         uint64
+	uploadedVersion uint64
+
+	successes   uint64
+	failures    uint64
+	lastSuccess time.Time
+	lastErr     error
+}
+
+// backupUploadStatus is a snapshot of the uploads to a single target.
+type backupUploadStatus struct {
+	target      string
+	successes   uint64
+	failures    uint64
+	lastSuccess time.Time
+	lastErr     error
+	upToDate    bool
+}
+
+// chanBackupFile keeps a static backup of all of our channels within a single
+// encrypted file on disk. The file is rewritten each time a channel is opened
+// or closed, so it may be copied elsewhere at any time to back up the node.
+// Updates are coalesced, so a burst of channel events results in a single
+// write. Each rewrite of the file is then uploaded to the configured targets,
+// so the backup is held off the machine before it's needed.
+type chanBackupFile struct {
+	chanDB *channeldb.DB
+
+	// backupKey is the key the backup is encrypted with. As it's derived
+	// from our identity key, it's recoverable from the seed.
+	backupKey [32]byte
+
+	file *chanbackup.MultiFile
+
+	// updates is signalled each time the backup needs to be rewritten.
+	updates chan struct{}
+
+	// uploads tracks the uploads to each target.
+	uploads []*backupUpload
+
+	wg   sync.WaitGroup
+	quit chan struct{}
+}
+
+// newChanBackupFile creates a new chanBackupFile writing the backup to the
+// passed path, encrypted with a key derived from the passed identity key, and
+// uploading it to the passed targets.
+func newChanBackupFile(chanDB *channeldb.DB, idKey *btcec.PrivateKey,
+	path string, targets []chanbackup.UploadTarget) *chanBackupFile {
+
+	uploads := make([]*backupUpload, 0, len(targets))
+	for _, target := range targets {
+		uploads = append(uploads, &backupUpload{
+			target:  target,
+			updates: make(chan struct{}, 1),
+		})
+	}
+
+	return &chanBackupFile{
+		chanDB:    chanDB,
+		backupKey: chanbackup.DeriveBackupKey(idKey),
+		file:      chanbackup.NewMultiFile(path),
+		updates:   make(chan struct{}, 1),
+		uploads:   uploads,
+		quit:      make(chan struct{}),
+	}
+}
+
+// Start writes a backup of our current channels, then begins rewriting it
+// upon each requested update, and uploading each rewrite to the targets.
+func (c *chanBackupFile) Start() error {
+	if err := c.update(); err != nil {
+		return err
+	}
+
+	c.wg.Add(1)
+	go c.updater()
+
+	for _, upload := range c.uploads {
+		c.wg.Add(1)
+		go c.uploader(upload)
+	}
+
+	return nil
+}
+
+// Stop signals the updater to exit, and waits for it to do so.
+func (c *chanBackupFile) Stop() {
+	close(c.quit)
+	c.wg.Wait()
+}
+
+// requestUpdate requests that the backup be rewritten to reflect our current
+// set of channels. It never blocks.
+func (c *chanBackupFile) requestUpdate() {
+	select {
+	case c.updates <- struct{}{}:
+	default:
+	}
+}
+
+// updater rewrites the backup upon each requested update.
+//
+// NOTE: This MUST be run as a goroutine.
+func (c *chanBackupFile) updater() {
+	defer c.wg.Done()
+
+	for {
+		select {
+		case <-c.updates:
+			if err := c.update(); err != nil {
+				srvrLog.Errorf("unable to update channel "+
+					"backup file: %v", err)
+			}
+		case <-c.quit:
+			return
+		}
+	}
+}
+
+// update replaces the backup file with a backup of our current channels.
+func (c *chanBackupFile) update() error {
+	multi, err := chanbackup.FetchMulti(c.chanDB)
+	if err != nil {
+		return err
+	}
+	if err := c.file.UpdateAndSwap(multi, c.backupKey); err != nil {
+		return err
+	}
+
+	srvrLog.Debugf("Updated channel backup file with %v channels",
+		len(multi.StaticBackups))
+
+	for _, upload := range c.uploads {
+		upload.mtx.Lock()
+		upload.version++
+		upload.mtx.Unlock()
+
+		select {
+		case upload.updates <- struct{}{}:
+		default:
+		}
+	}
+
+	return nil
+}
+
+// uploader uploads the backup file to the target upon each rewrite of the
+// file. Failed uploads are retried with an exponential backoff, with each
+// attempt uploading the latest backup.
+//
+// NOTE: This MUST be run as a goroutine.
+func (c *chanBackupFile) uploader(upload *backupUpload) {
+	defer c.wg.Done()
+
+	for {
+		select {
+		case <-upload.updates:
+		case <-c.quit:
+			return
+		}
+
+		backoff := minBackupUploadBackoff
+		for {
+			err := c.upload(upload)
+			if err == nil {
+				break
+			}
+
+			srvrLog.Warnf("Unable to upload channel backup to %v, "+
+				"retrying in %v: %v", upload.target, backoff, err)
+
+			select {
+			case <-time.After(backoff):
+			case <-c.quit:
+				return
+			}
+
+			backoff *= 2
+			if backoff > maxBackupUploadBackoff {
+				backoff = maxBackupUploadBackoff
+			}
+		}
+	}
+}
+
+// upload uploads the current backup file to the target, recording the
+// outcome.
+func (c *chanBackupFile) upload(upload *backupUpload) error {
+	// The version is read before the file, so the file uploaded is at
+	// least as recent as the version recorded as uploaded.
+	upload.mtx.Lock()
+	version := upload.version
+	upload.mtx.Unlock()
+
+	packed, err := c.file.ReadPacked()
+	if err == nil {
+		err = upload.target.Upload(packed)
+	}
+
+	upload.mtx.Lock()
+	defer upload.mtx.Unlock()
+
+	if err != nil {
+		upload.failures++
+		upload.lastErr = err
+		return err
+	}
+
+	upload.successes++
+	upload.lastSuccess = time.Now()
+	upload.lastErr = nil
+	upload.uploadedVersion = version
+
+	srvrLog.Infof("Uploaded channel backup to %v", upload.target)
+
+	return nil
+}
+
+// uploadStatus returns a snapshot of the uploads to each target.
+func (c *chanBackupFile) uploadStatus() []backupUploadStatus {
+	statuses := make([]backupUploadStatus, 0, len(c.uploads))
+	for _, upload := range c.uploads {
+		upload.mtx.Lock()
+		statuses = append(statuses, backupUploadStatus{
+			target:      upload.target.String(),
+			successes:   upload.successes,
+			failures:    upload.failures,
+			lastSuccess: upload.lastSuccess,
+			lastErr:     upload.lastErr,
+			upToDate:    upload.uploadedVersion == upload.version,
+		})
+		upload.mtx.Unlock()
+	}
+
+	return statuses
+}
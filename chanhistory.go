@@ -0,0 +1,202 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+// channelHistory records the notable events within the lifetime of each
+// channel into the channel's timeline within the database. The timeline
+// allows the history of a channel to be reconstructed long after the fact,
+// even once the channel has been closed.
+//
+// Recording an event is best effort: failures are logged rather than
+// interrupting the operation of the channel.
+type channelHistory struct {
+	db *channeldb.DB
+
+	// balanceThreshold is the minimum change in a channel's local balance
+	// since the last recorded balance which is recorded as a new event.
+	balanceThreshold btcutil.Amount
+
+	// lastBalances caches the last recorded local balance of each active
+	// channel.
+	lastBalances map[wire.OutPoint]btcutil.Amount
+	sync.Mutex
+}
+
+// newChannelHistory creates a new channelHistory backed by the passed
+// database.
+func newChannelHistory(db *channeldb.DB,
+	balanceThreshold btcutil.Amount) *channelHistory {
+
+	return &channelHistory{
+		db:               db,
+		balanceThreshold: balanceThreshold,
+		lastBalances:     make(map[wire.OutPoint]btcutil.Amount),
+	}
+}
+
+// record adds an event of the passed type to the timeline of the channel,
+// populated with its current balances.
+func (c *channelHistory) record(channel *lnwallet.LightningChannel,
+	eventType channeldb.ChannelEventType, detail string) {
+
+	snapshot := channel.StateSnapshot()
+	event := &channeldb.ChannelEvent{
+		Type:          eventType,
+		Timestamp:     time.Now(),
+		LocalBalance:  snapshot.LocalBalance,
+		RemoteBalance: snapshot.RemoteBalance,
+		Detail:        detail,
+	}
+
+	chanPoint := channel.ChannelPoint()
+	if err := c.db.AddChannelEvent(chanPoint, event); err != nil {
+		ltndLog.Errorf("unable to record %v event for "+
+			"ChannelPoint(%v): %v", eventType, chanPoint, err)
+		return
+	}
+
+	// Balance changes are measured against the balance within the last
+	// event which was recorded because of the balance.
+	switch eventType {
+	case channeldb.ChannelOpenedEvent, channeldb.BalanceChangedEvent:
+		c.Lock()
+		c.lastBalances[*chanPoint] = snapshot.LocalBalance
+		c.Unlock()
+	}
+}
+
+// channelOpened records that the channel is now open.
+func (c *channelHistory) channelOpened(channel *lnwallet.LightningChannel) {
+	c.record(channel, channeldb.ChannelOpenedEvent, "")
+}
+
+// balanceChanged records the current balances of the channel if its local
+// balance has changed by at least the balance threshold since the balances
+// were last recorded.
+func (c *channelHistory) balanceChanged(channel *lnwallet.LightningChannel) {
+	chanPoint := channel.ChannelPoint()
+	localBalance := channel.StateSnapshot().LocalBalance
+
+	c.Lock()
+	lastBalance, ok := c.lastBalances[*chanPoint]
+	c.Unlock()
+
+	// If this is the first balance change since the channel was loaded,
+	// then we'll compare against the balance within the most recent event
+	// which recorded one. If the channel has no such event, then the
+	// current balance becomes the baseline.
+	if !ok {
+		lastBalance = localBalance
+		for _, eventType := range []channeldb.ChannelEventType{
+			channeldb.BalanceChangedEvent,
+			channeldb.ChannelOpenedEvent,
+		} {
+			event, err := c.db.LastChannelEvent(chanPoint, eventType)
+			if err != nil {
+				ltndLog.Errorf("unable to fetch timeline of "+
+					"ChannelPoint(%v): %v", chanPoint, err)
+				return
+			}
+			if event != nil {
+				lastBalance = event.LocalBalance
+				break
+			}
+		}
+
+		c.Lock()
+		c.lastBalances[*chanPoint] = lastBalance
+		c.Unlock()
+	}
+
+	delta := localBalance - lastBalance
+	if delta < 0 {
+		delta = -delta
+	}
+	if delta == 0 || delta < c.balanceThreshold {
+		return
+	}
+
+	c.record(channel, channeldb.BalanceChangedEvent, "")
+}
+
+// policyApplied records the HTLC policy applied to the channel, unless it's
+// identical to the last policy recorded.
+func (c *channelHistory) policyApplied(channel *lnwallet.LightningChannel,
+	minHTLC, maxDustExposure, maxHashExposure btcutil.Amount) {
+
+	policy := fmt.Sprintf("min_htlc=%v max_dust_exposure=%v "+
+		"max_hash_exposure=%v", int64(minHTLC), int64(maxDustExposure),
+		int64(maxHashExposure))
+
+	chanPoint := channel.ChannelPoint()
+	last, err := c.db.LastChannelEvent(chanPoint,
+		channeldb.PolicyUpdatedEvent)
+	if err != nil {
+		ltndLog.Errorf("unable to fetch timeline of ChannelPoint(%v): "+
+			"%v", chanPoint, err)
+		return
+	}
+	if last != nil && last.Detail == policy {
+		return
+	}
+
+	c.record(channel, channeldb.PolicyUpdatedEvent, policy)
+}
+
+// peerDisconnected records that the peer of the channel has disconnected.
+func (c *channelHistory) peerDisconnected(channel *lnwallet.LightningChannel) {
+	c.record(channel, channeldb.PeerDisconnectedEvent, "")
+}
+
+// closeInitiated records that either party has begun to close the channel.
+// The detail describes the kind of closure, and which party initiated it.
+func (c *channelHistory) closeInitiated(channel *lnwallet.LightningChannel,
+	detail string) {
+
+	c.record(channel, channeldb.CloseInitiatedEvent, detail)
+}
+
+// channelResolved records that the closing transaction with the passed txid
+// has been confirmed. As the channel is no longer active, its cached balance
+// is discarded.
+func (c *channelHistory) channelResolved(channel *lnwallet.LightningChannel,
+	closingTxid *chainhash.Hash) {
+
+	detail := ""
+	if closingTxid != nil {
+		detail = fmt.Sprintf("closing_txid=%v", closingTxid)
+	}
+	c.record(channel, channeldb.ChannelResolvedEvent, detail)
+
+	c.Lock()
+	delete(c.lastBalances, *channel.ChannelPoint())
+	c.Unlock()
+}
+
+// justiceServed records that the justice transaction with the passed txid,
+// claiming the passed amount from the breached channel, has been confirmed.
+func (c *channelHistory) justiceServed(channel *lnwallet.LightningChannel,
+	justiceTxid *chainhash.Hash, claimed btcutil.Amount) {
+
+	detail := fmt.Sprintf("justice_txid=%v claimed=%v", justiceTxid,
+		int64(claimed))
+	c.record(channel, channeldb.JusticeServedEvent, detail)
+}
+
+// justiceFailed records that we were unable to exact justice for the breach
+// of the channel due to the passed error.
+func (c *channelHistory) justiceFailed(channel *lnwallet.LightningChannel,
+	err error) {
+
+	c.record(channel, channeldb.JusticeFailedEvent, err.Error())
+}
@@ -0,0 +1,109 @@
+package channeldb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/boltdb/bolt"
+)
+
+var (
+	// abandonedChannelBucket is the name of the bucket within the database
+	// that archives the state of abandoned channels. The archived state of
+	// each channel is keyed by its serialized funding outpoint. Each value
+	// is the big-endian encoding of the time the channel was abandoned in
+	// nanoseconds, followed by the JSON encoding of the channel's
+	// ChannelDump.
+	abandonedChannelBucket = []byte("abandoned-chans")
+)
+
+// AbandonedChannel is the archived state of a channel which was abandoned.
+type AbandonedChannel struct {
+	// AbandonTime is the time the channel was abandoned.
+	AbandonTime time.Time
+
+	// Dump is the redacted state of the channel at the time it was
+	// abandoned.
+	Dump *ChannelDump
+}
+
+// AbandonChannel removes the channel from the database without awaiting the
+// resolution of its funding output, as for a channel whose funding
+// transaction will never confirm, or whose remote party has vanished. As with
+// CloseChannel, the channel's state is deleted, and a summary of it recorded
+// within the closed channel bucket. Additionally, the redacted state of the
+// channel is archived so it may be audited later on.
+//
+// NOTE: Any funds within the channel are forfeit unless the channel's
+// funding output is later resolved by other means.
+func (c *OpenChannel) AbandonChannel() error {
+	dump, err := c.Dump()
+	if err != nil {
+		return err
+	}
+	dumpBytes, err := json.Marshal(dump)
+	if err != nil {
+		return err
+	}
+
+	var archive [8]byte
+	binary.BigEndian.PutUint64(archive[:], uint64(time.Now().UnixNano()))
+
+	var chanKey bytes.Buffer
+	if err := writeOutpoint(&chanKey, c.ChanID); err != nil {
+		return err
+	}
+
+	return c.Db.Update(func(tx *bolt.Tx) error {
+		if err := c.closeChannel(tx); err != nil {
+			return err
+		}
+
+		abandoned, err := tx.CreateBucketIfNotExists(
+			abandonedChannelBucket)
+		if err != nil {
+			return err
+		}
+
+		return abandoned.Put(chanKey.Bytes(),
+			append(archive[:], dumpBytes...))
+	})
+}
+
+// FetchAbandonedChannels returns the archived state of every channel which
+// has been abandoned, ordered by funding outpoint.
+func (d *DB) FetchAbandonedChannels() ([]*AbandonedChannel, error) {
+	var channels []*AbandonedChannel
+	err := d.View(func(tx *bolt.Tx) error {
+		abandoned := tx.Bucket(abandonedChannelBucket)
+		if abandoned == nil {
+			return nil
+		}
+
+		return abandoned.ForEach(func(k, v []byte) error {
+			if len(v) < 8 {
+				return io.ErrUnexpectedEOF
+			}
+
+			dump := &ChannelDump{}
+			if err := json.Unmarshal(v[8:], dump); err != nil {
+				return err
+			}
+
+			channels = append(channels, &AbandonedChannel{
+				AbandonTime: time.Unix(0,
+					int64(binary.BigEndian.Uint64(v[:8]))),
+				Dump: dump,
+			})
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return channels, nil
+}
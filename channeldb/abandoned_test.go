@@ -0,0 +1,79 @@
+package channeldb
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+// TestAbandonChannel tests that abandoning a pending channel removes it from
+// the database, marks it as closed, and archives its state.
+func TestAbandonChannel(t *testing.T) {
+	cdb, cleanUp, err := makeTestDB()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+	defer cleanUp()
+
+	state, err := createTestChannelState(cdb)
+	if err != nil {
+		t.Fatalf("unable to create channel state: %v", err)
+	}
+	addr := &net.TCPAddr{
+		IP:   net.ParseIP("127.0.0.1"),
+		Port: 18555,
+	}
+	if err := state.SyncPending(addr); err != nil {
+		t.Fatalf("unable to save and serialize channel state: %v", err)
+	}
+
+	channels, err := cdb.FetchAbandonedChannels()
+	if err != nil {
+		t.Fatalf("unable to fetch abandoned channels: %v", err)
+	}
+	if len(channels) != 0 {
+		t.Fatalf("expected no abandoned channels, got %v",
+			len(channels))
+	}
+
+	before := time.Now()
+	if err := state.AbandonChannel(); err != nil {
+		t.Fatalf("unable to abandon channel: %v", err)
+	}
+
+	pendingChannels, err := cdb.FetchPendingChannels()
+	if err != nil {
+		t.Fatalf("unable to list pending channels: %v", err)
+	}
+	if len(pendingChannels) != 0 {
+		t.Fatalf("expected no pending channels, got %v",
+			len(pendingChannels))
+	}
+	closed, err := cdb.IsChannelClosed(state.ChanID)
+	if err != nil {
+		t.Fatalf("unable to check channel closure: %v", err)
+	}
+	if !closed {
+		t.Fatalf("abandoned channel not marked as closed")
+	}
+
+	channels, err = cdb.FetchAbandonedChannels()
+	if err != nil {
+		t.Fatalf("unable to fetch abandoned channels: %v", err)
+	}
+	if len(channels) != 1 {
+		t.Fatalf("expected 1 abandoned channel, got %v", len(channels))
+	}
+	if channels[0].AbandonTime.Before(before) {
+		t.Fatalf("abandon time %v precedes the abandonment at %v",
+			channels[0].AbandonTime, before)
+	}
+	dump := channels[0].Dump
+	if dump.ChanPoint != state.ChanID.String() {
+		t.Fatalf("expected archived channel %v, got %v", state.ChanID,
+			dump.ChanPoint)
+	}
+	if !dump.IsPending || dump.Capacity != int64(state.Capacity) {
+		t.Fatalf("archived state doesn't match channel: %v", dump)
+	}
+}
@@ -0,0 +1,189 @@
+package channeldb
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/boltdb/bolt"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/wire"
+)
+
+var (
+	// chanAliasBucket is the name of the bucket within the database that
+	// stores the aliases allocated to zero-conf channels, keyed by the
+	// big-endian encoding of the alias. As keys are ordered, the last key
+	// within the bucket is the most recently allocated alias.
+	//
+	// maps: alias -> chanPoint || shortChanID
+	chanAliasBucket = []byte("chan-aliases")
+)
+
+// ChannelAlias is the alias of a zero-conf channel, which identifies the
+// channel in place of its short channel ID until its funding transaction
+// confirms.
+type ChannelAlias struct {
+	// Alias is the alias ChannelID allocated to the channel.
+	Alias lnwire.ChannelID
+
+	// ChanPoint is the funding outpoint of the channel.
+	ChanPoint wire.OutPoint
+
+	// ShortChanID is the location of the channel's funding output within
+	// the chain. It's zero until the funding transaction confirms.
+	ShortChanID lnwire.ChannelID
+}
+
+// Confirmed returns true if the channel's funding transaction has confirmed,
+// and its alias has been upgraded to a short channel ID.
+func (c *ChannelAlias) Confirmed() bool {
+	return c.ShortChanID.ToUint64() != 0
+}
+
+// AddChannelAlias allocates an alias to the channel with the passed funding
+// outpoint, unique among all the aliases allocated by the database. If the
+// channel was already allocated an alias, then that alias is returned.
+func (d *DB) AddChannelAlias(chanPoint *wire.OutPoint) (lnwire.ChannelID, error) {
+	var alias lnwire.ChannelID
+	err := d.Update(func(tx *bolt.Tx) error {
+		aliases, err := tx.CreateBucketIfNotExists(chanAliasBucket)
+		if err != nil {
+			return err
+		}
+
+		// If the channel was allocated an alias before, then we'll
+		// return it rather than allocating another.
+		c := aliases.Cursor()
+		for k, v := c.First(); k != nil; k, v = c.Next() {
+			chanAlias, err := deserializeChannelAlias(k, v)
+			if err != nil {
+				return err
+			}
+			if chanAlias.ChanPoint == *chanPoint {
+				alias = chanAlias.Alias
+				return nil
+			}
+		}
+
+		// Otherwise, the next alias follows the last one allocated, or
+		// is the first within the alias range if none have been.
+		first := lnwire.ChannelID{
+			BlockHeight: lnwire.AliasBlockHeightStart,
+		}
+		next := first.ToUint64()
+		if lastKey, _ := c.Last(); lastKey != nil {
+			next = byteOrder.Uint64(lastKey) + 1
+		}
+		alias = lnwire.NewChanIDFromInt(next)
+		if !alias.IsAlias() {
+			return ErrNoChannelAliasesLeft
+		}
+
+		chanAlias := &ChannelAlias{
+			Alias:     alias,
+			ChanPoint: *chanPoint,
+		}
+		return putChannelAlias(aliases, chanAlias)
+	})
+	if err != nil {
+		return lnwire.ChannelID{}, err
+	}
+
+	return alias, nil
+}
+
+// ConfirmChannelAlias records the short channel ID of the channel allocated
+// the passed alias, once its funding transaction has confirmed. The alias
+// remains indexed, so it may still be resolved to the channel. If the alias
+// was never allocated, then ErrChannelAliasNotFound is returned.
+func (d *DB) ConfirmChannelAlias(alias,
+	shortChanID lnwire.ChannelID) error {
+
+	return d.Update(func(tx *bolt.Tx) error {
+		aliases := tx.Bucket(chanAliasBucket)
+		if aliases == nil {
+			return ErrChannelAliasNotFound
+		}
+
+		var key [8]byte
+		byteOrder.PutUint64(key[:], alias.ToUint64())
+		v := aliases.Get(key[:])
+		if v == nil {
+			return ErrChannelAliasNotFound
+		}
+
+		chanAlias, err := deserializeChannelAlias(key[:], v)
+		if err != nil {
+			return err
+		}
+		chanAlias.ShortChanID = shortChanID
+
+		return putChannelAlias(aliases, chanAlias)
+	})
+}
+
+// FetchChannelAliases returns all the aliases allocated by the database,
+// ordered from the first allocated to the last.
+func (d *DB) FetchChannelAliases() ([]*ChannelAlias, error) {
+	var chanAliases []*ChannelAlias
+	err := d.View(func(tx *bolt.Tx) error {
+		aliases := tx.Bucket(chanAliasBucket)
+		if aliases == nil {
+			return nil
+		}
+
+		return aliases.ForEach(func(k, v []byte) error {
+			chanAlias, err := deserializeChannelAlias(k, v)
+			if err != nil {
+				return err
+			}
+
+			chanAliases = append(chanAliases, chanAlias)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return chanAliases, nil
+}
+
+func putChannelAlias(aliases *bolt.Bucket, chanAlias *ChannelAlias) error {
+	var key [8]byte
+	byteOrder.PutUint64(key[:], chanAlias.Alias.ToUint64())
+
+	var b bytes.Buffer
+	if err := writeOutpoint(&b, &chanAlias.ChanPoint); err != nil {
+		return err
+	}
+
+	var scratch [8]byte
+	byteOrder.PutUint64(scratch[:], chanAlias.ShortChanID.ToUint64())
+	if _, err := b.Write(scratch[:]); err != nil {
+		return err
+	}
+
+	return aliases.Put(key[:], b.Bytes())
+}
+
+func deserializeChannelAlias(k, v []byte) (*ChannelAlias, error) {
+	chanAlias := &ChannelAlias{
+		Alias: lnwire.NewChanIDFromInt(byteOrder.Uint64(k)),
+	}
+
+	r := bytes.NewReader(v)
+	if err := readOutpoint(r, &chanAlias.ChanPoint); err != nil {
+		return nil, err
+	}
+
+	var scratch [8]byte
+	if _, err := io.ReadFull(r, scratch[:]); err != nil {
+		return nil, err
+	}
+	chanAlias.ShortChanID = lnwire.NewChanIDFromInt(
+		byteOrder.Uint64(scratch[:]),
+	)
+
+	return chanAlias, nil
+}
@@ -0,0 +1,86 @@
+package channeldb
+
+import (
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
+)
+
+func TestChannelAliases(t *testing.T) {
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+
+	chanPoint1 := &wire.OutPoint{Hash: chainhash.Hash(key), Index: 1}
+	chanPoint2 := &wire.OutPoint{Hash: chainhash.Hash(key), Index: 2}
+
+	// Each channel should be allocated a distinct alias, starting from the
+	// beginning of the alias range.
+	alias1, err := db.AddChannelAlias(chanPoint1)
+	if err != nil {
+		t.Fatalf("unable to add alias: %v", err)
+	}
+	if !alias1.IsAlias() || alias1.BlockHeight != lnwire.AliasBlockHeightStart {
+		t.Fatalf("unexpected first alias: %v", alias1)
+	}
+	alias2, err := db.AddChannelAlias(chanPoint2)
+	if err != nil {
+		t.Fatalf("unable to add alias: %v", err)
+	}
+	if !alias2.IsAlias() || alias2 == alias1 {
+		t.Fatalf("expected a new alias, got %v", alias2)
+	}
+
+	// Adding an alias for a channel which already has one should return
+	// the existing alias.
+	alias, err := db.AddChannelAlias(chanPoint1)
+	if err != nil {
+		t.Fatalf("unable to add alias: %v", err)
+	}
+	if alias != alias1 {
+		t.Fatalf("expected existing alias %v, got %v", alias1, alias)
+	}
+
+	// Once the first channel confirms, its short channel ID should be
+	// recorded alongside its alias.
+	shortChanID := lnwire.ChannelID{
+		BlockHeight: 1000,
+		TxIndex:     10,
+		TxPosition:  1,
+	}
+	if err := db.ConfirmChannelAlias(alias1, shortChanID); err != nil {
+		t.Fatalf("unable to confirm alias: %v", err)
+	}
+
+	chanAliases, err := db.FetchChannelAliases()
+	if err != nil {
+		t.Fatalf("unable to fetch aliases: %v", err)
+	}
+	if len(chanAliases) != 2 {
+		t.Fatalf("expected 2 aliases, got %v", len(chanAliases))
+	}
+
+	first, second := chanAliases[0], chanAliases[1]
+	if first.Alias != alias1 || first.ChanPoint != *chanPoint1 ||
+		!first.Confirmed() || first.ShortChanID != shortChanID {
+
+		t.Fatalf("unexpected confirmed alias: %v", first)
+	}
+	if second.Alias != alias2 || second.ChanPoint != *chanPoint2 ||
+		second.Confirmed() {
+
+		t.Fatalf("unexpected unconfirmed alias: %v", second)
+	}
+
+	// Confirming an alias which was never allocated should fail.
+	var unknown lnwire.ChannelID
+	unknown.BlockHeight = lnwire.AliasBlockHeightEnd - 1
+	err = db.ConfirmChannelAlias(unknown, shortChanID)
+	if err != ErrChannelAliasNotFound {
+		t.Fatalf("expected ErrChannelAliasNotFound, got: %v", err)
+	}
+}
@@ -0,0 +1,159 @@
+package channeldb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/roasbeef/btcutil"
+)
+
+var (
+	// balanceHistoryBucket is the name of the bucket within the database
+	// that stores periodic snapshots of the node's balances. The
+	// snapshots are keyed by the big-endian encoding of their timestamp in
+	// nanoseconds, so a cursor scan yields them in chronological order.
+	balanceHistoryBucket = []byte("balance-history")
+)
+
+// BalanceSnapshot records the node's balances at a point in time.
+type BalanceSnapshot struct {
+	// Timestamp is the time the snapshot was taken.
+	Timestamp time.Time
+
+	// OnChainBalance is the wallet's confirmed balance.
+	OnChainBalance btcutil.Amount
+
+	// UnconfirmedBalance is the wallet's balance within outputs which
+	// have yet to confirm.
+	UnconfirmedBalance btcutil.Amount
+
+	// LocalBalance and RemoteBalance are the total settled balances of
+	// each side of our open channels.
+	LocalBalance  btcutil.Amount
+	RemoteBalance btcutil.Amount
+
+	// PendingOpenBalance is our total balance within channels whose
+	// funding transaction has yet to confirm.
+	PendingOpenBalance btcutil.Amount
+}
+
+// AddBalanceSnapshot appends a snapshot to the balance history. If a snapshot
+// already exists with an identical timestamp, then the timestamp of the new
+// snapshot is advanced until it's unique.
+func (d *DB) AddBalanceSnapshot(snapshot *BalanceSnapshot) error {
+	if d.LowDiskSpace() {
+		return ErrLowDiskSpace
+	}
+
+	var b bytes.Buffer
+	if err := serializeBalanceSnapshot(&b, snapshot); err != nil {
+		return err
+	}
+
+	return d.Update(func(tx *bolt.Tx) error {
+		history, err := tx.CreateBucketIfNotExists(balanceHistoryBucket)
+		if err != nil {
+			return err
+		}
+
+		var snapshotKey [8]byte
+		timestamp := uint64(snapshot.Timestamp.UnixNano())
+		for {
+			binary.BigEndian.PutUint64(snapshotKey[:], timestamp)
+			if history.Get(snapshotKey[:]) == nil {
+				break
+			}
+			timestamp++
+		}
+
+		return history.Put(snapshotKey[:], b.Bytes())
+	})
+}
+
+// FetchBalanceSnapshots returns the snapshots within the balance history which
+// were taken within the range [start, end], in chronological order. A zero
+// start or end time leaves that side of the range unbounded.
+func (d *DB) FetchBalanceSnapshots(start, end time.Time) ([]*BalanceSnapshot,
+	error) {
+
+	var startKey, endKey [8]byte
+	if !start.IsZero() {
+		binary.BigEndian.PutUint64(startKey[:], uint64(start.UnixNano()))
+	}
+	binary.BigEndian.PutUint64(endKey[:], math.MaxInt64)
+	if !end.IsZero() {
+		binary.BigEndian.PutUint64(endKey[:], uint64(end.UnixNano()))
+	}
+
+	var snapshots []*BalanceSnapshot
+	err := d.View(func(tx *bolt.Tx) error {
+		history := tx.Bucket(balanceHistoryBucket)
+		if history == nil {
+			return nil
+		}
+
+		c := history.Cursor()
+		for k, v := c.Seek(startKey[:]); k != nil &&
+			bytes.Compare(k, endKey[:]) <= 0; k, v = c.Next() {
+
+			snapshot, err := deserializeBalanceSnapshot(
+				bytes.NewReader(v),
+			)
+			if err != nil {
+				return err
+			}
+			snapshot.Timestamp = time.Unix(0,
+				int64(binary.BigEndian.Uint64(k)))
+
+			snapshots = append(snapshots, snapshot)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshots, nil
+}
+
+// serializeBalanceSnapshot writes the passed snapshot to w. The timestamp of
+// the snapshot isn't written, as it's encoded within the snapshot's key. As
+// snapshots are taken frequently, each balance is written as a varint to keep
+// the history compact.
+func serializeBalanceSnapshot(w io.Writer, s *BalanceSnapshot) error {
+	var b [binary.MaxVarintLen64 * 5]byte
+	n := binary.PutVarint(b[:], int64(s.OnChainBalance))
+	n += binary.PutVarint(b[n:], int64(s.UnconfirmedBalance))
+	n += binary.PutVarint(b[n:], int64(s.LocalBalance))
+	n += binary.PutVarint(b[n:], int64(s.RemoteBalance))
+	n += binary.PutVarint(b[n:], int64(s.PendingOpenBalance))
+
+	_, err := w.Write(b[:n])
+	return err
+}
+
+// deserializeBalanceSnapshot reads a snapshot written by
+// serializeBalanceSnapshot from r.
+func deserializeBalanceSnapshot(r io.ByteReader) (*BalanceSnapshot, error) {
+	var balances [5]int64
+	for i := range balances {
+		balance, err := binary.ReadVarint(r)
+		if err != nil {
+			return nil, err
+		}
+		balances[i] = balance
+	}
+
+	return &BalanceSnapshot{
+		OnChainBalance:     btcutil.Amount(balances[0]),
+		UnconfirmedBalance: btcutil.Amount(balances[1]),
+		LocalBalance:       btcutil.Amount(balances[2]),
+		RemoteBalance:      btcutil.Amount(balances[3]),
+		PendingOpenBalance: btcutil.Amount(balances[4]),
+	}, nil
+}
@@ -0,0 +1,83 @@
+package channeldb
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/davecgh/go-spew/spew"
+)
+
+func TestBalanceHistory(t *testing.T) {
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+
+	// Fetching from an empty history should return no snapshots.
+	snapshots, err := db.FetchBalanceSnapshots(time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("unable to fetch snapshots: %v", err)
+	}
+	if len(snapshots) != 0 {
+		t.Fatalf("expected no snapshots, got %v", len(snapshots))
+	}
+
+	start := time.Unix(1490000000, 0)
+	history := []*BalanceSnapshot{
+		{
+			Timestamp:      start,
+			OnChainBalance: 5000000,
+		},
+		{
+			Timestamp:          start.Add(time.Hour),
+			OnChainBalance:     3000000,
+			UnconfirmedBalance: 1900000,
+			PendingOpenBalance: 2000000,
+		},
+		{
+			Timestamp:      start.Add(time.Hour * 2),
+			OnChainBalance: 3000000,
+			LocalBalance:   1500000,
+			RemoteBalance:  500000,
+		},
+		{
+			Timestamp:      start.Add(time.Hour * 3),
+			OnChainBalance: 3000000,
+			LocalBalance:   1000000,
+			RemoteBalance:  1000000,
+		},
+	}
+
+	// The snapshots are added out of order, they should still be
+	// returned in chronological order.
+	for i := len(history) - 1; i >= 0; i-- {
+		if err := db.AddBalanceSnapshot(history[i]); err != nil {
+			t.Fatalf("unable to add snapshot: %v", err)
+		}
+	}
+
+	snapshots, err = db.FetchBalanceSnapshots(time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("unable to fetch snapshots: %v", err)
+	}
+	if !reflect.DeepEqual(history, snapshots) {
+		t.Fatalf("balance history fetched from db doesn't match "+
+			"original %v vs %v", spew.Sdump(history),
+			spew.Sdump(snapshots))
+	}
+
+	// Only the snapshots within the queried range, including its bounds,
+	// should be returned.
+	snapshots, err = db.FetchBalanceSnapshots(start.Add(time.Hour),
+		start.Add(time.Hour*2))
+	if err != nil {
+		t.Fatalf("unable to fetch snapshots: %v", err)
+	}
+	if !reflect.DeepEqual(history[1:3], snapshots) {
+		t.Fatalf("balance history fetched from db doesn't match "+
+			"original %v vs %v", spew.Sdump(history[1:3]),
+			spew.Sdump(snapshots))
+	}
+}
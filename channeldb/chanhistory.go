@@ -0,0 +1,301 @@
+package channeldb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+var (
+	// chanHistoryBucket is the name of the bucket within the database
+	// that stores the event timeline of each channel. Within the bucket,
+	// each channel has a sub-bucket keyed by its serialized funding
+	// outpoint. The events within a channel's bucket are keyed by the
+	// big-endian encoding of their timestamp in nanoseconds, so a cursor
+	// scan yields them in chronological order.
+	//
+	// NOTE: Unlike the channel's state, a channel's timeline is retained
+	// after the channel has been closed.
+	chanHistoryBucket = []byte("chan-history")
+)
+
+// ChannelEventType denotes the kind of event within a channel's timeline.
+type ChannelEventType uint8
+
+const (
+	// ChannelOpenedEvent is recorded once a channel's funding transaction
+	// has been confirmed, and the channel is usable.
+	ChannelOpenedEvent ChannelEventType = 0
+
+	// BalanceChangedEvent is recorded when the local balance of the
+	// channel changes by more than the configured threshold.
+	BalanceChangedEvent ChannelEventType = 1
+
+	// PolicyUpdatedEvent is recorded when the HTLC policy applied to the
+	// channel changes.
+	PolicyUpdatedEvent ChannelEventType = 2
+
+	// PeerDisconnectedEvent is recorded when the channel's peer
+	// disconnects while the channel is active.
+	PeerDisconnectedEvent ChannelEventType = 3
+
+	// CloseInitiatedEvent is recorded when either party begins to close
+	// the channel.
+	CloseInitiatedEvent ChannelEventType = 4
+
+	// ChannelResolvedEvent is recorded when the channel's closing
+	// transaction has been confirmed.
+	ChannelResolvedEvent ChannelEventType = 5
+
+	// JusticeServedEvent is recorded once the justice transaction
+	// sweeping the funds of a breached channel has been confirmed.
+	JusticeServedEvent ChannelEventType = 6
+
+	// JusticeFailedEvent is recorded when we're unable to create or
+	// broadcast the justice transaction for a breached channel.
+	JusticeFailedEvent ChannelEventType = 7
+)
+
+// String returns a human readable name for the event type.
+func (c ChannelEventType) String() string {
+	switch c {
+	case ChannelOpenedEvent:
+		return "Opened"
+	case BalanceChangedEvent:
+		return "BalanceChanged"
+	case PolicyUpdatedEvent:
+		return "PolicyUpdated"
+	case PeerDisconnectedEvent:
+		return "PeerDisconnected"
+	case CloseInitiatedEvent:
+		return "CloseInitiated"
+	case ChannelResolvedEvent:
+		return "Resolved"
+	case JusticeServedEvent:
+		return "JusticeServed"
+	case JusticeFailedEvent:
+		return "JusticeFailed"
+	default:
+		return "Unknown"
+	}
+}
+
+// ChannelEvent is a single event within the timeline of a channel.
+type ChannelEvent struct {
+	// Type is the kind of event.
+	Type ChannelEventType
+
+	// Timestamp is the time the event occurred.
+	Timestamp time.Time
+
+	// LocalBalance and RemoteBalance are the settled balances of the
+	// channel at the time of the event.
+	LocalBalance  btcutil.Amount
+	RemoteBalance btcutil.Amount
+
+	// Detail is a short, free-form description of the event, such as the
+	// txid of a closing transaction, or the new policy of the channel.
+	Detail string
+}
+
+// AddChannelEvent appends an event to the timeline of the channel identified
+// by the passed funding outpoint. If an event already exists with an
+// identical timestamp, then the timestamp of the new event is advanced until
+// it's unique.
+func (d *DB) AddChannelEvent(chanPoint *wire.OutPoint,
+	event *ChannelEvent) error {
+
+	var b bytes.Buffer
+	if err := serializeChannelEvent(&b, event); err != nil {
+		return err
+	}
+
+	var chanKey bytes.Buffer
+	if err := writeOutpoint(&chanKey, chanPoint); err != nil {
+		return err
+	}
+
+	return d.Update(func(tx *bolt.Tx) error {
+		history, err := tx.CreateBucketIfNotExists(chanHistoryBucket)
+		if err != nil {
+			return err
+		}
+		events, err := history.CreateBucketIfNotExists(chanKey.Bytes())
+		if err != nil {
+			return err
+		}
+
+		var eventKey [8]byte
+		timestamp := uint64(event.Timestamp.UnixNano())
+		for {
+			binary.BigEndian.PutUint64(eventKey[:], timestamp)
+			if events.Get(eventKey[:]) == nil {
+				break
+			}
+			timestamp++
+		}
+
+		return events.Put(eventKey[:], b.Bytes())
+	})
+}
+
+// FetchChannelEvents returns the events within the timeline of the channel
+// identified by the passed funding outpoint which occurred within the range
+// [start, end], in chronological order. A zero start or end time leaves that
+// side of the range unbounded.
+func (d *DB) FetchChannelEvents(chanPoint *wire.OutPoint, start,
+	end time.Time) ([]*ChannelEvent, error) {
+
+	var chanKey bytes.Buffer
+	if err := writeOutpoint(&chanKey, chanPoint); err != nil {
+		return nil, err
+	}
+
+	var startKey, endKey [8]byte
+	if !start.IsZero() {
+		binary.BigEndian.PutUint64(startKey[:], uint64(start.UnixNano()))
+	}
+	binary.BigEndian.PutUint64(endKey[:], math.MaxInt64)
+	if !end.IsZero() {
+		binary.BigEndian.PutUint64(endKey[:], uint64(end.UnixNano()))
+	}
+
+	var channelEvents []*ChannelEvent
+	err := d.View(func(tx *bolt.Tx) error {
+		history := tx.Bucket(chanHistoryBucket)
+		if history == nil {
+			return nil
+		}
+		events := history.Bucket(chanKey.Bytes())
+		if events == nil {
+			return nil
+		}
+
+		c := events.Cursor()
+		for k, v := c.Seek(startKey[:]); k != nil &&
+			bytes.Compare(k, endKey[:]) <= 0; k, v = c.Next() {
+
+			event, err := deserializeChannelEvent(bytes.NewReader(v))
+			if err != nil {
+				return err
+			}
+			event.Timestamp = time.Unix(0,
+				int64(binary.BigEndian.Uint64(k)))
+
+			channelEvents = append(channelEvents, event)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return channelEvents, nil
+}
+
+// LastChannelEvent returns the most recent event of the passed type within
+// the timeline of the channel identified by the passed funding outpoint. If
+// no such event exists, then nil is returned.
+func (d *DB) LastChannelEvent(chanPoint *wire.OutPoint,
+	eventType ChannelEventType) (*ChannelEvent, error) {
+
+	var chanKey bytes.Buffer
+	if err := writeOutpoint(&chanKey, chanPoint); err != nil {
+		return nil, err
+	}
+
+	var lastEvent *ChannelEvent
+	err := d.View(func(tx *bolt.Tx) error {
+		history := tx.Bucket(chanHistoryBucket)
+		if history == nil {
+			return nil
+		}
+		events := history.Bucket(chanKey.Bytes())
+		if events == nil {
+			return nil
+		}
+
+		c := events.Cursor()
+		for k, v := c.Last(); k != nil; k, v = c.Prev() {
+			// The event type is the first byte of each serialized
+			// event, so we can skip over events of other types
+			// without decoding them.
+			if len(v) == 0 || ChannelEventType(v[0]) != eventType {
+				continue
+			}
+
+			event, err := deserializeChannelEvent(bytes.NewReader(v))
+			if err != nil {
+				return err
+			}
+			event.Timestamp = time.Unix(0,
+				int64(binary.BigEndian.Uint64(k)))
+
+			lastEvent = event
+			return nil
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return lastEvent, nil
+}
+
+// serializeChannelEvent writes the passed event to w. The timestamp of the
+// event isn't written, as it's encoded within the event's key. In order to
+// keep the timeline compact, balances are written as variable length
+// integers.
+func serializeChannelEvent(w io.Writer, e *ChannelEvent) error {
+	if _, err := w.Write([]byte{byte(e.Type)}); err != nil {
+		return err
+	}
+
+	if err := wire.WriteVarInt(w, 0, uint64(e.LocalBalance)); err != nil {
+		return err
+	}
+	if err := wire.WriteVarInt(w, 0, uint64(e.RemoteBalance)); err != nil {
+		return err
+	}
+
+	return wire.WriteVarString(w, 0, e.Detail)
+}
+
+// deserializeChannelEvent reads an event written by serializeChannelEvent
+// from r.
+func deserializeChannelEvent(r io.Reader) (*ChannelEvent, error) {
+	var eventType [1]byte
+	if _, err := io.ReadFull(r, eventType[:]); err != nil {
+		return nil, err
+	}
+
+	localBalance, err := wire.ReadVarInt(r, 0)
+	if err != nil {
+		return nil, err
+	}
+	remoteBalance, err := wire.ReadVarInt(r, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	detail, err := wire.ReadVarString(r, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ChannelEvent{
+		Type:          ChannelEventType(eventType[0]),
+		LocalBalance:  btcutil.Amount(localBalance),
+		RemoteBalance: btcutil.Amount(remoteBalance),
+		Detail:        detail,
+	}, nil
+}
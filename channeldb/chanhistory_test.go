@@ -0,0 +1,147 @@
+package channeldb
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+func TestChannelHistory(t *testing.T) {
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+
+	chanPoint := &wire.OutPoint{Hash: chainhash.Hash(key), Index: 1}
+	otherChanPoint := &wire.OutPoint{Hash: chainhash.Hash(key), Index: 2}
+
+	// Fetching the timeline of a channel without any events should
+	// return an empty timeline.
+	events, err := db.FetchChannelEvents(chanPoint, time.Time{},
+		time.Time{})
+	if err != nil {
+		t.Fatalf("unable to fetch events: %v", err)
+	}
+	if len(events) != 0 {
+		t.Fatalf("expected no events, got %v", len(events))
+	}
+
+	start := time.Unix(1490000000, 0)
+	timeline := []*ChannelEvent{
+		{
+			Type:          ChannelOpenedEvent,
+			Timestamp:     start,
+			LocalBalance:  btcutil.Amount(1000000),
+			RemoteBalance: 0,
+		},
+		{
+			Type:          PolicyUpdatedEvent,
+			Timestamp:     start.Add(time.Hour),
+			LocalBalance:  btcutil.Amount(1000000),
+			RemoteBalance: 0,
+			Detail:        "min_htlc=1 max_dust_exposure=500000",
+		},
+		{
+			Type:          BalanceChangedEvent,
+			Timestamp:     start.Add(time.Hour * 2),
+			LocalBalance:  btcutil.Amount(900000),
+			RemoteBalance: btcutil.Amount(100000),
+		},
+		{
+			Type:          PeerDisconnectedEvent,
+			Timestamp:     start.Add(time.Hour * 3),
+			LocalBalance:  btcutil.Amount(900000),
+			RemoteBalance: btcutil.Amount(100000),
+		},
+		{
+			Type:          CloseInitiatedEvent,
+			Timestamp:     start.Add(time.Hour * 4),
+			LocalBalance:  btcutil.Amount(900000),
+			RemoteBalance: btcutil.Amount(100000),
+			Detail:        "cooperative, local",
+		},
+	}
+
+	// The events are added out of order, they should still be returned
+	// in chronological order.
+	for i := len(timeline) - 1; i >= 0; i-- {
+		if err := db.AddChannelEvent(chanPoint, timeline[i]); err != nil {
+			t.Fatalf("unable to add event: %v", err)
+		}
+	}
+
+	// Events of another channel shouldn't show up within the timeline.
+	err = db.AddChannelEvent(otherChanPoint, &ChannelEvent{
+		Type:      ChannelOpenedEvent,
+		Timestamp: start.Add(time.Minute),
+	})
+	if err != nil {
+		t.Fatalf("unable to add event: %v", err)
+	}
+
+	events, err = db.FetchChannelEvents(chanPoint, time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("unable to fetch events: %v", err)
+	}
+	if !reflect.DeepEqual(timeline, events) {
+		t.Fatalf("timeline fetched from db doesn't match original "+
+			"%v vs %v", spew.Sdump(timeline), spew.Sdump(events))
+	}
+
+	// Only the events within the queried range, including its bounds,
+	// should be returned.
+	events, err = db.FetchChannelEvents(chanPoint, start.Add(time.Hour),
+		start.Add(time.Hour*3))
+	if err != nil {
+		t.Fatalf("unable to fetch events: %v", err)
+	}
+	if !reflect.DeepEqual(timeline[1:4], events) {
+		t.Fatalf("timeline fetched from db doesn't match original "+
+			"%v vs %v", spew.Sdump(timeline[1:4]),
+			spew.Sdump(events))
+	}
+
+	// An event with a timestamp identical to an existing event should be
+	// recorded after it, rather than overwriting it.
+	resolved := &ChannelEvent{
+		Type:      ChannelResolvedEvent,
+		Timestamp: start.Add(time.Hour * 4),
+		Detail:    "closing_txid",
+	}
+	if err := db.AddChannelEvent(chanPoint, resolved); err != nil {
+		t.Fatalf("unable to add event: %v", err)
+	}
+	events, err = db.FetchChannelEvents(chanPoint, start.Add(time.Hour*4),
+		time.Time{})
+	if err != nil {
+		t.Fatalf("unable to fetch events: %v", err)
+	}
+	if len(events) != 2 || events[0].Type != CloseInitiatedEvent ||
+		events[1].Type != ChannelResolvedEvent {
+		t.Fatalf("unexpected events: %v", spew.Sdump(events))
+	}
+
+	// Finally, the most recent event of a particular type should be
+	// retrievable.
+	last, err := db.LastChannelEvent(chanPoint, PolicyUpdatedEvent)
+	if err != nil {
+		t.Fatalf("unable to fetch last event: %v", err)
+	}
+	if !reflect.DeepEqual(timeline[1], last) {
+		t.Fatalf("last event doesn't match original %v vs %v",
+			spew.Sdump(timeline[1]), spew.Sdump(last))
+	}
+	last, err = db.LastChannelEvent(otherChanPoint, PolicyUpdatedEvent)
+	if err != nil {
+		t.Fatalf("unable to fetch last event: %v", err)
+	}
+	if last != nil {
+		t.Fatalf("expected no event, got %v", spew.Sdump(last))
+	}
+}
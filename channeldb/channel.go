@@ -68,6 +68,10 @@ var (
 	satReceivedPrefix    = []byte("srp")
 	netFeesPrefix        = []byte("ntp")
 	isPendingPrefix      = []byte("pdg")
+	commitFeePrefix      = []byte("cfp")
+	chanReservePrefix    = []byte("crp")
+	commitFormatPrefix   = []byte("cfm")
+	outgoingFrozenPrefix = []byte("ofz")
 
 	// chanIDKey stores the node, and channelID for an active channel.
 	chanIDKey = []byte("cik")
@@ -117,8 +121,42 @@ const (
 	// funds towards the total capacity of the channel. The channel may be
 	// funded symmetrically or asymmetrically.
 	DualFunder = 1
+
+	// RecoveredChannel represents a channel reconstructed from recovery
+	// data exported by another implementation. As the channel's
+	// commitment state is unknown, it may only be cooperatively closed.
+	RecoveredChannel = 2
+)
+
+// CommitmentFormat denotes the format of the commitment transactions of a
+// channel, which is fixed when the channel is funded.
+type CommitmentFormat uint8
+
+const (
+	// LegacyCommitment is the format of commitment transactions carrying
+	// only the outputs of each party's balance and the pending HTLCs. All
+	// channels created before the format was tracked are of this format.
+	LegacyCommitment CommitmentFormat = 0
+
+	// AnchorCommitment is the format of commitment transactions which
+	// additionally carry an anchor output for each party, spendable by
+	// its funding key. Either party may spend its anchor to bump the fee
+	// of the commitment transaction via CPFP.
+	AnchorCommitment CommitmentFormat = 1
 )
 
+// String returns a human readable version of the CommitmentFormat.
+func (f CommitmentFormat) String() string {
+	switch f {
+	case LegacyCommitment:
+		return "legacy"
+	case AnchorCommitment:
+		return "anchors"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(f))
+	}
+}
+
 // OpenChannel encapsulates the persistent and dynamic state of an open channel
 // with a remote node. An open channel supports several options for on-disk
 // serialization depending on the exact context. Full (upon channel creation)
@@ -174,6 +212,20 @@ type OpenChannel struct {
 	// channel directly spendable by the remote node.
 	TheirBalance btcutil.Amount
 
+	// CommitFee is the fee paid by the current commitment transaction. The
+	// fee is paid by the initiator of the channel, who may propose a new
+	// fee during the lifetime of the channel.
+	CommitFee btcutil.Amount
+
+	// ChanReserve is the minimum balance each side of the channel must
+	// maintain, as negotiated during the funding workflow.
+	ChanReserve btcutil.Amount
+
+	// ChanReserveNegotiated denotes whether ChanReserve was negotiated
+	// during the funding workflow. Channels funded before the reserve was
+	// negotiated have none, and may have a reserve applied by the caller.
+	ChanReserveNegotiated bool
+
 	// OurCommitKey is the latest version of the commitment state,
 	// broadcast able by us.
 	OurCommitTx *wire.MsgTx
@@ -190,6 +242,10 @@ type OpenChannel struct {
 	// ChanType denotes which type of channel this is.
 	ChanType ChannelType
 
+	// CommitFormat is the format of the channel's commitment
+	// transactions.
+	CommitFormat CommitmentFormat
+
 	// IsInitiator is a bool which indicates if we were the original
 	// initiator for the channel. This value may affect how higher levels
 	// negotiate fees, or close the channel.
@@ -273,6 +329,12 @@ type OpenChannel struct {
 	// within the channel.
 	Htlcs []*HTLC
 
+	// OutgoingFrozen denotes that the operator has frozen the channel, so
+	// no new HTLCs may be offered over it, while those offered by the
+	// remote party are still accepted. A channel is typically frozen to
+	// drain it ahead of its closure.
+	OutgoingFrozen bool
+
 	// TODO(roasbeef): eww
 	Db *DB
 
@@ -371,6 +433,23 @@ func (c *OpenChannel) SyncPending(addr *net.TCPAddr) error {
 	})
 }
 
+// SetOutgoingFrozen freezes or thaws the channel, persisting the flag
+// preventing new HTLCs from being offered over it.
+func (c *OpenChannel) SetOutgoingFrozen(frozen bool) error {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.Db.Update(func(tx *bolt.Tx) error {
+		chanBucket, err := tx.CreateBucketIfNotExists(openChannelBucket)
+		if err != nil {
+			return err
+		}
+
+		c.OutgoingFrozen = frozen
+		return putChanOutgoingFrozen(chanBucket, c)
+	})
+}
+
 // UpdateCommitment updates the on-disk state of our currently broadcastable
 // commitment state. This method is to be called once we have revoked our prior
 // commitment state, accepting the new state as defined by the passed
@@ -400,6 +479,7 @@ func (c *OpenChannel) UpdateCommitment(newCommitment *wire.MsgTx,
 		c.OurCommitSig = newSig
 		c.OurBalance = delta.LocalBalance
 		c.TheirBalance = delta.RemoteBalance
+		c.CommitFee = delta.CommitFee
 		c.NumUpdates = delta.UpdateNum
 		c.Htlcs = delta.Htlcs
 
@@ -410,6 +490,9 @@ func (c *OpenChannel) UpdateCommitment(newCommitment *wire.MsgTx,
 		if err := putChanCapacity(chanBucket, c); err != nil {
 			return err
 		}
+		if err := putChanCommitFee(chanBucket, c); err != nil {
+			return err
+		}
 		if err := putChanAmountsTransferred(chanBucket, c); err != nil {
 			return err
 		}
@@ -477,6 +560,10 @@ type ChannelDelta struct {
 	RemoteBalance btcutil.Amount
 	UpdateNum     uint64
 
+	// CommitFee is the fee paid by the commitment. It isn't written to
+	// the revocation log, as it's only needed for the current state.
+	CommitFee btcutil.Amount
+
 	// TODO(roasbeef): add blockhash or timestamp?
 
 	Htlcs []*HTLC
@@ -586,74 +673,126 @@ func (c *OpenChannel) FindPreviousState(updateNum uint64) (*ChannelDelta, error)
 	return delta, nil
 }
 
-// CloseChannel closes a previously active lightning channel. Closing a channel
-// entails deleting all saved state within the database concerning this
-// channel, as well as created a small channel summary for record keeping
-// purposes.
-func (c *OpenChannel) CloseChannel() error {
-	return c.Db.Update(func(tx *bolt.Tx) error {
-		// First fetch the top level bucket which stores all data
-		// related to current, active channels.
+// RevocationLog returns every state recorded within the channel's revocation
+// log, ordered by update number.
+func (c *OpenChannel) RevocationLog() ([]*ChannelDelta, error) {
+	var deltas []*ChannelDelta
+
+	err := c.Db.View(func(tx *bolt.Tx) error {
 		chanBucket := tx.Bucket(openChannelBucket)
 		if chanBucket == nil {
 			return ErrNoChanDBExists
 		}
 
-		// Within this top level bucket, fetch the bucket dedicated to
-		// storing open channel data specific to the remote node.
 		nodePub := c.IdentityPub.SerializeCompressed()
 		nodeChanBucket := chanBucket.Bucket(nodePub)
 		if nodeChanBucket == nil {
 			return ErrNoActiveChannels
 		}
 
-		// Delete this channel ID from the node's active channel index.
-		chanIndexBucket := nodeChanBucket.Bucket(chanIDBucket)
-		if chanIndexBucket == nil {
-			return ErrNoActiveChannels
+		// If the log bucket hasn't yet been created, then no state
+		// transitions have taken place.
+		logBucket := nodeChanBucket.Bucket(channelLogBucket)
+		if logBucket == nil {
+			return nil
 		}
 
-		var b bytes.Buffer
-		if err := writeOutpoint(&b, c.ChanID); err != nil {
-			return err
-		}
+		var err error
+		deltas, err = fetchChannelLogEntries(logBucket, c.ChanID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
 
-		// If this channel isn't found within the channel index bucket,
-		// then it has already been deleted. So we can exit early as
-		// there isn't any more work for us to do here.
-		outPointBytes := b.Bytes()
-		if chanIndexBucket.Get(outPointBytes) == nil {
-			return nil
-		}
+	return deltas, nil
+}
 
-		// Otherwise, we can safely delete the channel from the index
-		// without running into any boltdb related errors by repeated
-		// deletion attempts.
-		if err := chanIndexBucket.Delete(outPointBytes); err != nil {
-			return err
-		}
+// CloseChannel closes a previously active lightning channel. Closing a channel
+// entails deleting all saved state within the database concerning this
+// channel, as well as created a small channel summary for record keeping
+// purposes.
+func (c *OpenChannel) CloseChannel() error {
+	return c.Db.Update(c.closeChannel)
+}
 
-		// Now that the index to this channel has been deleted, purge
-		// the remaining channel metadata from the database.
-		if err := deleteOpenChannel(chanBucket, nodeChanBucket,
-			outPointBytes, c.ChanID); err != nil {
+// closeChannel deletes the state of the channel within the passed
+// transaction, and records a summary of the channel within the closed
+// channel bucket.
+func (c *OpenChannel) closeChannel(tx *bolt.Tx) error {
+	// First fetch the top level bucket which stores all data
+	// related to current, active channels.
+	chanBucket := tx.Bucket(openChannelBucket)
+	if chanBucket == nil {
+		return ErrNoChanDBExists
+	}
+
+	// Within this top level bucket, fetch the bucket dedicated to
+	// storing open channel data specific to the remote node.
+	nodePub := c.IdentityPub.SerializeCompressed()
+	nodeChanBucket := chanBucket.Bucket(nodePub)
+	if nodeChanBucket == nil {
+		return ErrNoActiveChannels
+	}
+
+	// Delete this channel ID from the node's active channel index.
+	chanIndexBucket := nodeChanBucket.Bucket(chanIDBucket)
+	if chanIndexBucket == nil {
+		return ErrNoActiveChannels
+	}
+
+	var b bytes.Buffer
+	if err := writeOutpoint(&b, c.ChanID); err != nil {
+		return err
+	}
+
+	// If this channel isn't found within the channel index bucket,
+	// then it has already been deleted. So we can exit early as
+	// there isn't any more work for us to do here.
+	outPointBytes := b.Bytes()
+	if chanIndexBucket.Get(outPointBytes) == nil {
+		return nil
+	}
+
+	// Otherwise, we can safely delete the channel from the index
+	// without running into any boltdb related errors by repeated
+	// deletion attempts.
+	if err := chanIndexBucket.Delete(outPointBytes); err != nil {
+		return err
+	}
+
+	// Now that the index to this channel has been deleted, purge
+	// the remaining channel metadata from the database.
+	if err := deleteOpenChannel(chanBucket, nodeChanBucket,
+		outPointBytes, c.ChanID); err != nil {
+		return err
+	}
+
+	// With the base channel data deleted, attempt to delte the
+	// information stored within the revocation log.
+	logBucket := nodeChanBucket.Bucket(channelLogBucket)
+	if logBucket != nil {
+		err := wipeChannelLogEntries(logBucket, c.ChanID)
+		if err != nil {
 			return err
 		}
+	}
 
-		// With the base channel data deleted, attempt to delte the
-		// information stored within the revocation log.
-		logBucket := nodeChanBucket.Bucket(channelLogBucket)
-		if logBucket != nil {
-			err := wipeChannelLogEntries(logBucket, c.ChanID)
-			if err != nil {
-				return err
-			}
-		}
+	// The messages retained for retransmission to the remote party are
+	// of no further use once the channel has been closed.
+	if err := wipeLastChanMsgs(tx, outPointBytes); err != nil {
+		return err
+	}
 
-		// Finally, create a summary of this channel in the closed
-		// channel bucket for this node.
-		return putClosedChannelSummary(tx, outPointBytes)
-	})
+	// Likewise, the channel's output scripts no longer need to be
+	// indexed.
+	if err := wipeCommitOutputs(tx, outPointBytes); err != nil {
+		return err
+	}
+
+	// Finally, create a summary of this channel in the closed
+	// channel bucket for this node.
+	return putClosedChannelSummary(tx, outPointBytes)
 }
 
 // ChannelSnapshot is a frozen snapshot of the current channel state. A
@@ -729,6 +868,15 @@ func putOpenChannel(openChanBucket *bolt.Bucket, nodeChanBucket *bolt.Bucket,
 	if err := putChanCapacity(openChanBucket, channel); err != nil {
 		return err
 	}
+	if err := putChanCommitFee(openChanBucket, channel); err != nil {
+		return err
+	}
+	if err := putChanReserve(openChanBucket, channel); err != nil {
+		return err
+	}
+	if err := putChanCommitFormat(openChanBucket, channel); err != nil {
+		return err
+	}
 	if err := putChanMinFeePerKb(openChanBucket, channel); err != nil {
 		return err
 	}
@@ -747,6 +895,9 @@ func putOpenChannel(openChanBucket *bolt.Bucket, nodeChanBucket *bolt.Bucket,
 	if err := putChanIsPending(openChanBucket, channel); err != nil {
 		return err
 	}
+	if err := putChanOutgoingFrozen(openChanBucket, channel); err != nil {
+		return err
+	}
 
 	// Next, write out the fields of the channel update less frequently.
 	if err := putChannelIDs(nodeChanBucket, channel); err != nil {
@@ -816,6 +967,15 @@ func fetchOpenChannel(openChanBucket *bolt.Bucket, nodeChanBucket *bolt.Bucket,
 	if err = fetchChanCapacity(openChanBucket, channel); err != nil {
 		return nil, fmt.Errorf("unable to read chan capacity: %v", err)
 	}
+	if err = fetchChanCommitFee(openChanBucket, channel); err != nil {
+		return nil, fmt.Errorf("unable to read commit fee: %v", err)
+	}
+	if err = fetchChanReserve(openChanBucket, channel); err != nil {
+		return nil, fmt.Errorf("unable to read chan reserve: %v", err)
+	}
+	if err = fetchChanCommitFormat(openChanBucket, channel); err != nil {
+		return nil, fmt.Errorf("unable to read commit format: %v", err)
+	}
 	if err = fetchChanMinFeePerKb(openChanBucket, channel); err != nil {
 		return nil, fmt.Errorf("unable to read fee-per-kb: %v", err)
 	}
@@ -834,6 +994,9 @@ func fetchOpenChannel(openChanBucket *bolt.Bucket, nodeChanBucket *bolt.Bucket,
 	if err = fetchChanIsPending(openChanBucket, channel); err != nil {
 		return nil, err
 	}
+	if err = fetchChanOutgoingFrozen(openChanBucket, channel); err != nil {
+		return nil, fmt.Errorf("unable to read frozen flag: %v", err)
+	}
 
 	return channel, nil
 }
@@ -846,6 +1009,15 @@ func deleteOpenChannel(openChanBucket *bolt.Bucket, nodeChanBucket *bolt.Bucket,
 	if err := deleteChanCapacity(openChanBucket, channelID); err != nil {
 		return err
 	}
+	if err := deleteChanCommitFee(openChanBucket, channelID); err != nil {
+		return err
+	}
+	if err := deleteChanReserve(openChanBucket, channelID); err != nil {
+		return err
+	}
+	if err := deleteChanCommitFormat(openChanBucket, channelID); err != nil {
+		return err
+	}
 	if err := deleteChanMinFeePerKb(openChanBucket, channelID); err != nil {
 		return err
 	}
@@ -864,6 +1036,9 @@ func deleteOpenChannel(openChanBucket *bolt.Bucket, nodeChanBucket *bolt.Bucket,
 	if err := deleteChanIsPending(openChanBucket, channelID); err != nil {
 		return err
 	}
+	if err := deleteChanOutgoingFrozen(openChanBucket, channelID); err != nil {
+		return err
+	}
 
 	// Finally, delete all the fields directly within the node's channel
 	// bucket.
@@ -888,6 +1063,12 @@ func deleteOpenChannel(openChanBucket *bolt.Bucket, nodeChanBucket *bolt.Bucket,
 	if err := deleteCurrentHtlcs(nodeChanBucket, o); err != nil {
 		return err
 	}
+	if err := deleteChanSplice(nodeChanBucket, o); err != nil {
+		return err
+	}
+	if err := deleteChanCoopClose(nodeChanBucket, o); err != nil {
+		return err
+	}
 
 	return nil
 }
@@ -966,6 +1147,155 @@ func fetchChanCapacity(openChanBucket *bolt.Bucket, channel *OpenChannel) error
 	return nil
 }
 
+func putChanCommitFee(openChanBucket *bolt.Bucket, channel *OpenChannel) error {
+	scratch := make([]byte, 8)
+	byteOrder.PutUint64(scratch, uint64(channel.CommitFee))
+
+	var b bytes.Buffer
+	if err := writeOutpoint(&b, channel.ChanID); err != nil {
+		return err
+	}
+
+	keyPrefix := make([]byte, 3+b.Len())
+	copy(keyPrefix, commitFeePrefix)
+	copy(keyPrefix[3:], b.Bytes())
+
+	return openChanBucket.Put(keyPrefix, scratch)
+}
+
+func deleteChanCommitFee(openChanBucket *bolt.Bucket, chanID []byte) error {
+	keyPrefix := make([]byte, 3+len(chanID))
+	copy(keyPrefix, commitFeePrefix)
+	copy(keyPrefix[3:], chanID)
+	return openChanBucket.Delete(keyPrefix)
+}
+
+// fetchChanCommitFee reads the fee of the channel's current commitment. The
+// capacity, balances, and HTLCs of the channel MUST already be populated, as
+// channels created before the fee was stored have it derived from them.
+func fetchChanCommitFee(openChanBucket *bolt.Bucket, channel *OpenChannel) error {
+	var b bytes.Buffer
+	if err := writeOutpoint(&b, channel.ChanID); err != nil {
+		return err
+	}
+
+	keyPrefix := make([]byte, 3+b.Len())
+	copy(keyPrefix, commitFeePrefix)
+	copy(keyPrefix[3:], b.Bytes())
+
+	feeBytes := openChanBucket.Get(keyPrefix)
+	if feeBytes != nil {
+		channel.CommitFee = btcutil.Amount(byteOrder.Uint64(feeBytes))
+		return nil
+	}
+
+	// The fee is whatever remains of the capacity once the balances and
+	// the value of all pending HTLCs are accounted for.
+	channel.CommitFee = channel.Capacity - channel.OurBalance -
+		channel.TheirBalance
+	for _, htlc := range channel.Htlcs {
+		channel.CommitFee -= htlc.Amt
+	}
+
+	return nil
+}
+
+func putChanReserve(openChanBucket *bolt.Bucket, channel *OpenChannel) error {
+	scratch := make([]byte, 9)
+	byteOrder.PutUint64(scratch, uint64(channel.ChanReserve))
+	if channel.ChanReserveNegotiated {
+		scratch[8] = 1
+	}
+
+	var b bytes.Buffer
+	if err := writeOutpoint(&b, channel.ChanID); err != nil {
+		return err
+	}
+
+	keyPrefix := make([]byte, 3+b.Len())
+	copy(keyPrefix, chanReservePrefix)
+	copy(keyPrefix[3:], b.Bytes())
+
+	return openChanBucket.Put(keyPrefix, scratch)
+}
+
+func deleteChanReserve(openChanBucket *bolt.Bucket, chanID []byte) error {
+	keyPrefix := make([]byte, 3+len(chanID))
+	copy(keyPrefix, chanReservePrefix)
+	copy(keyPrefix[3:], chanID)
+	return openChanBucket.Delete(keyPrefix)
+}
+
+// fetchChanReserve reads the channel reserve negotiated during funding.
+// Channels created before the reserve was negotiated have none stored, and
+// are left with a reserve of zero which isn't marked as negotiated. Reserves
+// stored without the marker were only ever negotiated if non-zero.
+func fetchChanReserve(openChanBucket *bolt.Bucket, channel *OpenChannel) error {
+	var b bytes.Buffer
+	if err := writeOutpoint(&b, channel.ChanID); err != nil {
+		return err
+	}
+
+	keyPrefix := make([]byte, 3+b.Len())
+	copy(keyPrefix, chanReservePrefix)
+	copy(keyPrefix[3:], b.Bytes())
+
+	reserveBytes := openChanBucket.Get(keyPrefix)
+	if reserveBytes == nil {
+		return nil
+	}
+
+	channel.ChanReserve = btcutil.Amount(byteOrder.Uint64(reserveBytes))
+	if len(reserveBytes) > 8 {
+		channel.ChanReserveNegotiated = reserveBytes[8] == 1
+	} else {
+		channel.ChanReserveNegotiated = channel.ChanReserve != 0
+	}
+
+	return nil
+}
+
+func putChanCommitFormat(openChanBucket *bolt.Bucket, channel *OpenChannel) error {
+	var b bytes.Buffer
+	if err := writeOutpoint(&b, channel.ChanID); err != nil {
+		return err
+	}
+
+	keyPrefix := make([]byte, 3+b.Len())
+	copy(keyPrefix, commitFormatPrefix)
+	copy(keyPrefix[3:], b.Bytes())
+
+	return openChanBucket.Put(keyPrefix, []byte{byte(channel.CommitFormat)})
+}
+
+func deleteChanCommitFormat(openChanBucket *bolt.Bucket, chanID []byte) error {
+	keyPrefix := make([]byte, 3+len(chanID))
+	copy(keyPrefix, commitFormatPrefix)
+	copy(keyPrefix[3:], chanID)
+	return openChanBucket.Delete(keyPrefix)
+}
+
+// fetchChanCommitFormat reads the format of the channel's commitment
+// transactions. Channels created before the format was stored have none, and
+// are of the legacy format.
+func fetchChanCommitFormat(openChanBucket *bolt.Bucket, channel *OpenChannel) error {
+	var b bytes.Buffer
+	if err := writeOutpoint(&b, channel.ChanID); err != nil {
+		return err
+	}
+
+	keyPrefix := make([]byte, 3+b.Len())
+	copy(keyPrefix, commitFormatPrefix)
+	copy(keyPrefix[3:], b.Bytes())
+
+	channel.CommitFormat = LegacyCommitment
+	if formatBytes := openChanBucket.Get(keyPrefix); len(formatBytes) == 1 {
+		channel.CommitFormat = CommitmentFormat(formatBytes[0])
+	}
+
+	return nil
+}
+
 func putChanMinFeePerKb(openChanBucket *bolt.Bucket, channel *OpenChannel) error {
 	scratch := make([]byte, 8)
 	byteOrder.PutUint64(scratch, uint64(channel.MinFeePerKb))
@@ -1228,6 +1558,51 @@ func fetchChanIsPending(openChanBucket *bolt.Bucket, channel *OpenChannel) error
 	return nil
 }
 
+func putChanOutgoingFrozen(openChanBucket *bolt.Bucket,
+	channel *OpenChannel) error {
+
+	var b bytes.Buffer
+	if err := writeOutpoint(&b, channel.ChanID); err != nil {
+		return err
+	}
+
+	keyPrefix := make([]byte, 3+b.Len())
+	copy(keyPrefix, outgoingFrozenPrefix)
+	copy(keyPrefix[3:], b.Bytes())
+
+	// Only frozen channels carry the flag, so channels created before it
+	// was introduced are read as thawed.
+	if !channel.OutgoingFrozen {
+		return openChanBucket.Delete(keyPrefix)
+	}
+	return openChanBucket.Put(keyPrefix, []byte{1})
+}
+
+func deleteChanOutgoingFrozen(openChanBucket *bolt.Bucket, chanID []byte) error {
+	keyPrefix := make([]byte, 3+len(chanID))
+	copy(keyPrefix, outgoingFrozenPrefix)
+	copy(keyPrefix[3:], chanID)
+	return openChanBucket.Delete(keyPrefix)
+}
+
+func fetchChanOutgoingFrozen(openChanBucket *bolt.Bucket,
+	channel *OpenChannel) error {
+
+	var b bytes.Buffer
+	if err := writeOutpoint(&b, channel.ChanID); err != nil {
+		return err
+	}
+
+	keyPrefix := make([]byte, 3+b.Len())
+	copy(keyPrefix, outgoingFrozenPrefix)
+	copy(keyPrefix[3:], b.Bytes())
+
+	frozenBytes := openChanBucket.Get(keyPrefix)
+	channel.OutgoingFrozen = len(frozenBytes) == 1 && frozenBytes[0] == 1
+
+	return nil
+}
+
 func putChannelIDs(nodeChanBucket *bolt.Bucket, channel *OpenChannel) error {
 	// TODO(roasbeef): just pass in chanID everywhere for puts
 	var b bytes.Buffer
@@ -1921,6 +2296,34 @@ func fetchChannelLogEntry(log *bolt.Bucket, chanPoint *wire.OutPoint,
 	return deserializeChannelDelta(deltaReader)
 }
 
+func fetchChannelLogEntries(log *bolt.Bucket,
+	o *wire.OutPoint) ([]*ChannelDelta, error) {
+
+	var (
+		logPrefix [32 + 4]byte
+		scratch   [4]byte
+	)
+
+	// As the update number forms the suffix of each log key, scanning
+	// with the channel's prefix yields its entries in update order.
+	n := copy(logPrefix[:], o.Hash[:])
+	byteOrder.PutUint32(scratch[:], o.Index)
+	copy(logPrefix[n:], scratch[:])
+
+	var deltas []*ChannelDelta
+	logCursor := log.Cursor()
+	for k, v := logCursor.Seek(logPrefix[:]); bytes.HasPrefix(k, logPrefix[:]); k, v = logCursor.Next() {
+		delta, err := deserializeChannelDelta(bytes.NewReader(v))
+		if err != nil {
+			return nil, err
+		}
+
+		deltas = append(deltas, delta)
+	}
+
+	return deltas, nil
+}
+
 func wipeChannelLogEntries(log *bolt.Bucket, o *wire.OutPoint) error {
 	var (
 		n         int
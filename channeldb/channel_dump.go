@@ -0,0 +1,407 @@
+package channeldb
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/lightningnetwork/lnd/shachain"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+// ChannelDump is a complete, redacted export of a channel's state intended
+// to be attached to bug reports. All secrets which would allow funds within
+// the channel to be stolen, namely our revocation root, the revocation
+// hashes received from the remote party, and the remote party's signature
+// for our commitment transaction, are omitted. Only the height of the
+// revocation store is recorded.
+//
+// A ChannelDump is deterministic: exporting the same channel state twice
+// yields identical documents.
+type ChannelDump struct {
+	ChanPoint    string `json:"chan_point"`
+	IdentityPub  string `json:"identity_pub"`
+	ChanType     uint8  `json:"chan_type"`
+	CommitFormat uint8  `json:"commit_format"`
+	IsInitiator  bool   `json:"is_initiator"`
+	IsPending    bool   `json:"is_pending"`
+
+	OutgoingFrozen        bool `json:"outgoing_frozen"`
+	ChanReserveNegotiated bool `json:"chan_reserve_negotiated"`
+
+	Capacity       int64 `json:"capacity"`
+	OurBalance     int64 `json:"our_balance"`
+	TheirBalance   int64 `json:"their_balance"`
+	CommitFee      int64 `json:"commit_fee"`
+	ChanReserve    int64 `json:"chan_reserve"`
+	MinFeePerKb    int64 `json:"min_fee_per_kb"`
+	OurDustLimit   int64 `json:"our_dust_limit"`
+	TheirDustLimit int64 `json:"their_dust_limit"`
+
+	FundingOutpoint      string `json:"funding_outpoint"`
+	FundingWitnessScript string `json:"funding_witness_script"`
+	OurMultiSigKey       string `json:"our_multisig_key"`
+	TheirMultiSigKey     string `json:"their_multisig_key"`
+	OurCommitKey         string `json:"our_commit_key"`
+	TheirCommitKey       string `json:"their_commit_key"`
+	OurCommitTx          string `json:"our_commit_tx"`
+	StateHintObsfucator  string `json:"state_hint_obsfucator"`
+
+	NumConfsRequired uint16 `json:"num_confs_required"`
+	LocalCsvDelay    uint32 `json:"local_csv_delay"`
+	RemoteCsvDelay   uint32 `json:"remote_csv_delay"`
+
+	TheirCurrentRevocation     string `json:"their_current_revocation"`
+	TheirCurrentRevocationHash string `json:"their_current_revocation_hash"`
+
+	OurDeliveryScript   string `json:"our_delivery_script"`
+	TheirDeliveryScript string `json:"their_delivery_script"`
+
+	NumUpdates            uint64 `json:"num_updates"`
+	TotalSatoshisSent     uint64 `json:"total_satoshis_sent"`
+	TotalSatoshisReceived uint64 `json:"total_satoshis_received"`
+	CreationTime          int64  `json:"creation_time"`
+
+	// RevocationStoreHeight is the number of revocation hashes we've
+	// received from the remote party, and RevocationStoreBuckets the
+	// number of hashes the store retains in order to derive them.
+	RevocationStoreHeight  uint64 `json:"revocation_store_height"`
+	RevocationStoreBuckets uint8  `json:"revocation_store_buckets"`
+
+	Htlcs         []HTLCDump         `json:"htlcs"`
+	RevocationLog []ChannelDeltaDump `json:"revocation_log"`
+}
+
+// HTLCDump is the exported form of an HTLC within a ChannelDump.
+type HTLCDump struct {
+	Incoming        bool   `json:"incoming"`
+	Amt             int64  `json:"amt"`
+	RHash           string `json:"r_hash"`
+	RefundTimeout   uint32 `json:"refund_timeout"`
+	RevocationDelay uint32 `json:"revocation_delay"`
+	OutputIndex     uint16 `json:"output_index"`
+}
+
+// ChannelDeltaDump is the exported form of a revocation log entry within a
+// ChannelDump.
+type ChannelDeltaDump struct {
+	UpdateNum     uint64     `json:"update_num"`
+	LocalBalance  int64      `json:"local_balance"`
+	RemoteBalance int64      `json:"remote_balance"`
+	Htlcs         []HTLCDump `json:"htlcs"`
+}
+
+// Dump exports the complete state of the channel, including its revocation
+// log, as a redacted ChannelDump.
+func (c *OpenChannel) Dump() (*ChannelDump, error) {
+	c.RLock()
+	defer c.RUnlock()
+
+	var commitTx bytes.Buffer
+	if c.OurCommitTx != nil {
+		if err := c.OurCommitTx.Serialize(&commitTx); err != nil {
+			return nil, err
+		}
+	}
+
+	dump := &ChannelDump{
+		ChanPoint:                  c.ChanID.String(),
+		IdentityPub:                hexPubKey(c.IdentityPub),
+		ChanType:                   uint8(c.ChanType),
+		CommitFormat:               uint8(c.CommitFormat),
+		IsInitiator:                c.IsInitiator,
+		IsPending:                  c.IsPending,
+		Capacity:                   int64(c.Capacity),
+		OurBalance:                 int64(c.OurBalance),
+		TheirBalance:               int64(c.TheirBalance),
+		CommitFee:                  int64(c.CommitFee),
+		ChanReserve:                int64(c.ChanReserve),
+		ChanReserveNegotiated:      c.ChanReserveNegotiated,
+		MinFeePerKb:                int64(c.MinFeePerKb),
+		OurDustLimit:               int64(c.OurDustLimit),
+		TheirDustLimit:             int64(c.TheirDustLimit),
+		FundingWitnessScript:       hex.EncodeToString(c.FundingWitnessScript),
+		OurMultiSigKey:             hexPubKey(c.OurMultiSigKey),
+		TheirMultiSigKey:           hexPubKey(c.TheirMultiSigKey),
+		OurCommitKey:               hexPubKey(c.OurCommitKey),
+		TheirCommitKey:             hexPubKey(c.TheirCommitKey),
+		OurCommitTx:                hex.EncodeToString(commitTx.Bytes()),
+		StateHintObsfucator:        hex.EncodeToString(c.StateHintObsfucator[:]),
+		NumConfsRequired:           c.NumConfsRequired,
+		LocalCsvDelay:              c.LocalCsvDelay,
+		RemoteCsvDelay:             c.RemoteCsvDelay,
+		TheirCurrentRevocation:     hexPubKey(c.TheirCurrentRevocation),
+		TheirCurrentRevocationHash: hex.EncodeToString(c.TheirCurrentRevocationHash[:]),
+		OurDeliveryScript:          hex.EncodeToString(c.OurDeliveryScript),
+		TheirDeliveryScript:        hex.EncodeToString(c.TheirDeliveryScript),
+		NumUpdates:                 c.NumUpdates,
+		TotalSatoshisSent:          c.TotalSatoshisSent,
+		TotalSatoshisReceived:      c.TotalSatoshisReceived,
+		OutgoingFrozen:             c.OutgoingFrozen,
+		CreationTime:               c.CreationTime.Unix(),
+		Htlcs:                      dumpHTLCs(c.Htlcs),
+	}
+	if c.FundingOutpoint != nil {
+		dump.FundingOutpoint = c.FundingOutpoint.String()
+	}
+	if store, ok := c.RevocationStore.(*shachain.RevocationStore); ok {
+		dump.RevocationStoreHeight = store.Height()
+		dump.RevocationStoreBuckets = store.NumBuckets()
+	}
+
+	// The revocation log can only be read for channels which have been
+	// written to disk.
+	if c.Db != nil {
+		deltas, err := c.RevocationLog()
+		if err != nil {
+			return nil, err
+		}
+
+		dump.RevocationLog = make([]ChannelDeltaDump, len(deltas))
+		for i, delta := range deltas {
+			dump.RevocationLog[i] = ChannelDeltaDump{
+				UpdateNum:     delta.UpdateNum,
+				LocalBalance:  int64(delta.LocalBalance),
+				RemoteBalance: int64(delta.RemoteBalance),
+				Htlcs:         dumpHTLCs(delta.Htlcs),
+			}
+		}
+	}
+
+	return dump, nil
+}
+
+// ImportChannelDump reconstructs the channel described by the passed
+// ChannelDump, then writes it, along with its revocation log, to the
+// database. The remote node is recorded as reachable at addr. As the secrets
+// omitted from the dump can't be recovered, the imported channel is given a
+// fresh revocation root, and an empty revocation store. The resulting channel
+// is therefore only suitable for reproducing issues within test harnesses,
+// and must never be used on a live network.
+func (d *DB) ImportChannelDump(dump *ChannelDump,
+	addr *net.TCPAddr) (*OpenChannel, error) {
+
+	c := &OpenChannel{
+		ChanType:              ChannelType(dump.ChanType),
+		CommitFormat:          CommitmentFormat(dump.CommitFormat),
+		IsInitiator:           dump.IsInitiator,
+		IsPending:             dump.IsPending,
+		Capacity:              btcutil.Amount(dump.Capacity),
+		OurBalance:            btcutil.Amount(dump.OurBalance),
+		TheirBalance:          btcutil.Amount(dump.TheirBalance),
+		CommitFee:             btcutil.Amount(dump.CommitFee),
+		ChanReserve:           btcutil.Amount(dump.ChanReserve),
+		ChanReserveNegotiated: dump.ChanReserveNegotiated,
+		MinFeePerKb:           btcutil.Amount(dump.MinFeePerKb),
+		OurDustLimit:          btcutil.Amount(dump.OurDustLimit),
+		TheirDustLimit:        btcutil.Amount(dump.TheirDustLimit),
+		NumConfsRequired:      dump.NumConfsRequired,
+		LocalCsvDelay:         dump.LocalCsvDelay,
+		RemoteCsvDelay:        dump.RemoteCsvDelay,
+		NumUpdates:            dump.NumUpdates,
+		TotalSatoshisSent:     dump.TotalSatoshisSent,
+		TotalSatoshisReceived: dump.TotalSatoshisReceived,
+		OutgoingFrozen:        dump.OutgoingFrozen,
+		CreationTime:          time.Unix(dump.CreationTime, 0),
+		RevocationStore:       shachain.NewRevocationStore(),
+		Db:                    d,
+	}
+
+	var err error
+	if c.ChanID, err = parseOutPoint(dump.ChanPoint); err != nil {
+		return nil, err
+	}
+	c.FundingOutpoint = c.ChanID
+	if dump.FundingOutpoint != "" {
+		c.FundingOutpoint, err = parseOutPoint(dump.FundingOutpoint)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	pubKeys := []struct {
+		hexKey string
+		key    **btcec.PublicKey
+	}{
+		{dump.IdentityPub, &c.IdentityPub},
+		{dump.OurMultiSigKey, &c.OurMultiSigKey},
+		{dump.TheirMultiSigKey, &c.TheirMultiSigKey},
+		{dump.OurCommitKey, &c.OurCommitKey},
+		{dump.TheirCommitKey, &c.TheirCommitKey},
+		{dump.TheirCurrentRevocation, &c.TheirCurrentRevocation},
+	}
+	for _, pubKey := range pubKeys {
+		if *pubKey.key, err = parseHexPubKey(pubKey.hexKey); err != nil {
+			return nil, err
+		}
+	}
+
+	byteFields := []struct {
+		hexBytes string
+		field    *[]byte
+	}{
+		{dump.FundingWitnessScript, &c.FundingWitnessScript},
+		{dump.OurDeliveryScript, &c.OurDeliveryScript},
+		{dump.TheirDeliveryScript, &c.TheirDeliveryScript},
+	}
+	for _, byteField := range byteFields {
+		*byteField.field, err = hex.DecodeString(byteField.hexBytes)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := decodeHexArray(c.StateHintObsfucator[:],
+		dump.StateHintObsfucator); err != nil {
+		return nil, err
+	}
+	if err := decodeHexArray(c.TheirCurrentRevocationHash[:],
+		dump.TheirCurrentRevocationHash); err != nil {
+		return nil, err
+	}
+
+	commitTx, err := hex.DecodeString(dump.OurCommitTx)
+	if err != nil {
+		return nil, err
+	}
+	c.OurCommitTx = wire.NewMsgTx(1)
+	if err := c.OurCommitTx.Deserialize(bytes.NewReader(commitTx)); err != nil {
+		return nil, err
+	}
+
+	if c.Htlcs, err = importHTLCs(dump.Htlcs); err != nil {
+		return nil, err
+	}
+
+	// The revocation root is never exported, so we generate a fresh one.
+	var root chainhash.Hash
+	if _, err := rand.Read(root[:]); err != nil {
+		return nil, err
+	}
+	c.RevocationProducer = shachain.NewRevocationProducer(root)
+
+	if err := c.SyncPending(addr); err != nil {
+		return nil, err
+	}
+
+	for _, deltaDump := range dump.RevocationLog {
+		delta := &ChannelDelta{
+			UpdateNum:     deltaDump.UpdateNum,
+			LocalBalance:  btcutil.Amount(deltaDump.LocalBalance),
+			RemoteBalance: btcutil.Amount(deltaDump.RemoteBalance),
+		}
+		if delta.Htlcs, err = importHTLCs(deltaDump.Htlcs); err != nil {
+			return nil, err
+		}
+
+		if err := c.AppendToRevocationLog(delta); err != nil {
+			return nil, err
+		}
+	}
+
+	return c, nil
+}
+
+// dumpHTLCs converts the passed HTLCs into their exported form.
+func dumpHTLCs(htlcs []*HTLC) []HTLCDump {
+	dumps := make([]HTLCDump, len(htlcs))
+	for i, htlc := range htlcs {
+		dumps[i] = HTLCDump{
+			Incoming:        htlc.Incoming,
+			Amt:             int64(htlc.Amt),
+			RHash:           hex.EncodeToString(htlc.RHash[:]),
+			RefundTimeout:   htlc.RefundTimeout,
+			RevocationDelay: htlc.RevocationDelay,
+			OutputIndex:     htlc.OutputIndex,
+		}
+	}
+
+	return dumps
+}
+
+// importHTLCs converts the passed exported HTLCs back into HTLCs.
+func importHTLCs(dumps []HTLCDump) ([]*HTLC, error) {
+	htlcs := make([]*HTLC, len(dumps))
+	for i, dump := range dumps {
+		htlc := &HTLC{
+			Incoming:        dump.Incoming,
+			Amt:             btcutil.Amount(dump.Amt),
+			RefundTimeout:   dump.RefundTimeout,
+			RevocationDelay: dump.RevocationDelay,
+			OutputIndex:     dump.OutputIndex,
+		}
+		if err := decodeHexArray(htlc.RHash[:], dump.RHash); err != nil {
+			return nil, err
+		}
+
+		htlcs[i] = htlc
+	}
+
+	return htlcs, nil
+}
+
+// hexPubKey returns the hex encoding of the compressed public key, or an
+// empty string if the key is nil.
+func hexPubKey(key *btcec.PublicKey) string {
+	if key == nil {
+		return ""
+	}
+
+	return hex.EncodeToString(key.SerializeCompressed())
+}
+
+// parseHexPubKey parses a hex encoded public key, returning nil if the
+// string is empty.
+func parseHexPubKey(s string) (*btcec.PublicKey, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	keyBytes, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return btcec.ParsePubKey(keyBytes, btcec.S256())
+}
+
+// decodeHexArray decodes the hex string into the passed fixed size array,
+// ensuring the lengths match.
+func decodeHexArray(dst []byte, s string) error {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return err
+	}
+	if len(b) != len(dst) {
+		return fmt.Errorf("expected %v bytes, got %v", len(dst),
+			len(b))
+	}
+
+	copy(dst, b)
+	return nil
+}
+
+// parseOutPoint parses an outpoint in the txid:index format produced by
+// wire.OutPoint's String method.
+func parseOutPoint(s string) (*wire.OutPoint, error) {
+	var (
+		txid  string
+		index uint32
+	)
+	if _, err := fmt.Sscanf(s, "%64s:%d", &txid, &index); err != nil {
+		return nil, fmt.Errorf("invalid outpoint %v: %v", s, err)
+	}
+
+	hash, err := chainhash.NewHashFromStr(txid)
+	if err != nil {
+		return nil, err
+	}
+
+	return wire.NewOutPoint(hash, index), nil
+}
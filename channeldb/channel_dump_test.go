@@ -0,0 +1,141 @@
+package channeldb
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/roasbeef/btcutil"
+)
+
+func TestChannelDumpImport(t *testing.T) {
+	cdb, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+
+	state, err := createTestChannelState(cdb)
+	if err != nil {
+		t.Fatalf("unable to create channel state: %v", err)
+	}
+	state.Htlcs = []*HTLC{
+		{
+			Incoming:        true,
+			Amt:             10,
+			RHash:           key,
+			RefundTimeout:   1,
+			RevocationDelay: 2,
+			OutputIndex:     3,
+		},
+	}
+	state.OutgoingFrozen = true
+	addr := &net.TCPAddr{
+		IP:   net.ParseIP("127.0.0.1"),
+		Port: 18555,
+	}
+	if err := state.SyncPending(addr); err != nil {
+		t.Fatalf("unable to save channel state: %v", err)
+	}
+
+	// Record a few prior states within the revocation log so they're
+	// included within the dump.
+	for i := uint64(1); i <= 3; i++ {
+		delta := &ChannelDelta{
+			LocalBalance:  btcutil.Amount(1000 * i),
+			RemoteBalance: btcutil.Amount(2000 * i),
+			UpdateNum:     i,
+			Htlcs:         state.Htlcs,
+		}
+		if err := state.AppendToRevocationLog(delta); err != nil {
+			t.Fatalf("unable to append to revocation log: %v", err)
+		}
+	}
+
+	dump, err := state.Dump()
+	if err != nil {
+		t.Fatalf("unable to dump channel: %v", err)
+	}
+	if len(dump.RevocationLog) != 3 {
+		t.Fatalf("expected 3 revocation log entries, got %v",
+			len(dump.RevocationLog))
+	}
+	if dump.CommitFee != int64(state.CommitFee) {
+		t.Fatalf("expected commit fee of %v, got %v", state.CommitFee,
+			dump.CommitFee)
+	}
+	if dump.ChanReserve != int64(state.ChanReserve) {
+		t.Fatalf("expected channel reserve of %v, got %v",
+			state.ChanReserve, dump.ChanReserve)
+	}
+	if !dump.ChanReserveNegotiated {
+		t.Fatalf("expected channel reserve to be dumped as negotiated")
+	}
+	if dump.CommitFormat != uint8(state.CommitFormat) {
+		t.Fatalf("expected commitment format %v, got %v",
+			state.CommitFormat, dump.CommitFormat)
+	}
+	if !dump.OutgoingFrozen {
+		t.Fatalf("expected channel to be dumped as frozen")
+	}
+	if dump.RevocationStoreHeight != 1000 {
+		t.Fatalf("expected revocation store height of 1000, got %v",
+			dump.RevocationStoreHeight)
+	}
+
+	// Dumping the same state twice should yield identical documents.
+	dumpJSON, err := json.Marshal(dump)
+	if err != nil {
+		t.Fatalf("unable to marshal dump: %v", err)
+	}
+	secondDump, err := state.Dump()
+	if err != nil {
+		t.Fatalf("unable to dump channel: %v", err)
+	}
+	secondJSON, err := json.Marshal(secondDump)
+	if err != nil {
+		t.Fatalf("unable to marshal dump: %v", err)
+	}
+	if !bytes.Equal(dumpJSON, secondJSON) {
+		t.Fatalf("dumps of the same state differ")
+	}
+
+	// Now import the dump into a fresh database, as a test harness would.
+	harnessDB, harnessCleanUp, err := makeTestDB()
+	defer harnessCleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+
+	var decoded ChannelDump
+	if err := json.Unmarshal(dumpJSON, &decoded); err != nil {
+		t.Fatalf("unable to unmarshal dump: %v", err)
+	}
+	if _, err := harnessDB.ImportChannelDump(&decoded, addr); err != nil {
+		t.Fatalf("unable to import dump: %v", err)
+	}
+
+	channels, err := harnessDB.FetchAllChannels()
+	if err != nil {
+		t.Fatalf("unable to fetch channels: %v", err)
+	}
+	if len(channels) != 1 {
+		t.Fatalf("expected 1 channel, got %v", len(channels))
+	}
+	importedDump, err := channels[0].Dump()
+	if err != nil {
+		t.Fatalf("unable to dump imported channel: %v", err)
+	}
+
+	// Other than the revocation store, which can't be recovered from the
+	// dump, the imported channel should match the original.
+	importedDump.RevocationStoreHeight = dump.RevocationStoreHeight
+	importedDump.RevocationStoreBuckets = dump.RevocationStoreBuckets
+	if !reflect.DeepEqual(dump, importedDump) {
+		t.Fatalf("imported channel doesn't match: expected %v, got %v",
+			spew.Sdump(dump), spew.Sdump(importedDump))
+	}
+}
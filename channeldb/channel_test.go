@@ -152,6 +152,10 @@ func createTestChannelState(cdb *DB) (*OpenChannel, error) {
 		Capacity:                   btcutil.Amount(10000),
 		OurBalance:                 btcutil.Amount(3000),
 		TheirBalance:               btcutil.Amount(9000),
+		CommitFee:                  btcutil.Amount(5000),
+		ChanReserve:                btcutil.Amount(1000),
+		ChanReserveNegotiated:      true,
+		CommitFormat:               AnchorCommitment,
 		OurCommitTx:                testTx,
 		OurCommitSig:               bytes.Repeat([]byte{1}, 71),
 		RevocationProducer:         producer,
@@ -252,6 +256,18 @@ func TestOpenChannelPutGetDelete(t *testing.T) {
 	if state.TheirBalance != newState.TheirBalance {
 		t.Fatal("their balance doesn't match")
 	}
+	if state.CommitFee != newState.CommitFee {
+		t.Fatal("commit fee doesn't match")
+	}
+	if state.ChanReserve != newState.ChanReserve {
+		t.Fatal("chan reserve doesn't match")
+	}
+	if state.ChanReserveNegotiated != newState.ChanReserveNegotiated {
+		t.Fatal("chan reserve negotiation doesn't match")
+	}
+	if state.CommitFormat != newState.CommitFormat {
+		t.Fatal("commit format doesn't match")
+	}
 
 	var b1, b2 bytes.Buffer
 	if err := state.OurCommitTx.Serialize(&b1); err != nil {
@@ -395,6 +411,15 @@ func TestOpenChannelPutGetDelete(t *testing.T) {
 	if len(openChans) != 0 {
 		t.Fatalf("all channels not deleted, found %v", len(openChans))
 	}
+
+	// Finally, the channel should now be reported as closed.
+	closed, err := cdb.IsChannelClosed(state.ChanID)
+	if err != nil {
+		t.Fatalf("unable to query closed channels: %v", err)
+	}
+	if !closed {
+		t.Fatalf("channel not reported as closed")
+	}
 }
 
 func TestChannelStateTransition(t *testing.T) {
@@ -449,6 +474,7 @@ func TestChannelStateTransition(t *testing.T) {
 		RemoteBalance: btcutil.Amount(1e8),
 		Htlcs:         htlcs,
 		UpdateNum:     1,
+		CommitFee:     btcutil.Amount(7500),
 	}
 
 	// First update the local node's broadcastable state.
@@ -479,6 +505,10 @@ func TestChannelStateTransition(t *testing.T) {
 		t.Fatalf("remote balances don't match: %v vs %v",
 			updatedChannel[0].TheirBalance, delta.RemoteBalance)
 	}
+	if updatedChannel[0].CommitFee != delta.CommitFee {
+		t.Fatalf("commit fees don't match: %v vs %v",
+			updatedChannel[0].CommitFee, delta.CommitFee)
+	}
 	if updatedChannel[0].NumUpdates != uint64(delta.UpdateNum) {
 		t.Fatalf("update # doesn't match: %v vs %v",
 			updatedChannel[0].NumUpdates, delta.UpdateNum)
@@ -646,3 +676,47 @@ func TestFetchPendingChannels(t *testing.T) {
 			"got %v", 0, len(pendingChannels))
 	}
 }
+
+// TestChannelOutgoingFrozen tests that the flag freezing a channel is
+// persisted, and that channels are read as thawed until frozen.
+func TestChannelOutgoingFrozen(t *testing.T) {
+	cdb, cleanUp, err := makeTestDB()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+	defer cleanUp()
+
+	state, err := createTestChannelState(cdb)
+	if err != nil {
+		t.Fatalf("unable to create channel state: %v", err)
+	}
+	if err := state.FullSync(); err != nil {
+		t.Fatalf("unable to save and serialize channel state: %v", err)
+	}
+
+	fetchFrozen := func() bool {
+		openChannels, err := cdb.FetchOpenChannels(state.IdentityPub)
+		if err != nil {
+			t.Fatalf("unable to fetch open channel: %v", err)
+		}
+		return openChannels[0].OutgoingFrozen
+	}
+
+	if fetchFrozen() {
+		t.Fatalf("new channel shouldn't be frozen")
+	}
+
+	if err := state.SetOutgoingFrozen(true); err != nil {
+		t.Fatalf("unable to freeze channel: %v", err)
+	}
+	if !fetchFrozen() {
+		t.Fatalf("channel should be frozen")
+	}
+
+	if err := state.SetOutgoingFrozen(false); err != nil {
+		t.Fatalf("unable to thaw channel: %v", err)
+	}
+	if fetchFrozen() {
+		t.Fatalf("channel should be thawed")
+	}
+}
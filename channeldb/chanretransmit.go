@@ -0,0 +1,335 @@
+package channeldb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/boltdb/bolt"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/wire"
+)
+
+var (
+	// chanRetransmitBucket is the name of the bucket within the database
+	// that stores the latest messages, such as a RevokeAndAck, we've sent
+	// the remote party of each channel. Within the bucket, each channel has a
+	// sub-bucket keyed by its serialized funding outpoint, holding a
+	// single entry per message type. Each entry is prefixed by a sequence
+	// number, so the messages can be retransmitted in the order they were
+	// originally sent.
+	//
+	// maps: outPoint -> msgType -> seqNum || height || msg
+	chanRetransmitBucket = []byte("chan-retransmit")
+
+	// chanPendingCommitBucket is the name of the bucket within the
+	// database that stores the latest commitment we've signed for the
+	// remote party of each channel, along with the updates we sent which
+	// it covers.
+	//
+	// maps: outPoint -> height || numUpdates || (paymentHash || msg)* ||
+	//       commitSig
+	chanPendingCommitBucket = []byte("chan-pending-commit")
+
+	// chanRetransmitNet is the network written within the header of each
+	// stored message. As the messages are only read back by ourselves, the
+	// value is irrelevant, but it must be consistent between reads and
+	// writes.
+	chanRetransmitNet = wire.MainNet
+)
+
+// LastChanMsg is the latest message of a particular type we've sent the
+// remote party of a channel. It's retained so it may be retransmitted if it
+// didn't reach the remote party before we were disconnected.
+type LastChanMsg struct {
+	// Height is the commitment height the message advanced the channel
+	// to: the height of the remote party's signed commitment for a
+	// CommitSig, and our own height for a RevokeAndAck.
+	Height uint64
+
+	// Msg is the message itself.
+	Msg lnwire.Message
+}
+
+// PutLastChanMsg records the passed message as the latest of its type sent to
+// the remote party of the channel identified by the passed funding outpoint,
+// replacing the prior message of the same type. The message must be recorded
+// before it's sent, so it's available for retransmission even if we crash
+// before the remote party receives it.
+func (d *DB) PutLastChanMsg(chanPoint *wire.OutPoint, height uint64,
+	msg lnwire.Message) error {
+
+	var chanKey bytes.Buffer
+	if err := writeOutpoint(&chanKey, chanPoint); err != nil {
+		return err
+	}
+
+	var msgKey [4]byte
+	byteOrder.PutUint32(msgKey[:], msg.Command())
+
+	return d.Batch(func(tx *bolt.Tx) error {
+		retransmit, err := tx.CreateBucketIfNotExists(
+			chanRetransmitBucket)
+		if err != nil {
+			return err
+		}
+		msgs, err := retransmit.CreateBucketIfNotExists(chanKey.Bytes())
+		if err != nil {
+			return err
+		}
+
+		seqNum, err := msgs.NextSequence()
+		if err != nil {
+			return err
+		}
+
+		var b bytes.Buffer
+		var scratch [8]byte
+		binary.BigEndian.PutUint64(scratch[:], seqNum)
+		b.Write(scratch[:])
+		byteOrder.PutUint64(scratch[:], height)
+		b.Write(scratch[:])
+		_, err = lnwire.WriteMessage(&b, msg, 0, chanRetransmitNet)
+		if err != nil {
+			return err
+		}
+
+		return msgs.Put(msgKey[:], b.Bytes())
+	})
+}
+
+// FetchLastChanMsgs returns the latest message of each type sent to the
+// remote party of the channel identified by the passed funding outpoint, in
+// the order they were sent.
+func (d *DB) FetchLastChanMsgs(chanPoint *wire.OutPoint) ([]*LastChanMsg,
+	error) {
+
+	var chanKey bytes.Buffer
+	if err := writeOutpoint(&chanKey, chanPoint); err != nil {
+		return nil, err
+	}
+
+	var (
+		lastMsgs []*LastChanMsg
+		seqNums  []uint64
+	)
+	err := d.View(func(tx *bolt.Tx) error {
+		retransmit := tx.Bucket(chanRetransmitBucket)
+		if retransmit == nil {
+			return nil
+		}
+		msgs := retransmit.Bucket(chanKey.Bytes())
+		if msgs == nil {
+			return nil
+		}
+
+		return msgs.ForEach(func(k, v []byte) error {
+			r := bytes.NewReader(v)
+
+			var scratch [8]byte
+			if _, err := io.ReadFull(r, scratch[:]); err != nil {
+				return err
+			}
+			seqNum := binary.BigEndian.Uint64(scratch[:])
+
+			if _, err := io.ReadFull(r, scratch[:]); err != nil {
+				return err
+			}
+			height := byteOrder.Uint64(scratch[:])
+
+			_, msg, _, err := lnwire.ReadMessage(r, 0,
+				chanRetransmitNet)
+			if err != nil {
+				return err
+			}
+
+			// Insert the message according to its sequence
+			// number, so the messages are ordered as they were
+			// sent.
+			i := len(seqNums)
+			for i > 0 && seqNums[i-1] > seqNum {
+				i--
+			}
+			seqNums = append(seqNums, 0)
+			copy(seqNums[i+1:], seqNums[i:])
+			seqNums[i] = seqNum
+
+			lastMsgs = append(lastMsgs, nil)
+			copy(lastMsgs[i+1:], lastMsgs[i:])
+			lastMsgs[i] = &LastChanMsg{
+				Height: height,
+				Msg:    msg,
+			}
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return lastMsgs, nil
+}
+
+// PendingUpdate is an update we've sent the remote party of a channel which is
+// covered by the latest commitment we've signed for it.
+type PendingUpdate struct {
+	// PaymentHash is the payment hash of the HTLC the update adds,
+	// settles or fails, if any. HTLCs are re-indexed when a channel is
+	// reloaded, so it identifies the HTLC once the index within the
+	// message no longer does.
+	PaymentHash [32]byte
+
+	// Msg is the update itself.
+	Msg lnwire.Message
+}
+
+// PendingCommit is the latest commitment we've signed for the remote party of
+// a channel, along with the updates we sent since our prior signature, which
+// it covers. It's retained so the updates and the signature may be replayed
+// should the signature not reach the remote party before we're disconnected,
+// as both parties drop the updates the signature covers once reloading the
+// channel.
+type PendingCommit struct {
+	// Height is the height of the remote party's commitment we've signed.
+	Height uint64
+
+	// Updates are the updates the commitment covers, in the order they
+	// were sent.
+	Updates []*PendingUpdate
+
+	// CommitSig is our signature for the commitment.
+	CommitSig *lnwire.CommitSig
+}
+
+// PutPendingCommit records the passed commitment as the latest we've signed
+// for the remote party of the channel identified by the passed funding
+// outpoint, replacing the prior commitment. It must be recorded before the
+// signature is sent.
+func (d *DB) PutPendingCommit(chanPoint *wire.OutPoint,
+	commit *PendingCommit) error {
+
+	var chanKey bytes.Buffer
+	if err := writeOutpoint(&chanKey, chanPoint); err != nil {
+		return err
+	}
+
+	var b bytes.Buffer
+	var scratch [8]byte
+	byteOrder.PutUint64(scratch[:], commit.Height)
+	b.Write(scratch[:])
+	byteOrder.PutUint16(scratch[:2], uint16(len(commit.Updates)))
+	b.Write(scratch[:2])
+	for _, update := range commit.Updates {
+		b.Write(update.PaymentHash[:])
+		_, err := lnwire.WriteMessage(&b, update.Msg, 0,
+			chanRetransmitNet)
+		if err != nil {
+			return err
+		}
+	}
+	_, err := lnwire.WriteMessage(&b, commit.CommitSig, 0,
+		chanRetransmitNet)
+	if err != nil {
+		return err
+	}
+
+	return d.Batch(func(tx *bolt.Tx) error {
+		commits, err := tx.CreateBucketIfNotExists(
+			chanPendingCommitBucket)
+		if err != nil {
+			return err
+		}
+
+		return commits.Put(chanKey.Bytes(), b.Bytes())
+	})
+}
+
+// FetchPendingCommit returns the latest commitment we've signed for the remote
+// party of the channel identified by the passed funding outpoint, or nil if
+// none has been recorded.
+func (d *DB) FetchPendingCommit(chanPoint *wire.OutPoint) (*PendingCommit,
+	error) {
+
+	var chanKey bytes.Buffer
+	if err := writeOutpoint(&chanKey, chanPoint); err != nil {
+		return nil, err
+	}
+
+	var commit *PendingCommit
+	err := d.View(func(tx *bolt.Tx) error {
+		commits := tx.Bucket(chanPendingCommitBucket)
+		if commits == nil {
+			return nil
+		}
+		v := commits.Get(chanKey.Bytes())
+		if v == nil {
+			return nil
+		}
+		r := bytes.NewReader(v)
+
+		commit = &PendingCommit{}
+		var scratch [8]byte
+		if _, err := io.ReadFull(r, scratch[:]); err != nil {
+			return err
+		}
+		commit.Height = byteOrder.Uint64(scratch[:])
+
+		if _, err := io.ReadFull(r, scratch[:2]); err != nil {
+			return err
+		}
+		numUpdates := byteOrder.Uint16(scratch[:2])
+
+		for i := uint16(0); i < numUpdates; i++ {
+			update := &PendingUpdate{}
+			_, err := io.ReadFull(r, update.PaymentHash[:])
+			if err != nil {
+				return err
+			}
+			_, update.Msg, _, err = lnwire.ReadMessage(r, 0,
+				chanRetransmitNet)
+			if err != nil {
+				return err
+			}
+
+			commit.Updates = append(commit.Updates, update)
+		}
+
+		_, msg, _, err := lnwire.ReadMessage(r, 0, chanRetransmitNet)
+		if err != nil {
+			return err
+		}
+		commitSig, ok := msg.(*lnwire.CommitSig)
+		if !ok {
+			return fmt.Errorf("expected CommitSig, got %T", msg)
+		}
+		commit.CommitSig = commitSig
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return commit, nil
+}
+
+// wipeLastChanMsgs deletes the messages, along with the pending commitment,
+// retained for retransmission to the remote party of the channel identified
+// by the passed serialized funding outpoint.
+func wipeLastChanMsgs(tx *bolt.Tx, outPointBytes []byte) error {
+	commits := tx.Bucket(chanPendingCommitBucket)
+	if commits != nil {
+		if err := commits.Delete(outPointBytes); err != nil {
+			return err
+		}
+	}
+
+	retransmit := tx.Bucket(chanRetransmitBucket)
+	if retransmit == nil || retransmit.Bucket(outPointBytes) == nil {
+		return nil
+	}
+
+	return retransmit.DeleteBucket(outPointBytes)
+}
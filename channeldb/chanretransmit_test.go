@@ -0,0 +1,191 @@
+package channeldb
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// TestLastChanMsgs tests that the latest message of each type sent to the
+// remote party of a channel replaces its predecessor, that the messages are
+// returned in the order they were sent, and that they're wiped once the
+// channel is closed.
+func TestLastChanMsgs(t *testing.T) {
+	cdb, cleanUp, err := makeTestDB()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+	defer cleanUp()
+
+	state, err := createTestChannelState(cdb)
+	if err != nil {
+		t.Fatalf("unable to create channel state: %v", err)
+	}
+	if err := state.FullSync(); err != nil {
+		t.Fatalf("unable to save and serialize channel state: %v", err)
+	}
+
+	lastMsgs, err := cdb.FetchLastChanMsgs(state.ChanID)
+	if err != nil {
+		t.Fatalf("unable to fetch last messages: %v", err)
+	}
+	if len(lastMsgs) != 0 {
+		t.Fatalf("expected no messages, got %v", len(lastMsgs))
+	}
+
+	sig, err := privKey.Sign(key[:])
+	if err != nil {
+		t.Fatalf("unable to sign: %v", err)
+	}
+	staleCommitSig := &lnwire.CommitSig{
+		ChannelPoint: *state.ChanID,
+		CommitSig:    sig,
+	}
+	revocation := &lnwire.RevokeAndAck{
+		ChannelPoint:       *state.ChanID,
+		Revocation:         rev,
+		NextRevocationKey:  pubKey,
+		NextRevocationHash: key,
+	}
+	commitSig := &lnwire.CommitSig{
+		ChannelPoint: *state.ChanID,
+		CommitSig:    sig,
+	}
+
+	// The latest CommitSig replaces the stale one, so it's now sent after
+	// the revocation.
+	expected := []*LastChanMsg{
+		{Height: 4, Msg: revocation},
+		{Height: 6, Msg: commitSig},
+	}
+	err = cdb.PutLastChanMsg(state.ChanID, 5, staleCommitSig)
+	if err != nil {
+		t.Fatalf("unable to put last message: %v", err)
+	}
+	for _, lastMsg := range expected {
+		err := cdb.PutLastChanMsg(state.ChanID, lastMsg.Height,
+			lastMsg.Msg)
+		if err != nil {
+			t.Fatalf("unable to put last message: %v", err)
+		}
+	}
+
+	lastMsgs, err = cdb.FetchLastChanMsgs(state.ChanID)
+	if err != nil {
+		t.Fatalf("unable to fetch last messages: %v", err)
+	}
+	if !reflect.DeepEqual(lastMsgs, expected) {
+		t.Fatalf("expected %v, got %v", spew.Sdump(expected),
+			spew.Sdump(lastMsgs))
+	}
+
+	if err := state.CloseChannel(); err != nil {
+		t.Fatalf("unable to close channel: %v", err)
+	}
+	lastMsgs, err = cdb.FetchLastChanMsgs(state.ChanID)
+	if err != nil {
+		t.Fatalf("unable to fetch last messages: %v", err)
+	}
+	if len(lastMsgs) != 0 {
+		t.Fatalf("expected no messages after close, got %v",
+			len(lastMsgs))
+	}
+}
+
+// TestPendingCommit tests that the latest commitment signed for the remote
+// party of a channel, along with the updates it covers, replaces its
+// predecessor, and is wiped once the channel is closed.
+func TestPendingCommit(t *testing.T) {
+	cdb, cleanUp, err := makeTestDB()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+	defer cleanUp()
+
+	state, err := createTestChannelState(cdb)
+	if err != nil {
+		t.Fatalf("unable to create channel state: %v", err)
+	}
+	if err := state.FullSync(); err != nil {
+		t.Fatalf("unable to save and serialize channel state: %v", err)
+	}
+
+	commit, err := cdb.FetchPendingCommit(state.ChanID)
+	if err != nil {
+		t.Fatalf("unable to fetch pending commitment: %v", err)
+	}
+	if commit != nil {
+		t.Fatalf("expected no pending commitment, got %v",
+			spew.Sdump(commit))
+	}
+
+	sig, err := privKey.Sign(key[:])
+	if err != nil {
+		t.Fatalf("unable to sign: %v", err)
+	}
+	commitSig := &lnwire.CommitSig{
+		ChannelPoint: *state.ChanID,
+		CommitSig:    sig,
+	}
+
+	stale := &PendingCommit{
+		Height:    5,
+		CommitSig: commitSig,
+	}
+	expected := &PendingCommit{
+		Height: 6,
+		Updates: []*PendingUpdate{
+			{
+				PaymentHash: key,
+				Msg: &lnwire.UpdateFufillHTLC{
+					ChannelPoint:    *state.ChanID,
+					ID:              2,
+					PaymentPreimage: rev,
+				},
+			},
+			{
+				PaymentHash: rev,
+				Msg: &lnwire.UpdateFailHTLC{
+					ChannelPoint: *state.ChanID,
+					ID:           3,
+					Reason:       []byte{byte(lnwire.UnknownPaymentHash)},
+				},
+			},
+			{
+				Msg: &lnwire.UpdateFee{
+					ChannelPoint: *state.ChanID,
+					Fee:          5000,
+				},
+			},
+		},
+		CommitSig: commitSig,
+	}
+	for _, c := range []*PendingCommit{stale, expected} {
+		if err := cdb.PutPendingCommit(state.ChanID, c); err != nil {
+			t.Fatalf("unable to put pending commitment: %v", err)
+		}
+	}
+
+	commit, err = cdb.FetchPendingCommit(state.ChanID)
+	if err != nil {
+		t.Fatalf("unable to fetch pending commitment: %v", err)
+	}
+	if !reflect.DeepEqual(commit, expected) {
+		t.Fatalf("expected %v, got %v", spew.Sdump(expected),
+			spew.Sdump(commit))
+	}
+
+	if err := state.CloseChannel(); err != nil {
+		t.Fatalf("unable to close channel: %v", err)
+	}
+	commit, err = cdb.FetchPendingCommit(state.ChanID)
+	if err != nil {
+		t.Fatalf("unable to fetch pending commitment: %v", err)
+	}
+	if commit != nil {
+		t.Fatalf("expected no pending commitment after close, got %v",
+			spew.Sdump(commit))
+	}
+}
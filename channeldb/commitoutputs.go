@@ -0,0 +1,275 @@
+package channeldb
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/boltdb/bolt"
+	"github.com/roasbeef/btcd/wire"
+)
+
+var (
+	// commitOutputIndexBucket is the name of the bucket within the
+	// database that indexes the output scripts paying to us within the
+	// commitment transactions of each open channel. This allows the
+	// ownership of any transaction appearing on-chain to be determined by
+	// looking up its outputs, rather than re-deriving the scripts of each
+	// channel.
+	commitOutputIndexBucket = []byte("commit-output-index")
+
+	// commitOutputScriptsKey is the sub-bucket of the index mapping each
+	// output script to the channel and state it belongs to.
+	//
+	// maps: pkScript -> outPoint || outputType || stateNum
+	commitOutputScriptsKey = []byte("scripts")
+
+	// commitOutputChansKey is the sub-bucket of the index holding a
+	// bucket for each channel, keyed by its funding outpoint, which lists
+	// the channel's indexed scripts so they can be removed once the
+	// channel is closed.
+	//
+	// maps: outPoint -> pkScript -> nil
+	//                -> windowEnd
+	commitOutputChansKey = []byte("chans")
+
+	// commitOutputWindowKey is the key within a channel's bucket storing
+	// the state number up to which the channel's scripts are indexed. As
+	// it's shorter than any output script, it can't collide with one.
+	commitOutputWindowKey = []byte("window-end")
+)
+
+// CommitOutputType denotes which of our outputs within a channel's commitment
+// transactions an indexed script belongs to.
+type CommitOutputType uint8
+
+const (
+	// CommitOutputRemote is the output paying to us immediately within
+	// the remote party's commitment transaction. As it pays to our static
+	// commitment key, its script is the same for every state.
+	CommitOutputRemote CommitOutputType = 0
+
+	// CommitOutputLocalDelayed is the time-locked output paying to us
+	// within our own commitment transaction. Its script embeds the
+	// revocation key of the state, so it differs for each state.
+	CommitOutputLocalDelayed CommitOutputType = 1
+)
+
+// String returns a human readable name for the output type.
+func (t CommitOutputType) String() string {
+	switch t {
+	case CommitOutputRemote:
+		return "remote"
+	case CommitOutputLocalDelayed:
+		return "local_delayed"
+	default:
+		return "unknown"
+	}
+}
+
+// CommitOutput is an output script paying to us within one of the commitment
+// transactions of a channel.
+type CommitOutput struct {
+	// ChanPoint is the funding outpoint of the channel.
+	ChanPoint wire.OutPoint
+
+	// Type denotes which of our outputs the script belongs to.
+	Type CommitOutputType
+
+	// StateNum is the state of the commitment transaction containing the
+	// output. It's zero for CommitOutputRemote, as its script is shared
+	// by all states.
+	StateNum uint64
+
+	// PkScript is the output script.
+	PkScript []byte
+}
+
+// PutCommitOutputs adds the passed output scripts of the channel identified
+// by the passed funding outpoint to the index, and records that the
+// channel's scripts are now indexed up to, but excluding, the passed state
+// number.
+func (d *DB) PutCommitOutputs(chanPoint *wire.OutPoint, windowEnd uint64,
+	outputs []*CommitOutput) error {
+
+	var chanKey bytes.Buffer
+	if err := writeOutpoint(&chanKey, chanPoint); err != nil {
+		return err
+	}
+
+	return d.Update(func(tx *bolt.Tx) error {
+		index, err := tx.CreateBucketIfNotExists(commitOutputIndexBucket)
+		if err != nil {
+			return err
+		}
+		scripts, err := index.CreateBucketIfNotExists(
+			commitOutputScriptsKey)
+		if err != nil {
+			return err
+		}
+		chans, err := index.CreateBucketIfNotExists(commitOutputChansKey)
+		if err != nil {
+			return err
+		}
+		chanScripts, err := chans.CreateBucketIfNotExists(
+			chanKey.Bytes())
+		if err != nil {
+			return err
+		}
+
+		for _, output := range outputs {
+			var b bytes.Buffer
+			if err := writeCommitOutput(&b, output); err != nil {
+				return err
+			}
+
+			if err := scripts.Put(output.PkScript, b.Bytes()); err != nil {
+				return err
+			}
+			if err := chanScripts.Put(output.PkScript, []byte{}); err != nil {
+				return err
+			}
+		}
+
+		var scratch [8]byte
+		byteOrder.PutUint64(scratch[:], windowEnd)
+		return chanScripts.Put(commitOutputWindowKey, scratch[:])
+	})
+}
+
+// FetchCommitOutputWindow returns the state number up to which the output
+// scripts of the channel identified by the passed funding outpoint are
+// indexed. Zero is returned if none of the channel's scripts are indexed.
+func (d *DB) FetchCommitOutputWindow(chanPoint *wire.OutPoint) (uint64, error) {
+	var chanKey bytes.Buffer
+	if err := writeOutpoint(&chanKey, chanPoint); err != nil {
+		return 0, err
+	}
+
+	var windowEnd uint64
+	err := d.View(func(tx *bolt.Tx) error {
+		chanScripts := fetchCommitOutputChan(tx, chanKey.Bytes())
+		if chanScripts == nil {
+			return nil
+		}
+
+		if v := chanScripts.Get(commitOutputWindowKey); v != nil {
+			windowEnd = byteOrder.Uint64(v)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return windowEnd, nil
+}
+
+// LookupCommitOutputs looks up each output of the passed transaction within
+// the index, returning the outputs paying to us keyed by their index within
+// the transaction. An empty map is returned if none of the outputs are ours.
+func (d *DB) LookupCommitOutputs(tx *wire.MsgTx) (map[uint32]*CommitOutput,
+	error) {
+
+	owned := make(map[uint32]*CommitOutput)
+	err := d.View(func(boltTx *bolt.Tx) error {
+		index := boltTx.Bucket(commitOutputIndexBucket)
+		if index == nil {
+			return nil
+		}
+		scripts := index.Bucket(commitOutputScriptsKey)
+		if scripts == nil {
+			return nil
+		}
+
+		for i, txOut := range tx.TxOut {
+			v := scripts.Get(txOut.PkScript)
+			if v == nil {
+				continue
+			}
+
+			output, err := readCommitOutput(bytes.NewReader(v))
+			if err != nil {
+				return err
+			}
+			output.PkScript = txOut.PkScript
+
+			owned[uint32(i)] = output
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return owned, nil
+}
+
+// fetchCommitOutputChan returns the bucket listing the indexed scripts of the
+// channel with the passed serialized funding outpoint, or nil if none of the
+// channel's scripts are indexed.
+func fetchCommitOutputChan(tx *bolt.Tx, outPointBytes []byte) *bolt.Bucket {
+	index := tx.Bucket(commitOutputIndexBucket)
+	if index == nil {
+		return nil
+	}
+	chans := index.Bucket(commitOutputChansKey)
+	if chans == nil {
+		return nil
+	}
+
+	return chans.Bucket(outPointBytes)
+}
+
+// wipeCommitOutputs removes the output scripts of the channel with the passed
+// serialized funding outpoint from the index.
+func wipeCommitOutputs(tx *bolt.Tx, outPointBytes []byte) error {
+	chanScripts := fetchCommitOutputChan(tx, outPointBytes)
+	if chanScripts == nil {
+		return nil
+	}
+
+	index := tx.Bucket(commitOutputIndexBucket)
+	scripts := index.Bucket(commitOutputScriptsKey)
+	if scripts != nil {
+		err := chanScripts.ForEach(func(k, _ []byte) error {
+			if bytes.Equal(k, commitOutputWindowKey) {
+				return nil
+			}
+			return scripts.Delete(k)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return index.Bucket(commitOutputChansKey).DeleteBucket(outPointBytes)
+}
+
+func writeCommitOutput(w io.Writer, output *CommitOutput) error {
+	if err := writeOutpoint(w, &output.ChanPoint); err != nil {
+		return err
+	}
+
+	var scratch [9]byte
+	scratch[0] = byte(output.Type)
+	byteOrder.PutUint64(scratch[1:], output.StateNum)
+	_, err := w.Write(scratch[:])
+	return err
+}
+
+func readCommitOutput(r io.Reader) (*CommitOutput, error) {
+	output := &CommitOutput{}
+	if err := readOutpoint(r, &output.ChanPoint); err != nil {
+		return nil, err
+	}
+
+	var scratch [9]byte
+	if _, err := io.ReadFull(r, scratch[:]); err != nil {
+		return nil, err
+	}
+	output.Type = CommitOutputType(scratch[0])
+	output.StateNum = byteOrder.Uint64(scratch[1:])
+
+	return output, nil
+}
@@ -0,0 +1,102 @@
+package channeldb
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// TestCommitOutputIndex tests that the outputs of a transaction are
+// classified according to the scripts indexed for a channel, and that the
+// channel's scripts are removed from the index once the channel is closed.
+func TestCommitOutputIndex(t *testing.T) {
+	cdb, cleanUp, err := makeTestDB()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+	defer cleanUp()
+
+	state, err := createTestChannelState(cdb)
+	if err != nil {
+		t.Fatalf("unable to create channel state: %v", err)
+	}
+	if err := state.FullSync(); err != nil {
+		t.Fatalf("unable to save and serialize channel state: %v", err)
+	}
+
+	windowEnd, err := cdb.FetchCommitOutputWindow(state.ChanID)
+	if err != nil {
+		t.Fatalf("unable to fetch window: %v", err)
+	}
+	if windowEnd != 0 {
+		t.Fatalf("expected empty window, got %v", windowEnd)
+	}
+
+	remote := &CommitOutput{
+		ChanPoint: *state.ChanID,
+		Type:      CommitOutputRemote,
+		PkScript:  []byte{0, 20, 1, 2, 3},
+	}
+	delayed := &CommitOutput{
+		ChanPoint: *state.ChanID,
+		Type:      CommitOutputLocalDelayed,
+		StateNum:  7,
+		PkScript:  []byte{0, 32, 4, 5, 6},
+	}
+	err = cdb.PutCommitOutputs(state.ChanID, 5, []*CommitOutput{remote})
+	if err != nil {
+		t.Fatalf("unable to put commit outputs: %v", err)
+	}
+	err = cdb.PutCommitOutputs(state.ChanID, 10, []*CommitOutput{delayed})
+	if err != nil {
+		t.Fatalf("unable to put commit outputs: %v", err)
+	}
+
+	windowEnd, err = cdb.FetchCommitOutputWindow(state.ChanID)
+	if err != nil {
+		t.Fatalf("unable to fetch window: %v", err)
+	}
+	if windowEnd != 10 {
+		t.Fatalf("expected window end of 10, got %v", windowEnd)
+	}
+
+	tx := wire.NewMsgTx(2)
+	tx.AddTxOut(wire.NewTxOut(1000, delayed.PkScript))
+	tx.AddTxOut(wire.NewTxOut(2000, []byte{0, 20, 7, 8, 9}))
+	tx.AddTxOut(wire.NewTxOut(3000, remote.PkScript))
+
+	expected := map[uint32]*CommitOutput{
+		0: delayed,
+		2: remote,
+	}
+	owned, err := cdb.LookupCommitOutputs(tx)
+	if err != nil {
+		t.Fatalf("unable to look up outputs: %v", err)
+	}
+	if !reflect.DeepEqual(owned, expected) {
+		t.Fatalf("expected %v, got %v", spew.Sdump(expected),
+			spew.Sdump(owned))
+	}
+
+	if err := state.CloseChannel(); err != nil {
+		t.Fatalf("unable to close channel: %v", err)
+	}
+	owned, err = cdb.LookupCommitOutputs(tx)
+	if err != nil {
+		t.Fatalf("unable to look up outputs: %v", err)
+	}
+	if len(owned) != 0 {
+		t.Fatalf("expected no owned outputs after close, got %v",
+			len(owned))
+	}
+	windowEnd, err = cdb.FetchCommitOutputWindow(state.ChanID)
+	if err != nil {
+		t.Fatalf("unable to fetch window: %v", err)
+	}
+	if windowEnd != 0 {
+		t.Fatalf("expected empty window after close, got %v",
+			windowEnd)
+	}
+}
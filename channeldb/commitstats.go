@@ -0,0 +1,144 @@
+package channeldb
+
+import (
+	"fmt"
+	"path"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/boltdb/bolt"
+)
+
+// CommitStat is the latency of the write transactions made by a single call
+// site within the database, as seen by the caller. This includes the time
+// spent waiting for the database's single writer lock, and for write
+// transactions made via Batch, the time spent waiting for the batch to fill.
+type CommitStat struct {
+	// CallSite is the function, and line within it, which made the write
+	// transactions.
+	CallSite string
+
+	// Batched is true if the call site's transactions are coalesced with
+	// those of other call sites into a single commit.
+	Batched bool
+
+	// NumCommits is the number of write transactions made.
+	NumCommits uint64
+
+	// TotalLatency and MaxLatency are the total and the longest latency
+	// of the write transactions.
+	TotalLatency time.Duration
+	MaxLatency   time.Duration
+}
+
+// commitStats accumulates the latency of the database's write transactions
+// by call site.
+type commitStats struct {
+	sync.Mutex
+
+	// sites maps the program counter of each call site to its stats.
+	sites map[uintptr]*CommitStat
+}
+
+// record adds a write transaction of the passed latency made by the call site
+// at the passed program counter.
+func (c *commitStats) record(pc uintptr, batched bool,
+	latency time.Duration) {
+
+	c.Lock()
+	defer c.Unlock()
+
+	if c.sites == nil {
+		c.sites = make(map[uintptr]*CommitStat)
+	}
+
+	stat, ok := c.sites[pc]
+	if !ok {
+		stat = &CommitStat{
+			CallSite: callSiteName(pc),
+			Batched:  batched,
+		}
+		c.sites[pc] = stat
+	}
+
+	stat.NumCommits++
+	stat.TotalLatency += latency
+	if latency > stat.MaxLatency {
+		stat.MaxLatency = latency
+	}
+}
+
+// callSiteName returns the name of the function, and the line within it, at
+// the passed program counter, without the function's package path.
+func callSiteName(pc uintptr) string {
+	fn := runtime.FuncForPC(pc)
+	if fn == nil {
+		return fmt.Sprintf("%#x", pc)
+	}
+
+	_, line := fn.FileLine(pc)
+	return fmt.Sprintf("%v:%v", path.Base(fn.Name()), line)
+}
+
+// Update executes the passed function within a read-write transaction, which
+// is committed if the function returns nil, recording the latency of the
+// transaction against the calling function.
+//
+// NOTE: This shadows the Update method of the embedded bolt.DB.
+func (d *DB) Update(fn func(*bolt.Tx) error) error {
+	pc, _, _, _ := runtime.Caller(1)
+
+	start := time.Now()
+	err := d.DB.Update(fn)
+	d.commitStats.record(pc, false, time.Since(start))
+
+	return err
+}
+
+// Batch executes the passed function within a read-write transaction shared
+// with the functions passed by concurrent callers, so that a single commit,
+// and its fsync, is made for all of them. As the function may be executed
+// more than once should another function within the batch fail, it must be
+// idempotent. Batch should only be used for writes made concurrently by
+// several goroutines, as each call waits for the batch to fill or time out
+// before committing. The latency of the transaction is recorded against the
+// calling function.
+//
+// NOTE: This shadows the Batch method of the embedded bolt.DB.
+func (d *DB) Batch(fn func(*bolt.Tx) error) error {
+	pc, _, _, _ := runtime.Caller(1)
+
+	start := time.Now()
+	err := d.DB.Batch(fn)
+	d.commitStats.record(pc, true, time.Since(start))
+
+	return err
+}
+
+// CommitStats returns the latency of the database's write transactions by
+// call site, ordered by their total latency, highest first. The stats cover
+// the transactions made since the database was opened.
+func (d *DB) CommitStats() []CommitStat {
+	d.commitStats.Lock()
+	stats := make([]CommitStat, 0, len(d.commitStats.sites))
+	for _, stat := range d.commitStats.sites {
+		stats = append(stats, *stat)
+	}
+	d.commitStats.Unlock()
+
+	sort.Sort(commitStatsByLatency(stats))
+
+	return stats
+}
+
+// commitStatsByLatency implements sort.Interface to order commit stats from
+// the highest total latency to the lowest.
+type commitStatsByLatency []CommitStat
+
+func (c commitStatsByLatency) Len() int      { return len(c) }
+func (c commitStatsByLatency) Swap(i, j int) { c[i], c[j] = c[j], c[i] }
+func (c commitStatsByLatency) Less(i, j int) bool {
+	return c[i].TotalLatency > c[j].TotalLatency
+}
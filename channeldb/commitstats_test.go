@@ -0,0 +1,92 @@
+package channeldb
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/boltdb/bolt"
+)
+
+// TestCommitStats tests that the latency of write transactions is recorded
+// against the call site which made them, whether batched or not.
+func TestCommitStats(t *testing.T) {
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+
+	bucket := []byte("commit-stats")
+	put := func(tx *bolt.Tx) error {
+		b, err := tx.CreateBucketIfNotExists(bucket)
+		if err != nil {
+			return err
+		}
+		return b.Put([]byte("key"), []byte("value"))
+	}
+
+	// Make several plain write transactions from a single call site,
+	// along with several concurrent batched ones from another.
+	const numCommits = 5
+	for i := 0; i < numCommits; i++ {
+		if err := db.Update(put); err != nil {
+			t.Fatalf("unable to update db: %v", err)
+		}
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, numCommits)
+	for i := 0; i < numCommits; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- db.Batch(put)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("unable to batch update db: %v", err)
+		}
+	}
+
+	var updateStat, batchStat *CommitStat
+	stats := db.CommitStats()
+	for i := range stats {
+		stat := &stats[i]
+		if !strings.Contains(stat.CallSite, "TestCommitStats") {
+			continue
+		}
+		if stat.Batched {
+			batchStat = stat
+		} else {
+			updateStat = stat
+		}
+	}
+
+	for _, stat := range []*CommitStat{updateStat, batchStat} {
+		if stat == nil {
+			t.Fatalf("call site missing from stats: %v", stats)
+		}
+		if stat.NumCommits != numCommits {
+			t.Fatalf("expected %v commits from %v, got %v",
+				numCommits, stat.CallSite, stat.NumCommits)
+		}
+		if stat.TotalLatency < stat.MaxLatency ||
+			stat.MaxLatency == 0 {
+
+			t.Fatalf("invalid latency for %v: total=%v, max=%v",
+				stat.CallSite, stat.TotalLatency,
+				stat.MaxLatency)
+		}
+	}
+
+	// The stats are ordered by total latency, highest first.
+	for i := 1; i < len(stats); i++ {
+		if stats[i].TotalLatency > stats[i-1].TotalLatency {
+			t.Fatalf("stats aren't ordered by latency: %v", stats)
+		}
+	}
+}
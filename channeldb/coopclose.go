@@ -0,0 +1,197 @@
+package channeldb
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/boltdb/bolt"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+var (
+	// coopCloseKey stores each version of the cooperative closure
+	// transaction of an active channel we've signed, in the order they
+	// were signed. The key is stored within the node's channel bucket,
+	// and is suffixed by the channel's ID.
+	coopCloseKey = []byte("cck")
+)
+
+// CoopCloseVersion is a version of the cooperative closure transaction of a
+// channel which we've signed. As each party pays the fee of its own version
+// in full, and may replace it with one paying a higher fee, several versions
+// may be valid at once until one of them confirms.
+type CoopCloseVersion struct {
+	// Txid is the txid of the closure transaction.
+	Txid chainhash.Hash
+
+	// Fee is the fee paid by the closure transaction.
+	Fee btcutil.Amount
+
+	// LocalPays denotes that the version is our own, its fee being paid
+	// from our output. Otherwise, it's the remote party's version.
+	LocalPays bool
+}
+
+// AddCoopCloseVersion records the passed version of the channel's cooperative
+// closure transaction, which we've just signed, after those signed so far.
+func (c *OpenChannel) AddCoopCloseVersion(version *CoopCloseVersion) error {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.Db.Update(func(tx *bolt.Tx) error {
+		nodeChanBucket, err := c.nodeChanBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		key, err := makeCoopCloseKey(c.ChanID)
+		if err != nil {
+			return err
+		}
+
+		var versions []*CoopCloseVersion
+		if versionBytes := nodeChanBucket.Get(key); versionBytes != nil {
+			versions, err = deserializeCoopCloseVersions(
+				bytes.NewReader(versionBytes))
+			if err != nil {
+				return err
+			}
+		}
+		versions = append(versions, version)
+
+		var b bytes.Buffer
+		if err := serializeCoopCloseVersions(&b, versions); err != nil {
+			return err
+		}
+
+		return nodeChanBucket.Put(key, b.Bytes())
+	})
+}
+
+// FetchCoopCloseVersions returns each version of the channel's cooperative
+// closure transaction we've signed, in the order they were signed. If the
+// channel isn't being cooperatively closed, then no versions are returned.
+func (c *OpenChannel) FetchCoopCloseVersions() ([]*CoopCloseVersion, error) {
+	c.RLock()
+	defer c.RUnlock()
+
+	var versions []*CoopCloseVersion
+	err := c.Db.View(func(tx *bolt.Tx) error {
+		chanBucket := tx.Bucket(openChannelBucket)
+		if chanBucket == nil {
+			return nil
+		}
+		nodePub := c.IdentityPub.SerializeCompressed()
+		nodeChanBucket := chanBucket.Bucket(nodePub)
+		if nodeChanBucket == nil {
+			return nil
+		}
+
+		key, err := makeCoopCloseKey(c.ChanID)
+		if err != nil {
+			return err
+		}
+		versionBytes := nodeChanBucket.Get(key)
+		if versionBytes == nil {
+			return nil
+		}
+
+		versions, err = deserializeCoopCloseVersions(
+			bytes.NewReader(versionBytes))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return versions, nil
+}
+
+// makeCoopCloseKey returns the key of the cooperative closure transactions of
+// the channel with the passed ID.
+func makeCoopCloseKey(chanID *wire.OutPoint) ([]byte, error) {
+	var b bytes.Buffer
+	if err := writeOutpoint(&b, chanID); err != nil {
+		return nil, err
+	}
+
+	key := make([]byte, len(coopCloseKey)+b.Len())
+	copy(key[:len(coopCloseKey)], coopCloseKey)
+	copy(key[len(coopCloseKey):], b.Bytes())
+
+	return key, nil
+}
+
+func deleteChanCoopClose(nodeChanBucket *bolt.Bucket, chanID *wire.OutPoint) error {
+	key, err := makeCoopCloseKey(chanID)
+	if err != nil {
+		return err
+	}
+
+	return nodeChanBucket.Delete(key)
+}
+
+func serializeCoopCloseVersions(w io.Writer,
+	versions []*CoopCloseVersion) error {
+
+	var scratch [8]byte
+	byteOrder.PutUint16(scratch[:2], uint16(len(versions)))
+	if _, err := w.Write(scratch[:2]); err != nil {
+		return err
+	}
+
+	for _, version := range versions {
+		if _, err := w.Write(version.Txid[:]); err != nil {
+			return err
+		}
+
+		byteOrder.PutUint64(scratch[:], uint64(version.Fee))
+		if _, err := w.Write(scratch[:]); err != nil {
+			return err
+		}
+
+		var localPays [1]byte
+		if version.LocalPays {
+			localPays[0] = 1
+		}
+		if _, err := w.Write(localPays[:]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func deserializeCoopCloseVersions(r io.Reader) ([]*CoopCloseVersion, error) {
+	var scratch [8]byte
+	if _, err := io.ReadFull(r, scratch[:2]); err != nil {
+		return nil, err
+	}
+	numVersions := byteOrder.Uint16(scratch[:2])
+
+	versions := make([]*CoopCloseVersion, numVersions)
+	for i := range versions {
+		version := &CoopCloseVersion{}
+
+		if _, err := io.ReadFull(r, version.Txid[:]); err != nil {
+			return nil, err
+		}
+
+		if _, err := io.ReadFull(r, scratch[:]); err != nil {
+			return nil, err
+		}
+		version.Fee = btcutil.Amount(byteOrder.Uint64(scratch[:]))
+
+		var localPays [1]byte
+		if _, err := io.ReadFull(r, localPays[:]); err != nil {
+			return nil, err
+		}
+		version.LocalPays = localPays[0] == 1
+
+		versions[i] = version
+	}
+
+	return versions, nil
+}
@@ -0,0 +1,81 @@
+package channeldb
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcutil"
+)
+
+func TestCoopCloseVersions(t *testing.T) {
+	cdb, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+
+	state, err := createTestChannelState(cdb)
+	if err != nil {
+		t.Fatalf("unable to create channel state: %v", err)
+	}
+	if err := state.FullSync(); err != nil {
+		t.Fatalf("unable to save and serialize channel state: %v", err)
+	}
+
+	// A channel which isn't being cooperatively closed has no versions of
+	// its closure transaction.
+	versions, err := state.FetchCoopCloseVersions()
+	if err != nil {
+		t.Fatalf("unable to fetch close versions: %v", err)
+	}
+	if len(versions) != 0 {
+		t.Fatalf("expected no close versions, got %v", len(versions))
+	}
+
+	// Our version, its replacement, and the remote party's version should
+	// be returned in the order they were signed.
+	expected := []*CoopCloseVersion{
+		{
+			Txid:      chainhash.Hash{1},
+			Fee:       btcutil.Amount(5000),
+			LocalPays: true,
+		},
+		{
+			Txid:      chainhash.Hash{2},
+			Fee:       btcutil.Amount(10000),
+			LocalPays: true,
+		},
+		{
+			Txid: chainhash.Hash{3},
+			Fee:  btcutil.Amount(7000),
+		},
+	}
+	for _, version := range expected {
+		if err := state.AddCoopCloseVersion(version); err != nil {
+			t.Fatalf("unable to add close version: %v", err)
+		}
+	}
+	versions, err = state.FetchCoopCloseVersions()
+	if err != nil {
+		t.Fatalf("unable to fetch close versions: %v", err)
+	}
+	if !reflect.DeepEqual(expected, versions) {
+		t.Fatalf("close version mismatch: expected %v, got %v",
+			expected, versions)
+	}
+
+	// Once the channel is closed, the versions should be deleted along
+	// with the rest of its state.
+	if err := state.CloseChannel(); err != nil {
+		t.Fatalf("unable to close channel: %v", err)
+	}
+	versions, err = state.FetchCoopCloseVersions()
+	if err != nil {
+		t.Fatalf("unable to fetch close versions: %v", err)
+	}
+	if len(versions) != 0 {
+		t.Fatalf("expected close versions to be deleted, got %v",
+			len(versions))
+	}
+}
@@ -0,0 +1,200 @@
+package channeldb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+var (
+	// credentialUsageBucket is the name of the bucket within the database
+	// that stores the quota-relevant actions performed with each RPC
+	// credential. Within the bucket, each credential has a sub-bucket
+	// keyed by its name. The actions within a credential's bucket are
+	// keyed by the big-endian encoding of their timestamp in
+	// nanoseconds, so a cursor scan yields them in chronological order.
+	credentialUsageBucket = []byte("credential-usage")
+)
+
+// CredentialUsageType denotes the kind of action performed with a
+// credential.
+type CredentialUsageType uint8
+
+const (
+	// InvoiceCreatedUsage is recorded when an invoice is created.
+	InvoiceCreatedUsage CredentialUsageType = 0
+
+	// PaymentAttemptedUsage is recorded when a payment is attempted,
+	// regardless of whether it succeeds.
+	PaymentAttemptedUsage CredentialUsageType = 1
+
+	// PaymentSpentUsage is recorded when a payment succeeds. Its amount
+	// is the total amount spent, including fees.
+	PaymentSpentUsage CredentialUsageType = 2
+)
+
+// CredentialUsage is a single action performed with a credential.
+type CredentialUsage struct {
+	// Type is the kind of action.
+	Type CredentialUsageType
+
+	// Timestamp is the time the action was performed.
+	Timestamp time.Time
+
+	// Amount is the amount involved in the action, if any.
+	Amount btcutil.Amount
+}
+
+// AddCredentialUsage records an action performed with the named credential.
+// If an action already exists with an identical timestamp, then the
+// timestamp of the new action is advanced until it's unique.
+func (d *DB) AddCredentialUsage(credential string,
+	usage *CredentialUsage) error {
+
+	return d.Update(func(tx *bolt.Tx) error {
+		credentials, err := tx.CreateBucketIfNotExists(
+			credentialUsageBucket)
+		if err != nil {
+			return err
+		}
+		usageBucket, err := credentials.CreateBucketIfNotExists(
+			[]byte(credential))
+		if err != nil {
+			return err
+		}
+
+		var usageKey [8]byte
+		timestamp := uint64(usage.Timestamp.UnixNano())
+		for {
+			binary.BigEndian.PutUint64(usageKey[:], timestamp)
+			if usageBucket.Get(usageKey[:]) == nil {
+				break
+			}
+			timestamp++
+		}
+
+		var b bytes.Buffer
+		if err := serializeCredentialUsage(&b, usage); err != nil {
+			return err
+		}
+
+		return usageBucket.Put(usageKey[:], b.Bytes())
+	})
+}
+
+// FetchCredentialUsage returns the actions performed with the named
+// credential at, or after the passed time, in chronological order. A zero
+// time returns all recorded actions.
+func (d *DB) FetchCredentialUsage(credential string,
+	since time.Time) ([]*CredentialUsage, error) {
+
+	var startKey [8]byte
+	if !since.IsZero() {
+		binary.BigEndian.PutUint64(startKey[:], uint64(since.UnixNano()))
+	}
+
+	var usages []*CredentialUsage
+	err := d.View(func(tx *bolt.Tx) error {
+		credentials := tx.Bucket(credentialUsageBucket)
+		if credentials == nil {
+			return nil
+		}
+		usageBucket := credentials.Bucket([]byte(credential))
+		if usageBucket == nil {
+			return nil
+		}
+
+		c := usageBucket.Cursor()
+		for k, v := c.Seek(startKey[:]); k != nil; k, v = c.Next() {
+			usage, err := deserializeCredentialUsage(
+				bytes.NewReader(v))
+			if err != nil {
+				return err
+			}
+			usage.Timestamp = time.Unix(0,
+				int64(binary.BigEndian.Uint64(k)))
+
+			usages = append(usages, usage)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return usages, nil
+}
+
+// PruneCredentialUsage deletes all actions performed with any credential
+// before the passed time.
+func (d *DB) PruneCredentialUsage(before time.Time) error {
+	var endKey [8]byte
+	binary.BigEndian.PutUint64(endKey[:], uint64(before.UnixNano()))
+
+	return d.Update(func(tx *bolt.Tx) error {
+		credentials := tx.Bucket(credentialUsageBucket)
+		if credentials == nil {
+			return nil
+		}
+
+		return credentials.ForEach(func(name, v []byte) error {
+			usageBucket := credentials.Bucket(name)
+			if usageBucket == nil {
+				return nil
+			}
+
+			// Collect the keys to delete before deleting them, as
+			// modifying a bucket while iterating over it with a
+			// cursor is unsafe.
+			var expired [][]byte
+			c := usageBucket.Cursor()
+			for k, _ := c.First(); k != nil &&
+				bytes.Compare(k, endKey[:]) < 0; k, _ = c.Next() {
+
+				expired = append(expired, k)
+			}
+			for _, k := range expired {
+				if err := usageBucket.Delete(k); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+	})
+}
+
+// serializeCredentialUsage writes the passed action to w. The timestamp of
+// the action isn't written, as it's encoded within the action's key.
+func serializeCredentialUsage(w io.Writer, u *CredentialUsage) error {
+	if _, err := w.Write([]byte{byte(u.Type)}); err != nil {
+		return err
+	}
+
+	return wire.WriteVarInt(w, 0, uint64(u.Amount))
+}
+
+// deserializeCredentialUsage reads an action written by
+// serializeCredentialUsage from r.
+func deserializeCredentialUsage(r io.Reader) (*CredentialUsage, error) {
+	var usageType [1]byte
+	if _, err := io.ReadFull(r, usageType[:]); err != nil {
+		return nil, err
+	}
+
+	amount, err := wire.ReadVarInt(r, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CredentialUsage{
+		Type:   CredentialUsageType(usageType[0]),
+		Amount: btcutil.Amount(amount),
+	}, nil
+}
@@ -0,0 +1,87 @@
+package channeldb
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/roasbeef/btcutil"
+)
+
+func TestCredentialUsage(t *testing.T) {
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+
+	start := time.Unix(1490000000, 0)
+	usages := []*CredentialUsage{
+		{
+			Type:      InvoiceCreatedUsage,
+			Timestamp: start,
+		},
+		{
+			Type:      PaymentAttemptedUsage,
+			Timestamp: start.Add(time.Hour),
+		},
+		{
+			Type:      PaymentSpentUsage,
+			Timestamp: start.Add(time.Hour),
+			Amount:    btcutil.Amount(50000),
+		},
+		{
+			Type:      InvoiceCreatedUsage,
+			Timestamp: start.Add(time.Hour * 2),
+		},
+	}
+	for _, usage := range usages {
+		if err := db.AddCredentialUsage("shop", usage); err != nil {
+			t.Fatalf("unable to add usage: %v", err)
+		}
+	}
+
+	// Actions of another credential shouldn't be returned.
+	err = db.AddCredentialUsage("other", &CredentialUsage{
+		Type:      InvoiceCreatedUsage,
+		Timestamp: start.Add(time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("unable to add usage: %v", err)
+	}
+
+	// The action recorded with an identical timestamp should have been
+	// advanced by a nanosecond, rather than overwriting the prior one.
+	usages[2].Timestamp = usages[2].Timestamp.Add(time.Nanosecond)
+
+	fetched, err := db.FetchCredentialUsage("shop", start.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("unable to fetch usage: %v", err)
+	}
+	if !reflect.DeepEqual(usages[1:], fetched) {
+		t.Fatalf("usage fetched from db doesn't match original %v vs %v",
+			spew.Sdump(usages[1:]), spew.Sdump(fetched))
+	}
+
+	// Pruning should only remove the actions prior to the passed time.
+	if err := db.PruneCredentialUsage(start.Add(time.Hour * 2)); err != nil {
+		t.Fatalf("unable to prune usage: %v", err)
+	}
+	fetched, err = db.FetchCredentialUsage("shop", time.Time{})
+	if err != nil {
+		t.Fatalf("unable to fetch usage: %v", err)
+	}
+	if !reflect.DeepEqual(usages[3:], fetched) {
+		t.Fatalf("usage fetched from db doesn't match original %v vs %v",
+			spew.Sdump(usages[3:]), spew.Sdump(fetched))
+	}
+	fetched, err = db.FetchCredentialUsage("other", time.Time{})
+	if err != nil {
+		t.Fatalf("unable to fetch usage: %v", err)
+	}
+	if len(fetched) != 0 {
+		t.Fatalf("expected other credential's usage to be pruned, "+
+			"got %v", spew.Sdump(fetched))
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/boltdb/bolt"
 	"github.com/roasbeef/btcd/btcec"
@@ -16,6 +18,11 @@ import (
 const (
 	dbName           = "channel.db"
 	dbFilePermission = 0600
+
+	// dbLockTimeout is how long Open waits to acquire the exclusive lock
+	// on the database file before concluding it's held by another
+	// daemon.
+	dbLockTimeout = time.Second
 )
 
 // migration is a function which takes a prior outdated version of the database
@@ -60,10 +67,46 @@ var bufPool = &sync.Pool{
 type DB struct {
 	*bolt.DB
 	dbPath string
+
+	// lowDiskSpace is set while the volume the database resides on is low
+	// on free space. While set, non-critical writes are rejected, so the
+	// remaining space is reserved for writes which are vital to the
+	// safety of our channels, such as revocations and close data.
+	lowDiskSpace uint32 // To be used atomically.
+
+	// commitStats accumulates the latency of write transactions by call
+	// site, so the hottest writes can be identified.
+	commitStats commitStats
+
+	// fenceToken is the fencing token we were assigned upon acquiring
+	// the database. See CheckFence.
+	fenceToken uint64
+}
+
+// SetLowDiskSpace sets whether the volume the database resides on is low on
+// free space. While it is, writes of gossip, the forwarding log, mission
+// control failures, and balance snapshots fail with ErrLowDiskSpace, while
+// all writes related to the state of our channels are still permitted.
+func (d *DB) SetLowDiskSpace(low bool) {
+	var flag uint32
+	if low {
+		flag = 1
+	}
+	atomic.StoreUint32(&d.lowDiskSpace, flag)
+}
+
+// LowDiskSpace returns true if non-critical writes are currently being
+// rejected due to a lack of free space.
+func (d *DB) LowDiskSpace() bool {
+	return atomic.LoadUint32(&d.lowDiskSpace) == 1
 }
 
 // Open opens an existing channeldb. Any necessary schemas migrations due to
-// updates will take place as necessary.
+// updates will take place as necessary. The database file is exclusively
+// locked while open, so only a single daemon may use it at a time. If the
+// database is already open elsewhere, then ErrDBLocked is returned. Once
+// opened, we're assigned a new fencing token, fencing off any daemon which
+// opened the database before us.
 func Open(dbPath string) (*DB, error) {
 	path := filepath.Join(dbPath, dbName)
 
@@ -73,8 +116,12 @@ func Open(dbPath string) (*DB, error) {
 		}
 	}
 
-	bdb, err := bolt.Open(path, dbFilePermission, nil)
-	if err != nil {
+	bdb, err := bolt.Open(path, dbFilePermission, &bolt.Options{
+		Timeout: dbLockTimeout,
+	})
+	if err == bolt.ErrTimeout {
+		return nil, ErrDBLocked
+	} else if err != nil {
 		return nil, err
 	}
 
@@ -89,9 +136,40 @@ func Open(dbPath string) (*DB, error) {
 		return nil, err
 	}
 
+	if err := chanDB.acquireFence(); err != nil {
+		bdb.Close()
+		return nil, err
+	}
+
 	return chanDB, nil
 }
 
+// OpenStandby opens an existing channeldb just as Open, but if the database
+// is already open by another daemon, then it waits in standby for the lock on
+// the database to be released, taking over as soon as it is. This allows an
+// active and standby pair of daemons to share the database, with only one of
+// them signing for our channels at a time. If the quit channel is closed
+// while waiting, then ErrDBLocked is returned.
+func OpenStandby(dbPath string, quit <-chan struct{}) (*DB, error) {
+	for {
+		// Each attempt waits up to dbLockTimeout for the lock to be
+		// released, which bounds how long a failover takes.
+		chanDB, err := Open(dbPath)
+		if err != ErrDBLocked {
+			return chanDB, err
+		}
+
+		select {
+		case <-quit:
+			return nil, ErrDBLocked
+		default:
+		}
+
+		log.Debugf("Channel db at %v is in use, waiting in standby",
+			dbPath)
+	}
+}
+
 // Wipe completely deletes all saved state within all used buckets within the
 // database. The deletion is done in a single transaction, therefore this
 // operation is fully atomic.
@@ -112,6 +190,11 @@ func (d *DB) Wipe() error {
 			return err
 		}
 
+		err = tx.DeleteBucket(offerBucket)
+		if err != nil && err != bolt.ErrBucketNotFound {
+			return err
+		}
+
 		err = tx.DeleteBucket(nodeInfoBucket)
 		if err != nil && err != bolt.ErrBucketNotFound {
 			return err
@@ -168,6 +251,10 @@ func createChannelDB(dbPath string) error {
 			return err
 		}
 
+		if _, err := tx.CreateBucket(offerBucket); err != nil {
+			return err
+		}
+
 		if _, err := tx.CreateBucket(nodeInfoBucket); err != nil {
 			return err
 		}
@@ -307,6 +394,35 @@ func (d *DB) FetchPendingChannels() ([]*OpenChannel, error) {
 	return fetchChannels(d, true)
 }
 
+// IsChannelClosed returns true if the channel identified by the passed
+// outpoint was previously closed, and a summary of it recorded within the
+// database.
+func (d *DB) IsChannelClosed(chanPoint *wire.OutPoint) (bool, error) {
+	var b bytes.Buffer
+	if err := writeOutpoint(&b, chanPoint); err != nil {
+		return false, err
+	}
+
+	var closed bool
+	err := d.View(func(tx *bolt.Tx) error {
+		closedChanBucket := tx.Bucket(closedChannelBucket)
+		if closedChanBucket == nil {
+			return nil
+		}
+
+		// The summary of a closed channel is currently empty, so we
+		// seek to the key itself rather than checking for a value.
+		k, _ := closedChanBucket.Cursor().Seek(b.Bytes())
+		closed = bytes.Equal(k, b.Bytes())
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return closed, nil
+}
+
 // fetchChannels attempts to retrieve channels currently stored in the
 // database. The pendingOnly parameter determines whether only pending
 // channels will be returned. If no active channels exist within the network,
@@ -1,10 +1,15 @@
 package channeldb
 
 import (
+	"crypto/sha256"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
+
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
 )
 
 func TestOpenWithCreate(t *testing.T) {
@@ -31,3 +36,191 @@ func TestOpenWithCreate(t *testing.T) {
 		t.Fatalf("channeldb failed to create data directory")
 	}
 }
+
+// TestOpenLocked tests that a channeldb which is already open can't be opened
+// a second time, so two daemons never share the same channel state.
+func TestOpenLocked(t *testing.T) {
+	tempDirName, err := ioutil.TempDir("", "channeldb")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDirName)
+
+	cdb, err := Open(tempDirName)
+	if err != nil {
+		t.Fatalf("unable to create channeldb: %v", err)
+	}
+
+	if _, err := Open(tempDirName); err != ErrDBLocked {
+		t.Fatalf("expected ErrDBLocked, got %v", err)
+	}
+
+	// Once the first instance closes the database, it may be opened once
+	// more.
+	if err := cdb.Close(); err != nil {
+		t.Fatalf("unable to close channeldb: %v", err)
+	}
+	cdb, err = Open(tempDirName)
+	if err != nil {
+		t.Fatalf("unable to reopen channeldb: %v", err)
+	}
+	cdb.Close()
+}
+
+// TestOpenStandby tests that a standby waits for the channeldb to be released
+// by the daemon using it, then takes over with a higher fencing token.
+func TestOpenStandby(t *testing.T) {
+	tempDirName, err := ioutil.TempDir("", "channeldb")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDirName)
+
+	active, err := Open(tempDirName)
+	if err != nil {
+		t.Fatalf("unable to create channeldb: %v", err)
+	}
+
+	// A standby which is asked to quit while the database is in use
+	// gives up.
+	quit := make(chan struct{})
+	close(quit)
+	if _, err := OpenStandby(tempDirName, quit); err != ErrDBLocked {
+		t.Fatalf("expected ErrDBLocked, got %v", err)
+	}
+
+	// Otherwise, it waits for the active daemon to release the database.
+	type standbyResult struct {
+		cdb *DB
+		err error
+	}
+	result := make(chan standbyResult, 1)
+	go func() {
+		cdb, err := OpenStandby(tempDirName, make(chan struct{}))
+		result <- standbyResult{cdb, err}
+	}()
+
+	select {
+	case <-result:
+		t.Fatalf("standby acquired database in use")
+	case <-time.After(dbLockTimeout * 2):
+	}
+
+	activeToken := active.FenceToken()
+	if err := active.Close(); err != nil {
+		t.Fatalf("unable to close channeldb: %v", err)
+	}
+
+	select {
+	case res := <-result:
+		if res.err != nil {
+			t.Fatalf("standby unable to acquire database: %v",
+				res.err)
+		}
+		defer res.cdb.Close()
+
+		if res.cdb.FenceToken() <= activeToken {
+			t.Fatalf("expected standby's fencing token to exceed "+
+				"%v, got %v", activeToken, res.cdb.FenceToken())
+		}
+		if err := res.cdb.CheckFence(); err != nil {
+			t.Fatalf("standby fenced off: %v", err)
+		}
+	case <-time.After(dbLockTimeout * 5):
+		t.Fatalf("standby didn't take over released database")
+	}
+}
+
+// TestCheckFence tests that once another daemon acquires the channeldb, the
+// daemon which held it before is fenced off.
+func TestCheckFence(t *testing.T) {
+	cdb, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+
+	if err := cdb.CheckFence(); err != nil {
+		t.Fatalf("unexpectedly fenced off: %v", err)
+	}
+
+	// Another daemon acquiring the same database, as it would should the
+	// lock on shared storage be lost, fences us off.
+	other := &DB{DB: cdb.DB, dbPath: cdb.dbPath}
+	if err := other.acquireFence(); err != nil {
+		t.Fatalf("unable to acquire fence: %v", err)
+	}
+	if err := cdb.CheckFence(); err != ErrFenced {
+		t.Fatalf("expected ErrFenced, got %v", err)
+	}
+	if err := other.CheckFence(); err != nil {
+		t.Fatalf("new holder unexpectedly fenced off: %v", err)
+	}
+}
+
+// TestLowDiskSpace tests that non-critical writes are rejected while the
+// database is low on disk space, while writes required for the safety of our
+// channels are still permitted.
+func TestLowDiskSpace(t *testing.T) {
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+
+	db.SetLowDiskSpace(true)
+	if !db.LowDiskSpace() {
+		t.Fatalf("database should be low on disk space")
+	}
+
+	node, err := createTestVertex(db)
+	if err != nil {
+		t.Fatalf("unable to create test node: %v", err)
+	}
+	graph := db.ChannelGraph()
+	if err := graph.AddLightningNode(node); err != ErrLowDiskSpace {
+		t.Fatalf("expected ErrLowDiskSpace, got: %v", err)
+	}
+	err = db.AddBalanceSnapshot(&BalanceSnapshot{Timestamp: time.Now()})
+	if err != ErrLowDiskSpace {
+		t.Fatalf("expected ErrLowDiskSpace, got: %v", err)
+	}
+
+	// A forwarding intent is required to replay a settle after a crash,
+	// so it may still be written, and completed, however the completed
+	// forward isn't appended to the forwarding log.
+	intent := &ForwardingIntent{
+		IncomingChanPoint: wire.OutPoint{Hash: chainhash.Hash(key), Index: 1},
+		OutgoingChanPoint: wire.OutPoint{Hash: chainhash.Hash(key), Index: 2},
+		PaymentHash:       sha256.Sum256(rev[:]),
+		PaymentPreimage:   rev,
+		CreationTime:      time.Unix(1490000000, 0),
+	}
+	if err := db.AddForwardingIntent(intent); err != nil {
+		t.Fatalf("unable to add intent: %v", err)
+	}
+	_, err = db.CompleteForwardingIntent(&intent.IncomingChanPoint,
+		intent.PaymentHash, time.Now())
+	if err != nil {
+		t.Fatalf("unable to complete intent: %v", err)
+	}
+	intents, err := db.FetchForwardingIntents()
+	if err != nil {
+		t.Fatalf("unable to fetch intents: %v", err)
+	}
+	events, err := db.FetchForwardingLog()
+	if err != nil {
+		t.Fatalf("unable to fetch forwarding log: %v", err)
+	}
+	if len(intents) != 0 || len(events) != 0 {
+		t.Fatalf("expected no intents or events, got %v intents and "+
+			"%v events", len(intents), len(events))
+	}
+
+	// Once space has been freed, non-critical writes should succeed once
+	// more.
+	db.SetLowDiskSpace(false)
+	if err := graph.AddLightningNode(node); err != nil {
+		t.Fatalf("unable to add node: %v", err)
+	}
+}
@@ -0,0 +1,144 @@
+package channeldb
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/boltdb/bolt"
+	"github.com/roasbeef/btcd/btcec"
+)
+
+var (
+	// deviceKeyBucket is the name of the bucket within the database that
+	// indexes the keys which are held by an external signing device,
+	// such as a hardware wallet, rather than by the wallet itself. Each
+	// entry is keyed by the compressed public key, and stores the
+	// location of the key within the device's key hierarchy.
+	deviceKeyBucket = []byte("device-keys")
+)
+
+// DeviceKey describes the location of a key held by an external signing
+// device. Together, the fingerprint and derivation path allow the device to
+// re-derive the private key whenever a signature is requested.
+type DeviceKey struct {
+	// MasterFingerprint is the fingerprint of the device's master key,
+	// as defined within BIP32.
+	MasterFingerprint uint32
+
+	// DerivationPath is the BIP32 derivation path of the key starting
+	// from the device's master key. Hardened indexes include the
+	// hardened offset.
+	DerivationPath []uint32
+}
+
+// AddDeviceKey records that the passed public key is held by an external
+// signing device at the location described by key.
+func (d *DB) AddDeviceKey(pub *btcec.PublicKey, key *DeviceKey) error {
+	var b bytes.Buffer
+	if err := serializeDeviceKey(&b, key); err != nil {
+		return err
+	}
+
+	return d.Update(func(tx *bolt.Tx) error {
+		keys, err := tx.CreateBucketIfNotExists(deviceKeyBucket)
+		if err != nil {
+			return err
+		}
+
+		return keys.Put(pub.SerializeCompressed(), b.Bytes())
+	})
+}
+
+// FetchDeviceKey returns the location of the passed public key within the
+// key hierarchy of the signing device which holds it. If the key isn't held
+// by a signing device, then ErrDeviceKeyNotFound is returned.
+func (d *DB) FetchDeviceKey(pub *btcec.PublicKey) (*DeviceKey, error) {
+	var key *DeviceKey
+	err := d.View(func(tx *bolt.Tx) error {
+		keys := tx.Bucket(deviceKeyBucket)
+		if keys == nil {
+			return ErrDeviceKeyNotFound
+		}
+		keyBytes := keys.Get(pub.SerializeCompressed())
+		if keyBytes == nil {
+			return ErrDeviceKeyNotFound
+		}
+
+		var err error
+		key, err = deserializeDeviceKey(bytes.NewReader(keyBytes))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// NextDeviceKeyIndex returns a child index which hasn't yet been handed out
+// for the derivation of a device key. Each call returns a distinct index,
+// even if the key derived from a prior index was never added.
+func (d *DB) NextDeviceKeyIndex() (uint32, error) {
+	var index uint64
+	err := d.Update(func(tx *bolt.Tx) error {
+		keys, err := tx.CreateBucketIfNotExists(deviceKeyBucket)
+		if err != nil {
+			return err
+		}
+
+		// As BoltDB sequences start at one, we subtract one in order
+		// to hand out the first index.
+		index, err = keys.NextSequence()
+		index--
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return uint32(index), nil
+}
+
+func serializeDeviceKey(w io.Writer, k *DeviceKey) error {
+	var scratch [4]byte
+	byteOrder.PutUint32(scratch[:], k.MasterFingerprint)
+	if _, err := w.Write(scratch[:]); err != nil {
+		return err
+	}
+
+	if _, err := w.Write([]byte{uint8(len(k.DerivationPath))}); err != nil {
+		return err
+	}
+	for _, index := range k.DerivationPath {
+		byteOrder.PutUint32(scratch[:], index)
+		if _, err := w.Write(scratch[:]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func deserializeDeviceKey(r io.Reader) (*DeviceKey, error) {
+	k := &DeviceKey{}
+
+	var scratch [4]byte
+	if _, err := io.ReadFull(r, scratch[:]); err != nil {
+		return nil, err
+	}
+	k.MasterFingerprint = byteOrder.Uint32(scratch[:])
+
+	var pathLen [1]byte
+	if _, err := io.ReadFull(r, pathLen[:]); err != nil {
+		return nil, err
+	}
+	k.DerivationPath = make([]uint32, pathLen[0])
+	for i := range k.DerivationPath {
+		if _, err := io.ReadFull(r, scratch[:]); err != nil {
+			return nil, err
+		}
+		k.DerivationPath[i] = byteOrder.Uint32(scratch[:])
+	}
+
+	return k, nil
+}
@@ -0,0 +1,57 @@
+package channeldb
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcutil/hdkeychain"
+)
+
+func TestDeviceKeys(t *testing.T) {
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+
+	_, pub := btcec.PrivKeyFromBytes(btcec.S256(), key[:])
+
+	// A key which was never added shouldn't be found.
+	if _, err := db.FetchDeviceKey(pub); err != ErrDeviceKeyNotFound {
+		t.Fatalf("expected ErrDeviceKeyNotFound, got: %v", err)
+	}
+
+	// Each index handed out should be distinct, starting from zero.
+	for i := uint32(0); i < 3; i++ {
+		index, err := db.NextDeviceKeyIndex()
+		if err != nil {
+			t.Fatalf("unable to fetch index: %v", err)
+		}
+		if index != i {
+			t.Fatalf("expected index %v, got %v", i, index)
+		}
+	}
+
+	deviceKey := &DeviceKey{
+		MasterFingerprint: 0xdeadbeef,
+		DerivationPath: []uint32{
+			hdkeychain.HardenedKeyStart + 1017,
+			hdkeychain.HardenedKeyStart + 0,
+			0, 2,
+		},
+	}
+	if err := db.AddDeviceKey(pub, deviceKey); err != nil {
+		t.Fatalf("unable to add device key: %v", err)
+	}
+
+	fetched, err := db.FetchDeviceKey(pub)
+	if err != nil {
+		t.Fatalf("unable to fetch device key: %v", err)
+	}
+	if !reflect.DeepEqual(deviceKey, fetched) {
+		t.Fatalf("device key fetched from db doesn't match original "+
+			"%v vs %v", spew.Sdump(deviceKey), spew.Sdump(fetched))
+	}
+}
@@ -7,6 +7,17 @@ var (
 	// created.
 	ErrNoChanDBExists = fmt.Errorf("channel db has not yet been created")
 
+	// ErrDBLocked is returned when the channel db is already open by
+	// another daemon. Two daemons signing for the same channels risk
+	// broadcasting revoked states, so the second is refused.
+	ErrDBLocked = fmt.Errorf("channel db is in use by another process")
+
+	// ErrFenced is returned by CheckFence when another daemon has acquired
+	// the channel db since we did, so we may no longer sign for our
+	// channels.
+	ErrFenced = fmt.Errorf("channel db has been acquired by another " +
+		"process")
+
 	// ErrLinkNodesNotFound is returned when node info bucket hasn't been
 	// created.
 	ErrLinkNodesNotFound = fmt.Errorf("no link nodes exist")
@@ -31,10 +42,18 @@ var (
 	// payment hash already exists.
 	ErrDuplicateInvoice = fmt.Errorf("invoice with payment hash already exists")
 
+	// ErrInvoiceExpired is returned when an attempt is made to settle an
+	// invoice which has expired.
+	ErrInvoiceExpired = fmt.Errorf("invoice has expired")
+
 	// ErrNoPaymentsCreated is returned when bucket of payments hasn't been
 	// created.
 	ErrNoPaymentsCreated = fmt.Errorf("there are no existing payments")
 
+	// ErrPaymentIDNotFound is returned when no payment has been dispatched
+	// under the targeted payment identifier.
+	ErrPaymentIDNotFound = fmt.Errorf("unknown payment id")
+
 	// ErrNodeNotFound is returned when node bucket exists, but node with
 	// specific identity can't be found.
 	ErrNodeNotFound = fmt.Errorf("link node with target identity not found")
@@ -73,4 +92,89 @@ var (
 
 	// ErrNodeAliasNotFound is returned when alias for node can't be found.
 	ErrNodeAliasNotFound = fmt.Errorf("alias for node not found")
+
+	// ErrOfferNotFound is returned when a targeted offer can't be found.
+	ErrOfferNotFound = fmt.Errorf("unable to locate offer")
+
+	// ErrDuplicateOffer is returned when an offer with the target ID
+	// already exists.
+	ErrDuplicateOffer = fmt.Errorf("offer with id already exists")
+
+	// ErrOfferMemoTooLarge is returned when the memo attached to an offer
+	// exceeds MaxMemoSize.
+	ErrOfferMemoTooLarge = fmt.Errorf("offer memo exceeds max memo size")
+
+	// ErrForwardingIntentNotFound is returned when the targeted forwarding
+	// intent can't be found, either because it was never written, or it
+	// has already been completed.
+	ErrForwardingIntentNotFound = fmt.Errorf("unable to locate " +
+		"forwarding intent")
+
+	// ErrDeviceKeyNotFound is returned when a public key isn't known to
+	// be held by an external signing device.
+	ErrDeviceKeyNotFound = fmt.Errorf("key isn't held by a signing device")
+
+	// ErrOutboxSubscriberNotFound is returned when the targeted outbox
+	// subscriber isn't registered.
+	ErrOutboxSubscriberNotFound = fmt.Errorf("outbox subscriber not found")
+
+	// ErrNotificationNotFound is returned when the targeted notification
+	// hasn't been added to the outbox.
+	ErrNotificationNotFound = fmt.Errorf("notification not found")
+
+	// ErrRoutingPenaltyNotFound is returned when no payment failures have
+	// been recorded against the targeted channel or node.
+	ErrRoutingPenaltyNotFound = fmt.Errorf("no routing failures recorded")
+
+	// ErrPeerStorageNotFound is returned when the targeted peer hasn't
+	// asked us to store a backup on its behalf.
+	ErrPeerStorageNotFound = fmt.Errorf("no backup stored for peer")
+
+	// ErrNoPendingSplice is returned when the targeted channel isn't being
+	// spliced.
+	ErrNoPendingSplice = fmt.Errorf("channel has no pending splice")
+
+	// ErrChannelAliasNotFound is returned when the targeted alias was
+	// never allocated to a channel.
+	ErrChannelAliasNotFound = fmt.Errorf("channel alias not found")
+
+	// ErrNoChannelAliasesLeft is returned when every alias within the
+	// alias range has been allocated.
+	ErrNoChannelAliasesLeft = fmt.Errorf("no channel aliases left")
+
+	// ErrLowDiskSpace is returned when a non-critical write is attempted
+	// while the database's volume is low on free space.
+	ErrLowDiskSpace = fmt.Errorf("non-critical write rejected due to " +
+		"low disk space")
+
+	// ErrTowerSessionNotFound is returned when the targeted watchtower
+	// session doesn't exist, or isn't owned by the requesting client.
+	ErrTowerSessionNotFound = fmt.Errorf("tower session not found")
+
+	// ErrTowerSessionLimit is returned when a client attempts to create
+	// more watchtower sessions than it's allowed.
+	ErrTowerSessionLimit = fmt.Errorf("tower session limit reached")
+
+	// ErrTowerSessionFull is returned when an update is applied to a
+	// watchtower session which has no updates left.
+	ErrTowerSessionFull = fmt.Errorf("tower session has no updates left")
+
+	// ErrTowerSeqNumOutOfOrder is returned when an update applied to a
+	// watchtower session doesn't follow the last update applied.
+	ErrTowerSeqNumOutOfOrder = fmt.Errorf("tower update sequence number " +
+		"out of order")
+
+	// ErrTowerStorageFull is returned when storing an update would exceed
+	// the storage allotted to the watchtower.
+	ErrTowerStorageFull = fmt.Errorf("tower storage full")
+
+	// ErrTowerClientStorageFull is returned when storing an update would
+	// exceed the storage allotted to a single client of the watchtower.
+	ErrTowerClientStorageFull = fmt.Errorf("tower storage quota of " +
+		"client exceeded")
+
+	// ErrIdentityRotationNotFound is returned when confirming the rotation
+	// of a peer's identity to a key no pending rotation leads to.
+	ErrIdentityRotationNotFound = fmt.Errorf("no pending identity " +
+		"rotation to the key")
 )
@@ -0,0 +1,73 @@
+package channeldb
+
+import (
+	"github.com/boltdb/bolt"
+)
+
+var (
+	// fenceTokenKey is a key within the meta bucket storing the fencing
+	// token of the daemon which most recently acquired the database. The
+	// token is incremented each time the database is opened.
+	fenceTokenKey = []byte("fence")
+)
+
+// acquireFence increments the fencing token stored within the database,
+// adopting the new token as our own. Any daemon which acquired the database
+// before us holds a lower token, so it's fenced off from signing for our
+// channels from this point on.
+func (d *DB) acquireFence() error {
+	return d.Update(func(tx *bolt.Tx) error {
+		metaBucket, err := tx.CreateBucketIfNotExists(metaBucket)
+		if err != nil {
+			return err
+		}
+
+		token := fetchFenceToken(metaBucket) + 1
+
+		var scratch [8]byte
+		byteOrder.PutUint64(scratch[:], token)
+		if err := metaBucket.Put(fenceTokenKey, scratch[:]); err != nil {
+			return err
+		}
+
+		d.fenceToken = token
+		return nil
+	})
+}
+
+// FenceToken returns the fencing token we were assigned upon acquiring the
+// database.
+func (d *DB) FenceToken() uint64 {
+	return d.fenceToken
+}
+
+// CheckFence returns ErrFenced if another daemon has acquired the database
+// since we did, in which case our view of the channels may be outdated. It
+// must be checked before signing a new commitment, so only a single daemon
+// ever signs for our channels, even if the lock on the database is lost, such
+// as when it resides on shared storage.
+func (d *DB) CheckFence() error {
+	return d.View(func(tx *bolt.Tx) error {
+		var token uint64
+		if metaBucket := tx.Bucket(metaBucket); metaBucket != nil {
+			token = fetchFenceToken(metaBucket)
+		}
+
+		if token != d.fenceToken {
+			return ErrFenced
+		}
+
+		return nil
+	})
+}
+
+// fetchFenceToken returns the fencing token stored within the passed meta
+// bucket, or zero if the database has yet to be acquired.
+func fetchFenceToken(metaBucket *bolt.Bucket) uint64 {
+	tokenBytes := metaBucket.Get(fenceTokenKey)
+	if tokenBytes == nil {
+		return 0
+	}
+
+	return byteOrder.Uint64(tokenBytes)
+}
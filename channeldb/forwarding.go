@@ -0,0 +1,374 @@
+package channeldb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+var (
+	// forwardingIntentBucket is the name of the bucket within the
+	// database which acts as a write-ahead log for forwarded HTLCs which
+	// have been settled on their outgoing channel, but not yet on their
+	// incoming channel. Each intent is keyed by the serialized outpoint of
+	// the incoming channel, followed by the payment hash of the HTLC.
+	forwardingIntentBucket = []byte("fwd-intents")
+
+	// forwardingLogBucket is the name of the bucket within the database
+	// which stores the log of all completed forwards. Each event is keyed
+	// by a monotonically increasing uint64 generated with BoltDB's
+	// sequence feature.
+	forwardingLogBucket = []byte("fwd-log")
+)
+
+// ForwardingIntent records that an HTLC we forwarded has been settled on its
+// outgoing channel, and that the settle must still be propagated to its
+// incoming channel. The intent is written before the settle is handed to the
+// incoming channel, and is only removed once the settle has been committed
+// within the incoming channel's state. As a result, a crash between the
+// updates of the two channels leaves behind an intent from which the settle
+// can be replayed, rather than a hole within our accounting.
+type ForwardingIntent struct {
+	// IncomingChanPoint is the channel the HTLC was received on, which
+	// must be settled with PaymentPreimage.
+	IncomingChanPoint wire.OutPoint
+
+	// OutgoingChanPoint is the channel the HTLC was forwarded over, which
+	// has already been settled.
+	OutgoingChanPoint wire.OutPoint
+
+	// PaymentHash is the payment hash of the HTLC.
+	PaymentHash [32]byte
+
+	// PaymentPreimage is the preimage revealed by the settle of the
+	// outgoing HTLC.
+	PaymentPreimage [32]byte
+
+	// AmtIn and AmtOut are the amounts of the incoming and outgoing
+	// HTLCs. The difference between the two is the fee we've earned.
+	AmtIn  btcutil.Amount
+	AmtOut btcutil.Amount
+
+	// CreationTime is the time the outgoing HTLC was settled.
+	CreationTime time.Time
+}
+
+// ForwardingEvent is a single completed forward within the forwarding log.
+type ForwardingEvent struct {
+	// Timestamp is the time the forward was completed.
+	Timestamp time.Time
+
+	// IncomingChanPoint and OutgoingChanPoint are the channels the HTLC
+	// was received and forwarded over.
+	IncomingChanPoint wire.OutPoint
+	OutgoingChanPoint wire.OutPoint
+
+	// PaymentHash is the payment hash of the forwarded HTLC.
+	PaymentHash [32]byte
+
+	// AmtIn and AmtOut are the amounts of the incoming and outgoing
+	// HTLCs.
+	AmtIn  btcutil.Amount
+	AmtOut btcutil.Amount
+}
+
+// forwardingIntentKey returns the key of the intent for the HTLC with the
+// passed payment hash on the passed incoming channel.
+func forwardingIntentKey(incoming *wire.OutPoint,
+	paymentHash [32]byte) ([]byte, error) {
+
+	var b bytes.Buffer
+	if err := writeOutpoint(&b, incoming); err != nil {
+		return nil, err
+	}
+	if _, err := b.Write(paymentHash[:]); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+// AddForwardingIntent writes the passed intent to the database. An existing
+// intent for the same HTLC is overwritten.
+func (d *DB) AddForwardingIntent(intent *ForwardingIntent) error {
+	key, err := forwardingIntentKey(&intent.IncomingChanPoint,
+		intent.PaymentHash)
+	if err != nil {
+		return err
+	}
+
+	var b bytes.Buffer
+	if err := serializeForwardingIntent(&b, intent); err != nil {
+		return err
+	}
+
+	return d.Update(func(tx *bolt.Tx) error {
+		intents, err := tx.CreateBucketIfNotExists(forwardingIntentBucket)
+		if err != nil {
+			return err
+		}
+
+		return intents.Put(key, b.Bytes())
+	})
+}
+
+// FetchForwardingIntents returns all intents which haven't yet been
+// completed.
+func (d *DB) FetchForwardingIntents() ([]*ForwardingIntent, error) {
+	var fwdIntents []*ForwardingIntent
+	err := d.View(func(tx *bolt.Tx) error {
+		intents := tx.Bucket(forwardingIntentBucket)
+		if intents == nil {
+			return nil
+		}
+
+		return intents.ForEach(func(k, v []byte) error {
+			intent, err := deserializeForwardingIntent(
+				bytes.NewReader(v))
+			if err != nil {
+				return err
+			}
+
+			fwdIntents = append(fwdIntents, intent)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return fwdIntents, nil
+}
+
+// CompleteForwardingIntent removes the intent for the HTLC with the passed
+// payment hash on the passed incoming channel, and appends the completed
+// forward to the forwarding log. Both writes are performed within a single
+// database transaction, so a forward is always found either within the
+// intent log, or the forwarding log. If no matching intent exists, then
+// ErrForwardingIntentNotFound is returned.
+//
+// While the database is low on disk space, the intent is still removed, as
+// leaving it behind would cause the settle to be replayed, but the forward
+// isn't appended to the forwarding log.
+//
+// As forwards are completed concurrently by each incoming link, the
+// transaction is batched with those of other links, so a burst of forwards
+// is committed at once.
+func (d *DB) CompleteForwardingIntent(incoming *wire.OutPoint,
+	paymentHash [32]byte, timestamp time.Time) (*ForwardingEvent, error) {
+
+	key, err := forwardingIntentKey(incoming, paymentHash)
+	if err != nil {
+		return nil, err
+	}
+
+	var event *ForwardingEvent
+	err = d.Batch(func(tx *bolt.Tx) error {
+		intents := tx.Bucket(forwardingIntentBucket)
+		if intents == nil {
+			return ErrForwardingIntentNotFound
+		}
+		intentBytes := intents.Get(key)
+		if intentBytes == nil {
+			return ErrForwardingIntentNotFound
+		}
+
+		intent, err := deserializeForwardingIntent(
+			bytes.NewReader(intentBytes))
+		if err != nil {
+			return err
+		}
+
+		event = &ForwardingEvent{
+			Timestamp:         timestamp,
+			IncomingChanPoint: intent.IncomingChanPoint,
+			OutgoingChanPoint: intent.OutgoingChanPoint,
+			PaymentHash:       intent.PaymentHash,
+			AmtIn:             intent.AmtIn,
+			AmtOut:            intent.AmtOut,
+		}
+		if d.LowDiskSpace() {
+			return intents.Delete(key)
+		}
+
+		var b bytes.Buffer
+		if err := serializeForwardingEvent(&b, event); err != nil {
+			return err
+		}
+
+		fwdLog, err := tx.CreateBucketIfNotExists(forwardingLogBucket)
+		if err != nil {
+			return err
+		}
+		eventID, err := fwdLog.NextSequence()
+		if err != nil {
+			return err
+		}
+
+		// We use BigEndian for keys as it orders keys in ascending
+		// order, allowing bucket scans to return events in the order
+		// in which they were completed.
+		var eventKey [8]byte
+		binary.BigEndian.PutUint64(eventKey[:], eventID)
+		if err := fwdLog.Put(eventKey[:], b.Bytes()); err != nil {
+			return err
+		}
+
+		return intents.Delete(key)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+// FetchForwardingLog returns all completed forwards in the order in which
+// they were completed.
+func (d *DB) FetchForwardingLog() ([]*ForwardingEvent, error) {
+	var events []*ForwardingEvent
+	err := d.View(func(tx *bolt.Tx) error {
+		fwdLog := tx.Bucket(forwardingLogBucket)
+		if fwdLog == nil {
+			return nil
+		}
+
+		return fwdLog.ForEach(func(k, v []byte) error {
+			event, err := deserializeForwardingEvent(
+				bytes.NewReader(v))
+			if err != nil {
+				return err
+			}
+
+			events = append(events, event)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func serializeForwardingIntent(w io.Writer, i *ForwardingIntent) error {
+	if err := writeOutpoint(w, &i.IncomingChanPoint); err != nil {
+		return err
+	}
+	if err := writeOutpoint(w, &i.OutgoingChanPoint); err != nil {
+		return err
+	}
+	if _, err := w.Write(i.PaymentHash[:]); err != nil {
+		return err
+	}
+	if _, err := w.Write(i.PaymentPreimage[:]); err != nil {
+		return err
+	}
+
+	var scratch [8]byte
+	byteOrder.PutUint64(scratch[:], uint64(i.AmtIn))
+	if _, err := w.Write(scratch[:]); err != nil {
+		return err
+	}
+	byteOrder.PutUint64(scratch[:], uint64(i.AmtOut))
+	if _, err := w.Write(scratch[:]); err != nil {
+		return err
+	}
+	byteOrder.PutUint64(scratch[:], uint64(i.CreationTime.UnixNano()))
+	_, err := w.Write(scratch[:])
+	return err
+}
+
+func deserializeForwardingIntent(r io.Reader) (*ForwardingIntent, error) {
+	i := &ForwardingIntent{}
+	if err := readOutpoint(r, &i.IncomingChanPoint); err != nil {
+		return nil, err
+	}
+	if err := readOutpoint(r, &i.OutgoingChanPoint); err != nil {
+		return nil, err
+	}
+	if _, err := io.ReadFull(r, i.PaymentHash[:]); err != nil {
+		return nil, err
+	}
+	if _, err := io.ReadFull(r, i.PaymentPreimage[:]); err != nil {
+		return nil, err
+	}
+
+	var scratch [8]byte
+	if _, err := io.ReadFull(r, scratch[:]); err != nil {
+		return nil, err
+	}
+	i.AmtIn = btcutil.Amount(byteOrder.Uint64(scratch[:]))
+	if _, err := io.ReadFull(r, scratch[:]); err != nil {
+		return nil, err
+	}
+	i.AmtOut = btcutil.Amount(byteOrder.Uint64(scratch[:]))
+	if _, err := io.ReadFull(r, scratch[:]); err != nil {
+		return nil, err
+	}
+	i.CreationTime = time.Unix(0, int64(byteOrder.Uint64(scratch[:])))
+
+	return i, nil
+}
+
+func serializeForwardingEvent(w io.Writer, e *ForwardingEvent) error {
+	var scratch [8]byte
+	byteOrder.PutUint64(scratch[:], uint64(e.Timestamp.UnixNano()))
+	if _, err := w.Write(scratch[:]); err != nil {
+		return err
+	}
+
+	if err := writeOutpoint(w, &e.IncomingChanPoint); err != nil {
+		return err
+	}
+	if err := writeOutpoint(w, &e.OutgoingChanPoint); err != nil {
+		return err
+	}
+	if _, err := w.Write(e.PaymentHash[:]); err != nil {
+		return err
+	}
+
+	byteOrder.PutUint64(scratch[:], uint64(e.AmtIn))
+	if _, err := w.Write(scratch[:]); err != nil {
+		return err
+	}
+	byteOrder.PutUint64(scratch[:], uint64(e.AmtOut))
+	_, err := w.Write(scratch[:])
+	return err
+}
+
+func deserializeForwardingEvent(r io.Reader) (*ForwardingEvent, error) {
+	e := &ForwardingEvent{}
+
+	var scratch [8]byte
+	if _, err := io.ReadFull(r, scratch[:]); err != nil {
+		return nil, err
+	}
+	e.Timestamp = time.Unix(0, int64(byteOrder.Uint64(scratch[:])))
+
+	if err := readOutpoint(r, &e.IncomingChanPoint); err != nil {
+		return nil, err
+	}
+	if err := readOutpoint(r, &e.OutgoingChanPoint); err != nil {
+		return nil, err
+	}
+	if _, err := io.ReadFull(r, e.PaymentHash[:]); err != nil {
+		return nil, err
+	}
+
+	if _, err := io.ReadFull(r, scratch[:]); err != nil {
+		return nil, err
+	}
+	e.AmtIn = btcutil.Amount(byteOrder.Uint64(scratch[:]))
+	if _, err := io.ReadFull(r, scratch[:]); err != nil {
+		return nil, err
+	}
+	e.AmtOut = btcutil.Amount(byteOrder.Uint64(scratch[:]))
+
+	return e, nil
+}
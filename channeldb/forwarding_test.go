@@ -0,0 +1,103 @@
+package channeldb
+
+import (
+	"crypto/sha256"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+func TestForwardingIntentWorkflow(t *testing.T) {
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+
+	intent := &ForwardingIntent{
+		IncomingChanPoint: wire.OutPoint{Hash: chainhash.Hash(key), Index: 1},
+		OutgoingChanPoint: wire.OutPoint{Hash: chainhash.Hash(key), Index: 2},
+		PaymentPreimage:   rev,
+		AmtIn:             btcutil.Amount(10010),
+		AmtOut:            btcutil.Amount(10000),
+		CreationTime:      time.Unix(1490000000, 0),
+	}
+	intent.PaymentHash = sha256.Sum256(intent.PaymentPreimage[:])
+
+	// Completing an intent which was never written should fail.
+	_, err = db.CompleteForwardingIntent(&intent.IncomingChanPoint,
+		intent.PaymentHash, time.Now())
+	if err != ErrForwardingIntentNotFound {
+		t.Fatalf("expected ErrForwardingIntentNotFound, got: %v", err)
+	}
+
+	if err := db.AddForwardingIntent(intent); err != nil {
+		t.Fatalf("unable to add intent: %v", err)
+	}
+
+	// The intent should be returned as pending, and the forwarding log
+	// should still be empty.
+	intents, err := db.FetchForwardingIntents()
+	if err != nil {
+		t.Fatalf("unable to fetch intents: %v", err)
+	}
+	if len(intents) != 1 || !reflect.DeepEqual(intent, intents[0]) {
+		t.Fatalf("intents fetched from db don't match original: %v",
+			spew.Sdump(intents))
+	}
+	events, err := db.FetchForwardingLog()
+	if err != nil {
+		t.Fatalf("unable to fetch forwarding log: %v", err)
+	}
+	if len(events) != 0 {
+		t.Fatalf("expected empty forwarding log, got %v events",
+			len(events))
+	}
+
+	// Completing the intent should atomically remove it, and append the
+	// completed forward to the forwarding log.
+	completionTime := time.Unix(1490000060, 0)
+	event, err := db.CompleteForwardingIntent(&intent.IncomingChanPoint,
+		intent.PaymentHash, completionTime)
+	if err != nil {
+		t.Fatalf("unable to complete intent: %v", err)
+	}
+	expectedEvent := &ForwardingEvent{
+		Timestamp:         completionTime,
+		IncomingChanPoint: intent.IncomingChanPoint,
+		OutgoingChanPoint: intent.OutgoingChanPoint,
+		PaymentHash:       intent.PaymentHash,
+		AmtIn:             intent.AmtIn,
+		AmtOut:            intent.AmtOut,
+	}
+	if !reflect.DeepEqual(expectedEvent, event) {
+		t.Fatalf("unexpected forwarding event: %v", spew.Sdump(event))
+	}
+
+	intents, err = db.FetchForwardingIntents()
+	if err != nil {
+		t.Fatalf("unable to fetch intents: %v", err)
+	}
+	if len(intents) != 0 {
+		t.Fatalf("expected no pending intents, got %v", len(intents))
+	}
+	events, err = db.FetchForwardingLog()
+	if err != nil {
+		t.Fatalf("unable to fetch forwarding log: %v", err)
+	}
+	if len(events) != 1 || !reflect.DeepEqual(expectedEvent, events[0]) {
+		t.Fatalf("forwarding log doesn't match: %v", spew.Sdump(events))
+	}
+
+	// An intent may only be completed once.
+	_, err = db.CompleteForwardingIntent(&intent.IncomingChanPoint,
+		intent.PaymentHash, time.Now())
+	if err != ErrForwardingIntentNotFound {
+		t.Fatalf("expected ErrForwardingIntentNotFound, got: %v", err)
+	}
+}
@@ -0,0 +1,121 @@
+package channeldb
+
+import (
+	"fmt"
+
+	"github.com/boltdb/bolt"
+	"github.com/roasbeef/btcutil"
+)
+
+var (
+	// fundingPolicyBucket is the name of the bucket within the database
+	// that stores the policy applied to the channels our peers propose to
+	// open with us.
+	fundingPolicyBucket = []byte("funding-policy")
+
+	// fundingPolicyKey stores the serialized FundingPolicy. If the key is
+	// absent, then no policy has been set, and each limit is disabled.
+	fundingPolicyKey = []byte("policy")
+)
+
+// fundingPolicySize is the size of a serialized FundingPolicy.
+const fundingPolicySize = 8 + 8 + 4 + 8
+
+// FundingPolicy limits the channels our peers may open with us. A limit of
+// zero is disabled.
+type FundingPolicy struct {
+	// MinChanSize is the smallest capacity of a channel we accept.
+	MinChanSize btcutil.Amount
+
+	// MaxChanSize is the largest capacity of a channel we accept.
+	MaxChanSize btcutil.Amount
+
+	// MaxChansPerPeer is the largest number of channels, whether pending
+	// or open, we maintain with a single peer.
+	MaxChansPerPeer uint32
+
+	// MaxPeerExposure is the largest total capacity of the channels,
+	// whether pending or open, we maintain with a single peer.
+	MaxPeerExposure btcutil.Amount
+}
+
+// Validate checks that the policy's limits are consistent with one another.
+func (p *FundingPolicy) Validate() error {
+	switch {
+	case p.MinChanSize < 0 || p.MaxChanSize < 0 || p.MaxPeerExposure < 0:
+		return fmt.Errorf("funding policy limits can't be negative")
+
+	case p.MaxChanSize != 0 && p.MinChanSize > p.MaxChanSize:
+		return fmt.Errorf("minimum channel size of %v exceeds the "+
+			"maximum of %v", p.MinChanSize, p.MaxChanSize)
+
+	case p.MaxPeerExposure != 0 && p.MinChanSize > p.MaxPeerExposure:
+		return fmt.Errorf("minimum channel size of %v exceeds the "+
+			"maximum peer exposure of %v", p.MinChanSize,
+			p.MaxPeerExposure)
+	}
+
+	return nil
+}
+
+// FetchFundingPolicy returns the policy applied to the channels our peers
+// propose to open with us. If no policy has been set, then one with each limit
+// disabled is returned.
+func (d *DB) FetchFundingPolicy() (*FundingPolicy, error) {
+	policy := &FundingPolicy{}
+	err := d.View(func(tx *bolt.Tx) error {
+		policyBucket := tx.Bucket(fundingPolicyBucket)
+		if policyBucket == nil {
+			return nil
+		}
+
+		policyBytes := policyBucket.Get(fundingPolicyKey)
+		if policyBytes == nil {
+			return nil
+		}
+		if len(policyBytes) != fundingPolicySize {
+			return fmt.Errorf("invalid funding policy of %v bytes",
+				len(policyBytes))
+		}
+
+		policy.MinChanSize = btcutil.Amount(
+			byteOrder.Uint64(policyBytes[0:8]))
+		policy.MaxChanSize = btcutil.Amount(
+			byteOrder.Uint64(policyBytes[8:16]))
+		policy.MaxChansPerPeer = byteOrder.Uint32(policyBytes[16:20])
+		policy.MaxPeerExposure = btcutil.Amount(
+			byteOrder.Uint64(policyBytes[20:28]))
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+// PutFundingPolicy sets the policy applied to the channels our peers propose
+// to open with us, replacing any policy previously set.
+func (d *DB) PutFundingPolicy(policy *FundingPolicy) error {
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+
+	return d.Update(func(tx *bolt.Tx) error {
+		policyBucket, err := tx.CreateBucketIfNotExists(
+			fundingPolicyBucket)
+		if err != nil {
+			return err
+		}
+
+		var policyBytes [fundingPolicySize]byte
+		byteOrder.PutUint64(policyBytes[0:8], uint64(policy.MinChanSize))
+		byteOrder.PutUint64(policyBytes[8:16], uint64(policy.MaxChanSize))
+		byteOrder.PutUint32(policyBytes[16:20], policy.MaxChansPerPeer)
+		byteOrder.PutUint64(policyBytes[20:28],
+			uint64(policy.MaxPeerExposure))
+
+		return policyBucket.Put(fundingPolicyKey, policyBytes[:])
+	})
+}
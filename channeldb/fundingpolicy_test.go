@@ -0,0 +1,58 @@
+package channeldb
+
+import (
+	"testing"
+)
+
+// TestFundingPolicy tests that the funding policy defaults to one with each
+// limit disabled, may be replaced, and that inconsistent policies are
+// rejected.
+func TestFundingPolicy(t *testing.T) {
+	cdb, cleanUp, err := makeTestDB()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+	defer cleanUp()
+
+	policy, err := cdb.FetchFundingPolicy()
+	if err != nil {
+		t.Fatalf("unable to fetch funding policy: %v", err)
+	}
+	if *policy != (FundingPolicy{}) {
+		t.Fatalf("expected disabled policy, got %+v", policy)
+	}
+
+	newPolicy := &FundingPolicy{
+		MinChanSize:     20000,
+		MaxChanSize:     1e7,
+		MaxChansPerPeer: 3,
+		MaxPeerExposure: 2e7,
+	}
+	if err := cdb.PutFundingPolicy(newPolicy); err != nil {
+		t.Fatalf("unable to put funding policy: %v", err)
+	}
+	policy, err = cdb.FetchFundingPolicy()
+	if err != nil {
+		t.Fatalf("unable to fetch funding policy: %v", err)
+	}
+	if *policy != *newPolicy {
+		t.Fatalf("expected policy %+v, got %+v", newPolicy, policy)
+	}
+
+	// A minimum channel size above the maximum is rejected, leaving the
+	// stored policy untouched.
+	invalidPolicy := &FundingPolicy{
+		MinChanSize: 2e7,
+		MaxChanSize: 1e7,
+	}
+	if err := cdb.PutFundingPolicy(invalidPolicy); err == nil {
+		t.Fatalf("inconsistent funding policy accepted")
+	}
+	policy, err = cdb.FetchFundingPolicy()
+	if err != nil {
+		t.Fatalf("unable to fetch funding policy: %v", err)
+	}
+	if *policy != *newPolicy {
+		t.Fatalf("expected policy %+v, got %+v", newPolicy, policy)
+	}
+}
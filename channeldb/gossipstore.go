@@ -0,0 +1,193 @@
+package channeldb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/wire"
+)
+
+var (
+	// gossipStoreBucket is the name of the bucket within the database that
+	// stores the gossip messages we've recently received and validated.
+	// Messages are keyed by the time they were received, followed by a
+	// sequence number which disambiguates messages received at the same
+	// instant, so a cursor visits them in the order they arrived.
+	//
+	// maps: receivedTime || seqNum -> peerPubKey || msg
+	gossipStoreBucket = []byte("gossip-store")
+
+	// gossipStoreNet is the network written within the header of each
+	// stored message. As the messages never leave the database, the value
+	// is irrelevant, but it must be consistent between reads and writes.
+	gossipStoreNet = wire.MainNet
+)
+
+// GossipMessage is a gossip message stored along with the peer which sent it
+// to us, and the time at which it was received.
+type GossipMessage struct {
+	// Peer is the public key of the peer the message was received from.
+	Peer *btcec.PublicKey
+
+	// Received is the time at which the message was received.
+	Received time.Time
+
+	// Msg is the gossip message itself.
+	Msg lnwire.Message
+}
+
+// AddGossipMessages appends the passed messages to the gossip store. As the
+// store is bounded, older messages should periodically be removed via
+// PruneGossipMessages. As the store isn't critical, the write is batched with
+// other non-critical writes, such as those of the forwarding log, sharing
+// their commit.
+func (c *ChannelGraph) AddGossipMessages(msgs []*GossipMessage) error {
+	if c.db.LowDiskSpace() {
+		return ErrLowDiskSpace
+	}
+
+	return c.db.Batch(func(tx *bolt.Tx) error {
+		gossip, err := tx.CreateBucketIfNotExists(gossipStoreBucket)
+		if err != nil {
+			return err
+		}
+
+		for _, msg := range msgs {
+			seqNum, err := gossip.NextSequence()
+			if err != nil {
+				return err
+			}
+
+			var key [16]byte
+			binary.BigEndian.PutUint64(key[:8],
+				uint64(msg.Received.UnixNano()))
+			binary.BigEndian.PutUint64(key[8:], seqNum)
+
+			var b bytes.Buffer
+			b.Write(msg.Peer.SerializeCompressed())
+			_, err = lnwire.WriteMessage(&b, msg.Msg, 0, gossipStoreNet)
+			if err != nil {
+				return err
+			}
+
+			if err := gossip.Put(key[:], b.Bytes()); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// FetchGossipMessages returns the stored gossip messages received at or
+// after the passed time, in the order in which they were received.
+func (c *ChannelGraph) FetchGossipMessages(since time.Time) ([]*GossipMessage,
+	error) {
+
+	var msgs []*GossipMessage
+	err := c.db.View(func(tx *bolt.Tx) error {
+		gossip := tx.Bucket(gossipStoreBucket)
+		if gossip == nil {
+			return nil
+		}
+
+		var start [8]byte
+		if since.UnixNano() > 0 {
+			binary.BigEndian.PutUint64(start[:], uint64(since.UnixNano()))
+		}
+
+		cursor := gossip.Cursor()
+		for k, v := cursor.Seek(start[:]); k != nil; k, v = cursor.Next() {
+			msg, err := deserializeGossipMessage(k, v)
+			if err != nil {
+				return err
+			}
+			msgs = append(msgs, msg)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return msgs, nil
+}
+
+// PruneGossipMessages removes all stored gossip messages received before the
+// passed cutoff. Afterwards, if more than maxMessages remain, the oldest are
+// removed until the limit is met. A maxMessages of zero leaves the number of
+// messages unbounded. The number of removed messages is returned.
+func (c *ChannelGraph) PruneGossipMessages(cutoff time.Time,
+	maxMessages int) (int, error) {
+
+	var numPruned int
+	err := c.db.Update(func(tx *bolt.Tx) error {
+		gossip := tx.Bucket(gossipStoreBucket)
+		if gossip == nil {
+			return nil
+		}
+
+		numMsgs := gossip.Stats().KeyN
+		cutoffNano := uint64(cutoff.UnixNano())
+
+		// As the cursor visits the messages from oldest to newest, we
+		// can stop as soon as we reach a message which is both within
+		// the cutoff, and within the limit on the number of messages.
+		// The keys are collected first, as deleting while iterating
+		// would cause the cursor to skip entries.
+		var staleKeys [][]byte
+		cursor := gossip.Cursor()
+		for k, _ := cursor.First(); k != nil; k, _ = cursor.Next() {
+			received := binary.BigEndian.Uint64(k[:8])
+			overLimit := maxMessages != 0 &&
+				numMsgs-len(staleKeys) > maxMessages
+			if received >= cutoffNano && !overLimit {
+				break
+			}
+
+			staleKeys = append(staleKeys, append([]byte(nil), k...))
+		}
+
+		for _, k := range staleKeys {
+			if err := gossip.Delete(k); err != nil {
+				return err
+			}
+		}
+		numPruned = len(staleKeys)
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return numPruned, nil
+}
+
+// deserializeGossipMessage decodes a stored gossip message from its key and
+// value within the gossip store.
+func deserializeGossipMessage(k, v []byte) (*GossipMessage, error) {
+	received := int64(binary.BigEndian.Uint64(k[:8]))
+
+	peer, err := btcec.ParsePubKey(v[:33], btcec.S256())
+	if err != nil {
+		return nil, err
+	}
+
+	_, msg, _, err := lnwire.ReadMessage(bytes.NewReader(v[33:]), 0,
+		gossipStoreNet)
+	if err != nil {
+		return nil, err
+	}
+
+	return &GossipMessage{
+		Peer:     peer,
+		Received: time.Unix(0, received),
+		Msg:      msg,
+	}, nil
+}
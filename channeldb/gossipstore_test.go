@@ -0,0 +1,94 @@
+package channeldb
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/btcec"
+)
+
+func TestGossipStore(t *testing.T) {
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+
+	graph := db.ChannelGraph()
+
+	priv, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to create key: %v", err)
+	}
+
+	start := time.Unix(1490000000, 0)
+	var msgs []*GossipMessage
+	for i := 0; i < 3; i++ {
+		msgs = append(msgs, &GossipMessage{
+			Peer:     priv.PubKey(),
+			Received: start.Add(time.Duration(i) * time.Minute),
+			Msg: &lnwire.ChannelUpdateAnnouncement{
+				Signature:       testSig,
+				ChannelID:       lnwire.NewChanIDFromInt(uint64(i)),
+				Timestamp:       uint32(i),
+				TimeLockDelta:   144,
+				HtlcMinimumMsat: 1000,
+			},
+		})
+	}
+	if err := graph.AddGossipMessages(msgs); err != nil {
+		t.Fatalf("unable to add gossip messages: %v", err)
+	}
+
+	// Only the messages received at or after the passed time should be
+	// returned, in the order they were received.
+	stored, err := graph.FetchGossipMessages(start.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("unable to fetch gossip messages: %v", err)
+	}
+	if len(stored) != 2 {
+		t.Fatalf("expected 2 messages, got %v", len(stored))
+	}
+	for i, msg := range stored {
+		if !msg.Peer.IsEqual(msgs[i+1].Peer) {
+			t.Fatalf("peer mismatch for message %v", i)
+		}
+		if !msg.Received.Equal(msgs[i+1].Received) {
+			t.Fatalf("expected message %v received at %v, got %v",
+				i, msgs[i+1].Received, msg.Received)
+		}
+		if !reflect.DeepEqual(msg.Msg, msgs[i+1].Msg) {
+			t.Fatalf("message %v mismatch: expected %v, got %v",
+				i, msgs[i+1].Msg, msg.Msg)
+		}
+	}
+
+	// Pruning by age should remove only the first message.
+	numPruned, err := graph.PruneGossipMessages(start.Add(time.Minute), 0)
+	if err != nil {
+		t.Fatalf("unable to prune gossip messages: %v", err)
+	}
+	if numPruned != 1 {
+		t.Fatalf("expected 1 message pruned, got %v", numPruned)
+	}
+
+	// Pruning by size should remove the oldest remaining message.
+	numPruned, err = graph.PruneGossipMessages(start, 1)
+	if err != nil {
+		t.Fatalf("unable to prune gossip messages: %v", err)
+	}
+	if numPruned != 1 {
+		t.Fatalf("expected 1 message pruned, got %v", numPruned)
+	}
+
+	stored, err = graph.FetchGossipMessages(time.Time{})
+	if err != nil {
+		t.Fatalf("unable to fetch gossip messages: %v", err)
+	}
+	if len(stored) != 1 || stored[0].Msg.(*lnwire.ChannelUpdateAnnouncement).Timestamp != 2 {
+		t.Fatalf("expected only the newest message to remain, got %v",
+			len(stored))
+	}
+}
@@ -307,6 +307,10 @@ func (c *ChannelGraph) SetSourceNode(node *LightningNode) error {
 // inserted. Afterwards the edge information can then be updated.
 // TODO(roasbeef): also need sig of announcement
 func (c *ChannelGraph) AddLightningNode(node *LightningNode) error {
+	if c.db.LowDiskSpace() {
+		return ErrLowDiskSpace
+	}
+
 	return c.db.Update(func(tx *bolt.Tx) error {
 		return addLightningNode(tx, node)
 	})
@@ -391,6 +395,10 @@ func (c *ChannelGraph) DeleteLightningNode(nodePub *btcec.PublicKey) error {
 // the channel supports. The chanPoint and chanID are used to uniquely identify
 // the edge globally within the database.
 func (c *ChannelGraph) AddChannelEdge(edge *ChannelEdgeInfo) error {
+	if c.db.LowDiskSpace() {
+		return ErrLowDiskSpace
+	}
+
 	// Construct the channel's primary key which is the 8-byte channel ID.
 	var chanKey [8]byte
 	binary.BigEndian.PutUint64(chanKey[:], edge.ChannelID)
@@ -752,6 +760,17 @@ func delChannelByEdge(edges *bolt.Bucket, edgeIndex *bolt.Bucket,
 // determined tby the lexicographical ordering of the identity public keys of
 // the nodes on either side of the channel.
 func (c *ChannelGraph) UpdateEdgePolicy(edge *ChannelEdgePolicy) error {
+	return c.UpdateEdgePolicies([]*ChannelEdgePolicy{edge})
+}
+
+// UpdateEdgePolicies updates the routing policies of several directed edges
+// within a single database transaction. If any of the referenced channels
+// can't be found, then none of the policies are updated.
+func (c *ChannelGraph) UpdateEdgePolicies(policies []*ChannelEdgePolicy) error {
+	if c.db.LowDiskSpace() {
+		return ErrLowDiskSpace
+	}
+
 	return c.db.Update(func(tx *bolt.Tx) error {
 		edges, err := tx.CreateBucketIfNotExists(edgeBucket)
 		if err != nil {
@@ -762,35 +781,49 @@ func (c *ChannelGraph) UpdateEdgePolicy(edge *ChannelEdgePolicy) error {
 			return err
 		}
 
-		// Create the channelID key be converting the channel ID
-		// integer into a byte slice.
-		var chanID [8]byte
-		byteOrder.PutUint64(chanID[:], edge.ChannelID)
-
-		// With the channel ID, we then fetch the value storing the two
-		// nodes which connect this channel edge.
-		nodeInfo := edgeIndex.Get(chanID[:])
-		if nodeInfo == nil {
-			return ErrEdgeNotFound
-		}
-
-		// Depending on the flags value passed above, either the first
-		// or second edge policy is being updated.
-		var fromNode, toNode []byte
-		if edge.Flags == 0 {
-			fromNode = nodeInfo[:33]
-			toNode = nodeInfo[33:67]
-		} else {
-			fromNode = nodeInfo[33:67]
-			toNode = nodeInfo[:33]
+		for _, edge := range policies {
+			if err := updateEdgePolicy(edges, edgeIndex, edge); err != nil {
+				return err
+			}
 		}
 
-		// Finally, with the direction of the edge being updated
-		// identified, we update the on-disk edge representation.
-		return putChanEdgePolicy(edges, edge, fromNode, toNode)
+		return nil
 	})
 }
 
+// updateEdgePolicy updates the routing policy of a single directed edge
+// within the passed edge bucket.
+func updateEdgePolicy(edges, edgeIndex *bolt.Bucket,
+	edge *ChannelEdgePolicy) error {
+
+	// Create the channelID key be converting the channel ID integer into a
+	// byte slice.
+	var chanID [8]byte
+	byteOrder.PutUint64(chanID[:], edge.ChannelID)
+
+	// With the channel ID, we then fetch the value storing the two nodes
+	// which connect this channel edge.
+	nodeInfo := edgeIndex.Get(chanID[:])
+	if nodeInfo == nil {
+		return ErrEdgeNotFound
+	}
+
+	// Depending on the flags value passed above, either the first or
+	// second edge policy is being updated.
+	var fromNode, toNode []byte
+	if edge.Flags == 0 {
+		fromNode = nodeInfo[:33]
+		toNode = nodeInfo[33:67]
+	} else {
+		fromNode = nodeInfo[33:67]
+		toNode = nodeInfo[:33]
+	}
+
+	// Finally, with the direction of the edge being updated identified,
+	// we update the on-disk edge representation.
+	return putChanEdgePolicy(edges, edge, fromNode, toNode)
+}
+
 // LightningNode represents an individual vertex/node within the channel graph.
 // A node is connected to other nodes by one or more channel edges emanating
 // from it. As the graph is directed, a node will also have an incoming edge
@@ -475,6 +475,42 @@ func TestEdgeInfoUpdates(t *testing.T) {
 			dbEdge2)
 	}
 	assertEdgeInfoEqual(t, dbEdgeInfo, edgeInfo)
+
+	// Updating several policies at once should be atomic: if any of the
+	// referenced channels is unknown, then none of the policies should be
+	// updated.
+	newEdge1 := *edge1
+	newEdge1.LastUpdate = time.Unix(433454, 0)
+	newEdge1.FeeBaseMSat = 1000
+	unknownEdge := randEdgePolicy(chanID+1, outpoint, db)
+	err = graph.UpdateEdgePolicies(
+		[]*ChannelEdgePolicy{&newEdge1, unknownEdge},
+	)
+	if err != ErrEdgeNotFound {
+		t.Fatalf("expected ErrEdgeNotFound, got %v", err)
+	}
+	_, dbEdge1, _, err = graph.FetchChannelEdgesByID(chanID)
+	if err != nil {
+		t.Fatalf("unable to fetch channel by ID: %v", err)
+	}
+	if !reflect.DeepEqual(dbEdge1, edge1) {
+		t.Fatalf("edge was updated: expected %#v, \n got %#v", edge1,
+			dbEdge1)
+	}
+
+	// Once the unknown channel is left out, the update should succeed.
+	err = graph.UpdateEdgePolicies([]*ChannelEdgePolicy{&newEdge1})
+	if err != nil {
+		t.Fatalf("unable to update edges: %v", err)
+	}
+	_, dbEdge1, _, err = graph.FetchChannelEdgesByID(chanID)
+	if err != nil {
+		t.Fatalf("unable to fetch channel by ID: %v", err)
+	}
+	if !reflect.DeepEqual(dbEdge1, &newEdge1) {
+		t.Fatalf("edge doesn't match: expected %#v, \n got %#v",
+			&newEdge1, dbEdge1)
+	}
 }
 
 func randEdgePolicy(chanID uint64, op wire.OutPoint, db *DB) *ChannelEdgePolicy {
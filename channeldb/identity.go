@@ -0,0 +1,284 @@
+package channeldb
+
+import (
+	"bytes"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/roasbeef/btcd/btcec"
+)
+
+var (
+	// identityBucket is the name of the bucket within the database that
+	// tracks the rotation of identity keys, both of our own and of our
+	// peers.
+	identityBucket = []byte("identity")
+
+	// identityGenKey stores the generation of our current identity key.
+	// Each rotation of our identity key increments the generation, with
+	// the key of each generation derived from the wallet's root key. If
+	// the key is absent, then our identity has never been rotated.
+	identityGenKey = []byte("gen")
+
+	// pendingRotationBucket is a bucket nested within the identity bucket
+	// which records the rotations announced by our peers that have yet to
+	// be applied. Each entry is keyed by the compressed public key of the
+	// peer's new identity, and stores the compressed public key of the
+	// identity it replaces, followed by the unix time at which the
+	// rotation was first announced, and a byte set once the rotation has
+	// been confirmed by the node's operator.
+	pendingRotationBucket = []byte("pending-rotations")
+)
+
+// pendingRotationLen is the length of each entry within the pending rotation
+// bucket.
+const pendingRotationLen = 33 + 8 + 1
+
+// FetchIdentityGeneration returns the generation of our current identity key.
+// A generation of zero denotes that our identity has never been rotated.
+func (d *DB) FetchIdentityGeneration() (uint32, error) {
+	var gen uint32
+	err := d.View(func(tx *bolt.Tx) error {
+		identity := tx.Bucket(identityBucket)
+		if identity == nil {
+			return nil
+		}
+
+		if genBytes := identity.Get(identityGenKey); genBytes != nil {
+			gen = byteOrder.Uint32(genBytes)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return gen, nil
+}
+
+// PutIdentityGeneration sets the generation of our identity key. The identity
+// key of the new generation is used from the next startup onwards.
+func (d *DB) PutIdentityGeneration(gen uint32) error {
+	return d.Update(func(tx *bolt.Tx) error {
+		identity, err := tx.CreateBucketIfNotExists(identityBucket)
+		if err != nil {
+			return err
+		}
+
+		var genBytes [4]byte
+		byteOrder.PutUint32(genBytes[:], gen)
+		return identity.Put(identityGenKey, genBytes[:])
+	})
+}
+
+// PutIdentityRotation records that the peer known by the identity key oldPub
+// will henceforth be known by newPub, as announced at the passed time. If the
+// rotation was already announced, then the time of its first announcement is
+// kept. The rotation is applied by MigratePeerIdentity once the peer connects
+// with its new identity.
+func (d *DB) PutIdentityRotation(oldPub, newPub *btcec.PublicKey,
+	announcedAt time.Time) error {
+
+	return d.Update(func(tx *bolt.Tx) error {
+		identity, err := tx.CreateBucketIfNotExists(identityBucket)
+		if err != nil {
+			return err
+		}
+		rotations, err := identity.CreateBucketIfNotExists(
+			pendingRotationBucket)
+		if err != nil {
+			return err
+		}
+
+		newKey := newPub.SerializeCompressed()
+		oldKey := oldPub.SerializeCompressed()
+		if entry := rotations.Get(newKey); entry != nil &&
+			bytes.Equal(entry[:33], oldKey) {
+
+			return nil
+		}
+
+		var entry [pendingRotationLen]byte
+		copy(entry[:33], oldKey)
+		byteOrder.PutUint64(entry[33:41], uint64(announcedAt.Unix()))
+		return rotations.Put(newKey, entry[:])
+	})
+}
+
+// ConfirmIdentityRotation marks the pending rotation to the identity key
+// newPub as confirmed, so it's applied as soon as the peer connects with its
+// new identity. ErrIdentityRotationNotFound is returned if no rotation to the
+// key is pending.
+func (d *DB) ConfirmIdentityRotation(newPub *btcec.PublicKey) error {
+	return d.Update(func(tx *bolt.Tx) error {
+		identity := tx.Bucket(identityBucket)
+		if identity == nil {
+			return ErrIdentityRotationNotFound
+		}
+		rotations := identity.Bucket(pendingRotationBucket)
+		if rotations == nil {
+			return ErrIdentityRotationNotFound
+		}
+
+		newKey := newPub.SerializeCompressed()
+		entry := rotations.Get(newKey)
+		if entry == nil {
+			return ErrIdentityRotationNotFound
+		}
+		entry = append([]byte(nil), entry...)
+		entry[pendingRotationLen-1] = 1
+
+		return rotations.Put(newKey, entry)
+	})
+}
+
+// MigratePeerIdentity applies the pending rotation to the passed identity key,
+// if any, provided the rotation has been confirmed, or was announced no later
+// than announcedBefore. Our open channels with the peer, the peer's link node,
+// and the backup it stored with us are all re-keyed from its previous
+// identity to the new one, after which the identity key it replaced is
+// returned. If no rotation to the passed key is ready to be applied, then nil
+// is returned.
+func (d *DB) MigratePeerIdentity(newPub *btcec.PublicKey,
+	announcedBefore time.Time) (*btcec.PublicKey, error) {
+
+	var oldPub *btcec.PublicKey
+	err := d.Update(func(tx *bolt.Tx) error {
+		identity := tx.Bucket(identityBucket)
+		if identity == nil {
+			return nil
+		}
+		rotations := identity.Bucket(pendingRotationBucket)
+		if rotations == nil {
+			return nil
+		}
+
+		newKey := newPub.SerializeCompressed()
+		entry := rotations.Get(newKey)
+		if entry == nil {
+			return nil
+		}
+
+		announcedAt := time.Unix(int64(byteOrder.Uint64(entry[33:41])), 0)
+		confirmed := entry[pendingRotationLen-1] == 1
+		if !confirmed && announcedAt.After(announcedBefore) {
+			return nil
+		}
+		oldKey := append([]byte(nil), entry[:33]...)
+
+		var err error
+		oldPub, err = btcec.ParsePubKey(oldKey, btcec.S256())
+		if err != nil {
+			return err
+		}
+
+		if err := migrateNodeChannels(tx, oldKey, newKey); err != nil {
+			return err
+		}
+		if err := migrateLinkNode(tx, oldKey, newPub); err != nil {
+			return err
+		}
+		if storage := tx.Bucket(peerStorageBucket); storage != nil {
+			if blob := storage.Get(oldKey); blob != nil {
+				blob = append([]byte(nil), blob...)
+				if err := storage.Put(newKey, blob); err != nil {
+					return err
+				}
+				if err := storage.Delete(oldKey); err != nil {
+					return err
+				}
+			}
+		}
+
+		return rotations.Delete(newKey)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return oldPub, nil
+}
+
+// migrateNodeChannels moves the channel bucket of the node with the identity
+// key oldKey beneath the identity key newKey, updating the identity recorded
+// for each of the node's channels along the way.
+func migrateNodeChannels(tx *bolt.Tx, oldKey, newKey []byte) error {
+	openChanBucket := tx.Bucket(openChannelBucket)
+	if openChanBucket == nil {
+		return nil
+	}
+	oldNodeBucket := openChanBucket.Bucket(oldKey)
+	if oldNodeBucket == nil {
+		return nil
+	}
+
+	newNodeBucket, err := openChanBucket.CreateBucketIfNotExists(newKey)
+	if err != nil {
+		return err
+	}
+	if err := copyBucket(newNodeBucket, oldNodeBucket); err != nil {
+		return err
+	}
+
+	// Each channel stores the identity of the remote node under the
+	// chanIDKey prefix, which must now point to its new identity.
+	var idKeys [][]byte
+	err = newNodeBucket.ForEach(func(k, v []byte) error {
+		if v != nil && bytes.HasPrefix(k, chanIDKey) {
+			idKeys = append(idKeys, append([]byte(nil), k...))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, idKey := range idKeys {
+		if err := newNodeBucket.Put(idKey, newKey); err != nil {
+			return err
+		}
+	}
+
+	return openChanBucket.DeleteBucket(oldKey)
+}
+
+// migrateLinkNode re-keys the link node with the identity key oldKey to the
+// identity key newPub.
+func migrateLinkNode(tx *bolt.Tx, oldKey []byte, newPub *btcec.PublicKey) error {
+	nodeMetaBucket := tx.Bucket(nodeInfoBucket)
+	if nodeMetaBucket == nil {
+		return nil
+	}
+	nodeBytes := nodeMetaBucket.Get(oldKey)
+	if nodeBytes == nil {
+		return nil
+	}
+
+	node, err := deserializeLinkNode(bytes.NewReader(nodeBytes))
+	if err != nil {
+		return err
+	}
+	node.IdentityPub = newPub
+
+	if err := putLinkNode(nodeMetaBucket, node); err != nil {
+		return err
+	}
+
+	return nodeMetaBucket.Delete(oldKey)
+}
+
+// copyBucket recursively copies each key, and nested bucket, of src into dst.
+func copyBucket(dst, src *bolt.Bucket) error {
+	return src.ForEach(func(k, v []byte) error {
+		k = append([]byte(nil), k...)
+		if v != nil {
+			return dst.Put(k, append([]byte(nil), v...))
+		}
+
+		child, err := dst.CreateBucketIfNotExists(k)
+		if err != nil {
+			return err
+		}
+
+		return copyBucket(child, src.Bucket(k))
+	})
+}
@@ -0,0 +1,193 @@
+package channeldb
+
+import (
+	"bytes"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/roasbeef/btcd/btcec"
+)
+
+// TestIdentityGeneration tests that the generation of our identity key
+// defaults to zero, and may be updated.
+func TestIdentityGeneration(t *testing.T) {
+	cdb, cleanUp, err := makeTestDB()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+	defer cleanUp()
+
+	gen, err := cdb.FetchIdentityGeneration()
+	if err != nil {
+		t.Fatalf("unable to fetch identity generation: %v", err)
+	}
+	if gen != 0 {
+		t.Fatalf("expected generation 0, got %v", gen)
+	}
+
+	if err := cdb.PutIdentityGeneration(2); err != nil {
+		t.Fatalf("unable to put identity generation: %v", err)
+	}
+	gen, err = cdb.FetchIdentityGeneration()
+	if err != nil {
+		t.Fatalf("unable to fetch identity generation: %v", err)
+	}
+	if gen != 2 {
+		t.Fatalf("expected generation 2, got %v", gen)
+	}
+}
+
+// TestMigratePeerIdentity tests that applying a peer's identity rotation
+// re-keys our channels with it, its link node, and its stored backup, and
+// that a rotation is held back until it's confirmed, or was announced long
+// enough ago.
+func TestMigratePeerIdentity(t *testing.T) {
+	cdb, cleanUp, err := makeTestDB()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+	defer cleanUp()
+
+	state, err := createTestChannelState(cdb)
+	if err != nil {
+		t.Fatalf("unable to create channel state: %v", err)
+	}
+	addr := &net.TCPAddr{
+		IP:   net.ParseIP("127.0.0.1"),
+		Port: 18555,
+	}
+	if err := state.SyncPending(addr); err != nil {
+		t.Fatalf("unable to save and serialize channel state: %v", err)
+	}
+	oldPub := state.IdentityPub
+	if err := cdb.PutPeerStorage(oldPub, []byte("backup")); err != nil {
+		t.Fatalf("unable to store peer backup: %v", err)
+	}
+
+	newPriv, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+	newPub := newPriv.PubKey()
+
+	// Without a pending rotation, nothing should be migrated.
+	announcedAt := time.Unix(1490000000, 0)
+	migrated, err := cdb.MigratePeerIdentity(newPub, announcedAt)
+	if err != nil {
+		t.Fatalf("unable to migrate peer identity: %v", err)
+	}
+	if migrated != nil {
+		t.Fatalf("expected no migration, got one from %x",
+			migrated.SerializeCompressed())
+	}
+
+	// A rotation announced after the cutoff is held back. Announcing it
+	// again doesn't restart the delay.
+	err = cdb.PutIdentityRotation(oldPub, newPub, announcedAt)
+	if err != nil {
+		t.Fatalf("unable to record identity rotation: %v", err)
+	}
+	err = cdb.PutIdentityRotation(oldPub, newPub, announcedAt.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("unable to record identity rotation: %v", err)
+	}
+	migrated, err = cdb.MigratePeerIdentity(newPub,
+		announcedAt.Add(-time.Second))
+	if err != nil {
+		t.Fatalf("unable to migrate peer identity: %v", err)
+	}
+	if migrated != nil {
+		t.Fatalf("expected rotation to be held back")
+	}
+
+	migrated, err = cdb.MigratePeerIdentity(newPub, announcedAt)
+	if err != nil {
+		t.Fatalf("unable to migrate peer identity: %v", err)
+	}
+	if migrated == nil || !migrated.IsEqual(oldPub) {
+		t.Fatalf("expected migration from the previous identity")
+	}
+
+	oldChannels, err := cdb.FetchOpenChannels(oldPub)
+	if err != nil {
+		t.Fatalf("unable to fetch channels: %v", err)
+	}
+	if len(oldChannels) != 0 {
+		t.Fatalf("expected no channels under the previous identity, "+
+			"got %v", len(oldChannels))
+	}
+	newChannels, err := cdb.FetchOpenChannels(newPub)
+	if err != nil {
+		t.Fatalf("unable to fetch channels: %v", err)
+	}
+	if len(newChannels) != 1 {
+		t.Fatalf("expected 1 channel under the new identity, got %v",
+			len(newChannels))
+	}
+	channel := newChannels[0]
+	if !channel.IdentityPub.IsEqual(newPub) {
+		t.Fatalf("channel identity wasn't migrated")
+	}
+	if *channel.ChanID != *state.ChanID ||
+		channel.Capacity != state.Capacity {
+		t.Fatalf("migrated channel doesn't match: %v", channel)
+	}
+
+	if _, err := cdb.FetchLinkNode(oldPub); err != ErrNodeNotFound {
+		t.Fatalf("expected ErrNodeNotFound for the previous identity, "+
+			"got %v", err)
+	}
+	linkNode, err := cdb.FetchLinkNode(newPub)
+	if err != nil {
+		t.Fatalf("unable to fetch link node: %v", err)
+	}
+	if !linkNode.IdentityPub.IsEqual(newPub) {
+		t.Fatalf("link node identity wasn't migrated")
+	}
+
+	blob, err := cdb.FetchPeerStorage(newPub)
+	if err != nil {
+		t.Fatalf("unable to fetch peer backup: %v", err)
+	}
+	if !bytes.Equal(blob, []byte("backup")) {
+		t.Fatalf("expected migrated backup, got %x", blob)
+	}
+
+	// The rotation should only be applied once.
+	migrated, err = cdb.MigratePeerIdentity(newPub, announcedAt)
+	if err != nil {
+		t.Fatalf("unable to migrate peer identity: %v", err)
+	}
+	if migrated != nil {
+		t.Fatalf("expected rotation to be applied only once")
+	}
+
+	// Only a pending rotation may be confirmed.
+	nextPriv, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+	nextPub := nextPriv.PubKey()
+	err = cdb.ConfirmIdentityRotation(nextPub)
+	if err != ErrIdentityRotationNotFound {
+		t.Fatalf("expected ErrIdentityRotationNotFound, got %v", err)
+	}
+
+	// A confirmed rotation is applied regardless of when it was
+	// announced.
+	err = cdb.PutIdentityRotation(newPub, nextPub, announcedAt)
+	if err != nil {
+		t.Fatalf("unable to record identity rotation: %v", err)
+	}
+	if err := cdb.ConfirmIdentityRotation(nextPub); err != nil {
+		t.Fatalf("unable to confirm identity rotation: %v", err)
+	}
+	migrated, err = cdb.MigratePeerIdentity(nextPub, time.Time{})
+	if err != nil {
+		t.Fatalf("unable to migrate peer identity: %v", err)
+	}
+	if migrated == nil || !migrated.IsEqual(newPub) {
+		t.Fatalf("expected confirmed rotation to be applied")
+	}
+}
@@ -81,6 +81,23 @@ func TestInvoiceWorkflow(t *testing.T) {
 		t.Fatalf("invoice should now be settled but isn't")
 	}
 
+	// The time the invoice was settled should have been recorded, and
+	// left untouched should the invoice be settled once again.
+	if dbInvoice2.SettleDate.IsZero() {
+		t.Fatalf("invoice settle date wasn't recorded")
+	}
+	if err := db.SettleInvoice(paymentHash); err != nil {
+		t.Fatalf("unable to settle invoice: %v", err)
+	}
+	dbInvoice3, err := db.LookupInvoice(paymentHash)
+	if err != nil {
+		t.Fatalf("unable to fetch invoice: %v", err)
+	}
+	if !dbInvoice3.SettleDate.Equal(dbInvoice2.SettleDate) {
+		t.Fatalf("settle date changed from %v to %v",
+			dbInvoice2.SettleDate, dbInvoice3.SettleDate)
+	}
+
 	// Attempt to insert generated above again, this should fail as
 	// duplicates are rejected by the processing logic.
 	if err := db.AddInvoice(fakeInvoice); err != ErrDuplicateInvoice {
@@ -131,3 +148,179 @@ func TestInvoiceWorkflow(t *testing.T) {
 		}
 	}
 }
+
+func TestDerivedInvoicePreimage(t *testing.T) {
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+
+	// The preimage of each invoice is derived from the index it's
+	// assigned, which follows those of the invoices added before it.
+	derive := func(addIndex uint32) ([32]byte, error) {
+		var preimage [32]byte
+		byteOrder.PutUint32(preimage[:], addIndex)
+		return sha256.Sum256(preimage[:]), nil
+	}
+
+	randomInvoice, err := randInvoice(1000)
+	if err != nil {
+		t.Fatalf("unable to create invoice: %v", err)
+	}
+	if err := db.AddInvoice(randomInvoice); err != nil {
+		t.Fatalf("unable to add invoice: %v", err)
+	}
+
+	derivedInvoice, err := randInvoice(2000)
+	if err != nil {
+		t.Fatalf("unable to create invoice: %v", err)
+	}
+	if err := db.AddDerivedInvoice(derivedInvoice, derive); err != nil {
+		t.Fatalf("unable to add invoice: %v", err)
+	}
+
+	expectedPreimage, _ := derive(1)
+	if derivedInvoice.AddIndex != 1 || !derivedInvoice.DerivedPreimage ||
+		derivedInvoice.Terms.PaymentPreimage != expectedPreimage {
+
+		t.Fatalf("invoice preimage wasn't derived from its index: %v",
+			spew.Sdump(derivedInvoice))
+	}
+
+	// Both the index and the origin of the preimage should be retained
+	// once the invoice is read back, and remain once settled.
+	paymentHash := sha256.Sum256(expectedPreimage[:])
+	if err := db.SettleInvoice(paymentHash); err != nil {
+		t.Fatalf("unable to settle invoice: %v", err)
+	}
+	dbInvoice, err := db.LookupInvoice(paymentHash)
+	if err != nil {
+		t.Fatalf("unable to find invoice: %v", err)
+	}
+	derivedInvoice.Terms.Settled = true
+	if !reflect.DeepEqual(derivedInvoice, dbInvoice) {
+		t.Fatalf("invoice fetched from db doesn't match original %v vs %v",
+			spew.Sdump(derivedInvoice), spew.Sdump(dbInvoice))
+	}
+
+	randomHash := sha256.Sum256(randomInvoice.Terms.PaymentPreimage[:])
+	dbInvoice, err = db.LookupInvoice(randomHash)
+	if err != nil {
+		t.Fatalf("unable to find invoice: %v", err)
+	}
+	if dbInvoice.AddIndex != 0 || dbInvoice.DerivedPreimage {
+		t.Fatalf("unexpected random invoice: %v", spew.Sdump(dbInvoice))
+	}
+}
+
+// TestInvoiceExpiry tests that expired invoices can't be settled, and that
+// the invoice collector moves those left unsettled to the archive, while
+// retaining those which may still be paid.
+func TestInvoiceExpiry(t *testing.T) {
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+
+	newInvoice := func(expiry time.Time) *Invoice {
+		invoice, err := randInvoice(1000)
+		if err != nil {
+			t.Fatalf("unable to create invoice: %v", err)
+		}
+		invoice.Expiry = expiry
+		if err := db.AddInvoice(invoice); err != nil {
+			t.Fatalf("unable to add invoice: %v", err)
+		}
+		return invoice
+	}
+	hashOf := func(invoice *Invoice) [32]byte {
+		return sha256.Sum256(invoice.Terms.PaymentPreimage[:])
+	}
+
+	expired := newInvoice(time.Now().Add(-time.Minute))
+	pending := newInvoice(time.Now().Add(time.Hour))
+	settled := newInvoice(time.Now().Add(time.Hour))
+	unbounded := newInvoice(time.Time{})
+
+	// The expiry should be retained once the invoice is read back.
+	dbInvoice, err := db.LookupInvoice(hashOf(pending))
+	if err != nil {
+		t.Fatalf("unable to find invoice: %v", err)
+	}
+	if !dbInvoice.Expiry.Equal(pending.Expiry) {
+		t.Fatalf("expected expiry of %v, got %v", pending.Expiry,
+			dbInvoice.Expiry)
+	}
+
+	// The expired invoice can no longer be settled, unlike the others.
+	if err := db.SettleInvoice(hashOf(expired)); err != ErrInvoiceExpired {
+		t.Fatalf("expected expired invoice to be rejected, got: %v",
+			err)
+	}
+	if err := db.SettleInvoice(hashOf(settled)); err != nil {
+		t.Fatalf("unable to settle invoice: %v", err)
+	}
+
+	// Once all the invoices bearing an expiry have expired, only those
+	// left unsettled should be archived.
+	collector := NewInvoiceCollector(db, time.Hour)
+	collector.now = func() time.Time {
+		return time.Now().Add(2 * time.Hour)
+	}
+	archived, err := collector.collect()
+	if err != nil {
+		t.Fatalf("unable to archive invoices: %v", err)
+	}
+	if len(archived) != 2 || archived[0].AddIndex != expired.AddIndex ||
+		archived[1].AddIndex != pending.AddIndex {
+
+		t.Fatalf("unexpected archived invoices: %v",
+			spew.Sdump(archived))
+	}
+
+	dbArchived, err := db.FetchArchivedInvoices()
+	if err != nil {
+		t.Fatalf("unable to fetch archived invoices: %v", err)
+	}
+	if len(dbArchived) != 2 || !reflect.DeepEqual(archived, dbArchived) {
+		t.Fatalf("archived invoices don't match %v vs %v",
+			spew.Sdump(archived), spew.Sdump(dbArchived))
+	}
+
+	dbInvoices, err := db.FetchAllInvoices(false)
+	if err != nil {
+		t.Fatalf("unable to fetch invoices: %v", err)
+	}
+	if len(dbInvoices) != 2 || dbInvoices[0].AddIndex != settled.AddIndex ||
+		dbInvoices[1].AddIndex != unbounded.AddIndex {
+
+		t.Fatalf("unexpected remaining invoices: %v",
+			spew.Sdump(dbInvoices))
+	}
+
+	// The archived invoices can no longer be found, nor their payment
+	// hashes reused.
+	for _, invoice := range []*Invoice{expired, pending} {
+		_, err := db.LookupInvoice(hashOf(invoice))
+		if err != ErrInvoiceNotFound {
+			t.Fatalf("expected archived invoice not to be found, "+
+				"got: %v", err)
+		}
+		if err := db.AddInvoice(invoice); err != ErrDuplicateInvoice {
+			t.Fatalf("expected payment hash reuse to be rejected, "+
+				"got: %v", err)
+		}
+	}
+
+	// With nothing left to expire, a further collection is a noop.
+	archived, err = collector.collect()
+	if err != nil {
+		t.Fatalf("unable to archive invoices: %v", err)
+	}
+	if len(archived) != 0 {
+		t.Fatalf("expected no invoices to be archived, got %v",
+			len(archived))
+	}
+}
@@ -0,0 +1,104 @@
+package channeldb
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// InvoiceCollector periodically moves the invoices within the database which
+// expired without being settled to the invoice archive, so the invoices which
+// may still be paid aren't crowded out by those which never will be.
+type InvoiceCollector struct {
+	started uint32 // To be used atomically.
+	stopped uint32 // To be used atomically.
+
+	db *DB
+
+	// interval is the time between collections. A value of zero disables
+	// the collector.
+	interval time.Duration
+
+	// now returns the current time. It's replaced within tests.
+	now func() time.Time
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewInvoiceCollector creates a new InvoiceCollector which archives the
+// expired invoices within the passed database each interval.
+func NewInvoiceCollector(db *DB, interval time.Duration) *InvoiceCollector {
+	return &InvoiceCollector{
+		db:       db,
+		interval: interval,
+		now:      time.Now,
+		quit:     make(chan struct{}),
+	}
+}
+
+// Start launches the collector, if it's enabled.
+func (c *InvoiceCollector) Start() error {
+	if !atomic.CompareAndSwapUint32(&c.started, 0, 1) {
+		return nil
+	}
+
+	if c.interval != 0 {
+		log.Infof("Archiving expired invoices every %v", c.interval)
+
+		c.wg.Add(1)
+		go c.collector()
+	}
+
+	return nil
+}
+
+// Stop halts the collector.
+func (c *InvoiceCollector) Stop() error {
+	if !atomic.CompareAndSwapUint32(&c.stopped, 0, 1) {
+		return nil
+	}
+
+	close(c.quit)
+	c.wg.Wait()
+
+	return nil
+}
+
+// collector archives the expired invoices at start up, then each time the
+// interval passes.
+//
+// NOTE: This MUST be run as a goroutine.
+func (c *InvoiceCollector) collector() {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := c.collect(); err != nil {
+			log.Errorf("unable to archive expired invoices: %v", err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-c.quit:
+			return
+		}
+	}
+}
+
+// collect archives the invoices which have expired by now, returning those
+// archived.
+func (c *InvoiceCollector) collect() ([]*Invoice, error) {
+	archived, err := c.db.ArchiveExpiredInvoices(c.now())
+	if err != nil {
+		return nil, err
+	}
+
+	if len(archived) != 0 {
+		log.Infof("Archived %v expired invoices", len(archived))
+	}
+
+	return archived, nil
+}
@@ -33,6 +33,14 @@ var (
 	// stored within the invoiceIndexBucket. Within the invoiceBucket
 	// invoices are uniquely identified by the invoice ID.
 	numInvoicesKey = []byte("nik")
+
+	// invoiceArchiveBucket is the name of the bucket which stores the
+	// invoices which expired without being settled, once they've been
+	// moved out of the invoiceBucket. Archived invoices are keyed by their
+	// invoice ID. Their payment hashes remain within the payment hash
+	// index, so they can't be reused by new invoices, but as the invoices
+	// themselves are gone from the invoiceBucket, they can't be settled.
+	invoiceArchiveBucket = []byte("invoice-archive")
 )
 
 const (
@@ -43,6 +51,26 @@ const (
 	// MaxReceiptSize is the maximum size of the payment receipt stored
 	// within the database along side incoming/outgoing invoices.
 	MaxReceiptSize = 1024
+
+	// invoiceSettledFlag is set within the flags byte of a serialized
+	// invoice once the invoice has been settled. As it was once the sole
+	// value of the byte, invoices written before further flags were added
+	// remain readable.
+	invoiceSettledFlag = 1 << 0
+
+	// invoiceDerivedFlag is set within the flags byte of a serialized
+	// invoice whose preimage was derived from the wallet's seed.
+	invoiceDerivedFlag = 1 << 1
+
+	// invoiceSettleDateFlag is set within the flags byte of a serialized
+	// invoice if the time the invoice was settled follows the flags byte.
+	// Invoices settled before settle dates were recorded lack one.
+	invoiceSettleDateFlag = 1 << 2
+
+	// invoiceExpiryFlag is set within the flags byte of a serialized
+	// invoice if the time the invoice expires follows the flags byte, or
+	// the settle date if present. Invoices which never expire lack one.
+	invoiceExpiryFlag = 1 << 3
 )
 
 // ContractTerm is a companion struct to the Invoice struct. This struct houses
@@ -89,6 +117,15 @@ type Invoice struct {
 	// CreationDate is the exact time the invoice was created.
 	CreationDate time.Time
 
+	// SettleDate is the exact time the invoice was settled. It's zero if
+	// the invoice is unsettled, or was settled before settle dates were
+	// recorded.
+	SettleDate time.Time
+
+	// Expiry is the time after which the invoice can no longer be
+	// settled. It's zero if the invoice never expires.
+	Expiry time.Time
+
 	// Terms are the contractual payment terms of the invoice. Once
 	// all the terms have been satisfied by the payer, then the invoice can
 	// be considered fully fulfilled.
@@ -96,8 +133,31 @@ type Invoice struct {
 	// TODO(roasbeef): later allow for multiple terms to fulfill the final
 	// invoice: payment fragmentation, etc.
 	Terms ContractTerm
+
+	// AddIndex is the invoice ID assigned to the invoice when it was
+	// added to the database. Invoice IDs are assigned sequentially,
+	// starting from zero.
+	//
+	// NOTE: This field is set by the database, and isn't serialized.
+	AddIndex uint32
+
+	// DerivedPreimage indicates that the invoice's payment preimage was
+	// derived from the wallet's seed and the invoice's AddIndex, rather
+	// than from fresh randomness. The preimage of such an invoice can be
+	// recovered with only the seed and the invoice's AddIndex.
+	DerivedPreimage bool
 }
 
+// IsExpired returns true if the invoice is unsettled, and had expired by the
+// passed time.
+func (i *Invoice) IsExpired(now time.Time) bool {
+	return !i.Terms.Settled && !i.Expiry.IsZero() && !now.Before(i.Expiry)
+}
+
+// PreimageDeriver derives the payment preimage of an invoice from the invoice
+// ID it's to be assigned within the database.
+type PreimageDeriver func(addIndex uint32) ([32]byte, error)
+
 func validateInvoice(i *Invoice) error {
 	if len(i.Memo) > MaxMemoSize {
 		return fmt.Errorf("max length a memo is %v, and invoice "+
@@ -120,39 +180,70 @@ func (d *DB) AddInvoice(i *Invoice) error {
 		return err
 	}
 	return d.Update(func(tx *bolt.Tx) error {
-		invoices, err := tx.CreateBucketIfNotExists(invoiceBucket)
-		if err != nil {
-			return err
+		return addInvoice(tx, i, nil)
+	})
+}
+
+// AddDerivedInvoice inserts the targeted invoice into the database, with its
+// payment preimage derived from the invoice ID it's assigned by the passed
+// deriver. The invoice is marked as having a derived preimage, and any
+// preimage it carried is replaced.
+func (d *DB) AddDerivedInvoice(i *Invoice, derive PreimageDeriver) error {
+	if err := validateInvoice(i); err != nil {
+		return err
+	}
+	return d.Update(func(tx *bolt.Tx) error {
+		return addInvoice(tx, i, derive)
+	})
+}
+
+// addInvoice inserts the invoice into the database within the passed
+// transaction, assigning it the next available invoice ID. If derive is
+// non-nil, then the invoice's payment preimage is derived from its ID.
+func addInvoice(tx *bolt.Tx, i *Invoice, derive PreimageDeriver) error {
+	invoices, err := tx.CreateBucketIfNotExists(invoiceBucket)
+	if err != nil {
+		return err
+	}
+
+	invoiceIndex, err := invoices.CreateBucketIfNotExists(invoiceIndexBucket)
+	if err != nil {
+		return err
+	}
+
+	// If the current running payment ID counter hasn't yet been created,
+	// then create it now.
+	var invoiceNum uint32
+	invoiceCounter := invoiceIndex.Get(numInvoicesKey)
+	if invoiceCounter == nil {
+		var scratch [4]byte
+		byteOrder.PutUint32(scratch[:], invoiceNum)
+		if err := invoiceIndex.Put(numInvoicesKey, scratch[:]); err != nil {
+			return nil
 		}
+	} else {
+		invoiceNum = byteOrder.Uint32(invoiceCounter)
+	}
 
-		invoiceIndex, err := invoices.CreateBucketIfNotExists(invoiceIndexBucket)
+	// As the invoice ID is now known, the preimage can be derived from
+	// it if requested.
+	if derive != nil {
+		preimage, err := derive(invoiceNum)
 		if err != nil {
 			return err
 		}
+		i.Terms.PaymentPreimage = preimage
+		i.DerivedPreimage = true
+	}
 
-		// Ensure that an invoice an identical payment hash doesn't
-		// already exist within the index.
-		paymentHash := sha256.Sum256(i.Terms.PaymentPreimage[:])
-		if invoiceIndex.Get(paymentHash[:]) != nil {
-			return ErrDuplicateInvoice
-		}
-
-		// If the current running payment ID counter hasn't yet been
-		// created, then create it now.
-		var invoiceNum uint32
-		invoiceCounter := invoiceIndex.Get(numInvoicesKey)
-		if invoiceCounter == nil {
-			var scratch [4]byte
-			byteOrder.PutUint32(scratch[:], invoiceNum)
-			if err := invoiceIndex.Put(numInvoicesKey, scratch[:]); err != nil {
-				return nil
-			}
-		} else {
-			invoiceNum = byteOrder.Uint32(invoiceCounter)
-		}
+	// Ensure that an invoice an identical payment hash doesn't already
+	// exist within the index.
+	paymentHash := sha256.Sum256(i.Terms.PaymentPreimage[:])
+	if invoiceIndex.Get(paymentHash[:]) != nil {
+		return ErrDuplicateInvoice
+	}
 
-		return putInvoice(invoices, invoiceIndex, i, invoiceNum)
-	})
+	return putInvoice(invoices, invoiceIndex, i, invoiceNum)
 }
 
 // LookupInvoice attempts to look up an invoice according to it's 32 byte
@@ -222,6 +313,7 @@ func (d *DB) FetchAllInvoices(pendingOnly bool) ([]*Invoice, error) {
 			if err != nil {
 				return err
 			}
+			invoice.AddIndex = byteOrder.Uint32(k)
 
 			if pendingOnly && invoice.Terms.Settled {
 				return nil
@@ -242,7 +334,8 @@ func (d *DB) FetchAllInvoices(pendingOnly bool) ([]*Invoice, error) {
 // SettleInvoice attempts to mark an invoice corresponding to the passed
 // payment hash as fully settled. If an invoice matching the passed payment
 // hash doesn't existing within the database, then the action will fail with a
-// "not found" error.
+// "not found" error. If the invoice has expired, then ErrInvoiceExpired is
+// returned.
 func (d *DB) SettleInvoice(paymentHash [32]byte) error {
 	return d.Update(func(tx *bolt.Tx) error {
 		invoices, err := tx.CreateBucketIfNotExists(invoiceBucket)
@@ -265,6 +358,43 @@ func (d *DB) SettleInvoice(paymentHash [32]byte) error {
 	})
 }
 
+// UnsettleInvoice reverts the settlement of the invoice paying to the passed
+// payment hash. It's used should the HTLC paying the invoice fail to settle
+// after the invoice was settled on its behalf. If an invoice matching the
+// passed payment hash doesn't exist within the database, then
+// ErrInvoiceNotFound is returned.
+func (d *DB) UnsettleInvoice(paymentHash [32]byte) error {
+	return d.Update(func(tx *bolt.Tx) error {
+		invoices := tx.Bucket(invoiceBucket)
+		if invoices == nil {
+			return ErrInvoiceNotFound
+		}
+		invoiceIndex := invoices.Bucket(invoiceIndexBucket)
+		if invoiceIndex == nil {
+			return ErrInvoiceNotFound
+		}
+
+		invoiceNum := invoiceIndex.Get(paymentHash[:])
+		if invoiceNum == nil {
+			return ErrInvoiceNotFound
+		}
+
+		invoice, err := fetchInvoice(invoiceNum, invoices)
+		if err != nil {
+			return err
+		}
+		invoice.Terms.Settled = false
+		invoice.SettleDate = time.Time{}
+
+		var buf bytes.Buffer
+		if err := serializeInvoice(&buf, invoice); err != nil {
+			return err
+		}
+
+		return invoices.Put(invoiceNum, buf.Bytes())
+	})
+}
+
 func putInvoice(invoices *bolt.Bucket, invoiceIndex *bolt.Bucket,
 	i *Invoice, invoiceNum uint32) error {
 
@@ -272,6 +402,7 @@ func putInvoice(invoices *bolt.Bucket, invoiceIndex *bolt.Bucket,
 	// of the invoice number.
 	var invoiceKey [4]byte
 	byteOrder.PutUint32(invoiceKey[:], invoiceNum)
+	i.AddIndex = invoiceNum
 
 	// Increment the num invoice counter index so the next invoice bares
 	// the proper ID.
@@ -325,15 +456,42 @@ func serializeInvoice(w io.Writer, i *Invoice) error {
 		return err
 	}
 
-	var settleByte [1]byte
+	var flags [1]byte
 	if i.Terms.Settled {
-		settleByte[0] = 1
+		flags[0] |= invoiceSettledFlag
+	}
+	if i.DerivedPreimage {
+		flags[0] |= invoiceDerivedFlag
 	}
-	if _, err := w.Write(settleByte[:]); err != nil {
+	if !i.SettleDate.IsZero() {
+		flags[0] |= invoiceSettleDateFlag
+	}
+	if !i.Expiry.IsZero() {
+		flags[0] |= invoiceExpiryFlag
+	}
+	if _, err := w.Write(flags[:]); err != nil {
 		return err
 	}
 
-	return nil
+	if !i.SettleDate.IsZero() {
+		settleBytes, err := i.SettleDate.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		if err := wire.WriteVarBytes(w, 0, settleBytes); err != nil {
+			return err
+		}
+	}
+
+	if i.Expiry.IsZero() {
+		return nil
+	}
+	expiryBytes, err := i.Expiry.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	return wire.WriteVarBytes(w, 0, expiryBytes)
 }
 
 func fetchInvoice(invoiceNum []byte, invoices *bolt.Bucket) (*Invoice, error) {
@@ -344,7 +502,13 @@ func fetchInvoice(invoiceNum []byte, invoices *bolt.Bucket) (*Invoice, error) {
 
 	invoiceReader := bytes.NewReader(invoiceBytes)
 
-	return deserializeInvoice(invoiceReader)
+	invoice, err := deserializeInvoice(invoiceReader)
+	if err != nil {
+		return nil, err
+	}
+	invoice.AddIndex = byteOrder.Uint32(invoiceNum)
+
+	return invoice, nil
 }
 
 func deserializeInvoice(r io.Reader) (*Invoice, error) {
@@ -378,12 +542,32 @@ func deserializeInvoice(r io.Reader) (*Invoice, error) {
 	}
 	invoice.Terms.Value = btcutil.Amount(byteOrder.Uint64(scratch[:]))
 
-	var settleByte [1]byte
-	if _, err := io.ReadFull(r, settleByte[:]); err != nil {
+	var flags [1]byte
+	if _, err := io.ReadFull(r, flags[:]); err != nil {
 		return nil, err
 	}
-	if settleByte[0] == 1 {
-		invoice.Terms.Settled = true
+	invoice.Terms.Settled = flags[0]&invoiceSettledFlag != 0
+	invoice.DerivedPreimage = flags[0]&invoiceDerivedFlag != 0
+
+	if flags[0]&invoiceSettleDateFlag != 0 {
+		settleBytes, err := wire.ReadVarBytes(r, 0, 300, "settle")
+		if err != nil {
+			return nil, err
+		}
+		err = invoice.SettleDate.UnmarshalBinary(settleBytes)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if flags[0]&invoiceExpiryFlag != 0 {
+		expiryBytes, err := wire.ReadVarBytes(r, 0, 300, "expiry")
+		if err != nil {
+			return nil, err
+		}
+		if err := invoice.Expiry.UnmarshalBinary(expiryBytes); err != nil {
+			return nil, err
+		}
 	}
 
 	return invoice, nil
@@ -395,7 +579,16 @@ func settleInvoice(invoices *bolt.Bucket, invoiceNum []byte) error {
 		return err
 	}
 
-	invoice.Terms.Settled = true
+	// An invoice which has expired can no longer be settled, though
+	// settling an invoice which already is remains a noop.
+	if invoice.IsExpired(time.Now()) {
+		return ErrInvoiceExpired
+	}
+
+	if !invoice.Terms.Settled {
+		invoice.Terms.Settled = true
+		invoice.SettleDate = time.Now()
+	}
 
 	var buf bytes.Buffer
 	if err := serializeInvoice(&buf, invoice); err != nil {
@@ -404,3 +597,104 @@ func settleInvoice(invoices *bolt.Bucket, invoiceNum []byte) error {
 
 	return invoices.Put(invoiceNum[:], buf.Bytes())
 }
+
+// ArchiveExpiredInvoices moves all invoices which had expired by the passed
+// time without being settled from the invoice bucket into the archive bucket.
+// Once archived, an invoice can no longer be looked up or settled by its
+// payment hash, and is omitted from FetchAllInvoices, though its payment hash
+// still can't be reused. The archived invoices are returned.
+func (d *DB) ArchiveExpiredInvoices(now time.Time) ([]*Invoice, error) {
+	var archived []*Invoice
+	err := d.Update(func(tx *bolt.Tx) error {
+		archived = nil
+
+		invoices := tx.Bucket(invoiceBucket)
+		if invoices == nil {
+			return nil
+		}
+
+		// The expired invoices are gathered before any are moved, as
+		// a bucket mustn't be modified while it's being iterated.
+		var keys [][]byte
+		err := invoices.ForEach(func(k, v []byte) error {
+			if v == nil {
+				return nil
+			}
+
+			invoice, err := deserializeInvoice(bytes.NewReader(v))
+			if err != nil {
+				return err
+			}
+			invoice.AddIndex = byteOrder.Uint32(k)
+
+			if !invoice.IsExpired(now) {
+				return nil
+			}
+
+			keys = append(keys, append([]byte(nil), k...))
+			archived = append(archived, invoice)
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if len(archived) == 0 {
+			return nil
+		}
+
+		archive, err := tx.CreateBucketIfNotExists(invoiceArchiveBucket)
+		if err != nil {
+			return err
+		}
+		for i, invoice := range archived {
+			var buf bytes.Buffer
+			if err := serializeInvoice(&buf, invoice); err != nil {
+				return err
+			}
+			if err := archive.Put(keys[i], buf.Bytes()); err != nil {
+				return err
+			}
+
+			if err := invoices.Delete(keys[i]); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return archived, nil
+}
+
+// FetchArchivedInvoices returns all invoices which have been moved to the
+// archive bucket by ArchiveExpiredInvoices.
+func (d *DB) FetchArchivedInvoices() ([]*Invoice, error) {
+	var invoices []*Invoice
+	err := d.View(func(tx *bolt.Tx) error {
+		archive := tx.Bucket(invoiceArchiveBucket)
+		if archive == nil {
+			return nil
+		}
+
+		return archive.ForEach(func(k, v []byte) error {
+			invoice, err := deserializeInvoice(bytes.NewReader(v))
+			if err != nil {
+				return err
+			}
+			invoice.AddIndex = byteOrder.Uint32(k)
+
+			invoices = append(invoices, invoice)
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return invoices, nil
+}
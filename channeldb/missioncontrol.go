@@ -0,0 +1,222 @@
+package channeldb
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/roasbeef/btcd/btcec"
+)
+
+var (
+	// missionControlBucket is the name of the bucket within the database
+	// that stores the failures the router has observed while attempting
+	// payments, so the routing knowledge learned from them survives
+	// restarts.
+	missionControlBucket = []byte("mission-control")
+
+	// edgePenaltyBucket is a sub-bucket of the missionControlBucket which
+	// stores the failures of each channel, keyed by the big-endian
+	// encoding of its channel ID.
+	//
+	// maps: chanID -> failures || lastFailure
+	edgePenaltyBucket = []byte("edge-penalties")
+
+	// nodePenaltyBucket is a sub-bucket of the missionControlBucket which
+	// stores the failures of each node, keyed by its compressed public
+	// key.
+	//
+	// maps: pubKey -> failures || lastFailure
+	nodePenaltyBucket = []byte("node-penalties")
+)
+
+// RoutingPenalty records the payment failures attributed to a channel or
+// node.
+type RoutingPenalty struct {
+	// Failures is the number of failed payment attempts the channel or
+	// node was a part of.
+	Failures uint32
+
+	// LastFailure is the time of the most recent failure.
+	LastFailure time.Time
+}
+
+// AddRoutingFailure records a payment failure at time t against each of the
+// passed channels and nodes. As failures are reported concurrently by each
+// payment, the write is batched with those of concurrent payments.
+func (c *ChannelGraph) AddRoutingFailure(chanIDs []uint64,
+	nodes []*btcec.PublicKey, t time.Time) error {
+
+	if c.db.LowDiskSpace() {
+		return ErrLowDiskSpace
+	}
+
+	return c.db.Batch(func(tx *bolt.Tx) error {
+		edges, nodeBucket, err := createMissionControlBuckets(tx)
+		if err != nil {
+			return err
+		}
+
+		for _, chanID := range chanIDs {
+			var key [8]byte
+			binary.BigEndian.PutUint64(key[:], chanID)
+			if err := addPenaltyFailure(edges, key[:], t); err != nil {
+				return err
+			}
+		}
+		for _, node := range nodes {
+			key := node.SerializeCompressed()
+			if err := addPenaltyFailure(nodeBucket, key, t); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// FetchRoutingPenalties returns the recorded failures of each channel,
+// indexed by channel ID, and of each node, indexed by compressed public key.
+func (c *ChannelGraph) FetchRoutingPenalties() (map[uint64]*RoutingPenalty,
+	map[[33]byte]*RoutingPenalty, error) {
+
+	edges := make(map[uint64]*RoutingPenalty)
+	nodes := make(map[[33]byte]*RoutingPenalty)
+	err := c.db.View(func(tx *bolt.Tx) error {
+		missionControl := tx.Bucket(missionControlBucket)
+		if missionControl == nil {
+			return nil
+		}
+
+		edgeBucket := missionControl.Bucket(edgePenaltyBucket)
+		if edgeBucket != nil {
+			err := edgeBucket.ForEach(func(k, v []byte) error {
+				chanID := binary.BigEndian.Uint64(k)
+				edges[chanID] = deserializeRoutingPenalty(v)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		nodeBucket := missionControl.Bucket(nodePenaltyBucket)
+		if nodeBucket == nil {
+			return nil
+		}
+		return nodeBucket.ForEach(func(k, v []byte) error {
+			var pubKey [33]byte
+			copy(pubKey[:], k)
+			nodes[pubKey] = deserializeRoutingPenalty(v)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return edges, nodes, nil
+}
+
+// ResetEdgePenalty forgets the recorded failures of the target channel. If
+// none have been recorded, then ErrRoutingPenaltyNotFound is returned.
+func (c *ChannelGraph) ResetEdgePenalty(chanID uint64) error {
+	var key [8]byte
+	binary.BigEndian.PutUint64(key[:], chanID)
+
+	return c.db.Update(func(tx *bolt.Tx) error {
+		edges, _, err := createMissionControlBuckets(tx)
+		if err != nil {
+			return err
+		}
+
+		if edges.Get(key[:]) == nil {
+			return ErrRoutingPenaltyNotFound
+		}
+		return edges.Delete(key[:])
+	})
+}
+
+// ResetNodePenalty forgets the recorded failures of the target node. If none
+// have been recorded, then ErrRoutingPenaltyNotFound is returned.
+func (c *ChannelGraph) ResetNodePenalty(node *btcec.PublicKey) error {
+	key := node.SerializeCompressed()
+
+	return c.db.Update(func(tx *bolt.Tx) error {
+		_, nodes, err := createMissionControlBuckets(tx)
+		if err != nil {
+			return err
+		}
+
+		if nodes.Get(key) == nil {
+			return ErrRoutingPenaltyNotFound
+		}
+		return nodes.Delete(key)
+	})
+}
+
+// ResetRoutingPenalties forgets the recorded failures of all channels and
+// nodes.
+func (c *ChannelGraph) ResetRoutingPenalties() error {
+	return c.db.Update(func(tx *bolt.Tx) error {
+		err := tx.DeleteBucket(missionControlBucket)
+		if err != nil && err != bolt.ErrBucketNotFound {
+			return err
+		}
+
+		return nil
+	})
+}
+
+// createMissionControlBuckets returns the edge and node penalty buckets,
+// creating them if they don't yet exist.
+func createMissionControlBuckets(tx *bolt.Tx) (*bolt.Bucket, *bolt.Bucket,
+	error) {
+
+	missionControl, err := tx.CreateBucketIfNotExists(missionControlBucket)
+	if err != nil {
+		return nil, nil, err
+	}
+	edges, err := missionControl.CreateBucketIfNotExists(edgePenaltyBucket)
+	if err != nil {
+		return nil, nil, err
+	}
+	nodes, err := missionControl.CreateBucketIfNotExists(nodePenaltyBucket)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return edges, nodes, nil
+}
+
+// addPenaltyFailure increments the failures of the penalty stored under key
+// within the passed bucket, and sets its last failure to t.
+func addPenaltyFailure(bucket *bolt.Bucket, key []byte, t time.Time) error {
+	penalty := &RoutingPenalty{}
+	if v := bucket.Get(key); v != nil {
+		penalty = deserializeRoutingPenalty(v)
+	}
+
+	penalty.Failures++
+	penalty.LastFailure = t
+
+	return bucket.Put(key, serializeRoutingPenalty(penalty))
+}
+
+// serializeRoutingPenalty encodes the passed penalty as its failures followed
+// by the unix nanosecond timestamp of its last failure.
+func serializeRoutingPenalty(p *RoutingPenalty) []byte {
+	var b [12]byte
+	byteOrder.PutUint32(b[:4], p.Failures)
+	byteOrder.PutUint64(b[4:], uint64(p.LastFailure.UnixNano()))
+	return b[:]
+}
+
+// deserializeRoutingPenalty decodes a penalty encoded by
+// serializeRoutingPenalty.
+func deserializeRoutingPenalty(b []byte) *RoutingPenalty {
+	return &RoutingPenalty{
+		Failures:    byteOrder.Uint32(b[:4]),
+		LastFailure: time.Unix(0, int64(byteOrder.Uint64(b[4:]))),
+	}
+}
@@ -0,0 +1,88 @@
+package channeldb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/roasbeef/btcd/btcec"
+)
+
+func TestRoutingPenalties(t *testing.T) {
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+
+	graph := db.ChannelGraph()
+
+	priv, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to create key: %v", err)
+	}
+	node := priv.PubKey()
+	var nodeKey [33]byte
+	copy(nodeKey[:], node.SerializeCompressed())
+
+	first := time.Unix(1490000000, 0)
+	second := first.Add(time.Minute)
+	err = graph.AddRoutingFailure([]uint64{1, 2}, []*btcec.PublicKey{node},
+		first)
+	if err != nil {
+		t.Fatalf("unable to add routing failure: %v", err)
+	}
+	err = graph.AddRoutingFailure([]uint64{2}, nil, second)
+	if err != nil {
+		t.Fatalf("unable to add routing failure: %v", err)
+	}
+
+	edges, nodes, err := graph.FetchRoutingPenalties()
+	if err != nil {
+		t.Fatalf("unable to fetch penalties: %v", err)
+	}
+	if len(edges) != 2 || len(nodes) != 1 {
+		t.Fatalf("expected 2 edge and 1 node penalties, got %v and %v",
+			len(edges), len(nodes))
+	}
+	if edges[1].Failures != 1 || !edges[1].LastFailure.Equal(first) {
+		t.Fatalf("unexpected penalty of edge 1: %v", edges[1])
+	}
+	if edges[2].Failures != 2 || !edges[2].LastFailure.Equal(second) {
+		t.Fatalf("unexpected penalty of edge 2: %v", edges[2])
+	}
+	if nodes[nodeKey].Failures != 1 {
+		t.Fatalf("unexpected node penalty: %v", nodes[nodeKey])
+	}
+
+	// Penalties can be reset individually, but only if they exist.
+	if err := graph.ResetEdgePenalty(1); err != nil {
+		t.Fatalf("unable to reset edge penalty: %v", err)
+	}
+	if err := graph.ResetEdgePenalty(1); err != ErrRoutingPenaltyNotFound {
+		t.Fatalf("expected ErrRoutingPenaltyNotFound, got %v", err)
+	}
+	if err := graph.ResetNodePenalty(node); err != nil {
+		t.Fatalf("unable to reset node penalty: %v", err)
+	}
+	edges, nodes, err = graph.FetchRoutingPenalties()
+	if err != nil {
+		t.Fatalf("unable to fetch penalties: %v", err)
+	}
+	if len(edges) != 1 || edges[2] == nil || len(nodes) != 0 {
+		t.Fatalf("unexpected penalties after reset: %v, %v", edges,
+			nodes)
+	}
+
+	// Resetting all penalties should leave none behind.
+	if err := graph.ResetRoutingPenalties(); err != nil {
+		t.Fatalf("unable to reset penalties: %v", err)
+	}
+	edges, nodes, err = graph.FetchRoutingPenalties()
+	if err != nil {
+		t.Fatalf("unable to fetch penalties: %v", err)
+	}
+	if len(edges) != 0 || len(nodes) != 0 {
+		t.Fatalf("unexpected penalties after reset: %v, %v", edges,
+			nodes)
+	}
+}
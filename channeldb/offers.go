@@ -0,0 +1,226 @@
+package channeldb
+
+import (
+	"bytes"
+	"io"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+var (
+	// offerBucket is the name of the bucket within the database that
+	// stores all reusable offers. Within the offer bucket, each offer is
+	// keyed by its 32-byte offer ID.
+	offerBucket = []byte("offers")
+)
+
+// Offer is a reusable, static payment code. Unlike an Invoice, an offer
+// doesn't commit to a payment hash. Instead, a payer presenting the offer's
+// ID requests a fresh invoice from the node which created the offer, allowing
+// a single offer to be paid an arbitrary number of times.
+type Offer struct {
+	// ID uniquely identifies this offer. The ID is encoded within the
+	// static payment code handed out to payers.
+	ID [32]byte
+
+	// Memo is an optional memo which is copied into each invoice created
+	// from this offer.
+	Memo []byte
+
+	// Amount is the amount each invoice created from this offer requests.
+	// If the amount is zero, then the payer is free to select the amount
+	// when requesting an invoice.
+	Amount btcutil.Amount
+
+	// CreationDate is the exact time the offer was created.
+	CreationDate time.Time
+
+	// NumInvoices is the total number of invoices which have been issued
+	// in response to invoice requests for this offer.
+	NumInvoices uint64
+}
+
+// AddOffer inserts the targeted offer into the database. If an offer with an
+// identical ID already exists, then ErrDuplicateOffer is returned.
+func (d *DB) AddOffer(o *Offer) error {
+	if len(o.Memo) > MaxMemoSize {
+		return ErrOfferMemoTooLarge
+	}
+
+	return d.Update(func(tx *bolt.Tx) error {
+		offers, err := tx.CreateBucketIfNotExists(offerBucket)
+		if err != nil {
+			return err
+		}
+
+		if offers.Get(o.ID[:]) != nil {
+			return ErrDuplicateOffer
+		}
+
+		return putOffer(offers, o)
+	})
+}
+
+// LookupOffer attempts to look up an offer according to its 32-byte ID. If
+// the offer can't be found, then ErrOfferNotFound is returned.
+func (d *DB) LookupOffer(offerID [32]byte) (*Offer, error) {
+	var offer *Offer
+	err := d.View(func(tx *bolt.Tx) error {
+		offers := tx.Bucket(offerBucket)
+		if offers == nil {
+			return ErrOfferNotFound
+		}
+
+		offerBytes := offers.Get(offerID[:])
+		if offerBytes == nil {
+			return ErrOfferNotFound
+		}
+
+		o, err := deserializeOffer(bytes.NewReader(offerBytes))
+		if err != nil {
+			return err
+		}
+		offer = o
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return offer, nil
+}
+
+// FetchAllOffers returns all offers currently stored within the database.
+func (d *DB) FetchAllOffers() ([]*Offer, error) {
+	var offers []*Offer
+
+	err := d.View(func(tx *bolt.Tx) error {
+		offerB := tx.Bucket(offerBucket)
+		if offerB == nil {
+			return nil
+		}
+
+		return offerB.ForEach(func(k, v []byte) error {
+			offer, err := deserializeOffer(bytes.NewReader(v))
+			if err != nil {
+				return err
+			}
+
+			offers = append(offers, offer)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return offers, nil
+}
+
+// AddOfferInvoice atomically adds a fresh invoice created in response to an
+// invoice request for the target offer, and increments the offer's issued
+// invoice counter.
+func (d *DB) AddOfferInvoice(offerID [32]byte, i *Invoice) error {
+	if err := validateInvoice(i); err != nil {
+		return err
+	}
+
+	return d.Update(func(tx *bolt.Tx) error {
+		offers := tx.Bucket(offerBucket)
+		if offers == nil {
+			return ErrOfferNotFound
+		}
+		offerBytes := offers.Get(offerID[:])
+		if offerBytes == nil {
+			return ErrOfferNotFound
+		}
+		offer, err := deserializeOffer(bytes.NewReader(offerBytes))
+		if err != nil {
+			return err
+		}
+
+		if err := addInvoice(tx, i, nil); err != nil {
+			return err
+		}
+
+		offer.NumInvoices++
+		return putOffer(offers, offer)
+	})
+}
+
+func putOffer(offers *bolt.Bucket, o *Offer) error {
+	var b bytes.Buffer
+	if err := serializeOffer(&b, o); err != nil {
+		return err
+	}
+
+	return offers.Put(o.ID[:], b.Bytes())
+}
+
+func serializeOffer(w io.Writer, o *Offer) error {
+	if _, err := w.Write(o.ID[:]); err != nil {
+		return err
+	}
+	if err := wire.WriteVarBytes(w, 0, o.Memo[:]); err != nil {
+		return err
+	}
+
+	var scratch [8]byte
+	byteOrder.PutUint64(scratch[:], uint64(o.Amount))
+	if _, err := w.Write(scratch[:]); err != nil {
+		return err
+	}
+
+	birthBytes, err := o.CreationDate.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	if err := wire.WriteVarBytes(w, 0, birthBytes); err != nil {
+		return err
+	}
+
+	byteOrder.PutUint64(scratch[:], o.NumInvoices)
+	if _, err := w.Write(scratch[:]); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func deserializeOffer(r io.Reader) (*Offer, error) {
+	var err error
+	o := &Offer{}
+
+	if _, err := io.ReadFull(r, o.ID[:]); err != nil {
+		return nil, err
+	}
+	o.Memo, err = wire.ReadVarBytes(r, 0, MaxMemoSize, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var scratch [8]byte
+	if _, err := io.ReadFull(r, scratch[:]); err != nil {
+		return nil, err
+	}
+	o.Amount = btcutil.Amount(byteOrder.Uint64(scratch[:]))
+
+	birthBytes, err := wire.ReadVarBytes(r, 0, 300, "birth")
+	if err != nil {
+		return nil, err
+	}
+	if err := o.CreationDate.UnmarshalBinary(birthBytes); err != nil {
+		return nil, err
+	}
+
+	if _, err := io.ReadFull(r, scratch[:]); err != nil {
+		return nil, err
+	}
+	o.NumInvoices = byteOrder.Uint64(scratch[:])
+
+	return o, nil
+}
@@ -0,0 +1,95 @@
+package channeldb
+
+import (
+	"crypto/sha256"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/roasbeef/btcutil"
+)
+
+func TestOfferWorkflow(t *testing.T) {
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+
+	// Looking up an offer before any have been added should fail.
+	var offerID [32]byte
+	copy(offerID[:], rev[:])
+	if _, err := db.LookupOffer(offerID); err != ErrOfferNotFound {
+		t.Fatalf("expected ErrOfferNotFound, got: %v", err)
+	}
+
+	offer := &Offer{
+		ID:           offerID,
+		Memo:         []byte("coffee"),
+		Amount:       btcutil.Amount(5000),
+		CreationDate: time.Unix(time.Now().Unix(), 0),
+	}
+	if err := db.AddOffer(offer); err != nil {
+		t.Fatalf("unable to add offer: %v", err)
+	}
+
+	// Adding an offer with the same ID a second time should be rejected.
+	if err := db.AddOffer(offer); err != ErrDuplicateOffer {
+		t.Fatalf("expected ErrDuplicateOffer, got: %v", err)
+	}
+
+	dbOffer, err := db.LookupOffer(offerID)
+	if err != nil {
+		t.Fatalf("unable to fetch offer: %v", err)
+	}
+	if !reflect.DeepEqual(offer, dbOffer) {
+		t.Fatalf("offer fetched from db doesn't match original %v vs %v",
+			spew.Sdump(offer), spew.Sdump(dbOffer))
+	}
+
+	// Issue several fresh invoices from the offer, each of these should
+	// be retrievable by their payment hash, and the offer's counter should
+	// be bumped with each issued invoice.
+	const numInvoices = 3
+	for i := 0; i < numInvoices; i++ {
+		invoice, err := randInvoice(offer.Amount)
+		if err != nil {
+			t.Fatalf("unable to create invoice: %v", err)
+		}
+		if err := db.AddOfferInvoice(offerID, invoice); err != nil {
+			t.Fatalf("unable to add offer invoice: %v", err)
+		}
+
+		payHash := sha256.Sum256(invoice.Terms.PaymentPreimage[:])
+		if _, err := db.LookupInvoice(payHash); err != nil {
+			t.Fatalf("unable to find offer invoice: %v", err)
+		}
+	}
+
+	dbOffer, err = db.LookupOffer(offerID)
+	if err != nil {
+		t.Fatalf("unable to fetch offer: %v", err)
+	}
+	if dbOffer.NumInvoices != numInvoices {
+		t.Fatalf("expected %v issued invoices, instead have %v",
+			numInvoices, dbOffer.NumInvoices)
+	}
+
+	offers, err := db.FetchAllOffers()
+	if err != nil {
+		t.Fatalf("unable to fetch offers: %v", err)
+	}
+	if len(offers) != 1 {
+		t.Fatalf("expected 1 offer, instead have %v", len(offers))
+	}
+
+	// Invoices can't be issued for an offer that doesn't exist.
+	invoice, err := randInvoice(offer.Amount)
+	if err != nil {
+		t.Fatalf("unable to create invoice: %v", err)
+	}
+	if err := db.AddOfferInvoice([32]byte{}, invoice); err != ErrOfferNotFound {
+		t.Fatalf("expected ErrOfferNotFound, got: %v", err)
+	}
+}
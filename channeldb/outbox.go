@@ -0,0 +1,392 @@
+package channeldb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/roasbeef/btcd/wire"
+)
+
+var (
+	// outboxBucket is the name of the bucket within the database that
+	// stores event notifications awaiting delivery to subscribers, along
+	// with the delivery progress of each subscriber.
+	outboxBucket = []byte("notification-outbox")
+
+	// outboxNotificationsBucket is a sub-bucket of the outboxBucket which
+	// stores the notifications themselves, keyed by the big-endian
+	// encoding of their sequential ID.
+	//
+	// maps: id -> type || timestamp || payload
+	outboxNotificationsBucket = []byte("notifications")
+
+	// outboxSubscribersBucket is a sub-bucket of the outboxBucket which
+	// stores the ID of the last notification acknowledged by each
+	// subscriber. A notification is retained until every subscriber has
+	// acknowledged it.
+	//
+	// maps: name -> acked id
+	outboxSubscribersBucket = []byte("subscribers")
+
+	// lastNotificationKey is a key within the outboxBucket that stores
+	// the ID of the most recently added notification. It's tracked apart
+	// from the notifications, as they may all have been pruned.
+	lastNotificationKey = []byte("last-notification")
+)
+
+// NotificationType denotes the kind of event a notification describes.
+type NotificationType uint8
+
+const (
+	// InvoiceSettledNotification is added when one of our invoices has
+	// been paid.
+	InvoiceSettledNotification NotificationType = 0
+
+	// ChannelClosedNotification is added once the closing transaction of
+	// a channel has been confirmed.
+	ChannelClosedNotification NotificationType = 1
+
+	// BreachDetectedNotification is added when the remote party of a
+	// channel broadcasts a revoked commitment transaction.
+	BreachDetectedNotification NotificationType = 2
+
+	// StateDivergedNotification is added when the in-memory state of a
+	// channel diverges from its persisted state, freezing the channel
+	// until the divergence is acknowledged.
+	StateDivergedNotification NotificationType = 3
+
+	// LowDiskSpaceNotification is added when the free space on the volume
+	// of the channel database falls below the configured threshold, and
+	// non-critical writes begin to be rejected.
+	LowDiskSpaceNotification NotificationType = 4
+)
+
+// String returns a human readable name for the notification type.
+func (n NotificationType) String() string {
+	switch n {
+	case InvoiceSettledNotification:
+		return "InvoiceSettled"
+	case ChannelClosedNotification:
+		return "ChannelClosed"
+	case BreachDetectedNotification:
+		return "BreachDetected"
+	case StateDivergedNotification:
+		return "StateDiverged"
+	case LowDiskSpaceNotification:
+		return "LowDiskSpace"
+	default:
+		return "Unknown"
+	}
+}
+
+// Notification is a single event notification within the outbox.
+type Notification struct {
+	// ID is the sequential ID of the notification, assigned when it's
+	// added to the outbox. IDs start at 1.
+	ID uint64
+
+	// Type is the kind of event the notification describes.
+	Type NotificationType
+
+	// Timestamp is the time the event occurred.
+	Timestamp time.Time
+
+	// Payload is the opaque, serialized description of the event.
+	Payload []byte
+}
+
+// AddNotification appends the passed notification to the outbox, assigning
+// it the next sequential ID, which is returned.
+func (d *DB) AddNotification(n *Notification) (uint64, error) {
+	err := d.Update(func(tx *bolt.Tx) error {
+		notifications, _, err := createOutboxBuckets(tx)
+		if err != nil {
+			return err
+		}
+
+		n.ID = lastNotificationID(tx) + 1
+		var idBytes [8]byte
+		byteOrder.PutUint64(idBytes[:], n.ID)
+		outbox := tx.Bucket(outboxBucket)
+		if err := outbox.Put(lastNotificationKey, idBytes[:]); err != nil {
+			return err
+		}
+
+		var b bytes.Buffer
+		if err := serializeNotification(&b, n); err != nil {
+			return err
+		}
+
+		var key [8]byte
+		binary.BigEndian.PutUint64(key[:], n.ID)
+		return notifications.Put(key[:], b.Bytes())
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return n.ID, nil
+}
+
+// FetchNotifications returns the notifications within the outbox with an ID
+// greater than the passed ID, in the order they were added.
+func (d *DB) FetchNotifications(after uint64) ([]*Notification, error) {
+	var startKey [8]byte
+	binary.BigEndian.PutUint64(startKey[:], after+1)
+
+	var notifications []*Notification
+	err := d.View(func(tx *bolt.Tx) error {
+		outbox := tx.Bucket(outboxBucket)
+		if outbox == nil {
+			return nil
+		}
+		bucket := outbox.Bucket(outboxNotificationsBucket)
+		if bucket == nil {
+			return nil
+		}
+
+		c := bucket.Cursor()
+		for k, v := c.Seek(startKey[:]); k != nil; k, v = c.Next() {
+			n, err := deserializeNotification(bytes.NewReader(v))
+			if err != nil {
+				return err
+			}
+			n.ID = binary.BigEndian.Uint64(k)
+
+			notifications = append(notifications, n)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
+
+// AddOutboxSubscriber registers a subscriber to the outbox under the passed
+// name, and returns the ID of the last notification it has acknowledged. A
+// new subscriber is only delivered the notifications added after it was
+// registered. Registering an existing subscriber has no effect.
+func (d *DB) AddOutboxSubscriber(name string) (uint64, error) {
+	var acked uint64
+	err := d.Update(func(tx *bolt.Tx) error {
+		_, subscribers, err := createOutboxBuckets(tx)
+		if err != nil {
+			return err
+		}
+
+		if ackBytes := subscribers.Get([]byte(name)); ackBytes != nil {
+			acked = byteOrder.Uint64(ackBytes)
+			return nil
+		}
+
+		acked = lastNotificationID(tx)
+		var ackBytes [8]byte
+		byteOrder.PutUint64(ackBytes[:], acked)
+		return subscribers.Put([]byte(name), ackBytes[:])
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return acked, nil
+}
+
+// FetchOutboxSubscribers returns the ID of the last notification
+// acknowledged by each registered subscriber, indexed by the subscriber's
+// name.
+func (d *DB) FetchOutboxSubscribers() (map[string]uint64, error) {
+	subscribers := make(map[string]uint64)
+	err := d.View(func(tx *bolt.Tx) error {
+		outbox := tx.Bucket(outboxBucket)
+		if outbox == nil {
+			return nil
+		}
+		bucket := outbox.Bucket(outboxSubscribersBucket)
+		if bucket == nil {
+			return nil
+		}
+
+		return bucket.ForEach(func(k, v []byte) error {
+			subscribers[string(k)] = byteOrder.Uint64(v)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return subscribers, nil
+}
+
+// AckNotification records that the named subscriber has processed all
+// notifications up to, and including the one with the passed ID.
+// Acknowledgments are cumulative, so acknowledging an ID below that of a
+// prior acknowledgment has no effect. Notifications which have been
+// acknowledged by every subscriber are removed from the outbox.
+//
+// If the subscriber isn't registered, then ErrOutboxSubscriberNotFound is
+// returned. If no notification with the ID has been added yet, then
+// ErrNotificationNotFound is returned.
+func (d *DB) AckNotification(name string, id uint64) error {
+	return d.Update(func(tx *bolt.Tx) error {
+		notifications, subscribers, err := createOutboxBuckets(tx)
+		if err != nil {
+			return err
+		}
+
+		ackBytes := subscribers.Get([]byte(name))
+		if ackBytes == nil {
+			return ErrOutboxSubscriberNotFound
+		}
+		if id > lastNotificationID(tx) {
+			return ErrNotificationNotFound
+		}
+		if id <= byteOrder.Uint64(ackBytes) {
+			return nil
+		}
+
+		var newAck [8]byte
+		byteOrder.PutUint64(newAck[:], id)
+		if err := subscribers.Put([]byte(name), newAck[:]); err != nil {
+			return err
+		}
+
+		return pruneOutbox(notifications, subscribers)
+	})
+}
+
+// RemoveOutboxSubscriber unregisters the named subscriber, after which the
+// notifications it hasn't acknowledged are no longer retained on its behalf.
+// If the subscriber isn't registered, then ErrOutboxSubscriberNotFound is
+// returned.
+func (d *DB) RemoveOutboxSubscriber(name string) error {
+	return d.Update(func(tx *bolt.Tx) error {
+		notifications, subscribers, err := createOutboxBuckets(tx)
+		if err != nil {
+			return err
+		}
+
+		if subscribers.Get([]byte(name)) == nil {
+			return ErrOutboxSubscriberNotFound
+		}
+		if err := subscribers.Delete([]byte(name)); err != nil {
+			return err
+		}
+
+		return pruneOutbox(notifications, subscribers)
+	})
+}
+
+// lastNotificationID returns the ID of the most recently added notification,
+// or zero if none have been added yet.
+func lastNotificationID(tx *bolt.Tx) uint64 {
+	outbox := tx.Bucket(outboxBucket)
+	if outbox == nil {
+		return 0
+	}
+	idBytes := outbox.Get(lastNotificationKey)
+	if idBytes == nil {
+		return 0
+	}
+
+	return byteOrder.Uint64(idBytes)
+}
+
+// createOutboxBuckets returns the notifications and subscribers buckets of
+// the outbox, creating them if they don't yet exist.
+func createOutboxBuckets(tx *bolt.Tx) (*bolt.Bucket, *bolt.Bucket, error) {
+	outbox, err := tx.CreateBucketIfNotExists(outboxBucket)
+	if err != nil {
+		return nil, nil, err
+	}
+	notifications, err := outbox.CreateBucketIfNotExists(
+		outboxNotificationsBucket)
+	if err != nil {
+		return nil, nil, err
+	}
+	subscribers, err := outbox.CreateBucketIfNotExists(
+		outboxSubscribersBucket)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return notifications, subscribers, nil
+}
+
+// pruneOutbox deletes the notifications which have been acknowledged by
+// every subscriber. If there are no subscribers, then all notifications are
+// retained, as there's no one to deliver them to yet.
+func pruneOutbox(notifications, subscribers *bolt.Bucket) error {
+	var (
+		minAck uint64
+		found  bool
+	)
+	err := subscribers.ForEach(func(k, v []byte) error {
+		acked := byteOrder.Uint64(v)
+		if !found || acked < minAck {
+			minAck = acked
+			found = true
+		}
+		return nil
+	})
+	if err != nil || !found {
+		return err
+	}
+
+	// Collect the keys to delete before deleting them, as modifying a
+	// bucket while iterating over it with a cursor is unsafe.
+	var acked [][]byte
+	c := notifications.Cursor()
+	for k, _ := c.First(); k != nil &&
+		binary.BigEndian.Uint64(k) <= minAck; k, _ = c.Next() {
+
+		acked = append(acked, k)
+	}
+	for _, k := range acked {
+		if err := notifications.Delete(k); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// serializeNotification writes the passed notification to w. The ID of the
+// notification isn't written, as it's encoded within the notification's key.
+func serializeNotification(w io.Writer, n *Notification) error {
+	var scratch [9]byte
+	scratch[0] = byte(n.Type)
+	byteOrder.PutUint64(scratch[1:], uint64(n.Timestamp.UnixNano()))
+	if _, err := w.Write(scratch[:]); err != nil {
+		return err
+	}
+
+	return wire.WriteVarBytes(w, 0, n.Payload)
+}
+
+// deserializeNotification reads a notification written by
+// serializeNotification from r.
+func deserializeNotification(r io.Reader) (*Notification, error) {
+	var scratch [9]byte
+	if _, err := io.ReadFull(r, scratch[:]); err != nil {
+		return nil, err
+	}
+
+	payload, err := wire.ReadVarBytes(r, 0, 1<<20, "payload")
+	if err != nil {
+		return nil, err
+	}
+
+	return &Notification{
+		Type: NotificationType(scratch[0]),
+		Timestamp: time.Unix(0,
+			int64(byteOrder.Uint64(scratch[1:]))),
+		Payload: payload,
+	}, nil
+}
@@ -0,0 +1,132 @@
+package channeldb
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestNotificationOutbox(t *testing.T) {
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+
+	addNotification := func(nType NotificationType, payload string) {
+		n := &Notification{
+			Type:      nType,
+			Timestamp: time.Unix(1490000000, 0),
+			Payload:   []byte(payload),
+		}
+		if _, err := db.AddNotification(n); err != nil {
+			t.Fatalf("unable to add notification: %v", err)
+		}
+	}
+	assertPending := func(after uint64, expectedIDs ...uint64) {
+		notifications, err := db.FetchNotifications(after)
+		if err != nil {
+			t.Fatalf("unable to fetch notifications: %v", err)
+		}
+		if len(notifications) != len(expectedIDs) {
+			t.Fatalf("expected %v notifications, got %v",
+				len(expectedIDs), len(notifications))
+		}
+		for i, n := range notifications {
+			if n.ID != expectedIDs[i] {
+				t.Fatalf("expected notification %v, got %v",
+					expectedIDs[i], n.ID)
+			}
+		}
+	}
+
+	// Notifications added before any subscriber is registered should be
+	// retained.
+	addNotification(InvoiceSettledNotification, "invoice")
+	addNotification(ChannelClosedNotification, "close")
+	assertPending(0, 1, 2)
+
+	notifications, err := db.FetchNotifications(1)
+	if err != nil {
+		t.Fatalf("unable to fetch notifications: %v", err)
+	}
+	n := notifications[0]
+	if n.Type != ChannelClosedNotification ||
+		!n.Timestamp.Equal(time.Unix(1490000000, 0)) ||
+		!bytes.Equal(n.Payload, []byte("close")) {
+
+		t.Fatalf("notification mismatch: %v", n)
+	}
+
+	// New subscribers start after the last notification added.
+	acked, err := db.AddOutboxSubscriber("alice")
+	if err != nil {
+		t.Fatalf("unable to add subscriber: %v", err)
+	}
+	if acked != 2 {
+		t.Fatalf("expected new subscriber to start at 2, got %v", acked)
+	}
+
+	addNotification(BreachDetectedNotification, "breach")
+	addNotification(InvoiceSettledNotification, "invoice")
+	if _, err := db.AddOutboxSubscriber("bob"); err != nil {
+		t.Fatalf("unable to add subscriber: %v", err)
+	}
+	addNotification(ChannelClosedNotification, "close")
+
+	// Acknowledging a notification that doesn't exist yet, or on behalf
+	// of an unknown subscriber should fail.
+	if err := db.AckNotification("alice", 6); err != ErrNotificationNotFound {
+		t.Fatalf("expected ErrNotificationNotFound, got %v", err)
+	}
+	err = db.AckNotification("carol", 1)
+	if err != ErrOutboxSubscriberNotFound {
+		t.Fatalf("expected ErrOutboxSubscriberNotFound, got %v", err)
+	}
+
+	// Only the notifications acknowledged by both subscribers should be
+	// pruned.
+	if err := db.AckNotification("alice", 5); err != nil {
+		t.Fatalf("unable to ack notification: %v", err)
+	}
+	assertPending(0, 5)
+
+	// Acknowledgments are cumulative, so a stale one has no effect.
+	if err := db.AckNotification("alice", 3); err != nil {
+		t.Fatalf("unable to ack notification: %v", err)
+	}
+	subscribers, err := db.FetchOutboxSubscribers()
+	if err != nil {
+		t.Fatalf("unable to fetch subscribers: %v", err)
+	}
+	if len(subscribers) != 2 || subscribers["alice"] != 5 ||
+		subscribers["bob"] != 4 {
+
+		t.Fatalf("unexpected subscribers: %v", subscribers)
+	}
+
+	// Re-registering a subscriber should resume from its last
+	// acknowledgment.
+	acked, err = db.AddOutboxSubscriber("bob")
+	if err != nil {
+		t.Fatalf("unable to add subscriber: %v", err)
+	}
+	if acked != 4 {
+		t.Fatalf("expected subscriber to resume at 4, got %v", acked)
+	}
+
+	// Removing the lagging subscriber releases its notifications.
+	if err := db.RemoveOutboxSubscriber("bob"); err != nil {
+		t.Fatalf("unable to remove subscriber: %v", err)
+	}
+	assertPending(0)
+	err = db.RemoveOutboxSubscriber("bob")
+	if err != ErrOutboxSubscriberNotFound {
+		t.Fatalf("expected ErrOutboxSubscriberNotFound, got %v", err)
+	}
+
+	// IDs should keep increasing even though all notifications have been
+	// pruned.
+	addNotification(InvoiceSettledNotification, "invoice")
+	assertPending(5, 6)
+}
@@ -0,0 +1,197 @@
+package channeldb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+var (
+	// paymentAttemptBucket is the name of the bucket within the database
+	// that stores each attempt made at sending a payment, whether or not
+	// the payment ultimately succeeded. Within the bucket, each payment
+	// has a sub-bucket keyed by its payment hash. The attempts within a
+	// payment's bucket are keyed by the big-endian encoding of a sequence
+	// number, so a cursor scan yields them in the order they were made.
+	paymentAttemptBucket = []byte("payment-attempts")
+)
+
+// PaymentAttempt is the record of a single attempt at sending a payment.
+type PaymentAttempt struct {
+	// AttemptTime is the time the attempt was made.
+	AttemptTime time.Time
+
+	// FeeLimit is the fee limit the attempt was made under. A value of
+	// zero indicates that the fee was unbounded.
+	FeeLimit btcutil.Amount
+
+	// Path is the compressed public key of each node of the route the
+	// attempt was sent along, excluding our own. It's empty if no route
+	// satisfying the payment's limits was found.
+	Path [][33]byte
+
+	// Fee is the total fee of the route the attempt was sent along.
+	Fee btcutil.Amount
+
+	// TimeLock is the total time lock of the route the attempt was sent
+	// along.
+	TimeLock uint32
+
+	// Error describes why the attempt failed. It's empty if the attempt
+	// succeeded.
+	Error string
+}
+
+// AddPaymentAttempt records an attempt at sending the payment with the passed
+// payment hash.
+func (d *DB) AddPaymentAttempt(paymentHash [32]byte,
+	attempt *PaymentAttempt) error {
+
+	var b bytes.Buffer
+	if err := serializePaymentAttempt(&b, attempt); err != nil {
+		return err
+	}
+
+	return d.Update(func(tx *bolt.Tx) error {
+		attempts, err := tx.CreateBucketIfNotExists(paymentAttemptBucket)
+		if err != nil {
+			return err
+		}
+		paymentAttempts, err := attempts.CreateBucketIfNotExists(
+			paymentHash[:])
+		if err != nil {
+			return err
+		}
+
+		seqNum, err := paymentAttempts.NextSequence()
+		if err != nil {
+			return err
+		}
+
+		var key [8]byte
+		binary.BigEndian.PutUint64(key[:], seqNum)
+		return paymentAttempts.Put(key[:], b.Bytes())
+	})
+}
+
+// FetchPaymentAttempts returns each attempt made at sending the payment with
+// the passed payment hash, in the order they were made.
+func (d *DB) FetchPaymentAttempts(paymentHash [32]byte) ([]*PaymentAttempt,
+	error) {
+
+	var attempts []*PaymentAttempt
+	err := d.View(func(tx *bolt.Tx) error {
+		attemptBucket := tx.Bucket(paymentAttemptBucket)
+		if attemptBucket == nil {
+			return nil
+		}
+		paymentAttempts := attemptBucket.Bucket(paymentHash[:])
+		if paymentAttempts == nil {
+			return nil
+		}
+
+		return paymentAttempts.ForEach(func(k, v []byte) error {
+			attempt, err := deserializePaymentAttempt(
+				bytes.NewReader(v))
+			if err != nil {
+				return err
+			}
+
+			attempts = append(attempts, attempt)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return attempts, nil
+}
+
+func serializePaymentAttempt(w io.Writer, a *PaymentAttempt) error {
+	var scratch [8]byte
+
+	byteOrder.PutUint64(scratch[:], uint64(a.AttemptTime.UnixNano()))
+	if _, err := w.Write(scratch[:]); err != nil {
+		return err
+	}
+
+	byteOrder.PutUint64(scratch[:], uint64(a.FeeLimit))
+	if _, err := w.Write(scratch[:]); err != nil {
+		return err
+	}
+
+	byteOrder.PutUint32(scratch[:4], uint32(len(a.Path)))
+	if _, err := w.Write(scratch[:4]); err != nil {
+		return err
+	}
+	for _, hop := range a.Path {
+		if _, err := w.Write(hop[:]); err != nil {
+			return err
+		}
+	}
+
+	byteOrder.PutUint64(scratch[:], uint64(a.Fee))
+	if _, err := w.Write(scratch[:]); err != nil {
+		return err
+	}
+
+	byteOrder.PutUint32(scratch[:4], a.TimeLock)
+	if _, err := w.Write(scratch[:4]); err != nil {
+		return err
+	}
+
+	return wire.WriteVarString(w, 0, a.Error)
+}
+
+func deserializePaymentAttempt(r io.Reader) (*PaymentAttempt, error) {
+	var scratch [8]byte
+
+	a := &PaymentAttempt{}
+
+	if _, err := io.ReadFull(r, scratch[:]); err != nil {
+		return nil, err
+	}
+	a.AttemptTime = time.Unix(0, int64(byteOrder.Uint64(scratch[:])))
+
+	if _, err := io.ReadFull(r, scratch[:]); err != nil {
+		return nil, err
+	}
+	a.FeeLimit = btcutil.Amount(byteOrder.Uint64(scratch[:]))
+
+	if _, err := io.ReadFull(r, scratch[:4]); err != nil {
+		return nil, err
+	}
+	pathLen := byteOrder.Uint32(scratch[:4])
+	if pathLen != 0 {
+		a.Path = make([][33]byte, pathLen)
+	}
+	for i := range a.Path {
+		if _, err := io.ReadFull(r, a.Path[i][:]); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := io.ReadFull(r, scratch[:]); err != nil {
+		return nil, err
+	}
+	a.Fee = btcutil.Amount(byteOrder.Uint64(scratch[:]))
+
+	if _, err := io.ReadFull(r, scratch[:4]); err != nil {
+		return nil, err
+	}
+	a.TimeLock = byteOrder.Uint32(scratch[:4])
+
+	errString, err := wire.ReadVarString(r, 0)
+	if err != nil {
+		return nil, err
+	}
+	a.Error = errString
+
+	return a, nil
+}
@@ -0,0 +1,96 @@
+package channeldb
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// TestPaymentAttempts tests that the attempts made at sending a payment are
+// returned in the order they were recorded, are kept apart from those of
+// other payments, and are deleted along with the payments.
+func TestPaymentAttempts(t *testing.T) {
+	db, cleanUp, err := makeTestDB()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+	defer cleanUp()
+
+	paymentHash := [32]byte{1}
+	otherHash := [32]byte{2}
+
+	attempts, err := db.FetchPaymentAttempts(paymentHash)
+	if err != nil {
+		t.Fatalf("unable to fetch payment attempts: %v", err)
+	}
+	if len(attempts) != 0 {
+		t.Fatalf("expected no attempts, got %v", len(attempts))
+	}
+
+	expected := []*PaymentAttempt{
+		{
+			AttemptTime: time.Unix(1500000000, 0),
+			FeeLimit:    100,
+			Path:        [][33]byte{{2, 1}, {3, 2}},
+			Fee:         10,
+			TimeLock:    144,
+			Error:       "temporary channel failure",
+		},
+		{
+			AttemptTime: time.Unix(1500000005, 0),
+			FeeLimit:    150,
+			Error:       "unable to find a path to destination",
+		},
+		{
+			AttemptTime: time.Unix(1500000010, 0),
+			FeeLimit:    200,
+			Path:        [][33]byte{{2, 3}},
+			Fee:         0,
+			TimeLock:    9,
+		},
+	}
+	for _, attempt := range expected {
+		err := db.AddPaymentAttempt(paymentHash, attempt)
+		if err != nil {
+			t.Fatalf("unable to add payment attempt: %v", err)
+		}
+	}
+	err = db.AddPaymentAttempt(otherHash, &PaymentAttempt{
+		AttemptTime: time.Unix(1500000001, 0),
+	})
+	if err != nil {
+		t.Fatalf("unable to add payment attempt: %v", err)
+	}
+
+	attempts, err = db.FetchPaymentAttempts(paymentHash)
+	if err != nil {
+		t.Fatalf("unable to fetch payment attempts: %v", err)
+	}
+	if len(attempts) != len(expected) {
+		t.Fatalf("expected %v attempts, got %v", len(expected),
+			len(attempts))
+	}
+	for i, attempt := range attempts {
+		if !attempt.AttemptTime.Equal(expected[i].AttemptTime) {
+			t.Fatalf("attempt #%v: expected time %v, got %v", i,
+				expected[i].AttemptTime, attempt.AttemptTime)
+		}
+		attempt.AttemptTime = expected[i].AttemptTime
+		if !reflect.DeepEqual(attempt, expected[i]) {
+			t.Fatalf("attempt #%v: expected %+v, got %+v", i,
+				expected[i], attempt)
+		}
+	}
+
+	if err := db.DeleteAllPayments(); err != nil {
+		t.Fatalf("unable to delete payments: %v", err)
+	}
+	attempts, err = db.FetchPaymentAttempts(otherHash)
+	if err != nil {
+		t.Fatalf("unable to fetch payment attempts: %v", err)
+	}
+	if len(attempts) != 0 {
+		t.Fatalf("expected no attempts after deletion, got %v",
+			len(attempts))
+	}
+}
@@ -0,0 +1,154 @@
+package channeldb
+
+import (
+	"encoding/binary"
+	"io"
+
+	"github.com/boltdb/bolt"
+)
+
+var (
+	// paymentIDBucket is the name of the bucket within the database that
+	// records each payment identifier chosen by a client to make the
+	// dispatch of a payment idempotent. Each entry is keyed by the
+	// big-endian encoding of the identifier, and stores the status of the
+	// payment, followed by its payment hash, and, once it has succeeded,
+	// its preimage.
+	paymentIDBucket = []byte("payment-ids")
+)
+
+// PaymentIDStatus is the status of a payment dispatched under a payment
+// identifier.
+type PaymentIDStatus uint8
+
+const (
+	// PaymentIDInFlight denotes a payment which has been dispatched, but
+	// hasn't yet been observed to succeed.
+	PaymentIDInFlight PaymentIDStatus = 0
+
+	// PaymentIDSucceeded denotes a payment which has succeeded.
+	PaymentIDSucceeded PaymentIDStatus = 1
+)
+
+// String returns a human readable version of the status.
+func (s PaymentIDStatus) String() string {
+	switch s {
+	case PaymentIDInFlight:
+		return "in-flight"
+	case PaymentIDSucceeded:
+		return "succeeded"
+	default:
+		return "unknown"
+	}
+}
+
+// PaymentIDRecord is the record of a payment dispatched under a payment
+// identifier.
+type PaymentIDRecord struct {
+	// Status is the status of the payment.
+	Status PaymentIDStatus
+
+	// PaymentHash is the payment hash of the payment.
+	PaymentHash [32]byte
+
+	// Preimage is the preimage of the payment, which is only known once
+	// the payment has succeeded.
+	Preimage [32]byte
+}
+
+// AddPaymentID records that a payment with the passed payment hash is being
+// dispatched under the passed payment identifier. If the identifier has
+// already been used, then nothing is written and the existing record is
+// returned instead, so the caller may avoid dispatching the payment twice.
+func (d *DB) AddPaymentID(id uint64, paymentHash [32]byte) (*PaymentIDRecord, error) {
+	var existing *PaymentIDRecord
+	err := d.Update(func(tx *bolt.Tx) error {
+		ids, err := tx.CreateBucketIfNotExists(paymentIDBucket)
+		if err != nil {
+			return err
+		}
+
+		var idKey [8]byte
+		binary.BigEndian.PutUint64(idKey[:], id)
+		if v := ids.Get(idKey[:]); v != nil {
+			existing, err = deserializePaymentIDRecord(v)
+			return err
+		}
+
+		return ids.Put(idKey[:], serializePaymentIDRecord(
+			&PaymentIDRecord{
+				Status:      PaymentIDInFlight,
+				PaymentHash: paymentHash,
+			},
+		))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return existing, nil
+}
+
+// SettlePaymentID records that the payment dispatched under the passed
+// payment identifier has succeeded with the passed preimage.
+func (d *DB) SettlePaymentID(id uint64, preimage [32]byte) error {
+	return d.Update(func(tx *bolt.Tx) error {
+		ids := tx.Bucket(paymentIDBucket)
+		if ids == nil {
+			return ErrPaymentIDNotFound
+		}
+
+		var idKey [8]byte
+		binary.BigEndian.PutUint64(idKey[:], id)
+		v := ids.Get(idKey[:])
+		if v == nil {
+			return ErrPaymentIDNotFound
+		}
+
+		record, err := deserializePaymentIDRecord(v)
+		if err != nil {
+			return err
+		}
+		record.Status = PaymentIDSucceeded
+		record.Preimage = preimage
+
+		return ids.Put(idKey[:], serializePaymentIDRecord(record))
+	})
+}
+
+// DeletePaymentID removes the record of the passed payment identifier, which
+// allows a payment which has definitively failed to be dispatched anew under
+// the same identifier.
+func (d *DB) DeletePaymentID(id uint64) error {
+	return d.Update(func(tx *bolt.Tx) error {
+		ids := tx.Bucket(paymentIDBucket)
+		if ids == nil {
+			return nil
+		}
+
+		var idKey [8]byte
+		binary.BigEndian.PutUint64(idKey[:], id)
+		return ids.Delete(idKey[:])
+	})
+}
+
+func serializePaymentIDRecord(r *PaymentIDRecord) []byte {
+	b := make([]byte, 1+32+32)
+	b[0] = byte(r.Status)
+	copy(b[1:33], r.PaymentHash[:])
+	copy(b[33:], r.Preimage[:])
+	return b
+}
+
+func deserializePaymentIDRecord(b []byte) (*PaymentIDRecord, error) {
+	if len(b) != 1+32+32 {
+		return nil, io.ErrUnexpectedEOF
+	}
+
+	r := &PaymentIDRecord{
+		Status: PaymentIDStatus(b[0]),
+	}
+	copy(r.PaymentHash[:], b[1:33])
+	copy(r.Preimage[:], b[33:])
+	return r, nil
+}
@@ -0,0 +1,67 @@
+package channeldb
+
+import (
+	"crypto/sha256"
+	"testing"
+)
+
+// TestPaymentIDs tests that a payment identifier may only be used once, that
+// its record tracks the outcome of the payment, and that it's released once
+// deleted.
+func TestPaymentIDs(t *testing.T) {
+	db, cleanUp, err := makeTestDB()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+	defer cleanUp()
+
+	preimage := [32]byte{1}
+	paymentHash := sha256.Sum256(preimage[:])
+
+	existing, err := db.AddPaymentID(7, paymentHash)
+	if err != nil {
+		t.Fatalf("unable to add payment id: %v", err)
+	}
+	if existing != nil {
+		t.Fatalf("expected unused payment id, got %v", existing)
+	}
+
+	// A replay of the identifier should be detected, and report the
+	// in-flight payment.
+	existing, err = db.AddPaymentID(7, paymentHash)
+	if err != nil {
+		t.Fatalf("unable to add payment id: %v", err)
+	}
+	if existing == nil || existing.Status != PaymentIDInFlight ||
+		existing.PaymentHash != paymentHash {
+		t.Fatalf("expected in-flight payment, got %v", existing)
+	}
+
+	if err := db.SettlePaymentID(7, preimage); err != nil {
+		t.Fatalf("unable to settle payment id: %v", err)
+	}
+	existing, err = db.AddPaymentID(7, paymentHash)
+	if err != nil {
+		t.Fatalf("unable to add payment id: %v", err)
+	}
+	if existing == nil || existing.Status != PaymentIDSucceeded ||
+		existing.Preimage != preimage {
+		t.Fatalf("expected succeeded payment, got %v", existing)
+	}
+
+	if err := db.SettlePaymentID(8, preimage); err != ErrPaymentIDNotFound {
+		t.Fatalf("expected ErrPaymentIDNotFound, got %v", err)
+	}
+
+	// Once deleted, the identifier may be used again.
+	if err := db.DeletePaymentID(7); err != nil {
+		t.Fatalf("unable to delete payment id: %v", err)
+	}
+	existing, err = db.AddPaymentID(7, paymentHash)
+	if err != nil {
+		t.Fatalf("unable to add payment id: %v", err)
+	}
+	if existing != nil {
+		t.Fatalf("expected released payment id, got %v", existing)
+	}
+}
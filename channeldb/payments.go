@@ -118,7 +118,8 @@ func (db *DB) FetchAllPayments() ([]*OutgoingPayment, error) {
 	return payments, nil
 }
 
-// DeleteAllPayments deletes all payments from DB.
+// DeleteAllPayments deletes all payments from DB, along with the record of
+// each attempt made at sending them.
 func (db *DB) DeleteAllPayments() error {
 	return db.Update(func(tx *bolt.Tx) error {
 		err := tx.DeleteBucket(paymentBucket)
@@ -126,6 +127,11 @@ func (db *DB) DeleteAllPayments() error {
 			return err
 		}
 
+		err = tx.DeleteBucket(paymentAttemptBucket)
+		if err != nil && err != bolt.ErrBucketNotFound {
+			return err
+		}
+
 		_, err = tx.CreateBucket(paymentBucket)
 		return err
 	})
@@ -0,0 +1,291 @@
+package channeldb
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/boltdb/bolt"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcutil"
+)
+
+var (
+	// peerSettingsBucket is the name of the bucket within the database
+	// that stores the settings the operator has set for individual peers,
+	// overriding those applied to all other peers.
+	//
+	// maps: peerPubKey -> serialized PeerSettings
+	peerSettingsBucket = []byte("peer-settings")
+)
+
+// GossipSyncMode determines whether we synchronize the channel graph with a
+// peer.
+type GossipSyncMode uint8
+
+const (
+	// GossipSyncDefault leaves the peer to the regular selection of active
+	// gossip syncers.
+	GossipSyncDefault GossipSyncMode = 0
+
+	// GossipSyncActive always actively synchronizes the channel graph
+	// with the peer, without occupying one of the limited active syncer
+	// slots.
+	GossipSyncActive GossipSyncMode = 1
+
+	// GossipSyncPassive never actively synchronizes the channel graph
+	// with the peer.
+	GossipSyncPassive GossipSyncMode = 2
+)
+
+// String returns a human readable name for the sync mode.
+func (m GossipSyncMode) String() string {
+	switch m {
+	case GossipSyncDefault:
+		return "default"
+	case GossipSyncActive:
+		return "active"
+	case GossipSyncPassive:
+		return "passive"
+	default:
+		return "unknown"
+	}
+}
+
+// PeerFeePolicy is the routing policy we advertise for the channels we
+// maintain with a particular peer.
+type PeerFeePolicy struct {
+	// TimeLockDelta is the number of blocks we require the time lock of
+	// HTLCs forwarded over the channel to be reduced by.
+	TimeLockDelta uint16
+
+	// MinHTLC is the smallest HTLC we forward over the channel.
+	MinHTLC btcutil.Amount
+
+	// FeeBaseMSat is the base fee, in milli-satoshis, charged for each
+	// HTLC forwarded over the channel.
+	FeeBaseMSat btcutil.Amount
+
+	// FeeProportionalMillionths is the fee charged for each millionth of
+	// the value of the HTLCs forwarded over the channel.
+	FeeProportionalMillionths btcutil.Amount
+}
+
+// PeerSettings are the settings the operator has set for a particular peer,
+// allowing important partners to be treated differently from anonymous
+// peers. Each setting left at its zero value defers to the setting applied
+// to all peers.
+type PeerSettings struct {
+	// FeePolicy, if non-nil, is the routing policy advertised for the
+	// channels we maintain with the peer.
+	FeePolicy *PeerFeePolicy
+
+	// MaxChannels, if non-zero, overrides the funding policy's maximum
+	// number of channels, whether pending or open, we maintain with the
+	// peer.
+	MaxChannels uint32
+
+	// GossipSyncMode determines whether we synchronize the channel graph
+	// with the peer.
+	GossipSyncMode GossipSyncMode
+
+	// MaxPendingHTLCs, if non-zero, is the largest number of HTLCs we
+	// forward to the peer which may be pending at once.
+	MaxPendingHTLCs uint32
+
+	// MaxHTLCValue, if non-zero, is the largest HTLC we forward to the
+	// peer.
+	MaxHTLCValue btcutil.Amount
+}
+
+// Validate checks that each of the settings is within range.
+func (s *PeerSettings) Validate() error {
+	switch {
+	case s.GossipSyncMode > GossipSyncPassive:
+		return fmt.Errorf("unknown gossip sync mode %v",
+			s.GossipSyncMode)
+
+	case s.MaxHTLCValue < 0:
+		return fmt.Errorf("max htlc value can't be negative")
+
+	case s.FeePolicy == nil:
+		return nil
+
+	case s.FeePolicy.MinHTLC < 0 || s.FeePolicy.FeeBaseMSat < 0 ||
+		s.FeePolicy.FeeProportionalMillionths < 0:
+		return fmt.Errorf("fee policy can't be negative")
+	}
+
+	return nil
+}
+
+// FetchAllPeerSettings returns the settings set for each peer, keyed by the
+// peer's serialized public key.
+func (d *DB) FetchAllPeerSettings() (map[[33]byte]*PeerSettings, error) {
+	allSettings := make(map[[33]byte]*PeerSettings)
+	err := d.View(func(tx *bolt.Tx) error {
+		settingsBucket := tx.Bucket(peerSettingsBucket)
+		if settingsBucket == nil {
+			return nil
+		}
+
+		return settingsBucket.ForEach(func(k, v []byte) error {
+			if len(k) != 33 {
+				return fmt.Errorf("invalid peer key of %v "+
+					"bytes", len(k))
+			}
+
+			settings, err := deserializePeerSettings(
+				bytes.NewReader(v))
+			if err != nil {
+				return err
+			}
+
+			var peer [33]byte
+			copy(peer[:], k)
+			allSettings[peer] = settings
+
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return allSettings, nil
+}
+
+// PutPeerSettings sets the settings of the passed peer, replacing any
+// settings previously set.
+func (d *DB) PutPeerSettings(peer *btcec.PublicKey,
+	settings *PeerSettings) error {
+
+	if err := settings.Validate(); err != nil {
+		return err
+	}
+
+	var b bytes.Buffer
+	if err := serializePeerSettings(&b, settings); err != nil {
+		return err
+	}
+
+	return d.Update(func(tx *bolt.Tx) error {
+		settingsBucket, err := tx.CreateBucketIfNotExists(
+			peerSettingsBucket)
+		if err != nil {
+			return err
+		}
+
+		return settingsBucket.Put(peer.SerializeCompressed(), b.Bytes())
+	})
+}
+
+// DeletePeerSettings deletes the settings of the passed peer, so it's
+// treated like any other peer.
+func (d *DB) DeletePeerSettings(peer *btcec.PublicKey) error {
+	return d.Update(func(tx *bolt.Tx) error {
+		settingsBucket := tx.Bucket(peerSettingsBucket)
+		if settingsBucket == nil {
+			return nil
+		}
+
+		return settingsBucket.Delete(peer.SerializeCompressed())
+	})
+}
+
+func serializePeerSettings(w io.Writer, s *PeerSettings) error {
+	var scratch [8]byte
+
+	if s.FeePolicy == nil {
+		if _, err := w.Write([]byte{0}); err != nil {
+			return err
+		}
+	} else {
+		if _, err := w.Write([]byte{1}); err != nil {
+			return err
+		}
+
+		byteOrder.PutUint16(scratch[:2], s.FeePolicy.TimeLockDelta)
+		if _, err := w.Write(scratch[:2]); err != nil {
+			return err
+		}
+		for _, amt := range []btcutil.Amount{
+			s.FeePolicy.MinHTLC,
+			s.FeePolicy.FeeBaseMSat,
+			s.FeePolicy.FeeProportionalMillionths,
+		} {
+			byteOrder.PutUint64(scratch[:], uint64(amt))
+			if _, err := w.Write(scratch[:]); err != nil {
+				return err
+			}
+		}
+	}
+
+	byteOrder.PutUint32(scratch[:4], s.MaxChannels)
+	if _, err := w.Write(scratch[:4]); err != nil {
+		return err
+	}
+
+	if _, err := w.Write([]byte{byte(s.GossipSyncMode)}); err != nil {
+		return err
+	}
+
+	byteOrder.PutUint32(scratch[:4], s.MaxPendingHTLCs)
+	if _, err := w.Write(scratch[:4]); err != nil {
+		return err
+	}
+
+	byteOrder.PutUint64(scratch[:], uint64(s.MaxHTLCValue))
+	_, err := w.Write(scratch[:])
+	return err
+}
+
+func deserializePeerSettings(r io.Reader) (*PeerSettings, error) {
+	var scratch [8]byte
+	s := &PeerSettings{}
+
+	if _, err := io.ReadFull(r, scratch[:1]); err != nil {
+		return nil, err
+	}
+	if scratch[0] == 1 {
+		s.FeePolicy = &PeerFeePolicy{}
+
+		if _, err := io.ReadFull(r, scratch[:2]); err != nil {
+			return nil, err
+		}
+		s.FeePolicy.TimeLockDelta = byteOrder.Uint16(scratch[:2])
+
+		for _, amt := range []*btcutil.Amount{
+			&s.FeePolicy.MinHTLC,
+			&s.FeePolicy.FeeBaseMSat,
+			&s.FeePolicy.FeeProportionalMillionths,
+		} {
+			if _, err := io.ReadFull(r, scratch[:]); err != nil {
+				return nil, err
+			}
+			*amt = btcutil.Amount(byteOrder.Uint64(scratch[:]))
+		}
+	}
+
+	if _, err := io.ReadFull(r, scratch[:4]); err != nil {
+		return nil, err
+	}
+	s.MaxChannels = byteOrder.Uint32(scratch[:4])
+
+	if _, err := io.ReadFull(r, scratch[:1]); err != nil {
+		return nil, err
+	}
+	s.GossipSyncMode = GossipSyncMode(scratch[0])
+
+	if _, err := io.ReadFull(r, scratch[:4]); err != nil {
+		return nil, err
+	}
+	s.MaxPendingHTLCs = byteOrder.Uint32(scratch[:4])
+
+	if _, err := io.ReadFull(r, scratch[:]); err != nil {
+		return nil, err
+	}
+	s.MaxHTLCValue = btcutil.Amount(byteOrder.Uint64(scratch[:]))
+
+	return s, nil
+}
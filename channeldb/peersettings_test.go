@@ -0,0 +1,89 @@
+package channeldb
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/roasbeef/btcd/btcec"
+)
+
+// TestPeerSettings tests that the settings of each peer can be set, replaced
+// and deleted independently of one another, and that invalid settings are
+// rejected.
+func TestPeerSettings(t *testing.T) {
+	db, cleanUp, err := makeTestDB()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+	defer cleanUp()
+
+	_, otherPub := btcec.PrivKeyFromBytes(btcec.S256(), key[1:])
+	var peer, otherPeer [33]byte
+	copy(peer[:], pubKey.SerializeCompressed())
+	copy(otherPeer[:], otherPub.SerializeCompressed())
+
+	allSettings, err := db.FetchAllPeerSettings()
+	if err != nil {
+		t.Fatalf("unable to fetch peer settings: %v", err)
+	}
+	if len(allSettings) != 0 {
+		t.Fatalf("expected no peer settings, got %v", len(allSettings))
+	}
+
+	settings := &PeerSettings{
+		FeePolicy: &PeerFeePolicy{
+			TimeLockDelta:             40,
+			MinHTLC:                   1,
+			FeeBaseMSat:               1000,
+			FeeProportionalMillionths: 10,
+		},
+		MaxChannels:     5,
+		GossipSyncMode:  GossipSyncActive,
+		MaxPendingHTLCs: 30,
+		MaxHTLCValue:    100000,
+	}
+	otherSettings := &PeerSettings{
+		GossipSyncMode: GossipSyncPassive,
+	}
+	if err := db.PutPeerSettings(pubKey, otherSettings); err != nil {
+		t.Fatalf("unable to put peer settings: %v", err)
+	}
+	if err := db.PutPeerSettings(pubKey, settings); err != nil {
+		t.Fatalf("unable to put peer settings: %v", err)
+	}
+	if err := db.PutPeerSettings(otherPub, otherSettings); err != nil {
+		t.Fatalf("unable to put peer settings: %v", err)
+	}
+
+	expected := map[[33]byte]*PeerSettings{
+		peer:      settings,
+		otherPeer: otherSettings,
+	}
+	allSettings, err = db.FetchAllPeerSettings()
+	if err != nil {
+		t.Fatalf("unable to fetch peer settings: %v", err)
+	}
+	if !reflect.DeepEqual(allSettings, expected) {
+		t.Fatalf("expected %v, got %v", spew.Sdump(expected),
+			spew.Sdump(allSettings))
+	}
+
+	if err := db.DeletePeerSettings(pubKey); err != nil {
+		t.Fatalf("unable to delete peer settings: %v", err)
+	}
+	delete(expected, peer)
+	allSettings, err = db.FetchAllPeerSettings()
+	if err != nil {
+		t.Fatalf("unable to fetch peer settings: %v", err)
+	}
+	if !reflect.DeepEqual(allSettings, expected) {
+		t.Fatalf("expected %v, got %v", spew.Sdump(expected),
+			spew.Sdump(allSettings))
+	}
+
+	err = db.PutPeerSettings(pubKey, &PeerSettings{GossipSyncMode: 3})
+	if err == nil {
+		t.Fatalf("expected unknown gossip sync mode to be rejected")
+	}
+}
@@ -0,0 +1,55 @@
+package channeldb
+
+import (
+	"github.com/boltdb/bolt"
+	"github.com/roasbeef/btcd/btcec"
+)
+
+var (
+	// peerStorageBucket is the name of the bucket within the database
+	// that stores the backup blobs our peers have asked us to hold on
+	// their behalf. Each entry is keyed by the compressed public key of
+	// the peer, and stores the latest blob it sent us.
+	peerStorageBucket = []byte("peer-storage")
+)
+
+// PutPeerStorage stores the passed backup blob on behalf of the target peer,
+// replacing any blob previously stored for it.
+func (d *DB) PutPeerStorage(nodePub *btcec.PublicKey, blob []byte) error {
+	return d.Update(func(tx *bolt.Tx) error {
+		storage, err := tx.CreateBucketIfNotExists(peerStorageBucket)
+		if err != nil {
+			return err
+		}
+
+		return storage.Put(nodePub.SerializeCompressed(), blob)
+	})
+}
+
+// FetchPeerStorage returns the backup blob stored on behalf of the target
+// peer. If the peer never asked us to store a blob, then
+// ErrPeerStorageNotFound is returned.
+func (d *DB) FetchPeerStorage(nodePub *btcec.PublicKey) ([]byte, error) {
+	var blob []byte
+	err := d.View(func(tx *bolt.Tx) error {
+		storage := tx.Bucket(peerStorageBucket)
+		if storage == nil {
+			return ErrPeerStorageNotFound
+		}
+		storedBlob := storage.Get(nodePub.SerializeCompressed())
+		if storedBlob == nil {
+			return ErrPeerStorageNotFound
+		}
+
+		// The returned slice is only valid for the lifetime of the
+		// transaction, so it must be copied.
+		blob = make([]byte, len(storedBlob))
+		copy(blob, storedBlob)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return blob, nil
+}
@@ -0,0 +1,37 @@
+package channeldb
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/roasbeef/btcd/btcec"
+)
+
+func TestPeerStorage(t *testing.T) {
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+
+	_, pub := btcec.PrivKeyFromBytes(btcec.S256(), key[:])
+
+	// Nothing has been stored for the peer yet.
+	if _, err := db.FetchPeerStorage(pub); err != ErrPeerStorageNotFound {
+		t.Fatalf("expected ErrPeerStorageNotFound, got: %v", err)
+	}
+
+	// Each stored blob should replace the last.
+	for _, blob := range [][]byte{{1, 2, 3}, {4, 5}} {
+		if err := db.PutPeerStorage(pub, blob); err != nil {
+			t.Fatalf("unable to store blob: %v", err)
+		}
+		stored, err := db.FetchPeerStorage(pub)
+		if err != nil {
+			t.Fatalf("unable to fetch blob: %v", err)
+		}
+		if !bytes.Equal(stored, blob) {
+			t.Fatalf("expected blob %x, got %x", blob, stored)
+		}
+	}
+}
@@ -0,0 +1,212 @@
+package channeldb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/wire"
+)
+
+var (
+	// signingAuditBucket is the name of the bucket within the database
+	// that stores the audit log of each signature produced by the node.
+	// Events are keyed by the big-endian encoding of their timestamp in
+	// nanoseconds, so a cursor scan yields them in chronological order.
+	//
+	// NOTE: The log is append-only, no events are ever removed from it.
+	signingAuditBucket = []byte("signing-audit")
+)
+
+// SigningEventType denotes the purpose of a signature within the audit log.
+type SigningEventType uint8
+
+const (
+	// CommitmentSigEvent is recorded when we sign a commitment
+	// transaction spending a channel's funding output.
+	CommitmentSigEvent SigningEventType = 0
+
+	// CloseSigEvent is recorded when we sign a cooperative closure
+	// transaction spending a channel's funding output.
+	CloseSigEvent SigningEventType = 1
+
+	// SweepSigEvent is recorded when we sign a transaction sweeping an
+	// output of a commitment transaction, such as a delayed output, an
+	// HTLC, or a breached output.
+	SweepSigEvent SigningEventType = 2
+
+	// WalletSigEvent is recorded when we sign a transaction spending one
+	// of the wallet's own outputs, such as a funding transaction.
+	WalletSigEvent SigningEventType = 3
+)
+
+// String returns a human readable name for the event type.
+func (s SigningEventType) String() string {
+	switch s {
+	case CommitmentSigEvent:
+		return "Commitment"
+	case CloseSigEvent:
+		return "Close"
+	case SweepSigEvent:
+		return "Sweep"
+	case WalletSigEvent:
+		return "Wallet"
+	default:
+		return "Unknown"
+	}
+}
+
+// SigningEvent is a single signature within the audit log.
+type SigningEvent struct {
+	// Type is the purpose of the signature.
+	Type SigningEventType
+
+	// Timestamp is the time the signature was produced.
+	Timestamp time.Time
+
+	// Outpoint is the output spent by the signed input. For commitment
+	// and closure transactions, this is the channel's funding outpoint.
+	Outpoint wire.OutPoint
+
+	// PubKey is the key which produced the signature. If the key wasn't
+	// known to the caller, as with the wallet's own outputs, then it's
+	// nil.
+	PubKey *btcec.PublicKey
+
+	// DataHash is the hash of the data which was signed.
+	DataHash [32]byte
+}
+
+// AddSigningEvent appends an event to the signing audit log. If an event
+// already exists with an identical timestamp, then the timestamp of the new
+// event is advanced until it's unique.
+func (d *DB) AddSigningEvent(event *SigningEvent) error {
+	var b bytes.Buffer
+	if err := serializeSigningEvent(&b, event); err != nil {
+		return err
+	}
+
+	return d.Update(func(tx *bolt.Tx) error {
+		audit, err := tx.CreateBucketIfNotExists(signingAuditBucket)
+		if err != nil {
+			return err
+		}
+
+		var eventKey [8]byte
+		timestamp := uint64(event.Timestamp.UnixNano())
+		for {
+			binary.BigEndian.PutUint64(eventKey[:], timestamp)
+			if audit.Get(eventKey[:]) == nil {
+				break
+			}
+			timestamp++
+		}
+
+		return audit.Put(eventKey[:], b.Bytes())
+	})
+}
+
+// FetchSigningEvents returns the events within the signing audit log which
+// occurred within the range [start, end], in chronological order. A zero
+// start or end time leaves that side of the range unbounded.
+func (d *DB) FetchSigningEvents(start, end time.Time) ([]*SigningEvent,
+	error) {
+
+	var startKey, endKey [8]byte
+	if !start.IsZero() {
+		binary.BigEndian.PutUint64(startKey[:], uint64(start.UnixNano()))
+	}
+	binary.BigEndian.PutUint64(endKey[:], math.MaxInt64)
+	if !end.IsZero() {
+		binary.BigEndian.PutUint64(endKey[:], uint64(end.UnixNano()))
+	}
+
+	var signingEvents []*SigningEvent
+	err := d.View(func(tx *bolt.Tx) error {
+		audit := tx.Bucket(signingAuditBucket)
+		if audit == nil {
+			return nil
+		}
+
+		c := audit.Cursor()
+		for k, v := c.Seek(startKey[:]); k != nil &&
+			bytes.Compare(k, endKey[:]) <= 0; k, v = c.Next() {
+
+			event, err := deserializeSigningEvent(bytes.NewReader(v))
+			if err != nil {
+				return err
+			}
+			event.Timestamp = time.Unix(0,
+				int64(binary.BigEndian.Uint64(k)))
+
+			signingEvents = append(signingEvents, event)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return signingEvents, nil
+}
+
+// serializeSigningEvent writes the passed event to w. The timestamp of the
+// event isn't written, as it's encoded within the event's key.
+func serializeSigningEvent(w io.Writer, e *SigningEvent) error {
+	if _, err := w.Write([]byte{byte(e.Type)}); err != nil {
+		return err
+	}
+
+	if err := writeOutpoint(w, &e.Outpoint); err != nil {
+		return err
+	}
+
+	var pubKey []byte
+	if e.PubKey != nil {
+		pubKey = e.PubKey.SerializeCompressed()
+	}
+	if err := wire.WriteVarBytes(w, 0, pubKey); err != nil {
+		return err
+	}
+
+	_, err := w.Write(e.DataHash[:])
+	return err
+}
+
+// deserializeSigningEvent reads an event written by serializeSigningEvent
+// from r.
+func deserializeSigningEvent(r io.Reader) (*SigningEvent, error) {
+	var eventType [1]byte
+	if _, err := io.ReadFull(r, eventType[:]); err != nil {
+		return nil, err
+	}
+
+	event := &SigningEvent{
+		Type: SigningEventType(eventType[0]),
+	}
+	if err := readOutpoint(r, &event.Outpoint); err != nil {
+		return nil, err
+	}
+
+	pubKey, err := wire.ReadVarBytes(r, 0, 33, "pubkey")
+	if err != nil {
+		return nil, err
+	}
+	if len(pubKey) != 0 {
+		event.PubKey, err = btcec.ParsePubKey(pubKey, btcec.S256())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := io.ReadFull(r, event.DataHash[:]); err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
@@ -0,0 +1,112 @@
+package channeldb
+
+import (
+	"crypto/sha256"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
+)
+
+func TestSigningAudit(t *testing.T) {
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+
+	// Fetching from an empty log should return no events.
+	events, err := db.FetchSigningEvents(time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("unable to fetch events: %v", err)
+	}
+	if len(events) != 0 {
+		t.Fatalf("expected no events, got %v", len(events))
+	}
+
+	start := time.Unix(1490000000, 0)
+	chanPoint := wire.OutPoint{Hash: chainhash.Hash(key), Index: 1}
+	auditLog := []*SigningEvent{
+		{
+			Type:      WalletSigEvent,
+			Timestamp: start,
+			Outpoint:  wire.OutPoint{Hash: chainhash.Hash(key)},
+			DataHash:  sha256.Sum256([]byte("funding")),
+		},
+		{
+			Type:      CommitmentSigEvent,
+			Timestamp: start.Add(time.Hour),
+			Outpoint:  chanPoint,
+			PubKey:    pubKey,
+			DataHash:  sha256.Sum256([]byte("commitment")),
+		},
+		{
+			Type:      CloseSigEvent,
+			Timestamp: start.Add(time.Hour * 2),
+			Outpoint:  chanPoint,
+			PubKey:    pubKey,
+			DataHash:  sha256.Sum256([]byte("close")),
+		},
+		{
+			Type:      SweepSigEvent,
+			Timestamp: start.Add(time.Hour * 3),
+			Outpoint:  wire.OutPoint{Hash: chainhash.Hash(key), Index: 2},
+			PubKey:    pubKey,
+			DataHash:  sha256.Sum256([]byte("sweep")),
+		},
+	}
+
+	// The events are added out of order, they should still be returned
+	// in chronological order.
+	for i := len(auditLog) - 1; i >= 0; i-- {
+		if err := db.AddSigningEvent(auditLog[i]); err != nil {
+			t.Fatalf("unable to add event: %v", err)
+		}
+	}
+
+	events, err = db.FetchSigningEvents(time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("unable to fetch events: %v", err)
+	}
+	if !reflect.DeepEqual(auditLog, events) {
+		t.Fatalf("audit log fetched from db doesn't match original "+
+			"%v vs %v", spew.Sdump(auditLog), spew.Sdump(events))
+	}
+
+	// Only the events within the queried range, including its bounds,
+	// should be returned.
+	events, err = db.FetchSigningEvents(start.Add(time.Hour),
+		start.Add(time.Hour*2))
+	if err != nil {
+		t.Fatalf("unable to fetch events: %v", err)
+	}
+	if !reflect.DeepEqual(auditLog[1:3], events) {
+		t.Fatalf("audit log fetched from db doesn't match original "+
+			"%v vs %v", spew.Sdump(auditLog[1:3]),
+			spew.Sdump(events))
+	}
+
+	// An event with a timestamp identical to an existing event should be
+	// recorded after it, rather than overwriting it.
+	resign := &SigningEvent{
+		Type:      CommitmentSigEvent,
+		Timestamp: start.Add(time.Hour * 3),
+		Outpoint:  chanPoint,
+		PubKey:    pubKey,
+	}
+	if err := db.AddSigningEvent(resign); err != nil {
+		t.Fatalf("unable to add event: %v", err)
+	}
+	events, err = db.FetchSigningEvents(start.Add(time.Hour*3),
+		time.Time{})
+	if err != nil {
+		t.Fatalf("unable to fetch events: %v", err)
+	}
+	if len(events) != 2 || events[0].Type != SweepSigEvent ||
+		events[1].Type != CommitmentSigEvent {
+		t.Fatalf("unexpected events: %v", spew.Sdump(events))
+	}
+}
@@ -0,0 +1,286 @@
+package channeldb
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/boltdb/bolt"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+var (
+	// spliceKey stores the pending splice of an active channel, if any:
+	// the splice transaction, the funding output it creates, and our
+	// version of the commitment transaction spending that output. The
+	// key is stored within the node's channel bucket, and is suffixed by
+	// the channel's ID.
+	spliceKey = []byte("spk")
+)
+
+// PendingSplice is a splice of additional funds into a channel which has
+// been signed by both parties, but has yet to be buried deep enough within
+// the chain to be considered final. Until then, the channel's existing
+// funding output and commitment state remain in place, as a re-org may yet
+// remove the splice transaction from the chain.
+type PendingSplice struct {
+	// Tx is the splice transaction, which spends the channel's existing
+	// funding output. Once known, the witnesses of all its inputs are
+	// included.
+	Tx *wire.MsgTx
+
+	// FundingOutpoint is the funding output created by the splice
+	// transaction.
+	FundingOutpoint *wire.OutPoint
+
+	// Capacity is the value of the new funding output.
+	Capacity btcutil.Amount
+
+	// OurBalance and TheirBalance are the settled balances of each party
+	// once the spliced funds are credited to the splice's initiator.
+	OurBalance   btcutil.Amount
+	TheirBalance btcutil.Amount
+
+	// OurCommitTx is our version of the commitment transaction at the
+	// channel's current height which spends the new funding output, and
+	// OurCommitSig is the remote party's signature for it.
+	OurCommitTx  *wire.MsgTx
+	OurCommitSig []byte
+
+	// Confirmed denotes that the splice transaction has been included
+	// within the chain, invalidating the commitment transactions which
+	// spend the channel's existing funding output.
+	Confirmed bool
+}
+
+// PutPendingSplice records the passed splice as pending for the channel,
+// replacing any splice previously recorded.
+func (c *OpenChannel) PutPendingSplice(splice *PendingSplice) error {
+	c.Lock()
+	defer c.Unlock()
+
+	var b bytes.Buffer
+	if err := serializePendingSplice(&b, splice); err != nil {
+		return err
+	}
+
+	return c.Db.Update(func(tx *bolt.Tx) error {
+		nodeChanBucket, err := c.nodeChanBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		key, err := makeSpliceKey(c.ChanID)
+		if err != nil {
+			return err
+		}
+
+		return nodeChanBucket.Put(key, b.Bytes())
+	})
+}
+
+// FetchPendingSplice returns the pending splice of the channel. If the
+// channel isn't being spliced, then ErrNoPendingSplice is returned.
+func (c *OpenChannel) FetchPendingSplice() (*PendingSplice, error) {
+	c.RLock()
+	defer c.RUnlock()
+
+	var splice *PendingSplice
+	err := c.Db.View(func(tx *bolt.Tx) error {
+		chanBucket := tx.Bucket(openChannelBucket)
+		if chanBucket == nil {
+			return ErrNoPendingSplice
+		}
+		nodePub := c.IdentityPub.SerializeCompressed()
+		nodeChanBucket := chanBucket.Bucket(nodePub)
+		if nodeChanBucket == nil {
+			return ErrNoPendingSplice
+		}
+
+		key, err := makeSpliceKey(c.ChanID)
+		if err != nil {
+			return err
+		}
+		spliceBytes := nodeChanBucket.Get(key)
+		if spliceBytes == nil {
+			return ErrNoPendingSplice
+		}
+
+		splice, err = deserializePendingSplice(
+			bytes.NewReader(spliceBytes))
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return splice, nil
+}
+
+// DeletePendingSplice removes the pending splice of the channel, leaving the
+// channel's funding output and commitment state untouched.
+func (c *OpenChannel) DeletePendingSplice() error {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.Db.Update(func(tx *bolt.Tx) error {
+		nodeChanBucket, err := c.nodeChanBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		return deleteChanSplice(nodeChanBucket, c.ChanID)
+	})
+}
+
+// MigrateSplice migrates the channel to the funding output created by its
+// pending splice, adopting the splice's capacity, balances, and commitment
+// transaction as the channel's current state. The pending splice is removed
+// within the same transaction. The channel retains its ID, so it remains
+// indexed under the outpoint of its original funding output.
+func (c *OpenChannel) MigrateSplice(splice *PendingSplice) error {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.Db.Update(func(tx *bolt.Tx) error {
+		chanBucket, err := tx.CreateBucketIfNotExists(openChannelBucket)
+		if err != nil {
+			return err
+		}
+		nodeChanBucket, err := c.nodeChanBucket(tx)
+		if err != nil {
+			return err
+		}
+
+		c.FundingOutpoint = splice.FundingOutpoint
+		c.Capacity = splice.Capacity
+		c.OurBalance = splice.OurBalance
+		c.TheirBalance = splice.TheirBalance
+		c.OurCommitTx = splice.OurCommitTx
+		c.OurCommitSig = splice.OurCommitSig
+
+		if err := putChanCapacity(chanBucket, c); err != nil {
+			return err
+		}
+		if err := putChanCommitTxns(nodeChanBucket, c); err != nil {
+			return err
+		}
+		if err := putChanFundingInfo(nodeChanBucket, c); err != nil {
+			return err
+		}
+
+		return deleteChanSplice(nodeChanBucket, c.ChanID)
+	})
+}
+
+// nodeChanBucket returns the bucket storing the channels open with the
+// channel's remote node, creating it if necessary.
+func (c *OpenChannel) nodeChanBucket(tx *bolt.Tx) (*bolt.Bucket, error) {
+	chanBucket, err := tx.CreateBucketIfNotExists(openChannelBucket)
+	if err != nil {
+		return nil, err
+	}
+
+	nodePub := c.IdentityPub.SerializeCompressed()
+	return chanBucket.CreateBucketIfNotExists(nodePub)
+}
+
+// makeSpliceKey returns the key of the pending splice of the channel with the
+// passed ID.
+func makeSpliceKey(chanID *wire.OutPoint) ([]byte, error) {
+	var b bytes.Buffer
+	if err := writeOutpoint(&b, chanID); err != nil {
+		return nil, err
+	}
+
+	key := make([]byte, len(spliceKey)+b.Len())
+	copy(key[:len(spliceKey)], spliceKey)
+	copy(key[len(spliceKey):], b.Bytes())
+
+	return key, nil
+}
+
+func deleteChanSplice(nodeChanBucket *bolt.Bucket, chanID *wire.OutPoint) error {
+	key, err := makeSpliceKey(chanID)
+	if err != nil {
+		return err
+	}
+
+	return nodeChanBucket.Delete(key)
+}
+
+func serializePendingSplice(w io.Writer, splice *PendingSplice) error {
+	if err := splice.Tx.Serialize(w); err != nil {
+		return err
+	}
+	if err := writeOutpoint(w, splice.FundingOutpoint); err != nil {
+		return err
+	}
+
+	scratch := make([]byte, 8)
+	for _, amt := range []btcutil.Amount{splice.Capacity,
+		splice.OurBalance, splice.TheirBalance} {
+
+		byteOrder.PutUint64(scratch, uint64(amt))
+		if _, err := w.Write(scratch); err != nil {
+			return err
+		}
+	}
+
+	if err := splice.OurCommitTx.Serialize(w); err != nil {
+		return err
+	}
+	if err := wire.WriteVarBytes(w, 0, splice.OurCommitSig); err != nil {
+		return err
+	}
+
+	var confirmed [1]byte
+	if splice.Confirmed {
+		confirmed[0] = 1
+	}
+	_, err := w.Write(confirmed[:])
+	return err
+}
+
+func deserializePendingSplice(r io.Reader) (*PendingSplice, error) {
+	splice := &PendingSplice{
+		Tx:              wire.NewMsgTx(2),
+		FundingOutpoint: &wire.OutPoint{},
+		OurCommitTx:     wire.NewMsgTx(2),
+	}
+
+	if err := splice.Tx.Deserialize(r); err != nil {
+		return nil, err
+	}
+	if err := readOutpoint(r, splice.FundingOutpoint); err != nil {
+		return nil, err
+	}
+
+	scratch := make([]byte, 8)
+	for _, amt := range []*btcutil.Amount{&splice.Capacity,
+		&splice.OurBalance, &splice.TheirBalance} {
+
+		if _, err := io.ReadFull(r, scratch); err != nil {
+			return nil, err
+		}
+		*amt = btcutil.Amount(byteOrder.Uint64(scratch))
+	}
+
+	if err := splice.OurCommitTx.Deserialize(r); err != nil {
+		return nil, err
+	}
+
+	var err error
+	splice.OurCommitSig, err = wire.ReadVarBytes(r, 0, 80, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var confirmed [1]byte
+	if _, err := io.ReadFull(r, confirmed[:]); err != nil {
+		return nil, err
+	}
+	splice.Confirmed = confirmed[0] == 1
+
+	return splice, nil
+}
@@ -0,0 +1,102 @@
+package channeldb
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+func TestPendingSplice(t *testing.T) {
+	cdb, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test database: %v", err)
+	}
+
+	state, err := createTestChannelState(cdb)
+	if err != nil {
+		t.Fatalf("unable to create channel state: %v", err)
+	}
+	if err := state.FullSync(); err != nil {
+		t.Fatalf("unable to save and serialize channel state: %v", err)
+	}
+
+	// A channel which isn't being spliced has no pending splice.
+	if _, err := state.FetchPendingSplice(); err != ErrNoPendingSplice {
+		t.Fatalf("expected ErrNoPendingSplice, got %v", err)
+	}
+
+	spliceTx := testTx.Copy()
+	spliceTx.TxIn[0].Witness = wire.TxWitness{bytes.Repeat([]byte{2}, 72)}
+	splice := &PendingSplice{
+		Tx: spliceTx,
+		FundingOutpoint: &wire.OutPoint{
+			Hash:  spliceTx.TxHash(),
+			Index: 1,
+		},
+		Capacity:     btcutil.Amount(15000),
+		OurBalance:   btcutil.Amount(8000),
+		TheirBalance: btcutil.Amount(9000),
+		OurCommitTx:  testTx,
+		OurCommitSig: bytes.Repeat([]byte{3}, 71),
+	}
+	if err := state.PutPendingSplice(splice); err != nil {
+		t.Fatalf("unable to put pending splice: %v", err)
+	}
+
+	// Once the splice confirms, the updated record should replace the
+	// original.
+	splice.Confirmed = true
+	if err := state.PutPendingSplice(splice); err != nil {
+		t.Fatalf("unable to put pending splice: %v", err)
+	}
+	fetched, err := state.FetchPendingSplice()
+	if err != nil {
+		t.Fatalf("unable to fetch pending splice: %v", err)
+	}
+	if !reflect.DeepEqual(splice, fetched) {
+		t.Fatalf("splice mismatch: expected %v, got %v", splice,
+			fetched)
+	}
+
+	// Migrating the channel to the splice should update its funding
+	// output, capacity, balances and commitment, while the channel
+	// remains indexed under its original ID.
+	if err := state.MigrateSplice(splice); err != nil {
+		t.Fatalf("unable to migrate splice: %v", err)
+	}
+	openChannels, err := cdb.FetchOpenChannels(state.IdentityPub)
+	if err != nil {
+		t.Fatalf("unable to fetch open channel: %v", err)
+	}
+	newState := openChannels[0]
+	if *newState.ChanID != *id {
+		t.Fatalf("channel ID changed to %v", newState.ChanID)
+	}
+	if *newState.FundingOutpoint != *splice.FundingOutpoint {
+		t.Fatalf("expected funding outpoint %v, got %v",
+			splice.FundingOutpoint, newState.FundingOutpoint)
+	}
+	if newState.Capacity != splice.Capacity {
+		t.Fatalf("expected capacity %v, got %v", splice.Capacity,
+			newState.Capacity)
+	}
+	if newState.OurBalance != splice.OurBalance ||
+		newState.TheirBalance != splice.TheirBalance {
+
+		t.Fatalf("balance mismatch: expected %v/%v, got %v/%v",
+			splice.OurBalance, splice.TheirBalance,
+			newState.OurBalance, newState.TheirBalance)
+	}
+	if !bytes.Equal(newState.OurCommitSig, splice.OurCommitSig) {
+		t.Fatal("commitment signature doesn't match")
+	}
+
+	// The pending splice should have been removed by the migration.
+	if _, err := state.FetchPendingSplice(); err != ErrNoPendingSplice {
+		t.Fatalf("expected ErrNoPendingSplice, got %v", err)
+	}
+}
@@ -0,0 +1,231 @@
+package channeldb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/roasbeef/btcd/wire"
+)
+
+var (
+	// stateLogBucket is the name of the bucket within the database that
+	// stores the append-only log of the state machine transitions of each
+	// channel. Within the bucket, each channel has a sub-bucket keyed by
+	// its serialized funding outpoint. The entries within a channel's
+	// bucket are keyed by the big-endian encoding of a sequence number, so
+	// a cursor scan yields them in the order they were appended.
+	//
+	// NOTE: Like a channel's timeline, its state log is retained after
+	// the channel has been closed.
+	stateLogBucket = []byte("state-log")
+)
+
+// StateEventType denotes the kind of transition within a channel's state log.
+type StateEventType uint8
+
+const (
+	// StateProposedEvent is logged when we propose a new state to the
+	// remote party by signing their next commitment.
+	StateProposedEvent StateEventType = 0
+
+	// SigReceivedEvent is logged when we accept the remote party's
+	// signature for our next commitment.
+	SigReceivedEvent StateEventType = 1
+
+	// RevocationSentEvent is logged when we revoke our prior commitment.
+	RevocationSentEvent StateEventType = 2
+
+	// RevocationReceivedEvent is logged when the remote party's
+	// revocation of their prior commitment is ingested into our
+	// revocation store.
+	RevocationReceivedEvent StateEventType = 3
+
+	// CloseDetectedEvent is logged when a transaction closing the
+	// channel is detected on-chain.
+	CloseDetectedEvent StateEventType = 4
+)
+
+// String returns a human readable name for the event type.
+func (s StateEventType) String() string {
+	switch s {
+	case StateProposedEvent:
+		return "StateProposed"
+	case SigReceivedEvent:
+		return "SigReceived"
+	case RevocationSentEvent:
+		return "RevocationSent"
+	case RevocationReceivedEvent:
+		return "RevocationReceived"
+	case CloseDetectedEvent:
+		return "CloseDetected"
+	default:
+		return "Unknown"
+	}
+}
+
+// StateEvent is a single transition within the state log of a channel.
+type StateEvent struct {
+	// Type is the kind of transition.
+	Type StateEventType
+
+	// Timestamp is the time the transition occurred.
+	Timestamp time.Time
+
+	// LocalHeight and RemoteHeight are the heights of the tips of our
+	// local and the remote party's commitment chains following the
+	// transition.
+	LocalHeight  uint64
+	RemoteHeight uint64
+
+	// Detail is a short, free-form description of the transition.
+	Detail string
+}
+
+// AppendStateEvent appends an event to the state log of the channel
+// identified by the passed funding outpoint. As transitions are logged
+// concurrently by each active channel, the write is batched with those of
+// other channels.
+func (d *DB) AppendStateEvent(chanPoint *wire.OutPoint,
+	event *StateEvent) error {
+
+	var b bytes.Buffer
+	if err := serializeStateEvent(&b, event); err != nil {
+		return err
+	}
+
+	var chanKey bytes.Buffer
+	if err := writeOutpoint(&chanKey, chanPoint); err != nil {
+		return err
+	}
+
+	return d.Batch(func(tx *bolt.Tx) error {
+		stateLog, err := tx.CreateBucketIfNotExists(stateLogBucket)
+		if err != nil {
+			return err
+		}
+		events, err := stateLog.CreateBucketIfNotExists(chanKey.Bytes())
+		if err != nil {
+			return err
+		}
+
+		seqNum, err := events.NextSequence()
+		if err != nil {
+			return err
+		}
+
+		var eventKey [8]byte
+		binary.BigEndian.PutUint64(eventKey[:], seqNum)
+		return events.Put(eventKey[:], b.Bytes())
+	})
+}
+
+// FetchStateLog returns the events within the state log of the channel
+// identified by the passed funding outpoint, in the order they were appended.
+// If limit is non-zero, then only the most recent limit events are returned.
+func (d *DB) FetchStateLog(chanPoint *wire.OutPoint,
+	limit uint32) ([]*StateEvent, error) {
+
+	var chanKey bytes.Buffer
+	if err := writeOutpoint(&chanKey, chanPoint); err != nil {
+		return nil, err
+	}
+
+	var stateEvents []*StateEvent
+	err := d.View(func(tx *bolt.Tx) error {
+		stateLog := tx.Bucket(stateLogBucket)
+		if stateLog == nil {
+			return nil
+		}
+		events := stateLog.Bucket(chanKey.Bytes())
+		if events == nil {
+			return nil
+		}
+
+		// We walk the log backwards from its most recent event, so the
+		// limit can be applied without reading the entire log.
+		c := events.Cursor()
+		for k, v := c.Last(); k != nil; k, v = c.Prev() {
+			if limit != 0 && uint32(len(stateEvents)) == limit {
+				break
+			}
+
+			event, err := deserializeStateEvent(bytes.NewReader(v))
+			if err != nil {
+				return err
+			}
+			stateEvents = append(stateEvents, event)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i, j := 0, len(stateEvents)-1; i < j; i, j = i+1, j-1 {
+		stateEvents[i], stateEvents[j] = stateEvents[j], stateEvents[i]
+	}
+
+	return stateEvents, nil
+}
+
+func serializeStateEvent(w io.Writer, e *StateEvent) error {
+	var scratch [8]byte
+
+	if _, err := w.Write([]byte{byte(e.Type)}); err != nil {
+		return err
+	}
+
+	byteOrder.PutUint64(scratch[:], uint64(e.Timestamp.UnixNano()))
+	if _, err := w.Write(scratch[:]); err != nil {
+		return err
+	}
+
+	if err := wire.WriteVarInt(w, 0, e.LocalHeight); err != nil {
+		return err
+	}
+	if err := wire.WriteVarInt(w, 0, e.RemoteHeight); err != nil {
+		return err
+	}
+
+	return wire.WriteVarString(w, 0, e.Detail)
+}
+
+func deserializeStateEvent(r io.Reader) (*StateEvent, error) {
+	var scratch [8]byte
+
+	if _, err := io.ReadFull(r, scratch[:1]); err != nil {
+		return nil, err
+	}
+	eventType := StateEventType(scratch[0])
+
+	if _, err := io.ReadFull(r, scratch[:]); err != nil {
+		return nil, err
+	}
+	timestamp := time.Unix(0, int64(byteOrder.Uint64(scratch[:])))
+
+	localHeight, err := wire.ReadVarInt(r, 0)
+	if err != nil {
+		return nil, err
+	}
+	remoteHeight, err := wire.ReadVarInt(r, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	detail, err := wire.ReadVarString(r, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return &StateEvent{
+		Type:         eventType,
+		Timestamp:    timestamp,
+		LocalHeight:  localHeight,
+		RemoteHeight: remoteHeight,
+		Detail:       detail,
+	}, nil
+}
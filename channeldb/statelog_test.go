@@ -0,0 +1,116 @@
+package channeldb
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// TestStateLog tests that the events of a channel's state log are returned in
+// the order they were appended, even if their timestamps are identical, that
+// the log may be limited to its most recent events, and that the logs of
+// distinct channels are kept apart.
+func TestStateLog(t *testing.T) {
+	db, cleanUp, err := makeTestDB()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+	defer cleanUp()
+
+	chanPoint := &wire.OutPoint{Hash: chainhash.Hash(key), Index: 1}
+	otherChanPoint := &wire.OutPoint{Hash: chainhash.Hash(key), Index: 2}
+
+	events, err := db.FetchStateLog(chanPoint, 0)
+	if err != nil {
+		t.Fatalf("unable to fetch state log: %v", err)
+	}
+	if len(events) != 0 {
+		t.Fatalf("expected no events, got %v", len(events))
+	}
+
+	now := time.Unix(0, 1490000000123456789)
+	stateLog := []*StateEvent{
+		{
+			Type:         StateProposedEvent,
+			Timestamp:    now,
+			LocalHeight:  0,
+			RemoteHeight: 1,
+			Detail:       "updates=2",
+		},
+		{
+			Type:         RevocationReceivedEvent,
+			Timestamp:    now,
+			LocalHeight:  0,
+			RemoteHeight: 1,
+		},
+		{
+			Type:         SigReceivedEvent,
+			Timestamp:    now.Add(time.Millisecond),
+			LocalHeight:  1,
+			RemoteHeight: 1,
+		},
+		{
+			Type:         RevocationSentEvent,
+			Timestamp:    now.Add(time.Millisecond),
+			LocalHeight:  1,
+			RemoteHeight: 1,
+		},
+		{
+			Type:         CloseDetectedEvent,
+			Timestamp:    now.Add(time.Hour),
+			LocalHeight:  1,
+			RemoteHeight: 1,
+			Detail:       "unilateral, remote",
+		},
+	}
+	for _, event := range stateLog {
+		if err := db.AppendStateEvent(chanPoint, event); err != nil {
+			t.Fatalf("unable to append state event: %v", err)
+		}
+	}
+	err = db.AppendStateEvent(otherChanPoint, &StateEvent{
+		Type:      StateProposedEvent,
+		Timestamp: now,
+	})
+	if err != nil {
+		t.Fatalf("unable to append state event: %v", err)
+	}
+
+	assertStateLog := func(events, expected []*StateEvent) {
+		if len(events) != len(expected) {
+			t.Fatalf("expected %v events, got %v", len(expected),
+				len(events))
+		}
+		for i, event := range events {
+			if !event.Timestamp.Equal(expected[i].Timestamp) {
+				t.Fatalf("event #%v: expected time %v, got %v",
+					i, expected[i].Timestamp,
+					event.Timestamp)
+			}
+			event.Timestamp = expected[i].Timestamp
+			if !reflect.DeepEqual(event, expected[i]) {
+				t.Fatalf("event #%v: expected %v, got %v", i,
+					spew.Sdump(expected[i]),
+					spew.Sdump(event))
+			}
+		}
+	}
+
+	events, err = db.FetchStateLog(chanPoint, 0)
+	if err != nil {
+		t.Fatalf("unable to fetch state log: %v", err)
+	}
+	assertStateLog(events, stateLog)
+
+	// A limited query should only return the most recent events, still
+	// in the order they were appended.
+	events, err = db.FetchStateLog(chanPoint, 2)
+	if err != nil {
+		t.Fatalf("unable to fetch state log: %v", err)
+	}
+	assertStateLog(events, stateLog[3:])
+}
@@ -0,0 +1,705 @@
+package channeldb
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/boltdb/bolt"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+var (
+	// towerBucket is the name of the top-level bucket holding the state
+	// of our watchtower: the sessions opened by its clients, and the
+	// encrypted justice kits uploaded within them.
+	towerBucket = []byte("tower")
+
+	// towerSessionBucket is a sub-bucket of the tower bucket storing each
+	// session, keyed by its ID.
+	//
+	// maps: sessionID -> clientPub || maxUpdates || lastApplied ||
+	//                    sweepFeeRate || rewardRate || rewardPkScript
+	towerSessionBucket = []byte("sessions")
+
+	// towerClientBucket is a sub-bucket of the tower bucket indexing the
+	// sessions owned by each client.
+	//
+	// maps: clientPub -> sessionID -> nil
+	towerClientBucket = []byte("clients")
+
+	// towerHintBucket is a sub-bucket of the tower bucket storing the
+	// encrypted justice kits, grouped by the hint of the breach
+	// transaction they sweep.
+	//
+	// maps: hint -> sessionID || seqNum -> blob
+	towerHintBucket = []byte("hints")
+
+	// towerStorageKey is the key within the tower bucket storing the
+	// total size of the stored justice kits.
+	towerStorageKey = []byte("storage")
+
+	// towerScanHeightKey is the key within the tower bucket storing the
+	// height of the last block scanned for breaches.
+	towerScanHeightKey = []byte("scanheight")
+
+	// towerClientStorageBucket is a sub-bucket of the tower bucket storing
+	// the total size of the justice kits stored by each client.
+	//
+	// maps: clientPub -> storageUsed
+	towerClientStorageBucket = []byte("clientstorage")
+
+	// towerPendingBucket is a sub-bucket of the tower bucket storing the
+	// breaches detected whose justice transaction couldn't be dispatched
+	// for a reason which may pass, so the dispatch is retried.
+	//
+	// maps: hint || sessionID || seqNum -> breachHeight || breachTx
+	towerPendingBucket = []byte("pending")
+)
+
+// TowerSession is a session opened with our watchtower by one of its
+// clients. Within the session, the client may upload up to MaxUpdates
+// encrypted justice kits, all of which sweep funds according to the session's
+// policy.
+type TowerSession struct {
+	// ID uniquely identifies the session.
+	ID uint64
+
+	// ClientPub is the identity key of the client which owns the session.
+	ClientPub *btcec.PublicKey
+
+	// MaxUpdates is the number of updates the session may hold.
+	MaxUpdates uint16
+
+	// LastApplied is the sequence number of the last update applied to
+	// the session. Sequence numbers start from one, so a session without
+	// updates has a LastApplied of zero.
+	LastApplied uint16
+
+	// SweepFeeRate is the fee rate, in satoshis per kilo-weight, paid by
+	// the justice transactions of the session.
+	SweepFeeRate btcutil.Amount
+
+	// RewardRate is the share of the swept funds, in millionths, paid to
+	// the tower.
+	RewardRate uint32
+
+	// RewardPkScript is the script the tower's reward is paid to.
+	RewardPkScript []byte
+}
+
+// TowerBlob is an encrypted justice kit uploaded within a watchtower session.
+type TowerBlob struct {
+	// SessionID identifies the session the blob was uploaded within.
+	SessionID uint64
+
+	// SeqNum is the sequence number of the update carrying the blob.
+	SeqNum uint16
+
+	// Blob is the encrypted justice kit.
+	Blob []byte
+}
+
+// TowerPendingJustice is a breach detected by our watchtower, whose justice
+// transaction, described by the blob uploaded within the session under the
+// breach transaction's hint, is yet to be dispatched.
+type TowerPendingJustice struct {
+	// Hint is the hint of the breach transaction the blob is stored
+	// under.
+	Hint [16]byte
+
+	// SessionID identifies the session the blob was uploaded within.
+	SessionID uint64
+
+	// SeqNum is the sequence number of the update carrying the blob.
+	SeqNum uint16
+
+	// BreachHeight is the height of the block the breach was detected
+	// within.
+	BreachHeight uint32
+
+	// BreachTx is the breach transaction.
+	BreachTx *wire.MsgTx
+}
+
+// TowerStats summarizes the state held by our watchtower.
+type TowerStats struct {
+	// NumSessions is the number of sessions opened by clients.
+	NumSessions uint64
+
+	// NumBlobs is the number of justice kits stored.
+	NumBlobs uint64
+
+	// StorageUsed is the total size of the stored justice kits, in bytes.
+	StorageUsed uint64
+}
+
+// CreateTowerSession assigns the passed session a fresh ID and stores it. If
+// the session's client already owns maxPerClient sessions, then
+// ErrTowerSessionLimit is returned.
+func (d *DB) CreateTowerSession(session *TowerSession,
+	maxPerClient uint32) error {
+
+	return d.Update(func(tx *bolt.Tx) error {
+		tower, err := tx.CreateBucketIfNotExists(towerBucket)
+		if err != nil {
+			return err
+		}
+		sessions, err := tower.CreateBucketIfNotExists(towerSessionBucket)
+		if err != nil {
+			return err
+		}
+		clients, err := tower.CreateBucketIfNotExists(towerClientBucket)
+		if err != nil {
+			return err
+		}
+
+		client, err := clients.CreateBucketIfNotExists(
+			session.ClientPub.SerializeCompressed())
+		if err != nil {
+			return err
+		}
+		if countTowerKeys(client) >= uint64(maxPerClient) {
+			return ErrTowerSessionLimit
+		}
+
+		session.ID, err = sessions.NextSequence()
+		if err != nil {
+			return err
+		}
+
+		var sessionID [8]byte
+		byteOrder.PutUint64(sessionID[:], session.ID)
+		if err := client.Put(sessionID[:], nil); err != nil {
+			return err
+		}
+
+		return putTowerSession(sessions, session)
+	})
+}
+
+// FetchTowerSession returns the session with the passed ID. If the session
+// doesn't exist, then ErrTowerSessionNotFound is returned.
+func (d *DB) FetchTowerSession(sessionID uint64) (*TowerSession, error) {
+	var session *TowerSession
+	err := d.View(func(tx *bolt.Tx) error {
+		var err error
+		session, err = fetchTowerSession(tx.Bucket(towerBucket),
+			sessionID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// ApplyTowerUpdate stores the blob uploaded by the passed client within one
+// of its sessions under the passed hint, and returns the sequence number of
+// the last update applied to the session. Updates must be applied in order
+// of their sequence numbers. An update which was already applied is ignored,
+// so a client may safely resend updates it didn't receive a reply for. If
+// storing the blob would bring the total size of the stored blobs beyond
+// maxStorage bytes, or the size of those stored by the client beyond
+// maxClientStorage bytes, then ErrTowerStorageFull or
+// ErrTowerClientStorageFull is returned respectively.
+func (d *DB) ApplyTowerUpdate(clientPub *btcec.PublicKey, sessionID uint64,
+	seqNum uint16, hint [16]byte, blob []byte, maxStorage,
+	maxClientStorage uint64) (uint16, error) {
+
+	var lastApplied uint16
+	err := d.Update(func(tx *bolt.Tx) error {
+		tower := tx.Bucket(towerBucket)
+		session, err := fetchTowerSession(tower, sessionID)
+		if err != nil {
+			return err
+		}
+		if !session.ClientPub.IsEqual(clientPub) {
+			return ErrTowerSessionNotFound
+		}
+
+		lastApplied = session.LastApplied
+		switch {
+		case seqNum <= session.LastApplied:
+			return nil
+		case session.LastApplied >= session.MaxUpdates:
+			return ErrTowerSessionFull
+		case seqNum != session.LastApplied+1:
+			return ErrTowerSeqNumOutOfOrder
+		}
+
+		var storageUsed uint64
+		if b := tower.Get(towerStorageKey); b != nil {
+			storageUsed = byteOrder.Uint64(b)
+		}
+		storageUsed += uint64(len(blob))
+		if storageUsed > maxStorage {
+			return ErrTowerStorageFull
+		}
+
+		clientStorage, err := tower.CreateBucketIfNotExists(
+			towerClientStorageBucket)
+		if err != nil {
+			return err
+		}
+		clientKey := clientPub.SerializeCompressed()
+		var clientUsed uint64
+		if b := clientStorage.Get(clientKey); b != nil {
+			clientUsed = byteOrder.Uint64(b)
+		}
+		clientUsed += uint64(len(blob))
+		if clientUsed > maxClientStorage {
+			return ErrTowerClientStorageFull
+		}
+
+		hints, err := tower.CreateBucketIfNotExists(towerHintBucket)
+		if err != nil {
+			return err
+		}
+		hintBucket, err := hints.CreateBucketIfNotExists(hint[:])
+		if err != nil {
+			return err
+		}
+		if err := hintBucket.Put(towerBlobKey(sessionID, seqNum), blob); err != nil {
+			return err
+		}
+
+		var scratch [8]byte
+		byteOrder.PutUint64(scratch[:], storageUsed)
+		if err := tower.Put(towerStorageKey, scratch[:]); err != nil {
+			return err
+		}
+		byteOrder.PutUint64(scratch[:], clientUsed)
+		if err := clientStorage.Put(clientKey, scratch[:]); err != nil {
+			return err
+		}
+
+		session.LastApplied = seqNum
+		lastApplied = seqNum
+		return putTowerSession(tower.Bucket(towerSessionBucket), session)
+	})
+	if err != nil {
+		return lastApplied, err
+	}
+
+	return lastApplied, nil
+}
+
+// FetchTowerBlobs returns every blob stored under the passed hint.
+func (d *DB) FetchTowerBlobs(hint [16]byte) ([]*TowerBlob, error) {
+	var blobs []*TowerBlob
+	err := d.View(func(tx *bolt.Tx) error {
+		tower := tx.Bucket(towerBucket)
+		if tower == nil {
+			return nil
+		}
+		hints := tower.Bucket(towerHintBucket)
+		if hints == nil {
+			return nil
+		}
+		hintBucket := hints.Bucket(hint[:])
+		if hintBucket == nil {
+			return nil
+		}
+
+		return hintBucket.ForEach(func(k, v []byte) error {
+			// The returned slice is only valid for the lifetime of
+			// the transaction, so it must be copied.
+			blob := make([]byte, len(v))
+			copy(blob, v)
+
+			blobs = append(blobs, &TowerBlob{
+				SessionID: byteOrder.Uint64(k[:8]),
+				SeqNum:    byteOrder.Uint16(k[8:]),
+				Blob:      blob,
+			})
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return blobs, nil
+}
+
+// DeleteTowerBlob deletes the blob uploaded within the passed session under
+// the passed hint, releasing the storage it occupied, both in total and
+// within the quota of the session's client. Any dispatch of the blob's justice
+// transaction pending a retry is deleted along with it.
+func (d *DB) DeleteTowerBlob(hint [16]byte, sessionID uint64,
+	seqNum uint16) error {
+
+	return d.Update(func(tx *bolt.Tx) error {
+		tower := tx.Bucket(towerBucket)
+		if tower == nil {
+			return nil
+		}
+		hints := tower.Bucket(towerHintBucket)
+		if hints == nil {
+			return nil
+		}
+		hintBucket := hints.Bucket(hint[:])
+		if hintBucket == nil {
+			return nil
+		}
+
+		blobKey := towerBlobKey(sessionID, seqNum)
+		blob := hintBucket.Get(blobKey)
+		if blob == nil {
+			return nil
+		}
+
+		if pending := tower.Bucket(towerPendingBucket); pending != nil {
+			pendingKey := towerPendingKey(hint, sessionID, seqNum)
+			if err := pending.Delete(pendingKey); err != nil {
+				return err
+			}
+		}
+
+		var storageUsed uint64
+		if b := tower.Get(towerStorageKey); b != nil {
+			storageUsed = byteOrder.Uint64(b)
+		}
+		blobSize := uint64(len(blob))
+		storageUsed -= blobSize
+
+		if err := hintBucket.Delete(blobKey); err != nil {
+			return err
+		}
+		if countTowerKeys(hintBucket) == 0 {
+			if err := hints.DeleteBucket(hint[:]); err != nil {
+				return err
+			}
+		}
+
+		var scratch [8]byte
+		byteOrder.PutUint64(scratch[:], storageUsed)
+		if err := tower.Put(towerStorageKey, scratch[:]); err != nil {
+			return err
+		}
+
+		// Blobs stored before the storage of each client was tracked
+		// aren't counted against its quota, so the client's usage may
+		// fall short of the blob's size.
+		session, err := fetchTowerSession(tower, sessionID)
+		if err == ErrTowerSessionNotFound {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		clientStorage := tower.Bucket(towerClientStorageBucket)
+		if clientStorage == nil {
+			return nil
+		}
+		clientKey := session.ClientPub.SerializeCompressed()
+		b := clientStorage.Get(clientKey)
+		if b == nil {
+			return nil
+		}
+		clientUsed := byteOrder.Uint64(b)
+		if clientUsed > blobSize {
+			clientUsed -= blobSize
+		} else {
+			clientUsed = 0
+		}
+		byteOrder.PutUint64(scratch[:], clientUsed)
+		return clientStorage.Put(clientKey, scratch[:])
+	})
+}
+
+// AddTowerPendingJustice records a breach whose justice transaction couldn't
+// be dispatched, so the dispatch can be retried. Recording a breach which is
+// already pending is a noop, so the height it was first detected at is kept.
+func (d *DB) AddTowerPendingJustice(p *TowerPendingJustice) error {
+	return d.Update(func(tx *bolt.Tx) error {
+		tower, err := tx.CreateBucketIfNotExists(towerBucket)
+		if err != nil {
+			return err
+		}
+		pending, err := tower.CreateBucketIfNotExists(towerPendingBucket)
+		if err != nil {
+			return err
+		}
+
+		key := towerPendingKey(p.Hint, p.SessionID, p.SeqNum)
+		if pending.Get(key) != nil {
+			return nil
+		}
+
+		var b bytes.Buffer
+		var scratch [4]byte
+		byteOrder.PutUint32(scratch[:], p.BreachHeight)
+		if _, err := b.Write(scratch[:]); err != nil {
+			return err
+		}
+		if err := p.BreachTx.Serialize(&b); err != nil {
+			return err
+		}
+
+		return pending.Put(key, b.Bytes())
+	})
+}
+
+// FetchTowerPendingJustice returns every breach whose justice transaction is
+// pending a retry.
+func (d *DB) FetchTowerPendingJustice() ([]*TowerPendingJustice, error) {
+	var pendingJustice []*TowerPendingJustice
+	err := d.View(func(tx *bolt.Tx) error {
+		tower := tx.Bucket(towerBucket)
+		if tower == nil {
+			return nil
+		}
+		pending := tower.Bucket(towerPendingBucket)
+		if pending == nil {
+			return nil
+		}
+
+		return pending.ForEach(func(k, v []byte) error {
+			p := &TowerPendingJustice{
+				SessionID:    byteOrder.Uint64(k[16:24]),
+				SeqNum:       byteOrder.Uint16(k[24:]),
+				BreachHeight: byteOrder.Uint32(v[:4]),
+				BreachTx:     &wire.MsgTx{},
+			}
+			copy(p.Hint[:], k[:16])
+
+			err := p.BreachTx.Deserialize(bytes.NewReader(v[4:]))
+			if err != nil {
+				return err
+			}
+
+			pendingJustice = append(pendingJustice, p)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pendingJustice, nil
+}
+
+// FetchTowerBlob returns the blob uploaded within the passed session under
+// the passed hint. If the blob doesn't exist, then nil is returned.
+func (d *DB) FetchTowerBlob(hint [16]byte, sessionID uint64,
+	seqNum uint16) (*TowerBlob, error) {
+
+	blobs, err := d.FetchTowerBlobs(hint)
+	if err != nil {
+		return nil, err
+	}
+	for _, blob := range blobs {
+		if blob.SessionID == sessionID && blob.SeqNum == seqNum {
+			return blob, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// PutTowerScanHeight records the height of the last block our watchtower
+// scanned for breaches.
+func (d *DB) PutTowerScanHeight(height uint32) error {
+	return d.Update(func(tx *bolt.Tx) error {
+		tower, err := tx.CreateBucketIfNotExists(towerBucket)
+		if err != nil {
+			return err
+		}
+
+		var scratch [4]byte
+		byteOrder.PutUint32(scratch[:], height)
+		return tower.Put(towerScanHeightKey, scratch[:])
+	})
+}
+
+// FetchTowerScanHeight returns the height of the last block our watchtower
+// scanned for breaches. If no block has been scanned yet, then false is
+// returned.
+func (d *DB) FetchTowerScanHeight() (uint32, bool, error) {
+	var (
+		height uint32
+		ok     bool
+	)
+	err := d.View(func(tx *bolt.Tx) error {
+		tower := tx.Bucket(towerBucket)
+		if tower == nil {
+			return nil
+		}
+		if b := tower.Get(towerScanHeightKey); b != nil {
+			height = byteOrder.Uint32(b)
+			ok = true
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, false, err
+	}
+
+	return height, ok, nil
+}
+
+// FetchTowerStats returns a summary of the state held by our watchtower.
+func (d *DB) FetchTowerStats() (*TowerStats, error) {
+	stats := &TowerStats{}
+	err := d.View(func(tx *bolt.Tx) error {
+		tower := tx.Bucket(towerBucket)
+		if tower == nil {
+			return nil
+		}
+
+		if b := tower.Get(towerStorageKey); b != nil {
+			stats.StorageUsed = byteOrder.Uint64(b)
+		}
+		if sessions := tower.Bucket(towerSessionBucket); sessions != nil {
+			stats.NumSessions = countTowerKeys(sessions)
+		}
+
+		hints := tower.Bucket(towerHintBucket)
+		if hints == nil {
+			return nil
+		}
+		return hints.ForEach(func(k, _ []byte) error {
+			hintBucket := hints.Bucket(k)
+			if hintBucket == nil {
+				return nil
+			}
+			stats.NumBlobs += countTowerKeys(hintBucket)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return stats, nil
+}
+
+// countTowerKeys returns the number of keys within the passed bucket. Unlike
+// the bucket's stats, the count reflects writes made within the current
+// transaction.
+func countTowerKeys(b *bolt.Bucket) uint64 {
+	var n uint64
+	c := b.Cursor()
+	for k, _ := c.First(); k != nil; k, _ = c.Next() {
+		n++
+	}
+	return n
+}
+
+// towerPendingKey returns the key the pending dispatch of the justice
+// transaction of a blob is stored under.
+func towerPendingKey(hint [16]byte, sessionID uint64, seqNum uint16) []byte {
+	var key [26]byte
+	copy(key[:16], hint[:])
+	copy(key[16:], towerBlobKey(sessionID, seqNum))
+	return key[:]
+}
+
+// towerBlobKey returns the key a blob uploaded within the passed session is
+// stored under within its hint's bucket.
+func towerBlobKey(sessionID uint64, seqNum uint16) []byte {
+	var key [10]byte
+	byteOrder.PutUint64(key[:8], sessionID)
+	byteOrder.PutUint16(key[8:], seqNum)
+	return key[:]
+}
+
+func fetchTowerSession(tower *bolt.Bucket,
+	sessionID uint64) (*TowerSession, error) {
+
+	if tower == nil {
+		return nil, ErrTowerSessionNotFound
+	}
+	sessions := tower.Bucket(towerSessionBucket)
+	if sessions == nil {
+		return nil, ErrTowerSessionNotFound
+	}
+
+	var key [8]byte
+	byteOrder.PutUint64(key[:], sessionID)
+	sessionBytes := sessions.Get(key[:])
+	if sessionBytes == nil {
+		return nil, ErrTowerSessionNotFound
+	}
+
+	session := &TowerSession{ID: sessionID}
+	if err := deserializeTowerSession(bytes.NewReader(sessionBytes), session); err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+func putTowerSession(sessions *bolt.Bucket, session *TowerSession) error {
+	var b bytes.Buffer
+	if err := serializeTowerSession(&b, session); err != nil {
+		return err
+	}
+
+	var key [8]byte
+	byteOrder.PutUint64(key[:], session.ID)
+	return sessions.Put(key[:], b.Bytes())
+}
+
+func serializeTowerSession(w io.Writer, session *TowerSession) error {
+	if _, err := w.Write(session.ClientPub.SerializeCompressed()); err != nil {
+		return err
+	}
+
+	var scratch [8]byte
+	byteOrder.PutUint16(scratch[:2], session.MaxUpdates)
+	if _, err := w.Write(scratch[:2]); err != nil {
+		return err
+	}
+	byteOrder.PutUint16(scratch[:2], session.LastApplied)
+	if _, err := w.Write(scratch[:2]); err != nil {
+		return err
+	}
+	byteOrder.PutUint64(scratch[:], uint64(session.SweepFeeRate))
+	if _, err := w.Write(scratch[:]); err != nil {
+		return err
+	}
+	byteOrder.PutUint32(scratch[:4], session.RewardRate)
+	if _, err := w.Write(scratch[:4]); err != nil {
+		return err
+	}
+
+	return wire.WriteVarBytes(w, 0, session.RewardPkScript)
+}
+
+func deserializeTowerSession(r io.Reader, session *TowerSession) error {
+	var pub [33]byte
+	if _, err := io.ReadFull(r, pub[:]); err != nil {
+		return err
+	}
+	clientPub, err := btcec.ParsePubKey(pub[:], btcec.S256())
+	if err != nil {
+		return err
+	}
+	session.ClientPub = clientPub
+
+	var scratch [8]byte
+	if _, err := io.ReadFull(r, scratch[:2]); err != nil {
+		return err
+	}
+	session.MaxUpdates = byteOrder.Uint16(scratch[:2])
+	if _, err := io.ReadFull(r, scratch[:2]); err != nil {
+		return err
+	}
+	session.LastApplied = byteOrder.Uint16(scratch[:2])
+	if _, err := io.ReadFull(r, scratch[:]); err != nil {
+		return err
+	}
+	session.SweepFeeRate = btcutil.Amount(byteOrder.Uint64(scratch[:]))
+	if _, err := io.ReadFull(r, scratch[:4]); err != nil {
+		return err
+	}
+	session.RewardRate = byteOrder.Uint32(scratch[:4])
+
+	session.RewardPkScript, err = wire.ReadVarBytes(r, 0, 34,
+		"reward script")
+	return err
+}
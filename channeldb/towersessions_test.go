@@ -0,0 +1,320 @@
+package channeldb
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/wire"
+)
+
+func TestTowerSessions(t *testing.T) {
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+
+	clientPub := pubKey
+	_, otherPub := btcec.PrivKeyFromBytes(btcec.S256(), rev[:])
+
+	// Each client may only hold two sessions.
+	newSession := func() *TowerSession {
+		return &TowerSession{
+			ClientPub:      clientPub,
+			MaxUpdates:     2,
+			SweepFeeRate:   2500,
+			RewardRate:     10000,
+			RewardPkScript: bytes.Repeat([]byte{1}, 22),
+		}
+	}
+	first, second := newSession(), newSession()
+	if err := db.CreateTowerSession(first, 2); err != nil {
+		t.Fatalf("unable to create session: %v", err)
+	}
+	if err := db.CreateTowerSession(second, 2); err != nil {
+		t.Fatalf("unable to create session: %v", err)
+	}
+	if first.ID == second.ID {
+		t.Fatalf("sessions were assigned the same ID %v", first.ID)
+	}
+	if err := db.CreateTowerSession(newSession(), 2); err != ErrTowerSessionLimit {
+		t.Fatalf("expected ErrTowerSessionLimit, got %v", err)
+	}
+
+	stored, err := db.FetchTowerSession(first.ID)
+	if err != nil {
+		t.Fatalf("unable to fetch session: %v", err)
+	}
+	if !reflect.DeepEqual(stored, first) {
+		t.Fatalf("expected session %v, got %v", first, stored)
+	}
+	if _, err := db.FetchTowerSession(100); err != ErrTowerSessionNotFound {
+		t.Fatalf("expected ErrTowerSessionNotFound, got %v", err)
+	}
+
+	var hint [16]byte
+	hint[0] = 1
+	blob := bytes.Repeat([]byte{2}, 100)
+	const maxStorage = 250
+
+	// Updates may only be applied by the session's owner, in order.
+	_, err = db.ApplyTowerUpdate(otherPub, first.ID, 1, hint, blob,
+		maxStorage, maxStorage)
+	if err != ErrTowerSessionNotFound {
+		t.Fatalf("expected ErrTowerSessionNotFound, got %v", err)
+	}
+	_, err = db.ApplyTowerUpdate(clientPub, first.ID, 2, hint, blob,
+		maxStorage, maxStorage)
+	if err != ErrTowerSeqNumOutOfOrder {
+		t.Fatalf("expected ErrTowerSeqNumOutOfOrder, got %v", err)
+	}
+	for seqNum := uint16(1); seqNum <= 2; seqNum++ {
+		lastApplied, err := db.ApplyTowerUpdate(clientPub, first.ID,
+			seqNum, hint, blob, maxStorage, maxStorage)
+		if err != nil {
+			t.Fatalf("unable to apply update: %v", err)
+		}
+		if lastApplied != seqNum {
+			t.Fatalf("expected last applied %v, got %v", seqNum,
+				lastApplied)
+		}
+	}
+
+	// Resending an applied update is harmless, while the session has no
+	// room for further updates.
+	lastApplied, err := db.ApplyTowerUpdate(clientPub, first.ID, 1, hint,
+		blob, maxStorage, maxStorage)
+	if err != nil || lastApplied != 2 {
+		t.Fatalf("expected resent update to be ignored, got %v, %v",
+			lastApplied, err)
+	}
+	_, err = db.ApplyTowerUpdate(clientPub, first.ID, 3, hint, blob,
+		maxStorage, maxStorage)
+	if err != ErrTowerSessionFull {
+		t.Fatalf("expected ErrTowerSessionFull, got %v", err)
+	}
+
+	// A third blob would exceed the storage limit.
+	_, err = db.ApplyTowerUpdate(clientPub, second.ID, 1, hint, blob,
+		maxStorage, maxStorage)
+	if err != ErrTowerStorageFull {
+		t.Fatalf("expected ErrTowerStorageFull, got %v", err)
+	}
+
+	blobs, err := db.FetchTowerBlobs(hint)
+	if err != nil {
+		t.Fatalf("unable to fetch blobs: %v", err)
+	}
+	expected := []*TowerBlob{
+		{SessionID: first.ID, SeqNum: 1, Blob: blob},
+		{SessionID: first.ID, SeqNum: 2, Blob: blob},
+	}
+	if !reflect.DeepEqual(blobs, expected) {
+		t.Fatalf("expected blobs %v, got %v", expected, blobs)
+	}
+
+	// Deleting a blob releases its storage.
+	if err := db.DeleteTowerBlob(hint, first.ID, 1); err != nil {
+		t.Fatalf("unable to delete blob: %v", err)
+	}
+	stats, err := db.FetchTowerStats()
+	if err != nil {
+		t.Fatalf("unable to fetch stats: %v", err)
+	}
+	expectedStats := &TowerStats{
+		NumSessions: 2,
+		NumBlobs:    1,
+		StorageUsed: 100,
+	}
+	if !reflect.DeepEqual(stats, expectedStats) {
+		t.Fatalf("expected stats %v, got %v", expectedStats, stats)
+	}
+	_, err = db.ApplyTowerUpdate(clientPub, second.ID, 1, hint, blob,
+		maxStorage, maxStorage)
+	if err != nil {
+		t.Fatalf("unable to apply update: %v", err)
+	}
+}
+
+// TestTowerClientStorage tests that a single client can't exhaust the storage
+// of the watchtower, as the blobs it stores are limited by its own quota,
+// which is released as its blobs are deleted.
+func TestTowerClientStorage(t *testing.T) {
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+
+	_, otherPub := btcec.PrivKeyFromBytes(btcec.S256(), rev[:])
+	newSession := func(clientPub *btcec.PublicKey) *TowerSession {
+		session := &TowerSession{
+			ClientPub:      clientPub,
+			MaxUpdates:     10,
+			SweepFeeRate:   2500,
+			RewardRate:     10000,
+			RewardPkScript: bytes.Repeat([]byte{1}, 22),
+		}
+		if err := db.CreateTowerSession(session, 10); err != nil {
+			t.Fatalf("unable to create session: %v", err)
+		}
+		return session
+	}
+	greedy, modest := newSession(pubKey), newSession(otherPub)
+
+	var hint [16]byte
+	blob := bytes.Repeat([]byte{2}, 100)
+	const (
+		maxStorage       = 1000
+		maxClientStorage = 150
+	)
+
+	// The second blob of the greedy client would exceed its quota, though
+	// the tower has room to spare, which remains available to the other
+	// client.
+	_, err = db.ApplyTowerUpdate(pubKey, greedy.ID, 1, hint, blob,
+		maxStorage, maxClientStorage)
+	if err != nil {
+		t.Fatalf("unable to apply update: %v", err)
+	}
+	_, err = db.ApplyTowerUpdate(pubKey, greedy.ID, 2, hint, blob,
+		maxStorage, maxClientStorage)
+	if err != ErrTowerClientStorageFull {
+		t.Fatalf("expected ErrTowerClientStorageFull, got %v", err)
+	}
+	_, err = db.ApplyTowerUpdate(otherPub, modest.ID, 1, hint, blob,
+		maxStorage, maxClientStorage)
+	if err != nil {
+		t.Fatalf("unable to apply update: %v", err)
+	}
+
+	// Once its blob is deleted, the greedy client may store another.
+	if err := db.DeleteTowerBlob(hint, greedy.ID, 1); err != nil {
+		t.Fatalf("unable to delete blob: %v", err)
+	}
+	_, err = db.ApplyTowerUpdate(pubKey, greedy.ID, 2, hint, blob,
+		maxStorage, maxClientStorage)
+	if err != nil {
+		t.Fatalf("unable to apply update: %v", err)
+	}
+}
+
+// TestTowerPendingJustice tests that breaches pending the dispatch of their
+// justice transaction are retained across retries, keeping the height they
+// were first detected at, until their blob is deleted.
+func TestTowerPendingJustice(t *testing.T) {
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+
+	session := &TowerSession{
+		ClientPub:      pubKey,
+		MaxUpdates:     10,
+		SweepFeeRate:   2500,
+		RewardRate:     10000,
+		RewardPkScript: bytes.Repeat([]byte{1}, 22),
+	}
+	if err := db.CreateTowerSession(session, 10); err != nil {
+		t.Fatalf("unable to create session: %v", err)
+	}
+
+	var hint [16]byte
+	hint[0] = 1
+	blob := bytes.Repeat([]byte{2}, 100)
+	_, err = db.ApplyTowerUpdate(pubKey, session.ID, 1, hint, blob, 1000,
+		1000)
+	if err != nil {
+		t.Fatalf("unable to apply update: %v", err)
+	}
+
+	breachTx := wire.NewMsgTx(2)
+	breachTx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Index: 1}, nil, nil))
+	breachTx.AddTxOut(wire.NewTxOut(1000, []byte{0, 20, 1}))
+	pending := &TowerPendingJustice{
+		Hint:         hint,
+		SessionID:    session.ID,
+		SeqNum:       1,
+		BreachHeight: 100,
+		BreachTx:     breachTx,
+	}
+	if err := db.AddTowerPendingJustice(pending); err != nil {
+		t.Fatalf("unable to add pending justice: %v", err)
+	}
+
+	// Recording the breach again, such as once its dispatch failed anew,
+	// should keep the height it was first detected at.
+	retried := *pending
+	retried.BreachHeight = 101
+	if err := db.AddTowerPendingJustice(&retried); err != nil {
+		t.Fatalf("unable to add pending justice: %v", err)
+	}
+
+	stored, err := db.FetchTowerPendingJustice()
+	if err != nil {
+		t.Fatalf("unable to fetch pending justice: %v", err)
+	}
+	if len(stored) != 1 {
+		t.Fatalf("expected a single pending justice, got %v",
+			len(stored))
+	}
+	if stored[0].Hint != hint || stored[0].SessionID != session.ID ||
+		stored[0].SeqNum != 1 || stored[0].BreachHeight != 100 ||
+		stored[0].BreachTx.TxHash() != breachTx.TxHash() {
+
+		t.Fatalf("expected pending justice %v, got %v", pending,
+			stored[0])
+	}
+
+	storedBlob, err := db.FetchTowerBlob(hint, session.ID, 1)
+	if err != nil {
+		t.Fatalf("unable to fetch blob: %v", err)
+	}
+	if storedBlob == nil || !bytes.Equal(storedBlob.Blob, blob) {
+		t.Fatalf("expected blob %x, got %v", blob, storedBlob)
+	}
+
+	// Once its blob is deleted, the breach is no longer pending.
+	if err := db.DeleteTowerBlob(hint, session.ID, 1); err != nil {
+		t.Fatalf("unable to delete blob: %v", err)
+	}
+	stored, err = db.FetchTowerPendingJustice()
+	if err != nil {
+		t.Fatalf("unable to fetch pending justice: %v", err)
+	}
+	if len(stored) != 0 {
+		t.Fatalf("expected no pending justice, got %v", stored)
+	}
+}
+
+// TestTowerScanHeight tests that the height of the last block scanned by our
+// watchtower is only reported once it has been recorded, and that the latest
+// height recorded is returned.
+func TestTowerScanHeight(t *testing.T) {
+	db, cleanUp, err := makeTestDB()
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to make test db: %v", err)
+	}
+
+	if _, ok, err := db.FetchTowerScanHeight(); err != nil || ok {
+		t.Fatalf("expected no scan height, got %v: %v", ok, err)
+	}
+
+	for _, height := range []uint32{100, 101} {
+		if err := db.PutTowerScanHeight(height); err != nil {
+			t.Fatalf("unable to put scan height: %v", err)
+		}
+		stored, ok, err := db.FetchTowerScanHeight()
+		if err != nil {
+			t.Fatalf("unable to fetch scan height: %v", err)
+		}
+		if !ok || stored != height {
+			t.Fatalf("expected scan height %v, got %v", height,
+				stored)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/roasbeef/btcutil"
+)
+
+// selfPolicies returns the routing policy of our own direction of each of our
+// channels within the graph, keyed by the channel point of the channel.
+func selfPolicies(
+	graph *channeldb.ChannelGraph) (map[string]*channeldb.ChannelEdgePolicy, error) {
+
+	selfNode, err := graph.SourceNode()
+	if err != nil {
+		return nil, err
+	}
+
+	policies := make(map[string]*channeldb.ChannelEdgePolicy)
+	err = selfNode.ForEachChannel(nil, func(info *channeldb.ChannelEdgeInfo,
+		policy *channeldb.ChannelEdgePolicy) error {
+
+		policies[info.ChannelPoint.String()] = policy
+		return nil
+	})
+	switch {
+	// If we've yet to open any channels, then there won't be any edges
+	// within the graph.
+	case err == channeldb.ErrGraphNotFound:
+	case err == channeldb.ErrGraphNoEdgesFound:
+	case err != nil:
+		return nil, err
+	}
+
+	return policies, nil
+}
+
+// policiesByChanPoint sorts channel policies by their channel point.
+type policiesByChanPoint []*lnrpc.ChannelPolicy
+
+func (p policiesByChanPoint) Len() int      { return len(p) }
+func (p policiesByChanPoint) Swap(i, j int) { p[i], p[j] = p[j], p[i] }
+func (p policiesByChanPoint) Less(i, j int) bool {
+	return p[i].ChanPoint < p[j].ChanPoint
+}
+
+// marshalRoutingPolicy converts a routing policy within the graph to its
+// RPC representation.
+func marshalRoutingPolicy(policy *channeldb.ChannelEdgePolicy) *lnrpc.RoutingPolicy {
+	return &lnrpc.RoutingPolicy{
+		TimeLockDelta:    uint32(policy.TimeLockDelta),
+		MinHtlc:          int64(policy.MinHTLC),
+		FeeBaseMsat:      int64(policy.FeeBaseMSat),
+		FeeRateMilliMsat: int64(policy.FeeProportionalMillionths),
+	}
+}
+
+// validateRoutingPolicy ensures each attribute of the passed policy can be
+// advertised within a channel update.
+func validateRoutingPolicy(policy *lnrpc.RoutingPolicy) error {
+	switch {
+	case policy.TimeLockDelta > math.MaxUint16:
+		return fmt.Errorf("time lock delta of %v exceeds maximum of %v",
+			policy.TimeLockDelta, math.MaxUint16)
+
+	case policy.MinHtlc < 0 || policy.MinHtlc > math.MaxUint32:
+		return fmt.Errorf("min htlc of %v is out of range",
+			policy.MinHtlc)
+
+	case policy.FeeBaseMsat < 0 || policy.FeeBaseMsat > math.MaxUint32:
+		return fmt.Errorf("base fee of %v is out of range",
+			policy.FeeBaseMsat)
+
+	case policy.FeeRateMilliMsat < 0 ||
+		policy.FeeRateMilliMsat > math.MaxUint32:
+		return fmt.Errorf("fee rate of %v is out of range",
+			policy.FeeRateMilliMsat)
+	}
+
+	return nil
+}
+
+// diffChannelPolicies compares the imported routing policies against the
+// current policies of our channels, returning a description of each policy
+// which would change, along with the updated policies to be applied. Each
+// updated policy is timestamped with the passed time, or just after its
+// current policy if that's later, so the update supersedes it. If any of the
+// imported policies is invalid, or refers to a channel which isn't one of
+// ours, then an error is returned, so an import is applied either in full or
+// not at all.
+func diffChannelPolicies(current map[string]*channeldb.ChannelEdgePolicy,
+	imported []*lnrpc.ChannelPolicy, now time.Time) ([]*lnrpc.ChannelPolicyDiff,
+	[]*channeldb.ChannelEdgePolicy, error) {
+
+	var (
+		diffs   []*lnrpc.ChannelPolicyDiff
+		updates []*channeldb.ChannelEdgePolicy
+	)
+	seen := make(map[string]struct{})
+	for _, p := range imported {
+		if _, ok := seen[p.ChanPoint]; ok {
+			return nil, nil, fmt.Errorf("duplicate policy for "+
+				"channel %v", p.ChanPoint)
+		}
+		seen[p.ChanPoint] = struct{}{}
+
+		cur, ok := current[p.ChanPoint]
+		if !ok {
+			return nil, nil, fmt.Errorf("channel %v isn't one of "+
+				"our channels", p.ChanPoint)
+		}
+		if p.Policy == nil {
+			return nil, nil, fmt.Errorf("no policy specified for "+
+				"channel %v", p.ChanPoint)
+		}
+		if err := validateRoutingPolicy(p.Policy); err != nil {
+			return nil, nil, fmt.Errorf("invalid policy for "+
+				"channel %v: %v", p.ChanPoint, err)
+		}
+
+		oldPolicy := marshalRoutingPolicy(cur)
+		if *oldPolicy == *p.Policy {
+			continue
+		}
+
+		// Channel updates are timestamped in seconds, so we'll ensure
+		// the new policy's timestamp is strictly later than the
+		// current one's.
+		lastUpdate := time.Unix(now.Unix(), 0)
+		if !lastUpdate.After(cur.LastUpdate) {
+			lastUpdate = time.Unix(cur.LastUpdate.Unix()+1, 0)
+		}
+
+		update := *cur
+		update.LastUpdate = lastUpdate
+		update.TimeLockDelta = uint16(p.Policy.TimeLockDelta)
+		update.MinHTLC = btcutil.Amount(p.Policy.MinHtlc)
+		update.FeeBaseMSat = btcutil.Amount(p.Policy.FeeBaseMsat)
+		update.FeeProportionalMillionths = btcutil.Amount(
+			p.Policy.FeeRateMilliMsat,
+		)
+
+		diffs = append(diffs, &lnrpc.ChannelPolicyDiff{
+			ChanPoint: p.ChanPoint,
+			OldPolicy: oldPolicy,
+			NewPolicy: p.Policy,
+		})
+		updates = append(updates, &update)
+	}
+
+	return diffs, updates, nil
+}
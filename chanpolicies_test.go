@@ -0,0 +1,130 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnrpc"
+)
+
+func TestDiffChannelPolicies(t *testing.T) {
+	now := time.Unix(1490000000, 0)
+	current := map[string]*channeldb.ChannelEdgePolicy{
+		"a:0": {
+			ChannelID:                 1,
+			LastUpdate:                now.Add(-time.Hour),
+			TimeLockDelta:             144,
+			MinHTLC:                   1,
+			FeeBaseMSat:               1000,
+			FeeProportionalMillionths: 1,
+		},
+		"b:1": {
+			ChannelID:                 2,
+			LastUpdate:                now,
+			Flags:                     1,
+			TimeLockDelta:             144,
+			MinHTLC:                   1,
+			FeeBaseMSat:               1000,
+			FeeProportionalMillionths: 1,
+		},
+	}
+	unchanged := &lnrpc.RoutingPolicy{
+		TimeLockDelta:    144,
+		MinHtlc:          1,
+		FeeBaseMsat:      1000,
+		FeeRateMilliMsat: 1,
+	}
+	changed := &lnrpc.RoutingPolicy{
+		TimeLockDelta:    40,
+		MinHtlc:          1,
+		FeeBaseMsat:      0,
+		FeeRateMilliMsat: 500,
+	}
+
+	// Policies which are unchanged shouldn't be reported or updated.
+	diffs, updates, err := diffChannelPolicies(current,
+		[]*lnrpc.ChannelPolicy{
+			{ChanPoint: "a:0", Policy: unchanged},
+			{ChanPoint: "b:1", Policy: unchanged},
+		}, now)
+	if err != nil {
+		t.Fatalf("unable to diff policies: %v", err)
+	}
+	if len(diffs) != 0 || len(updates) != 0 {
+		t.Fatalf("expected no changes, got %v diffs and %v updates",
+			len(diffs), len(updates))
+	}
+
+	// Both changed policies should be reported, and updated with a
+	// timestamp which supersedes their current policy.
+	diffs, updates, err = diffChannelPolicies(current,
+		[]*lnrpc.ChannelPolicy{
+			{ChanPoint: "a:0", Policy: changed},
+			{ChanPoint: "b:1", Policy: changed},
+		}, now)
+	if err != nil {
+		t.Fatalf("unable to diff policies: %v", err)
+	}
+	if len(diffs) != 2 || len(updates) != 2 {
+		t.Fatalf("expected two changes, got %v diffs and %v updates",
+			len(diffs), len(updates))
+	}
+	for i, chanPoint := range []string{"a:0", "b:1"} {
+		diff, update := diffs[i], updates[i]
+		if diff.ChanPoint != chanPoint ||
+			*diff.OldPolicy != *unchanged ||
+			*diff.NewPolicy != *changed {
+
+			t.Fatalf("unexpected diff: %v", diff)
+		}
+
+		cur := current[chanPoint]
+		if update.ChannelID != cur.ChannelID ||
+			update.Flags != cur.Flags ||
+			*marshalRoutingPolicy(update) != *changed {
+
+			t.Fatalf("unexpected update for %v: %v", chanPoint,
+				update)
+		}
+		if !update.LastUpdate.After(cur.LastUpdate) {
+			t.Fatalf("update for %v at %v doesn't supersede policy "+
+				"at %v", chanPoint, update.LastUpdate,
+				cur.LastUpdate)
+		}
+	}
+
+	// Any invalid policy should cause the entire import to be rejected.
+	invalidImports := [][]*lnrpc.ChannelPolicy{
+		{
+			{ChanPoint: "a:0", Policy: changed},
+			{ChanPoint: "c:2", Policy: changed},
+		},
+		{
+			{ChanPoint: "a:0", Policy: changed},
+			{ChanPoint: "a:0", Policy: unchanged},
+		},
+		{
+			{ChanPoint: "a:0"},
+		},
+		{
+			{ChanPoint: "a:0", Policy: &lnrpc.RoutingPolicy{
+				TimeLockDelta: 1 << 16,
+			}},
+		},
+		{
+			{ChanPoint: "a:0", Policy: &lnrpc.RoutingPolicy{
+				FeeBaseMsat: -1,
+			}},
+		},
+	}
+	for i, imported := range invalidImports {
+		diffs, updates, err := diffChannelPolicies(current, imported,
+			now)
+		if err == nil {
+			t.Fatalf("#%v: expected invalid import to be rejected, "+
+				"got %v diffs and %v updates", i, len(diffs),
+				len(updates))
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"sync"
+
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/chanbackup"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// chanRecovery recovers the funds of channels restored from a static channel
+// backup. As we hold none of the state of such a channel, we're unable to
+// safely broadcast our own commitment transaction, so we instead ask the
+// remote party to force close the channel by broadcasting theirs. Their
+// commitment transaction pays our balance to our commitment key without
+// delay, which, as it's a key of our wallet, is then credited to the wallet.
+//
+// Restored channels are tracked in memory only, so a restore must be repeated
+// following a restart for channels which haven't yet been closed.
+type chanRecovery struct {
+	server *server
+
+	// pending maps the identity key of each remote party to the channels
+	// with it we've asked it to close, which have yet to be spent.
+	pending map[serializedPubKey]map[wire.OutPoint]struct{}
+	sync.Mutex
+}
+
+// newChanRecovery creates a new chanRecovery.
+func newChanRecovery(s *server) *chanRecovery {
+	return &chanRecovery{
+		server:  s,
+		pending: make(map[serializedPubKey]map[wire.OutPoint]struct{}),
+	}
+}
+
+// recoverChannel begins the recovery of the channel within the passed backup.
+// If we're connected to the remote party, then it's asked to force close the
+// channel right away. Otherwise, we connect to it using its address from the
+// channel graph, and ask once connected.
+func (c *chanRecovery) recoverChannel(backup *chanbackup.Single) error {
+	chanPoint := backup.ChanPoint
+	peerKey := newSerializedKey(backup.RemoteNodePub)
+
+	c.Lock()
+	if _, ok := c.pending[peerKey][chanPoint]; ok {
+		c.Unlock()
+		return nil
+	}
+
+	// We'll stop asking the remote party to close the channel once its
+	// funding output has been spent.
+	spendNtfn, err := c.server.chainNotifier.RegisterSpendNtfn(&chanPoint)
+	if err != nil {
+		c.Unlock()
+		return err
+	}
+
+	if _, ok := c.pending[peerKey]; !ok {
+		c.pending[peerKey] = make(map[wire.OutPoint]struct{})
+	}
+	c.pending[peerKey][chanPoint] = struct{}{}
+	c.Unlock()
+
+	go c.waitForClose(backup, spendNtfn.Spend)
+
+	if p, err := c.server.findPeer(backup.RemoteNodePub); err == nil {
+		p.queueMsg(lnwire.NewForceCloseRequest(chanPoint), nil)
+		return nil
+	}
+
+	node, err := c.server.chanDB.ChannelGraph().FetchLightningNode(
+		backup.RemoteNodePub)
+	if err != nil || node.Address == nil {
+		srvrLog.Warnf("No known address for %x, waiting for it to "+
+			"connect to recover ChannelPoint(%v)",
+			backup.RemoteNodePub.SerializeCompressed(), chanPoint)
+		return nil
+	}
+
+	lnAddr := &lnwire.NetAddress{
+		IdentityKey: backup.RemoteNodePub,
+		Address:     node.Address,
+	}
+	go func() {
+		if err := c.server.ConnectToPeer(lnAddr, true); err != nil {
+			srvrLog.Errorf("unable to connect to %v to recover "+
+				"ChannelPoint(%v): %v", lnAddr, chanPoint, err)
+		}
+	}()
+
+	return nil
+}
+
+// waitForClose waits for the funding output of the restored channel to be
+// spent, after which the remote party is no longer asked to close it.
+func (c *chanRecovery) waitForClose(backup *chanbackup.Single,
+	spend <-chan *chainntnfs.SpendDetail) {
+
+	select {
+	case detail, ok := <-spend:
+		if !ok {
+			return
+		}
+
+		srvrLog.Infof("Restored ChannelPoint(%v) closed by txid=%v, "+
+			"our balance is paid to our wallet", backup.ChanPoint,
+			detail.SpenderTxHash)
+	case <-c.server.quit:
+		return
+	}
+
+	peerKey := newSerializedKey(backup.RemoteNodePub)
+
+	c.Lock()
+	delete(c.pending[peerKey], backup.ChanPoint)
+	if len(c.pending[peerKey]) == 0 {
+		delete(c.pending, peerKey)
+	}
+	c.Unlock()
+}
+
+// peerConnected asks the newly connected peer to force close each restored
+// channel with it which is yet to be closed.
+func (c *chanRecovery) peerConnected(p *peer) {
+	peerKey := newSerializedKey(p.addr.IdentityKey)
+
+	c.Lock()
+	defer c.Unlock()
+
+	for chanPoint := range c.pending[peerKey] {
+		srvrLog.Infof("Requesting %v force close restored "+
+			"ChannelPoint(%v)", p, chanPoint)
+
+		p.queueMsg(lnwire.NewForceCloseRequest(chanPoint), nil)
+	}
+}
+
+// processForceCloseRequest force closes our channel with the peer which has
+// lost its state for the channel, broadcasting our latest commitment
+// transaction.
+func (c *chanRecovery) processForceCloseRequest(p *peer,
+	msg *lnwire.ForceCloseRequest) {
+
+	chanPoint := msg.ChannelPoint
+	channel, err := c.server.rpcServer.fetchActiveChannel(chanPoint)
+	if err != nil {
+		peerLog.Warnf("%v requested force close of unknown "+
+			"ChannelPoint(%v)", p, chanPoint)
+		return
+	}
+
+	// Only the remote party of the channel may request that we close it.
+	snapshot := channel.StateSnapshot()
+	if !snapshot.RemoteIdentity.IsEqual(p.addr.IdentityKey) {
+		peerLog.Warnf("%v requested force close of ChannelPoint(%v), "+
+			"which belongs to another peer", p, chanPoint)
+		return
+	}
+
+	peerLog.Warnf("%v has lost its state for ChannelPoint(%v), force "+
+		"closing the channel at its request", p, chanPoint)
+
+	closingTxid, err := c.server.rpcServer.forceCloseChan(channel)
+	if err != nil {
+		peerLog.Errorf("unable to force close ChannelPoint(%v): %v",
+			chanPoint, err)
+		return
+	}
+	c.server.chanHistory.closeInitiated(channel,
+		"unilateral, requested by remote")
+
+	go func() {
+		err := c.server.rpcServer.resolveForceClose(channel,
+			&chanPoint, closingTxid)
+		if err != nil && err != errResolveAborted {
+			peerLog.Errorf("unable to resolve force close of "+
+				"ChannelPoint(%v): %v", chanPoint, err)
+		}
+	}()
+}
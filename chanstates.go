@@ -0,0 +1,167 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/lnwallet"
+)
+
+// machineTransitions are the transitions between the phases of a channel's
+// commitment state machine taken during the exchange of updates, signatures,
+// and revocations, along with the event triggering each transition.
+var machineTransitions = []struct {
+	from, to lnwallet.MachinePhase
+	event    string
+}{
+	{
+		from:  lnwallet.PhaseIdle,
+		to:    lnwallet.PhasePendingSign,
+		event: "add",
+	},
+	{
+		from:  lnwallet.PhasePendingSign,
+		to:    lnwallet.PhaseAwaitingRevocation,
+		event: "sign",
+	},
+	{
+		from:  lnwallet.PhaseAwaitingRevocation,
+		to:    lnwallet.PhaseAwaitingSig,
+		event: "revoke_and_ack",
+	},
+	{
+		from:  lnwallet.PhaseAwaitingRevocation,
+		to:    lnwallet.PhaseIdle,
+		event: "revoke_and_ack",
+	},
+	{
+		from:  lnwallet.PhaseIdle,
+		to:    lnwallet.PhaseAwaitingSig,
+		event: "remote add",
+	},
+	{
+		from:  lnwallet.PhaseAwaitingSig,
+		to:    lnwallet.PhasePendingRevocation,
+		event: "commit_sig",
+	},
+	{
+		from:  lnwallet.PhasePendingRevocation,
+		to:    lnwallet.PhasePendingSign,
+		event: "revoke",
+	},
+	{
+		from:  lnwallet.PhasePendingRevocation,
+		to:    lnwallet.PhaseIdle,
+		event: "revoke",
+	},
+	{
+		from:  lnwallet.PhaseIdle,
+		to:    lnwallet.PhaseShutdownPending,
+		event: "shutdown",
+	},
+	{
+		from:  lnwallet.PhaseShutdownPending,
+		to:    lnwallet.PhaseClosing,
+		event: "closing_signed",
+	},
+}
+
+// newChannelStateMachine returns the RPC representation of the status of the
+// state machine of the passed channel.
+func newChannelStateMachine(channel *lnwallet.LightningChannel,
+	remotePub string) *lnrpc.ChannelStateMachine {
+
+	status := channel.MachineStatus()
+	return &lnrpc.ChannelStateMachine{
+		ChannelPoint:       channel.ChannelPoint().String(),
+		RemotePubkey:       remotePub,
+		Phase:              string(status.Phase),
+		LocalTailHeight:    status.LocalTailHeight,
+		LocalTipHeight:     status.LocalTipHeight,
+		RemoteTailHeight:   status.RemoteTailHeight,
+		RemoteTipHeight:    status.RemoteTipHeight,
+		LocalLogIndex:      status.LocalLogIndex,
+		RemoteLogIndex:     status.RemoteLogIndex,
+		UnsignedUpdates:    status.UnsignedUpdates,
+		UncommittedUpdates: status.UncommittedUpdates,
+		AwaitingRevocation: status.AwaitingRevocation,
+		RevocationWindow:   uint32(status.RevocationWindow),
+		LocalShutdown:      status.LocalShutdown,
+		RemoteShutdown:     status.RemoteShutdown,
+	}
+}
+
+// channelStateMachines sorts channel state machines by channel point.
+type channelStateMachines []*lnrpc.ChannelStateMachine
+
+func (c channelStateMachines) Len() int      { return len(c) }
+func (c channelStateMachines) Swap(i, j int) { c[i], c[j] = c[j], c[i] }
+func (c channelStateMachines) Less(i, j int) bool {
+	return c[i].ChannelPoint < c[j].ChannelPoint
+}
+
+// sortChannelStateMachines sorts the passed state machines by channel point,
+// so exports of the same channels are comparable.
+func sortChannelStateMachines(machines []*lnrpc.ChannelStateMachine) {
+	sort.Sort(channelStateMachines(machines))
+}
+
+// channelStatesDOT renders the phases of the commitment state machine, and
+// the phase each of the passed channels is in, as a graph in the DOT
+// language. Phases occupied by at least one channel are highlighted.
+func channelStatesDOT(machines []*lnrpc.ChannelStateMachine) string {
+	occupied := make(map[string]struct{})
+	for _, machine := range machines {
+		occupied[machine.Phase] = struct{}{}
+	}
+
+	var b bytes.Buffer
+	b.WriteString("digraph channel_states {\n")
+	b.WriteString("\trankdir=LR;\n")
+	b.WriteString("\tnode [shape=ellipse];\n")
+
+	// Each phase is declared once, in the order it's first encountered,
+	// followed by any phase outside of the regular transitions which a
+	// channel is stuck in.
+	declared := make(map[string]struct{})
+	declare := func(phase string) {
+		if _, ok := declared[phase]; ok {
+			return
+		}
+		declared[phase] = struct{}{}
+
+		if _, ok := occupied[phase]; ok {
+			fmt.Fprintf(&b, "\t%q [style=filled];\n", phase)
+		} else {
+			fmt.Fprintf(&b, "\t%q;\n", phase)
+		}
+	}
+	for _, transition := range machineTransitions {
+		declare(string(transition.from))
+		declare(string(transition.to))
+	}
+	for _, machine := range machines {
+		declare(machine.Phase)
+	}
+
+	for _, transition := range machineTransitions {
+		fmt.Fprintf(&b, "\t%q -> %q [label=%q];\n", transition.from,
+			transition.to, transition.event)
+	}
+
+	for _, machine := range machines {
+		fmt.Fprintf(&b, "\t%q [shape=box, label=%q];\n",
+			machine.ChannelPoint, fmt.Sprintf("%v\nlocal=%v/%v "+
+				"remote=%v/%v", machine.ChannelPoint,
+				machine.LocalTailHeight, machine.LocalTipHeight,
+				machine.RemoteTailHeight,
+				machine.RemoteTipHeight))
+		fmt.Fprintf(&b, "\t%q -> %q [style=dashed];\n",
+			machine.ChannelPoint, machine.Phase)
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+)
+
+// TestChannelStatesDOT tests that channels are attached to the phase of the
+// state machine they're in, and that occupied phases are highlighted,
+// including those outside of the regular transitions.
+func TestChannelStatesDOT(t *testing.T) {
+	machines := []*lnrpc.ChannelStateMachine{
+		{
+			ChannelPoint:     "bb:1",
+			Phase:            "frozen",
+			LocalTailHeight:  7,
+			LocalTipHeight:   7,
+			RemoteTailHeight: 7,
+			RemoteTipHeight:  8,
+		},
+		{
+			ChannelPoint: "aa:0",
+			Phase:        "awaiting_revocation",
+		},
+	}
+	sortChannelStateMachines(machines)
+	if machines[0].ChannelPoint != "aa:0" {
+		t.Fatalf("expected machines to be sorted by channel point, "+
+			"got %v first", machines[0].ChannelPoint)
+	}
+
+	dot := channelStatesDOT(machines)
+	if !strings.HasPrefix(dot, "digraph channel_states {\n") ||
+		!strings.HasSuffix(dot, "}\n") {
+
+		t.Fatalf("malformed graph: %v", dot)
+	}
+
+	expectedLines := []string{
+		`	"idle";`,
+		`	"awaiting_revocation" [style=filled];`,
+		`	"frozen" [style=filled];`,
+		`	"idle" -> "pending_sign" [label="add"];`,
+		`	"bb:1" [shape=box, label="bb:1\nlocal=7/7 remote=7/8"];`,
+		`	"bb:1" -> "frozen" [style=dashed];`,
+		`	"aa:0" -> "awaiting_revocation" [style=dashed];`,
+	}
+	lines := strings.Split(dot, "\n")
+	for _, expected := range expectedLines {
+		found := false
+		for _, line := range lines {
+			if line == expected {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Fatalf("expected line %q within graph: %v", expected,
+				dot)
+		}
+	}
+
+	// Each phase must be declared exactly once.
+	if n := strings.Count(dot, "\t\"idle\";\n"); n != 1 {
+		t.Fatalf("expected idle to be declared once, got %v", n)
+	}
+}
@@ -0,0 +1,274 @@
+package main
+
+import (
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/wire"
+)
+
+const (
+	// dataLossProtectFeature is the name of the local feature advertised
+	// by nodes which exchange ChannelReestablish messages upon
+	// reconnecting, allowing a node which has lost its channel state to
+	// detect it.
+	dataLossProtectFeature = "data-loss-protect"
+
+	// dataLossProtectFeatureIndex is the index of dataLossProtectFeature
+	// within the local feature vector.
+	dataLossProtectFeatureIndex = 6
+)
+
+// sendChanSyncMsgs sends the peer our view of each active channel we share
+// with it, if it supports data loss protection.
+func (p *peer) sendChanSyncMsgs() {
+	if !p.localSharedFeatures.IsActive(dataLossProtectFeature) {
+		return
+	}
+
+	p.activeChanMtx.RLock()
+	defer p.activeChanMtx.RUnlock()
+
+	for chanPoint, channel := range p.activeChannels {
+		msg, err := channel.ChanSyncMsg()
+		if err != nil {
+			peerLog.Errorf("unable to create sync message for "+
+				"ChannelPoint(%v): %v", chanPoint, err)
+			continue
+		}
+
+		p.queueMsg(msg, nil)
+	}
+}
+
+// handleChanSyncMsg compares the peer's view of a channel against our own. If
+// the peer proves we've lost our state for the channel, then the channel is
+// frozen, and we ask the peer to force close it, which pays our balance to
+// our wallet. If instead the peer has lost its state, then it'll ask us to
+// force close the channel once it processes our own view. Otherwise, we
+// retransmit any revocation or signature, along with the updates it covers,
+// which didn't reach the peer before we were disconnected, so the channel
+// doesn't stall awaiting it.
+func (p *peer) handleChanSyncMsg(msg *lnwire.ChannelReestablish) {
+	chanPoint := msg.ChannelPoint
+
+	p.activeChanMtx.RLock()
+	channel, ok := p.activeChannels[chanPoint]
+	p.activeChanMtx.RUnlock()
+	if !ok {
+		peerLog.Warnf("%v sent sync message for unknown "+
+			"ChannelPoint(%v)", p, chanPoint)
+		return
+	}
+
+	retransmission, err := channel.ProcessChanSyncMsg(msg)
+	switch err {
+	case nil:
+		if retransmission.RevocationHeight == 0 &&
+			retransmission.CommitHeight == 0 {
+
+			peerLog.Debugf("ChannelPoint(%v) in sync with %v",
+				chanPoint, p)
+		} else {
+			p.retransmitChanMsgs(&chanPoint, retransmission)
+		}
+
+		// Our latest signature may also have been lost along with the
+		// updates it covers as we each reloaded the channel, in which
+		// case the updates are replayed by the channel's htlcManager,
+		// as it drives the channel's state machine.
+		p.htlcManMtx.Lock()
+		upstreamLink, ok := p.htlcManagers[chanPoint]
+		p.htlcManMtx.Unlock()
+		if ok {
+			upstreamLink <- msg
+		}
+
+	case lnwallet.ErrCommitSyncDataLoss:
+		peerLog.Errorf("We've lost our state for ChannelPoint(%v), "+
+			"our commitment is likely revoked and won't be "+
+			"broadcast, requesting %v force close the channel",
+			chanPoint, p)
+
+		p.queueMsg(lnwire.NewForceCloseRequest(chanPoint), nil)
+
+	case lnwallet.ErrCommitSyncRemoteDataLoss:
+		peerLog.Warnf("%v has lost its state for ChannelPoint(%v), "+
+			"awaiting its request to force close the channel", p,
+			chanPoint)
+
+	default:
+		peerLog.Errorf("unable to sync ChannelPoint(%v) with %v: %v",
+			chanPoint, p, err)
+	}
+}
+
+// retransmitChanMsgs resends the peer the revocation and signature it's
+// missing for the channel, the signature along with the updates it covers.
+// The messages are read back from the database, as they may have been sent
+// before we restarted.
+func (p *peer) retransmitChanMsgs(chanPoint *wire.OutPoint,
+	retransmission *lnwallet.ChanSyncRetransmission) {
+
+	if retransmission.RevocationHeight != 0 {
+		p.retransmitRevocation(chanPoint,
+			retransmission.RevocationHeight)
+	}
+	if retransmission.CommitHeight != 0 {
+		p.retransmitCommitSig(chanPoint, retransmission.CommitHeight)
+	}
+}
+
+// retransmitRevocation resends the peer our revocation at the passed height
+// for the channel.
+func (p *peer) retransmitRevocation(chanPoint *wire.OutPoint, height uint64) {
+	lastMsgs, err := p.server.chanDB.FetchLastChanMsgs(chanPoint)
+	if err != nil {
+		peerLog.Errorf("unable to fetch last messages sent for "+
+			"ChannelPoint(%v): %v", chanPoint, err)
+		return
+	}
+
+	for _, lastMsg := range lastMsgs {
+		if _, ok := lastMsg.Msg.(*lnwire.RevokeAndAck); !ok {
+			continue
+		}
+		if lastMsg.Height != height {
+			continue
+		}
+
+		peerLog.Infof("Retransmitting %T at height %v for "+
+			"ChannelPoint(%v) to %v", lastMsg.Msg, lastMsg.Height,
+			chanPoint, p)
+
+		p.queueMsg(lastMsg.Msg, nil)
+		return
+	}
+
+	// The revocation may not have been retained, for instance if it was
+	// sent before the node was upgraded, in which case the channel can't
+	// resume.
+	peerLog.Errorf("unable to retransmit revocation at height %v for "+
+		"ChannelPoint(%v) to %v, no such message retained", height,
+		chanPoint, p)
+}
+
+// retransmitCommitSig resends the peer our signature for its commitment at
+// the passed height, preceded by the updates it covers, as sent originally.
+// We must still hold the commitment, so the updates remain applied to the
+// channel as they were sent.
+func (p *peer) retransmitCommitSig(chanPoint *wire.OutPoint, height uint64) {
+	commit, err := p.server.chanDB.FetchPendingCommit(chanPoint)
+	if err != nil {
+		peerLog.Errorf("unable to fetch pending commitment of "+
+			"ChannelPoint(%v): %v", chanPoint, err)
+		return
+	}
+	if commit == nil || commit.Height != height {
+		peerLog.Errorf("unable to retransmit signature at height %v "+
+			"for ChannelPoint(%v) to %v, no such commitment "+
+			"retained", height, chanPoint, p)
+		return
+	}
+
+	peerLog.Infof("Retransmitting %v updates and signature at height %v "+
+		"for ChannelPoint(%v) to %v", len(commit.Updates), height,
+		chanPoint, p)
+
+	for _, update := range commit.Updates {
+		p.queueMsg(update.Msg, nil)
+	}
+	p.queueMsg(commit.CommitSig, nil)
+}
+
+// replayPendingCommit replays the updates covered by the latest commitment
+// we've signed for the peer, should we each have lost the commitment when
+// reloading the channel, as our signature never reached the peer. The updates
+// are re-applied to the channel and retransmitted, then covered by a new
+// signature. The passed message is the peer's view of the channel.
+func (p *peer) replayPendingCommit(state *commitmentState,
+	msg *lnwire.ChannelReestablish) {
+
+	commit, err := p.server.chanDB.FetchPendingCommit(state.chanPoint)
+	if err != nil {
+		peerLog.Errorf("unable to fetch pending commitment of "+
+			"ChannelPoint(%v): %v", state.chanPoint, err)
+		return
+	}
+	if commit == nil || len(commit.Updates) == 0 {
+		return
+	}
+
+	// The peer holds the commitment if its own commitment chain has
+	// reached the commitment's height. If instead we still hold the
+	// commitment, then our signature is retransmitted as is.
+	_, remoteHeight := state.channel.CommitHeights()
+	if msg.NextLocalCommitHeight > commit.Height ||
+		remoteHeight >= commit.Height {
+
+		return
+	}
+
+	peerLog.Infof("Replaying %v updates covered by lost signature at "+
+		"height %v for ChannelPoint(%v) to %v", len(commit.Updates),
+		commit.Height, state.chanPoint, p)
+
+	for _, update := range state.channel.ReplayUpdates(commit.Updates) {
+		p.sendUpdate(state, update.Msg, update.PaymentHash)
+	}
+
+	if err := p.updateCommitTx(state, false); err != nil {
+		peerLog.Errorf("unable to update commitment: %v", err)
+		p.Disconnect()
+	}
+}
+
+// sendUpdate sends the peer an update of the channel, recording it so it's
+// retained along with the next commitment we sign. The payment hash is that
+// of the HTLC the update adds, settles or fails, if any.
+func (p *peer) sendUpdate(state *commitmentState, msg lnwire.Message,
+	paymentHash [32]byte) {
+
+	state.unsignedUpdates = append(state.unsignedUpdates,
+		&channeldb.PendingUpdate{
+			PaymentHash: paymentHash,
+			Msg:         msg,
+		})
+	p.queueMsg(msg, nil)
+}
+
+// persistPendingCommit records the commitment we've just signed for the peer,
+// along with the updates it covers, so they may be replayed should our
+// signature not reach the peer before we're disconnected. Failing to record
+// the commitment doesn't prevent the signature from being sent.
+func (p *peer) persistPendingCommit(state *commitmentState,
+	commitSig *lnwire.CommitSig) {
+
+	_, remoteHeight := state.channel.CommitHeights()
+	commit := &channeldb.PendingCommit{
+		Height:    remoteHeight,
+		Updates:   state.unsignedUpdates,
+		CommitSig: commitSig,
+	}
+	state.unsignedUpdates = nil
+
+	err := p.server.chanDB.PutPendingCommit(state.chanPoint, commit)
+	if err != nil {
+		peerLog.Errorf("unable to persist pending commitment of "+
+			"ChannelPoint(%v): %v", state.chanPoint, err)
+	}
+}
+
+// persistLastChanMsg records a revocation about to be sent to the peer for
+// the channel, so it may be retransmitted should it not reach the peer before
+// we're disconnected. Failing to record the message doesn't prevent it from
+// being sent.
+func (p *peer) persistLastChanMsg(chanPoint *wire.OutPoint, height uint64,
+	msg lnwire.Message) {
+
+	err := p.server.chanDB.PutLastChanMsg(chanPoint, height, msg)
+	if err != nil {
+		peerLog.Errorf("unable to persist %T for ChannelPoint(%v): %v",
+			msg, chanPoint, err)
+	}
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"encoding/csv"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
@@ -12,6 +13,7 @@ import (
 	"os/exec"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/awalterschulze/gographviz"
 	"github.com/golang/protobuf/jsonpb"
@@ -113,6 +115,11 @@ var sendCoinsCommand = cli.Command{
 			Name:  "amt",
 			Usage: "the number of bitcoin denominated in satoshis to send",
 		},
+		cli.BoolFlag{
+			Name: "override_whitelist",
+			Usage: "pay to the address even if it's outside the " +
+				"node's sweep whitelist",
+		},
 	},
 	Action: sendCoins,
 }
@@ -158,8 +165,9 @@ func sendCoins(ctx *cli.Context) error {
 	defer cleanUp()
 
 	req := &lnrpc.SendCoinsRequest{
-		Addr:   addr,
-		Amount: amt,
+		Addr:              addr,
+		Amount:            amt,
+		OverrideWhitelist: ctx.Bool("override_whitelist"),
 	}
 	txid, err := client.SendCoins(ctxb, req)
 	if err != nil {
@@ -179,6 +187,13 @@ var sendManyCommand = cli.Command{
 		"   'send-json-string' decodes addresses and the amount to send " +
 		"respectively in the following format.\n" +
 		`   '{"ExampleAddr": NumCoinsInSatoshis, "SecondAddr": NumCoins}'`,
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name: "override_whitelist",
+			Usage: "pay to the addresses even if they're outside " +
+				"the node's sweep whitelist",
+		},
+	},
 	Action: sendMany,
 }
 
@@ -195,7 +210,8 @@ func sendMany(ctx *cli.Context) error {
 	defer cleanUp()
 
 	txid, err := client.SendMany(ctxb, &lnrpc.SendManyRequest{
-		AddrToAmount: amountToAddr,
+		AddrToAmount:      amountToAddr,
+		OverrideWhitelist: ctx.Bool("override_whitelist"),
 	})
 	if err != nil {
 		return err
@@ -208,7 +224,7 @@ func sendMany(ctx *cli.Context) error {
 var connectCommand = cli.Command{
 	Name:      "connect",
 	Usage:     "connect to a remote lnd peer",
-	ArgsUsage: "<pubkey>@host",
+	ArgsUsage: "<pubkey or alias>@host",
 	Flags: []cli.Flag{
 		cli.BoolFlag{
 			Name: "perm",
@@ -229,7 +245,7 @@ func connectPeer(ctx *cli.Context) error {
 	splitAddr := strings.Split(targetAddress, "@")
 	if len(splitAddr) != 2 {
 		return fmt.Errorf("target address expected in format: " +
-			"pubkey@host:port")
+			"pubkey@host:port or alias@host:port")
 	}
 
 	addr := &lnrpc.LightningAddress{
@@ -257,9 +273,12 @@ var openChannelCommand = cli.Command{
 	Description: "Attempt to open a new channel to an existing peer with the key node-key, " +
 		"optionally blocking until the channel is 'open'. " +
 		"The channel will be initialized with local-amt satoshis local and push-amt " +
-		"satoshis for the remote node. Once the " +
+		"satoshis for the remote node. If remote_amt is set, the remote node " +
+		"is asked to contribute that many satoshis as well. Once the " +
 		"channel is open, a channelPoint (txid:vout) of the funding " +
-		"output is returned. NOTE: peer_id and node_key are " +
+		"output is returned. The node key may also be given as the " +
+		"node's alias, or a unique prefix of its public key. " +
+		"NOTE: peer_id and node_key are " +
 		"mutually exclusive, only one should be used, not both.",
 	ArgsUsage: "node-key local-amt push-amt [num-confs]",
 	Flags: []cli.Flag{
@@ -270,7 +289,7 @@ var openChannelCommand = cli.Command{
 		cli.StringFlag{
 			Name: "node_key",
 			Usage: "the identity public key of the target peer " +
-				"serialized in compressed format",
+				"serialized in compressed format, or its alias",
 		},
 		cli.IntFlag{
 			Name:  "local_amt",
@@ -281,12 +300,24 @@ var openChannelCommand = cli.Command{
 			Usage: "the number of satoshis to push to the remote " +
 				"side as part of the initial commitment state",
 		},
+		cli.IntFlag{
+			Name: "remote_amt",
+			Usage: "the number of satoshis the remote peer should " +
+				"commit to the channel, opening a dual funded " +
+				"channel",
+		},
 		cli.IntFlag{
 			Name: "num_confs",
 			Usage: "the number of confirmations required before the " +
 				"channel is considered 'open'",
 			Value: 1,
 		},
+		cli.BoolFlag{
+			Name: "zero_conf",
+			Usage: "open the channel as soon as the funding " +
+				"transaction is broadcast, which the remote " +
+				"peer only accepts if it trusts us",
+		},
 		cli.BoolFlag{
 			Name:  "block",
 			Usage: "block and wait until the channel is fully open",
@@ -301,64 +332,15 @@ func openChannel(ctx *cli.Context) error {
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
-	args := ctx.Args()
-	var err error
-
 	// Show command help if no arguments provided
 	if ctx.NArg() == 0 && ctx.NumFlags() == 0 {
 		cli.ShowCommandHelp(ctx, "openchannel")
 		return nil
 	}
 
-	if ctx.IsSet("peer_id") && ctx.IsSet("node_key") {
-		return fmt.Errorf("both peer_id and lightning_id cannot be set " +
-			"at the same time, only one can be specified")
-	}
-
-	req := &lnrpc.OpenChannelRequest{
-		NumConfs: uint32(ctx.Int("num_confs")),
-	}
-
-	switch {
-	case ctx.IsSet("peer_id"):
-		req.TargetPeerId = int32(ctx.Int("peer_id"))
-	case ctx.IsSet("node_key"):
-		nodePubHex, err := hex.DecodeString(ctx.String("node_key"))
-		if err != nil {
-			return fmt.Errorf("unable to decode node public key: %v", err)
-		}
-		req.NodePubkey = nodePubHex
-	case args.Present():
-		nodePubHex, err := hex.DecodeString(args.First())
-		if err != nil {
-			return fmt.Errorf("unable to decode node public key: %v", err)
-		}
-		args = args.Tail()
-		req.NodePubkey = nodePubHex
-	default:
-		return fmt.Errorf("node id argument missing")
-	}
-
-	switch {
-	case ctx.IsSet("local_amt"):
-		req.LocalFundingAmount = int64(ctx.Int("local_amt"))
-	case args.Present():
-		req.LocalFundingAmount, err = strconv.ParseInt(args.First(), 10, 64)
-		if err != nil {
-			return fmt.Errorf("unable to decode local amt: %v", err)
-		}
-		args = args.Tail()
-	default:
-		return fmt.Errorf("local amt argument missing")
-	}
-
-	if ctx.IsSet("push_amt") {
-		req.PushSat = int64(ctx.Int("push_amt"))
-	} else if args.Present() {
-		req.PushSat, err = strconv.ParseInt(args.First(), 10, 64)
-		if err != nil {
-			return fmt.Errorf("unable to decode push amt: %v", err)
-		}
+	req, err := parseOpenChannelRequest(ctx)
+	if err != nil {
+		return err
 	}
 
 	stream, err := client.OpenChannel(ctxb, req)
@@ -410,205 +392,122 @@ func openChannel(ctx *cli.Context) error {
 	}
 }
 
-// TODO(roasbeef): also allow short relative channel ID.
-
-var closeChannelCommand = cli.Command{
-	Name:  "closechannel",
-	Usage: "Close an existing channel.",
-	Description: "Close an existing channel. The channel can be closed either " +
-		"cooperatively, or uncooperatively (forced).",
-	ArgsUsage: "funding_txid [output_index [time_limit]]",
-	Flags: []cli.Flag{
-		cli.StringFlag{
-			Name:  "funding_txid",
-			Usage: "the txid of the channel's funding transaction",
-		},
-		cli.IntFlag{
-			Name: "output_index",
-			Usage: "the output index for the funding output of the funding " +
-				"transaction",
-		},
-		cli.StringFlag{
-			Name: "time_limit",
-			Usage: "a relative deadline afterwhich the attempt should be " +
-				"abandonded",
-		},
-		cli.BoolFlag{
-			Name: "force",
-			Usage: "after the time limit has passed, attempt an " +
-				"uncooperative closure",
-		},
-		cli.BoolFlag{
-			Name:  "block",
-			Usage: "block until the channel is closed",
-		},
-	},
-	Action: closeChannel,
-}
-
-func closeChannel(ctx *cli.Context) error {
-	ctxb := context.Background()
-	client, cleanUp := getClient(ctx)
-	defer cleanUp()
+// parseOpenChannelRequest parses the arguments and flags shared by the
+// commands opening a channel, and estimating the cost of doing so.
+func parseOpenChannelRequest(ctx *cli.Context) (*lnrpc.OpenChannelRequest,
+	error) {
 
 	args := ctx.Args()
-	var (
-		txid string
-		err  error
-	)
+	var err error
 
-	// Show command help if no arguments provieded
-	if ctx.NArg() == 0 && ctx.NumFlags() == 0 {
-		cli.ShowCommandHelp(ctx, "closeChannel")
-		return nil
+	if ctx.IsSet("peer_id") && ctx.IsSet("node_key") {
+		return nil, fmt.Errorf("both peer_id and lightning_id cannot " +
+			"be set at the same time, only one can be specified")
 	}
 
-	// TODO(roasbeef): implement time deadline within server
-	req := &lnrpc.CloseChannelRequest{
-		ChannelPoint: &lnrpc.ChannelPoint{},
-		Force:        ctx.Bool("force"),
+	req := &lnrpc.OpenChannelRequest{
+		NumConfs: uint32(ctx.Int("num_confs")),
+		ZeroConf: ctx.Bool("zero_conf"),
 	}
 
 	switch {
-	case ctx.IsSet("funding_txid"):
-		txid = ctx.String("funding_txid")
+	case ctx.IsSet("peer_id"):
+		req.TargetPeerId = int32(ctx.Int("peer_id"))
+	case ctx.IsSet("node_key"):
+		req.NodePubkeyString = ctx.String("node_key")
 	case args.Present():
-		txid = args.First()
+		req.NodePubkeyString = args.First()
 		args = args.Tail()
 	default:
-		return fmt.Errorf("funding txid argument missing")
-	}
-
-	txidhash, err := chainhash.NewHashFromStr(txid)
-	if err != nil {
-		return err
+		return nil, fmt.Errorf("node id argument missing")
 	}
-	req.ChannelPoint.FundingTxid = txidhash[:]
 
 	switch {
-	case ctx.IsSet("output_index"):
-		req.ChannelPoint.OutputIndex = uint32(ctx.Int("output_index"))
+	case ctx.IsSet("local_amt"):
+		req.LocalFundingAmount = int64(ctx.Int("local_amt"))
 	case args.Present():
-		index, err := strconv.ParseInt(args.First(), 10, 32)
+		req.LocalFundingAmount, err = strconv.ParseInt(args.First(), 10, 64)
 		if err != nil {
-			return fmt.Errorf("unable to decode output index: %v", err)
+			return nil, fmt.Errorf("unable to decode local amt: %v",
+				err)
 		}
-		req.ChannelPoint.OutputIndex = uint32(index)
+		args = args.Tail()
 	default:
-		req.ChannelPoint.OutputIndex = 0
-	}
-
-	stream, err := client.CloseChannel(ctxb, req)
-	if err != nil {
-		return err
+		return nil, fmt.Errorf("local amt argument missing")
 	}
 
-	for {
-		resp, err := stream.Recv()
-		if err == io.EOF {
-			return nil
-		} else if err != nil {
-			return err
-		}
-
-		switch update := resp.Update.(type) {
-		case *lnrpc.CloseStatusUpdate_ClosePending:
-			closingHash := update.ClosePending.Txid
-			txid, err := chainhash.NewHash(closingHash)
-			if err != nil {
-				return err
-			}
-
-			printJSON(struct {
-				ClosingTXID string `json:"closing_txid"`
-			}{
-				ClosingTXID: txid.String(),
-			})
-
-			if !ctx.Bool("block") {
-				return nil
-			}
-
-		case *lnrpc.CloseStatusUpdate_ChanClose:
-			closingHash := update.ChanClose.ClosingTxid
-			txid, err := chainhash.NewHash(closingHash)
-			if err != nil {
-				return err
-			}
-
-			printJSON(struct {
-				ClosingTXID string `json:"closing_txid"`
-			}{
-				ClosingTXID: txid.String(),
-			})
+	if ctx.IsSet("push_amt") {
+		req.PushSat = int64(ctx.Int("push_amt"))
+	} else if args.Present() {
+		req.PushSat, err = strconv.ParseInt(args.First(), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode push amt: %v",
+				err)
 		}
 	}
-}
-
-var listPeersCommand = cli.Command{
-	Name:   "listpeers",
-	Usage:  "List all active, currently connected peers.",
-	Action: listPeers,
-}
-
-func listPeers(ctx *cli.Context) error {
-	ctxb := context.Background()
-	client, cleanUp := getClient(ctx)
-	defer cleanUp()
 
-	req := &lnrpc.ListPeersRequest{}
-	resp, err := client.ListPeers(ctxb, req)
-	if err != nil {
-		return err
+	if ctx.IsSet("remote_amt") {
+		req.RemoteFundingAmount = int64(ctx.Int("remote_amt"))
 	}
 
-	printRespJSON(resp)
-	return nil
+	return req, nil
 }
 
-var walletBalanceCommand = cli.Command{
-	Name:  "walletbalance",
-	Usage: "compute and display the wallet's current balance",
+var estimateOpenChannelCommand = cli.Command{
+	Name:  "estimateopenchannel",
+	Usage: "Estimate the cost of opening a channel to an existing peer.",
+	Description: "Project the cost of opening the channel openchannel " +
+		"would open with the same arguments, without opening it: the " +
+		"fee of the funding transaction, the reserve each side must " +
+		"maintain, and the cost of a force close. Whether the wallet " +
+		"holds enough funds to open the channel is reported as well.",
+	ArgsUsage: "node-key local-amt push-amt",
 	Flags: []cli.Flag{
-		cli.BoolFlag{
-			Name: "witness_only",
-			Usage: "if only witness outputs should be considered when " +
-				"calculating the wallet's balance",
+		cli.IntFlag{
+			Name:  "peer_id",
+			Usage: "the relative id of the peer to open a channel with",
+		},
+		cli.StringFlag{
+			Name: "node_key",
+			Usage: "the identity public key of the target peer " +
+				"serialized in compressed format, or its alias",
+		},
+		cli.IntFlag{
+			Name:  "local_amt",
+			Usage: "the number of satoshis the wallet should commit to the channel",
+		},
+		cli.IntFlag{
+			Name: "push_amt",
+			Usage: "the number of satoshis to push to the remote " +
+				"side as part of the initial commitment state",
+		},
+		cli.IntFlag{
+			Name: "remote_amt",
+			Usage: "the number of satoshis the remote peer should " +
+				"commit to the channel, opening a dual funded " +
+				"channel",
 		},
 	},
-	Action: walletBalance,
+	Action: estimateOpenChannel,
 }
 
-func walletBalance(ctx *cli.Context) error {
+func estimateOpenChannel(ctx *cli.Context) error {
 	ctxb := context.Background()
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
-	req := &lnrpc.WalletBalanceRequest{
-		WitnessOnly: ctx.Bool("witness_only"),
+	// Show command help if no arguments provided
+	if ctx.NArg() == 0 && ctx.NumFlags() == 0 {
+		cli.ShowCommandHelp(ctx, "estimateopenchannel")
+		return nil
 	}
-	resp, err := client.WalletBalance(ctxb, req)
+
+	req, err := parseOpenChannelRequest(ctx)
 	if err != nil {
 		return err
 	}
 
-	printRespJSON(resp)
-	return nil
-}
-
-var channelBalanceCommand = cli.Command{
-	Name:   "channelbalance",
-	Usage:  "returns the sum of the total available channel balance across all open channels",
-	Action: channelBalance,
-}
-
-func channelBalance(ctx *cli.Context) error {
-	ctxb := context.Background()
-	client, cleanUp := getClient(ctx)
-	defer cleanUp()
-
-	req := &lnrpc.ChannelBalanceRequest{}
-	resp, err := client.ChannelBalance(ctxb, req)
+	resp, err := client.EstimateOpenChannel(ctxb, req)
 	if err != nil {
 		return err
 	}
@@ -617,832 +516,3951 @@ func channelBalance(ctx *cli.Context) error {
 	return nil
 }
 
-var getInfoCommand = cli.Command{
-	Name:   "getinfo",
-	Usage:  "returns basic information related to the active daemon",
-	Action: getInfo,
+var fundingPolicyCommand = cli.Command{
+	Name:  "fundingpolicy",
+	Usage: "Display the policy limiting the channels peers may open.",
+	Description: "Display the limits applied to the channels peers " +
+		"propose to open with this node. A limit of zero is disabled.",
+	Action: fundingPolicy,
 }
 
-func getInfo(ctx *cli.Context) error {
+func fundingPolicy(ctx *cli.Context) error {
 	ctxb := context.Background()
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
-	req := &lnrpc.GetInfoRequest{}
-	resp, err := client.GetInfo(ctxb, req)
+	policy, err := client.GetFundingPolicy(ctxb,
+		&lnrpc.GetFundingPolicyRequest{})
 	if err != nil {
 		return err
 	}
 
-	printRespJSON(resp)
+	printRespJSON(policy)
 	return nil
 }
 
-var pendingChannelsCommand = cli.Command{
-	Name:  "pendingchannels",
-	Usage: "display information pertaining to pending channels",
+var updateFundingPolicyCommand = cli.Command{
+	Name:  "updatefundingpolicy",
+	Usage: "Update the policy limiting the channels peers may open.",
+	Description: "Update the limits applied to the channels peers " +
+		"propose to open with this node. Only the limits passed are " +
+		"changed, and a limit of zero is disabled. The policy is " +
+		"persisted, and applies to funding requests received from " +
+		"now on.",
 	Flags: []cli.Flag{
-		cli.BoolFlag{
-			Name:  "open, o",
-			Usage: "display the status of new pending channels",
+		cli.Int64Flag{
+			Name:  "min_chan_size",
+			Usage: "the smallest channel capacity accepted in satoshis",
 		},
-		cli.BoolFlag{
-			Name:  "close, c",
-			Usage: "display the status of channels being closed",
+		cli.Int64Flag{
+			Name:  "max_chan_size",
+			Usage: "the largest channel capacity accepted in satoshis",
 		},
-		cli.BoolFlag{
-			Name: "all, a",
-			Usage: "display the status of channels in the " +
-				"process of being opened or closed",
+		cli.Int64Flag{
+			Name: "max_chans_per_peer",
+			Usage: "the largest number of channels, pending or " +
+				"open, maintained with a single peer",
+		},
+		cli.Int64Flag{
+			Name: "max_peer_exposure",
+			Usage: "the largest total capacity in satoshis of the " +
+				"channels, pending or open, maintained with a " +
+				"single peer",
 		},
 	},
-	Action: pendingChannels,
+	Action: updateFundingPolicy,
 }
 
-func pendingChannels(ctx *cli.Context) error {
+func updateFundingPolicy(ctx *cli.Context) error {
 	ctxb := context.Background()
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
-	var channelStatus lnrpc.ChannelStatus
-	switch {
-	case ctx.Bool("all"):
-		channelStatus = lnrpc.ChannelStatus_ALL
-	case ctx.Bool("open"):
-		channelStatus = lnrpc.ChannelStatus_OPENING
-	case ctx.Bool("close"):
-		channelStatus = lnrpc.ChannelStatus_CLOSING
-	default:
-		channelStatus = lnrpc.ChannelStatus_ALL
+	if ctx.NumFlags() == 0 {
+		cli.ShowCommandHelp(ctx, "updatefundingpolicy")
+		return nil
 	}
 
-	req := &lnrpc.PendingChannelRequest{Status: channelStatus}
-	resp, err := client.PendingChannels(ctxb, req)
+	// The limits which aren't passed are left as they currently are.
+	policy, err := client.GetFundingPolicy(ctxb,
+		&lnrpc.GetFundingPolicyRequest{})
 	if err != nil {
 		return err
 	}
 
-	printRespJSON(resp)
+	if ctx.IsSet("min_chan_size") {
+		policy.MinChanSize = ctx.Int64("min_chan_size")
+	}
+	if ctx.IsSet("max_chan_size") {
+		policy.MaxChanSize = ctx.Int64("max_chan_size")
+	}
+	if ctx.IsSet("max_chans_per_peer") {
+		maxChans := ctx.Int64("max_chans_per_peer")
+		if maxChans < 0 || maxChans > math.MaxUint32 {
+			return fmt.Errorf("invalid max_chans_per_peer: %v",
+				maxChans)
+		}
+		policy.MaxChansPerPeer = uint32(maxChans)
+	}
+	if ctx.IsSet("max_peer_exposure") {
+		policy.MaxPeerExposure = ctx.Int64("max_peer_exposure")
+	}
+
+	if _, err := client.UpdateFundingPolicy(ctxb, policy); err != nil {
+		return err
+	}
 
+	printRespJSON(policy)
 	return nil
 }
 
-var listChannelsCommand = cli.Command{
-	Name:  "listchannels",
-	Usage: "list all open channels",
-	Flags: []cli.Flag{
-		cli.BoolFlag{
-			Name:  "active_only, a",
-			Usage: "only list channels which are currently active",
-		},
-	},
-	Action: listChannels,
+var peerSettingsCommand = cli.Command{
+	Name:   "peersettings",
+	Usage:  "List the settings set for individual peers.",
+	Action: peerSettings,
 }
 
-func listChannels(ctx *cli.Context) error {
-	ctxb := context.Background()
+func peerSettings(ctx *cli.Context) error {
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
-	req := &lnrpc.ListChannelsRequest{}
-	resp, err := client.ListChannels(ctxb, req)
+	resp, err := client.ListPeerSettings(context.Background(),
+		&lnrpc.ListPeerSettingsRequest{})
 	if err != nil {
 		return err
 	}
 
-	// TODO(roasbeef): defer close the client for the all
-
 	printRespJSON(resp)
-
 	return nil
 }
 
-var sendPaymentCommand = cli.Command{
-	Name:  "sendpayment",
-	Usage: "send a payment over lightning",
-	ArgsUsage: "(destination amount payment_hash " +
-		"| --pay_req=[payment request])",
+var updatePeerSettingsCommand = cli.Command{
+	Name:      "updatepeersettings",
+	Usage:     "Update the settings of an individual peer.",
+	ArgsUsage: "pub_key",
+	Description: "Update the settings which override, for a single peer, " +
+		"those applied to all other peers. Only the settings passed " +
+		"are changed, and a limit of zero defers to the setting " +
+		"applied to all peers. A fee policy is also applied to the " +
+		"channels already maintained with the peer.",
 	Flags: []cli.Flag{
 		cli.StringFlag{
-			Name: "dest, d",
-			Usage: "the compressed identity pubkey of the " +
-				"payment recipient",
+			Name:  "pub_key",
+			Usage: "the hex-encoded identity public key of the peer",
 		},
 		cli.Int64Flag{
-			Name:  "amt, a",
-			Usage: "number of satoshis to send",
+			Name: "max_channels",
+			Usage: "the largest number of channels, pending or " +
+				"open, maintained with the peer",
 		},
 		cli.StringFlag{
-			Name:  "payment_hash, r",
-			Usage: "the hash to use within the payment's HTLC",
+			Name: "gossip_sync_mode",
+			Usage: "whether the channel graph is synchronized with " +
+				"the peer: active, passive or default",
 		},
-		cli.BoolFlag{
-			Name:  "debug_send",
-			Usage: "use the debug rHash when sending the HTLC",
+		cli.Int64Flag{
+			Name: "max_pending_htlcs",
+			Usage: "the largest number of HTLCs forwarded to the " +
+				"peer which may be pending at once",
 		},
-		cli.StringFlag{
-			Name:  "pay_req",
-			Usage: "a zbase32-check encoded payment request to fulfill",
+		cli.Int64Flag{
+			Name:  "max_htlc_value",
+			Usage: "the largest HTLC forwarded to the peer in satoshis",
+		},
+		cli.Int64Flag{
+			Name:  "base_fee_msat",
+			Usage: "the base fee charged on the peer's channels",
+		},
+		cli.Int64Flag{
+			Name: "fee_rate",
+			Usage: "the fee charged on the peer's channels for " +
+				"each millionth of the forwarded value",
+		},
+		cli.Int64Flag{
+			Name: "time_lock_delta",
+			Usage: "the time lock delta required on the peer's " +
+				"channels",
+		},
+		cli.Int64Flag{
+			Name:  "min_htlc",
+			Usage: "the smallest HTLC forwarded over the peer's channels",
+		},
+		cli.BoolFlag{
+			Name: "default_fee_policy",
+			Usage: "announce new channels with the peer under the " +
+				"default fee policy",
 		},
 	},
-	Action: sendPayment,
+	Action: updatePeerSettings,
 }
 
-func sendPayment(ctx *cli.Context) error {
+func updatePeerSettings(ctx *cli.Context) error {
+	ctxb := context.Background()
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
-	// Show command help if no arguments provieded
-	if ctx.NArg() == 0 && ctx.NumFlags() == 0 {
-		cli.ShowCommandHelp(ctx, "sendpayment")
-		return nil
+	var pubKey string
+	switch {
+	case ctx.IsSet("pub_key"):
+		pubKey = ctx.String("pub_key")
+	case ctx.Args().Present():
+		pubKey = ctx.Args().First()
+	default:
+		return cli.ShowCommandHelp(ctx, "updatepeersettings")
 	}
 
-	var req *lnrpc.SendRequest
-	if ctx.IsSet("pay_req") {
-		req = &lnrpc.SendRequest{
-			PaymentRequest: ctx.String("pay_req"),
+	// The settings which aren't passed are left as they currently are.
+	allSettings, err := client.ListPeerSettings(ctxb,
+		&lnrpc.ListPeerSettingsRequest{})
+	if err != nil {
+		return err
+	}
+	settings := &lnrpc.PeerSettings{PubKey: pubKey}
+	for _, existing := range allSettings.Peers {
+		if existing.PubKey == pubKey {
+			settings = existing
+			break
 		}
-	} else {
-		args := ctx.Args()
+	}
 
-		var (
-			destNode []byte
-			err      error
-			amount   int64
-		)
+	parseUint32 := func(name string) (uint32, error) {
+		v := ctx.Int64(name)
+		if v < 0 || v > math.MaxUint32 {
+			return 0, fmt.Errorf("invalid %v: %v", name, v)
+		}
+		return uint32(v), nil
+	}
 
-		switch {
-		case ctx.IsSet("dest"):
-			destNode, err = hex.DecodeString(ctx.String("dest"))
-		case args.Present():
-			destNode, err = hex.DecodeString(args.First())
-			args = args.Tail()
-		default:
-			return fmt.Errorf("destination txid argument missing")
+	if ctx.IsSet("max_channels") {
+		settings.MaxChannels, err = parseUint32("max_channels")
+		if err != nil {
+			return err
 		}
+	}
+	if ctx.IsSet("gossip_sync_mode") {
+		settings.GossipSyncMode = ctx.String("gossip_sync_mode")
+	}
+	if ctx.IsSet("max_pending_htlcs") {
+		settings.MaxPendingHtlcs, err = parseUint32("max_pending_htlcs")
 		if err != nil {
 			return err
 		}
+	}
+	if ctx.IsSet("max_htlc_value") {
+		settings.MaxHtlcValue = ctx.Int64("max_htlc_value")
+	}
 
-		if len(destNode) != 33 {
-			return fmt.Errorf("dest node pubkey must be exactly 33 bytes, is "+
-				"instead: %v", len(destNode))
-		}
+	if ctx.Bool("default_fee_policy") {
+		settings.FeePolicy = nil
+	}
+	for _, name := range []string{"base_fee_msat", "fee_rate",
+		"time_lock_delta", "min_htlc"} {
 
-		if ctx.IsSet("amt") {
-			amount = ctx.Int64("amt")
-		} else if args.Present() {
-			amount, err = strconv.ParseInt(args.First(), 10, 64)
-			args = args.Tail()
-			if err != nil {
-				return fmt.Errorf("unable to decode payment amount: %v", err)
-			}
+		if !ctx.IsSet(name) {
+			continue
 		}
-
-		req = &lnrpc.SendRequest{
-			Dest: destNode,
-			Amt:  amount,
+		if ctx.Bool("default_fee_policy") {
+			return fmt.Errorf("%v can't be combined with "+
+				"default_fee_policy", name)
+		}
+		if settings.FeePolicy == nil {
+			settings.FeePolicy = &lnrpc.RoutingPolicy{}
 		}
 
-		if ctx.Bool("debug_send") && (ctx.IsSet("payment_hash") || args.Present()) {
-			return fmt.Errorf("do not provide a payment hash with debug send")
-		} else if !ctx.Bool("debug_send") {
-			var rHash []byte
-
-			switch {
-			case ctx.IsSet("payment_hash"):
-				rHash, err = hex.DecodeString(ctx.String("payment_hash"))
-			case args.Present():
-				rHash, err = hex.DecodeString(args.First())
-			default:
-				return fmt.Errorf("payment hash argument missing")
-			}
-
+		switch name {
+		case "base_fee_msat":
+			settings.FeePolicy.FeeBaseMsat = ctx.Int64(name)
+		case "fee_rate":
+			settings.FeePolicy.FeeRateMilliMsat = ctx.Int64(name)
+		case "time_lock_delta":
+			delta, err := parseUint32(name)
 			if err != nil {
 				return err
 			}
-			if len(rHash) != 32 {
-				return fmt.Errorf("payment hash must be exactly 32 "+
-					"bytes, is instead %v", len(rHash))
-			}
-			req.PaymentHash = rHash
+			settings.FeePolicy.TimeLockDelta = delta
+		case "min_htlc":
+			settings.FeePolicy.MinHtlc = ctx.Int64(name)
 		}
 	}
 
-	paymentStream, err := client.SendPayment(context.Background())
-	if err != nil {
+	if _, err := client.UpdatePeerSettings(ctxb, settings); err != nil {
 		return err
 	}
 
-	if err := paymentStream.Send(req); err != nil {
-		return err
+	printRespJSON(settings)
+	return nil
+}
+
+var deletePeerSettingsCommand = cli.Command{
+	Name:      "deletepeersettings",
+	Usage:     "Delete the settings of an individual peer.",
+	ArgsUsage: "pub_key",
+	Description: "Delete the settings of a peer, so it's treated like " +
+		"any other peer. A fee policy already applied to the peer's " +
+		"channels is left in place.",
+	Action: deletePeerSettings,
+}
+
+func deletePeerSettings(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	if !ctx.Args().Present() {
+		return cli.ShowCommandHelp(ctx, "deletepeersettings")
 	}
 
-	resp, err := paymentStream.Recv()
+	req := &lnrpc.DeletePeerSettingsRequest{
+		PubKey: ctx.Args().First(),
+	}
+	resp, err := client.DeletePeerSettings(context.Background(), req)
 	if err != nil {
 		return err
 	}
 
-	paymentStream.CloseSend()
-
-	printJSON(struct {
-		P string       `json:"payment_preimage"`
-		R *lnrpc.Route `json:"payment_route"`
-	}{
-		P: hex.EncodeToString(resp.PaymentPreimage),
-		R: resp.PaymentRoute,
-	})
-
+	printRespJSON(resp)
 	return nil
 }
 
-var addInvoiceCommand = cli.Command{
-	Name:  "addinvoice",
-	Usage: "add a new invoice.",
-	Description: "Add a new invoice, expressing intent for a future payment. " +
-		"The value of the invoice in satoshis and a 32 byte hash preimage are neccesary for the creation",
-	ArgsUsage: "value preimage",
+// TODO(roasbeef): also allow short relative channel ID.
+
+var closeChannelCommand = cli.Command{
+	Name:  "closechannel",
+	Usage: "Close an existing channel.",
+	Description: "Close an existing channel. The channel can be closed either " +
+		"cooperatively, or uncooperatively (forced).",
+	ArgsUsage: "funding_txid [output_index [time_limit]]",
 	Flags: []cli.Flag{
 		cli.StringFlag{
-			Name:  "memo",
-			Usage: "an optional memo to attach along with the invoice",
+			Name:  "funding_txid",
+			Usage: "the txid of the channel's funding transaction",
 		},
-		cli.StringFlag{
-			Name:  "receipt",
-			Usage: "an optional cryptographic receipt of payment",
+		cli.IntFlag{
+			Name: "output_index",
+			Usage: "the output index for the funding output of the funding " +
+				"transaction",
 		},
 		cli.StringFlag{
-			Name:  "preimage",
-			Usage: "the hex-encoded preimage (32 byte) which will allow settling an incoming HTLC payable to this preimage",
+			Name: "time_limit",
+			Usage: "a relative deadline afterwhich the attempt should be " +
+				"abandonded",
+		},
+		cli.BoolFlag{
+			Name: "force",
+			Usage: "after the time limit has passed, attempt an " +
+				"uncooperative closure",
+		},
+		cli.BoolFlag{
+			Name:  "block",
+			Usage: "block until the channel is closed",
+		},
+		cli.BoolFlag{
+			Name: "dry_run",
+			Usage: "with --force, print the transaction that would " +
+				"be broadcast without broadcasting it",
+		},
+		cli.IntFlag{
+			Name: "conf_target",
+			Usage: "the number of blocks our cooperative closure " +
+				"transaction should confirm within, used to " +
+				"estimate its fee rate",
 		},
 		cli.Int64Flag{
-			Name:  "value",
-			Usage: "the value of this invoice in satoshis",
+			Name: "sat_per_byte",
+			Usage: "the fee rate in satoshis per byte paid by our " +
+				"cooperative closure transaction",
 		},
 	},
-	Action: addInvoice,
+	Action: closeChannel,
 }
 
-func addInvoice(ctx *cli.Context) error {
-	var (
-		preimage []byte
-		receipt  []byte
-		value    int64
-		err      error
-	)
-
+func closeChannel(ctx *cli.Context) error {
+	ctxb := context.Background()
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
 	args := ctx.Args()
+	var (
+		txid string
+		err  error
+	)
+
+	// Show command help if no arguments provieded
+	if ctx.NArg() == 0 && ctx.NumFlags() == 0 {
+		cli.ShowCommandHelp(ctx, "closeChannel")
+		return nil
+	}
+
+	// TODO(roasbeef): implement time deadline within server
+	req := &lnrpc.CloseChannelRequest{
+		ChannelPoint: &lnrpc.ChannelPoint{},
+		Force:        ctx.Bool("force"),
+		DryRun:       ctx.Bool("dry_run"),
+		TargetConf:   int32(ctx.Int("conf_target")),
+		SatPerByte:   ctx.Int64("sat_per_byte"),
+	}
 
 	switch {
-	case ctx.IsSet("value"):
-		value = ctx.Int64("value")
+	case ctx.IsSet("funding_txid"):
+		txid = ctx.String("funding_txid")
 	case args.Present():
-		value, err = strconv.ParseInt(args.First(), 10, 64)
+		txid = args.First()
 		args = args.Tail()
-		if err != nil {
-			return fmt.Errorf("unable to decode value argument: %v", err)
-		}
 	default:
-		return fmt.Errorf("value argument missing")
+		return fmt.Errorf("funding txid argument missing")
+	}
+
+	txidhash, err := chainhash.NewHashFromStr(txid)
+	if err != nil {
+		return err
 	}
+	req.ChannelPoint.FundingTxid = txidhash[:]
 
 	switch {
-	case ctx.IsSet("preimage"):
-		preimage, err = hex.DecodeString(ctx.String("preimage"))
+	case ctx.IsSet("output_index"):
+		req.ChannelPoint.OutputIndex = uint32(ctx.Int("output_index"))
 	case args.Present():
-		preimage, err = hex.DecodeString(args.First())
+		index, err := strconv.ParseInt(args.First(), 10, 32)
+		if err != nil {
+			return fmt.Errorf("unable to decode output index: %v", err)
+		}
+		req.ChannelPoint.OutputIndex = uint32(index)
+	default:
+		req.ChannelPoint.OutputIndex = 0
 	}
 
+	stream, err := client.CloseChannel(ctxb, req)
 	if err != nil {
-		return fmt.Errorf("unable to parse preimage: %v", err)
+		return err
 	}
 
-	receipt, err = hex.DecodeString(ctx.String("receipt"))
-	if err != nil {
-		return fmt.Errorf("unable to parse receipt: %v", err)
-	}
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
 
-	invoice := &lnrpc.Invoice{
-		Memo:      ctx.String("memo"),
-		Receipt:   receipt,
-		RPreimage: preimage,
-		Value:     value,
+		switch update := resp.Update.(type) {
+		case *lnrpc.CloseStatusUpdate_ClosePending:
+			closingHash := update.ClosePending.Txid
+			txid, err := chainhash.NewHash(closingHash)
+			if err != nil {
+				return err
+			}
+
+			printJSON(struct {
+				ClosingTXID string `json:"closing_txid"`
+			}{
+				ClosingTXID: txid.String(),
+			})
+
+			if !ctx.Bool("block") {
+				return nil
+			}
+
+		case *lnrpc.CloseStatusUpdate_ChanClose:
+			closingHash := update.ChanClose.ClosingTxid
+			txid, err := chainhash.NewHash(closingHash)
+			if err != nil {
+				return err
+			}
+
+			printJSON(struct {
+				ClosingTXID string `json:"closing_txid"`
+			}{
+				ClosingTXID: txid.String(),
+			})
+
+		case *lnrpc.CloseStatusUpdate_DryRun:
+			txid, err := chainhash.NewHash(update.DryRun.Txid)
+			if err != nil {
+				return err
+			}
+
+			printJSON(struct {
+				ClosingTXID string `json:"closing_txid"`
+				RawTx       string `json:"raw_tx"`
+				LocalAmount int64  `json:"local_amount"`
+				CsvDelay    uint32 `json:"csv_delay"`
+			}{
+				ClosingTXID: txid.String(),
+				RawTx:       hex.EncodeToString(update.DryRun.RawTx),
+				LocalAmount: update.DryRun.LocalAmount,
+				CsvDelay:    update.DryRun.CsvDelay,
+			})
+		}
 	}
+}
 
-	resp, err := client.AddInvoice(context.Background(), invoice)
+var listPeersCommand = cli.Command{
+	Name:   "listpeers",
+	Usage:  "List all active, currently connected peers.",
+	Action: listPeers,
+}
+
+func listPeers(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.ListPeersRequest{}
+	resp, err := client.ListPeers(ctxb, req)
 	if err != nil {
 		return err
 	}
 
-	printJSON(struct {
-		RHash  string `json:"r_hash"`
-		PayReq string `json:"pay_req"`
-	}{
-		RHash:  hex.EncodeToString(resp.RHash),
-		PayReq: resp.PaymentRequest,
-	})
-
+	printRespJSON(resp)
 	return nil
 }
 
-var lookupInvoiceCommand = cli.Command{
-	Name:      "lookupinvoice",
-	Usage:     "Lookup an existing invoice by its payment hash.",
-	ArgsUsage: "rhash",
+var walletBalanceCommand = cli.Command{
+	Name:  "walletbalance",
+	Usage: "compute and display the wallet's current balance",
 	Flags: []cli.Flag{
-		cli.StringFlag{
-			Name: "rhash",
-			Usage: "the 32 byte payment hash of the invoice to query for, the hash " +
-				"should be a hex-encoded string",
+		cli.BoolFlag{
+			Name: "witness_only",
+			Usage: "if only witness outputs should be considered when " +
+				"calculating the wallet's balance",
 		},
 	},
-	Action: lookupInvoice,
+	Action: walletBalance,
 }
 
-func lookupInvoice(ctx *cli.Context) error {
+func walletBalance(ctx *cli.Context) error {
+	ctxb := context.Background()
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
-	var (
-		rHash []byte
-		err   error
-	)
-
-	switch {
-	case ctx.IsSet("rhash"):
-		rHash, err = hex.DecodeString(ctx.String("rhash"))
-	case ctx.Args().Present():
-		rHash, err = hex.DecodeString(ctx.Args().First())
-	default:
-		return fmt.Errorf("rhash argument missing")
+	req := &lnrpc.WalletBalanceRequest{
+		WitnessOnly: ctx.Bool("witness_only"),
 	}
-
+	resp, err := client.WalletBalance(ctxb, req)
 	if err != nil {
-		return fmt.Errorf("unable to decode rhash argument: %v", err)
+		return err
 	}
 
-	req := &lnrpc.PaymentHash{
-		RHash: rHash,
-	}
+	printRespJSON(resp)
+	return nil
+}
 
-	invoice, err := client.LookupInvoice(context.Background(), req)
+var channelBalanceCommand = cli.Command{
+	Name:   "channelbalance",
+	Usage:  "returns the sum of the total available channel balance across all open channels",
+	Action: channelBalance,
+}
+
+func channelBalance(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.ChannelBalanceRequest{}
+	resp, err := client.ChannelBalance(ctxb, req)
 	if err != nil {
 		return err
 	}
 
-	printRespJSON(invoice)
-
+	printRespJSON(resp)
 	return nil
 }
 
-var listInvoicesCommand = cli.Command{
-	Name:  "listinvoices",
-	Usage: "List all invoices currently stored.",
-	Flags: []cli.Flag{
-		cli.BoolFlag{
-			Name: "pending_only",
-			Usage: "toggles if all invoices should be returned, or only " +
-				"those that are currently unsettled",
-		},
-	},
-	Action: listInvoices,
+var getInfoCommand = cli.Command{
+	Name:   "getinfo",
+	Usage:  "returns basic information related to the active daemon",
+	Action: getInfo,
 }
 
-func listInvoices(ctx *cli.Context) error {
+func getInfo(ctx *cli.Context) error {
+	ctxb := context.Background()
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
-	pendingOnly := true
-	if !ctx.Bool("pending_only") {
-		pendingOnly = false
-	}
-
-	req := &lnrpc.ListInvoiceRequest{
-		PendingOnly: pendingOnly,
-	}
-
-	invoices, err := client.ListInvoices(context.Background(), req)
+	req := &lnrpc.GetInfoRequest{}
+	resp, err := client.GetInfo(ctxb, req)
 	if err != nil {
 		return err
 	}
 
-	printRespJSON(invoices)
-
+	printRespJSON(resp)
 	return nil
 }
 
-var describeGraphCommand = cli.Command{
-	Name: "describegraph",
-	Description: "prints a human readable version of the known channel " +
-		"graph from the PoV of the node",
-	Usage: "describe the network graph",
+var pendingChannelsCommand = cli.Command{
+	Name:  "pendingchannels",
+	Usage: "display information pertaining to pending channels",
 	Flags: []cli.Flag{
 		cli.BoolFlag{
-			Name:  "render",
-			Usage: "If set, then an image of graph will be generated and displayed. The generated image is stored within the current directory with a file name of 'graph.svg'",
+			Name:  "open, o",
+			Usage: "display the status of new pending channels",
+		},
+		cli.BoolFlag{
+			Name:  "close, c",
+			Usage: "display the status of channels being closed",
+		},
+		cli.BoolFlag{
+			Name: "all, a",
+			Usage: "display the status of channels in the " +
+				"process of being opened or closed",
 		},
 	},
-	Action: describeGraph,
+	Action: pendingChannels,
 }
 
-func describeGraph(ctx *cli.Context) error {
+func pendingChannels(ctx *cli.Context) error {
+	ctxb := context.Background()
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
-	req := &lnrpc.ChannelGraphRequest{}
+	var channelStatus lnrpc.ChannelStatus
+	switch {
+	case ctx.Bool("all"):
+		channelStatus = lnrpc.ChannelStatus_ALL
+	case ctx.Bool("open"):
+		channelStatus = lnrpc.ChannelStatus_OPENING
+	case ctx.Bool("close"):
+		channelStatus = lnrpc.ChannelStatus_CLOSING
+	default:
+		channelStatus = lnrpc.ChannelStatus_ALL
+	}
 
-	graph, err := client.DescribeGraph(context.Background(), req)
+	req := &lnrpc.PendingChannelRequest{Status: channelStatus}
+	resp, err := client.PendingChannels(ctxb, req)
 	if err != nil {
 		return err
 	}
 
-	// If the draw flag is on, then we'll use the 'dot' command to create a
-	// visualization of the graph itself.
-	if ctx.Bool("render") {
-		return drawChannelGraph(graph)
-	}
+	printRespJSON(resp)
 
-	printRespJSON(graph)
 	return nil
 }
 
-// normalizeFunc is a factory function which returns a function that normalizes
-// the capacity of of edges within the graph. The value of the returned
-// function can be used to either plot the capacities, or to use a weight in a
-// rendering of the graph.
-func normalizeFunc(edges []*lnrpc.ChannelEdge, scaleFactor float64) func(int64) float64 {
-	var (
-		min float64 = math.MaxInt64
-		max float64
-	)
+var listChannelsCommand = cli.Command{
+	Name:  "listchannels",
+	Usage: "list all open channels",
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "active_only, a",
+			Usage: "only list channels which are currently active",
+		},
+	},
+	Action: listChannels,
+}
 
-	for _, edge := range edges {
-		// In order to obtain saner values, we reduce the capacity of a
-		// channel to it's base 2 logarithm.
-		z := math.Log2(float64(edge.Capacity))
+func listChannels(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
 
-		if z < min {
-			min = z
-		}
-		if z > max {
-			max = z
-		}
+	req := &lnrpc.ListChannelsRequest{}
+	resp, err := client.ListChannels(ctxb, req)
+	if err != nil {
+		return err
 	}
 
-	return func(x int64) float64 {
-		y := math.Log2(float64(x))
+	// TODO(roasbeef): defer close the client for the all
 
-		// TODO(roasbeef): results in min being zero
-		return (y - min) / (max - min) * scaleFactor
-	}
+	printRespJSON(resp)
+
+	return nil
 }
 
-func drawChannelGraph(graph *lnrpc.ChannelGraph) error {
-	// First we'll create a temporary file that we'll write the compiled
-	// string that describes our graph in the dot format to.
-	tempDotFile, err := ioutil.TempFile("", "")
+var exportChanBackupCommand = cli.Command{
+	Name:  "exportchanbackup",
+	Usage: "export an encrypted static backup of all channels",
+	Description: "Export an encrypted static backup of all open and " +
+		"pending channels. The backup can only be decrypted by this " +
+		"node. If no output file is specified, the backup is printed " +
+		"as hex.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "output_file",
+			Usage: "the file to write the backup to",
+		},
+	},
+	Action: exportChanBackup,
+}
+
+func exportChanBackup(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.ExportChanBackupRequest{}
+	resp, err := client.ExportChanBackup(context.Background(), req)
 	if err != nil {
 		return err
 	}
-	defer os.Remove(tempDotFile.Name())
 
-	// Next, we'll create (or re-create) the file that the final graph
-	// image will be written to.
-	imageFile, err := os.Create("graph.svg")
-	if err != nil {
-		return err
+	if ctx.IsSet("output_file") {
+		return ioutil.WriteFile(ctx.String("output_file"),
+			resp.MultiChanBackup, 0600)
 	}
 
-	// With our temporary files set up, we'll initialize the graphviz
-	// object that we'll use to draw our graph.
-	graphName := "LightningNetwork"
-	graphCanvas := gographviz.NewGraph()
-	graphCanvas.SetName(graphName)
-	graphCanvas.SetDir(false)
+	printJSON(struct {
+		MultiChanBackup string `json:"multi_chan_backup"`
+	}{
+		MultiChanBackup: hex.EncodeToString(resp.MultiChanBackup),
+	})
 
-	const numKeyChars = 10
+	return nil
+}
 
-	truncateStr := func(k string, n uint) string {
-		return k[:n]
+// chanBackupFlags are the flags used to pass a static channel backup to a
+// command.
+var chanBackupFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "multi_backup",
+		Usage: "the hex encoded static channel backup",
+	},
+	cli.StringFlag{
+		Name:  "multi_file",
+		Usage: "the file containing the static channel backup",
+	},
+}
+
+// parseChanBackup reads the static channel backup specified by the
+// chanBackupFlags.
+func parseChanBackup(ctx *cli.Context) ([]byte, error) {
+	switch {
+	case ctx.IsSet("multi_backup"):
+		return hex.DecodeString(ctx.String("multi_backup"))
+	case ctx.IsSet("multi_file"):
+		return ioutil.ReadFile(ctx.String("multi_file"))
+	default:
+		return nil, fmt.Errorf("either multi_backup or multi_file " +
+			"must be specified")
 	}
+}
 
-	// For each node within the graph, we'll add a new vertex to the graph.
-	for _, node := range graph.Nodes {
-		// Rather than using the entire hex-encoded string, we'll only
-		// use the first 10 characters. We also add a prefix of "Z" as
-		// graphviz is unable to parse the compressed pubkey as a
-		// non-integer.
-		//
-		// TODO(roasbeef): should be able to get around this?
-		nodeID := fmt.Sprintf(`"%v"`, truncateStr(node.PubKey, numKeyChars))
+var verifyChanBackupCommand = cli.Command{
+	Name:  "verifychanbackup",
+	Usage: "verify a static channel backup",
+	Description: "Verify that a static channel backup can be decrypted " +
+		"by this node, and check each channel within it against the " +
+		"node's live channel state.",
+	Flags:  chanBackupFlags,
+	Action: verifyChanBackup,
+}
 
-		graphCanvas.AddNode(graphName, nodeID, gographviz.Attrs{})
+func verifyChanBackup(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	backup, err := parseChanBackup(ctx)
+	if err != nil {
+		return err
 	}
 
-	normalize := normalizeFunc(graph.Edges, 3)
+	req := &lnrpc.ChanBackupSnapshot{
+		MultiChanBackup: backup,
+	}
+	resp, err := client.VerifyChanBackup(context.Background(), req)
+	if err != nil {
+		return err
+	}
 
-	// Similarly, for each edge we'll add an edge between the corresponding
-	// nodes added to the graph above.
-	for _, edge := range graph.Edges {
-		// Once again, we add a 'Z' prefix so we're compliant with the
-		// dot grammar.
-		src := fmt.Sprintf(`"%v"`, truncateStr(edge.Node1Pub, numKeyChars))
-		dest := fmt.Sprintf(`"%v"`, truncateStr(edge.Node2Pub, numKeyChars))
+	printRespJSON(resp)
 
-		// The weight for our edge will be the total capacity of the
-		// channel, in BTC.
-		// TODO(roasbeef): can also factor in the edges time-lock delta
-		// and fee information
-		amt := btcutil.Amount(edge.Capacity).ToBTC()
-		edgeWeight := strconv.FormatFloat(amt, 'f', -1, 64)
+	return nil
+}
 
-		// The label for each edge will simply be a truncated version
-		// of it's channel ID.
-		chanIDStr := strconv.FormatUint(edge.ChannelId, 10)
-		edgeLabel := fmt.Sprintf(`"cid:%v"`, truncateStr(chanIDStr, 7))
+var restoreChanBackupCommand = cli.Command{
+	Name:  "restorechanbackup",
+	Usage: "restore the channels within a static channel backup",
+	Description: "Restore the channels within a static channel backup. " +
+		"Each channel is recovered by asking the remote party to " +
+		"force close it, which pays our balance to our wallet. " +
+		"With --dry_run, nothing is restored; instead the command " +
+		"reports exactly which channels would be recovered and how.",
+	Flags: append([]cli.Flag{
+		cli.BoolFlag{
+			Name:  "dry_run",
+			Usage: "only report what the restore would do",
+		},
+	}, chanBackupFlags...),
+	Action: restoreChanBackup,
+}
 
-		// We'll also use a normalized version of the channels'
-		// capacity in satoshis in order to modulate the "thickness" of
-		// the line that creates the edge within the graph.
-		normalizedCapacity := normalize(edge.Capacity)
-		edgeThickness := strconv.FormatFloat(normalizedCapacity, 'f', -1, 64)
+func restoreChanBackup(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
 
-		// TODO(roasbeef): color code based on percentile capacity
-		graphCanvas.AddEdge(src, dest, false, gographviz.Attrs{
-			"penwidth": edgeThickness,
-			"weight":   edgeWeight,
-			"label":    edgeLabel,
-		})
+	backup, err := parseChanBackup(ctx)
+	if err != nil {
+		return err
 	}
 
-	// With the declarative generation of the graph complete, we now write
-	// the dot-string description of the graph
-	graphDotString := graphCanvas.String()
-	if _, err := tempDotFile.WriteString(graphDotString); err != nil {
-		return err
+	req := &lnrpc.RestoreChanBackupRequest{
+		MultiChanBackup: backup,
+		DryRun:          ctx.Bool("dry_run"),
 	}
-	if err := tempDotFile.Sync(); err != nil {
+	resp, err := client.RestoreChanBackup(context.Background(), req)
+	if err != nil {
 		return err
 	}
 
-	var errBuffer bytes.Buffer
+	printRespJSON(resp)
 
-	// Once our dot file has been written to disk, we can use the dot
-	// command itself to generate the drawn rendering of the graph
-	// described.
-	drawCmd := exec.Command("dot", "-T"+"svg", "-o"+imageFile.Name(),
-		tempDotFile.Name())
-	drawCmd.Stderr = &errBuffer
-	if err := drawCmd.Run(); err != nil {
-		fmt.Println("error rendering graph: ", errBuffer.String())
-		fmt.Println("dot: ", graphDotString)
+	return nil
+}
 
-		return err
-	}
+var backupUploadStatusCommand = cli.Command{
+	Name:  "backupuploadstatus",
+	Usage: "report the uploads of the channel backup file",
+	Description: "Report the uploads of the channel backup file to each " +
+		"of the targets configured with backupupload.target, " +
+		"including the number of successful and failed uploads, the " +
+		"error of the latest failure, and whether the target holds " +
+		"the latest backup.",
+	Action: backupUploadStatus,
+}
 
-	errBuffer.Reset()
+func backupUploadStatus(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
 
-	// Finally, we'll open the drawn graph to display to the user.
-	openCmd := exec.Command("open", imageFile.Name())
-	openCmd.Stderr = &errBuffer
-	if err := openCmd.Run(); err != nil {
-		fmt.Println("error opening rendered graph image: ",
-			errBuffer.String())
+	req := &lnrpc.BackupUploadStatusRequest{}
+	resp, err := client.BackupUploadStatus(context.Background(), req)
+	if err != nil {
 		return err
 	}
 
+	printRespJSON(resp)
+
 	return nil
 }
 
-var listPaymentsCommand = cli.Command{
-	Name:   "listpayments",
-	Usage:  "list all outgoing payments",
-	Action: listPayments,
+var importChanRecoveryCommand = cli.Command{
+	Name:      "importchanrecovery",
+	Usage:     "import channel recovery data from another implementation",
+	ArgsUsage: "recovery_file",
+	Description: "Import a JSON array of channel recovery data exported " +
+		"by another Lightning implementation. Each entry must " +
+		"contain the funding_outpoint, capacity, local_balance, " +
+		"is_initiator, the WIF encoded funding_key, the " +
+		"remote_node_pub, remote_addr, remote_funding_pub, and " +
+		"remote_delivery_script. The recovered channels may only " +
+		"be cooperatively closed.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "recovery_file",
+			Usage: "the file containing the recovery data",
+		},
+	},
+	Action: importChanRecovery,
 }
 
-func listPayments(ctx *cli.Context) error {
+func importChanRecovery(ctx *cli.Context) error {
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
-	req := &lnrpc.ListPaymentsRequest{}
+	var recoveryFile string
+	switch {
+	case ctx.IsSet("recovery_file"):
+		recoveryFile = ctx.String("recovery_file")
+	case ctx.Args().Present():
+		recoveryFile = ctx.Args().First()
+	default:
+		return fmt.Errorf("recovery_file argument missing")
+	}
 
-	payments, err := client.ListPayments(context.Background(), req)
+	recoveryJSON, err := ioutil.ReadFile(recoveryFile)
 	if err != nil {
 		return err
 	}
 
-	printRespJSON(payments)
+	req := &lnrpc.ImportChannelRecoveryRequest{
+		RecoveryJson: recoveryJSON,
+	}
+	resp, err := client.ImportChannelRecovery(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
 	return nil
 }
 
-var getChanInfoCommand = cli.Command{
-	Name:  "getchaninfo",
-	Usage: "get the state of a channel",
-	Description: "prints out the latest authenticated state for a " +
-		"particular channel",
-	ArgsUsage: "chan_id",
+var sendPaymentCommand = cli.Command{
+	Name:  "sendpayment",
+	Usage: "send a payment over lightning",
+	ArgsUsage: "(destination amount payment_hash " +
+		"| --pay_req=[payment request])",
 	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "dest, d",
+			Usage: "the compressed identity pubkey of the " +
+				"payment recipient, or its alias",
+		},
 		cli.Int64Flag{
-			Name:  "chan_id",
-			Usage: "the 8-byte compact channel ID to query for",
+			Name:  "amt, a",
+			Usage: "number of satoshis to send",
+		},
+		cli.StringFlag{
+			Name:  "payment_hash, r",
+			Usage: "the hash to use within the payment's HTLC",
+		},
+		cli.BoolFlag{
+			Name:  "debug_send",
+			Usage: "use the debug rHash when sending the HTLC",
+		},
+		cli.StringFlag{
+			Name:  "pay_req",
+			Usage: "a zbase32-check encoded payment request to fulfill",
+		},
+		cli.StringFlag{
+			Name: "fee_preset",
+			Usage: "the name of the fee preset whose fee, time lock " +
+				"and retry limits should be applied to the " +
+				"payment, see feepresets",
+		},
+		cli.Int64Flag{
+			Name: "payment_id",
+			Usage: "a unique identifier for the payment, so that " +
+				"retrying the command after a failure won't " +
+				"pay twice",
 		},
 	},
-	Action: getChanInfo,
+	Action: sendPayment,
 }
 
-func getChanInfo(ctx *cli.Context) error {
-	ctxb := context.Background()
+func sendPayment(ctx *cli.Context) error {
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
-	var (
-		chanID int64
-		err    error
-	)
-
-	switch {
-	case ctx.IsSet("chan_id"):
-		chanID = ctx.Int64("chan_id")
-	case ctx.Args().Present():
-		chanID, err = strconv.ParseInt(ctx.Args().First(), 10, 64)
-	default:
-		return fmt.Errorf("chan_id argument missing")
+	// Show command help if no arguments provieded
+	if ctx.NArg() == 0 && ctx.NumFlags() == 0 {
+		cli.ShowCommandHelp(ctx, "sendpayment")
+		return nil
 	}
 
-	req := &lnrpc.ChanInfoRequest{
+	var req *lnrpc.SendRequest
+	if ctx.IsSet("pay_req") {
+		req = &lnrpc.SendRequest{
+			PaymentRequest: ctx.String("pay_req"),
+		}
+	} else {
+		args := ctx.Args()
+
+		var (
+			destNode string
+			err      error
+			amount   int64
+		)
+
+		// The destination is resolved by lnd, so it may be given as
+		// either a public key or an alias.
+		switch {
+		case ctx.IsSet("dest"):
+			destNode = ctx.String("dest")
+		case args.Present():
+			destNode = args.First()
+			args = args.Tail()
+		default:
+			return fmt.Errorf("destination txid argument missing")
+		}
+
+		if ctx.IsSet("amt") {
+			amount = ctx.Int64("amt")
+		} else if args.Present() {
+			amount, err = strconv.ParseInt(args.First(), 10, 64)
+			args = args.Tail()
+			if err != nil {
+				return fmt.Errorf("unable to decode payment amount: %v", err)
+			}
+		}
+
+		req = &lnrpc.SendRequest{
+			DestString: destNode,
+			Amt:        amount,
+		}
+
+		if ctx.Bool("debug_send") && (ctx.IsSet("payment_hash") || args.Present()) {
+			return fmt.Errorf("do not provide a payment hash with debug send")
+		} else if !ctx.Bool("debug_send") {
+			var rHash []byte
+
+			switch {
+			case ctx.IsSet("payment_hash"):
+				rHash, err = hex.DecodeString(ctx.String("payment_hash"))
+			case args.Present():
+				rHash, err = hex.DecodeString(args.First())
+			default:
+				return fmt.Errorf("payment hash argument missing")
+			}
+
+			if err != nil {
+				return err
+			}
+			if len(rHash) != 32 {
+				return fmt.Errorf("payment hash must be exactly 32 "+
+					"bytes, is instead %v", len(rHash))
+			}
+			req.PaymentHash = rHash
+		}
+	}
+
+	req.FeePreset = ctx.String("fee_preset")
+	req.PaymentId = uint64(ctx.Int64("payment_id"))
+
+	paymentStream, err := client.SendPayment(context.Background())
+	if err != nil {
+		return err
+	}
+
+	if err := paymentStream.Send(req); err != nil {
+		return err
+	}
+
+	resp, err := paymentStream.Recv()
+	if err != nil {
+		return err
+	}
+
+	paymentStream.CloseSend()
+
+	printJSON(struct {
+		P string       `json:"payment_preimage"`
+		R *lnrpc.Route `json:"payment_route"`
+	}{
+		P: hex.EncodeToString(resp.PaymentPreimage),
+		R: resp.PaymentRoute,
+	})
+
+	return nil
+}
+
+var addInvoiceCommand = cli.Command{
+	Name:  "addinvoice",
+	Usage: "add a new invoice.",
+	Description: "Add a new invoice, expressing intent for a future payment. " +
+		"The value of the invoice in satoshis and a 32 byte hash preimage are neccesary for the creation",
+	ArgsUsage: "value preimage",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "memo",
+			Usage: "an optional memo to attach along with the invoice",
+		},
+		cli.StringFlag{
+			Name:  "receipt",
+			Usage: "an optional cryptographic receipt of payment",
+		},
+		cli.StringFlag{
+			Name:  "preimage",
+			Usage: "the hex-encoded preimage (32 byte) which will allow settling an incoming HTLC payable to this preimage",
+		},
+		cli.Int64Flag{
+			Name:  "value",
+			Usage: "the value of this invoice in satoshis",
+		},
+		cli.BoolFlag{
+			Name: "onchain_fallback",
+			Usage: "generate an on-chain address which the invoice " +
+				"may also be paid to, included within the " +
+				"unified URI",
+		},
+		cli.BoolFlag{
+			Name: "derive_preimage",
+			Usage: "derive the preimage from the wallet's seed and " +
+				"the invoice's index, rather than from fresh " +
+				"randomness, so it can be recovered from the seed",
+		},
+		cli.StringFlag{
+			Name:  "qr_file",
+			Usage: "write a QR code of the unified URI to the given PNG file",
+		},
+		cli.Int64Flag{
+			Name: "expiry",
+			Usage: "the number of seconds after which the invoice " +
+				"can no longer be paid, the invoice never " +
+				"expires if unset",
+		},
+	},
+	Action: addInvoice,
+}
+
+func addInvoice(ctx *cli.Context) error {
+	var (
+		preimage []byte
+		receipt  []byte
+		value    int64
+		err      error
+	)
+
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	args := ctx.Args()
+
+	switch {
+	case ctx.IsSet("value"):
+		value = ctx.Int64("value")
+	case args.Present():
+		value, err = strconv.ParseInt(args.First(), 10, 64)
+		args = args.Tail()
+		if err != nil {
+			return fmt.Errorf("unable to decode value argument: %v", err)
+		}
+	default:
+		return fmt.Errorf("value argument missing")
+	}
+
+	switch {
+	case ctx.IsSet("preimage"):
+		preimage, err = hex.DecodeString(ctx.String("preimage"))
+	case args.Present():
+		preimage, err = hex.DecodeString(args.First())
+	}
+
+	if err != nil {
+		return fmt.Errorf("unable to parse preimage: %v", err)
+	}
+
+	receipt, err = hex.DecodeString(ctx.String("receipt"))
+	if err != nil {
+		return fmt.Errorf("unable to parse receipt: %v", err)
+	}
+
+	invoice := &lnrpc.Invoice{
+		Memo:            ctx.String("memo"),
+		Receipt:         receipt,
+		RPreimage:       preimage,
+		Value:           value,
+		OnchainFallback: ctx.Bool("onchain_fallback"),
+		QrCode:          ctx.IsSet("qr_file"),
+		DerivePreimage:  ctx.Bool("derive_preimage"),
+		Expiry:          ctx.Int64("expiry"),
+	}
+
+	resp, err := client.AddInvoice(context.Background(), invoice)
+	if err != nil {
+		return err
+	}
+
+	if ctx.IsSet("qr_file") {
+		err := ioutil.WriteFile(ctx.String("qr_file"), resp.QrPng, 0644)
+		if err != nil {
+			return err
+		}
+	}
+
+	printJSON(struct {
+		RHash        string `json:"r_hash"`
+		PayReq       string `json:"pay_req"`
+		UnifiedURI   string `json:"unified_uri"`
+		FallbackAddr string `json:"fallback_addr,omitempty"`
+	}{
+		RHash:        hex.EncodeToString(resp.RHash),
+		PayReq:       resp.PaymentRequest,
+		UnifiedURI:   resp.UnifiedUri,
+		FallbackAddr: resp.FallbackAddr,
+	})
+
+	return nil
+}
+
+var lookupInvoiceCommand = cli.Command{
+	Name:      "lookupinvoice",
+	Usage:     "Lookup an existing invoice by its payment hash.",
+	ArgsUsage: "rhash",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "rhash",
+			Usage: "the 32 byte payment hash of the invoice to query for, the hash " +
+				"should be a hex-encoded string",
+		},
+	},
+	Action: lookupInvoice,
+}
+
+func lookupInvoice(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	var (
+		rHash []byte
+		err   error
+	)
+
+	switch {
+	case ctx.IsSet("rhash"):
+		rHash, err = hex.DecodeString(ctx.String("rhash"))
+	case ctx.Args().Present():
+		rHash, err = hex.DecodeString(ctx.Args().First())
+	default:
+		return fmt.Errorf("rhash argument missing")
+	}
+
+	if err != nil {
+		return fmt.Errorf("unable to decode rhash argument: %v", err)
+	}
+
+	req := &lnrpc.PaymentHash{
+		RHash: rHash,
+	}
+
+	invoice, err := client.LookupInvoice(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(invoice)
+
+	return nil
+}
+
+var listInvoicesCommand = cli.Command{
+	Name:  "listinvoices",
+	Usage: "List all invoices currently stored.",
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name: "pending_only",
+			Usage: "toggles if all invoices should be returned, or only " +
+				"those that are currently unsettled",
+		},
+	},
+	Action: listInvoices,
+}
+
+func listInvoices(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	pendingOnly := true
+	if !ctx.Bool("pending_only") {
+		pendingOnly = false
+	}
+
+	req := &lnrpc.ListInvoiceRequest{
+		PendingOnly: pendingOnly,
+	}
+
+	invoices, err := client.ListInvoices(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(invoices)
+
+	return nil
+}
+
+var addOfferCommand = cli.Command{
+	Name:  "addoffer",
+	Usage: "add a new reusable offer.",
+	Description: "Add a new reusable offer. The returned offer code can be " +
+		"paid any number of times, each payer requesting a fresh invoice " +
+		"for the offer. If the value is omitted, the payer selects the amount.",
+	ArgsUsage: "value",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "memo",
+			Usage: "an optional memo to attach to each invoice created from the offer",
+		},
+		cli.Int64Flag{
+			Name:  "value",
+			Usage: "the value of each payment in satoshis",
+		},
+	},
+	Action: addOffer,
+}
+
+func addOffer(ctx *cli.Context) error {
+	var (
+		value int64
+		err   error
+	)
+
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	args := ctx.Args()
+
+	switch {
+	case ctx.IsSet("value"):
+		value = ctx.Int64("value")
+	case args.Present():
+		value, err = strconv.ParseInt(args.First(), 10, 64)
+		if err != nil {
+			return fmt.Errorf("unable to decode value argument: %v", err)
+		}
+	}
+
+	offer := &lnrpc.Offer{
+		Memo:  ctx.String("memo"),
+		Value: value,
+	}
+
+	resp, err := client.AddOffer(context.Background(), offer)
+	if err != nil {
+		return err
+	}
+
+	printJSON(struct {
+		OfferID   string `json:"offer_id"`
+		OfferCode string `json:"offer_code"`
+	}{
+		OfferID:   hex.EncodeToString(resp.OfferId),
+		OfferCode: resp.OfferCode,
+	})
+
+	return nil
+}
+
+var listOffersCommand = cli.Command{
+	Name:   "listoffers",
+	Usage:  "List all reusable offers currently stored.",
+	Action: listOffers,
+}
+
+func listOffers(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	offers, err := client.ListOffers(context.Background(),
+		&lnrpc.ListOffersRequest{})
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(offers)
+
+	return nil
+}
+
+var requestOfferInvoiceCommand = cli.Command{
+	Name:  "requestofferinvoice",
+	Usage: "request a fresh invoice for a reusable offer.",
+	Description: "Request a fresh invoice from the node which created the " +
+		"passed offer code. The returned payment request can then be paid " +
+		"using sendpayment. If the node which created the offer isn't " +
+		"a connected peer, then the request is routed to it over " +
+		"onion messages through the channel graph.",
+	ArgsUsage: "offer_code [amt]",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "offer_code",
+			Usage: "the encoded offer code",
+		},
+		cli.Int64Flag{
+			Name: "amt",
+			Usage: "the amount to pay in satoshis, only used if the " +
+				"offer doesn't specify an amount",
+		},
+	},
+	Action: requestOfferInvoice,
+}
+
+func requestOfferInvoice(ctx *cli.Context) error {
+	var (
+		offerCode string
+		amt       int64
+		err       error
+	)
+
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	args := ctx.Args()
+
+	switch {
+	case ctx.IsSet("offer_code"):
+		offerCode = ctx.String("offer_code")
+	case args.Present():
+		offerCode = args.First()
+		args = args.Tail()
+	default:
+		return fmt.Errorf("offer_code argument missing")
+	}
+
+	switch {
+	case ctx.IsSet("amt"):
+		amt = ctx.Int64("amt")
+	case args.Present():
+		amt, err = strconv.ParseInt(args.First(), 10, 64)
+		if err != nil {
+			return fmt.Errorf("unable to decode amt argument: %v", err)
+		}
+	}
+
+	resp, err := client.RequestOfferInvoice(context.Background(),
+		&lnrpc.OfferInvoiceRequest{
+			OfferCode: offerCode,
+			Amt:       amt,
+		})
+	if err != nil {
+		return err
+	}
+
+	printJSON(struct {
+		RHash  string `json:"r_hash"`
+		PayReq string `json:"pay_req"`
+	}{
+		RHash:  hex.EncodeToString(resp.RHash),
+		PayReq: resp.PaymentRequest,
+	})
+
+	return nil
+}
+
+var describeGraphCommand = cli.Command{
+	Name: "describegraph",
+	Description: "prints a human readable version of the known channel " +
+		"graph from the PoV of the node",
+	Usage: "describe the network graph",
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "render",
+			Usage: "If set, then an image of graph will be generated and displayed. The generated image is stored within the current directory with a file name of 'graph.svg'",
+		},
+	},
+	Action: describeGraph,
+}
+
+func describeGraph(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.ChannelGraphRequest{}
+
+	graph, err := client.DescribeGraph(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	// If the draw flag is on, then we'll use the 'dot' command to create a
+	// visualization of the graph itself.
+	if ctx.Bool("render") {
+		return drawChannelGraph(graph)
+	}
+
+	printRespJSON(graph)
+	return nil
+}
+
+// normalizeFunc is a factory function which returns a function that normalizes
+// the capacity of of edges within the graph. The value of the returned
+// function can be used to either plot the capacities, or to use a weight in a
+// rendering of the graph.
+func normalizeFunc(edges []*lnrpc.ChannelEdge, scaleFactor float64) func(int64) float64 {
+	var (
+		min float64 = math.MaxInt64
+		max float64
+	)
+
+	for _, edge := range edges {
+		// In order to obtain saner values, we reduce the capacity of a
+		// channel to it's base 2 logarithm.
+		z := math.Log2(float64(edge.Capacity))
+
+		if z < min {
+			min = z
+		}
+		if z > max {
+			max = z
+		}
+	}
+
+	return func(x int64) float64 {
+		y := math.Log2(float64(x))
+
+		// TODO(roasbeef): results in min being zero
+		return (y - min) / (max - min) * scaleFactor
+	}
+}
+
+func drawChannelGraph(graph *lnrpc.ChannelGraph) error {
+	// First we'll create a temporary file that we'll write the compiled
+	// string that describes our graph in the dot format to.
+	tempDotFile, err := ioutil.TempFile("", "")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tempDotFile.Name())
+
+	// Next, we'll create (or re-create) the file that the final graph
+	// image will be written to.
+	imageFile, err := os.Create("graph.svg")
+	if err != nil {
+		return err
+	}
+
+	// With our temporary files set up, we'll initialize the graphviz
+	// object that we'll use to draw our graph.
+	graphName := "LightningNetwork"
+	graphCanvas := gographviz.NewGraph()
+	graphCanvas.SetName(graphName)
+	graphCanvas.SetDir(false)
+
+	const numKeyChars = 10
+
+	truncateStr := func(k string, n uint) string {
+		return k[:n]
+	}
+
+	// For each node within the graph, we'll add a new vertex to the graph.
+	for _, node := range graph.Nodes {
+		// Rather than using the entire hex-encoded string, we'll only
+		// use the first 10 characters. We also add a prefix of "Z" as
+		// graphviz is unable to parse the compressed pubkey as a
+		// non-integer.
+		//
+		// TODO(roasbeef): should be able to get around this?
+		nodeID := fmt.Sprintf(`"%v"`, truncateStr(node.PubKey, numKeyChars))
+
+		graphCanvas.AddNode(graphName, nodeID, gographviz.Attrs{})
+	}
+
+	normalize := normalizeFunc(graph.Edges, 3)
+
+	// Similarly, for each edge we'll add an edge between the corresponding
+	// nodes added to the graph above.
+	for _, edge := range graph.Edges {
+		// Once again, we add a 'Z' prefix so we're compliant with the
+		// dot grammar.
+		src := fmt.Sprintf(`"%v"`, truncateStr(edge.Node1Pub, numKeyChars))
+		dest := fmt.Sprintf(`"%v"`, truncateStr(edge.Node2Pub, numKeyChars))
+
+		// The weight for our edge will be the total capacity of the
+		// channel, in BTC.
+		// TODO(roasbeef): can also factor in the edges time-lock delta
+		// and fee information
+		amt := btcutil.Amount(edge.Capacity).ToBTC()
+		edgeWeight := strconv.FormatFloat(amt, 'f', -1, 64)
+
+		// The label for each edge will simply be a truncated version
+		// of it's channel ID.
+		chanIDStr := strconv.FormatUint(edge.ChannelId, 10)
+		edgeLabel := fmt.Sprintf(`"cid:%v"`, truncateStr(chanIDStr, 7))
+
+		// We'll also use a normalized version of the channels'
+		// capacity in satoshis in order to modulate the "thickness" of
+		// the line that creates the edge within the graph.
+		normalizedCapacity := normalize(edge.Capacity)
+		edgeThickness := strconv.FormatFloat(normalizedCapacity, 'f', -1, 64)
+
+		// TODO(roasbeef): color code based on percentile capacity
+		graphCanvas.AddEdge(src, dest, false, gographviz.Attrs{
+			"penwidth": edgeThickness,
+			"weight":   edgeWeight,
+			"label":    edgeLabel,
+		})
+	}
+
+	// With the declarative generation of the graph complete, we now write
+	// the dot-string description of the graph
+	graphDotString := graphCanvas.String()
+	if _, err := tempDotFile.WriteString(graphDotString); err != nil {
+		return err
+	}
+	if err := tempDotFile.Sync(); err != nil {
+		return err
+	}
+
+	var errBuffer bytes.Buffer
+
+	// Once our dot file has been written to disk, we can use the dot
+	// command itself to generate the drawn rendering of the graph
+	// described.
+	drawCmd := exec.Command("dot", "-T"+"svg", "-o"+imageFile.Name(),
+		tempDotFile.Name())
+	drawCmd.Stderr = &errBuffer
+	if err := drawCmd.Run(); err != nil {
+		fmt.Println("error rendering graph: ", errBuffer.String())
+		fmt.Println("dot: ", graphDotString)
+
+		return err
+	}
+
+	errBuffer.Reset()
+
+	// Finally, we'll open the drawn graph to display to the user.
+	openCmd := exec.Command("open", imageFile.Name())
+	openCmd.Stderr = &errBuffer
+	if err := openCmd.Run(); err != nil {
+		fmt.Println("error opening rendered graph image: ",
+			errBuffer.String())
+		return err
+	}
+
+	return nil
+}
+
+var listPaymentsCommand = cli.Command{
+	Name:   "listpayments",
+	Usage:  "list all outgoing payments",
+	Action: listPayments,
+}
+
+func listPayments(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.ListPaymentsRequest{}
+
+	payments, err := client.ListPayments(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(payments)
+	return nil
+}
+
+var listPaymentAttemptsCommand = cli.Command{
+	Name:      "listpaymentattempts",
+	Usage:     "list the attempts made at sending a payment",
+	ArgsUsage: "payment_hash",
+	Description: "Lists each attempt made at sending the payment with the " +
+		"passed payment hash, along with the route and fee limit of " +
+		"each attempt, and why it failed. Attempts are recorded " +
+		"whether or not the payment ultimately succeeded.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "payment_hash",
+			Usage: "the hex-encoded payment hash of the payment",
+		},
+	},
+	Action: listPaymentAttempts,
+}
+
+func listPaymentAttempts(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	var paymentHash string
+	switch {
+	case ctx.IsSet("payment_hash"):
+		paymentHash = ctx.String("payment_hash")
+	case ctx.Args().Present():
+		paymentHash = ctx.Args().First()
+	default:
+		return cli.ShowCommandHelp(ctx, "listpaymentattempts")
+	}
+
+	req := &lnrpc.ListPaymentAttemptsRequest{
+		PaymentHash: paymentHash,
+	}
+
+	resp, err := client.ListPaymentAttempts(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var paymentTelemetryCommand = cli.Command{
+	Name:  "paymenttelemetry",
+	Usage: "report the success rate and latency of recent payments",
+	Description: "Reports the success rate, average number of attempts, " +
+		"latency percentiles, and most common failure codes of the " +
+		"payments recently sent by the node, allowing the quality of " +
+		"its routes to be compared before and after adjusting fees " +
+		"or channels. Requires the node to be started with " +
+		"--paymenttelemetry.",
+	Action: paymentTelemetry,
+}
+
+func paymentTelemetry(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.PaymentTelemetryRequest{}
+	resp, err := client.PaymentTelemetry(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var towerInfoCommand = cli.Command{
+	Name:  "towerinfo",
+	Usage: "report the state held by the node's watchtower",
+	Description: "Reports the number of sessions opened with the node's " +
+		"watchtower, along with the number and total size of the " +
+		"justice transactions it stores on behalf of its clients. " +
+		"The node acts as a watchtower if started with " +
+		"--watchtower.active.",
+	Action: towerInfo,
+}
+
+func towerInfo(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.TowerInfoRequest{}
+	resp, err := client.TowerInfo(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var revenueReportCommand = cli.Command{
+	Name:  "revenuereport",
+	Usage: "report the revenue earned on each day",
+	Description: "Reports the revenue earned from settled invoices and " +
+		"forwarding fees on each UTC day within the passed range of " +
+		"unix timestamps, omitting days without any revenue. Requires " +
+		"the node to be started with --analyticsdb.",
+	Flags: []cli.Flag{
+		cli.Int64Flag{
+			Name:  "start_time",
+			Usage: "the unix timestamp of the start of the range",
+		},
+		cli.Int64Flag{
+			Name: "end_time",
+			Usage: "the unix timestamp of the end of the range, " +
+				"the current time if unset",
+		},
+	},
+	Action: revenueReport,
+}
+
+func revenueReport(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.RevenueReportRequest{
+		StartTime: ctx.Int64("start_time"),
+		EndTime:   ctx.Int64("end_time"),
+	}
+	resp, err := client.RevenueReport(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var topCounterpartiesCommand = cli.Command{
+	Name:  "topcounterparties",
+	Usage: "report the counterparties with the most volume",
+	Description: "Reports the counterparties the node has exchanged the " +
+		"most volume with, through both outgoing payments and " +
+		"forwarded HTLCs. Requires the node to be started with " +
+		"--analyticsdb.",
+	Flags: []cli.Flag{
+		cli.IntFlag{
+			Name:  "limit",
+			Usage: "the maximum number of counterparties to report",
+			Value: 10,
+		},
+	},
+	Action: topCounterparties,
+}
+
+func topCounterparties(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.TopCounterpartiesRequest{
+		Limit: uint32(ctx.Int("limit")),
+	}
+	resp, err := client.TopCounterparties(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var deleteAllPaymentsCommand = cli.Command{
+	Name:  "deleteallpayments",
+	Usage: "delete all outgoing payments",
+	Description: "Deletes the record of every outgoing payment, printing " +
+		"the number of payments deleted. With --dry_run, the payments " +
+		"are only counted.",
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "dry_run",
+			Usage: "count the payments without deleting them",
+		},
+	},
+	Action: deleteAllPayments,
+}
+
+func deleteAllPayments(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.DeleteAllPaymentsRequest{
+		DryRun: ctx.Bool("dry_run"),
+	}
+
+	resp, err := client.DeleteAllPayments(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var feePresetsCommand = cli.Command{
+	Name:  "feepresets",
+	Usage: "list the fee presets payments may select",
+	Description: "prints out the name of each fee preset along with the " +
+		"fee, time lock and retry limits it applies to a payment",
+	Action: feePresets,
+}
+
+func feePresets(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.ListFeePresetsRequest{}
+
+	presets, err := client.ListFeePresets(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(presets)
+	return nil
+}
+
+var getChanInfoCommand = cli.Command{
+	Name:  "getchaninfo",
+	Usage: "get the state of a channel",
+	Description: "prints out the latest authenticated state for a " +
+		"particular channel",
+	ArgsUsage: "chan_id",
+	Flags: []cli.Flag{
+		cli.Int64Flag{
+			Name:  "chan_id",
+			Usage: "the 8-byte compact channel ID to query for",
+		},
+	},
+	Action: getChanInfo,
+}
+
+func getChanInfo(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	var (
+		chanID int64
+		err    error
+	)
+
+	switch {
+	case ctx.IsSet("chan_id"):
+		chanID = ctx.Int64("chan_id")
+	case ctx.Args().Present():
+		chanID, err = strconv.ParseInt(ctx.Args().First(), 10, 64)
+	default:
+		return fmt.Errorf("chan_id argument missing")
+	}
+
+	req := &lnrpc.ChanInfoRequest{
 		ChanId: uint64(chanID),
 	}
 
-	chanInfo, err := client.GetChanInfo(ctxb, req)
-	if err != nil {
+	chanInfo, err := client.GetChanInfo(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(chanInfo)
+	return nil
+}
+
+var getNodeInfoCommand = cli.Command{
+	Name:  "getnodeinfo",
+	Usage: "Get information on a specific node.",
+	Description: "prints out the latest authenticated node state for an " +
+		"advertised node",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "pub_key",
+			Usage: "the 33-byte hex-encoded compressed public of the target " +
+				"node, or its alias",
+		},
+	},
+	Action: getNodeInfo,
+}
+
+func getNodeInfo(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	if !ctx.IsSet("pub_key") {
+		return fmt.Errorf("pub_key argument missing")
+	}
+
+	req := &lnrpc.NodeInfoRequest{
+		PubKey: ctx.String("pub_key"),
+	}
+
+	nodeInfo, err := client.GetNodeInfo(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(nodeInfo)
+	return nil
+}
+
+var queryRouteCommand = cli.Command{
+	Name:        "queryroute",
+	Usage:       "Query a route to a destination.",
+	Description: "Queries the channel router for a potential path to the destination that has sufficient flow for the amount including fees",
+	ArgsUsage:   "dest amt",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "dest",
+			Usage: "the 33-byte hex-encoded public key for the payment " +
+				"destination, or its alias",
+		},
+		cli.Int64Flag{
+			Name:  "amt",
+			Usage: "the amount to send expressed in satoshis",
+		},
+	},
+	Action: queryRoute,
+}
+
+func queryRoute(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	var (
+		dest string
+		amt  int64
+		err  error
+	)
+
+	args := ctx.Args()
+
+	switch {
+	case ctx.IsSet("dest"):
+		dest = ctx.String("dest")
+	case args.Present():
+		dest = args.First()
+		args = args.Tail()
+	default:
+		return fmt.Errorf("dest argument missing")
+	}
+
+	switch {
+	case ctx.IsSet("amt"):
+		amt = ctx.Int64("amt")
+	case args.Present():
+		amt, err = strconv.ParseInt(args.First(), 10, 64)
+		if err != nil {
+			return fmt.Errorf("unable to decode amt argument: %v", err)
+		}
+	default:
+		return fmt.Errorf("amt argument missing")
+	}
+
+	req := &lnrpc.RouteRequest{
+		PubKey: dest,
+		Amt:    amt,
+	}
+
+	route, err := client.QueryRoute(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(route)
+	return nil
+}
+
+var getNetworkInfoCommand = cli.Command{
+	Name:  "getnetworkinfo",
+	Usage: "getnetworkinfo",
+	Description: "returns a set of statistics pertaining to the known channel " +
+		"graph",
+	Action: getNetworkInfo,
+}
+
+func getNetworkInfo(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.NetworkInfoRequest{}
+
+	netInfo, err := client.GetNetworkInfo(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(netInfo)
+	return nil
+}
+
+var debugLevelCommand = cli.Command{
+	Name:        "debuglevel",
+	Usage:       "Set the debug level.",
+	Description: "Logging level for all subsystems {trace, debug, info, warn, error, critical} -- You may also specify <subsystem>=<level>,<subsystem2>=<level>,... to set the log level for individual subsystems -- Use show to list available subsystems",
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "show",
+			Usage: "if true, then the list of available sub-systems will be printed out",
+		},
+		cli.StringFlag{
+			Name:  "level",
+			Usage: "the level specification to target either a coarse logging level, or granular set of specific sub-systems with loggin levels for each",
+		},
+	},
+	Action: debugLevel,
+}
+
+func debugLevel(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.DebugLevelRequest{
+		Show:      ctx.Bool("show"),
+		LevelSpec: ctx.String("level"),
+	}
+
+	resp, err := client.DebugLevel(ctxb, req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var dbCommitStatsCommand = cli.Command{
+	Name:  "dbcommitstats",
+	Usage: "report the latency of database write transactions",
+	Description: "Report the number, total latency and maximum latency " +
+		"of the database's write transactions made by each call " +
+		"site since lnd started, and whether they're batched.",
+	Action: dbCommitStats,
+}
+
+func dbCommitStats(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.DBCommitStatsRequest{}
+	resp, err := client.DBCommitStats(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var createPayReqCommand = cli.Command{
+	Name:  "createpayreq",
+	Usage: "Create a signed payment request for an existing invoice.",
+	Description: "Create a bech32 encoded payment request for the invoice " +
+		"with the passed payment hash, signed by this node, and carrying " +
+		"the invoice's memo and an expiry",
+	ArgsUsage: "rhash",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name: "rhash",
+			Usage: "the 32 byte payment hash of the invoice, the hash " +
+				"should be a hex-encoded string",
+		},
+		cli.Int64Flag{
+			Name: "expiry",
+			Usage: "the number of seconds during which the payment " +
+				"request may be paid, capped by the expiry of the " +
+				"invoice, and defaulting to it, or to one hour if " +
+				"the invoice never expires",
+		},
+	},
+	Action: createPayReq,
+}
+
+func createPayReq(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	var (
+		rHash []byte
+		err   error
+	)
+
+	switch {
+	case ctx.IsSet("rhash"):
+		rHash, err = hex.DecodeString(ctx.String("rhash"))
+	case ctx.Args().Present():
+		rHash, err = hex.DecodeString(ctx.Args().First())
+	default:
+		return fmt.Errorf("rhash argument missing")
+	}
+
+	if err != nil {
+		return fmt.Errorf("unable to decode rhash argument: %v", err)
+	}
+
+	req := &lnrpc.CreatePayReqRequest{
+		RHash:  rHash,
+		Expiry: ctx.Int64("expiry"),
+	}
+
+	resp, err := client.CreatePayReq(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var decodePayReqComamnd = cli.Command{
+	Name:        "decodepayreq",
+	Usage:       "Decode a payment request.",
+	Description: "Decode the passed payment request revealing the destination, payment hash and value of the payment request",
+	ArgsUsage:   "pay_req",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "pay_req",
+			Usage: "the zpay32 encoded payment request",
+		},
+	},
+	Action: decodePayReq,
+}
+
+func decodePayReq(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	var payreq string
+
+	switch {
+	case ctx.IsSet("pay_req"):
+		payreq = ctx.String("pay_req")
+	case ctx.Args().Present():
+		payreq = ctx.Args().First()
+	default:
+		return fmt.Errorf("pay_req argument missing")
+	}
+
+	resp, err := client.DecodePayReq(ctxb, &lnrpc.PayReqString{
+		PayReq: payreq,
+	})
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var listChainTxnsCommand = cli.Command{
+	Name:        "listchaintxns",
+	Usage:       "List transactions from the wallet.",
+	Description: "List all transactions an address of the wallet was involved in.",
+	Action:      listChainTxns,
+}
+
+func listChainTxns(ctx *cli.Context) error {
+	ctxb := context.Background()
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	resp, err := client.GetTransactions(ctxb, &lnrpc.GetTransactionsRequest{})
+
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var abandonChannelCommand = cli.Command{
+	Name:  "abandonchannel",
+	Usage: "abandon a channel which can no longer be closed",
+	Description: "Remove a channel whose funding transaction will never " +
+		"confirm, or whose remote party has vanished, from the node " +
+		"without closing it on-chain. The channel's state is archived " +
+		"for later audit, and any of the wallet's outputs locked to " +
+		"fund the channel are unlocked. Any funds within the channel " +
+		"are forfeit, so channels which can be closed must be closed " +
+		"instead.",
+	ArgsUsage: "funding_txid [output_index]",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "funding_txid",
+			Usage: "the txid of the channel's funding transaction",
+		},
+		cli.IntFlag{
+			Name: "output_index",
+			Usage: "the output index for the funding output of the funding " +
+				"transaction",
+		},
+	},
+	Action: abandonChannel,
+}
+
+func abandonChannel(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	args := ctx.Args()
+	var txid string
+
+	switch {
+	case ctx.IsSet("funding_txid"):
+		txid = ctx.String("funding_txid")
+	case args.Present():
+		txid = args.First()
+		args = args.Tail()
+	default:
+		return fmt.Errorf("funding txid argument missing")
+	}
+
+	txidHash, err := chainhash.NewHashFromStr(txid)
+	if err != nil {
+		return err
+	}
+	chanPoint := &lnrpc.ChannelPoint{
+		FundingTxid: txidHash[:],
+	}
+
+	switch {
+	case ctx.IsSet("output_index"):
+		chanPoint.OutputIndex = uint32(ctx.Int("output_index"))
+	case args.Present():
+		index, err := strconv.ParseInt(args.First(), 10, 32)
+		if err != nil {
+			return fmt.Errorf("unable to decode output index: %v", err)
+		}
+		chanPoint.OutputIndex = uint32(index)
+	}
+
+	resp, err := client.AbandonChannel(context.Background(), chanPoint)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+
+	return nil
+}
+
+var rotateIdentityCommand = cli.Command{
+	Name:  "rotateidentity",
+	Usage: "rotate the node's identity key",
+	Description: "Rotate the node's identity key, as after its partial " +
+		"compromise. Each peer the node has channels with is " +
+		"notified of the new identity, so the channels are carried " +
+		"over to it, while each announced channel is cooperatively " +
+		"closed. All such peers must be online and support identity " +
+		"rotation. The new identity key is used once lnd is " +
+		"restarted. Each peer carries the channels over once its " +
+		"operator confirms the new identity with " +
+		"confirmidentityrotation, or its identity rotation delay " +
+		"elapses.",
+	Action: rotateIdentity,
+}
+
+func rotateIdentity(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.RotateIdentityRequest{}
+	resp, err := client.RotateIdentity(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var confirmIdentityRotationCommand = cli.Command{
+	Name:  "confirmidentityrotation",
+	Usage: "confirm the identity rotation of a peer",
+	Description: "Confirm that a peer is rotating its identity to the " +
+		"given key, as conveyed by the peer's operator out of band. " +
+		"Our channels with the peer are then carried over to its " +
+		"new identity as soon as it connects under it, rather than " +
+		"once the identity rotation delay elapses.",
+	ArgsUsage: "identity_pubkey",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "identity_pubkey",
+			Usage: "the new identity key announced by the peer",
+		},
+	},
+	Action: confirmIdentityRotation,
+}
+
+func confirmIdentityRotation(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	var pubKey string
+	switch {
+	case ctx.IsSet("identity_pubkey"):
+		pubKey = ctx.String("identity_pubkey")
+	case ctx.Args().Present():
+		pubKey = ctx.Args().First()
+	default:
+		return fmt.Errorf("identity_pubkey argument missing")
+	}
+
+	req := &lnrpc.ConfirmIdentityRotationRequest{
+		IdentityPubkey: pubKey,
+	}
+	resp, err := client.ConfirmIdentityRotation(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var mineBlocksCommand = cli.Command{
+	Name:  "mineblocks",
+	Usage: "mine blocks on the virtual chain of a simulation",
+	Description: "Mine the given number of blocks on the virtual chain, " +
+		"confirming all transactions within its mempool. The block " +
+		"subsidies and fees are paid to the wallet. Only available " +
+		"when lnd runs in simulation mode.",
+	ArgsUsage: "num_blocks",
+	Flags: []cli.Flag{
+		cli.Int64Flag{
+			Name:  "num_blocks",
+			Usage: "the number of blocks to mine",
+		},
+	},
+	Action: mineBlocks,
+}
+
+func mineBlocks(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	var (
+		numBlocks int64
+		err       error
+	)
+	args := ctx.Args()
+	switch {
+	case ctx.IsSet("num_blocks"):
+		numBlocks = ctx.Int64("num_blocks")
+	case args.Present():
+		numBlocks, err = strconv.ParseInt(args.First(), 10, 64)
+		if err != nil {
+			return fmt.Errorf("unable to decode num_blocks: %v", err)
+		}
+	default:
+		numBlocks = 1
+	}
+	if numBlocks <= 0 {
+		return fmt.Errorf("num_blocks must be positive")
+	}
+
+	req := &lnrpc.MineBlocksRequest{
+		NumBlocks: uint32(numBlocks),
+	}
+	resp, err := client.MineBlocks(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var advanceTimeCommand = cli.Command{
+	Name:  "advancetime",
+	Usage: "advance the clock of the virtual chain of a simulation",
+	Description: "Move the clock of the virtual chain forward by the " +
+		"given number of seconds without mining any block. The " +
+		"clock governs the timestamps of mined blocks and the time " +
+		"locks of published transactions. Only available when lnd " +
+		"runs in simulation mode.",
+	ArgsUsage: "seconds",
+	Flags: []cli.Flag{
+		cli.Int64Flag{
+			Name:  "seconds",
+			Usage: "the number of seconds to advance the clock by",
+		},
+	},
+	Action: advanceTime,
+}
+
+func advanceTime(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	var (
+		seconds int64
+		err     error
+	)
+	args := ctx.Args()
+	switch {
+	case ctx.IsSet("seconds"):
+		seconds = ctx.Int64("seconds")
+	case args.Present():
+		seconds, err = strconv.ParseInt(args.First(), 10, 64)
+		if err != nil {
+			return fmt.Errorf("unable to decode seconds: %v", err)
+		}
+	default:
+		return fmt.Errorf("seconds argument missing")
+	}
+
+	req := &lnrpc.AdvanceTimeRequest{
+		Seconds: seconds,
+	}
+	resp, err := client.AdvanceTime(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var exportChanStateCommand = cli.Command{
+	Name:  "exportchanstate",
+	Usage: "export the complete state of a channel for debugging",
+	Description: "Export the complete state of a channel as a redacted " +
+		"JSON document. Secrets which would allow the channel's funds " +
+		"to be stolen are omitted, so the document is safe to attach " +
+		"to bug reports. The channel may be reconstructed from the " +
+		"document on a simulated node with importchanstate.",
+	ArgsUsage: "funding_txid [output_index]",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "funding_txid",
+			Usage: "the txid of the channel's funding transaction",
+		},
+		cli.IntFlag{
+			Name: "output_index",
+			Usage: "the output index for the funding output of the funding " +
+				"transaction",
+		},
+		cli.StringFlag{
+			Name:  "output_file",
+			Usage: "the file to write the exported state to",
+		},
+	},
+	Action: exportChanState,
+}
+
+func exportChanState(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	args := ctx.Args()
+	var txid string
+
+	switch {
+	case ctx.IsSet("funding_txid"):
+		txid = ctx.String("funding_txid")
+	case args.Present():
+		txid = args.First()
+		args = args.Tail()
+	default:
+		return fmt.Errorf("funding txid argument missing")
+	}
+
+	txidHash, err := chainhash.NewHashFromStr(txid)
+	if err != nil {
+		return err
+	}
+	chanPoint := &lnrpc.ChannelPoint{
+		FundingTxid: txidHash[:],
+	}
+
+	switch {
+	case ctx.IsSet("output_index"):
+		chanPoint.OutputIndex = uint32(ctx.Int("output_index"))
+	case args.Present():
+		index, err := strconv.ParseInt(args.First(), 10, 32)
+		if err != nil {
+			return fmt.Errorf("unable to decode output index: %v", err)
+		}
+		chanPoint.OutputIndex = uint32(index)
+	}
+
+	resp, err := client.ExportChannelState(context.Background(), chanPoint)
+	if err != nil {
+		return err
+	}
+
+	if ctx.IsSet("output_file") {
+		return ioutil.WriteFile(ctx.String("output_file"),
+			[]byte(resp.StateJson), 0600)
+	}
+
+	fmt.Println(resp.StateJson)
+
+	return nil
+}
+
+var importChanStateCommand = cli.Command{
+	Name:  "importchanstate",
+	Usage: "reconstruct a channel from its exported state",
+	Description: "Reconstruct a channel from a JSON document written by " +
+		"exportchanstate, so an issue reported against it may be " +
+		"reproduced. The secrets omitted from the export are " +
+		"regenerated, so the channel can't be used on a live " +
+		"network. Only available when lnd runs in simulation mode.",
+	ArgsUsage: "input_file peer_address",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "input_file",
+			Usage: "the file holding the exported channel state",
+		},
+		cli.StringFlag{
+			Name: "peer_address",
+			Usage: "the host:port the channel's remote node is " +
+				"reachable at",
+		},
+	},
+	Action: importChanState,
+}
+
+func importChanState(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	args := ctx.Args()
+	var inputFile, peerAddr string
+
+	switch {
+	case ctx.IsSet("input_file"):
+		inputFile = ctx.String("input_file")
+	case args.Present():
+		inputFile = args.First()
+		args = args.Tail()
+	default:
+		return fmt.Errorf("input file argument missing")
+	}
+
+	switch {
+	case ctx.IsSet("peer_address"):
+		peerAddr = ctx.String("peer_address")
+	case args.Present():
+		peerAddr = args.First()
+	default:
+		return fmt.Errorf("peer address argument missing")
+	}
+
+	stateJSON, err := ioutil.ReadFile(inputFile)
+	if err != nil {
+		return err
+	}
+
+	req := &lnrpc.ImportChannelStateRequest{
+		StateJson:   string(stateJSON),
+		PeerAddress: peerAddr,
+	}
+	resp, err := client.ImportChannelState(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var chanHistoryCommand = cli.Command{
+	Name:  "chanhistory",
+	Usage: "list the timeline of events within the lifetime of a channel",
+	Description: "Prints out the events recorded within the lifetime of a " +
+		"channel, such as its opening, large balance changes, policy " +
+		"updates, peer disconnections, and its closure. The history of " +
+		"closed channels is retained. The events may be restricted to " +
+		"a time range, with each bound given either as a unix " +
+		"timestamp, or in RFC3339 format.",
+	ArgsUsage: "funding_txid [output_index]",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "funding_txid",
+			Usage: "the txid of the channel's funding transaction",
+		},
+		cli.IntFlag{
+			Name: "output_index",
+			Usage: "the output index for the funding output of the funding " +
+				"transaction",
+		},
+		cli.StringFlag{
+			Name:  "start",
+			Usage: "only list events which occurred at or after this time",
+		},
+		cli.StringFlag{
+			Name:  "end",
+			Usage: "only list events which occurred at or before this time",
+		},
+	},
+	Action: chanHistory,
+}
+
+func chanHistory(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	args := ctx.Args()
+	var txid string
+
+	switch {
+	case ctx.IsSet("funding_txid"):
+		txid = ctx.String("funding_txid")
+	case args.Present():
+		txid = args.First()
+		args = args.Tail()
+	default:
+		return fmt.Errorf("funding txid argument missing")
+	}
+
+	txidHash, err := chainhash.NewHashFromStr(txid)
+	if err != nil {
+		return err
+	}
+	req := &lnrpc.ChannelHistoryRequest{
+		ChannelPoint: &lnrpc.ChannelPoint{
+			FundingTxid: txidHash[:],
+		},
+	}
+
+	switch {
+	case ctx.IsSet("output_index"):
+		req.ChannelPoint.OutputIndex = uint32(ctx.Int("output_index"))
+	case args.Present():
+		index, err := strconv.ParseInt(args.First(), 10, 32)
+		if err != nil {
+			return fmt.Errorf("unable to decode output index: %v", err)
+		}
+		req.ChannelPoint.OutputIndex = uint32(index)
+	}
+
+	if ctx.IsSet("start") {
+		req.StartTime, err = parseTimestamp(ctx.String("start"))
+		if err != nil {
+			return err
+		}
+	}
+	if ctx.IsSet("end") {
+		req.EndTime, err = parseTimestamp(ctx.String("end"))
+		if err != nil {
+			return err
+		}
+	}
+
+	resp, err := client.ChannelHistory(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var stateLogCommand = cli.Command{
+	Name:  "statelog",
+	Usage: "list the state machine transitions of a channel",
+	Description: "Prints out the log of every transition of a channel's " +
+		"commitment state machine: each new state we propose, each " +
+		"signature and revocation exchanged with the peer, and the " +
+		"detection of the channel's closure. Each transition carries " +
+		"the heights of both commitment chains which followed it. The " +
+		"log of closed channels is retained.",
+	ArgsUsage: "funding_txid [output_index]",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "funding_txid",
+			Usage: "the txid of the channel's funding transaction",
+		},
+		cli.IntFlag{
+			Name: "output_index",
+			Usage: "the output index for the funding output of the funding " +
+				"transaction",
+		},
+		cli.IntFlag{
+			Name:  "limit",
+			Usage: "only list this many of the most recent transitions",
+		},
+	},
+	Action: stateLog,
+}
+
+func stateLog(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	args := ctx.Args()
+	var txid string
+
+	switch {
+	case ctx.IsSet("funding_txid"):
+		txid = ctx.String("funding_txid")
+	case args.Present():
+		txid = args.First()
+		args = args.Tail()
+	default:
+		return cli.ShowCommandHelp(ctx, "statelog")
+	}
+
+	txidHash, err := chainhash.NewHashFromStr(txid)
+	if err != nil {
+		return err
+	}
+	req := &lnrpc.StateLogRequest{
+		ChannelPoint: &lnrpc.ChannelPoint{
+			FundingTxid: txidHash[:],
+		},
+		Limit: uint32(ctx.Int("limit")),
+	}
+
+	switch {
+	case ctx.IsSet("output_index"):
+		req.ChannelPoint.OutputIndex = uint32(ctx.Int("output_index"))
+	case args.Present():
+		index, err := strconv.ParseInt(args.First(), 10, 32)
+		if err != nil {
+			return fmt.Errorf("unable to decode output index: %v", err)
+		}
+		req.ChannelPoint.OutputIndex = uint32(index)
+	}
+
+	resp, err := client.StateLog(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var channelStatesCommand = cli.Command{
+	Name:  "channelstates",
+	Usage: "export the state machine status of each active channel",
+	Description: "Prints out the status of the commitment state machine of " +
+		"each active channel: whether it's idle, has updates pending a " +
+		"signature, or awaits a signature or revocation from the " +
+		"peer, along with the heights of its commitment chains and " +
+		"the indexes of its update logs. With --dot, the state " +
+		"machines are instead printed as a graph in the DOT language, " +
+		"which may be rendered with graphviz.",
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name:  "dot",
+			Usage: "print the state machines as a DOT graph",
+		},
+	},
+	Action: channelStates,
+}
+
+func channelStates(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.ChannelStatesRequest{
+		Dot: ctx.Bool("dot"),
+	}
+	resp, err := client.ChannelStates(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	if req.Dot {
+		fmt.Print(resp.Dot)
+		return nil
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+// parseTimestamp parses a time given either as a unix timestamp, or in
+// RFC3339 format, returning it as a unix timestamp.
+func parseTimestamp(s string) (int64, error) {
+	if timestamp, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return timestamp, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return 0, fmt.Errorf("unable to parse time %v, expected a "+
+			"unix timestamp or RFC3339 time", s)
+	}
+
+	return t.Unix(), nil
+}
+
+var listRecommendationsCommand = cli.Command{
+	Name:  "listrecommendations",
+	Usage: "list the recommended channel rebalances, closes and opens",
+	Description: "Prints out the actions recommended by the channel " +
+		"advisor, based on the balances of the node's channels and " +
+		"their recent forwarding history. Busy channels whose balance " +
+		"rests almost entirely on one side should be rebalanced, idle " +
+		"channels may be closed, and peers whose demand exceeds the " +
+		"capacity of their channel warrant another. Close and open " +
+		"recommendations may be carried out with executerecommendation.",
+	Action: listRecommendations,
+}
+
+func listRecommendations(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.ListRecommendationsRequest{}
+
+	resp, err := client.ListRecommendations(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var executeRecommendationCommand = cli.Command{
+	Name:  "executerecommendation",
+	Usage: "carry out a recommended channel close or open",
+	Description: "Executes the recommendation with the given id, as " +
+		"listed by listrecommendations. The command returns once the " +
+		"closing or funding transaction has been broadcast.",
+	ArgsUsage: "id",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "id",
+			Usage: "the id of the recommendation to execute",
+		},
+	},
+	Action: executeRecommendation,
+}
+
+func executeRecommendation(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	var id string
+	switch {
+	case ctx.IsSet("id"):
+		id = ctx.String("id")
+	case ctx.Args().Present():
+		id = ctx.Args().First()
+	default:
+		return fmt.Errorf("recommendation id argument missing")
+	}
+
+	req := &lnrpc.ExecuteRecommendationRequest{Id: id}
+
+	resp, err := client.ExecuteRecommendation(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var subscribeOutboxCommand = cli.Command{
+	Name:  "subscribeoutbox",
+	Usage: "stream event notifications from the outbox",
+	Description: "Subscribes to the notification outbox under the given " +
+		"name, then prints out each notification of a settled " +
+		"invoice, closed channel, or detected breach, acknowledging " +
+		"each once printed. The outbox retains notifications until " +
+		"they're acknowledged, so resubscribing under the same name " +
+		"resumes where the prior subscription left off.",
+	ArgsUsage: "name",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "name",
+			Usage: "the name of the subscriber",
+		},
+		cli.BoolFlag{
+			Name: "remove",
+			Usage: "unregister the subscriber, releasing the " +
+				"notifications retained for it, instead of " +
+				"subscribing",
+		},
+	},
+	Action: subscribeOutbox,
+}
+
+func subscribeOutbox(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	var name string
+	switch {
+	case ctx.IsSet("name"):
+		name = ctx.String("name")
+	case ctx.Args().Present():
+		name = ctx.Args().First()
+	default:
+		return fmt.Errorf("subscriber name argument missing")
+	}
+
+	ctxb := context.Background()
+
+	if ctx.Bool("remove") {
+		req := &lnrpc.RemoveOutboxSubscriberRequest{
+			SubscriberName: name,
+		}
+		resp, err := client.RemoveOutboxSubscriber(ctxb, req)
+		if err != nil {
+			return err
+		}
+
+		printRespJSON(resp)
+		return nil
+	}
+
+	stream, err := client.SubscribeOutbox(ctxb, &lnrpc.OutboxSubscription{
+		SubscriberName: name,
+	})
+	if err != nil {
+		return err
+	}
+
+	for {
+		notification, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		printRespJSON(notification)
+
+		_, err = client.AckOutboxNotification(ctxb, &lnrpc.OutboxAck{
+			SubscriberName: name,
+			Id:             notification.Id,
+		})
+		if err != nil {
+			return err
+		}
+	}
+}
+
+var queryMissionControlCommand = cli.Command{
+	Name:  "querymissioncontrol",
+	Usage: "list the channels and nodes penalized by failed payments",
+	Description: "Prints out the channels and nodes which were part of " +
+		"failed payment attempts, along with the weight currently " +
+		"added to them during path finding. Penalties decay over " +
+		"time, and persist across restarts.",
+	Action: queryMissionControl,
+}
+
+func queryMissionControl(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.QueryMissionControlRequest{}
+
+	resp, err := client.QueryMissionControl(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var resetMissionControlCommand = cli.Command{
+	Name:  "resetmissioncontrol",
+	Usage: "forget the payment failures of a channel, node, or all of them",
+	Description: "Resets the penalty of the given channel or node, so " +
+		"it's no longer avoided during path finding. If neither is " +
+		"given, then all penalties are reset.",
+	Flags: []cli.Flag{
+		cli.Int64Flag{
+			Name:  "chan_id",
+			Usage: "the id of the channel to reset",
+		},
+		cli.StringFlag{
+			Name:  "pub_key",
+			Usage: "the public key or alias of the node to reset",
+		},
+	},
+	Action: resetMissionControl,
+}
+
+func resetMissionControl(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.ResetMissionControlRequest{
+		ChanId: uint64(ctx.Int64("chan_id")),
+		PubKey: ctx.String("pub_key"),
+	}
+
+	resp, err := client.ResetMissionControl(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var ackChanDivergenceCommand = cli.Command{
+	Name:  "ackchandivergence",
+	Usage: "unfreeze a channel whose state has diverged from disk",
+	Description: "Acknowledge that the in-memory state of a channel has " +
+		"diverged from its persisted state, allowing the channel to " +
+		"sign once more. Restarting the daemon, which reloads the " +
+		"channel's state from disk, is typically the safer option.",
+	ArgsUsage: "funding_txid [output_index]",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "funding_txid",
+			Usage: "the txid of the channel's funding transaction",
+		},
+		cli.IntFlag{
+			Name: "output_index",
+			Usage: "the output index for the funding output of the funding " +
+				"transaction",
+		},
+	},
+	Action: ackChanDivergence,
+}
+
+func ackChanDivergence(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	args := ctx.Args()
+	var txid string
+
+	switch {
+	case ctx.IsSet("funding_txid"):
+		txid = ctx.String("funding_txid")
+	case args.Present():
+		txid = args.First()
+		args = args.Tail()
+	default:
+		return fmt.Errorf("funding txid argument missing")
+	}
+
+	txidHash, err := chainhash.NewHashFromStr(txid)
+	if err != nil {
+		return err
+	}
+	chanPoint := &lnrpc.ChannelPoint{
+		FundingTxid: txidHash[:],
+	}
+
+	switch {
+	case ctx.IsSet("output_index"):
+		chanPoint.OutputIndex = uint32(ctx.Int("output_index"))
+	case args.Present():
+		index, err := strconv.ParseInt(args.First(), 10, 32)
+		if err != nil {
+			return fmt.Errorf("unable to decode output index: %v", err)
+		}
+		chanPoint.OutputIndex = uint32(index)
+	}
+
+	resp, err := client.AckChannelDivergence(context.Background(), chanPoint)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var freezeChannelCommand = cli.Command{
+	Name:  "freezechannel",
+	Usage: "prevent new outgoing HTLCs over a channel",
+	Description: "Freeze a channel, so no new HTLCs are offered over it, " +
+		"while those offered by the remote party are still accepted. " +
+		"The channel remains frozen across restarts until thawed, " +
+		"allowing it to be drained ahead of its closure.",
+	ArgsUsage: "funding_txid [output_index]",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "funding_txid",
+			Usage: "the txid of the channel's funding transaction",
+		},
+		cli.IntFlag{
+			Name: "output_index",
+			Usage: "the output index for the funding output of the funding " +
+				"transaction",
+		},
+	},
+	Action: freezeChannel,
+}
+
+func freezeChannel(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	chanPoint, err := parseChanPointArgs(ctx)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.FreezeChannel(context.Background(), chanPoint)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var thawChannelCommand = cli.Command{
+	Name:      "thawchannel",
+	Usage:     "allow new outgoing HTLCs over a frozen channel",
+	ArgsUsage: "funding_txid [output_index]",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "funding_txid",
+			Usage: "the txid of the channel's funding transaction",
+		},
+		cli.IntFlag{
+			Name: "output_index",
+			Usage: "the output index for the funding output of the funding " +
+				"transaction",
+		},
+	},
+	Action: thawChannel,
+}
+
+func thawChannel(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	chanPoint, err := parseChanPointArgs(ctx)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.ThawChannel(context.Background(), chanPoint)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+// parseChanPointArgs parses the channel point given by the funding_txid and
+// output_index flags, or by the positional arguments of the same name.
+func parseChanPointArgs(ctx *cli.Context) (*lnrpc.ChannelPoint, error) {
+	args := ctx.Args()
+	var txid string
+
+	switch {
+	case ctx.IsSet("funding_txid"):
+		txid = ctx.String("funding_txid")
+	case args.Present():
+		txid = args.First()
+		args = args.Tail()
+	default:
+		return nil, fmt.Errorf("funding txid argument missing")
+	}
+
+	txidHash, err := chainhash.NewHashFromStr(txid)
+	if err != nil {
+		return nil, err
+	}
+	chanPoint := &lnrpc.ChannelPoint{
+		FundingTxid: txidHash[:],
+	}
+
+	switch {
+	case ctx.IsSet("output_index"):
+		chanPoint.OutputIndex = uint32(ctx.Int("output_index"))
+	case args.Present():
+		index, err := strconv.ParseInt(args.First(), 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode output index: %v",
+				err)
+		}
+		chanPoint.OutputIndex = uint32(index)
+	}
+
+	return chanPoint, nil
+}
+
+var sendCustomMessageCommand = cli.Command{
+	Name:  "sendcustommessage",
+	Usage: "send a message of an experimental type to a peer",
+	Description: "Sends a message of a type within the experimental " +
+		"range to a connected peer. A plugin must have subscribed to " +
+		"handle the type, and the peer must advertise the feature bit " +
+		"the plugin tied the type to.",
+	ArgsUsage: "peer type data",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "peer",
+			Usage: "the public key or alias of the peer",
+		},
+		cli.Int64Flag{
+			Name:  "type",
+			Usage: "the message type, at least 32768",
+		},
+		cli.StringFlag{
+			Name:  "data",
+			Usage: "the hex encoded payload of the message",
+		},
+	},
+	Action: sendCustomMessage,
+}
+
+func sendCustomMessage(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	args := ctx.Args()
+	req := &lnrpc.SendCustomMessageRequest{}
+
+	switch {
+	case ctx.IsSet("peer"):
+		req.Peer = ctx.String("peer")
+	case args.Present():
+		req.Peer = args.First()
+		args = args.Tail()
+	default:
+		return fmt.Errorf("peer argument missing")
+	}
+
+	switch {
+	case ctx.IsSet("type"):
+		req.Type = uint32(ctx.Int64("type"))
+	case args.Present():
+		msgType, err := strconv.ParseUint(args.First(), 10, 32)
+		if err != nil {
+			return fmt.Errorf("unable to decode message type: %v",
+				err)
+		}
+		req.Type = uint32(msgType)
+		args = args.Tail()
+	default:
+		return fmt.Errorf("message type argument missing")
+	}
+
+	var dataHex string
+	switch {
+	case ctx.IsSet("data"):
+		dataHex = ctx.String("data")
+	case args.Present():
+		dataHex = args.First()
+	}
+	data, err := hex.DecodeString(dataHex)
+	if err != nil {
+		return fmt.Errorf("unable to decode data: %v", err)
+	}
+	req.Data = data
+
+	resp, err := client.SendCustomMessage(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var exportSigningAuditCommand = cli.Command{
+	Name:  "exportsigningaudit",
+	Usage: "export the audit log of signatures produced by the node",
+	Description: "Prints out each signature recorded within the signing " +
+		"audit log, along with its purpose, the output spent by the " +
+		"signed input, the key which produced it, and the hash of " +
+		"the signed data. Signatures are only recorded if lnd was " +
+		"started with --signingaudit. The events may be restricted " +
+		"to a time range, with each bound given either as a unix " +
+		"timestamp, or in RFC3339 format.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "start",
+			Usage: "only export signatures produced at or after this time",
+		},
+		cli.StringFlag{
+			Name:  "end",
+			Usage: "only export signatures produced at or before this time",
+		},
+	},
+	Action: exportSigningAudit,
+}
+
+func exportSigningAudit(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	var (
+		req = &lnrpc.SigningAuditRequest{}
+		err error
+	)
+	if ctx.IsSet("start") {
+		req.StartTime, err = parseTimestamp(ctx.String("start"))
+		if err != nil {
+			return err
+		}
+	}
+	if ctx.IsSet("end") {
+		req.EndTime, err = parseTimestamp(ctx.String("end"))
+		if err != nil {
+			return err
+		}
+	}
+
+	resp, err := client.ExportSigningAudit(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var exportChannelPoliciesCommand = cli.Command{
+	Name:  "exportchannelpolicies",
+	Usage: "export the routing policies of all channels",
+	Description: "Export the routing policy of each of the node's " +
+		"channels as a JSON document, which may be edited, kept " +
+		"under version control, and applied with " +
+		"importchannelpolicies. If no output file is specified, the " +
+		"document is printed.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "output_file",
+			Usage: "the file to write the policies to",
+		},
+	},
+	Action: exportChannelPolicies,
+}
+
+func exportChannelPolicies(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	req := &lnrpc.ExportChannelPoliciesRequest{}
+	resp, err := client.ExportChannelPolicies(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	if !ctx.IsSet("output_file") {
+		printRespJSON(resp)
+		return nil
+	}
+
+	jsonMarshaler := &jsonpb.Marshaler{
+		EmitDefaults: true,
+		Indent:       "    ",
+	}
+	jsonStr, err := jsonMarshaler.MarshalToString(resp)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(ctx.String("output_file"),
+		[]byte(jsonStr+"\n"), 0644)
+}
+
+var importChannelPoliciesCommand = cli.Command{
+	Name:      "importchannelpolicies",
+	Usage:     "apply the routing policies within a JSON document",
+	ArgsUsage: "policy-file",
+	Description: "Apply the routing policies within a JSON document, as " +
+		"produced by exportchannelpolicies, to the node's channels. " +
+		"Each changed policy is printed along with the policy it " +
+		"replaces. Channels which aren't listed keep their current " +
+		"policy. The policies are applied atomically, so if any of " +
+		"them is invalid, or refers to an unknown channel, then none " +
+		"are applied.",
+	Flags: []cli.Flag{
+		cli.BoolFlag{
+			Name: "dry_run",
+			Usage: "only print the changes which would be made, " +
+				"without applying them",
+		},
+	},
+	Action: importChannelPolicies,
+}
+
+func importChannelPolicies(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	if ctx.NArg() != 1 {
+		return fmt.Errorf("policy file argument missing")
+	}
+
+	policyFile, err := os.Open(ctx.Args().First())
+	if err != nil {
+		return err
+	}
+	defer policyFile.Close()
+
+	var policies lnrpc.ChannelPolicies
+	if err := jsonpb.Unmarshal(policyFile, &policies); err != nil {
+		return fmt.Errorf("unable to parse policy file: %v", err)
+	}
+
+	req := &lnrpc.ImportChannelPoliciesRequest{
+		Policies: policies.Policies,
+		DryRun:   ctx.Bool("dry_run"),
+	}
+	resp, err := client.ImportChannelPolicies(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var balanceHistoryCommand = cli.Command{
+	Name:  "balancehistory",
+	Usage: "list the recorded snapshots of the node's balances",
+	Description: "Prints out each snapshot of the node's balances " +
+		"recorded within the balance history: the wallet's confirmed " +
+		"and unconfirmed balances, the local and remote balances of " +
+		"open channels, our balance within channels pending open, " +
+		"and the node's total balance. Snapshots are recorded every " +
+		"--balancesnapshotinterval. The snapshots may be restricted " +
+		"to a time range, with each bound given either as a unix " +
+		"timestamp, or in RFC3339 format.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "start",
+			Usage: "only list snapshots taken at or after this time",
+		},
+		cli.StringFlag{
+			Name:  "end",
+			Usage: "only list snapshots taken at or before this time",
+		},
+	},
+	Action: balanceHistory,
+}
+
+func balanceHistory(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	var (
+		req = &lnrpc.BalanceHistoryRequest{}
+		err error
+	)
+	if ctx.IsSet("start") {
+		req.StartTime, err = parseTimestamp(ctx.String("start"))
+		if err != nil {
+			return err
+		}
+	}
+	if ctx.IsSet("end") {
+		req.EndTime, err = parseTimestamp(ctx.String("end"))
+		if err != nil {
+			return err
+		}
+	}
+
+	resp, err := client.BalanceHistory(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	printRespJSON(resp)
+	return nil
+}
+
+var exportAccountingCommand = cli.Command{
+	Name:  "exportaccounting",
+	Usage: "export the node's settled invoices, payments, forwarding fees, and on-chain fees",
+	Description: "Export the node's settled invoices, completed " +
+		"payments, the fees earned by forwards, and its confirmed " +
+		"on-chain transactions, ordered by time, as CSV or JSON for " +
+		"bookkeeping. Each entry has the same columns in both " +
+		"formats:\n\n" +
+		"   timestamp: the unix timestamp of the entry\n" +
+		"   time: the time of the entry in RFC3339 format, within the " +
+		"chosen timezone\n" +
+		"   type: one of invoice, payment, forward, or onchain\n" +
+		"   amount_sat: the change to the node's balance, excluding " +
+		"fees, which is negative for funds sent\n" +
+		"   fee_sat: the fee earned by the node if positive, or paid " +
+		"if negative\n" +
+		"   reference: the payment hash of invoices, payments, and " +
+		"forwards, or the txid of on-chain transactions\n" +
+		"   description: the memo of invoices and payments, or the " +
+		"channels of forwards\n\n" +
+		"The entries may be restricted to a time range, with each " +
+		"bound given either as a unix timestamp, an RFC3339 time, or " +
+		"a date in YYYY-MM-DD format. Dates are interpreted within " +
+		"the chosen timezone, and an end date includes the entire day.",
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "start",
+			Usage: "only export entries at or after this time",
+		},
+		cli.StringFlag{
+			Name:  "end",
+			Usage: "only export entries at or before this time",
+		},
+		cli.StringFlag{
+			Name:  "format",
+			Value: "csv",
+			Usage: "the format of the export, either csv or json",
+		},
+		cli.StringFlag{
+			Name:  "timezone",
+			Value: "UTC",
+			Usage: "the IANA timezone, such as Europe/Berlin, which " +
+				"times are shown in and dates are interpreted " +
+				"within, or Local for the system's timezone",
+		},
+		cli.StringFlag{
+			Name:  "output_file",
+			Usage: "the file to write the export to",
+		},
+	},
+	Action: exportAccounting,
+}
+
+// accountingColumns are the columns of each entry of the accounting export.
+// The columns are only ever added to, so existing spreadsheets and scripts
+// keep working.
+var accountingColumns = []string{
+	"timestamp", "time", "type", "amount_sat", "fee_sat", "reference",
+	"description",
+}
+
+// accountingRow is an entry of the accounting export in JSON format, with the
+// same fields as the columns of the CSV format.
+type accountingRow struct {
+	Timestamp   int64  `json:"timestamp"`
+	Time        string `json:"time"`
+	Type        string `json:"type"`
+	AmountSat   int64  `json:"amount_sat"`
+	FeeSat      int64  `json:"fee_sat"`
+	Reference   string `json:"reference"`
+	Description string `json:"description"`
+}
+
+// parseAccountingTime parses a bound of the accounting export's time range,
+// given either as a unix timestamp, an RFC3339 time, or a date within the
+// passed timezone. If endOfDay is set, then a date refers to the final second
+// of the day rather than the first.
+func parseAccountingTime(s string, loc *time.Location,
+	endOfDay bool) (int64, error) {
+
+	date, err := time.ParseInLocation("2006-01-02", s, loc)
+	if err != nil {
+		return parseTimestamp(s)
+	}
+	if endOfDay {
+		date = date.AddDate(0, 0, 1).Add(-time.Second)
+	}
+
+	return date.Unix(), nil
+}
+
+func exportAccounting(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	format := ctx.String("format")
+	if format != "csv" && format != "json" {
+		return fmt.Errorf("unknown format %v, expected csv or json",
+			format)
+	}
+	loc, err := time.LoadLocation(ctx.String("timezone"))
+	if err != nil {
+		return fmt.Errorf("unknown timezone %v: %v",
+			ctx.String("timezone"), err)
+	}
+
+	req := &lnrpc.AccountingRequest{}
+	if ctx.IsSet("start") {
+		req.StartTime, err = parseAccountingTime(ctx.String("start"),
+			loc, false)
+		if err != nil {
+			return err
+		}
+	}
+	if ctx.IsSet("end") {
+		req.EndTime, err = parseAccountingTime(ctx.String("end"), loc,
+			true)
+		if err != nil {
+			return err
+		}
+	}
+
+	resp, err := client.ExportAccounting(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	rows := make([]accountingRow, 0, len(resp.Entries))
+	for _, entry := range resp.Entries {
+		rows = append(rows, accountingRow{
+			Timestamp: entry.Timestamp,
+			Time: time.Unix(entry.Timestamp, 0).In(loc).Format(
+				time.RFC3339),
+			Type:        entry.Type,
+			AmountSat:   entry.Amount,
+			FeeSat:      entry.Fee,
+			Reference:   entry.Reference,
+			Description: entry.Description,
+		})
+	}
+
+	var out bytes.Buffer
+	switch format {
+	case "csv":
+		w := csv.NewWriter(&out)
+		if err := w.Write(accountingColumns); err != nil {
+			return err
+		}
+		for _, row := range rows {
+			err := w.Write([]string{
+				strconv.FormatInt(row.Timestamp, 10),
+				row.Time,
+				row.Type,
+				strconv.FormatInt(row.AmountSat, 10),
+				strconv.FormatInt(row.FeeSat, 10),
+				row.Reference,
+				row.Description,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return err
+		}
+
+	case "json":
+		b, err := json.MarshalIndent(rows, "", "    ")
+		if err != nil {
+			return err
+		}
+		out.Write(b)
+		out.WriteString("\n")
+	}
+
+	if !ctx.IsSet("output_file") {
+		_, err := out.WriteTo(os.Stdout)
 		return err
 	}
 
-	printRespJSON(chanInfo)
-	return nil
+	return ioutil.WriteFile(ctx.String("output_file"), out.Bytes(), 0644)
 }
 
-var getNodeInfoCommand = cli.Command{
-	Name:  "getnodeinfo",
-	Usage: "Get information on a specific node.",
-	Description: "prints out the latest authenticated node state for an " +
-		"advertised node",
+var listUnresolvedHTLCsCommand = cli.Command{
+	Name:  "listunresolvedhtlcs",
+	Usage: "list the HTLCs within active channels which have yet to be resolved",
+	Description: "Prints out each HTLC within our active channels which " +
+		"has yet to be settled or failed, along with its age, " +
+		"direction, amount, expiration height, the memo of the " +
+		"invoice it pays, if any, and the subsystem currently " +
+		"responsible for its resolution:\n\n" +
+		"   link: the HTLC's addition or removal awaits a state " +
+		"update with the peer\n" +
+		"   remote_peer: the peer has yet to settle or fail our " +
+		"outgoing HTLC\n" +
+		"   switch: the HTLC has been forwarded, and awaits the " +
+		"resolution of the outgoing HTLC\n" +
+		"   invoice_registry: the HTLC pays part of one of our " +
+		"invoices, and awaits the remainder of the payment\n" +
+		"   async_holder: the HTLC is held for an offline recipient\n\n" +
+		"The age of HTLCs added before the daemon last started is " +
+		"measured from its start up.",
 	Flags: []cli.Flag{
-		cli.StringFlag{
-			Name: "pub_key",
-			Usage: "the 33-byte hex-encoded compressed public of the target " +
-				"node",
+		cli.Int64Flag{
+			Name: "min_age",
+			Usage: "only list HTLCs which have been pending for at " +
+				"least this many seconds",
 		},
 	},
-	Action: getNodeInfo,
+	Action: listUnresolvedHTLCs,
 }
 
-func getNodeInfo(ctx *cli.Context) error {
-	ctxb := context.Background()
+func listUnresolvedHTLCs(ctx *cli.Context) error {
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
-	if !ctx.IsSet("pub_key") {
-		return fmt.Errorf("pub_key argument missing")
-	}
-
-	req := &lnrpc.NodeInfoRequest{
-		PubKey: ctx.String("pub_key"),
+	req := &lnrpc.ListUnresolvedHTLCsRequest{
+		MinAge: ctx.Int64("min_age"),
 	}
-
-	nodeInfo, err := client.GetNodeInfo(ctxb, req)
+	resp, err := client.ListUnresolvedHTLCs(context.Background(), req)
 	if err != nil {
 		return err
 	}
 
-	printRespJSON(nodeInfo)
+	printRespJSON(resp)
 	return nil
 }
 
-var queryRouteCommand = cli.Command{
-	Name:        "queryroute",
-	Usage:       "Query a route to a destination.",
-	Description: "Queries the channel router for a potential path to the destination that has sufficient flow for the amount including fees",
-	ArgsUsage:   "dest amt",
+var updateCommitFeeCommand = cli.Command{
+	Name:  "updatecommitfee",
+	Usage: "propose a new commitment fee for a channel we initiated",
+	Description: "Propose a new absolute fee, in satoshis, for the " +
+		"commitment transactions of a channel. The fee is paid by the " +
+		"initiator of the channel, so only channels opened by this " +
+		"node may have their fee updated.",
+	ArgsUsage: "funding_txid output_index fee",
 	Flags: []cli.Flag{
 		cli.StringFlag{
-			Name: "dest",
-			Usage: "the 33-byte hex-encoded public key for the payment " +
-				"destination",
+			Name:  "funding_txid",
+			Usage: "the txid of the channel's funding transaction",
+		},
+		cli.IntFlag{
+			Name: "output_index",
+			Usage: "the output index for the funding output of the funding " +
+				"transaction",
 		},
 		cli.Int64Flag{
-			Name:  "amt",
-			Usage: "the amount to send expressed in satoshis",
+			Name:  "fee",
+			Usage: "the new fee of the commitment transactions in satoshis",
 		},
 	},
-	Action: queryRoute,
+	Action: updateCommitFee,
 }
 
-func queryRoute(ctx *cli.Context) error {
-	ctxb := context.Background()
+func updateCommitFee(ctx *cli.Context) error {
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
-	var (
-		dest string
-		amt  int64
-		err  error
-	)
-
 	args := ctx.Args()
+	var txid string
 
 	switch {
-	case ctx.IsSet("dest"):
-		dest = ctx.String("dest")
+	case ctx.IsSet("funding_txid"):
+		txid = ctx.String("funding_txid")
 	case args.Present():
-		dest = args.First()
+		txid = args.First()
 		args = args.Tail()
 	default:
-		return fmt.Errorf("dest argument missing")
+		return fmt.Errorf("funding txid argument missing")
+	}
+
+	txidHash, err := chainhash.NewHashFromStr(txid)
+	if err != nil {
+		return err
+	}
+	req := &lnrpc.UpdateCommitFeeRequest{
+		ChannelPoint: &lnrpc.ChannelPoint{
+			FundingTxid: txidHash[:],
+		},
 	}
 
 	switch {
-	case ctx.IsSet("amt"):
-		amt = ctx.Int64("amt")
+	case ctx.IsSet("output_index"):
+		req.ChannelPoint.OutputIndex = uint32(ctx.Int("output_index"))
 	case args.Present():
-		amt, err = strconv.ParseInt(args.First(), 10, 64)
+		index, err := strconv.ParseInt(args.First(), 10, 32)
 		if err != nil {
-			return fmt.Errorf("unable to decode amt argument: %v", err)
+			return fmt.Errorf("unable to decode output index: %v", err)
 		}
+		req.ChannelPoint.OutputIndex = uint32(index)
+		args = args.Tail()
 	default:
-		return fmt.Errorf("amt argument missing")
+		return fmt.Errorf("output index argument missing")
 	}
 
-	req := &lnrpc.RouteRequest{
-		PubKey: dest,
-		Amt:    amt,
+	switch {
+	case ctx.IsSet("fee"):
+		req.Fee = ctx.Int64("fee")
+	case args.Present():
+		req.Fee, err = strconv.ParseInt(args.First(), 10, 64)
+		if err != nil {
+			return fmt.Errorf("unable to decode fee: %v", err)
+		}
+	default:
+		return fmt.Errorf("fee argument missing")
 	}
 
-	route, err := client.QueryRoute(ctxb, req)
+	resp, err := client.UpdateCommitFee(context.Background(), req)
 	if err != nil {
 		return err
 	}
 
-	printRespJSON(route)
+	printRespJSON(resp)
 	return nil
 }
 
-var getNetworkInfoCommand = cli.Command{
-	Name:  "getnetworkinfo",
-	Usage: "getnetworkinfo",
-	Description: "returns a set of statistics pertaining to the known channel " +
-		"graph",
-	Action: getNetworkInfo,
+var sendBatchPaymentCommand = cli.Command{
+	Name:  "sendbatchpayment",
+	Usage: "pay several payment requests in a single call",
+	Description: "Pay each of the given payment requests, printing the " +
+		"result of each payment as it completes. The payments share " +
+		"a total fee budget and time lock limit, and at most " +
+		"max_concurrency of them are in flight at once.",
+	ArgsUsage: "pay_req [pay_req...]",
+	Flags: []cli.Flag{
+		cli.Int64Flag{
+			Name: "fee_budget",
+			Usage: "the maximum total fee of all payments in " +
+				"satoshis, 0 for no limit",
+		},
+		cli.IntFlag{
+			Name: "cltv_limit",
+			Usage: "the maximum total time lock of each payment's " +
+				"route, 0 for no limit",
+		},
+		cli.IntFlag{
+			Name: "max_concurrency",
+			Usage: "the maximum number of payments in flight at " +
+				"once, 0 for the default",
+		},
+		cli.StringFlag{
+			Name: "fee_preset",
+			Usage: "the name of the fee preset whose limits apply to " +
+				"each payment",
+		},
+	},
+	Action: sendBatchPayment,
 }
 
-func getNetworkInfo(ctx *cli.Context) error {
-	ctxb := context.Background()
+func sendBatchPayment(ctx *cli.Context) error {
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
-	req := &lnrpc.NetworkInfoRequest{}
+	if !ctx.Args().Present() {
+		return fmt.Errorf("payment request arguments missing")
+	}
 
-	netInfo, err := client.GetNetworkInfo(ctxb, req)
+	req := &lnrpc.BatchPaymentRequest{
+		PaymentRequests: ctx.Args(),
+		FeeBudget:       ctx.Int64("fee_budget"),
+		CltvLimit:       uint32(ctx.Int("cltv_limit")),
+		MaxConcurrency:  uint32(ctx.Int("max_concurrency")),
+		FeePreset:       ctx.String("fee_preset"),
+	}
+
+	stream, err := client.SendBatchPayment(context.Background(), req)
 	if err != nil {
 		return err
 	}
 
-	printRespJSON(netInfo)
-	return nil
+	for {
+		result, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		printRespJSON(result)
+	}
 }
 
-var debugLevelCommand = cli.Command{
-	Name:        "debuglevel",
-	Usage:       "Set the debug level.",
-	Description: "Logging level for all subsystems {trace, debug, info, warn, error, critical} -- You may also specify <subsystem>=<level>,<subsystem2>=<level>,... to set the log level for individual subsystems -- Use show to list available subsystems",
+var exportGossipCommand = cli.Command{
+	Name:  "exportgossip",
+	Usage: "export the messages within the gossip store",
+	Description: "Export the gossip messages the node has received and " +
+		"accepted, which are kept for --gossipstoreage, as a JSON " +
+		"document. The document may be replayed into another node on " +
+		"the same network with replaygossip. The messages may be " +
+		"restricted to those received since a time given either as a " +
+		"unix timestamp, or in RFC3339 format. If no output file is " +
+		"specified, the document is printed.",
 	Flags: []cli.Flag{
-		cli.BoolFlag{
-			Name:  "show",
-			Usage: "if true, then the list of available sub-systems will be printed out",
+		cli.StringFlag{
+			Name:  "since",
+			Usage: "only export messages received at or after this time",
 		},
 		cli.StringFlag{
-			Name:  "level",
-			Usage: "the level specification to target either a coarse logging level, or granular set of specific sub-systems with loggin levels for each",
+			Name:  "output_file",
+			Usage: "the file to write the messages to",
 		},
 	},
-	Action: debugLevel,
+	Action: exportGossip,
 }
 
-func debugLevel(ctx *cli.Context) error {
-	ctxb := context.Background()
+func exportGossip(ctx *cli.Context) error {
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
-	req := &lnrpc.DebugLevelRequest{
-		Show:      ctx.Bool("show"),
-		LevelSpec: ctx.String("level"),
+	var (
+		req = &lnrpc.ExportGossipRequest{}
+		err error
+	)
+	if ctx.IsSet("since") {
+		req.Since, err = parseTimestamp(ctx.String("since"))
+		if err != nil {
+			return err
+		}
 	}
 
-	resp, err := client.DebugLevel(ctxb, req)
+	resp, err := client.ExportGossip(context.Background(), req)
+	if err != nil {
+		return err
+	}
+
+	if !ctx.IsSet("output_file") {
+		printRespJSON(resp)
+		return nil
+	}
+
+	jsonMarshaler := &jsonpb.Marshaler{
+		EmitDefaults: true,
+		Indent:       "    ",
+	}
+	jsonStr, err := jsonMarshaler.MarshalToString(resp)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(ctx.String("output_file"),
+		[]byte(jsonStr+"\n"), 0644)
+}
+
+var replayGossipCommand = cli.Command{
+	Name:      "replaygossip",
+	Usage:     "replay exported gossip messages into the node",
+	ArgsUsage: "gossip-file",
+	Description: "Feed the gossip messages within a JSON document, as " +
+		"produced by exportgossip, into the node as if they had just " +
+		"been received from their original peers. This is intended " +
+		"for reproducing the gossip observed by another node within " +
+		"a test node. If any of the messages can't be decoded, then " +
+		"none are replayed.",
+	Action: replayGossip,
+}
+
+func replayGossip(ctx *cli.Context) error {
+	client, cleanUp := getClient(ctx)
+	defer cleanUp()
+
+	if ctx.NArg() != 1 {
+		return fmt.Errorf("gossip file argument missing")
+	}
+
+	gossipFile, err := os.Open(ctx.Args().First())
+	if err != nil {
+		return err
+	}
+	defer gossipFile.Close()
+
+	var msgs lnrpc.GossipMessages
+	if err := jsonpb.Unmarshal(gossipFile, &msgs); err != nil {
+		return fmt.Errorf("unable to parse gossip file: %v", err)
+	}
+
+	resp, err := client.ReplayGossip(context.Background(), &msgs)
 	if err != nil {
 		return err
 	}
@@ -1451,65 +4469,101 @@ func debugLevel(ctx *cli.Context) error {
 	return nil
 }
 
-var decodePayReqComamnd = cli.Command{
-	Name:        "decodepayreq",
-	Usage:       "Decode a payment request.",
-	Description: "Decode the passed payment request revealing the destination, payment hash and value of the payment request",
-	ArgsUsage:   "pay_req",
+var spliceInCommand = cli.Command{
+	Name:  "splicein",
+	Usage: "splice additional funds from the wallet into a channel",
+	Description: "Increase the capacity of an active channel by splicing " +
+		"funds from the wallet into it, without closing the channel. " +
+		"The spliced funds are credited to this node's balance. The " +
+		"channel is unable to forward payments until the splice " +
+		"transaction confirms, and continues to use its original " +
+		"channel point as its identifier afterwards.",
+	ArgsUsage: "funding_txid output_index amt",
 	Flags: []cli.Flag{
 		cli.StringFlag{
-			Name:  "pay_req",
-			Usage: "the zpay32 encoded payment request",
+			Name:  "funding_txid",
+			Usage: "the txid of the channel's funding transaction",
+		},
+		cli.IntFlag{
+			Name: "output_index",
+			Usage: "the output index for the funding output of the funding " +
+				"transaction",
+		},
+		cli.Int64Flag{
+			Name:  "amt",
+			Usage: "the number of satoshis to splice into the channel",
 		},
 	},
-	Action: decodePayReq,
+	Action: spliceIn,
 }
 
-func decodePayReq(ctx *cli.Context) error {
-	ctxb := context.Background()
+func spliceIn(ctx *cli.Context) error {
 	client, cleanUp := getClient(ctx)
 	defer cleanUp()
 
-	var payreq string
+	args := ctx.Args()
+	var txid string
 
 	switch {
-	case ctx.IsSet("pay_req"):
-		payreq = ctx.String("pay_req")
-	case ctx.Args().Present():
-		payreq = ctx.Args().First()
+	case ctx.IsSet("funding_txid"):
+		txid = ctx.String("funding_txid")
+	case args.Present():
+		txid = args.First()
+		args = args.Tail()
 	default:
-		return fmt.Errorf("pay_req argument missing")
+		return fmt.Errorf("funding txid argument missing")
 	}
 
-	resp, err := client.DecodePayReq(ctxb, &lnrpc.PayReqString{
-		PayReq: payreq,
-	})
+	txidHash, err := chainhash.NewHashFromStr(txid)
 	if err != nil {
 		return err
 	}
+	req := &lnrpc.SpliceInRequest{
+		ChannelPoint: &lnrpc.ChannelPoint{
+			FundingTxid: txidHash[:],
+		},
+	}
 
-	printRespJSON(resp)
-	return nil
-}
-
-var listChainTxnsCommand = cli.Command{
-	Name:        "listchaintxns",
-	Usage:       "List transactions from the wallet.",
-	Description: "List all transactions an address of the wallet was involved in.",
-	Action:      listChainTxns,
-}
+	switch {
+	case ctx.IsSet("output_index"):
+		req.ChannelPoint.OutputIndex = uint32(ctx.Int("output_index"))
+	case args.Present():
+		index, err := strconv.ParseInt(args.First(), 10, 32)
+		if err != nil {
+			return fmt.Errorf("unable to decode output index: %v", err)
+		}
+		req.ChannelPoint.OutputIndex = uint32(index)
+		args = args.Tail()
+	default:
+		return fmt.Errorf("output index argument missing")
+	}
 
-func listChainTxns(ctx *cli.Context) error {
-	ctxb := context.Background()
-	client, cleanUp := getClient(ctx)
-	defer cleanUp()
+	switch {
+	case ctx.IsSet("amt"):
+		req.Amount = ctx.Int64("amt")
+	case args.Present():
+		req.Amount, err = strconv.ParseInt(args.First(), 10, 64)
+		if err != nil {
+			return fmt.Errorf("unable to decode amt: %v", err)
+		}
+	default:
+		return fmt.Errorf("amt argument missing")
+	}
 
-	resp, err := client.GetTransactions(ctxb, &lnrpc.GetTransactionsRequest{})
+	resp, err := client.SpliceIn(context.Background(), req)
+	if err != nil {
+		return err
+	}
 
+	spliceTxid, err := chainhash.NewHash(resp.SpliceTxid)
 	if err != nil {
 		return err
 	}
 
-	printRespJSON(resp)
+	printJSON(struct {
+		SpliceTxid string `json:"splice_txid"`
+	}{
+		SpliceTxid: spliceTxid.String(),
+	})
 	return nil
 }
@@ -2,10 +2,13 @@ package main
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
+	"strings"
 
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/urfave/cli"
+	"golang.org/x/net/context"
 
 	"google.golang.org/grpc"
 )
@@ -32,6 +35,17 @@ func getClientConn(ctx *cli.Context) *grpc.ClientConn {
 	// * https://github.com/go-macaroon/macaroon
 	opts := []grpc.DialOption{grpc.WithInsecure()}
 
+	// If a credential was specified, then its token is attached to each
+	// call, subjecting the calls to the credential's quotas.
+	if tokenPath := ctx.GlobalString("credential"); tokenPath != "" {
+		token, err := ioutil.ReadFile(tokenPath)
+		if err != nil {
+			fatal(fmt.Errorf("unable to read credential: %v", err))
+		}
+		opts = append(opts, grpc.WithPerRPCCredentials(
+			credentialToken(strings.TrimSpace(string(token)))))
+	}
+
 	conn, err := grpc.Dial(ctx.GlobalString("rpcserver"), opts...)
 	if err != nil {
 		fatal(err)
@@ -40,6 +54,27 @@ func getClientConn(ctx *cli.Context) *grpc.ClientConn {
 	return conn
 }
 
+// credentialToken attaches the hex encoded token of an lnd credential to each
+// call.
+type credentialToken string
+
+// GetRequestMetadata returns the metadata the token is presented within.
+//
+// NOTE: Part of the credentials.PerRPCCredentials interface.
+func (c credentialToken) GetRequestMetadata(ctx context.Context,
+	uri ...string) (map[string]string, error) {
+
+	return map[string]string{"credential": string(c)}, nil
+}
+
+// RequireTransportSecurity returns false, as the connection to lnd isn't
+// encrypted.
+//
+// NOTE: Part of the credentials.PerRPCCredentials interface.
+func (c credentialToken) RequireTransportSecurity() bool {
+	return false
+}
+
 func main() {
 	app := cli.NewApp()
 	app.Name = "lncli"
@@ -51,6 +86,10 @@ func main() {
 			Value: "localhost:10009",
 			Usage: "host:port of ln daemon",
 		},
+		cli.StringFlag{
+			Name:  "credential",
+			Usage: "path to the token file of the credential to make calls with",
+		},
 	}
 	app.Commands = []cli.Command{
 		newAddressCommand,
@@ -58,7 +97,18 @@ func main() {
 		sendCoinsCommand,
 		connectCommand,
 		openChannelCommand,
+		estimateOpenChannelCommand,
+		fundingPolicyCommand,
+		updateFundingPolicyCommand,
+		peerSettingsCommand,
+		updatePeerSettingsCommand,
+		deletePeerSettingsCommand,
 		closeChannelCommand,
+		abandonChannelCommand,
+		rotateIdentityCommand,
+		confirmIdentityRotationCommand,
+		mineBlocksCommand,
+		advanceTimeCommand,
 		listPeersCommand,
 		walletBalanceCommand,
 		channelBalanceCommand,
@@ -68,16 +118,58 @@ func main() {
 		addInvoiceCommand,
 		lookupInvoiceCommand,
 		listInvoicesCommand,
+		addOfferCommand,
+		listOffersCommand,
+		requestOfferInvoiceCommand,
 		listChannelsCommand,
+		exportChanBackupCommand,
+		verifyChanBackupCommand,
+		restoreChanBackupCommand,
+		backupUploadStatusCommand,
+		importChanRecoveryCommand,
 		listPaymentsCommand,
+		listPaymentAttemptsCommand,
+		paymentTelemetryCommand,
+		towerInfoCommand,
+		revenueReportCommand,
+		topCounterpartiesCommand,
+		deleteAllPaymentsCommand,
+		feePresetsCommand,
 		describeGraphCommand,
 		getChanInfoCommand,
 		getNodeInfoCommand,
 		queryRouteCommand,
 		getNetworkInfoCommand,
 		debugLevelCommand,
+		dbCommitStatsCommand,
+		exportChanStateCommand,
+		importChanStateCommand,
+		chanHistoryCommand,
+		stateLogCommand,
+		channelStatesCommand,
+		createPayReqCommand,
 		decodePayReqComamnd,
 		listChainTxnsCommand,
+		listRecommendationsCommand,
+		executeRecommendationCommand,
+		subscribeOutboxCommand,
+		queryMissionControlCommand,
+		resetMissionControlCommand,
+		ackChanDivergenceCommand,
+		freezeChannelCommand,
+		thawChannelCommand,
+		sendCustomMessageCommand,
+		exportSigningAuditCommand,
+		exportChannelPoliciesCommand,
+		importChannelPoliciesCommand,
+		balanceHistoryCommand,
+		exportAccountingCommand,
+		listUnresolvedHTLCsCommand,
+		updateCommitFeeCommand,
+		sendBatchPaymentCommand,
+		exportGossipCommand,
+		replayGossipCommand,
+		spliceInCommand,
 	}
 
 	if err := app.Run(os.Args); err != nil {
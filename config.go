@@ -8,10 +8,14 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	flags "github.com/btcsuite/go-flags"
 	"github.com/lightningnetwork/lnd/brontide"
+	"github.com/lightningnetwork/lnd/hwsigner"
+	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/routing"
 	"github.com/roasbeef/btcd/btcec"
 	"github.com/roasbeef/btcutil"
 )
@@ -22,6 +26,7 @@ const (
 	defaultLogLevel           = "info"
 	defaultLogDirname         = "logs"
 	defaultLogFilename        = "lnd.log"
+	defaultBackupFilename     = "channel.backup"
 	defaultRPCPort            = 10009
 	defaultSPVMode            = false
 	defaultPeerPort           = 10011
@@ -30,6 +35,89 @@ const (
 	defaultRPCPass            = "passwd"
 	defaultSPVHostAdr         = "localhost:18333"
 	defaultMaxPendingChannels = 1
+	defaultMinHTLC            = 1
+	defaultMaxDustExposure    = 500000
+	defaultMaxHashExposure    = 100000
+	defaultChanReserve        = 10000
+	defaultLowFreeSpace       = 100
+	defaultCloseBumpBlocks    = 6
+	defaultSweepBumpBlocks    = 6
+	defaultSpliceConfs        = 6
+	defaultChanHistoryThresh  = 10000
+	defaultNumGraphSyncPeers  = 3
+	defaultBlockCacheSize     = 20 * 1024 * 1024
+	defaultMiddlewareTimeout  = 2 * time.Second
+	defaultAdvisorLookback    = 7 * 24 * time.Hour
+	defaultAdvisorInterval    = time.Hour
+	defaultBalanceSnapshots   = time.Hour
+	defaultInvoiceArchiving   = time.Hour
+	defaultPathFindingTimeout = 10 * time.Second
+	defaultGossipStoreAge     = 24 * time.Hour
+	defaultGossipStoreSize    = 100000
+	defaultIdentityRotation   = 7 * 24 * time.Hour
+
+	defaultConsolidationMaxFeeRate     = 5
+	defaultConsolidationConfTarget     = 12
+	defaultConsolidationMaxOutputValue = 100000
+	defaultConsolidationMinOutputs     = 5
+	defaultConsolidationMaxOutputs     = 50
+
+	defaultAsyncHoldTimeout = time.Hour
+	defaultAsyncMaxHeld     = 20
+
+	// The default CSV delay policy scales the delay of new channels from a
+	// day for the smallest to a week for those at the largest capacity
+	// permitted by the protocol. The delays proposed by peers aren't
+	// scaled, so the shortest we accept matches the fixed delay proposed
+	// by peers which predate the scaling.
+	defaultCsvDelayMin          = 144
+	defaultCsvDelayMax          = 1008
+	defaultCsvDelayFullCapacity = 16777215
+	defaultCsvDelayMinRemote    = 4
+
+	// By default, half of each channel's HTLC slots and liquidity are
+	// reserved for endorsed HTLCs from upstream peers which have earned
+	// us some fees within the past few weeks.
+	defaultJammingProtectedSlots     = 0.5
+	defaultJammingProtectedLiquidity = 0.5
+	defaultJammingMinReputation      = 100
+	defaultJammingReputationHalfLife = 14 * 24 * time.Hour
+	defaultJammingResolutionPeriod   = 90 * time.Second
+
+	defaultSimulationBlockInterval = 10 * time.Minute
+
+	// By default, payments which don't select a fee preset are attempted
+	// up to three times, with a growing delay between attempts.
+	defaultPaymentRetryMaxAttempts = 3
+	defaultPaymentRetryBackoff     = time.Second
+	defaultPaymentRetryMaxBackoff  = 30 * time.Second
+
+	// By default, a watchtower holds up to 100MB of justice transactions,
+	// and is paid 1% of the funds it sweeps.
+	defaultWatchtowerMaxSessionsPerClient = 10
+	defaultWatchtowerMaxUpdatesPerSession = 1024
+	defaultWatchtowerMaxStorage           = 100 * 1024 * 1024
+	defaultWatchtowerMaxStoragePerClient  = 10 * 1024 * 1024
+	defaultWatchtowerRewardRate           = 10000
+
+	// By default, no transaction we broadcast may pay more than 0.005 BTC
+	// in fees, or more than ten times the fee rate estimated to confirm
+	// within six blocks.
+	defaultFeeCeilingMaxFee          = 500000
+	defaultFeeCeilingMaxRateMultiple = 10
+	defaultFeeCeilingConfTarget      = 6
+
+	// By default, the final hop of our payments is padded by up to
+	// three blocks, and an amount may be probed three times within ten
+	// minutes before further probes of it are masked.
+	defaultProbingFinalCltvPadding   = 3
+	defaultProbingMaxIdenticalProbes = 3
+	defaultProbingProbeWindow        = 10 * time.Minute
+
+	// By default, payment requests hint at the three unannounced channels
+	// with the most inbound capacity.
+	defaultRouteHintsStrategy = hintStrategyInbound
+	defaultRouteHintsMaxHints = 3
 )
 
 var (
@@ -53,6 +141,13 @@ type config struct {
 	DataDir    string `short:"b" long:"datadir" description:"The directory to store lnd's data within"`
 	LogDir     string `long:"logdir" description:"Directory to log output."`
 
+	ChanDBDir    string `long:"chandbdir" description:"The directory to store the channel database within. Namespaced per network like the data directory. Defaults to the data directory"`
+	Standby      bool   `long:"standby" description:"If the channel database is in use by another daemon, wait in standby for it to be released, then take over, rather than exiting. The daemon which held it previously is fenced off from signing for our channels"`
+	WalletDir    string `long:"walletdir" description:"The directory to store the wallet within. Defaults to the lnwallet directory within the data directory"`
+	BackupFile   string `long:"backupfile" description:"The path of the encrypted static backup of all channels, which is updated each time a channel is opened or closed. Defaults to channel.backup within the channel database directory"`
+	MinFreeSpace uint64 `long:"minfreespace" description:"The minimum free space in megabytes required on the volume of each data and log directory at startup. Set to 0 to disable the check"`
+	LowFreeSpace uint64 `long:"lowfreespace" description:"The free space in megabytes on the volume of the channel database below which non-critical writes, such as gossip and the forwarding log, are rejected and the operator is alerted, reserving the remaining space for writes which are vital to the safety of channels. Set to 0 to disable the check"`
+
 	Listeners   []string `long:"listen" description:"Add an interface/port to listen for connections (default all interfaces port: 10011)"`
 	ExternalIPs []string `long:"externalip" description:"Add an ip to the list of local addresses we claim to listen on to peers"`
 
@@ -69,8 +164,239 @@ type config struct {
 	RawRPCCert         string `long:"rawrpccert" description:"The raw bytes of btcd's PEM-encoded certificate chain which will be used to authenticate the RPC connection."`
 	TestNet3           bool   `long:"testnet" description:"Use the test network"`
 	SimNet             bool   `long:"simnet" description:"Use the simulation test network"`
+	SigNet             bool   `long:"signet" description:"Use the default public signet"`
+	CustomNet          bool   `long:"customnet" description:"Use the custom network defined by the customnet options"`
 	DebugHTLC          bool   `long:"debughtlc" description:"Activate the debug htlc mode. With the debug HTLC mode, all payments sent use a pre-determined R-Hash. Additionally, all HTLCs sent to a node with the debug HTLC R-Hash are immediately settled in the next available state transition."`
 	MaxPendingChannels int    `long:"maxpendingchannels" description:"The maximum number of incoming pending channels permitted per peer."`
+	MinHTLC            int64  `long:"minhtlc" description:"The smallest HTLC in satoshis that will be accepted or forwarded."`
+	MaxDustExposure    int64  `long:"maxdustexposure" description:"The maximum total value in satoshis of HTLCs below the dust limit which may be pending within a single channel. Dust HTLCs are trimmed from the commitment transaction, so their value is lost to fees on force close. A value of zero disables the limit."`
+	MaxHashExposure    int64  `long:"maxhashexposure" description:"The maximum total value in satoshis of HTLCs sharing a single payment hash which may be pending within a single channel. The first HTLC with a payment hash isn't subject to the limit, only further HTLCs correlated with it, as seen in probing and looping attacks. A value of zero disables the limit."`
+	ChanReserve        int64  `long:"chanreserve" description:"The minimum balance in satoshis each side of a channel must maintain. HTLCs which would push the balance of the party offering them below the reserve are rejected, so a party always has funds at stake should they broadcast a revoked state. A value of zero disables the reserve."`
+	MaxDualFundingAmt  int64  `long:"maxdualfundingamt" description:"The largest amount in satoshis we'll contribute to a channel a remote peer proposes we both fund. Requests for a larger contribution are declined. A value of zero declines all such requests."`
+	CloseBumpBlocks    uint32 `long:"closebumpblocks" description:"The number of blocks a cooperative closure transaction may remain unconfirmed before we double the fee of our own version of it, which is paid from our output, and have the remote peer sign the replacement. A value of zero disables fee bumping."`
+	SweepBumpBlocks    uint32 `long:"sweepbumpblocks" description:"The number of blocks a transaction sweeping the time-locked outputs of force closed channels into the wallet may remain unconfirmed before it's replaced by a version paying double the fee. The fee is never bumped beyond half of the swept funds. A value of zero disables fee bumping."`
+	SpliceConfs        uint32 `long:"spliceconfs" description:"The number of confirmations a splice transaction adding funds to a channel requires before the channel is migrated to the funding output it creates. Until then, the channel accepts no updates, as a re-org could still remove the splice transaction from the chain."`
+	ChanHistoryThresh  int64  `long:"chanhistorythreshold" description:"The smallest change in satoshis of a channel's local balance since its balance was last recorded which is recorded as a new event within the channel's timeline."`
+	NumGraphSyncPeers  int    `long:"numgraphsyncpeers" description:"The number of connected peers the channel graph is actively synchronized with. New announcements are only exchanged with these peers, with the best connected peers within the graph being preferred, and peers which fall behind being rotated out. A value of zero synchronizes the graph with every connected peer."`
+	BlockCacheSize     int64  `long:"blockcachesize" description:"The maximum size in bytes of the cache of blocks and transactions fetched from the chain backend, which serves repeated historical lookups from memory. A value of zero disables the cache."`
+	AnalyticsDB        string `long:"analyticsdb" description:"Path to an optional SQLite database which invoices, payments, and forwarding events are mirrored into for reporting. If unset, the analytics store is disabled."`
+
+	PathFindingTimeout time.Duration `long:"pathfindingtimeout" description:"The maximum time spent computing a single route. Once exceeded, the best route found so far is used, or the payment fails if none has been found. A value of zero leaves route computation unbounded."`
+
+	GossipStoreAge  time.Duration `long:"gossipstoreage" description:"How long the gossip messages we receive and accept are kept within the database. Stored messages allow our peers to be sent the original signed announcements when synchronizing the channel graph, and may be exported with the exportgossip command to be replayed into another node. A value of zero disables the gossip store."`
+	GossipStoreSize int           `long:"gossipstoresize" description:"The maximum number of messages kept within the gossip store, beyond which the oldest are removed. A value of zero leaves the number of messages bounded only by their age."`
+
+	PreferReliablePeers bool `long:"preferreliablepeers" description:"Favor routes whose first hop is one of our more reliable peers, as measured by their uptime, latency, and the time they take to resolve HTLCs. The quality of each peer is shown by listpeers."`
+
+	PaymentTelemetry bool `long:"paymenttelemetry" description:"Aggregate the outcomes of the payments we send, namely their success rate, latency percentiles, and most common failure codes, which may be viewed with the paymenttelemetry command, and are published as the paymenttelemetry variable at /debug/vars when HTTP profiling is enabled. Only anonymized outcomes are retained, without the hash, amount, destination, or route of any payment."`
+
+	SigningAudit bool `long:"signingaudit" description:"Record every signature produced by the node, such as those over commitment, closure, and sweep transactions, within an append-only audit log in the database, which may be exported for compliance review with the exportsigningaudit command. A signature is only used once it has been recorded."`
+
+	AllowKeyReuse bool `long:"allowkeyreuse" description:"Start even if the audit of the key material of our channels made at startup finds a key or revocation root reused across channels, or a key derived from a trivially weak secret, logging the findings rather than refusing to start. Such findings point to a key derivation bug, and the funds within the channels concerned may be at risk."`
+
+	BalanceSnapshotInterval time.Duration `long:"balancesnapshotinterval" description:"How often a snapshot of the node's on-chain, channel, and pending balances is recorded within the balance history, which may be queried with the balancehistory command. A value of zero disables the snapshots."`
+
+	IdentityRotationDelay time.Duration `long:"identityrotationdelay" description:"How long after a peer announces the rotation of its identity key our channels with it are carried over to its new identity, unless the rotation is confirmed sooner with the confirmidentityrotation command. The delay leaves the peer's operator time to notice a rotation announced with a compromised key. A value of zero only carries channels over once the rotation is confirmed."`
+
+	InvoiceArchiveInterval time.Duration `long:"invoicearchiveinterval" description:"How often the invoices which expired without being settled are moved to the invoice archive within the database, after which they're no longer listed by listinvoices, nor found by lookupinvoice. A value of zero disables archiving."`
+
+	FeePresets []string `long:"feepreset" description:"Define a named fee preset which payments may select, of the form name:fee_ppm:cltv_limit:max_attempts:timeout. fee_ppm caps the route's fees in millionths of the payment amount, and cltv_limit caps its total time lock, with zero disabling either limit. Redefining one of the built in economy, normal, or urgent presets replaces it."`
+
+	SweepWhitelist []string `long:"sweepwhitelist" description:"Add an address, such as one of a cold storage wallet, to the whitelist of destinations on-chain funds leaving the node may be paid to. Once any address is whitelisted, funds swept from force closed and breached channels, and our balance of channels subsequently opened and closed cooperatively, are paid to the first whitelisted address rather than the wallet, and sendcoins and sendmany refuse to pay elsewhere unless the whitelist is explicitly overridden. Only P2PKH, P2WKH, P2SH, and P2WSH addresses are supported."`
+
+	Quotas []string `long:"quota" description:"Define a named credential whose calls are subject to quotas, of the form name:invoices_per_hour:payments_per_hour:daily_spend_sat[:permissions], with zero disabling a limit. The optional permissions are a comma separated list of the operations affecting our channels or funds the credential may carry out, from open, coopclose, forceclose, abandon, send and policy, and default to all of them. A token for the credential is written to the credentials directory within the data directory, which clients present to authenticate as the credential. Once any credential is defined, calls made without a token are rejected, unless allowed by allowuncredentialed."`
+
+	AllowUncredentialed bool `long:"allowuncredentialed" description:"Permit calls made without a credential token to carry out any operation, even though credentials are defined. Intended only for nodes whose RPC interface is reachable solely by the operator."`
+
+	CustomNetParams customNetConfig `group:"Custom Network" namespace:"customnet"`
+
+	HWI hwiConfig `group:"Hardware Wallet" namespace:"hwi"`
+
+	RPCMiddleware rpcMiddlewareConfig `group:"RPC Middleware" namespace:"rpcmiddleware"`
+
+	Advisor advisorConfig `group:"Channel Advisor" namespace:"advisor"`
+
+	Outbox outboxConfig `group:"Notification Outbox" namespace:"outbox"`
+
+	BackupUpload backupUploadConfig `group:"Backup Upload" namespace:"backupupload"`
+
+	Consolidation consolidationConfig `group:"UTXO Consolidation" namespace:"consolidation"`
+
+	CustomMessages customMessageConfig `group:"Custom Messages" namespace:"custommessages"`
+
+	Async asyncPaymentConfig `group:"Async Payments" namespace:"async"`
+
+	ZeroConf zeroConfConfig `group:"Zero-Conf Channels" namespace:"zeroconf"`
+
+	CsvDelay csvDelayConfig `group:"CSV Delay" namespace:"csvdelay"`
+
+	Jamming jammingConfig `group:"Jamming Mitigation" namespace:"jamming"`
+
+	Simulation simulationConfig `group:"Simulation" namespace:"simulation"`
+
+	PaymentRetry paymentRetryConfig `group:"Payment Retry" namespace:"paymentretry"`
+
+	Watchtower watchtowerConfig `group:"Watchtower" namespace:"watchtower"`
+
+	FeeCeiling feeCeilingConfig `group:"Fee Ceiling" namespace:"feeceiling"`
+
+	Probing probeDefenseConfig `group:"Probing Defense" namespace:"probing"`
+
+	RouteHints routeHintConfig `group:"Route Hints" namespace:"routehints"`
+
+	// feePresets are the fee presets available to payments, indexed by
+	// name, as parsed from FeePresets.
+	feePresets map[string]*feePreset
+
+	// quotas are the quotas of the named credentials, indexed by name, as
+	// parsed from Quotas.
+	quotas map[string]*credentialQuota
+
+	// sweepWhitelist is the whitelist of destinations for on-chain funds
+	// leaving the node, as parsed from SweepWhitelist.
+	sweepWhitelist *scriptWhitelist
+}
+
+// hwiConfig defines the options used to hold channel funding keys on a
+// hardware device, reached through an HWI-compatible bridge. The node's
+// identity key, and the keys used to update commitments, remain on the host.
+type hwiConfig struct {
+	Command     string `long:"command" description:"Path to an HWI-compatible executable used to communicate with the hardware device. If set, the multi-sig keys of new channels are held by the device, and cooperative closes must be signed on it. Channels funded with such keys can't be updated after they're opened, and may only be closed."`
+	DeviceType  string `long:"devicetype" description:"The type of the hardware device, such as trezor or ledger"`
+	Fingerprint string `long:"fingerprint" description:"The hex encoded fingerprint of the hardware device's master key"`
+	FundingPath string `long:"fundingpath" description:"The derivation path beneath which funding keys are derived on the device. Defaults to m/1017'/<coin type>'/0'/0"`
+
+	// fingerprint and fundingPath are the parsed forms of Fingerprint,
+	// and FundingPath.
+	fingerprint uint32
+	fundingPath []uint32
+}
+
+// parseHWIConfig validates the hardware wallet options, populating their
+// parsed forms. The options are ignored unless a bridge command is set.
+func parseHWIConfig(hwi *hwiConfig, coinType uint32) error {
+	if hwi.Command == "" {
+		return nil
+	}
+	if hwi.DeviceType == "" || hwi.Fingerprint == "" {
+		return fmt.Errorf("hwi.devicetype and hwi.fingerprint must " +
+			"be set when using a hardware device")
+	}
+
+	fingerprint, err := strconv.ParseUint(hwi.Fingerprint, 16, 32)
+	if err != nil || len(hwi.Fingerprint) != 8 {
+		return fmt.Errorf("invalid hwi.fingerprint %q, must be 8 hex "+
+			"characters", hwi.Fingerprint)
+	}
+	hwi.fingerprint = uint32(fingerprint)
+
+	if hwi.FundingPath == "" {
+		hwi.FundingPath = fmt.Sprintf("m/1017'/%v'/0'/0", coinType)
+	}
+	hwi.fundingPath, err = hwsigner.ParsePath(hwi.FundingPath)
+	if err != nil {
+		return fmt.Errorf("invalid hwi.fundingpath: %v", err)
+	}
+
+	return nil
+}
+
+// csvDelayConfig defines the policy used to set the CSV delay of new
+// channels, which is scaled between a minimum and maximum according to the
+// channel's capacity. The delays proposed by remote peers aren't scaled, but
+// must lie between a separate minimum and the same maximum.
+type csvDelayConfig struct {
+	Min          uint32 `long:"min" description:"The CSV delay in blocks of the smallest channels we open"`
+	Max          uint32 `long:"max" description:"The CSV delay in blocks of channels at or above the full capacity, and the longest delay we accept from a peer"`
+	FullCapacity int64  `long:"fullcapacity" description:"The capacity in satoshis from which channels are given the maximum CSV delay. The delay of smaller channels is scaled linearly between the minimum and maximum"`
+	MinRemote    uint32 `long:"minremote" description:"The shortest CSV delay in blocks we accept from a peer, whatever the channel's capacity"`
+
+	// policy is the CSV delay policy defined by the options.
+	policy *lnwallet.CsvDelayPolicy
+}
+
+// validate checks the CSV delay bounds for consistency, and constructs the
+// policy they define.
+func (c *csvDelayConfig) validate() error {
+	c.policy = &lnwallet.CsvDelayPolicy{
+		MinDelay:          c.Min,
+		MaxDelay:          c.Max,
+		FullDelayCapacity: btcutil.Amount(c.FullCapacity),
+		MinRemoteDelay:    c.MinRemote,
+	}
+	if err := c.policy.Validate(); err != nil {
+		return fmt.Errorf("invalid csvdelay options: %v", err)
+	}
+
+	return nil
+}
+
+// feeCeilingConfig defines the bounds on the fee of each transaction we
+// broadcast, guarding against bugs within the fee calculation of the close,
+// sweep, and justice transactions we construct.
+type feeCeilingConfig struct {
+	MaxFee          int64   `long:"maxfee" description:"The largest fee in satoshis any transaction we broadcast may pay. A value of zero places no limit on the fee"`
+	MaxRateMultiple float64 `long:"maxratemultiple" description:"The largest multiple of the estimated fee rate any transaction we broadcast may pay. A value of zero places no limit on the fee rate"`
+	ConfTarget      uint32  `long:"conftarget" description:"The number of blocks within which a transaction is expected to confirm at the estimated fee rate transactions are compared to"`
+
+	// ceiling is the fee ceiling defined by the options.
+	ceiling *lnwallet.FeeCeiling
+}
+
+// validate checks the fee ceiling options for consistency, and constructs
+// the ceiling they define.
+func (c *feeCeilingConfig) validate() error {
+	c.ceiling = &lnwallet.FeeCeiling{
+		MaxFee:             btcutil.Amount(c.MaxFee),
+		MaxFeeRateMultiple: c.MaxRateMultiple,
+		ConfTarget:         c.ConfTarget,
+	}
+	if err := c.ceiling.Validate(); err != nil {
+		return fmt.Errorf("invalid feeceiling options: %v", err)
+	}
+
+	return nil
+}
+
+// paymentRetryConfig defines the policy by which failed outgoing payments are
+// retried. The number of attempts is overridden by the fee preset a payment
+// selects, if any.
+type paymentRetryConfig struct {
+	MaxAttempts       uint32        `long:"maxattempts" description:"The number of times a payment which doesn't select a fee preset is attempted before giving up"`
+	Backoff           time.Duration `long:"backoff" description:"The delay before a failed payment is retried, which is doubled after each subsequent failure. A value of zero retries immediately"`
+	MaxBackoff        time.Duration `long:"maxbackoff" description:"The longest delay between two attempts of a payment. A value of zero places no limit on the delay"`
+	ExcludeFailedHops bool          `long:"excludefailedhops" description:"Exclude the channels of each failed route, other than our own, from the subsequent attempts of the payment"`
+	FeeLimitStep      uint64        `long:"feelimitstep" description:"The amount, in millionths of the payment amount, by which the fee limit of a payment is widened after each failed attempt. A value of zero keeps the fee limit fixed"`
+
+	// policy is the retry policy defined by the options.
+	policy routing.RetryPolicy
+}
+
+// validate checks the retry options for consistency, and constructs the
+// policy they define.
+func (c *paymentRetryConfig) validate() error {
+	switch {
+	case c.MaxAttempts == 0:
+		return fmt.Errorf("paymentretry.maxattempts must be positive")
+
+	case c.Backoff < 0 || c.MaxBackoff < 0:
+		return fmt.Errorf("paymentretry.backoff and " +
+			"paymentretry.maxbackoff can't be negative")
+
+	case c.MaxBackoff != 0 && c.MaxBackoff < c.Backoff:
+		return fmt.Errorf("paymentretry.maxbackoff can't be below " +
+			"paymentretry.backoff")
+	}
+
+	c.policy = routing.RetryPolicy{
+		MaxAttempts:       c.MaxAttempts,
+		Backoff:           c.Backoff,
+		MaxBackoff:        c.MaxBackoff,
+		ExcludeFailedHops: c.ExcludeFailedHops,
+		FeeLimitStep:      c.FeeLimitStep,
+	}
+
+	return nil
 }
 
 // loadConfig initializes and parses the config using a config file and command
@@ -94,6 +420,83 @@ func loadConfig() (*config, error) {
 		RPCPass:            defaultRPCPass,
 		RPCCert:            defaultRPCCertFile,
 		MaxPendingChannels: defaultMaxPendingChannels,
+		MinHTLC:            defaultMinHTLC,
+		MaxDustExposure:    defaultMaxDustExposure,
+		MaxHashExposure:    defaultMaxHashExposure,
+		ChanReserve:        defaultChanReserve,
+		LowFreeSpace:       defaultLowFreeSpace,
+		CloseBumpBlocks:    defaultCloseBumpBlocks,
+		SweepBumpBlocks:    defaultSweepBumpBlocks,
+		SpliceConfs:        defaultSpliceConfs,
+		ChanHistoryThresh:  defaultChanHistoryThresh,
+		NumGraphSyncPeers:  defaultNumGraphSyncPeers,
+		BlockCacheSize:     defaultBlockCacheSize,
+		PathFindingTimeout: defaultPathFindingTimeout,
+		GossipStoreAge:     defaultGossipStoreAge,
+		GossipStoreSize:    defaultGossipStoreSize,
+		RPCMiddleware: rpcMiddlewareConfig{
+			InterceptTimeout: defaultMiddlewareTimeout,
+		},
+		Advisor: advisorConfig{
+			Lookback: defaultAdvisorLookback,
+			Interval: defaultAdvisorInterval,
+		},
+		Consolidation: consolidationConfig{
+			MaxFeeRate:     defaultConsolidationMaxFeeRate,
+			ConfTarget:     defaultConsolidationConfTarget,
+			MaxOutputValue: defaultConsolidationMaxOutputValue,
+			MinOutputs:     defaultConsolidationMinOutputs,
+			MaxOutputs:     defaultConsolidationMaxOutputs,
+		},
+		Async: asyncPaymentConfig{
+			HoldTimeout: defaultAsyncHoldTimeout,
+			MaxHeld:     defaultAsyncMaxHeld,
+		},
+		CsvDelay: csvDelayConfig{
+			Min:          defaultCsvDelayMin,
+			Max:          defaultCsvDelayMax,
+			FullCapacity: defaultCsvDelayFullCapacity,
+			MinRemote:    defaultCsvDelayMinRemote,
+		},
+		Jamming: jammingConfig{
+			ProtectedSlots:     defaultJammingProtectedSlots,
+			ProtectedLiquidity: defaultJammingProtectedLiquidity,
+			MinReputation:      defaultJammingMinReputation,
+			ReputationHalfLife: defaultJammingReputationHalfLife,
+			ResolutionPeriod:   defaultJammingResolutionPeriod,
+		},
+		Simulation: simulationConfig{
+			BlockInterval: defaultSimulationBlockInterval,
+		},
+		PaymentRetry: paymentRetryConfig{
+			MaxAttempts: defaultPaymentRetryMaxAttempts,
+			Backoff:     defaultPaymentRetryBackoff,
+			MaxBackoff:  defaultPaymentRetryMaxBackoff,
+		},
+		Watchtower: watchtowerConfig{
+			MaxSessionsPerClient: defaultWatchtowerMaxSessionsPerClient,
+			MaxUpdatesPerSession: defaultWatchtowerMaxUpdatesPerSession,
+			MaxStorage:           defaultWatchtowerMaxStorage,
+			MaxStoragePerClient:  defaultWatchtowerMaxStoragePerClient,
+			RewardRate:           defaultWatchtowerRewardRate,
+		},
+		FeeCeiling: feeCeilingConfig{
+			MaxFee:          defaultFeeCeilingMaxFee,
+			MaxRateMultiple: defaultFeeCeilingMaxRateMultiple,
+			ConfTarget:      defaultFeeCeilingConfTarget,
+		},
+		Probing: probeDefenseConfig{
+			FinalCltvPadding:   defaultProbingFinalCltvPadding,
+			MaxIdenticalProbes: defaultProbingMaxIdenticalProbes,
+			ProbeWindow:        defaultProbingProbeWindow,
+		},
+		RouteHints: routeHintConfig{
+			Strategy: defaultRouteHintsStrategy,
+			MaxHints: defaultRouteHintsMaxHints,
+		},
+		BalanceSnapshotInterval: defaultBalanceSnapshots,
+		IdentityRotationDelay:   defaultIdentityRotation,
+		InvoiceArchiveInterval:  defaultInvoiceArchiving,
 	}
 
 	// Pre-parse the command line options to pick up an alternative config
@@ -155,10 +558,245 @@ func loadConfig() (*config, error) {
 		numNets++
 		activeNetParams = simNetParams
 	}
+	if cfg.SigNet {
+		numNets++
+		activeNetParams = sigNetParams
+	}
+	if cfg.CustomNet {
+		numNets++
+		customNetParams, err := newCustomNetParams(&cfg.CustomNetParams)
+		if err != nil {
+			str := "%s: invalid custom network: %v"
+			err := fmt.Errorf(str, funcName, err)
+			fmt.Fprintln(os.Stderr, err)
+			return nil, err
+		}
+		activeNetParams = customNetParams
+	}
 	if numNets > 1 {
-		str := "%s: The testnet, segnet, and simnet params can't be " +
-			"used together -- choose one of the three"
+		str := "%s: The testnet, simnet, signet, and customnet params " +
+			"can't be used together -- choose one of the four"
+		err := fmt.Errorf(str, funcName)
+		return nil, err
+	}
+
+	// Networks unknown to chaincfg must be registered so that addresses
+	// on them can be decoded.
+	if err := registerNetParams(activeNetParams); err != nil {
+		str := "%s: unable to register %v params: %v"
+		err := fmt.Errorf(str, funcName, activeNetParams.Name, err)
+		fmt.Fprintln(os.Stderr, err)
+		return nil, err
+	}
+
+	// The HTLC policy values are amounts, and therefore can't be negative.
+	if cfg.MinHTLC < 0 || cfg.MaxDustExposure < 0 ||
+		cfg.MaxHashExposure < 0 || cfg.ChanReserve < 0 ||
+		cfg.ChanHistoryThresh < 0 || cfg.MaxDualFundingAmt < 0 {
+
+		str := "%s: minhtlc, maxdustexposure, maxhashexposure, " +
+			"chanreserve, chanhistorythreshold, and " +
+			"maxdualfundingamt must not be negative"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	if cfg.NumGraphSyncPeers < 0 || cfg.BlockCacheSize < 0 ||
+		cfg.BalanceSnapshotInterval < 0 || cfg.GossipStoreAge < 0 ||
+		cfg.GossipStoreSize < 0 || cfg.InvoiceArchiveInterval < 0 ||
+		cfg.IdentityRotationDelay < 0 {
+
+		str := "%s: numgraphsyncpeers, blockcachesize, " +
+			"balancesnapshotinterval, gossipstoreage, " +
+			"gossipstoresize, invoicearchiveinterval, and " +
+			"identityrotationdelay must not be negative"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	// A splice is only migrated to once its transaction has confirmed, so
+	// at least a single confirmation is required.
+	if cfg.SpliceConfs == 0 {
+		str := "%s: spliceconfs must be at least 1"
+		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	// Parse the operator's fee presets, merging them with the built in
+	// ones.
+	feePresets, err := parseFeePresets(cfg.FeePresets)
+	if err != nil {
+		err := fmt.Errorf("%s: %v", funcName, err)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+	cfg.feePresets = feePresets
+
+	// Parse the quotas of the operator's credentials.
+	quotas, err := parseCredentialQuotas(cfg.Quotas)
+	if err != nil {
+		err := fmt.Errorf("%s: %v", funcName, err)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+	cfg.quotas = quotas
+
+	// Parse the whitelist of destinations for on-chain funds.
+	sweepWhitelist, err := parseScriptWhitelist(cfg.SweepWhitelist,
+		activeNetParams.Params)
+	if err != nil {
+		err := fmt.Errorf("%s: %v", funcName, err)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+	cfg.sweepWhitelist = sweepWhitelist
+
+	// Validate the hardware wallet options, if a device is in use.
+	err = parseHWIConfig(&cfg.HWI, activeNetParams.HDCoinType)
+	if err != nil {
+		err := fmt.Errorf("%s: %v", funcName, err)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	// Mandatory middleware can only be registered if the middleware
+	// framework is enabled.
+	if len(cfg.RPCMiddleware.Mandatory) != 0 && !cfg.RPCMiddleware.Enable {
+		str := "%s: rpcmiddleware.addmandatory requires " +
+			"rpcmiddleware.enable"
 		err := fmt.Errorf(str, funcName)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	// Validate the channel advisor options.
+	if err := cfg.Advisor.validate(); err != nil {
+		err := fmt.Errorf("%s: %v", funcName, err)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	// Validate the notification outbox webhooks.
+	if err := cfg.Outbox.validate(); err != nil {
+		err := fmt.Errorf("%s: %v", funcName, err)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	// Parse the targets the channel backup file is uploaded to.
+	if err := cfg.BackupUpload.validate(); err != nil {
+		err := fmt.Errorf("%s: %v", funcName, err)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	// Validate the UTXO consolidation options.
+	if err := cfg.Consolidation.validate(); err != nil {
+		err := fmt.Errorf("%s: %v", funcName, err)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	// Validate the experimental feature bits of custom messages.
+	if err := cfg.CustomMessages.validate(); err != nil {
+		err := fmt.Errorf("%s: %v", funcName, err)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	// Validate the async payment options, and parse the designated
+	// holders.
+	if err := cfg.Async.validate(); err != nil {
+		err := fmt.Errorf("%s: %v", funcName, err)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	// Parse the peers we accept zero-conf channels from.
+	if err := cfg.ZeroConf.validate(); err != nil {
+		err := fmt.Errorf("%s: %v", funcName, err)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	// Construct the policy setting the CSV delay of new channels.
+	if err := cfg.CsvDelay.validate(); err != nil {
+		err := fmt.Errorf("%s: %v", funcName, err)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	// Validate the reservation of channel resources for endorsed HTLCs.
+	if err := cfg.Jamming.validate(); err != nil {
+		err := fmt.Errorf("%s: %v", funcName, err)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	// The virtual chain of the simulation mode only simulates simnet.
+	if err := cfg.Simulation.validate(cfg.SimNet); err != nil {
+		err := fmt.Errorf("%s: %v", funcName, err)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	if err := cfg.PaymentRetry.validate(); err != nil {
+		err := fmt.Errorf("%s: %v", funcName, err)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	// Validate the watchtower's quotas.
+	if err := cfg.Watchtower.validate(); err != nil {
+		err := fmt.Errorf("%s: %v", funcName, err)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	// Construct the ceiling bounding the fees of our transactions.
+	if err := cfg.FeeCeiling.validate(); err != nil {
+		err := fmt.Errorf("%s: %v", funcName, err)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	// Validate the defense against the probing of our channels.
+	if err := cfg.Probing.validate(); err != nil {
+		err := fmt.Errorf("%s: %v", funcName, err)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
+		return nil, err
+	}
+
+	// Validate the policy selecting the route hints of payment requests.
+	if err := cfg.RouteHints.validate(); err != nil {
+		err := fmt.Errorf("%s: %v", funcName, err)
+		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintln(os.Stderr, usageMessage)
 		return nil, err
 	}
 
@@ -188,6 +826,50 @@ func loadConfig() (*config, error) {
 	cfg.LogDir = cleanAndExpandPath(cfg.LogDir)
 	cfg.LogDir = filepath.Join(cfg.LogDir, activeNetParams.Name)
 
+	// The channel database and wallet may be placed on separate volumes
+	// from the rest of the data directory. The channel database is
+	// namespaced in the same fashion as the data directory, while the
+	// wallet namespaces its own files.
+	if cfg.ChanDBDir == "" {
+		cfg.ChanDBDir = cfg.DataDir
+	} else {
+		cfg.ChanDBDir = cleanAndExpandPath(cfg.ChanDBDir)
+		cfg.ChanDBDir = filepath.Join(cfg.ChanDBDir,
+			activeNetParams.Name)
+	}
+	if cfg.WalletDir == "" {
+		cfg.WalletDir = filepath.Join(cfg.DataDir, "lnwallet")
+	} else {
+		cfg.WalletDir = cleanAndExpandPath(cfg.WalletDir)
+	}
+	if cfg.BackupFile == "" {
+		cfg.BackupFile = filepath.Join(cfg.ChanDBDir,
+			defaultBackupFilename)
+	} else {
+		cfg.BackupFile = cleanAndExpandPath(cfg.BackupFile)
+	}
+
+	if cfg.AnalyticsDB != "" {
+		cfg.AnalyticsDB = cleanAndExpandPath(cfg.AnalyticsDB)
+	}
+
+	// Ensure each directory is usable before any component attempts to
+	// write to it, so a misconfigured or full volume is reported up
+	// front.
+	dirs := []dataDir{
+		{component: "data", path: cfg.DataDir},
+		{component: "log", path: cfg.LogDir},
+		{component: "channel database", path: cfg.ChanDBDir},
+		{component: "wallet", path: cfg.WalletDir},
+		{component: "channel backup", path: filepath.Dir(cfg.BackupFile)},
+	}
+	err = validateDataDirs(dirs, cfg.MinFreeSpace*bytesPerMegabyte)
+	if err != nil {
+		err := fmt.Errorf("%s: %v", funcName, err)
+		fmt.Fprintln(os.Stderr, err)
+		return nil, err
+	}
+
 	// Initialize logging at the default logging level.
 	initSeelogLogger(filepath.Join(cfg.LogDir, defaultLogFilename))
 	setLogLevels(defaultLogLevel)
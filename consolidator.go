@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/roasbeef/btcutil"
+)
+
+// consolidationConfig defines the options of the wallet's output
+// consolidation scheduler.
+type consolidationConfig struct {
+	Interval       time.Duration `long:"interval" description:"How often the wallet's outputs are checked for consolidation. A value of zero disables consolidation"`
+	MaxFeeRate     int64         `long:"maxfeerate" description:"The highest estimated fee rate in sat/byte at which outputs are consolidated"`
+	ConfTarget     uint32        `long:"conftarget" description:"The number of blocks within which the consolidation transaction should confirm, used to estimate the fee rate"`
+	MaxOutputValue int64         `long:"maxoutputvalue" description:"The largest output in satoshis which is considered small enough to be consolidated"`
+	MinOutputs     int           `long:"minoutputs" description:"The fewest small outputs worth consolidating at once"`
+	MaxOutputs     int           `long:"maxoutputs" description:"The most outputs consolidated within a single transaction. A value of zero removes the limit"`
+}
+
+// validate checks the consolidation options for consistency.
+func (c *consolidationConfig) validate() error {
+	if c.Interval < 0 || c.MaxFeeRate < 0 || c.MaxOutputValue < 0 ||
+		c.MinOutputs < 0 || c.MaxOutputs < 0 {
+
+		return fmt.Errorf("consolidation options must not be negative")
+	}
+	if c.Interval == 0 {
+		return nil
+	}
+
+	if c.ConfTarget == 0 {
+		return fmt.Errorf("consolidation.conftarget must be positive")
+	}
+	if c.MinOutputs < 2 {
+		return fmt.Errorf("consolidation.minoutputs must be at least 2")
+	}
+	if c.MaxOutputs != 0 && c.MaxOutputs < c.MinOutputs {
+		return fmt.Errorf("consolidation.maxoutputs must not be below " +
+			"consolidation.minoutputs")
+	}
+
+	return nil
+}
+
+// utxoConsolidator periodically sweeps the wallet's small outputs into a
+// single larger output whenever fee rates are low, so future channels can be
+// funded with fewer inputs, and so more cheaply.
+type utxoConsolidator struct {
+	started uint32 // To be used atomically.
+	stopped uint32 // To be used atomically.
+
+	cfg    *consolidationConfig
+	wallet *lnwallet.LightningWallet
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newUtxoConsolidator creates a new consolidator of the passed wallet's
+// outputs.
+func newUtxoConsolidator(cfg *consolidationConfig,
+	wallet *lnwallet.LightningWallet) *utxoConsolidator {
+
+	return &utxoConsolidator{
+		cfg:    cfg,
+		wallet: wallet,
+		quit:   make(chan struct{}),
+	}
+}
+
+// Start launches the consolidation scheduler, if the operator has enabled it.
+func (u *utxoConsolidator) Start() error {
+	if !atomic.CompareAndSwapUint32(&u.started, 0, 1) {
+		return nil
+	}
+
+	if u.cfg.Interval != 0 {
+		lnwlLog.Infof("Consolidating outputs below %v every %v at fee "+
+			"rates up to %v sat/byte",
+			btcutil.Amount(u.cfg.MaxOutputValue), u.cfg.Interval,
+			u.cfg.MaxFeeRate)
+
+		u.wg.Add(1)
+		go u.scheduler()
+	}
+
+	return nil
+}
+
+// Stop halts the consolidation scheduler.
+func (u *utxoConsolidator) Stop() error {
+	if !atomic.CompareAndSwapUint32(&u.stopped, 0, 1) {
+		return nil
+	}
+
+	close(u.quit)
+	u.wg.Wait()
+
+	return nil
+}
+
+// scheduler attempts a consolidation each time the interval passes.
+//
+// NOTE: This MUST be run as a goroutine.
+func (u *utxoConsolidator) scheduler() {
+	defer u.wg.Done()
+
+	ticker := time.NewTicker(u.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := u.consolidate(); err != nil {
+				lnwlLog.Errorf("unable to consolidate "+
+					"outputs: %v", err)
+			}
+
+		case <-u.quit:
+			return
+		}
+	}
+}
+
+// consolidate sweeps the wallet's small outputs, if the current fee rate is
+// within the configured threshold.
+func (u *utxoConsolidator) consolidate() error {
+	feeRate, err := u.wallet.EstimateFeePerByte(u.cfg.ConfTarget)
+	switch {
+	case err == lnwallet.ErrNoFeeEstimate:
+		lnwlLog.Debugf("Skipping consolidation, no fee estimate " +
+			"available")
+		return nil
+	case err != nil:
+		return err
+	}
+
+	if int64(feeRate) > u.cfg.MaxFeeRate {
+		lnwlLog.Debugf("Skipping consolidation, fee rate of %v "+
+			"sat/byte exceeds %v sat/byte", int64(feeRate),
+			u.cfg.MaxFeeRate)
+		return nil
+	}
+
+	// Fee rates below 1 sat/byte won't relay.
+	if feeRate < 1 {
+		feeRate = 1
+	}
+
+	_, err = u.wallet.ConsolidateCoins(uint64(feeRate),
+		btcutil.Amount(u.cfg.MaxOutputValue), u.cfg.MinOutputs,
+		u.cfg.MaxOutputs)
+	return err
+}
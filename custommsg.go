@@ -0,0 +1,255 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/btcec"
+)
+
+const (
+	// minCustomFeatureBit is the lowest feature bit which may be
+	// advertised for an experimental protocol extension. Lower bits are
+	// reserved for the features of the protocol proper.
+	minCustomFeatureBit = 100
+
+	// customMessageQueueSize is the number of received messages which may
+	// be queued for delivery to a single handler. Messages are dropped
+	// once the queue is full.
+	customMessageQueueSize = 100
+)
+
+// customMessageConfig defines the options of the experimental message
+// extensions.
+type customMessageConfig struct {
+	Features []uint16 `long:"feature" description:"Advertise the given experimental feature bit to peers, which must be odd and at least 100. Plugins may exchange custom messages tied to the feature with peers which also advertise it. May be specified multiple times"`
+}
+
+// validate checks that each feature bit is an optional bit within the
+// experimental range.
+func (c *customMessageConfig) validate() error {
+	seen := make(map[uint16]struct{})
+	for _, bit := range c.Features {
+		if bit < minCustomFeatureBit {
+			return fmt.Errorf("custommessages.feature %v is below "+
+				"the experimental range starting at %v", bit,
+				minCustomFeatureBit)
+		}
+		if bit%2 == 0 {
+			return fmt.Errorf("custommessages.feature %v must be "+
+				"odd, as experimental features are optional",
+				bit)
+		}
+		if _, ok := seen[bit]; ok {
+			return fmt.Errorf("duplicate custommessages.feature %v",
+				bit)
+		}
+		seen[bit] = struct{}{}
+	}
+
+	return nil
+}
+
+// customFeatureName returns the name of the experimental feature with the
+// passed bit within our local feature vector.
+func customFeatureName(bit uint16) string {
+	return fmt.Sprintf("experimental-%v", bit)
+}
+
+// customPeerMessage is a custom message received from a peer.
+type customPeerMessage struct {
+	peer *btcec.PublicKey
+	msg  *lnwire.CustomMessage
+}
+
+// customMessageHandler is a single plugin registered to handle a set of
+// custom message types, all tied to the same feature bit.
+type customMessageHandler struct {
+	featureBit uint16
+	types      []uint32
+
+	// msgs is the queue of received messages awaiting delivery to the
+	// plugin.
+	msgs chan *customPeerMessage
+}
+
+// customMessageRegistry allows external plugins to exchange messages of types
+// within the experimental range with peers, without requiring changes to the
+// wire layer. Each message type is tied to an experimental feature bit, and
+// messages of the type are only exchanged with peers which advertise the
+// feature.
+//
+// The feature bits are fixed by the configuration, as they're advertised
+// within the init message when connecting to each peer. Plugins register
+// handlers for message types at runtime.
+type customMessageRegistry struct {
+	// features is the set of configured experimental feature bits.
+	features map[uint16]struct{}
+
+	// sendToPeer delivers a message to the target peer.
+	sendToPeer func(*btcec.PublicKey, ...lnwire.Message) error
+
+	// peerFeatures returns the features shared with the target peer, and
+	// fails if we aren't connected to it.
+	peerFeatures func(*btcec.PublicKey) (*lnwire.SharedFeatures, error)
+
+	// handlers maps each registered message type to its handler.
+	handlers map[uint32]*customMessageHandler
+	sync.Mutex
+}
+
+// newCustomMessageRegistry creates a new registry for message types tied to
+// the passed feature bits.
+func newCustomMessageRegistry(featureBits []uint16,
+	sendToPeer func(*btcec.PublicKey, ...lnwire.Message) error,
+	peerFeatures func(*btcec.PublicKey) (*lnwire.SharedFeatures, error),
+) *customMessageRegistry {
+
+	features := make(map[uint16]struct{})
+	for _, bit := range featureBits {
+		features[bit] = struct{}{}
+	}
+
+	return &customMessageRegistry{
+		features:     features,
+		sendToPeer:   sendToPeer,
+		peerFeatures: peerFeatures,
+		handlers:     make(map[uint32]*customMessageHandler),
+	}
+}
+
+// addFeatures adds each configured experimental feature to the passed local
+// feature vector, advertising it as optional.
+func (r *customMessageRegistry) addFeatures(f *lnwire.FeatureVector) error {
+	for bit := range r.features {
+		// Each feature occupies a pair of bits, of which the odd bit
+		// signals the feature is optional.
+		err := f.AddFeature(customFeatureName(bit), int(bit/2),
+			lnwire.OptionalFlag)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// registerHandler registers a new handler for the passed message types, tied
+// to the passed feature bit. Each message type may only have a single
+// handler at a time.
+func (r *customMessageRegistry) registerHandler(featureBit uint16,
+	types []uint32) (*customMessageHandler, error) {
+
+	if _, ok := r.features[featureBit]; !ok {
+		return nil, fmt.Errorf("feature bit %v isn't configured via "+
+			"custommessages.feature", featureBit)
+	}
+	if len(types) == 0 {
+		return nil, fmt.Errorf("at least one message type must be " +
+			"registered")
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	for _, msgType := range types {
+		if msgType < lnwire.CmdCustomMessageStart {
+			return nil, fmt.Errorf("message type %v is below the "+
+				"experimental range starting at %v", msgType,
+				lnwire.CmdCustomMessageStart)
+		}
+		if _, ok := r.handlers[msgType]; ok {
+			return nil, fmt.Errorf("message type %v already has "+
+				"a handler", msgType)
+		}
+	}
+
+	handler := &customMessageHandler{
+		featureBit: featureBit,
+		types:      types,
+		msgs:       make(chan *customPeerMessage, customMessageQueueSize),
+	}
+	for _, msgType := range types {
+		r.handlers[msgType] = handler
+	}
+
+	return handler, nil
+}
+
+// unregisterHandler releases the message types of the passed handler.
+func (r *customMessageRegistry) unregisterHandler(
+	handler *customMessageHandler) {
+
+	r.Lock()
+	defer r.Unlock()
+
+	for _, msgType := range handler.types {
+		if r.handlers[msgType] == handler {
+			delete(r.handlers, msgType)
+		}
+	}
+}
+
+// processCustomMessage queues a custom message received from the passed peer
+// for delivery to the handler of its type. Messages of types without a
+// handler, or from peers which don't advertise the type's feature, are
+// dropped.
+func (r *customMessageRegistry) processCustomMessage(peer *btcec.PublicKey,
+	shared *lnwire.SharedFeatures, msg *lnwire.CustomMessage) {
+
+	r.Lock()
+	handler, ok := r.handlers[msg.Type]
+	r.Unlock()
+	if !ok {
+		peerLog.Debugf("Dropping custom message of unhandled type %v "+
+			"from %x", msg.Type, peer.SerializeCompressed())
+		return
+	}
+
+	if !shared.IsActive(customFeatureName(handler.featureBit)) {
+		peerLog.Debugf("Dropping custom message of type %v from %x, "+
+			"which doesn't advertise feature bit %v", msg.Type,
+			peer.SerializeCompressed(), handler.featureBit)
+		return
+	}
+
+	select {
+	case handler.msgs <- &customPeerMessage{peer: peer, msg: msg}:
+	default:
+		peerLog.Warnf("Handler of custom message type %v has fallen "+
+			"behind, dropping message from %x", msg.Type,
+			peer.SerializeCompressed())
+	}
+}
+
+// sendCustomMessage sends a custom message of the passed type to the target
+// peer. The type must have a registered handler, and the peer must advertise
+// the feature the type is tied to.
+func (r *customMessageRegistry) sendCustomMessage(target *btcec.PublicKey,
+	msgType uint32, data []byte) error {
+
+	r.Lock()
+	handler, ok := r.handlers[msgType]
+	r.Unlock()
+	if !ok {
+		return fmt.Errorf("message type %v has no registered handler",
+			msgType)
+	}
+
+	shared, err := r.peerFeatures(target)
+	if err != nil {
+		return err
+	}
+	if !shared.IsActive(customFeatureName(handler.featureBit)) {
+		return fmt.Errorf("peer %x doesn't advertise feature bit %v",
+			target.SerializeCompressed(), handler.featureBit)
+	}
+
+	msg, err := lnwire.NewCustomMessage(msgType, data)
+	if err != nil {
+		return err
+	}
+
+	return r.sendToPeer(target, msg)
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/btcec"
+)
+
+func TestCustomMessageRegistry(t *testing.T) {
+	const (
+		featureBit = 101
+		msgType    = lnwire.CmdCustomMessageStart + 1
+	)
+
+	priv, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+	peerKey := priv.PubKey()
+
+	// The peer either advertises the experimental feature, or only the
+	// features of the protocol proper.
+	var (
+		sent        []lnwire.Message
+		peerShared  *lnwire.SharedFeatures
+		withFeature = localFeatures.Copy()
+	)
+	registry := newCustomMessageRegistry([]uint16{featureBit},
+		func(target *btcec.PublicKey, msgs ...lnwire.Message) error {
+			sent = append(sent, msgs...)
+			return nil
+		},
+		func(target *btcec.PublicKey) (*lnwire.SharedFeatures, error) {
+			return peerShared, nil
+		},
+	)
+	if err := registry.addFeatures(withFeature); err != nil {
+		t.Fatalf("unable to add features: %v", err)
+	}
+	sharedWith, err := withFeature.Compare(withFeature)
+	if err != nil {
+		t.Fatalf("unable to compare features: %v", err)
+	}
+	sharedWithout, err := withFeature.Compare(localFeatures)
+	if err != nil {
+		t.Fatalf("unable to compare features: %v", err)
+	}
+
+	// Handlers may only be registered for configured feature bits, and
+	// types within the experimental range.
+	if _, err := registry.registerHandler(103, []uint32{msgType}); err == nil {
+		t.Fatalf("handler shouldn't register for unconfigured feature")
+	}
+	_, err = registry.registerHandler(featureBit, []uint32{lnwire.CmdPing})
+	if err == nil {
+		t.Fatalf("handler shouldn't register for built-in type")
+	}
+
+	handler, err := registry.registerHandler(featureBit, []uint32{msgType})
+	if err != nil {
+		t.Fatalf("unable to register handler: %v", err)
+	}
+	if _, err := registry.registerHandler(featureBit, []uint32{msgType}); err == nil {
+		t.Fatalf("type shouldn't have a second handler")
+	}
+
+	// A message from a peer which advertises the feature is delivered to
+	// the handler, while one from a peer which doesn't is dropped.
+	msg, err := lnwire.NewCustomMessage(msgType, []byte{1, 2, 3})
+	if err != nil {
+		t.Fatalf("unable to create message: %v", err)
+	}
+	registry.processCustomMessage(peerKey, sharedWithout, msg)
+	registry.processCustomMessage(peerKey, sharedWith, msg)
+	if len(handler.msgs) != 1 {
+		t.Fatalf("expected 1 delivered message, got %v",
+			len(handler.msgs))
+	}
+	delivered := <-handler.msgs
+	if delivered.msg != msg || !delivered.peer.IsEqual(peerKey) {
+		t.Fatalf("unexpected delivered message: %v", delivered)
+	}
+
+	// Likewise, messages may only be sent to peers which advertise the
+	// feature.
+	peerShared = sharedWithout
+	if err := registry.sendCustomMessage(peerKey, msgType, nil); err == nil {
+		t.Fatalf("message shouldn't be sent to peer without feature")
+	}
+	peerShared = sharedWith
+	err = registry.sendCustomMessage(peerKey, msgType, []byte{4, 5})
+	if err != nil {
+		t.Fatalf("unable to send message: %v", err)
+	}
+	if len(sent) != 1 {
+		t.Fatalf("expected 1 sent message, got %v", len(sent))
+	}
+	sentMsg := sent[0].(*lnwire.CustomMessage)
+	if sentMsg.Type != msgType || !bytes.Equal(sentMsg.Data, []byte{4, 5}) {
+		t.Fatalf("unexpected sent message: %v", sentMsg)
+	}
+
+	// Once unregistered, the type may no longer be sent, and may be
+	// registered once more.
+	registry.unregisterHandler(handler)
+	if err := registry.sendCustomMessage(peerKey, msgType, nil); err == nil {
+		t.Fatalf("unhandled type shouldn't be sent")
+	}
+	if _, err := registry.registerHandler(featureBit, []uint32{msgType}); err != nil {
+		t.Fatalf("unable to register handler: %v", err)
+	}
+}
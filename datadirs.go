@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+)
+
+// bytesPerMegabyte is used to convert the configured minimum free space into
+// bytes.
+const bytesPerMegabyte = 1024 * 1024
+
+// dataDir is a directory one of the daemon's components stores its files
+// within.
+type dataDir struct {
+	// component is a human readable name of the component which stores
+	// its files within the directory.
+	component string
+
+	// path is the path to the directory.
+	path string
+}
+
+// validateDataDirs ensures each of the passed directories exists, creating it
+// if need be, and that files can be created within it. If minFreeSpace is
+// non-zero, then the volume each directory resides on must also have at
+// least that many bytes free. This allows a misconfigured or full volume to
+// be detected at startup, rather than once a component first writes to it.
+func validateDataDirs(dirs []dataDir, minFreeSpace uint64) error {
+	for _, dir := range dirs {
+		if err := os.MkdirAll(dir.path, 0700); err != nil {
+			return fmt.Errorf("unable to create %v directory: %v",
+				dir.component, err)
+		}
+
+		// The only reliable way to check that the directory is
+		// writable is to write to it.
+		probe, err := ioutil.TempFile(dir.path, ".probe")
+		if err != nil {
+			return fmt.Errorf("%v directory %v isn't writable: %v",
+				dir.component, dir.path, err)
+		}
+		probe.Close()
+		if err := os.Remove(probe.Name()); err != nil {
+			return fmt.Errorf("unable to remove probe file from %v "+
+				"directory: %v", dir.component, err)
+		}
+
+		if minFreeSpace == 0 {
+			continue
+		}
+
+		free, err := freeSpace(dir.path)
+		if err != nil {
+			return fmt.Errorf("unable to determine free space of %v "+
+				"directory: %v", dir.component, err)
+		}
+		if free < minFreeSpace {
+			return fmt.Errorf("%v directory %v has %v MB free, "+
+				"below the minimum of %v MB", dir.component,
+				dir.path, free/bytesPerMegabyte,
+				minFreeSpace/bytesPerMegabyte)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestValidateDataDirs(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "datadirs")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	// Missing directories should be created, and the probe file cleaned
+	// up afterwards.
+	dbDir := filepath.Join(tempDir, "chandb", "simnet")
+	dirs := []dataDir{{component: "channel database", path: dbDir}}
+	if err := validateDataDirs(dirs, 0); err != nil {
+		t.Fatalf("unable to validate dirs: %v", err)
+	}
+	files, err := ioutil.ReadDir(dbDir)
+	if err != nil {
+		t.Fatalf("directory wasn't created: %v", err)
+	}
+	if len(files) != 0 {
+		t.Fatalf("probe file wasn't removed")
+	}
+
+	// No volume has that much space free.
+	if err := validateDataDirs(dirs, math.MaxUint64); err == nil {
+		t.Fatalf("free space check should fail")
+	}
+
+	// A path which can't be created as a directory should be rejected.
+	filePath := filepath.Join(tempDir, "file")
+	if err := ioutil.WriteFile(filePath, nil, 0600); err != nil {
+		t.Fatalf("unable to create file: %v", err)
+	}
+	dirs = []dataDir{{component: "wallet", path: filePath}}
+	if err := validateDataDirs(dirs, 0); err == nil {
+		t.Fatalf("file shouldn't be accepted as a directory")
+	}
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+)
+
+// diskCheckInterval is the time between checks of the free space on the
+// volume of the channel database.
+const diskCheckInterval = time.Minute
+
+// diskMonitor periodically checks the free space on the volume of the channel
+// database. Once it falls below the threshold, non-critical writes to the
+// database are rejected, and the operator is alerted via the notification
+// outbox. A full disk while writing a revocation or close data can leave a
+// channel in an unrecoverable state, so the remaining space is reserved for
+// those writes.
+type diskMonitor struct {
+	started uint32 // To be used atomically.
+	stopped uint32 // To be used atomically.
+
+	// path is a path on the volume of the channel database.
+	path string
+
+	// threshold is the free space in bytes below which non-critical
+	// writes are rejected. A value of zero disables the monitor.
+	threshold uint64
+
+	db     *channeldb.DB
+	outbox *notificationOutbox
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newDiskMonitor creates a new diskMonitor which guards the passed database,
+// residing at path, once its volume has less than threshold bytes free.
+func newDiskMonitor(path string, threshold uint64, db *channeldb.DB,
+	outbox *notificationOutbox) *diskMonitor {
+
+	return &diskMonitor{
+		path:      path,
+		threshold: threshold,
+		db:        db,
+		outbox:    outbox,
+		quit:      make(chan struct{}),
+	}
+}
+
+// Start launches the monitor, if the operator has enabled it.
+func (d *diskMonitor) Start() error {
+	if !atomic.CompareAndSwapUint32(&d.started, 0, 1) {
+		return nil
+	}
+
+	if d.threshold != 0 {
+		ltndLog.Infof("Rejecting non-critical database writes once "+
+			"free space falls below %v MB",
+			d.threshold/bytesPerMegabyte)
+
+		d.wg.Add(1)
+		go d.monitor()
+	}
+
+	return nil
+}
+
+// Stop halts the monitor.
+func (d *diskMonitor) Stop() error {
+	if !atomic.CompareAndSwapUint32(&d.stopped, 0, 1) {
+		return nil
+	}
+
+	close(d.quit)
+	d.wg.Wait()
+
+	return nil
+}
+
+// monitor checks the free space at start up, then each time the check
+// interval passes.
+//
+// NOTE: This MUST be run as a goroutine.
+func (d *diskMonitor) monitor() {
+	defer d.wg.Done()
+
+	ticker := time.NewTicker(diskCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := d.checkFreeSpace(); err != nil {
+			ltndLog.Errorf("unable to check free space of %v: %v",
+				d.path, err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-d.quit:
+			return
+		}
+	}
+}
+
+// checkFreeSpace toggles the rejection of non-critical writes based on the
+// current free space of the database's volume, alerting the operator when
+// they begin to be rejected.
+func (d *diskMonitor) checkFreeSpace() error {
+	free, err := freeSpace(d.path)
+	if err != nil {
+		return err
+	}
+
+	wasLow := d.db.LowDiskSpace()
+	isLow := free < d.threshold
+	d.db.SetLowDiskSpace(isLow)
+
+	switch {
+	case isLow && !wasLow:
+		ltndLog.Criticalf("Only %v MB free on the volume of %v, "+
+			"rejecting gossip, forwarding log, and other "+
+			"non-critical database writes until space is freed",
+			free/bytesPerMegabyte, d.path)
+
+		d.outbox.lowDiskSpace(d.path, free, d.threshold)
+
+	case !isLow && wasLow:
+		ltndLog.Infof("%v MB free on the volume of %v, resuming "+
+			"non-critical database writes", free/bytesPerMegabyte,
+			d.path)
+	}
+
+	return nil
+}
@@ -12,4 +12,5 @@ var localFeatures = lnwire.NewFeatureVector([]lnwire.Feature{
 	{Name: "lcp-stop-and-wait", Flag: lnwire.RequiredFlag},
 	{Name: "48-bit-state-hint", Flag: lnwire.RequiredFlag},
 	{Name: "shachain", Flag: lnwire.RequiredFlag},
+	{Name: peerStorageFeature, Flag: lnwire.OptionalFlag},
 })
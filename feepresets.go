@@ -0,0 +1,155 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lightningnetwork/lnd/routing"
+	"github.com/roasbeef/btcutil"
+)
+
+// feePreset is a named bundle of limits applied to an outgoing payment. Presets
+// allow the operator to define a payment policy once, rather than requiring
+// each client application to re-implement it.
+type feePreset struct {
+	// name is the name clients select the preset by.
+	name string
+
+	// feeRate is the maximum total fee of a payment's route, expressed in
+	// millionths of the payment amount. A value of zero places no limit
+	// on the fee.
+	feeRate uint64
+
+	// cltvLimit is the maximum total time lock of a payment's route. A
+	// value of zero places no limit on the time lock.
+	cltvLimit uint32
+
+	// maxAttempts is the number of times a failed payment will be
+	// attempted.
+	maxAttempts uint32
+
+	// timeout bounds the time spent retrying a failed payment. A value of
+	// zero places no limit on the duration.
+	timeout time.Duration
+}
+
+// applyTo sets the limits of the preset on the passed payment. The fee limit
+// is derived from the payment's amount, which must already be set.
+func (f *feePreset) applyTo(payment *routing.LightningPayment) {
+	if f.feeRate != 0 {
+		feeLimit := uint64(payment.Amount) * f.feeRate / 1000000
+		payment.FeeLimit = btcutil.Amount(feeLimit)
+
+		// A fee limit of zero would be interpreted as no limit at
+		// all, so payments too small to afford any fee at this rate
+		// are still capped at a single satoshi.
+		if payment.FeeLimit == 0 {
+			payment.FeeLimit = 1
+		}
+	}
+	payment.CltvLimit = f.cltvLimit
+	payment.MaxAttempts = f.maxAttempts
+	payment.Timeout = f.timeout
+}
+
+// defaultFeePresets returns the built in presets, which may be overridden by
+// the operator.
+func defaultFeePresets() map[string]*feePreset {
+	return map[string]*feePreset{
+		"economy": {
+			name:        "economy",
+			feeRate:     1000,
+			cltvLimit:   2016,
+			maxAttempts: 10,
+			timeout:     time.Minute * 5,
+		},
+		"normal": {
+			name:        "normal",
+			feeRate:     5000,
+			cltvLimit:   1008,
+			maxAttempts: 3,
+			timeout:     time.Minute,
+		},
+		"urgent": {
+			name:        "urgent",
+			feeRate:     20000,
+			cltvLimit:   432,
+			maxAttempts: 1,
+			timeout:     time.Second * 30,
+		},
+	}
+}
+
+// parseFeePresets parses the operator's fee preset definitions, each of the
+// form name:fee_ppm:cltv_limit:max_attempts:timeout, and merges them with the
+// built in presets. A definition with the name of a built in preset replaces
+// it.
+func parseFeePresets(specs []string) (map[string]*feePreset, error) {
+	presets := defaultFeePresets()
+	for _, spec := range specs {
+		preset, err := parseFeePreset(spec)
+		if err != nil {
+			return nil, err
+		}
+		presets[preset.name] = preset
+	}
+
+	return presets, nil
+}
+
+// parseFeePreset parses a single fee preset definition.
+func parseFeePreset(spec string) (*feePreset, error) {
+	parts := strings.Split(spec, ":")
+	if len(parts) != 5 || parts[0] == "" {
+		return nil, fmt.Errorf("invalid fee preset %q, expected "+
+			"name:fee_ppm:cltv_limit:max_attempts:timeout", spec)
+	}
+
+	feeRate, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid fee rate for fee preset "+
+			"%v: %v", parts[0], err)
+	}
+	cltvLimit, err := strconv.ParseUint(parts[2], 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cltv limit for fee preset "+
+			"%v: %v", parts[0], err)
+	}
+	maxAttempts, err := strconv.ParseUint(parts[3], 10, 32)
+	if err != nil || maxAttempts == 0 {
+		return nil, fmt.Errorf("invalid max attempts for fee preset "+
+			"%v, must be a positive integer", parts[0])
+	}
+	timeout, err := time.ParseDuration(parts[4])
+	if err != nil || timeout < 0 {
+		return nil, fmt.Errorf("invalid timeout for fee preset %v, "+
+			"must be a non-negative duration", parts[0])
+	}
+
+	return &feePreset{
+		name:        parts[0],
+		feeRate:     feeRate,
+		cltvLimit:   uint32(cltvLimit),
+		maxAttempts: uint32(maxAttempts),
+		timeout:     timeout,
+	}, nil
+}
+
+// sortedFeePresets returns the passed presets ordered by name.
+func sortedFeePresets(presets map[string]*feePreset) []*feePreset {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	sorted := make([]*feePreset, 0, len(names))
+	for _, name := range names {
+		sorted = append(sorted, presets[name])
+	}
+
+	return sorted
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/routing"
+	"github.com/roasbeef/btcutil"
+)
+
+// TestParseFeePresets tests that operator defined fee presets are merged with
+// the built in ones, and that invalid definitions are rejected.
+func TestParseFeePresets(t *testing.T) {
+	presets, err := parseFeePresets([]string{
+		"urgent:50000:144:2:10s",
+		"batch:100:4032:20:1h",
+	})
+	if err != nil {
+		t.Fatalf("unable to parse fee presets: %v", err)
+	}
+	if len(presets) != 4 {
+		t.Fatalf("expected 4 presets, got %v", len(presets))
+	}
+
+	urgent := presets["urgent"]
+	if urgent.feeRate != 50000 || urgent.cltvLimit != 144 ||
+		urgent.maxAttempts != 2 || urgent.timeout != time.Second*10 {
+		t.Fatalf("built in preset wasn't replaced: %v", urgent)
+	}
+
+	sorted := sortedFeePresets(presets)
+	names := []string{"batch", "economy", "normal", "urgent"}
+	for i, preset := range sorted {
+		if preset.name != names[i] {
+			t.Fatalf("expected preset %v at index %v, got %v",
+				names[i], i, preset.name)
+		}
+	}
+
+	invalid := []string{
+		"missing:1000:144:2",
+		":1000:144:2:10s",
+		"fee:-1:144:2:10s",
+		"cltv:1000:x:2:10s",
+		"attempts:1000:144:0:10s",
+		"timeout:1000:144:2:-1s",
+	}
+	for _, spec := range invalid {
+		if _, err := parseFeePresets([]string{spec}); err == nil {
+			t.Fatalf("invalid fee preset %q accepted", spec)
+		}
+	}
+}
+
+// TestFeePresetApply tests that a preset's fee rate is converted into a fee
+// limit proportional to the payment's amount.
+func TestFeePresetApply(t *testing.T) {
+	preset := &feePreset{
+		feeRate:     5000,
+		cltvLimit:   1008,
+		maxAttempts: 3,
+		timeout:     time.Minute,
+	}
+
+	tests := []struct {
+		amount   btcutil.Amount
+		feeLimit btcutil.Amount
+	}{
+		{amount: 1000000, feeLimit: 5000},
+		{amount: 1000, feeLimit: 5},
+
+		// Payments too small to afford any fee are still limited.
+		{amount: 100, feeLimit: 1},
+	}
+	for _, test := range tests {
+		payment := &routing.LightningPayment{Amount: test.amount}
+		preset.applyTo(payment)
+
+		if payment.FeeLimit != test.feeLimit {
+			t.Fatalf("expected fee limit of %v for %v, got %v",
+				test.feeLimit, test.amount, payment.FeeLimit)
+		}
+		if payment.CltvLimit != preset.cltvLimit ||
+			payment.MaxAttempts != preset.maxAttempts ||
+			payment.Timeout != preset.timeout {
+			t.Fatalf("preset limits weren't applied: %v", payment)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+// +build !windows
+
+package main
+
+import "syscall"
+
+// freeSpace returns the number of bytes available to unprivileged users on
+// the volume the passed path resides on.
+func freeSpace(path string) (uint64, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, err
+	}
+
+	return uint64(stat.Bavail) * uint64(stat.Bsize), nil
+}
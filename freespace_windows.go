@@ -0,0 +1,31 @@
+package main
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// getDiskFreeSpaceEx is the Windows API call which reports the free space of
+// a volume.
+var getDiskFreeSpaceEx = syscall.NewLazyDLL("kernel32.dll").NewProc(
+	"GetDiskFreeSpaceExW")
+
+// freeSpace returns the number of bytes available to the current user on
+// the volume the passed path resides on.
+func freeSpace(path string) (uint64, error) {
+	pathPtr, err := syscall.UTF16PtrFromString(path)
+	if err != nil {
+		return 0, err
+	}
+
+	var available uint64
+	ret, _, err := getDiskFreeSpaceEx.Call(
+		uintptr(unsafe.Pointer(pathPtr)),
+		uintptr(unsafe.Pointer(&available)), 0, 0,
+	)
+	if ret == 0 {
+		return 0, err
+	}
+
+	return available, nil
+}
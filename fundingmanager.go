@@ -25,8 +25,40 @@ import (
 const (
 	// TODO(roasbeef): tune
 	msgBufferSize = 50
+
+	// anchorCommitmentsFeature is the name of the local feature signalling
+	// support for commitment transactions carrying anchor outputs. Single
+	// funder channels opened between two nodes advertising it use the
+	// anchor commitment format.
+	anchorCommitmentsFeature = "anchor-commitments"
+
+	// anchorCommitmentsFeatureIndex is the index of
+	// anchorCommitmentsFeature within the local feature vector.
+	anchorCommitmentsFeatureIndex = 5
 )
 
+// commitFormat returns the commitment format of single funder channels opened
+// with the passed peer, based on the features shared with it.
+func commitFormat(p *peer) channeldb.CommitmentFormat {
+	if p.localSharedFeatures.IsActive(anchorCommitmentsFeature) {
+		return channeldb.AnchorCommitment
+	}
+
+	return channeldb.LegacyCommitment
+}
+
+// chanReserveForCapacity returns the channel reserve we propose for a channel
+// of the passed capacity: our configured reserve, limited to the largest
+// reserve the remote peer would accept.
+func chanReserveForCapacity(capacity btcutil.Amount) btcutil.Amount {
+	chanReserve := btcutil.Amount(cfg.ChanReserve)
+	if maxReserve := lnwallet.MaxChanReserve(capacity); chanReserve > maxReserve {
+		chanReserve = maxReserve
+	}
+
+	return chanReserve
+}
+
 // reservationWithCtx encapsulates a pending channel reservation. This wrapper
 // struct is used internally within the funding manager to track and progress
 // the funding workflow initiated by incoming/outgoing methods from the target
@@ -85,6 +117,38 @@ type fundingSignCompleteMsg struct {
 	peerAddress *lnwire.NetAddress
 }
 
+// dualFundingRequestMsg couples an lnwire.DualFundingRequest message with the
+// peer who sent the message. This allows the funding manager to queue a
+// response directly to the peer, progressing the dual funder workflow.
+type dualFundingRequestMsg struct {
+	msg         *lnwire.DualFundingRequest
+	peerAddress *lnwire.NetAddress
+}
+
+// dualFundingResponseMsg couples an lnwire.DualFundingResponse message with
+// the peer who sent the message. This allows the funding manager to queue a
+// response directly to the peer, progressing the dual funder workflow.
+type dualFundingResponseMsg struct {
+	msg         *lnwire.DualFundingResponse
+	peerAddress *lnwire.NetAddress
+}
+
+// dualFundingCompleteMsg couples an lnwire.DualFundingComplete message with
+// the peer who sent the message. This allows the funding manager to queue a
+// response directly to the peer, progressing the dual funder workflow.
+type dualFundingCompleteMsg struct {
+	msg         *lnwire.DualFundingComplete
+	peerAddress *lnwire.NetAddress
+}
+
+// dualFundingSignCompleteMsg couples an lnwire.DualFundingSignComplete
+// message with the peer who sent the message. This allows the funding manager
+// to finalize the dual funder workflow.
+type dualFundingSignCompleteMsg struct {
+	msg         *lnwire.DualFundingSignComplete
+	peerAddress *lnwire.NetAddress
+}
+
 // fundingLockedMsg couples an lnwire.FundingLocked message with the peer who
 // sent the message. This allows the funding manager to finalize the funding
 // process and announce the existence of the new channel.
@@ -158,6 +222,20 @@ type fundingConfig struct {
 	// FindChannel queries the database for the channel with the given
 	// funding transaction outpoint.
 	FindChannel func(chanPoint wire.OutPoint) (*lnwallet.LightningChannel, error)
+
+	// UpdateChanBackup requests that the static backup of our channels be
+	// rewritten, so it includes a newly persisted channel.
+	UpdateChanBackup func()
+
+	// AddAliasEdge adds a zero-conf channel to the channel graph under its
+	// alias, so our own path finding may use the channel before its
+	// funding transaction confirms.
+	AddAliasEdge func(*channeldb.ChannelEdgeInfo,
+		...*channeldb.ChannelEdgePolicy) error
+
+	// PeerSettings, if non-nil, returns the settings the operator has set
+	// for the passed peer, or nil if the peer is treated like any other.
+	PeerSettings func(peerKey *btcec.PublicKey) *channeldb.PeerSettings
 }
 
 // fundingManager acts as an orchestrator/bridge between the wallet's
@@ -204,6 +282,11 @@ type fundingManager struct {
 
 	fakeProof *channelProof
 
+	// policy limits the channels our peers may open with us. It's loaded
+	// from the database on start up, and may be replaced at runtime.
+	policyMtx sync.RWMutex
+	policy    channeldb.FundingPolicy
+
 	quit chan struct{}
 	wg   sync.WaitGroup
 }
@@ -245,6 +328,14 @@ func (f *fundingManager) Start() error {
 
 	fndgLog.Tracef("Funding manager running")
 
+	// Load the policy limiting the channels our peers may open with us,
+	// as it was last set.
+	policy, err := f.cfg.Wallet.ChannelDB.FetchFundingPolicy()
+	if err != nil {
+		return err
+	}
+	f.policy = *policy
+
 	// Upon restart, the Funding Manager will check the database to load any
 	// channels that were  waiting for their funding transactions to be
 	// confirmed on the blockchain at the time when the daemon last went
@@ -258,6 +349,7 @@ func (f *fundingManager) Start() error {
 	// last connected, the Funding Manager will re-initialize the channel
 	// barriers and will also launch waitForFundingConfirmation to wait for
 	// the channel's funding transaction to be confirmed on the blockchain.
+	pendingPoints := make(map[wire.OutPoint]struct{})
 	for _, channel := range pendingChannels {
 		f.barrierMtx.Lock()
 		fndgLog.Tracef("Loading pending ChannelPoint(%v), creating chan "+
@@ -265,10 +357,31 @@ func (f *fundingManager) Start() error {
 		f.newChanBarriers[*channel.FundingOutpoint] = make(chan struct{})
 		f.barrierMtx.Unlock()
 
+		pendingPoints[*channel.FundingOutpoint] = struct{}{}
+
 		doneChan := make(chan struct{})
 		go f.waitForFundingConfirmation(channel, doneChan)
 	}
 
+	// Zero-conf channels are opened before their funding transaction
+	// confirms, so we'll also resume waiting on the funding transaction
+	// of each whose alias has yet to be upgraded. Those still pending are
+	// opened, and waited on, by waitForFundingConfirmation above.
+	chanAliases, err := f.cfg.Wallet.ChannelDB.FetchChannelAliases()
+	if err != nil {
+		return err
+	}
+	for _, chanAlias := range chanAliases {
+		if chanAlias.Confirmed() {
+			continue
+		}
+		if _, ok := pendingPoints[chanAlias.ChanPoint]; ok {
+			continue
+		}
+
+		go f.waitForAliasUpgrade(chanAlias.ChanPoint, chanAlias.Alias)
+	}
+
 	f.wg.Add(1) // TODO(roasbeef): tune
 	go f.reservationCoordinator()
 
@@ -357,6 +470,14 @@ func (f *fundingManager) reservationCoordinator() {
 				f.handleFundingComplete(fmsg)
 			case *fundingSignCompleteMsg:
 				f.handleFundingSignComplete(fmsg)
+			case *dualFundingRequestMsg:
+				f.handleDualFundingRequest(fmsg)
+			case *dualFundingResponseMsg:
+				f.handleDualFundingResponse(fmsg)
+			case *dualFundingCompleteMsg:
+				f.handleDualFundingComplete(fmsg)
+			case *dualFundingSignCompleteMsg:
+				f.handleDualFundingSignComplete(fmsg)
 			case *fundingLockedMsg:
 				f.handleFundingLocked(fmsg)
 			case *fundingErrorMsg:
@@ -433,6 +554,120 @@ func (f *fundingManager) processFundingRequest(msg *lnwire.SingleFundingRequest,
 	f.fundingMsgs <- &fundingRequestMsg{msg, peerAddress}
 }
 
+// FundingPolicy returns the policy currently limiting the channels our peers
+// may open with us.
+func (f *fundingManager) FundingPolicy() channeldb.FundingPolicy {
+	f.policyMtx.RLock()
+	defer f.policyMtx.RUnlock()
+
+	return f.policy
+}
+
+// SetFundingPolicy replaces the policy limiting the channels our peers may
+// open with us, persisting it so it remains in effect across restarts. The
+// policy only applies to funding requests received from now on.
+func (f *fundingManager) SetFundingPolicy(policy *channeldb.FundingPolicy) error {
+	f.policyMtx.Lock()
+	defer f.policyMtx.Unlock()
+
+	if err := f.cfg.Wallet.ChannelDB.PutFundingPolicy(policy); err != nil {
+		return err
+	}
+	f.policy = *policy
+
+	fndgLog.Infof("Funding policy updated: %+v", *policy)
+
+	return nil
+}
+
+// checkFundingPolicy checks that a channel of the passed capacity proposed by
+// the target peer is within our funding policy, counting the channels we
+// already maintain with the peer, whether pending or open, along with the
+// reservations of those still being negotiated.
+func (f *fundingManager) checkFundingPolicy(peerKey *btcec.PublicKey,
+	capacity btcutil.Amount) error {
+
+	policy := f.FundingPolicy()
+
+	// The operator may allow particular peers more, or fewer, channels
+	// than any other peer.
+	maxChans := policy.MaxChansPerPeer
+	if settings := f.peerSettings(peerKey); settings != nil &&
+		settings.MaxChannels != 0 {
+
+		maxChans = settings.MaxChannels
+	}
+
+	switch {
+	case policy.MinChanSize != 0 && capacity < policy.MinChanSize:
+		return errors.Errorf("channel capacity of %v is below the "+
+			"minimum of %v", capacity, policy.MinChanSize)
+
+	case policy.MaxChanSize != 0 && capacity > policy.MaxChanSize:
+		return errors.Errorf("channel capacity of %v exceeds the "+
+			"maximum of %v", capacity, policy.MaxChanSize)
+
+	case maxChans == 0 && policy.MaxPeerExposure == 0:
+		return nil
+	}
+
+	channels, err := f.cfg.Wallet.ChannelDB.FetchOpenChannels(peerKey)
+	if err != nil {
+		return err
+	}
+	numChans := uint32(len(channels))
+	exposure := capacity
+	for _, channel := range channels {
+		exposure += channel.Capacity
+	}
+
+	f.resMtx.RLock()
+	for _, resCtx := range f.activeReservations[newSerializedKey(peerKey)] {
+		numChans++
+		exposure += resCtx.reservation.Capacity()
+	}
+	f.resMtx.RUnlock()
+
+	switch {
+	case maxChans != 0 && numChans >= maxChans:
+		return errors.Errorf("already at the maximum of %v channels "+
+			"per peer", maxChans)
+
+	case policy.MaxPeerExposure != 0 && exposure > policy.MaxPeerExposure:
+		return errors.Errorf("total capacity of %v with peer would "+
+			"exceed the maximum of %v", exposure,
+			policy.MaxPeerExposure)
+	}
+
+	return nil
+}
+
+// peerSettings returns the settings the operator has set for the passed peer,
+// or nil if the peer is treated like any other.
+func (f *fundingManager) peerSettings(
+	peerKey *btcec.PublicKey) *channeldb.PeerSettings {
+
+	if f.cfg.PeerSettings == nil {
+		return nil
+	}
+
+	return f.cfg.PeerSettings(peerKey)
+}
+
+// peerFeePolicy returns the routing policy the operator has set for the
+// channels we maintain with the passed peer, or nil if the default policy
+// applies.
+func (f *fundingManager) peerFeePolicy(
+	peerKey *btcec.PublicKey) *channeldb.PeerFeePolicy {
+
+	settings := f.peerSettings(peerKey)
+	if settings == nil {
+		return nil
+	}
+
+	return settings.FeePolicy
+}
+
 // handleFundingRequest creates an initial 'ChannelReservation' within
 // the wallet, then responds to the source peer with a single funder response
 // message progressing the funding workflow.
@@ -499,6 +734,84 @@ func (f *fundingManager) handleFundingRequest(fmsg *fundingRequestMsg) {
 	ourDustLimit := lnwallet.DefaultDustLimit()
 	theirDustlimit := msg.DustLimit
 
+	// Before committing any resources to the channel, ensure that the
+	// dust limit proposed for the remote party's commitment won't allow it
+	// to contain unrelayable outputs, or to trim an excessive amount into
+	// fees.
+	if err := lnwallet.ValidateDustLimit(theirDustlimit); err != nil {
+		fndgLog.Errorf("Rejecting funding request from peer(%x): %v",
+			fmsg.peerAddress.IdentityKey.SerializeCompressed(), err)
+
+		errMsg := &lnwire.ErrorGeneric{
+			ChannelPoint: wire.OutPoint{
+				Hash:  chainhash.Hash{},
+				Index: 0,
+			},
+			Problem:          err.Error(),
+			Code:             lnwire.ErrInvalidDustLimit,
+			PendingChannelID: fmsg.msg.ChannelID,
+		}
+		if err := f.cfg.SendToPeer(fmsg.peerAddress.IdentityKey, errMsg); err != nil {
+			fndgLog.Errorf("unable to send error message to peer %v", err)
+		}
+		return
+	}
+
+	// Likewise, we'll only accept the channel if the parameters proposed
+	// by the initiator are within our policy, otherwise the request is
+	// explicitly rejected so the initiator can cancel its reservation.
+	err = lnwallet.ValidateFundingParams(amt, msg.PushSatoshis,
+		msg.ChannelReserve, delay)
+	if err == nil {
+		err = cfg.CsvDelay.policy.ValidateDelay(delay)
+	}
+	if err == nil {
+		err = f.checkFundingPolicy(fmsg.peerAddress.IdentityKey, amt)
+	}
+	if err != nil {
+		fndgLog.Errorf("Rejecting funding request from peer(%x): %v",
+			fmsg.peerAddress.IdentityKey.SerializeCompressed(), err)
+
+		errMsg := &lnwire.ErrorGeneric{
+			ChannelPoint: wire.OutPoint{
+				Hash:  chainhash.Hash{},
+				Index: 0,
+			},
+			Problem:          err.Error(),
+			Code:             lnwire.ErrChanParamsRejected,
+			PendingChannelID: fmsg.msg.ChannelID,
+		}
+		if err := f.cfg.SendToPeer(fmsg.peerAddress.IdentityKey, errMsg); err != nil {
+			fndgLog.Errorf("unable to send error message to peer %v", err)
+		}
+		return
+	}
+
+	// A zero-conf channel is usable before its funding transaction
+	// confirms, during which the initiator is able to double spend it, so
+	// it's only accepted from peers we trust.
+	if msg.ConfirmationDepth == 0 &&
+		!cfg.ZeroConf.trusts(fmsg.peerAddress.IdentityKey) {
+
+		fndgLog.Errorf("Rejecting zero-conf funding request from "+
+			"untrusted peer(%x)",
+			fmsg.peerAddress.IdentityKey.SerializeCompressed())
+
+		errMsg := &lnwire.ErrorGeneric{
+			ChannelPoint: wire.OutPoint{
+				Hash:  chainhash.Hash{},
+				Index: 0,
+			},
+			Problem:          "zero-conf channels are only accepted from trusted peers",
+			Code:             lnwire.ErrChanParamsRejected,
+			PendingChannelID: fmsg.msg.ChannelID,
+		}
+		if err := f.cfg.SendToPeer(fmsg.peerAddress.IdentityKey, errMsg); err != nil {
+			fndgLog.Errorf("unable to send error message to peer %v", err)
+		}
+		return
+	}
+
 	// Attempt to initialize a reservation within the wallet. If the wallet
 	// has insufficient resources to create the channel, then the reservation
 	// attempt may be rejected. Note that since we're on the responding
@@ -517,6 +830,7 @@ func (f *fundingManager) handleFundingRequest(fmsg *fundingRequestMsg) {
 	}
 
 	reservation.SetTheirDustLimit(theirDustlimit)
+	reservation.SetChanReserve(msg.ChannelReserve)
 
 	// Once the reservation has been created successfully, we add it to
 	// this peers map of pending reservations to track this particular
@@ -539,6 +853,32 @@ func (f *fundingManager) handleFundingRequest(fmsg *fundingRequestMsg) {
 		}
 	}
 
+	// The commitment format is derived from the features we share with
+	// the initiator, who does the same on its end, so both sides agree on
+	// it without further negotiation.
+	peer, err := f.cfg.FindPeer(fmsg.peerAddress.IdentityKey)
+	if err != nil {
+		fndgLog.Errorf("Unable to find peer: %v", err)
+		cancelReservation()
+		return
+	}
+	if err := reservation.SetCommitFormat(commitFormat(peer)); err != nil {
+		fndgLog.Errorf("Unable to set commitment format: %v", err)
+		cancelReservation()
+		return
+	}
+
+	// If the operator has whitelisted the destinations of on-chain funds,
+	// then our balance is delivered to the whitelist upon a cooperative
+	// close.
+	if addr := cfg.sweepWhitelist.destination(); addr != nil {
+		if err := reservation.SetOurDeliveryAddress(addr); err != nil {
+			fndgLog.Errorf("Unable to set delivery address: %v", err)
+			cancelReservation()
+			return
+		}
+	}
+
 	// With our portion of the reservation initialized, process the
 	// initiators contribution to the channel.
 	_, addrs, _, err := txscript.ExtractPkScriptAddrs(msg.DeliveryPkScript, activeNetParams.Params)
@@ -613,8 +953,26 @@ func (f *fundingManager) handleFundingResponse(fmsg *fundingResponseMsg) {
 
 	fndgLog.Infof("Recv'd fundingResponse for pendingID(%v)", msg.ChannelID)
 
+	if err := lnwallet.ValidateDustLimit(msg.DustLimit); err != nil {
+		fndgLog.Errorf("Rejecting funding response from %v: %v",
+			fmsg.peerAddress.IdentityKey, err)
+		cancelReservation()
+		resCtx.err <- err
+		return
+	}
 	resCtx.reservation.SetTheirDustLimit(msg.DustLimit)
 
+	// The delay of the remote party's outputs must leave us enough time
+	// to respond to a breach.
+	err = cfg.CsvDelay.policy.ValidateDelay(msg.CsvDelay)
+	if err != nil {
+		fndgLog.Errorf("Rejecting funding response from %v: %v",
+			fmsg.peerAddress.IdentityKey, err)
+		cancelReservation()
+		resCtx.err <- err
+		return
+	}
+
 	// The remote node has responded with their portion of the channel
 	// contribution. At this point, we can process their contribution which
 	// allows us to construct and sign both the commitment transaction, and
@@ -816,6 +1174,17 @@ func (f *fundingManager) handleFundingSignComplete(fmsg *fundingSignCompleteMsg)
 		return
 	}
 
+	f.trackPendingChannel(resCtx, completeChan, peerKey, chanID)
+}
+
+// trackPendingChannel notifies the local caller which initiated a funding
+// workflow that negotiation with the remote peer is over, then waits for the
+// funding transaction to confirm before notifying the caller that the channel
+// is open.
+func (f *fundingManager) trackPendingChannel(resCtx *reservationWithCtx,
+	completeChan *channeldb.OpenChannel, peerKey *btcec.PublicKey,
+	chanID uint64) {
+
 	fundingPoint := resCtx.reservation.FundingOutpoint()
 	fndgLog.Infof("Finalizing pendingID(%v) over ChannelPoint(%v), "+
 		"waiting for channel open on-chain", chanID, fundingPoint)
@@ -857,7 +1226,7 @@ func (f *fundingManager) handleFundingSignComplete(fmsg *fundingSignCompleteMsg)
 			},
 		}
 
-		f.deleteReservationCtx(peerKey, fmsg.msg.ChannelID)
+		f.deleteReservationCtx(peerKey, chanID)
 	}()
 }
 
@@ -871,6 +1240,17 @@ func (f *fundingManager) waitForFundingConfirmation(
 
 	defer close(doneChan)
 
+	// The channel has been persisted as pending by the time its funding
+	// transaction is awaited, so our static backup must now include it.
+	f.cfg.UpdateChanBackup()
+
+	// A zero-conf channel is opened without awaiting its funding
+	// transaction, so it's usable straight away under an alias.
+	if completeChan.NumConfsRequired == 0 {
+		f.openZeroConfChannel(completeChan)
+		return
+	}
+
 	// Register with the ChainNotifier for a notification once the funding
 	// transaction reaches `numConfs` confirmations.
 	txid := completeChan.FundingOutpoint.Hash
@@ -891,11 +1271,26 @@ func (f *fundingManager) waitForFundingConfirmation(
 	}
 
 	fundingPoint := *completeChan.FundingOutpoint
+
+	// The channel may have been abandoned while its funding transaction
+	// was awaited, in which case it's no longer within the database.
+	abandoned, err := f.cfg.Wallet.ChannelDB.IsChannelClosed(&fundingPoint)
+	if err != nil {
+		fndgLog.Errorf("unable to check closure of ChannelPoint(%v): %v",
+			fundingPoint, err)
+		return
+	}
+	if abandoned {
+		fndgLog.Warnf("Funding tx (%v) of abandoned ChannelPoint(%v) "+
+			"confirmed, channel won't be opened", txid, fundingPoint)
+		return
+	}
+
 	fndgLog.Infof("ChannelPoint(%v) is now active",
 		fundingPoint)
 
 	completeChan.IsPending = false
-	err := f.cfg.Wallet.ChannelDB.MarkChannelAsOpen(&fundingPoint)
+	err = f.cfg.Wallet.ChannelDB.MarkChannelAsOpen(&fundingPoint)
 	if err != nil {
 		fndgLog.Errorf("error setting channel pending flag to false: "+
 			"%v", err)
@@ -904,11 +1299,54 @@ func (f *fundingManager) waitForFundingConfirmation(
 
 	// Finally, create and officially open the payment channel!
 	// TODO(roasbeef): CreationTime once tx is 'open'
+	channel, err := f.activateChannel(completeChan)
+	if err != nil {
+		fndgLog.Errorf("unable to activate ChannelPoint(%v): %v",
+			fundingPoint, err)
+		return
+	}
+
+	// With the block height and the transaction index known, we can
+	// construct the compact chainID which is used on the network to unique
+	// identify channels.
+	// TODO(roasbeef): remove after spec change, no more chanID's!!!
+	chanID := lnwire.ChannelID{
+		BlockHeight: confDetails.BlockHeight,
+		TxIndex:     confDetails.TxIndex,
+		TxPosition:  uint16(fundingPoint.Index),
+	}
+
+	// With the channel finally open, we'll now send over the funding
+	// locked message which marks that we consider the channel open by
+	// presenting the remote party with our next revocation key. Without
+	// the revocation key, the remote party will be unable to propose state
+	// transitions.
+	nextRevocation, err := channel.NextRevocationkey()
+	if err != nil {
+		fndgLog.Errorf("unable to create next revocation: %v", err)
+		return
+	}
+	fundingLockedMsg := lnwire.NewFundingLocked(fundingPoint, chanID,
+		nextRevocation)
+
+	f.cfg.SendToPeer(completeChan.IdentityPub, fundingLockedMsg)
+
+	return
+}
+
+// activateChannel creates the state machine of a newly opened channel, hands
+// it to the peer the channel is open with, and lifts the channel's barrier,
+// so commitment updates may proceed. The breach arbiter is then sent the
+// channel to watch over.
+func (f *fundingManager) activateChannel(
+	completeChan *channeldb.OpenChannel) (*lnwallet.LightningChannel, error) {
+
+	fundingPoint := *completeChan.FundingOutpoint
 	channel, err := lnwallet.NewLightningChannel(f.cfg.Wallet.Signer,
 		f.cfg.Notifier, completeChan)
 	if err != nil {
-		fndgLog.Errorf("error creating new lightning channel: %v", err)
-		return
+		return nil, errors.Errorf("error creating new lightning "+
+			"channel: %v", err)
 	}
 
 	// Now that the channel is open, we need to notify a number of parties
@@ -917,8 +1355,7 @@ func (f *fundingManager) waitForFundingConfirmation(
 	// First we send the newly opened channel to the source peer.
 	peer, err := f.cfg.FindPeer(completeChan.IdentityPub)
 	if err != nil {
-		fndgLog.Errorf("Unable to find peer: %v", err)
-		return
+		return nil, errors.Errorf("unable to find peer: %v", err)
 	}
 
 	newChanDone := make(chan struct{})
@@ -932,7 +1369,7 @@ func (f *fundingManager) waitForFundingConfirmation(
 	// before we close the channel barrier corresponding to the channel.
 	select {
 	case <-f.quit:
-		return
+		return nil, errors.New("funding manager shutting down")
 	case <-newChanDone: // Fallthrough if we're not quitting.
 	}
 
@@ -949,32 +1386,7 @@ func (f *fundingManager) waitForFundingConfirmation(
 	// party.
 	f.cfg.ArbiterChan <- channel
 
-	// With the block height and the transaction index known, we can
-	// construct the compact chainID which is used on the network to unique
-	// identify channels.
-	// TODO(roasbeef): remove after spec change, no more chanID's!!!
-	chanID := lnwire.ChannelID{
-		BlockHeight: confDetails.BlockHeight,
-		TxIndex:     confDetails.TxIndex,
-		TxPosition:  uint16(fundingPoint.Index),
-	}
-
-	// With the channel finally open, we'll now send over the funding
-	// locked message which marks that we consider the channel open by
-	// presenting the remote party with our next revocation key. Without
-	// the revocation key, the remote party will be unable to propose state
-	// transitions.
-	nextRevocation, err := channel.NextRevocationkey()
-	if err != nil {
-		fndgLog.Errorf("unable to create next revocation: %v", err)
-		return
-	}
-	fundingLockedMsg := lnwire.NewFundingLocked(fundingPoint, chanID,
-		nextRevocation)
-
-	f.cfg.SendToPeer(completeChan.IdentityPub, fundingLockedMsg)
-
-	return
+	return channel, nil
 }
 
 // processFundingLocked sends a message to the fundingManager allowing it to finish
@@ -1022,10 +1434,12 @@ type chanAnnouncement struct {
 // channel and contains four signatures binding the funding pub keys and
 // identity pub keys of both parties to the channel, and the second segment is
 // authenticated only by us and contains our directional routing policy for the
-// channel.
+// channel. If feePolicy is non-nil, then it's advertised in place of the
+// default routing policy.
 func newChanAnnouncement(localIdentity, remotePub *btcec.PublicKey,
 	channel *lnwallet.LightningChannel, chanID lnwire.ChannelID,
-	localProof, remoteProof *channelProof) *chanAnnouncement {
+	localProof, remoteProof *channelProof,
+	feePolicy *channeldb.PeerFeePolicy) *chanAnnouncement {
 
 	// The unconditional section of the announcement is the ChannelID
 	// itself which compactly encodes the location of the funding output
@@ -1085,6 +1499,13 @@ func newChanAnnouncement(localIdentity, remotePub *btcec.PublicKey,
 		FeeBaseMsat:               0,
 		FeeProportionalMillionths: 0,
 	}
+	if feePolicy != nil {
+		chanUpdateAnn.TimeLockDelta = feePolicy.TimeLockDelta
+		chanUpdateAnn.HtlcMinimumMsat = uint32(feePolicy.MinHTLC)
+		chanUpdateAnn.FeeBaseMsat = uint32(feePolicy.FeeBaseMSat)
+		chanUpdateAnn.FeeProportionalMillionths = uint32(
+			feePolicy.FeeProportionalMillionths)
+	}
 
 	return &chanAnnouncement{
 		chanAnn:    chanAnn,
@@ -1104,7 +1525,7 @@ func (f *fundingManager) announceChannel(idKey, remoteIDKey *btcec.PublicKey,
 	// TODO(roasbeef): need a Signer.SignMessage method to finalize
 	// advertisements
 	chanAnnouncement := newChanAnnouncement(idKey, remoteIDKey, channel, chanID,
-		localProof, remoteProof)
+		localProof, remoteProof, f.peerFeePolicy(remoteIDKey))
 
 	f.cfg.SendToRouter(chanAnnouncement.chanAnn)
 	f.cfg.SendToRouter(chanAnnouncement.edgeUpdate)
@@ -1126,31 +1547,56 @@ func (f *fundingManager) initFundingWorkflow(peerAddress *lnwire.NetAddress,
 // funding workflow.
 func (f *fundingManager) handleInitFundingMsg(msg *initFundingMsg) {
 	var (
-		// TODO(roasbeef): add delay
 		peerKey      = msg.peerAddress.IdentityKey
 		localAmt     = msg.localFundingAmt
 		remoteAmt    = msg.remoteFundingAmt
 		capacity     = localAmt + remoteAmt
 		numConfs     = msg.numConfs
 		ourDustLimit = lnwallet.DefaultDustLimit()
+		csvDelay     = cfg.CsvDelay.policy.DelayForCapacity(capacity)
 	)
 
+	// If the remote peer is to contribute funds as well, then the channel
+	// is opened via the dual funder workflow instead.
+	if remoteAmt != 0 {
+		f.handleInitDualFundingMsg(msg)
+		return
+	}
+
 	fndgLog.Infof("Initiating fundingRequest(localAmt=%v, remoteAmt=%v, "+
-		"capacity=%v, numConfs=%v, addr=%v, dustLimit=%v)", localAmt,
-		msg.pushAmt, capacity, numConfs, msg.peerAddress.Address,
-		ourDustLimit)
+		"capacity=%v, numConfs=%v, csvDelay=%v, addr=%v, dustLimit=%v)",
+		localAmt, msg.pushAmt, capacity, numConfs, csvDelay,
+		msg.peerAddress.Address, ourDustLimit)
 
 	// Initialize a funding reservation with the local wallet. If the
 	// wallet doesn't have enough funds to commit to this channel, then
 	// the request will fail, and be aborted.
 	reservation, err := f.cfg.Wallet.InitChannelReservation(capacity,
-		localAmt, peerKey, msg.peerAddress.Address, uint16(numConfs), 4,
-		ourDustLimit, msg.pushAmt)
+		localAmt, peerKey, msg.peerAddress.Address, uint16(numConfs),
+		csvDelay, ourDustLimit, msg.pushAmt)
 	if err != nil {
 		msg.err <- err
 		return
 	}
 
+	// If the operator has whitelisted the destinations of on-chain funds,
+	// then our balance is delivered to the whitelist upon a cooperative
+	// close.
+	if addr := cfg.sweepWhitelist.destination(); addr != nil {
+		if err := reservation.SetOurDeliveryAddress(addr); err != nil {
+			reservation.Cancel()
+			msg.err <- err
+			return
+		}
+	}
+
+	// We propose our configured channel reserve to the remote peer,
+	// limited to the largest reserve they'd accept for a channel of this
+	// size. The reserve is recorded within the reservation, so it's
+	// persisted along with the channel once the workflow completes.
+	chanReserve := chanReserveForCapacity(capacity)
+	reservation.SetChanReserve(chanReserve)
+
 	// Obtain a new pending channel ID which is used to track this
 	// reservation throughout its lifetime.
 	peer, err := f.cfg.FindPeer(peerKey)
@@ -1161,6 +1607,14 @@ func (f *fundingManager) handleInitFundingMsg(msg *initFundingMsg) {
 
 	chanID := peer.fetchNextPendingChanID()
 
+	// The anchors are paid for by us as the initiator, so the commitment
+	// format must be set before our balance is sent to the remote peer.
+	if err := reservation.SetCommitFormat(commitFormat(peer)); err != nil {
+		reservation.Cancel()
+		msg.err <- err
+		return
+	}
+
 	// If a pending channel map for this peer isn't already created, then
 	// we create one, ultimately allowing us to track this pending
 	// reservation within the target peer.
@@ -1205,6 +1659,7 @@ func (f *fundingManager) handleInitFundingMsg(msg *initFundingMsg) {
 		ourDustLimit,
 		msg.pushAmt,
 		numConfs,
+		chanReserve,
 	)
 	if err := f.cfg.SendToPeer(peerKey, fundingReq); err != nil {
 		fndgLog.Errorf("Unable to send funding request message: %v", err)
@@ -1213,11 +1668,572 @@ func (f *fundingManager) handleInitFundingMsg(msg *initFundingMsg) {
 	}
 }
 
-// waitUntilChannelOpen is designed to prevent other lnd subsystems from
-// sending new update messages to a channel before the channel is fully
-// opened.
-func (f *fundingManager) waitUntilChannelOpen(targetChan wire.OutPoint) {
-	f.barrierMtx.RLock()
+// handleInitDualFundingMsg creates a dual funder channel reservation within
+// the daemon's wallet, then sends a funding request carrying our inputs and
+// change outputs to the remote peer, kicking off the dual funder workflow.
+func (f *fundingManager) handleInitDualFundingMsg(msg *initFundingMsg) {
+	var (
+		peerKey      = msg.peerAddress.IdentityKey
+		localAmt     = msg.localFundingAmt
+		remoteAmt    = msg.remoteFundingAmt
+		capacity     = localAmt + remoteAmt
+		numConfs     = msg.numConfs
+		ourDustLimit = lnwallet.DefaultDustLimit()
+		csvDelay     = cfg.CsvDelay.policy.DelayForCapacity(capacity)
+	)
+
+	fndgLog.Infof("Initiating dualFundingRequest(localAmt=%v, "+
+		"remoteAmt=%v, capacity=%v, numConfs=%v, csvDelay=%v, "+
+		"addr=%v, dustLimit=%v)", localAmt, remoteAmt, capacity,
+		numConfs, csvDelay, msg.peerAddress.Address, ourDustLimit)
+
+	// Initialize a dual funder reservation with the local wallet, which
+	// selects the inputs funding our contribution to the channel.
+	reservation, err := f.cfg.Wallet.InitDualChannelReservation(localAmt,
+		remoteAmt, true, peerKey, msg.peerAddress.Address,
+		uint16(numConfs), csvDelay, ourDustLimit)
+	if err != nil {
+		msg.err <- err
+		return
+	}
+
+	if addr := cfg.sweepWhitelist.destination(); addr != nil {
+		if err := reservation.SetOurDeliveryAddress(addr); err != nil {
+			reservation.Cancel()
+			msg.err <- err
+			return
+		}
+	}
+
+	chanReserve := chanReserveForCapacity(capacity)
+	reservation.SetChanReserve(chanReserve)
+
+	peer, err := f.cfg.FindPeer(peerKey)
+	if err != nil {
+		reservation.Cancel()
+		msg.err <- err
+		return
+	}
+
+	chanID := peer.fetchNextPendingChanID()
+
+	peerIDKey := newSerializedKey(peerKey)
+	f.resMtx.Lock()
+	if _, ok := f.activeReservations[peerIDKey]; !ok {
+		f.activeReservations[peerIDKey] = make(pendingChannels)
+	}
+	f.activeReservations[peerIDKey][chanID] = &reservationWithCtx{
+		reservation: reservation,
+		peerAddress: msg.peerAddress,
+		updates:     msg.updates,
+		err:         msg.err,
+	}
+	f.resMtx.Unlock()
+
+	contribution := reservation.OurContribution()
+	deliveryScript, err := txscript.PayToAddrScript(contribution.DeliveryAddress)
+	if err != nil {
+		fndgLog.Errorf("Unable to convert address to pkscript: %v", err)
+		f.cancelReservationCtx(peerKey, chanID)
+		msg.err <- err
+		return
+	}
+
+	fndgLog.Infof("Starting dual funding workflow for pendingID(%v)",
+		chanID)
+
+	fundingReq := lnwire.NewDualFundingRequest(
+		chanID,
+		msg.channelType,
+		msg.coinType,
+		0, // TODO(roasbeef): grab from fee estimation model
+		localAmt,
+		remoteAmt,
+		contribution.CsvDelay,
+		contribution.CommitKey,
+		contribution.MultiSigKey,
+		deliveryScript,
+		ourDustLimit,
+		numConfs,
+		chanReserve,
+		contribution.Inputs,
+		contribution.ChangeOutputs,
+	)
+	if err := f.cfg.SendToPeer(peerKey, fundingReq); err != nil {
+		fndgLog.Errorf("Unable to send dual funding request message: %v",
+			err)
+		f.cancelReservationCtx(peerKey, chanID)
+		msg.err <- err
+		return
+	}
+}
+
+// processDualFundingRequest sends a message to the fundingManager allowing it
+// to respond to a request from the source peer to open a dual funded channel.
+func (f *fundingManager) processDualFundingRequest(msg *lnwire.DualFundingRequest,
+	peerAddress *lnwire.NetAddress) {
+	f.fundingMsgs <- &dualFundingRequestMsg{msg, peerAddress}
+}
+
+// sendFundingError rejects a pending funding workflow by sending the remote
+// peer an ErrorGeneric message carrying the passed code and problem.
+func (f *fundingManager) sendFundingError(peerKey *btcec.PublicKey,
+	pendingChanID uint64, code lnwire.ErrorCode, problem string) {
+
+	errMsg := &lnwire.ErrorGeneric{
+		ChannelPoint: wire.OutPoint{
+			Hash:  chainhash.Hash{},
+			Index: 0,
+		},
+		Problem:          problem,
+		Code:             code,
+		PendingChannelID: pendingChanID,
+	}
+	if err := f.cfg.SendToPeer(peerKey, errMsg); err != nil {
+		fndgLog.Errorf("unable to send error message to peer %v", err)
+	}
+}
+
+// handleDualFundingRequest creates a dual funder reservation within the
+// wallet which funds our contribution to the channel, then responds to the
+// source peer with our keys, inputs and change outputs.
+func (f *fundingManager) handleDualFundingRequest(fmsg *dualFundingRequestMsg) {
+	msg := fmsg.msg
+	peerKey := fmsg.peerAddress.IdentityKey
+	peerIDKey := newSerializedKey(peerKey)
+
+	if len(f.activeReservations[peerIDKey]) >= cfg.MaxPendingChannels {
+		f.sendFundingError(peerKey, msg.ChannelID,
+			lnwire.ErrMaxPendingChannels,
+			"Number of pending channels exceed maximum")
+		return
+	}
+
+	isSynced, err := f.cfg.Wallet.IsSynced()
+	if err != nil {
+		fndgLog.Errorf("unable to query wallet: %v", err)
+		return
+	}
+	if !isSynced {
+		f.sendFundingError(peerKey, msg.ChannelID,
+			lnwire.ErrSynchronizingChain, "Synchronizing blockchain")
+		return
+	}
+
+	localAmt := msg.ResponderAmount
+	remoteAmt := msg.FundingAmount
+	capacity := localAmt + remoteAmt
+
+	fndgLog.Infof("Recv'd dualFundingRequest(localAmt=%v, remoteAmt=%v, "+
+		"delay=%v, pendingId=%v) from peer(%x)", localAmt, remoteAmt,
+		msg.CsvDelay, msg.ChannelID, peerKey.SerializeCompressed())
+
+	// Our funds are only committed to a channel the remote peer proposes
+	// if the operator has opted into contributing the amount requested.
+	if localAmt > btcutil.Amount(cfg.MaxDualFundingAmt) {
+		fndgLog.Infof("Declining dual funding request from peer(%x) "+
+			"for %v, our maximum contribution is %v",
+			peerKey.SerializeCompressed(), localAmt,
+			btcutil.Amount(cfg.MaxDualFundingAmt))
+
+		f.sendFundingError(peerKey, msg.ChannelID,
+			lnwire.ErrDualFundingDeclined,
+			"requested contribution exceeds our maximum")
+		return
+	}
+
+	if err := lnwallet.ValidateDustLimit(msg.DustLimit); err != nil {
+		fndgLog.Errorf("Rejecting dual funding request from peer(%x): "+
+			"%v", peerKey.SerializeCompressed(), err)
+		f.sendFundingError(peerKey, msg.ChannelID,
+			lnwire.ErrInvalidDustLimit, err.Error())
+		return
+	}
+
+	err = lnwallet.ValidateFundingParams(capacity, 0, msg.ChannelReserve,
+		msg.CsvDelay)
+	if err == nil {
+		err = cfg.CsvDelay.policy.ValidateDelay(msg.CsvDelay)
+	}
+	if err == nil {
+		err = f.checkFundingPolicy(peerKey, capacity)
+	}
+	if err != nil {
+		fndgLog.Errorf("Rejecting dual funding request from peer(%x): "+
+			"%v", peerKey.SerializeCompressed(), err)
+		f.sendFundingError(peerKey, msg.ChannelID,
+			lnwire.ErrChanParamsRejected, err.Error())
+		return
+	}
+
+	// Attempt to initialize a reservation within the wallet, selecting
+	// the inputs which fund our contribution. If we're unable to fund the
+	// requested amount, then the request is declined.
+	ourDustLimit := lnwallet.DefaultDustLimit()
+	reservation, err := f.cfg.Wallet.InitDualChannelReservation(localAmt,
+		remoteAmt, false, peerKey, fmsg.peerAddress.Address,
+		uint16(msg.ConfirmationDepth), msg.CsvDelay, ourDustLimit)
+	if err != nil {
+		fndgLog.Errorf("Unable to initialize dual reservation: %v", err)
+		f.sendFundingError(peerKey, msg.ChannelID,
+			lnwire.ErrDualFundingDeclined, err.Error())
+		return
+	}
+
+	reservation.SetTheirDustLimit(msg.DustLimit)
+	reservation.SetChanReserve(msg.ChannelReserve)
+
+	f.resMtx.Lock()
+	if _, ok := f.activeReservations[peerIDKey]; !ok {
+		f.activeReservations[peerIDKey] = make(pendingChannels)
+	}
+	f.activeReservations[peerIDKey][msg.ChannelID] = &reservationWithCtx{
+		reservation: reservation,
+		err:         make(chan error, 1),
+		peerAddress: fmsg.peerAddress,
+	}
+	f.resMtx.Unlock()
+
+	cancelReservation := func() {
+		_, err := f.cancelReservationCtx(peerKey, msg.ChannelID)
+		if err != nil {
+			fndgLog.Errorf("unable to cancel reservation: %v", err)
+		}
+	}
+
+	if addr := cfg.sweepWhitelist.destination(); addr != nil {
+		if err := reservation.SetOurDeliveryAddress(addr); err != nil {
+			fndgLog.Errorf("Unable to set delivery address: %v", err)
+			cancelReservation()
+			return
+		}
+	}
+
+	// Record the initiator's contribution, including their inputs and
+	// change outputs. Their revocation key isn't known until they've
+	// assembled the funding transaction, so at this point the wallet only
+	// derives our own revocation key.
+	_, addrs, _, err := txscript.ExtractPkScriptAddrs(msg.DeliveryPkScript,
+		activeNetParams.Params)
+	if err != nil {
+		fndgLog.Errorf("Unable to extract addresses from script: %v", err)
+		cancelReservation()
+		return
+	}
+	contribution := &lnwallet.ChannelContribution{
+		FundingAmount:   remoteAmt,
+		Inputs:          msg.Inputs,
+		ChangeOutputs:   msg.ChangeOutputs,
+		MultiSigKey:     copyPubKey(msg.ChannelDerivationPoint),
+		CommitKey:       copyPubKey(msg.CommitmentKey),
+		DeliveryAddress: addrs[0],
+		CsvDelay:        msg.CsvDelay,
+	}
+	if err := reservation.ProcessSingleContribution(contribution); err != nil {
+		fndgLog.Errorf("unable to add contribution reservation: %v", err)
+		cancelReservation()
+		return
+	}
+
+	fndgLog.Infof("Sending dualFundingResp for pendingID(%v)",
+		msg.ChannelID)
+
+	ourContribution := reservation.OurContribution()
+	deliveryScript, err := txscript.PayToAddrScript(ourContribution.DeliveryAddress)
+	if err != nil {
+		fndgLog.Errorf("unable to convert address to pkscript: %v", err)
+		cancelReservation()
+		return
+	}
+	fundingResp := lnwire.NewDualFundingResponse(msg.ChannelID,
+		ourContribution.RevocationKey, ourContribution.CommitKey,
+		ourContribution.MultiSigKey, ourContribution.CsvDelay,
+		deliveryScript, ourDustLimit, ourContribution.Inputs,
+		ourContribution.ChangeOutputs)
+
+	if err := f.cfg.SendToPeer(peerKey, fundingResp); err != nil {
+		fndgLog.Errorf("unable to send dual funding response to "+
+			"peer: %v", err)
+		cancelReservation()
+		return
+	}
+}
+
+// processDualFundingResponse sends a message to the fundingManager allowing
+// it to continue the second phase of a dual funder workflow with the target
+// peer.
+func (f *fundingManager) processDualFundingResponse(msg *lnwire.DualFundingResponse,
+	peerAddress *lnwire.NetAddress) {
+	f.fundingMsgs <- &dualFundingResponseMsg{msg, peerAddress}
+}
+
+// handleDualFundingResponse processes the remote peer's contribution to a
+// dual funder workflow we initiated. With their inputs known, we assemble the
+// funding transaction, then send the remote peer our signatures for our
+// inputs and their version of the commitment transaction.
+func (f *fundingManager) handleDualFundingResponse(fmsg *dualFundingResponseMsg) {
+	msg := fmsg.msg
+	chanID := msg.ChannelID
+	peerKey := fmsg.peerAddress.IdentityKey
+
+	resCtx, err := f.getReservationCtx(peerKey, chanID)
+	if err != nil {
+		fndgLog.Warnf("Can't find reservation (peerKey:%v, chanID:%v)",
+			peerKey, chanID)
+		return
+	}
+
+	cancelReservation := func() {
+		if _, err := f.cancelReservationCtx(peerKey, chanID); err != nil {
+			fndgLog.Errorf("unable to cancel reservation: %v", err)
+		}
+	}
+
+	fndgLog.Infof("Recv'd dualFundingResponse for pendingID(%v)", chanID)
+
+	if err := lnwallet.ValidateDustLimit(msg.DustLimit); err != nil {
+		fndgLog.Errorf("Rejecting dual funding response from %v: %v",
+			peerKey, err)
+		cancelReservation()
+		resCtx.err <- err
+		return
+	}
+	resCtx.reservation.SetTheirDustLimit(msg.DustLimit)
+
+	// The delay of the remote party's outputs must leave us enough time
+	// to respond to a breach.
+	err = cfg.CsvDelay.policy.ValidateDelay(msg.CsvDelay)
+	if err != nil {
+		fndgLog.Errorf("Rejecting dual funding response from %v: %v",
+			peerKey, err)
+		cancelReservation()
+		resCtx.err <- err
+		return
+	}
+
+	// Processing the remote node's contribution ensures their inputs
+	// fund their share of the channel, then assembles the funding
+	// transaction and signs both our inputs and their commitment.
+	_, addrs, _, err := txscript.ExtractPkScriptAddrs(msg.DeliveryPkScript,
+		activeNetParams.Params)
+	if err != nil {
+		fndgLog.Errorf("Unable to extract addresses from script: %v", err)
+		cancelReservation()
+		resCtx.err <- err
+		return
+	}
+	contribution := &lnwallet.ChannelContribution{
+		Inputs:          msg.Inputs,
+		ChangeOutputs:   msg.ChangeOutputs,
+		MultiSigKey:     copyPubKey(msg.ChannelDerivationPoint),
+		CommitKey:       copyPubKey(msg.CommitmentKey),
+		DeliveryAddress: addrs[0],
+		RevocationKey:   copyPubKey(msg.RevocationKey),
+		CsvDelay:        msg.CsvDelay,
+	}
+	if err := resCtx.reservation.ProcessContribution(contribution); err != nil {
+		fndgLog.Errorf("Unable to process contribution from %v: %v",
+			peerKey, err)
+		cancelReservation()
+		resCtx.err <- err
+		return
+	}
+
+	outPoint := resCtx.reservation.FundingOutpoint()
+	inputScripts, sig := resCtx.reservation.OurSignatures()
+	commitSig, err := btcec.ParseSignature(sig, btcec.S256())
+	if err != nil {
+		fndgLog.Errorf("Unable to parse signature: %v", err)
+		cancelReservation()
+		resCtx.err <- err
+		return
+	}
+
+	f.barrierMtx.Lock()
+	fndgLog.Debugf("Creating chan barrier for "+
+		"ChannelPoint(%v)", outPoint)
+	f.newChanBarriers[*outPoint] = make(chan struct{})
+	f.barrierMtx.Unlock()
+
+	fndgLog.Infof("Generated ChannelPoint(%v) for pendingID(%v)", outPoint,
+		chanID)
+
+	revocationKey := resCtx.reservation.OurContribution().RevocationKey
+	fundingComplete := lnwire.NewDualFundingComplete(chanID, revocationKey,
+		commitSig, toWireInputScripts(inputScripts))
+
+	if err := f.cfg.SendToPeer(peerKey, fundingComplete); err != nil {
+		fndgLog.Errorf("Unable to send dual funding complete message: "+
+			"%v", err)
+		cancelReservation()
+		resCtx.err <- err
+		return
+	}
+}
+
+// processDualFundingComplete queues a dual funding complete message coupled
+// with the source peer to the fundingManager.
+func (f *fundingManager) processDualFundingComplete(msg *lnwire.DualFundingComplete,
+	peerAddress *lnwire.NetAddress) {
+	f.fundingMsgs <- &dualFundingCompleteMsg{msg, peerAddress}
+}
+
+// handleDualFundingComplete progresses the dual funder workflow when we're
+// the responder. Now that the initiator's revocation key is known, we
+// assemble the funding transaction ourselves, verify the initiator's
+// signatures, then send our own signatures so the initiator is also able to
+// broadcast the funding transaction.
+func (f *fundingManager) handleDualFundingComplete(fmsg *dualFundingCompleteMsg) {
+	msg := fmsg.msg
+	chanID := msg.ChannelID
+	peerKey := fmsg.peerAddress.IdentityKey
+
+	resCtx, err := f.getReservationCtx(peerKey, chanID)
+	if err != nil {
+		fndgLog.Warnf("can't find reservation (peerID:%v, chanID:%v)",
+			peerKey, chanID)
+		return
+	}
+
+	cancelReservation := func() {
+		if _, err := f.cancelReservationCtx(peerKey, chanID); err != nil {
+			fndgLog.Errorf("unable to cancel reservation: %v", err)
+		}
+	}
+
+	// Complete the initiator's contribution recorded from their request
+	// with their revocation key, allowing us to construct the funding
+	// transaction and both commitment transactions.
+	theirContribution := *resCtx.reservation.TheirContribution()
+	theirContribution.RevocationKey = copyPubKey(msg.RevocationKey)
+	err = resCtx.reservation.ProcessContribution(&theirContribution)
+	if err != nil {
+		fndgLog.Errorf("unable to process dual contribution: %v", err)
+		cancelReservation()
+		return
+	}
+
+	// With the funding transaction assembled, verify the initiator's
+	// signatures for their inputs and our commitment transaction. Once
+	// verified, the funding transaction is fully signed and broadcast.
+	completeChan, err := resCtx.reservation.CompleteReservation(
+		toWalletInputScripts(msg.FundingInputScripts),
+		msg.CommitSignature.Serialize(),
+	)
+	if err != nil {
+		fndgLog.Errorf("unable to complete dual reservation: %v", err)
+		cancelReservation()
+		return
+	}
+
+	fundingOut := *resCtx.reservation.FundingOutpoint()
+	inputScripts, sig := resCtx.reservation.OurSignatures()
+	ourCommitSig, err := btcec.ParseSignature(sig, btcec.S256())
+	if err != nil {
+		fndgLog.Errorf("unable to parse signature: %v", err)
+		cancelReservation()
+		return
+	}
+
+	f.barrierMtx.Lock()
+	fndgLog.Debugf("Creating chan barrier for "+
+		"ChannelPoint(%v)", fundingOut)
+	f.newChanBarriers[fundingOut] = make(chan struct{})
+	f.barrierMtx.Unlock()
+
+	fndgLog.Infof("sending dualSignComplete for pendingID(%v) over "+
+		"ChannelPoint(%v)", chanID, fundingOut)
+
+	signComplete := lnwire.NewDualFundingSignComplete(chanID, ourCommitSig,
+		toWireInputScripts(inputScripts))
+	if err := f.cfg.SendToPeer(peerKey, signComplete); err != nil {
+		fndgLog.Errorf("unable to send dualSignComplete message: %v",
+			err)
+	}
+
+	// As the channel has been persisted and its funding transaction
+	// broadcast, we await its confirmation even if the message above
+	// couldn't be delivered.
+	go func() {
+		doneChan := make(chan struct{})
+		go f.waitForFundingConfirmation(completeChan, doneChan)
+
+		<-doneChan
+		f.deleteReservationCtx(peerKey, chanID)
+	}()
+}
+
+// processDualFundingSignComplete sends a dual funding sign complete message
+// along with the source peer to the funding manager.
+func (f *fundingManager) processDualFundingSignComplete(msg *lnwire.DualFundingSignComplete,
+	peerAddress *lnwire.NetAddress) {
+	f.fundingMsgs <- &dualFundingSignCompleteMsg{msg, peerAddress}
+}
+
+// handleDualFundingSignComplete processes the final message of a dual funder
+// workflow we initiated. Once the responder's signatures are verified, the
+// funding transaction is broadcast and we await its confirmation.
+func (f *fundingManager) handleDualFundingSignComplete(fmsg *dualFundingSignCompleteMsg) {
+	chanID := fmsg.msg.ChannelID
+	peerKey := fmsg.peerAddress.IdentityKey
+
+	resCtx, err := f.getReservationCtx(peerKey, chanID)
+	if err != nil {
+		fndgLog.Warnf("can't find reservation (peerID:%v, chanID:%v)",
+			peerKey, chanID)
+		return
+	}
+
+	completeChan, err := resCtx.reservation.CompleteReservation(
+		toWalletInputScripts(fmsg.msg.FundingInputScripts),
+		fmsg.msg.CommitSignature.Serialize(),
+	)
+	if err != nil {
+		fndgLog.Errorf("unable to complete dual reservation: %v", err)
+		resCtx.err <- err
+
+		if _, err := f.cancelReservationCtx(peerKey, chanID); err != nil {
+			fndgLog.Errorf("unable to cancel reservation: %v", err)
+		}
+		return
+	}
+
+	f.trackPendingChannel(resCtx, completeChan, peerKey, chanID)
+}
+
+// toWireInputScripts converts the wallet's scripts spending the inputs to a
+// funding transaction into their wire representation.
+func toWireInputScripts(scripts []*lnwallet.InputScript) []*lnwire.FundingInputScript {
+	wireScripts := make([]*lnwire.FundingInputScript, len(scripts))
+	for i, script := range scripts {
+		wireScripts[i] = &lnwire.FundingInputScript{
+			Witness:   script.Witness,
+			ScriptSig: script.ScriptSig,
+		}
+	}
+
+	return wireScripts
+}
+
+// toWalletInputScripts converts scripts spending the inputs to a funding
+// transaction received from the remote peer into the wallet's representation.
+func toWalletInputScripts(scripts []*lnwire.FundingInputScript) []*lnwallet.InputScript {
+	walletScripts := make([]*lnwallet.InputScript, len(scripts))
+	for i, script := range scripts {
+		walletScripts[i] = &lnwallet.InputScript{
+			Witness:   script.Witness,
+			ScriptSig: script.ScriptSig,
+		}
+	}
+
+	return walletScripts
+}
+
+// waitUntilChannelOpen is designed to prevent other lnd subsystems from
+// sending new update messages to a channel before the channel is fully
+// opened.
+func (f *fundingManager) waitUntilChannelOpen(targetChan wire.OutPoint) {
+	f.barrierMtx.RLock()
 	barrier, ok := f.newChanBarriers[targetChan]
 	f.barrierMtx.RUnlock()
 	if ok {
@@ -1251,6 +2267,12 @@ func (f *fundingManager) handleErrorGenericMsg(fmsg *fundingErrorMsg) {
 	case lnwire.ErrMaxPendingChannels:
 		fallthrough
 	case lnwire.ErrSynchronizingChain:
+		fallthrough
+	case lnwire.ErrInvalidDustLimit:
+		fallthrough
+	case lnwire.ErrChanParamsRejected:
+		fallthrough
+	case lnwire.ErrDualFundingDeclined:
 		peerKey := fmsg.peerAddress.IdentityKey
 		chanID := fmsg.err.PendingChannelID
 
@@ -13,6 +13,7 @@ import (
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/sqlstore"
 	"github.com/roasbeef/btcd/btcec"
 	"github.com/roasbeef/btcd/chaincfg/chainhash"
 	"github.com/roasbeef/btcd/wire"
@@ -67,6 +68,23 @@ type htlcPacket struct {
 	preImage chan [32]byte
 
 	err chan error
+
+	// replayed is true if this packet is a settle replayed from a
+	// forwarding intent which wasn't completed prior to a restart. The
+	// HTLC it settles may have already been settled, so a failure to
+	// apply the packet isn't fatal to the link.
+	replayed bool
+
+	// shardOnions, if non-nil, indicates that the destination of this
+	// locally initiated payment is the peer of the target interface. If
+	// none of the interface's links have sufficient bandwidth, then the
+	// payment may be split into shards across several of them, each of
+	// the additional shards using one of these onion blobs.
+	shardOnions [][]byte
+
+	// endorsed is true if the HTLC being added arrived endorsed from a
+	// peer which supports the endorsement of HTLCs.
+	endorsed bool
 }
 
 // circuitKey uniquely identifies an active Sphinx (onion routing) circuit
@@ -103,6 +121,21 @@ type paymentCircuit struct {
 	// complete unless the reference count on the circuit is greater than
 	// 1.
 	settle *link
+
+	// amtIn is the value of the HTLC which arrived over the settle link,
+	// and amtOut is the value of the HTLC forwarded over the clear link.
+	amtIn  btcutil.Amount
+	amtOut btcutil.Amount
+
+	// endorsed is true if the incoming HTLC arrived endorsed, and general
+	// is true if the outgoing HTLC uses the general resources of the
+	// clear link, rather than those reserved for protected HTLCs.
+	endorsed bool
+	general  bool
+
+	// addTime is the time the circuit was created, used to measure how
+	// long the HTLC occupied our channels.
+	addTime time.Time
 }
 
 // htlcSwitch is a central messaging bus for all incoming/outgoing HTLCs.
@@ -119,6 +152,15 @@ type htlcSwitch struct {
 	started  int32 // atomic
 	shutdown int32 // atomic
 
+	// numCorrelatedHTLCs is the number of HTLCs we've refused to forward
+	// as a circuit for their payment hash was already active.
+	numCorrelatedHTLCs uint64 // atomic
+
+	// numHashExposureRejections is the number of HTLCs our links have
+	// rejected for exceeding a channel's maximum exposure to a single
+	// payment hash.
+	numHashExposureRejections uint64 // atomic
+
 	// chanIndex maps a channel's outpoint to a link which contains
 	// additional information about the channel, and additionally houses a
 	// pointer to the peer managing the channel.
@@ -143,6 +185,13 @@ type htlcSwitch struct {
 	// onion routed payments within the network.
 	paymentCircuits map[circuitKey]*paymentCircuit
 
+	// heldShards maps the payment hash of a payment which was split
+	// across several of our channels with the sender to the shards we've
+	// received so far, which are held until they sum to the invoice's
+	// value.
+	heldShardsMtx sync.Mutex
+	heldShards    map[chainhash.Hash]*shardSet
+
 	// linkControl is a channel used by connected links to notify the
 	// switch of a non-multi-hop triggered link state update.
 	linkControl chan interface{}
@@ -158,19 +207,46 @@ type htlcSwitch struct {
 	// in.
 	htlcPlex chan *htlcPacket
 
+	// db is the database which forwarding intents, and the forwarding log
+	// are written to.
+	db *channeldb.DB
+
+	// analytics, if non-nil, is the SQL store that completed forwarding
+	// events are recorded within.
+	analytics *sqlstore.Store
+
+	// asyncHolder, if non-nil, holds HTLCs destined to offline
+	// recipients which have asked us to, rather than failing them.
+	asyncHolder *asyncPaymentHolder
+
+	// jamming, if non-nil, reserves a portion of the resources of each
+	// link for protected HTLCs, failing other HTLCs once the remainder is
+	// exhausted.
+	jamming *jammingMitigator
+
+	// peerSettings, if non-nil, holds the limits the operator has set on
+	// the HTLCs forwarded to particular peers.
+	peerSettings *peerSettingsStore
+
 	// TODO(roasbeef): sampler to log sat/sec and tx/sec
 
 	wg   sync.WaitGroup
 	quit chan struct{}
 }
 
-// newHtlcSwitch creates a new htlcSwitch.
-func newHtlcSwitch() *htlcSwitch {
+// newHtlcSwitch creates a new htlcSwitch. If the passed analytics store is
+// non-nil, then each successfully forwarded HTLC is recorded within it.
+func newHtlcSwitch(db *channeldb.DB,
+	analytics *sqlstore.Store) *htlcSwitch {
+
 	return &htlcSwitch{
+		db:               db,
+		analytics:        analytics,
 		chanIndex:        make(map[wire.OutPoint]*link),
 		interfaces:       make(map[chainhash.Hash][]*link),
 		onionIndex:       make(map[[ripemd160.Size]byte][]*link),
 		paymentCircuits:  make(map[circuitKey]*paymentCircuit),
+		heldShards:       make(map[chainhash.Hash]*shardSet),
 		linkControl:      make(chan interface{}),
 		htlcPlex:         make(chan *htlcPacket, htlcQueueSize),
 		outgoingPayments: make(chan *htlcPacket, htlcQueueSize),
@@ -258,6 +334,9 @@ out:
 			wireMsg := htlcPkt.msg.(*lnwire.UpdateAddHTLC)
 			amt := wireMsg.Amount
 
+			// We vouch for the payments we initiate ourselves.
+			wireMsg.Endorsed = h.jamming != nil
+
 			// Handle this send request in a distinct goroutine in
 			// order to avoid a possible deadlock between the htlc
 			// switch and channel's htlc manager.
@@ -284,6 +363,15 @@ out:
 				continue out
 			}
 
+			// If none of the links can carry the payment on its
+			// own, then it may still be split across them if
+			// we're paying the peer directly.
+			if htlcPkt.shardOnions != nil &&
+				h.sendShards(htlcPkt, chanInterface) {
+
+				continue
+			}
+
 			hswcLog.Errorf("Unable to send payment, insufficient capacity")
 			htlcPkt.preImage <- zeroBytes
 			htlcPkt.err <- fmt.Errorf("Insufficient capacity")
@@ -305,12 +393,26 @@ out:
 
 				// Create the two ends of the payment circuit
 				// required to ensure completion of this new
-				// payment.
+				// payment. The onion names the next node
+				// rather than a channel, so an HTLC routed
+				// over the alias of a zero-conf channel
+				// needs no resolution to its funding
+				// outpoint here.
 				nextHop := pkt.onion.NextHop
 				h.onionMtx.RLock()
 				clearLink, ok := h.onionIndex[nextHop]
 				h.onionMtx.RUnlock()
 				if !ok {
+					// If the next hop is an offline
+					// recipient of asynchronous payments,
+					// then the HTLC is held until it
+					// returns.
+					if h.asyncHolder != nil &&
+						h.asyncHolder.hold(nextHop, pkt) {
+
+						continue
+					}
+
 					hswcLog.Errorf("unable to find dest end of "+
 						"circuit: %x", nextHop)
 
@@ -365,12 +467,97 @@ out:
 					continue
 				}
 
+				// If a circuit for this payment hash is
+				// already active, then the HTLC is correlated
+				// with one we're already forwarding, as seen
+				// when probing or looping payments through
+				// us. As circuits are keyed by payment hash,
+				// we'll cancel the HTLC rather than clobber
+				// the existing circuit.
+				cKey := circuitKey(wireMsg.PaymentHash)
+				if _, ok := h.paymentCircuits[cKey]; ok {
+					hswcLog.Warnf("Rejecting HTLC for %x "+
+						"from link %v, circuit for "+
+						"payment hash already active",
+						cKey[:], settleLink.chanPoint)
+
+					atomic.AddUint64(&h.numCorrelatedHTLCs, 1)
+
+					pkt := &htlcPacket{
+						payHash: payHash,
+						msg: &lnwire.UpdateFailHTLC{
+							Reason: []byte{uint8(lnwire.HashExposureExceeded)},
+						},
+						err: make(chan error, 1),
+					}
+
+					settleLink.linkChan <- pkt
+					continue
+				}
+
+				// The HTLC must also be within any limits the
+				// operator has set for the peer of the clear
+				// link.
+				err := h.checkPeerHTLCLimits(clearLink[0],
+					wireMsg.Amount)
+				if err != nil {
+					hswcLog.Warnf("Rejecting HTLC for %x "+
+						"from link %v: %v", cKey[:],
+						settleLink.chanPoint, err)
+
+					pkt := &htlcPacket{
+						payHash: payHash,
+						msg: &lnwire.UpdateFailHTLC{
+							Reason: []byte{uint8(lnwire.InsufficientCapacity)},
+						},
+						err: make(chan error, 1),
+					}
+
+					settleLink.linkChan <- pkt
+					continue
+				}
+
+				// Unless the HTLC is protected, it must fit
+				// within the general resources of the clear
+				// link. Only protected HTLCs are forwarded
+				// endorsed.
+				var protected bool
+				if h.jamming != nil {
+					var err error
+					protected, err = h.jamming.admit(
+						settleLink.peer.addr.IdentityKey,
+						pkt.endorsed, clearLink[0].chanPoint,
+						clearLink[0].capacity, wireMsg.Amount)
+					if err != nil {
+						hswcLog.Warnf("Rejecting HTLC for "+
+							"%x from link %v: %v",
+							cKey[:], settleLink.chanPoint,
+							err)
+
+						pkt := &htlcPacket{
+							payHash: payHash,
+							msg: &lnwire.UpdateFailHTLC{
+								Reason: []byte{uint8(lnwire.ChannelJammed)},
+							},
+							err: make(chan error, 1),
+						}
+
+						settleLink.linkChan <- pkt
+						continue
+					}
+				}
+				wireMsg.Endorsed = protected
+
 				circuit := &paymentCircuit{
-					clear:  clearLink[0],
-					settle: settleLink,
+					clear:    clearLink[0],
+					settle:   settleLink,
+					amtIn:    pkt.amt,
+					amtOut:   wireMsg.Amount,
+					endorsed: pkt.endorsed,
+					general:  h.jamming != nil && !protected,
+					addTime:  time.Now(),
 				}
 
-				cKey := circuitKey(wireMsg.PaymentHash)
 				h.paymentCircuits[cKey] = circuit
 
 				hswcLog.Debugf("Creating onion circuit for %x: %v<->%v",
@@ -422,9 +609,31 @@ out:
 					circuit.clear.chanPoint,
 					circuit.settle.chanPoint)
 
+				// Before handing the settle to the incoming
+				// link, we write an intent to settle the
+				// incoming HTLC. The intent is only completed
+				// once the settle has been committed within
+				// the incoming channel, so a crash before then
+				// doesn't lose the preimage we've been paid
+				// with on the outgoing channel.
+				intent := &channeldb.ForwardingIntent{
+					IncomingChanPoint: *circuit.settle.chanPoint,
+					OutgoingChanPoint: *circuit.clear.chanPoint,
+					PaymentHash:       rHash,
+					PaymentPreimage:   wireMsg.PaymentPreimage,
+					AmtIn:             circuit.amtIn,
+					AmtOut:            circuit.amtOut,
+					CreationTime:      time.Now(),
+				}
+				if err := h.db.AddForwardingIntent(intent); err != nil {
+					hswcLog.Errorf("unable to write forwarding "+
+						"intent for %x: %v", rHash[:], err)
+				}
+
 				circuit.settle.linkChan <- &htlcPacket{
-					msg: wireMsg,
-					err: make(chan error, 1),
+					msg:     wireMsg,
+					payHash: rHash,
+					err:     make(chan error, 1),
 				}
 
 				// Increase the available bandwidth for the
@@ -439,6 +648,11 @@ out:
 				satSent += pkt.amt
 
 				delete(h.paymentCircuits, cKey)
+				h.circuitResolved(circuit, true)
+
+				if h.analytics != nil {
+					go h.recordForward(circuit)
+				}
 
 			// We've just received an HTLC cancellation triggered
 			// by an upstream peer somewhere within the ultimate
@@ -476,6 +690,7 @@ out:
 				}
 
 				delete(h.paymentCircuits, pkt.payHash)
+				h.circuitResolved(circuit, false)
 			}
 		case <-logTicker.C:
 			if numUpdates == 0 {
@@ -497,6 +712,181 @@ out:
 	h.wg.Done()
 }
 
+// checkPeerHTLCLimits checks that forwarding an HTLC of the passed amount over
+// the clear link is within the limits the operator has set for the link's
+// peer, counting the HTLCs already forwarded to the peer over any of our
+// channels with it.
+func (h *htlcSwitch) checkPeerHTLCLimits(clear *link,
+	amt btcutil.Amount) error {
+
+	if h.peerSettings == nil {
+		return nil
+	}
+
+	peerKey := clear.peer.addr.IdentityKey
+	settings := h.peerSettings.get(peerKey)
+	switch {
+	case settings == nil:
+		return nil
+
+	case settings.MaxHTLCValue != 0 && amt > settings.MaxHTLCValue:
+		return fmt.Errorf("htlc of %v exceeds the maximum of %v set "+
+			"for peer", amt, settings.MaxHTLCValue)
+
+	case settings.MaxPendingHTLCs == 0:
+		return nil
+	}
+
+	var numPending uint32
+	for _, circuit := range h.paymentCircuits {
+		if circuit.clear.peer.addr.IdentityKey.IsEqual(peerKey) {
+			numPending++
+		}
+	}
+	if numPending >= settings.MaxPendingHTLCs {
+		return fmt.Errorf("already at the maximum of %v pending "+
+			"htlcs set for peer", settings.MaxPendingHTLCs)
+	}
+
+	return nil
+}
+
+// circuitResolved releases the general resources used by the passed circuit
+// once its HTLC has been settled or failed, and accounts for the HTLC within
+// the reputation of the upstream peer.
+func (h *htlcSwitch) circuitResolved(circuit *paymentCircuit, settled bool) {
+	if h.jamming == nil {
+		return
+	}
+
+	if circuit.general {
+		h.jamming.release(circuit.clear.chanPoint, circuit.amtOut)
+	}
+
+	h.jamming.resolved(circuit.settle.peer.addr.IdentityKey,
+		circuit.endorsed, settled, circuit.amtIn-circuit.amtOut,
+		time.Since(circuit.addTime))
+}
+
+// resubmitHeld hands an HTLC which was held for an offline recipient back to
+// the switch, in order to forward it now the recipient has returned.
+func (h *htlcSwitch) resubmitHeld(pkt *htlcPacket) {
+	select {
+	case h.htlcPlex <- pkt:
+	case <-h.quit:
+	}
+}
+
+// failHeld fails an HTLC which was held for an offline recipient back to the
+// link it arrived over. It returns false if the link isn't currently active.
+func (h *htlcSwitch) failHeld(pkt *htlcPacket, code lnwire.FailCode) bool {
+	h.chanIndexMtx.RLock()
+	cancelLink, ok := h.chanIndex[pkt.srcLink]
+	h.chanIndexMtx.RUnlock()
+	if !ok {
+		return false
+	}
+
+	cancelPkt := &htlcPacket{
+		payHash: pkt.msg.(*lnwire.UpdateAddHTLC).PaymentHash,
+		msg: &lnwire.UpdateFailHTLC{
+			Reason: []byte{uint8(code)},
+		},
+		err: make(chan error, 1),
+	}
+
+	// The cancel is sent from a distinct goroutine in order to avoid a
+	// possible deadlock between the caller and the channel's htlc
+	// manager.
+	go func() {
+		select {
+		case cancelLink.linkChan <- cancelPkt:
+		case <-h.quit:
+		}
+	}()
+
+	return true
+}
+
+// forwardSettled is called by the incoming link of a forwarded HTLC once the
+// settle of the HTLC has been committed within the channel's state. The
+// forwarding intent of the HTLC is completed, atomically adding the forward
+// to the forwarding log.
+func (h *htlcSwitch) forwardSettled(incoming *wire.OutPoint,
+	payHash [32]byte) {
+
+	event, err := h.db.CompleteForwardingIntent(incoming, payHash,
+		time.Now())
+	switch {
+	// If no intent exists, then it either failed to be written, or
+	// the settle was replayed after the forward had already been
+	// completed.
+	case err == channeldb.ErrForwardingIntentNotFound:
+		hswcLog.Debugf("No forwarding intent for %x on "+
+			"ChannelPoint(%v)", payHash[:], incoming)
+		return
+	case err != nil:
+		hswcLog.Errorf("unable to complete forwarding intent for %x "+
+			"on ChannelPoint(%v): %v", payHash[:], incoming, err)
+		return
+	}
+
+	hswcLog.Debugf("Completed forward of %x: %v<->%v, amt_in=%v, "+
+		"amt_out=%v", payHash[:], event.IncomingChanPoint,
+		event.OutgoingChanPoint, event.AmtIn, event.AmtOut)
+}
+
+// replayForwardingIntents replays the settles of all forwarding intents of
+// the passed incoming link which weren't completed, such as due to a crash
+// after the outgoing HTLC was settled.
+func (h *htlcSwitch) replayForwardingIntents(l *link) {
+	intents, err := h.db.FetchForwardingIntents()
+	if err != nil {
+		hswcLog.Errorf("unable to fetch forwarding intents: %v", err)
+		return
+	}
+
+	for _, intent := range intents {
+		if intent.IncomingChanPoint != *l.chanPoint {
+			continue
+		}
+
+		hswcLog.Infof("Replaying settle of forwarded HTLC %x over "+
+			"ChannelPoint(%v)", intent.PaymentHash[:], l.chanPoint)
+
+		pkt := &htlcPacket{
+			msg: &lnwire.UpdateFufillHTLC{
+				PaymentPreimage: intent.PaymentPreimage,
+			},
+			payHash:  intent.PaymentHash,
+			err:      make(chan error, 1),
+			replayed: true,
+		}
+		select {
+		case l.linkChan <- pkt:
+		case <-h.quit:
+			return
+		}
+	}
+}
+
+// recordForward records the completed payment circuit as a forwarding event
+// within the analytics store.
+func (h *htlcSwitch) recordForward(circuit *paymentCircuit) {
+	event := &sqlstore.ForwardingEvent{
+		Timestamp:         time.Now(),
+		IncomingChanPoint: circuit.settle.chanPoint.String(),
+		OutgoingChanPoint: circuit.clear.chanPoint.String(),
+		IncomingPeer:      circuit.settle.peer.addr.IdentityKey.SerializeCompressed(),
+		OutgoingPeer:      circuit.clear.peer.addr.IdentityKey.SerializeCompressed(),
+		AmtIn:             circuit.amtIn,
+		AmtOut:            circuit.amtOut,
+	}
+	if err := h.analytics.AddForwardingEvent(event); err != nil {
+		hswcLog.Errorf("unable to record forwarding event: %v", err)
+	}
+}
+
 // networkAdmin is responsible for handling requests to register, unregister,
 // and close any link. In the event that an unregister request leaves an
 // interface with no active links, that interface is garbage collected.
@@ -562,6 +952,10 @@ func (h *htlcSwitch) handleRegisterLink(req *registerLinkMsg) {
 		"chan_point=%v, capacity=%v", interfaceID[:], onionID,
 		chanPoint, newLink.capacity)
 
+	// Settles which were pending on this link when we last shut down are
+	// replayed now that it's active again.
+	go h.replayForwardingIntents(newLink)
+
 	if req.done != nil {
 		req.done <- struct{}{}
 	}
@@ -761,16 +1155,23 @@ type closeLinkReq struct {
 
 	chanPoint *wire.OutPoint
 
+	// fee is the fee paid by our version of the cooperative closure
+	// transaction. If zero, then the default fee is paid.
+	fee btcutil.Amount
+
 	updates chan *lnrpc.CloseStatusUpdate
 	err     chan error
 }
 
 // CloseLink closes an active link targetted by its channel point. Closing the
 // link initiates a cooperative channel closure iff forceClose is false. If
-// forceClose is true, then a unilateral channel closure is executed.
+// forceClose is true, then a unilateral channel closure is executed. Our
+// version of a cooperative closure transaction pays the passed fee, or the
+// default fee if it's zero.
 // TODO(roasbeef): consolidate with UnregisterLink?
 func (h *htlcSwitch) CloseLink(chanPoint *wire.OutPoint,
-	closeType LinkCloseType) (chan *lnrpc.CloseStatusUpdate, chan error) {
+	closeType LinkCloseType,
+	fee btcutil.Amount) (chan *lnrpc.CloseStatusUpdate, chan error) {
 
 	updateChan := make(chan *lnrpc.CloseStatusUpdate, 1)
 	errChan := make(chan error, 1)
@@ -778,6 +1179,7 @@ func (h *htlcSwitch) CloseLink(chanPoint *wire.OutPoint,
 	h.linkControl <- &closeLinkReq{
 		CloseType: closeType,
 		chanPoint: chanPoint,
+		fee:       fee,
 		updates:   updateChan,
 		err:       errChan,
 	}
@@ -800,3 +1202,43 @@ type linkInfoUpdateMsg struct {
 func (h *htlcSwitch) UpdateLink(chanPoint *wire.OutPoint, bandwidthDelta btcutil.Amount) {
 	h.linkControl <- &linkInfoUpdateMsg{chanPoint, bandwidthDelta}
 }
+
+// UpdateLinkFee proposes a new commitment fee within the channel of the target
+// link. The fee is only updated if we're the initiator of the channel, and are
+// able to afford the new fee.
+func (h *htlcSwitch) UpdateLinkFee(chanPoint *wire.OutPoint,
+	fee btcutil.Amount) error {
+
+	h.chanIndexMtx.RLock()
+	targetLink, ok := h.chanIndex[*chanPoint]
+	h.chanIndexMtx.RUnlock()
+	if !ok {
+		return fmt.Errorf("channel %v isn't active", chanPoint)
+	}
+
+	feePkt := &htlcPacket{
+		msg: &lnwire.UpdateFee{
+			Fee: fee,
+		},
+		err: make(chan error, 1),
+	}
+
+	select {
+	case targetLink.linkChan <- feePkt:
+	case <-h.quit:
+		return fmt.Errorf("htlc switch was stopped")
+	}
+
+	select {
+	case err := <-feePkt.err:
+		return err
+	case <-h.quit:
+		return fmt.Errorf("htlc switch was stopped")
+	}
+}
+
+// hashExposureRejected records that a link rejected an HTLC for exceeding its
+// channel's maximum exposure to a single payment hash.
+func (h *htlcSwitch) hashExposureRejected() {
+	atomic.AddUint64(&h.numHashExposureRejections, 1)
+}
@@ -0,0 +1,187 @@
+package hwsigner
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcutil/hdkeychain"
+)
+
+// Config describes how to reach a hardware signing device through an
+// HWI-compatible command line bridge.
+type Config struct {
+	// Command is the path to the HWI-compatible executable used to
+	// communicate with the device.
+	Command string
+
+	// DeviceType is the type of the device, such as "trezor" or
+	// "ledger", as understood by the bridge.
+	DeviceType string
+
+	// Fingerprint is the fingerprint of the device's master key, which
+	// selects the device if several are connected.
+	Fingerprint uint32
+
+	// Chain is the name of the chain the device should operate on, such
+	// as "main", "test", or "regtest".
+	Chain string
+}
+
+// Device is a hardware signing device reachable through an HWI-compatible
+// command line bridge. Each request is carried out by invoking the bridge
+// with the device selected by its fingerprint, and decoding its JSON reply.
+type Device struct {
+	cfg Config
+
+	// run executes the bridge with the passed arguments, returning its
+	// standard output.
+	run func(args ...string) ([]byte, error)
+}
+
+// NewDevice creates a new Device which communicates with the hardware device
+// described by the passed config.
+func NewDevice(cfg Config) *Device {
+	d := &Device{cfg: cfg}
+	d.run = d.exec
+	return d
+}
+
+// Fingerprint returns the fingerprint of the device's master key.
+func (d *Device) Fingerprint() uint32 {
+	return d.cfg.Fingerprint
+}
+
+// exec runs the configured bridge executable with the passed arguments,
+// prefixed by the arguments which select the device.
+func (d *Device) exec(args ...string) ([]byte, error) {
+	baseArgs := []string{
+		"--device-type", d.cfg.DeviceType,
+		"--fingerprint", fmt.Sprintf("%08x", d.cfg.Fingerprint),
+	}
+	if d.cfg.Chain != "" {
+		baseArgs = append(baseArgs, "--chain", d.cfg.Chain)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command(d.cfg.Command, append(baseArgs, args...)...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("signing device bridge failed: %v: %s",
+			err, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// call invokes the bridge with the passed arguments and decodes its reply
+// into resp. Errors reported by the bridge within its reply are returned.
+func (d *Device) call(resp interface{}, args ...string) error {
+	out, err := d.run(args...)
+	if err != nil {
+		return err
+	}
+
+	var bridgeErr struct {
+		Error string `json:"error"`
+		Code  int    `json:"code"`
+	}
+	if err := json.Unmarshal(out, &bridgeErr); err != nil {
+		return fmt.Errorf("invalid reply from signing device: %v", err)
+	}
+	if bridgeErr.Error != "" {
+		return fmt.Errorf("signing device error (code %v): %v",
+			bridgeErr.Code, bridgeErr.Error)
+	}
+
+	return json.Unmarshal(out, resp)
+}
+
+// PubKey returns the public key at the passed derivation path within the
+// device's key hierarchy.
+func (d *Device) PubKey(path []uint32) (*btcec.PublicKey, error) {
+	var resp struct {
+		Xpub string `json:"xpub"`
+	}
+	if err := d.call(&resp, "getxpub", FormatPath(path)); err != nil {
+		return nil, err
+	}
+
+	xpub, err := hdkeychain.NewKeyFromString(resp.Xpub)
+	if err != nil {
+		return nil, err
+	}
+
+	return xpub.ECPubKey()
+}
+
+// SignPacket requests that the device sign all inputs of the passed packet
+// it holds keys for, returning the packet augmented with the resulting
+// partial signatures. Depending on the device, the user may be required to
+// confirm the transaction on the device itself.
+func (d *Device) SignPacket(packet *Packet) (*Packet, error) {
+	encoded, err := packet.B64Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Psbt string `json:"psbt"`
+	}
+	if err := d.call(&resp, "signtx", encoded); err != nil {
+		return nil, err
+	}
+
+	return DecodeB64Packet(resp.Psbt)
+}
+
+// ParsePath parses a BIP32 derivation path of the form m/84'/0'/0'/0. Both '
+// and h are accepted as markers of hardened indexes.
+func ParsePath(path string) ([]uint32, error) {
+	elements := strings.Split(path, "/")
+	if len(elements) == 0 || elements[0] != "m" {
+		return nil, fmt.Errorf("derivation path %q must begin with m",
+			path)
+	}
+
+	indexes := make([]uint32, 0, len(elements)-1)
+	for _, element := range elements[1:] {
+		var offset uint32
+		if strings.HasSuffix(element, "'") ||
+			strings.HasSuffix(element, "h") {
+
+			offset = hdkeychain.HardenedKeyStart
+			element = element[:len(element)-1]
+		}
+
+		index, err := strconv.ParseUint(element, 10, 31)
+		if err != nil {
+			return nil, fmt.Errorf("invalid index %q within "+
+				"derivation path %q", element, path)
+		}
+		indexes = append(indexes, uint32(index)+offset)
+	}
+
+	return indexes, nil
+}
+
+// FormatPath formats the passed derivation path in the form accepted by
+// ParsePath.
+func FormatPath(path []uint32) string {
+	elements := []string{"m"}
+	for _, index := range path {
+		if index >= hdkeychain.HardenedKeyStart {
+			elements = append(elements, fmt.Sprintf("%v'",
+				index-hdkeychain.HardenedKeyStart))
+			continue
+		}
+		elements = append(elements, strconv.FormatUint(uint64(index), 10))
+	}
+
+	return strings.Join(elements, "/")
+}
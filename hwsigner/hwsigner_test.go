@@ -0,0 +1,329 @@
+package hwsigner
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/chaincfg"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/txscript"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil/hdkeychain"
+)
+
+var (
+	testSeed = bytes.Repeat([]byte{0x11}, 32)
+
+	testTx = &wire.MsgTx{
+		Version: 2,
+		TxIn: []*wire.TxIn{
+			{
+				PreviousOutPoint: wire.OutPoint{
+					Hash:  chainhash.Hash{0x01},
+					Index: 0,
+				},
+				SignatureScript: []byte{0x51},
+				Witness:         [][]byte{{0x01, 0x02}},
+				Sequence:        0xffffffff,
+			},
+		},
+		TxOut: []*wire.TxOut{
+			{
+				Value:    90000,
+				PkScript: bytes.Repeat([]byte{0x02}, 22),
+			},
+		},
+	}
+)
+
+func TestPacketSerialization(t *testing.T) {
+	packet := NewPacket(testTx)
+
+	// The packet should hold a stripped copy of the transaction, leaving
+	// the original untouched.
+	if packet.UnsignedTx.TxIn[0].SignatureScript != nil ||
+		packet.UnsignedTx.TxIn[0].Witness != nil {
+		t.Fatalf("packet transaction wasn't stripped")
+	}
+	if testTx.TxIn[0].SignatureScript == nil {
+		t.Fatalf("original transaction was modified")
+	}
+
+	packet.Inputs[0] = PInput{
+		WitnessUtxo: &wire.TxOut{
+			Value:    100000,
+			PkScript: bytes.Repeat([]byte{0x03}, 34),
+		},
+		PartialSigs: []*PartialSig{{
+			PubKey:    bytes.Repeat([]byte{0x02}, 33),
+			Signature: []byte{0x30, 0x01, 0x01},
+		}},
+		SighashType:   txscript.SigHashAll,
+		WitnessScript: []byte{0x52, 0xae},
+		Bip32Derivation: []*Bip32Derivation{{
+			PubKey:            bytes.Repeat([]byte{0x03}, 33),
+			MasterFingerprint: 0xdeadbeef,
+			Path:              []uint32{hdkeychain.HardenedKeyStart, 7},
+		}},
+		Unknowns: []*Unknown{{Key: []byte{0xfc}, Value: []byte{0x01}}},
+	}
+	packet.Outputs[0].Unknowns = []*Unknown{
+		{Key: []byte{0x02, 0x01}, Value: []byte{0x02}},
+	}
+
+	encoded, err := packet.B64Encode()
+	if err != nil {
+		t.Fatalf("unable to encode packet: %v", err)
+	}
+	decoded, err := DecodeB64Packet(encoded)
+	if err != nil {
+		t.Fatalf("unable to decode packet: %v", err)
+	}
+	if decoded.UnsignedTx.TxHash() != packet.UnsignedTx.TxHash() {
+		t.Fatalf("decoded transaction doesn't match original")
+	}
+	decoded.UnsignedTx = packet.UnsignedTx
+	if !reflect.DeepEqual(packet, decoded) {
+		t.Fatalf("decoded packet doesn't match original %v vs %v",
+			spew.Sdump(packet), spew.Sdump(decoded))
+	}
+
+	// A packet without the magic bytes should be rejected.
+	if _, err := ParsePacket(bytes.NewReader([]byte("psbx"))); err == nil {
+		t.Fatalf("expected invalid magic bytes to be rejected")
+	}
+}
+
+func TestDerivationPaths(t *testing.T) {
+	tests := []struct {
+		path  string
+		valid bool
+		want  []uint32
+	}{
+		{"m", true, []uint32{}},
+		{"m/1017'/0'/0", true, []uint32{
+			hdkeychain.HardenedKeyStart + 1017,
+			hdkeychain.HardenedKeyStart, 0,
+		}},
+		{"m/1h/2", true, []uint32{hdkeychain.HardenedKeyStart + 1, 2}},
+		{"1/2", false, nil},
+		{"m/x", false, nil},
+		{"m/2147483648", false, nil},
+	}
+
+	for _, test := range tests {
+		path, err := ParsePath(test.path)
+		if !test.valid {
+			if err == nil {
+				t.Fatalf("expected %q to be rejected", test.path)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("unable to parse %q: %v", test.path, err)
+		}
+		if !reflect.DeepEqual(path, test.want) {
+			t.Fatalf("%q parsed as %v, expected %v", test.path,
+				path, test.want)
+		}
+	}
+
+	path := []uint32{hdkeychain.HardenedKeyStart + 1017, 3}
+	if formatted := FormatPath(path); formatted != "m/1017'/3" {
+		t.Fatalf("unexpected formatted path: %v", formatted)
+	}
+}
+
+// mockDevice emulates the bridge of a hardware device holding the keys
+// derived from testSeed.
+type mockDevice struct {
+	t      *testing.T
+	master *hdkeychain.ExtendedKey
+}
+
+func (m *mockDevice) deriveKey(path []uint32) *hdkeychain.ExtendedKey {
+	key := m.master
+	for _, index := range path {
+		var err error
+		key, err = key.Child(index)
+		if err != nil {
+			m.t.Fatalf("unable to derive key: %v", err)
+		}
+	}
+	return key
+}
+
+func (m *mockDevice) run(args ...string) ([]byte, error) {
+	switch args[0] {
+	case "getxpub":
+		path, err := ParsePath(args[1])
+		if err != nil {
+			return json.Marshal(map[string]interface{}{
+				"error": err.Error(), "code": -1,
+			})
+		}
+		xpub, err := m.deriveKey(path).Neuter()
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(map[string]string{"xpub": xpub.String()})
+
+	case "signtx":
+		packet, err := DecodeB64Packet(args[1])
+		if err != nil {
+			return nil, err
+		}
+		sigHashes := txscript.NewTxSigHashes(packet.UnsignedTx)
+		for i := range packet.Inputs {
+			input := &packet.Inputs[i]
+			for _, derivation := range input.Bip32Derivation {
+				privKey, err := m.deriveKey(
+					derivation.Path).ECPrivKey()
+				if err != nil {
+					return nil, err
+				}
+				sig, err := txscript.RawTxInWitnessSignature(
+					packet.UnsignedTx, sigHashes, i,
+					input.WitnessUtxo.Value,
+					input.WitnessScript,
+					input.SighashType, privKey)
+				if err != nil {
+					return nil, err
+				}
+				input.PartialSigs = append(input.PartialSigs,
+					&PartialSig{derivation.PubKey, sig})
+			}
+		}
+		encoded, err := packet.B64Encode()
+		if err != nil {
+			return nil, err
+		}
+		return json.Marshal(map[string]string{"psbt": encoded})
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"error": "unknown command", "code": -2,
+	})
+}
+
+// hostSigner is a signer for keys which aren't held by the device. It
+// records whether it was used.
+type hostSigner struct {
+	called bool
+}
+
+func (h *hostSigner) SignOutputRaw(tx *wire.MsgTx,
+	signDesc *lnwallet.SignDescriptor) ([]byte, error) {
+
+	h.called = true
+	return []byte{0x01}, nil
+}
+
+func (h *hostSigner) ComputeInputScript(tx *wire.MsgTx,
+	signDesc *lnwallet.SignDescriptor) (*lnwallet.InputScript, error) {
+
+	h.called = true
+	return &lnwallet.InputScript{}, nil
+}
+
+func TestSignerDeviceKeys(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "hwsigner")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	db, err := channeldb.Open(tempDir)
+	if err != nil {
+		t.Fatalf("unable to open db: %v", err)
+	}
+	defer db.Close()
+
+	master, err := hdkeychain.NewMaster(testSeed, &chaincfg.TestNet3Params)
+	if err != nil {
+		t.Fatalf("unable to create master key: %v", err)
+	}
+	mock := &mockDevice{t: t, master: master}
+	device := NewDevice(Config{Fingerprint: 0xdeadbeef})
+	device.run = mock.run
+
+	host := &hostSigner{}
+	signer := NewSigner(host, device, db)
+
+	// Funding keys handed out by the key ring should be derived at
+	// successive indexes beneath the base path, and recorded as device
+	// keys.
+	basePath := []uint32{hdkeychain.HardenedKeyStart + 1017}
+	keyRing := NewKeyRing(device, db, basePath)
+	fundingKey, err := keyRing.NewFundingKey()
+	if err != nil {
+		t.Fatalf("unable to create funding key: %v", err)
+	}
+	deviceKey, err := db.FetchDeviceKey(fundingKey)
+	if err != nil {
+		t.Fatalf("funding key wasn't recorded: %v", err)
+	}
+	wantPath := []uint32{hdkeychain.HardenedKeyStart + 1017, 0}
+	if !reflect.DeepEqual(deviceKey.DerivationPath, wantPath) {
+		t.Fatalf("unexpected derivation path: %v",
+			deviceKey.DerivationPath)
+	}
+
+	// A signature for the funding key should be produced by the device,
+	// and be valid for the funding key.
+	_, otherKey := btcec.PrivKeyFromBytes(btcec.S256(), testSeed)
+	witnessScript, err := txscript.NewScriptBuilder().
+		AddOp(txscript.OP_2).
+		AddData(fundingKey.SerializeCompressed()).
+		AddData(otherKey.SerializeCompressed()).
+		AddOp(txscript.OP_2).
+		AddOp(txscript.OP_CHECKMULTISIG).
+		Script()
+	if err != nil {
+		t.Fatalf("unable to create multi-sig script: %v", err)
+	}
+	signDesc := &lnwallet.SignDescriptor{
+		PubKey:        fundingKey,
+		WitnessScript: witnessScript,
+		Output:        &wire.TxOut{Value: 100000},
+		HashType:      txscript.SigHashAll,
+		InputIndex:    0,
+	}
+	rawSig, err := signer.SignOutputRaw(testTx, signDesc)
+	if err != nil {
+		t.Fatalf("unable to sign with device: %v", err)
+	}
+	if host.called {
+		t.Fatalf("device key was signed for by the host")
+	}
+
+	sig, err := btcec.ParseDERSignature(rawSig, btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to parse signature: %v", err)
+	}
+	sigHash, err := txscript.CalcWitnessSigHash(witnessScript,
+		txscript.NewTxSigHashes(testTx), txscript.SigHashAll, testTx,
+		0, 100000)
+	if err != nil {
+		t.Fatalf("unable to compute sighash: %v", err)
+	}
+	if !sig.Verify(sigHash, fundingKey) {
+		t.Fatalf("device signature is invalid")
+	}
+
+	// Signatures for all other keys should be produced by the host.
+	signDesc.PubKey = otherKey
+	if _, err := signer.SignOutputRaw(testTx, signDesc); err != nil {
+		t.Fatalf("unable to sign with host: %v", err)
+	}
+	if !host.called {
+		t.Fatalf("host key wasn't signed for by the host")
+	}
+}
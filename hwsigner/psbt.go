@@ -0,0 +1,398 @@
+package hwsigner
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/roasbeef/btcd/txscript"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// psbtMagic is the magic sequence which prefixes every serialized PSBT as
+// defined within BIP174.
+var psbtMagic = [5]byte{0x70, 0x73, 0x62, 0x74, 0xff}
+
+// maxPsbtValueSize is the largest key or value we'll read from a serialized
+// PSBT, guarding against a malicious or corrupt device response causing an
+// excessive allocation.
+const maxPsbtValueSize = 4000000
+
+// The following are the key types of the PSBT fields we understand. All other
+// fields are retained as unknowns, so they survive a round trip through a
+// signing device untouched.
+const (
+	psbtGlobalUnsignedTx = 0x00
+
+	psbtInWitnessUtxo     = 0x01
+	psbtInPartialSig      = 0x02
+	psbtInSighashType     = 0x03
+	psbtInWitnessScript   = 0x05
+	psbtInBip32Derivation = 0x06
+)
+
+// Unknown is a key-value pair within a PSBT which isn't otherwise parsed.
+type Unknown struct {
+	Key   []byte
+	Value []byte
+}
+
+// PartialSig is a signature for a single input by one of the keys within the
+// input's script.
+type PartialSig struct {
+	// PubKey is the compressed public key the signature is valid for.
+	PubKey []byte
+
+	// Signature is the DER encoded signature, including the trailing
+	// sighash byte.
+	Signature []byte
+}
+
+// Bip32Derivation describes the location of a key within the key hierarchy of
+// the device holding it.
+type Bip32Derivation struct {
+	// PubKey is the compressed public key.
+	PubKey []byte
+
+	// MasterFingerprint is the fingerprint of the master key the key is
+	// derived from.
+	MasterFingerprint uint32
+
+	// Path is the derivation path of the key from the master key.
+	Path []uint32
+}
+
+// PInput holds the fields of a single input within a PSBT.
+type PInput struct {
+	WitnessUtxo     *wire.TxOut
+	PartialSigs     []*PartialSig
+	SighashType     txscript.SigHashType
+	WitnessScript   []byte
+	Bip32Derivation []*Bip32Derivation
+	Unknowns        []*Unknown
+}
+
+// POutput holds the fields of a single output within a PSBT. No output fields
+// are required by the signer, so all fields are retained as unknowns.
+type POutput struct {
+	Unknowns []*Unknown
+}
+
+// Packet is a partially signed bitcoin transaction as defined within BIP174.
+// Only the subset of fields required to request signatures for segwit inputs
+// from a signing device is parsed.
+type Packet struct {
+	// UnsignedTx is the transaction to be signed. Its inputs must not
+	// contain any signature scripts or witnesses.
+	UnsignedTx *wire.MsgTx
+
+	Inputs   []PInput
+	Outputs  []POutput
+	Unknowns []*Unknown
+}
+
+// NewPacket creates a new packet for the passed transaction. Any signature
+// scripts and witnesses within the transaction are stripped from the copy
+// held within the packet.
+func NewPacket(tx *wire.MsgTx) *Packet {
+	unsignedTx := tx.Copy()
+	for _, txIn := range unsignedTx.TxIn {
+		txIn.SignatureScript = nil
+		txIn.Witness = nil
+	}
+
+	return &Packet{
+		UnsignedTx: unsignedTx,
+		Inputs:     make([]PInput, len(unsignedTx.TxIn)),
+		Outputs:    make([]POutput, len(unsignedTx.TxOut)),
+	}
+}
+
+// B64Encode returns the base64 encoding of the serialized packet, which is
+// the format signing devices exchange PSBTs in.
+func (p *Packet) B64Encode() (string, error) {
+	var b bytes.Buffer
+	if err := p.Serialize(&b); err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(b.Bytes()), nil
+}
+
+// Serialize writes the packet to w in the format defined by BIP174.
+func (p *Packet) Serialize(w io.Writer) error {
+	if _, err := w.Write(psbtMagic[:]); err != nil {
+		return err
+	}
+
+	var tx bytes.Buffer
+	if err := p.UnsignedTx.SerializeNoWitness(&tx); err != nil {
+		return err
+	}
+	err := writePsbtPair(w, []byte{psbtGlobalUnsignedTx}, tx.Bytes())
+	if err != nil {
+		return err
+	}
+	if err := writePsbtUnknowns(w, p.Unknowns); err != nil {
+		return err
+	}
+	if _, err := w.Write([]byte{0x00}); err != nil {
+		return err
+	}
+
+	for i := range p.Inputs {
+		if err := p.Inputs[i].serialize(w); err != nil {
+			return err
+		}
+	}
+	for _, output := range p.Outputs {
+		if err := writePsbtUnknowns(w, output.Unknowns); err != nil {
+			return err
+		}
+		if _, err := w.Write([]byte{0x00}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (i *PInput) serialize(w io.Writer) error {
+	if i.WitnessUtxo != nil {
+		var b bytes.Buffer
+		err := wire.WriteTxOut(&b, 0, 0, i.WitnessUtxo)
+		if err != nil {
+			return err
+		}
+		err = writePsbtPair(w, []byte{psbtInWitnessUtxo}, b.Bytes())
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, sig := range i.PartialSigs {
+		key := append([]byte{psbtInPartialSig}, sig.PubKey...)
+		if err := writePsbtPair(w, key, sig.Signature); err != nil {
+			return err
+		}
+	}
+
+	if i.SighashType != 0 {
+		var sigHash [4]byte
+		binary.LittleEndian.PutUint32(sigHash[:], uint32(i.SighashType))
+		err := writePsbtPair(w, []byte{psbtInSighashType}, sigHash[:])
+		if err != nil {
+			return err
+		}
+	}
+
+	if i.WitnessScript != nil {
+		err := writePsbtPair(w, []byte{psbtInWitnessScript},
+			i.WitnessScript)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, derivation := range i.Bip32Derivation {
+		key := append([]byte{psbtInBip32Derivation},
+			derivation.PubKey...)
+		value := make([]byte, 4+4*len(derivation.Path))
+		binary.BigEndian.PutUint32(value[:4],
+			derivation.MasterFingerprint)
+		for j, index := range derivation.Path {
+			binary.LittleEndian.PutUint32(value[4+4*j:], index)
+		}
+		if err := writePsbtPair(w, key, value); err != nil {
+			return err
+		}
+	}
+
+	if err := writePsbtUnknowns(w, i.Unknowns); err != nil {
+		return err
+	}
+	_, err := w.Write([]byte{0x00})
+	return err
+}
+
+// DecodeB64Packet decodes a base64 encoded packet, as returned by a signing
+// device.
+func DecodeB64Packet(encoded string) (*Packet, error) {
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParsePacket(bytes.NewReader(raw))
+}
+
+// ParsePacket reads a packet in the format defined by BIP174 from r.
+func ParsePacket(r io.Reader) (*Packet, error) {
+	var magic [5]byte
+	if _, err := io.ReadFull(r, magic[:]); err != nil {
+		return nil, err
+	}
+	if magic != psbtMagic {
+		return nil, fmt.Errorf("invalid psbt magic bytes")
+	}
+
+	p := &Packet{}
+	err := readPsbtMap(r, func(key, value []byte) error {
+		if len(key) == 1 && key[0] == psbtGlobalUnsignedTx {
+			tx := wire.NewMsgTx(1)
+			err := tx.DeserializeNoWitness(bytes.NewReader(value))
+			if err != nil {
+				return err
+			}
+			p.UnsignedTx = tx
+			return nil
+		}
+
+		p.Unknowns = append(p.Unknowns, &Unknown{key, value})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if p.UnsignedTx == nil {
+		return nil, fmt.Errorf("psbt is missing unsigned transaction")
+	}
+
+	p.Inputs = make([]PInput, len(p.UnsignedTx.TxIn))
+	for i := range p.Inputs {
+		input := &p.Inputs[i]
+		if err := readPsbtMap(r, input.parseField); err != nil {
+			return nil, err
+		}
+	}
+
+	p.Outputs = make([]POutput, len(p.UnsignedTx.TxOut))
+	for i := range p.Outputs {
+		output := &p.Outputs[i]
+		err := readPsbtMap(r, func(key, value []byte) error {
+			output.Unknowns = append(output.Unknowns,
+				&Unknown{key, value})
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return p, nil
+}
+
+func (i *PInput) parseField(key, value []byte) error {
+	switch key[0] {
+	case psbtInWitnessUtxo:
+		if len(value) < 8 {
+			return fmt.Errorf("invalid psbt witness utxo")
+		}
+		pkScript, err := wire.ReadVarBytes(bytes.NewReader(value[8:]),
+			0, maxPsbtValueSize, "pkScript")
+		if err != nil {
+			return err
+		}
+		i.WitnessUtxo = &wire.TxOut{
+			Value:    int64(binary.LittleEndian.Uint64(value[:8])),
+			PkScript: pkScript,
+		}
+
+	case psbtInPartialSig:
+		i.PartialSigs = append(i.PartialSigs, &PartialSig{
+			PubKey:    key[1:],
+			Signature: value,
+		})
+
+	case psbtInSighashType:
+		if len(value) != 4 {
+			return fmt.Errorf("invalid psbt sighash type")
+		}
+		i.SighashType = txscript.SigHashType(
+			binary.LittleEndian.Uint32(value))
+
+	case psbtInWitnessScript:
+		i.WitnessScript = value
+
+	case psbtInBip32Derivation:
+		if len(value) < 4 || len(value)%4 != 0 {
+			return fmt.Errorf("invalid psbt bip32 derivation")
+		}
+		derivation := &Bip32Derivation{
+			PubKey:            key[1:],
+			MasterFingerprint: binary.BigEndian.Uint32(value[:4]),
+			Path:              make([]uint32, len(value)/4-1),
+		}
+		for j := range derivation.Path {
+			derivation.Path[j] = binary.LittleEndian.Uint32(
+				value[4+4*j:])
+		}
+		i.Bip32Derivation = append(i.Bip32Derivation, derivation)
+
+	default:
+		i.Unknowns = append(i.Unknowns, &Unknown{key, value})
+	}
+
+	return nil
+}
+
+// readPsbtMap reads the key-value pairs of a single map within a PSBT,
+// passing each to parseField, until the map's terminating separator is
+// reached.
+func readPsbtMap(r io.Reader, parseField func(key, value []byte) error) error {
+	for {
+		key, err := readPsbtBytes(r)
+		if err != nil {
+			return err
+		}
+		if len(key) == 0 {
+			return nil
+		}
+
+		value, err := readPsbtBytes(r)
+		if err != nil {
+			return err
+		}
+		if err := parseField(key, value); err != nil {
+			return err
+		}
+	}
+}
+
+func readPsbtBytes(r io.Reader) ([]byte, error) {
+	length, err := wire.ReadVarInt(r, 0)
+	if err != nil {
+		return nil, err
+	}
+	if length > maxPsbtValueSize {
+		return nil, fmt.Errorf("psbt field of %v bytes is too large",
+			length)
+	}
+
+	b := make([]byte, length)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+func writePsbtPair(w io.Writer, key, value []byte) error {
+	if err := wire.WriteVarBytes(w, 0, key); err != nil {
+		return err
+	}
+
+	return wire.WriteVarBytes(w, 0, value)
+}
+
+func writePsbtUnknowns(w io.Writer, unknowns []*Unknown) error {
+	for _, unknown := range unknowns {
+		if err := writePsbtPair(w, unknown.Key, unknown.Value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
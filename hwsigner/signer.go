@@ -0,0 +1,164 @@
+package hwsigner
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// KeyRing is an implementation of the lnwallet.FundingKeyRing interface
+// which hands out funding keys held by a hardware device. Each key is derived
+// at a fresh child index beneath a base derivation path, and recorded within
+// the database so signatures can later be requested for it.
+type KeyRing struct {
+	device   *Device
+	db       *channeldb.DB
+	basePath []uint32
+}
+
+// A compile time check to ensure KeyRing implements the
+// lnwallet.FundingKeyRing interface.
+var _ lnwallet.FundingKeyRing = (*KeyRing)(nil)
+
+// NewKeyRing creates a new KeyRing which derives funding keys from the
+// passed device beneath basePath.
+func NewKeyRing(device *Device, db *channeldb.DB, basePath []uint32) *KeyRing {
+	return &KeyRing{
+		device:   device,
+		db:       db,
+		basePath: basePath,
+	}
+}
+
+// NewFundingKey returns a fresh funding key held by the device.
+//
+// This is part of the lnwallet.FundingKeyRing interface.
+func (k *KeyRing) NewFundingKey() (*btcec.PublicKey, error) {
+	index, err := k.db.NextDeviceKeyIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	path := make([]uint32, len(k.basePath), len(k.basePath)+1)
+	copy(path, k.basePath)
+	path = append(path, index)
+
+	pubKey, err := k.device.PubKey(path)
+	if err != nil {
+		return nil, err
+	}
+
+	err = k.db.AddDeviceKey(pubKey, &channeldb.DeviceKey{
+		MasterFingerprint: k.device.Fingerprint(),
+		DerivationPath:    path,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return pubKey, nil
+}
+
+// Signer is an implementation of the lnwallet.Signer interface which routes
+// requests for signatures by device held keys to a hardware device as PSBTs.
+// All other requests, including those for the node's identity and commitment
+// keys, are served by the host's signer.
+type Signer struct {
+	host   lnwallet.Signer
+	device *Device
+	db     *channeldb.DB
+}
+
+// A compile time check to ensure Signer implements the lnwallet.Signer
+// interface.
+var _ lnwallet.Signer = (*Signer)(nil)
+
+// NewSigner creates a new Signer which signs for the device held keys
+// recorded within db with the passed device, and for all other keys with
+// host.
+func NewSigner(host lnwallet.Signer, device *Device,
+	db *channeldb.DB) *Signer {
+
+	return &Signer{
+		host:   host,
+		device: device,
+		db:     db,
+	}
+}
+
+// SignOutputRaw generates a signature for the passed transaction according
+// to the data within the passed SignDescriptor. If the key to sign with is
+// held by the device, then the device is asked to sign the input.
+//
+// This is part of the lnwallet.Signer interface.
+func (s *Signer) SignOutputRaw(tx *wire.MsgTx,
+	signDesc *lnwallet.SignDescriptor) ([]byte, error) {
+
+	deviceKey, err := s.db.FetchDeviceKey(signDesc.PubKey)
+	switch {
+	case err == channeldb.ErrDeviceKeyNotFound:
+		return s.host.SignOutputRaw(tx, signDesc)
+	case err != nil:
+		return nil, err
+	}
+
+	// Device held keys are never tweaked, so a tweaked signature can't
+	// be produced by the device.
+	if signDesc.PrivateTweak != nil {
+		return nil, fmt.Errorf("unable to sign with tweaked device key")
+	}
+	if signDesc.InputIndex >= len(tx.TxIn) {
+		return nil, fmt.Errorf("input index %v out of range",
+			signDesc.InputIndex)
+	}
+
+	pubKey := signDesc.PubKey.SerializeCompressed()
+	packet := NewPacket(tx)
+	packet.Inputs[signDesc.InputIndex] = PInput{
+		WitnessUtxo:   signDesc.Output,
+		SighashType:   signDesc.HashType,
+		WitnessScript: signDesc.WitnessScript,
+		Bip32Derivation: []*Bip32Derivation{{
+			PubKey:            pubKey,
+			MasterFingerprint: deviceKey.MasterFingerprint,
+			Path:              deviceKey.DerivationPath,
+		}},
+	}
+
+	signedPacket, err := s.device.SignPacket(packet)
+	if err != nil {
+		return nil, err
+	}
+	if len(signedPacket.Inputs) != len(packet.Inputs) {
+		return nil, fmt.Errorf("signing device returned a psbt with " +
+			"a different number of inputs")
+	}
+
+	// Locate the device's signature for our key. The sighash byte is
+	// stripped, as the Signer interface returns raw signatures.
+	for _, sig := range signedPacket.Inputs[signDesc.InputIndex].PartialSigs {
+		if !bytes.Equal(sig.PubKey, pubKey) || len(sig.Signature) == 0 {
+			continue
+		}
+
+		return sig.Signature[:len(sig.Signature)-1], nil
+	}
+
+	return nil, fmt.Errorf("signing device didn't sign input %v",
+		signDesc.InputIndex)
+}
+
+// ComputeInputScript generates a complete input script for the passed
+// transaction. As such inputs only ever spend from the wallet's own outputs,
+// the request is always served by the host's signer.
+//
+// This is part of the lnwallet.Signer interface.
+func (s *Signer) ComputeInputScript(tx *wire.MsgTx,
+	signDesc *lnwallet.SignDescriptor) (*lnwallet.InputScript, error) {
+
+	return s.host.ComputeInputScript(tx, signDesc)
+}
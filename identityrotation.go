@@ -0,0 +1,240 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
+)
+
+const (
+	// identityRotationFeature is the name of the local feature advertised
+	// by nodes which carry their channels over to the new identity key of
+	// a peer which has rotated its identity.
+	identityRotationFeature = "identity-rotation"
+
+	// identityRotationFeatureIndex is the index of identityRotationFeature
+	// within the local feature vector.
+	identityRotationFeatureIndex = 7
+)
+
+// identityRotationDigest returns the digest signed by the new identity key of
+// a node rotating its identity from oldPub to newPub.
+func identityRotationDigest(oldPub, newPub *btcec.PublicKey) []byte {
+	msg := append(oldPub.SerializeCompressed(),
+		newPub.SerializeCompressed()...)
+	return chainhash.DoubleHashB(msg)
+}
+
+// rotateIdentity rotates our identity key to the next generation. The
+// rotation proceeds as follows:
+//  * each peer we have channels with is sent the new identity key, so it's
+//    able to carry our channels over to it. The rotation is refused if any
+//    such peer is offline, or doesn't support the rotation of identities, as
+//    our channels with it would be stranded.
+//  * each announced channel is cooperatively closed. The network knows these
+//    channels by our current identity, so they can't be announced anew under
+//    the new one, and their routing policies could be forged by whoever holds
+//    the retired key.
+//  * the new generation is persisted, so the new identity key is used from
+//    the next startup onwards. Upon restarting, our node is announced under
+//    its new identity, and our peers migrate their channels with us as we
+//    reconnect, once their operators confirm the rotation, or their
+//    identity rotation delay elapses.
+//
+// Our own channel records are keyed by the identity of the remote peer, so
+// they carry over to the new identity as is. The new identity key is returned
+// along with the channels being closed.
+func (s *server) rotateIdentity() (*btcec.PublicKey, []wire.OutPoint, error) {
+	gen, err := s.chanDB.FetchIdentityGeneration()
+	if err != nil {
+		return nil, nil, err
+	}
+	newPriv, err := s.lnwallet.DeriveIdentityKey(gen + 1)
+	if err != nil {
+		return nil, nil, err
+	}
+	oldPub, newPub := s.identityPriv.PubKey(), newPriv.PubKey()
+
+	sig, err := newPriv.Sign(identityRotationDigest(oldPub, newPub))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	channels, err := s.chanDB.FetchAllChannels()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Before anything is changed, ensure each peer we have channels with
+	// is able to follow us to our new identity.
+	peers := make(map[string]*peer)
+	for _, channel := range channels {
+		pubStr := string(channel.IdentityPub.SerializeCompressed())
+		if _, ok := peers[pubStr]; ok {
+			continue
+		}
+
+		p, err := s.findPeer(channel.IdentityPub)
+		if err != nil {
+			return nil, nil, fmt.Errorf("peer %x of ChannelPoint(%v) "+
+				"is offline, unable to rotate identity",
+				channel.IdentityPub.SerializeCompressed(),
+				channel.ChanID)
+		}
+		if !p.localSharedFeatures.IsActive(identityRotationFeature) {
+			return nil, nil, fmt.Errorf("peer %v doesn't support "+
+				"identity rotation, close ChannelPoint(%v) "+
+				"first", p, channel.ChanID)
+		}
+		peers[pubStr] = p
+	}
+
+	rotation := &lnwire.IdentityRotation{
+		NewNodeID: newPub,
+		Signature: sig,
+	}
+	for _, p := range peers {
+		done := make(chan struct{}, 1)
+		p.queueMsg(rotation, done)
+		select {
+		case <-done:
+		case <-p.quit:
+			return nil, nil, fmt.Errorf("peer %v disconnected before "+
+				"being notified of identity rotation", p)
+		case <-s.quit:
+			return nil, nil, fmt.Errorf("server shutting down")
+		}
+	}
+
+	graph := s.chanDB.ChannelGraph()
+	var closing []wire.OutPoint
+	for _, channel := range channels {
+		if channel.IsPending {
+			continue
+		}
+		_, err := graph.ChannelID(channel.ChanID)
+		switch {
+		case err == channeldb.ErrEdgeNotFound ||
+			err == channeldb.ErrGraphNoEdgesFound:
+			continue
+		case err != nil:
+			return nil, nil, err
+		}
+
+		srvrLog.Infof("Closing announced ChannelPoint(%v) to rotate "+
+			"identity", channel.ChanID)
+
+		if err := s.advisorCloseChannel(channel.ChanID); err != nil {
+			return nil, nil, fmt.Errorf("unable to close "+
+				"ChannelPoint(%v): %v", channel.ChanID, err)
+		}
+		closing = append(closing, *channel.ChanID)
+	}
+
+	if err := s.chanDB.PutIdentityGeneration(gen + 1); err != nil {
+		return nil, nil, err
+	}
+
+	srvrLog.Infof("Identity rotated from %x to %x, which takes effect upon "+
+		"restart", oldPub.SerializeCompressed(),
+		newPub.SerializeCompressed())
+
+	return newPub, closing, nil
+}
+
+// processIdentityRotation handles the announcement by a peer of its upcoming
+// identity rotation. The rotation is recorded, then applied to our channels
+// with the peer once it connects under its new identity, provided the
+// rotation has since been confirmed, or the identity rotation delay has
+// elapsed.
+func (s *server) processIdentityRotation(p *peer,
+	msg *lnwire.IdentityRotation) {
+
+	if !p.localSharedFeatures.IsActive(identityRotationFeature) {
+		return
+	}
+
+	// The message was received over a connection authenticated by the
+	// peer's current identity, and is signed by the key it's rotating
+	// to, so both keys are held by the peer. Whoever compromised the
+	// current key could announce a rotation to a key of their own though,
+	// so the rotation isn't applied straight away.
+	oldPub := p.addr.IdentityKey
+	digest := identityRotationDigest(oldPub, msg.NewNodeID)
+	if !msg.Signature.Verify(digest, msg.NewNodeID) {
+		peerLog.Warnf("Ignoring identity rotation of %v with invalid "+
+			"signature", p)
+		return
+	}
+
+	err := s.chanDB.PutIdentityRotation(oldPub, msg.NewNodeID, time.Now())
+	if err != nil {
+		peerLog.Errorf("unable to record identity rotation of %v: %v",
+			p, err)
+		return
+	}
+
+	newKey := msg.NewNodeID.SerializeCompressed()
+	if cfg.IdentityRotationDelay == 0 {
+		peerLog.Infof("Peer %v will rotate its identity to %x, which "+
+			"takes effect once confirmed", p, newKey)
+		return
+	}
+	peerLog.Infof("Peer %v will rotate its identity to %x, which takes "+
+		"effect in %v unless confirmed sooner", p, newKey,
+		cfg.IdentityRotationDelay)
+}
+
+// confirmIdentityRotation confirms the pending rotation of a peer's identity
+// to the passed key, which the peer's operator conveyed to us out of band.
+// If the peer is already connected under its new identity, then it's
+// disconnected, so our channels with it are carried over as it reconnects.
+func (s *server) confirmIdentityRotation(newPub *btcec.PublicKey) error {
+	if err := s.chanDB.ConfirmIdentityRotation(newPub); err != nil {
+		return err
+	}
+
+	srvrLog.Infof("Confirmed identity rotation to %x",
+		newPub.SerializeCompressed())
+
+	if p, err := s.findPeer(newPub); err == nil {
+		p.Disconnect()
+	}
+
+	return nil
+}
+
+// applyIdentityRotation carries our channels with a peer connecting under the
+// passed identity key over from its previous identity, if the peer announced
+// such a rotation which has been confirmed, or was announced longer than the
+// identity rotation delay ago.
+func (s *server) applyIdentityRotation(nodePub *btcec.PublicKey) error {
+	// Without a delay, only confirmed rotations are applied, which the
+	// zero time lets through alone.
+	var announcedBefore time.Time
+	if cfg.IdentityRotationDelay != 0 {
+		announcedBefore = time.Now().Add(-cfg.IdentityRotationDelay)
+	}
+
+	oldPub, err := s.chanDB.MigratePeerIdentity(nodePub, announcedBefore)
+	if err != nil {
+		return err
+	}
+	if oldPub == nil {
+		return nil
+	}
+
+	peerLog.Infof("Migrated channels of peer %x to its rotated identity "+
+		"%x", oldPub.SerializeCompressed(), nodePub.SerializeCompressed())
+
+	// The static backup is keyed by the identity of each peer, so it must
+	// reflect the migration.
+	s.chanBackup.requestUpdate()
+
+	return nil
+}
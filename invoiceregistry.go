@@ -8,6 +8,8 @@ import (
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/sqlstore"
 	"github.com/roasbeef/btcd/chaincfg/chainhash"
 	"github.com/roasbeef/btcutil"
 )
@@ -31,6 +33,13 @@ type invoiceRegistry struct {
 
 	cdb *channeldb.DB
 
+	// analytics, if non-nil, is the SQL store that newly added and
+	// settled invoices are mirrored into.
+	analytics *sqlstore.Store
+
+	// outbox, if non-nil, is notified of each settled invoice.
+	outbox *notificationOutbox
+
 	clientMtx           sync.Mutex
 	nextClientID        uint32
 	notificationClients map[uint32]*invoiceSubscription
@@ -44,10 +53,17 @@ type invoiceRegistry struct {
 // newInvoiceRegistry creates a new invoice registry. The invoice registry
 // wraps the persistent on-disk invoice storage with an additional in-memory
 // layer. The in-memory layer is in place such that debug invoices can be added
-// which are volatile yet available system wide within the daemon.
-func newInvoiceRegistry(cdb *channeldb.DB) *invoiceRegistry {
+// which are volatile yet available system wide within the daemon. If the
+// passed analytics store is non-nil, then all invoices are also mirrored into
+// it. Likewise, if the passed outbox is non-nil, then settled invoices are
+// added to it.
+func newInvoiceRegistry(cdb *channeldb.DB, analytics *sqlstore.Store,
+	outbox *notificationOutbox) *invoiceRegistry {
+
 	return &invoiceRegistry{
 		cdb:                 cdb,
+		analytics:           analytics,
+		outbox:              outbox,
 		debugInvoices:       make(map[chainhash.Hash]*channeldb.Invoice),
 		notificationClients: make(map[uint32]*invoiceSubscription),
 	}
@@ -83,17 +99,110 @@ func (i *invoiceRegistry) AddDebugInvoice(amt btcutil.Amount, preimage chainhash
 // daemon add/forward HTLCs are able to obtain the proper preimage required
 // for redemption in the case that we're the final destination.
 func (i *invoiceRegistry) AddInvoice(invoice *channeldb.Invoice) error {
-	ltndLog.Debugf("Adding invoice %v", newLogClosure(func() string {
+	return i.addInvoice(invoice, func() error {
+		return i.cdb.AddInvoice(invoice)
+	})
+}
+
+// AddDerivedInvoice adds a regular invoice for the specified amount, with its
+// preimage derived from the invoice ID it's assigned by the passed deriver.
+// The derived preimage is set within the passed invoice once it's been added.
+func (i *invoiceRegistry) AddDerivedInvoice(invoice *channeldb.Invoice,
+	derive channeldb.PreimageDeriver) error {
+
+	return i.addInvoice(invoice, func() error {
+		return i.cdb.AddDerivedInvoice(invoice, derive)
+	})
+}
+
+// AddOfferInvoice adds a regular invoice created in response to an invoice
+// request for the target offer, incrementing the offer's issued invoice
+// counter along with it.
+func (i *invoiceRegistry) AddOfferInvoice(offerID [32]byte,
+	invoice *channeldb.Invoice) error {
+
+	return i.addInvoice(invoice, func() error {
+		return i.cdb.AddOfferInvoice(offerID, invoice)
+	})
+}
+
+// addInvoice adds the passed invoice to the database using the passed
+// closure, then carries out the bookkeeping common to every invoice added.
+func (i *invoiceRegistry) addInvoice(invoice *channeldb.Invoice,
+	add func() error) error {
+
+	// TODO(roasbeef): also check in memory for quick lookups/settles?
+	if err := add(); err != nil {
+		return err
+	}
+
+	ltndLog.Debugf("Added invoice %v", newLogClosure(func() string {
 		return spew.Sdump(invoice)
 	}))
 
-	// TODO(roasbeef): also check in memory for quick lookups/settles?
-	return i.cdb.AddInvoice(invoice)
+	// The invoice is mirrored into the analytics store before returning,
+	// rather than asynchronously, so its record is always added before
+	// the invoice can be settled.
+	if i.analytics != nil {
+		record := &sqlstore.Invoice{
+			PaymentHash:  sha256.Sum256(invoice.Terms.PaymentPreimage[:]),
+			Memo:         string(invoice.Memo),
+			Value:        invoice.Terms.Value,
+			CreationDate: invoice.CreationDate,
+		}
+		if err := i.analytics.AddInvoice(record); err != nil {
+			ltndLog.Errorf("unable to record invoice in analytics "+
+				"store: %v", err)
+		}
+	}
+
+	return nil
 
 	// TODO(roasbeef): re-enable?
 	//go i.notifyClients(invoice, false)
 }
 
+// backfillAnalytics mirrors each invoice within the database into the
+// analytics store, if it's enabled, so invoices added or settled before the
+// store was enabled are accounted for. Invoices already recorded are left
+// untouched, besides recording any settle which was missed. An invoice
+// settled before settle dates were recorded is taken to have been settled
+// upon its creation.
+func (i *invoiceRegistry) backfillAnalytics() error {
+	if i.analytics == nil {
+		return nil
+	}
+
+	invoices, err := i.cdb.FetchAllInvoices(false)
+	if err != nil {
+		return err
+	}
+
+	for _, invoice := range invoices {
+		record := &sqlstore.Invoice{
+			PaymentHash:  sha256.Sum256(invoice.Terms.PaymentPreimage[:]),
+			Memo:         string(invoice.Memo),
+			Value:        invoice.Terms.Value,
+			CreationDate: invoice.CreationDate,
+		}
+		if invoice.Terms.Settled {
+			record.SettleDate = invoice.SettleDate
+			if record.SettleDate.IsZero() {
+				record.SettleDate = invoice.CreationDate
+			}
+		}
+
+		if err := i.analytics.AddInvoice(record); err != nil {
+			return err
+		}
+	}
+
+	ltndLog.Infof("Mirrored %v invoices into analytics store",
+		len(invoices))
+
+	return nil
+}
+
 // lookupInvoice looks up an invoice by its payment hash (R-Hash), if found
 // then we're able to pull the funds pending within an HTLC.
 // TODO(roasbeef): ignore if settled?
@@ -138,6 +247,29 @@ func (i *invoiceRegistry) SettleInvoice(rHash chainhash.Hash) error {
 		return err
 	}
 
+	// The settle is mirrored into the analytics store before returning,
+	// as was the invoice's addition, so the two are always recorded in
+	// order.
+	if i.analytics != nil {
+		err := i.analytics.SettleInvoice(rHash, time.Now())
+		if err != nil {
+			ltndLog.Errorf("unable to record settled invoice in "+
+				"analytics store: %v", err)
+		}
+	}
+
+	// The outbox is notified before returning, rather than within the
+	// goroutine below, so the notification can't be lost should the
+	// daemon shut down in the meantime.
+	if i.outbox != nil {
+		invoice, err := i.cdb.LookupInvoice(rHash)
+		if err != nil {
+			ltndLog.Errorf("unable to find invoice: %v", err)
+		} else {
+			i.outbox.invoiceSettled(rHash, invoice)
+		}
+	}
+
 	// Launch a new goroutine to notify any/all registered invoice
 	// notification clients.
 	go func() {
@@ -155,6 +287,68 @@ func (i *invoiceRegistry) SettleInvoice(rHash chainhash.Hash) error {
 	return nil
 }
 
+// settleExitHTLC settles the invoice paid by an HTLC for which we're the final
+// destination, then settles the HTLC itself with the passed settleHTLC, along
+// with the passed shards of the payment released by the switch, if non-nil.
+// The invoice is settled before the HTLC, as the invoice may have expired
+// since the HTLC was accepted, such as while its shards were held, in which
+// case the shards are failed, and the error is returned so the HTLC is failed
+// as well. Should the HTLC fail to settle, then the invoice is rolled back,
+// unless it had already been settled, and the shards are failed as well.
+// Otherwise the shards are settled.
+func (i *invoiceRegistry) settleExitHTLC(rHash chainhash.Hash,
+	shards *shardSet, htlcSwitch *htlcSwitch,
+	settleHTLC func() error) error {
+
+	failShards := func() {
+		if shards != nil {
+			htlcSwitch.failShards(rHash, shards,
+				lnwire.UnknownPaymentHash)
+		}
+	}
+
+	// Debug invoices are never settled, so there's nothing to roll back
+	// for them.
+	i.RLock()
+	_, isDebug := i.debugInvoices[rHash]
+	i.RUnlock()
+
+	var wasSettled bool
+	if !isDebug {
+		invoice, err := i.cdb.LookupInvoice(rHash)
+		if err != nil {
+			failShards()
+			return err
+		}
+		wasSettled = invoice.Terms.Settled
+	}
+
+	if err := i.SettleInvoice(rHash); err != nil {
+		failShards()
+		return err
+	}
+
+	if err := settleHTLC(); err != nil {
+		if !isDebug && !wasSettled {
+			ltndLog.Warnf("Rolling back settle of invoice %x: %v",
+				rHash[:], err)
+
+			if err := i.cdb.UnsettleInvoice(rHash); err != nil {
+				ltndLog.Errorf("unable to roll back settle of "+
+					"invoice %x: %v", rHash[:], err)
+			}
+		}
+		failShards()
+		return err
+	}
+
+	if shards != nil {
+		htlcSwitch.settleShards(rHash, shards)
+	}
+
+	return nil
+}
+
 // notifyClients notifies all currently registered invoice notification clients
 // of a newly added/settled invoice.
 func (i *invoiceRegistry) notifyClients(invoice *channeldb.Invoice, settle bool) {
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// TestSettleExitHTLCExpiry tests that a payment whose held shards are only
+// completed once its invoice has expired is failed without revealing the
+// preimage, and leaves the invoice unsettled, while a payment completed in
+// time settles both the invoice and its held shards. Should the HTLC
+// completing a payment fail to settle, then the invoice is rolled back.
+func TestSettleExitHTLCExpiry(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "invoiceregistry")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	db, err := channeldb.Open(tempDir)
+	if err != nil {
+		t.Fatalf("unable to open db: %v", err)
+	}
+	defer db.Close()
+
+	registry := newInvoiceRegistry(db, nil, nil)
+	htlcSwitch := newHtlcSwitch(db, nil)
+
+	// The first shard of each payment is held within a channel whose link
+	// receives the packet resolving it, while the second completes the
+	// payment within another.
+	heldChan := wire.OutPoint{Index: 1}
+	heldLink := &link{
+		linkChan:  make(chan *htlcPacket, 1),
+		chanPoint: &heldChan,
+	}
+	htlcSwitch.chanIndex[heldChan] = heldLink
+	completingChan := wire.OutPoint{Index: 2}
+
+	const expiry = 100 * time.Millisecond
+	pay := func() *channeldb.Invoice {
+		invoice := &channeldb.Invoice{
+			CreationDate: time.Now(),
+			Expiry:       time.Now().Add(expiry),
+		}
+		invoice.Terms.Value = 2000
+		if _, err := rand.Read(invoice.Terms.PaymentPreimage[:]); err != nil {
+			t.Fatalf("unable to create preimage: %v", err)
+		}
+		if err := registry.AddInvoice(invoice); err != nil {
+			t.Fatalf("unable to add invoice: %v", err)
+		}
+		payHash := chainhash.Hash(sha256.Sum256(
+			invoice.Terms.PaymentPreimage[:]))
+
+		// The first shard is accepted while the invoice has yet to
+		// expire.
+		if invoice.IsExpired(time.Now()) {
+			t.Fatalf("invoice expired before its shard was accepted")
+		}
+		shards, err := htlcSwitch.holdShard(payHash, invoice, heldChan,
+			1000)
+		if err != nil || shards != nil {
+			t.Fatalf("expected shard to be held, got %v: %v",
+				shards, err)
+		}
+
+		return invoice
+	}
+
+	complete := func(invoice *channeldb.Invoice,
+		settleHTLC func() error) (*htlcPacket, error) {
+
+		payHash := chainhash.Hash(sha256.Sum256(
+			invoice.Terms.PaymentPreimage[:]))
+		shards, err := htlcSwitch.holdShard(payHash, invoice,
+			completingChan, 1000)
+		if err != nil || shards == nil {
+			t.Fatalf("expected shards to be released, got %v: %v",
+				shards, err)
+		}
+
+		settleErr := registry.settleExitHTLC(payHash, shards, htlcSwitch,
+			settleHTLC)
+
+		select {
+		case pkt := <-heldLink.linkChan:
+			return pkt, settleErr
+		case <-time.After(time.Second):
+			t.Fatalf("held shard wasn't resolved")
+		}
+		return nil, nil
+	}
+
+	// A payment completed before the invoice expires should settle the
+	// invoice, and reveal its preimage to the held shard.
+	invoice := pay()
+	settleHTLC := func() error { return nil }
+	pkt, err := complete(invoice, settleHTLC)
+	if err != nil {
+		t.Fatalf("unable to settle payment: %v", err)
+	}
+	settle, ok := pkt.msg.(*lnwire.UpdateFufillHTLC)
+	if !ok || settle.PaymentPreimage != invoice.Terms.PaymentPreimage {
+		t.Fatalf("expected held shard to be settled, got %v", pkt.msg)
+	}
+	payHash := sha256.Sum256(invoice.Terms.PaymentPreimage[:])
+	dbInvoice, err := db.LookupInvoice(payHash)
+	if err != nil {
+		t.Fatalf("unable to find invoice: %v", err)
+	}
+	if !dbInvoice.Terms.Settled {
+		t.Fatalf("invoice should be settled")
+	}
+
+	// A payment whose shards are only completed once the invoice has
+	// expired should be failed, along with the held shard, while the
+	// invoice remains unsettled.
+	invoice = pay()
+	time.Sleep(expiry)
+	pkt, err = complete(invoice, settleHTLC)
+	if err != channeldb.ErrInvoiceExpired {
+		t.Fatalf("expected expired invoice to be rejected, got: %v",
+			err)
+	}
+	fail, ok := pkt.msg.(*lnwire.UpdateFailHTLC)
+	if !ok || lnwire.FailCode(fail.Reason[0]) != lnwire.UnknownPaymentHash {
+		t.Fatalf("expected held shard to be failed, got %v", pkt.msg)
+	}
+	payHash = sha256.Sum256(invoice.Terms.PaymentPreimage[:])
+	dbInvoice, err = db.LookupInvoice(payHash)
+	if err != nil {
+		t.Fatalf("unable to find invoice: %v", err)
+	}
+	if dbInvoice.Terms.Settled {
+		t.Fatalf("expired invoice shouldn't be settled")
+	}
+
+	// A payment whose completing HTLC fails to settle should be failed,
+	// along with the held shard, and its invoice rolled back.
+	invoice = pay()
+	htlcErr := fmt.Errorf("htlc not found")
+	pkt, err = complete(invoice, func() error { return htlcErr })
+	if err != htlcErr {
+		t.Fatalf("expected htlc settle error, got: %v", err)
+	}
+	fail, ok = pkt.msg.(*lnwire.UpdateFailHTLC)
+	if !ok {
+		t.Fatalf("expected held shard to be failed, got %v", pkt.msg)
+	}
+	payHash = sha256.Sum256(invoice.Terms.PaymentPreimage[:])
+	dbInvoice, err = db.LookupInvoice(payHash)
+	if err != nil {
+		t.Fatalf("unable to find invoice: %v", err)
+	}
+	if dbInvoice.Terms.Settled || !dbInvoice.SettleDate.IsZero() {
+		t.Fatalf("invoice settle should have been rolled back")
+	}
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/roasbeef/btcutil"
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// invoiceQRSize is the width and height in pixels of the QR codes rendered
+// for invoices.
+const invoiceQRSize = 512
+
+// unifiedInvoiceURI returns a BIP21 URI which allows the invoice with the
+// passed payment request to be paid either over Lightning, or on-chain to the
+// fallback address by wallets which don't support Lightning. If no fallback
+// address is given, then a plain lightning URI is returned.
+//
+// NOTE: Payments to the fallback address aren't matched to the invoice, so
+// the invoice remains unsettled if it's paid on-chain.
+func unifiedInvoiceURI(payReq string, fallback btcutil.Address,
+	amt btcutil.Amount, memo string) string {
+
+	if fallback == nil {
+		return "lightning:" + payReq
+	}
+
+	// BIP21 amounts are denominated in whole bitcoin, and written without
+	// trailing zeroes.
+	params := []string{
+		"amount=" + strconv.FormatFloat(amt.ToBTC(), 'f', -1, 64),
+	}
+	if memo != "" {
+		params = append(params, "label="+bip21Escape(memo))
+	}
+	params = append(params, "lightning="+payReq)
+
+	return "bitcoin:" + fallback.EncodeAddress() + "?" +
+		strings.Join(params, "&")
+}
+
+// bip21Escape escapes the passed string for use as the value of a BIP21 query
+// parameter. Spaces are percent encoded, as not all wallets decode a plus as
+// a space.
+func bip21Escape(s string) string {
+	return strings.Replace(url.QueryEscape(s), "+", "%20", -1)
+}
+
+// invoiceQRCode renders the passed invoice URI as a QR code, returning the
+// image PNG encoded.
+func invoiceQRCode(uri string) ([]byte, error) {
+	return qrcode.Encode(uri, qrcode.Medium, invoiceQRSize)
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/roasbeef/btcd/chaincfg"
+	"github.com/roasbeef/btcutil"
+)
+
+func TestUnifiedInvoiceURI(t *testing.T) {
+	const payReq = "yxf5kgrtfwdrfqc7"
+
+	fallback, err := btcutil.DecodeAddress(
+		"1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2",
+		&chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatalf("unable to decode address: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		fallback btcutil.Address
+		amt      btcutil.Amount
+		memo     string
+		uri      string
+	}{
+		{
+			name: "lightning only",
+			amt:  50000,
+			uri:  "lightning:" + payReq,
+		},
+		{
+			name:     "fallback",
+			fallback: fallback,
+			amt:      150000,
+			uri: "bitcoin:1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2" +
+				"?amount=0.0015&lightning=" + payReq,
+		},
+		{
+			name:     "fallback with memo",
+			fallback: fallback,
+			amt:      btcutil.SatoshiPerBitcoin,
+			memo:     "coffee & cake",
+			uri: "bitcoin:1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2" +
+				"?amount=1&label=coffee%20%26%20cake&lightning=" +
+				payReq,
+		},
+	}
+
+	for _, test := range tests {
+		uri := unifiedInvoiceURI(payReq, test.fallback, test.amt,
+			test.memo)
+		if uri != test.uri {
+			t.Fatalf("%v: expected uri %v, got %v", test.name,
+				test.uri, uri)
+		}
+	}
+}
@@ -0,0 +1,322 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+const (
+	// htlcEndorsementFeature is the name of the local feature advertised
+	// by nodes which understand the endorsement signal of HTLCs.
+	htlcEndorsementFeature = "htlc-endorsement"
+
+	// htlcEndorsementFeatureIndex is the index of htlcEndorsementFeature
+	// within our local feature vector.
+	htlcEndorsementFeatureIndex = 8
+
+	// maxOutgoingHTLCs is the number of HTLC slots of a channel's
+	// commitment we consider available to the HTLCs we forward over it.
+	// The remainder is left to the HTLCs offered by the remote party.
+	maxOutgoingHTLCs = lnwallet.MaxHTLCNumber / 2
+)
+
+// errGeneralResourcesExhausted is returned when an HTLC which isn't protected
+// would exceed the general resources of its outgoing channel.
+var errGeneralResourcesExhausted = fmt.Errorf("general resources of " +
+	"outgoing channel exhausted")
+
+// jammingConfig defines the options of our mitigation of channel jamming
+// attacks, in which HTLCs are forwarded through us only to occupy the slots
+// and liquidity of our channels.
+type jammingConfig struct {
+	Disable bool `long:"disable" description:"Disable the endorsement of HTLCs, along with the reservation of channel resources for endorsed HTLCs"`
+
+	ProtectedSlots     float64 `long:"protectedslots" description:"The fraction of each channel's HTLC slots reserved for endorsed HTLCs from upstream peers with a good reputation"`
+	ProtectedLiquidity float64 `long:"protectedliquidity" description:"The fraction of each channel's capacity reserved for endorsed HTLCs from upstream peers with a good reputation"`
+
+	MinReputation      int64         `long:"minreputation" description:"The decayed fees, in satoshis, that forwards from an upstream peer must have earned us before its endorsed HTLCs are protected"`
+	ReputationHalfLife time.Duration `long:"reputationhalflife" description:"The time after which the fees earned by a forward count for half as much toward the reputation of its upstream peer"`
+	ResolutionPeriod   time.Duration `long:"resolutionperiod" description:"Endorsed HTLCs which take longer than this to resolve count against the reputation of their upstream peer"`
+}
+
+// validate checks the jamming mitigation options for consistency.
+func (c *jammingConfig) validate() error {
+	if c.Disable {
+		return nil
+	}
+
+	if c.ProtectedSlots < 0 || c.ProtectedSlots > 1 {
+		return fmt.Errorf("jamming.protectedslots must be between " +
+			"0 and 1")
+	}
+	if c.ProtectedLiquidity < 0 || c.ProtectedLiquidity > 1 {
+		return fmt.Errorf("jamming.protectedliquidity must be between " +
+			"0 and 1")
+	}
+	if c.MinReputation < 0 {
+		return fmt.Errorf("jamming.minreputation mustn't be negative")
+	}
+	if c.ReputationHalfLife <= 0 {
+		return fmt.Errorf("jamming.reputationhalflife must be positive")
+	}
+	if c.ResolutionPeriod <= 0 {
+		return fmt.Errorf("jamming.resolutionperiod must be positive")
+	}
+
+	return nil
+}
+
+// peerReputation is the reputation of an upstream peer, which decays
+// exponentially over time.
+type peerReputation struct {
+	// score is the reputation as of updated, in satoshis.
+	score float64
+
+	// updated is the time the score was last updated.
+	updated time.Time
+}
+
+// generalResources are the resources of an outgoing channel in use by the
+// forwarded HTLCs which aren't protected.
+type generalResources struct {
+	slots     int
+	liquidity btcutil.Amount
+}
+
+// jammingMitigator protects our routing capacity against channel jamming. The
+// HTLCs we forward are sorted into two buckets:
+//  * protected HTLCs arrived endorsed from an upstream peer with a good
+//    reputation, and may use all of the resources of their outgoing channel.
+//    We endorse them in turn as we forward them.
+//  * all other HTLCs may only use the general resources of their outgoing
+//    channel, which exclude the configured fraction of its slots and
+//    liquidity. Once these are exhausted, further such HTLCs are failed.
+//
+// The reputation of an upstream peer is the fees its forwards have earned us,
+// decayed over time, less a penalty for each endorsed HTLC it held within our
+// channels beyond the resolution period. An attacker is therefore unable to
+// jam our channels without either first paying us fees, or limiting itself to
+// the general resources left open to anyone.
+//
+// NOTE: Reputations are kept in memory, and are rebuilt from the forwarding
+// log with each start up. Penalties are lost across restarts.
+type jammingMitigator struct {
+	cfg *jammingConfig
+
+	// now returns the current time. It's replaced within tests.
+	now func() time.Time
+
+	// reputations maps the serialized public key of each upstream peer to
+	// its reputation.
+	reputations map[[33]byte]*peerReputation
+
+	// general maps each outgoing channel to its general resources in use.
+	general map[wire.OutPoint]*generalResources
+
+	sync.Mutex
+}
+
+// newJammingMitigator creates a new jammingMitigator without any
+// reputations.
+func newJammingMitigator(cfg *jammingConfig) *jammingMitigator {
+	return &jammingMitigator{
+		cfg:         cfg,
+		now:         time.Now,
+		reputations: make(map[[33]byte]*peerReputation),
+		general:     make(map[wire.OutPoint]*generalResources),
+	}
+}
+
+// bootstrap seeds the reputation of our upstream peers with the fees earned
+// by the passed forwards. The peer of each incoming channel is looked up
+// within chanPeers, and forwards over channels missing from it are skipped.
+func (j *jammingMitigator) bootstrap(events []*channeldb.ForwardingEvent,
+	chanPeers map[wire.OutPoint]*btcec.PublicKey) {
+
+	j.Lock()
+	defer j.Unlock()
+
+	for _, event := range events {
+		peer, ok := chanPeers[event.IncomingChanPoint]
+		if !ok {
+			continue
+		}
+
+		fee := float64(event.AmtIn - event.AmtOut)
+		j.credit(peer, fee, event.Timestamp)
+	}
+}
+
+// loadReputations seeds the reputation of our upstream peers from the
+// forwarding log within the passed database. Only the forwards over channels
+// which remain open are accounted for.
+func (j *jammingMitigator) loadReputations(db *channeldb.DB) error {
+	channels, err := db.FetchAllChannels()
+	if err != nil {
+		return err
+	}
+	chanPeers := make(map[wire.OutPoint]*btcec.PublicKey, len(channels))
+	for _, channel := range channels {
+		chanPeers[*channel.ChanID] = channel.IdentityPub
+	}
+
+	events, err := db.FetchForwardingLog()
+	if err != nil {
+		return err
+	}
+
+	j.bootstrap(events, chanPeers)
+	return nil
+}
+
+// credit adds the passed amount, which may be negative, to the reputation of
+// the passed peer as of the passed time.
+//
+// NOTE: The mitigator's mutex MUST be held when calling this method.
+func (j *jammingMitigator) credit(peer *btcec.PublicKey, amt float64,
+	at time.Time) {
+
+	var key [33]byte
+	copy(key[:], peer.SerializeCompressed())
+
+	rep, ok := j.reputations[key]
+	if !ok {
+		rep = &peerReputation{updated: at}
+		j.reputations[key] = rep
+	}
+
+	// Forwards replayed from the log may predate the last update, in
+	// which case they're decayed up to it instead.
+	if at.Before(rep.updated) {
+		rep.score += amt * j.decay(rep.updated.Sub(at))
+		return
+	}
+
+	rep.score = rep.score*j.decay(at.Sub(rep.updated)) + amt
+	rep.updated = at
+}
+
+// decay returns the factor by which a reputation decays over the passed
+// duration.
+func (j *jammingMitigator) decay(elapsed time.Duration) float64 {
+	halfLives := float64(elapsed) / float64(j.cfg.ReputationHalfLife)
+	return math.Pow(0.5, halfLives)
+}
+
+// reputation returns the current reputation of the passed peer, in
+// satoshis.
+func (j *jammingMitigator) reputation(peer *btcec.PublicKey) btcutil.Amount {
+	j.Lock()
+	defer j.Unlock()
+
+	return btcutil.Amount(j.currentScore(peer))
+}
+
+// currentScore returns the current reputation of the passed peer.
+//
+// NOTE: The mitigator's mutex MUST be held when calling this method.
+func (j *jammingMitigator) currentScore(peer *btcec.PublicKey) float64 {
+	var key [33]byte
+	copy(key[:], peer.SerializeCompressed())
+
+	rep, ok := j.reputations[key]
+	if !ok {
+		return 0
+	}
+
+	return rep.score * j.decay(j.now().Sub(rep.updated))
+}
+
+// admit decides whether an HTLC of the passed amount, which arrived from the
+// passed upstream peer, may be forwarded over the passed outgoing channel. If
+// the HTLC is protected, then true is returned. Otherwise, it's accounted
+// against the general resources of the channel, and
+// errGeneralResourcesExhausted is returned if these are insufficient. The
+// general resources used by an admitted HTLC must be released once it's
+// resolved.
+func (j *jammingMitigator) admit(upstream *btcec.PublicKey, endorsed bool,
+	outgoing *wire.OutPoint, capacity, amt btcutil.Amount) (bool, error) {
+
+	j.Lock()
+	defer j.Unlock()
+
+	if endorsed &&
+		j.currentScore(upstream) >= float64(j.cfg.MinReputation) {
+
+		return true, nil
+	}
+
+	maxSlots := int(float64(maxOutgoingHTLCs) * (1 - j.cfg.ProtectedSlots))
+	maxLiquidity := btcutil.Amount(
+		float64(capacity) * (1 - j.cfg.ProtectedLiquidity))
+
+	used, ok := j.general[*outgoing]
+	if !ok {
+		used = &generalResources{}
+		j.general[*outgoing] = used
+	}
+	if used.slots+1 > maxSlots || used.liquidity+amt > maxLiquidity {
+		return false, errGeneralResourcesExhausted
+	}
+
+	used.slots++
+	used.liquidity += amt
+
+	return false, nil
+}
+
+// release returns the general resources used by an HTLC of the passed amount
+// to its outgoing channel.
+func (j *jammingMitigator) release(outgoing *wire.OutPoint,
+	amt btcutil.Amount) {
+
+	j.Lock()
+	defer j.Unlock()
+
+	used, ok := j.general[*outgoing]
+	if !ok {
+		return
+	}
+
+	used.slots--
+	used.liquidity -= amt
+	if used.slots <= 0 {
+		delete(j.general, *outgoing)
+	}
+}
+
+// resolved updates the reputation of the passed upstream peer once an HTLC
+// it sent us, which earned the passed fee, has been settled or failed after
+// being held for the passed duration. Settled HTLCs credit their fee. An
+// endorsed HTLC held beyond the resolution period is penalized by the fees it
+// could have earned over the periods it occupied our channel, whether it
+// settled or not. As the peer didn't vouch for them, unendorsed HTLCs are
+// never penalized.
+func (j *jammingMitigator) resolved(upstream *btcec.PublicKey, endorsed,
+	settled bool, fee btcutil.Amount, held time.Duration) {
+
+	j.Lock()
+	defer j.Unlock()
+
+	var amt float64
+	if settled {
+		amt += float64(fee)
+	}
+	if endorsed && held > j.cfg.ResolutionPeriod {
+		periods := float64(held) / float64(j.cfg.ResolutionPeriod)
+		amt -= float64(fee) * periods
+	}
+
+	if amt == 0 {
+		return
+	}
+	j.credit(upstream, amt, j.now())
+}
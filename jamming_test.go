@@ -0,0 +1,142 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/wire"
+)
+
+func TestJammingMitigator(t *testing.T) {
+	var peers [2]*btcec.PublicKey
+	for i := range peers {
+		priv, err := btcec.NewPrivateKey(btcec.S256())
+		if err != nil {
+			t.Fatalf("unable to generate key: %v", err)
+		}
+		peers[i] = priv.PubKey()
+	}
+	honest, attacker := peers[0], peers[1]
+
+	cfg := &jammingConfig{
+		ProtectedSlots:     0.5,
+		ProtectedLiquidity: 0.5,
+		MinReputation:      100,
+		ReputationHalfLife: time.Hour,
+		ResolutionPeriod:   time.Minute,
+	}
+	now := time.Unix(1490000000, 0)
+	mitigator := newJammingMitigator(cfg)
+	mitigator.now = func() time.Time { return now }
+
+	// The honest peer has earned us fees in the past, which decay over
+	// time. Forwards over unknown channels are ignored.
+	honestChan := wire.OutPoint{Index: 1}
+	mitigator.bootstrap([]*channeldb.ForwardingEvent{
+		{
+			Timestamp:         now.Add(-time.Hour),
+			IncomingChanPoint: honestChan,
+			AmtIn:             1400,
+			AmtOut:            1000,
+		},
+		{
+			Timestamp:         now.Add(-time.Hour),
+			IncomingChanPoint: wire.OutPoint{Index: 2},
+			AmtIn:             1400,
+			AmtOut:            1000,
+		},
+	}, map[wire.OutPoint]*btcec.PublicKey{honestChan: honest})
+
+	if rep := mitigator.reputation(honest); rep != 200 {
+		t.Fatalf("expected reputation of 200, got %v", rep)
+	}
+	if rep := mitigator.reputation(attacker); rep != 0 {
+		t.Fatalf("expected no reputation, got %v", rep)
+	}
+
+	outgoing := &wire.OutPoint{Index: 3}
+	const capacity = 10000
+
+	// Endorsed HTLCs from the honest peer are protected, and don't use
+	// the general resources.
+	protected, err := mitigator.admit(honest, true, outgoing, capacity,
+		capacity)
+	if err != nil || !protected {
+		t.Fatalf("expected protected HTLC, got %v, %v", protected, err)
+	}
+
+	// Neither unendorsed HTLCs, nor endorsed HTLCs from the attacker, are
+	// protected, and together they're limited to half the liquidity.
+	protected, err = mitigator.admit(honest, false, outgoing, capacity,
+		3000)
+	if err != nil || protected {
+		t.Fatalf("expected general HTLC, got %v, %v", protected, err)
+	}
+	protected, err = mitigator.admit(attacker, true, outgoing, capacity,
+		2000)
+	if err != nil || protected {
+		t.Fatalf("expected general HTLC, got %v, %v", protected, err)
+	}
+	_, err = mitigator.admit(attacker, true, outgoing, capacity, 1)
+	if err != errGeneralResourcesExhausted {
+		t.Fatalf("expected general resources to be exhausted, got %v",
+			err)
+	}
+
+	// Once an HTLC is released, its liquidity is available again.
+	mitigator.release(outgoing, 2000)
+	if _, err := mitigator.admit(attacker, false, outgoing, capacity,
+		2000); err != nil {
+
+		t.Fatalf("unable to admit HTLC: %v", err)
+	}
+
+	// The attacker's general HTLCs are also limited to half the slots.
+	otherOutgoing := &wire.OutPoint{Index: 4}
+	maxSlots := int(maxOutgoingHTLCs * 0.5)
+	for i := 0; i < maxSlots; i++ {
+		_, err := mitigator.admit(attacker, false, otherOutgoing,
+			capacity, 1)
+		if err != nil {
+			t.Fatalf("unable to admit HTLC %v: %v", i, err)
+		}
+	}
+	_, err = mitigator.admit(attacker, false, otherOutgoing, capacity, 1)
+	if err != errGeneralResourcesExhausted {
+		t.Fatalf("expected general slots to be exhausted, got %v", err)
+	}
+
+	// Settled HTLCs credit their fee, while failed HTLCs don't.
+	mitigator.resolved(attacker, false, true, 50, time.Second)
+	mitigator.resolved(attacker, false, false, 50, time.Second)
+	if rep := mitigator.reputation(attacker); rep != 50 {
+		t.Fatalf("expected reputation of 50, got %v", rep)
+	}
+
+	// An endorsed HTLC which is held beyond the resolution period costs
+	// the honest peer its reputation, and with it the protection of its
+	// endorsed HTLCs.
+	mitigator.resolved(honest, true, true, 100, 4*time.Minute)
+	if rep := mitigator.reputation(honest); rep != -100 {
+		t.Fatalf("expected reputation of -100, got %v", rep)
+	}
+	protected, err = mitigator.admit(honest, true, &wire.OutPoint{Index: 5},
+		capacity, 1)
+	if err != nil || protected {
+		t.Fatalf("expected general HTLC, got %v, %v", protected, err)
+	}
+
+	// Unendorsed HTLCs aren't penalized however long they're held.
+	mitigator.resolved(attacker, false, false, 50, time.Hour)
+	if rep := mitigator.reputation(attacker); rep != 50 {
+		t.Fatalf("expected reputation of 50, got %v", rep)
+	}
+
+	// Reputations decay over time.
+	now = now.Add(time.Hour)
+	if rep := mitigator.reputation(attacker); rep != 25 {
+		t.Fatalf("expected reputation of 25, got %v", rep)
+	}
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+)
+
+// numWeakSecrets is the number of the smallest private keys, starting from 1,
+// whose public keys are flagged by the key audit. Such a key is a sign of a
+// derivation path which failed to mix in any entropy.
+const numWeakSecrets = 256
+
+// keyFinding is an instance of suspicious key material uncovered by the key
+// audit.
+type keyFinding struct {
+	// desc describes the suspicious key material.
+	desc string
+
+	// uses are where within the node's channels the key material is
+	// used.
+	uses []string
+}
+
+// String returns a human readable description of the finding.
+func (k *keyFinding) String() string {
+	return fmt.Sprintf("%v: %v", k.desc, strings.Join(k.uses, ", "))
+}
+
+// auditChannelKeys checks the key material of our side of the passed channels
+// for signs of a derivation bug: the same key or revocation root used by more
+// than one channel or role, our keys coinciding with the node's identity key
+// or the remote party's keys, and keys derived from trivially weak secrets.
+// As each channel's keys are derived afresh, any of these put the funds of
+// the channels concerned at risk.
+func auditChannelKeys(idKey *btcec.PublicKey,
+	channels []*channeldb.OpenChannel) ([]*keyFinding, error) {
+
+	var findings []*keyFinding
+
+	weakKeys := make(map[string]int, numWeakSecrets)
+	for i := 1; i <= numWeakSecrets; i++ {
+		secret := []byte{byte(i >> 8), byte(i)}
+		_, pub := btcec.PrivKeyFromBytes(btcec.S256(), secret)
+		weakKeys[string(pub.SerializeCompressed())] = i
+	}
+
+	// Each of our keys, and each channel's first revocation secret, is
+	// indexed along with where it's used, so any reuse is found once all
+	// channels have been visited.
+	keyUses := make(map[string][]string)
+	var keyOrder []string
+	addKey := func(key *btcec.PublicKey, use string) {
+		if key == nil {
+			return
+		}
+
+		k := string(key.SerializeCompressed())
+		if _, ok := keyUses[k]; !ok {
+			keyOrder = append(keyOrder, k)
+		}
+		keyUses[k] = append(keyUses[k], use)
+
+		if secret, ok := weakKeys[k]; ok {
+			findings = append(findings, &keyFinding{
+				desc: fmt.Sprintf("key %x has the weak private "+
+					"key %v", key.SerializeCompressed(),
+					secret),
+				uses: []string{use},
+			})
+		}
+	}
+	addKey(idKey, "node identity key")
+
+	rootUses := make(map[chainhash.Hash][]string)
+	var rootOrder []chainhash.Hash
+
+	for _, channel := range channels {
+		chanPoint := channel.FundingOutpoint
+		if chanPoint == nil {
+			chanPoint = channel.ChanID
+		}
+
+		addKey(channel.OurMultiSigKey,
+			fmt.Sprintf("multi-sig key of ChannelPoint(%v)", chanPoint))
+
+		// Channels recovered from a backup reuse their multi-sig key
+		// as their commitment key by design, as the original
+		// commitment key is unknown.
+		if channel.ChanType != channeldb.RecoveredChannel {
+			addKey(channel.OurCommitKey, fmt.Sprintf("commitment "+
+				"key of ChannelPoint(%v)", chanPoint))
+		}
+
+		for _, theirKey := range []*btcec.PublicKey{
+			channel.TheirMultiSigKey, channel.TheirCommitKey,
+		} {
+			if !sameKey(theirKey, channel.OurMultiSigKey) &&
+				!sameKey(theirKey, channel.OurCommitKey) {
+
+				continue
+			}
+
+			findings = append(findings, &keyFinding{
+				desc: fmt.Sprintf("key %x is used by both "+
+					"parties", theirKey.SerializeCompressed()),
+				uses: []string{fmt.Sprintf("ChannelPoint(%v)",
+					chanPoint)},
+			})
+		}
+
+		if channel.RevocationProducer == nil {
+			continue
+		}
+		secret, err := channel.RevocationProducer.AtIndex(0)
+		if err != nil {
+			return nil, fmt.Errorf("unable to derive revocation "+
+				"secret of ChannelPoint(%v): %v", chanPoint, err)
+		}
+		if *secret == (chainhash.Hash{}) {
+			findings = append(findings, &keyFinding{
+				desc: "revocation root produces a zero secret",
+				uses: []string{fmt.Sprintf("ChannelPoint(%v)",
+					chanPoint)},
+			})
+		}
+		if _, ok := rootUses[*secret]; !ok {
+			rootOrder = append(rootOrder, *secret)
+		}
+		rootUses[*secret] = append(rootUses[*secret],
+			fmt.Sprintf("ChannelPoint(%v)", chanPoint))
+	}
+
+	for _, k := range keyOrder {
+		if uses := keyUses[k]; len(uses) > 1 {
+			findings = append(findings, &keyFinding{
+				desc: fmt.Sprintf("key %x is reused",
+					[]byte(k)),
+				uses: uses,
+			})
+		}
+	}
+	for _, secret := range rootOrder {
+		if uses := rootUses[secret]; len(uses) > 1 {
+			findings = append(findings, &keyFinding{
+				desc: "revocation root is reused",
+				uses: uses,
+			})
+		}
+	}
+
+	return findings, nil
+}
+
+// runKeyAudit audits the key material of all the channels within the
+// database, logging each finding. Unless allowed by the configuration, an
+// error is returned if anything is found, so the node refuses to start rather
+// than update or open channels with reused keys.
+func runKeyAudit(idKey *btcec.PublicKey, chanDB *channeldb.DB) error {
+	openChannels, err := chanDB.FetchAllChannels()
+	if err != nil {
+		return err
+	}
+	pendingChannels, err := chanDB.FetchPendingChannels()
+	if err != nil {
+		return err
+	}
+
+	findings, err := auditChannelKeys(idKey,
+		append(openChannels, pendingChannels...))
+	if err != nil {
+		return err
+	}
+	if len(findings) == 0 {
+		return nil
+	}
+
+	for _, finding := range findings {
+		ltndLog.Criticalf("Key audit: %v", finding)
+	}
+
+	if cfg.AllowKeyReuse {
+		ltndLog.Criticalf("Key audit found %v instance(s) of suspicious "+
+			"key material, continuing as --allowkeyreuse is set",
+			len(findings))
+		return nil
+	}
+
+	return fmt.Errorf("key audit found %v instance(s) of suspicious key "+
+		"material, refusing to start; set --allowkeyreuse to start "+
+		"regardless", len(findings))
+}
+
+// sameKey returns true if the passed keys are both set and equal.
+func sameKey(a, b *btcec.PublicKey) bool {
+	if a == nil || b == nil {
+		return false
+	}
+
+	return bytes.Equal(a.SerializeCompressed(), b.SerializeCompressed())
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/shachain"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// TestAuditChannelKeys tests that the key audit flags key material reused
+// across channels, or derived from weak secrets, while accepting channels
+// with distinct keys.
+func TestAuditChannelKeys(t *testing.T) {
+	newKey := func() *btcec.PublicKey {
+		priv, err := btcec.NewPrivateKey(btcec.S256())
+		if err != nil {
+			t.Fatalf("unable to generate key: %v", err)
+		}
+		return priv.PubKey()
+	}
+	newChannel := func(index uint32) *channeldb.OpenChannel {
+		root := chainhash.Hash{byte(index), 1}
+		return &channeldb.OpenChannel{
+			FundingOutpoint:    &wire.OutPoint{Index: index},
+			OurMultiSigKey:     newKey(),
+			OurCommitKey:       newKey(),
+			TheirMultiSigKey:   newKey(),
+			TheirCommitKey:     newKey(),
+			RevocationProducer: shachain.NewRevocationProducer(root),
+		}
+	}
+	idKey := newKey()
+
+	// Channels with distinct keys, including one recovered from a backup
+	// which reuses its multi-sig key as its commitment key, should pass
+	// the audit.
+	recovered := newChannel(2)
+	recovered.ChanType = channeldb.RecoveredChannel
+	recovered.OurCommitKey = recovered.OurMultiSigKey
+	channels := []*channeldb.OpenChannel{
+		newChannel(0), newChannel(1), recovered,
+	}
+	findings, err := auditChannelKeys(idKey, channels)
+	if err != nil {
+		t.Fatalf("unable to audit keys: %v", err)
+	}
+	if len(findings) != 0 {
+		t.Fatalf("expected no findings, got %v", findings)
+	}
+
+	_, weakKey := btcec.PrivKeyFromBytes(btcec.S256(), []byte{1})
+
+	tests := []struct {
+		name   string
+		mutate func(a, b *channeldb.OpenChannel)
+		expect string
+	}{
+		{
+			name: "multi-sig key reused",
+			mutate: func(a, b *channeldb.OpenChannel) {
+				b.OurMultiSigKey = a.OurMultiSigKey
+			},
+			expect: "is reused",
+		},
+		{
+			name: "key reused across roles",
+			mutate: func(a, b *channeldb.OpenChannel) {
+				b.OurCommitKey = a.OurMultiSigKey
+			},
+			expect: "is reused",
+		},
+		{
+			name: "identity key reused",
+			mutate: func(a, b *channeldb.OpenChannel) {
+				a.OurCommitKey = idKey
+			},
+			expect: "is reused",
+		},
+		{
+			name: "revocation root reused",
+			mutate: func(a, b *channeldb.OpenChannel) {
+				b.RevocationProducer = a.RevocationProducer
+			},
+			expect: "revocation root is reused",
+		},
+		{
+			name: "key shared with remote party",
+			mutate: func(a, b *channeldb.OpenChannel) {
+				a.TheirCommitKey = a.OurCommitKey
+			},
+			expect: "used by both parties",
+		},
+		{
+			name: "weak key",
+			mutate: func(a, b *channeldb.OpenChannel) {
+				a.OurMultiSigKey = weakKey
+			},
+			expect: "weak private key 1",
+		},
+	}
+
+	for _, test := range tests {
+		a, b := newChannel(0), newChannel(1)
+		test.mutate(a, b)
+
+		findings, err := auditChannelKeys(idKey,
+			[]*channeldb.OpenChannel{a, b})
+		if err != nil {
+			t.Fatalf("%v: unable to audit keys: %v", test.name, err)
+		}
+		if len(findings) != 1 {
+			t.Fatalf("%v: expected 1 finding, got %v", test.name,
+				findings)
+		}
+		if !strings.Contains(findings[0].String(), test.expect) {
+			t.Fatalf("%v: unexpected finding: %v", test.name,
+				findings[0])
+		}
+	}
+}
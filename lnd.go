@@ -2,13 +2,13 @@ package main
 
 import (
 	"encoding/hex"
+	"expvar"
 	"fmt"
 	"io/ioutil"
 	"net"
 	"net/http"
 	_ "net/http/pprof"
 	"os"
-	"path/filepath"
 	"runtime"
 	"strconv"
 	"strings"
@@ -18,11 +18,17 @@ import (
 	"google.golang.org/grpc"
 
 	proxy "github.com/grpc-ecosystem/grpc-gateway/runtime"
+	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/chainntnfs/btcdnotify"
+	"github.com/lightningnetwork/lnd/chainntnfs/virtualnotify"
 	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/hwsigner"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwallet/btcwallet"
+	"github.com/lightningnetwork/lnd/lnwallet/virtualwallet"
+	"github.com/lightningnetwork/lnd/sqlstore"
+	"github.com/lightningnetwork/lnd/virtualchain"
 
 	"github.com/roasbeef/btcrpcclient"
 )
@@ -60,83 +66,107 @@ func lndMain() error {
 	}
 
 	// Open the channeldb, which is dedicated to storing channel, and
-	// network related metadata.
-	chanDB, err := channeldb.Open(cfg.DataDir)
+	// network related metadata. If we're a standby, then we wait for the
+	// daemon currently using it to release it.
+	var chanDB *channeldb.DB
+	if cfg.Standby {
+		ltndLog.Infof("Waiting in standby to acquire channeldb")
+
+		standbyQuit := make(chan struct{})
+		addInterruptHandler(func() {
+			close(standbyQuit)
+		})
+		chanDB, err = channeldb.OpenStandby(cfg.ChanDBDir, standbyQuit)
+	} else {
+		chanDB, err = channeldb.Open(cfg.ChanDBDir)
+	}
 	if err != nil {
 		fmt.Println("unable to open channeldb: ", err)
 		return err
 	}
 	defer chanDB.Close()
 
-	// Next load btcd's TLS cert for the RPC connection. If a raw cert was
-	// specified in the config, then we'll set that directly. Otherwise, we
-	// attempt to read the cert from the path specified in the config.
-	var rpcCert []byte
-	if cfg.RawRPCCert != "" {
-		rpcCert, err = hex.DecodeString(cfg.RawRPCCert)
+	// If requested, open the SQL analytics store which mirrors invoices,
+	// payments, and forwarding events for reporting purposes.
+	var analytics *sqlstore.Store
+	if cfg.AnalyticsDB != "" {
+		analytics, err = sqlstore.Open(cfg.AnalyticsDB)
 		if err != nil {
+			fmt.Println("unable to open analytics db: ", err)
 			return err
 		}
-	} else {
-		certFile, err := os.Open(cfg.RPCCert)
+		defer analytics.Close()
+	}
+
+	// In simulation mode, the notifier and the wallet follow a virtual
+	// chain. Otherwise, both are backed by btcd.
+	var (
+		notifier     chainntnfs.ChainNotifier
+		wc           lnwallet.WalletController
+		signer       lnwallet.Signer
+		bio          lnwallet.BlockChainIO
+		virtualChain virtualchain.Backend
+	)
+	if cfg.Simulation.Enable {
+		virtualChain, err = newVirtualChain(&cfg.Simulation)
 		if err != nil {
+			fmt.Printf("unable to create virtual chain: %v\n", err)
 			return err
 		}
-		rpcCert, err = ioutil.ReadAll(certFile)
+
+		notifier = virtualnotify.New(virtualChain)
+		virtualWallet, err := virtualwallet.New(&virtualwallet.Config{
+			DataDir:   cfg.WalletDir,
+			NetParams: activeNetParams.Params,
+			Backend:   virtualChain,
+		})
 		if err != nil {
+			fmt.Printf("unable to create wallet controller: %v\n", err)
 			return err
 		}
-		if err := certFile.Close(); err != nil {
+		wc, signer, bio = virtualWallet, virtualWallet, virtualWallet
+
+		ltndLog.Infof("Running in simulation mode on a virtual chain")
+	} else {
+		btcdNotifier, btcWallet, err := newBtcdBackend()
+		if err != nil {
 			return err
 		}
+		notifier, wc, signer, bio = btcdNotifier, btcWallet, btcWallet,
+			btcWallet
 	}
 
-	// If the specified host for the btcd RPC server already has a port
-	// specified, then we use that directly. Otherwise, we assume the
-	// default port according to the selected chain parameters.
-	var btcdHost string
-	if strings.Contains(cfg.RPCHost, ":") {
-		btcdHost = cfg.RPCHost
-	} else {
-		btcdHost = fmt.Sprintf("%v:%v", cfg.RPCHost, activeNetParams.rpcPort)
+	// Unless disabled, historical lookups of blocks and transactions are
+	// served from a cache in front of the chain backend.
+	if cfg.BlockCacheSize != 0 {
+		bio = newChainCache(bio, cfg.BlockCacheSize)
 	}
 
-	btcdUser := cfg.RPCUser
-	btcdPass := cfg.RPCPass
+	// If a hardware device is configured, then the multi-sig keys of new
+	// channels are held by the device, and signatures for them are
+	// requested from the device. All other keys, including our identity
+	// key, remain within the wallet.
+	var fundingKeys lnwallet.FundingKeyRing
+	if cfg.HWI.Command != "" {
+		device := hwsigner.NewDevice(hwsigner.Config{
+			Command:     cfg.HWI.Command,
+			DeviceType:  cfg.HWI.DeviceType,
+			Fingerprint: cfg.HWI.fingerprint,
+			Chain:       hwiChainName(activeNetParams),
+		})
+		signer = hwsigner.NewSigner(signer, device, chanDB)
+		fundingKeys = hwsigner.NewKeyRing(device, chanDB,
+			cfg.HWI.fundingPath)
 
-	// TODO(roasbeef): parse config here and select chosen notifier instead
-	rpcConfig := &btcrpcclient.ConnConfig{
-		Host:                 btcdHost,
-		Endpoint:             "ws",
-		User:                 btcdUser,
-		Pass:                 btcdPass,
-		Certificates:         rpcCert,
-		DisableTLS:           false,
-		DisableConnectOnNew:  true,
-		DisableAutoReconnect: false,
-	}
-	notifier, err := btcdnotify.New(rpcConfig)
-	if err != nil {
-		return err
+		ltndLog.Infof("Funding keys will be held by %v device %v",
+			cfg.HWI.DeviceType, cfg.HWI.Fingerprint)
 	}
 
-	// TODO(roasbeef): parse config here select chosen WalletController
-	walletConfig := &btcwallet.Config{
-		PrivatePass: []byte("hello"),
-		DataDir:     filepath.Join(cfg.DataDir, "lnwallet"),
-		RPCHost:     btcdHost,
-		RPCUser:     cfg.RPCUser,
-		RPCPass:     cfg.RPCPass,
-		CACert:      rpcCert,
-		NetParams:   activeNetParams.Params,
-	}
-	wc, err := btcwallet.New(walletConfig)
-	if err != nil {
-		fmt.Printf("unable to create wallet controller: %v\n", err)
-		return err
+	// If enabled, each signature is recorded within the signing audit log
+	// before it's used.
+	if cfg.SigningAudit {
+		signer = newAuditSigner(signer, chanDB)
 	}
-	signer := wc
-	bio := wc
 
 	// Create, and start the lnwallet, which handles the core payment
 	// channel logic, and exposes control via proxy state machines.
@@ -146,22 +176,47 @@ func lndMain() error {
 		fmt.Printf("unable to create wallet: %v\n", err)
 		return err
 	}
+	wallet.FundingKeys = fundingKeys
+	wallet.FeeCeiling = cfg.FeeCeiling.ceiling
 	if err := wallet.Startup(); err != nil {
 		fmt.Printf("unable to start wallet: %v\n", err)
 		return err
 	}
 	ltndLog.Info("LightningWallet opened")
 
+	// Before any channel is loaded, audit the key material of our
+	// channels, as reused keys point to a derivation bug which puts the
+	// funds within the channels at risk.
+	idPrivKey, err := wallet.GetIdentitykey()
+	if err != nil {
+		return err
+	}
+	if err := runKeyAudit(idPrivKey.PubKey(), chanDB); err != nil {
+		ltndLog.Errorf("Key audit failed: %v", err)
+		return err
+	}
+
 	// Set up the core server which will listen for incoming peer
 	// connections.
 	defaultListenAddrs := []string{
 		net.JoinHostPort("", strconv.Itoa(cfg.PeerPort)),
 	}
-	server, err := newServer(defaultListenAddrs, notifier, bio, wallet, chanDB)
+	server, err := newServer(defaultListenAddrs, notifier, bio, wallet, chanDB,
+		analytics)
 	if err != nil {
 		srvrLog.Errorf("unable to create server: %v\n", err)
 		return err
 	}
+	server.virtualChain = virtualChain
+
+	// Publish the payment telemetry alongside the profiling endpoints, so
+	// it may be scraped by local monitoring.
+	if server.paymentTelemetry != nil {
+		expvar.Publish("paymenttelemetry", expvar.Func(func() interface{} {
+			return server.paymentTelemetry.report()
+		}))
+	}
+
 	if err := server.Start(); err != nil {
 		srvrLog.Errorf("unable to create to start server: %v\n", err)
 		return err
@@ -175,6 +230,12 @@ func lndMain() error {
 
 	// Initialize, and register our implementation of the gRPC server.
 	var opts []grpc.ServerOption
+	if middleware := server.rpcServer.middleware; middleware != nil {
+		opts = append(opts,
+			grpc.UnaryInterceptor(middleware.unaryInterceptor),
+			grpc.StreamInterceptor(middleware.streamInterceptor),
+		)
+	}
 	grpcServer := grpc.NewServer(opts...)
 	lnrpc.RegisterLightningServer(grpcServer, server.rpcServer)
 
@@ -214,6 +275,82 @@ func lndMain() error {
 	return nil
 }
 
+// newBtcdBackend creates the chain notifier and the wallet controller backed
+// by the btcd node configured.
+func newBtcdBackend() (*btcdnotify.BtcdNotifier, *btcwallet.BtcWallet, error) {
+	var err error
+
+	// Load btcd's TLS cert for the RPC connection. If a raw cert was
+	// specified in the config, then we'll set that directly. Otherwise, we
+	// attempt to read the cert from the path specified in the config.
+	var rpcCert []byte
+	if cfg.RawRPCCert != "" {
+		rpcCert, err = hex.DecodeString(cfg.RawRPCCert)
+		if err != nil {
+			return nil, nil, err
+		}
+	} else {
+		certFile, err := os.Open(cfg.RPCCert)
+		if err != nil {
+			return nil, nil, err
+		}
+		rpcCert, err = ioutil.ReadAll(certFile)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := certFile.Close(); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// If the specified host for the btcd RPC server already has a port
+	// specified, then we use that directly. Otherwise, we assume the
+	// default port according to the selected chain parameters.
+	var btcdHost string
+	if strings.Contains(cfg.RPCHost, ":") {
+		btcdHost = cfg.RPCHost
+	} else {
+		btcdHost = fmt.Sprintf("%v:%v", cfg.RPCHost, activeNetParams.rpcPort)
+	}
+
+	btcdUser := cfg.RPCUser
+	btcdPass := cfg.RPCPass
+
+	// TODO(roasbeef): parse config here and select chosen notifier instead
+	rpcConfig := &btcrpcclient.ConnConfig{
+		Host:                 btcdHost,
+		Endpoint:             "ws",
+		User:                 btcdUser,
+		Pass:                 btcdPass,
+		Certificates:         rpcCert,
+		DisableTLS:           false,
+		DisableConnectOnNew:  true,
+		DisableAutoReconnect: false,
+	}
+	notifier, err := btcdnotify.New(rpcConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// TODO(roasbeef): parse config here select chosen WalletController
+	walletConfig := &btcwallet.Config{
+		PrivatePass: []byte("hello"),
+		DataDir:     cfg.WalletDir,
+		RPCHost:     btcdHost,
+		RPCUser:     cfg.RPCUser,
+		RPCPass:     cfg.RPCPass,
+		CACert:      rpcCert,
+		NetParams:   activeNetParams.Params,
+	}
+	wc, err := btcwallet.New(walletConfig)
+	if err != nil {
+		fmt.Printf("unable to create wallet controller: %v\n", err)
+		return nil, nil, err
+	}
+
+	return notifier, wc, nil
+}
+
 func main() {
 	// Use all processor cores.
 	// TODO(roasbeef): remove this if required version # is > 1.6?
@@ -1281,6 +1281,106 @@ func testBasicChannelCreation(net *networkHarness, t *harnessTest) {
 	}
 }
 
+// testSplitPayment tests that a payment to a direct peer which exceeds the
+// bandwidth of each of our channels with it, but not their aggregate
+// bandwidth, is split into shards across the channels.
+func testSplitPayment(net *networkHarness, t *harnessTest) {
+	const (
+		numChannels = 2
+		timeout     = time.Duration(time.Second * 5)
+		chanAmt     = btcutil.Amount(100000)
+		paymentAmt  = 150000
+	)
+	ctxb := context.Background()
+
+	// Open two channels between Alice and Bob, with Alice being the sole
+	// funder of both.
+	chanPoints := make([]*lnrpc.ChannelPoint, numChannels)
+	for i := 0; i < numChannels; i++ {
+		ctxt, _ := context.WithTimeout(ctxb, timeout)
+		chanPoints[i] = openChannelAndAssert(ctxt, t, net, net.Alice,
+			net.Bob, chanAmt, 0)
+
+		ctxt, _ = context.WithTimeout(ctxb, timeout)
+		err := net.Alice.WaitForNetworkChannelOpen(ctxt, chanPoints[i])
+		if err != nil {
+			t.Fatalf("alice didn't advertise channel before "+
+				"timeout: %v", err)
+		}
+	}
+
+	// Bob's invoice exceeds the capacity of either channel.
+	preimage := bytes.Repeat([]byte("S"), 32)
+	invoice := &lnrpc.Invoice{
+		Memo:      "split",
+		RPreimage: preimage,
+		Value:     paymentAmt,
+	}
+	invoiceResp, err := net.Bob.AddInvoice(ctxb, invoice)
+	if err != nil {
+		t.Fatalf("unable to add invoice: %v", err)
+	}
+
+	// The payment should still succeed, as it's split across both
+	// channels.
+	sendStream, err := net.Alice.SendPayment(ctxb)
+	if err != nil {
+		t.Fatalf("unable to create alice payment stream: %v", err)
+	}
+	sendReq := &lnrpc.SendRequest{
+		PaymentHash: invoiceResp.RHash,
+		Dest:        net.Bob.PubKey[:],
+		Amt:         paymentAmt,
+	}
+	if err := sendStream.Send(sendReq); err != nil {
+		t.Fatalf("unable to send payment: %v", err)
+	}
+	resp, err := sendStream.Recv()
+	if err != nil {
+		t.Fatalf("error when attempting recv: %v", err)
+	}
+	if !bytes.Equal(preimage, resp.PaymentPreimage) {
+		t.Fatalf("preimage mismatch: expected %v, got %v", preimage,
+			resp.PaymentPreimage)
+	}
+
+	// Bob's invoice should be settled, with the payment having been
+	// received over both channels.
+	payHash := &lnrpc.PaymentHash{
+		RHash: invoiceResp.RHash,
+	}
+	dbInvoice, err := net.Bob.LookupInvoice(ctxb, payHash)
+	if err != nil {
+		t.Fatalf("unable to lookup invoice: %v", err)
+	}
+	if !dbInvoice.Settled {
+		t.Fatalf("bob's invoice should be marked as settled: %v",
+			spew.Sdump(dbInvoice))
+	}
+
+	listReq := &lnrpc.ListChannelsRequest{}
+	bobChannels, err := net.Bob.ListChannels(ctxb, listReq)
+	if err != nil {
+		t.Fatalf("unable to query for bob's channel list: %v", err)
+	}
+	var received int64
+	for _, channel := range bobChannels.Channels {
+		if channel.TotalSatoshisReceived == 0 {
+			t.Fatalf("expected payment to be received over all " +
+				"channels")
+		}
+		received += channel.TotalSatoshisReceived
+	}
+	if received != paymentAmt {
+		t.Fatalf("bob received %v, expected %v", received, paymentAmt)
+	}
+
+	for _, chanPoint := range chanPoints {
+		ctxt, _ := context.WithTimeout(ctxb, timeout)
+		closeChannelAndAssert(ctxt, t, net, net.Alice, chanPoint, false)
+	}
+}
+
 // testMaxPendingChannels checks that error is returned from remote peer if
 // max pending channel number was exceeded and that '--maxpendingchannels' flag
 // exists and works properly.
@@ -2107,6 +2207,10 @@ var testsCases = []*testCase{
 		name: "multiple channel creation",
 		test: testBasicChannelCreation,
 	},
+	{
+		name: "split payment across parallel channels",
+		test: testSplitPayment,
+	},
 	{
 		name: "invoice update subscription",
 		test: testInvoiceSubscriptions,
@@ -6,9 +6,11 @@
 Package lnrpc is a generated protocol buffer package.
 
 It is generated from these files:
+
 	rpc.proto
 
 It has these top-level messages:
+
 	Transaction
 	GetTransactionsRequest
 	TransactionDetails
@@ -44,6 +46,14 @@ It has these top-level messages:
 	OpenStatusUpdate
 	PendingChannelRequest
 	PendingChannelResponse
+	ExportChanBackupRequest
+	ChanBackupSnapshot
+	ChannelBackupReport
+	VerifyChanBackupResponse
+	RestoreChanBackupRequest
+	RestoreChanBackupResponse
+	ImportChannelRecoveryRequest
+	ImportChannelRecoveryResponse
 	WalletBalanceRequest
 	WalletBalanceResponse
 	ChannelBalanceRequest
@@ -74,6 +84,12 @@ It has these top-level messages:
 	ListInvoiceRequest
 	ListInvoiceResponse
 	InvoiceSubscription
+	Offer
+	AddOfferResponse
+	ListOffersRequest
+	ListOffersResponse
+	OfferInvoiceRequest
+	OfferInvoiceResponse
 	Payment
 	ListPaymentsRequest
 	ListPaymentsResponse
@@ -81,8 +97,121 @@ It has these top-level messages:
 	DeleteAllPaymentsResponse
 	DebugLevelRequest
 	DebugLevelResponse
+	ChannelStateExport
 	PayReqString
 	PayReq
+	FeePreset
+	ListFeePresetsRequest
+	ListFeePresetsResponse
+	ChannelHistoryRequest
+	ChannelEvent
+	ChannelHistoryResponse
+	MiddlewareRegistration
+	RPCMiddlewareRequest
+	RPCMiddlewareResponse
+	ChannelRecommendation
+	ListRecommendationsRequest
+	ListRecommendationsResponse
+	ExecuteRecommendationRequest
+	ExecuteRecommendationResponse
+	OutboxSubscription
+	OutboxNotification
+	OutboxAck
+	OutboxAckResponse
+	RemoveOutboxSubscriberRequest
+	RemoveOutboxSubscriberResponse
+	CloseDryRun
+	EdgePenalty
+	NodePenalty
+	QueryMissionControlRequest
+	QueryMissionControlResponse
+	ResetMissionControlRequest
+	ResetMissionControlResponse
+	AckChannelDivergenceResponse
+	CustomMessageSubscription
+	CustomMessage
+	SendCustomMessageRequest
+	SendCustomMessageResponse
+	SigningAuditRequest
+	SigningEvent
+	SigningAuditResponse
+	ExportChannelPoliciesRequest
+	ChannelPolicy
+	ChannelPolicies
+	ImportChannelPoliciesRequest
+	ChannelPolicyDiff
+	ImportChannelPoliciesResponse
+	BalanceHistoryRequest
+	BalanceSnapshot
+	BalanceHistoryResponse
+	ListUnresolvedHTLCsRequest
+	UnresolvedHTLC
+	ListUnresolvedHTLCsResponse
+	UpdateCommitFeeRequest
+	UpdateCommitFeeResponse
+	BatchPaymentRequest
+	BatchPaymentResult
+	ExportGossipRequest
+	StoredGossipMessage
+	GossipMessages
+	ReplayGossipResponse
+	SpliceInRequest
+	SpliceInResponse
+	BackupUploadStatusRequest
+	BackupUploadTarget
+	BackupUploadStatusResponse
+	DBCommitStatsRequest
+	DBCommitStat
+	DBCommitStatsResponse
+	AccountingRequest
+	AccountingEntry
+	AccountingReport
+	AbandonChannelResponse
+	RotateIdentityRequest
+	RotateIdentityResponse
+	MineBlocksRequest
+	MineBlocksResponse
+	AdvanceTimeRequest
+	AdvanceTimeResponse
+	EstimateOpenChannelResponse
+	GetFundingPolicyRequest
+	FundingPolicy
+	UpdateFundingPolicyResponse
+	ListPaymentAttemptsRequest
+	PaymentAttempt
+	ListPaymentAttemptsResponse
+	StateLogRequest
+	StateEvent
+	StateLogResponse
+	PeerSettings
+	UpdatePeerSettingsResponse
+	ListPeerSettingsRequest
+	ListPeerSettingsResponse
+	DeletePeerSettingsRequest
+	DeletePeerSettingsResponse
+	PaymentTelemetryRequest
+	FailureCodeCount
+	PaymentTelemetryResponse
+	TowerInfoRequest
+	TowerInfoResponse
+	ChannelStatesRequest
+	ChannelStateMachine
+	ChannelStatesResponse
+	FreezeChannelResponse
+	ThawChannelResponse
+	CreatePayReqRequest
+	CreatePayReqResponse
+	RevenueReportRequest
+	DailyRevenue
+	RevenueReportResponse
+	TopCounterpartiesRequest
+	CounterpartyVolume
+	TopCounterpartiesResponse
+	HopHint
+	RouteHint
+	ConfirmIdentityRotationRequest
+	ConfirmIdentityRotationResponse
+	ImportChannelStateRequest
 */
 package lnrpc
 
@@ -252,6 +381,8 @@ type SendRequest struct {
 	PaymentHash       []byte `protobuf:"bytes,4,opt,name=payment_hash,json=paymentHash,proto3" json:"payment_hash,omitempty"`
 	PaymentHashString string `protobuf:"bytes,5,opt,name=payment_hash_string,json=paymentHashString" json:"payment_hash_string,omitempty"`
 	PaymentRequest    string `protobuf:"bytes,6,opt,name=payment_request,json=paymentRequest" json:"payment_request,omitempty"`
+	FeePreset         string `protobuf:"bytes,7,opt,name=fee_preset,json=feePreset" json:"fee_preset,omitempty"`
+	PaymentId         uint64 `protobuf:"varint,8,opt,name=payment_id,json=paymentId" json:"payment_id,omitempty"`
 }
 
 func (m *SendRequest) Reset()                    { *m = SendRequest{} }
@@ -301,6 +432,20 @@ func (m *SendRequest) GetPaymentRequest() string {
 	return ""
 }
 
+func (m *SendRequest) GetFeePreset() string {
+	if m != nil {
+		return m.FeePreset
+	}
+	return ""
+}
+
+func (m *SendRequest) GetPaymentId() uint64 {
+	if m != nil {
+		return m.PaymentId
+	}
+	return 0
+}
+
 type SendResponse struct {
 	PaymentPreimage []byte `protobuf:"bytes,1,opt,name=payment_preimage,proto3" json:"payment_preimage,omitempty"`
 	PaymentRoute    *Route `protobuf:"bytes,2,opt,name=payment_route" json:"payment_route,omitempty"`
@@ -383,7 +528,8 @@ func (m *LightningAddress) GetHost() string {
 }
 
 type SendManyRequest struct {
-	AddrToAmount map[string]int64 `protobuf:"bytes,1,rep,name=AddrToAmount,json=addrToAmount" json:"AddrToAmount,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	AddrToAmount      map[string]int64 `protobuf:"bytes,1,rep,name=AddrToAmount,json=addrToAmount" json:"AddrToAmount,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+	OverrideWhitelist bool             `protobuf:"varint,2,opt,name=override_whitelist,json=overrideWhitelist" json:"override_whitelist,omitempty"`
 }
 
 func (m *SendManyRequest) Reset()                    { *m = SendManyRequest{} }
@@ -398,6 +544,13 @@ func (m *SendManyRequest) GetAddrToAmount() map[string]int64 {
 	return nil
 }
 
+func (m *SendManyRequest) GetOverrideWhitelist() bool {
+	if m != nil {
+		return m.OverrideWhitelist
+	}
+	return false
+}
+
 type SendManyResponse struct {
 	Txid string `protobuf:"bytes,1,opt,name=txid" json:"txid,omitempty"`
 }
@@ -415,8 +568,9 @@ func (m *SendManyResponse) GetTxid() string {
 }
 
 type SendCoinsRequest struct {
-	Addr   string `protobuf:"bytes,1,opt,name=addr" json:"addr,omitempty"`
-	Amount int64  `protobuf:"varint,2,opt,name=amount" json:"amount,omitempty"`
+	Addr              string `protobuf:"bytes,1,opt,name=addr" json:"addr,omitempty"`
+	Amount            int64  `protobuf:"varint,2,opt,name=amount" json:"amount,omitempty"`
+	OverrideWhitelist bool   `protobuf:"varint,3,opt,name=override_whitelist,json=overrideWhitelist" json:"override_whitelist,omitempty"`
 }
 
 func (m *SendCoinsRequest) Reset()                    { *m = SendCoinsRequest{} }
@@ -438,6 +592,13 @@ func (m *SendCoinsRequest) GetAmount() int64 {
 	return 0
 }
 
+func (m *SendCoinsRequest) GetOverrideWhitelist() bool {
+	if m != nil {
+		return m.OverrideWhitelist
+	}
+	return false
+}
+
 type SendCoinsResponse struct {
 	Txid string `protobuf:"bytes,1,opt,name=txid" json:"txid,omitempty"`
 }
@@ -595,6 +756,8 @@ type ActiveChannel struct {
 	TotalSatoshisReceived int64   `protobuf:"varint,10,opt,name=total_satoshis_received" json:"total_satoshis_received,omitempty"`
 	NumUpdates            uint64  `protobuf:"varint,11,opt,name=num_updates" json:"num_updates,omitempty"`
 	PendingHtlcs          []*HTLC `protobuf:"bytes,12,rep,name=pending_htlcs" json:"pending_htlcs,omitempty"`
+	ShutdownPending       bool    `protobuf:"varint,13,opt,name=shutdown_pending" json:"shutdown_pending,omitempty"`
+	OutgoingFrozen        bool    `protobuf:"varint,14,opt,name=outgoing_frozen" json:"outgoing_frozen,omitempty"`
 }
 
 func (m *ActiveChannel) Reset()                    { *m = ActiveChannel{} }
@@ -686,6 +849,20 @@ func (m *ActiveChannel) GetPendingHtlcs() []*HTLC {
 	return nil
 }
 
+func (m *ActiveChannel) GetShutdownPending() bool {
+	if m != nil {
+		return m.ShutdownPending
+	}
+	return false
+}
+
+func (m *ActiveChannel) GetOutgoingFrozen() bool {
+	if m != nil {
+		return m.OutgoingFrozen
+	}
+	return false
+}
+
 type ListChannelsRequest struct {
 }
 
@@ -711,15 +888,19 @@ func (m *ListChannelsResponse) GetChannels() []*ActiveChannel {
 }
 
 type Peer struct {
-	PubKey    string `protobuf:"bytes,1,opt,name=pub_key" json:"pub_key,omitempty"`
-	PeerId    int32  `protobuf:"varint,2,opt,name=peer_id" json:"peer_id,omitempty"`
-	Address   string `protobuf:"bytes,3,opt,name=address" json:"address,omitempty"`
-	BytesSent uint64 `protobuf:"varint,4,opt,name=bytes_sent" json:"bytes_sent,omitempty"`
-	BytesRecv uint64 `protobuf:"varint,5,opt,name=bytes_recv" json:"bytes_recv,omitempty"`
-	SatSent   int64  `protobuf:"varint,6,opt,name=sat_sent" json:"sat_sent,omitempty"`
-	SatRecv   int64  `protobuf:"varint,7,opt,name=sat_recv" json:"sat_recv,omitempty"`
-	Inbound   bool   `protobuf:"varint,8,opt,name=inbound" json:"inbound,omitempty"`
-	PingTime  int64  `protobuf:"varint,9,opt,name=ping_time" json:"ping_time,omitempty"`
+	PubKey             string  `protobuf:"bytes,1,opt,name=pub_key" json:"pub_key,omitempty"`
+	PeerId             int32   `protobuf:"varint,2,opt,name=peer_id" json:"peer_id,omitempty"`
+	Address            string  `protobuf:"bytes,3,opt,name=address" json:"address,omitempty"`
+	BytesSent          uint64  `protobuf:"varint,4,opt,name=bytes_sent" json:"bytes_sent,omitempty"`
+	BytesRecv          uint64  `protobuf:"varint,5,opt,name=bytes_recv" json:"bytes_recv,omitempty"`
+	SatSent            int64   `protobuf:"varint,6,opt,name=sat_sent" json:"sat_sent,omitempty"`
+	SatRecv            int64   `protobuf:"varint,7,opt,name=sat_recv" json:"sat_recv,omitempty"`
+	Inbound            bool    `protobuf:"varint,8,opt,name=inbound" json:"inbound,omitempty"`
+	PingTime           int64   `protobuf:"varint,9,opt,name=ping_time" json:"ping_time,omitempty"`
+	Uptime             float64 `protobuf:"fixed64,10,opt,name=uptime" json:"uptime,omitempty"`
+	HtlcResolutionTime int64   `protobuf:"varint,11,opt,name=htlc_resolution_time" json:"htlc_resolution_time,omitempty"`
+	QualityScore       float64 `protobuf:"fixed64,12,opt,name=quality_score" json:"quality_score,omitempty"`
+	Reputation         int64   `protobuf:"varint,13,opt,name=reputation" json:"reputation,omitempty"`
 }
 
 func (m *Peer) Reset()                    { *m = Peer{} }
@@ -790,6 +971,34 @@ func (m *Peer) GetPingTime() int64 {
 	return 0
 }
 
+func (m *Peer) GetUptime() float64 {
+	if m != nil {
+		return m.Uptime
+	}
+	return 0
+}
+
+func (m *Peer) GetHtlcResolutionTime() int64 {
+	if m != nil {
+		return m.HtlcResolutionTime
+	}
+	return 0
+}
+
+func (m *Peer) GetQualityScore() float64 {
+	if m != nil {
+		return m.QualityScore
+	}
+	return 0
+}
+
+func (m *Peer) GetReputation() int64 {
+	if m != nil {
+		return m.Reputation
+	}
+	return 0
+}
+
 type ListPeersRequest struct {
 }
 
@@ -823,15 +1032,21 @@ func (*GetInfoRequest) ProtoMessage()               {}
 func (*GetInfoRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{23} }
 
 type GetInfoResponse struct {
-	IdentityPubkey     string `protobuf:"bytes,1,opt,name=identity_pubkey" json:"identity_pubkey,omitempty"`
-	Alias              string `protobuf:"bytes,2,opt,name=alias" json:"alias,omitempty"`
-	NumPendingChannels uint32 `protobuf:"varint,3,opt,name=num_pending_channels" json:"num_pending_channels,omitempty"`
-	NumActiveChannels  uint32 `protobuf:"varint,4,opt,name=num_active_channels" json:"num_active_channels,omitempty"`
-	NumPeers           uint32 `protobuf:"varint,5,opt,name=num_peers" json:"num_peers,omitempty"`
-	BlockHeight        uint32 `protobuf:"varint,6,opt,name=block_height" json:"block_height,omitempty"`
-	BlockHash          string `protobuf:"bytes,8,opt,name=block_hash" json:"block_hash,omitempty"`
-	SyncedToChain      bool   `protobuf:"varint,9,opt,name=synced_to_chain" json:"synced_to_chain,omitempty"`
-	Testnet            bool   `protobuf:"varint,10,opt,name=testnet" json:"testnet,omitempty"`
+	IdentityPubkey            string `protobuf:"bytes,1,opt,name=identity_pubkey" json:"identity_pubkey,omitempty"`
+	Alias                     string `protobuf:"bytes,2,opt,name=alias" json:"alias,omitempty"`
+	NumPendingChannels        uint32 `protobuf:"varint,3,opt,name=num_pending_channels" json:"num_pending_channels,omitempty"`
+	NumActiveChannels         uint32 `protobuf:"varint,4,opt,name=num_active_channels" json:"num_active_channels,omitempty"`
+	NumPeers                  uint32 `protobuf:"varint,5,opt,name=num_peers" json:"num_peers,omitempty"`
+	BlockHeight               uint32 `protobuf:"varint,6,opt,name=block_height" json:"block_height,omitempty"`
+	BlockHash                 string `protobuf:"bytes,8,opt,name=block_hash" json:"block_hash,omitempty"`
+	SyncedToChain             bool   `protobuf:"varint,9,opt,name=synced_to_chain" json:"synced_to_chain,omitempty"`
+	Testnet                   bool   `protobuf:"varint,10,opt,name=testnet" json:"testnet,omitempty"`
+	NumCorrelatedHtlcs        uint64 `protobuf:"varint,11,opt,name=num_correlated_htlcs" json:"num_correlated_htlcs,omitempty"`
+	NumHashExposureRejections uint64 `protobuf:"varint,12,opt,name=num_hash_exposure_rejections" json:"num_hash_exposure_rejections,omitempty"`
+	BlockCacheHits            uint64 `protobuf:"varint,13,opt,name=block_cache_hits" json:"block_cache_hits,omitempty"`
+	BlockCacheMisses          uint64 `protobuf:"varint,14,opt,name=block_cache_misses" json:"block_cache_misses,omitempty"`
+	NumSuspectedProbes        uint64 `protobuf:"varint,15,opt,name=num_suspected_probes" json:"num_suspected_probes,omitempty"`
+	NumMaskedProbes           uint64 `protobuf:"varint,16,opt,name=num_masked_probes" json:"num_masked_probes,omitempty"`
 }
 
 func (m *GetInfoResponse) Reset()                    { *m = GetInfoResponse{} }
@@ -902,6 +1117,48 @@ func (m *GetInfoResponse) GetTestnet() bool {
 	return false
 }
 
+func (m *GetInfoResponse) GetNumCorrelatedHtlcs() uint64 {
+	if m != nil {
+		return m.NumCorrelatedHtlcs
+	}
+	return 0
+}
+
+func (m *GetInfoResponse) GetNumHashExposureRejections() uint64 {
+	if m != nil {
+		return m.NumHashExposureRejections
+	}
+	return 0
+}
+
+func (m *GetInfoResponse) GetBlockCacheHits() uint64 {
+	if m != nil {
+		return m.BlockCacheHits
+	}
+	return 0
+}
+
+func (m *GetInfoResponse) GetBlockCacheMisses() uint64 {
+	if m != nil {
+		return m.BlockCacheMisses
+	}
+	return 0
+}
+
+func (m *GetInfoResponse) GetNumSuspectedProbes() uint64 {
+	if m != nil {
+		return m.NumSuspectedProbes
+	}
+	return 0
+}
+
+func (m *GetInfoResponse) GetNumMaskedProbes() uint64 {
+	if m != nil {
+		return m.NumMaskedProbes
+	}
+	return 0
+}
+
 type ConfirmationUpdate struct {
 	BlockSha     []byte `protobuf:"bytes,1,opt,name=block_sha,json=blockSha,proto3" json:"block_sha,omitempty"`
 	BlockHeight  int32  `protobuf:"varint,2,opt,name=block_height,json=blockHeight" json:"block_height,omitempty"`
@@ -978,6 +1235,9 @@ type CloseChannelRequest struct {
 	ChannelPoint *ChannelPoint `protobuf:"bytes,1,opt,name=channel_point,json=channelPoint" json:"channel_point,omitempty"`
 	TimeLimit    int64         `protobuf:"varint,2,opt,name=time_limit,json=timeLimit" json:"time_limit,omitempty"`
 	Force        bool          `protobuf:"varint,3,opt,name=force" json:"force,omitempty"`
+	DryRun       bool          `protobuf:"varint,4,opt,name=dry_run,json=dryRun" json:"dry_run,omitempty"`
+	TargetConf   int32         `protobuf:"varint,5,opt,name=target_conf,json=targetConf" json:"target_conf,omitempty"`
+	SatPerByte   int64         `protobuf:"varint,6,opt,name=sat_per_byte,json=satPerByte" json:"sat_per_byte,omitempty"`
 }
 
 func (m *CloseChannelRequest) Reset()                    { *m = CloseChannelRequest{} }
@@ -1006,11 +1266,33 @@ func (m *CloseChannelRequest) GetForce() bool {
 	return false
 }
 
+func (m *CloseChannelRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
+func (m *CloseChannelRequest) GetTargetConf() int32 {
+	if m != nil {
+		return m.TargetConf
+	}
+	return 0
+}
+
+func (m *CloseChannelRequest) GetSatPerByte() int64 {
+	if m != nil {
+		return m.SatPerByte
+	}
+	return 0
+}
+
 type CloseStatusUpdate struct {
 	// Types that are valid to be assigned to Update:
 	//	*CloseStatusUpdate_ClosePending
 	//	*CloseStatusUpdate_Confirmation
 	//	*CloseStatusUpdate_ChanClose
+	//	*CloseStatusUpdate_DryRun
 	Update isCloseStatusUpdate_Update `protobuf_oneof:"update"`
 }
 
@@ -1032,10 +1314,14 @@ type CloseStatusUpdate_Confirmation struct {
 type CloseStatusUpdate_ChanClose struct {
 	ChanClose *ChannelCloseUpdate `protobuf:"bytes,3,opt,name=chan_close,oneof"`
 }
+type CloseStatusUpdate_DryRun struct {
+	DryRun *CloseDryRun `protobuf:"bytes,4,opt,name=dry_run,oneof"`
+}
 
 func (*CloseStatusUpdate_ClosePending) isCloseStatusUpdate_Update() {}
 func (*CloseStatusUpdate_Confirmation) isCloseStatusUpdate_Update() {}
 func (*CloseStatusUpdate_ChanClose) isCloseStatusUpdate_Update()    {}
+func (*CloseStatusUpdate_DryRun) isCloseStatusUpdate_Update()       {}
 
 func (m *CloseStatusUpdate) GetUpdate() isCloseStatusUpdate_Update {
 	if m != nil {
@@ -1065,12 +1351,20 @@ func (m *CloseStatusUpdate) GetChanClose() *ChannelCloseUpdate {
 	return nil
 }
 
+func (m *CloseStatusUpdate) GetDryRun() *CloseDryRun {
+	if x, ok := m.GetUpdate().(*CloseStatusUpdate_DryRun); ok {
+		return x.DryRun
+	}
+	return nil
+}
+
 // XXX_OneofFuncs is for the internal use of the proto package.
 func (*CloseStatusUpdate) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
 	return _CloseStatusUpdate_OneofMarshaler, _CloseStatusUpdate_OneofUnmarshaler, _CloseStatusUpdate_OneofSizer, []interface{}{
 		(*CloseStatusUpdate_ClosePending)(nil),
 		(*CloseStatusUpdate_Confirmation)(nil),
 		(*CloseStatusUpdate_ChanClose)(nil),
+		(*CloseStatusUpdate_DryRun)(nil),
 	}
 }
 
@@ -1093,6 +1387,11 @@ func _CloseStatusUpdate_OneofMarshaler(msg proto.Message, b *proto.Buffer) error
 		if err := b.EncodeMessage(x.ChanClose); err != nil {
 			return err
 		}
+	case *CloseStatusUpdate_DryRun:
+		b.EncodeVarint(4<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.DryRun); err != nil {
+			return err
+		}
 	case nil:
 	default:
 		return fmt.Errorf("CloseStatusUpdate.Update has unexpected type %T", x)
@@ -1127,6 +1426,14 @@ func _CloseStatusUpdate_OneofUnmarshaler(msg proto.Message, tag, wire int, b *pr
 		err := b.DecodeMessage(msg)
 		m.Update = &CloseStatusUpdate_ChanClose{msg}
 		return true, err
+	case 4: // update.dry_run
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(CloseDryRun)
+		err := b.DecodeMessage(msg)
+		m.Update = &CloseStatusUpdate_DryRun{msg}
+		return true, err
 	default:
 		return false, nil
 	}
@@ -1151,6 +1458,11 @@ func _CloseStatusUpdate_OneofSizer(msg proto.Message) (n int) {
 		n += proto.SizeVarint(3<<3 | proto.WireBytes)
 		n += proto.SizeVarint(uint64(s))
 		n += s
+	case *CloseStatusUpdate_DryRun:
+		s := proto.Size(x.DryRun)
+		n += proto.SizeVarint(4<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
 	case nil:
 	default:
 		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
@@ -1183,12 +1495,14 @@ func (m *PendingUpdate) GetOutputIndex() uint32 {
 }
 
 type OpenChannelRequest struct {
-	TargetPeerId       int32  `protobuf:"varint,1,opt,name=target_peer_id" json:"target_peer_id,omitempty"`
-	NodePubkey         []byte `protobuf:"bytes,2,opt,name=node_pubkey,proto3" json:"node_pubkey,omitempty"`
-	NodePubkeyString   string `protobuf:"bytes,3,opt,name=node_pubkey_string" json:"node_pubkey_string,omitempty"`
-	LocalFundingAmount int64  `protobuf:"varint,4,opt,name=local_funding_amount" json:"local_funding_amount,omitempty"`
-	PushSat            int64  `protobuf:"varint,5,opt,name=push_sat" json:"push_sat,omitempty"`
-	NumConfs           uint32 `protobuf:"varint,6,opt,name=num_confs" json:"num_confs,omitempty"`
+	TargetPeerId        int32  `protobuf:"varint,1,opt,name=target_peer_id" json:"target_peer_id,omitempty"`
+	NodePubkey          []byte `protobuf:"bytes,2,opt,name=node_pubkey,proto3" json:"node_pubkey,omitempty"`
+	NodePubkeyString    string `protobuf:"bytes,3,opt,name=node_pubkey_string" json:"node_pubkey_string,omitempty"`
+	LocalFundingAmount  int64  `protobuf:"varint,4,opt,name=local_funding_amount" json:"local_funding_amount,omitempty"`
+	PushSat             int64  `protobuf:"varint,5,opt,name=push_sat" json:"push_sat,omitempty"`
+	NumConfs            uint32 `protobuf:"varint,6,opt,name=num_confs" json:"num_confs,omitempty"`
+	RemoteFundingAmount int64  `protobuf:"varint,7,opt,name=remote_funding_amount" json:"remote_funding_amount,omitempty"`
+	ZeroConf            bool   `protobuf:"varint,8,opt,name=zero_conf" json:"zero_conf,omitempty"`
 }
 
 func (m *OpenChannelRequest) Reset()                    { *m = OpenChannelRequest{} }
@@ -1238,6 +1552,20 @@ func (m *OpenChannelRequest) GetNumConfs() uint32 {
 	return 0
 }
 
+func (m *OpenChannelRequest) GetRemoteFundingAmount() int64 {
+	if m != nil {
+		return m.RemoteFundingAmount
+	}
+	return 0
+}
+
+func (m *OpenChannelRequest) GetZeroConf() bool {
+	if m != nil {
+		return m.ZeroConf
+	}
+	return false
+}
+
 type OpenStatusUpdate struct {
 	// Types that are valid to be assigned to Update:
 	//	*OpenStatusUpdate_ChanPending
@@ -1488,6 +1816,182 @@ func (m *PendingChannelResponse_PendingChannel) GetStatus() ChannelStatus {
 	return ChannelStatus_ALL
 }
 
+type ExportChanBackupRequest struct {
+}
+
+func (m *ExportChanBackupRequest) Reset()                    { *m = ExportChanBackupRequest{} }
+func (m *ExportChanBackupRequest) String() string            { return proto.CompactTextString(m) }
+func (*ExportChanBackupRequest) ProtoMessage()               {}
+func (*ExportChanBackupRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{35} }
+
+type ChanBackupSnapshot struct {
+	MultiChanBackup []byte `protobuf:"bytes,1,opt,name=multi_chan_backup,proto3" json:"multi_chan_backup,omitempty"`
+}
+
+func (m *ChanBackupSnapshot) Reset()                    { *m = ChanBackupSnapshot{} }
+func (m *ChanBackupSnapshot) String() string            { return proto.CompactTextString(m) }
+func (*ChanBackupSnapshot) ProtoMessage()               {}
+func (*ChanBackupSnapshot) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{36} }
+
+func (m *ChanBackupSnapshot) GetMultiChanBackup() []byte {
+	if m != nil {
+		return m.MultiChanBackup
+	}
+	return nil
+}
+
+type ChannelBackupReport struct {
+	ChannelPoint   string `protobuf:"bytes,1,opt,name=channel_point" json:"channel_point,omitempty"`
+	RemotePubkey   string `protobuf:"bytes,2,opt,name=remote_pubkey" json:"remote_pubkey,omitempty"`
+	Capacity       int64  `protobuf:"varint,3,opt,name=capacity" json:"capacity,omitempty"`
+	Status         string `protobuf:"bytes,4,opt,name=status" json:"status,omitempty"`
+	Problem        string `protobuf:"bytes,5,opt,name=problem" json:"problem,omitempty"`
+	RecoveryAction string `protobuf:"bytes,6,opt,name=recovery_action" json:"recovery_action,omitempty"`
+	ChannelType    string `protobuf:"bytes,7,opt,name=channel_type,json=channelType" json:"channel_type,omitempty"`
+}
+
+func (m *ChannelBackupReport) Reset()                    { *m = ChannelBackupReport{} }
+func (m *ChannelBackupReport) String() string            { return proto.CompactTextString(m) }
+func (*ChannelBackupReport) ProtoMessage()               {}
+func (*ChannelBackupReport) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{37} }
+
+func (m *ChannelBackupReport) GetChannelPoint() string {
+	if m != nil {
+		return m.ChannelPoint
+	}
+	return ""
+}
+
+func (m *ChannelBackupReport) GetRemotePubkey() string {
+	if m != nil {
+		return m.RemotePubkey
+	}
+	return ""
+}
+
+func (m *ChannelBackupReport) GetCapacity() int64 {
+	if m != nil {
+		return m.Capacity
+	}
+	return 0
+}
+
+func (m *ChannelBackupReport) GetStatus() string {
+	if m != nil {
+		return m.Status
+	}
+	return ""
+}
+
+func (m *ChannelBackupReport) GetProblem() string {
+	if m != nil {
+		return m.Problem
+	}
+	return ""
+}
+
+func (m *ChannelBackupReport) GetRecoveryAction() string {
+	if m != nil {
+		return m.RecoveryAction
+	}
+	return ""
+}
+
+func (m *ChannelBackupReport) GetChannelType() string {
+	if m != nil {
+		return m.ChannelType
+	}
+	return ""
+}
+
+type VerifyChanBackupResponse struct {
+	Channels []*ChannelBackupReport `protobuf:"bytes,1,rep,name=channels" json:"channels,omitempty"`
+}
+
+func (m *VerifyChanBackupResponse) Reset()                    { *m = VerifyChanBackupResponse{} }
+func (m *VerifyChanBackupResponse) String() string            { return proto.CompactTextString(m) }
+func (*VerifyChanBackupResponse) ProtoMessage()               {}
+func (*VerifyChanBackupResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{38} }
+
+func (m *VerifyChanBackupResponse) GetChannels() []*ChannelBackupReport {
+	if m != nil {
+		return m.Channels
+	}
+	return nil
+}
+
+type RestoreChanBackupRequest struct {
+	MultiChanBackup []byte `protobuf:"bytes,1,opt,name=multi_chan_backup,proto3" json:"multi_chan_backup,omitempty"`
+	DryRun          bool   `protobuf:"varint,2,opt,name=dry_run" json:"dry_run,omitempty"`
+}
+
+func (m *RestoreChanBackupRequest) Reset()                    { *m = RestoreChanBackupRequest{} }
+func (m *RestoreChanBackupRequest) String() string            { return proto.CompactTextString(m) }
+func (*RestoreChanBackupRequest) ProtoMessage()               {}
+func (*RestoreChanBackupRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{39} }
+
+func (m *RestoreChanBackupRequest) GetMultiChanBackup() []byte {
+	if m != nil {
+		return m.MultiChanBackup
+	}
+	return nil
+}
+
+func (m *RestoreChanBackupRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
+type RestoreChanBackupResponse struct {
+	Channels []*ChannelBackupReport `protobuf:"bytes,1,rep,name=channels" json:"channels,omitempty"`
+}
+
+func (m *RestoreChanBackupResponse) Reset()                    { *m = RestoreChanBackupResponse{} }
+func (m *RestoreChanBackupResponse) String() string            { return proto.CompactTextString(m) }
+func (*RestoreChanBackupResponse) ProtoMessage()               {}
+func (*RestoreChanBackupResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{40} }
+
+func (m *RestoreChanBackupResponse) GetChannels() []*ChannelBackupReport {
+	if m != nil {
+		return m.Channels
+	}
+	return nil
+}
+
+type ImportChannelRecoveryRequest struct {
+	RecoveryJson []byte `protobuf:"bytes,1,opt,name=recovery_json,proto3" json:"recovery_json,omitempty"`
+}
+
+func (m *ImportChannelRecoveryRequest) Reset()                    { *m = ImportChannelRecoveryRequest{} }
+func (m *ImportChannelRecoveryRequest) String() string            { return proto.CompactTextString(m) }
+func (*ImportChannelRecoveryRequest) ProtoMessage()               {}
+func (*ImportChannelRecoveryRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{41} }
+
+func (m *ImportChannelRecoveryRequest) GetRecoveryJson() []byte {
+	if m != nil {
+		return m.RecoveryJson
+	}
+	return nil
+}
+
+type ImportChannelRecoveryResponse struct {
+	ChannelPoints []string `protobuf:"bytes,1,rep,name=channel_points" json:"channel_points,omitempty"`
+}
+
+func (m *ImportChannelRecoveryResponse) Reset()                    { *m = ImportChannelRecoveryResponse{} }
+func (m *ImportChannelRecoveryResponse) String() string            { return proto.CompactTextString(m) }
+func (*ImportChannelRecoveryResponse) ProtoMessage()               {}
+func (*ImportChannelRecoveryResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{42} }
+
+func (m *ImportChannelRecoveryResponse) GetChannelPoints() []string {
+	if m != nil {
+		return m.ChannelPoints
+	}
+	return nil
+}
+
 type WalletBalanceRequest struct {
 	WitnessOnly bool `protobuf:"varint,1,opt,name=witness_only,json=witnessOnly" json:"witness_only,omitempty"`
 }
@@ -1495,7 +1999,7 @@ type WalletBalanceRequest struct {
 func (m *WalletBalanceRequest) Reset()                    { *m = WalletBalanceRequest{} }
 func (m *WalletBalanceRequest) String() string            { return proto.CompactTextString(m) }
 func (*WalletBalanceRequest) ProtoMessage()               {}
-func (*WalletBalanceRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{35} }
+func (*WalletBalanceRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{43} }
 
 func (m *WalletBalanceRequest) GetWitnessOnly() bool {
 	if m != nil {
@@ -1511,7 +2015,7 @@ type WalletBalanceResponse struct {
 func (m *WalletBalanceResponse) Reset()                    { *m = WalletBalanceResponse{} }
 func (m *WalletBalanceResponse) String() string            { return proto.CompactTextString(m) }
 func (*WalletBalanceResponse) ProtoMessage()               {}
-func (*WalletBalanceResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{36} }
+func (*WalletBalanceResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{44} }
 
 func (m *WalletBalanceResponse) GetBalance() float64 {
 	if m != nil {
@@ -1526,7 +2030,7 @@ type ChannelBalanceRequest struct {
 func (m *ChannelBalanceRequest) Reset()                    { *m = ChannelBalanceRequest{} }
 func (m *ChannelBalanceRequest) String() string            { return proto.CompactTextString(m) }
 func (*ChannelBalanceRequest) ProtoMessage()               {}
-func (*ChannelBalanceRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{37} }
+func (*ChannelBalanceRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{45} }
 
 type ChannelBalanceResponse struct {
 	Balance int64 `protobuf:"varint,1,opt,name=balance" json:"balance,omitempty"`
@@ -1535,7 +2039,7 @@ type ChannelBalanceResponse struct {
 func (m *ChannelBalanceResponse) Reset()                    { *m = ChannelBalanceResponse{} }
 func (m *ChannelBalanceResponse) String() string            { return proto.CompactTextString(m) }
 func (*ChannelBalanceResponse) ProtoMessage()               {}
-func (*ChannelBalanceResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{38} }
+func (*ChannelBalanceResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{46} }
 
 func (m *ChannelBalanceResponse) GetBalance() int64 {
 	if m != nil {
@@ -1552,7 +2056,7 @@ type RouteRequest struct {
 func (m *RouteRequest) Reset()                    { *m = RouteRequest{} }
 func (m *RouteRequest) String() string            { return proto.CompactTextString(m) }
 func (*RouteRequest) ProtoMessage()               {}
-func (*RouteRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{39} }
+func (*RouteRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{47} }
 
 func (m *RouteRequest) GetPubKey() string {
 	if m != nil {
@@ -1578,7 +2082,7 @@ type Hop struct {
 func (m *Hop) Reset()                    { *m = Hop{} }
 func (m *Hop) String() string            { return proto.CompactTextString(m) }
 func (*Hop) ProtoMessage()               {}
-func (*Hop) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{40} }
+func (*Hop) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{48} }
 
 func (m *Hop) GetChanId() uint64 {
 	if m != nil {
@@ -1618,7 +2122,7 @@ type Route struct {
 func (m *Route) Reset()                    { *m = Route{} }
 func (m *Route) String() string            { return proto.CompactTextString(m) }
 func (*Route) ProtoMessage()               {}
-func (*Route) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{41} }
+func (*Route) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{49} }
 
 func (m *Route) GetTotalTimeLock() uint32 {
 	if m != nil {
@@ -1655,7 +2159,7 @@ type NodeInfoRequest struct {
 func (m *NodeInfoRequest) Reset()                    { *m = NodeInfoRequest{} }
 func (m *NodeInfoRequest) String() string            { return proto.CompactTextString(m) }
 func (*NodeInfoRequest) ProtoMessage()               {}
-func (*NodeInfoRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{42} }
+func (*NodeInfoRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{50} }
 
 func (m *NodeInfoRequest) GetPubKey() string {
 	if m != nil {
@@ -1673,7 +2177,7 @@ type NodeInfo struct {
 func (m *NodeInfo) Reset()                    { *m = NodeInfo{} }
 func (m *NodeInfo) String() string            { return proto.CompactTextString(m) }
 func (*NodeInfo) ProtoMessage()               {}
-func (*NodeInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{43} }
+func (*NodeInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{51} }
 
 func (m *NodeInfo) GetNode() *LightningNode {
 	if m != nil {
@@ -1706,7 +2210,7 @@ type LightningNode struct {
 func (m *LightningNode) Reset()                    { *m = LightningNode{} }
 func (m *LightningNode) String() string            { return proto.CompactTextString(m) }
 func (*LightningNode) ProtoMessage()               {}
-func (*LightningNode) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{44} }
+func (*LightningNode) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{52} }
 
 func (m *LightningNode) GetLastUpdate() uint32 {
 	if m != nil {
@@ -1746,7 +2250,7 @@ type RoutingPolicy struct {
 func (m *RoutingPolicy) Reset()                    { *m = RoutingPolicy{} }
 func (m *RoutingPolicy) String() string            { return proto.CompactTextString(m) }
 func (*RoutingPolicy) ProtoMessage()               {}
-func (*RoutingPolicy) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{45} }
+func (*RoutingPolicy) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{53} }
 
 func (m *RoutingPolicy) GetTimeLockDelta() uint32 {
 	if m != nil {
@@ -1790,7 +2294,7 @@ type ChannelEdge struct {
 func (m *ChannelEdge) Reset()                    { *m = ChannelEdge{} }
 func (m *ChannelEdge) String() string            { return proto.CompactTextString(m) }
 func (*ChannelEdge) ProtoMessage()               {}
-func (*ChannelEdge) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{46} }
+func (*ChannelEdge) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{54} }
 
 func (m *ChannelEdge) GetChannelId() uint64 {
 	if m != nil {
@@ -1854,7 +2358,7 @@ type ChannelGraphRequest struct {
 func (m *ChannelGraphRequest) Reset()                    { *m = ChannelGraphRequest{} }
 func (m *ChannelGraphRequest) String() string            { return proto.CompactTextString(m) }
 func (*ChannelGraphRequest) ProtoMessage()               {}
-func (*ChannelGraphRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{47} }
+func (*ChannelGraphRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{55} }
 
 type ChannelGraph struct {
 	Nodes []*LightningNode `protobuf:"bytes,1,rep,name=nodes" json:"nodes,omitempty"`
@@ -1864,7 +2368,7 @@ type ChannelGraph struct {
 func (m *ChannelGraph) Reset()                    { *m = ChannelGraph{} }
 func (m *ChannelGraph) String() string            { return proto.CompactTextString(m) }
 func (*ChannelGraph) ProtoMessage()               {}
-func (*ChannelGraph) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{48} }
+func (*ChannelGraph) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{56} }
 
 func (m *ChannelGraph) GetNodes() []*LightningNode {
 	if m != nil {
@@ -1887,7 +2391,7 @@ type ChanInfoRequest struct {
 func (m *ChanInfoRequest) Reset()                    { *m = ChanInfoRequest{} }
 func (m *ChanInfoRequest) String() string            { return proto.CompactTextString(m) }
 func (*ChanInfoRequest) ProtoMessage()               {}
-func (*ChanInfoRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{49} }
+func (*ChanInfoRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{57} }
 
 func (m *ChanInfoRequest) GetChanId() uint64 {
 	if m != nil {
@@ -1902,7 +2406,7 @@ type NetworkInfoRequest struct {
 func (m *NetworkInfoRequest) Reset()                    { *m = NetworkInfoRequest{} }
 func (m *NetworkInfoRequest) String() string            { return proto.CompactTextString(m) }
 func (*NetworkInfoRequest) ProtoMessage()               {}
-func (*NetworkInfoRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{50} }
+func (*NetworkInfoRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{58} }
 
 type NetworkInfo struct {
 	GraphDiameter        uint32  `protobuf:"varint,1,opt,name=graph_diameter" json:"graph_diameter,omitempty"`
@@ -1919,7 +2423,7 @@ type NetworkInfo struct {
 func (m *NetworkInfo) Reset()                    { *m = NetworkInfo{} }
 func (m *NetworkInfo) String() string            { return proto.CompactTextString(m) }
 func (*NetworkInfo) ProtoMessage()               {}
-func (*NetworkInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{51} }
+func (*NetworkInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{59} }
 
 func (m *NetworkInfo) GetGraphDiameter() uint32 {
 	if m != nil {
@@ -1990,7 +2494,7 @@ type GraphTopologySubscription struct {
 func (m *GraphTopologySubscription) Reset()                    { *m = GraphTopologySubscription{} }
 func (m *GraphTopologySubscription) String() string            { return proto.CompactTextString(m) }
 func (*GraphTopologySubscription) ProtoMessage()               {}
-func (*GraphTopologySubscription) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{52} }
+func (*GraphTopologySubscription) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{60} }
 
 type GraphTopologyUpdate struct {
 	NodeUpdates    []*NodeUpdate          `protobuf:"bytes,1,rep,name=node_updates,json=nodeUpdates" json:"node_updates,omitempty"`
@@ -2001,7 +2505,7 @@ type GraphTopologyUpdate struct {
 func (m *GraphTopologyUpdate) Reset()                    { *m = GraphTopologyUpdate{} }
 func (m *GraphTopologyUpdate) String() string            { return proto.CompactTextString(m) }
 func (*GraphTopologyUpdate) ProtoMessage()               {}
-func (*GraphTopologyUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{53} }
+func (*GraphTopologyUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{61} }
 
 func (m *GraphTopologyUpdate) GetNodeUpdates() []*NodeUpdate {
 	if m != nil {
@@ -2034,7 +2538,7 @@ type NodeUpdate struct {
 func (m *NodeUpdate) Reset()                    { *m = NodeUpdate{} }
 func (m *NodeUpdate) String() string            { return proto.CompactTextString(m) }
 func (*NodeUpdate) ProtoMessage()               {}
-func (*NodeUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{54} }
+func (*NodeUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{62} }
 
 func (m *NodeUpdate) GetAddresses() []string {
 	if m != nil {
@@ -2076,7 +2580,7 @@ type ChannelEdgeUpdate struct {
 func (m *ChannelEdgeUpdate) Reset()                    { *m = ChannelEdgeUpdate{} }
 func (m *ChannelEdgeUpdate) String() string            { return proto.CompactTextString(m) }
 func (*ChannelEdgeUpdate) ProtoMessage()               {}
-func (*ChannelEdgeUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{55} }
+func (*ChannelEdgeUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{63} }
 
 func (m *ChannelEdgeUpdate) GetChanId() uint64 {
 	if m != nil {
@@ -2130,7 +2634,7 @@ type ClosedChannelUpdate struct {
 func (m *ClosedChannelUpdate) Reset()                    { *m = ClosedChannelUpdate{} }
 func (m *ClosedChannelUpdate) String() string            { return proto.CompactTextString(m) }
 func (*ClosedChannelUpdate) ProtoMessage()               {}
-func (*ClosedChannelUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{56} }
+func (*ClosedChannelUpdate) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{64} }
 
 func (m *ClosedChannelUpdate) GetChanId() uint64 {
 	if m != nil {
@@ -2167,7 +2671,7 @@ type SetAliasRequest struct {
 func (m *SetAliasRequest) Reset()                    { *m = SetAliasRequest{} }
 func (m *SetAliasRequest) String() string            { return proto.CompactTextString(m) }
 func (*SetAliasRequest) ProtoMessage()               {}
-func (*SetAliasRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{57} }
+func (*SetAliasRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{65} }
 
 func (m *SetAliasRequest) GetNewAlias() string {
 	if m != nil {
@@ -2182,24 +2686,29 @@ type SetAliasResponse struct {
 func (m *SetAliasResponse) Reset()                    { *m = SetAliasResponse{} }
 func (m *SetAliasResponse) String() string            { return proto.CompactTextString(m) }
 func (*SetAliasResponse) ProtoMessage()               {}
-func (*SetAliasResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{58} }
+func (*SetAliasResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{66} }
 
 type Invoice struct {
-	Memo           string `protobuf:"bytes,1,opt,name=memo" json:"memo,omitempty"`
-	Receipt        []byte `protobuf:"bytes,2,opt,name=receipt,proto3" json:"receipt,omitempty"`
-	RPreimage      []byte `protobuf:"bytes,3,opt,name=r_preimage,proto3" json:"r_preimage,omitempty"`
-	RHash          []byte `protobuf:"bytes,4,opt,name=r_hash,proto3" json:"r_hash,omitempty"`
-	Value          int64  `protobuf:"varint,5,opt,name=value" json:"value,omitempty"`
-	Settled        bool   `protobuf:"varint,6,opt,name=settled" json:"settled,omitempty"`
-	CreationDate   int64  `protobuf:"varint,7,opt,name=creation_date" json:"creation_date,omitempty"`
-	SettleDate     int64  `protobuf:"varint,8,opt,name=settle_date" json:"settle_date,omitempty"`
-	PaymentRequest string `protobuf:"bytes,9,opt,name=payment_request" json:"payment_request,omitempty"`
+	Memo            string `protobuf:"bytes,1,opt,name=memo" json:"memo,omitempty"`
+	Receipt         []byte `protobuf:"bytes,2,opt,name=receipt,proto3" json:"receipt,omitempty"`
+	RPreimage       []byte `protobuf:"bytes,3,opt,name=r_preimage,proto3" json:"r_preimage,omitempty"`
+	RHash           []byte `protobuf:"bytes,4,opt,name=r_hash,proto3" json:"r_hash,omitempty"`
+	Value           int64  `protobuf:"varint,5,opt,name=value" json:"value,omitempty"`
+	Settled         bool   `protobuf:"varint,6,opt,name=settled" json:"settled,omitempty"`
+	CreationDate    int64  `protobuf:"varint,7,opt,name=creation_date" json:"creation_date,omitempty"`
+	SettleDate      int64  `protobuf:"varint,8,opt,name=settle_date" json:"settle_date,omitempty"`
+	PaymentRequest  string `protobuf:"bytes,9,opt,name=payment_request" json:"payment_request,omitempty"`
+	OnchainFallback bool   `protobuf:"varint,10,opt,name=onchain_fallback" json:"onchain_fallback,omitempty"`
+	QrCode          bool   `protobuf:"varint,11,opt,name=qr_code" json:"qr_code,omitempty"`
+	DerivePreimage  bool   `protobuf:"varint,12,opt,name=derive_preimage" json:"derive_preimage,omitempty"`
+	AddIndex        uint32 `protobuf:"varint,13,opt,name=add_index" json:"add_index,omitempty"`
+	Expiry          int64  `protobuf:"varint,14,opt,name=expiry" json:"expiry,omitempty"`
 }
 
 func (m *Invoice) Reset()                    { *m = Invoice{} }
 func (m *Invoice) String() string            { return proto.CompactTextString(m) }
 func (*Invoice) ProtoMessage()               {}
-func (*Invoice) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{59} }
+func (*Invoice) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{67} }
 
 func (m *Invoice) GetMemo() string {
 	if m != nil {
@@ -2264,15 +2773,53 @@ func (m *Invoice) GetPaymentRequest() string {
 	return ""
 }
 
+func (m *Invoice) GetOnchainFallback() bool {
+	if m != nil {
+		return m.OnchainFallback
+	}
+	return false
+}
+
+func (m *Invoice) GetQrCode() bool {
+	if m != nil {
+		return m.QrCode
+	}
+	return false
+}
+
+func (m *Invoice) GetDerivePreimage() bool {
+	if m != nil {
+		return m.DerivePreimage
+	}
+	return false
+}
+
+func (m *Invoice) GetAddIndex() uint32 {
+	if m != nil {
+		return m.AddIndex
+	}
+	return 0
+}
+
+func (m *Invoice) GetExpiry() int64 {
+	if m != nil {
+		return m.Expiry
+	}
+	return 0
+}
+
 type AddInvoiceResponse struct {
 	RHash          []byte `protobuf:"bytes,1,opt,name=r_hash,proto3" json:"r_hash,omitempty"`
 	PaymentRequest string `protobuf:"bytes,2,opt,name=payment_request" json:"payment_request,omitempty"`
+	UnifiedUri     string `protobuf:"bytes,3,opt,name=unified_uri" json:"unified_uri,omitempty"`
+	FallbackAddr   string `protobuf:"bytes,4,opt,name=fallback_addr" json:"fallback_addr,omitempty"`
+	QrPng          []byte `protobuf:"bytes,5,opt,name=qr_png,proto3" json:"qr_png,omitempty"`
 }
 
 func (m *AddInvoiceResponse) Reset()                    { *m = AddInvoiceResponse{} }
 func (m *AddInvoiceResponse) String() string            { return proto.CompactTextString(m) }
 func (*AddInvoiceResponse) ProtoMessage()               {}
-func (*AddInvoiceResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{60} }
+func (*AddInvoiceResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{68} }
 
 func (m *AddInvoiceResponse) GetRHash() []byte {
 	if m != nil {
@@ -2288,16 +2835,37 @@ func (m *AddInvoiceResponse) GetPaymentRequest() string {
 	return ""
 }
 
-type PaymentHash struct {
-	RHashStr string `protobuf:"bytes,1,opt,name=r_hash_str" json:"r_hash_str,omitempty"`
-	RHash    []byte `protobuf:"bytes,2,opt,name=r_hash,proto3" json:"r_hash,omitempty"`
+func (m *AddInvoiceResponse) GetUnifiedUri() string {
+	if m != nil {
+		return m.UnifiedUri
+	}
+	return ""
 }
 
-func (m *PaymentHash) Reset()                    { *m = PaymentHash{} }
-func (m *PaymentHash) String() string            { return proto.CompactTextString(m) }
-func (*PaymentHash) ProtoMessage()               {}
-func (*PaymentHash) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{61} }
-
+func (m *AddInvoiceResponse) GetFallbackAddr() string {
+	if m != nil {
+		return m.FallbackAddr
+	}
+	return ""
+}
+
+func (m *AddInvoiceResponse) GetQrPng() []byte {
+	if m != nil {
+		return m.QrPng
+	}
+	return nil
+}
+
+type PaymentHash struct {
+	RHashStr string `protobuf:"bytes,1,opt,name=r_hash_str" json:"r_hash_str,omitempty"`
+	RHash    []byte `protobuf:"bytes,2,opt,name=r_hash,proto3" json:"r_hash,omitempty"`
+}
+
+func (m *PaymentHash) Reset()                    { *m = PaymentHash{} }
+func (m *PaymentHash) String() string            { return proto.CompactTextString(m) }
+func (*PaymentHash) ProtoMessage()               {}
+func (*PaymentHash) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{69} }
+
 func (m *PaymentHash) GetRHashStr() string {
 	if m != nil {
 		return m.RHashStr
@@ -2319,7 +2887,7 @@ type ListInvoiceRequest struct {
 func (m *ListInvoiceRequest) Reset()                    { *m = ListInvoiceRequest{} }
 func (m *ListInvoiceRequest) String() string            { return proto.CompactTextString(m) }
 func (*ListInvoiceRequest) ProtoMessage()               {}
-func (*ListInvoiceRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{62} }
+func (*ListInvoiceRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{70} }
 
 func (m *ListInvoiceRequest) GetPendingOnly() bool {
 	if m != nil {
@@ -2335,7 +2903,7 @@ type ListInvoiceResponse struct {
 func (m *ListInvoiceResponse) Reset()                    { *m = ListInvoiceResponse{} }
 func (m *ListInvoiceResponse) String() string            { return proto.CompactTextString(m) }
 func (*ListInvoiceResponse) ProtoMessage()               {}
-func (*ListInvoiceResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{63} }
+func (*ListInvoiceResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{71} }
 
 func (m *ListInvoiceResponse) GetInvoices() []*Invoice {
 	if m != nil {
@@ -2350,7 +2918,159 @@ type InvoiceSubscription struct {
 func (m *InvoiceSubscription) Reset()                    { *m = InvoiceSubscription{} }
 func (m *InvoiceSubscription) String() string            { return proto.CompactTextString(m) }
 func (*InvoiceSubscription) ProtoMessage()               {}
-func (*InvoiceSubscription) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{64} }
+func (*InvoiceSubscription) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{72} }
+
+type Offer struct {
+	Memo         string `protobuf:"bytes,1,opt,name=memo" json:"memo,omitempty"`
+	Value        int64  `protobuf:"varint,2,opt,name=value" json:"value,omitempty"`
+	OfferId      []byte `protobuf:"bytes,3,opt,name=offer_id,proto3" json:"offer_id,omitempty"`
+	CreationDate int64  `protobuf:"varint,4,opt,name=creation_date" json:"creation_date,omitempty"`
+	NumInvoices  uint64 `protobuf:"varint,5,opt,name=num_invoices" json:"num_invoices,omitempty"`
+	OfferCode    string `protobuf:"bytes,6,opt,name=offer_code" json:"offer_code,omitempty"`
+}
+
+func (m *Offer) Reset()                    { *m = Offer{} }
+func (m *Offer) String() string            { return proto.CompactTextString(m) }
+func (*Offer) ProtoMessage()               {}
+func (*Offer) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{73} }
+
+func (m *Offer) GetMemo() string {
+	if m != nil {
+		return m.Memo
+	}
+	return ""
+}
+
+func (m *Offer) GetValue() int64 {
+	if m != nil {
+		return m.Value
+	}
+	return 0
+}
+
+func (m *Offer) GetOfferId() []byte {
+	if m != nil {
+		return m.OfferId
+	}
+	return nil
+}
+
+func (m *Offer) GetCreationDate() int64 {
+	if m != nil {
+		return m.CreationDate
+	}
+	return 0
+}
+
+func (m *Offer) GetNumInvoices() uint64 {
+	if m != nil {
+		return m.NumInvoices
+	}
+	return 0
+}
+
+func (m *Offer) GetOfferCode() string {
+	if m != nil {
+		return m.OfferCode
+	}
+	return ""
+}
+
+type AddOfferResponse struct {
+	OfferId   []byte `protobuf:"bytes,1,opt,name=offer_id,proto3" json:"offer_id,omitempty"`
+	OfferCode string `protobuf:"bytes,2,opt,name=offer_code" json:"offer_code,omitempty"`
+}
+
+func (m *AddOfferResponse) Reset()                    { *m = AddOfferResponse{} }
+func (m *AddOfferResponse) String() string            { return proto.CompactTextString(m) }
+func (*AddOfferResponse) ProtoMessage()               {}
+func (*AddOfferResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{74} }
+
+func (m *AddOfferResponse) GetOfferId() []byte {
+	if m != nil {
+		return m.OfferId
+	}
+	return nil
+}
+
+func (m *AddOfferResponse) GetOfferCode() string {
+	if m != nil {
+		return m.OfferCode
+	}
+	return ""
+}
+
+type ListOffersRequest struct {
+}
+
+func (m *ListOffersRequest) Reset()                    { *m = ListOffersRequest{} }
+func (m *ListOffersRequest) String() string            { return proto.CompactTextString(m) }
+func (*ListOffersRequest) ProtoMessage()               {}
+func (*ListOffersRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{75} }
+
+type ListOffersResponse struct {
+	Offers []*Offer `protobuf:"bytes,1,rep,name=offers" json:"offers,omitempty"`
+}
+
+func (m *ListOffersResponse) Reset()                    { *m = ListOffersResponse{} }
+func (m *ListOffersResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListOffersResponse) ProtoMessage()               {}
+func (*ListOffersResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{76} }
+
+func (m *ListOffersResponse) GetOffers() []*Offer {
+	if m != nil {
+		return m.Offers
+	}
+	return nil
+}
+
+type OfferInvoiceRequest struct {
+	OfferCode string `protobuf:"bytes,1,opt,name=offer_code" json:"offer_code,omitempty"`
+	Amt       int64  `protobuf:"varint,2,opt,name=amt" json:"amt,omitempty"`
+}
+
+func (m *OfferInvoiceRequest) Reset()                    { *m = OfferInvoiceRequest{} }
+func (m *OfferInvoiceRequest) String() string            { return proto.CompactTextString(m) }
+func (*OfferInvoiceRequest) ProtoMessage()               {}
+func (*OfferInvoiceRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{77} }
+
+func (m *OfferInvoiceRequest) GetOfferCode() string {
+	if m != nil {
+		return m.OfferCode
+	}
+	return ""
+}
+
+func (m *OfferInvoiceRequest) GetAmt() int64 {
+	if m != nil {
+		return m.Amt
+	}
+	return 0
+}
+
+type OfferInvoiceResponse struct {
+	RHash          []byte `protobuf:"bytes,1,opt,name=r_hash,proto3" json:"r_hash,omitempty"`
+	PaymentRequest string `protobuf:"bytes,2,opt,name=payment_request" json:"payment_request,omitempty"`
+}
+
+func (m *OfferInvoiceResponse) Reset()                    { *m = OfferInvoiceResponse{} }
+func (m *OfferInvoiceResponse) String() string            { return proto.CompactTextString(m) }
+func (*OfferInvoiceResponse) ProtoMessage()               {}
+func (*OfferInvoiceResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{78} }
+
+func (m *OfferInvoiceResponse) GetRHash() []byte {
+	if m != nil {
+		return m.RHash
+	}
+	return nil
+}
+
+func (m *OfferInvoiceResponse) GetPaymentRequest() string {
+	if m != nil {
+		return m.PaymentRequest
+	}
+	return ""
+}
 
 type Payment struct {
 	PaymentHash  string   `protobuf:"bytes,1,opt,name=payment_hash" json:"payment_hash,omitempty"`
@@ -2363,7 +3083,7 @@ type Payment struct {
 func (m *Payment) Reset()                    { *m = Payment{} }
 func (m *Payment) String() string            { return proto.CompactTextString(m) }
 func (*Payment) ProtoMessage()               {}
-func (*Payment) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{65} }
+func (*Payment) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{79} }
 
 func (m *Payment) GetPaymentHash() string {
 	if m != nil {
@@ -2406,7 +3126,7 @@ type ListPaymentsRequest struct {
 func (m *ListPaymentsRequest) Reset()                    { *m = ListPaymentsRequest{} }
 func (m *ListPaymentsRequest) String() string            { return proto.CompactTextString(m) }
 func (*ListPaymentsRequest) ProtoMessage()               {}
-func (*ListPaymentsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{66} }
+func (*ListPaymentsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{80} }
 
 type ListPaymentsResponse struct {
 	Payments []*Payment `protobuf:"bytes,1,rep,name=payments" json:"payments,omitempty"`
@@ -2415,7 +3135,7 @@ type ListPaymentsResponse struct {
 func (m *ListPaymentsResponse) Reset()                    { *m = ListPaymentsResponse{} }
 func (m *ListPaymentsResponse) String() string            { return proto.CompactTextString(m) }
 func (*ListPaymentsResponse) ProtoMessage()               {}
-func (*ListPaymentsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{67} }
+func (*ListPaymentsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{81} }
 
 func (m *ListPaymentsResponse) GetPayments() []*Payment {
 	if m != nil {
@@ -2425,20 +3145,36 @@ func (m *ListPaymentsResponse) GetPayments() []*Payment {
 }
 
 type DeleteAllPaymentsRequest struct {
+	DryRun bool `protobuf:"varint,1,opt,name=dry_run" json:"dry_run,omitempty"`
 }
 
 func (m *DeleteAllPaymentsRequest) Reset()                    { *m = DeleteAllPaymentsRequest{} }
 func (m *DeleteAllPaymentsRequest) String() string            { return proto.CompactTextString(m) }
 func (*DeleteAllPaymentsRequest) ProtoMessage()               {}
-func (*DeleteAllPaymentsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{68} }
+func (*DeleteAllPaymentsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{82} }
+
+func (m *DeleteAllPaymentsRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
 
 type DeleteAllPaymentsResponse struct {
+	NumPayments uint64 `protobuf:"varint,1,opt,name=num_payments" json:"num_payments,omitempty"`
 }
 
 func (m *DeleteAllPaymentsResponse) Reset()                    { *m = DeleteAllPaymentsResponse{} }
 func (m *DeleteAllPaymentsResponse) String() string            { return proto.CompactTextString(m) }
 func (*DeleteAllPaymentsResponse) ProtoMessage()               {}
-func (*DeleteAllPaymentsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{69} }
+func (*DeleteAllPaymentsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{83} }
+
+func (m *DeleteAllPaymentsResponse) GetNumPayments() uint64 {
+	if m != nil {
+		return m.NumPayments
+	}
+	return 0
+}
 
 type DebugLevelRequest struct {
 	Show      bool   `protobuf:"varint,1,opt,name=show" json:"show,omitempty"`
@@ -2448,7 +3184,7 @@ type DebugLevelRequest struct {
 func (m *DebugLevelRequest) Reset()                    { *m = DebugLevelRequest{} }
 func (m *DebugLevelRequest) String() string            { return proto.CompactTextString(m) }
 func (*DebugLevelRequest) ProtoMessage()               {}
-func (*DebugLevelRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{70} }
+func (*DebugLevelRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{84} }
 
 func (m *DebugLevelRequest) GetShow() bool {
 	if m != nil {
@@ -2471,7 +3207,7 @@ type DebugLevelResponse struct {
 func (m *DebugLevelResponse) Reset()                    { *m = DebugLevelResponse{} }
 func (m *DebugLevelResponse) String() string            { return proto.CompactTextString(m) }
 func (*DebugLevelResponse) ProtoMessage()               {}
-func (*DebugLevelResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{71} }
+func (*DebugLevelResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{85} }
 
 func (m *DebugLevelResponse) GetSubSystems() string {
 	if m != nil {
@@ -2480,6 +3216,22 @@ func (m *DebugLevelResponse) GetSubSystems() string {
 	return ""
 }
 
+type ChannelStateExport struct {
+	StateJson string `protobuf:"bytes,1,opt,name=state_json" json:"state_json,omitempty"`
+}
+
+func (m *ChannelStateExport) Reset()                    { *m = ChannelStateExport{} }
+func (m *ChannelStateExport) String() string            { return proto.CompactTextString(m) }
+func (*ChannelStateExport) ProtoMessage()               {}
+func (*ChannelStateExport) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{86} }
+
+func (m *ChannelStateExport) GetStateJson() string {
+	if m != nil {
+		return m.StateJson
+	}
+	return ""
+}
+
 type PayReqString struct {
 	PayReq string `protobuf:"bytes,1,opt,name=pay_req,json=payReq" json:"pay_req,omitempty"`
 }
@@ -2487,7 +3239,7 @@ type PayReqString struct {
 func (m *PayReqString) Reset()                    { *m = PayReqString{} }
 func (m *PayReqString) String() string            { return proto.CompactTextString(m) }
 func (*PayReqString) ProtoMessage()               {}
-func (*PayReqString) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{72} }
+func (*PayReqString) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{87} }
 
 func (m *PayReqString) GetPayReq() string {
 	if m != nil {
@@ -2497,15 +3249,19 @@ func (m *PayReqString) GetPayReq() string {
 }
 
 type PayReq struct {
-	Destination string `protobuf:"bytes,1,opt,name=destination" json:"destination,omitempty"`
-	PaymentHash string `protobuf:"bytes,2,opt,name=payment_hash" json:"payment_hash,omitempty"`
-	NumSatoshis int64  `protobuf:"varint,3,opt,name=num_satoshis" json:"num_satoshis,omitempty"`
+	Destination string       `protobuf:"bytes,1,opt,name=destination" json:"destination,omitempty"`
+	PaymentHash string       `protobuf:"bytes,2,opt,name=payment_hash" json:"payment_hash,omitempty"`
+	NumSatoshis int64        `protobuf:"varint,3,opt,name=num_satoshis" json:"num_satoshis,omitempty"`
+	Timestamp   int64        `protobuf:"varint,4,opt,name=timestamp" json:"timestamp,omitempty"`
+	Expiry      int64        `protobuf:"varint,5,opt,name=expiry" json:"expiry,omitempty"`
+	Description string       `protobuf:"bytes,6,opt,name=description" json:"description,omitempty"`
+	RouteHints  []*RouteHint `protobuf:"bytes,7,rep,name=route_hints" json:"route_hints,omitempty"`
 }
 
 func (m *PayReq) Reset()                    { *m = PayReq{} }
 func (m *PayReq) String() string            { return proto.CompactTextString(m) }
 func (*PayReq) ProtoMessage()               {}
-func (*PayReq) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{73} }
+func (*PayReq) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{88} }
 
 func (m *PayReq) GetDestination() string {
 	if m != nil {
@@ -2528,1231 +3284,6130 @@ func (m *PayReq) GetNumSatoshis() int64 {
 	return 0
 }
 
-func init() {
-	proto.RegisterType((*Transaction)(nil), "lnrpc.Transaction")
-	proto.RegisterType((*GetTransactionsRequest)(nil), "lnrpc.GetTransactionsRequest")
-	proto.RegisterType((*TransactionDetails)(nil), "lnrpc.TransactionDetails")
-	proto.RegisterType((*SendRequest)(nil), "lnrpc.SendRequest")
-	proto.RegisterType((*SendResponse)(nil), "lnrpc.SendResponse")
-	proto.RegisterType((*ChannelPoint)(nil), "lnrpc.ChannelPoint")
-	proto.RegisterType((*LightningAddress)(nil), "lnrpc.LightningAddress")
-	proto.RegisterType((*SendManyRequest)(nil), "lnrpc.SendManyRequest")
-	proto.RegisterType((*SendManyResponse)(nil), "lnrpc.SendManyResponse")
-	proto.RegisterType((*SendCoinsRequest)(nil), "lnrpc.SendCoinsRequest")
-	proto.RegisterType((*SendCoinsResponse)(nil), "lnrpc.SendCoinsResponse")
-	proto.RegisterType((*NewAddressRequest)(nil), "lnrpc.NewAddressRequest")
-	proto.RegisterType((*NewWitnessAddressRequest)(nil), "lnrpc.NewWitnessAddressRequest")
-	proto.RegisterType((*NewAddressResponse)(nil), "lnrpc.NewAddressResponse")
-	proto.RegisterType((*ConnectPeerRequest)(nil), "lnrpc.ConnectPeerRequest")
-	proto.RegisterType((*ConnectPeerResponse)(nil), "lnrpc.ConnectPeerResponse")
-	proto.RegisterType((*HTLC)(nil), "lnrpc.HTLC")
-	proto.RegisterType((*ActiveChannel)(nil), "lnrpc.ActiveChannel")
-	proto.RegisterType((*ListChannelsRequest)(nil), "lnrpc.ListChannelsRequest")
-	proto.RegisterType((*ListChannelsResponse)(nil), "lnrpc.ListChannelsResponse")
-	proto.RegisterType((*Peer)(nil), "lnrpc.Peer")
-	proto.RegisterType((*ListPeersRequest)(nil), "lnrpc.ListPeersRequest")
-	proto.RegisterType((*ListPeersResponse)(nil), "lnrpc.ListPeersResponse")
-	proto.RegisterType((*GetInfoRequest)(nil), "lnrpc.GetInfoRequest")
-	proto.RegisterType((*GetInfoResponse)(nil), "lnrpc.GetInfoResponse")
-	proto.RegisterType((*ConfirmationUpdate)(nil), "lnrpc.ConfirmationUpdate")
-	proto.RegisterType((*ChannelOpenUpdate)(nil), "lnrpc.ChannelOpenUpdate")
-	proto.RegisterType((*ChannelCloseUpdate)(nil), "lnrpc.ChannelCloseUpdate")
-	proto.RegisterType((*CloseChannelRequest)(nil), "lnrpc.CloseChannelRequest")
-	proto.RegisterType((*CloseStatusUpdate)(nil), "lnrpc.CloseStatusUpdate")
-	proto.RegisterType((*PendingUpdate)(nil), "lnrpc.PendingUpdate")
-	proto.RegisterType((*OpenChannelRequest)(nil), "lnrpc.OpenChannelRequest")
-	proto.RegisterType((*OpenStatusUpdate)(nil), "lnrpc.OpenStatusUpdate")
-	proto.RegisterType((*PendingChannelRequest)(nil), "lnrpc.PendingChannelRequest")
-	proto.RegisterType((*PendingChannelResponse)(nil), "lnrpc.PendingChannelResponse")
-	proto.RegisterType((*PendingChannelResponse_PendingChannel)(nil), "lnrpc.PendingChannelResponse.PendingChannel")
-	proto.RegisterType((*WalletBalanceRequest)(nil), "lnrpc.WalletBalanceRequest")
-	proto.RegisterType((*WalletBalanceResponse)(nil), "lnrpc.WalletBalanceResponse")
-	proto.RegisterType((*ChannelBalanceRequest)(nil), "lnrpc.ChannelBalanceRequest")
-	proto.RegisterType((*ChannelBalanceResponse)(nil), "lnrpc.ChannelBalanceResponse")
-	proto.RegisterType((*RouteRequest)(nil), "lnrpc.RouteRequest")
-	proto.RegisterType((*Hop)(nil), "lnrpc.Hop")
-	proto.RegisterType((*Route)(nil), "lnrpc.Route")
-	proto.RegisterType((*NodeInfoRequest)(nil), "lnrpc.NodeInfoRequest")
-	proto.RegisterType((*NodeInfo)(nil), "lnrpc.NodeInfo")
-	proto.RegisterType((*LightningNode)(nil), "lnrpc.LightningNode")
-	proto.RegisterType((*RoutingPolicy)(nil), "lnrpc.RoutingPolicy")
-	proto.RegisterType((*ChannelEdge)(nil), "lnrpc.ChannelEdge")
-	proto.RegisterType((*ChannelGraphRequest)(nil), "lnrpc.ChannelGraphRequest")
-	proto.RegisterType((*ChannelGraph)(nil), "lnrpc.ChannelGraph")
-	proto.RegisterType((*ChanInfoRequest)(nil), "lnrpc.ChanInfoRequest")
-	proto.RegisterType((*NetworkInfoRequest)(nil), "lnrpc.NetworkInfoRequest")
-	proto.RegisterType((*NetworkInfo)(nil), "lnrpc.NetworkInfo")
-	proto.RegisterType((*GraphTopologySubscription)(nil), "lnrpc.GraphTopologySubscription")
-	proto.RegisterType((*GraphTopologyUpdate)(nil), "lnrpc.GraphTopologyUpdate")
-	proto.RegisterType((*NodeUpdate)(nil), "lnrpc.NodeUpdate")
-	proto.RegisterType((*ChannelEdgeUpdate)(nil), "lnrpc.ChannelEdgeUpdate")
-	proto.RegisterType((*ClosedChannelUpdate)(nil), "lnrpc.ClosedChannelUpdate")
-	proto.RegisterType((*SetAliasRequest)(nil), "lnrpc.SetAliasRequest")
-	proto.RegisterType((*SetAliasResponse)(nil), "lnrpc.SetAliasResponse")
-	proto.RegisterType((*Invoice)(nil), "lnrpc.Invoice")
-	proto.RegisterType((*AddInvoiceResponse)(nil), "lnrpc.AddInvoiceResponse")
-	proto.RegisterType((*PaymentHash)(nil), "lnrpc.PaymentHash")
-	proto.RegisterType((*ListInvoiceRequest)(nil), "lnrpc.ListInvoiceRequest")
-	proto.RegisterType((*ListInvoiceResponse)(nil), "lnrpc.ListInvoiceResponse")
-	proto.RegisterType((*InvoiceSubscription)(nil), "lnrpc.InvoiceSubscription")
-	proto.RegisterType((*Payment)(nil), "lnrpc.Payment")
-	proto.RegisterType((*ListPaymentsRequest)(nil), "lnrpc.ListPaymentsRequest")
-	proto.RegisterType((*ListPaymentsResponse)(nil), "lnrpc.ListPaymentsResponse")
-	proto.RegisterType((*DeleteAllPaymentsRequest)(nil), "lnrpc.DeleteAllPaymentsRequest")
-	proto.RegisterType((*DeleteAllPaymentsResponse)(nil), "lnrpc.DeleteAllPaymentsResponse")
-	proto.RegisterType((*DebugLevelRequest)(nil), "lnrpc.DebugLevelRequest")
-	proto.RegisterType((*DebugLevelResponse)(nil), "lnrpc.DebugLevelResponse")
-	proto.RegisterType((*PayReqString)(nil), "lnrpc.PayReqString")
-	proto.RegisterType((*PayReq)(nil), "lnrpc.PayReq")
-	proto.RegisterEnum("lnrpc.ChannelStatus", ChannelStatus_name, ChannelStatus_value)
-	proto.RegisterEnum("lnrpc.NewAddressRequest_AddressType", NewAddressRequest_AddressType_name, NewAddressRequest_AddressType_value)
+func (m *PayReq) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
 }
 
-// Reference imports to suppress errors if they are not otherwise used.
-var _ context.Context
-var _ grpc.ClientConn
+func (m *PayReq) GetExpiry() int64 {
+	if m != nil {
+		return m.Expiry
+	}
+	return 0
+}
 
-// This is a compile-time assertion to ensure that this generated file
-// is compatible with the grpc package it is being compiled against.
-const _ = grpc.SupportPackageIsVersion4
+func (m *PayReq) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
 
-// Client API for Lightning service
+func (m *PayReq) GetRouteHints() []*RouteHint {
+	if m != nil {
+		return m.RouteHints
+	}
+	return nil
+}
 
-type LightningClient interface {
-	WalletBalance(ctx context.Context, in *WalletBalanceRequest, opts ...grpc.CallOption) (*WalletBalanceResponse, error)
-	ChannelBalance(ctx context.Context, in *ChannelBalanceRequest, opts ...grpc.CallOption) (*ChannelBalanceResponse, error)
-	GetTransactions(ctx context.Context, in *GetTransactionsRequest, opts ...grpc.CallOption) (*TransactionDetails, error)
-	SendCoins(ctx context.Context, in *SendCoinsRequest, opts ...grpc.CallOption) (*SendCoinsResponse, error)
-	SubscribeTransactions(ctx context.Context, in *GetTransactionsRequest, opts ...grpc.CallOption) (Lightning_SubscribeTransactionsClient, error)
-	SendMany(ctx context.Context, in *SendManyRequest, opts ...grpc.CallOption) (*SendManyResponse, error)
-	NewAddress(ctx context.Context, in *NewAddressRequest, opts ...grpc.CallOption) (*NewAddressResponse, error)
-	NewWitnessAddress(ctx context.Context, in *NewWitnessAddressRequest, opts ...grpc.CallOption) (*NewAddressResponse, error)
-	ConnectPeer(ctx context.Context, in *ConnectPeerRequest, opts ...grpc.CallOption) (*ConnectPeerResponse, error)
-	ListPeers(ctx context.Context, in *ListPeersRequest, opts ...grpc.CallOption) (*ListPeersResponse, error)
-	GetInfo(ctx context.Context, in *GetInfoRequest, opts ...grpc.CallOption) (*GetInfoResponse, error)
-	// TODO(roasbeef): merge with below with bool?
-	PendingChannels(ctx context.Context, in *PendingChannelRequest, opts ...grpc.CallOption) (*PendingChannelResponse, error)
-	ListChannels(ctx context.Context, in *ListChannelsRequest, opts ...grpc.CallOption) (*ListChannelsResponse, error)
-	OpenChannelSync(ctx context.Context, in *OpenChannelRequest, opts ...grpc.CallOption) (*ChannelPoint, error)
-	OpenChannel(ctx context.Context, in *OpenChannelRequest, opts ...grpc.CallOption) (Lightning_OpenChannelClient, error)
-	CloseChannel(ctx context.Context, in *CloseChannelRequest, opts ...grpc.CallOption) (Lightning_CloseChannelClient, error)
-	SendPayment(ctx context.Context, opts ...grpc.CallOption) (Lightning_SendPaymentClient, error)
-	SendPaymentSync(ctx context.Context, in *SendRequest, opts ...grpc.CallOption) (*SendResponse, error)
-	AddInvoice(ctx context.Context, in *Invoice, opts ...grpc.CallOption) (*AddInvoiceResponse, error)
-	ListInvoices(ctx context.Context, in *ListInvoiceRequest, opts ...grpc.CallOption) (*ListInvoiceResponse, error)
-	LookupInvoice(ctx context.Context, in *PaymentHash, opts ...grpc.CallOption) (*Invoice, error)
-	SubscribeInvoices(ctx context.Context, in *InvoiceSubscription, opts ...grpc.CallOption) (Lightning_SubscribeInvoicesClient, error)
-	DecodePayReq(ctx context.Context, in *PayReqString, opts ...grpc.CallOption) (*PayReq, error)
-	ListPayments(ctx context.Context, in *ListPaymentsRequest, opts ...grpc.CallOption) (*ListPaymentsResponse, error)
-	DeleteAllPayments(ctx context.Context, in *DeleteAllPaymentsRequest, opts ...grpc.CallOption) (*DeleteAllPaymentsResponse, error)
-	DescribeGraph(ctx context.Context, in *ChannelGraphRequest, opts ...grpc.CallOption) (*ChannelGraph, error)
-	GetChanInfo(ctx context.Context, in *ChanInfoRequest, opts ...grpc.CallOption) (*ChannelEdge, error)
-	GetNodeInfo(ctx context.Context, in *NodeInfoRequest, opts ...grpc.CallOption) (*NodeInfo, error)
-	QueryRoute(ctx context.Context, in *RouteRequest, opts ...grpc.CallOption) (*Route, error)
-	GetNetworkInfo(ctx context.Context, in *NetworkInfoRequest, opts ...grpc.CallOption) (*NetworkInfo, error)
-	SubscribeChannelGraph(ctx context.Context, in *GraphTopologySubscription, opts ...grpc.CallOption) (Lightning_SubscribeChannelGraphClient, error)
-	SetAlias(ctx context.Context, in *SetAliasRequest, opts ...grpc.CallOption) (*SetAliasResponse, error)
-	DebugLevel(ctx context.Context, in *DebugLevelRequest, opts ...grpc.CallOption) (*DebugLevelResponse, error)
-}
-
-type lightningClient struct {
-	cc *grpc.ClientConn
+type FeePreset struct {
+	Name           string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	FeePpm         uint64 `protobuf:"varint,2,opt,name=fee_ppm" json:"fee_ppm,omitempty"`
+	CltvLimit      uint32 `protobuf:"varint,3,opt,name=cltv_limit" json:"cltv_limit,omitempty"`
+	MaxAttempts    uint32 `protobuf:"varint,4,opt,name=max_attempts" json:"max_attempts,omitempty"`
+	TimeoutSeconds int64  `protobuf:"varint,5,opt,name=timeout_seconds" json:"timeout_seconds,omitempty"`
 }
 
-func NewLightningClient(cc *grpc.ClientConn) LightningClient {
-	return &lightningClient{cc}
-}
+func (m *FeePreset) Reset()                    { *m = FeePreset{} }
+func (m *FeePreset) String() string            { return proto.CompactTextString(m) }
+func (*FeePreset) ProtoMessage()               {}
+func (*FeePreset) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{89} }
 
-func (c *lightningClient) WalletBalance(ctx context.Context, in *WalletBalanceRequest, opts ...grpc.CallOption) (*WalletBalanceResponse, error) {
-	out := new(WalletBalanceResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/WalletBalance", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *FeePreset) GetName() string {
+	if m != nil {
+		return m.Name
 	}
-	return out, nil
+	return ""
 }
 
-func (c *lightningClient) ChannelBalance(ctx context.Context, in *ChannelBalanceRequest, opts ...grpc.CallOption) (*ChannelBalanceResponse, error) {
-	out := new(ChannelBalanceResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ChannelBalance", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *FeePreset) GetFeePpm() uint64 {
+	if m != nil {
+		return m.FeePpm
 	}
-	return out, nil
+	return 0
 }
 
-func (c *lightningClient) GetTransactions(ctx context.Context, in *GetTransactionsRequest, opts ...grpc.CallOption) (*TransactionDetails, error) {
-	out := new(TransactionDetails)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/GetTransactions", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *FeePreset) GetCltvLimit() uint32 {
+	if m != nil {
+		return m.CltvLimit
 	}
-	return out, nil
+	return 0
 }
 
-func (c *lightningClient) SendCoins(ctx context.Context, in *SendCoinsRequest, opts ...grpc.CallOption) (*SendCoinsResponse, error) {
-	out := new(SendCoinsResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/SendCoins", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *FeePreset) GetMaxAttempts() uint32 {
+	if m != nil {
+		return m.MaxAttempts
 	}
-	return out, nil
+	return 0
 }
 
-func (c *lightningClient) SubscribeTransactions(ctx context.Context, in *GetTransactionsRequest, opts ...grpc.CallOption) (Lightning_SubscribeTransactionsClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[0], c.cc, "/lnrpc.Lightning/SubscribeTransactions", opts...)
-	if err != nil {
-		return nil, err
-	}
-	x := &lightningSubscribeTransactionsClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
-	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+func (m *FeePreset) GetTimeoutSeconds() int64 {
+	if m != nil {
+		return m.TimeoutSeconds
 	}
-	return x, nil
+	return 0
 }
 
-type Lightning_SubscribeTransactionsClient interface {
-	Recv() (*Transaction, error)
-	grpc.ClientStream
+type ListFeePresetsRequest struct {
 }
 
-type lightningSubscribeTransactionsClient struct {
-	grpc.ClientStream
+func (m *ListFeePresetsRequest) Reset()                    { *m = ListFeePresetsRequest{} }
+func (m *ListFeePresetsRequest) String() string            { return proto.CompactTextString(m) }
+func (*ListFeePresetsRequest) ProtoMessage()               {}
+func (*ListFeePresetsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{90} }
+
+type ListFeePresetsResponse struct {
+	Presets []*FeePreset `protobuf:"bytes,1,rep,name=presets" json:"presets,omitempty"`
 }
 
-func (x *lightningSubscribeTransactionsClient) Recv() (*Transaction, error) {
-	m := new(Transaction)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
+func (m *ListFeePresetsResponse) Reset()                    { *m = ListFeePresetsResponse{} }
+func (m *ListFeePresetsResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListFeePresetsResponse) ProtoMessage()               {}
+func (*ListFeePresetsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{91} }
+
+func (m *ListFeePresetsResponse) GetPresets() []*FeePreset {
+	if m != nil {
+		return m.Presets
 	}
-	return m, nil
+	return nil
 }
 
-func (c *lightningClient) SendMany(ctx context.Context, in *SendManyRequest, opts ...grpc.CallOption) (*SendManyResponse, error) {
-	out := new(SendManyResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/SendMany", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+type ChannelHistoryRequest struct {
+	ChannelPoint *ChannelPoint `protobuf:"bytes,1,opt,name=channel_point" json:"channel_point,omitempty"`
+	StartTime    int64         `protobuf:"varint,2,opt,name=start_time" json:"start_time,omitempty"`
+	EndTime      int64         `protobuf:"varint,3,opt,name=end_time" json:"end_time,omitempty"`
 }
 
-func (c *lightningClient) NewAddress(ctx context.Context, in *NewAddressRequest, opts ...grpc.CallOption) (*NewAddressResponse, error) {
-	out := new(NewAddressResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/NewAddress", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *ChannelHistoryRequest) Reset()                    { *m = ChannelHistoryRequest{} }
+func (m *ChannelHistoryRequest) String() string            { return proto.CompactTextString(m) }
+func (*ChannelHistoryRequest) ProtoMessage()               {}
+func (*ChannelHistoryRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{92} }
+
+func (m *ChannelHistoryRequest) GetChannelPoint() *ChannelPoint {
+	if m != nil {
+		return m.ChannelPoint
 	}
-	return out, nil
+	return nil
 }
 
-func (c *lightningClient) NewWitnessAddress(ctx context.Context, in *NewWitnessAddressRequest, opts ...grpc.CallOption) (*NewAddressResponse, error) {
-	out := new(NewAddressResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/NewWitnessAddress", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *ChannelHistoryRequest) GetStartTime() int64 {
+	if m != nil {
+		return m.StartTime
 	}
-	return out, nil
+	return 0
 }
 
-func (c *lightningClient) ConnectPeer(ctx context.Context, in *ConnectPeerRequest, opts ...grpc.CallOption) (*ConnectPeerResponse, error) {
-	out := new(ConnectPeerResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ConnectPeer", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *ChannelHistoryRequest) GetEndTime() int64 {
+	if m != nil {
+		return m.EndTime
 	}
-	return out, nil
+	return 0
 }
 
-func (c *lightningClient) ListPeers(ctx context.Context, in *ListPeersRequest, opts ...grpc.CallOption) (*ListPeersResponse, error) {
-	out := new(ListPeersResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ListPeers", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+type ChannelEvent struct {
+	Type          string `protobuf:"bytes,1,opt,name=type" json:"type,omitempty"`
+	Timestamp     int64  `protobuf:"varint,2,opt,name=timestamp" json:"timestamp,omitempty"`
+	LocalBalance  int64  `protobuf:"varint,3,opt,name=local_balance" json:"local_balance,omitempty"`
+	RemoteBalance int64  `protobuf:"varint,4,opt,name=remote_balance" json:"remote_balance,omitempty"`
+	Detail        string `protobuf:"bytes,5,opt,name=detail" json:"detail,omitempty"`
 }
 
-func (c *lightningClient) GetInfo(ctx context.Context, in *GetInfoRequest, opts ...grpc.CallOption) (*GetInfoResponse, error) {
-	out := new(GetInfoResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/GetInfo", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *ChannelEvent) Reset()                    { *m = ChannelEvent{} }
+func (m *ChannelEvent) String() string            { return proto.CompactTextString(m) }
+func (*ChannelEvent) ProtoMessage()               {}
+func (*ChannelEvent) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{93} }
+
+func (m *ChannelEvent) GetType() string {
+	if m != nil {
+		return m.Type
 	}
-	return out, nil
+	return ""
 }
 
-func (c *lightningClient) PendingChannels(ctx context.Context, in *PendingChannelRequest, opts ...grpc.CallOption) (*PendingChannelResponse, error) {
-	out := new(PendingChannelResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/PendingChannels", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *ChannelEvent) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
 	}
-	return out, nil
+	return 0
 }
 
-func (c *lightningClient) ListChannels(ctx context.Context, in *ListChannelsRequest, opts ...grpc.CallOption) (*ListChannelsResponse, error) {
-	out := new(ListChannelsResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ListChannels", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *ChannelEvent) GetLocalBalance() int64 {
+	if m != nil {
+		return m.LocalBalance
 	}
-	return out, nil
+	return 0
 }
 
-func (c *lightningClient) OpenChannelSync(ctx context.Context, in *OpenChannelRequest, opts ...grpc.CallOption) (*ChannelPoint, error) {
-	out := new(ChannelPoint)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/OpenChannelSync", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *ChannelEvent) GetRemoteBalance() int64 {
+	if m != nil {
+		return m.RemoteBalance
 	}
-	return out, nil
+	return 0
 }
 
-func (c *lightningClient) OpenChannel(ctx context.Context, in *OpenChannelRequest, opts ...grpc.CallOption) (Lightning_OpenChannelClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[1], c.cc, "/lnrpc.Lightning/OpenChannel", opts...)
-	if err != nil {
-		return nil, err
-	}
-	x := &lightningOpenChannelClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
-	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+func (m *ChannelEvent) GetDetail() string {
+	if m != nil {
+		return m.Detail
 	}
-	return x, nil
+	return ""
 }
 
-type Lightning_OpenChannelClient interface {
-	Recv() (*OpenStatusUpdate, error)
-	grpc.ClientStream
+type ChannelHistoryResponse struct {
+	Events []*ChannelEvent `protobuf:"bytes,1,rep,name=events" json:"events,omitempty"`
 }
 
-type lightningOpenChannelClient struct {
-	grpc.ClientStream
-}
+func (m *ChannelHistoryResponse) Reset()                    { *m = ChannelHistoryResponse{} }
+func (m *ChannelHistoryResponse) String() string            { return proto.CompactTextString(m) }
+func (*ChannelHistoryResponse) ProtoMessage()               {}
+func (*ChannelHistoryResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{94} }
 
-func (x *lightningOpenChannelClient) Recv() (*OpenStatusUpdate, error) {
-	m := new(OpenStatusUpdate)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
+func (m *ChannelHistoryResponse) GetEvents() []*ChannelEvent {
+	if m != nil {
+		return m.Events
 	}
-	return m, nil
+	return nil
 }
 
-func (c *lightningClient) CloseChannel(ctx context.Context, in *CloseChannelRequest, opts ...grpc.CallOption) (Lightning_CloseChannelClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[2], c.cc, "/lnrpc.Lightning/CloseChannel", opts...)
-	if err != nil {
-		return nil, err
+type MiddlewareRegistration struct {
+	MiddlewareName string   `protobuf:"bytes,1,opt,name=middleware_name" json:"middleware_name,omitempty"`
+	Methods        []string `protobuf:"bytes,2,rep,name=methods" json:"methods,omitempty"`
+	ReadOnly       bool     `protobuf:"varint,3,opt,name=read_only" json:"read_only,omitempty"`
+}
+
+func (m *MiddlewareRegistration) Reset()                    { *m = MiddlewareRegistration{} }
+func (m *MiddlewareRegistration) String() string            { return proto.CompactTextString(m) }
+func (*MiddlewareRegistration) ProtoMessage()               {}
+func (*MiddlewareRegistration) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{95} }
+
+func (m *MiddlewareRegistration) GetMiddlewareName() string {
+	if m != nil {
+		return m.MiddlewareName
 	}
-	x := &lightningCloseChannelClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
-		return nil, err
+	return ""
+}
+
+func (m *MiddlewareRegistration) GetMethods() []string {
+	if m != nil {
+		return m.Methods
 	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+	return nil
+}
+
+func (m *MiddlewareRegistration) GetReadOnly() bool {
+	if m != nil {
+		return m.ReadOnly
 	}
-	return x, nil
+	return false
 }
 
-type Lightning_CloseChannelClient interface {
-	Recv() (*CloseStatusUpdate, error)
-	grpc.ClientStream
+type RPCMiddlewareRequest struct {
+	RequestId  uint64 `protobuf:"varint,1,opt,name=request_id" json:"request_id,omitempty"`
+	Method     string `protobuf:"bytes,2,opt,name=method" json:"method,omitempty"`
+	TypeName   string `protobuf:"bytes,3,opt,name=type_name" json:"type_name,omitempty"`
+	Serialized []byte `protobuf:"bytes,4,opt,name=serialized,proto3" json:"serialized,omitempty"`
 }
 
-type lightningCloseChannelClient struct {
-	grpc.ClientStream
+func (m *RPCMiddlewareRequest) Reset()                    { *m = RPCMiddlewareRequest{} }
+func (m *RPCMiddlewareRequest) String() string            { return proto.CompactTextString(m) }
+func (*RPCMiddlewareRequest) ProtoMessage()               {}
+func (*RPCMiddlewareRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{96} }
+
+func (m *RPCMiddlewareRequest) GetRequestId() uint64 {
+	if m != nil {
+		return m.RequestId
+	}
+	return 0
 }
 
-func (x *lightningCloseChannelClient) Recv() (*CloseStatusUpdate, error) {
-	m := new(CloseStatusUpdate)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
+func (m *RPCMiddlewareRequest) GetMethod() string {
+	if m != nil {
+		return m.Method
 	}
-	return m, nil
+	return ""
 }
 
-func (c *lightningClient) SendPayment(ctx context.Context, opts ...grpc.CallOption) (Lightning_SendPaymentClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[3], c.cc, "/lnrpc.Lightning/SendPayment", opts...)
-	if err != nil {
-		return nil, err
+func (m *RPCMiddlewareRequest) GetTypeName() string {
+	if m != nil {
+		return m.TypeName
 	}
-	x := &lightningSendPaymentClient{stream}
-	return x, nil
+	return ""
 }
 
-type Lightning_SendPaymentClient interface {
-	Send(*SendRequest) error
-	Recv() (*SendResponse, error)
-	grpc.ClientStream
+func (m *RPCMiddlewareRequest) GetSerialized() []byte {
+	if m != nil {
+		return m.Serialized
+	}
+	return nil
 }
 
-type lightningSendPaymentClient struct {
-	grpc.ClientStream
+type RPCMiddlewareResponse struct {
+	Register    *MiddlewareRegistration `protobuf:"bytes,1,opt,name=register" json:"register,omitempty"`
+	RequestId   uint64                  `protobuf:"varint,2,opt,name=request_id" json:"request_id,omitempty"`
+	Error       string                  `protobuf:"bytes,3,opt,name=error" json:"error,omitempty"`
+	Replace     bool                    `protobuf:"varint,4,opt,name=replace" json:"replace,omitempty"`
+	Replacement []byte                  `protobuf:"bytes,5,opt,name=replacement,proto3" json:"replacement,omitempty"`
 }
 
-func (x *lightningSendPaymentClient) Send(m *SendRequest) error {
-	return x.ClientStream.SendMsg(m)
+func (m *RPCMiddlewareResponse) Reset()                    { *m = RPCMiddlewareResponse{} }
+func (m *RPCMiddlewareResponse) String() string            { return proto.CompactTextString(m) }
+func (*RPCMiddlewareResponse) ProtoMessage()               {}
+func (*RPCMiddlewareResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{97} }
+
+func (m *RPCMiddlewareResponse) GetRegister() *MiddlewareRegistration {
+	if m != nil {
+		return m.Register
+	}
+	return nil
 }
 
-func (x *lightningSendPaymentClient) Recv() (*SendResponse, error) {
-	m := new(SendResponse)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
+func (m *RPCMiddlewareResponse) GetRequestId() uint64 {
+	if m != nil {
+		return m.RequestId
 	}
-	return m, nil
+	return 0
 }
 
-func (c *lightningClient) SendPaymentSync(ctx context.Context, in *SendRequest, opts ...grpc.CallOption) (*SendResponse, error) {
-	out := new(SendResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/SendPaymentSync", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func (m *RPCMiddlewareResponse) GetError() string {
+	if m != nil {
+		return m.Error
 	}
-	return out, nil
+	return ""
 }
 
-func (c *lightningClient) AddInvoice(ctx context.Context, in *Invoice, opts ...grpc.CallOption) (*AddInvoiceResponse, error) {
+func (m *RPCMiddlewareResponse) GetReplace() bool {
+	if m != nil {
+		return m.Replace
+	}
+	return false
+}
+
+func (m *RPCMiddlewareResponse) GetReplacement() []byte {
+	if m != nil {
+		return m.Replacement
+	}
+	return nil
+}
+
+type ChannelRecommendation struct {
+	Id           string `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+	Action       string `protobuf:"bytes,2,opt,name=action" json:"action,omitempty"`
+	ChannelPoint string `protobuf:"bytes,3,opt,name=channel_point" json:"channel_point,omitempty"`
+	RemotePubkey string `protobuf:"bytes,4,opt,name=remote_pubkey" json:"remote_pubkey,omitempty"`
+	Amount       int64  `protobuf:"varint,5,opt,name=amount" json:"amount,omitempty"`
+	Reason       string `protobuf:"bytes,6,opt,name=reason" json:"reason,omitempty"`
+}
+
+func (m *ChannelRecommendation) Reset()                    { *m = ChannelRecommendation{} }
+func (m *ChannelRecommendation) String() string            { return proto.CompactTextString(m) }
+func (*ChannelRecommendation) ProtoMessage()               {}
+func (*ChannelRecommendation) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{98} }
+
+func (m *ChannelRecommendation) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *ChannelRecommendation) GetAction() string {
+	if m != nil {
+		return m.Action
+	}
+	return ""
+}
+
+func (m *ChannelRecommendation) GetChannelPoint() string {
+	if m != nil {
+		return m.ChannelPoint
+	}
+	return ""
+}
+
+func (m *ChannelRecommendation) GetRemotePubkey() string {
+	if m != nil {
+		return m.RemotePubkey
+	}
+	return ""
+}
+
+func (m *ChannelRecommendation) GetAmount() int64 {
+	if m != nil {
+		return m.Amount
+	}
+	return 0
+}
+
+func (m *ChannelRecommendation) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+type ListRecommendationsRequest struct {
+}
+
+func (m *ListRecommendationsRequest) Reset()                    { *m = ListRecommendationsRequest{} }
+func (m *ListRecommendationsRequest) String() string            { return proto.CompactTextString(m) }
+func (*ListRecommendationsRequest) ProtoMessage()               {}
+func (*ListRecommendationsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{99} }
+
+type ListRecommendationsResponse struct {
+	Recommendations []*ChannelRecommendation `protobuf:"bytes,1,rep,name=recommendations" json:"recommendations,omitempty"`
+}
+
+func (m *ListRecommendationsResponse) Reset()                    { *m = ListRecommendationsResponse{} }
+func (m *ListRecommendationsResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListRecommendationsResponse) ProtoMessage()               {}
+func (*ListRecommendationsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{100} }
+
+func (m *ListRecommendationsResponse) GetRecommendations() []*ChannelRecommendation {
+	if m != nil {
+		return m.Recommendations
+	}
+	return nil
+}
+
+type ExecuteRecommendationRequest struct {
+	Id string `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+}
+
+func (m *ExecuteRecommendationRequest) Reset()                    { *m = ExecuteRecommendationRequest{} }
+func (m *ExecuteRecommendationRequest) String() string            { return proto.CompactTextString(m) }
+func (*ExecuteRecommendationRequest) ProtoMessage()               {}
+func (*ExecuteRecommendationRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{101} }
+
+func (m *ExecuteRecommendationRequest) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+type ExecuteRecommendationResponse struct {
+}
+
+func (m *ExecuteRecommendationResponse) Reset()         { *m = ExecuteRecommendationResponse{} }
+func (m *ExecuteRecommendationResponse) String() string { return proto.CompactTextString(m) }
+func (*ExecuteRecommendationResponse) ProtoMessage()    {}
+func (*ExecuteRecommendationResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{102}
+}
+
+type OutboxSubscription struct {
+	SubscriberName string `protobuf:"bytes,1,opt,name=subscriber_name" json:"subscriber_name,omitempty"`
+}
+
+func (m *OutboxSubscription) Reset()                    { *m = OutboxSubscription{} }
+func (m *OutboxSubscription) String() string            { return proto.CompactTextString(m) }
+func (*OutboxSubscription) ProtoMessage()               {}
+func (*OutboxSubscription) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{103} }
+
+func (m *OutboxSubscription) GetSubscriberName() string {
+	if m != nil {
+		return m.SubscriberName
+	}
+	return ""
+}
+
+type OutboxNotification struct {
+	Id        uint64 `protobuf:"varint,1,opt,name=id" json:"id,omitempty"`
+	Type      string `protobuf:"bytes,2,opt,name=type" json:"type,omitempty"`
+	Timestamp int64  `protobuf:"varint,3,opt,name=timestamp" json:"timestamp,omitempty"`
+	Payload   string `protobuf:"bytes,4,opt,name=payload" json:"payload,omitempty"`
+}
+
+func (m *OutboxNotification) Reset()                    { *m = OutboxNotification{} }
+func (m *OutboxNotification) String() string            { return proto.CompactTextString(m) }
+func (*OutboxNotification) ProtoMessage()               {}
+func (*OutboxNotification) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{104} }
+
+func (m *OutboxNotification) GetId() uint64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *OutboxNotification) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *OutboxNotification) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *OutboxNotification) GetPayload() string {
+	if m != nil {
+		return m.Payload
+	}
+	return ""
+}
+
+type OutboxAck struct {
+	SubscriberName string `protobuf:"bytes,1,opt,name=subscriber_name" json:"subscriber_name,omitempty"`
+	Id             uint64 `protobuf:"varint,2,opt,name=id" json:"id,omitempty"`
+}
+
+func (m *OutboxAck) Reset()                    { *m = OutboxAck{} }
+func (m *OutboxAck) String() string            { return proto.CompactTextString(m) }
+func (*OutboxAck) ProtoMessage()               {}
+func (*OutboxAck) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{105} }
+
+func (m *OutboxAck) GetSubscriberName() string {
+	if m != nil {
+		return m.SubscriberName
+	}
+	return ""
+}
+
+func (m *OutboxAck) GetId() uint64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+type OutboxAckResponse struct {
+}
+
+func (m *OutboxAckResponse) Reset()                    { *m = OutboxAckResponse{} }
+func (m *OutboxAckResponse) String() string            { return proto.CompactTextString(m) }
+func (*OutboxAckResponse) ProtoMessage()               {}
+func (*OutboxAckResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{106} }
+
+type RemoveOutboxSubscriberRequest struct {
+	SubscriberName string `protobuf:"bytes,1,opt,name=subscriber_name" json:"subscriber_name,omitempty"`
+}
+
+func (m *RemoveOutboxSubscriberRequest) Reset()         { *m = RemoveOutboxSubscriberRequest{} }
+func (m *RemoveOutboxSubscriberRequest) String() string { return proto.CompactTextString(m) }
+func (*RemoveOutboxSubscriberRequest) ProtoMessage()    {}
+func (*RemoveOutboxSubscriberRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{107}
+}
+
+func (m *RemoveOutboxSubscriberRequest) GetSubscriberName() string {
+	if m != nil {
+		return m.SubscriberName
+	}
+	return ""
+}
+
+type RemoveOutboxSubscriberResponse struct {
+}
+
+func (m *RemoveOutboxSubscriberResponse) Reset()         { *m = RemoveOutboxSubscriberResponse{} }
+func (m *RemoveOutboxSubscriberResponse) String() string { return proto.CompactTextString(m) }
+func (*RemoveOutboxSubscriberResponse) ProtoMessage()    {}
+func (*RemoveOutboxSubscriberResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{108}
+}
+
+type CloseDryRun struct {
+	Txid        []byte `protobuf:"bytes,1,opt,name=txid,proto3" json:"txid,omitempty"`
+	RawTx       []byte `protobuf:"bytes,2,opt,name=raw_tx,proto3" json:"raw_tx,omitempty"`
+	LocalAmount int64  `protobuf:"varint,3,opt,name=local_amount" json:"local_amount,omitempty"`
+	CsvDelay    uint32 `protobuf:"varint,4,opt,name=csv_delay" json:"csv_delay,omitempty"`
+}
+
+func (m *CloseDryRun) Reset()                    { *m = CloseDryRun{} }
+func (m *CloseDryRun) String() string            { return proto.CompactTextString(m) }
+func (*CloseDryRun) ProtoMessage()               {}
+func (*CloseDryRun) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{109} }
+
+func (m *CloseDryRun) GetTxid() []byte {
+	if m != nil {
+		return m.Txid
+	}
+	return nil
+}
+
+func (m *CloseDryRun) GetRawTx() []byte {
+	if m != nil {
+		return m.RawTx
+	}
+	return nil
+}
+
+func (m *CloseDryRun) GetLocalAmount() int64 {
+	if m != nil {
+		return m.LocalAmount
+	}
+	return 0
+}
+
+func (m *CloseDryRun) GetCsvDelay() uint32 {
+	if m != nil {
+		return m.CsvDelay
+	}
+	return 0
+}
+
+type EdgePenalty struct {
+	ChanId      uint64  `protobuf:"varint,1,opt,name=chan_id" json:"chan_id,omitempty"`
+	Failures    uint32  `protobuf:"varint,2,opt,name=failures" json:"failures,omitempty"`
+	LastFailure int64   `protobuf:"varint,3,opt,name=last_failure" json:"last_failure,omitempty"`
+	Weight      float64 `protobuf:"fixed64,4,opt,name=weight" json:"weight,omitempty"`
+}
+
+func (m *EdgePenalty) Reset()                    { *m = EdgePenalty{} }
+func (m *EdgePenalty) String() string            { return proto.CompactTextString(m) }
+func (*EdgePenalty) ProtoMessage()               {}
+func (*EdgePenalty) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{110} }
+
+func (m *EdgePenalty) GetChanId() uint64 {
+	if m != nil {
+		return m.ChanId
+	}
+	return 0
+}
+
+func (m *EdgePenalty) GetFailures() uint32 {
+	if m != nil {
+		return m.Failures
+	}
+	return 0
+}
+
+func (m *EdgePenalty) GetLastFailure() int64 {
+	if m != nil {
+		return m.LastFailure
+	}
+	return 0
+}
+
+func (m *EdgePenalty) GetWeight() float64 {
+	if m != nil {
+		return m.Weight
+	}
+	return 0
+}
+
+type NodePenalty struct {
+	PubKey      string  `protobuf:"bytes,1,opt,name=pub_key" json:"pub_key,omitempty"`
+	Failures    uint32  `protobuf:"varint,2,opt,name=failures" json:"failures,omitempty"`
+	LastFailure int64   `protobuf:"varint,3,opt,name=last_failure" json:"last_failure,omitempty"`
+	Weight      float64 `protobuf:"fixed64,4,opt,name=weight" json:"weight,omitempty"`
+}
+
+func (m *NodePenalty) Reset()                    { *m = NodePenalty{} }
+func (m *NodePenalty) String() string            { return proto.CompactTextString(m) }
+func (*NodePenalty) ProtoMessage()               {}
+func (*NodePenalty) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{111} }
+
+func (m *NodePenalty) GetPubKey() string {
+	if m != nil {
+		return m.PubKey
+	}
+	return ""
+}
+
+func (m *NodePenalty) GetFailures() uint32 {
+	if m != nil {
+		return m.Failures
+	}
+	return 0
+}
+
+func (m *NodePenalty) GetLastFailure() int64 {
+	if m != nil {
+		return m.LastFailure
+	}
+	return 0
+}
+
+func (m *NodePenalty) GetWeight() float64 {
+	if m != nil {
+		return m.Weight
+	}
+	return 0
+}
+
+type QueryMissionControlRequest struct {
+}
+
+func (m *QueryMissionControlRequest) Reset()                    { *m = QueryMissionControlRequest{} }
+func (m *QueryMissionControlRequest) String() string            { return proto.CompactTextString(m) }
+func (*QueryMissionControlRequest) ProtoMessage()               {}
+func (*QueryMissionControlRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{112} }
+
+type QueryMissionControlResponse struct {
+	Edges []*EdgePenalty `protobuf:"bytes,1,rep,name=edges" json:"edges,omitempty"`
+	Nodes []*NodePenalty `protobuf:"bytes,2,rep,name=nodes" json:"nodes,omitempty"`
+}
+
+func (m *QueryMissionControlResponse) Reset()                    { *m = QueryMissionControlResponse{} }
+func (m *QueryMissionControlResponse) String() string            { return proto.CompactTextString(m) }
+func (*QueryMissionControlResponse) ProtoMessage()               {}
+func (*QueryMissionControlResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{113} }
+
+func (m *QueryMissionControlResponse) GetEdges() []*EdgePenalty {
+	if m != nil {
+		return m.Edges
+	}
+	return nil
+}
+
+func (m *QueryMissionControlResponse) GetNodes() []*NodePenalty {
+	if m != nil {
+		return m.Nodes
+	}
+	return nil
+}
+
+type ResetMissionControlRequest struct {
+	ChanId uint64 `protobuf:"varint,1,opt,name=chan_id" json:"chan_id,omitempty"`
+	PubKey string `protobuf:"bytes,2,opt,name=pub_key" json:"pub_key,omitempty"`
+}
+
+func (m *ResetMissionControlRequest) Reset()                    { *m = ResetMissionControlRequest{} }
+func (m *ResetMissionControlRequest) String() string            { return proto.CompactTextString(m) }
+func (*ResetMissionControlRequest) ProtoMessage()               {}
+func (*ResetMissionControlRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{114} }
+
+func (m *ResetMissionControlRequest) GetChanId() uint64 {
+	if m != nil {
+		return m.ChanId
+	}
+	return 0
+}
+
+func (m *ResetMissionControlRequest) GetPubKey() string {
+	if m != nil {
+		return m.PubKey
+	}
+	return ""
+}
+
+type ResetMissionControlResponse struct {
+}
+
+func (m *ResetMissionControlResponse) Reset()                    { *m = ResetMissionControlResponse{} }
+func (m *ResetMissionControlResponse) String() string            { return proto.CompactTextString(m) }
+func (*ResetMissionControlResponse) ProtoMessage()               {}
+func (*ResetMissionControlResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{115} }
+
+type AckChannelDivergenceResponse struct {
+	Divergence string `protobuf:"bytes,1,opt,name=divergence" json:"divergence,omitempty"`
+}
+
+func (m *AckChannelDivergenceResponse) Reset()                    { *m = AckChannelDivergenceResponse{} }
+func (m *AckChannelDivergenceResponse) String() string            { return proto.CompactTextString(m) }
+func (*AckChannelDivergenceResponse) ProtoMessage()               {}
+func (*AckChannelDivergenceResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{116} }
+
+func (m *AckChannelDivergenceResponse) GetDivergence() string {
+	if m != nil {
+		return m.Divergence
+	}
+	return ""
+}
+
+type CustomMessageSubscription struct {
+	FeatureBit uint32   `protobuf:"varint,1,opt,name=feature_bit" json:"feature_bit,omitempty"`
+	Types      []uint32 `protobuf:"varint,2,rep,packed,name=types" json:"types,omitempty"`
+}
+
+func (m *CustomMessageSubscription) Reset()                    { *m = CustomMessageSubscription{} }
+func (m *CustomMessageSubscription) String() string            { return proto.CompactTextString(m) }
+func (*CustomMessageSubscription) ProtoMessage()               {}
+func (*CustomMessageSubscription) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{117} }
+
+func (m *CustomMessageSubscription) GetFeatureBit() uint32 {
+	if m != nil {
+		return m.FeatureBit
+	}
+	return 0
+}
+
+func (m *CustomMessageSubscription) GetTypes() []uint32 {
+	if m != nil {
+		return m.Types
+	}
+	return nil
+}
+
+type CustomMessage struct {
+	Peer string `protobuf:"bytes,1,opt,name=peer" json:"peer,omitempty"`
+	Type uint32 `protobuf:"varint,2,opt,name=type" json:"type,omitempty"`
+	Data []byte `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (m *CustomMessage) Reset()                    { *m = CustomMessage{} }
+func (m *CustomMessage) String() string            { return proto.CompactTextString(m) }
+func (*CustomMessage) ProtoMessage()               {}
+func (*CustomMessage) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{118} }
+
+func (m *CustomMessage) GetPeer() string {
+	if m != nil {
+		return m.Peer
+	}
+	return ""
+}
+
+func (m *CustomMessage) GetType() uint32 {
+	if m != nil {
+		return m.Type
+	}
+	return 0
+}
+
+func (m *CustomMessage) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+type SendCustomMessageRequest struct {
+	Peer string `protobuf:"bytes,1,opt,name=peer" json:"peer,omitempty"`
+	Type uint32 `protobuf:"varint,2,opt,name=type" json:"type,omitempty"`
+	Data []byte `protobuf:"bytes,3,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (m *SendCustomMessageRequest) Reset()                    { *m = SendCustomMessageRequest{} }
+func (m *SendCustomMessageRequest) String() string            { return proto.CompactTextString(m) }
+func (*SendCustomMessageRequest) ProtoMessage()               {}
+func (*SendCustomMessageRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{119} }
+
+func (m *SendCustomMessageRequest) GetPeer() string {
+	if m != nil {
+		return m.Peer
+	}
+	return ""
+}
+
+func (m *SendCustomMessageRequest) GetType() uint32 {
+	if m != nil {
+		return m.Type
+	}
+	return 0
+}
+
+func (m *SendCustomMessageRequest) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+type SendCustomMessageResponse struct {
+}
+
+func (m *SendCustomMessageResponse) Reset()                    { *m = SendCustomMessageResponse{} }
+func (m *SendCustomMessageResponse) String() string            { return proto.CompactTextString(m) }
+func (*SendCustomMessageResponse) ProtoMessage()               {}
+func (*SendCustomMessageResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{120} }
+
+type SigningAuditRequest struct {
+	StartTime int64 `protobuf:"varint,1,opt,name=start_time" json:"start_time,omitempty"`
+	EndTime   int64 `protobuf:"varint,2,opt,name=end_time" json:"end_time,omitempty"`
+}
+
+func (m *SigningAuditRequest) Reset()                    { *m = SigningAuditRequest{} }
+func (m *SigningAuditRequest) String() string            { return proto.CompactTextString(m) }
+func (*SigningAuditRequest) ProtoMessage()               {}
+func (*SigningAuditRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{121} }
+
+func (m *SigningAuditRequest) GetStartTime() int64 {
+	if m != nil {
+		return m.StartTime
+	}
+	return 0
+}
+
+func (m *SigningAuditRequest) GetEndTime() int64 {
+	if m != nil {
+		return m.EndTime
+	}
+	return 0
+}
+
+type SigningEvent struct {
+	Type      string `protobuf:"bytes,1,opt,name=type" json:"type,omitempty"`
+	Timestamp int64  `protobuf:"varint,2,opt,name=timestamp" json:"timestamp,omitempty"`
+	Outpoint  string `protobuf:"bytes,3,opt,name=outpoint" json:"outpoint,omitempty"`
+	PubKey    string `protobuf:"bytes,4,opt,name=pub_key" json:"pub_key,omitempty"`
+	DataHash  string `protobuf:"bytes,5,opt,name=data_hash" json:"data_hash,omitempty"`
+}
+
+func (m *SigningEvent) Reset()                    { *m = SigningEvent{} }
+func (m *SigningEvent) String() string            { return proto.CompactTextString(m) }
+func (*SigningEvent) ProtoMessage()               {}
+func (*SigningEvent) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{122} }
+
+func (m *SigningEvent) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *SigningEvent) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *SigningEvent) GetOutpoint() string {
+	if m != nil {
+		return m.Outpoint
+	}
+	return ""
+}
+
+func (m *SigningEvent) GetPubKey() string {
+	if m != nil {
+		return m.PubKey
+	}
+	return ""
+}
+
+func (m *SigningEvent) GetDataHash() string {
+	if m != nil {
+		return m.DataHash
+	}
+	return ""
+}
+
+type SigningAuditResponse struct {
+	Events []*SigningEvent `protobuf:"bytes,1,rep,name=events" json:"events,omitempty"`
+}
+
+func (m *SigningAuditResponse) Reset()                    { *m = SigningAuditResponse{} }
+func (m *SigningAuditResponse) String() string            { return proto.CompactTextString(m) }
+func (*SigningAuditResponse) ProtoMessage()               {}
+func (*SigningAuditResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{123} }
+
+func (m *SigningAuditResponse) GetEvents() []*SigningEvent {
+	if m != nil {
+		return m.Events
+	}
+	return nil
+}
+
+type ExportChannelPoliciesRequest struct {
+}
+
+func (m *ExportChannelPoliciesRequest) Reset()                    { *m = ExportChannelPoliciesRequest{} }
+func (m *ExportChannelPoliciesRequest) String() string            { return proto.CompactTextString(m) }
+func (*ExportChannelPoliciesRequest) ProtoMessage()               {}
+func (*ExportChannelPoliciesRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{124} }
+
+type ChannelPolicy struct {
+	ChanPoint string         `protobuf:"bytes,1,opt,name=chan_point" json:"chan_point,omitempty"`
+	Policy    *RoutingPolicy `protobuf:"bytes,2,opt,name=policy" json:"policy,omitempty"`
+}
+
+func (m *ChannelPolicy) Reset()                    { *m = ChannelPolicy{} }
+func (m *ChannelPolicy) String() string            { return proto.CompactTextString(m) }
+func (*ChannelPolicy) ProtoMessage()               {}
+func (*ChannelPolicy) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{125} }
+
+func (m *ChannelPolicy) GetChanPoint() string {
+	if m != nil {
+		return m.ChanPoint
+	}
+	return ""
+}
+
+func (m *ChannelPolicy) GetPolicy() *RoutingPolicy {
+	if m != nil {
+		return m.Policy
+	}
+	return nil
+}
+
+type ChannelPolicies struct {
+	Policies []*ChannelPolicy `protobuf:"bytes,1,rep,name=policies" json:"policies,omitempty"`
+}
+
+func (m *ChannelPolicies) Reset()                    { *m = ChannelPolicies{} }
+func (m *ChannelPolicies) String() string            { return proto.CompactTextString(m) }
+func (*ChannelPolicies) ProtoMessage()               {}
+func (*ChannelPolicies) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{126} }
+
+func (m *ChannelPolicies) GetPolicies() []*ChannelPolicy {
+	if m != nil {
+		return m.Policies
+	}
+	return nil
+}
+
+type ImportChannelPoliciesRequest struct {
+	Policies []*ChannelPolicy `protobuf:"bytes,1,rep,name=policies" json:"policies,omitempty"`
+	DryRun   bool             `protobuf:"varint,2,opt,name=dry_run" json:"dry_run,omitempty"`
+}
+
+func (m *ImportChannelPoliciesRequest) Reset()                    { *m = ImportChannelPoliciesRequest{} }
+func (m *ImportChannelPoliciesRequest) String() string            { return proto.CompactTextString(m) }
+func (*ImportChannelPoliciesRequest) ProtoMessage()               {}
+func (*ImportChannelPoliciesRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{127} }
+
+func (m *ImportChannelPoliciesRequest) GetPolicies() []*ChannelPolicy {
+	if m != nil {
+		return m.Policies
+	}
+	return nil
+}
+
+func (m *ImportChannelPoliciesRequest) GetDryRun() bool {
+	if m != nil {
+		return m.DryRun
+	}
+	return false
+}
+
+type ChannelPolicyDiff struct {
+	ChanPoint string         `protobuf:"bytes,1,opt,name=chan_point" json:"chan_point,omitempty"`
+	OldPolicy *RoutingPolicy `protobuf:"bytes,2,opt,name=old_policy" json:"old_policy,omitempty"`
+	NewPolicy *RoutingPolicy `protobuf:"bytes,3,opt,name=new_policy" json:"new_policy,omitempty"`
+}
+
+func (m *ChannelPolicyDiff) Reset()                    { *m = ChannelPolicyDiff{} }
+func (m *ChannelPolicyDiff) String() string            { return proto.CompactTextString(m) }
+func (*ChannelPolicyDiff) ProtoMessage()               {}
+func (*ChannelPolicyDiff) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{128} }
+
+func (m *ChannelPolicyDiff) GetChanPoint() string {
+	if m != nil {
+		return m.ChanPoint
+	}
+	return ""
+}
+
+func (m *ChannelPolicyDiff) GetOldPolicy() *RoutingPolicy {
+	if m != nil {
+		return m.OldPolicy
+	}
+	return nil
+}
+
+func (m *ChannelPolicyDiff) GetNewPolicy() *RoutingPolicy {
+	if m != nil {
+		return m.NewPolicy
+	}
+	return nil
+}
+
+type ImportChannelPoliciesResponse struct {
+	Changes []*ChannelPolicyDiff `protobuf:"bytes,1,rep,name=changes" json:"changes,omitempty"`
+}
+
+func (m *ImportChannelPoliciesResponse) Reset()         { *m = ImportChannelPoliciesResponse{} }
+func (m *ImportChannelPoliciesResponse) String() string { return proto.CompactTextString(m) }
+func (*ImportChannelPoliciesResponse) ProtoMessage()    {}
+func (*ImportChannelPoliciesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{129}
+}
+
+func (m *ImportChannelPoliciesResponse) GetChanges() []*ChannelPolicyDiff {
+	if m != nil {
+		return m.Changes
+	}
+	return nil
+}
+
+type BalanceHistoryRequest struct {
+	StartTime int64 `protobuf:"varint,1,opt,name=start_time" json:"start_time,omitempty"`
+	EndTime   int64 `protobuf:"varint,2,opt,name=end_time" json:"end_time,omitempty"`
+}
+
+func (m *BalanceHistoryRequest) Reset()                    { *m = BalanceHistoryRequest{} }
+func (m *BalanceHistoryRequest) String() string            { return proto.CompactTextString(m) }
+func (*BalanceHistoryRequest) ProtoMessage()               {}
+func (*BalanceHistoryRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{130} }
+
+func (m *BalanceHistoryRequest) GetStartTime() int64 {
+	if m != nil {
+		return m.StartTime
+	}
+	return 0
+}
+
+func (m *BalanceHistoryRequest) GetEndTime() int64 {
+	if m != nil {
+		return m.EndTime
+	}
+	return 0
+}
+
+type BalanceSnapshot struct {
+	Timestamp          int64 `protobuf:"varint,1,opt,name=timestamp" json:"timestamp,omitempty"`
+	OnChainBalance     int64 `protobuf:"varint,2,opt,name=on_chain_balance" json:"on_chain_balance,omitempty"`
+	UnconfirmedBalance int64 `protobuf:"varint,3,opt,name=unconfirmed_balance" json:"unconfirmed_balance,omitempty"`
+	LocalBalance       int64 `protobuf:"varint,4,opt,name=local_balance" json:"local_balance,omitempty"`
+	RemoteBalance      int64 `protobuf:"varint,5,opt,name=remote_balance" json:"remote_balance,omitempty"`
+	PendingOpenBalance int64 `protobuf:"varint,6,opt,name=pending_open_balance" json:"pending_open_balance,omitempty"`
+	TotalBalance       int64 `protobuf:"varint,7,opt,name=total_balance" json:"total_balance,omitempty"`
+}
+
+func (m *BalanceSnapshot) Reset()                    { *m = BalanceSnapshot{} }
+func (m *BalanceSnapshot) String() string            { return proto.CompactTextString(m) }
+func (*BalanceSnapshot) ProtoMessage()               {}
+func (*BalanceSnapshot) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{131} }
+
+func (m *BalanceSnapshot) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *BalanceSnapshot) GetOnChainBalance() int64 {
+	if m != nil {
+		return m.OnChainBalance
+	}
+	return 0
+}
+
+func (m *BalanceSnapshot) GetUnconfirmedBalance() int64 {
+	if m != nil {
+		return m.UnconfirmedBalance
+	}
+	return 0
+}
+
+func (m *BalanceSnapshot) GetLocalBalance() int64 {
+	if m != nil {
+		return m.LocalBalance
+	}
+	return 0
+}
+
+func (m *BalanceSnapshot) GetRemoteBalance() int64 {
+	if m != nil {
+		return m.RemoteBalance
+	}
+	return 0
+}
+
+func (m *BalanceSnapshot) GetPendingOpenBalance() int64 {
+	if m != nil {
+		return m.PendingOpenBalance
+	}
+	return 0
+}
+
+func (m *BalanceSnapshot) GetTotalBalance() int64 {
+	if m != nil {
+		return m.TotalBalance
+	}
+	return 0
+}
+
+type BalanceHistoryResponse struct {
+	Snapshots []*BalanceSnapshot `protobuf:"bytes,1,rep,name=snapshots" json:"snapshots,omitempty"`
+}
+
+func (m *BalanceHistoryResponse) Reset()                    { *m = BalanceHistoryResponse{} }
+func (m *BalanceHistoryResponse) String() string            { return proto.CompactTextString(m) }
+func (*BalanceHistoryResponse) ProtoMessage()               {}
+func (*BalanceHistoryResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{132} }
+
+func (m *BalanceHistoryResponse) GetSnapshots() []*BalanceSnapshot {
+	if m != nil {
+		return m.Snapshots
+	}
+	return nil
+}
+
+type ListUnresolvedHTLCsRequest struct {
+	MinAge int64 `protobuf:"varint,1,opt,name=min_age" json:"min_age,omitempty"`
+}
+
+func (m *ListUnresolvedHTLCsRequest) Reset()                    { *m = ListUnresolvedHTLCsRequest{} }
+func (m *ListUnresolvedHTLCsRequest) String() string            { return proto.CompactTextString(m) }
+func (*ListUnresolvedHTLCsRequest) ProtoMessage()               {}
+func (*ListUnresolvedHTLCsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{133} }
+
+func (m *ListUnresolvedHTLCsRequest) GetMinAge() int64 {
+	if m != nil {
+		return m.MinAge
+	}
+	return 0
+}
+
+type UnresolvedHTLC struct {
+	ChannelPoint     string `protobuf:"bytes,1,opt,name=channel_point" json:"channel_point,omitempty"`
+	RemotePubkey     string `protobuf:"bytes,2,opt,name=remote_pubkey" json:"remote_pubkey,omitempty"`
+	Incoming         bool   `protobuf:"varint,3,opt,name=incoming" json:"incoming,omitempty"`
+	Amount           int64  `protobuf:"varint,4,opt,name=amount" json:"amount,omitempty"`
+	PaymentHash      []byte `protobuf:"bytes,5,opt,name=payment_hash,proto3" json:"payment_hash,omitempty"`
+	ExpirationHeight uint32 `protobuf:"varint,6,opt,name=expiration_height" json:"expiration_height,omitempty"`
+	Age              int64  `protobuf:"varint,7,opt,name=age" json:"age,omitempty"`
+	LockedIn         bool   `protobuf:"varint,8,opt,name=locked_in" json:"locked_in,omitempty"`
+	Owner            string `protobuf:"bytes,9,opt,name=owner" json:"owner,omitempty"`
+	InvoiceMemo      string `protobuf:"bytes,10,opt,name=invoice_memo" json:"invoice_memo,omitempty"`
+}
+
+func (m *UnresolvedHTLC) Reset()                    { *m = UnresolvedHTLC{} }
+func (m *UnresolvedHTLC) String() string            { return proto.CompactTextString(m) }
+func (*UnresolvedHTLC) ProtoMessage()               {}
+func (*UnresolvedHTLC) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{134} }
+
+func (m *UnresolvedHTLC) GetChannelPoint() string {
+	if m != nil {
+		return m.ChannelPoint
+	}
+	return ""
+}
+
+func (m *UnresolvedHTLC) GetRemotePubkey() string {
+	if m != nil {
+		return m.RemotePubkey
+	}
+	return ""
+}
+
+func (m *UnresolvedHTLC) GetIncoming() bool {
+	if m != nil {
+		return m.Incoming
+	}
+	return false
+}
+
+func (m *UnresolvedHTLC) GetAmount() int64 {
+	if m != nil {
+		return m.Amount
+	}
+	return 0
+}
+
+func (m *UnresolvedHTLC) GetPaymentHash() []byte {
+	if m != nil {
+		return m.PaymentHash
+	}
+	return nil
+}
+
+func (m *UnresolvedHTLC) GetExpirationHeight() uint32 {
+	if m != nil {
+		return m.ExpirationHeight
+	}
+	return 0
+}
+
+func (m *UnresolvedHTLC) GetAge() int64 {
+	if m != nil {
+		return m.Age
+	}
+	return 0
+}
+
+func (m *UnresolvedHTLC) GetLockedIn() bool {
+	if m != nil {
+		return m.LockedIn
+	}
+	return false
+}
+
+func (m *UnresolvedHTLC) GetOwner() string {
+	if m != nil {
+		return m.Owner
+	}
+	return ""
+}
+
+func (m *UnresolvedHTLC) GetInvoiceMemo() string {
+	if m != nil {
+		return m.InvoiceMemo
+	}
+	return ""
+}
+
+type ListUnresolvedHTLCsResponse struct {
+	Htlcs []*UnresolvedHTLC `protobuf:"bytes,1,rep,name=htlcs" json:"htlcs,omitempty"`
+}
+
+func (m *ListUnresolvedHTLCsResponse) Reset()                    { *m = ListUnresolvedHTLCsResponse{} }
+func (m *ListUnresolvedHTLCsResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListUnresolvedHTLCsResponse) ProtoMessage()               {}
+func (*ListUnresolvedHTLCsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{135} }
+
+func (m *ListUnresolvedHTLCsResponse) GetHtlcs() []*UnresolvedHTLC {
+	if m != nil {
+		return m.Htlcs
+	}
+	return nil
+}
+
+type UpdateCommitFeeRequest struct {
+	ChannelPoint *ChannelPoint `protobuf:"bytes,1,opt,name=channel_point" json:"channel_point,omitempty"`
+	Fee          int64         `protobuf:"varint,2,opt,name=fee" json:"fee,omitempty"`
+}
+
+func (m *UpdateCommitFeeRequest) Reset()                    { *m = UpdateCommitFeeRequest{} }
+func (m *UpdateCommitFeeRequest) String() string            { return proto.CompactTextString(m) }
+func (*UpdateCommitFeeRequest) ProtoMessage()               {}
+func (*UpdateCommitFeeRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{136} }
+
+func (m *UpdateCommitFeeRequest) GetChannelPoint() *ChannelPoint {
+	if m != nil {
+		return m.ChannelPoint
+	}
+	return nil
+}
+
+func (m *UpdateCommitFeeRequest) GetFee() int64 {
+	if m != nil {
+		return m.Fee
+	}
+	return 0
+}
+
+type UpdateCommitFeeResponse struct {
+}
+
+func (m *UpdateCommitFeeResponse) Reset()                    { *m = UpdateCommitFeeResponse{} }
+func (m *UpdateCommitFeeResponse) String() string            { return proto.CompactTextString(m) }
+func (*UpdateCommitFeeResponse) ProtoMessage()               {}
+func (*UpdateCommitFeeResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{137} }
+
+type BatchPaymentRequest struct {
+	PaymentRequests []string `protobuf:"bytes,1,rep,name=payment_requests" json:"payment_requests,omitempty"`
+	FeeBudget       int64    `protobuf:"varint,2,opt,name=fee_budget" json:"fee_budget,omitempty"`
+	CltvLimit       uint32   `protobuf:"varint,3,opt,name=cltv_limit" json:"cltv_limit,omitempty"`
+	MaxConcurrency  uint32   `protobuf:"varint,4,opt,name=max_concurrency" json:"max_concurrency,omitempty"`
+	FeePreset       string   `protobuf:"bytes,5,opt,name=fee_preset" json:"fee_preset,omitempty"`
+}
+
+func (m *BatchPaymentRequest) Reset()                    { *m = BatchPaymentRequest{} }
+func (m *BatchPaymentRequest) String() string            { return proto.CompactTextString(m) }
+func (*BatchPaymentRequest) ProtoMessage()               {}
+func (*BatchPaymentRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{138} }
+
+func (m *BatchPaymentRequest) GetPaymentRequests() []string {
+	if m != nil {
+		return m.PaymentRequests
+	}
+	return nil
+}
+
+func (m *BatchPaymentRequest) GetFeeBudget() int64 {
+	if m != nil {
+		return m.FeeBudget
+	}
+	return 0
+}
+
+func (m *BatchPaymentRequest) GetCltvLimit() uint32 {
+	if m != nil {
+		return m.CltvLimit
+	}
+	return 0
+}
+
+func (m *BatchPaymentRequest) GetMaxConcurrency() uint32 {
+	if m != nil {
+		return m.MaxConcurrency
+	}
+	return 0
+}
+
+func (m *BatchPaymentRequest) GetFeePreset() string {
+	if m != nil {
+		return m.FeePreset
+	}
+	return ""
+}
+
+type BatchPaymentResult struct {
+	Index           uint32 `protobuf:"varint,1,opt,name=index" json:"index,omitempty"`
+	PaymentRequest  string `protobuf:"bytes,2,opt,name=payment_request" json:"payment_request,omitempty"`
+	PaymentHash     []byte `protobuf:"bytes,3,opt,name=payment_hash,proto3" json:"payment_hash,omitempty"`
+	PaymentPreimage []byte `protobuf:"bytes,4,opt,name=payment_preimage,proto3" json:"payment_preimage,omitempty"`
+	PaymentRoute    *Route `protobuf:"bytes,5,opt,name=payment_route" json:"payment_route,omitempty"`
+	PaymentError    string `protobuf:"bytes,6,opt,name=payment_error" json:"payment_error,omitempty"`
+}
+
+func (m *BatchPaymentResult) Reset()                    { *m = BatchPaymentResult{} }
+func (m *BatchPaymentResult) String() string            { return proto.CompactTextString(m) }
+func (*BatchPaymentResult) ProtoMessage()               {}
+func (*BatchPaymentResult) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{139} }
+
+func (m *BatchPaymentResult) GetIndex() uint32 {
+	if m != nil {
+		return m.Index
+	}
+	return 0
+}
+
+func (m *BatchPaymentResult) GetPaymentRequest() string {
+	if m != nil {
+		return m.PaymentRequest
+	}
+	return ""
+}
+
+func (m *BatchPaymentResult) GetPaymentHash() []byte {
+	if m != nil {
+		return m.PaymentHash
+	}
+	return nil
+}
+
+func (m *BatchPaymentResult) GetPaymentPreimage() []byte {
+	if m != nil {
+		return m.PaymentPreimage
+	}
+	return nil
+}
+
+func (m *BatchPaymentResult) GetPaymentRoute() *Route {
+	if m != nil {
+		return m.PaymentRoute
+	}
+	return nil
+}
+
+func (m *BatchPaymentResult) GetPaymentError() string {
+	if m != nil {
+		return m.PaymentError
+	}
+	return ""
+}
+
+type ExportGossipRequest struct {
+	Since int64 `protobuf:"varint,1,opt,name=since" json:"since,omitempty"`
+}
+
+func (m *ExportGossipRequest) Reset()                    { *m = ExportGossipRequest{} }
+func (m *ExportGossipRequest) String() string            { return proto.CompactTextString(m) }
+func (*ExportGossipRequest) ProtoMessage()               {}
+func (*ExportGossipRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{140} }
+
+func (m *ExportGossipRequest) GetSince() int64 {
+	if m != nil {
+		return m.Since
+	}
+	return 0
+}
+
+type StoredGossipMessage struct {
+	Peer     string `protobuf:"bytes,1,opt,name=peer" json:"peer,omitempty"`
+	Received int64  `protobuf:"varint,2,opt,name=received" json:"received,omitempty"`
+	Message  []byte `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (m *StoredGossipMessage) Reset()                    { *m = StoredGossipMessage{} }
+func (m *StoredGossipMessage) String() string            { return proto.CompactTextString(m) }
+func (*StoredGossipMessage) ProtoMessage()               {}
+func (*StoredGossipMessage) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{141} }
+
+func (m *StoredGossipMessage) GetPeer() string {
+	if m != nil {
+		return m.Peer
+	}
+	return ""
+}
+
+func (m *StoredGossipMessage) GetReceived() int64 {
+	if m != nil {
+		return m.Received
+	}
+	return 0
+}
+
+func (m *StoredGossipMessage) GetMessage() []byte {
+	if m != nil {
+		return m.Message
+	}
+	return nil
+}
+
+type GossipMessages struct {
+	Messages []*StoredGossipMessage `protobuf:"bytes,1,rep,name=messages" json:"messages,omitempty"`
+}
+
+func (m *GossipMessages) Reset()                    { *m = GossipMessages{} }
+func (m *GossipMessages) String() string            { return proto.CompactTextString(m) }
+func (*GossipMessages) ProtoMessage()               {}
+func (*GossipMessages) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{142} }
+
+func (m *GossipMessages) GetMessages() []*StoredGossipMessage {
+	if m != nil {
+		return m.Messages
+	}
+	return nil
+}
+
+type ReplayGossipResponse struct {
+	NumReplayed uint32 `protobuf:"varint,1,opt,name=num_replayed" json:"num_replayed,omitempty"`
+}
+
+func (m *ReplayGossipResponse) Reset()                    { *m = ReplayGossipResponse{} }
+func (m *ReplayGossipResponse) String() string            { return proto.CompactTextString(m) }
+func (*ReplayGossipResponse) ProtoMessage()               {}
+func (*ReplayGossipResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{143} }
+
+func (m *ReplayGossipResponse) GetNumReplayed() uint32 {
+	if m != nil {
+		return m.NumReplayed
+	}
+	return 0
+}
+
+type SpliceInRequest struct {
+	ChannelPoint *ChannelPoint `protobuf:"bytes,1,opt,name=channel_point" json:"channel_point,omitempty"`
+	Amount       int64         `protobuf:"varint,2,opt,name=amount" json:"amount,omitempty"`
+}
+
+func (m *SpliceInRequest) Reset()                    { *m = SpliceInRequest{} }
+func (m *SpliceInRequest) String() string            { return proto.CompactTextString(m) }
+func (*SpliceInRequest) ProtoMessage()               {}
+func (*SpliceInRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{144} }
+
+func (m *SpliceInRequest) GetChannelPoint() *ChannelPoint {
+	if m != nil {
+		return m.ChannelPoint
+	}
+	return nil
+}
+
+func (m *SpliceInRequest) GetAmount() int64 {
+	if m != nil {
+		return m.Amount
+	}
+	return 0
+}
+
+type SpliceInResponse struct {
+	SpliceTxid []byte `protobuf:"bytes,1,opt,name=splice_txid,proto3" json:"splice_txid,omitempty"`
+}
+
+func (m *SpliceInResponse) Reset()                    { *m = SpliceInResponse{} }
+func (m *SpliceInResponse) String() string            { return proto.CompactTextString(m) }
+func (*SpliceInResponse) ProtoMessage()               {}
+func (*SpliceInResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{145} }
+
+func (m *SpliceInResponse) GetSpliceTxid() []byte {
+	if m != nil {
+		return m.SpliceTxid
+	}
+	return nil
+}
+
+type BackupUploadStatusRequest struct {
+}
+
+func (m *BackupUploadStatusRequest) Reset()                    { *m = BackupUploadStatusRequest{} }
+func (m *BackupUploadStatusRequest) String() string            { return proto.CompactTextString(m) }
+func (*BackupUploadStatusRequest) ProtoMessage()               {}
+func (*BackupUploadStatusRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{146} }
+
+type BackupUploadTarget struct {
+	Target      string `protobuf:"bytes,1,opt,name=target" json:"target,omitempty"`
+	Successes   uint64 `protobuf:"varint,2,opt,name=successes" json:"successes,omitempty"`
+	Failures    uint64 `protobuf:"varint,3,opt,name=failures" json:"failures,omitempty"`
+	LastSuccess int64  `protobuf:"varint,4,opt,name=last_success" json:"last_success,omitempty"`
+	LastError   string `protobuf:"bytes,5,opt,name=last_error" json:"last_error,omitempty"`
+	UpToDate    bool   `protobuf:"varint,6,opt,name=up_to_date" json:"up_to_date,omitempty"`
+}
+
+func (m *BackupUploadTarget) Reset()                    { *m = BackupUploadTarget{} }
+func (m *BackupUploadTarget) String() string            { return proto.CompactTextString(m) }
+func (*BackupUploadTarget) ProtoMessage()               {}
+func (*BackupUploadTarget) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{147} }
+
+func (m *BackupUploadTarget) GetTarget() string {
+	if m != nil {
+		return m.Target
+	}
+	return ""
+}
+
+func (m *BackupUploadTarget) GetSuccesses() uint64 {
+	if m != nil {
+		return m.Successes
+	}
+	return 0
+}
+
+func (m *BackupUploadTarget) GetFailures() uint64 {
+	if m != nil {
+		return m.Failures
+	}
+	return 0
+}
+
+func (m *BackupUploadTarget) GetLastSuccess() int64 {
+	if m != nil {
+		return m.LastSuccess
+	}
+	return 0
+}
+
+func (m *BackupUploadTarget) GetLastError() string {
+	if m != nil {
+		return m.LastError
+	}
+	return ""
+}
+
+func (m *BackupUploadTarget) GetUpToDate() bool {
+	if m != nil {
+		return m.UpToDate
+	}
+	return false
+}
+
+type BackupUploadStatusResponse struct {
+	Targets []*BackupUploadTarget `protobuf:"bytes,1,rep,name=targets" json:"targets,omitempty"`
+}
+
+func (m *BackupUploadStatusResponse) Reset()                    { *m = BackupUploadStatusResponse{} }
+func (m *BackupUploadStatusResponse) String() string            { return proto.CompactTextString(m) }
+func (*BackupUploadStatusResponse) ProtoMessage()               {}
+func (*BackupUploadStatusResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{148} }
+
+func (m *BackupUploadStatusResponse) GetTargets() []*BackupUploadTarget {
+	if m != nil {
+		return m.Targets
+	}
+	return nil
+}
+
+type DBCommitStatsRequest struct {
+}
+
+func (m *DBCommitStatsRequest) Reset()                    { *m = DBCommitStatsRequest{} }
+func (m *DBCommitStatsRequest) String() string            { return proto.CompactTextString(m) }
+func (*DBCommitStatsRequest) ProtoMessage()               {}
+func (*DBCommitStatsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{149} }
+
+type DBCommitStat struct {
+	CallSite       string `protobuf:"bytes,1,opt,name=call_site" json:"call_site,omitempty"`
+	Batched        bool   `protobuf:"varint,2,opt,name=batched" json:"batched,omitempty"`
+	NumCommits     uint64 `protobuf:"varint,3,opt,name=num_commits" json:"num_commits,omitempty"`
+	TotalLatencyUs uint64 `protobuf:"varint,4,opt,name=total_latency_us" json:"total_latency_us,omitempty"`
+	MaxLatencyUs   uint64 `protobuf:"varint,5,opt,name=max_latency_us" json:"max_latency_us,omitempty"`
+}
+
+func (m *DBCommitStat) Reset()                    { *m = DBCommitStat{} }
+func (m *DBCommitStat) String() string            { return proto.CompactTextString(m) }
+func (*DBCommitStat) ProtoMessage()               {}
+func (*DBCommitStat) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{150} }
+
+func (m *DBCommitStat) GetCallSite() string {
+	if m != nil {
+		return m.CallSite
+	}
+	return ""
+}
+
+func (m *DBCommitStat) GetBatched() bool {
+	if m != nil {
+		return m.Batched
+	}
+	return false
+}
+
+func (m *DBCommitStat) GetNumCommits() uint64 {
+	if m != nil {
+		return m.NumCommits
+	}
+	return 0
+}
+
+func (m *DBCommitStat) GetTotalLatencyUs() uint64 {
+	if m != nil {
+		return m.TotalLatencyUs
+	}
+	return 0
+}
+
+func (m *DBCommitStat) GetMaxLatencyUs() uint64 {
+	if m != nil {
+		return m.MaxLatencyUs
+	}
+	return 0
+}
+
+type DBCommitStatsResponse struct {
+	Stats []*DBCommitStat `protobuf:"bytes,1,rep,name=stats" json:"stats,omitempty"`
+}
+
+func (m *DBCommitStatsResponse) Reset()                    { *m = DBCommitStatsResponse{} }
+func (m *DBCommitStatsResponse) String() string            { return proto.CompactTextString(m) }
+func (*DBCommitStatsResponse) ProtoMessage()               {}
+func (*DBCommitStatsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{151} }
+
+func (m *DBCommitStatsResponse) GetStats() []*DBCommitStat {
+	if m != nil {
+		return m.Stats
+	}
+	return nil
+}
+
+type AccountingRequest struct {
+	StartTime int64 `protobuf:"varint,1,opt,name=start_time" json:"start_time,omitempty"`
+	EndTime   int64 `protobuf:"varint,2,opt,name=end_time" json:"end_time,omitempty"`
+}
+
+func (m *AccountingRequest) Reset()                    { *m = AccountingRequest{} }
+func (m *AccountingRequest) String() string            { return proto.CompactTextString(m) }
+func (*AccountingRequest) ProtoMessage()               {}
+func (*AccountingRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{152} }
+
+func (m *AccountingRequest) GetStartTime() int64 {
+	if m != nil {
+		return m.StartTime
+	}
+	return 0
+}
+
+func (m *AccountingRequest) GetEndTime() int64 {
+	if m != nil {
+		return m.EndTime
+	}
+	return 0
+}
+
+type AccountingEntry struct {
+	Timestamp   int64  `protobuf:"varint,1,opt,name=timestamp" json:"timestamp,omitempty"`
+	Type        string `protobuf:"bytes,2,opt,name=type" json:"type,omitempty"`
+	Amount      int64  `protobuf:"varint,3,opt,name=amount" json:"amount,omitempty"`
+	Fee         int64  `protobuf:"varint,4,opt,name=fee" json:"fee,omitempty"`
+	Reference   string `protobuf:"bytes,5,opt,name=reference" json:"reference,omitempty"`
+	Description string `protobuf:"bytes,6,opt,name=description" json:"description,omitempty"`
+}
+
+func (m *AccountingEntry) Reset()                    { *m = AccountingEntry{} }
+func (m *AccountingEntry) String() string            { return proto.CompactTextString(m) }
+func (*AccountingEntry) ProtoMessage()               {}
+func (*AccountingEntry) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{153} }
+
+func (m *AccountingEntry) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+func (m *AccountingEntry) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *AccountingEntry) GetAmount() int64 {
+	if m != nil {
+		return m.Amount
+	}
+	return 0
+}
+
+func (m *AccountingEntry) GetFee() int64 {
+	if m != nil {
+		return m.Fee
+	}
+	return 0
+}
+
+func (m *AccountingEntry) GetReference() string {
+	if m != nil {
+		return m.Reference
+	}
+	return ""
+}
+
+func (m *AccountingEntry) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
+
+type AccountingReport struct {
+	Entries []*AccountingEntry `protobuf:"bytes,1,rep,name=entries" json:"entries,omitempty"`
+}
+
+func (m *AccountingReport) Reset()                    { *m = AccountingReport{} }
+func (m *AccountingReport) String() string            { return proto.CompactTextString(m) }
+func (*AccountingReport) ProtoMessage()               {}
+func (*AccountingReport) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{154} }
+
+func (m *AccountingReport) GetEntries() []*AccountingEntry {
+	if m != nil {
+		return m.Entries
+	}
+	return nil
+}
+
+type AbandonChannelResponse struct {
+	UnlockedOutpoints []string `protobuf:"bytes,1,rep,name=unlocked_outpoints" json:"unlocked_outpoints,omitempty"`
+}
+
+func (m *AbandonChannelResponse) Reset()                    { *m = AbandonChannelResponse{} }
+func (m *AbandonChannelResponse) String() string            { return proto.CompactTextString(m) }
+func (*AbandonChannelResponse) ProtoMessage()               {}
+func (*AbandonChannelResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{155} }
+
+func (m *AbandonChannelResponse) GetUnlockedOutpoints() []string {
+	if m != nil {
+		return m.UnlockedOutpoints
+	}
+	return nil
+}
+
+type RotateIdentityRequest struct {
+}
+
+func (m *RotateIdentityRequest) Reset()                    { *m = RotateIdentityRequest{} }
+func (m *RotateIdentityRequest) String() string            { return proto.CompactTextString(m) }
+func (*RotateIdentityRequest) ProtoMessage()               {}
+func (*RotateIdentityRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{156} }
+
+type RotateIdentityResponse struct {
+	IdentityPubkey  string          `protobuf:"bytes,1,opt,name=identity_pubkey" json:"identity_pubkey,omitempty"`
+	ClosingChannels []*ChannelPoint `protobuf:"bytes,2,rep,name=closing_channels" json:"closing_channels,omitempty"`
+}
+
+func (m *RotateIdentityResponse) Reset()                    { *m = RotateIdentityResponse{} }
+func (m *RotateIdentityResponse) String() string            { return proto.CompactTextString(m) }
+func (*RotateIdentityResponse) ProtoMessage()               {}
+func (*RotateIdentityResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{157} }
+
+func (m *RotateIdentityResponse) GetIdentityPubkey() string {
+	if m != nil {
+		return m.IdentityPubkey
+	}
+	return ""
+}
+
+func (m *RotateIdentityResponse) GetClosingChannels() []*ChannelPoint {
+	if m != nil {
+		return m.ClosingChannels
+	}
+	return nil
+}
+
+type MineBlocksRequest struct {
+	NumBlocks uint32 `protobuf:"varint,1,opt,name=num_blocks" json:"num_blocks,omitempty"`
+}
+
+func (m *MineBlocksRequest) Reset()                    { *m = MineBlocksRequest{} }
+func (m *MineBlocksRequest) String() string            { return proto.CompactTextString(m) }
+func (*MineBlocksRequest) ProtoMessage()               {}
+func (*MineBlocksRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{158} }
+
+func (m *MineBlocksRequest) GetNumBlocks() uint32 {
+	if m != nil {
+		return m.NumBlocks
+	}
+	return 0
+}
+
+type MineBlocksResponse struct {
+	BlockHashes []string `protobuf:"bytes,1,rep,name=block_hashes" json:"block_hashes,omitempty"`
+	Height      int32    `protobuf:"varint,2,opt,name=height" json:"height,omitempty"`
+}
+
+func (m *MineBlocksResponse) Reset()                    { *m = MineBlocksResponse{} }
+func (m *MineBlocksResponse) String() string            { return proto.CompactTextString(m) }
+func (*MineBlocksResponse) ProtoMessage()               {}
+func (*MineBlocksResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{159} }
+
+func (m *MineBlocksResponse) GetBlockHashes() []string {
+	if m != nil {
+		return m.BlockHashes
+	}
+	return nil
+}
+
+func (m *MineBlocksResponse) GetHeight() int32 {
+	if m != nil {
+		return m.Height
+	}
+	return 0
+}
+
+type AdvanceTimeRequest struct {
+	Seconds int64 `protobuf:"varint,1,opt,name=seconds" json:"seconds,omitempty"`
+}
+
+func (m *AdvanceTimeRequest) Reset()                    { *m = AdvanceTimeRequest{} }
+func (m *AdvanceTimeRequest) String() string            { return proto.CompactTextString(m) }
+func (*AdvanceTimeRequest) ProtoMessage()               {}
+func (*AdvanceTimeRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{160} }
+
+func (m *AdvanceTimeRequest) GetSeconds() int64 {
+	if m != nil {
+		return m.Seconds
+	}
+	return 0
+}
+
+type AdvanceTimeResponse struct {
+	Timestamp int64 `protobuf:"varint,1,opt,name=timestamp" json:"timestamp,omitempty"`
+}
+
+func (m *AdvanceTimeResponse) Reset()                    { *m = AdvanceTimeResponse{} }
+func (m *AdvanceTimeResponse) String() string            { return proto.CompactTextString(m) }
+func (*AdvanceTimeResponse) ProtoMessage()               {}
+func (*AdvanceTimeResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{161} }
+
+func (m *AdvanceTimeResponse) GetTimestamp() int64 {
+	if m != nil {
+		return m.Timestamp
+	}
+	return 0
+}
+
+type EstimateOpenChannelResponse struct {
+	Capacity         int64  `protobuf:"varint,1,opt,name=capacity" json:"capacity,omitempty"`
+	FundingFee       int64  `protobuf:"varint,2,opt,name=funding_fee" json:"funding_fee,omitempty"`
+	FundingFeeRate   int64  `protobuf:"varint,3,opt,name=funding_fee_rate" json:"funding_fee_rate,omitempty"`
+	NumInputs        uint32 `protobuf:"varint,4,opt,name=num_inputs" json:"num_inputs,omitempty"`
+	ChangeAmount     int64  `protobuf:"varint,5,opt,name=change_amount" json:"change_amount,omitempty"`
+	CommitFee        int64  `protobuf:"varint,6,opt,name=commit_fee" json:"commit_fee,omitempty"`
+	AnchorsAmount    int64  `protobuf:"varint,7,opt,name=anchors_amount" json:"anchors_amount,omitempty"`
+	LocalReserve     int64  `protobuf:"varint,8,opt,name=local_reserve" json:"local_reserve,omitempty"`
+	RemoteReserve    int64  `protobuf:"varint,9,opt,name=remote_reserve" json:"remote_reserve,omitempty"`
+	CsvDelay         uint32 `protobuf:"varint,10,opt,name=csv_delay" json:"csv_delay,omitempty"`
+	SweepFee         int64  `protobuf:"varint,11,opt,name=sweep_fee" json:"sweep_fee,omitempty"`
+	ForceCloseCost   int64  `protobuf:"varint,12,opt,name=force_close_cost" json:"force_close_cost,omitempty"`
+	AvailableBalance int64  `protobuf:"varint,13,opt,name=available_balance" json:"available_balance,omitempty"`
+	CanFund          bool   `protobuf:"varint,14,opt,name=can_fund" json:"can_fund,omitempty"`
+}
+
+func (m *EstimateOpenChannelResponse) Reset()                    { *m = EstimateOpenChannelResponse{} }
+func (m *EstimateOpenChannelResponse) String() string            { return proto.CompactTextString(m) }
+func (*EstimateOpenChannelResponse) ProtoMessage()               {}
+func (*EstimateOpenChannelResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{162} }
+
+func (m *EstimateOpenChannelResponse) GetCapacity() int64 {
+	if m != nil {
+		return m.Capacity
+	}
+	return 0
+}
+
+func (m *EstimateOpenChannelResponse) GetFundingFee() int64 {
+	if m != nil {
+		return m.FundingFee
+	}
+	return 0
+}
+
+func (m *EstimateOpenChannelResponse) GetFundingFeeRate() int64 {
+	if m != nil {
+		return m.FundingFeeRate
+	}
+	return 0
+}
+
+func (m *EstimateOpenChannelResponse) GetNumInputs() uint32 {
+	if m != nil {
+		return m.NumInputs
+	}
+	return 0
+}
+
+func (m *EstimateOpenChannelResponse) GetChangeAmount() int64 {
+	if m != nil {
+		return m.ChangeAmount
+	}
+	return 0
+}
+
+func (m *EstimateOpenChannelResponse) GetCommitFee() int64 {
+	if m != nil {
+		return m.CommitFee
+	}
+	return 0
+}
+
+func (m *EstimateOpenChannelResponse) GetAnchorsAmount() int64 {
+	if m != nil {
+		return m.AnchorsAmount
+	}
+	return 0
+}
+
+func (m *EstimateOpenChannelResponse) GetLocalReserve() int64 {
+	if m != nil {
+		return m.LocalReserve
+	}
+	return 0
+}
+
+func (m *EstimateOpenChannelResponse) GetRemoteReserve() int64 {
+	if m != nil {
+		return m.RemoteReserve
+	}
+	return 0
+}
+
+func (m *EstimateOpenChannelResponse) GetCsvDelay() uint32 {
+	if m != nil {
+		return m.CsvDelay
+	}
+	return 0
+}
+
+func (m *EstimateOpenChannelResponse) GetSweepFee() int64 {
+	if m != nil {
+		return m.SweepFee
+	}
+	return 0
+}
+
+func (m *EstimateOpenChannelResponse) GetForceCloseCost() int64 {
+	if m != nil {
+		return m.ForceCloseCost
+	}
+	return 0
+}
+
+func (m *EstimateOpenChannelResponse) GetAvailableBalance() int64 {
+	if m != nil {
+		return m.AvailableBalance
+	}
+	return 0
+}
+
+func (m *EstimateOpenChannelResponse) GetCanFund() bool {
+	if m != nil {
+		return m.CanFund
+	}
+	return false
+}
+
+type GetFundingPolicyRequest struct {
+}
+
+func (m *GetFundingPolicyRequest) Reset()                    { *m = GetFundingPolicyRequest{} }
+func (m *GetFundingPolicyRequest) String() string            { return proto.CompactTextString(m) }
+func (*GetFundingPolicyRequest) ProtoMessage()               {}
+func (*GetFundingPolicyRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{163} }
+
+type FundingPolicy struct {
+	MinChanSize     int64  `protobuf:"varint,1,opt,name=min_chan_size" json:"min_chan_size,omitempty"`
+	MaxChanSize     int64  `protobuf:"varint,2,opt,name=max_chan_size" json:"max_chan_size,omitempty"`
+	MaxChansPerPeer uint32 `protobuf:"varint,3,opt,name=max_chans_per_peer" json:"max_chans_per_peer,omitempty"`
+	MaxPeerExposure int64  `protobuf:"varint,4,opt,name=max_peer_exposure" json:"max_peer_exposure,omitempty"`
+}
+
+func (m *FundingPolicy) Reset()                    { *m = FundingPolicy{} }
+func (m *FundingPolicy) String() string            { return proto.CompactTextString(m) }
+func (*FundingPolicy) ProtoMessage()               {}
+func (*FundingPolicy) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{164} }
+
+func (m *FundingPolicy) GetMinChanSize() int64 {
+	if m != nil {
+		return m.MinChanSize
+	}
+	return 0
+}
+
+func (m *FundingPolicy) GetMaxChanSize() int64 {
+	if m != nil {
+		return m.MaxChanSize
+	}
+	return 0
+}
+
+func (m *FundingPolicy) GetMaxChansPerPeer() uint32 {
+	if m != nil {
+		return m.MaxChansPerPeer
+	}
+	return 0
+}
+
+func (m *FundingPolicy) GetMaxPeerExposure() int64 {
+	if m != nil {
+		return m.MaxPeerExposure
+	}
+	return 0
+}
+
+type UpdateFundingPolicyResponse struct {
+}
+
+func (m *UpdateFundingPolicyResponse) Reset()                    { *m = UpdateFundingPolicyResponse{} }
+func (m *UpdateFundingPolicyResponse) String() string            { return proto.CompactTextString(m) }
+func (*UpdateFundingPolicyResponse) ProtoMessage()               {}
+func (*UpdateFundingPolicyResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{165} }
+
+type ListPaymentAttemptsRequest struct {
+	PaymentHash string `protobuf:"bytes,1,opt,name=payment_hash" json:"payment_hash,omitempty"`
+}
+
+func (m *ListPaymentAttemptsRequest) Reset()                    { *m = ListPaymentAttemptsRequest{} }
+func (m *ListPaymentAttemptsRequest) String() string            { return proto.CompactTextString(m) }
+func (*ListPaymentAttemptsRequest) ProtoMessage()               {}
+func (*ListPaymentAttemptsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{166} }
+
+func (m *ListPaymentAttemptsRequest) GetPaymentHash() string {
+	if m != nil {
+		return m.PaymentHash
+	}
+	return ""
+}
+
+type PaymentAttempt struct {
+	AttemptTime int64    `protobuf:"varint,1,opt,name=attempt_time" json:"attempt_time,omitempty"`
+	FeeLimit    int64    `protobuf:"varint,2,opt,name=fee_limit" json:"fee_limit,omitempty"`
+	Path        []string `protobuf:"bytes,3,rep,name=path" json:"path,omitempty"`
+	Fee         int64    `protobuf:"varint,4,opt,name=fee" json:"fee,omitempty"`
+	TimeLock    uint32   `protobuf:"varint,5,opt,name=time_lock" json:"time_lock,omitempty"`
+	Error       string   `protobuf:"bytes,6,opt,name=error" json:"error,omitempty"`
+}
+
+func (m *PaymentAttempt) Reset()                    { *m = PaymentAttempt{} }
+func (m *PaymentAttempt) String() string            { return proto.CompactTextString(m) }
+func (*PaymentAttempt) ProtoMessage()               {}
+func (*PaymentAttempt) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{167} }
+
+func (m *PaymentAttempt) GetAttemptTime() int64 {
+	if m != nil {
+		return m.AttemptTime
+	}
+	return 0
+}
+
+func (m *PaymentAttempt) GetFeeLimit() int64 {
+	if m != nil {
+		return m.FeeLimit
+	}
+	return 0
+}
+
+func (m *PaymentAttempt) GetPath() []string {
+	if m != nil {
+		return m.Path
+	}
+	return nil
+}
+
+func (m *PaymentAttempt) GetFee() int64 {
+	if m != nil {
+		return m.Fee
+	}
+	return 0
+}
+
+func (m *PaymentAttempt) GetTimeLock() uint32 {
+	if m != nil {
+		return m.TimeLock
+	}
+	return 0
+}
+
+func (m *PaymentAttempt) GetError() string {
+	if m != nil {
+		return m.Error
+	}
+	return ""
+}
+
+type ListPaymentAttemptsResponse struct {
+	Attempts []*PaymentAttempt `protobuf:"bytes,1,rep,name=attempts" json:"attempts,omitempty"`
+}
+
+func (m *ListPaymentAttemptsResponse) Reset()                    { *m = ListPaymentAttemptsResponse{} }
+func (m *ListPaymentAttemptsResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListPaymentAttemptsResponse) ProtoMessage()               {}
+func (*ListPaymentAttemptsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{168} }
+
+func (m *ListPaymentAttemptsResponse) GetAttempts() []*PaymentAttempt {
+	if m != nil {
+		return m.Attempts
+	}
+	return nil
+}
+
+type StateLogRequest struct {
+	ChannelPoint *ChannelPoint `protobuf:"bytes,1,opt,name=channel_point" json:"channel_point,omitempty"`
+	Limit        uint32        `protobuf:"varint,2,opt,name=limit" json:"limit,omitempty"`
+}
+
+func (m *StateLogRequest) Reset()                    { *m = StateLogRequest{} }
+func (m *StateLogRequest) String() string            { return proto.CompactTextString(m) }
+func (*StateLogRequest) ProtoMessage()               {}
+func (*StateLogRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{169} }
+
+func (m *StateLogRequest) GetChannelPoint() *ChannelPoint {
+	if m != nil {
+		return m.ChannelPoint
+	}
+	return nil
+}
+
+func (m *StateLogRequest) GetLimit() uint32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+type StateEvent struct {
+	Type         string `protobuf:"bytes,1,opt,name=type" json:"type,omitempty"`
+	TimestampNs  int64  `protobuf:"varint,2,opt,name=timestamp_ns" json:"timestamp_ns,omitempty"`
+	LocalHeight  uint64 `protobuf:"varint,3,opt,name=local_height" json:"local_height,omitempty"`
+	RemoteHeight uint64 `protobuf:"varint,4,opt,name=remote_height" json:"remote_height,omitempty"`
+	Detail       string `protobuf:"bytes,5,opt,name=detail" json:"detail,omitempty"`
+}
+
+func (m *StateEvent) Reset()                    { *m = StateEvent{} }
+func (m *StateEvent) String() string            { return proto.CompactTextString(m) }
+func (*StateEvent) ProtoMessage()               {}
+func (*StateEvent) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{170} }
+
+func (m *StateEvent) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *StateEvent) GetTimestampNs() int64 {
+	if m != nil {
+		return m.TimestampNs
+	}
+	return 0
+}
+
+func (m *StateEvent) GetLocalHeight() uint64 {
+	if m != nil {
+		return m.LocalHeight
+	}
+	return 0
+}
+
+func (m *StateEvent) GetRemoteHeight() uint64 {
+	if m != nil {
+		return m.RemoteHeight
+	}
+	return 0
+}
+
+func (m *StateEvent) GetDetail() string {
+	if m != nil {
+		return m.Detail
+	}
+	return ""
+}
+
+type StateLogResponse struct {
+	Events []*StateEvent `protobuf:"bytes,1,rep,name=events" json:"events,omitempty"`
+}
+
+func (m *StateLogResponse) Reset()                    { *m = StateLogResponse{} }
+func (m *StateLogResponse) String() string            { return proto.CompactTextString(m) }
+func (*StateLogResponse) ProtoMessage()               {}
+func (*StateLogResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{171} }
+
+func (m *StateLogResponse) GetEvents() []*StateEvent {
+	if m != nil {
+		return m.Events
+	}
+	return nil
+}
+
+type PeerSettings struct {
+	PubKey          string         `protobuf:"bytes,1,opt,name=pub_key" json:"pub_key,omitempty"`
+	FeePolicy       *RoutingPolicy `protobuf:"bytes,2,opt,name=fee_policy" json:"fee_policy,omitempty"`
+	MaxChannels     uint32         `protobuf:"varint,3,opt,name=max_channels" json:"max_channels,omitempty"`
+	GossipSyncMode  string         `protobuf:"bytes,4,opt,name=gossip_sync_mode" json:"gossip_sync_mode,omitempty"`
+	MaxPendingHtlcs uint32         `protobuf:"varint,5,opt,name=max_pending_htlcs" json:"max_pending_htlcs,omitempty"`
+	MaxHtlcValue    int64          `protobuf:"varint,6,opt,name=max_htlc_value" json:"max_htlc_value,omitempty"`
+}
+
+func (m *PeerSettings) Reset()                    { *m = PeerSettings{} }
+func (m *PeerSettings) String() string            { return proto.CompactTextString(m) }
+func (*PeerSettings) ProtoMessage()               {}
+func (*PeerSettings) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{172} }
+
+func (m *PeerSettings) GetPubKey() string {
+	if m != nil {
+		return m.PubKey
+	}
+	return ""
+}
+
+func (m *PeerSettings) GetFeePolicy() *RoutingPolicy {
+	if m != nil {
+		return m.FeePolicy
+	}
+	return nil
+}
+
+func (m *PeerSettings) GetMaxChannels() uint32 {
+	if m != nil {
+		return m.MaxChannels
+	}
+	return 0
+}
+
+func (m *PeerSettings) GetGossipSyncMode() string {
+	if m != nil {
+		return m.GossipSyncMode
+	}
+	return ""
+}
+
+func (m *PeerSettings) GetMaxPendingHtlcs() uint32 {
+	if m != nil {
+		return m.MaxPendingHtlcs
+	}
+	return 0
+}
+
+func (m *PeerSettings) GetMaxHtlcValue() int64 {
+	if m != nil {
+		return m.MaxHtlcValue
+	}
+	return 0
+}
+
+type UpdatePeerSettingsResponse struct {
+}
+
+func (m *UpdatePeerSettingsResponse) Reset()                    { *m = UpdatePeerSettingsResponse{} }
+func (m *UpdatePeerSettingsResponse) String() string            { return proto.CompactTextString(m) }
+func (*UpdatePeerSettingsResponse) ProtoMessage()               {}
+func (*UpdatePeerSettingsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{173} }
+
+type ListPeerSettingsRequest struct {
+}
+
+func (m *ListPeerSettingsRequest) Reset()                    { *m = ListPeerSettingsRequest{} }
+func (m *ListPeerSettingsRequest) String() string            { return proto.CompactTextString(m) }
+func (*ListPeerSettingsRequest) ProtoMessage()               {}
+func (*ListPeerSettingsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{174} }
+
+type ListPeerSettingsResponse struct {
+	Peers []*PeerSettings `protobuf:"bytes,1,rep,name=peers" json:"peers,omitempty"`
+}
+
+func (m *ListPeerSettingsResponse) Reset()                    { *m = ListPeerSettingsResponse{} }
+func (m *ListPeerSettingsResponse) String() string            { return proto.CompactTextString(m) }
+func (*ListPeerSettingsResponse) ProtoMessage()               {}
+func (*ListPeerSettingsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{175} }
+
+func (m *ListPeerSettingsResponse) GetPeers() []*PeerSettings {
+	if m != nil {
+		return m.Peers
+	}
+	return nil
+}
+
+type DeletePeerSettingsRequest struct {
+	PubKey string `protobuf:"bytes,1,opt,name=pub_key" json:"pub_key,omitempty"`
+}
+
+func (m *DeletePeerSettingsRequest) Reset()                    { *m = DeletePeerSettingsRequest{} }
+func (m *DeletePeerSettingsRequest) String() string            { return proto.CompactTextString(m) }
+func (*DeletePeerSettingsRequest) ProtoMessage()               {}
+func (*DeletePeerSettingsRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{176} }
+
+func (m *DeletePeerSettingsRequest) GetPubKey() string {
+	if m != nil {
+		return m.PubKey
+	}
+	return ""
+}
+
+type DeletePeerSettingsResponse struct {
+}
+
+func (m *DeletePeerSettingsResponse) Reset()                    { *m = DeletePeerSettingsResponse{} }
+func (m *DeletePeerSettingsResponse) String() string            { return proto.CompactTextString(m) }
+func (*DeletePeerSettingsResponse) ProtoMessage()               {}
+func (*DeletePeerSettingsResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{177} }
+
+type PaymentTelemetryRequest struct {
+}
+
+func (m *PaymentTelemetryRequest) Reset()                    { *m = PaymentTelemetryRequest{} }
+func (m *PaymentTelemetryRequest) String() string            { return proto.CompactTextString(m) }
+func (*PaymentTelemetryRequest) ProtoMessage()               {}
+func (*PaymentTelemetryRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{178} }
+
+type FailureCodeCount struct {
+	Code  string `protobuf:"bytes,1,opt,name=code" json:"code,omitempty"`
+	Count uint64 `protobuf:"varint,2,opt,name=count" json:"count,omitempty"`
+}
+
+func (m *FailureCodeCount) Reset()                    { *m = FailureCodeCount{} }
+func (m *FailureCodeCount) String() string            { return proto.CompactTextString(m) }
+func (*FailureCodeCount) ProtoMessage()               {}
+func (*FailureCodeCount) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{179} }
+
+func (m *FailureCodeCount) GetCode() string {
+	if m != nil {
+		return m.Code
+	}
+	return ""
+}
+
+func (m *FailureCodeCount) GetCount() uint64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+type PaymentTelemetryResponse struct {
+	NumPayments    uint64              `protobuf:"varint,1,opt,name=num_payments" json:"num_payments,omitempty"`
+	NumSucceeded   uint64              `protobuf:"varint,2,opt,name=num_succeeded" json:"num_succeeded,omitempty"`
+	SuccessRate    float64             `protobuf:"fixed64,3,opt,name=success_rate" json:"success_rate,omitempty"`
+	AvgAttempts    float64             `protobuf:"fixed64,4,opt,name=avg_attempts" json:"avg_attempts,omitempty"`
+	LatencyP50Ms   int64               `protobuf:"varint,5,opt,name=latency_p50_ms" json:"latency_p50_ms,omitempty"`
+	LatencyP90Ms   int64               `protobuf:"varint,6,opt,name=latency_p90_ms" json:"latency_p90_ms,omitempty"`
+	LatencyP99Ms   int64               `protobuf:"varint,7,opt,name=latency_p99_ms" json:"latency_p99_ms,omitempty"`
+	FailureCodes   []*FailureCodeCount `protobuf:"bytes,8,rep,name=failure_codes" json:"failure_codes,omitempty"`
+	TotalPayments  uint64              `protobuf:"varint,9,opt,name=total_payments" json:"total_payments,omitempty"`
+	TotalSucceeded uint64              `protobuf:"varint,10,opt,name=total_succeeded" json:"total_succeeded,omitempty"`
+}
+
+func (m *PaymentTelemetryResponse) Reset()                    { *m = PaymentTelemetryResponse{} }
+func (m *PaymentTelemetryResponse) String() string            { return proto.CompactTextString(m) }
+func (*PaymentTelemetryResponse) ProtoMessage()               {}
+func (*PaymentTelemetryResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{180} }
+
+func (m *PaymentTelemetryResponse) GetNumPayments() uint64 {
+	if m != nil {
+		return m.NumPayments
+	}
+	return 0
+}
+
+func (m *PaymentTelemetryResponse) GetNumSucceeded() uint64 {
+	if m != nil {
+		return m.NumSucceeded
+	}
+	return 0
+}
+
+func (m *PaymentTelemetryResponse) GetSuccessRate() float64 {
+	if m != nil {
+		return m.SuccessRate
+	}
+	return 0
+}
+
+func (m *PaymentTelemetryResponse) GetAvgAttempts() float64 {
+	if m != nil {
+		return m.AvgAttempts
+	}
+	return 0
+}
+
+func (m *PaymentTelemetryResponse) GetLatencyP50Ms() int64 {
+	if m != nil {
+		return m.LatencyP50Ms
+	}
+	return 0
+}
+
+func (m *PaymentTelemetryResponse) GetLatencyP90Ms() int64 {
+	if m != nil {
+		return m.LatencyP90Ms
+	}
+	return 0
+}
+
+func (m *PaymentTelemetryResponse) GetLatencyP99Ms() int64 {
+	if m != nil {
+		return m.LatencyP99Ms
+	}
+	return 0
+}
+
+func (m *PaymentTelemetryResponse) GetFailureCodes() []*FailureCodeCount {
+	if m != nil {
+		return m.FailureCodes
+	}
+	return nil
+}
+
+func (m *PaymentTelemetryResponse) GetTotalPayments() uint64 {
+	if m != nil {
+		return m.TotalPayments
+	}
+	return 0
+}
+
+func (m *PaymentTelemetryResponse) GetTotalSucceeded() uint64 {
+	if m != nil {
+		return m.TotalSucceeded
+	}
+	return 0
+}
+
+type TowerInfoRequest struct {
+}
+
+func (m *TowerInfoRequest) Reset()                    { *m = TowerInfoRequest{} }
+func (m *TowerInfoRequest) String() string            { return proto.CompactTextString(m) }
+func (*TowerInfoRequest) ProtoMessage()               {}
+func (*TowerInfoRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{181} }
+
+type TowerInfoResponse struct {
+	Active              bool   `protobuf:"varint,1,opt,name=active" json:"active,omitempty"`
+	NumSessions         uint64 `protobuf:"varint,2,opt,name=num_sessions" json:"num_sessions,omitempty"`
+	NumBlobs            uint64 `protobuf:"varint,3,opt,name=num_blobs" json:"num_blobs,omitempty"`
+	StorageUsed         uint64 `protobuf:"varint,4,opt,name=storage_used" json:"storage_used,omitempty"`
+	MaxStorage          uint64 `protobuf:"varint,5,opt,name=max_storage" json:"max_storage,omitempty"`
+	RewardRate          uint32 `protobuf:"varint,6,opt,name=reward_rate" json:"reward_rate,omitempty"`
+	MaxStoragePerClient uint64 `protobuf:"varint,7,opt,name=max_storage_per_client" json:"max_storage_per_client,omitempty"`
+}
+
+func (m *TowerInfoResponse) Reset()                    { *m = TowerInfoResponse{} }
+func (m *TowerInfoResponse) String() string            { return proto.CompactTextString(m) }
+func (*TowerInfoResponse) ProtoMessage()               {}
+func (*TowerInfoResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{182} }
+
+func (m *TowerInfoResponse) GetActive() bool {
+	if m != nil {
+		return m.Active
+	}
+	return false
+}
+
+func (m *TowerInfoResponse) GetNumSessions() uint64 {
+	if m != nil {
+		return m.NumSessions
+	}
+	return 0
+}
+
+func (m *TowerInfoResponse) GetNumBlobs() uint64 {
+	if m != nil {
+		return m.NumBlobs
+	}
+	return 0
+}
+
+func (m *TowerInfoResponse) GetStorageUsed() uint64 {
+	if m != nil {
+		return m.StorageUsed
+	}
+	return 0
+}
+
+func (m *TowerInfoResponse) GetMaxStorage() uint64 {
+	if m != nil {
+		return m.MaxStorage
+	}
+	return 0
+}
+
+func (m *TowerInfoResponse) GetRewardRate() uint32 {
+	if m != nil {
+		return m.RewardRate
+	}
+	return 0
+}
+
+func (m *TowerInfoResponse) GetMaxStoragePerClient() uint64 {
+	if m != nil {
+		return m.MaxStoragePerClient
+	}
+	return 0
+}
+
+type ChannelStatesRequest struct {
+	Dot bool `protobuf:"varint,1,opt,name=dot" json:"dot,omitempty"`
+}
+
+func (m *ChannelStatesRequest) Reset()                    { *m = ChannelStatesRequest{} }
+func (m *ChannelStatesRequest) String() string            { return proto.CompactTextString(m) }
+func (*ChannelStatesRequest) ProtoMessage()               {}
+func (*ChannelStatesRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{183} }
+
+func (m *ChannelStatesRequest) GetDot() bool {
+	if m != nil {
+		return m.Dot
+	}
+	return false
+}
+
+type ChannelStateMachine struct {
+	ChannelPoint       string `protobuf:"bytes,1,opt,name=channel_point" json:"channel_point,omitempty"`
+	RemotePubkey       string `protobuf:"bytes,2,opt,name=remote_pubkey" json:"remote_pubkey,omitempty"`
+	Phase              string `protobuf:"bytes,3,opt,name=phase" json:"phase,omitempty"`
+	LocalTailHeight    uint64 `protobuf:"varint,4,opt,name=local_tail_height" json:"local_tail_height,omitempty"`
+	LocalTipHeight     uint64 `protobuf:"varint,5,opt,name=local_tip_height" json:"local_tip_height,omitempty"`
+	RemoteTailHeight   uint64 `protobuf:"varint,6,opt,name=remote_tail_height" json:"remote_tail_height,omitempty"`
+	RemoteTipHeight    uint64 `protobuf:"varint,7,opt,name=remote_tip_height" json:"remote_tip_height,omitempty"`
+	LocalLogIndex      uint64 `protobuf:"varint,8,opt,name=local_log_index" json:"local_log_index,omitempty"`
+	RemoteLogIndex     uint64 `protobuf:"varint,9,opt,name=remote_log_index" json:"remote_log_index,omitempty"`
+	UnsignedUpdates    uint64 `protobuf:"varint,10,opt,name=unsigned_updates" json:"unsigned_updates,omitempty"`
+	UncommittedUpdates uint64 `protobuf:"varint,11,opt,name=uncommitted_updates" json:"uncommitted_updates,omitempty"`
+	AwaitingRevocation bool   `protobuf:"varint,12,opt,name=awaiting_revocation" json:"awaiting_revocation,omitempty"`
+	RevocationWindow   uint32 `protobuf:"varint,13,opt,name=revocation_window" json:"revocation_window,omitempty"`
+	LocalShutdown      bool   `protobuf:"varint,14,opt,name=local_shutdown" json:"local_shutdown,omitempty"`
+	RemoteShutdown     bool   `protobuf:"varint,15,opt,name=remote_shutdown" json:"remote_shutdown,omitempty"`
+}
+
+func (m *ChannelStateMachine) Reset()                    { *m = ChannelStateMachine{} }
+func (m *ChannelStateMachine) String() string            { return proto.CompactTextString(m) }
+func (*ChannelStateMachine) ProtoMessage()               {}
+func (*ChannelStateMachine) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{184} }
+
+func (m *ChannelStateMachine) GetChannelPoint() string {
+	if m != nil {
+		return m.ChannelPoint
+	}
+	return ""
+}
+
+func (m *ChannelStateMachine) GetRemotePubkey() string {
+	if m != nil {
+		return m.RemotePubkey
+	}
+	return ""
+}
+
+func (m *ChannelStateMachine) GetPhase() string {
+	if m != nil {
+		return m.Phase
+	}
+	return ""
+}
+
+func (m *ChannelStateMachine) GetLocalTailHeight() uint64 {
+	if m != nil {
+		return m.LocalTailHeight
+	}
+	return 0
+}
+
+func (m *ChannelStateMachine) GetLocalTipHeight() uint64 {
+	if m != nil {
+		return m.LocalTipHeight
+	}
+	return 0
+}
+
+func (m *ChannelStateMachine) GetRemoteTailHeight() uint64 {
+	if m != nil {
+		return m.RemoteTailHeight
+	}
+	return 0
+}
+
+func (m *ChannelStateMachine) GetRemoteTipHeight() uint64 {
+	if m != nil {
+		return m.RemoteTipHeight
+	}
+	return 0
+}
+
+func (m *ChannelStateMachine) GetLocalLogIndex() uint64 {
+	if m != nil {
+		return m.LocalLogIndex
+	}
+	return 0
+}
+
+func (m *ChannelStateMachine) GetRemoteLogIndex() uint64 {
+	if m != nil {
+		return m.RemoteLogIndex
+	}
+	return 0
+}
+
+func (m *ChannelStateMachine) GetUnsignedUpdates() uint64 {
+	if m != nil {
+		return m.UnsignedUpdates
+	}
+	return 0
+}
+
+func (m *ChannelStateMachine) GetUncommittedUpdates() uint64 {
+	if m != nil {
+		return m.UncommittedUpdates
+	}
+	return 0
+}
+
+func (m *ChannelStateMachine) GetAwaitingRevocation() bool {
+	if m != nil {
+		return m.AwaitingRevocation
+	}
+	return false
+}
+
+func (m *ChannelStateMachine) GetRevocationWindow() uint32 {
+	if m != nil {
+		return m.RevocationWindow
+	}
+	return 0
+}
+
+func (m *ChannelStateMachine) GetLocalShutdown() bool {
+	if m != nil {
+		return m.LocalShutdown
+	}
+	return false
+}
+
+func (m *ChannelStateMachine) GetRemoteShutdown() bool {
+	if m != nil {
+		return m.RemoteShutdown
+	}
+	return false
+}
+
+type ChannelStatesResponse struct {
+	Channels []*ChannelStateMachine `protobuf:"bytes,1,rep,name=channels" json:"channels,omitempty"`
+	Dot      string                 `protobuf:"bytes,2,opt,name=dot" json:"dot,omitempty"`
+}
+
+func (m *ChannelStatesResponse) Reset()                    { *m = ChannelStatesResponse{} }
+func (m *ChannelStatesResponse) String() string            { return proto.CompactTextString(m) }
+func (*ChannelStatesResponse) ProtoMessage()               {}
+func (*ChannelStatesResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{185} }
+
+func (m *ChannelStatesResponse) GetChannels() []*ChannelStateMachine {
+	if m != nil {
+		return m.Channels
+	}
+	return nil
+}
+
+func (m *ChannelStatesResponse) GetDot() string {
+	if m != nil {
+		return m.Dot
+	}
+	return ""
+}
+
+type FreezeChannelResponse struct {
+}
+
+func (m *FreezeChannelResponse) Reset()                    { *m = FreezeChannelResponse{} }
+func (m *FreezeChannelResponse) String() string            { return proto.CompactTextString(m) }
+func (*FreezeChannelResponse) ProtoMessage()               {}
+func (*FreezeChannelResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{186} }
+
+type ThawChannelResponse struct {
+}
+
+func (m *ThawChannelResponse) Reset()                    { *m = ThawChannelResponse{} }
+func (m *ThawChannelResponse) String() string            { return proto.CompactTextString(m) }
+func (*ThawChannelResponse) ProtoMessage()               {}
+func (*ThawChannelResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{187} }
+
+type CreatePayReqRequest struct {
+	RHash    []byte `protobuf:"bytes,1,opt,name=r_hash,proto3" json:"r_hash,omitempty"`
+	RHashStr string `protobuf:"bytes,2,opt,name=r_hash_str" json:"r_hash_str,omitempty"`
+	Expiry   int64  `protobuf:"varint,3,opt,name=expiry" json:"expiry,omitempty"`
+}
+
+func (m *CreatePayReqRequest) Reset()                    { *m = CreatePayReqRequest{} }
+func (m *CreatePayReqRequest) String() string            { return proto.CompactTextString(m) }
+func (*CreatePayReqRequest) ProtoMessage()               {}
+func (*CreatePayReqRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{188} }
+
+func (m *CreatePayReqRequest) GetRHash() []byte {
+	if m != nil {
+		return m.RHash
+	}
+	return nil
+}
+
+func (m *CreatePayReqRequest) GetRHashStr() string {
+	if m != nil {
+		return m.RHashStr
+	}
+	return ""
+}
+
+func (m *CreatePayReqRequest) GetExpiry() int64 {
+	if m != nil {
+		return m.Expiry
+	}
+	return 0
+}
+
+type CreatePayReqResponse struct {
+	PayReq string `protobuf:"bytes,1,opt,name=pay_req" json:"pay_req,omitempty"`
+}
+
+func (m *CreatePayReqResponse) Reset()                    { *m = CreatePayReqResponse{} }
+func (m *CreatePayReqResponse) String() string            { return proto.CompactTextString(m) }
+func (*CreatePayReqResponse) ProtoMessage()               {}
+func (*CreatePayReqResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{189} }
+
+func (m *CreatePayReqResponse) GetPayReq() string {
+	if m != nil {
+		return m.PayReq
+	}
+	return ""
+}
+
+type RevenueReportRequest struct {
+	StartTime int64 `protobuf:"varint,1,opt,name=start_time" json:"start_time,omitempty"`
+	EndTime   int64 `protobuf:"varint,2,opt,name=end_time" json:"end_time,omitempty"`
+}
+
+func (m *RevenueReportRequest) Reset()                    { *m = RevenueReportRequest{} }
+func (m *RevenueReportRequest) String() string            { return proto.CompactTextString(m) }
+func (*RevenueReportRequest) ProtoMessage()               {}
+func (*RevenueReportRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{190} }
+
+func (m *RevenueReportRequest) GetStartTime() int64 {
+	if m != nil {
+		return m.StartTime
+	}
+	return 0
+}
+
+func (m *RevenueReportRequest) GetEndTime() int64 {
+	if m != nil {
+		return m.EndTime
+	}
+	return 0
+}
+
+type DailyRevenue struct {
+	Day            int64  `protobuf:"varint,1,opt,name=day" json:"day,omitempty"`
+	InvoiceRevenue int64  `protobuf:"varint,2,opt,name=invoice_revenue" json:"invoice_revenue,omitempty"`
+	ForwardingFees int64  `protobuf:"varint,3,opt,name=forwarding_fees" json:"forwarding_fees,omitempty"`
+	NumForwards    uint64 `protobuf:"varint,4,opt,name=num_forwards" json:"num_forwards,omitempty"`
+}
+
+func (m *DailyRevenue) Reset()                    { *m = DailyRevenue{} }
+func (m *DailyRevenue) String() string            { return proto.CompactTextString(m) }
+func (*DailyRevenue) ProtoMessage()               {}
+func (*DailyRevenue) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{191} }
+
+func (m *DailyRevenue) GetDay() int64 {
+	if m != nil {
+		return m.Day
+	}
+	return 0
+}
+
+func (m *DailyRevenue) GetInvoiceRevenue() int64 {
+	if m != nil {
+		return m.InvoiceRevenue
+	}
+	return 0
+}
+
+func (m *DailyRevenue) GetForwardingFees() int64 {
+	if m != nil {
+		return m.ForwardingFees
+	}
+	return 0
+}
+
+func (m *DailyRevenue) GetNumForwards() uint64 {
+	if m != nil {
+		return m.NumForwards
+	}
+	return 0
+}
+
+type RevenueReportResponse struct {
+	Days []*DailyRevenue `protobuf:"bytes,1,rep,name=days" json:"days,omitempty"`
+}
+
+func (m *RevenueReportResponse) Reset()                    { *m = RevenueReportResponse{} }
+func (m *RevenueReportResponse) String() string            { return proto.CompactTextString(m) }
+func (*RevenueReportResponse) ProtoMessage()               {}
+func (*RevenueReportResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{192} }
+
+func (m *RevenueReportResponse) GetDays() []*DailyRevenue {
+	if m != nil {
+		return m.Days
+	}
+	return nil
+}
+
+type TopCounterpartiesRequest struct {
+	Limit uint32 `protobuf:"varint,1,opt,name=limit" json:"limit,omitempty"`
+}
+
+func (m *TopCounterpartiesRequest) Reset()                    { *m = TopCounterpartiesRequest{} }
+func (m *TopCounterpartiesRequest) String() string            { return proto.CompactTextString(m) }
+func (*TopCounterpartiesRequest) ProtoMessage()               {}
+func (*TopCounterpartiesRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{193} }
+
+func (m *TopCounterpartiesRequest) GetLimit() uint32 {
+	if m != nil {
+		return m.Limit
+	}
+	return 0
+}
+
+type CounterpartyVolume struct {
+	PubKey string `protobuf:"bytes,1,opt,name=pub_key" json:"pub_key,omitempty"`
+	Volume int64  `protobuf:"varint,2,opt,name=volume" json:"volume,omitempty"`
+	Count  uint64 `protobuf:"varint,3,opt,name=count" json:"count,omitempty"`
+}
+
+func (m *CounterpartyVolume) Reset()                    { *m = CounterpartyVolume{} }
+func (m *CounterpartyVolume) String() string            { return proto.CompactTextString(m) }
+func (*CounterpartyVolume) ProtoMessage()               {}
+func (*CounterpartyVolume) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{194} }
+
+func (m *CounterpartyVolume) GetPubKey() string {
+	if m != nil {
+		return m.PubKey
+	}
+	return ""
+}
+
+func (m *CounterpartyVolume) GetVolume() int64 {
+	if m != nil {
+		return m.Volume
+	}
+	return 0
+}
+
+func (m *CounterpartyVolume) GetCount() uint64 {
+	if m != nil {
+		return m.Count
+	}
+	return 0
+}
+
+type TopCounterpartiesResponse struct {
+	Counterparties []*CounterpartyVolume `protobuf:"bytes,1,rep,name=counterparties" json:"counterparties,omitempty"`
+}
+
+func (m *TopCounterpartiesResponse) Reset()                    { *m = TopCounterpartiesResponse{} }
+func (m *TopCounterpartiesResponse) String() string            { return proto.CompactTextString(m) }
+func (*TopCounterpartiesResponse) ProtoMessage()               {}
+func (*TopCounterpartiesResponse) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{195} }
+
+func (m *TopCounterpartiesResponse) GetCounterparties() []*CounterpartyVolume {
+	if m != nil {
+		return m.Counterparties
+	}
+	return nil
+}
+
+type HopHint struct {
+	NodeId                    string `protobuf:"bytes,1,opt,name=node_id" json:"node_id,omitempty"`
+	ChanId                    uint64 `protobuf:"varint,2,opt,name=chan_id" json:"chan_id,omitempty"`
+	FeeBaseMsat               uint32 `protobuf:"varint,3,opt,name=fee_base_msat" json:"fee_base_msat,omitempty"`
+	FeeProportionalMillionths uint32 `protobuf:"varint,4,opt,name=fee_proportional_millionths" json:"fee_proportional_millionths,omitempty"`
+	CltvExpiryDelta           uint32 `protobuf:"varint,5,opt,name=cltv_expiry_delta" json:"cltv_expiry_delta,omitempty"`
+}
+
+func (m *HopHint) Reset()                    { *m = HopHint{} }
+func (m *HopHint) String() string            { return proto.CompactTextString(m) }
+func (*HopHint) ProtoMessage()               {}
+func (*HopHint) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{196} }
+
+func (m *HopHint) GetNodeId() string {
+	if m != nil {
+		return m.NodeId
+	}
+	return ""
+}
+
+func (m *HopHint) GetChanId() uint64 {
+	if m != nil {
+		return m.ChanId
+	}
+	return 0
+}
+
+func (m *HopHint) GetFeeBaseMsat() uint32 {
+	if m != nil {
+		return m.FeeBaseMsat
+	}
+	return 0
+}
+
+func (m *HopHint) GetFeeProportionalMillionths() uint32 {
+	if m != nil {
+		return m.FeeProportionalMillionths
+	}
+	return 0
+}
+
+func (m *HopHint) GetCltvExpiryDelta() uint32 {
+	if m != nil {
+		return m.CltvExpiryDelta
+	}
+	return 0
+}
+
+type RouteHint struct {
+	HopHints []*HopHint `protobuf:"bytes,1,rep,name=hop_hints" json:"hop_hints,omitempty"`
+}
+
+func (m *RouteHint) Reset()                    { *m = RouteHint{} }
+func (m *RouteHint) String() string            { return proto.CompactTextString(m) }
+func (*RouteHint) ProtoMessage()               {}
+func (*RouteHint) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{197} }
+
+func (m *RouteHint) GetHopHints() []*HopHint {
+	if m != nil {
+		return m.HopHints
+	}
+	return nil
+}
+
+type ConfirmIdentityRotationRequest struct {
+	IdentityPubkey string `protobuf:"bytes,1,opt,name=identity_pubkey" json:"identity_pubkey,omitempty"`
+}
+
+func (m *ConfirmIdentityRotationRequest) Reset()         { *m = ConfirmIdentityRotationRequest{} }
+func (m *ConfirmIdentityRotationRequest) String() string { return proto.CompactTextString(m) }
+func (*ConfirmIdentityRotationRequest) ProtoMessage()    {}
+func (*ConfirmIdentityRotationRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{198}
+}
+
+func (m *ConfirmIdentityRotationRequest) GetIdentityPubkey() string {
+	if m != nil {
+		return m.IdentityPubkey
+	}
+	return ""
+}
+
+type ConfirmIdentityRotationResponse struct {
+}
+
+func (m *ConfirmIdentityRotationResponse) Reset()         { *m = ConfirmIdentityRotationResponse{} }
+func (m *ConfirmIdentityRotationResponse) String() string { return proto.CompactTextString(m) }
+func (*ConfirmIdentityRotationResponse) ProtoMessage()    {}
+func (*ConfirmIdentityRotationResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{199}
+}
+
+type ImportChannelStateRequest struct {
+	StateJson   string `protobuf:"bytes,1,opt,name=state_json" json:"state_json,omitempty"`
+	PeerAddress string `protobuf:"bytes,2,opt,name=peer_address" json:"peer_address,omitempty"`
+}
+
+func (m *ImportChannelStateRequest) Reset()                    { *m = ImportChannelStateRequest{} }
+func (m *ImportChannelStateRequest) String() string            { return proto.CompactTextString(m) }
+func (*ImportChannelStateRequest) ProtoMessage()               {}
+func (*ImportChannelStateRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{200} }
+
+func (m *ImportChannelStateRequest) GetStateJson() string {
+	if m != nil {
+		return m.StateJson
+	}
+	return ""
+}
+
+func (m *ImportChannelStateRequest) GetPeerAddress() string {
+	if m != nil {
+		return m.PeerAddress
+	}
+	return ""
+}
+
+func init() {
+	proto.RegisterType((*Transaction)(nil), "lnrpc.Transaction")
+	proto.RegisterType((*GetTransactionsRequest)(nil), "lnrpc.GetTransactionsRequest")
+	proto.RegisterType((*TransactionDetails)(nil), "lnrpc.TransactionDetails")
+	proto.RegisterType((*SendRequest)(nil), "lnrpc.SendRequest")
+	proto.RegisterType((*SendResponse)(nil), "lnrpc.SendResponse")
+	proto.RegisterType((*ChannelPoint)(nil), "lnrpc.ChannelPoint")
+	proto.RegisterType((*LightningAddress)(nil), "lnrpc.LightningAddress")
+	proto.RegisterType((*SendManyRequest)(nil), "lnrpc.SendManyRequest")
+	proto.RegisterType((*SendManyResponse)(nil), "lnrpc.SendManyResponse")
+	proto.RegisterType((*SendCoinsRequest)(nil), "lnrpc.SendCoinsRequest")
+	proto.RegisterType((*SendCoinsResponse)(nil), "lnrpc.SendCoinsResponse")
+	proto.RegisterType((*NewAddressRequest)(nil), "lnrpc.NewAddressRequest")
+	proto.RegisterType((*NewWitnessAddressRequest)(nil), "lnrpc.NewWitnessAddressRequest")
+	proto.RegisterType((*NewAddressResponse)(nil), "lnrpc.NewAddressResponse")
+	proto.RegisterType((*ConnectPeerRequest)(nil), "lnrpc.ConnectPeerRequest")
+	proto.RegisterType((*ConnectPeerResponse)(nil), "lnrpc.ConnectPeerResponse")
+	proto.RegisterType((*HTLC)(nil), "lnrpc.HTLC")
+	proto.RegisterType((*ActiveChannel)(nil), "lnrpc.ActiveChannel")
+	proto.RegisterType((*ListChannelsRequest)(nil), "lnrpc.ListChannelsRequest")
+	proto.RegisterType((*ListChannelsResponse)(nil), "lnrpc.ListChannelsResponse")
+	proto.RegisterType((*Peer)(nil), "lnrpc.Peer")
+	proto.RegisterType((*ListPeersRequest)(nil), "lnrpc.ListPeersRequest")
+	proto.RegisterType((*ListPeersResponse)(nil), "lnrpc.ListPeersResponse")
+	proto.RegisterType((*GetInfoRequest)(nil), "lnrpc.GetInfoRequest")
+	proto.RegisterType((*GetInfoResponse)(nil), "lnrpc.GetInfoResponse")
+	proto.RegisterType((*ConfirmationUpdate)(nil), "lnrpc.ConfirmationUpdate")
+	proto.RegisterType((*ChannelOpenUpdate)(nil), "lnrpc.ChannelOpenUpdate")
+	proto.RegisterType((*ChannelCloseUpdate)(nil), "lnrpc.ChannelCloseUpdate")
+	proto.RegisterType((*CloseChannelRequest)(nil), "lnrpc.CloseChannelRequest")
+	proto.RegisterType((*CloseStatusUpdate)(nil), "lnrpc.CloseStatusUpdate")
+	proto.RegisterType((*PendingUpdate)(nil), "lnrpc.PendingUpdate")
+	proto.RegisterType((*OpenChannelRequest)(nil), "lnrpc.OpenChannelRequest")
+	proto.RegisterType((*OpenStatusUpdate)(nil), "lnrpc.OpenStatusUpdate")
+	proto.RegisterType((*PendingChannelRequest)(nil), "lnrpc.PendingChannelRequest")
+	proto.RegisterType((*PendingChannelResponse)(nil), "lnrpc.PendingChannelResponse")
+	proto.RegisterType((*PendingChannelResponse_PendingChannel)(nil), "lnrpc.PendingChannelResponse.PendingChannel")
+	proto.RegisterType((*ExportChanBackupRequest)(nil), "lnrpc.ExportChanBackupRequest")
+	proto.RegisterType((*ChanBackupSnapshot)(nil), "lnrpc.ChanBackupSnapshot")
+	proto.RegisterType((*ChannelBackupReport)(nil), "lnrpc.ChannelBackupReport")
+	proto.RegisterType((*VerifyChanBackupResponse)(nil), "lnrpc.VerifyChanBackupResponse")
+	proto.RegisterType((*RestoreChanBackupRequest)(nil), "lnrpc.RestoreChanBackupRequest")
+	proto.RegisterType((*RestoreChanBackupResponse)(nil), "lnrpc.RestoreChanBackupResponse")
+	proto.RegisterType((*ImportChannelRecoveryRequest)(nil), "lnrpc.ImportChannelRecoveryRequest")
+	proto.RegisterType((*ImportChannelRecoveryResponse)(nil), "lnrpc.ImportChannelRecoveryResponse")
+	proto.RegisterType((*WalletBalanceRequest)(nil), "lnrpc.WalletBalanceRequest")
+	proto.RegisterType((*WalletBalanceResponse)(nil), "lnrpc.WalletBalanceResponse")
+	proto.RegisterType((*ChannelBalanceRequest)(nil), "lnrpc.ChannelBalanceRequest")
+	proto.RegisterType((*ChannelBalanceResponse)(nil), "lnrpc.ChannelBalanceResponse")
+	proto.RegisterType((*RouteRequest)(nil), "lnrpc.RouteRequest")
+	proto.RegisterType((*Hop)(nil), "lnrpc.Hop")
+	proto.RegisterType((*Route)(nil), "lnrpc.Route")
+	proto.RegisterType((*NodeInfoRequest)(nil), "lnrpc.NodeInfoRequest")
+	proto.RegisterType((*NodeInfo)(nil), "lnrpc.NodeInfo")
+	proto.RegisterType((*LightningNode)(nil), "lnrpc.LightningNode")
+	proto.RegisterType((*RoutingPolicy)(nil), "lnrpc.RoutingPolicy")
+	proto.RegisterType((*ChannelEdge)(nil), "lnrpc.ChannelEdge")
+	proto.RegisterType((*ChannelGraphRequest)(nil), "lnrpc.ChannelGraphRequest")
+	proto.RegisterType((*ChannelGraph)(nil), "lnrpc.ChannelGraph")
+	proto.RegisterType((*ChanInfoRequest)(nil), "lnrpc.ChanInfoRequest")
+	proto.RegisterType((*NetworkInfoRequest)(nil), "lnrpc.NetworkInfoRequest")
+	proto.RegisterType((*NetworkInfo)(nil), "lnrpc.NetworkInfo")
+	proto.RegisterType((*GraphTopologySubscription)(nil), "lnrpc.GraphTopologySubscription")
+	proto.RegisterType((*GraphTopologyUpdate)(nil), "lnrpc.GraphTopologyUpdate")
+	proto.RegisterType((*NodeUpdate)(nil), "lnrpc.NodeUpdate")
+	proto.RegisterType((*ChannelEdgeUpdate)(nil), "lnrpc.ChannelEdgeUpdate")
+	proto.RegisterType((*ClosedChannelUpdate)(nil), "lnrpc.ClosedChannelUpdate")
+	proto.RegisterType((*SetAliasRequest)(nil), "lnrpc.SetAliasRequest")
+	proto.RegisterType((*SetAliasResponse)(nil), "lnrpc.SetAliasResponse")
+	proto.RegisterType((*Invoice)(nil), "lnrpc.Invoice")
+	proto.RegisterType((*AddInvoiceResponse)(nil), "lnrpc.AddInvoiceResponse")
+	proto.RegisterType((*PaymentHash)(nil), "lnrpc.PaymentHash")
+	proto.RegisterType((*ListInvoiceRequest)(nil), "lnrpc.ListInvoiceRequest")
+	proto.RegisterType((*ListInvoiceResponse)(nil), "lnrpc.ListInvoiceResponse")
+	proto.RegisterType((*InvoiceSubscription)(nil), "lnrpc.InvoiceSubscription")
+	proto.RegisterType((*Offer)(nil), "lnrpc.Offer")
+	proto.RegisterType((*AddOfferResponse)(nil), "lnrpc.AddOfferResponse")
+	proto.RegisterType((*ListOffersRequest)(nil), "lnrpc.ListOffersRequest")
+	proto.RegisterType((*ListOffersResponse)(nil), "lnrpc.ListOffersResponse")
+	proto.RegisterType((*OfferInvoiceRequest)(nil), "lnrpc.OfferInvoiceRequest")
+	proto.RegisterType((*OfferInvoiceResponse)(nil), "lnrpc.OfferInvoiceResponse")
+	proto.RegisterType((*Payment)(nil), "lnrpc.Payment")
+	proto.RegisterType((*ListPaymentsRequest)(nil), "lnrpc.ListPaymentsRequest")
+	proto.RegisterType((*ListPaymentsResponse)(nil), "lnrpc.ListPaymentsResponse")
+	proto.RegisterType((*DeleteAllPaymentsRequest)(nil), "lnrpc.DeleteAllPaymentsRequest")
+	proto.RegisterType((*DeleteAllPaymentsResponse)(nil), "lnrpc.DeleteAllPaymentsResponse")
+	proto.RegisterType((*DebugLevelRequest)(nil), "lnrpc.DebugLevelRequest")
+	proto.RegisterType((*DebugLevelResponse)(nil), "lnrpc.DebugLevelResponse")
+	proto.RegisterType((*ChannelStateExport)(nil), "lnrpc.ChannelStateExport")
+	proto.RegisterType((*PayReqString)(nil), "lnrpc.PayReqString")
+	proto.RegisterType((*PayReq)(nil), "lnrpc.PayReq")
+	proto.RegisterType((*FeePreset)(nil), "lnrpc.FeePreset")
+	proto.RegisterType((*ListFeePresetsRequest)(nil), "lnrpc.ListFeePresetsRequest")
+	proto.RegisterType((*ListFeePresetsResponse)(nil), "lnrpc.ListFeePresetsResponse")
+	proto.RegisterType((*ChannelHistoryRequest)(nil), "lnrpc.ChannelHistoryRequest")
+	proto.RegisterType((*ChannelEvent)(nil), "lnrpc.ChannelEvent")
+	proto.RegisterType((*ChannelHistoryResponse)(nil), "lnrpc.ChannelHistoryResponse")
+	proto.RegisterType((*MiddlewareRegistration)(nil), "lnrpc.MiddlewareRegistration")
+	proto.RegisterType((*RPCMiddlewareRequest)(nil), "lnrpc.RPCMiddlewareRequest")
+	proto.RegisterType((*RPCMiddlewareResponse)(nil), "lnrpc.RPCMiddlewareResponse")
+	proto.RegisterType((*ChannelRecommendation)(nil), "lnrpc.ChannelRecommendation")
+	proto.RegisterType((*ListRecommendationsRequest)(nil), "lnrpc.ListRecommendationsRequest")
+	proto.RegisterType((*ListRecommendationsResponse)(nil), "lnrpc.ListRecommendationsResponse")
+	proto.RegisterType((*ExecuteRecommendationRequest)(nil), "lnrpc.ExecuteRecommendationRequest")
+	proto.RegisterType((*ExecuteRecommendationResponse)(nil), "lnrpc.ExecuteRecommendationResponse")
+	proto.RegisterType((*OutboxSubscription)(nil), "lnrpc.OutboxSubscription")
+	proto.RegisterType((*OutboxNotification)(nil), "lnrpc.OutboxNotification")
+	proto.RegisterType((*OutboxAck)(nil), "lnrpc.OutboxAck")
+	proto.RegisterType((*OutboxAckResponse)(nil), "lnrpc.OutboxAckResponse")
+	proto.RegisterType((*RemoveOutboxSubscriberRequest)(nil), "lnrpc.RemoveOutboxSubscriberRequest")
+	proto.RegisterType((*RemoveOutboxSubscriberResponse)(nil), "lnrpc.RemoveOutboxSubscriberResponse")
+	proto.RegisterType((*CloseDryRun)(nil), "lnrpc.CloseDryRun")
+	proto.RegisterType((*EdgePenalty)(nil), "lnrpc.EdgePenalty")
+	proto.RegisterType((*NodePenalty)(nil), "lnrpc.NodePenalty")
+	proto.RegisterType((*QueryMissionControlRequest)(nil), "lnrpc.QueryMissionControlRequest")
+	proto.RegisterType((*QueryMissionControlResponse)(nil), "lnrpc.QueryMissionControlResponse")
+	proto.RegisterType((*ResetMissionControlRequest)(nil), "lnrpc.ResetMissionControlRequest")
+	proto.RegisterType((*ResetMissionControlResponse)(nil), "lnrpc.ResetMissionControlResponse")
+	proto.RegisterType((*AckChannelDivergenceResponse)(nil), "lnrpc.AckChannelDivergenceResponse")
+	proto.RegisterType((*CustomMessageSubscription)(nil), "lnrpc.CustomMessageSubscription")
+	proto.RegisterType((*CustomMessage)(nil), "lnrpc.CustomMessage")
+	proto.RegisterType((*SendCustomMessageRequest)(nil), "lnrpc.SendCustomMessageRequest")
+	proto.RegisterType((*SendCustomMessageResponse)(nil), "lnrpc.SendCustomMessageResponse")
+	proto.RegisterType((*SigningAuditRequest)(nil), "lnrpc.SigningAuditRequest")
+	proto.RegisterType((*SigningEvent)(nil), "lnrpc.SigningEvent")
+	proto.RegisterType((*SigningAuditResponse)(nil), "lnrpc.SigningAuditResponse")
+	proto.RegisterType((*ExportChannelPoliciesRequest)(nil), "lnrpc.ExportChannelPoliciesRequest")
+	proto.RegisterType((*ChannelPolicy)(nil), "lnrpc.ChannelPolicy")
+	proto.RegisterType((*ChannelPolicies)(nil), "lnrpc.ChannelPolicies")
+	proto.RegisterType((*ImportChannelPoliciesRequest)(nil), "lnrpc.ImportChannelPoliciesRequest")
+	proto.RegisterType((*ChannelPolicyDiff)(nil), "lnrpc.ChannelPolicyDiff")
+	proto.RegisterType((*ImportChannelPoliciesResponse)(nil), "lnrpc.ImportChannelPoliciesResponse")
+	proto.RegisterType((*BalanceHistoryRequest)(nil), "lnrpc.BalanceHistoryRequest")
+	proto.RegisterType((*BalanceSnapshot)(nil), "lnrpc.BalanceSnapshot")
+	proto.RegisterType((*BalanceHistoryResponse)(nil), "lnrpc.BalanceHistoryResponse")
+	proto.RegisterType((*ListUnresolvedHTLCsRequest)(nil), "lnrpc.ListUnresolvedHTLCsRequest")
+	proto.RegisterType((*UnresolvedHTLC)(nil), "lnrpc.UnresolvedHTLC")
+	proto.RegisterType((*ListUnresolvedHTLCsResponse)(nil), "lnrpc.ListUnresolvedHTLCsResponse")
+	proto.RegisterType((*UpdateCommitFeeRequest)(nil), "lnrpc.UpdateCommitFeeRequest")
+	proto.RegisterType((*UpdateCommitFeeResponse)(nil), "lnrpc.UpdateCommitFeeResponse")
+	proto.RegisterType((*BatchPaymentRequest)(nil), "lnrpc.BatchPaymentRequest")
+	proto.RegisterType((*BatchPaymentResult)(nil), "lnrpc.BatchPaymentResult")
+	proto.RegisterType((*ExportGossipRequest)(nil), "lnrpc.ExportGossipRequest")
+	proto.RegisterType((*StoredGossipMessage)(nil), "lnrpc.StoredGossipMessage")
+	proto.RegisterType((*GossipMessages)(nil), "lnrpc.GossipMessages")
+	proto.RegisterType((*ReplayGossipResponse)(nil), "lnrpc.ReplayGossipResponse")
+	proto.RegisterType((*SpliceInRequest)(nil), "lnrpc.SpliceInRequest")
+	proto.RegisterType((*SpliceInResponse)(nil), "lnrpc.SpliceInResponse")
+	proto.RegisterType((*BackupUploadStatusRequest)(nil), "lnrpc.BackupUploadStatusRequest")
+	proto.RegisterType((*BackupUploadTarget)(nil), "lnrpc.BackupUploadTarget")
+	proto.RegisterType((*BackupUploadStatusResponse)(nil), "lnrpc.BackupUploadStatusResponse")
+	proto.RegisterType((*DBCommitStatsRequest)(nil), "lnrpc.DBCommitStatsRequest")
+	proto.RegisterType((*DBCommitStat)(nil), "lnrpc.DBCommitStat")
+	proto.RegisterType((*DBCommitStatsResponse)(nil), "lnrpc.DBCommitStatsResponse")
+	proto.RegisterType((*AccountingRequest)(nil), "lnrpc.AccountingRequest")
+	proto.RegisterType((*AccountingEntry)(nil), "lnrpc.AccountingEntry")
+	proto.RegisterType((*AccountingReport)(nil), "lnrpc.AccountingReport")
+	proto.RegisterType((*AbandonChannelResponse)(nil), "lnrpc.AbandonChannelResponse")
+	proto.RegisterType((*RotateIdentityRequest)(nil), "lnrpc.RotateIdentityRequest")
+	proto.RegisterType((*RotateIdentityResponse)(nil), "lnrpc.RotateIdentityResponse")
+	proto.RegisterType((*MineBlocksRequest)(nil), "lnrpc.MineBlocksRequest")
+	proto.RegisterType((*MineBlocksResponse)(nil), "lnrpc.MineBlocksResponse")
+	proto.RegisterType((*AdvanceTimeRequest)(nil), "lnrpc.AdvanceTimeRequest")
+	proto.RegisterType((*AdvanceTimeResponse)(nil), "lnrpc.AdvanceTimeResponse")
+	proto.RegisterType((*EstimateOpenChannelResponse)(nil), "lnrpc.EstimateOpenChannelResponse")
+	proto.RegisterType((*GetFundingPolicyRequest)(nil), "lnrpc.GetFundingPolicyRequest")
+	proto.RegisterType((*FundingPolicy)(nil), "lnrpc.FundingPolicy")
+	proto.RegisterType((*UpdateFundingPolicyResponse)(nil), "lnrpc.UpdateFundingPolicyResponse")
+	proto.RegisterType((*ListPaymentAttemptsRequest)(nil), "lnrpc.ListPaymentAttemptsRequest")
+	proto.RegisterType((*PaymentAttempt)(nil), "lnrpc.PaymentAttempt")
+	proto.RegisterType((*ListPaymentAttemptsResponse)(nil), "lnrpc.ListPaymentAttemptsResponse")
+	proto.RegisterType((*StateLogRequest)(nil), "lnrpc.StateLogRequest")
+	proto.RegisterType((*StateEvent)(nil), "lnrpc.StateEvent")
+	proto.RegisterType((*StateLogResponse)(nil), "lnrpc.StateLogResponse")
+	proto.RegisterType((*PeerSettings)(nil), "lnrpc.PeerSettings")
+	proto.RegisterType((*UpdatePeerSettingsResponse)(nil), "lnrpc.UpdatePeerSettingsResponse")
+	proto.RegisterType((*ListPeerSettingsRequest)(nil), "lnrpc.ListPeerSettingsRequest")
+	proto.RegisterType((*ListPeerSettingsResponse)(nil), "lnrpc.ListPeerSettingsResponse")
+	proto.RegisterType((*DeletePeerSettingsRequest)(nil), "lnrpc.DeletePeerSettingsRequest")
+	proto.RegisterType((*DeletePeerSettingsResponse)(nil), "lnrpc.DeletePeerSettingsResponse")
+	proto.RegisterType((*PaymentTelemetryRequest)(nil), "lnrpc.PaymentTelemetryRequest")
+	proto.RegisterType((*FailureCodeCount)(nil), "lnrpc.FailureCodeCount")
+	proto.RegisterType((*PaymentTelemetryResponse)(nil), "lnrpc.PaymentTelemetryResponse")
+	proto.RegisterType((*TowerInfoRequest)(nil), "lnrpc.TowerInfoRequest")
+	proto.RegisterType((*TowerInfoResponse)(nil), "lnrpc.TowerInfoResponse")
+	proto.RegisterType((*ChannelStatesRequest)(nil), "lnrpc.ChannelStatesRequest")
+	proto.RegisterType((*ChannelStateMachine)(nil), "lnrpc.ChannelStateMachine")
+	proto.RegisterType((*ChannelStatesResponse)(nil), "lnrpc.ChannelStatesResponse")
+	proto.RegisterType((*FreezeChannelResponse)(nil), "lnrpc.FreezeChannelResponse")
+	proto.RegisterType((*ThawChannelResponse)(nil), "lnrpc.ThawChannelResponse")
+	proto.RegisterType((*CreatePayReqRequest)(nil), "lnrpc.CreatePayReqRequest")
+	proto.RegisterType((*CreatePayReqResponse)(nil), "lnrpc.CreatePayReqResponse")
+	proto.RegisterType((*RevenueReportRequest)(nil), "lnrpc.RevenueReportRequest")
+	proto.RegisterType((*DailyRevenue)(nil), "lnrpc.DailyRevenue")
+	proto.RegisterType((*RevenueReportResponse)(nil), "lnrpc.RevenueReportResponse")
+	proto.RegisterType((*TopCounterpartiesRequest)(nil), "lnrpc.TopCounterpartiesRequest")
+	proto.RegisterType((*CounterpartyVolume)(nil), "lnrpc.CounterpartyVolume")
+	proto.RegisterType((*TopCounterpartiesResponse)(nil), "lnrpc.TopCounterpartiesResponse")
+	proto.RegisterType((*HopHint)(nil), "lnrpc.HopHint")
+	proto.RegisterType((*RouteHint)(nil), "lnrpc.RouteHint")
+	proto.RegisterType((*ConfirmIdentityRotationRequest)(nil), "lnrpc.ConfirmIdentityRotationRequest")
+	proto.RegisterType((*ConfirmIdentityRotationResponse)(nil), "lnrpc.ConfirmIdentityRotationResponse")
+	proto.RegisterType((*ImportChannelStateRequest)(nil), "lnrpc.ImportChannelStateRequest")
+	proto.RegisterEnum("lnrpc.ChannelStatus", ChannelStatus_name, ChannelStatus_value)
+	proto.RegisterEnum("lnrpc.NewAddressRequest_AddressType", NewAddressRequest_AddressType_name, NewAddressRequest_AddressType_value)
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for Lightning service
+
+type LightningClient interface {
+	WalletBalance(ctx context.Context, in *WalletBalanceRequest, opts ...grpc.CallOption) (*WalletBalanceResponse, error)
+	ChannelBalance(ctx context.Context, in *ChannelBalanceRequest, opts ...grpc.CallOption) (*ChannelBalanceResponse, error)
+	GetTransactions(ctx context.Context, in *GetTransactionsRequest, opts ...grpc.CallOption) (*TransactionDetails, error)
+	SendCoins(ctx context.Context, in *SendCoinsRequest, opts ...grpc.CallOption) (*SendCoinsResponse, error)
+	SubscribeTransactions(ctx context.Context, in *GetTransactionsRequest, opts ...grpc.CallOption) (Lightning_SubscribeTransactionsClient, error)
+	SendMany(ctx context.Context, in *SendManyRequest, opts ...grpc.CallOption) (*SendManyResponse, error)
+	NewAddress(ctx context.Context, in *NewAddressRequest, opts ...grpc.CallOption) (*NewAddressResponse, error)
+	NewWitnessAddress(ctx context.Context, in *NewWitnessAddressRequest, opts ...grpc.CallOption) (*NewAddressResponse, error)
+	ConnectPeer(ctx context.Context, in *ConnectPeerRequest, opts ...grpc.CallOption) (*ConnectPeerResponse, error)
+	ListPeers(ctx context.Context, in *ListPeersRequest, opts ...grpc.CallOption) (*ListPeersResponse, error)
+	GetInfo(ctx context.Context, in *GetInfoRequest, opts ...grpc.CallOption) (*GetInfoResponse, error)
+	// TODO(roasbeef): merge with below with bool?
+	PendingChannels(ctx context.Context, in *PendingChannelRequest, opts ...grpc.CallOption) (*PendingChannelResponse, error)
+	ListChannels(ctx context.Context, in *ListChannelsRequest, opts ...grpc.CallOption) (*ListChannelsResponse, error)
+	OpenChannelSync(ctx context.Context, in *OpenChannelRequest, opts ...grpc.CallOption) (*ChannelPoint, error)
+	OpenChannel(ctx context.Context, in *OpenChannelRequest, opts ...grpc.CallOption) (Lightning_OpenChannelClient, error)
+	CloseChannel(ctx context.Context, in *CloseChannelRequest, opts ...grpc.CallOption) (Lightning_CloseChannelClient, error)
+	ExportChanBackup(ctx context.Context, in *ExportChanBackupRequest, opts ...grpc.CallOption) (*ChanBackupSnapshot, error)
+	VerifyChanBackup(ctx context.Context, in *ChanBackupSnapshot, opts ...grpc.CallOption) (*VerifyChanBackupResponse, error)
+	RestoreChanBackup(ctx context.Context, in *RestoreChanBackupRequest, opts ...grpc.CallOption) (*RestoreChanBackupResponse, error)
+	ImportChannelRecovery(ctx context.Context, in *ImportChannelRecoveryRequest, opts ...grpc.CallOption) (*ImportChannelRecoveryResponse, error)
+	SendPayment(ctx context.Context, opts ...grpc.CallOption) (Lightning_SendPaymentClient, error)
+	SendPaymentSync(ctx context.Context, in *SendRequest, opts ...grpc.CallOption) (*SendResponse, error)
+	ListFeePresets(ctx context.Context, in *ListFeePresetsRequest, opts ...grpc.CallOption) (*ListFeePresetsResponse, error)
+	AddInvoice(ctx context.Context, in *Invoice, opts ...grpc.CallOption) (*AddInvoiceResponse, error)
+	ListInvoices(ctx context.Context, in *ListInvoiceRequest, opts ...grpc.CallOption) (*ListInvoiceResponse, error)
+	LookupInvoice(ctx context.Context, in *PaymentHash, opts ...grpc.CallOption) (*Invoice, error)
+	SubscribeInvoices(ctx context.Context, in *InvoiceSubscription, opts ...grpc.CallOption) (Lightning_SubscribeInvoicesClient, error)
+	AddOffer(ctx context.Context, in *Offer, opts ...grpc.CallOption) (*AddOfferResponse, error)
+	ListOffers(ctx context.Context, in *ListOffersRequest, opts ...grpc.CallOption) (*ListOffersResponse, error)
+	RequestOfferInvoice(ctx context.Context, in *OfferInvoiceRequest, opts ...grpc.CallOption) (*OfferInvoiceResponse, error)
+	DecodePayReq(ctx context.Context, in *PayReqString, opts ...grpc.CallOption) (*PayReq, error)
+	ListPayments(ctx context.Context, in *ListPaymentsRequest, opts ...grpc.CallOption) (*ListPaymentsResponse, error)
+	DeleteAllPayments(ctx context.Context, in *DeleteAllPaymentsRequest, opts ...grpc.CallOption) (*DeleteAllPaymentsResponse, error)
+	DescribeGraph(ctx context.Context, in *ChannelGraphRequest, opts ...grpc.CallOption) (*ChannelGraph, error)
+	GetChanInfo(ctx context.Context, in *ChanInfoRequest, opts ...grpc.CallOption) (*ChannelEdge, error)
+	GetNodeInfo(ctx context.Context, in *NodeInfoRequest, opts ...grpc.CallOption) (*NodeInfo, error)
+	QueryRoute(ctx context.Context, in *RouteRequest, opts ...grpc.CallOption) (*Route, error)
+	GetNetworkInfo(ctx context.Context, in *NetworkInfoRequest, opts ...grpc.CallOption) (*NetworkInfo, error)
+	SubscribeChannelGraph(ctx context.Context, in *GraphTopologySubscription, opts ...grpc.CallOption) (Lightning_SubscribeChannelGraphClient, error)
+	SetAlias(ctx context.Context, in *SetAliasRequest, opts ...grpc.CallOption) (*SetAliasResponse, error)
+	DebugLevel(ctx context.Context, in *DebugLevelRequest, opts ...grpc.CallOption) (*DebugLevelResponse, error)
+	ExportChannelState(ctx context.Context, in *ChannelPoint, opts ...grpc.CallOption) (*ChannelStateExport, error)
+	ChannelHistory(ctx context.Context, in *ChannelHistoryRequest, opts ...grpc.CallOption) (*ChannelHistoryResponse, error)
+	RegisterRPCMiddleware(ctx context.Context, opts ...grpc.CallOption) (Lightning_RegisterRPCMiddlewareClient, error)
+	ListRecommendations(ctx context.Context, in *ListRecommendationsRequest, opts ...grpc.CallOption) (*ListRecommendationsResponse, error)
+	ExecuteRecommendation(ctx context.Context, in *ExecuteRecommendationRequest, opts ...grpc.CallOption) (*ExecuteRecommendationResponse, error)
+	SubscribeOutbox(ctx context.Context, in *OutboxSubscription, opts ...grpc.CallOption) (Lightning_SubscribeOutboxClient, error)
+	AckOutboxNotification(ctx context.Context, in *OutboxAck, opts ...grpc.CallOption) (*OutboxAckResponse, error)
+	RemoveOutboxSubscriber(ctx context.Context, in *RemoveOutboxSubscriberRequest, opts ...grpc.CallOption) (*RemoveOutboxSubscriberResponse, error)
+	QueryMissionControl(ctx context.Context, in *QueryMissionControlRequest, opts ...grpc.CallOption) (*QueryMissionControlResponse, error)
+	ResetMissionControl(ctx context.Context, in *ResetMissionControlRequest, opts ...grpc.CallOption) (*ResetMissionControlResponse, error)
+	AckChannelDivergence(ctx context.Context, in *ChannelPoint, opts ...grpc.CallOption) (*AckChannelDivergenceResponse, error)
+	SubscribeCustomMessages(ctx context.Context, in *CustomMessageSubscription, opts ...grpc.CallOption) (Lightning_SubscribeCustomMessagesClient, error)
+	SendCustomMessage(ctx context.Context, in *SendCustomMessageRequest, opts ...grpc.CallOption) (*SendCustomMessageResponse, error)
+	ExportSigningAudit(ctx context.Context, in *SigningAuditRequest, opts ...grpc.CallOption) (*SigningAuditResponse, error)
+	ExportChannelPolicies(ctx context.Context, in *ExportChannelPoliciesRequest, opts ...grpc.CallOption) (*ChannelPolicies, error)
+	ImportChannelPolicies(ctx context.Context, in *ImportChannelPoliciesRequest, opts ...grpc.CallOption) (*ImportChannelPoliciesResponse, error)
+	BalanceHistory(ctx context.Context, in *BalanceHistoryRequest, opts ...grpc.CallOption) (*BalanceHistoryResponse, error)
+	ListUnresolvedHTLCs(ctx context.Context, in *ListUnresolvedHTLCsRequest, opts ...grpc.CallOption) (*ListUnresolvedHTLCsResponse, error)
+	UpdateCommitFee(ctx context.Context, in *UpdateCommitFeeRequest, opts ...grpc.CallOption) (*UpdateCommitFeeResponse, error)
+	SendBatchPayment(ctx context.Context, in *BatchPaymentRequest, opts ...grpc.CallOption) (Lightning_SendBatchPaymentClient, error)
+	ExportGossip(ctx context.Context, in *ExportGossipRequest, opts ...grpc.CallOption) (*GossipMessages, error)
+	ReplayGossip(ctx context.Context, in *GossipMessages, opts ...grpc.CallOption) (*ReplayGossipResponse, error)
+	SpliceIn(ctx context.Context, in *SpliceInRequest, opts ...grpc.CallOption) (*SpliceInResponse, error)
+	BackupUploadStatus(ctx context.Context, in *BackupUploadStatusRequest, opts ...grpc.CallOption) (*BackupUploadStatusResponse, error)
+	DBCommitStats(ctx context.Context, in *DBCommitStatsRequest, opts ...grpc.CallOption) (*DBCommitStatsResponse, error)
+	ExportAccounting(ctx context.Context, in *AccountingRequest, opts ...grpc.CallOption) (*AccountingReport, error)
+	AbandonChannel(ctx context.Context, in *ChannelPoint, opts ...grpc.CallOption) (*AbandonChannelResponse, error)
+	RotateIdentity(ctx context.Context, in *RotateIdentityRequest, opts ...grpc.CallOption) (*RotateIdentityResponse, error)
+	MineBlocks(ctx context.Context, in *MineBlocksRequest, opts ...grpc.CallOption) (*MineBlocksResponse, error)
+	AdvanceTime(ctx context.Context, in *AdvanceTimeRequest, opts ...grpc.CallOption) (*AdvanceTimeResponse, error)
+	EstimateOpenChannel(ctx context.Context, in *OpenChannelRequest, opts ...grpc.CallOption) (*EstimateOpenChannelResponse, error)
+	GetFundingPolicy(ctx context.Context, in *GetFundingPolicyRequest, opts ...grpc.CallOption) (*FundingPolicy, error)
+	UpdateFundingPolicy(ctx context.Context, in *FundingPolicy, opts ...grpc.CallOption) (*UpdateFundingPolicyResponse, error)
+	ListPaymentAttempts(ctx context.Context, in *ListPaymentAttemptsRequest, opts ...grpc.CallOption) (*ListPaymentAttemptsResponse, error)
+	StateLog(ctx context.Context, in *StateLogRequest, opts ...grpc.CallOption) (*StateLogResponse, error)
+	UpdatePeerSettings(ctx context.Context, in *PeerSettings, opts ...grpc.CallOption) (*UpdatePeerSettingsResponse, error)
+	ListPeerSettings(ctx context.Context, in *ListPeerSettingsRequest, opts ...grpc.CallOption) (*ListPeerSettingsResponse, error)
+	DeletePeerSettings(ctx context.Context, in *DeletePeerSettingsRequest, opts ...grpc.CallOption) (*DeletePeerSettingsResponse, error)
+	PaymentTelemetry(ctx context.Context, in *PaymentTelemetryRequest, opts ...grpc.CallOption) (*PaymentTelemetryResponse, error)
+	TowerInfo(ctx context.Context, in *TowerInfoRequest, opts ...grpc.CallOption) (*TowerInfoResponse, error)
+	ChannelStates(ctx context.Context, in *ChannelStatesRequest, opts ...grpc.CallOption) (*ChannelStatesResponse, error)
+	FreezeChannel(ctx context.Context, in *ChannelPoint, opts ...grpc.CallOption) (*FreezeChannelResponse, error)
+	ThawChannel(ctx context.Context, in *ChannelPoint, opts ...grpc.CallOption) (*ThawChannelResponse, error)
+	CreatePayReq(ctx context.Context, in *CreatePayReqRequest, opts ...grpc.CallOption) (*CreatePayReqResponse, error)
+	RevenueReport(ctx context.Context, in *RevenueReportRequest, opts ...grpc.CallOption) (*RevenueReportResponse, error)
+	TopCounterparties(ctx context.Context, in *TopCounterpartiesRequest, opts ...grpc.CallOption) (*TopCounterpartiesResponse, error)
+	ConfirmIdentityRotation(ctx context.Context, in *ConfirmIdentityRotationRequest, opts ...grpc.CallOption) (*ConfirmIdentityRotationResponse, error)
+	ImportChannelState(ctx context.Context, in *ImportChannelStateRequest, opts ...grpc.CallOption) (*ChannelPoint, error)
+}
+
+type lightningClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewLightningClient(cc *grpc.ClientConn) LightningClient {
+	return &lightningClient{cc}
+}
+
+func (c *lightningClient) WalletBalance(ctx context.Context, in *WalletBalanceRequest, opts ...grpc.CallOption) (*WalletBalanceResponse, error) {
+	out := new(WalletBalanceResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/WalletBalance", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ChannelBalance(ctx context.Context, in *ChannelBalanceRequest, opts ...grpc.CallOption) (*ChannelBalanceResponse, error) {
+	out := new(ChannelBalanceResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ChannelBalance", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) GetTransactions(ctx context.Context, in *GetTransactionsRequest, opts ...grpc.CallOption) (*TransactionDetails, error) {
+	out := new(TransactionDetails)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/GetTransactions", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) SendCoins(ctx context.Context, in *SendCoinsRequest, opts ...grpc.CallOption) (*SendCoinsResponse, error) {
+	out := new(SendCoinsResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/SendCoins", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) SubscribeTransactions(ctx context.Context, in *GetTransactionsRequest, opts ...grpc.CallOption) (Lightning_SubscribeTransactionsClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[0], c.cc, "/lnrpc.Lightning/SubscribeTransactions", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningSubscribeTransactionsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Lightning_SubscribeTransactionsClient interface {
+	Recv() (*Transaction, error)
+	grpc.ClientStream
+}
+
+type lightningSubscribeTransactionsClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningSubscribeTransactionsClient) Recv() (*Transaction, error) {
+	m := new(Transaction)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lightningClient) SendMany(ctx context.Context, in *SendManyRequest, opts ...grpc.CallOption) (*SendManyResponse, error) {
+	out := new(SendManyResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/SendMany", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) NewAddress(ctx context.Context, in *NewAddressRequest, opts ...grpc.CallOption) (*NewAddressResponse, error) {
+	out := new(NewAddressResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/NewAddress", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) NewWitnessAddress(ctx context.Context, in *NewWitnessAddressRequest, opts ...grpc.CallOption) (*NewAddressResponse, error) {
+	out := new(NewAddressResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/NewWitnessAddress", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ConnectPeer(ctx context.Context, in *ConnectPeerRequest, opts ...grpc.CallOption) (*ConnectPeerResponse, error) {
+	out := new(ConnectPeerResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ConnectPeer", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ListPeers(ctx context.Context, in *ListPeersRequest, opts ...grpc.CallOption) (*ListPeersResponse, error) {
+	out := new(ListPeersResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ListPeers", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) GetInfo(ctx context.Context, in *GetInfoRequest, opts ...grpc.CallOption) (*GetInfoResponse, error) {
+	out := new(GetInfoResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/GetInfo", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) PendingChannels(ctx context.Context, in *PendingChannelRequest, opts ...grpc.CallOption) (*PendingChannelResponse, error) {
+	out := new(PendingChannelResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/PendingChannels", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ListChannels(ctx context.Context, in *ListChannelsRequest, opts ...grpc.CallOption) (*ListChannelsResponse, error) {
+	out := new(ListChannelsResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ListChannels", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) OpenChannelSync(ctx context.Context, in *OpenChannelRequest, opts ...grpc.CallOption) (*ChannelPoint, error) {
+	out := new(ChannelPoint)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/OpenChannelSync", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) OpenChannel(ctx context.Context, in *OpenChannelRequest, opts ...grpc.CallOption) (Lightning_OpenChannelClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[1], c.cc, "/lnrpc.Lightning/OpenChannel", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningOpenChannelClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Lightning_OpenChannelClient interface {
+	Recv() (*OpenStatusUpdate, error)
+	grpc.ClientStream
+}
+
+type lightningOpenChannelClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningOpenChannelClient) Recv() (*OpenStatusUpdate, error) {
+	m := new(OpenStatusUpdate)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lightningClient) CloseChannel(ctx context.Context, in *CloseChannelRequest, opts ...grpc.CallOption) (Lightning_CloseChannelClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[2], c.cc, "/lnrpc.Lightning/CloseChannel", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningCloseChannelClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Lightning_CloseChannelClient interface {
+	Recv() (*CloseStatusUpdate, error)
+	grpc.ClientStream
+}
+
+type lightningCloseChannelClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningCloseChannelClient) Recv() (*CloseStatusUpdate, error) {
+	m := new(CloseStatusUpdate)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lightningClient) ExportChanBackup(ctx context.Context, in *ExportChanBackupRequest, opts ...grpc.CallOption) (*ChanBackupSnapshot, error) {
+	out := new(ChanBackupSnapshot)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ExportChanBackup", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) VerifyChanBackup(ctx context.Context, in *ChanBackupSnapshot, opts ...grpc.CallOption) (*VerifyChanBackupResponse, error) {
+	out := new(VerifyChanBackupResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/VerifyChanBackup", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) RestoreChanBackup(ctx context.Context, in *RestoreChanBackupRequest, opts ...grpc.CallOption) (*RestoreChanBackupResponse, error) {
+	out := new(RestoreChanBackupResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/RestoreChanBackup", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ImportChannelRecovery(ctx context.Context, in *ImportChannelRecoveryRequest, opts ...grpc.CallOption) (*ImportChannelRecoveryResponse, error) {
+	out := new(ImportChannelRecoveryResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ImportChannelRecovery", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) SendPayment(ctx context.Context, opts ...grpc.CallOption) (Lightning_SendPaymentClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[3], c.cc, "/lnrpc.Lightning/SendPayment", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningSendPaymentClient{stream}
+	return x, nil
+}
+
+type Lightning_SendPaymentClient interface {
+	Send(*SendRequest) error
+	Recv() (*SendResponse, error)
+	grpc.ClientStream
+}
+
+type lightningSendPaymentClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningSendPaymentClient) Send(m *SendRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *lightningSendPaymentClient) Recv() (*SendResponse, error) {
+	m := new(SendResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lightningClient) SendPaymentSync(ctx context.Context, in *SendRequest, opts ...grpc.CallOption) (*SendResponse, error) {
+	out := new(SendResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/SendPaymentSync", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ListFeePresets(ctx context.Context, in *ListFeePresetsRequest, opts ...grpc.CallOption) (*ListFeePresetsResponse, error) {
+	out := new(ListFeePresetsResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ListFeePresets", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) AddInvoice(ctx context.Context, in *Invoice, opts ...grpc.CallOption) (*AddInvoiceResponse, error) {
 	out := new(AddInvoiceResponse)
 	err := grpc.Invoke(ctx, "/lnrpc.Lightning/AddInvoice", in, out, c.cc, opts...)
 	if err != nil {
 		return nil, err
 	}
-	return out, nil
+	return out, nil
+}
+
+func (c *lightningClient) ListInvoices(ctx context.Context, in *ListInvoiceRequest, opts ...grpc.CallOption) (*ListInvoiceResponse, error) {
+	out := new(ListInvoiceResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ListInvoices", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) LookupInvoice(ctx context.Context, in *PaymentHash, opts ...grpc.CallOption) (*Invoice, error) {
+	out := new(Invoice)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/LookupInvoice", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) SubscribeInvoices(ctx context.Context, in *InvoiceSubscription, opts ...grpc.CallOption) (Lightning_SubscribeInvoicesClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[4], c.cc, "/lnrpc.Lightning/SubscribeInvoices", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningSubscribeInvoicesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Lightning_SubscribeInvoicesClient interface {
+	Recv() (*Invoice, error)
+	grpc.ClientStream
+}
+
+type lightningSubscribeInvoicesClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningSubscribeInvoicesClient) Recv() (*Invoice, error) {
+	m := new(Invoice)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lightningClient) AddOffer(ctx context.Context, in *Offer, opts ...grpc.CallOption) (*AddOfferResponse, error) {
+	out := new(AddOfferResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/AddOffer", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ListOffers(ctx context.Context, in *ListOffersRequest, opts ...grpc.CallOption) (*ListOffersResponse, error) {
+	out := new(ListOffersResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ListOffers", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) RequestOfferInvoice(ctx context.Context, in *OfferInvoiceRequest, opts ...grpc.CallOption) (*OfferInvoiceResponse, error) {
+	out := new(OfferInvoiceResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/RequestOfferInvoice", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) DecodePayReq(ctx context.Context, in *PayReqString, opts ...grpc.CallOption) (*PayReq, error) {
+	out := new(PayReq)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/DecodePayReq", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ListPayments(ctx context.Context, in *ListPaymentsRequest, opts ...grpc.CallOption) (*ListPaymentsResponse, error) {
+	out := new(ListPaymentsResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ListPayments", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) DeleteAllPayments(ctx context.Context, in *DeleteAllPaymentsRequest, opts ...grpc.CallOption) (*DeleteAllPaymentsResponse, error) {
+	out := new(DeleteAllPaymentsResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/DeleteAllPayments", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) DescribeGraph(ctx context.Context, in *ChannelGraphRequest, opts ...grpc.CallOption) (*ChannelGraph, error) {
+	out := new(ChannelGraph)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/DescribeGraph", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) GetChanInfo(ctx context.Context, in *ChanInfoRequest, opts ...grpc.CallOption) (*ChannelEdge, error) {
+	out := new(ChannelEdge)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/GetChanInfo", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) GetNodeInfo(ctx context.Context, in *NodeInfoRequest, opts ...grpc.CallOption) (*NodeInfo, error) {
+	out := new(NodeInfo)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/GetNodeInfo", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) QueryRoute(ctx context.Context, in *RouteRequest, opts ...grpc.CallOption) (*Route, error) {
+	out := new(Route)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/QueryRoute", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) GetNetworkInfo(ctx context.Context, in *NetworkInfoRequest, opts ...grpc.CallOption) (*NetworkInfo, error) {
+	out := new(NetworkInfo)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/GetNetworkInfo", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) SubscribeChannelGraph(ctx context.Context, in *GraphTopologySubscription, opts ...grpc.CallOption) (Lightning_SubscribeChannelGraphClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[5], c.cc, "/lnrpc.Lightning/SubscribeChannelGraph", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningSubscribeChannelGraphClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Lightning_SubscribeChannelGraphClient interface {
+	Recv() (*GraphTopologyUpdate, error)
+	grpc.ClientStream
+}
+
+type lightningSubscribeChannelGraphClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningSubscribeChannelGraphClient) Recv() (*GraphTopologyUpdate, error) {
+	m := new(GraphTopologyUpdate)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lightningClient) SetAlias(ctx context.Context, in *SetAliasRequest, opts ...grpc.CallOption) (*SetAliasResponse, error) {
+	out := new(SetAliasResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/SetAlias", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) DebugLevel(ctx context.Context, in *DebugLevelRequest, opts ...grpc.CallOption) (*DebugLevelResponse, error) {
+	out := new(DebugLevelResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/DebugLevel", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ExportChannelState(ctx context.Context, in *ChannelPoint, opts ...grpc.CallOption) (*ChannelStateExport, error) {
+	out := new(ChannelStateExport)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ExportChannelState", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ChannelHistory(ctx context.Context, in *ChannelHistoryRequest, opts ...grpc.CallOption) (*ChannelHistoryResponse, error) {
+	out := new(ChannelHistoryResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ChannelHistory", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) RegisterRPCMiddleware(ctx context.Context, opts ...grpc.CallOption) (Lightning_RegisterRPCMiddlewareClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[6], c.cc, "/lnrpc.Lightning/RegisterRPCMiddleware", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningRegisterRPCMiddlewareClient{stream}
+	return x, nil
+}
+
+type Lightning_RegisterRPCMiddlewareClient interface {
+	Send(*RPCMiddlewareResponse) error
+	Recv() (*RPCMiddlewareRequest, error)
+	grpc.ClientStream
+}
+
+type lightningRegisterRPCMiddlewareClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningRegisterRPCMiddlewareClient) Send(m *RPCMiddlewareResponse) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *lightningRegisterRPCMiddlewareClient) Recv() (*RPCMiddlewareRequest, error) {
+	m := new(RPCMiddlewareRequest)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lightningClient) ListRecommendations(ctx context.Context, in *ListRecommendationsRequest, opts ...grpc.CallOption) (*ListRecommendationsResponse, error) {
+	out := new(ListRecommendationsResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ListRecommendations", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ExecuteRecommendation(ctx context.Context, in *ExecuteRecommendationRequest, opts ...grpc.CallOption) (*ExecuteRecommendationResponse, error) {
+	out := new(ExecuteRecommendationResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ExecuteRecommendation", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) SubscribeOutbox(ctx context.Context, in *OutboxSubscription, opts ...grpc.CallOption) (Lightning_SubscribeOutboxClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[7], c.cc, "/lnrpc.Lightning/SubscribeOutbox", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningSubscribeOutboxClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Lightning_SubscribeOutboxClient interface {
+	Recv() (*OutboxNotification, error)
+	grpc.ClientStream
+}
+
+type lightningSubscribeOutboxClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningSubscribeOutboxClient) Recv() (*OutboxNotification, error) {
+	m := new(OutboxNotification)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lightningClient) AckOutboxNotification(ctx context.Context, in *OutboxAck, opts ...grpc.CallOption) (*OutboxAckResponse, error) {
+	out := new(OutboxAckResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/AckOutboxNotification", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) RemoveOutboxSubscriber(ctx context.Context, in *RemoveOutboxSubscriberRequest, opts ...grpc.CallOption) (*RemoveOutboxSubscriberResponse, error) {
+	out := new(RemoveOutboxSubscriberResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/RemoveOutboxSubscriber", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) QueryMissionControl(ctx context.Context, in *QueryMissionControlRequest, opts ...grpc.CallOption) (*QueryMissionControlResponse, error) {
+	out := new(QueryMissionControlResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/QueryMissionControl", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ResetMissionControl(ctx context.Context, in *ResetMissionControlRequest, opts ...grpc.CallOption) (*ResetMissionControlResponse, error) {
+	out := new(ResetMissionControlResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ResetMissionControl", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) AckChannelDivergence(ctx context.Context, in *ChannelPoint, opts ...grpc.CallOption) (*AckChannelDivergenceResponse, error) {
+	out := new(AckChannelDivergenceResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/AckChannelDivergence", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) SubscribeCustomMessages(ctx context.Context, in *CustomMessageSubscription, opts ...grpc.CallOption) (Lightning_SubscribeCustomMessagesClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[8], c.cc, "/lnrpc.Lightning/SubscribeCustomMessages", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningSubscribeCustomMessagesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Lightning_SubscribeCustomMessagesClient interface {
+	Recv() (*CustomMessage, error)
+	grpc.ClientStream
+}
+
+type lightningSubscribeCustomMessagesClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningSubscribeCustomMessagesClient) Recv() (*CustomMessage, error) {
+	m := new(CustomMessage)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lightningClient) SendCustomMessage(ctx context.Context, in *SendCustomMessageRequest, opts ...grpc.CallOption) (*SendCustomMessageResponse, error) {
+	out := new(SendCustomMessageResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/SendCustomMessage", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ExportSigningAudit(ctx context.Context, in *SigningAuditRequest, opts ...grpc.CallOption) (*SigningAuditResponse, error) {
+	out := new(SigningAuditResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ExportSigningAudit", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ExportChannelPolicies(ctx context.Context, in *ExportChannelPoliciesRequest, opts ...grpc.CallOption) (*ChannelPolicies, error) {
+	out := new(ChannelPolicies)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ExportChannelPolicies", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ImportChannelPolicies(ctx context.Context, in *ImportChannelPoliciesRequest, opts ...grpc.CallOption) (*ImportChannelPoliciesResponse, error) {
+	out := new(ImportChannelPoliciesResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ImportChannelPolicies", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) BalanceHistory(ctx context.Context, in *BalanceHistoryRequest, opts ...grpc.CallOption) (*BalanceHistoryResponse, error) {
+	out := new(BalanceHistoryResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/BalanceHistory", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ListUnresolvedHTLCs(ctx context.Context, in *ListUnresolvedHTLCsRequest, opts ...grpc.CallOption) (*ListUnresolvedHTLCsResponse, error) {
+	out := new(ListUnresolvedHTLCsResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ListUnresolvedHTLCs", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) UpdateCommitFee(ctx context.Context, in *UpdateCommitFeeRequest, opts ...grpc.CallOption) (*UpdateCommitFeeResponse, error) {
+	out := new(UpdateCommitFeeResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/UpdateCommitFee", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) SendBatchPayment(ctx context.Context, in *BatchPaymentRequest, opts ...grpc.CallOption) (Lightning_SendBatchPaymentClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[9], c.cc, "/lnrpc.Lightning/SendBatchPayment", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &lightningSendBatchPaymentClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Lightning_SendBatchPaymentClient interface {
+	Recv() (*BatchPaymentResult, error)
+	grpc.ClientStream
+}
+
+type lightningSendBatchPaymentClient struct {
+	grpc.ClientStream
+}
+
+func (x *lightningSendBatchPaymentClient) Recv() (*BatchPaymentResult, error) {
+	m := new(BatchPaymentResult)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *lightningClient) ExportGossip(ctx context.Context, in *ExportGossipRequest, opts ...grpc.CallOption) (*GossipMessages, error) {
+	out := new(GossipMessages)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ExportGossip", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ReplayGossip(ctx context.Context, in *GossipMessages, opts ...grpc.CallOption) (*ReplayGossipResponse, error) {
+	out := new(ReplayGossipResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ReplayGossip", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) SpliceIn(ctx context.Context, in *SpliceInRequest, opts ...grpc.CallOption) (*SpliceInResponse, error) {
+	out := new(SpliceInResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/SpliceIn", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) BackupUploadStatus(ctx context.Context, in *BackupUploadStatusRequest, opts ...grpc.CallOption) (*BackupUploadStatusResponse, error) {
+	out := new(BackupUploadStatusResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/BackupUploadStatus", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) DBCommitStats(ctx context.Context, in *DBCommitStatsRequest, opts ...grpc.CallOption) (*DBCommitStatsResponse, error) {
+	out := new(DBCommitStatsResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/DBCommitStats", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ExportAccounting(ctx context.Context, in *AccountingRequest, opts ...grpc.CallOption) (*AccountingReport, error) {
+	out := new(AccountingReport)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ExportAccounting", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) AbandonChannel(ctx context.Context, in *ChannelPoint, opts ...grpc.CallOption) (*AbandonChannelResponse, error) {
+	out := new(AbandonChannelResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/AbandonChannel", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) RotateIdentity(ctx context.Context, in *RotateIdentityRequest, opts ...grpc.CallOption) (*RotateIdentityResponse, error) {
+	out := new(RotateIdentityResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/RotateIdentity", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) MineBlocks(ctx context.Context, in *MineBlocksRequest, opts ...grpc.CallOption) (*MineBlocksResponse, error) {
+	out := new(MineBlocksResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/MineBlocks", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) AdvanceTime(ctx context.Context, in *AdvanceTimeRequest, opts ...grpc.CallOption) (*AdvanceTimeResponse, error) {
+	out := new(AdvanceTimeResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/AdvanceTime", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) EstimateOpenChannel(ctx context.Context, in *OpenChannelRequest, opts ...grpc.CallOption) (*EstimateOpenChannelResponse, error) {
+	out := new(EstimateOpenChannelResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/EstimateOpenChannel", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) GetFundingPolicy(ctx context.Context, in *GetFundingPolicyRequest, opts ...grpc.CallOption) (*FundingPolicy, error) {
+	out := new(FundingPolicy)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/GetFundingPolicy", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) UpdateFundingPolicy(ctx context.Context, in *FundingPolicy, opts ...grpc.CallOption) (*UpdateFundingPolicyResponse, error) {
+	out := new(UpdateFundingPolicyResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/UpdateFundingPolicy", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ListPaymentAttempts(ctx context.Context, in *ListPaymentAttemptsRequest, opts ...grpc.CallOption) (*ListPaymentAttemptsResponse, error) {
+	out := new(ListPaymentAttemptsResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ListPaymentAttempts", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) StateLog(ctx context.Context, in *StateLogRequest, opts ...grpc.CallOption) (*StateLogResponse, error) {
+	out := new(StateLogResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/StateLog", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) UpdatePeerSettings(ctx context.Context, in *PeerSettings, opts ...grpc.CallOption) (*UpdatePeerSettingsResponse, error) {
+	out := new(UpdatePeerSettingsResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/UpdatePeerSettings", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ListPeerSettings(ctx context.Context, in *ListPeerSettingsRequest, opts ...grpc.CallOption) (*ListPeerSettingsResponse, error) {
+	out := new(ListPeerSettingsResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ListPeerSettings", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) DeletePeerSettings(ctx context.Context, in *DeletePeerSettingsRequest, opts ...grpc.CallOption) (*DeletePeerSettingsResponse, error) {
+	out := new(DeletePeerSettingsResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/DeletePeerSettings", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) PaymentTelemetry(ctx context.Context, in *PaymentTelemetryRequest, opts ...grpc.CallOption) (*PaymentTelemetryResponse, error) {
+	out := new(PaymentTelemetryResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/PaymentTelemetry", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) TowerInfo(ctx context.Context, in *TowerInfoRequest, opts ...grpc.CallOption) (*TowerInfoResponse, error) {
+	out := new(TowerInfoResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/TowerInfo", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ChannelStates(ctx context.Context, in *ChannelStatesRequest, opts ...grpc.CallOption) (*ChannelStatesResponse, error) {
+	out := new(ChannelStatesResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ChannelStates", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) FreezeChannel(ctx context.Context, in *ChannelPoint, opts ...grpc.CallOption) (*FreezeChannelResponse, error) {
+	out := new(FreezeChannelResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/FreezeChannel", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ThawChannel(ctx context.Context, in *ChannelPoint, opts ...grpc.CallOption) (*ThawChannelResponse, error) {
+	out := new(ThawChannelResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ThawChannel", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) CreatePayReq(ctx context.Context, in *CreatePayReqRequest, opts ...grpc.CallOption) (*CreatePayReqResponse, error) {
+	out := new(CreatePayReqResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/CreatePayReq", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) RevenueReport(ctx context.Context, in *RevenueReportRequest, opts ...grpc.CallOption) (*RevenueReportResponse, error) {
+	out := new(RevenueReportResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/RevenueReport", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) TopCounterparties(ctx context.Context, in *TopCounterpartiesRequest, opts ...grpc.CallOption) (*TopCounterpartiesResponse, error) {
+	out := new(TopCounterpartiesResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/TopCounterparties", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ConfirmIdentityRotation(ctx context.Context, in *ConfirmIdentityRotationRequest, opts ...grpc.CallOption) (*ConfirmIdentityRotationResponse, error) {
+	out := new(ConfirmIdentityRotationResponse)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ConfirmIdentityRotation", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *lightningClient) ImportChannelState(ctx context.Context, in *ImportChannelStateRequest, opts ...grpc.CallOption) (*ChannelPoint, error) {
+	out := new(ChannelPoint)
+	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ImportChannelState", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for Lightning service
+
+type LightningServer interface {
+	WalletBalance(context.Context, *WalletBalanceRequest) (*WalletBalanceResponse, error)
+	ChannelBalance(context.Context, *ChannelBalanceRequest) (*ChannelBalanceResponse, error)
+	GetTransactions(context.Context, *GetTransactionsRequest) (*TransactionDetails, error)
+	SendCoins(context.Context, *SendCoinsRequest) (*SendCoinsResponse, error)
+	SubscribeTransactions(*GetTransactionsRequest, Lightning_SubscribeTransactionsServer) error
+	SendMany(context.Context, *SendManyRequest) (*SendManyResponse, error)
+	NewAddress(context.Context, *NewAddressRequest) (*NewAddressResponse, error)
+	NewWitnessAddress(context.Context, *NewWitnessAddressRequest) (*NewAddressResponse, error)
+	ConnectPeer(context.Context, *ConnectPeerRequest) (*ConnectPeerResponse, error)
+	ListPeers(context.Context, *ListPeersRequest) (*ListPeersResponse, error)
+	GetInfo(context.Context, *GetInfoRequest) (*GetInfoResponse, error)
+	// TODO(roasbeef): merge with below with bool?
+	PendingChannels(context.Context, *PendingChannelRequest) (*PendingChannelResponse, error)
+	ListChannels(context.Context, *ListChannelsRequest) (*ListChannelsResponse, error)
+	OpenChannelSync(context.Context, *OpenChannelRequest) (*ChannelPoint, error)
+	OpenChannel(*OpenChannelRequest, Lightning_OpenChannelServer) error
+	CloseChannel(*CloseChannelRequest, Lightning_CloseChannelServer) error
+	ExportChanBackup(context.Context, *ExportChanBackupRequest) (*ChanBackupSnapshot, error)
+	VerifyChanBackup(context.Context, *ChanBackupSnapshot) (*VerifyChanBackupResponse, error)
+	RestoreChanBackup(context.Context, *RestoreChanBackupRequest) (*RestoreChanBackupResponse, error)
+	ImportChannelRecovery(context.Context, *ImportChannelRecoveryRequest) (*ImportChannelRecoveryResponse, error)
+	SendPayment(Lightning_SendPaymentServer) error
+	SendPaymentSync(context.Context, *SendRequest) (*SendResponse, error)
+	ListFeePresets(context.Context, *ListFeePresetsRequest) (*ListFeePresetsResponse, error)
+	AddInvoice(context.Context, *Invoice) (*AddInvoiceResponse, error)
+	ListInvoices(context.Context, *ListInvoiceRequest) (*ListInvoiceResponse, error)
+	LookupInvoice(context.Context, *PaymentHash) (*Invoice, error)
+	SubscribeInvoices(*InvoiceSubscription, Lightning_SubscribeInvoicesServer) error
+	AddOffer(context.Context, *Offer) (*AddOfferResponse, error)
+	ListOffers(context.Context, *ListOffersRequest) (*ListOffersResponse, error)
+	RequestOfferInvoice(context.Context, *OfferInvoiceRequest) (*OfferInvoiceResponse, error)
+	DecodePayReq(context.Context, *PayReqString) (*PayReq, error)
+	ListPayments(context.Context, *ListPaymentsRequest) (*ListPaymentsResponse, error)
+	DeleteAllPayments(context.Context, *DeleteAllPaymentsRequest) (*DeleteAllPaymentsResponse, error)
+	DescribeGraph(context.Context, *ChannelGraphRequest) (*ChannelGraph, error)
+	GetChanInfo(context.Context, *ChanInfoRequest) (*ChannelEdge, error)
+	GetNodeInfo(context.Context, *NodeInfoRequest) (*NodeInfo, error)
+	QueryRoute(context.Context, *RouteRequest) (*Route, error)
+	GetNetworkInfo(context.Context, *NetworkInfoRequest) (*NetworkInfo, error)
+	SubscribeChannelGraph(*GraphTopologySubscription, Lightning_SubscribeChannelGraphServer) error
+	SetAlias(context.Context, *SetAliasRequest) (*SetAliasResponse, error)
+	DebugLevel(context.Context, *DebugLevelRequest) (*DebugLevelResponse, error)
+	ExportChannelState(context.Context, *ChannelPoint) (*ChannelStateExport, error)
+	ChannelHistory(context.Context, *ChannelHistoryRequest) (*ChannelHistoryResponse, error)
+	RegisterRPCMiddleware(Lightning_RegisterRPCMiddlewareServer) error
+	ListRecommendations(context.Context, *ListRecommendationsRequest) (*ListRecommendationsResponse, error)
+	ExecuteRecommendation(context.Context, *ExecuteRecommendationRequest) (*ExecuteRecommendationResponse, error)
+	SubscribeOutbox(*OutboxSubscription, Lightning_SubscribeOutboxServer) error
+	AckOutboxNotification(context.Context, *OutboxAck) (*OutboxAckResponse, error)
+	RemoveOutboxSubscriber(context.Context, *RemoveOutboxSubscriberRequest) (*RemoveOutboxSubscriberResponse, error)
+	QueryMissionControl(context.Context, *QueryMissionControlRequest) (*QueryMissionControlResponse, error)
+	ResetMissionControl(context.Context, *ResetMissionControlRequest) (*ResetMissionControlResponse, error)
+	AckChannelDivergence(context.Context, *ChannelPoint) (*AckChannelDivergenceResponse, error)
+	SubscribeCustomMessages(*CustomMessageSubscription, Lightning_SubscribeCustomMessagesServer) error
+	SendCustomMessage(context.Context, *SendCustomMessageRequest) (*SendCustomMessageResponse, error)
+	ExportSigningAudit(context.Context, *SigningAuditRequest) (*SigningAuditResponse, error)
+	ExportChannelPolicies(context.Context, *ExportChannelPoliciesRequest) (*ChannelPolicies, error)
+	ImportChannelPolicies(context.Context, *ImportChannelPoliciesRequest) (*ImportChannelPoliciesResponse, error)
+	BalanceHistory(context.Context, *BalanceHistoryRequest) (*BalanceHistoryResponse, error)
+	ListUnresolvedHTLCs(context.Context, *ListUnresolvedHTLCsRequest) (*ListUnresolvedHTLCsResponse, error)
+	UpdateCommitFee(context.Context, *UpdateCommitFeeRequest) (*UpdateCommitFeeResponse, error)
+	SendBatchPayment(*BatchPaymentRequest, Lightning_SendBatchPaymentServer) error
+	ExportGossip(context.Context, *ExportGossipRequest) (*GossipMessages, error)
+	ReplayGossip(context.Context, *GossipMessages) (*ReplayGossipResponse, error)
+	SpliceIn(context.Context, *SpliceInRequest) (*SpliceInResponse, error)
+	BackupUploadStatus(context.Context, *BackupUploadStatusRequest) (*BackupUploadStatusResponse, error)
+	DBCommitStats(context.Context, *DBCommitStatsRequest) (*DBCommitStatsResponse, error)
+	ExportAccounting(context.Context, *AccountingRequest) (*AccountingReport, error)
+	AbandonChannel(context.Context, *ChannelPoint) (*AbandonChannelResponse, error)
+	RotateIdentity(context.Context, *RotateIdentityRequest) (*RotateIdentityResponse, error)
+	MineBlocks(context.Context, *MineBlocksRequest) (*MineBlocksResponse, error)
+	AdvanceTime(context.Context, *AdvanceTimeRequest) (*AdvanceTimeResponse, error)
+	EstimateOpenChannel(context.Context, *OpenChannelRequest) (*EstimateOpenChannelResponse, error)
+	GetFundingPolicy(context.Context, *GetFundingPolicyRequest) (*FundingPolicy, error)
+	UpdateFundingPolicy(context.Context, *FundingPolicy) (*UpdateFundingPolicyResponse, error)
+	ListPaymentAttempts(context.Context, *ListPaymentAttemptsRequest) (*ListPaymentAttemptsResponse, error)
+	StateLog(context.Context, *StateLogRequest) (*StateLogResponse, error)
+	UpdatePeerSettings(context.Context, *PeerSettings) (*UpdatePeerSettingsResponse, error)
+	ListPeerSettings(context.Context, *ListPeerSettingsRequest) (*ListPeerSettingsResponse, error)
+	DeletePeerSettings(context.Context, *DeletePeerSettingsRequest) (*DeletePeerSettingsResponse, error)
+	PaymentTelemetry(context.Context, *PaymentTelemetryRequest) (*PaymentTelemetryResponse, error)
+	TowerInfo(context.Context, *TowerInfoRequest) (*TowerInfoResponse, error)
+	ChannelStates(context.Context, *ChannelStatesRequest) (*ChannelStatesResponse, error)
+	FreezeChannel(context.Context, *ChannelPoint) (*FreezeChannelResponse, error)
+	ThawChannel(context.Context, *ChannelPoint) (*ThawChannelResponse, error)
+	CreatePayReq(context.Context, *CreatePayReqRequest) (*CreatePayReqResponse, error)
+	RevenueReport(context.Context, *RevenueReportRequest) (*RevenueReportResponse, error)
+	TopCounterparties(context.Context, *TopCounterpartiesRequest) (*TopCounterpartiesResponse, error)
+	ConfirmIdentityRotation(context.Context, *ConfirmIdentityRotationRequest) (*ConfirmIdentityRotationResponse, error)
+	ImportChannelState(context.Context, *ImportChannelStateRequest) (*ChannelPoint, error)
+}
+
+func RegisterLightningServer(s *grpc.Server, srv LightningServer) {
+	s.RegisterService(&_Lightning_serviceDesc, srv)
+}
+
+func _Lightning_WalletBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WalletBalanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).WalletBalance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/WalletBalance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).WalletBalance(ctx, req.(*WalletBalanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_ChannelBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChannelBalanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ChannelBalance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ChannelBalance",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ChannelBalance(ctx, req.(*ChannelBalanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_GetTransactions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTransactionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).GetTransactions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/GetTransactions",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).GetTransactions(ctx, req.(*GetTransactionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_SendCoins_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendCoinsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).SendCoins(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/SendCoins",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).SendCoins(ctx, req.(*SendCoinsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_SubscribeTransactions_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GetTransactionsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LightningServer).SubscribeTransactions(m, &lightningSubscribeTransactionsServer{stream})
+}
+
+type Lightning_SubscribeTransactionsServer interface {
+	Send(*Transaction) error
+	grpc.ServerStream
+}
+
+type lightningSubscribeTransactionsServer struct {
+	grpc.ServerStream
+}
+
+func (x *lightningSubscribeTransactionsServer) Send(m *Transaction) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Lightning_SendMany_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendManyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).SendMany(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/SendMany",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).SendMany(ctx, req.(*SendManyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_NewAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NewAddressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).NewAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/NewAddress",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).NewAddress(ctx, req.(*NewAddressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_NewWitnessAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NewWitnessAddressRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).NewWitnessAddress(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/NewWitnessAddress",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).NewWitnessAddress(ctx, req.(*NewWitnessAddressRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_ConnectPeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConnectPeerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ConnectPeer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ConnectPeer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ConnectPeer(ctx, req.(*ConnectPeerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_ListPeers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPeersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ListPeers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ListPeers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ListPeers(ctx, req.(*ListPeersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_GetInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).GetInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/GetInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).GetInfo(ctx, req.(*GetInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_PendingChannels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PendingChannelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).PendingChannels(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/PendingChannels",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).PendingChannels(ctx, req.(*PendingChannelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_ListChannels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListChannelsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ListChannels(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ListChannels",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ListChannels(ctx, req.(*ListChannelsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_OpenChannelSync_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OpenChannelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).OpenChannelSync(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/OpenChannelSync",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).OpenChannelSync(ctx, req.(*OpenChannelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_OpenChannel_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(OpenChannelRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LightningServer).OpenChannel(m, &lightningOpenChannelServer{stream})
+}
+
+type Lightning_OpenChannelServer interface {
+	Send(*OpenStatusUpdate) error
+	grpc.ServerStream
+}
+
+type lightningOpenChannelServer struct {
+	grpc.ServerStream
+}
+
+func (x *lightningOpenChannelServer) Send(m *OpenStatusUpdate) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Lightning_CloseChannel_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(CloseChannelRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LightningServer).CloseChannel(m, &lightningCloseChannelServer{stream})
+}
+
+type Lightning_CloseChannelServer interface {
+	Send(*CloseStatusUpdate) error
+	grpc.ServerStream
+}
+
+type lightningCloseChannelServer struct {
+	grpc.ServerStream
+}
+
+func (x *lightningCloseChannelServer) Send(m *CloseStatusUpdate) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Lightning_ExportChanBackup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportChanBackupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ExportChanBackup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ExportChanBackup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ExportChanBackup(ctx, req.(*ExportChanBackupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_VerifyChanBackup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChanBackupSnapshot)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).VerifyChanBackup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/VerifyChanBackup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).VerifyChanBackup(ctx, req.(*ChanBackupSnapshot))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_RestoreChanBackup_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreChanBackupRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).RestoreChanBackup(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/RestoreChanBackup",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).RestoreChanBackup(ctx, req.(*RestoreChanBackupRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_ImportChannelRecovery_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportChannelRecoveryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ImportChannelRecovery(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ImportChannelRecovery",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ImportChannelRecovery(ctx, req.(*ImportChannelRecoveryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_SendPayment_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(LightningServer).SendPayment(&lightningSendPaymentServer{stream})
+}
+
+type Lightning_SendPaymentServer interface {
+	Send(*SendResponse) error
+	Recv() (*SendRequest, error)
+	grpc.ServerStream
+}
+
+type lightningSendPaymentServer struct {
+	grpc.ServerStream
+}
+
+func (x *lightningSendPaymentServer) Send(m *SendResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *lightningSendPaymentServer) Recv() (*SendRequest, error) {
+	m := new(SendRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Lightning_SendPaymentSync_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).SendPaymentSync(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/SendPaymentSync",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).SendPaymentSync(ctx, req.(*SendRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_ListFeePresets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFeePresetsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ListFeePresets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ListFeePresets",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ListFeePresets(ctx, req.(*ListFeePresetsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_AddInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Invoice)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).AddInvoice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/AddInvoice",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).AddInvoice(ctx, req.(*Invoice))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_ListInvoices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListInvoiceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ListInvoices(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ListInvoices",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ListInvoices(ctx, req.(*ListInvoiceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_LookupInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PaymentHash)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).LookupInvoice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/LookupInvoice",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).LookupInvoice(ctx, req.(*PaymentHash))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_SubscribeInvoices_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(InvoiceSubscription)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LightningServer).SubscribeInvoices(m, &lightningSubscribeInvoicesServer{stream})
+}
+
+type Lightning_SubscribeInvoicesServer interface {
+	Send(*Invoice) error
+	grpc.ServerStream
+}
+
+type lightningSubscribeInvoicesServer struct {
+	grpc.ServerStream
+}
+
+func (x *lightningSubscribeInvoicesServer) Send(m *Invoice) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Lightning_AddOffer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Offer)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).AddOffer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/AddOffer",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).AddOffer(ctx, req.(*Offer))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_ListOffers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOffersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ListOffers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ListOffers",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ListOffers(ctx, req.(*ListOffersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_RequestOfferInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OfferInvoiceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).RequestOfferInvoice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/RequestOfferInvoice",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).RequestOfferInvoice(ctx, req.(*OfferInvoiceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_DecodePayReq_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PayReqString)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).DecodePayReq(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/DecodePayReq",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).DecodePayReq(ctx, req.(*PayReqString))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_ListPayments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPaymentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ListPayments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ListPayments",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ListPayments(ctx, req.(*ListPaymentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_DeleteAllPayments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteAllPaymentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).DeleteAllPayments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/DeleteAllPayments",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).DeleteAllPayments(ctx, req.(*DeleteAllPaymentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_DescribeGraph_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChannelGraphRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).DescribeGraph(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/DescribeGraph",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).DescribeGraph(ctx, req.(*ChannelGraphRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_GetChanInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChanInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).GetChanInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/GetChanInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).GetChanInfo(ctx, req.(*ChanInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_GetNodeInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NodeInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).GetNodeInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/GetNodeInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).GetNodeInfo(ctx, req.(*NodeInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_QueryRoute_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RouteRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).QueryRoute(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/QueryRoute",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).QueryRoute(ctx, req.(*RouteRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *lightningClient) ListInvoices(ctx context.Context, in *ListInvoiceRequest, opts ...grpc.CallOption) (*ListInvoiceResponse, error) {
-	out := new(ListInvoiceResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ListInvoices", in, out, c.cc, opts...)
-	if err != nil {
+func _Lightning_GetNetworkInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(NetworkInfoRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(LightningServer).GetNetworkInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/GetNetworkInfo",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).GetNetworkInfo(ctx, req.(*NetworkInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *lightningClient) LookupInvoice(ctx context.Context, in *PaymentHash, opts ...grpc.CallOption) (*Invoice, error) {
-	out := new(Invoice)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/LookupInvoice", in, out, c.cc, opts...)
-	if err != nil {
+func _Lightning_SubscribeChannelGraph_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(GraphTopologySubscription)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LightningServer).SubscribeChannelGraph(m, &lightningSubscribeChannelGraphServer{stream})
+}
+
+type Lightning_SubscribeChannelGraphServer interface {
+	Send(*GraphTopologyUpdate) error
+	grpc.ServerStream
+}
+
+type lightningSubscribeChannelGraphServer struct {
+	grpc.ServerStream
+}
+
+func (x *lightningSubscribeChannelGraphServer) Send(m *GraphTopologyUpdate) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Lightning_SetAlias_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetAliasRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(LightningServer).SetAlias(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/SetAlias",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).SetAlias(ctx, req.(*SetAliasRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *lightningClient) SubscribeInvoices(ctx context.Context, in *InvoiceSubscription, opts ...grpc.CallOption) (Lightning_SubscribeInvoicesClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[4], c.cc, "/lnrpc.Lightning/SubscribeInvoices", opts...)
-	if err != nil {
+func _Lightning_DebugLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DebugLevelRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	x := &lightningSubscribeInvoicesClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
+	if interceptor == nil {
+		return srv.(LightningServer).DebugLevel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/DebugLevel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).DebugLevel(ctx, req.(*DebugLevelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_ExportChannelState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChannelPoint)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	if err := x.ClientStream.CloseSend(); err != nil {
+	if interceptor == nil {
+		return srv.(LightningServer).ExportChannelState(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ExportChannelState",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ExportChannelState(ctx, req.(*ChannelPoint))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_ChannelHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChannelHistoryRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return x, nil
+	if interceptor == nil {
+		return srv.(LightningServer).ChannelHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ChannelHistory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ChannelHistory(ctx, req.(*ChannelHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type Lightning_SubscribeInvoicesClient interface {
-	Recv() (*Invoice, error)
-	grpc.ClientStream
+func _Lightning_RegisterRPCMiddleware_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(LightningServer).RegisterRPCMiddleware(&lightningRegisterRPCMiddlewareServer{stream})
 }
 
-type lightningSubscribeInvoicesClient struct {
-	grpc.ClientStream
+type Lightning_RegisterRPCMiddlewareServer interface {
+	Send(*RPCMiddlewareRequest) error
+	Recv() (*RPCMiddlewareResponse, error)
+	grpc.ServerStream
 }
 
-func (x *lightningSubscribeInvoicesClient) Recv() (*Invoice, error) {
-	m := new(Invoice)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
+type lightningRegisterRPCMiddlewareServer struct {
+	grpc.ServerStream
+}
+
+func (x *lightningRegisterRPCMiddlewareServer) Send(m *RPCMiddlewareRequest) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *lightningRegisterRPCMiddlewareServer) Recv() (*RPCMiddlewareResponse, error) {
+	m := new(RPCMiddlewareResponse)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
 		return nil, err
 	}
 	return m, nil
 }
 
-func (c *lightningClient) DecodePayReq(ctx context.Context, in *PayReqString, opts ...grpc.CallOption) (*PayReq, error) {
-	out := new(PayReq)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/DecodePayReq", in, out, c.cc, opts...)
-	if err != nil {
+func _Lightning_ListRecommendations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListRecommendationsRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(LightningServer).ListRecommendations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ListRecommendations",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ListRecommendations(ctx, req.(*ListRecommendationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *lightningClient) ListPayments(ctx context.Context, in *ListPaymentsRequest, opts ...grpc.CallOption) (*ListPaymentsResponse, error) {
-	out := new(ListPaymentsResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/ListPayments", in, out, c.cc, opts...)
-	if err != nil {
+func _Lightning_ExecuteRecommendation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExecuteRecommendationRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(LightningServer).ExecuteRecommendation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ExecuteRecommendation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ExecuteRecommendation(ctx, req.(*ExecuteRecommendationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *lightningClient) DeleteAllPayments(ctx context.Context, in *DeleteAllPaymentsRequest, opts ...grpc.CallOption) (*DeleteAllPaymentsResponse, error) {
-	out := new(DeleteAllPaymentsResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/DeleteAllPayments", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
+func _Lightning_SubscribeOutbox_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(OutboxSubscription)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
 	}
-	return out, nil
+	return srv.(LightningServer).SubscribeOutbox(m, &lightningSubscribeOutboxServer{stream})
 }
 
-func (c *lightningClient) DescribeGraph(ctx context.Context, in *ChannelGraphRequest, opts ...grpc.CallOption) (*ChannelGraph, error) {
-	out := new(ChannelGraph)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/DescribeGraph", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+type Lightning_SubscribeOutboxServer interface {
+	Send(*OutboxNotification) error
+	grpc.ServerStream
 }
 
-func (c *lightningClient) GetChanInfo(ctx context.Context, in *ChanInfoRequest, opts ...grpc.CallOption) (*ChannelEdge, error) {
-	out := new(ChannelEdge)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/GetChanInfo", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+type lightningSubscribeOutboxServer struct {
+	grpc.ServerStream
 }
 
-func (c *lightningClient) GetNodeInfo(ctx context.Context, in *NodeInfoRequest, opts ...grpc.CallOption) (*NodeInfo, error) {
-	out := new(NodeInfo)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/GetNodeInfo", in, out, c.cc, opts...)
-	if err != nil {
+func (x *lightningSubscribeOutboxServer) Send(m *OutboxNotification) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Lightning_AckOutboxNotification_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OutboxAck)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(LightningServer).AckOutboxNotification(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/AckOutboxNotification",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).AckOutboxNotification(ctx, req.(*OutboxAck))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *lightningClient) QueryRoute(ctx context.Context, in *RouteRequest, opts ...grpc.CallOption) (*Route, error) {
-	out := new(Route)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/QueryRoute", in, out, c.cc, opts...)
-	if err != nil {
+func _Lightning_RemoveOutboxSubscriber_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveOutboxSubscriberRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(LightningServer).RemoveOutboxSubscriber(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/RemoveOutboxSubscriber",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).RemoveOutboxSubscriber(ctx, req.(*RemoveOutboxSubscriberRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *lightningClient) GetNetworkInfo(ctx context.Context, in *NetworkInfoRequest, opts ...grpc.CallOption) (*NetworkInfo, error) {
-	out := new(NetworkInfo)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/GetNetworkInfo", in, out, c.cc, opts...)
-	if err != nil {
+func _Lightning_QueryMissionControl_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryMissionControlRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(LightningServer).QueryMissionControl(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/QueryMissionControl",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).QueryMissionControl(ctx, req.(*QueryMissionControlRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (c *lightningClient) SubscribeChannelGraph(ctx context.Context, in *GraphTopologySubscription, opts ...grpc.CallOption) (Lightning_SubscribeChannelGraphClient, error) {
-	stream, err := grpc.NewClientStream(ctx, &_Lightning_serviceDesc.Streams[5], c.cc, "/lnrpc.Lightning/SubscribeChannelGraph", opts...)
-	if err != nil {
+func _Lightning_ResetMissionControl_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResetMissionControlRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	x := &lightningSubscribeChannelGraphClient{stream}
-	if err := x.ClientStream.SendMsg(in); err != nil {
+	if interceptor == nil {
+		return srv.(LightningServer).ResetMissionControl(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ResetMissionControl",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ResetMissionControl(ctx, req.(*ResetMissionControlRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Lightning_AckChannelDivergence_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChannelPoint)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	if err := x.ClientStream.CloseSend(); err != nil {
-		return nil, err
+	if interceptor == nil {
+		return srv.(LightningServer).AckChannelDivergence(ctx, in)
 	}
-	return x, nil
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/AckChannelDivergence",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).AckChannelDivergence(ctx, req.(*ChannelPoint))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-type Lightning_SubscribeChannelGraphClient interface {
-	Recv() (*GraphTopologyUpdate, error)
-	grpc.ClientStream
+func _Lightning_SubscribeCustomMessages_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(CustomMessageSubscription)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LightningServer).SubscribeCustomMessages(m, &lightningSubscribeCustomMessagesServer{stream})
 }
 
-type lightningSubscribeChannelGraphClient struct {
-	grpc.ClientStream
+type Lightning_SubscribeCustomMessagesServer interface {
+	Send(*CustomMessage) error
+	grpc.ServerStream
 }
 
-func (x *lightningSubscribeChannelGraphClient) Recv() (*GraphTopologyUpdate, error) {
-	m := new(GraphTopologyUpdate)
-	if err := x.ClientStream.RecvMsg(m); err != nil {
-		return nil, err
-	}
-	return m, nil
+type lightningSubscribeCustomMessagesServer struct {
+	grpc.ServerStream
 }
 
-func (c *lightningClient) SetAlias(ctx context.Context, in *SetAliasRequest, opts ...grpc.CallOption) (*SetAliasResponse, error) {
-	out := new(SetAliasResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/SetAlias", in, out, c.cc, opts...)
-	if err != nil {
-		return nil, err
-	}
-	return out, nil
+func (x *lightningSubscribeCustomMessagesServer) Send(m *CustomMessage) error {
+	return x.ServerStream.SendMsg(m)
 }
 
-func (c *lightningClient) DebugLevel(ctx context.Context, in *DebugLevelRequest, opts ...grpc.CallOption) (*DebugLevelResponse, error) {
-	out := new(DebugLevelResponse)
-	err := grpc.Invoke(ctx, "/lnrpc.Lightning/DebugLevel", in, out, c.cc, opts...)
-	if err != nil {
+func _Lightning_SendCustomMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SendCustomMessageRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return out, nil
+	if interceptor == nil {
+		return srv.(LightningServer).SendCustomMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/SendCustomMessage",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).SendCustomMessage(ctx, req.(*SendCustomMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-// Server API for Lightning service
-
-type LightningServer interface {
-	WalletBalance(context.Context, *WalletBalanceRequest) (*WalletBalanceResponse, error)
-	ChannelBalance(context.Context, *ChannelBalanceRequest) (*ChannelBalanceResponse, error)
-	GetTransactions(context.Context, *GetTransactionsRequest) (*TransactionDetails, error)
-	SendCoins(context.Context, *SendCoinsRequest) (*SendCoinsResponse, error)
-	SubscribeTransactions(*GetTransactionsRequest, Lightning_SubscribeTransactionsServer) error
-	SendMany(context.Context, *SendManyRequest) (*SendManyResponse, error)
-	NewAddress(context.Context, *NewAddressRequest) (*NewAddressResponse, error)
-	NewWitnessAddress(context.Context, *NewWitnessAddressRequest) (*NewAddressResponse, error)
-	ConnectPeer(context.Context, *ConnectPeerRequest) (*ConnectPeerResponse, error)
-	ListPeers(context.Context, *ListPeersRequest) (*ListPeersResponse, error)
-	GetInfo(context.Context, *GetInfoRequest) (*GetInfoResponse, error)
-	// TODO(roasbeef): merge with below with bool?
-	PendingChannels(context.Context, *PendingChannelRequest) (*PendingChannelResponse, error)
-	ListChannels(context.Context, *ListChannelsRequest) (*ListChannelsResponse, error)
-	OpenChannelSync(context.Context, *OpenChannelRequest) (*ChannelPoint, error)
-	OpenChannel(*OpenChannelRequest, Lightning_OpenChannelServer) error
-	CloseChannel(*CloseChannelRequest, Lightning_CloseChannelServer) error
-	SendPayment(Lightning_SendPaymentServer) error
-	SendPaymentSync(context.Context, *SendRequest) (*SendResponse, error)
-	AddInvoice(context.Context, *Invoice) (*AddInvoiceResponse, error)
-	ListInvoices(context.Context, *ListInvoiceRequest) (*ListInvoiceResponse, error)
-	LookupInvoice(context.Context, *PaymentHash) (*Invoice, error)
-	SubscribeInvoices(*InvoiceSubscription, Lightning_SubscribeInvoicesServer) error
-	DecodePayReq(context.Context, *PayReqString) (*PayReq, error)
-	ListPayments(context.Context, *ListPaymentsRequest) (*ListPaymentsResponse, error)
-	DeleteAllPayments(context.Context, *DeleteAllPaymentsRequest) (*DeleteAllPaymentsResponse, error)
-	DescribeGraph(context.Context, *ChannelGraphRequest) (*ChannelGraph, error)
-	GetChanInfo(context.Context, *ChanInfoRequest) (*ChannelEdge, error)
-	GetNodeInfo(context.Context, *NodeInfoRequest) (*NodeInfo, error)
-	QueryRoute(context.Context, *RouteRequest) (*Route, error)
-	GetNetworkInfo(context.Context, *NetworkInfoRequest) (*NetworkInfo, error)
-	SubscribeChannelGraph(*GraphTopologySubscription, Lightning_SubscribeChannelGraphServer) error
-	SetAlias(context.Context, *SetAliasRequest) (*SetAliasResponse, error)
-	DebugLevel(context.Context, *DebugLevelRequest) (*DebugLevelResponse, error)
+func _Lightning_ExportSigningAudit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SigningAuditRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ExportSigningAudit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ExportSigningAudit",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ExportSigningAudit(ctx, req.(*SigningAuditRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func RegisterLightningServer(s *grpc.Server, srv LightningServer) {
-	s.RegisterService(&_Lightning_serviceDesc, srv)
+func _Lightning_ExportChannelPolicies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportChannelPoliciesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).ExportChannelPolicies(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ExportChannelPolicies",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ExportChannelPolicies(ctx, req.(*ExportChannelPoliciesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_WalletBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(WalletBalanceRequest)
+func _Lightning_ImportChannelPolicies_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportChannelPoliciesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).WalletBalance(ctx, in)
+		return srv.(LightningServer).ImportChannelPolicies(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/WalletBalance",
+		FullMethod: "/lnrpc.Lightning/ImportChannelPolicies",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).WalletBalance(ctx, req.(*WalletBalanceRequest))
+		return srv.(LightningServer).ImportChannelPolicies(ctx, req.(*ImportChannelPoliciesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_ChannelBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ChannelBalanceRequest)
+func _Lightning_BalanceHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BalanceHistoryRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).ChannelBalance(ctx, in)
+		return srv.(LightningServer).BalanceHistory(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/ChannelBalance",
+		FullMethod: "/lnrpc.Lightning/BalanceHistory",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).ChannelBalance(ctx, req.(*ChannelBalanceRequest))
+		return srv.(LightningServer).BalanceHistory(ctx, req.(*BalanceHistoryRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_GetTransactions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetTransactionsRequest)
+func _Lightning_ListUnresolvedHTLCs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListUnresolvedHTLCsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).GetTransactions(ctx, in)
+		return srv.(LightningServer).ListUnresolvedHTLCs(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/GetTransactions",
+		FullMethod: "/lnrpc.Lightning/ListUnresolvedHTLCs",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).GetTransactions(ctx, req.(*GetTransactionsRequest))
+		return srv.(LightningServer).ListUnresolvedHTLCs(ctx, req.(*ListUnresolvedHTLCsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_SendCoins_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SendCoinsRequest)
+func _Lightning_UpdateCommitFee_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateCommitFeeRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).SendCoins(ctx, in)
+		return srv.(LightningServer).UpdateCommitFee(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/SendCoins",
+		FullMethod: "/lnrpc.Lightning/UpdateCommitFee",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).SendCoins(ctx, req.(*SendCoinsRequest))
+		return srv.(LightningServer).UpdateCommitFee(ctx, req.(*UpdateCommitFeeRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_SubscribeTransactions_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(GetTransactionsRequest)
+func _Lightning_SendBatchPayment_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(BatchPaymentRequest)
 	if err := stream.RecvMsg(m); err != nil {
 		return err
 	}
-	return srv.(LightningServer).SubscribeTransactions(m, &lightningSubscribeTransactionsServer{stream})
+	return srv.(LightningServer).SendBatchPayment(m, &lightningSendBatchPaymentServer{stream})
 }
 
-type Lightning_SubscribeTransactionsServer interface {
-	Send(*Transaction) error
+type Lightning_SendBatchPaymentServer interface {
+	Send(*BatchPaymentResult) error
 	grpc.ServerStream
 }
 
-type lightningSubscribeTransactionsServer struct {
+type lightningSendBatchPaymentServer struct {
 	grpc.ServerStream
 }
 
-func (x *lightningSubscribeTransactionsServer) Send(m *Transaction) error {
+func (x *lightningSendBatchPaymentServer) Send(m *BatchPaymentResult) error {
 	return x.ServerStream.SendMsg(m)
 }
 
-func _Lightning_SendMany_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SendManyRequest)
+func _Lightning_ExportGossip_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportGossipRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).SendMany(ctx, in)
+		return srv.(LightningServer).ExportGossip(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/SendMany",
+		FullMethod: "/lnrpc.Lightning/ExportGossip",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).SendMany(ctx, req.(*SendManyRequest))
+		return srv.(LightningServer).ExportGossip(ctx, req.(*ExportGossipRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_NewAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(NewAddressRequest)
+func _Lightning_ReplayGossip_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GossipMessages)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).NewAddress(ctx, in)
+		return srv.(LightningServer).ReplayGossip(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/NewAddress",
+		FullMethod: "/lnrpc.Lightning/ReplayGossip",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).NewAddress(ctx, req.(*NewAddressRequest))
+		return srv.(LightningServer).ReplayGossip(ctx, req.(*GossipMessages))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_NewWitnessAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(NewWitnessAddressRequest)
+func _Lightning_SpliceIn_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SpliceInRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).NewWitnessAddress(ctx, in)
+		return srv.(LightningServer).SpliceIn(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/NewWitnessAddress",
+		FullMethod: "/lnrpc.Lightning/SpliceIn",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).NewWitnessAddress(ctx, req.(*NewWitnessAddressRequest))
+		return srv.(LightningServer).SpliceIn(ctx, req.(*SpliceInRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_ConnectPeer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ConnectPeerRequest)
+func _Lightning_BackupUploadStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(BackupUploadStatusRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).ConnectPeer(ctx, in)
+		return srv.(LightningServer).BackupUploadStatus(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/ConnectPeer",
+		FullMethod: "/lnrpc.Lightning/BackupUploadStatus",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).ConnectPeer(ctx, req.(*ConnectPeerRequest))
+		return srv.(LightningServer).BackupUploadStatus(ctx, req.(*BackupUploadStatusRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_ListPeers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListPeersRequest)
+func _Lightning_DBCommitStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DBCommitStatsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).ListPeers(ctx, in)
+		return srv.(LightningServer).DBCommitStats(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/ListPeers",
+		FullMethod: "/lnrpc.Lightning/DBCommitStats",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).ListPeers(ctx, req.(*ListPeersRequest))
+		return srv.(LightningServer).DBCommitStats(ctx, req.(*DBCommitStatsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_GetInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetInfoRequest)
+func _Lightning_ExportAccounting_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccountingRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).GetInfo(ctx, in)
+		return srv.(LightningServer).ExportAccounting(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/GetInfo",
+		FullMethod: "/lnrpc.Lightning/ExportAccounting",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).GetInfo(ctx, req.(*GetInfoRequest))
+		return srv.(LightningServer).ExportAccounting(ctx, req.(*AccountingRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_PendingChannels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(PendingChannelRequest)
+func _Lightning_AbandonChannel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChannelPoint)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).PendingChannels(ctx, in)
+		return srv.(LightningServer).AbandonChannel(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/PendingChannels",
+		FullMethod: "/lnrpc.Lightning/AbandonChannel",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).PendingChannels(ctx, req.(*PendingChannelRequest))
+		return srv.(LightningServer).AbandonChannel(ctx, req.(*ChannelPoint))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_ListChannels_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListChannelsRequest)
+func _Lightning_RotateIdentity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RotateIdentityRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).ListChannels(ctx, in)
+		return srv.(LightningServer).RotateIdentity(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/ListChannels",
+		FullMethod: "/lnrpc.Lightning/RotateIdentity",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).ListChannels(ctx, req.(*ListChannelsRequest))
+		return srv.(LightningServer).RotateIdentity(ctx, req.(*RotateIdentityRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_OpenChannelSync_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(OpenChannelRequest)
+func _Lightning_MineBlocks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MineBlocksRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).OpenChannelSync(ctx, in)
+		return srv.(LightningServer).MineBlocks(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/OpenChannelSync",
+		FullMethod: "/lnrpc.Lightning/MineBlocks",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).OpenChannelSync(ctx, req.(*OpenChannelRequest))
+		return srv.(LightningServer).MineBlocks(ctx, req.(*MineBlocksRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_OpenChannel_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(OpenChannelRequest)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+func _Lightning_AdvanceTime_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdvanceTimeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	return srv.(LightningServer).OpenChannel(m, &lightningOpenChannelServer{stream})
-}
-
-type Lightning_OpenChannelServer interface {
-	Send(*OpenStatusUpdate) error
-	grpc.ServerStream
-}
-
-type lightningOpenChannelServer struct {
-	grpc.ServerStream
-}
-
-func (x *lightningOpenChannelServer) Send(m *OpenStatusUpdate) error {
-	return x.ServerStream.SendMsg(m)
-}
-
-func _Lightning_CloseChannel_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(CloseChannelRequest)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+	if interceptor == nil {
+		return srv.(LightningServer).AdvanceTime(ctx, in)
 	}
-	return srv.(LightningServer).CloseChannel(m, &lightningCloseChannelServer{stream})
-}
-
-type Lightning_CloseChannelServer interface {
-	Send(*CloseStatusUpdate) error
-	grpc.ServerStream
-}
-
-type lightningCloseChannelServer struct {
-	grpc.ServerStream
-}
-
-func (x *lightningCloseChannelServer) Send(m *CloseStatusUpdate) error {
-	return x.ServerStream.SendMsg(m)
-}
-
-func _Lightning_SendPayment_Handler(srv interface{}, stream grpc.ServerStream) error {
-	return srv.(LightningServer).SendPayment(&lightningSendPaymentServer{stream})
-}
-
-type Lightning_SendPaymentServer interface {
-	Send(*SendResponse) error
-	Recv() (*SendRequest, error)
-	grpc.ServerStream
-}
-
-type lightningSendPaymentServer struct {
-	grpc.ServerStream
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/AdvanceTime",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).AdvanceTime(ctx, req.(*AdvanceTimeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (x *lightningSendPaymentServer) Send(m *SendResponse) error {
-	return x.ServerStream.SendMsg(m)
+func _Lightning_EstimateOpenChannel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OpenChannelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LightningServer).EstimateOpenChannel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/EstimateOpenChannel",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).EstimateOpenChannel(ctx, req.(*OpenChannelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func (x *lightningSendPaymentServer) Recv() (*SendRequest, error) {
-	m := new(SendRequest)
-	if err := x.ServerStream.RecvMsg(m); err != nil {
+func _Lightning_GetFundingPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFundingPolicyRequest)
+	if err := dec(in); err != nil {
 		return nil, err
 	}
-	return m, nil
+	if interceptor == nil {
+		return srv.(LightningServer).GetFundingPolicy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/GetFundingPolicy",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).GetFundingPolicy(ctx, req.(*GetFundingPolicyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_SendPaymentSync_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SendRequest)
+func _Lightning_UpdateFundingPolicy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FundingPolicy)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).SendPaymentSync(ctx, in)
+		return srv.(LightningServer).UpdateFundingPolicy(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/SendPaymentSync",
+		FullMethod: "/lnrpc.Lightning/UpdateFundingPolicy",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).SendPaymentSync(ctx, req.(*SendRequest))
+		return srv.(LightningServer).UpdateFundingPolicy(ctx, req.(*FundingPolicy))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_AddInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Invoice)
+func _Lightning_ListPaymentAttempts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPaymentAttemptsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).AddInvoice(ctx, in)
+		return srv.(LightningServer).ListPaymentAttempts(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/AddInvoice",
+		FullMethod: "/lnrpc.Lightning/ListPaymentAttempts",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).AddInvoice(ctx, req.(*Invoice))
+		return srv.(LightningServer).ListPaymentAttempts(ctx, req.(*ListPaymentAttemptsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_ListInvoices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListInvoiceRequest)
+func _Lightning_StateLog_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StateLogRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).ListInvoices(ctx, in)
+		return srv.(LightningServer).StateLog(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/ListInvoices",
+		FullMethod: "/lnrpc.Lightning/StateLog",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).ListInvoices(ctx, req.(*ListInvoiceRequest))
+		return srv.(LightningServer).StateLog(ctx, req.(*StateLogRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_LookupInvoice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(PaymentHash)
+func _Lightning_UpdatePeerSettings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PeerSettings)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).LookupInvoice(ctx, in)
+		return srv.(LightningServer).UpdatePeerSettings(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/LookupInvoice",
+		FullMethod: "/lnrpc.Lightning/UpdatePeerSettings",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).LookupInvoice(ctx, req.(*PaymentHash))
+		return srv.(LightningServer).UpdatePeerSettings(ctx, req.(*PeerSettings))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_SubscribeInvoices_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(InvoiceSubscription)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+func _Lightning_ListPeerSettings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPeerSettingsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	return srv.(LightningServer).SubscribeInvoices(m, &lightningSubscribeInvoicesServer{stream})
-}
-
-type Lightning_SubscribeInvoicesServer interface {
-	Send(*Invoice) error
-	grpc.ServerStream
-}
-
-type lightningSubscribeInvoicesServer struct {
-	grpc.ServerStream
-}
-
-func (x *lightningSubscribeInvoicesServer) Send(m *Invoice) error {
-	return x.ServerStream.SendMsg(m)
+	if interceptor == nil {
+		return srv.(LightningServer).ListPeerSettings(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/ListPeerSettings",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).ListPeerSettings(ctx, req.(*ListPeerSettingsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_DecodePayReq_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(PayReqString)
+func _Lightning_DeletePeerSettings_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeletePeerSettingsRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).DecodePayReq(ctx, in)
+		return srv.(LightningServer).DeletePeerSettings(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/DecodePayReq",
+		FullMethod: "/lnrpc.Lightning/DeletePeerSettings",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).DecodePayReq(ctx, req.(*PayReqString))
+		return srv.(LightningServer).DeletePeerSettings(ctx, req.(*DeletePeerSettingsRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_ListPayments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListPaymentsRequest)
+func _Lightning_PaymentTelemetry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PaymentTelemetryRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).ListPayments(ctx, in)
+		return srv.(LightningServer).PaymentTelemetry(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/ListPayments",
+		FullMethod: "/lnrpc.Lightning/PaymentTelemetry",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).ListPayments(ctx, req.(*ListPaymentsRequest))
+		return srv.(LightningServer).PaymentTelemetry(ctx, req.(*PaymentTelemetryRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_DeleteAllPayments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DeleteAllPaymentsRequest)
+func _Lightning_TowerInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TowerInfoRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).DeleteAllPayments(ctx, in)
+		return srv.(LightningServer).TowerInfo(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/DeleteAllPayments",
+		FullMethod: "/lnrpc.Lightning/TowerInfo",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).DeleteAllPayments(ctx, req.(*DeleteAllPaymentsRequest))
+		return srv.(LightningServer).TowerInfo(ctx, req.(*TowerInfoRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_DescribeGraph_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ChannelGraphRequest)
+func _Lightning_ChannelStates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChannelStatesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).DescribeGraph(ctx, in)
+		return srv.(LightningServer).ChannelStates(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/DescribeGraph",
+		FullMethod: "/lnrpc.Lightning/ChannelStates",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).DescribeGraph(ctx, req.(*ChannelGraphRequest))
+		return srv.(LightningServer).ChannelStates(ctx, req.(*ChannelStatesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_GetChanInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ChanInfoRequest)
+func _Lightning_FreezeChannel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChannelPoint)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).GetChanInfo(ctx, in)
+		return srv.(LightningServer).FreezeChannel(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/GetChanInfo",
+		FullMethod: "/lnrpc.Lightning/FreezeChannel",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).GetChanInfo(ctx, req.(*ChanInfoRequest))
+		return srv.(LightningServer).FreezeChannel(ctx, req.(*ChannelPoint))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_GetNodeInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(NodeInfoRequest)
+func _Lightning_ThawChannel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChannelPoint)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).GetNodeInfo(ctx, in)
+		return srv.(LightningServer).ThawChannel(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/GetNodeInfo",
+		FullMethod: "/lnrpc.Lightning/ThawChannel",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).GetNodeInfo(ctx, req.(*NodeInfoRequest))
+		return srv.(LightningServer).ThawChannel(ctx, req.(*ChannelPoint))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_QueryRoute_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(RouteRequest)
+func _Lightning_CreatePayReq_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreatePayReqRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).QueryRoute(ctx, in)
+		return srv.(LightningServer).CreatePayReq(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/QueryRoute",
+		FullMethod: "/lnrpc.Lightning/CreatePayReq",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).QueryRoute(ctx, req.(*RouteRequest))
+		return srv.(LightningServer).CreatePayReq(ctx, req.(*CreatePayReqRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_GetNetworkInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(NetworkInfoRequest)
+func _Lightning_RevenueReport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevenueReportRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).GetNetworkInfo(ctx, in)
+		return srv.(LightningServer).RevenueReport(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/GetNetworkInfo",
+		FullMethod: "/lnrpc.Lightning/RevenueReport",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).GetNetworkInfo(ctx, req.(*NetworkInfoRequest))
+		return srv.(LightningServer).RevenueReport(ctx, req.(*RevenueReportRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_SubscribeChannelGraph_Handler(srv interface{}, stream grpc.ServerStream) error {
-	m := new(GraphTopologySubscription)
-	if err := stream.RecvMsg(m); err != nil {
-		return err
+func _Lightning_TopCounterparties_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TopCounterpartiesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
 	}
-	return srv.(LightningServer).SubscribeChannelGraph(m, &lightningSubscribeChannelGraphServer{stream})
-}
-
-type Lightning_SubscribeChannelGraphServer interface {
-	Send(*GraphTopologyUpdate) error
-	grpc.ServerStream
-}
-
-type lightningSubscribeChannelGraphServer struct {
-	grpc.ServerStream
-}
-
-func (x *lightningSubscribeChannelGraphServer) Send(m *GraphTopologyUpdate) error {
-	return x.ServerStream.SendMsg(m)
+	if interceptor == nil {
+		return srv.(LightningServer).TopCounterparties(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/lnrpc.Lightning/TopCounterparties",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LightningServer).TopCounterparties(ctx, req.(*TopCounterpartiesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_SetAlias_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(SetAliasRequest)
+func _Lightning_ConfirmIdentityRotation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConfirmIdentityRotationRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).SetAlias(ctx, in)
+		return srv.(LightningServer).ConfirmIdentityRotation(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/SetAlias",
+		FullMethod: "/lnrpc.Lightning/ConfirmIdentityRotation",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).SetAlias(ctx, req.(*SetAliasRequest))
+		return srv.(LightningServer).ConfirmIdentityRotation(ctx, req.(*ConfirmIdentityRotationRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _Lightning_DebugLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DebugLevelRequest)
+func _Lightning_ImportChannelState_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportChannelStateRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(LightningServer).DebugLevel(ctx, in)
+		return srv.(LightningServer).ImportChannelState(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: "/lnrpc.Lightning/DebugLevel",
+		FullMethod: "/lnrpc.Lightning/ImportChannelState",
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(LightningServer).DebugLevel(ctx, req.(*DebugLevelRequest))
+		return srv.(LightningServer).ImportChannelState(ctx, req.(*ImportChannelStateRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -3813,10 +9468,30 @@ var _Lightning_serviceDesc = grpc.ServiceDesc{
 			MethodName: "OpenChannelSync",
 			Handler:    _Lightning_OpenChannelSync_Handler,
 		},
+		{
+			MethodName: "ExportChanBackup",
+			Handler:    _Lightning_ExportChanBackup_Handler,
+		},
+		{
+			MethodName: "VerifyChanBackup",
+			Handler:    _Lightning_VerifyChanBackup_Handler,
+		},
+		{
+			MethodName: "RestoreChanBackup",
+			Handler:    _Lightning_RestoreChanBackup_Handler,
+		},
+		{
+			MethodName: "ImportChannelRecovery",
+			Handler:    _Lightning_ImportChannelRecovery_Handler,
+		},
 		{
 			MethodName: "SendPaymentSync",
 			Handler:    _Lightning_SendPaymentSync_Handler,
 		},
+		{
+			MethodName: "ListFeePresets",
+			Handler:    _Lightning_ListFeePresets_Handler,
+		},
 		{
 			MethodName: "AddInvoice",
 			Handler:    _Lightning_AddInvoice_Handler,
@@ -3829,6 +9504,18 @@ var _Lightning_serviceDesc = grpc.ServiceDesc{
 			MethodName: "LookupInvoice",
 			Handler:    _Lightning_LookupInvoice_Handler,
 		},
+		{
+			MethodName: "AddOffer",
+			Handler:    _Lightning_AddOffer_Handler,
+		},
+		{
+			MethodName: "ListOffers",
+			Handler:    _Lightning_ListOffers_Handler,
+		},
+		{
+			MethodName: "RequestOfferInvoice",
+			Handler:    _Lightning_RequestOfferInvoice_Handler,
+		},
 		{
 			MethodName: "DecodePayReq",
 			Handler:    _Lightning_DecodePayReq_Handler,
@@ -3869,6 +9556,182 @@ var _Lightning_serviceDesc = grpc.ServiceDesc{
 			MethodName: "DebugLevel",
 			Handler:    _Lightning_DebugLevel_Handler,
 		},
+		{
+			MethodName: "ExportChannelState",
+			Handler:    _Lightning_ExportChannelState_Handler,
+		},
+		{
+			MethodName: "ChannelHistory",
+			Handler:    _Lightning_ChannelHistory_Handler,
+		},
+		{
+			MethodName: "ListRecommendations",
+			Handler:    _Lightning_ListRecommendations_Handler,
+		},
+		{
+			MethodName: "ExecuteRecommendation",
+			Handler:    _Lightning_ExecuteRecommendation_Handler,
+		},
+		{
+			MethodName: "AckOutboxNotification",
+			Handler:    _Lightning_AckOutboxNotification_Handler,
+		},
+		{
+			MethodName: "RemoveOutboxSubscriber",
+			Handler:    _Lightning_RemoveOutboxSubscriber_Handler,
+		},
+		{
+			MethodName: "QueryMissionControl",
+			Handler:    _Lightning_QueryMissionControl_Handler,
+		},
+		{
+			MethodName: "ResetMissionControl",
+			Handler:    _Lightning_ResetMissionControl_Handler,
+		},
+		{
+			MethodName: "AckChannelDivergence",
+			Handler:    _Lightning_AckChannelDivergence_Handler,
+		},
+		{
+			MethodName: "SendCustomMessage",
+			Handler:    _Lightning_SendCustomMessage_Handler,
+		},
+		{
+			MethodName: "ExportSigningAudit",
+			Handler:    _Lightning_ExportSigningAudit_Handler,
+		},
+		{
+			MethodName: "ExportChannelPolicies",
+			Handler:    _Lightning_ExportChannelPolicies_Handler,
+		},
+		{
+			MethodName: "ImportChannelPolicies",
+			Handler:    _Lightning_ImportChannelPolicies_Handler,
+		},
+		{
+			MethodName: "BalanceHistory",
+			Handler:    _Lightning_BalanceHistory_Handler,
+		},
+		{
+			MethodName: "ListUnresolvedHTLCs",
+			Handler:    _Lightning_ListUnresolvedHTLCs_Handler,
+		},
+		{
+			MethodName: "UpdateCommitFee",
+			Handler:    _Lightning_UpdateCommitFee_Handler,
+		},
+		{
+			MethodName: "ExportGossip",
+			Handler:    _Lightning_ExportGossip_Handler,
+		},
+		{
+			MethodName: "ReplayGossip",
+			Handler:    _Lightning_ReplayGossip_Handler,
+		},
+		{
+			MethodName: "SpliceIn",
+			Handler:    _Lightning_SpliceIn_Handler,
+		},
+		{
+			MethodName: "BackupUploadStatus",
+			Handler:    _Lightning_BackupUploadStatus_Handler,
+		},
+		{
+			MethodName: "DBCommitStats",
+			Handler:    _Lightning_DBCommitStats_Handler,
+		},
+		{
+			MethodName: "ExportAccounting",
+			Handler:    _Lightning_ExportAccounting_Handler,
+		},
+		{
+			MethodName: "AbandonChannel",
+			Handler:    _Lightning_AbandonChannel_Handler,
+		},
+		{
+			MethodName: "RotateIdentity",
+			Handler:    _Lightning_RotateIdentity_Handler,
+		},
+		{
+			MethodName: "MineBlocks",
+			Handler:    _Lightning_MineBlocks_Handler,
+		},
+		{
+			MethodName: "AdvanceTime",
+			Handler:    _Lightning_AdvanceTime_Handler,
+		},
+		{
+			MethodName: "EstimateOpenChannel",
+			Handler:    _Lightning_EstimateOpenChannel_Handler,
+		},
+		{
+			MethodName: "GetFundingPolicy",
+			Handler:    _Lightning_GetFundingPolicy_Handler,
+		},
+		{
+			MethodName: "UpdateFundingPolicy",
+			Handler:    _Lightning_UpdateFundingPolicy_Handler,
+		},
+		{
+			MethodName: "ListPaymentAttempts",
+			Handler:    _Lightning_ListPaymentAttempts_Handler,
+		},
+		{
+			MethodName: "StateLog",
+			Handler:    _Lightning_StateLog_Handler,
+		},
+		{
+			MethodName: "UpdatePeerSettings",
+			Handler:    _Lightning_UpdatePeerSettings_Handler,
+		},
+		{
+			MethodName: "ListPeerSettings",
+			Handler:    _Lightning_ListPeerSettings_Handler,
+		},
+		{
+			MethodName: "DeletePeerSettings",
+			Handler:    _Lightning_DeletePeerSettings_Handler,
+		},
+		{
+			MethodName: "PaymentTelemetry",
+			Handler:    _Lightning_PaymentTelemetry_Handler,
+		},
+		{
+			MethodName: "TowerInfo",
+			Handler:    _Lightning_TowerInfo_Handler,
+		},
+		{
+			MethodName: "ChannelStates",
+			Handler:    _Lightning_ChannelStates_Handler,
+		},
+		{
+			MethodName: "FreezeChannel",
+			Handler:    _Lightning_FreezeChannel_Handler,
+		},
+		{
+			MethodName: "ThawChannel",
+			Handler:    _Lightning_ThawChannel_Handler,
+		},
+		{
+			MethodName: "CreatePayReq",
+			Handler:    _Lightning_CreatePayReq_Handler,
+		},
+		{
+			MethodName: "RevenueReport",
+			Handler:    _Lightning_RevenueReport_Handler,
+		},
+		{
+			MethodName: "TopCounterparties",
+			Handler:    _Lightning_TopCounterparties_Handler,
+		},
+		{
+			MethodName: "ConfirmIdentityRotation",
+			Handler:    _Lightning_ConfirmIdentityRotation_Handler,
+		},
+		{
+			MethodName: "ImportChannelState",
+			Handler:    _Lightning_ImportChannelState_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -3902,6 +9765,27 @@ var _Lightning_serviceDesc = grpc.ServiceDesc{
 			Handler:       _Lightning_SubscribeChannelGraph_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "RegisterRPCMiddleware",
+			Handler:       _Lightning_RegisterRPCMiddleware_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "SubscribeOutbox",
+			Handler:       _Lightning_SubscribeOutbox_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SubscribeCustomMessages",
+			Handler:       _Lightning_SubscribeCustomMessages_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SendBatchPayment",
+			Handler:       _Lightning_SendBatchPayment_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "rpc.proto",
 }
@@ -3909,250 +9793,573 @@ var _Lightning_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("rpc.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 3917 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x7a, 0xcf, 0x6f, 0x1c, 0x47,
-	0x76, 0xbf, 0x7a, 0x38, 0x24, 0x67, 0xde, 0xcc, 0xf0, 0x47, 0x91, 0x22, 0x47, 0x43, 0xd9, 0x96,
-	0x6a, 0x0d, 0x8b, 0x5f, 0x7e, 0x0d, 0x52, 0x66, 0x02, 0x47, 0xb6, 0x93, 0x2c, 0x68, 0x89, 0x2b,
-	0x0a, 0xcb, 0xa5, 0xb8, 0x4d, 0xd9, 0xda, 0xec, 0x22, 0x98, 0x34, 0xa7, 0x8b, 0xc3, 0x5e, 0xf5,
-	0x74, 0xb7, 0xbb, 0x6b, 0x48, 0xcd, 0x0a, 0x42, 0x82, 0xcd, 0xde, 0x92, 0x60, 0x11, 0x04, 0xc8,
-	0x71, 0x11, 0x20, 0xe7, 0x5c, 0x72, 0x4c, 0xfe, 0x86, 0x00, 0x01, 0xf6, 0x94, 0x43, 0x6e, 0x41,
-	0xee, 0xb9, 0xe7, 0x10, 0xbc, 0xfa, 0xd1, 0x5d, 0xd5, 0xdd, 0xb4, 0x15, 0xe4, 0x34, 0x53, 0x9f,
-	0x7a, 0xf5, 0xaa, 0xea, 0xd5, 0x7b, 0xaf, 0xde, 0x7b, 0xd5, 0xd0, 0x4e, 0x93, 0xd1, 0x6e, 0x92,
-	0xc6, 0x3c, 0x26, 0xf3, 0x61, 0x94, 0x26, 0xa3, 0xc1, 0xdd, 0x71, 0x1c, 0x8f, 0x43, 0xb6, 0xe7,
-	0x25, 0xc1, 0x9e, 0x17, 0x45, 0x31, 0xf7, 0x78, 0x10, 0x47, 0x99, 0x24, 0xa2, 0xff, 0xe5, 0x40,
-	0xe7, 0x45, 0xea, 0x45, 0x99, 0x37, 0x42, 0x98, 0xf4, 0x61, 0x91, 0xbf, 0x1e, 0x5e, 0x7a, 0xd9,
-	0x65, 0xdf, 0xb9, 0xe7, 0x6c, 0xb7, 0x5d, 0xdd, 0x24, 0x1b, 0xb0, 0xe0, 0x4d, 0xe2, 0x69, 0xc4,
-	0xfb, 0x8d, 0x7b, 0xce, 0xf6, 0x9c, 0xab, 0x5a, 0xe4, 0x63, 0x58, 0x8d, 0xa6, 0x93, 0xe1, 0x28,
-	0x8e, 0x2e, 0x82, 0x74, 0x22, 0x99, 0xf7, 0xe7, 0xee, 0x39, 0xdb, 0xf3, 0x6e, 0xb5, 0x83, 0xbc,
-	0x0f, 0x70, 0x1e, 0xc6, 0xa3, 0x57, 0x72, 0x8a, 0xa6, 0x98, 0xc2, 0x40, 0x08, 0x85, 0xae, 0x6a,
-	0xb1, 0x60, 0x7c, 0xc9, 0xfb, 0xf3, 0x82, 0x91, 0x85, 0x21, 0x0f, 0x1e, 0x4c, 0xd8, 0x30, 0xe3,
-	0xde, 0x24, 0xe9, 0x2f, 0x88, 0xd5, 0x18, 0x88, 0xe8, 0x8f, 0xb9, 0x17, 0x0e, 0x2f, 0x18, 0xcb,
-	0xfa, 0x8b, 0xaa, 0x3f, 0x47, 0x68, 0x1f, 0x36, 0x9e, 0x32, 0x6e, 0xec, 0x3a, 0x73, 0xd9, 0x37,
-	0x53, 0x96, 0x71, 0x7a, 0x0c, 0xc4, 0x80, 0x9f, 0x30, 0xee, 0x05, 0x61, 0x46, 0x3e, 0x85, 0x2e,
-	0x37, 0x88, 0xfb, 0xce, 0xbd, 0xb9, 0xed, 0xce, 0x3e, 0xd9, 0x15, 0xf2, 0xdd, 0x35, 0x06, 0xb8,
-	0x16, 0x1d, 0xfd, 0x57, 0x07, 0x3a, 0x67, 0x2c, 0xf2, 0x15, 0x77, 0x42, 0xa0, 0xe9, 0xb3, 0x8c,
-	0x0b, 0xc1, 0x76, 0x5d, 0xf1, 0x9f, 0x7c, 0x00, 0x1d, 0xfc, 0x1d, 0x66, 0x3c, 0x0d, 0xa2, 0xb1,
-	0x10, 0x6d, 0xdb, 0x05, 0x84, 0xce, 0x04, 0x42, 0x56, 0x60, 0xce, 0x9b, 0x70, 0x21, 0xd0, 0x39,
-	0x17, 0xff, 0x92, 0xfb, 0xd0, 0x4d, 0xbc, 0xd9, 0x84, 0x45, 0xbc, 0x10, 0x62, 0xd7, 0xed, 0x28,
-	0xec, 0x08, 0xa5, 0xb8, 0x0b, 0x6b, 0x26, 0x89, 0xe6, 0x3e, 0x2f, 0xb8, 0xaf, 0x1a, 0x94, 0x6a,
-	0x92, 0x07, 0xb0, 0xac, 0xe9, 0x53, 0xb9, 0x58, 0x21, 0xd6, 0xb6, 0xbb, 0xa4, 0x60, 0x2d, 0xa0,
-	0x08, 0xba, 0x72, 0x47, 0x59, 0x12, 0x47, 0x19, 0x23, 0x3b, 0xb0, 0xa2, 0x07, 0x26, 0x29, 0x0b,
-	0x26, 0xde, 0x98, 0xa9, 0xed, 0x55, 0x70, 0xb2, 0x0f, 0xbd, 0x7c, 0x92, 0x78, 0xca, 0x99, 0xd8,
-	0x6c, 0x67, 0xbf, 0xab, 0xe4, 0xe8, 0x22, 0xe6, 0xda, 0x24, 0xf4, 0x97, 0x0e, 0x74, 0x1f, 0x5f,
-	0x7a, 0x51, 0xc4, 0xc2, 0xd3, 0x38, 0x88, 0x38, 0xea, 0xc7, 0xc5, 0x34, 0xf2, 0x83, 0x68, 0x3c,
-	0xe4, 0xaf, 0x03, 0x5f, 0x4d, 0x66, 0x61, 0xb8, 0x28, 0xb3, 0x8d, 0xbb, 0x57, 0x82, 0xad, 0xe0,
-	0xc8, 0x2f, 0x9e, 0xf2, 0x64, 0xca, 0x87, 0x41, 0xe4, 0xb3, 0xd7, 0x42, 0xce, 0x3d, 0xd7, 0xc2,
-	0xe8, 0x1f, 0xc2, 0xca, 0x31, 0x2a, 0x5e, 0x14, 0x44, 0xe3, 0x03, 0xdf, 0x4f, 0x59, 0x96, 0xa1,
-	0x35, 0x24, 0xd3, 0xf3, 0x57, 0x6c, 0xa6, 0xcc, 0x44, 0xb5, 0xf0, 0x8c, 0x2f, 0xe3, 0x8c, 0xab,
-	0xf9, 0xc4, 0x7f, 0xfa, 0x77, 0x0e, 0x2c, 0xa3, 0xd4, 0x7e, 0xe4, 0x45, 0x33, 0xad, 0x0b, 0xc7,
-	0xd0, 0x45, 0x56, 0x2f, 0xe2, 0x03, 0x69, 0x53, 0x52, 0xa7, 0xb6, 0x95, 0x2c, 0x4a, 0xd4, 0xbb,
-	0x26, 0xe9, 0x61, 0xc4, 0xd3, 0x99, 0xdb, 0xf5, 0x0c, 0x68, 0xf0, 0x7d, 0x58, 0xad, 0x90, 0xa0,
-	0xe6, 0x14, 0xeb, 0xc3, 0xbf, 0x64, 0x1d, 0xe6, 0xaf, 0xbc, 0x70, 0xca, 0x94, 0x05, 0xcb, 0xc6,
-	0xe7, 0x8d, 0x47, 0x0e, 0xfd, 0x08, 0x56, 0x8a, 0x39, 0xd5, 0xd9, 0x12, 0x68, 0xe6, 0x22, 0x6e,
-	0xbb, 0xe2, 0x3f, 0x8a, 0x02, 0xe9, 0x1e, 0xc7, 0x41, 0x6e, 0x34, 0x48, 0x87, 0x8b, 0xd1, 0x74,
-	0xf8, 0xff, 0x26, 0x67, 0x41, 0x1f, 0xc0, 0xaa, 0x31, 0xfe, 0x5b, 0x26, 0xfa, 0x8d, 0x03, 0xab,
-	0x27, 0xec, 0x5a, 0x89, 0x5b, 0x4f, 0xf5, 0x08, 0x9a, 0x7c, 0x96, 0x48, 0x15, 0x5b, 0xda, 0xff,
-	0x50, 0x49, 0xab, 0x42, 0xb7, 0xab, 0x9a, 0x2f, 0x66, 0x09, 0x73, 0xc5, 0x08, 0xfa, 0x1c, 0x3a,
-	0x06, 0x48, 0x36, 0x61, 0xed, 0xe5, 0xb3, 0x17, 0x27, 0x87, 0x67, 0x67, 0xc3, 0xd3, 0xaf, 0xbe,
-	0xfc, 0xe1, 0xe1, 0x1f, 0x0d, 0x8f, 0x0e, 0xce, 0x8e, 0x56, 0x6e, 0x91, 0x0d, 0x20, 0x27, 0x87,
-	0x67, 0x2f, 0x0e, 0x9f, 0x58, 0xb8, 0x43, 0x96, 0xa1, 0x63, 0x02, 0x0d, 0x3a, 0x80, 0xfe, 0x09,
-	0xbb, 0x7e, 0x19, 0xf0, 0x88, 0x65, 0x99, 0x3d, 0x3d, 0xdd, 0x05, 0x62, 0xae, 0x49, 0x6d, 0xb3,
-	0x0f, 0x8b, 0x9e, 0x84, 0xb4, 0x6b, 0x55, 0x4d, 0xfa, 0x15, 0x90, 0xc7, 0x71, 0x14, 0xb1, 0x11,
-	0x3f, 0x65, 0x2c, 0xd5, 0x9b, 0xfd, 0xff, 0x86, 0x5c, 0x3b, 0xfb, 0x9b, 0x6a, 0xb3, 0x65, 0x4d,
-	0x54, 0x02, 0x27, 0xd0, 0x4c, 0x58, 0x3a, 0x11, 0xe2, 0x6e, 0xb9, 0xe2, 0x3f, 0xdd, 0x83, 0x35,
-	0x8b, 0x6d, 0xb1, 0x8e, 0x84, 0xb1, 0x74, 0xa8, 0x24, 0x3e, 0xef, 0xea, 0x26, 0xfd, 0x47, 0x07,
-	0x9a, 0x47, 0x2f, 0x8e, 0x1f, 0x93, 0x01, 0xb4, 0x82, 0x68, 0x14, 0x4f, 0xd0, 0x69, 0x38, 0x82,
-	0x63, 0xde, 0xbe, 0xf1, 0x1e, 0xb8, 0x0b, 0x6d, 0xe1, 0x6b, 0xd0, 0x53, 0x0b, 0x33, 0xea, 0xba,
-	0x05, 0x80, 0xb7, 0x04, 0x7b, 0x9d, 0x04, 0xa9, 0xb8, 0x06, 0xb4, 0x73, 0x6f, 0x0a, 0x63, 0xab,
-	0x76, 0xa0, 0x05, 0xa7, 0xec, 0x2a, 0x1e, 0x49, 0xd0, 0x67, 0xa1, 0x37, 0x13, 0xce, 0xab, 0xe7,
-	0x56, 0x70, 0xfa, 0x9f, 0x73, 0xd0, 0x3b, 0x18, 0xf1, 0xe0, 0x8a, 0x29, 0x47, 0x21, 0x56, 0x28,
-	0x00, 0xb5, 0x76, 0xd5, 0x22, 0x1f, 0x42, 0x2f, 0x65, 0x93, 0x98, 0xb3, 0xa1, 0x32, 0x5d, 0x69,
-	0xa4, 0x36, 0x88, 0x54, 0x23, 0xc9, 0x68, 0x98, 0xa0, 0xcb, 0x11, 0x7b, 0x69, 0xbb, 0x36, 0x88,
-	0x42, 0x44, 0x00, 0x85, 0x88, 0xbb, 0x68, 0xba, 0xba, 0x89, 0xb2, 0x1b, 0x79, 0x89, 0x37, 0x0a,
-	0xb8, 0x5c, 0xf3, 0x9c, 0x9b, 0xb7, 0x91, 0x77, 0x18, 0x8f, 0xbc, 0x70, 0x78, 0xee, 0x85, 0x5e,
-	0x34, 0x62, 0xea, 0xf2, 0xb2, 0x41, 0xf2, 0x11, 0x2c, 0xa9, 0x25, 0x69, 0x32, 0x79, 0x87, 0x95,
-	0x50, 0x94, 0xe9, 0x34, 0xca, 0x18, 0xe7, 0x21, 0xf3, 0x73, 0xd2, 0x96, 0x20, 0xad, 0x76, 0x90,
-	0x87, 0xb0, 0x26, 0xef, 0xc0, 0xcc, 0xe3, 0x71, 0x76, 0x19, 0x64, 0xc3, 0x8c, 0x45, 0xbc, 0xdf,
-	0x16, 0xf4, 0x75, 0x5d, 0xe4, 0x11, 0x6c, 0x96, 0xe0, 0x94, 0x8d, 0x58, 0x70, 0xc5, 0xfc, 0x3e,
-	0x88, 0x51, 0x37, 0x75, 0x93, 0x7b, 0xd0, 0xc1, 0xab, 0x7f, 0x9a, 0xf8, 0x1e, 0x67, 0x59, 0xbf,
-	0x23, 0x24, 0x64, 0x42, 0xe4, 0x13, 0xe8, 0x25, 0x4c, 0xfa, 0xe2, 0x4b, 0x1e, 0x8e, 0xb2, 0x7e,
-	0x57, 0x38, 0xc0, 0x8e, 0xd2, 0x72, 0xd4, 0x42, 0xd7, 0xa6, 0xa0, 0xb7, 0x61, 0xed, 0x38, 0xc8,
-	0xb8, 0x3a, 0xe5, 0xdc, 0xd8, 0x8e, 0x60, 0xdd, 0x86, 0x95, 0x9a, 0x3f, 0x84, 0x96, 0x3a, 0x32,
-	0x5c, 0x00, 0x32, 0x5f, 0x57, 0xcc, 0x2d, 0x6d, 0x71, 0x73, 0x2a, 0xfa, 0xab, 0x06, 0x34, 0xd1,
-	0x52, 0x84, 0x85, 0x4c, 0xcf, 0x87, 0x85, 0xf7, 0xd4, 0x4d, 0xd3, 0x76, 0x1a, 0x96, 0xed, 0x98,
-	0xd6, 0x3d, 0x67, 0x59, 0xb7, 0x08, 0x79, 0x66, 0x9c, 0x29, 0x79, 0x4b, 0x6d, 0x31, 0x90, 0xa2,
-	0x3f, 0x65, 0xa3, 0x2b, 0xa1, 0x32, 0x79, 0x3f, 0x22, 0xa8, 0x50, 0x99, 0xc7, 0xe5, 0x68, 0xa9,
-	0x2f, 0x79, 0x5b, 0xf7, 0x89, 0x91, 0x8b, 0x45, 0x9f, 0x18, 0xd7, 0x87, 0xc5, 0x20, 0x3a, 0x8f,
-	0xa7, 0x91, 0x2f, 0x94, 0xa2, 0xe5, 0xea, 0x26, 0x9a, 0x6a, 0x22, 0x6e, 0xc1, 0x60, 0xc2, 0x94,
-	0x02, 0x14, 0x00, 0x25, 0x78, 0xdd, 0x65, 0xc2, 0x67, 0xe4, 0x42, 0xfe, 0x14, 0x56, 0x0d, 0x4c,
-	0x49, 0xf8, 0x3e, 0xcc, 0xe3, 0xee, 0x75, 0x40, 0xa4, 0xcf, 0x4e, 0x38, 0x1b, 0xd9, 0x43, 0x57,
-	0x60, 0xe9, 0x29, 0xe3, 0xcf, 0xa2, 0x8b, 0x58, 0x73, 0xfa, 0xf7, 0x06, 0x2c, 0xe7, 0x90, 0x62,
-	0xb4, 0x0d, 0xcb, 0x81, 0xcf, 0x22, 0x1e, 0xf0, 0xd9, 0xd0, 0xba, 0x55, 0xcb, 0x30, 0xde, 0x60,
-	0x5e, 0x18, 0x78, 0x99, 0x32, 0x5d, 0xd9, 0x20, 0xfb, 0xb0, 0x8e, 0xba, 0xa5, 0xd5, 0x25, 0x3f,
-	0x76, 0x79, 0x99, 0xd7, 0xf6, 0xa1, 0x39, 0x20, 0x2e, 0x5d, 0x43, 0x31, 0x44, 0xba, 0xa4, 0xba,
-	0x2e, 0x94, 0x9a, 0xe4, 0x84, 0x5b, 0x96, 0xde, 0xa8, 0x00, 0x2a, 0x81, 0xeb, 0x82, 0x0c, 0x24,
-	0xca, 0x81, 0xab, 0x11, 0xfc, 0xb6, 0x2a, 0xc1, 0xef, 0x36, 0x2c, 0x67, 0xb3, 0x68, 0xc4, 0xfc,
-	0x21, 0x8f, 0x71, 0xde, 0x20, 0x12, 0xa7, 0xd3, 0x72, 0xcb, 0xb0, 0x08, 0xd3, 0x59, 0xc6, 0x23,
-	0xc6, 0x85, 0x29, 0xb6, 0x5c, 0xdd, 0xa4, 0xbf, 0x10, 0x77, 0x49, 0x1e, 0x71, 0x7f, 0x25, 0xec,
-	0x8d, 0x6c, 0x41, 0x5b, 0xce, 0x93, 0x5d, 0x7a, 0x2a, 0x66, 0x6a, 0x09, 0xe0, 0xec, 0xd2, 0xc3,
-	0x80, 0xd2, 0x5a, 0xba, 0xd4, 0xec, 0x8e, 0xc0, 0x8e, 0xe4, 0xca, 0x3f, 0x84, 0x25, 0x1d, 0xcb,
-	0x67, 0xc3, 0x90, 0x5d, 0x70, 0x1d, 0x28, 0x45, 0xd3, 0x09, 0x4e, 0x97, 0x1d, 0xb3, 0x0b, 0x4e,
-	0x4f, 0x60, 0x55, 0x59, 0xd5, 0xf3, 0x84, 0xe9, 0xa9, 0x3f, 0x2b, 0xfb, 0x53, 0x79, 0x9f, 0xad,
-	0x29, 0x6d, 0x31, 0xa3, 0xbb, 0x92, 0x93, 0xa5, 0x2e, 0x10, 0xd5, 0xfd, 0x38, 0x8c, 0x33, 0xa6,
-	0x18, 0x52, 0xe8, 0x8e, 0xc2, 0x38, 0x2b, 0x87, 0x80, 0x26, 0x86, 0xf2, 0xc9, 0xa6, 0xa3, 0x11,
-	0x5a, 0xa3, 0xbc, 0x11, 0x75, 0x93, 0xfe, 0xca, 0x81, 0x35, 0xc1, 0x4d, 0xdb, 0x7f, 0x1e, 0x5a,
-	0xbc, 0xfb, 0x32, 0xbb, 0x23, 0x33, 0x24, 0x7d, 0x4f, 0xa5, 0x23, 0x61, 0x30, 0x09, 0xf4, 0xa5,
-	0xd8, 0x46, 0xe4, 0x18, 0x01, 0x54, 0xd9, 0x8b, 0x38, 0x1d, 0x31, 0x21, 0xb1, 0x96, 0x2b, 0x1b,
-	0xf4, 0xdf, 0x1c, 0x58, 0x15, 0xcb, 0x38, 0xe3, 0x1e, 0x9f, 0x66, 0x6a, 0x6b, 0xbf, 0x0f, 0x3d,
-	0xdc, 0x06, 0xd3, 0xea, 0xaa, 0x16, 0xb1, 0x9e, 0x5b, 0x96, 0x40, 0x25, 0xf1, 0xd1, 0x2d, 0xd7,
-	0x26, 0x26, 0xdf, 0x87, 0xae, 0x99, 0x6c, 0xa9, 0xf8, 0xfa, 0x8e, 0xde, 0x41, 0x45, 0x2b, 0x8e,
-	0x6e, 0xb9, 0xd6, 0x00, 0xf2, 0x05, 0x80, 0xb8, 0xc5, 0x04, 0x5b, 0xb1, 0x5e, 0x63, 0x78, 0xe5,
-	0x20, 0x8e, 0x6e, 0xb9, 0x06, 0xf9, 0x97, 0x2d, 0x58, 0x90, 0xce, 0x9d, 0x3e, 0x85, 0x9e, 0xb5,
-	0x52, 0x2b, 0xc0, 0xeb, 0xca, 0x00, 0xaf, 0x12, 0x78, 0x37, 0x6a, 0x02, 0xef, 0xff, 0x76, 0x80,
-	0xa0, 0x26, 0x95, 0x8e, 0xea, 0x23, 0x58, 0xe2, 0x5e, 0x3a, 0x66, 0x7c, 0x68, 0xc7, 0x31, 0x25,
-	0x54, 0xdc, 0x42, 0xb1, 0x6f, 0xdd, 0xf6, 0x5d, 0xd7, 0x84, 0xc8, 0x2e, 0x10, 0xa3, 0xa9, 0xd3,
-	0x24, 0xe9, 0xbf, 0x6b, 0x7a, 0xd0, 0xd1, 0xc8, 0xab, 0x5a, 0xe7, 0x11, 0x2a, 0x12, 0x6a, 0x8a,
-	0x43, 0xaf, 0xed, 0x43, 0x17, 0x9d, 0x4c, 0x31, 0x07, 0xf3, 0xb8, 0x8e, 0x07, 0x74, 0x5b, 0xbb,
-	0x14, 0x61, 0x56, 0xca, 0x63, 0x14, 0x00, 0xfd, 0xad, 0x03, 0x2b, 0xb8, 0x7d, 0x4b, 0x45, 0x3e,
-	0x07, 0xa1, 0x7d, 0xef, 0xa8, 0x21, 0x16, 0xed, 0xff, 0x5d, 0x41, 0x1e, 0x41, 0x5b, 0x30, 0x8c,
-	0x13, 0x16, 0x29, 0xfd, 0xe8, 0xdb, 0xfa, 0x51, 0x18, 0xfe, 0xd1, 0x2d, 0xb7, 0x20, 0x36, 0xb4,
-	0xe3, 0x10, 0x6e, 0xab, 0x55, 0x96, 0x8e, 0xf5, 0x63, 0x58, 0xc8, 0xc4, 0x4e, 0x55, 0x78, 0xbf,
-	0x6e, 0x73, 0x96, 0x52, 0x70, 0x15, 0x0d, 0xfd, 0x8b, 0x39, 0xd8, 0x28, 0xf3, 0x51, 0xd7, 0xc9,
-	0x4f, 0x60, 0xa5, 0x72, 0x15, 0xc8, 0x2b, 0xea, 0x63, 0x5b, 0x4c, 0xa5, 0x81, 0x65, 0xb8, 0xc2,
-	0x65, 0xf0, 0xb7, 0x0d, 0x58, 0xb2, 0x89, 0x50, 0x8f, 0xf3, 0x4b, 0xaa, 0xb8, 0xb8, 0x2c, 0xac,
-	0x1a, 0x52, 0x36, 0xea, 0x42, 0x4a, 0x33, 0x70, 0x9c, 0xfb, 0xae, 0xc0, 0xb1, 0xf9, 0x6e, 0x81,
-	0xe3, 0x7c, 0x6d, 0xe0, 0x58, 0xf6, 0xa0, 0x32, 0xd7, 0xb7, 0x3d, 0x68, 0x71, 0x1a, 0x8b, 0xef,
-	0x70, 0x1a, 0x9f, 0xc1, 0xfa, 0x4b, 0x2f, 0x0c, 0x19, 0xff, 0x52, 0x4e, 0xa1, 0xcf, 0xf4, 0x3e,
-	0x74, 0xaf, 0x65, 0x8a, 0x34, 0x8c, 0xa3, 0x70, 0xa6, 0x02, 0xf2, 0x8e, 0xc2, 0x9e, 0x47, 0xe1,
-	0x8c, 0x7e, 0x02, 0xb7, 0x4b, 0x43, 0x8b, 0x3c, 0x45, 0x6f, 0x03, 0x87, 0x39, 0xae, 0x6e, 0xd2,
-	0x4d, 0xb8, 0xad, 0x96, 0x61, 0x4f, 0x47, 0xf7, 0x61, 0xa3, 0xdc, 0x51, 0xcf, 0x6c, 0xae, 0x60,
-	0xf6, 0x19, 0x74, 0x65, 0xe9, 0x41, 0x2d, 0x79, 0xb3, 0x1c, 0xfc, 0x61, 0x6a, 0xff, 0x43, 0x36,
-	0xd3, 0x95, 0x98, 0x46, 0x5e, 0x89, 0xa1, 0x7f, 0x0a, 0x73, 0x47, 0x71, 0x62, 0xe6, 0x02, 0x8e,
-	0x9d, 0x0b, 0xa8, 0x83, 0x1f, 0xe6, 0xe7, 0x2a, 0x07, 0xdb, 0x20, 0x1e, 0x9b, 0x37, 0xe1, 0x78,
-	0xb9, 0x5f, 0xc4, 0xe9, 0xb5, 0x97, 0xfa, 0xea, 0xf8, 0x4b, 0x28, 0x2e, 0xe0, 0x82, 0xe9, 0xa3,
-	0xc7, 0xbf, 0xf4, 0xd7, 0x0e, 0xcc, 0x8b, 0xc5, 0x63, 0xe8, 0x20, 0x83, 0x71, 0x79, 0x15, 0x61,
-	0x0e, 0xe6, 0x08, 0x7f, 0x52, 0x86, 0x4b, 0xd5, 0xb1, 0x46, 0xb9, 0x3a, 0x86, 0x3e, 0x49, 0xb6,
-	0x8a, 0xb2, 0x53, 0x01, 0x90, 0xf7, 0xa1, 0x79, 0x19, 0x27, 0x18, 0x27, 0xa1, 0x3d, 0x81, 0x0e,
-	0xd7, 0xe3, 0xc4, 0x15, 0x38, 0xdd, 0x81, 0xe5, 0x93, 0xd8, 0x67, 0x46, 0xc4, 0x77, 0xa3, 0x40,
-	0xe9, 0x9f, 0x39, 0xd0, 0xd2, 0xc4, 0x64, 0x1b, 0x9a, 0xe8, 0x70, 0x4b, 0xfe, 0x2c, 0xcf, 0x76,
-	0x91, 0xce, 0x15, 0x14, 0xa8, 0xbd, 0xc2, 0x47, 0x6a, 0xd3, 0x6e, 0xe4, 0x91, 0x48, 0x11, 0xab,
-	0xe1, 0x15, 0x21, 0xd6, 0x5c, 0xb2, 0xa8, 0x12, 0x4a, 0xdf, 0x40, 0xcf, 0x9a, 0x02, 0xef, 0x8c,
-	0xd0, 0xcb, 0xb8, 0xca, 0x53, 0x94, 0x0c, 0x4d, 0xc8, 0x4c, 0x0e, 0x1a, 0x95, 0xe4, 0xe0, 0x86,
-	0x14, 0x20, 0x0f, 0x5b, 0x9b, 0x46, 0xd8, 0x4a, 0xff, 0xc1, 0x81, 0x1e, 0x9e, 0x5e, 0x10, 0x8d,
-	0x4f, 0xe3, 0x30, 0x18, 0xcd, 0xc4, 0x29, 0xea, 0x83, 0xc2, 0xf4, 0x96, 0x7b, 0xf9, 0x29, 0xda,
-	0x30, 0x3a, 0x8b, 0x49, 0x10, 0x89, 0xcc, 0x48, 0x9d, 0x61, 0xde, 0x46, 0xad, 0xbb, 0x60, 0x68,
-	0xed, 0x19, 0x1b, 0x4e, 0xf0, 0xda, 0x91, 0x7b, 0xb7, 0x41, 0x0c, 0x80, 0x11, 0x48, 0x3d, 0xce,
-	0x86, 0x93, 0x20, 0x0c, 0x03, 0x49, 0x2b, 0xb5, 0xab, 0xae, 0x8b, 0xfe, 0x73, 0x03, 0x3a, 0xca,
-	0xbc, 0x0e, 0xfd, 0x31, 0x43, 0x4d, 0xd2, 0x1e, 0x2c, 0x57, 0x7d, 0x03, 0xd1, 0xfd, 0x96, 0xcf,
-	0x33, 0x90, 0xb2, 0xac, 0xe7, 0xaa, 0xb2, 0xc6, 0xfb, 0x31, 0xf6, 0xd9, 0x27, 0x78, 0x0d, 0x2b,
-	0xd9, 0x15, 0x80, 0xee, 0xdd, 0x17, 0xbd, 0xf3, 0x45, 0xaf, 0x00, 0x2c, 0x77, 0xba, 0x50, 0x72,
-	0xa7, 0x8f, 0xa0, 0xab, 0xd8, 0x08, 0xb9, 0x0b, 0x17, 0x57, 0x28, 0x9d, 0x75, 0x26, 0xae, 0x45,
-	0xa9, 0x47, 0xee, 0xeb, 0x91, 0xad, 0xef, 0x1a, 0xa9, 0x29, 0x31, 0x7d, 0x55, 0xc2, 0x7b, 0x9a,
-	0x7a, 0xc9, 0xa5, 0x76, 0x59, 0x7e, 0x5e, 0xe0, 0x14, 0x30, 0xd9, 0x81, 0x79, 0x1c, 0xa6, 0x6f,
-	0xac, 0x7a, 0x43, 0x90, 0x24, 0x64, 0x1b, 0xe6, 0x99, 0x3f, 0x16, 0x56, 0x6c, 0x56, 0xa4, 0x8d,
-	0x33, 0x72, 0x25, 0x01, 0x9a, 0x25, 0xa2, 0x25, 0xb3, 0xb4, 0xbd, 0xd6, 0x02, 0x36, 0x9f, 0xf9,
-	0x74, 0x1d, 0xc8, 0x09, 0xe3, 0xd7, 0x71, 0xfa, 0xca, 0xcc, 0xdb, 0xfe, 0x7c, 0x0e, 0x3a, 0x06,
-	0x8c, 0x16, 0x36, 0xc6, 0x05, 0x0f, 0xfd, 0xc0, 0x9b, 0x30, 0xce, 0x52, 0xa5, 0xa9, 0x25, 0x54,
-	0x38, 0xb7, 0xab, 0xf1, 0x30, 0x9e, 0xf2, 0xa1, 0xcf, 0xc6, 0x29, 0x93, 0xc5, 0x47, 0xc7, 0x2d,
-	0xa1, 0x48, 0x37, 0xf1, 0x5e, 0x9b, 0x74, 0x52, 0x1f, 0x4a, 0xa8, 0x0e, 0x99, 0xa4, 0x8c, 0x9a,
-	0x45, 0xc8, 0x24, 0x25, 0x52, 0xf6, 0x0d, 0xf3, 0x35, 0xbe, 0xe1, 0x53, 0xd8, 0x90, 0x5e, 0x20,
-	0x92, 0xdb, 0x19, 0x96, 0xd4, 0xe4, 0x86, 0x5e, 0xb2, 0x03, 0x2b, 0xb8, 0x66, 0xad, 0xe0, 0x59,
-	0xf0, 0x0b, 0x59, 0x98, 0x71, 0xdc, 0x0a, 0x8e, 0xb4, 0x68, 0x8e, 0x16, 0xad, 0xac, 0xcc, 0x54,
-	0x70, 0x41, 0xeb, 0xbd, 0xb6, 0x69, 0xdb, 0x8a, 0xb6, 0x84, 0xd3, 0x2d, 0xb8, 0x23, 0xd4, 0xe4,
-	0x45, 0x9c, 0xc4, 0x61, 0x3c, 0x9e, 0x9d, 0x4d, 0xcf, 0xb3, 0x51, 0x1a, 0x24, 0x18, 0x9d, 0xd1,
-	0x7f, 0x71, 0x60, 0xcd, 0xea, 0x55, 0x21, 0xe3, 0xef, 0x4a, 0x9d, 0xcd, 0xcb, 0x31, 0x52, 0xb3,
-	0x56, 0x75, 0xf5, 0x34, 0xf6, 0x55, 0x40, 0x2f, 0x63, 0xe3, 0xaf, 0x54, 0x85, 0xe6, 0x00, 0x96,
-	0xf5, 0xd4, 0x7a, 0xa0, 0x54, 0xb3, 0x7e, 0x55, 0xcd, 0xd4, 0xf8, 0x25, 0x35, 0x40, 0xb3, 0xf8,
-	0x03, 0x19, 0x67, 0x30, 0x5f, 0x6c, 0x02, 0xbd, 0x22, 0x8e, 0x1f, 0xe8, 0xf1, 0xa2, 0xeb, 0xb1,
-	0x39, 0xc4, 0xed, 0x8c, 0x72, 0x30, 0xa3, 0x7f, 0xe9, 0x00, 0x14, 0xab, 0xc3, 0x93, 0x57, 0xfe,
-	0x54, 0xed, 0xa1, 0xed, 0x16, 0x00, 0x46, 0x1a, 0x56, 0x1c, 0x26, 0xdd, 0x4d, 0x47, 0x63, 0x78,
-	0x81, 0x3f, 0x80, 0xe5, 0x71, 0x18, 0x9f, 0x8b, 0x8b, 0xce, 0xe3, 0xd3, 0x94, 0x65, 0xaa, 0x4e,
-	0xb9, 0x24, 0xe1, 0x1f, 0x28, 0xf4, 0x06, 0x77, 0xfd, 0x57, 0x8d, 0x3c, 0xbd, 0x2d, 0xf6, 0x7c,
-	0xa3, 0x19, 0x91, 0xfd, 0x8a, 0xf7, 0xbb, 0x21, 0x9b, 0x14, 0x51, 0xf2, 0xe9, 0x77, 0x86, 0x80,
-	0x5f, 0xc0, 0x52, 0x2a, 0xdd, 0x8b, 0xf6, 0x3d, 0xcd, 0x6f, 0xf1, 0x3d, 0xbd, 0xd4, 0xba, 0x58,
-	0xfe, 0x1f, 0xac, 0x78, 0xfe, 0x15, 0x4b, 0x79, 0x20, 0x22, 0x3c, 0x71, 0xd3, 0x4a, 0x8f, 0xb9,
-	0x6c, 0xe0, 0xe2, 0x06, 0x7c, 0x00, 0xcb, 0x23, 0x59, 0x35, 0xce, 0x29, 0xd5, 0x5b, 0x50, 0x01,
-	0x23, 0x21, 0xfd, 0x7b, 0x9d, 0x49, 0xdb, 0x67, 0x78, 0xb3, 0x44, 0xcc, 0xdd, 0x35, 0x4a, 0xbb,
-	0xfb, 0x9e, 0xca, 0x7c, 0x7d, 0x5d, 0x84, 0x50, 0xf5, 0x05, 0x09, 0xaa, 0x2a, 0x84, 0x2d, 0xd2,
-	0xe6, 0xbb, 0x88, 0x94, 0xee, 0xc2, 0xf2, 0x19, 0xe3, 0x07, 0x78, 0x82, 0xda, 0xf3, 0x6d, 0x41,
-	0x3b, 0x62, 0xd7, 0x43, 0x79, 0xc4, 0x32, 0x24, 0x69, 0x45, 0xec, 0x5a, 0xd0, 0x50, 0x02, 0x2b,
-	0x05, 0xbd, 0x0c, 0x1e, 0xe9, 0x5f, 0x37, 0x60, 0xf1, 0x59, 0x74, 0x15, 0x07, 0x23, 0x91, 0xcb,
-	0x4e, 0xd8, 0x24, 0xd6, 0x8f, 0x15, 0xf8, 0x1f, 0x2f, 0x7e, 0x51, 0xfa, 0x4c, 0xb8, 0x4a, 0x32,
-	0x75, 0x13, 0xaf, 0xc0, 0xb4, 0x78, 0x19, 0x93, 0xda, 0x66, 0x20, 0x64, 0x03, 0x16, 0x52, 0xf3,
-	0x15, 0x4f, 0xb5, 0x8a, 0x97, 0x9a, 0x79, 0xe3, 0xa5, 0x46, 0x54, 0x35, 0x64, 0x55, 0x57, 0x1c,
-	0x49, 0xcb, 0xd5, 0x4d, 0x11, 0x68, 0xa6, 0x4c, 0x95, 0xc5, 0xf1, 0x32, 0x5d, 0x54, 0x81, 0xa6,
-	0x09, 0xe2, 0x85, 0x2b, 0x07, 0x48, 0x1a, 0xe9, 0x90, 0x4c, 0x08, 0x03, 0x90, 0xf2, 0x43, 0x60,
-	0x5b, 0xaa, 0x49, 0x09, 0xa6, 0x5f, 0x03, 0x39, 0xf0, 0x7d, 0x25, 0x95, 0x3c, 0xcc, 0x2e, 0xf6,
-	0xe3, 0x58, 0xfb, 0xa9, 0xe1, 0xdb, 0xa8, 0xe7, 0x7b, 0x08, 0x9d, 0x53, 0xe3, 0x25, 0x53, 0x08,
-	0x50, 0xbf, 0x61, 0x2a, 0xa1, 0x1b, 0x88, 0x31, 0x61, 0xc3, 0x9c, 0x90, 0xfe, 0x1e, 0x90, 0xe3,
-	0x20, 0xe3, 0xf9, 0xfa, 0xf2, 0x74, 0x44, 0xe7, 0x74, 0x66, 0x3a, 0xa2, 0x30, 0x91, 0x8e, 0x1c,
-	0xc8, 0x2a, 0x73, 0x79, 0x63, 0x3b, 0xd0, 0x0a, 0x24, 0xa4, 0xfd, 0xe7, 0x92, 0x52, 0x3c, 0x4d,
-	0x99, 0xf7, 0xe3, 0x4d, 0xaf, 0x40, 0xcb, 0x3d, 0xff, 0xda, 0x81, 0x45, 0xb5, 0x35, 0xbc, 0xa7,
-	0xac, 0x37, 0x5c, 0x95, 0x35, 0x9a, 0x58, 0xfd, 0x6b, 0x5d, 0xf5, 0xa4, 0xe7, 0xea, 0x4e, 0x9a,
-	0x40, 0x33, 0xf1, 0xf8, 0xa5, 0x08, 0xd3, 0xdb, 0xae, 0xf8, 0xaf, 0xd3, 0x87, 0xf9, 0x22, 0x7d,
-	0x50, 0x15, 0x75, 0xb5, 0xa8, 0xbc, 0xd8, 0xfb, 0xa5, 0xac, 0xa8, 0x17, 0x70, 0x21, 0x03, 0xb5,
-	0xc0, 0xb2, 0x0c, 0x14, 0xa9, 0x9b, 0xf7, 0xd3, 0x01, 0xf4, 0x9f, 0xb0, 0x90, 0x71, 0x76, 0x10,
-	0x86, 0x65, 0xfe, 0x5b, 0x70, 0xa7, 0xa6, 0x4f, 0xd9, 0xda, 0x0f, 0x60, 0xf5, 0x09, 0x3b, 0x9f,
-	0x8e, 0x8f, 0xd9, 0x55, 0x51, 0x1a, 0x20, 0xd0, 0xcc, 0x2e, 0xe3, 0x6b, 0x75, 0x5e, 0xe2, 0x3f,
-	0x79, 0x0f, 0x20, 0x44, 0x9a, 0x61, 0x96, 0xb0, 0x91, 0xd2, 0xa6, 0xb6, 0x40, 0xce, 0x12, 0x36,
-	0xa2, 0x9f, 0x02, 0x31, 0xf9, 0xa8, 0x2d, 0xa0, 0x05, 0x4c, 0xcf, 0x87, 0xd9, 0x2c, 0xe3, 0x6c,
-	0xa2, 0x8d, 0xdf, 0x84, 0xe8, 0x03, 0xe8, 0x9e, 0x7a, 0x33, 0x97, 0x7d, 0xa3, 0x9e, 0xc6, 0x31,
-	0x7b, 0xf1, 0x66, 0xa8, 0x9e, 0x79, 0xf6, 0x22, 0xba, 0x69, 0x0a, 0x0b, 0x92, 0x10, 0x99, 0xfa,
-	0x2c, 0xe3, 0x41, 0x24, 0xab, 0x2a, 0x8a, 0xa9, 0x01, 0x55, 0x8e, 0xbb, 0x51, 0x73, 0xdc, 0x2a,
-	0x74, 0xd1, 0x8f, 0x29, 0xea, 0x5c, 0x2d, 0x6c, 0x67, 0x1f, 0x7a, 0x56, 0xfe, 0x4d, 0x16, 0x61,
-	0xee, 0xe0, 0xf8, 0x78, 0xe5, 0x16, 0xe9, 0xc0, 0xe2, 0xf3, 0xd3, 0xc3, 0x93, 0x67, 0x27, 0x4f,
-	0x57, 0x1c, 0x6c, 0x3c, 0x3e, 0x7e, 0x7e, 0x86, 0x8d, 0xc6, 0xfe, 0x3f, 0x6d, 0x42, 0x3b, 0x8f,
-	0x1e, 0xc9, 0xcf, 0xa1, 0x67, 0x65, 0xdb, 0x64, 0x4b, 0x1d, 0x61, 0x5d, 0xfa, 0x3e, 0xb8, 0x5b,
-	0xdf, 0xa9, 0x8e, 0xea, 0xfd, 0x5f, 0xfe, 0xf6, 0x3f, 0xfe, 0xa6, 0xd1, 0x27, 0x1b, 0x7b, 0x57,
-	0x9f, 0xec, 0xa9, 0x74, 0x7a, 0x4f, 0x54, 0x8d, 0x65, 0x91, 0xfa, 0x15, 0x2c, 0xd9, 0xd9, 0x38,
-	0xb9, 0x6b, 0x3b, 0xeb, 0xd2, 0x6c, 0xef, 0xdd, 0xd0, 0xab, 0xa6, 0xbb, 0x2b, 0xa6, 0xdb, 0x20,
-	0xeb, 0xe6, 0x74, 0x79, 0x54, 0xc7, 0xc4, 0xb3, 0x82, 0xf9, 0x51, 0x07, 0xd1, 0xfc, 0xea, 0x3f,
-	0xf6, 0x18, 0xdc, 0xa9, 0x7e, 0xc0, 0xa1, 0xbe, 0xf8, 0xa0, 0x7d, 0x31, 0x15, 0x21, 0x2b, 0x38,
-	0x95, 0xf9, 0x4d, 0x07, 0xf9, 0x19, 0xb4, 0xf3, 0x07, 0x6c, 0xb2, 0x69, 0x3c, 0xd7, 0x9b, 0x4f,
-	0xe2, 0x83, 0x7e, 0xb5, 0x43, 0x6d, 0x62, 0x4b, 0x70, 0xbe, 0x4d, 0x2b, 0x9c, 0x3f, 0x77, 0x76,
-	0xc8, 0x31, 0xdc, 0x56, 0x1e, 0xe3, 0x9c, 0xfd, 0x6f, 0x76, 0x52, 0xf3, 0x29, 0xca, 0x43, 0x87,
-	0x7c, 0x01, 0x2d, 0xfd, 0xa6, 0x4f, 0x36, 0xea, 0x3f, 0x2c, 0x18, 0x6c, 0x56, 0x70, 0x65, 0x28,
-	0x07, 0x00, 0xc5, 0x13, 0x36, 0xe9, 0xdf, 0xf4, 0xd2, 0x9e, 0x0b, 0xb1, 0xe6, 0xbd, 0x7b, 0x2c,
-	0x5e, 0xf0, 0xed, 0x17, 0x72, 0xf2, 0x41, 0x41, 0x5f, 0xfb, 0x76, 0xfe, 0x2d, 0x0c, 0xe9, 0x86,
-	0x90, 0xdd, 0x0a, 0x59, 0x42, 0xd9, 0x45, 0xec, 0x5a, 0x67, 0xd7, 0x3f, 0x85, 0x8e, 0xf1, 0xce,
-	0x4d, 0x8c, 0x7a, 0x66, 0xe9, 0x49, 0x7d, 0x30, 0xa8, 0xeb, 0x52, 0xdc, 0xd7, 0x05, 0xf7, 0x25,
-	0xda, 0x46, 0xee, 0xe2, 0x4d, 0x07, 0x8f, 0xe4, 0xc7, 0x68, 0x3c, 0xea, 0xe1, 0x8b, 0x14, 0x6f,
-	0xf0, 0xf6, 0xf3, 0x58, 0x7e, 0xde, 0x95, 0x37, 0x32, 0xba, 0x2a, 0xb8, 0x76, 0x48, 0xc1, 0x95,
-	0xfc, 0x08, 0x16, 0xd5, 0x03, 0x18, 0xb9, 0x5d, 0x9c, 0xab, 0x91, 0x6b, 0x0d, 0x36, 0xca, 0xb0,
-	0x62, 0xb6, 0x26, 0x98, 0xf5, 0x48, 0x07, 0x99, 0x8d, 0x19, 0x0f, 0x90, 0x47, 0x08, 0xcb, 0x76,
-	0x49, 0x32, 0xcb, 0xcd, 0xac, 0xb6, 0xce, 0x9a, 0x9b, 0x59, 0x7d, 0x11, 0xd4, 0x36, 0x33, 0x6d,
-	0x5e, 0x7b, 0xba, 0x84, 0xfc, 0xc7, 0xd0, 0x35, 0x5f, 0x5b, 0xc9, 0xc0, 0xd8, 0x79, 0xe9, 0x65,
-	0x76, 0xb0, 0x55, 0xdb, 0x67, 0x8b, 0x9b, 0x74, 0xcd, 0x69, 0xc8, 0x4f, 0x61, 0xd9, 0x28, 0xf8,
-	0x9f, 0xcd, 0xa2, 0x51, 0x7e, 0x9c, 0xd5, 0x87, 0x80, 0x41, 0x5d, 0xec, 0x47, 0x37, 0x05, 0xe3,
-	0x55, 0x6a, 0x31, 0xc6, 0xa3, 0x7c, 0x0c, 0x1d, 0x83, 0xc7, 0xb7, 0xf1, 0xdd, 0x34, 0xba, 0xcc,
-	0xe2, 0xfb, 0x43, 0x87, 0xfc, 0xc6, 0x81, 0xae, 0xf9, 0x7c, 0x44, 0xac, 0x6c, 0xa6, 0xc4, 0xa7,
-	0x6f, 0xf6, 0x99, 0x8c, 0xe8, 0xd7, 0x62, 0x91, 0xa7, 0x3b, 0x27, 0x96, 0x90, 0xdf, 0x58, 0x05,
-	0xe1, 0x5d, 0xf3, 0x63, 0xa5, 0xb7, 0xe5, 0x4e, 0xf3, 0xa1, 0xe4, 0xed, 0xde, 0x1b, 0xf1, 0xaa,
-	0xf4, 0xf6, 0xa1, 0x43, 0x3e, 0x97, 0xdf, 0x9c, 0xe9, 0x40, 0x83, 0x18, 0x06, 0x5e, 0x16, 0x9b,
-	0xf9, 0x25, 0xd7, 0xb6, 0xf3, 0xd0, 0x21, 0x7f, 0x22, 0xbf, 0x53, 0x52, 0x63, 0x85, 0xf4, 0xdf,
-	0x75, 0x3c, 0xfd, 0x50, 0xec, 0xe8, 0x7d, 0x7a, 0xc7, 0xda, 0x51, 0xd9, 0xc3, 0x9d, 0x02, 0x14,
-	0x51, 0x23, 0x29, 0x85, 0x50, 0xb9, 0xed, 0x57, 0x03, 0x4b, 0xfb, 0x54, 0x75, 0xa4, 0x85, 0x1c,
-	0x7f, 0x2e, 0x15, 0x52, 0xd1, 0x67, 0xf9, 0xb1, 0x56, 0xa3, 0xbf, 0xc1, 0xa0, 0xae, 0x4b, 0xf1,
-	0xff, 0x9e, 0xe0, 0xff, 0x1e, 0xd9, 0x32, 0xf9, 0xef, 0xbd, 0x31, 0xa3, 0xc5, 0xb7, 0xe4, 0x6b,
-	0xe8, 0x1d, 0xc7, 0xf1, 0xab, 0x69, 0x92, 0x27, 0x03, 0x76, 0xfc, 0x83, 0x11, 0xeb, 0xa0, 0xb4,
-	0x29, 0x7a, 0x5f, 0x70, 0xde, 0x22, 0x77, 0x6c, 0xce, 0x45, 0x0c, 0xfb, 0x96, 0x78, 0xb0, 0x9a,
-	0xfb, 0xfd, 0x7c, 0x23, 0x03, 0x9b, 0x8f, 0x19, 0x4a, 0x56, 0xe6, 0xb0, 0x6e, 0xe2, 0x7c, 0x8e,
-	0x4c, 0xf3, 0x7c, 0xe8, 0x90, 0x53, 0xe8, 0x3e, 0x61, 0xa3, 0xd8, 0x67, 0x2a, 0x66, 0x59, 0x2b,
-	0x56, 0x9e, 0xc7, 0x3a, 0x83, 0x9e, 0x05, 0xda, 0x9e, 0x20, 0xf1, 0x66, 0x29, 0xfb, 0x66, 0xef,
-	0x8d, 0x0a, 0x86, 0xde, 0x6a, 0x4f, 0xa0, 0x03, 0x38, 0xcb, 0x13, 0x94, 0x22, 0x3e, 0xcb, 0x13,
-	0x54, 0x22, 0x3e, 0xcb, 0x13, 0xe8, 0x00, 0x92, 0x84, 0x18, 0x07, 0x96, 0x82, 0xc4, 0xfc, 0xf6,
-	0xb8, 0x29, 0xb4, 0x1c, 0xdc, 0xbb, 0x99, 0xc0, 0x9e, 0x6d, 0xc7, 0x9e, 0xed, 0x0c, 0x7a, 0x4f,
-	0x98, 0x14, 0x96, 0x2c, 0xc3, 0x0d, 0x6c, 0xd7, 0x62, 0x96, 0xec, 0xca, 0x6e, 0x47, 0xf4, 0xd9,
-	0x8e, 0x5e, 0xd4, 0xc0, 0xc8, 0xcf, 0xa0, 0xf3, 0x94, 0x71, 0x5d, 0x77, 0xcb, 0xef, 0xe0, 0x52,
-	0x21, 0x6e, 0x50, 0x53, 0xb6, 0xa3, 0xf7, 0x04, 0xb7, 0x01, 0xe9, 0xe7, 0xdc, 0xf6, 0x98, 0x3f,
-	0x66, 0xd2, 0x09, 0x0c, 0x03, 0xff, 0x2d, 0xf9, 0x89, 0x60, 0x9e, 0x97, 0xcf, 0x37, 0x8c, 0x6a,
-	0x8e, 0xc9, 0x7c, 0xb9, 0x84, 0xd7, 0x71, 0xc6, 0x1c, 0x7f, 0xef, 0x8d, 0xaa, 0x62, 0x23, 0x67,
-	0xf8, 0xf1, 0x94, 0xa5, 0x33, 0xf9, 0xb0, 0xb0, 0x66, 0x7d, 0x9e, 0xa9, 0xb8, 0x5a, 0xdf, 0x6c,
-	0xd2, 0x07, 0x82, 0xe5, 0x7d, 0xf2, 0x41, 0xc1, 0x52, 0x7c, 0xbd, 0x59, 0xf0, 0xdc, 0x7b, 0xe3,
-	0x4d, 0xf8, 0x5b, 0xf2, 0x52, 0x7c, 0x0d, 0x62, 0x56, 0x11, 0x8b, 0xdb, 0xbe, 0x5c, 0x70, 0xcc,
-	0xc5, 0x62, 0x74, 0xd9, 0x11, 0x80, 0x9c, 0x49, 0xdc, 0x81, 0x2f, 0x8d, 0xc0, 0xc9, 0xaa, 0xa6,
-	0x6a, 0x7d, 0xb8, 0xb1, 0x68, 0x96, 0x3b, 0x85, 0x9a, 0xc2, 0x99, 0x8e, 0xa1, 0x64, 0x35, 0xc0,
-	0x88, 0xa1, 0xac, 0x72, 0x82, 0x11, 0x43, 0xd9, 0x65, 0x03, 0x8c, 0xa1, 0x8a, 0x14, 0x24, 0x8f,
-	0xa1, 0x2a, 0xd9, 0x4d, 0xee, 0xf6, 0xaa, 0xf9, 0xca, 0xf9, 0x82, 0xf8, 0x4a, 0xfb, 0x77, 0xfe,
-	0x27, 0x00, 0x00, 0xff, 0xff, 0xd8, 0xb5, 0x39, 0xb1, 0xd7, 0x2d, 0x00, 0x00,
+	// 9087 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0x03, 0xb5, 0x3d, 0xdb, 0x72, 0x5c, 0xc7,
+	0x71, 0xde, 0x0b, 0x08, 0x60, 0x16, 0xd7, 0x83, 0x0b, 0x17, 0x0b, 0x52, 0x14, 0x8f, 0x64, 0x49,
+	0xa6, 0x55, 0xa4, 0x44, 0xc9, 0x8a, 0x28, 0x5f, 0x64, 0x10, 0xa4, 0x44, 0x4a, 0xbc, 0xc0, 0x07,
+	0x14, 0x65, 0x27, 0x76, 0x36, 0x07, 0xbb, 0x07, 0xc0, 0x4a, 0xbb, 0x7b, 0x56, 0x7b, 0xce, 0x02,
+	0x84, 0x54, 0x8a, 0x53, 0x4e, 0x5e, 0x52, 0x4e, 0x9c, 0x07, 0xc7, 0xae, 0x3c, 0x39, 0x0f, 0xa9,
+	0x4a, 0x5e, 0xe2, 0x97, 0x54, 0x39, 0xae, 0x94, 0xfd, 0xe8, 0xa7, 0x5c, 0xaa, 0x5c, 0xe5, 0x1f,
+	0xc8, 0x43, 0x7e, 0x20, 0x1f, 0x90, 0x54, 0xba, 0x67, 0x7a, 0xae, 0x67, 0x76, 0x09, 0xd9, 0xcc,
+	0x13, 0x76, 0x7a, 0x7a, 0xe6, 0xcc, 0xf4, 0xf4, 0xf4, 0x74, 0xf7, 0x74, 0x0f, 0xd8, 0xec, 0x70,
+	0xd0, 0xba, 0x3c, 0x18, 0xa6, 0x79, 0x1a, 0x4c, 0x75, 0xfb, 0x50, 0x68, 0x9c, 0x3b, 0x48, 0xd3,
+	0x83, 0x6e, 0x72, 0x25, 0x1e, 0x74, 0xae, 0xc4, 0xfd, 0x7e, 0x9a, 0xc7, 0x79, 0x27, 0xed, 0x67,
+	0x02, 0x29, 0xfc, 0xef, 0x12, 0xab, 0x3d, 0x18, 0xc6, 0xfd, 0x2c, 0x6e, 0x21, 0x38, 0xa8, 0xb3,
+	0xe9, 0xfc, 0x51, 0xf3, 0x30, 0xce, 0x0e, 0xeb, 0xa5, 0xa7, 0x4b, 0x2f, 0xcc, 0x46, 0xb2, 0x18,
+	0xac, 0xb3, 0x33, 0x71, 0x2f, 0x1d, 0xf5, 0xf3, 0x7a, 0x19, 0x2a, 0x2a, 0x11, 0x95, 0x82, 0x17,
+	0xd9, 0x72, 0x7f, 0xd4, 0x6b, 0xb6, 0xd2, 0xfe, 0x7e, 0x67, 0xd8, 0x13, 0x9d, 0xd7, 0x2b, 0x80,
+	0x32, 0x15, 0x15, 0x2b, 0x82, 0xa7, 0x18, 0xdb, 0xeb, 0xa6, 0xad, 0x0f, 0xc5, 0x27, 0xaa, 0xfc,
+	0x13, 0x06, 0x24, 0x08, 0xd9, 0x1c, 0x95, 0x92, 0xce, 0xc1, 0x61, 0x5e, 0x9f, 0xe2, 0x1d, 0x59,
+	0x30, 0xec, 0x23, 0xef, 0xf4, 0x92, 0x66, 0x96, 0xc7, 0xbd, 0x41, 0xfd, 0x0c, 0x1f, 0x8d, 0x01,
+	0xe1, 0xf5, 0x30, 0xcd, 0x6e, 0x73, 0x3f, 0x49, 0xb2, 0xfa, 0x34, 0xd5, 0x2b, 0x48, 0x58, 0x67,
+	0xeb, 0x6f, 0x27, 0xb9, 0x31, 0xeb, 0x2c, 0x4a, 0x3e, 0x1a, 0x25, 0x59, 0x1e, 0xde, 0x61, 0x81,
+	0x01, 0xbe, 0x91, 0xe4, 0x71, 0xa7, 0x9b, 0x05, 0xaf, 0xb1, 0xb9, 0xdc, 0x40, 0x06, 0xc2, 0x54,
+	0x5e, 0xa8, 0x5d, 0x0d, 0x2e, 0x73, 0xfa, 0x5e, 0x36, 0x1a, 0x44, 0x16, 0x5e, 0xf8, 0x83, 0x32,
+	0xab, 0xed, 0x26, 0xfd, 0x36, 0xf5, 0x1e, 0x04, 0xac, 0xda, 0x86, 0xbf, 0x9c, 0xb0, 0x73, 0x11,
+	0xff, 0x1d, 0x5c, 0x60, 0x35, 0xfc, 0x0b, 0x23, 0x1f, 0x76, 0xfa, 0x07, 0x9c, 0xb4, 0x40, 0x10,
+	0x04, 0xed, 0x72, 0x48, 0xb0, 0xc4, 0x2a, 0x71, 0x2f, 0xe7, 0x04, 0xad, 0x44, 0xf8, 0x33, 0xb8,
+	0xc8, 0xe6, 0x06, 0xf1, 0x49, 0x2f, 0xe9, 0xe7, 0x9a, 0x88, 0x73, 0x51, 0x8d, 0x60, 0xb7, 0x90,
+	0x8a, 0x97, 0xd9, 0x8a, 0x89, 0x22, 0x7b, 0x9f, 0xe2, 0xbd, 0x2f, 0x1b, 0x98, 0xf4, 0x91, 0xe7,
+	0xd9, 0xa2, 0xc4, 0x1f, 0x8a, 0xc1, 0x72, 0xb2, 0xce, 0x46, 0x0b, 0x04, 0x96, 0x53, 0x38, 0xcf,
+	0x18, 0x90, 0xb0, 0x39, 0x18, 0x26, 0x59, 0x92, 0x73, 0xd2, 0xce, 0x46, 0xb3, 0x00, 0xd9, 0xe1,
+	0x00, 0xac, 0x96, 0xfd, 0x74, 0xda, 0xf5, 0x19, 0xa8, 0xae, 0x46, 0xb3, 0x04, 0xb9, 0xdd, 0x0e,
+	0xfb, 0x6c, 0x4e, 0xd0, 0x23, 0x1b, 0x00, 0x7d, 0x92, 0xe0, 0x12, 0x5b, 0x92, 0xe8, 0xd0, 0x63,
+	0xa7, 0x17, 0x1f, 0x24, 0x44, 0x9c, 0x02, 0x3c, 0xb8, 0xca, 0xe6, 0xd5, 0x10, 0xd3, 0x51, 0x9e,
+	0x70, 0x52, 0xd5, 0xae, 0xce, 0xd1, 0x2a, 0x44, 0x08, 0x8b, 0x6c, 0x94, 0xf0, 0x7b, 0x25, 0x36,
+	0xb7, 0x7d, 0x08, 0x4c, 0x9f, 0x74, 0x77, 0xd2, 0x0e, 0xf0, 0x2a, 0x70, 0xd7, 0xfe, 0xa8, 0xdf,
+	0x86, 0x29, 0x37, 0xf3, 0x47, 0x30, 0x42, 0xf1, 0x31, 0x0b, 0x86, 0x83, 0x32, 0xcb, 0x48, 0x3b,
+	0x5a, 0x96, 0x02, 0x1c, 0xfb, 0x83, 0x0f, 0x0d, 0x46, 0x30, 0xdd, 0x7e, 0x3b, 0x79, 0xc4, 0x57,
+	0x69, 0x3e, 0xb2, 0x60, 0xe1, 0xd7, 0xd8, 0xd2, 0x1d, 0x64, 0xdb, 0x3e, 0xb4, 0xdc, 0x6a, 0xb7,
+	0x81, 0x50, 0x19, 0xee, 0xa5, 0xc1, 0x68, 0xef, 0xc3, 0xe4, 0x84, 0x36, 0x19, 0x95, 0x90, 0x43,
+	0x0e, 0xd3, 0x2c, 0xa7, 0xef, 0xf1, 0xdf, 0xe1, 0xaf, 0x4b, 0x6c, 0x11, 0xa9, 0x76, 0x37, 0xee,
+	0x9f, 0xc8, 0x65, 0xb8, 0xc3, 0xe6, 0xb0, 0xab, 0x07, 0xe9, 0x96, 0xd8, 0x91, 0x82, 0x23, 0x5f,
+	0x20, 0x5a, 0x38, 0xd8, 0x97, 0x4d, 0xd4, 0x9b, 0xfd, 0x7c, 0x78, 0x12, 0x59, 0xad, 0x1b, 0x6f,
+	0xb2, 0xe5, 0x02, 0x0a, 0xf2, 0x9d, 0x1e, 0x1f, 0xfe, 0x0c, 0x56, 0xd9, 0xd4, 0x51, 0xdc, 0x1d,
+	0x25, 0xb4, 0xff, 0x45, 0xe1, 0x8d, 0xf2, 0xeb, 0x25, 0x60, 0xb7, 0x20, 0x3d, 0x4a, 0x86, 0xc3,
+	0x4e, 0x3b, 0x69, 0x1e, 0x1f, 0x76, 0xf2, 0xa4, 0xdb, 0xa1, 0x49, 0xcc, 0x44, 0x9e, 0x9a, 0xf0,
+	0x39, 0xb6, 0xa4, 0xc7, 0x48, 0xbc, 0x00, 0x53, 0x57, 0x4b, 0x02, 0x53, 0xc7, 0xdf, 0xc0, 0x2f,
+	0x1c, 0x6f, 0x1b, 0xd6, 0x2e, 0x33, 0x36, 0x51, 0x0c, 0x83, 0x95, 0x78, 0xf8, 0x7b, 0xac, 0x68,
+	0xf2, 0x8f, 0xab, 0x32, 0x76, 0x5c, 0xcf, 0xb3, 0x65, 0xe3, 0x7b, 0x13, 0x06, 0xf6, 0x93, 0x12,
+	0x5b, 0xbe, 0x97, 0x1c, 0xd3, 0x72, 0xca, 0xa1, 0xbd, 0x0e, 0x98, 0x27, 0x03, 0xc1, 0xc2, 0x0b,
+	0x57, 0x9f, 0xa5, 0xd5, 0x28, 0xe0, 0x5d, 0xa6, 0xe2, 0x03, 0xc0, 0x8d, 0x78, 0x8b, 0xf0, 0x3e,
+	0xab, 0x19, 0xc0, 0xe0, 0x2c, 0x5b, 0x79, 0xff, 0xf6, 0x83, 0x7b, 0x37, 0x77, 0x77, 0x9b, 0x3b,
+	0xef, 0x5d, 0x7f, 0xf7, 0xe6, 0xb7, 0x9a, 0xb7, 0xb6, 0x76, 0x6f, 0x2d, 0x7d, 0x0e, 0x26, 0x1a,
+	0x00, 0xf4, 0xc1, 0xcd, 0x1b, 0x16, 0xbc, 0x14, 0x2c, 0xb2, 0x9a, 0x09, 0x28, 0x87, 0x0d, 0x56,
+	0x87, 0xef, 0xbe, 0xdf, 0xc9, 0xfb, 0xd0, 0xa7, 0xfd, 0xf9, 0x10, 0xa8, 0x62, 0x8e, 0x89, 0xa6,
+	0x09, 0x82, 0x3f, 0x16, 0x20, 0x29, 0xf8, 0xa9, 0x18, 0xbe, 0xc7, 0x82, 0xed, 0x14, 0xf6, 0x50,
+	0x2b, 0xdf, 0x49, 0x92, 0xa1, 0x9c, 0xec, 0x17, 0x8d, 0x75, 0xa8, 0x5d, 0x3d, 0x4b, 0x93, 0x75,
+	0x39, 0x9d, 0x16, 0x08, 0x68, 0x38, 0x48, 0x86, 0x3d, 0x62, 0x09, 0xfe, 0x3b, 0xbc, 0xc2, 0x56,
+	0xac, 0x6e, 0xf5, 0x38, 0x06, 0x50, 0x6e, 0x12, 0xc5, 0xa7, 0x22, 0x59, 0x0c, 0xff, 0xa9, 0xc4,
+	0xaa, 0xb7, 0x1e, 0xdc, 0xd9, 0x0e, 0x1a, 0x6c, 0xa6, 0xd3, 0x6f, 0xa5, 0x3d, 0x14, 0x69, 0x25,
+	0xde, 0xa3, 0x2a, 0x8f, 0x65, 0x85, 0x73, 0x6c, 0x96, 0x4b, 0x42, 0x3c, 0x47, 0x38, 0x07, 0xcc,
+	0x45, 0x1a, 0x80, 0x67, 0x58, 0xf2, 0x68, 0xd0, 0x19, 0xf2, 0x43, 0x4a, 0x1e, 0x3d, 0x55, 0xbe,
+	0x99, 0x8b, 0x15, 0x28, 0x21, 0x86, 0xc9, 0x51, 0xda, 0x12, 0xc0, 0x76, 0xd2, 0x8d, 0x4f, 0xb8,
+	0x68, 0x9d, 0x8f, 0x0a, 0xf0, 0xf0, 0x97, 0x55, 0x36, 0xbf, 0x05, 0xe7, 0xc1, 0x51, 0x42, 0x82,
+	0x88, 0x8f, 0x90, 0x03, 0x68, 0xec, 0x54, 0x0a, 0x9e, 0x65, 0xf3, 0xc3, 0xa4, 0x97, 0xe6, 0x20,
+	0x5d, 0x85, 0x68, 0x10, 0x42, 0xc0, 0x06, 0x22, 0x56, 0x4b, 0x74, 0xd4, 0x1c, 0xa0, 0x48, 0xe3,
+	0x73, 0x01, 0x2c, 0x0b, 0x88, 0x44, 0x44, 0x00, 0x12, 0xb1, 0xca, 0x85, 0xb0, 0x2c, 0x22, 0xed,
+	0x5a, 0xf1, 0x20, 0x6e, 0x75, 0x72, 0x31, 0xe6, 0x4a, 0xa4, 0xca, 0xd8, 0x37, 0x50, 0x03, 0x4e,
+	0xc9, 0xbd, 0xb8, 0x1b, 0xf7, 0x5b, 0x09, 0x1d, 0xad, 0x36, 0x30, 0x78, 0x8e, 0x2d, 0xd0, 0x90,
+	0x24, 0x9a, 0x38, 0x61, 0x1d, 0x28, 0xd2, 0x74, 0x04, 0x0b, 0x9a, 0xe7, 0xdd, 0xa4, 0xad, 0x50,
+	0x67, 0x38, 0x6a, 0xb1, 0x22, 0x78, 0x89, 0xad, 0x88, 0x13, 0x3a, 0x8b, 0xf3, 0x34, 0x3b, 0xec,
+	0x64, 0xcd, 0x0c, 0xe4, 0x78, 0x7d, 0x96, 0xe3, 0xfb, 0xaa, 0x60, 0xb7, 0x9d, 0x75, 0xc0, 0xc3,
+	0xa4, 0x95, 0x00, 0x25, 0xdb, 0x75, 0xc6, 0x5b, 0x8d, 0xab, 0x0e, 0x9e, 0x66, 0x35, 0x54, 0x4c,
+	0x46, 0x83, 0x76, 0x9c, 0x83, 0x82, 0x50, 0xe3, 0x14, 0x32, 0x41, 0xc1, 0xcb, 0x70, 0xd8, 0x24,
+	0x42, 0xd6, 0x1f, 0xe6, 0xdd, 0x56, 0x56, 0x9f, 0xe3, 0x02, 0xb6, 0x46, 0x5c, 0x8e, 0x5c, 0x18,
+	0xd9, 0x18, 0xc8, 0x14, 0xd9, 0xe1, 0x28, 0x6f, 0xa7, 0xc7, 0xfd, 0x26, 0xd5, 0xd4, 0xe7, 0xf9,
+	0x02, 0x17, 0xe0, 0xc1, 0x0b, 0x6c, 0x11, 0x8e, 0x88, 0x83, 0x14, 0x5b, 0xef, 0x0f, 0xd3, 0x8f,
+	0x93, 0x7e, 0x7d, 0x81, 0xa3, 0xba, 0xe0, 0x70, 0x8d, 0xad, 0xdc, 0x01, 0xc9, 0x44, 0xbc, 0xa3,
+	0xb6, 0xf0, 0x2d, 0xb6, 0x6a, 0x83, 0x69, 0xf3, 0xbc, 0x04, 0xab, 0x4b, 0x30, 0x98, 0x16, 0x0e,
+	0x79, 0x95, 0x86, 0x6c, 0xf1, 0x60, 0xa4, 0xb0, 0xc2, 0x1f, 0x57, 0x58, 0x15, 0xf7, 0x1f, 0xdf,
+	0x77, 0xa3, 0xbd, 0xa6, 0x96, 0xf9, 0xb2, 0x68, 0xee, 0xc8, 0xb2, 0xb5, 0x23, 0x4d, 0x99, 0x51,
+	0xb1, 0x64, 0x06, 0x57, 0xf3, 0x4e, 0x80, 0x92, 0x62, 0x15, 0x05, 0x0f, 0x1a, 0x10, 0x5d, 0x0f,
+	0x8b, 0x72, 0xc4, 0x19, 0x51, 0xd5, 0x23, 0x04, 0xd9, 0x14, 0xd6, 0x4d, 0xb4, 0x16, 0x5c, 0xa8,
+	0xca, 0xb2, 0x8e, 0xb7, 0x9c, 0xd6, 0x75, 0xbc, 0x1d, 0x8c, 0xa8, 0xd3, 0xdf, 0x83, 0x1d, 0x2f,
+	0xb4, 0x8f, 0x99, 0x48, 0x16, 0x51, 0x00, 0x0c, 0xf8, 0xd9, 0x0d, 0x7a, 0x22, 0xb1, 0x95, 0x06,
+	0xe0, 0xa6, 0x1c, 0x0d, 0x78, 0x15, 0xf2, 0x4e, 0x29, 0xa2, 0x12, 0x68, 0x1d, 0xab, 0xb8, 0xbc,
+	0xd0, 0x79, 0x96, 0x76, 0x47, 0x7c, 0x5f, 0x73, 0xac, 0x1a, 0xef, 0xc0, 0x5b, 0x87, 0xdb, 0xe8,
+	0xa3, 0x51, 0xdc, 0x85, 0x1d, 0xd5, 0xcc, 0x5a, 0xe9, 0x30, 0x01, 0xe6, 0xc1, 0x2e, 0x6d, 0x20,
+	0x52, 0x60, 0x98, 0x80, 0x96, 0xc0, 0x85, 0x05, 0xe7, 0x14, 0x50, 0x52, 0x35, 0x24, 0x0c, 0x50,
+	0x6d, 0xc8, 0xb8, 0x6c, 0x54, 0xcb, 0xfe, 0x1a, 0x5b, 0x36, 0x60, 0xb4, 0xe6, 0x17, 0xd9, 0x14,
+	0xae, 0x87, 0x54, 0x4b, 0x25, 0x8f, 0x72, 0xa1, 0x2a, 0x6a, 0xc2, 0x25, 0xb6, 0x00, 0x0a, 0xef,
+	0xed, 0xfe, 0x7e, 0x2a, 0x7b, 0xfa, 0xe1, 0x14, 0x5b, 0x54, 0x20, 0xea, 0x08, 0xb8, 0x12, 0x8e,
+	0xc3, 0x7e, 0x8e, 0x63, 0xb4, 0xb4, 0x13, 0x17, 0x8c, 0x9a, 0x00, 0x4c, 0x25, 0xce, 0x48, 0x44,
+	0x89, 0x02, 0xd2, 0x0a, 0xf7, 0x90, 0xdc, 0x16, 0x8a, 0x11, 0x85, 0x52, 0xe4, 0xad, 0xc3, 0x6d,
+	0x8f, 0x70, 0x21, 0x02, 0x75, 0x13, 0x21, 0x7a, 0x7d, 0x55, 0xb8, 0x8e, 0xa2, 0x27, 0x9c, 0xb2,
+	0x90, 0xba, 0x1a, 0x50, 0x30, 0x1f, 0xce, 0x08, 0x85, 0xcc, 0x35, 0x1f, 0x0c, 0x13, 0x64, 0xa6,
+	0x60, 0x82, 0x00, 0x1d, 0xb2, 0x13, 0x90, 0x49, 0xed, 0x66, 0x9e, 0xe2, 0x77, 0x3b, 0x7d, 0xce,
+	0x2f, 0xb0, 0x3b, 0x1d, 0x30, 0x37, 0x96, 0x80, 0x9a, 0x7d, 0x50, 0x85, 0x99, 0xe0, 0x36, 0x2a,
+	0x4a, 0x5a, 0xc0, 0x4a, 0x0f, 0xe1, 0x18, 0xc8, 0xa1, 0x91, 0x90, 0x23, 0x42, 0xd6, 0x78, 0xeb,
+	0x82, 0xeb, 0xec, 0x1c, 0xc2, 0xf9, 0xa9, 0x04, 0x87, 0x4e, 0x9a, 0x8d, 0x86, 0x09, 0x30, 0xd7,
+	0x07, 0x09, 0x99, 0x1d, 0x73, 0xbc, 0xed, 0x44, 0x1c, 0x94, 0x42, 0x62, 0x26, 0xad, 0xb8, 0x75,
+	0x98, 0x34, 0x41, 0xb3, 0xc9, 0x38, 0x6f, 0x55, 0xa3, 0x02, 0x1c, 0xb5, 0x23, 0x13, 0xd6, 0xeb,
+	0x64, 0x19, 0x48, 0xc3, 0x05, 0x8e, 0xed, 0xa9, 0x91, 0x73, 0xca, 0x46, 0xd9, 0x00, 0x3e, 0x07,
+	0xc3, 0x06, 0x0b, 0x72, 0x0f, 0x5a, 0x2c, 0xea, 0x39, 0xb9, 0x75, 0xd2, 0x38, 0xec, 0xc5, 0xd9,
+	0x87, 0xba, 0xc1, 0x12, 0x6f, 0x50, 0xac, 0x08, 0x3f, 0xe6, 0x9a, 0x86, 0xb2, 0x16, 0xdf, 0xe3,
+	0xd2, 0x38, 0xd8, 0x64, 0xb3, 0x62, 0x34, 0xd9, 0x61, 0x4c, 0x1a, 0xfb, 0x0c, 0x07, 0xec, 0x1e,
+	0xc6, 0x68, 0x0c, 0x59, 0x0b, 0x2e, 0x24, 0x54, 0x8d, 0xc3, 0x6e, 0x89, 0xf5, 0x7e, 0x96, 0x2d,
+	0x48, 0x3b, 0x34, 0x6b, 0x76, 0x93, 0xfd, 0x5c, 0xaa, 0xe9, 0x00, 0xc5, 0xcf, 0x65, 0x77, 0x00,
+	0x16, 0xde, 0x63, 0xcb, 0x24, 0x1d, 0xef, 0x03, 0x97, 0xd2, 0xa7, 0xaf, 0xb9, 0xa7, 0xad, 0xd0,
+	0x76, 0x56, 0x68, 0x8f, 0x99, 0xb6, 0x85, 0x73, 0x04, 0x87, 0x11, 0xcc, 0x45, 0x00, 0xb6, 0xbb,
+	0x69, 0x96, 0x50, 0x87, 0xc0, 0x9f, 0x2d, 0x28, 0xba, 0x06, 0x88, 0x09, 0x43, 0xae, 0xca, 0x46,
+	0xad, 0x16, 0x4a, 0x55, 0xa1, 0x2f, 0xc9, 0x62, 0xf8, 0x9f, 0x25, 0xd0, 0x99, 0xb0, 0x37, 0x29,
+	0xc7, 0x95, 0xe2, 0x79, 0xfa, 0x61, 0xce, 0xb5, 0x4c, 0x83, 0xe8, 0x3c, 0x99, 0xd2, 0xdd, 0x4e,
+	0xaf, 0x23, 0x55, 0xa6, 0x59, 0x84, 0xdc, 0x41, 0x00, 0x6e, 0xf4, 0xfd, 0x74, 0x08, 0xe7, 0xb6,
+	0xd0, 0x99, 0x45, 0x01, 0xd4, 0xd3, 0xe9, 0xf6, 0xf0, 0xa4, 0x39, 0x1c, 0xf5, 0xf9, 0x46, 0x05,
+	0x15, 0x06, 0x8a, 0xd1, 0xa8, 0x8f, 0xc6, 0x6c, 0x1e, 0x0f, 0x0f, 0x92, 0x9c, 0x13, 0x9b, 0x6c,
+	0x77, 0x26, 0x40, 0x48, 0x69, 0x38, 0x79, 0xe7, 0x50, 0x54, 0x83, 0xfe, 0xd7, 0x44, 0x61, 0x2f,
+	0x6d, 0x77, 0x80, 0xed, 0x24, 0xc3, 0xeb, 0x00, 0x09, 0xff, 0xbc, 0x0c, 0xeb, 0x80, 0x53, 0xdc,
+	0x05, 0x39, 0x38, 0xca, 0x88, 0x6c, 0x5f, 0x81, 0x09, 0x22, 0x50, 0x9d, 0xac, 0x62, 0x82, 0xab,
+	0x4a, 0xd6, 0x71, 0xa8, 0x40, 0xbe, 0xf5, 0xb9, 0xc8, 0x46, 0x0e, 0xde, 0x04, 0xa2, 0x1b, 0x6c,
+	0x45, 0x96, 0xe3, 0x86, 0xa4, 0x4e, 0x81, 0xe3, 0xa0, 0x07, 0xab, 0x41, 0xf0, 0x65, 0xc6, 0xb8,
+	0xfe, 0xc4, 0xbb, 0xe5, 0xb4, 0x30, 0x9a, 0x17, 0x16, 0x19, 0x9a, 0x1b, 0xe8, 0xb0, 0xcd, 0x2c,
+	0x6a, 0x69, 0xc7, 0x01, 0x6f, 0x72, 0x83, 0x53, 0x0e, 0x9a, 0x48, 0xa4, 0xeb, 0x33, 0x78, 0x14,
+	0x61, 0x3f, 0xe1, 0xdb, 0x6c, 0xde, 0x9a, 0x99, 0x65, 0x8a, 0xcc, 0x09, 0x53, 0xa4, 0x60, 0x82,
+	0x96, 0x3d, 0x26, 0xe8, 0xaf, 0xcb, 0x2c, 0x40, 0xae, 0x76, 0xd8, 0x06, 0x34, 0x39, 0x5a, 0x2e,
+	0x5b, 0xe3, 0x76, 0xa0, 0x5c, 0x5f, 0x4a, 0xdb, 0x96, 0x5e, 0x3a, 0x17, 0x99, 0x20, 0x14, 0x25,
+	0x46, 0x51, 0xba, 0x1b, 0x84, 0x4e, 0xe0, 0xa9, 0x41, 0x51, 0x22, 0x94, 0x4a, 0x69, 0x51, 0x93,
+	0xce, 0x5e, 0x15, 0xc7, 0xaa, 0xaf, 0x0e, 0x8f, 0xfd, 0xc1, 0x08, 0x7d, 0x19, 0x71, 0x2e, 0x35,
+	0x57, 0x59, 0x96, 0x87, 0x02, 0xdf, 0xe2, 0x24, 0xf3, 0x35, 0x20, 0x78, 0x95, 0xad, 0x91, 0x6e,
+	0xea, 0x7c, 0x4e, 0x68, 0x0f, 0xfe, 0x4a, 0xec, 0xf3, 0xe3, 0x64, 0x98, 0x0a, 0x56, 0x16, 0xca,
+	0x84, 0x06, 0x84, 0xbf, 0x29, 0xb1, 0x25, 0x24, 0xa9, 0xc5, 0xa6, 0x6f, 0x30, 0xbe, 0xbb, 0x4e,
+	0xc9, 0xa5, 0x16, 0xee, 0xef, 0xce, 0xa4, 0xaf, 0xb3, 0x59, 0xde, 0x61, 0x0a, 0x3d, 0x12, 0x8f,
+	0xd6, 0x6d, 0x1e, 0xd5, 0x82, 0x0d, 0x1a, 0x6b, 0x64, 0x83, 0xe3, 0x6e, 0xb2, 0x35, 0x1a, 0xa5,
+	0xc3, 0x2a, 0x2f, 0xb2, 0x33, 0x19, 0x9f, 0x29, 0x19, 0xb7, 0xab, 0x76, 0xcf, 0x82, 0x0a, 0x11,
+	0xe1, 0x84, 0xdf, 0xaf, 0xb0, 0x75, 0xb7, 0x1f, 0x52, 0x32, 0xbe, 0xc9, 0x96, 0x0a, 0x0a, 0x82,
+	0x50, 0x5c, 0x5e, 0xb4, 0xc9, 0xe4, 0x34, 0x74, 0xc1, 0x85, 0x5e, 0x1a, 0x3f, 0x2e, 0xb3, 0x05,
+	0x1b, 0x09, 0xf7, 0x86, 0x52, 0x5d, 0xb4, 0x3a, 0x63, 0xc1, 0x8a, 0x06, 0x55, 0xd9, 0x67, 0x50,
+	0x99, 0x66, 0x53, 0xe5, 0x71, 0x66, 0x53, 0xf5, 0x74, 0x66, 0xd3, 0x94, 0xd7, 0x6c, 0x72, 0x4f,
+	0x08, 0xe1, 0x87, 0xb3, 0x4f, 0x08, 0xbd, 0x1a, 0xd3, 0xa7, 0x58, 0x8d, 0x0d, 0x76, 0xf6, 0x26,
+	0xa8, 0x0a, 0x43, 0x6e, 0x2e, 0x5c, 0x8f, 0x5b, 0x1f, 0x8e, 0x06, 0x52, 0x0d, 0xbc, 0x2e, 0x0e,
+	0x29, 0x01, 0xdc, 0xed, 0xc7, 0x83, 0xec, 0x30, 0xe5, 0x1e, 0xdd, 0xde, 0xa8, 0x9b, 0x77, 0x38,
+	0x6d, 0x61, 0x60, 0x58, 0x49, 0x32, 0xa7, 0x58, 0x11, 0xfe, 0x0f, 0x1e, 0x4a, 0xe2, 0xc3, 0xb2,
+	0x73, 0xfc, 0x58, 0x91, 0xb0, 0x25, 0x1f, 0x61, 0x4f, 0x67, 0xf5, 0x4e, 0x22, 0xff, 0xba, 0x22,
+	0x86, 0xf0, 0x26, 0x53, 0x89, 0x9b, 0x2d, 0xa0, 0x56, 0x74, 0x93, 0x1e, 0xf9, 0x3d, 0x65, 0x11,
+	0x15, 0x3c, 0x30, 0x16, 0xd0, 0xff, 0x73, 0xd2, 0x14, 0xbe, 0x5a, 0xa2, 0xb2, 0x0b, 0xe6, 0x8b,
+	0x41, 0xc3, 0xe5, 0x9e, 0x9d, 0x69, 0x5a, 0x0c, 0x03, 0x06, 0x07, 0x7d, 0xfd, 0x61, 0x32, 0xec,
+	0xec, 0x9f, 0x98, 0xe4, 0x25, 0x6e, 0x7f, 0xcd, 0xb0, 0xc7, 0x04, 0x97, 0x37, 0xec, 0xa5, 0x32,
+	0x29, 0x66, 0x58, 0x65, 0x7b, 0xac, 0x0e, 0x7d, 0xe4, 0x60, 0x27, 0x14, 0xd6, 0xec, 0xb3, 0xad,
+	0x0e, 0x52, 0x41, 0x9e, 0x3e, 0xa4, 0x4c, 0x50, 0x31, 0xdc, 0x65, 0x1b, 0x9e, 0x6f, 0xfc, 0x8e,
+	0x03, 0xbf, 0xc1, 0xce, 0xdd, 0xee, 0x49, 0x5e, 0xe3, 0xdb, 0x57, 0x10, 0x54, 0x0e, 0x9e, 0x2f,
+	0x37, 0xd1, 0xf8, 0x83, 0x0c, 0x08, 0x2f, 0x06, 0x6e, 0x03, 0xe1, 0xe0, 0x3b, 0x3f, 0xa6, 0x17,
+	0x1a, 0x1e, 0x6c, 0x26, 0x8b, 0x8d, 0xc4, 0x20, 0x67, 0x23, 0x07, 0x1a, 0x5e, 0x63, 0xab, 0xef,
+	0xc7, 0xdd, 0x6e, 0x92, 0x5f, 0x17, 0xbb, 0x4b, 0x0e, 0x03, 0xb4, 0xc6, 0x63, 0xe1, 0x1b, 0x6b,
+	0xa6, 0xfd, 0xee, 0x09, 0x79, 0x62, 0x6a, 0x04, 0xbb, 0x0f, 0xa0, 0xf0, 0x65, 0xb6, 0xe6, 0x34,
+	0xd5, 0x0e, 0x2a, 0xb9, 0x83, 0x4b, 0xdc, 0xb0, 0x93, 0xc5, 0xf0, 0x2c, 0x5b, 0x53, 0xd4, 0x31,
+	0x3f, 0x17, 0x5e, 0x65, 0xeb, 0x6e, 0x85, 0xbf, 0xb3, 0x8a, 0xee, 0xec, 0x1a, 0x9b, 0x13, 0x3e,
+	0x6d, 0x1a, 0xf2, 0x59, 0xd7, 0x3e, 0x47, 0x9f, 0xf1, 0xbb, 0xc9, 0x89, 0xbc, 0x20, 0x28, 0xab,
+	0x0b, 0x82, 0xf0, 0xbb, 0xac, 0x72, 0x2b, 0x1d, 0x98, 0x4e, 0xa0, 0x92, 0xed, 0x04, 0xa2, 0xad,
+	0xd9, 0x54, 0x7b, 0x4a, 0x34, 0xb6, 0x81, 0x48, 0x64, 0xe8, 0x0d, 0xad, 0x1d, 0x50, 0xfb, 0x8e,
+	0xe3, 0x61, 0x9b, 0xb6, 0x9e, 0x03, 0xc5, 0x01, 0xec, 0x27, 0x52, 0xea, 0xe1, 0xcf, 0xf0, 0xaf,
+	0x4a, 0x6c, 0x8a, 0x0f, 0x1e, 0xb7, 0x9a, 0xf0, 0xc2, 0x08, 0x2d, 0x13, 0x9d, 0x6f, 0x25, 0x7e,
+	0x3c, 0xbb, 0x60, 0xe7, 0xd2, 0xa6, 0xec, 0x5e, 0xda, 0xe0, 0x71, 0x2c, 0x4a, 0xfa, 0x36, 0x44,
+	0x03, 0xa0, 0x75, 0xf5, 0x30, 0x1d, 0xa0, 0x08, 0x40, 0x5e, 0x65, 0xd2, 0x4f, 0x93, 0x0e, 0x22,
+	0x0e, 0x0f, 0x2f, 0xb1, 0xc5, 0x7b, 0xa0, 0x86, 0x18, 0x26, 0xf0, 0x58, 0x82, 0x86, 0x7f, 0x52,
+	0x62, 0x33, 0x12, 0x19, 0x26, 0x50, 0x45, 0xfd, 0xc5, 0x39, 0xca, 0x95, 0x9b, 0x13, 0xf1, 0x22,
+	0x8e, 0x81, 0xb2, 0x82, 0xab, 0x1c, 0x72, 0xdb, 0x94, 0x95, 0x91, 0xa1, 0x8d, 0x57, 0xd4, 0xb8,
+	0xf8, 0x98, 0x1d, 0x69, 0xe6, 0x40, 0xc3, 0x4f, 0xd8, 0xbc, 0xf5, 0x09, 0x54, 0xc1, 0xba, 0x71,
+	0x96, 0x93, 0x83, 0x8a, 0x68, 0x68, 0x82, 0x4c, 0xff, 0x4d, 0xb9, 0xe0, 0xbf, 0x19, 0xe3, 0xa5,
+	0x51, 0x76, 0x7c, 0xd5, 0xb0, 0xe3, 0xc3, 0x9f, 0x96, 0xd8, 0x3c, 0xae, 0x1e, 0x7c, 0x7b, 0x27,
+	0xed, 0x76, 0x5a, 0x27, 0x7c, 0x15, 0xe5, 0x42, 0xa1, 0x5f, 0x33, 0x8f, 0xd5, 0x2a, 0xda, 0x60,
+	0x14, 0xd4, 0xbd, 0x4e, 0x9f, 0x1b, 0xb4, 0xb4, 0x86, 0xaa, 0x8c, 0x5c, 0x87, 0x77, 0x47, 0x7b,
+	0x31, 0xa8, 0xe6, 0x3d, 0xd4, 0xe2, 0xc4, 0xdc, 0x6d, 0x20, 0x7a, 0x04, 0x10, 0x30, 0x84, 0x39,
+	0x81, 0xe1, 0xd9, 0xed, 0x76, 0x04, 0xae, 0xe0, 0x2e, 0x5f, 0x55, 0xf8, 0x8b, 0x32, 0xab, 0xd1,
+	0xf6, 0xba, 0xd9, 0x3e, 0xe0, 0x9e, 0x15, 0x29, 0x06, 0x14, 0xeb, 0x1b, 0x10, 0x59, 0x6f, 0x1d,
+	0xf7, 0x06, 0xc4, 0xa5, 0x75, 0xa5, 0x48, 0x6b, 0x54, 0x37, 0x61, 0x55, 0x5e, 0xc6, 0xe3, 0x89,
+	0x68, 0xa7, 0x01, 0xb2, 0xf6, 0x2a, 0xaf, 0x9d, 0xd2, 0xb5, 0x1c, 0x60, 0x1d, 0x65, 0x67, 0x9c,
+	0xa3, 0xec, 0x75, 0x60, 0x21, 0xd1, 0x0d, 0xa7, 0x3b, 0x3f, 0x6e, 0x34, 0xd3, 0x59, 0x6b, 0x12,
+	0x59, 0x98, 0xb2, 0xe5, 0x55, 0xd9, 0x72, 0xe6, 0x71, 0x2d, 0x25, 0x26, 0x7a, 0x18, 0x89, 0x78,
+	0x6f, 0x0f, 0xe3, 0xc1, 0xa1, 0x14, 0x59, 0x6d, 0x75, 0x73, 0xc6, 0xc1, 0xc1, 0x25, 0x36, 0x85,
+	0xcd, 0xe4, 0x69, 0xe0, 0xdf, 0x08, 0x02, 0x05, 0xd8, 0x65, 0x2a, 0x81, 0x85, 0xc0, 0x2d, 0x60,
+	0x5e, 0x94, 0x1a, 0x6b, 0x14, 0x09, 0x04, 0xdc, 0x96, 0x08, 0x75, 0xb6, 0xa5, 0x2d, 0xb5, 0xce,
+	0x60, 0xf1, 0x76, 0x3b, 0x5c, 0xc5, 0x6b, 0x8b, 0xfc, 0x38, 0x1d, 0x7e, 0x68, 0x3a, 0xb2, 0xfe,
+	0xb4, 0xc2, 0x6a, 0x06, 0x18, 0x77, 0xd8, 0x01, 0x0e, 0xb8, 0xd9, 0xee, 0xc4, 0xbd, 0x24, 0x4f,
+	0x86, 0xc4, 0xa9, 0x0e, 0x94, 0x0b, 0xb7, 0xa3, 0x83, 0x26, 0x10, 0x06, 0x38, 0xf7, 0x60, 0x98,
+	0x88, 0x5b, 0xad, 0x52, 0xe4, 0x40, 0x11, 0xaf, 0x17, 0x3f, 0x32, 0xf1, 0x04, 0x3f, 0x38, 0x50,
+	0x69, 0x81, 0x08, 0x1a, 0x55, 0xb5, 0x05, 0x22, 0x28, 0xe2, 0xca, 0x86, 0x29, 0x8f, 0x6c, 0x78,
+	0x8d, 0xad, 0x0b, 0x29, 0xd0, 0x17, 0xd3, 0x69, 0x3a, 0x6c, 0x32, 0xa6, 0x16, 0x5d, 0x3e, 0x38,
+	0x66, 0xc9, 0xe0, 0x59, 0xe7, 0x63, 0xa1, 0xa7, 0x94, 0xa2, 0x02, 0x1c, 0x71, 0x71, 0x3b, 0x5a,
+	0xb8, 0xc2, 0x25, 0x5f, 0x80, 0x73, 0x5c, 0x98, 0xa3, 0x85, 0x3b, 0x4b, 0xb8, 0x0e, 0x3c, 0xdc,
+	0x64, 0x1b, 0x9c, 0x4d, 0x1e, 0xa4, 0xc0, 0x55, 0xe9, 0xc1, 0xc9, 0xee, 0x68, 0x2f, 0x6b, 0x0d,
+	0x3b, 0x03, 0xee, 0xc9, 0xfc, 0x0f, 0x50, 0x10, 0xad, 0x5a, 0xb2, 0x96, 0x5e, 0x15, 0x3c, 0xab,
+	0xfc, 0xf0, 0x82, 0xb3, 0x96, 0xe5, 0xb5, 0x19, 0x54, 0x09, 0x44, 0x61, 0x6a, 0xbe, 0x47, 0xae,
+	0xf9, 0x2d, 0xb6, 0x28, 0x3f, 0x2d, 0x1b, 0x0a, 0x36, 0xab, 0x17, 0xd9, 0x8c, 0xda, 0x4b, 0xad,
+	0x40, 0x76, 0xf1, 0x55, 0xa1, 0x62, 0x27, 0x6d, 0x3e, 0x09, 0x94, 0x8a, 0x96, 0x82, 0xc3, 0xab,
+	0xb6, 0xcd, 0x26, 0x51, 0xad, 0xa5, 0x80, 0x59, 0xf8, 0x17, 0x25, 0xc6, 0xf4, 0xe8, 0x70, 0xe5,
+	0x49, 0x9e, 0x26, 0x52, 0x0d, 0xd1, 0x00, 0xd4, 0x34, 0x2c, 0x13, 0x44, 0x88, 0x9b, 0x9a, 0x84,
+	0xe1, 0x01, 0xfe, 0x3c, 0x5b, 0x3c, 0xe8, 0xa6, 0x7b, 0xfc, 0xa0, 0x03, 0xcd, 0x15, 0x1a, 0xd2,
+	0x05, 0xd5, 0x82, 0x00, 0xbf, 0x45, 0xd0, 0x31, 0xe2, 0xfa, 0x2f, 0xcb, 0xca, 0x73, 0xa5, 0xe7,
+	0x3c, 0x76, 0x1b, 0x81, 0xe9, 0xed, 0x4a, 0xbf, 0x31, 0x8e, 0x22, 0x6e, 0x20, 0xee, 0x3c, 0xd6,
+	0xfa, 0xf9, 0x32, 0xd8, 0x35, 0x42, 0xbc, 0x48, 0xd9, 0x53, 0x9d, 0x20, 0x7b, 0xe6, 0x87, 0xd6,
+	0xc1, 0xf2, 0x05, 0xe0, 0xdd, 0x36, 0x68, 0x76, 0x79, 0x87, 0x1b, 0x37, 0xfc, 0xa4, 0x15, 0x12,
+	0x73, 0xd1, 0x80, 0xf3, 0x13, 0x10, 0xa8, 0xd4, 0x12, 0xd7, 0x85, 0x0a, 0x93, 0x42, 0x14, 0x34,
+	0x18, 0x11, 0xc3, 0xbf, 0x93, 0x4e, 0x32, 0x7b, 0x0d, 0xc7, 0x53, 0xc4, 0x9c, 0x5d, 0xd9, 0x99,
+	0xdd, 0x33, 0xe4, 0x78, 0x6a, 0x4b, 0xff, 0x22, 0xb9, 0x0e, 0x05, 0x90, 0x1c, 0x8c, 0x36, 0x49,
+	0xab, 0xa7, 0x21, 0x69, 0x78, 0x19, 0x2f, 0xf5, 0xf3, 0x2d, 0x5c, 0x41, 0x29, 0xf9, 0x36, 0x41,
+	0x84, 0x24, 0xc7, 0x4d, 0xb1, 0xc4, 0x42, 0x25, 0x99, 0x01, 0x00, 0xc7, 0xc1, 0xeb, 0x00, 0x8d,
+	0x2f, 0x94, 0xc7, 0xf0, 0xe7, 0x15, 0x36, 0x7d, 0xbb, 0x7f, 0x94, 0x76, 0x5a, 0xdc, 0x35, 0xd4,
+	0x03, 0x93, 0x49, 0xde, 0x52, 0xe3, 0x6f, 0x3c, 0xf8, 0xf9, 0x9d, 0xd7, 0x20, 0x27, 0x9f, 0x8d,
+	0x2c, 0xf2, 0xcb, 0x07, 0x1d, 0x72, 0x21, 0xb8, 0xcd, 0x80, 0xa0, 0x4d, 0x35, 0x34, 0x83, 0x4b,
+	0xa8, 0xa4, 0x43, 0x00, 0xa6, 0x8c, 0x10, 0x00, 0xee, 0xb0, 0x14, 0xd7, 0x79, 0x7c, 0x49, 0xd0,
+	0x61, 0x29, 0x8a, 0x5c, 0xd1, 0x1c, 0x26, 0x74, 0x1f, 0x8a, 0x87, 0xe9, 0x34, 0x29, 0x9a, 0x26,
+	0x10, 0x0f, 0x5c, 0xd1, 0x40, 0xe0, 0x08, 0x81, 0x64, 0x82, 0x50, 0x01, 0x71, 0xe3, 0x53, 0x66,
+	0x05, 0x9b, 0x38, 0x60, 0x94, 0x5a, 0x69, 0x9f, 0x7b, 0xe7, 0x9b, 0xfb, 0xa0, 0xbe, 0xa3, 0x15,
+	0x44, 0xbe, 0xf9, 0x02, 0x1c, 0xc7, 0xfd, 0xd1, 0xb0, 0xd9, 0x42, 0x56, 0xaa, 0x89, 0x71, 0x53,
+	0x11, 0xbf, 0xd7, 0x06, 0x9b, 0xee, 0x28, 0xd1, 0x44, 0x9a, 0x13, 0x57, 0x00, 0x0e, 0x98, 0x76,
+	0x3f, 0xf9, 0xde, 0xe6, 0x85, 0xdc, 0x57, 0x00, 0xa4, 0x23, 0xbf, 0x3e, 0x3e, 0xe1, 0x6e, 0xf5,
+	0x4a, 0x44, 0xa5, 0xf0, 0x9f, 0x4b, 0x2c, 0xd8, 0x6a, 0xb7, 0x69, 0xf1, 0x94, 0x35, 0xa0, 0xc9,
+	0x5e, 0xb2, 0xc8, 0xee, 0x99, 0x7e, 0xd9, 0x3f, 0x7d, 0x20, 0xe5, 0xa8, 0xdf, 0xd9, 0xef, 0x00,
+	0xc3, 0x8e, 0x86, 0x1d, 0xd2, 0xf7, 0x4c, 0x10, 0xd7, 0xc2, 0x88, 0x00, 0x4d, 0x7e, 0x81, 0x2f,
+	0x84, 0x89, 0x0d, 0xc4, 0x91, 0x00, 0x2d, 0x06, 0x14, 0x33, 0x04, 0x23, 0x11, 0xa5, 0xf0, 0x26,
+	0xab, 0xed, 0x18, 0x71, 0x46, 0x9c, 0x8f, 0x64, 0x84, 0x11, 0xf1, 0x9e, 0x01, 0x31, 0x26, 0x54,
+	0x36, 0x27, 0x14, 0xfe, 0x1e, 0x0b, 0xf0, 0x22, 0x4b, 0xcd, 0x5f, 0x59, 0x65, 0xd2, 0xab, 0x63,
+	0x5a, 0x65, 0x04, 0xe3, 0x56, 0xd9, 0x96, 0xb8, 0x0f, 0x75, 0x09, 0x77, 0x09, 0x23, 0x02, 0x38,
+	0x48, 0x1e, 0x23, 0x0b, 0xb4, 0xff, 0x24, 0xa6, 0xaa, 0x47, 0x85, 0x87, 0x80, 0xd6, 0x29, 0xf5,
+	0x73, 0xb0, 0x59, 0xee, 0xef, 0xef, 0x27, 0x43, 0xef, 0x56, 0xf2, 0xc6, 0xbe, 0xa0, 0xe4, 0x48,
+	0xb1, 0x09, 0xca, 0x14, 0xb1, 0x89, 0x54, 0xb9, 0xc8, 0xfa, 0x55, 0x1f, 0xeb, 0x93, 0x62, 0xa0,
+	0x06, 0x2f, 0x6e, 0x42, 0x2d, 0x18, 0x12, 0x59, 0xf4, 0xda, 0xd2, 0x42, 0xcf, 0x80, 0x84, 0xf7,
+	0xd8, 0x12, 0xf0, 0x12, 0x1f, 0xbb, 0x22, 0x88, 0x39, 0xb2, 0x92, 0x33, 0x32, 0xbb, 0xbf, 0x72,
+	0xa1, 0xbf, 0x15, 0x71, 0xcb, 0xc8, 0x3b, 0x54, 0x57, 0x8f, 0x6f, 0x88, 0x15, 0x93, 0x40, 0xfa,
+	0xcc, 0xb3, 0xec, 0x0c, 0x6f, 0x28, 0xa9, 0x2e, 0xa3, 0xb1, 0xc4, 0x60, 0xa8, 0x0e, 0xcc, 0xf9,
+	0x15, 0x0e, 0x70, 0x96, 0xdb, 0x1e, 0x47, 0xc9, 0x1d, 0x87, 0xc7, 0xb0, 0xfd, 0x26, 0x5b, 0xb5,
+	0x3b, 0x7a, 0x52, 0xfb, 0x06, 0x2d, 0xd6, 0x69, 0x62, 0x6c, 0x5c, 0x13, 0x2b, 0xbe, 0x8e, 0xbc,
+	0x86, 0x26, 0x6c, 0x0c, 0x3f, 0x14, 0xd6, 0xbc, 0xe2, 0x5b, 0x73, 0x0c, 0x86, 0x89, 0xf3, 0x43,
+	0x6e, 0xab, 0x02, 0x7f, 0xe1, 0x6f, 0x69, 0x43, 0x4f, 0x69, 0x1b, 0x9a, 0x6e, 0xfe, 0x69, 0x50,
+	0x99, 0xf6, 0xd8, 0xad, 0xda, 0x60, 0xbd, 0x03, 0x68, 0x80, 0xee, 0x0e, 0x20, 0xd4, 0x48, 0xd5,
+	0x87, 0xaf, 0xb2, 0xfa, 0x8d, 0xa4, 0x0b, 0x6a, 0xf0, 0x56, 0xb7, 0xeb, 0xf4, 0x6f, 0xfa, 0x8b,
+	0x4a, 0xb6, 0xbf, 0xe8, 0x4d, 0xb6, 0xe1, 0x69, 0x45, 0x9f, 0x27, 0x3e, 0x36, 0x86, 0xa0, 0xf8,
+	0x58, 0x7d, 0xf6, 0x2d, 0xb6, 0x7c, 0x23, 0xd9, 0x1b, 0x1d, 0xdc, 0x49, 0x8e, 0xb4, 0x63, 0x19,
+	0x88, 0x91, 0x1d, 0xa6, 0xc7, 0xf4, 0x31, 0xfe, 0x1b, 0x2f, 0xa5, 0xba, 0x88, 0xd3, 0xc4, 0xcb,
+	0x44, 0x5a, 0xb1, 0x59, 0x0e, 0xd9, 0x05, 0x40, 0xf8, 0x1a, 0x0b, 0xcc, 0x7e, 0x68, 0x04, 0x78,
+	0x88, 0x80, 0xc1, 0x9b, 0x9d, 0x64, 0x79, 0xd2, 0x93, 0xe7, 0xa7, 0x09, 0x82, 0x69, 0x07, 0x86,
+	0x83, 0x34, 0x11, 0x3e, 0x51, 0xe4, 0x42, 0x74, 0x18, 0x26, 0xda, 0x1d, 0x05, 0x5c, 0xa8, 0x21,
+	0xe1, 0xf3, 0x6c, 0x0e, 0x66, 0x0b, 0xc3, 0xa5, 0x50, 0x49, 0x74, 0x1b, 0xc4, 0x27, 0xc8, 0x38,
+	0xca, 0x6d, 0xc0, 0xab, 0xc3, 0xff, 0x2d, 0xb1, 0x33, 0x02, 0x13, 0xc7, 0x82, 0x11, 0x9c, 0x9d,
+	0xbe, 0x70, 0xe5, 0xd3, 0x58, 0x0c, 0x50, 0x81, 0xc7, 0xca, 0x1e, 0x1e, 0x23, 0x9a, 0xca, 0xf8,
+	0x15, 0x62, 0x26, 0x0b, 0xc6, 0xbd, 0x22, 0x60, 0x82, 0x8b, 0x48, 0xd8, 0xaa, 0xbe, 0xbe, 0x13,
+	0x81, 0xb0, 0xfa, 0xf8, 0x99, 0x32, 0x8f, 0x1f, 0x1a, 0x9f, 0x14, 0x7d, 0x24, 0x52, 0x4c, 0x10,
+	0xa8, 0x34, 0x35, 0x1e, 0x42, 0xd9, 0x3c, 0xe4, 0xde, 0xb5, 0x69, 0xce, 0x51, 0x4b, 0x66, 0xac,
+	0xe5, 0x2d, 0x54, 0x68, 0x4c, 0xa4, 0xf0, 0x1f, 0x4a, 0x6c, 0xf6, 0x2d, 0x15, 0x0a, 0x0a, 0x0b,
+	0xdb, 0x07, 0x5b, 0x4b, 0x4a, 0x51, 0xfc, 0x8d, 0xcc, 0xc5, 0xa3, 0x47, 0x07, 0x22, 0x12, 0xac,
+	0x1a, 0xc9, 0x22, 0xb7, 0xc9, 0xbb, 0xf9, 0x11, 0xdd, 0x43, 0x0a, 0x25, 0xcb, 0x80, 0x20, 0x2d,
+	0xd0, 0xe8, 0x88, 0x73, 0x58, 0xc9, 0x41, 0x2e, 0x2d, 0x2c, 0x0b, 0x26, 0xbd, 0x14, 0x68, 0x94,
+	0x65, 0x09, 0x28, 0x85, 0xed, 0x8c, 0xa6, 0xed, 0x82, 0xd1, 0x51, 0x87, 0x9b, 0x48, 0x0d, 0x56,
+	0xed, 0xae, 0x1b, 0x6c, 0xdd, 0xad, 0x50, 0xfb, 0x6b, 0x5a, 0x04, 0xbd, 0xca, 0xed, 0x25, 0x89,
+	0xa1, 0x70, 0x23, 0x89, 0x10, 0xfe, 0xa0, 0xa4, 0x1c, 0x81, 0xb7, 0x3a, 0xe8, 0x61, 0x55, 0xee,
+	0xcf, 0xdf, 0xfe, 0x3e, 0x99, 0xf8, 0x74, 0x98, 0x8b, 0xf8, 0x13, 0xf2, 0x8f, 0x69, 0x08, 0x4a,
+	0x7c, 0x38, 0x27, 0x45, 0x2d, 0xe9, 0xe8, 0xb2, 0x1c, 0xfe, 0xbd, 0x8e, 0x83, 0xbd, 0x79, 0x84,
+	0x22, 0x2e, 0x30, 0x22, 0x15, 0x67, 0x45, 0x0c, 0xa2, 0xcd, 0x4a, 0x65, 0x97, 0x95, 0x0a, 0x97,
+	0x1c, 0x95, 0xd3, 0x5d, 0x72, 0x54, 0xbd, 0x97, 0x1c, 0xc0, 0x98, 0x6d, 0x1e, 0x5c, 0x4d, 0xda,
+	0x3e, 0x95, 0x40, 0xbd, 0x58, 0x77, 0x09, 0x47, 0xf4, 0xff, 0x22, 0xb0, 0xf2, 0x91, 0x21, 0xdd,
+	0x1c, 0x92, 0xf1, 0x69, 0x45, 0x84, 0x12, 0x7e, 0xcc, 0xd6, 0xef, 0x76, 0xda, 0xed, 0x6e, 0x72,
+	0x1c, 0x0f, 0xe1, 0x94, 0x38, 0x80, 0xbe, 0x44, 0x04, 0x1f, 0xf2, 0x48, 0x4f, 0xd5, 0x34, 0x0d,
+	0x06, 0x75, 0xc1, 0xc8, 0xab, 0xbd, 0x24, 0x3f, 0x4c, 0xdb, 0xc2, 0xbe, 0x9c, 0x8d, 0x64, 0x11,
+	0x09, 0x05, 0xf2, 0xbc, 0x2d, 0x74, 0x14, 0x71, 0x31, 0xae, 0x01, 0x68, 0x1d, 0xae, 0x46, 0x3b,
+	0xdb, 0xe6, 0xf7, 0xd5, 0x71, 0x47, 0xa7, 0x8d, 0xe1, 0x96, 0xd2, 0x10, 0xa4, 0x89, 0xf8, 0x02,
+	0x09, 0x03, 0x2a, 0xf1, 0x75, 0x81, 0xf5, 0x11, 0x83, 0x15, 0x0a, 0x9d, 0x06, 0x70, 0xb6, 0x00,
+	0x95, 0x14, 0x8c, 0x86, 0x8f, 0x93, 0x36, 0x69, 0xeb, 0x06, 0x24, 0xfc, 0x15, 0xf0, 0xa2, 0x33,
+	0x1c, 0xa2, 0xe8, 0x35, 0x36, 0x33, 0xe4, 0xa4, 0x49, 0x64, 0x10, 0xe7, 0x79, 0xa2, 0xa9, 0x9f,
+	0x76, 0x91, 0x42, 0x77, 0xa6, 0x52, 0x2e, 0x4c, 0x05, 0x4e, 0xc7, 0x64, 0x38, 0x4c, 0x87, 0x34,
+	0x5c, 0x51, 0x10, 0xe6, 0xc8, 0xa0, 0x1b, 0x13, 0x57, 0xcc, 0x44, 0xb2, 0x88, 0xf2, 0x88, 0x7e,
+	0xa2, 0xf4, 0x23, 0x95, 0xd3, 0x04, 0x85, 0xbf, 0xd0, 0x5b, 0x0a, 0x2f, 0x03, 0x7a, 0x00, 0x6c,
+	0x8b, 0x15, 0x5d, 0x60, 0x65, 0x15, 0x9c, 0x5b, 0x16, 0x64, 0xa4, 0x3b, 0x1d, 0x22, 0x23, 0x5d,
+	0xe5, 0x9c, 0x2e, 0x70, 0xb2, 0x70, 0x1d, 0x55, 0xf5, 0x5d, 0x47, 0xe9, 0x20, 0xd3, 0x29, 0x2b,
+	0xc8, 0x14, 0xf5, 0x90, 0x24, 0xce, 0x94, 0x48, 0xa5, 0x52, 0x78, 0x8e, 0x35, 0x50, 0xac, 0xd8,
+	0x23, 0x57, 0x42, 0x27, 0x61, 0x9b, 0xde, 0x5a, 0x5a, 0xa7, 0xb7, 0xc4, 0x6d, 0x95, 0x51, 0x45,
+	0x5b, 0xe0, 0x9c, 0xbd, 0x05, 0xec, 0xf6, 0x91, 0xdb, 0x08, 0x2c, 0xce, 0x73, 0x37, 0x1f, 0x25,
+	0x2d, 0x7e, 0xa5, 0x60, 0x61, 0x12, 0x7f, 0x3a, 0x84, 0x0c, 0x2f, 0xb0, 0xf3, 0x63, 0xf0, 0xc9,
+	0xfc, 0xfc, 0x1a, 0x0b, 0xee, 0x8f, 0xf2, 0xbd, 0xf4, 0x91, 0xa9, 0x47, 0xf3, 0xe8, 0x29, 0x51,
+	0xde, 0x03, 0x45, 0xce, 0xdc, 0x61, 0x0e, 0x38, 0x1c, 0xc8, 0xf6, 0xf7, 0xd2, 0x1c, 0xec, 0x93,
+	0x96, 0xbb, 0x9e, 0x55, 0xbe, 0x9e, 0x52, 0x54, 0x95, 0xc7, 0x89, 0xaa, 0x8a, 0x2b, 0xaa, 0xea,
+	0xfc, 0x84, 0xee, 0xa6, 0x71, 0x9b, 0x56, 0x4f, 0x16, 0x41, 0xbc, 0xcc, 0x8a, 0x2f, 0x6e, 0x81,
+	0xf5, 0x77, 0xea, 0x81, 0xd2, 0x90, 0xca, 0x72, 0x48, 0xa8, 0x20, 0xab, 0x6e, 0x14, 0x35, 0x6e,
+	0xb3, 0xf3, 0x11, 0x30, 0xc9, 0x51, 0x62, 0xd1, 0x64, 0x4f, 0x07, 0x4c, 0x9f, 0x9e, 0x30, 0x4f,
+	0xb3, 0xa7, 0xc6, 0x75, 0x45, 0x1f, 0xfb, 0x84, 0xd5, 0x8c, 0xe8, 0x11, 0x6f, 0x5c, 0x08, 0xf2,
+	0x62, 0x7c, 0xdc, 0xcc, 0x1f, 0x29, 0xd3, 0x8b, 0x97, 0xf0, 0x24, 0x15, 0x32, 0x9b, 0x38, 0x98,
+	0xb4, 0x0a, 0x13, 0x86, 0xf4, 0x6d, 0x65, 0x47, 0x14, 0xd9, 0x4c, 0xce, 0x4c, 0x05, 0x08, 0xbf,
+	0xcb, 0x6a, 0xe8, 0x68, 0xda, 0x49, 0xfa, 0x71, 0x37, 0x3f, 0x99, 0x70, 0xcd, 0x04, 0x47, 0xd2,
+	0x3e, 0x48, 0x75, 0xee, 0xd1, 0x12, 0xb7, 0x21, 0xaa, 0xcc, 0x87, 0x81, 0x1e, 0x75, 0x02, 0xa8,
+	0x61, 0x18, 0x30, 0x9c, 0xc2, 0xb1, 0x0e, 0xc5, 0x2e, 0x45, 0x54, 0xc2, 0x01, 0xa0, 0xa7, 0xc7,
+	0x18, 0xc0, 0x98, 0xc8, 0xd5, 0xff, 0xaf, 0x01, 0xc0, 0x7e, 0xfe, 0xc6, 0x28, 0x19, 0x9e, 0xdc,
+	0xed, 0x64, 0x19, 0xf0, 0xec, 0x76, 0xda, 0xcf, 0x87, 0xa9, 0x54, 0x69, 0xc3, 0x8f, 0xd8, 0xa6,
+	0xb7, 0x56, 0x85, 0x59, 0x92, 0x77, 0xdc, 0x4e, 0x23, 0x32, 0x48, 0x4a, 0xde, 0x71, 0xc4, 0x14,
+	0xfe, 0x64, 0xdb, 0x8f, 0x6e, 0xcc, 0x9d, 0x3c, 0xee, 0xe1, 0x0e, 0x6b, 0x44, 0xa8, 0x7b, 0x78,
+	0x07, 0x34, 0x61, 0x85, 0xc6, 0x5e, 0x1a, 0x85, 0xe7, 0xd9, 0xa6, 0xb7, 0x47, 0xb5, 0xf7, 0xcf,
+	0x01, 0xf3, 0x93, 0xe4, 0xb9, 0xd1, 0x39, 0x4a, 0x86, 0x07, 0x89, 0x79, 0xaf, 0x09, 0x27, 0x44,
+	0x5b, 0x41, 0xa5, 0x56, 0xad, 0x21, 0x78, 0xf9, 0xbc, 0x3d, 0x82, 0x13, 0xbe, 0x77, 0x37, 0xc9,
+	0xb2, 0xf8, 0xc0, 0x32, 0xc5, 0xf1, 0x38, 0x20, 0x4f, 0x68, 0x73, 0xaf, 0x93, 0xcb, 0xcb, 0x2e,
+	0x03, 0x84, 0x07, 0x0c, 0x0a, 0x02, 0x41, 0x99, 0xf9, 0x48, 0x14, 0xc2, 0x77, 0xd9, 0xbc, 0xd5,
+	0xa9, 0x48, 0x3b, 0x48, 0x54, 0xae, 0x08, 0xfe, 0xb6, 0xe4, 0xc9, 0x3c, 0xc9, 0x13, 0x4c, 0xcc,
+	0x8a, 0xf3, 0x98, 0x6c, 0x78, 0xfe, 0x3b, 0x7c, 0xc8, 0xea, 0x3c, 0x17, 0xc4, 0xec, 0xd0, 0x30,
+	0x5a, 0x7e, 0xeb, 0x7e, 0x37, 0xd9, 0x86, 0xa7, 0x5f, 0x22, 0xeb, 0x37, 0xd8, 0xca, 0x6e, 0xe7,
+	0x80, 0xe7, 0x4f, 0x8c, 0xda, 0x9d, 0xdc, 0x50, 0x1d, 0x0c, 0xdd, 0xaf, 0x34, 0x51, 0xf7, 0x2b,
+	0x3b, 0xba, 0xdf, 0x5f, 0x83, 0xee, 0x47, 0x7d, 0xfe, 0xb6, 0xba, 0x1f, 0x3a, 0x13, 0x46, 0xb9,
+	0x79, 0x6a, 0xaa, 0xb2, 0xc9, 0x41, 0x55, 0x7b, 0xf3, 0x41, 0x9f, 0x38, 0x61, 0x61, 0xdf, 0xd0,
+	0x35, 0x98, 0x02, 0x84, 0xdb, 0x6c, 0xd5, 0x9e, 0xe9, 0x63, 0xf4, 0x3c, 0x73, 0x0a, 0x4a, 0xcf,
+	0x7b, 0x0a, 0x8f, 0x34, 0x23, 0x4e, 0x80, 0x7b, 0x95, 0x3b, 0x89, 0x3a, 0x59, 0xbf, 0x03, 0x0c,
+	0x61, 0xd4, 0x9c, 0x38, 0x57, 0x7f, 0xa5, 0xc2, 0xd5, 0xdf, 0x8b, 0xec, 0x0c, 0x39, 0xb1, 0xcb,
+	0x13, 0x9c, 0xd8, 0x84, 0x03, 0x73, 0x58, 0x74, 0x3e, 0x8c, 0x01, 0xf8, 0x03, 0xfa, 0xed, 0xdc,
+	0x94, 0x59, 0x03, 0x89, 0x14, 0x56, 0xf8, 0x81, 0x13, 0x31, 0xe1, 0xcc, 0xe1, 0xb3, 0xf7, 0x38,
+	0x21, 0xe4, 0xe3, 0x6f, 0x4b, 0xea, 0xaa, 0x40, 0xb4, 0xba, 0xd1, 0xd9, 0xdf, 0x7f, 0x2c, 0x51,
+	0x5e, 0x65, 0x2c, 0xed, 0xb6, 0x9b, 0xa7, 0x20, 0x8c, 0x81, 0x87, 0xad, 0xd0, 0x9b, 0x4d, 0xad,
+	0x2a, 0x93, 0x5a, 0x69, 0x3c, 0x90, 0x0b, 0xe7, 0xc7, 0x50, 0x83, 0xf8, 0xe3, 0xaa, 0x90, 0x65,
+	0x5a, 0x7e, 0xd6, 0x7d, 0xd4, 0xc0, 0x79, 0x45, 0x12, 0x11, 0x3a, 0x5d, 0xa3, 0xb8, 0x0b, 0xc7,
+	0x1c, 0xfb, 0x5d, 0xf6, 0xd5, 0xcf, 0xca, 0x6c, 0x91, 0x7a, 0x55, 0x81, 0x53, 0xd6, 0x36, 0x2a,
+	0xb9, 0xdb, 0x88, 0xbb, 0xa6, 0x45, 0xe4, 0xb8, 0x32, 0x8f, 0x44, 0xaf, 0x05, 0x38, 0xde, 0x82,
+	0x8f, 0xfa, 0x14, 0xde, 0x67, 0xa4, 0xcf, 0x88, 0x43, 0xca, 0x57, 0xf5, 0x84, 0xa3, 0xd0, 0xae,
+	0xb2, 0x55, 0xe5, 0x8a, 0x85, 0x1f, 0x4e, 0x46, 0x90, 0xb7, 0x0e, 0x47, 0x20, 0xae, 0x28, 0xed,
+	0xbc, 0x20, 0x1b, 0x18, 0xde, 0x63, 0xeb, 0xee, 0x62, 0xd0, 0xd2, 0xbe, 0xca, 0x66, 0x33, 0xa2,
+	0xa4, 0x5c, 0xdc, 0x75, 0x5a, 0x5c, 0x87, 0xd0, 0x91, 0x46, 0x0c, 0x5f, 0x13, 0xba, 0xf5, 0x7b,
+	0x7d, 0x9e, 0x86, 0x71, 0x94, 0xb4, 0x31, 0x39, 0xc7, 0x74, 0x67, 0xe1, 0xc5, 0xa6, 0x4c, 0x2c,
+	0xad, 0x44, 0xb2, 0x18, 0xfe, 0x7b, 0x99, 0x2d, 0xd8, 0x8d, 0x9e, 0x74, 0xc4, 0x9a, 0xca, 0x51,
+	0xab, 0x8c, 0xcd, 0x51, 0xab, 0x5a, 0xe6, 0x83, 0xeb, 0x14, 0x12, 0x76, 0x90, 0xed, 0x14, 0xf2,
+	0x66, 0xaa, 0x9d, 0x19, 0x97, 0xa9, 0x86, 0x2e, 0xd4, 0x03, 0xb9, 0x10, 0x15, 0xba, 0xaf, 0xc0,
+	0x70, 0x8d, 0x04, 0x6f, 0x28, 0x64, 0x54, 0xab, 0x02, 0xe0, 0xb9, 0x9a, 0x1e, 0xf7, 0xe1, 0x64,
+	0x13, 0xb7, 0x2b, 0xa2, 0xc0, 0xc3, 0x28, 0x85, 0xc7, 0xb5, 0xc9, 0x1d, 0xe3, 0x8c, 0xc2, 0x28,
+	0x0d, 0x58, 0xf8, 0x8e, 0x30, 0x62, 0x0a, 0xcb, 0xa0, 0xc4, 0xfa, 0x94, 0x48, 0x80, 0x10, 0xeb,
+	0xba, 0x46, 0xeb, 0x6a, 0xa3, 0x47, 0x02, 0x07, 0x0c, 0xa2, 0x75, 0x71, 0x67, 0xb7, 0x0d, 0x66,
+	0x47, 0x07, 0xbd, 0x31, 0x4f, 0xc0, 0x7f, 0x42, 0x1e, 0xd6, 0xb2, 0xf6, 0xb0, 0x6e, 0xb0, 0xb3,
+	0x85, 0xcf, 0xd0, 0x39, 0xfc, 0x6f, 0x25, 0xb6, 0x72, 0x3d, 0xce, 0x5b, 0x87, 0x3b, 0x76, 0xfa,
+	0xb3, 0x91, 0xb0, 0x4c, 0xe6, 0xae, 0xbc, 0xf2, 0x2d, 0xc0, 0x51, 0xb8, 0xf0, 0xc8, 0x96, 0x11,
+	0xe8, 0x72, 0xd2, 0x8b, 0x6d, 0x40, 0x1e, 0xeb, 0xf2, 0x42, 0x57, 0x05, 0xde, 0xb3, 0xa7, 0xfd,
+	0xd6, 0x68, 0x38, 0x04, 0xad, 0x49, 0xaa, 0xe2, 0x2e, 0x58, 0x7e, 0x89, 0x92, 0xb2, 0xc5, 0x51,
+	0x6b, 0x40, 0xd0, 0x33, 0x19, 0xd8, 0xb3, 0xc9, 0x46, 0x5d, 0xae, 0x44, 0x89, 0x6b, 0x2b, 0xa1,
+	0x60, 0x89, 0xc2, 0x67, 0xb8, 0x6b, 0x72, 0xd9, 0xb5, 0xe2, 0x61, 0x57, 0x5f, 0x86, 0x77, 0xf5,
+	0xb4, 0x19, 0xde, 0x53, 0x8f, 0xcd, 0xf0, 0xc6, 0xcd, 0x28, 0x01, 0xc2, 0xe3, 0x20, 0x0c, 0x6f,
+	0x1b, 0x18, 0x7e, 0x91, 0xad, 0x08, 0x3d, 0xe1, 0xed, 0x14, 0xb4, 0x59, 0x15, 0x49, 0x09, 0x04,
+	0xc8, 0x3a, 0x3a, 0xf4, 0x4e, 0x14, 0xc2, 0x26, 0xe8, 0x60, 0x18, 0x15, 0xd9, 0x16, 0xc8, 0x93,
+	0x74, 0xc9, 0x06, 0xba, 0x50, 0x28, 0xe7, 0x90, 0xce, 0x07, 0x95, 0x64, 0xc8, 0xfd, 0x47, 0xbc,
+	0x29, 0x11, 0x46, 0x16, 0xc3, 0x5b, 0x6c, 0xc1, 0xea, 0x1a, 0x43, 0x3f, 0x66, 0xa8, 0xd2, 0x8d,
+	0xb6, 0xf4, 0x8c, 0x24, 0x52, 0xb8, 0xe1, 0x1b, 0x6c, 0x35, 0x42, 0x27, 0xc9, 0x89, 0x9c, 0x97,
+	0xed, 0x8d, 0xe7, 0x0e, 0x94, 0x93, 0xa4, 0x4d, 0x0b, 0x6c, 0xc1, 0xc2, 0x36, 0x5b, 0xdc, 0x1d,
+	0xc0, 0x59, 0x99, 0xdc, 0xee, 0x3f, 0x81, 0xdd, 0x35, 0x26, 0xed, 0x36, 0x7c, 0x95, 0x2d, 0xe9,
+	0xaf, 0x18, 0x9e, 0x7a, 0x0e, 0x33, 0x53, 0x60, 0x4c, 0x10, 0xea, 0xc8, 0x22, 0xbe, 0xf4, 0xbd,
+	0x01, 0xda, 0xed, 0x14, 0xcf, 0x4c, 0x4a, 0xdd, 0xbf, 0x72, 0x6e, 0xd6, 0xb5, 0x0f, 0x78, 0xb2,
+	0x02, 0x8e, 0x40, 0xa4, 0x2d, 0x48, 0xb7, 0xbc, 0x28, 0xa1, 0xc0, 0xa3, 0xf4, 0x19, 0x32, 0x02,
+	0xab, 0x91, 0x06, 0x58, 0x16, 0x62, 0x85, 0x57, 0x16, 0x2d, 0x44, 0x99, 0x8c, 0x53, 0x35, 0x2c,
+	0x44, 0x82, 0xe1, 0xd6, 0xe3, 0x65, 0xc1, 0x7c, 0xb4, 0xf5, 0x34, 0x04, 0xeb, 0x47, 0x03, 0x0c,
+	0x96, 0xe4, 0xd7, 0x41, 0xe2, 0x76, 0xdc, 0x80, 0x80, 0xc2, 0xdf, 0xf0, 0xcd, 0x94, 0x28, 0xf5,
+	0x0a, 0x9b, 0x16, 0xb3, 0x90, 0x6c, 0xb1, 0xa1, 0xce, 0x43, 0x77, 0xfe, 0x91, 0xc4, 0x0c, 0xd7,
+	0xd9, 0xea, 0x8d, 0xeb, 0x42, 0xa4, 0x61, 0x77, 0x8a, 0x6e, 0xbf, 0x04, 0x43, 0xc0, 0xac, 0xe0,
+	0x56, 0x7e, 0xdc, 0xc5, 0x08, 0x9e, 0x5c, 0x5a, 0x03, 0x1a, 0x20, 0x22, 0x53, 0x41, 0x66, 0x10,
+	0x6b, 0xcf, 0x44, 0xb2, 0x28, 0xd3, 0x67, 0x5b, 0xbc, 0x27, 0x49, 0x36, 0x13, 0x84, 0xbb, 0x5e,
+	0x1c, 0xfa, 0x98, 0xde, 0x06, 0x12, 0xaa, 0x49, 0xc1, 0xd9, 0xd5, 0xa8, 0x00, 0x97, 0x01, 0x56,
+	0x06, 0xa6, 0xb8, 0x03, 0x75, 0xa0, 0xe1, 0x75, 0xb6, 0xe6, 0x4c, 0x8b, 0x88, 0xf4, 0x05, 0xd8,
+	0xc5, 0x08, 0x70, 0x0c, 0x06, 0x13, 0x39, 0x12, 0x18, 0xe1, 0x7d, 0xb6, 0xbc, 0xd5, 0x6a, 0x21,
+	0x63, 0xc2, 0x31, 0xfc, 0x24, 0x94, 0xc0, 0x9f, 0x96, 0xd8, 0xa2, 0xee, 0x51, 0x3c, 0x9c, 0x30,
+	0x59, 0x09, 0xf4, 0xb9, 0xb3, 0xf4, 0xe6, 0xa9, 0x58, 0xfa, 0x40, 0x21, 0xb0, 0x56, 0xb8, 0x9e,
+	0xf7, 0x93, 0x61, 0x22, 0x35, 0xb7, 0xd9, 0x48, 0x03, 0x1e, 0x7f, 0xad, 0x13, 0xde, 0x60, 0x4b,
+	0x26, 0x01, 0xf8, 0x05, 0xd8, 0x4b, 0x6c, 0x1a, 0x24, 0xe5, 0x50, 0xdb, 0x17, 0xeb, 0x2a, 0x65,
+	0xd8, 0x9a, 0x58, 0x24, 0xd1, 0x40, 0x80, 0xad, 0x6f, 0xed, 0xc5, 0xfd, 0x76, 0xda, 0x77, 0xb3,
+	0x3b, 0x2e, 0xb3, 0x60, 0xd4, 0x27, 0x75, 0x42, 0x9a, 0x88, 0xf2, 0x84, 0xf4, 0xd4, 0xe0, 0x45,
+	0x4c, 0x84, 0x0f, 0xd2, 0x24, 0xb7, 0x29, 0x1e, 0x4a, 0x85, 0xf5, 0x95, 0xd8, 0xba, 0x5b, 0xf3,
+	0x99, 0xd3, 0x54, 0xdf, 0x64, 0x4b, 0x32, 0x6d, 0xc2, 0x88, 0xca, 0xad, 0x8c, 0x13, 0x69, 0x05,
+	0xe4, 0xf0, 0x15, 0xb6, 0x7c, 0xb7, 0xd3, 0x4f, 0xae, 0xe3, 0xb8, 0x33, 0x83, 0x5f, 0x90, 0xd7,
+	0x79, 0x86, 0x61, 0x46, 0xa2, 0xd5, 0x80, 0x84, 0x3b, 0x2c, 0x30, 0x1b, 0x69, 0x91, 0xac, 0x53,
+	0x4c, 0x55, 0xa0, 0x98, 0x05, 0x43, 0x3e, 0xb0, 0xb2, 0x18, 0xa9, 0x84, 0x0f, 0x36, 0x6c, 0xb5,
+	0x8f, 0x50, 0x01, 0x7e, 0x00, 0x7c, 0x64, 0xa8, 0xb6, 0xf2, 0x9a, 0x8b, 0x54, 0x5b, 0x79, 0xbd,
+	0xf5, 0x0a, 0x5b, 0xb1, 0xf0, 0x69, 0x08, 0x13, 0x19, 0x33, 0xfc, 0x51, 0x95, 0x6d, 0xde, 0xcc,
+	0xa0, 0x0c, 0x34, 0xb7, 0x72, 0xc5, 0x74, 0x44, 0x81, 0x8a, 0x92, 0x2a, 0x39, 0x51, 0x52, 0xe8,
+	0xb0, 0xa1, 0xe4, 0x29, 0xad, 0x63, 0x99, 0x20, 0xf3, 0x51, 0x15, 0x19, 0xc2, 0x4b, 0xcc, 0x5e,
+	0x80, 0x4b, 0x02, 0x77, 0xfa, 0x83, 0x91, 0xba, 0xe9, 0x33, 0x20, 0x52, 0x4d, 0x3f, 0x48, 0x9a,
+	0x96, 0x13, 0xde, 0x06, 0x72, 0xf5, 0x8a, 0x0b, 0x00, 0x3e, 0x24, 0x4a, 0x34, 0xd4, 0x10, 0x1e,
+	0x00, 0xda, 0x6f, 0x1d, 0xa6, 0xc3, 0xcc, 0xce, 0x06, 0x73, 0xa0, 0xda, 0xae, 0x42, 0x5d, 0x6a,
+	0x78, 0x24, 0xc3, 0x93, 0x6c, 0xa0, 0x61, 0x57, 0x49, 0xb4, 0x59, 0xcb, 0xae, 0x92, 0x78, 0x96,
+	0x67, 0x95, 0x39, 0x9e, 0x55, 0x7e, 0x56, 0x1d, 0x27, 0xc9, 0x80, 0x0f, 0x59, 0xa4, 0x98, 0x6b,
+	0x00, 0xa7, 0x21, 0xe6, 0x5f, 0x8a, 0xbc, 0x42, 0x10, 0xb6, 0xa0, 0x9a, 0xcd, 0x11, 0x0d, 0x1d,
+	0x38, 0x9a, 0x09, 0xf1, 0x11, 0x1c, 0x64, 0xf1, 0x5e, 0x57, 0x9b, 0x7a, 0x22, 0xc9, 0xbc, 0x58,
+	0x21, 0xd6, 0xb6, 0xcf, 0x13, 0xe0, 0xe8, 0x21, 0x02, 0x55, 0x46, 0x2d, 0xf9, 0xed, 0x24, 0x7f,
+	0x4b, 0x2c, 0x12, 0x59, 0xec, 0xb4, 0x49, 0xff, 0xa5, 0xc4, 0xe6, 0xad, 0x0a, 0x24, 0x96, 0x8c,
+	0x23, 0x15, 0x01, 0xa3, 0x82, 0x53, 0x6c, 0x20, 0xc7, 0xa2, 0x08, 0x52, 0x81, 0x45, 0xe9, 0x07,
+	0x16, 0x10, 0x65, 0x89, 0x04, 0x64, 0x3c, 0x63, 0x94, 0xab, 0x5f, 0x42, 0x4f, 0xf6, 0xd4, 0xf0,
+	0xbc, 0x18, 0x80, 0xf2, 0xa4, 0x45, 0x99, 0x1a, 0x4d, 0xb2, 0xb3, 0x58, 0x81, 0xfe, 0x4d, 0xa1,
+	0xfc, 0x3b, 0x33, 0x23, 0x03, 0xe0, 0xeb, 0xc2, 0xaa, 0x24, 0x85, 0x79, 0x8b, 0xae, 0x98, 0xa3,
+	0x31, 0x9a, 0xaf, 0x27, 0x42, 0x24, 0xfc, 0xc7, 0x12, 0x5b, 0xb0, 0x9b, 0x63, 0x33, 0xba, 0xac,
+	0x36, 0xcf, 0x1a, 0x0b, 0x86, 0x2c, 0x80, 0x1b, 0xc1, 0xca, 0xc7, 0x55, 0x00, 0x15, 0x3a, 0x52,
+	0x29, 0x86, 0x8e, 0xd8, 0xa7, 0x84, 0x4e, 0xb7, 0xa0, 0x14, 0x79, 0x9d, 0x68, 0xa1, 0x2e, 0xe7,
+	0xce, 0x18, 0x97, 0x73, 0x20, 0xb5, 0x36, 0xbd, 0x13, 0xa6, 0xdd, 0xff, 0x32, 0x9b, 0x51, 0x77,
+	0xef, 0xb6, 0x09, 0x67, 0xb7, 0x88, 0x14, 0x5a, 0xb8, 0x07, 0x0a, 0x26, 0x0a, 0xf0, 0x3b, 0xe9,
+	0xc1, 0x13, 0x50, 0x30, 0x61, 0xd4, 0x9a, 0x26, 0x60, 0xac, 0xf0, 0x02, 0x5e, 0x6c, 0x33, 0x11,
+	0xcc, 0x31, 0xd6, 0xb5, 0x09, 0x44, 0x57, 0x42, 0xae, 0xd9, 0x97, 0x99, 0x25, 0x16, 0x4c, 0xdf,
+	0x89, 0x18, 0x41, 0x9e, 0xd5, 0xc8, 0x82, 0x19, 0x66, 0xbf, 0xf1, 0x3c, 0x4c, 0x35, 0xb2, 0x81,
+	0x63, 0x2f, 0xb6, 0xbf, 0x0a, 0x7a, 0xb0, 0x22, 0x86, 0x52, 0x5c, 0x6c, 0x57, 0xe7, 0xb2, 0xd2,
+	0xf9, 0xe5, 0x84, 0x94, 0xa3, 0xf3, 0xcf, 0xca, 0x6c, 0x0e, 0x1f, 0x74, 0xd8, 0x4d, 0x72, 0x3c,
+	0x8f, 0xb3, 0x09, 0x77, 0x1e, 0xaf, 0x92, 0x31, 0x78, 0x0a, 0x6f, 0x9d, 0xc6, 0x93, 0xf1, 0x15,
+	0xce, 0x9b, 0x0d, 0x16, 0x0c, 0xe5, 0xcf, 0x01, 0xb7, 0x33, 0x9a, 0xf8, 0x0e, 0x42, 0xb3, 0x87,
+	0x51, 0x5b, 0xc2, 0xe7, 0x5b, 0x80, 0xeb, 0xcd, 0x68, 0x3e, 0xa2, 0x22, 0x58, 0xb1, 0x58, 0x21,
+	0x75, 0x40, 0xfe, 0x9a, 0x86, 0x08, 0xab, 0x12, 0xf2, 0xda, 0x81, 0xe2, 0xbd, 0x8b, 0xd8, 0xb4,
+	0x26, 0x2d, 0xd4, 0x9e, 0x05, 0x49, 0x25, 0x5f, 0xc7, 0xd0, 0x75, 0x42, 0x52, 0xdd, 0x64, 0xf5,
+	0x62, 0x95, 0xd6, 0x1f, 0xcd, 0xf7, 0x33, 0x56, 0x8c, 0xf7, 0x33, 0x14, 0x2e, 0xbd, 0xa3, 0xf1,
+	0x25, 0x19, 0x02, 0xe5, 0xf9, 0xc6, 0xf8, 0x25, 0xc1, 0x61, 0xfb, 0x9a, 0xe9, 0x61, 0xd3, 0x1e,
+	0x7a, 0x00, 0x48, 0xbd, 0x24, 0x57, 0xfe, 0xc9, 0xf0, 0x2b, 0x6c, 0xe9, 0x2d, 0x61, 0x8d, 0x6c,
+	0x03, 0x51, 0xb7, 0xf9, 0x79, 0x04, 0x3c, 0x6e, 0xc4, 0xcb, 0xf1, 0xdf, 0xb8, 0x39, 0x5a, 0xca,
+	0xf8, 0xaa, 0x46, 0xa2, 0x10, 0xfe, 0xac, 0xc2, 0xea, 0xc5, 0x9e, 0x4f, 0x1f, 0xb0, 0x85, 0x2c,
+	0x2f, 0x1e, 0x75, 0x00, 0x5b, 0x27, 0x69, 0x27, 0xf2, 0x0a, 0xd4, 0x06, 0x62, 0x4f, 0x64, 0x0d,
+	0xe9, 0x63, 0xbd, 0x14, 0x59, 0x30, 0x2e, 0xf9, 0x8e, 0x0e, 0xec, 0xf0, 0x1d, 0xc0, 0x31, 0x61,
+	0xc8, 0x04, 0x52, 0xdd, 0x1f, 0x7c, 0xe9, 0xa5, 0x66, 0x4f, 0x46, 0xef, 0x38, 0x50, 0x0b, 0xef,
+	0x1a, 0xc7, 0x3b, 0xe3, 0xe0, 0x5d, 0x2b, 0xe2, 0x5d, 0x43, 0xbc, 0x69, 0x17, 0x0f, 0xa1, 0xc1,
+	0x57, 0x31, 0x20, 0x96, 0x13, 0x99, 0x87, 0x1d, 0x66, 0x70, 0xc0, 0x57, 0x8c, 0x17, 0xad, 0xdc,
+	0x05, 0x88, 0x6c, 0x6c, 0x9d, 0xd2, 0xa5, 0x48, 0x39, 0x2b, 0xec, 0x17, 0x1b, 0xaa, 0x33, 0xe1,
+	0x34, 0x39, 0x19, 0x47, 0x74, 0xc1, 0x18, 0xea, 0xfd, 0x20, 0x3d, 0xc6, 0x28, 0x47, 0x9d, 0xe6,
+	0x82, 0x41, 0xfe, 0x06, 0x50, 0xc7, 0x3d, 0x7a, 0x9f, 0x92, 0x92, 0x91, 0x63, 0x09, 0xbf, 0xbb,
+	0x93, 0x66, 0xaf, 0x05, 0x93, 0x09, 0x2b, 0xa0, 0x80, 0xee, 0x49, 0x1b, 0x4e, 0x03, 0xf8, 0xa2,
+	0xe6, 0xe9, 0x30, 0x06, 0x7d, 0x6a, 0x94, 0x25, 0xf2, 0x15, 0x29, 0x0b, 0x86, 0x5a, 0x1f, 0xee,
+	0x4f, 0x82, 0x91, 0xd9, 0x66, 0x82, 0x44, 0x5c, 0x07, 0xe6, 0x08, 0x0a, 0xce, 0x10, 0x6e, 0x4a,
+	0x13, 0x84, 0x49, 0x2f, 0x46, 0x03, 0x7e, 0x98, 0xb7, 0xba, 0x9d, 0x84, 0xb4, 0xb1, 0x6a, 0x34,
+	0xa6, 0x36, 0x7c, 0x81, 0xad, 0x9a, 0xb1, 0x7c, 0x6a, 0x13, 0xc2, 0x61, 0xd8, 0x4e, 0x73, 0x22,
+	0x07, 0xfe, 0x0c, 0x7f, 0x34, 0xa5, 0x12, 0x9c, 0x38, 0xea, 0xdd, 0xb8, 0x75, 0x08, 0xea, 0xf9,
+	0x13, 0x75, 0xf6, 0xc2, 0xfe, 0x1b, 0xc0, 0xa1, 0x2f, 0xc3, 0x73, 0x44, 0x01, 0x65, 0xa0, 0x38,
+	0x41, 0xf0, 0x04, 0xb0, 0x4f, 0x8d, 0x62, 0x05, 0x4a, 0x57, 0x02, 0x82, 0x20, 0x35, 0x1e, 0xbf,
+	0x04, 0x9b, 0xd9, 0x85, 0xa3, 0x6a, 0x44, 0x03, 0x30, 0xbb, 0x3e, 0x23, 0x5e, 0x6e, 0x29, 0xd6,
+	0xe0, 0x48, 0x24, 0x54, 0x77, 0x2e, 0x08, 0x5c, 0xac, 0x40, 0x4e, 0x15, 0x5f, 0xec, 0xa6, 0x07,
+	0x14, 0xd8, 0x2e, 0x5e, 0x72, 0x74, 0xc1, 0xe2, 0x21, 0x34, 0xde, 0x5c, 0xa3, 0x0a, 0xee, 0x2f,
+	0xc0, 0x11, 0x77, 0xd4, 0xcf, 0x3a, 0x07, 0x7d, 0x8c, 0x43, 0xa7, 0xc4, 0x1d, 0xb1, 0x01, 0x0a,
+	0x70, 0x79, 0xfb, 0x81, 0xba, 0x7a, 0x6e, 0xa0, 0x8b, 0xc7, 0x73, 0x7c, 0x55, 0xd8, 0x22, 0x3e,
+	0x8e, 0x3b, 0x3c, 0x37, 0x44, 0xbf, 0xc1, 0x46, 0x41, 0xfb, 0xbe, 0x2a, 0x41, 0x13, 0xf5, 0x58,
+	0xdb, 0x31, 0x0c, 0x32, 0x3d, 0xa6, 0x00, 0xfe, 0x62, 0x05, 0x17, 0x26, 0x7c, 0xf2, 0xf2, 0x2d,
+	0x2f, 0xd2, 0x93, 0x1d, 0xa8, 0x48, 0x2d, 0xe7, 0x33, 0x57, 0x88, 0x8b, 0x22, 0x71, 0xc0, 0x01,
+	0x87, 0xb1, 0x0a, 0x68, 0x92, 0x1c, 0x7c, 0xda, 0xd4, 0x6b, 0x93, 0x8d, 0x75, 0xea, 0xb5, 0x64,
+	0x7d, 0xc1, 0xa0, 0x9c, 0xf5, 0xc1, 0xba, 0x7e, 0x6b, 0x98, 0x24, 0x1f, 0x27, 0x8e, 0x2d, 0x87,
+	0xb1, 0xc5, 0x0f, 0x0e, 0xe3, 0x63, 0x17, 0x9c, 0xc0, 0x4e, 0xc1, 0x48, 0xe5, 0x44, 0x84, 0xb1,
+	0xca, 0x3d, 0x35, 0x2e, 0xba, 0xda, 0x0e, 0xfe, 0x2f, 0xfb, 0x82, 0xff, 0x29, 0xfa, 0xb4, 0x62,
+	0x25, 0x3f, 0xbc, 0x04, 0x7b, 0xd7, 0xfa, 0x8c, 0xf1, 0xf2, 0x9f, 0x15, 0x59, 0x2b, 0x8b, 0x61,
+	0x84, 0x7e, 0x4e, 0xd0, 0x84, 0x46, 0x09, 0x25, 0x9c, 0x3f, 0x01, 0xd7, 0xcd, 0xdf, 0xa0, 0x3b,
+	0x0c, 0xf6, 0xc8, 0x09, 0xf5, 0xcc, 0xe9, 0x17, 0x4b, 0xdb, 0x16, 0x7f, 0x72, 0x4f, 0x03, 0xdd,
+	0x71, 0x0c, 0x05, 0x12, 0xf5, 0xe2, 0x82, 0x11, 0x93, 0xb2, 0xa1, 0xc9, 0x92, 0x95, 0xd1, 0xba,
+	0x2e, 0x58, 0x8a, 0x66, 0x02, 0x4b, 0xb7, 0x98, 0x05, 0x03, 0xe3, 0x63, 0xcd, 0x99, 0x2e, 0x51,
+	0xe8, 0x79, 0x8c, 0x27, 0x38, 0x29, 0x78, 0xba, 0x8c, 0x59, 0x44, 0x1c, 0x01, 0x48, 0x5c, 0x7f,
+	0x90, 0x0e, 0xf8, 0x79, 0x95, 0x0c, 0x07, 0x40, 0x0f, 0xe3, 0x42, 0x59, 0x69, 0xd2, 0x25, 0x53,
+	0x93, 0xfe, 0x36, 0x3e, 0xbd, 0xa4, 0xd0, 0x4f, 0x1e, 0xa6, 0xdd, 0x51, 0x2f, 0x99, 0xa0, 0x66,
+	0xc2, 0xe2, 0x1e, 0x71, 0x1c, 0xe9, 0xf0, 0x15, 0x25, 0xad, 0x8a, 0x54, 0x4c, 0x55, 0xe4, 0x0f,
+	0xd9, 0x86, 0x67, 0x3c, 0x34, 0xab, 0x2d, 0xb6, 0xd0, 0xb2, 0x6a, 0x1c, 0x67, 0x67, 0x71, 0x5c,
+	0x91, 0xd3, 0x00, 0x5f, 0x63, 0x99, 0xbe, 0x95, 0x0e, 0x6e, 0x51, 0x44, 0x02, 0x4f, 0x2b, 0x54,
+	0xc1, 0x6c, 0xb2, 0x68, 0xc6, 0xc1, 0x94, 0x0b, 0x09, 0xf1, 0xc5, 0xd4, 0xe4, 0x79, 0x37, 0x35,
+	0xf9, 0xeb, 0x6c, 0x53, 0xdc, 0xaa, 0xa4, 0xb8, 0x2a, 0x20, 0x1d, 0x60, 0xe7, 0xf3, 0x3c, 0xe4,
+	0xb4, 0x9f, 0x1f, 0x4a, 0x4f, 0xc5, 0x24, 0x14, 0x14, 0x3a, 0xfc, 0x86, 0x47, 0xec, 0x04, 0x4a,
+	0xa5, 0x26, 0xb5, 0xb8, 0x50, 0x11, 0x5e, 0x63, 0xb3, 0x2a, 0xd4, 0x1a, 0x9a, 0xce, 0x1e, 0xa6,
+	0x03, 0x8a, 0xc7, 0xb6, 0x23, 0xfc, 0x69, 0xe6, 0x91, 0x46, 0x08, 0xdf, 0x61, 0x4f, 0xd1, 0x93,
+	0x31, 0xca, 0x7f, 0x46, 0x0f, 0x3f, 0x1b, 0xe1, 0x68, 0xa7, 0x73, 0xa3, 0x85, 0x17, 0xd9, 0x85,
+	0xb1, 0x7d, 0x91, 0xe4, 0x68, 0xb2, 0x0d, 0xeb, 0xda, 0x9e, 0x8b, 0x28, 0x7b, 0x97, 0x8e, 0x8d,
+	0xb0, 0xe7, 0xd6, 0x34, 0xda, 0xe6, 0x32, 0x49, 0x5d, 0xc6, 0xc2, 0x1b, 0xb0, 0x4b, 0x57, 0x55,
+	0x24, 0x87, 0x70, 0x91, 0x07, 0xd3, 0xac, 0xb2, 0x75, 0xe7, 0xce, 0xd2, 0xe7, 0x82, 0x1a, 0x9b,
+	0xbe, 0xbf, 0x73, 0xf3, 0xde, 0xed, 0x7b, 0x6f, 0x2f, 0x95, 0xb0, 0xb0, 0x7d, 0xe7, 0xfe, 0x2e,
+	0x16, 0xca, 0x57, 0x7f, 0xf5, 0x15, 0x36, 0xab, 0xf2, 0x93, 0x83, 0x0f, 0xd8, 0xbc, 0xf5, 0x9e,
+	0x43, 0xb0, 0x49, 0xd4, 0xf3, 0x3d, 0x10, 0xd1, 0x38, 0xe7, 0xaf, 0xa4, 0xe9, 0x3e, 0xf5, 0xbd,
+	0xdf, 0xfc, 0xd7, 0x0f, 0xcb, 0xf5, 0x60, 0xfd, 0xca, 0xd1, 0xcb, 0x57, 0xc8, 0x89, 0x72, 0x85,
+	0xbb, 0xf2, 0xc4, 0xbb, 0x70, 0x1f, 0xb2, 0x05, 0xfb, 0xbd, 0x87, 0xe0, 0x9c, 0xfb, 0x7a, 0x86,
+	0xf5, 0xb5, 0xf3, 0x63, 0x6a, 0xe9, 0x73, 0xe7, 0xf8, 0xe7, 0xd6, 0x83, 0x55, 0xf3, 0x73, 0x4a,
+	0xee, 0x27, 0xfc, 0x25, 0x3f, 0xf3, 0x35, 0xeb, 0x40, 0xf6, 0xe7, 0x7f, 0xe5, 0xba, 0xb1, 0x51,
+	0x7c, 0xb9, 0x9a, 0x9e, 0xba, 0x0e, 0xeb, 0xfc, 0x53, 0x41, 0xb0, 0x84, 0x9f, 0x32, 0x1f, 0xb3,
+	0x0e, 0xfe, 0x80, 0xcd, 0xaa, 0xb7, 0x71, 0x83, 0xb3, 0xc6, 0x4b, 0xc3, 0xe6, 0xeb, 0xbc, 0x8d,
+	0x7a, 0xb1, 0x82, 0x26, 0xb1, 0xc9, 0x7b, 0x5e, 0x0b, 0x0b, 0x3d, 0xbf, 0x51, 0xba, 0x14, 0xdc,
+	0x61, 0x6b, 0x2a, 0xca, 0xf1, 0xb3, 0xcc, 0xc4, 0xf3, 0x06, 0xf7, 0x4b, 0xa5, 0xe0, 0xcb, 0x6c,
+	0x46, 0x3e, 0x2f, 0x1c, 0xac, 0xfb, 0xdf, 0x44, 0x6e, 0x9c, 0x2d, 0xc0, 0x95, 0x34, 0x62, 0xfa,
+	0x75, 0xdc, 0xa0, 0x3e, 0xee, 0x11, 0x5f, 0x45, 0x44, 0xcf, 0x53, 0xba, 0x07, 0xfc, 0x71, 0x60,
+	0xfb, 0xf1, 0xdd, 0xe0, 0x82, 0xc6, 0xf7, 0x3e, 0xcb, 0x3b, 0xa1, 0xc3, 0x70, 0x9d, 0xd3, 0x6e,
+	0x29, 0x58, 0x40, 0xda, 0xf5, 0x41, 0x7d, 0xa6, 0x3e, 0x7f, 0x9f, 0xd5, 0x8c, 0x27, 0x74, 0x03,
+	0xe3, 0xb1, 0x28, 0xe7, 0xb5, 0xde, 0x46, 0xc3, 0x57, 0x45, 0xbd, 0xaf, 0xf2, 0xde, 0x17, 0x60,
+	0x1d, 0xc2, 0x59, 0xfc, 0x80, 0x78, 0x49, 0xf1, 0x1b, 0xb8, 0x79, 0xe8, 0xad, 0xc9, 0x40, 0x3f,
+	0xef, 0x6b, 0xbf, 0x48, 0xa9, 0xd6, 0xbb, 0xf0, 0x2c, 0x65, 0xb8, 0xcc, 0x7b, 0xad, 0x05, 0x46,
+	0x97, 0x77, 0xd9, 0x34, 0xbd, 0x39, 0x19, 0xac, 0xe9, 0x75, 0x35, 0xcc, 0x9c, 0xc6, 0xba, 0x0b,
+	0xa6, 0xce, 0x56, 0x78, 0x67, 0xf3, 0x41, 0x0d, 0x3b, 0x3b, 0x48, 0x40, 0x97, 0x83, 0x3e, 0xba,
+	0x6c, 0xd1, 0x7e, 0xef, 0x29, 0x53, 0xdb, 0xcc, 0xfb, 0x88, 0x95, 0xda, 0x66, 0xfe, 0x17, 0xa6,
+	0xec, 0x6d, 0x26, 0xb7, 0xd7, 0x15, 0xf9, 0x3e, 0xd7, 0x77, 0xd8, 0x9c, 0xf9, 0xe4, 0x6a, 0xd0,
+	0x30, 0x66, 0xee, 0x3c, 0xcf, 0xda, 0xd8, 0xf4, 0xd6, 0xd9, 0xe4, 0x0e, 0xe6, 0xcc, 0xcf, 0xc0,
+	0x52, 0x2e, 0x1a, 0x5e, 0xf7, 0xdd, 0x93, 0x7e, 0x4b, 0x2d, 0x67, 0xf1, 0xe5, 0xb6, 0x86, 0xcf,
+	0x63, 0x16, 0x9e, 0xe5, 0x1d, 0x2f, 0x87, 0x56, 0xc7, 0xb8, 0xbb, 0xb6, 0x59, 0xcd, 0xe8, 0x63,
+	0x52, 0xbf, 0x67, 0x8d, 0x2a, 0xf3, 0x65, 0x33, 0xd8, 0x54, 0x3f, 0xc1, 0x1c, 0x12, 0xe3, 0xed,
+	0xc1, 0xc0, 0xca, 0x97, 0x77, 0xfa, 0xa9, 0x9b, 0x75, 0x66, 0x47, 0xe1, 0x43, 0x3e, 0xc8, 0x9d,
+	0x4b, 0xf7, 0x2c, 0x22, 0x7f, 0x62, 0x59, 0x5d, 0x97, 0xcd, 0x77, 0xd6, 0x3f, 0x75, 0x2b, 0xcd,
+	0x97, 0xed, 0xa0, 0x92, 0xbb, 0xbe, 0x3f, 0x85, 0x01, 0x7e, 0xc0, 0x96, 0xdc, 0x67, 0xae, 0x82,
+	0xa7, 0x64, 0x70, 0xad, 0xff, 0xfd, 0xab, 0x86, 0xf9, 0x88, 0x9f, 0xfd, 0x08, 0x96, 0x94, 0x57,
+	0xc1, 0x8a, 0x35, 0x50, 0x7a, 0x55, 0x69, 0xc4, 0x96, 0xdc, 0x37, 0x9f, 0x82, 0xf1, 0x7d, 0x35,
+	0xe4, 0xde, 0x1f, 0xf7, 0x4e, 0x54, 0xf8, 0x79, 0xfe, 0xb1, 0x0b, 0xb8, 0x05, 0x1b, 0x9e, 0xef,
+	0x5d, 0x39, 0xe2, 0x0d, 0x83, 0x3f, 0x66, 0xcb, 0x85, 0x27, 0x9b, 0x94, 0x60, 0x19, 0xf7, 0x60,
+	0x54, 0xe3, 0xe9, 0xf1, 0x08, 0xf4, 0xf9, 0xe7, 0xf8, 0xe7, 0x9f, 0x0e, 0x37, 0x7d, 0xdf, 0x1e,
+	0x8a, 0x66, 0xc8, 0x48, 0xdf, 0x2f, 0xb1, 0x35, 0xef, 0xc3, 0x4c, 0xc1, 0x33, 0x32, 0xdd, 0x76,
+	0xc2, 0xe3, 0x4f, 0x8d, 0x67, 0x27, 0x23, 0xd1, 0x60, 0x9e, 0xe7, 0x83, 0xb9, 0x18, 0x9e, 0xb3,
+	0x06, 0x23, 0x1f, 0x88, 0xba, 0xd2, 0xe1, 0x8d, 0x71, 0x34, 0x6f, 0x88, 0xff, 0xae, 0x20, 0xd3,
+	0x36, 0x03, 0x43, 0xa2, 0xbb, 0xfb, 0xc4, 0xfc, 0xaf, 0x03, 0x2f, 0x94, 0x80, 0x59, 0xfe, 0x48,
+	0xbc, 0xa9, 0x4f, 0x6d, 0xf9, 0x76, 0x3b, 0x6d, 0xfb, 0xf0, 0x59, 0x3e, 0xc0, 0xa7, 0xc2, 0x0d,
+	0x6b, 0x80, 0xee, 0x91, 0xd6, 0x67, 0x0b, 0x76, 0x2a, 0x99, 0x12, 0x4e, 0xde, 0xd4, 0x33, 0x25,
+	0x9c, 0xfc, 0xf9, 0x67, 0xe1, 0x05, 0xfe, 0xd1, 0x8d, 0xe0, 0x2c, 0x17, 0xa7, 0xe4, 0x54, 0xba,
+	0x02, 0xba, 0x26, 0x25, 0x9d, 0x05, 0x3b, 0x8c, 0xe9, 0x8c, 0xf2, 0xc0, 0x49, 0x7f, 0x56, 0x8c,
+	0x5e, 0x4c, 0x3a, 0xb7, 0xc5, 0x86, 0x4c, 0x3a, 0xc6, 0x19, 0x7c, 0x20, 0x24, 0xde, 0x6d, 0x99,
+	0x87, 0xbc, 0x61, 0x8c, 0xd0, 0x4e, 0xe5, 0x6d, 0x34, 0x7c, 0x55, 0xd4, 0xff, 0x33, 0xbc, 0xff,
+	0xf3, 0xc1, 0xa6, 0xd9, 0xff, 0x95, 0x4f, 0xcc, 0x4c, 0xef, 0x4f, 0x83, 0x87, 0x6c, 0xfe, 0x4e,
+	0x9a, 0x02, 0xbb, 0xa9, 0xf7, 0x0c, 0xec, 0xeb, 0x05, 0xcc, 0x36, 0x6f, 0x38, 0x93, 0x0a, 0x2f,
+	0xf2, 0x9e, 0x37, 0x83, 0x0d, 0xbb, 0x67, 0x6d, 0x82, 0x7e, 0x1a, 0xc4, 0x6c, 0x59, 0x29, 0x16,
+	0x6a, 0x22, 0x0d, 0xbb, 0x1f, 0x33, 0xf6, 0xbc, 0xf0, 0x0d, 0x4b, 0xd5, 0x53, 0xdf, 0x50, 0x19,
+	0x1b, 0xc0, 0x4a, 0xb7, 0xd8, 0x8c, 0x4c, 0xbf, 0x0e, 0xac, 0xfc, 0x67, 0x25, 0x4d, 0xdd, 0xec,
+	0xec, 0x70, 0x8d, 0x77, 0xba, 0x18, 0x32, 0xec, 0x54, 0x24, 0x49, 0x23, 0xc1, 0xdf, 0x63, 0x4c,
+	0xe7, 0x58, 0x07, 0xe6, 0xd1, 0x6a, 0xe5, 0x62, 0x37, 0x36, 0x3c, 0x35, 0xd4, 0x73, 0xc0, 0x7b,
+	0x9e, 0x0b, 0x8c, 0x9e, 0x83, 0x1e, 0x5b, 0xa1, 0x96, 0x66, 0xf2, 0xb4, 0xa2, 0x82, 0x27, 0x35,
+	0x5b, 0x1d, 0x60, 0xbe, 0x6c, 0xeb, 0xf0, 0x3c, 0xff, 0xc6, 0xd9, 0x30, 0xd0, 0xdf, 0x90, 0x94,
+	0xc1, 0x59, 0xec, 0x80, 0x5d, 0x9d, 0xa0, 0x6f, 0x94, 0x92, 0x61, 0x57, 0xf4, 0x4a, 0xaa, 0x2c,
+	0xda, 0xc6, 0xbc, 0x05, 0xb4, 0x8f, 0x5e, 0xe0, 0x6e, 0x30, 0xfa, 0x81, 0x43, 0x84, 0xf5, 0xff,
+	0xa9, 0x3c, 0x7a, 0x65, 0xd2, 0xb1, 0x75, 0xf4, 0x3a, 0xf9, 0xcb, 0xd6, 0xd1, 0xeb, 0x66, 0x29,
+	0xdb, 0x47, 0xaf, 0xf2, 0xcc, 0x76, 0x31, 0x2f, 0xd9, 0x49, 0x6c, 0x56, 0x52, 0x75, 0x5c, 0xa2,
+	0xb4, 0x92, 0xaa, 0x63, 0x73, 0xa2, 0xe5, 0xd7, 0x2e, 0xd9, 0x5f, 0xdb, 0x65, 0xf3, 0x37, 0x12,
+	0xc1, 0x3c, 0xe2, 0x65, 0x25, 0xc7, 0xbb, 0x63, 0xbe, 0xc2, 0xe4, 0x9e, 0xf3, 0xbc, 0xce, 0xd6,
+	0xac, 0xf8, 0xb3, 0x46, 0xa0, 0x9c, 0xd7, 0x40, 0x65, 0x92, 0x4f, 0x29, 0x29, 0xa5, 0xd7, 0x79,
+	0x5b, 0xa9, 0xe1, 0x79, 0x89, 0x29, 0x7c, 0x9a, 0xf7, 0xd6, 0x08, 0xea, 0xaa, 0xb7, 0x2b, 0x98,
+	0x7d, 0x22, 0x4e, 0x5d, 0xb0, 0x8d, 0x3f, 0x0d, 0xbe, 0xc9, 0x3b, 0x57, 0x2f, 0xa2, 0xad, 0x1b,
+	0x69, 0x28, 0x66, 0xe7, 0x8b, 0x0e, 0xdc, 0xd7, 0x33, 0x5a, 0xe2, 0xb0, 0xb0, 0xc2, 0x87, 0x80,
+	0x3d, 0x33, 0x9e, 0x29, 0x23, 0xde, 0x8a, 0x5b, 0xb1, 0x02, 0xfd, 0xa8, 0x57, 0x2b, 0xfa, 0x4f,
+	0x9e, 0x0d, 0xc1, 0x05, 0xdd, 0x25, 0x8f, 0x03, 0xd4, 0x7d, 0x5e, 0xf9, 0x24, 0xee, 0xe5, 0x9f,
+	0x06, 0xef, 0xf3, 0x17, 0xcf, 0xcd, 0x87, 0xa1, 0xb4, 0x7a, 0xed, 0xbe, 0x21, 0xa5, 0xc8, 0x62,
+	0x54, 0xd9, 0x2a, 0xb7, 0xf8, 0x12, 0x57, 0x3a, 0xdf, 0x37, 0x2c, 0x15, 0xeb, 0x81, 0x2c, 0xc9,
+	0x0f, 0x63, 0xdf, 0x41, 0x52, 0x42, 0xd2, 0xf3, 0x16, 0x92, 0x34, 0x5a, 0xc4, 0x03, 0x2f, 0x86,
+	0xd1, 0x62, 0xbd, 0x10, 0x63, 0x18, 0x2d, 0xf6, 0x4b, 0x30, 0x68, 0xb4, 0xe8, 0x94, 0x78, 0x25,
+	0x39, 0x0a, 0xd9, 0xf6, 0x4a, 0x72, 0x78, 0xf2, 0xe7, 0x6f, 0xb0, 0xc0, 0xca, 0xa5, 0xe0, 0x26,
+	0x7c, 0xe0, 0x53, 0x34, 0x1b, 0x1b, 0x1e, 0x7f, 0x24, 0x65, 0xd3, 0xdf, 0x55, 0x96, 0x2f, 0x45,
+	0x77, 0xbb, 0x96, 0xaf, 0x1d, 0x81, 0xef, 0x5a, 0xbe, 0x6e, 0x48, 0xf8, 0x43, 0xf4, 0x84, 0x89,
+	0xa4, 0x53, 0x2b, 0x89, 0x55, 0xf5, 0xea, 0x4d, 0x6d, 0x55, 0x42, 0xc0, 0x97, 0x87, 0xcb, 0x8f,
+	0xff, 0x6f, 0x8b, 0xc7, 0x15, 0x9c, 0x94, 0xcb, 0xe0, 0xa2, 0x21, 0x3c, 0xfc, 0xc9, 0x9a, 0x8d,
+	0x70, 0x12, 0x0a, 0x8d, 0x7a, 0x8f, 0xad, 0x79, 0x33, 0x27, 0x95, 0x96, 0x34, 0x29, 0x0f, 0x53,
+	0x69, 0x49, 0x13, 0x93, 0x2f, 0x83, 0xdb, 0xa0, 0xc0, 0x48, 0x3e, 0x14, 0x69, 0x82, 0x5a, 0xaf,
+	0x2f, 0x24, 0x65, 0x36, 0xec, 0x2a, 0x33, 0xdf, 0x12, 0x88, 0xb1, 0xcd, 0xd6, 0xb6, 0x5a, 0x1f,
+	0x7a, 0x52, 0x31, 0x97, 0xac, 0x56, 0x80, 0xa3, 0xf4, 0xfa, 0x42, 0xfa, 0x63, 0x90, 0xb0, 0x75,
+	0x7f, 0xce, 0x62, 0xf0, 0xac, 0x52, 0x3f, 0x27, 0x64, 0x47, 0x36, 0x3e, 0xff, 0x18, 0x2c, 0xfa,
+	0x0c, 0x2c, 0x9c, 0x27, 0xb7, 0x4e, 0x2d, 0xdc, 0xf8, 0xac, 0x3c, 0xb5, 0x70, 0x93, 0x52, 0xf3,
+	0xbe, 0x8d, 0x27, 0x65, 0x21, 0xe9, 0x4d, 0xf5, 0x3e, 0x3e, 0xc5, 0x4e, 0xf5, 0x3e, 0x21, 0x67,
+	0x0e, 0x0e, 0xc6, 0x55, 0x5f, 0xce, 0x9c, 0x7f, 0x8f, 0x3d, 0xa3, 0x82, 0xf0, 0x26, 0x64, 0xd9,
+	0xed, 0xb2, 0xb3, 0x5a, 0x18, 0x99, 0x09, 0x65, 0x99, 0x12, 0x47, 0x63, 0xb3, 0xec, 0x1a, 0xab,
+	0x3e, 0x0c, 0x60, 0x87, 0x87, 0xf4, 0x4f, 0x90, 0xac, 0x4c, 0xba, 0x0b, 0xa6, 0x5f, 0xc7, 0x93,
+	0x12, 0xa7, 0x8e, 0xc3, 0xb1, 0xb9, 0x6d, 0x20, 0x1a, 0x48, 0xc0, 0x98, 0x79, 0x5f, 0xea, 0xf4,
+	0xf3, 0xa4, 0xbd, 0xa9, 0x6d, 0xec, 0x4d, 0x14, 0x7b, 0x80, 0x9b, 0xcc, 0x93, 0x29, 0x64, 0x6c,
+	0xb2, 0xf1, 0x59, 0x55, 0x8d, 0x75, 0x4f, 0xd6, 0x10, 0x36, 0xde, 0x73, 0x0c, 0x9c, 0x42, 0xaf,
+	0x93, 0x72, 0xb5, 0xfc, 0x06, 0x4e, 0x21, 0x85, 0x09, 0x64, 0xa4, 0x9d, 0x01, 0xa3, 0xa4, 0x99,
+	0x37, 0x4b, 0x49, 0xc9, 0xc8, 0x31, 0x69, 0x33, 0x24, 0xcb, 0x9c, 0xcc, 0x0b, 0x4b, 0x96, 0xf9,
+	0x93, 0x63, 0x2c, 0x59, 0x36, 0x2e, 0x71, 0x63, 0x87, 0x2d, 0x3a, 0x49, 0x12, 0xca, 0x27, 0xe7,
+	0xcf, 0xd1, 0x68, 0x3c, 0x35, 0xae, 0x9a, 0x7a, 0x7c, 0x57, 0xfc, 0x53, 0x2f, 0x33, 0x21, 0x41,
+	0x71, 0x81, 0x27, 0xe7, 0xa2, 0xb1, 0xe1, 0xad, 0xc3, 0x0c, 0x06, 0x60, 0xd6, 0x2d, 0x36, 0x67,
+	0x46, 0xf6, 0xab, 0x8e, 0x3c, 0xe1, 0xfe, 0x0d, 0xe5, 0x73, 0xb2, 0x83, 0xef, 0xaf, 0xb3, 0x39,
+	0x33, 0x88, 0x3e, 0xf0, 0xa3, 0xe9, 0x33, 0xc5, 0x17, 0x70, 0x8f, 0x87, 0x37, 0x85, 0xb9, 0xeb,
+	0xc3, 0xdb, 0x8e, 0xae, 0xd7, 0x87, 0xb7, 0x1b, 0x0f, 0xff, 0x2d, 0x3b, 0x9e, 0x9d, 0x1c, 0xdc,
+	0x4f, 0x7b, 0x42, 0xbd, 0xad, 0x40, 0xf8, 0xc6, 0xc5, 0x09, 0x18, 0xd4, 0xf5, 0x3b, 0xa0, 0x6c,
+	0x9a, 0x41, 0xd3, 0xca, 0xe9, 0xed, 0x8b, 0x10, 0x57, 0x4e, 0x6f, 0x7f, 0x9c, 0xf5, 0x4d, 0xe9,
+	0x5f, 0xd1, 0x71, 0xc1, 0x4a, 0xd3, 0x28, 0x44, 0x55, 0x6b, 0xdb, 0xc7, 0x0d, 0x37, 0xbe, 0xc1,
+	0x16, 0xec, 0xe0, 0x61, 0xbf, 0xfc, 0x93, 0x4c, 0x36, 0x26, 0xd0, 0x18, 0xf6, 0x90, 0x1d, 0x1e,
+	0xac, 0x35, 0x02, 0x5f, 0x3c, 0xb1, 0xea, 0x6e, 0x4c, 0x4c, 0x31, 0xe8, 0x4f, 0x3a, 0x66, 0x57,
+	0xcd, 0xaa, 0x10, 0xfb, 0xab, 0x78, 0xd1, 0x13, 0xe0, 0x7b, 0x03, 0xff, 0x85, 0x9b, 0x0a, 0xba,
+	0x0d, 0xb4, 0xc1, 0xed, 0x06, 0xee, 0x2a, 0x3d, 0xd0, 0x17, 0xa3, 0xfb, 0x80, 0xad, 0x78, 0x82,
+	0x70, 0x27, 0xb9, 0xec, 0xe4, 0x26, 0x9e, 0x14, 0xbb, 0x7b, 0x8b, 0x2d, 0xb9, 0x31, 0x9c, 0xca,
+	0x35, 0x36, 0x26, 0xb8, 0x53, 0x1d, 0x0f, 0x76, 0xab, 0xfb, 0x6c, 0xc5, 0x13, 0x36, 0x19, 0x78,
+	0x91, 0xd5, 0xd0, 0x26, 0x04, 0x5a, 0x4a, 0xe9, 0xe5, 0xc4, 0x1d, 0x5a, 0xd2, 0xcb, 0x1f, 0x84,
+	0x69, 0x49, 0xaf, 0x71, 0x61, 0x8b, 0xb8, 0x2f, 0x29, 0xec, 0x4e, 0xef, 0x4b, 0x3b, 0x28, 0x51,
+	0xef, 0x4b, 0x37, 0x3e, 0xef, 0x0e, 0x0b, 0x8a, 0xd1, 0x66, 0x81, 0x2f, 0x3e, 0x4c, 0x6d, 0xc5,
+	0xf1, 0xd1, 0x69, 0x70, 0x56, 0x2f, 0xb9, 0x21, 0x68, 0x6a, 0x0d, 0xc6, 0x84, 0xad, 0x29, 0xbf,
+	0xe1, 0xd8, 0xd8, 0xb5, 0x6f, 0xe1, 0x53, 0x58, 0x6e, 0x64, 0x59, 0x60, 0x9b, 0xa6, 0xbe, 0x8e,
+	0x2f, 0x4e, 0xc0, 0xd0, 0xe3, 0x75, 0x83, 0xc7, 0xd4, 0x78, 0xc7, 0xc4, 0xab, 0xa9, 0xf1, 0x8e,
+	0x8d, 0x3a, 0xfb, 0x1a, 0x9b, 0x55, 0x51, 0x4c, 0xea, 0x52, 0xc1, 0x0d, 0x76, 0x52, 0x5a, 0x66,
+	0x31, 0xe0, 0xe9, 0x1d, 0xeb, 0x1a, 0x30, 0xd1, 0xf2, 0xcc, 0x17, 0x0c, 0xd4, 0x38, 0xe7, 0xaf,
+	0xa4, 0xbe, 0xae, 0xb3, 0x79, 0x2b, 0x3a, 0xc2, 0x2f, 0x87, 0x64, 0x1f, 0xde, 0x40, 0x0a, 0x98,
+	0x4f, 0xcd, 0x08, 0xa4, 0xf0, 0xf7, 0x20, 0xb7, 0xbb, 0x27, 0xe2, 0x22, 0x78, 0x9b, 0xcd, 0x99,
+	0xa1, 0x10, 0xda, 0x17, 0x50, 0x0c, 0xc3, 0x50, 0x07, 0x90, 0x37, 0x76, 0x02, 0x08, 0x63, 0x85,
+	0x0c, 0x04, 0xfa, 0xb8, 0x2a, 0xc6, 0x4d, 0xa8, 0x49, 0xf9, 0xa3, 0x0c, 0x1e, 0x62, 0xa8, 0x99,
+	0x73, 0x59, 0xaf, 0x14, 0xc0, 0x71, 0x61, 0x05, 0x4a, 0x01, 0x1c, 0x7f, 0xcf, 0x7f, 0xc8, 0xce,
+	0x8e, 0xb9, 0x47, 0x0e, 0x3e, 0x6f, 0xff, 0x9b, 0x93, 0x31, 0x77, 0xd6, 0x8d, 0xe7, 0x1e, 0x87,
+	0xa6, 0x54, 0x8c, 0xa0, 0x78, 0x1d, 0xad, 0xb6, 0xc5, 0xd8, 0x9b, 0x6a, 0xef, 0xb5, 0xca, 0xde,
+	0x19, 0xfe, 0x8f, 0x92, 0x5f, 0xf9, 0x3f, 0x68, 0x0e, 0x59, 0xc7, 0x5a, 0x79, 0x00, 0x00,
 }
@@ -0,0 +1,143 @@
+package lnwallet
+
+import (
+	"fmt"
+
+	"github.com/roasbeef/btcd/blockchain"
+	"github.com/roasbeef/btcd/txscript"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+const (
+	// anchorWitnessSize is the size of the witness spending an anchor
+	// output with a signature under its funding key: the number of
+	// witness elements, the signature, and the anchor script, each along
+	// with its length.
+	anchorWitnessSize = 1 + 1 + 73 + 1 + 40
+
+	// anchorSpendSize is an estimate of the virtual size of an input
+	// spending an anchor output.
+	anchorSpendSize = 32 + 4 + 1 + 4 + (anchorWitnessSize+3)/4
+)
+
+// anchorChildAmt returns the amount the wallet's coins must contribute to the
+// fee of a child spending our anchor output of a commitment transaction of
+// the passed virtual size and fee, for the commitment and the spend of the
+// anchor together to pay the passed fee rate, expressed in sat/byte. The fee
+// of spending the wallet's coins is left to coin selection. If the commitment
+// already pays enough that the anchor's own value makes up the difference,
+// then zero is returned, as no child is warranted.
+func anchorChildAmt(parentSize int64, parentFee btcutil.Amount,
+	feeRate uint64) btcutil.Amount {
+
+	packageFee := btcutil.Amount(uint64(parentSize+anchorSpendSize) *
+		feeRate)
+	amt := packageFee - parentFee - AnchorSize
+	if amt < 0 {
+		return 0
+	}
+
+	return amt
+}
+
+// CreateAnchorChild creates a transaction spending our anchor output of the
+// commitment transaction described by the passed summary, along with coins of
+// the wallet, which bumps the fee of the commitment through CPFP so the two
+// together pay the passed fee rate, expressed in sat/byte. Any change is paid
+// back to the wallet. If the commitment carries no anchor of ours, or already
+// pays the fee rate, then nil is returned. The coins spent remain locked, and
+// should be unlocked via UnlockAnchorChild should the child be abandoned.
+func (l *LightningWallet) CreateAnchorChild(summary *ForceCloseSummary,
+	feeRate uint64) (*wire.MsgTx, error) {
+
+	if summary.AnchorSignDesc == nil {
+		return nil, nil
+	}
+
+	weight := blockchain.GetTransactionWeight(btcutil.NewTx(summary.CloseTx))
+	parentSize := (weight + blockchain.WitnessScaleFactor - 1) /
+		blockchain.WitnessScaleFactor
+
+	amt := anchorChildAmt(parentSize, summary.CloseFee, feeRate)
+	if amt == 0 {
+		return nil, nil
+	}
+
+	contribution := &ChannelContribution{}
+	err := l.selectCoinsAndChange(feeRate, amt, contribution)
+	if err != nil {
+		return nil, err
+	}
+
+	childTx := wire.NewMsgTx(2)
+	childTx.AddTxIn(wire.NewTxIn(&summary.AnchorOutpoint, nil, nil))
+	for _, txIn := range contribution.Inputs {
+		childTx.AddTxIn(txIn)
+	}
+	for _, txOut := range contribution.ChangeOutputs {
+		childTx.AddTxOut(txOut)
+	}
+
+	// The child must carry an output, so it's only created if coin
+	// selection left change to pay back to the wallet.
+	if len(childTx.TxOut) == 0 {
+		l.UnlockAnchorChild(childTx)
+		return nil, fmt.Errorf("no change left by coin selection to " +
+			"pay to the anchor child")
+	}
+
+	// Our anchor is the first input, while the remaining inputs spend
+	// coins of the wallet.
+	hashCache := txscript.NewTxSigHashes(childTx)
+	anchorSignDesc := *summary.AnchorSignDesc
+	anchorSignDesc.SigHashes = hashCache
+	anchorSignDesc.InputIndex = 0
+	witness, err := AnchorSpend(l.Signer, &anchorSignDesc, childTx)
+	if err != nil {
+		l.UnlockAnchorChild(childTx)
+		return nil, err
+	}
+	childTx.TxIn[0].Witness = witness
+
+	signDesc := SignDescriptor{
+		HashType:  txscript.SigHashAll,
+		SigHashes: hashCache,
+	}
+	for i := 1; i < len(childTx.TxIn); i++ {
+		txIn := childTx.TxIn[i]
+		info, err := l.FetchInputInfo(&txIn.PreviousOutPoint)
+		if err != nil {
+			l.UnlockAnchorChild(childTx)
+			return nil, err
+		}
+
+		signDesc.Output = info
+		signDesc.InputIndex = i
+
+		inputScript, err := l.Signer.ComputeInputScript(childTx,
+			&signDesc)
+		if err != nil {
+			l.UnlockAnchorChild(childTx)
+			return nil, err
+		}
+
+		txIn.SignatureScript = inputScript.ScriptSig
+		txIn.Witness = inputScript.Witness
+	}
+
+	return childTx, nil
+}
+
+// UnlockAnchorChild unlocks the coins of the wallet spent by the passed child
+// created by CreateAnchorChild, which has since been abandoned.
+func (l *LightningWallet) UnlockAnchorChild(childTx *wire.MsgTx) {
+	l.limboMtx.Lock()
+	defer l.limboMtx.Unlock()
+
+	// The first input spends our anchor rather than a coin of the wallet.
+	for _, txIn := range childTx.TxIn[1:] {
+		delete(l.lockedOutPoints, txIn.PreviousOutPoint)
+		l.UnlockOutpoint(txIn.PreviousOutPoint)
+	}
+}
@@ -0,0 +1,47 @@
+package lnwallet
+
+import (
+	"testing"
+
+	"github.com/roasbeef/btcutil"
+)
+
+// TestAnchorChildAmt tests that the wallet's coins only contribute to the fee
+// of an anchor child when the commitment's fee, along with the value of the
+// anchor itself, falls short of the package fee.
+func TestAnchorChildAmt(t *testing.T) {
+	const (
+		feeRate    = 10
+		parentSize = 700
+	)
+	packageFee := btcutil.Amount((parentSize + anchorSpendSize) * feeRate)
+
+	tests := []struct {
+		name      string
+		parentFee btcutil.Amount
+		amt       btcutil.Amount
+	}{
+		{
+			name:      "commitment underpays",
+			parentFee: 1000,
+			amt:       packageFee - 1000 - AnchorSize,
+		},
+		{
+			name:      "anchor makes up the difference",
+			parentFee: packageFee - AnchorSize,
+			amt:       0,
+		},
+		{
+			name:      "commitment pays the fee rate",
+			parentFee: packageFee,
+			amt:       0,
+		},
+	}
+	for _, test := range tests {
+		amt := anchorChildAmt(parentSize, test.parentFee, feeRate)
+		if amt != test.amt {
+			t.Fatalf("%v: expected amount of %v, got %v", test.name,
+				test.amt, amt)
+		}
+	}
+}
@@ -3,8 +3,10 @@ package btcwallet
 import (
 	"bytes"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"math"
+	"strconv"
 	"sync"
 	"time"
 
@@ -249,6 +251,24 @@ func (b *BtcWallet) NewRawKey() (*btcec.PublicKey, error) {
 	return pkAddr.(waddrmgr.ManagedPubKeyAddress).PubKey(), nil
 }
 
+// ImportPrivateKey imports the passed private key into the wallet's imported
+// account. No rescan is performed, as the key is only expected to be used to
+// sign for the funding outputs of recovered channels.
+//
+// This is a part of the WalletController interface.
+func (b *BtcWallet) ImportPrivateKey(key *btcec.PrivateKey) error {
+	wif, err := btcutil.NewWIF(key, b.netParams, true)
+	if err != nil {
+		return err
+	}
+
+	_, err = b.wallet.ImportPrivateKey(wif, nil, false)
+	if waddrmgr.IsError(err, waddrmgr.ErrDuplicateAddress) {
+		return nil
+	}
+	return err
+}
+
 // FetchRootKey returns a root key which is intended to be used as an initial
 // seed/salt to generate any Lightning specific secrets.
 //
@@ -381,6 +401,38 @@ func (b *BtcWallet) PublishTransaction(tx *wire.MsgTx) error {
 	return b.wallet.PublishTransaction(tx)
 }
 
+// EstimateFeePerByte returns the fee rate in satoshis per byte expected to
+// confirm a transaction within the passed number of blocks, as estimated by
+// the btcd node.
+//
+// This is a part of the WalletController interface.
+func (b *BtcWallet) EstimateFeePerByte(numBlocks uint32) (btcutil.Amount, error) {
+	params := []json.RawMessage{
+		json.RawMessage(strconv.FormatUint(uint64(numBlocks), 10)),
+	}
+	resp, err := b.rpc.RawRequest("estimatefee", params)
+	if err != nil {
+		return 0, err
+	}
+
+	// The fee rate is returned in BTC/kB, or as -1 if the node hasn't
+	// seen enough blocks to produce an estimate.
+	var btcPerKB float64
+	if err := json.Unmarshal(resp, &btcPerKB); err != nil {
+		return 0, err
+	}
+	if btcPerKB <= 0 {
+		return 0, lnwallet.ErrNoFeeEstimate
+	}
+
+	satPerKB, err := btcutil.NewAmount(btcPerKB)
+	if err != nil {
+		return 0, err
+	}
+
+	return satPerKB / 1000, nil
+}
+
 // extractBalanceDelta extracts the net balance delta from the PoV of the
 // wallet given a TransactionSummary.
 func extractBalanceDelta(txSummary base.TransactionSummary) (btcutil.Amount, error) {
@@ -5,13 +5,16 @@ import (
 	"container/list"
 	"crypto/sha256"
 	"fmt"
+	"sort"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/davecgh/go-spew/spew"
 	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/shachain"
 	"github.com/roasbeef/btcd/blockchain"
 	"github.com/roasbeef/btcd/chaincfg/chainhash"
 
@@ -44,9 +47,133 @@ var (
 	// maximum number of allowed HTLC's if committed in a state transition
 	ErrMaxHTLCNumber = fmt.Errorf("commitment transaction exceed max " +
 		"htlc number")
+
+	// ErrBelowMinHTLC is returned when a proposed HTLC is smaller than
+	// the minimum HTLC amount permitted by the channel's policy.
+	ErrBelowMinHTLC = fmt.Errorf("htlc amount below minimum htlc amount")
+
+	// ErrMaxDustExposure is returned when a proposed HTLC would push the
+	// total value of dust HTLCs within the channel beyond the maximum
+	// dust exposure permitted by the channel's policy.
+	ErrMaxDustExposure = fmt.Errorf("htlc would exceed max dust exposure")
+
+	// ErrMaxHashExposure is returned when a proposed HTLC shares its
+	// payment hash with HTLCs already pending within the channel, and
+	// would push their total value beyond the maximum permitted by the
+	// channel's policy.
+	ErrMaxHashExposure = fmt.Errorf("htlc would exceed max exposure to " +
+		"its payment hash")
+
+	// ErrBelowChanReserve is returned when a proposed HTLC would push the
+	// balance of the party offering it below the channel reserve.
+	ErrBelowChanReserve = fmt.Errorf("htlc would push balance below " +
+		"channel reserve")
+
+	// ErrRecoveredChannel is returned when an operation other than a
+	// cooperative close is attempted on a channel reconstructed from
+	// another implementation's recovery data.
+	ErrRecoveredChannel = fmt.Errorf("channel was recovered from " +
+		"external data, it may only be cooperatively closed")
+
+	// ErrDeviceFundingKey is returned when an operation which requires a
+	// new commitment signature is attempted on a channel whose funding
+	// key is held by an external signing device. As the device must
+	// approve each signature, such channels can't be updated off-chain,
+	// and may only be closed.
+	ErrDeviceFundingKey = fmt.Errorf("channel funding key is held by " +
+		"a signing device, the channel can't be updated")
+
+	// ErrChannelFrozen is returned when an operation which requires a
+	// signature is attempted on a channel whose in-memory state has
+	// diverged from its persisted state. Signing from a stale state risks
+	// broadcasting a revoked commitment, so the channel remains frozen
+	// until the operator acknowledges the divergence.
+	ErrChannelFrozen = fmt.Errorf("channel state has diverged from " +
+		"disk, signing is frozen until acknowledged")
+
+	// ErrCommitSyncDataLoss is returned when the remote party proves,
+	// upon re-establishing the channel, that we've revoked commitments
+	// beyond our own latest state, meaning we've lost our state for the
+	// channel, for instance by restoring an outdated database. Our latest
+	// commitment is likely revoked, so it mustn't be broadcast, and the
+	// channel refuses all further signatures while we wait for the remote
+	// party to force close it.
+	ErrCommitSyncDataLoss = fmt.Errorf("remote party holds revocations " +
+		"beyond our latest state, our channel state has been lost")
+
+	// ErrCommitSyncRemoteDataLoss is returned when the remote party's
+	// view of the channel, as sent upon re-establishing it, lags behind
+	// the state we've proven it has committed to, meaning the remote
+	// party has lost its state for the channel.
+	ErrCommitSyncRemoteDataLoss = fmt.Errorf("remote party is behind " +
+		"our view of the channel, its channel state has been lost")
+
+	// ErrCannotSyncCommitChains is returned when the remote party claims,
+	// upon re-establishing the channel, to hold a commitment beyond the
+	// latest we've signed, so the views of the channel can't be
+	// reconciled by retransmitting our latest messages.
+	ErrCannotSyncCommitChains = fmt.Errorf("unable to synchronize " +
+		"commitment chains, remote party is ahead of our signatures")
+
+	// ErrInvalidLastCommitSecret is returned when the latest revocation
+	// the remote party claims to have received from us upon
+	// re-establishing the channel doesn't match our revocation producer.
+	ErrInvalidLastCommitSecret = fmt.Errorf("remote party sent an " +
+		"invalid revocation of our commitment")
+
+	// ErrCloseFeeTooLow is returned when a replacement cooperative closure
+	// transaction doesn't pay a higher fee than the version of the same
+	// party it replaces.
+	ErrCloseFeeTooLow = fmt.Errorf("replacement closure transaction must " +
+		"pay a higher fee")
+
+	// ErrNonInitiatorFeeUpdate is returned when the party which didn't
+	// initiate the channel attempts to update the commitment fee. As the
+	// fee is paid by the initiator, only they may update it.
+	ErrNonInitiatorFeeUpdate = fmt.Errorf("only the initiator of a " +
+		"channel may update its commitment fee")
+
+	// ErrFeeUnaffordable is returned when a proposed commitment fee
+	// exceeds the available balance of the initiator.
+	ErrFeeUnaffordable = fmt.Errorf("commitment fee exceeds the " +
+		"initiator's available balance")
+
+	// ErrSpliceInProgress is returned when an update is attempted on a
+	// channel which is being spliced. The commitment transactions
+	// spending the splice's funding output are signed at the channel's
+	// current height, so the channel is frozen until the splice
+	// transaction is buried deep enough to be migrated to.
+	ErrSpliceInProgress = fmt.Errorf("channel is being spliced, " +
+		"operation disallowed")
+
+	// ErrChanShuttingDown is returned when a new HTLC is offered over a
+	// channel after either party has signalled its intent to
+	// cooperatively close it.
+	ErrChanShuttingDown = fmt.Errorf("channel is shutting down, " +
+		"operation disallowed")
+
+	// ErrOutgoingFrozen is returned when a new HTLC is offered over a
+	// channel the operator has frozen. HTLCs offered by the remote party
+	// are still accepted.
+	ErrOutgoingFrozen = fmt.Errorf("channel is frozen, outgoing HTLCs " +
+		"disallowed")
 )
 
 const (
+	// DefaultCoopCloseFee is the fee initially paid by each party's version
+	// of the cooperative closure transaction. If the transaction fails to
+	// confirm in a timely manner, then its proposer is able to bump this
+	// fee.
+	//
+	// TODO(roasbeef): take sat/byte here instead of properly calc
+	DefaultCoopCloseFee = btcutil.Amount(5000)
+
+	// AnchorSize is the value of each anchor output carried by commitment
+	// transactions of the anchor format. Both anchors are paid for by the
+	// initiator of the channel, and the value of an anchor omitted from a
+	// commitment transaction goes to its fee.
+	AnchorSize = btcutil.Amount(330)
+
 	// InitialRevocationWindow is the number of revoked commitment
 	// transactions allowed within the commitment chain. This value allows
 	// a greater degree of de-synchronization by allowing either parties to
@@ -113,6 +240,14 @@ const (
 	// original add entry from the remote party's log after the next state
 	// transition.
 	Settle
+
+	// FeeUpdate is an update type which sets a new fee for the commitment
+	// transaction, with the Amount of the entry being the new fee. Only
+	// the initiator of the channel may add a FeeUpdate entry to their
+	// log. The difference between the prior fee and the new fee is
+	// credited to, or debited from, the initiator's balance once a new
+	// commitment view containing the entry has been evaluated.
+	FeeUpdate
 )
 
 // PaymentDescriptor represents a commitment state update which either adds,
@@ -176,6 +311,10 @@ type PaymentDescriptor struct {
 	// possible upstream peers in the route.
 	isForwarded bool
 
+	// addTime is the time an Add entry was appended to the log. For HTLCs
+	// restored from disk, this is the time the channel was restored.
+	addTime time.Time
+
 	// pkScript is the raw public key  script that encodes the redemption
 	// rules for this particular HTLC. This field will only be populated
 	// iff the EntryType of this PaymentDescriptor is Add.
@@ -219,6 +358,10 @@ type commitment struct {
 	ourBalance   btcutil.Amount
 	theirBalance btcutil.Amount
 
+	// fee is the fee paid by the commitment transaction, deducted from
+	// the balance of the initiator.
+	fee btcutil.Amount
+
 	// htlcs is the set of HTLCs which remain unsettled within this
 	// commitment.
 	outgoingHTLCs []*PaymentDescriptor
@@ -233,6 +376,7 @@ func (c *commitment) toChannelDelta() (*channeldb.ChannelDelta, error) {
 	delta := &channeldb.ChannelDelta{
 		LocalBalance:  c.ourBalance,
 		RemoteBalance: c.theirBalance,
+		CommitFee:     c.fee,
 		UpdateNum:     c.height,
 		Htlcs:         make([]*channeldb.HTLC, 0, numHtlcs),
 	}
@@ -432,6 +576,20 @@ func compactLogs(ourLog, theirLog *updateLog,
 				continue
 			}
 
+			// A fee update has no parent entry, so it's evicted
+			// by itself once it's been committed to within the
+			// tail of both chains.
+			if htlc.EntryType == FeeUpdate {
+				if htlc.addCommitHeightRemote != 0 &&
+					htlc.addCommitHeightLocal != 0 &&
+					remoteChainTail >= htlc.addCommitHeightRemote &&
+					localChainTail >= htlc.addCommitHeightLocal {
+
+					logA.remove(htlc.Index)
+				}
+				continue
+			}
+
 			// If the HTLC hasn't yet been removed from either
 			// chain, the skip it.
 			if htlc.removeCommitHeightRemote == 0 ||
@@ -500,6 +658,49 @@ type LightningChannel struct {
 
 	status channelState
 
+	// deviceFundingKey denotes that our funding key is held by an
+	// external signing device rather than the wallet. Commitment updates
+	// are rejected for such channels, as each one would require the
+	// device to sign.
+	deviceFundingKey bool
+
+	// divergence, if non-nil, is the divergence between the channel's
+	// in-memory and persisted state which has frozen the channel. While
+	// frozen, no new signatures are produced for the channel.
+	divergence *ErrStateDiverged
+
+	// dataLoss denotes that the remote party has proven that we've lost
+	// our state for the channel. As our latest commitment is likely
+	// revoked, no new signatures, including those needed to force close
+	// the channel, are ever produced for the channel.
+	dataLoss bool
+
+	// splice, if non-nil, is the splice of additional funds into the
+	// channel which is either being negotiated, or awaiting enough
+	// confirmations to be migrated to. While set, no updates to the
+	// channel are accepted.
+	splice *spliceState
+
+	// localCloseFee is the fee paid by the latest version of our own
+	// cooperative closure transaction, and remoteCloseFee is that of the
+	// remote party's version. Each is only set once the respective party
+	// has proposed a closure transaction.
+	localCloseFee  btcutil.Amount
+	remoteCloseFee btcutil.Amount
+
+	// closeVersions is each version of the cooperative closure
+	// transaction we've signed, in the order they were signed. Each is
+	// written to disk as it's signed, so the closure can be resumed after
+	// a restart.
+	closeVersions []*channeldb.CoopCloseVersion
+
+	// localShutdown and remoteShutdown denote that we, and the remote
+	// party respectively, have signalled the intent to cooperatively
+	// close the channel. Once we've sent a shutdown, we offer no new
+	// HTLCs, and once we've received one, we accept no new HTLCs.
+	localShutdown  bool
+	remoteShutdown bool
+
 	// Capcity is the total capacity of this channel.
 	Capacity btcutil.Amount
 
@@ -559,6 +760,36 @@ type LightningChannel struct {
 	// way to lookup the original PaymentDescriptor.
 	rHashMap map[PaymentHash][]*PaymentDescriptor
 
+	// minHTLC is the smallest HTLC we'll add to, or accept within, this
+	// channel. A value of zero disables the check.
+	minHTLC btcutil.Amount
+
+	// maxDustExposure is the maximum total value of HTLCs below the dust
+	// limit that may be pending within this channel at any time. As dust
+	// HTLCs are trimmed from the commitment transaction, their value
+	// would be lost to fees in the case of a force close. A value of zero
+	// disables the check.
+	maxDustExposure btcutil.Amount
+
+	// maxHashExposure is the maximum total value of HTLCs sharing a
+	// single payment hash that may be pending within this channel at any
+	// time. The first HTLC with a payment hash is never subject to the
+	// limit, only those correlated with it, as repeated HTLCs with the
+	// same hash are a hallmark of probing and looping attacks. A value
+	// of zero disables the check.
+	maxHashExposure btcutil.Amount
+
+	// chanReserve is the minimum balance each side of the channel must
+	// maintain. As a party's balance is forfeited if they broadcast a
+	// revoked state, keeping a reserve on each side ensures cheating
+	// always carries a cost. A value of zero disables the check.
+	chanReserve btcutil.Amount
+
+	// outputIndexEnd is the state number up to which the output scripts
+	// paying to us within our commitment transactions are indexed within
+	// the database.
+	outputIndexEnd uint64
+
 	LocalDeliveryScript  []byte
 	RemoteDeliveryScript []byte
 
@@ -626,9 +857,22 @@ func NewLightningChannel(signer Signer, events chainntnfs.ChainNotifier,
 		ContractBreach:        make(chan *BreachRetribution, 1),
 		LocalFundingKey:       state.OurMultiSigKey,
 		RemoteFundingKey:      state.TheirMultiSigKey,
+		chanReserve:           state.ChanReserve,
 		quit:                  make(chan struct{}),
 	}
 
+	// Determine whether our funding key is held by a signing device, in
+	// which case the channel is frozen at its current state.
+	if state.Db != nil {
+		_, err := state.Db.FetchDeviceKey(state.OurMultiSigKey)
+		switch {
+		case err == nil:
+			lc.deviceFundingKey = true
+		case err != channeldb.ErrDeviceKeyNotFound:
+			return nil, err
+		}
+	}
+
 	// Initialize both of our chains the current un-revoked commitment for
 	// each side.
 	// TODO(roasbeef): add chnneldb.RevocationLogTail method, then init
@@ -639,6 +883,7 @@ func NewLightningChannel(signer Signer, events chainntnfs.ChainNotifier,
 		ourMessageIndex:   0,
 		theirBalance:      state.TheirBalance,
 		theirMessageIndex: 0,
+		fee:               state.CommitFee,
 	}
 	lc.localCommitChain.addCommitment(initialCommitment)
 	lc.remoteCommitChain.addCommitment(initialCommitment)
@@ -669,10 +914,37 @@ func NewLightningChannel(signer Signer, events chainntnfs.ChainNotifier,
 		InputIndex: 0,
 	}
 
+	// Restore any splice of the channel which has yet to be migrated to.
+	if state.Db != nil {
+		if err := lc.restoreSplice(); err != nil {
+			return nil, err
+		}
+
+		// Likewise, resume any cooperative closure of the channel.
+		if err := lc.restoreCooperativeClose(); err != nil {
+			return nil, err
+		}
+
+		// Ensure the output scripts paying to us are indexed for the
+		// states ahead of our current state.
+		lc.outputIndexEnd, err = state.Db.FetchCommitOutputWindow(
+			state.ChanID)
+		if err != nil {
+			return nil, err
+		}
+		if err := lc.indexCommitOutputs(); err != nil {
+			return nil, err
+		}
+	}
+
 	// We'll only launch a close observer if the ChainNotifier
 	// implementation is non-nil. Passing a nil value indicates that the
-	// channel shouldn't be actively watched for.
-	if lc.channelEvents != nil {
+	// channel shouldn't be actively watched for. Recovered channels also
+	// aren't watched, as we lack the state required to act upon a
+	// unilateral close.
+	if lc.channelEvents != nil &&
+		state.ChanType != channeldb.RecoveredChannel {
+
 		// Register for a notification to be dispatched if the funding
 		// outpoint has been spent. This indicates that either us or
 		// the remote party has broadcasted a commitment transaction
@@ -864,9 +1136,33 @@ func (lc *LightningChannel) closeObserver(channelCloseNtfn *chainntnfs.SpendEven
 		return
 	}
 
+	// If the funding output was spent by the splice transaction of the
+	// channel, then the channel remains open. We'll instead watch the
+	// funding output created by the splice for the broadcast of any
+	// commitment transaction spending it.
+	lc.RLock()
+	if lc.isSpliceTx(commitSpend.SpendingTx) {
+		fundingOut := lc.splice.fundingTxIn.PreviousOutPoint
+		lc.RUnlock()
+
+		walletLog.Infof("Splice of ChannelPoint(%v) detected, "+
+			"watching funding output %v", lc.channelState.ChanID,
+			fundingOut)
+
+		spliceCloseNtfn, err := lc.channelEvents.RegisterSpendNtfn(
+			&fundingOut)
+		if err != nil {
+			walletLog.Errorf("unable to register for spend of "+
+				"splice funding output: %v", err)
+			return
+		}
+
+		go lc.closeObserver(spliceCloseNtfn)
+		return
+	}
+
 	// If we've already initiated a local cooperative or unilateral close
 	// locally, then we have nothing more to do.
-	lc.RLock()
 	if lc.status == channelClosed || lc.status == channelDispute ||
 		lc.status == channelClosing {
 
@@ -892,7 +1188,42 @@ func (lc *LightningChannel) closeObserver(channelCloseNtfn *chainntnfs.SpendEven
 
 	currentStateNum := lc.currentHeight
 
+	// Classify the outputs of the broadcast transaction using the index
+	// of our commitment outputs. If it contains our delayed output, then
+	// it's our own commitment transaction, broadcast by a stale instance
+	// of ourselves, rather than one of the remote party's.
+	owned, err := lc.ownedCommitOutputs(commitTxBroadcast)
+	if err != nil {
+		walletLog.Errorf("unable to classify commitment outputs: %v",
+			err)
+	}
+	ownCommitment := false
+	for i, output := range owned {
+		walletLog.Infof("Output %v of ChannelPoint(%v) closing "+
+			"transaction pays to us: type=%v, state=%v", i,
+			lc.channelState.ChanID, output.Type, output.StateNum)
+
+		if output.Type == channeldb.CommitOutputLocalDelayed {
+			ownCommitment = true
+		}
+	}
+
 	switch {
+	// If our own commitment transaction was broadcast, then the remote
+	// party hasn't breached the contract, whatever the state, so we
+	// handle it as a unilateral close.
+	case ownCommitment:
+		walletLog.Warnf("Our own commitment for ChannelPoint(%v) at "+
+			"state #%v was broadcast", lc.channelState.ChanID,
+			broadcastStateNum)
+
+		if err := lc.deleteState(); err != nil {
+			walletLog.Errorf("unable to delete channel state: %v",
+				err)
+		}
+
+		close(lc.UnilateralCloseSignal)
+
 	// If state number spending transaction matches the current latest
 	// state, then they've initiated a unilateral close. So we'll trigger
 	// the unilateral close signal so subscribers can clean up the state as
@@ -910,7 +1241,7 @@ func (lc *LightningChannel) closeObserver(channelCloseNtfn *chainntnfs.SpendEven
 		// As we've deleted that the channel has been closed,
 		// immediately delete the state from disk, creating a close
 		// summary for future usage by related sub-systems.
-		if err := lc.DeleteState(); err != nil {
+		if err := lc.deleteState(); err != nil {
 			walletLog.Errorf("unable to delete channel state: %v",
 				err)
 		}
@@ -981,6 +1312,7 @@ func (lc *LightningChannel) restoreStateLogs() error {
 			EntryType:             Add,
 			addCommitHeightRemote: pastHeight,
 			addCommitHeightLocal:  pastHeight,
+			addTime:               time.Now(),
 		}
 
 		if !htlc.Incoming {
@@ -1064,13 +1396,15 @@ func (lc *LightningChannel) fetchCommitmentView(remoteChain bool,
 	}
 
 	// TODO(roasbeef): don't assume view is always fetched from tip?
-	var ourBalance, theirBalance btcutil.Amount
+	var ourBalance, theirBalance, fee btcutil.Amount
 	if commitChain.tip() == nil {
 		ourBalance = lc.channelState.OurBalance
 		theirBalance = lc.channelState.TheirBalance
+		fee = lc.channelState.CommitFee
 	} else {
 		ourBalance = commitChain.tip().ourBalance
 		theirBalance = commitChain.tip().theirBalance
+		fee = commitChain.tip().fee
 	}
 
 	nextHeight := commitChain.tip().height + 1
@@ -1081,80 +1415,71 @@ func (lc *LightningChannel) fetchCommitmentView(remoteChain bool,
 	// TODO(roasbeef): error if log empty?
 	htlcView := lc.fetchHTLCView(theirLogIndex, ourLogIndex)
 	filteredHTLCView := lc.evaluateHTLCView(htlcView, &ourBalance, &theirBalance,
-		nextHeight, remoteChain)
+		&fee, nextHeight, remoteChain)
 
-	var selfKey *btcec.PublicKey
-	var remoteKey *btcec.PublicKey
-	var delay uint32
-	var delayBalance, p2wkhBalance, dustLimit btcutil.Amount
+	var ownerBalance, counterpartyBalance btcutil.Amount
 	if remoteChain {
-		selfKey = lc.channelState.TheirCommitKey
-		remoteKey = lc.channelState.OurCommitKey
-		delay = lc.channelState.RemoteCsvDelay
-		delayBalance = theirBalance
-		p2wkhBalance = ourBalance
-		dustLimit = lc.channelState.TheirDustLimit
+		ownerBalance, counterpartyBalance = theirBalance, ourBalance
 	} else {
-		selfKey = lc.channelState.OurCommitKey
-		remoteKey = lc.channelState.TheirCommitKey
-		delay = lc.channelState.LocalCsvDelay
-		delayBalance = ourBalance
-		p2wkhBalance = theirBalance
-		dustLimit = lc.channelState.OurDustLimit
+		ownerBalance, counterpartyBalance = ourBalance, theirBalance
 	}
 
 	// Generate a new commitment transaction with all the latest
-	// unsettled/un-timed out HTLCs.
-	ourCommitTx := !remoteChain
-	commitTx, err := CreateCommitTx(lc.fundingTxIn, selfKey, remoteKey,
-		revocationKey, delay, delayBalance, p2wkhBalance, dustLimit)
-	if err != nil {
-		return nil, err
-	}
+	// unsettled/un-timed out HTLCs. The HTLCs we offered are offered by
+	// the owner of our own commitment, and to the owner of theirs.
+	htlcDescs := make([]*PaymentDescriptor, 0,
+		len(filteredHTLCView.ourUpdates)+len(filteredHTLCView.theirUpdates))
+	htlcDescs = append(htlcDescs, filteredHTLCView.ourUpdates...)
+	htlcDescs = append(htlcDescs, filteredHTLCView.theirUpdates...)
+
+	htlcs := make([]CommitmentHTLC, 0, len(htlcDescs))
 	for _, htlc := range filteredHTLCView.ourUpdates {
-		if htlc.Amount < dustLimit {
-			continue
-		}
-
-		err := lc.addHTLC(commitTx, ourCommitTx, htlc,
-			revocationHash, delay, false)
-		if err != nil {
-			return nil, err
-		}
+		htlcs = append(htlcs, CommitmentHTLC{
+			Offered:     !remoteChain,
+			Amount:      htlc.Amount,
+			Expiry:      htlc.Timeout,
+			PaymentHash: htlc.RHash,
+		})
 	}
 	for _, htlc := range filteredHTLCView.theirUpdates {
-		if htlc.Amount < dustLimit {
-			continue
-		}
-
-		err := lc.addHTLC(commitTx, ourCommitTx, htlc,
-			revocationHash, delay, true)
-		if err != nil {
-			return nil, err
-		}
+		htlcs = append(htlcs, CommitmentHTLC{
+			Offered:     remoteChain,
+			Amount:      htlc.Amount,
+			Expiry:      htlc.Timeout,
+			PaymentHash: htlc.RHash,
+		})
 	}
 
-	// Set the state hint of the commitment transaction to facilitate
-	// quickly recovering the necessary penalty state in the case of an
-	// uncooperative broadcast.
-	obsfucator := lc.channelState.StateHintObsfucator
-	stateNum := nextHeight
-	if err := SetStateNumHint(commitTx, stateNum, obsfucator); err != nil {
+	builder := lc.commitmentBuilder(remoteChain, lc.fundingTxIn,
+		lc.Capacity)
+	commit, err := builder.Build(&CommitmentState{
+		Height:              nextHeight,
+		RevocationKey:       revocationKey,
+		RevocationHash:      revocationHash,
+		OwnerBalance:        ownerBalance,
+		CounterpartyBalance: counterpartyBalance,
+		MinFee:              fee,
+	}, htlcs)
+	if err != nil {
 		return nil, err
 	}
 
-	// Sort the transactions according to the agreed upon canonical
-	// ordering. This lets us skip sending the entire transaction over,
-	// instead we'll just send signatures.
-	txsort.InPlaceSort(commitTx)
+	// Store the pkScript of each HTLC's PaymentDescriptor so we can
+	// quickly locate it within the commitment transaction later.
+	for i, htlc := range htlcDescs {
+		if pkScript := commit.HtlcScripts[i]; pkScript != nil {
+			htlc.pkScript = pkScript
+		}
+	}
 
 	return &commitment{
-		txn:               commitTx,
+		txn:               commit.Tx,
 		height:            nextHeight,
 		ourBalance:        ourBalance,
 		ourMessageIndex:   ourLogIndex,
 		theirMessageIndex: theirLogIndex,
 		theirBalance:      theirBalance,
+		fee:               fee,
 		outgoingHTLCs:     filteredHTLCView.ourUpdates,
 		incomingHTLCs:     filteredHTLCView.theirUpdates,
 	}, nil
@@ -1164,9 +1489,10 @@ func (lc *LightningChannel) fetchCommitmentView(remoteChain bool,
 // producing a final view which is the result of properly applying all adds,
 // settles, and timeouts found in both logs. The resulting view returned
 // reflects the current state of HTLCs within the remote or local commitment
-// chain.
+// chain. Any fee updates found in either log are applied to the passed fee.
 func (lc *LightningChannel) evaluateHTLCView(view *htlcView, ourBalance,
-	theirBalance *btcutil.Amount, nextHeight uint64, remoteChain bool) *htlcView {
+	theirBalance, fee *btcutil.Amount, nextHeight uint64,
+	remoteChain bool) *htlcView {
 
 	newView := &htlcView{}
 
@@ -1185,6 +1511,12 @@ func (lc *LightningChannel) evaluateHTLCView(view *htlcView, ourBalance,
 			continue
 		}
 
+		if entry.EntryType == FeeUpdate {
+			processFeeUpdate(entry, ourBalance, fee, nextHeight,
+				remoteChain)
+			continue
+		}
+
 		// If we're settling in inbound HTLC, and it hasn't been
 		// processed, yet, the increment our state tracking the total
 		// number of satoshis we've received within the channel.
@@ -1204,6 +1536,12 @@ func (lc *LightningChannel) evaluateHTLCView(view *htlcView, ourBalance,
 			continue
 		}
 
+		if entry.EntryType == FeeUpdate {
+			processFeeUpdate(entry, theirBalance, fee, nextHeight,
+				remoteChain)
+			continue
+		}
+
 		// If the remote party is settling one of our outbound HTLC's,
 		// and it hasn't been processed, yet, the increment our state
 		// tracking the total number of satoshis we've sent within the
@@ -1331,6 +1669,31 @@ func processRemoveEntry(htlc *PaymentDescriptor, ourBalance,
 	*removeHeight = nextHeight
 }
 
+// processFeeUpdate evaluates the effect of a fee update entry within the log.
+// The difference between the current fee and the new fee is settled with the
+// balance of the initiator, which is the owner of the log the entry resides
+// in. If the update has already been committed within the chain being
+// evaluated, it is skipped.
+func processFeeUpdate(feeUpdate *PaymentDescriptor, initiatorBalance,
+	fee *btcutil.Amount, nextHeight uint64, remoteChain bool) {
+
+	var addHeight *uint64
+	if remoteChain {
+		addHeight = &feeUpdate.addCommitHeightRemote
+	} else {
+		addHeight = &feeUpdate.addCommitHeightLocal
+	}
+
+	if *addHeight != 0 {
+		return
+	}
+
+	*initiatorBalance += *fee - feeUpdate.Amount
+	*fee = feeUpdate.Amount
+
+	*addHeight = nextHeight
+}
+
 // SignNextCommitment signs a new commitment which includes any previous
 // unsettled HTLCs, any new HTLCs, and any modifications to prior HTLCs
 // committed in previous commitment updates. Signing a new commitment
@@ -1341,6 +1704,24 @@ func (lc *LightningChannel) SignNextCommitment() ([]byte, error) {
 	lc.Lock()
 	defer lc.Unlock()
 
+	// Signing a new commitment would require the approval of the
+	// signing device which holds our funding key.
+	if lc.deviceFundingKey {
+		return nil, ErrDeviceFundingKey
+	}
+	if lc.divergence != nil {
+		return nil, ErrChannelFrozen
+	}
+	if lc.dataLoss {
+		return nil, ErrCommitSyncDataLoss
+	}
+
+	// Should another daemon have acquired the database since we did, then
+	// it signs for the channel in our place, and our state may be stale.
+	if err := lc.checkFence(); err != nil {
+		return nil, err
+	}
+
 	// If we're awaiting an ACK to a commitment signature, then we're
 	// unable to create new states as we don't have any revocations we can
 	// use.
@@ -1444,17 +1825,19 @@ func (lc *LightningChannel) validateCommitmentSanity(theirLogCounter,
 	htlcView := lc.fetchHTLCView(theirLogCounter, ourLogCounter)
 
 	for _, entry := range htlcView.ourUpdates {
-		if entry.EntryType == Add {
+		switch entry.EntryType {
+		case Add:
 			htlcCount++
-		} else {
+		case Settle, Fail:
 			htlcCount--
 		}
 	}
 
 	for _, entry := range htlcView.theirUpdates {
-		if entry.EntryType == Add {
+		switch entry.EntryType {
+		case Add:
 			htlcCount++
-		} else {
+		case Settle, Fail:
 			htlcCount--
 		}
 	}
@@ -1502,6 +1885,18 @@ func (lc *LightningChannel) ReceiveNewCommitment(rawSig []byte) error {
 	revocationKey := DeriveRevocationPubkey(theirCommitKey, revocation[:])
 	revocationHash := sha256.Sum256(revocation[:])
 
+	// Constructing the new commitment view marks the entries it covers as
+	// committed within our chain, so the marks are rolled back should the
+	// commitment be rejected. Otherwise, the entries would be left out of
+	// the balances of a valid commitment sent afterwards.
+	checkpoint := lc.checkpointLocalChain()
+	accepted := false
+	defer func() {
+		if !accepted {
+			lc.restoreLocalChain(checkpoint)
+		}
+	}()
+
 	// With the revocation information calculated, construct the new
 	// commitment view which includes all the entries we know of in their
 	// HTLC log, and up to ourLogIndex in our HTLC log.
@@ -1531,8 +1926,6 @@ func (lc *LightningChannel) ReceiveNewCommitment(rawSig []byte) error {
 	sigHash, err := txscript.CalcWitnessSigHash(multiSigScript, hashCache,
 		txscript.SigHashAll, localCommitTx, 0, int64(lc.channelState.Capacity))
 	if err != nil {
-		// TODO(roasbeef): fetchview has already mutated the HTLCs...
-		//  * need to either roll-back, or make pure
 		return err
 	}
 
@@ -1548,6 +1941,7 @@ func (lc *LightningChannel) ReceiveNewCommitment(rawSig []byte) error {
 
 	// The signature checks out, so we can now add the new commitment to
 	// our local commitment chain.
+	accepted = true
 	localCommitmentView.sig = rawSig
 	lc.localCommitChain.addCommitment(localCommitmentView)
 
@@ -1559,6 +1953,55 @@ func (lc *LightningChannel) ReceiveNewCommitment(rawSig []byte) error {
 	return nil
 }
 
+// localChainCheckpoint records the state which is mutated when evaluating a
+// new commitment for our local chain, namely the heights at which each entry
+// within the update logs was committed within our chain, and the totals sent
+// and received within the channel.
+type localChainCheckpoint struct {
+	addHeights    map[*PaymentDescriptor]uint64
+	removeHeights map[*PaymentDescriptor]uint64
+
+	satoshisSent     uint64
+	satoshisReceived uint64
+}
+
+// checkpointLocalChain returns a checkpoint of the state mutated when
+// evaluating a new commitment for our local chain.
+//
+// NOTE: This method requires the channel's lock to be held.
+func (lc *LightningChannel) checkpointLocalChain() *localChainCheckpoint {
+	checkpoint := &localChainCheckpoint{
+		addHeights:       make(map[*PaymentDescriptor]uint64),
+		removeHeights:    make(map[*PaymentDescriptor]uint64),
+		satoshisSent:     lc.channelState.TotalSatoshisSent,
+		satoshisReceived: lc.channelState.TotalSatoshisReceived,
+	}
+	for _, log := range []*updateLog{lc.localUpdateLog, lc.remoteUpdateLog} {
+		for e := log.Front(); e != nil; e = e.Next() {
+			pd := e.Value.(*PaymentDescriptor)
+			checkpoint.addHeights[pd] = pd.addCommitHeightLocal
+			checkpoint.removeHeights[pd] = pd.removeCommitHeightLocal
+		}
+	}
+
+	return checkpoint
+}
+
+// restoreLocalChain restores the state recorded within the passed
+// checkpoint.
+//
+// NOTE: This method requires the channel's lock to be held.
+func (lc *LightningChannel) restoreLocalChain(checkpoint *localChainCheckpoint) {
+	for pd, height := range checkpoint.addHeights {
+		pd.addCommitHeightLocal = height
+	}
+	for pd, height := range checkpoint.removeHeights {
+		pd.removeCommitHeightLocal = height
+	}
+	lc.channelState.TotalSatoshisSent = checkpoint.satoshisSent
+	lc.channelState.TotalSatoshisReceived = checkpoint.satoshisReceived
+}
+
 // PendingUpdates returns a boolean value reflecting if there are any pending
 // updates which need to be committed. The state machine has pending updates if
 // the local log index on the local and remote chain tip aren't identical. This
@@ -1576,6 +2019,193 @@ func (lc *LightningChannel) PendingUpdates() bool {
 	return !fullySynced
 }
 
+// CommitHeights returns the heights of the tips of our local commitment chain,
+// and of the remote party's commitment chain.
+func (lc *LightningChannel) CommitHeights() (uint64, uint64) {
+	lc.RLock()
+	defer lc.RUnlock()
+
+	return lc.localCommitChain.tip().height,
+		lc.remoteCommitChain.tip().height
+}
+
+// MachinePhase summarizes the progress of a channel's commitment state
+// machine.
+type MachinePhase string
+
+const (
+	// PhaseIdle indicates every update has been committed to, and
+	// revoked down to, a single commitment within both chains.
+	PhaseIdle MachinePhase = "idle"
+
+	// PhasePendingRevocation indicates we've received a new commitment
+	// from the remote party, yet have to revoke our prior commitment.
+	PhasePendingRevocation MachinePhase = "pending_revocation"
+
+	// PhasePendingSign indicates we hold updates which we have yet to
+	// sign a new commitment for the remote party covering.
+	PhasePendingSign MachinePhase = "pending_sign"
+
+	// PhaseAwaitingRevocation indicates we've signed a new commitment for
+	// the remote party, and are awaiting the revocation of its prior
+	// commitment before we may sign another.
+	PhaseAwaitingRevocation MachinePhase = "awaiting_revocation"
+
+	// PhaseAwaitingSig indicates updates are pending which the remote
+	// party has yet to sign a new commitment for us covering.
+	PhaseAwaitingSig MachinePhase = "awaiting_sig"
+
+	// PhaseShutdownPending indicates either party has signalled its
+	// intent to cooperatively close the otherwise idle channel.
+	PhaseShutdownPending MachinePhase = "shutdown_pending"
+
+	// PhaseSplicing indicates the channel is being spliced, during which
+	// no updates are accepted.
+	PhaseSplicing MachinePhase = "splicing"
+
+	// PhaseClosing indicates the channel is being cooperatively closed.
+	PhaseClosing MachinePhase = "closing"
+
+	// PhaseDispute indicates the channel has been closed unilaterally.
+	PhaseDispute MachinePhase = "dispute"
+
+	// PhaseFrozen indicates the channel was frozen due to the divergence
+	// of its in-memory and persisted state.
+	PhaseFrozen MachinePhase = "frozen"
+
+	// PhaseDataLoss indicates the remote party has proven we've lost our
+	// state for the channel.
+	PhaseDataLoss MachinePhase = "data_loss"
+)
+
+// MachineStatus is a snapshot of a channel's commitment state machine,
+// allowing a channel which has stopped making progress to be diagnosed.
+type MachineStatus struct {
+	// Phase summarizes the progress of the state machine.
+	Phase MachinePhase
+
+	// LocalTailHeight and LocalTipHeight are the heights of the lowest
+	// unrevoked and of the latest commitment within our local chain.
+	LocalTailHeight uint64
+	LocalTipHeight  uint64
+
+	// RemoteTailHeight and RemoteTipHeight are the heights of the lowest
+	// unrevoked and of the latest commitment within the remote chain.
+	RemoteTailHeight uint64
+	RemoteTipHeight  uint64
+
+	// LocalLogIndex and RemoteLogIndex are the number of updates ever
+	// added to our update log and to the remote party's.
+	LocalLogIndex  uint64
+	RemoteLogIndex uint64
+
+	// UnsignedUpdates is the number of updates not yet covered by a
+	// commitment we've signed for the remote party.
+	UnsignedUpdates uint64
+
+	// UncommittedUpdates is the number of updates not yet covered by a
+	// commitment the remote party has signed for us.
+	UncommittedUpdates uint64
+
+	// AwaitingRevocation denotes we've signed a commitment for the
+	// remote party, and await the revocation of its prior commitment.
+	AwaitingRevocation bool
+
+	// RevocationWindow is the number of unused revocations the remote
+	// party has given us.
+	RevocationWindow int
+
+	// LocalShutdown and RemoteShutdown denote that we, and the remote
+	// party respectively, have signalled the intent to cooperatively
+	// close the channel.
+	LocalShutdown  bool
+	RemoteShutdown bool
+}
+
+// MachineStatus returns a snapshot of the channel's commitment state machine.
+func (lc *LightningChannel) MachineStatus() *MachineStatus {
+	lc.RLock()
+	defer lc.RUnlock()
+
+	localTip := lc.localCommitChain.tip()
+	remoteTip := lc.remoteCommitChain.tip()
+	localLogIndex := lc.localUpdateLog.logIndex
+	remoteLogIndex := lc.remoteUpdateLog.logIndex
+
+	// Our updates are unsigned until the tip of the remote chain covers
+	// them, as are the remote party's updates once they're covered by our
+	// local chain, but not yet by the remote chain. Updates by either
+	// party are uncommitted until the tip of our local chain covers them.
+	unsigned := logDistance(localLogIndex, remoteTip.ourMessageIndex) +
+		logDistance(localTip.theirMessageIndex,
+			remoteTip.theirMessageIndex)
+	uncommitted := logDistance(localLogIndex, localTip.ourMessageIndex) +
+		logDistance(remoteLogIndex, localTip.theirMessageIndex)
+
+	status := &MachineStatus{
+		LocalTailHeight:    lc.localCommitChain.tail().height,
+		LocalTipHeight:     localTip.height,
+		RemoteTailHeight:   lc.remoteCommitChain.tail().height,
+		RemoteTipHeight:    remoteTip.height,
+		LocalLogIndex:      localLogIndex,
+		RemoteLogIndex:     remoteLogIndex,
+		UnsignedUpdates:    unsigned,
+		UncommittedUpdates: uncommitted,
+		AwaitingRevocation: lc.pendingACK,
+		RevocationWindow:   len(lc.revocationWindow),
+		LocalShutdown:      lc.localShutdown,
+		RemoteShutdown:     lc.remoteShutdown,
+	}
+
+	switch {
+	case lc.dataLoss:
+		status.Phase = PhaseDataLoss
+	case lc.divergence != nil:
+		status.Phase = PhaseFrozen
+	case lc.status == channelDispute:
+		status.Phase = PhaseDispute
+	case lc.status == channelClosing || lc.status == channelClosed:
+		status.Phase = PhaseClosing
+	case lc.splice != nil:
+		status.Phase = PhaseSplicing
+	case lc.pendingACK:
+		status.Phase = PhaseAwaitingRevocation
+	case lc.localCommitChain.commitments.Len() > 1:
+		status.Phase = PhasePendingRevocation
+	case status.UnsignedUpdates > 0:
+		status.Phase = PhasePendingSign
+	case status.UncommittedUpdates > 0:
+		status.Phase = PhaseAwaitingSig
+	case lc.localShutdown || lc.remoteShutdown:
+		status.Phase = PhaseShutdownPending
+	default:
+		status.Phase = PhaseIdle
+	}
+
+	return status
+}
+
+// logDistance returns the number of updates between the passed log indexes,
+// or zero if the second isn't behind the first.
+func logDistance(index, committedIndex uint64) uint64 {
+	if committedIndex >= index {
+		return 0
+	}
+	return index - committedIndex
+}
+
+// checkFence returns channeldb.ErrFenced if another daemon has acquired the
+// channel's database since we did, in which case we must neither sign nor
+// revoke commitments, as the two of us would otherwise advance the channel's
+// state independently of one another.
+func (lc *LightningChannel) checkFence() error {
+	if lc.channelState.Db == nil {
+		return nil
+	}
+
+	return lc.channelState.Db.CheckFence()
+}
+
 // RevokeCurrentCommitment revokes the next lowest unrevoked commitment
 // transaction in the local commitment chain. As a result the edge of our
 // revocation window is extended by one, and the tail of our local commitment
@@ -1585,6 +2215,16 @@ func (lc *LightningChannel) RevokeCurrentCommitment() (*lnwire.RevokeAndAck, err
 	lc.Lock()
 	defer lc.Unlock()
 
+	if lc.divergence != nil {
+		return nil, ErrChannelFrozen
+	}
+	if lc.dataLoss {
+		return nil, ErrCommitSyncDataLoss
+	}
+	if err := lc.checkFence(); err != nil {
+		return nil, err
+	}
+
 	theirCommitKey := lc.channelState.TheirCommitKey
 
 	// Now that we've accept a new state transition, we send the remote
@@ -1631,6 +2271,14 @@ func (lc *LightningChannel) RevokeCurrentCommitment() (*lnwire.RevokeAndAck, err
 		"our_balance=%v, their_balance=%v", lc.channelState.ChanID,
 		tail.ourBalance, tail.theirBalance)
 
+	// Top up the index of our output scripts should we be nearing the
+	// end of the indexed states. As the index already covers the states
+	// just ahead of this one, a failure isn't fatal to the transition.
+	if err := lc.indexCommitOutputs(); err != nil {
+		walletLog.Errorf("ChannelPoint(%v): unable to index commitment "+
+			"outputs: %v", lc.channelState.ChanID, err)
+	}
+
 	// In the process of revoking our current commitment, we've also
 	// implicitly ACK'd their set of pending changes that arrived before
 	// the signature the triggered this revocation. So we'll move up their
@@ -1659,22 +2307,18 @@ func (lc *LightningChannel) ReceiveRevocation(revMsg *lnwire.RevokeAndAck) ([]*P
 		return nil, nil
 	}
 
-	// Now that we've received a new revocation from the remote party,
-	// we'll toggle our pendingACk bool to indicate that we can create a
-	// new commitment state after we finish processing this revocation.
-	lc.pendingACK = false
+	// A revocation may only be received once we've extended the remote
+	// party's commitment chain, otherwise they're revoking a commitment
+	// which has yet to be superseded, or replaying an earlier revocation.
+	if len(lc.usedRevocations) == 0 {
+		return nil, fmt.Errorf("unexpected revocation, no commitment " +
+			"is awaiting revocation")
+	}
 
 	ourCommitKey := lc.channelState.OurCommitKey
 	currentRevocationKey := lc.channelState.TheirCurrentRevocation
 	pendingRevocation := chainhash.Hash(revMsg.Revocation)
 
-	// Ensure that the new pre-image can be placed in preimage store.
-	// TODO(rosbeef): abstract into func
-	store := lc.channelState.RevocationStore
-	if err := store.AddNextEntry(&pendingRevocation); err != nil {
-		return nil, err
-	}
-
 	// Verify that the revocation public key we can derive using this
 	// preimage and our private key is identical to the revocation key we
 	// were given for their current (prior) commitment transaction.
@@ -1693,6 +2337,20 @@ func (lc *LightningChannel) ReceiveRevocation(revMsg *lnwire.RevokeAndAck) ([]*P
 		}
 	}
 
+	// Only once the revocation has been verified can the pre-image be
+	// placed in the preimage store, as an invalid pre-image would
+	// otherwise corrupt the store.
+	// TODO(rosbeef): abstract into func
+	store := lc.channelState.RevocationStore
+	if err := store.AddNextEntry(&pendingRevocation); err != nil {
+		return nil, err
+	}
+
+	// Now that we've received a new revocation from the remote party,
+	// we'll toggle our pendingACk bool to indicate that we can create a
+	// new commitment state after we finish processing this revocation.
+	lc.pendingACK = false
+
 	// Advance the head of the revocation queue now that this revocation has
 	// been verified. Additionally, extend the end of our unused revocation
 	// queue with the newly extended revocation window update.
@@ -1735,7 +2393,9 @@ func (lc *LightningChannel) ReceiveRevocation(revMsg *lnwire.RevokeAndAck) ([]*P
 	for e := lc.remoteUpdateLog.Front(); e != nil; e = e.Next() {
 		htlc := e.Value.(*PaymentDescriptor)
 
-		if htlc.isForwarded {
+		// Fee updates aren't forwarded, they only modify the
+		// commitment itself.
+		if htlc.isForwarded || htlc.EntryType == FeeUpdate {
 			continue
 		}
 
@@ -1826,493 +2486,1537 @@ func (lc *LightningChannel) NextRevocationkey() (*btcec.PublicKey, error) {
 		revocation[:]), nil
 }
 
-// AddHTLC adds an HTLC to the state machine's local update log. This method
-// should be called when preparing to send an outgoing HTLC.
-//
-// TODO(roasbeef): check for duplicates below? edge case during restart w/ HTLC
-// persistence
-func (lc *LightningChannel) AddHTLC(htlc *lnwire.UpdateAddHTLC) (uint64, error) {
+// SetHTLCPolicy sets the minimum HTLC amount, the maximum dust exposure, and
+// the maximum exposure to a single payment hash enforced for all HTLCs
+// subsequently added to the channel. A zero value disables the respective
+// check.
+func (lc *LightningChannel) SetHTLCPolicy(minHTLC, maxDustExposure,
+	maxHashExposure btcutil.Amount) {
+
 	lc.Lock()
 	defer lc.Unlock()
 
-	if err := lc.validateCommitmentSanity(lc.remoteUpdateLog.logIndex,
-		lc.localUpdateLog.logIndex, true); err != nil {
-		return 0, err
-	}
-
-	pd := &PaymentDescriptor{
-		EntryType: Add,
-		RHash:     PaymentHash(htlc.PaymentHash),
-		Timeout:   htlc.Expiry,
-		Amount:    htlc.Amount,
-		Index:     lc.localUpdateLog.logIndex,
-	}
-
-	lc.localUpdateLog.appendUpdate(pd)
-
-	return pd.Index, nil
+	lc.minHTLC = minHTLC
+	lc.maxDustExposure = maxDustExposure
+	lc.maxHashExposure = maxHashExposure
 }
 
-// ReceiveHTLC adds an HTLC to the state machine's remote update log. This
-// method should be called in response to receiving a new HTLC from the remote
-// party.
-func (lc *LightningChannel) ReceiveHTLC(htlc *lnwire.UpdateAddHTLC) (uint64, error) {
+// SetChanReserve sets the minimum balance each side of the channel must
+// maintain. Any HTLC subsequently added to, or received within, the channel
+// which would push the offering party's balance below the reserve is
+// rejected. A value of zero disables the check.
+func (lc *LightningChannel) SetChanReserve(reserve btcutil.Amount) {
 	lc.Lock()
 	defer lc.Unlock()
 
-	if err := lc.validateCommitmentSanity(lc.remoteUpdateLog.logIndex,
-		lc.localUpdateLog.logIndex, true); err != nil {
-		return 0, err
-	}
+	lc.chanReserve = reserve
+}
 
-	pd := &PaymentDescriptor{
-		EntryType: Add,
-		RHash:     PaymentHash(htlc.PaymentHash),
-		Timeout:   htlc.Expiry,
-		Amount:    htlc.Amount,
-		Index:     lc.remoteUpdateLog.logIndex,
-	}
+// ChanReserve returns the minimum balance each side of the channel must
+// currently maintain.
+func (lc *LightningChannel) ChanReserve() btcutil.Amount {
+	lc.RLock()
+	defer lc.RUnlock()
 
-	lc.remoteUpdateLog.appendUpdate(pd)
+	return lc.chanReserve
+}
 
-	lc.rHashMap[pd.RHash] = append(lc.rHashMap[pd.RHash], pd)
+// ChanReserveNegotiated returns whether the channel reserve was negotiated
+// during the funding workflow. Channels funded before the reserve was
+// negotiated have none, and may have one set by the caller instead.
+func (lc *LightningChannel) ChanReserveNegotiated() bool {
+	lc.RLock()
+	defer lc.RUnlock()
 
-	return pd.Index, nil
+	return lc.channelState.ChanReserveNegotiated
 }
 
-// SettleHTLC attempts to settle an existing outstanding received HTLC. The
-// remote log index of the HTLC settled is returned in order to facilitate
-// creating the corresponding wire message. In the case the supplied preimage
-// is invalid, an error is returned.
-func (lc *LightningChannel) SettleHTLC(preimage [32]byte) (uint64, error) {
-	lc.Lock()
-	defer lc.Unlock()
+// availableBalances returns the balance of each side of the channel, less the
+// value of any HTLCs they've offered, and any change to the commitment fee
+// they've proposed, which are yet to be included within our latest
+// commitment.
+//
+// NOTE: The channel's mutex MUST be held when calling this method.
+func (lc *LightningChannel) availableBalances() (btcutil.Amount,
+	btcutil.Amount) {
 
-	paymentHash := sha256.Sum256(preimage[:])
-	targetHTLCs, ok := lc.rHashMap[paymentHash]
-	if !ok {
-		return 0, fmt.Errorf("invalid payment hash")
-	}
-	targetHTLC := targetHTLCs[0]
+	tip := lc.localCommitChain.tip()
+	ourBalance, theirBalance := tip.ourBalance, tip.theirBalance
+	fee := tip.fee
 
-	pd := &PaymentDescriptor{
+	// Settles and fails are ignored, as the value they return to either
+	// side isn't spendable until they've been committed. Only one of the
+	// logs may contain fee updates, so the fee is applied in log order.
+	for e := lc.localUpdateLog.Front(); e != nil; e = e.Next() {
+		htlc := e.Value.(*PaymentDescriptor)
+		if htlc.addCommitHeightLocal != 0 {
+			continue
+		}
+
+		switch htlc.EntryType {
+		case Add:
+			ourBalance -= htlc.Amount
+		case FeeUpdate:
+			ourBalance += fee - htlc.Amount
+			fee = htlc.Amount
+		}
+	}
+	for e := lc.remoteUpdateLog.Front(); e != nil; e = e.Next() {
+		htlc := e.Value.(*PaymentDescriptor)
+		if htlc.addCommitHeightLocal != 0 {
+			continue
+		}
+
+		switch htlc.EntryType {
+		case Add:
+			theirBalance -= htlc.Amount
+		case FeeUpdate:
+			theirBalance += fee - htlc.Amount
+			fee = htlc.Amount
+		}
+	}
+
+	return ourBalance, theirBalance
+}
+
+// pendingFee returns the commitment fee which will be in effect once all fee
+// updates within either log have been committed.
+//
+// NOTE: The channel's mutex MUST be held when calling this method.
+func (lc *LightningChannel) pendingFee() btcutil.Amount {
+	fee := lc.localCommitChain.tip().fee
+	for _, log := range []*updateLog{lc.localUpdateLog, lc.remoteUpdateLog} {
+		for e := log.Front(); e != nil; e = e.Next() {
+			htlc := e.Value.(*PaymentDescriptor)
+			if htlc.EntryType == FeeUpdate &&
+				htlc.addCommitHeightLocal == 0 {
+
+				fee = htlc.Amount
+			}
+		}
+	}
+
+	return fee
+}
+
+// CommitFee returns the fee paid by our current commitment transaction.
+func (lc *LightningChannel) CommitFee() btcutil.Amount {
+	lc.RLock()
+	defer lc.RUnlock()
+
+	return lc.localCommitChain.tail().fee
+}
+
+// CheckHTLCPolicy returns a non-nil error if an HTLC of the passed amount and
+// payment hash would violate the channel's HTLC policy if added to the
+// channel.
+func (lc *LightningChannel) CheckHTLCPolicy(amt btcutil.Amount,
+	rHash [32]byte) error {
+
+	lc.RLock()
+	defer lc.RUnlock()
+
+	return lc.checkHTLCPolicy(amt, PaymentHash(rHash))
+}
+
+// checkHTLCPolicy is the non-locking version of CheckHTLCPolicy.
+//
+// NOTE: The channel's mutex MUST be held when calling this method.
+func (lc *LightningChannel) checkHTLCPolicy(amt btcutil.Amount,
+	rHash PaymentHash) error {
+
+	if lc.minHTLC != 0 && amt < lc.minHTLC {
+		return ErrBelowMinHTLC
+	}
+
+	// If HTLCs with the same payment hash are already pending, then
+	// ensure this one doesn't push their total beyond the permitted
+	// maximum.
+	if lc.maxHashExposure != 0 {
+		exposure := lc.hashExposure(rHash)
+		if exposure != 0 && exposure+amt > lc.maxHashExposure {
+			return ErrMaxHashExposure
+		}
+	}
+
+	// If this HTLC would be trimmed from either commitment transaction,
+	// then ensure that it doesn't push our total dust exposure beyond the
+	// permitted maximum.
+	dustLimit := lc.channelState.OurDustLimit
+	if lc.channelState.TheirDustLimit > dustLimit {
+		dustLimit = lc.channelState.TheirDustLimit
+	}
+	if lc.maxDustExposure != 0 && amt < dustLimit {
+		if lc.dustExposure(dustLimit)+amt > lc.maxDustExposure {
+			return ErrMaxDustExposure
+		}
+	}
+
+	return nil
+}
+
+// DustExposure returns the total value of all pending HTLCs within the channel
+// which fall below the dust limit of either commitment transaction.
+func (lc *LightningChannel) DustExposure() btcutil.Amount {
+	lc.RLock()
+	defer lc.RUnlock()
+
+	dustLimit := lc.channelState.OurDustLimit
+	if lc.channelState.TheirDustLimit > dustLimit {
+		dustLimit = lc.channelState.TheirDustLimit
+	}
+
+	return lc.dustExposure(dustLimit)
+}
+
+// HashExposure returns the total value of all pending HTLCs within the
+// channel with the passed payment hash.
+func (lc *LightningChannel) HashExposure(rHash [32]byte) btcutil.Amount {
+	lc.RLock()
+	defer lc.RUnlock()
+
+	return lc.hashExposure(PaymentHash(rHash))
+}
+
+// PendingHTLC describes an HTLC which has yet to be fully removed from the
+// channel.
+type PendingHTLC struct {
+	// Incoming is true if the HTLC was offered to us by the remote party.
+	Incoming bool
+
+	// Amount is the value of the HTLC.
+	Amount btcutil.Amount
+
+	// RHash is the payment hash of the HTLC.
+	RHash PaymentHash
+
+	// Expiry is the absolute height at which the HTLC times out.
+	Expiry uint32
+
+	// AddTime is the time the HTLC was added to the channel. For HTLCs
+	// added prior to the channel's last restart, this is the time the
+	// channel was restored.
+	AddTime time.Time
+
+	// LockedIn is true once the HTLC has been committed within both
+	// commitment chains.
+	LockedIn bool
+
+	// Removing is true once the HTLC has been settled or failed, but the
+	// removal has yet to be committed within both commitment chains.
+	Removing bool
+}
+
+// PendingHTLCs returns each HTLC within the channel's update logs which has
+// yet to be fully removed, ordered by the time it was added.
+func (lc *LightningChannel) PendingHTLCs() []*PendingHTLC {
+	lc.RLock()
+	defer lc.RUnlock()
+
+	removedLocal, removedRemote := lc.removedAdds()
+
+	var htlcs []*PendingHTLC
+	collect := func(log *updateLog, removed map[uint64]struct{},
+		incoming bool) {
+
+		for e := log.Front(); e != nil; e = e.Next() {
+			htlc := e.Value.(*PaymentDescriptor)
+			if htlc.EntryType != Add {
+				continue
+			}
+
+			_, removing := removed[htlc.Index]
+			htlcs = append(htlcs, &PendingHTLC{
+				Incoming: incoming,
+				Amount:   htlc.Amount,
+				RHash:    htlc.RHash,
+				Expiry:   htlc.Timeout,
+				AddTime:  htlc.addTime,
+				LockedIn: htlc.addCommitHeightLocal != 0 &&
+					htlc.addCommitHeightRemote != 0,
+				Removing: removing,
+			})
+		}
+	}
+	collect(lc.localUpdateLog, removedLocal, false)
+	collect(lc.remoteUpdateLog, removedRemote, true)
+
+	sort.Sort(pendingHTLCsByAddTime(htlcs))
+
+	return htlcs
+}
+
+// pendingHTLCsByAddTime sorts pending HTLCs by the time they were added.
+type pendingHTLCsByAddTime []*PendingHTLC
+
+func (p pendingHTLCsByAddTime) Len() int      { return len(p) }
+func (p pendingHTLCsByAddTime) Swap(i, j int) { p[i], p[j] = p[j], p[i] }
+func (p pendingHTLCsByAddTime) Less(i, j int) bool {
+	return p[i].AddTime.Before(p[j].AddTime)
+}
+
+// hashExposure sums the value of all HTLC adds within both update logs with
+// the passed payment hash, which haven't yet been removed by a corresponding
+// settle or fail.
+//
+// NOTE: The channel's mutex MUST be held when calling this method.
+func (lc *LightningChannel) hashExposure(rHash PaymentHash) btcutil.Amount {
+	return lc.sumPendingAdds(func(htlc *PaymentDescriptor) bool {
+		return htlc.RHash == rHash
+	})
+}
+
+// dustExposure sums the value of all HTLC adds within both update logs which
+// are below the passed dust limit, and haven't yet been removed by a
+// corresponding settle or fail.
+//
+// NOTE: The channel's mutex MUST be held when calling this method.
+func (lc *LightningChannel) dustExposure(dustLimit btcutil.Amount) btcutil.Amount {
+	return lc.sumPendingAdds(func(htlc *PaymentDescriptor) bool {
+		return htlc.Amount < dustLimit
+	})
+}
+
+// removedAdds returns the indexes of the adds within the local and remote
+// update logs respectively which have been settled or failed. Settles and
+// fails within one log remove adds from the opposite log.
+//
+// NOTE: The channel's mutex MUST be held when calling this method.
+func (lc *LightningChannel) removedAdds() (map[uint64]struct{},
+	map[uint64]struct{}) {
+
+	removedLocal := make(map[uint64]struct{})
+	for e := lc.remoteUpdateLog.Front(); e != nil; e = e.Next() {
+		htlc := e.Value.(*PaymentDescriptor)
+		if htlc.EntryType == Settle || htlc.EntryType == Fail {
+			removedLocal[htlc.ParentIndex] = struct{}{}
+		}
+	}
+	removedRemote := make(map[uint64]struct{})
+	for e := lc.localUpdateLog.Front(); e != nil; e = e.Next() {
+		htlc := e.Value.(*PaymentDescriptor)
+		if htlc.EntryType == Settle || htlc.EntryType == Fail {
+			removedRemote[htlc.ParentIndex] = struct{}{}
+		}
+	}
+
+	return removedLocal, removedRemote
+}
+
+// sumPendingAdds sums the value of all HTLC adds within both update logs
+// which match the passed filter, and haven't yet been removed by a
+// corresponding settle or fail.
+//
+// NOTE: The channel's mutex MUST be held when calling this method.
+func (lc *LightningChannel) sumPendingAdds(
+	filter func(*PaymentDescriptor) bool) btcutil.Amount {
+
+	removedLocal, removedRemote := lc.removedAdds()
+
+	sum := func(log *updateLog, removed map[uint64]struct{}) btcutil.Amount {
+		var total btcutil.Amount
+		for e := log.Front(); e != nil; e = e.Next() {
+			htlc := e.Value.(*PaymentDescriptor)
+			if htlc.EntryType != Add || !filter(htlc) {
+				continue
+			}
+			if _, ok := removed[htlc.Index]; ok {
+				continue
+			}
+
+			total += htlc.Amount
+		}
+
+		return total
+	}
+
+	return sum(lc.localUpdateLog, removedLocal) +
+		sum(lc.remoteUpdateLog, removedRemote)
+}
+
+// AddHTLC adds an HTLC to the state machine's local update log. This method
+// should be called when preparing to send an outgoing HTLC.
+//
+// TODO(roasbeef): check for duplicates below? edge case during restart w/ HTLC
+// persistence
+func (lc *LightningChannel) AddHTLC(htlc *lnwire.UpdateAddHTLC) (uint64, error) {
+	lc.Lock()
+	defer lc.Unlock()
+
+	if lc.channelState.ChanType == channeldb.RecoveredChannel {
+		return 0, ErrRecoveredChannel
+	}
+	if lc.deviceFundingKey {
+		return 0, ErrDeviceFundingKey
+	}
+	if lc.splice != nil {
+		return 0, ErrSpliceInProgress
+	}
+	if lc.localShutdown || lc.remoteShutdown {
+		return 0, ErrChanShuttingDown
+	}
+	if lc.channelState.OutgoingFrozen {
+		return 0, ErrOutgoingFrozen
+	}
+
+	if err := lc.validateCommitmentSanity(lc.remoteUpdateLog.logIndex,
+		lc.localUpdateLog.logIndex, true); err != nil {
+		return 0, err
+	}
+
+	err := lc.checkHTLCPolicy(htlc.Amount, PaymentHash(htlc.PaymentHash))
+	if err != nil {
+		return 0, err
+	}
+
+	ourBalance, _ := lc.availableBalances()
+	if lc.chanReserve != 0 && ourBalance-htlc.Amount < lc.chanReserve {
+		return 0, ErrBelowChanReserve
+	}
+
+	pd := &PaymentDescriptor{
+		EntryType: Add,
+		RHash:     PaymentHash(htlc.PaymentHash),
+		Timeout:   htlc.Expiry,
+		Amount:    htlc.Amount,
+		Index:     lc.localUpdateLog.logIndex,
+		addTime:   time.Now(),
+	}
+
+	lc.localUpdateLog.appendUpdate(pd)
+
+	return pd.Index, nil
+}
+
+// ErrHTLCRejected is returned by ReceiveHTLC when an HTLC offered by the
+// remote party violates the channel's constraints. The remote party has
+// already added the HTLC to its log, so it's still added to ours in order to
+// keep both logs in sync, and must be failed back once it's locked in.
+type ErrHTLCRejected struct {
+	// Index is the log index of the rejected HTLC.
+	Index uint64
+
+	// Reason is the constraint the HTLC violates.
+	Reason error
+}
+
+func (e *ErrHTLCRejected) Error() string {
+	return fmt.Sprintf("htlc %v rejected: %v", e.Index, e.Reason)
+}
+
+// ReceiveHTLC adds an HTLC to the state machine's remote update log. This
+// method should be called in response to receiving a new HTLC from the remote
+// party. If the HTLC would push the remote party's balance below the channel
+// reserve, or violates our HTLC policy, then it's added nonetheless, and an
+// ErrHTLCRejected is returned.
+func (lc *LightningChannel) ReceiveHTLC(htlc *lnwire.UpdateAddHTLC) (uint64, error) {
+	lc.Lock()
+	defer lc.Unlock()
+
+	if lc.deviceFundingKey {
+		return 0, ErrDeviceFundingKey
+	}
+	if lc.splice != nil {
+		return 0, ErrSpliceInProgress
+	}
+
+	// HTLCs offered by the remote party before it received our shutdown
+	// are still accepted, but none may follow its own.
+	if lc.remoteShutdown {
+		return 0, ErrChanShuttingDown
+	}
+
+	if err := lc.validateCommitmentSanity(lc.remoteUpdateLog.logIndex,
+		lc.localUpdateLog.logIndex, true); err != nil {
+		return 0, err
+	}
+
+	// The remote party is bound by the same reserve as us, so we'll
+	// reject any HTLC which would push their balance below it.
+	var rejection error
+	_, theirBalance := lc.availableBalances()
+	if lc.chanReserve != 0 && theirBalance-htlc.Amount < lc.chanReserve {
+		rejection = ErrBelowChanReserve
+	}
+
+	// The HTLC policy is checked while still holding the lock, and before
+	// the HTLC is added, so it isn't counted towards its own exposure, nor
+	// can a concurrent update slip in between the check and the add.
+	if rejection == nil {
+		rejection = lc.checkHTLCPolicy(htlc.Amount, htlc.PaymentHash)
+	}
+
+	pd := &PaymentDescriptor{
+		EntryType: Add,
+		RHash:     PaymentHash(htlc.PaymentHash),
+		Timeout:   htlc.Expiry,
+		Amount:    htlc.Amount,
+		Index:     lc.remoteUpdateLog.logIndex,
+		addTime:   time.Now(),
+	}
+
+	lc.remoteUpdateLog.appendUpdate(pd)
+
+	lc.rHashMap[pd.RHash] = append(lc.rHashMap[pd.RHash], pd)
+
+	if rejection != nil {
+		return pd.Index, &ErrHTLCRejected{
+			Index:  pd.Index,
+			Reason: rejection,
+		}
+	}
+
+	return pd.Index, nil
+}
+
+// UpdateFee proposes a new fee for the commitment transactions of both
+// parties by adding a fee update to the local update log. The new fee takes
+// effect within each commitment signed after the update, and once the prior
+// commitments have been revoked, the old fee is no longer enforceable. Only
+// the initiator of the channel, which pays the fee, may update it. The fee
+// which was to be paid prior to the update is returned.
+func (lc *LightningChannel) UpdateFee(fee btcutil.Amount) (btcutil.Amount,
+	error) {
+
+	lc.Lock()
+	defer lc.Unlock()
+
+	if lc.channelState.ChanType == channeldb.RecoveredChannel {
+		return 0, ErrRecoveredChannel
+	}
+	if lc.deviceFundingKey {
+		return 0, ErrDeviceFundingKey
+	}
+	if lc.splice != nil {
+		return 0, ErrSpliceInProgress
+	}
+	if !lc.channelState.IsInitiator {
+		return 0, ErrNonInitiatorFeeUpdate
+	}
+
+	prevFee := lc.pendingFee()
+	ourBalance, _ := lc.availableBalances()
+	if ourBalance+prevFee-fee < 0 {
+		return 0, ErrFeeUnaffordable
+	}
+
+	pd := &PaymentDescriptor{
+		EntryType: FeeUpdate,
+		Amount:    fee,
+		Index:     lc.localUpdateLog.logIndex,
+	}
+
+	lc.localUpdateLog.appendUpdate(pd)
+
+	return prevFee, nil
+}
+
+// ReceiveUpdateFee adds a fee update proposed by the remote party to the
+// remote update log. This method should be called in response to receiving
+// an UpdateFee message from the remote party, which must be the initiator of
+// the channel. If a ceiling is passed, a fee exceeding it is rejected, with
+// the fee rate of the commitment compared to feeRate, our own estimate in
+// sat/byte. This prevents a spike in the remote party's estimate, or a
+// malicious initiator, from burning their balance, and the value of any
+// HTLCs which become dust, into fees we'd have to broadcast.
+func (lc *LightningChannel) ReceiveUpdateFee(fee btcutil.Amount,
+	ceiling *FeeCeiling, feeRate btcutil.Amount) error {
+
+	lc.Lock()
+	defer lc.Unlock()
+
+	if lc.deviceFundingKey {
+		return ErrDeviceFundingKey
+	}
+	if lc.splice != nil {
+		return ErrSpliceInProgress
+	}
+	if lc.channelState.IsInitiator ||
+		lc.channelState.ChanType != channeldb.SingleFunder {
+
+		return ErrNonInitiatorFeeUpdate
+	}
+
+	_, theirBalance := lc.availableBalances()
+	if theirBalance+lc.pendingFee()-fee < 0 {
+		return ErrFeeUnaffordable
+	}
+
+	if ceiling != nil {
+		tip := lc.localCommitChain.tip()
+		numHTLCs := len(tip.outgoingHTLCs) + len(tip.incomingHTLCs)
+		if err := ceiling.Check(fee, commitVSize(numHTLCs),
+			feeRate); err != nil {
+
+			return fmt.Errorf("commitment fee rejected: %v", err)
+		}
+	}
+
+	pd := &PaymentDescriptor{
+		EntryType: FeeUpdate,
+		Amount:    fee,
+		Index:     lc.remoteUpdateLog.logIndex,
+	}
+
+	lc.remoteUpdateLog.appendUpdate(pd)
+
+	return nil
+}
+
+// SettleHTLC attempts to settle an existing outstanding received HTLC. The
+// remote log index of the HTLC settled is returned in order to facilitate
+// creating the corresponding wire message. In the case the supplied preimage
+// is invalid, an error is returned.
+func (lc *LightningChannel) SettleHTLC(preimage [32]byte) (uint64, error) {
+	lc.Lock()
+	defer lc.Unlock()
+
+	paymentHash := sha256.Sum256(preimage[:])
+	targetHTLCs, ok := lc.rHashMap[paymentHash]
+	if !ok {
+		return 0, fmt.Errorf("invalid payment hash")
+	}
+	targetHTLC := targetHTLCs[0]
+
+	pd := &PaymentDescriptor{
 		Amount:      targetHTLC.Amount,
 		RPreimage:   preimage,
 		Index:       lc.localUpdateLog.logIndex,
-		ParentIndex: targetHTLC.Index,
-		EntryType:   Settle,
+		ParentIndex: targetHTLC.Index,
+		EntryType:   Settle,
+	}
+
+	lc.localUpdateLog.appendUpdate(pd)
+
+	lc.rHashMap[paymentHash][0] = nil
+	lc.rHashMap[paymentHash] = lc.rHashMap[paymentHash][1:]
+	if len(lc.rHashMap[paymentHash]) == 0 {
+		delete(lc.rHashMap, paymentHash)
+	}
+
+	return targetHTLC.Index, nil
+}
+
+// ReceiveHTLCSettle attempts to settle an existing outgoing HTLC indexed by an
+// index into the local log. If the specified index doesn't exist within the
+// log, and error is returned. Similarly if the preimage is invalid w.r.t to
+// the referenced of then a distinct error is returned.
+func (lc *LightningChannel) ReceiveHTLCSettle(preimage [32]byte, logIndex uint64) error {
+	lc.Lock()
+	defer lc.Unlock()
+
+	paymentHash := sha256.Sum256(preimage[:])
+	htlc := lc.localUpdateLog.lookup(logIndex)
+	if htlc == nil {
+		return fmt.Errorf("non existant log entry")
+	}
+
+	if !bytes.Equal(htlc.RHash[:], paymentHash[:]) {
+		return fmt.Errorf("invalid payment hash")
+	}
+	if lc.removalPending(logIndex) {
+		return fmt.Errorf("log entry %v already removed", logIndex)
+	}
+
+	pd := &PaymentDescriptor{
+		Amount:      htlc.Amount,
+		RPreimage:   preimage,
+		ParentIndex: htlc.Index,
+		Index:       lc.remoteUpdateLog.logIndex,
+		EntryType:   Settle,
+	}
+
+	lc.remoteUpdateLog.appendUpdate(pd)
+
+	return nil
+}
+
+// FailHTLC attempts to fail a targeted HTLC by its payment hash, inserting an
+// entry which will remove the target log entry within the next commitment
+// update. This method is intended to be called in order to cancel in
+// _incoming_ HTLC.
+func (lc *LightningChannel) FailHTLC(rHash [32]byte) (uint64, error) {
+	lc.Lock()
+	defer lc.Unlock()
+
+	addEntries, ok := lc.rHashMap[rHash]
+	if !ok {
+		return 0, fmt.Errorf("unable to find HTLC to fail")
+	}
+	addEntry := addEntries[0]
+
+	pd := &PaymentDescriptor{
+		Amount:      addEntry.Amount,
+		RHash:       addEntry.RHash,
+		ParentIndex: addEntry.Index,
+		Index:       lc.localUpdateLog.logIndex,
+		EntryType:   Fail,
+	}
+
+	lc.localUpdateLog.appendUpdate(pd)
+
+	lc.rHashMap[rHash][0] = nil
+	lc.rHashMap[rHash] = lc.rHashMap[rHash][1:]
+	if len(lc.rHashMap[rHash]) == 0 {
+		delete(lc.rHashMap, rHash)
+	}
+
+	return addEntry.Index, nil
+}
+
+// ReceiveFailHTLC attempts to cancel a targeted HTLC by its log index,
+// inserting an entry which will remove the target log entry within the next
+// commitment update. This method should be called in response to the upstream
+// party cancelling an outgoing HTLC.
+func (lc *LightningChannel) ReceiveFailHTLC(logIndex uint64) error {
+	lc.Lock()
+	defer lc.Unlock()
+
+	htlc := lc.localUpdateLog.lookup(logIndex)
+	if htlc == nil {
+		return fmt.Errorf("unable to find HTLC to fail")
+	}
+	if lc.removalPending(logIndex) {
+		return fmt.Errorf("log entry %v already removed", logIndex)
+	}
+
+	pd := &PaymentDescriptor{
+		Amount:      htlc.Amount,
+		RHash:       htlc.RHash,
+		ParentIndex: htlc.Index,
+		Index:       lc.remoteUpdateLog.logIndex,
+		EntryType:   Fail,
+	}
+
+	lc.remoteUpdateLog.appendUpdate(pd)
+
+	return nil
+}
+
+// removalPending returns true if the remote party has already settled or
+// failed the HTLC at the passed index within our update log, though the HTLC
+// has yet to be removed from the log. A repeated removal would otherwise
+// credit the HTLC twice.
+//
+// NOTE: This method requires the channel's lock to be held.
+func (lc *LightningChannel) removalPending(logIndex uint64) bool {
+	for e := lc.remoteUpdateLog.Front(); e != nil; e = e.Next() {
+		pd := e.Value.(*PaymentDescriptor)
+		if (pd.EntryType == Settle || pd.EntryType == Fail) &&
+			pd.ParentIndex == logIndex {
+
+			return true
+		}
+	}
+
+	return false
+}
+
+// ChannelPoint returns the outpoint of the original funding transaction which
+// created this active channel. This outpoint is used throughout various
+// subsystems to uniquely identify an open channel.
+func (lc *LightningChannel) ChannelPoint() *wire.OutPoint {
+	return lc.channelState.ChanID
+}
+
+// FundingOutpoint returns the outpoint of the channel's current funding
+// output. This differs from the channel point once the channel has been
+// migrated to the funding output created by a splice.
+func (lc *LightningChannel) FundingOutpoint() *wire.OutPoint {
+	lc.RLock()
+	defer lc.RUnlock()
+
+	fundingOutpoint := lc.fundingTxIn.PreviousOutPoint
+	return &fundingOutpoint
+}
+
+// ForceCloseSummary describes the final commitment state before the channel is
+// locked-down to initiate a force closure by broadcasting the latest state
+// on-chain. The summary includes all the information required to claim all
+// rightfully owned outputs.
+// TODO(roasbeef): generalize, add HTLC info, etc.
+type ForceCloseSummary struct {
+	// CloseTx is the transaction which closed the channel on-chain. If we
+	// initiate the force close, then this'll be our latest commitment
+	// state. Otherwise, this'll be the state that the remote peer
+	// broadcasted on-chain.
+	CloseTx *wire.MsgTx
+
+	// CloseFee is the fee paid by the above close tx.
+	CloseFee btcutil.Amount
+
+	// SelfOutpoint is the output created by the above close tx which is
+	// spendable by us after a relative time delay.
+	SelfOutpoint wire.OutPoint
+
+	// SelfOutputMaturity is the relative maturity period before the above
+	// output can be claimed.
+	SelfOutputMaturity uint32
+
+	// SelfOutputSignDesc is a fully populated sign descriptor capable of
+	// generating a valid signature to sweep the self output.
+	SelfOutputSignDesc *SignDescriptor
+
+	// AnchorOutpoint is our anchor output within the above close tx. It's
+	// only set if the channel uses anchor commitments.
+	AnchorOutpoint wire.OutPoint
+
+	// AnchorSignDesc is a fully populated sign descriptor capable of
+	// generating a valid signature to sweep our anchor output. If the
+	// channel doesn't use anchor commitments, this is nil.
+	AnchorSignDesc *SignDescriptor
+
+	// HtlcResolutions describe how each non-dust HTLC output of the close
+	// tx may be claimed by us.
+	HtlcResolutions []HtlcResolution
+}
+
+// HtlcResolution describes an HTLC output of a commitment transaction we've
+// broadcast, along with the means to claim it. An outgoing HTLC is reclaimed
+// through its timeout clause once it has expired, while an incoming HTLC is
+// claimed through its success clause with the payment preimage. In either
+// case, the claim is subject to the same relative delay as our to-self
+// output, giving the remote party a chance to claim the HTLC first should the
+// commitment have been revoked.
+type HtlcResolution struct {
+	// Outpoint is the HTLC output within the commitment transaction.
+	Outpoint wire.OutPoint
+
+	// Incoming is true if the HTLC was offered to us by the remote party.
+	Incoming bool
+
+	// PaymentHash is the payment hash of the HTLC.
+	PaymentHash [32]byte
+
+	// Expiry is the absolute height at which the HTLC times out. An
+	// outgoing HTLC may only be reclaimed by a transaction with a lock
+	// time of at least this height.
+	Expiry uint32
+
+	// CsvDelay is the relative delay, counted from the confirmation of
+	// the commitment transaction, before the HTLC output may be claimed.
+	CsvDelay uint32
+
+	// SignDesc is a fully populated sign descriptor capable of generating
+	// a valid signature to claim the HTLC output.
+	SignDesc *SignDescriptor
+}
+
+// getSignedCommitTx function take the latest commitment transaction and populate
+// it with witness data.
+func (lc *LightningChannel) getSignedCommitTx() (*wire.MsgTx, error) {
+	// Fetch the current commitment transaction, along with their signature
+	// for the transaction. Once the splice transaction of the channel has
+	// confirmed, the current funding output is spent, so we'll instead use
+	// the version of the commitment transaction spending the new funding
+	// output.
+	commitTx := lc.channelState.OurCommitTx
+	commitSig := lc.channelState.OurCommitSig
+	signDesc := lc.signDesc
+	if lc.splice != nil && lc.splice.confirmed {
+		commitTx = lc.splice.localCommitTx
+		commitSig = lc.splice.localCommitSig
+		signDesc = lc.splice.signDesc
+	}
+	theirSig := append(commitSig, byte(txscript.SigHashAll))
+
+	// With this, we then generate the full witness so the caller can
+	// broadcast a fully signed transaction.
+	signDesc.SigHashes = txscript.NewTxSigHashes(commitTx)
+	ourSigRaw, err := lc.signer.SignOutputRaw(commitTx, signDesc)
+	if err != nil {
+		return nil, err
+	}
+
+	ourSig := append(ourSigRaw, byte(txscript.SigHashAll))
+
+	// With the final signature generated, create the witness stack
+	// required to spend from the multi-sig output.
+	ourKey := lc.channelState.OurMultiSigKey.SerializeCompressed()
+	theirKey := lc.channelState.TheirMultiSigKey.SerializeCompressed()
+
+	commitTx.TxIn[0].Witness = SpendMultiSig(lc.FundingWitnessScript, ourKey,
+		ourSig, theirKey, theirSig)
+
+	return commitTx, nil
+}
+
+// ForceClose executes a unilateral closure of the transaction at the current
+// lowest commitment height of the channel. Following a force closure, all
+// state transitions, or modifications to the state update logs will be
+// rejected. Additionally, this function also returns a ForceCloseSummary which
+// includes the necessary details required to sweep all the time-locked within
+// the commitment transaction.
+//
+// TODO(roasbeef): all methods need to abort if in dispute state
+// TODO(roasbeef): method to generate CloseSummaries for when the remote peer
+// does a unilateral close
+func (lc *LightningChannel) ForceClose() (*ForceCloseSummary, error) {
+	lc.Lock()
+	defer lc.Unlock()
+
+	// We don't know the commitment transaction of a recovered channel, so
+	// it can't be unilaterally closed.
+	if lc.channelState.ChanType == channeldb.RecoveredChannel {
+		return nil, ErrRecoveredChannel
+	}
+	if lc.divergence != nil {
+		return nil, ErrChannelFrozen
+	}
+	if lc.dataLoss {
+		return nil, ErrCommitSyncDataLoss
+	}
+
+	// Set the channel state to indicate that the channel is now in a
+	// contested state.
+	lc.status = channelDispute
+
+	commitTx, err := lc.getSignedCommitTx()
+	if err != nil {
+		return nil, err
+	}
+
+	// Re-derive the original pkScript for to-self output within the
+	// commitment transaction. We'll need this to find the corresponding
+	// output in the commitment transaction and potentially for creating
+	// the sign descriptor.
+	csvTimeout := lc.channelState.LocalCsvDelay
+	selfKey := lc.channelState.OurCommitKey
+	producer := lc.channelState.RevocationProducer
+	unusedRevocation, err := producer.AtIndex(lc.currentHeight)
+	if err != nil {
+		return nil, err
+	}
+	revokeKey := DeriveRevocationPubkey(lc.channelState.TheirCommitKey,
+		unusedRevocation[:])
+	selfScript, err := commitScriptToSelf(csvTimeout, selfKey, revokeKey)
+	if err != nil {
+		return nil, err
+	}
+	payToUsScriptHash, err := witnessScriptHash(selfScript)
+	if err != nil {
+		return nil, err
+	}
+
+	// Locate the output index of the delayed commitment output back to us.
+	// We'll return the details of this output to the caller so they can
+	// sweep it once it's mature.
+	// TODO(roasbeef): also return HTLC info, assumes only p2wsh is commit
+	// tx
+	var delayIndex uint32
+	var delayScript []byte
+	var selfSignDesc *SignDescriptor
+	for i, txOut := range commitTx.TxOut {
+		if !bytes.Equal(payToUsScriptHash, txOut.PkScript) {
+			continue
+		}
+
+		delayIndex = uint32(i)
+		delayScript = txOut.PkScript
+		break
+	}
+
+	// With the necessary information gathered above, create a new sign
+	// descriptor which is capable of generating the signature the caller
+	// needs to sweep this output. The hash cache, and input index are not
+	// set as the caller will decide these values once sweeping the output.
+	// If the output is non-existant (dust), have the sign descriptor be nil.
+	if len(delayScript) != 0 {
+		ourBalance := lc.channelState.OurBalance
+		if lc.splice != nil && lc.splice.confirmed {
+			ourBalance = lc.splice.ourBalance
+		}
+
+		selfSignDesc = &SignDescriptor{
+			PubKey:        selfKey,
+			WitnessScript: selfScript,
+			Output: &wire.TxOut{
+				PkScript: delayScript,
+				Value:    int64(ourBalance),
+			},
+			HashType: txscript.SigHashAll,
+		}
+	}
+
+	// Each HTLC output locked within the commitment transaction is
+	// claimed on its own terms, so we'll describe how to claim each.
+	revocationHash := sha256.Sum256(unusedRevocation[:])
+	htlcResolutions, err := lc.htlcResolutions(commitTx, csvTimeout,
+		revocationHash)
+	if err != nil {
+		return nil, err
+	}
+
+	// Whatever remains of the funding output once the commitment's
+	// outputs have been paid goes to fees.
+	closeFee := btcutil.Amount(lc.signDesc.Output.Value)
+	for _, txOut := range commitTx.TxOut {
+		closeFee -= btcutil.Amount(txOut.Value)
+	}
+
+	summary := &ForceCloseSummary{
+		CloseTx:  commitTx,
+		CloseFee: closeFee,
+		SelfOutpoint: wire.OutPoint{
+			Hash:  commitTx.TxHash(),
+			Index: delayIndex,
+		},
+		SelfOutputMaturity: csvTimeout,
+		SelfOutputSignDesc: selfSignDesc,
+		HtlcResolutions:    htlcResolutions,
+	}
+
+	// If the commitment transaction carries anchors, then we'll also
+	// locate our anchor output, allowing the caller to either sweep it, or
+	// spend it to bump the fee of the commitment transaction.
+	if lc.channelState.CommitFormat == channeldb.AnchorCommitment {
+		anchorKey := lc.channelState.OurMultiSigKey
+		witnessScript, err := anchorScript(anchorKey)
+		if err != nil {
+			return nil, err
+		}
+		anchorPkScript, err := witnessScriptHash(witnessScript)
+		if err != nil {
+			return nil, err
+		}
+
+		for i, txOut := range commitTx.TxOut {
+			if !bytes.Equal(anchorPkScript, txOut.PkScript) {
+				continue
+			}
+
+			summary.AnchorOutpoint = wire.OutPoint{
+				Hash:  commitTx.TxHash(),
+				Index: uint32(i),
+			}
+			summary.AnchorSignDesc = &SignDescriptor{
+				PubKey:        anchorKey,
+				WitnessScript: witnessScript,
+				Output: &wire.TxOut{
+					PkScript: anchorPkScript,
+					Value:    int64(AnchorSize),
+				},
+				HashType: txscript.SigHashAll,
+			}
+			break
+		}
 	}
 
-	lc.localUpdateLog.appendUpdate(pd)
+	// Finally, close the channel force close signal which notifies any
+	// subscribers that the channel has now been forcibly closed. This
+	// allows callers to begin to carry out any post channel closure
+	// activities.
+	close(lc.ForceCloseSignal)
+
+	return summary, nil
+}
+
+// htlcResolutions returns the resolution of each HTLC output of the passed
+// commitment transaction, which is our current commitment created with the
+// passed relative delay and revocation hash. HTLCs below the dust limit have
+// no output, and so no resolution.
+//
+// NOTE: The channel's mutex MUST be held when calling this method.
+func (lc *LightningChannel) htlcResolutions(commitTx *wire.MsgTx,
+	csvDelay uint32, revocationHash [32]byte) ([]HtlcResolution, error) {
+
+	localKey := lc.channelState.OurCommitKey
+	builder := lc.commitmentBuilder(false, lc.fundingTxIn, lc.Capacity)
+	commitHash := commitTx.TxHash()
+
+	// Several HTLCs may share an identical script, so each output is only
+	// matched once.
+	claimed := make(map[int]struct{})
 
-	lc.rHashMap[paymentHash][0] = nil
-	lc.rHashMap[paymentHash] = lc.rHashMap[paymentHash][1:]
-	if len(lc.rHashMap[paymentHash]) == 0 {
-		delete(lc.rHashMap, paymentHash)
+	var resolutions []HtlcResolution
+	for _, htlc := range lc.channelState.Htlcs {
+		// Our commitment carries the receiver's version of the script
+		// for incoming HTLCs, and the sender's version for outgoing
+		// ones, with us as the party able to claim each after the
+		// relative delay.
+		witnessScript, err := builder.htlcWitnessScript(revocationHash,
+			&CommitmentHTLC{
+				Offered:     !htlc.Incoming,
+				Amount:      htlc.Amt,
+				Expiry:      htlc.RefundTimeout,
+				PaymentHash: htlc.RHash,
+			})
+		if err != nil {
+			return nil, err
+		}
+		pkScript, err := witnessScriptHash(witnessScript)
+		if err != nil {
+			return nil, err
+		}
+
+		for i, txOut := range commitTx.TxOut {
+			if _, ok := claimed[i]; ok {
+				continue
+			}
+			if !bytes.Equal(pkScript, txOut.PkScript) {
+				continue
+			}
+			claimed[i] = struct{}{}
+
+			resolutions = append(resolutions, HtlcResolution{
+				Outpoint: wire.OutPoint{
+					Hash:  commitHash,
+					Index: uint32(i),
+				},
+				Incoming:    htlc.Incoming,
+				PaymentHash: htlc.RHash,
+				Expiry:      htlc.RefundTimeout,
+				CsvDelay:    csvDelay,
+				SignDesc: &SignDescriptor{
+					PubKey:        localKey,
+					WitnessScript: witnessScript,
+					Output: &wire.TxOut{
+						PkScript: pkScript,
+						Value:    txOut.Value,
+					},
+					HashType: txscript.SigHashAll,
+				},
+			})
+			break
+		}
 	}
 
-	return targetHTLC.Index, nil
+	return resolutions, nil
 }
 
-// ReceiveHTLCSettle attempts to settle an existing outgoing HTLC indexed by an
-// index into the local log. If the specified index doesn't exist within the
-// log, and error is returned. Similarly if the preimage is invalid w.r.t to
-// the referenced of then a distinct error is returned.
-func (lc *LightningChannel) ReceiveHTLCSettle(preimage [32]byte, logIndex uint64) error {
-	lc.Lock()
-	defer lc.Unlock()
+// DryRunForceClose returns the commitment transaction ForceClose would
+// broadcast, along with the amount returned to us by the transaction and the
+// relative delay before it may be swept. The transaction is returned without
+// its witness, so it can't be broadcast, and the state of the channel is left
+// untouched. As the commitment transaction spends a witness output, its txid
+// is identical to that of the signed transaction.
+func (lc *LightningChannel) DryRunForceClose() (*wire.MsgTx,
+	btcutil.Amount, uint32, error) {
 
-	paymentHash := sha256.Sum256(preimage[:])
-	htlc := lc.localUpdateLog.lookup(logIndex)
-	if htlc == nil {
-		return fmt.Errorf("non existant log entry")
+	lc.RLock()
+	defer lc.RUnlock()
+
+	if lc.channelState.ChanType == channeldb.RecoveredChannel {
+		return nil, 0, 0, ErrRecoveredChannel
 	}
 
-	if !bytes.Equal(htlc.RHash[:], paymentHash[:]) {
-		return fmt.Errorf("invalid payment hash")
+	commitTx := lc.channelState.OurCommitTx
+	ourBalance := lc.channelState.OurBalance
+	if lc.splice != nil && lc.splice.confirmed {
+		commitTx = lc.splice.localCommitTx
+		ourBalance = lc.splice.ourBalance
 	}
 
-	pd := &PaymentDescriptor{
-		Amount:      htlc.Amount,
-		RPreimage:   preimage,
-		ParentIndex: htlc.Index,
-		Index:       lc.remoteUpdateLog.logIndex,
-		EntryType:   Settle,
+	commitTx = commitTx.Copy()
+	for _, txIn := range commitTx.TxIn {
+		txIn.Witness = nil
 	}
 
-	lc.remoteUpdateLog.appendUpdate(pd)
+	return commitTx, ourBalance, lc.channelState.LocalCsvDelay, nil
+}
+
+// InitShutdown signals our intent to cooperatively close the channel, after
+// which no new HTLCs are offered over it. HTLCs already in flight are left to
+// resolve, and once the channel is quiescent, the closure may proceed via
+// InitCooperativeClose. Calling InitShutdown more than once has no effect.
+func (lc *LightningChannel) InitShutdown() error {
+	lc.Lock()
+	defer lc.Unlock()
+
+	if err := lc.canShutdown(); err != nil {
+		return err
+	}
 
+	lc.localShutdown = true
 	return nil
 }
 
-// FailHTLC attempts to fail a targeted HTLC by its payment hash, inserting an
-// entry which will remove the target log entry within the next commitment
-// update. This method is intended to be called in order to cancel in
-// _incoming_ HTLC.
-func (lc *LightningChannel) FailHTLC(rHash [32]byte) (uint64, error) {
+// ReceiveShutdown records the remote party's intent to cooperatively close
+// the channel, after which we neither accept nor offer new HTLCs over it.
+func (lc *LightningChannel) ReceiveShutdown() error {
 	lc.Lock()
 	defer lc.Unlock()
 
-	addEntries, ok := lc.rHashMap[rHash]
-	if !ok {
-		return 0, fmt.Errorf("unable to find HTLC to fail")
+	if err := lc.canShutdown(); err != nil {
+		return err
 	}
-	addEntry := addEntries[0]
 
-	pd := &PaymentDescriptor{
-		Amount:      addEntry.Amount,
-		RHash:       addEntry.RHash,
-		ParentIndex: addEntry.Index,
-		Index:       lc.localUpdateLog.logIndex,
-		EntryType:   Fail,
+	lc.remoteShutdown = true
+	return nil
+}
+
+// canShutdown returns an error if the channel is unable to be shut down.
+//
+// NOTE: This method MUST be called with the channel's mutex held.
+func (lc *LightningChannel) canShutdown() error {
+	switch {
+	case lc.status == channelClosed || lc.status == channelDispute:
+		return ErrChanClosing
+	case lc.splice != nil:
+		return ErrSpliceInProgress
 	}
 
-	lc.localUpdateLog.appendUpdate(pd)
+	return nil
+}
 
-	lc.rHashMap[rHash][0] = nil
-	lc.rHashMap[rHash] = lc.rHashMap[rHash][1:]
-	if len(lc.rHashMap[rHash]) == 0 {
-		delete(lc.rHashMap, rHash)
-	}
+// ShutdownPending returns true if either party has signalled its intent to
+// cooperatively close the channel.
+func (lc *LightningChannel) ShutdownPending() bool {
+	lc.RLock()
+	defer lc.RUnlock()
 
-	return addEntry.Index, nil
+	return lc.localShutdown || lc.remoteShutdown
 }
 
-// ReceiveFailHTLC attempts to cancel a targeted HTLC by its log index,
-// inserting an entry which will remove the target log entry within the next
-// commitment update. This method should be called in response to the upstream
-// party cancelling an outgoing HTLC.
-func (lc *LightningChannel) ReceiveFailHTLC(logIndex uint64) error {
+// ShutdownComplete returns true once both parties have signalled their
+// intent to cooperatively close the channel, and all updates in flight have
+// since been resolved, allowing the closure to proceed.
+func (lc *LightningChannel) ShutdownComplete() bool {
+	lc.RLock()
+	defer lc.RUnlock()
+
+	return lc.localShutdown && lc.remoteShutdown && lc.quiescent()
+}
+
+// quiescent returns true if the channel has no updates in flight: neither
+// party's update log holds any entries, and both commitment chains have been
+// fully revoked down to a single commitment.
+//
+// NOTE: This method MUST be called with the channel's mutex held.
+func (lc *LightningChannel) quiescent() bool {
+	return lc.localUpdateLog.Len() == 0 && lc.remoteUpdateLog.Len() == 0 &&
+		lc.localCommitChain.commitments.Len() == 1 &&
+		lc.remoteCommitChain.commitments.Len() == 1 && !lc.pendingACK
+}
+
+// InitCooperativeClose initiates a cooperative closure of an active lightning
+// channel. This method should only be executed once all pending HTLCs (if any)
+// on the channel have been cleared/removed. Upon completion, the source
+// channel will shift into the "closing" state, which indicates that all
+// incoming/outgoing HTLC requests should be rejected. Our version of the
+// closing transaction pays the passed fee in its entirety from our own
+// output. Our signature for it, and its txid are returned. The signature
+// should be sent to the remote party, which will reply with its own signature
+// allowing us to complete and broadcast the transaction via
+// CompleteCooperativeClose. In the case of an unresponsive remote party, the
+// initiator can either choose to execute a force closure, or backoff for a
+// period of time, and retry the cooperative closure.
+//
+// NOTE: The remote party may have already proposed its own version of the
+// closing transaction, in which case both versions remain valid until one of
+// them confirms.
+//
+// TODO(roasbeef): caller should initiate signal to reject all incoming HTLCs,
+// settle any inflight.
+func (lc *LightningChannel) InitCooperativeClose(
+	fee btcutil.Amount) ([]byte, *chainhash.Hash, error) {
+
 	lc.Lock()
 	defer lc.Unlock()
 
-	htlc := lc.localUpdateLog.lookup(logIndex)
-	if htlc == nil {
-		return fmt.Errorf("unable to find HTLC to fail")
+	// If we've already proposed our own version of the closing
+	// transaction, then ignore this request.
+	if lc.status == channelClosed || lc.localCloseFee != 0 ||
+		(lc.status == channelClosing && lc.remoteCloseFee == 0) {
+
+		// TODO(roasbeef): check to ensure no pending payments
+		return nil, nil, ErrChanClosing
 	}
 
-	pd := &PaymentDescriptor{
-		Amount:      htlc.Amount,
-		RHash:       htlc.RHash,
-		ParentIndex: htlc.Index,
-		Index:       lc.remoteUpdateLog.logIndex,
-		EntryType:   Fail,
+	closeSig, closeTx, err := lc.signCooperativeClose(fee)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := lc.recordCooperativeClose(closeTx, fee, true); err != nil {
+		return nil, nil, err
 	}
 
-	lc.remoteUpdateLog.appendUpdate(pd)
+	// As everything checks out, indicate in the channel status that a
+	// channel closure has been initiated.
+	lc.status = channelClosing
+	lc.localCloseFee = fee
 
-	return nil
+	closeTxSha := closeTx.TxHash()
+	return closeSig, &closeTxSha, nil
 }
 
-// ChannelPoint returns the outpoint of the original funding transaction which
-// created this active channel. This outpoint is used throughout various
-// subsystems to uniquely identify an open channel.
-func (lc *LightningChannel) ChannelPoint() *wire.OutPoint {
-	return lc.channelState.ChanID
-}
+// BumpCooperativeClose signs a replacement for our version of the cooperative
+// closure transaction, paying the passed fee from our own output. As each
+// party pays for its own version in its entirety, either party is able to
+// bump the fee of its version without the consent of the other. The signature
+// for the replacement transaction, and its txid are returned. The signature
+// should be sent to the remote party, which will reply with its own signature
+// allowing us to broadcast the replacement, allowing a closure which is stuck
+// below the prevailing fee rate to confirm.
+func (lc *LightningChannel) BumpCooperativeClose(
+	fee btcutil.Amount) ([]byte, *chainhash.Hash, error) {
 
-// addHTLC adds a new HTLC to the passed commitment transaction. One of four
-// full scripts will be generated for the HTLC output depending on if the HTLC
-// is incoming and if it's being applied to our commitment transaction or that
-// of the remote node's. Additionally, in order to be able to efficiently
-// locate the added HTLC on the commitment transaction from the
-// PaymentDescriptor that generated it, the generated script is stored within
-// the descriptor itself.
-func (lc *LightningChannel) addHTLC(commitTx *wire.MsgTx, ourCommit bool,
-	paymentDesc *PaymentDescriptor, revocation [32]byte, delay uint32,
-	isIncoming bool) error {
+	lc.Lock()
+	defer lc.Unlock()
 
-	localKey := lc.channelState.OurCommitKey
-	remoteKey := lc.channelState.TheirCommitKey
-	timeout := paymentDesc.Timeout
-	rHash := paymentDesc.RHash
-
-	// Generate the proper redeem scripts for the HTLC output modified by
-	// two-bits denoting if this is an incoming HTLC, and if the HTLC is
-	// being applied to their commitment transaction or ours.
-	var pkScript []byte
-	var err error
-	switch {
-	// The HTLC is paying to us, and being applied to our commitment
-	// transaction. So we need to use the receiver's version of HTLC the
-	// script.
-	case isIncoming && ourCommit:
-		pkScript, err = receiverHTLCScript(timeout, delay, remoteKey,
-			localKey, revocation[:], rHash[:])
-	// We're being paid via an HTLC by the remote party, and the HTLC is
-	// being added to their commitment transaction, so we use the sender's
-	// version of the HTLC script.
-	case isIncoming && !ourCommit:
-		pkScript, err = senderHTLCScript(timeout, delay, remoteKey,
-			localKey, revocation[:], rHash[:])
-	// We're sending an HTLC which is being added to our commitment
-	// transaction. Therefore, we need to use the sender's version of the
-	// HTLC script.
-	case !isIncoming && ourCommit:
-		pkScript, err = senderHTLCScript(timeout, delay, localKey,
-			remoteKey, revocation[:], rHash[:])
-	// Finally, we're paying the remote party via an HTLC, which is being
-	// added to their commitment transaction. Therefore, we use the
-	// receiver's version of the HTLC script.
-	case !isIncoming && !ourCommit:
-		pkScript, err = receiverHTLCScript(timeout, delay, localKey,
-			remoteKey, revocation[:], rHash[:])
+	if lc.status != channelClosing || lc.localCloseFee == 0 {
+		return nil, nil, fmt.Errorf("channel isn't being cooperatively " +
+			"closed by us")
 	}
-	if err != nil {
-		return err
+	if fee <= lc.localCloseFee {
+		return nil, nil, ErrCloseFeeTooLow
 	}
 
-	// Now that we have the redeem scripts, create the P2WSH public key
-	// script for the output itself.
-	htlcP2WSH, err := witnessScriptHash(pkScript)
+	closeSig, closeTx, err := lc.signCooperativeClose(fee)
 	if err != nil {
-		return err
+		return nil, nil, err
+	}
+	if err := lc.recordCooperativeClose(closeTx, fee, true); err != nil {
+		return nil, nil, err
 	}
 
-	// Add the new HTLC outputs to the respective commitment transactions.
-	amountPending := int64(paymentDesc.Amount)
-	commitTx.AddTxOut(wire.NewTxOut(amountPending, htlcP2WSH))
-
-	// Store the pkScript of this particular PaymentDescriptor so we can
-	// quickly locate it within the commitment transaction later.
-	paymentDesc.pkScript = htlcP2WSH
+	lc.localCloseFee = fee
 
-	return nil
+	closeTxSha := closeTx.TxHash()
+	return closeSig, &closeTxSha, nil
 }
 
-// ForceCloseSummary describes the final commitment state before the channel is
-// locked-down to initiate a force closure by broadcasting the latest state
-// on-chain. The summary includes all the information required to claim all
-// rightfully owned outputs.
-// TODO(roasbeef): generalize, add HTLC info, etc.
-type ForceCloseSummary struct {
-	// CloseTx is the transaction which closed the channel on-chain. If we
-	// initiate the force close, then this'll be our latest commitment
-	// state. Otherwise, this'll be the state that the remote peer
-	// broadcasted on-chain.
-	CloseTx *wire.MsgTx
+// CooperativeCloseFee returns the fee paid by the latest version of our own
+// cooperative closure transaction, or zero if we haven't proposed one.
+func (lc *LightningChannel) CooperativeCloseFee() btcutil.Amount {
+	lc.RLock()
+	defer lc.RUnlock()
 
-	// SelfOutpoint is the output created by the above close tx which is
-	// spendable by us after a relative time delay.
-	SelfOutpoint wire.OutPoint
+	return lc.localCloseFee
+}
 
-	// SelfOutputMaturity is the relative maturity period before the above
-	// output can be claimed.
-	SelfOutputMaturity uint32
+// CooperativeCloseVersions returns each version of the cooperative closure
+// transaction we've signed, in the order they were signed, including those
+// signed before the channel was last loaded from disk.
+func (lc *LightningChannel) CooperativeCloseVersions() []*channeldb.CoopCloseVersion {
+	lc.RLock()
+	defer lc.RUnlock()
 
-	// SelfOutputSignDesc is a fully populated sign descriptor capable of
-	// generating a valid signature to sweep the self output.
-	SelfOutputSignDesc *SignDescriptor
+	versions := make([]*channeldb.CoopCloseVersion, len(lc.closeVersions))
+	copy(versions, lc.closeVersions)
+	return versions
 }
 
-// getSignedCommitTx function take the latest commitment transaction and populate
-// it with witness data.
-func (lc *LightningChannel) getSignedCommitTx() (*wire.MsgTx, error) {
-	// Fetch the current commitment transaction, along with their signature
-	// for the transaction.
-	commitTx := lc.channelState.OurCommitTx
-	theirSig := append(lc.channelState.OurCommitSig, byte(txscript.SigHashAll))
-
-	// With this, we then generate the full witness so the caller can
-	// broadcast a fully signed transaction.
-	lc.signDesc.SigHashes = txscript.NewTxSigHashes(commitTx)
-	ourSigRaw, err := lc.signer.SignOutputRaw(commitTx, lc.signDesc)
-	if err != nil {
-		return nil, err
+// recordCooperativeClose writes the passed version of the cooperative closure
+// transaction, which we've just signed, to disk, so the closure can be
+// resumed, and our own version bumped from where it left off, after a
+// restart.
+//
+// NOTE: This method MUST be called with the channel's mutex held.
+func (lc *LightningChannel) recordCooperativeClose(closeTx *wire.MsgTx,
+	fee btcutil.Amount, localPays bool) error {
+
+	version := &channeldb.CoopCloseVersion{
+		Txid:      closeTx.TxHash(),
+		Fee:       fee,
+		LocalPays: localPays,
+	}
+	if err := lc.channelState.AddCoopCloseVersion(version); err != nil {
+		return err
 	}
 
-	ourSig := append(ourSigRaw, byte(txscript.SigHashAll))
+	lc.closeVersions = append(lc.closeVersions, version)
+	return nil
+}
 
-	// With the final signature generated, create the witness stack
-	// required to spend from the multi-sig output.
-	ourKey := lc.channelState.OurMultiSigKey.SerializeCompressed()
-	theirKey := lc.channelState.TheirMultiSigKey.SerializeCompressed()
+// restoreCooperativeClose restores the versions of the cooperative closure
+// transaction written to disk, if any, placing the channel back into the
+// closing state, with the latest fee paid by each party's version.
+func (lc *LightningChannel) restoreCooperativeClose() error {
+	versions, err := lc.channelState.FetchCoopCloseVersions()
+	if err != nil {
+		return err
+	}
 
-	commitTx.TxIn[0].Witness = SpendMultiSig(lc.FundingWitnessScript, ourKey,
-		ourSig, theirKey, theirSig)
+	for _, version := range versions {
+		switch {
+		case version.LocalPays && version.Fee > lc.localCloseFee:
+			lc.localCloseFee = version.Fee
+		case !version.LocalPays && version.Fee > lc.remoteCloseFee:
+			lc.remoteCloseFee = version.Fee
+		}
+	}
+	if len(versions) != 0 {
+		lc.status = channelClosing
+	}
+	lc.closeVersions = versions
 
-	return commitTx, nil
+	return nil
 }
 
-// ForceClose executes a unilateral closure of the transaction at the current
-// lowest commitment height of the channel. Following a force closure, all
-// state transitions, or modifications to the state update logs will be
-// rejected. Additionally, this function also returns a ForceCloseSummary which
-// includes the necessary details required to sweep all the time-locked within
-// the commitment transaction.
-//
-// TODO(roasbeef): all methods need to abort if in dispute state
-// TODO(roasbeef): method to generate CloseSummaries for when the remote peer
-// does a unilateral close
-func (lc *LightningChannel) ForceClose() (*ForceCloseSummary, error) {
-	lc.Lock()
-	defer lc.Unlock()
+// CooperativeCloseFeeAtRate returns the fee our version of the cooperative
+// closure transaction must pay to achieve the passed fee rate in satoshis per
+// byte of its virtual size.
+func (lc *LightningChannel) CooperativeCloseFeeAtRate(
+	feePerByte btcutil.Amount) (btcutil.Amount, error) {
 
-	// Set the channel state to indicate that the channel is now in a
-	// contested state.
-	lc.status = channelDispute
+	lc.RLock()
+	defer lc.RUnlock()
 
-	commitTx, err := lc.getSignedCommitTx()
+	closeTx, err := lc.createCooperativeCloseTx(0, true)
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
 
-	// Re-derive the original pkScript for to-self output within the
-	// commitment transaction. We'll need this to find the corresponding
-	// output in the commitment transaction and potentially for creating
-	// the sign descriptor.
-	csvTimeout := lc.channelState.LocalCsvDelay
-	selfKey := lc.channelState.OurCommitKey
-	producer := lc.channelState.RevocationProducer
-	unusedRevocation, err := producer.AtIndex(lc.currentHeight)
-	if err != nil {
-		return nil, err
+	// The witness spending the funding output carries a signature of
+	// each party, so it's of the same size whichever version confirms.
+	weight := blockchain.WitnessScaleFactor*closeTx.SerializeSizeStripped() +
+		WitnessHeaderSize + WitnessSize
+	vsize := (weight + blockchain.WitnessScaleFactor - 1) /
+		blockchain.WitnessScaleFactor
+
+	return feePerByte * btcutil.Amount(vsize), nil
+}
+
+// signCooperativeClose creates our version of the cooperative closure
+// transaction paying the passed fee, returning our signature for it along with
+// the transaction itself.
+//
+// NOTE: This method MUST be called with the channel's mutex held.
+func (lc *LightningChannel) signCooperativeClose(
+	fee btcutil.Amount) ([]byte, *wire.MsgTx, error) {
+
+	if lc.divergence != nil {
+		return nil, nil, ErrChannelFrozen
 	}
-	revokeKey := DeriveRevocationPubkey(lc.channelState.TheirCommitKey,
-		unusedRevocation[:])
-	selfScript, err := commitScriptToSelf(csvTimeout, selfKey, revokeKey)
+	if lc.dataLoss {
+		return nil, nil, ErrCommitSyncDataLoss
+	}
+	if lc.splice != nil {
+		return nil, nil, ErrSpliceInProgress
+	}
+
+	closeTx, err := lc.createCooperativeCloseTx(fee, true)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	payToUsScriptHash, err := witnessScriptHash(selfScript)
+
+	// TODO: the funding output is always a P2WSH 2-of-2 multisig, so the
+	// closure is signed with plain ECDSA signatures and needs no nonce
+	// exchange. Should taproot funding outputs be introduced, their
+	// MuSig2 key-path closure must fall back to a script-path spend of a
+	// committed multisig leaf after repeated failed nonce exchanges, so
+	// a buggy peer can't strand the funds behind the key path.
+	//
+	// Finally, sign the completed cooperative closure transaction. We'll
+	// simply send our signature over to the remote party, using the
+	// generated txid to be notified once the closure transaction has been
+	// confirmed.
+	lc.signDesc.SigHashes = txscript.NewTxSigHashes(closeTx)
+	closeSig, err := lc.signer.SignOutputRaw(closeTx, lc.signDesc)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	// Locate the output index of the delayed commitment output back to us.
-	// We'll return the details of this output to the caller so they can
-	// sweep it once it's mature.
-	// TODO(roasbeef): also return HTLC info, assumes only p2wsh is commit
-	// tx
-	var delayIndex uint32
-	var delayScript []byte
-	var selfSignDesc *SignDescriptor
-	for i, txOut := range commitTx.TxOut {
-		if !bytes.Equal(payToUsScriptHash, txOut.PkScript) {
-			continue
-		}
+	return closeSig, closeTx, nil
+}
+
+// CompleteCooperativeClose completes the latest version of our own
+// cooperative closure transaction with the passed signature of the remote
+// party, which must cover the version paying the passed fee. This method
+// should be called in response to the remote node signing a closure
+// transaction we've proposed. A fully signed closure transaction is returned.
+// It is the duty of the proposing node to broadcast a signed+valid closure
+// transaction to the network.
+//
+// NOTE: The passed remote sig is expected to be a fully complete signature
+// including the proper sighash byte.
+func (lc *LightningChannel) CompleteCooperativeClose(remoteSig []byte,
+	fee btcutil.Amount) (*wire.MsgTx, error) {
 
-		delayIndex = uint32(i)
-		delayScript = txOut.PkScript
-		break
+	lc.Lock()
+	defer lc.Unlock()
+
+	if lc.status != channelClosing || lc.localCloseFee == 0 {
+		return nil, fmt.Errorf("channel isn't being cooperatively " +
+			"closed by us")
 	}
 
-	// With the necessary information gathered above, create a new sign
-	// descriptor which is capable of generating the signature the caller
-	// needs to sweep this output. The hash cache, and input index are not
-	// set as the caller will decide these values once sweeping the output.
-	// If the output is non-existant (dust), have the sign descriptor be nil.
-	if len(delayScript) != 0 {
-		selfSignDesc = &SignDescriptor{
-			PubKey:        selfKey,
-			WitnessScript: selfScript,
-			Output: &wire.TxOut{
-				PkScript: delayScript,
-				Value:    int64(lc.channelState.OurBalance),
-			},
-			HashType: txscript.SigHashAll,
-		}
+	// A signature for a version we've since replaced is of no use, as the
+	// replacement would conflict with it.
+	if fee != lc.localCloseFee {
+		return nil, fmt.Errorf("signature covers closure transaction "+
+			"paying %v, latest version pays %v", fee,
+			lc.localCloseFee)
 	}
 
-	// Finally, close the channel force close signal which notifies any
-	// subscribers that the channel has now been forcibly closed. This
-	// allows callers to begin to carry out any post channel closure
-	// activities.
-	close(lc.ForceCloseSignal)
+	closeTx, _, err := lc.completeCooperativeClose(remoteSig, fee, true)
+	if err != nil {
+		return nil, err
+	}
 
-	return &ForceCloseSummary{
-		CloseTx: commitTx,
-		SelfOutpoint: wire.OutPoint{
-			Hash:  commitTx.TxHash(),
-			Index: delayIndex,
-		},
-		SelfOutputMaturity: csvTimeout,
-		SelfOutputSignDesc: selfSignDesc,
-	}, nil
+	return closeTx, nil
 }
 
-// InitCooperativeClose initiates a cooperative closure of an active lightning
-// channel. This method should only be executed once all pending HTLCs (if any)
-// on the channel have been cleared/removed. Upon completion, the source
-// channel will shift into the "closing" state, which indicates that all
-// incoming/outgoing HTLC requests should be rejected. A signature for the
-// closing transaction, and the txid of the closing transaction are returned.
-// The initiator of the channel closure should then watch the blockchain for a
-// confirmation of the closing transaction before considering the channel
-// terminated. In the case of an unresponsive remote party, the initiator can
-// either choose to execute a force closure, or backoff for a period of time,
-// and retry the cooperative closure.
+// SignRemoteCooperativeClose signs the remote party's version of the
+// cooperative closure transaction, which pays the passed fee from the remote
+// party's output. This method should be called in response to the remote node
+// proposing, or bumping the fee of, its own closure transaction. The remote
+// signature is verified, and our signature along with the txid of the
+// transaction are returned. The signature should be sent to the remote party,
+// which will then broadcast the transaction. Upon completion, the channel
+// will shift into the "closing" state.
 //
-// TODO(roasbeef): caller should initiate signal to reject all incoming HTLCs,
-// settle any inflight.
-func (lc *LightningChannel) InitCooperativeClose() ([]byte, *chainhash.Hash, error) {
+// NOTE: The passed remote sig is expected to be a fully complete signature
+// including the proper sighash byte.
+func (lc *LightningChannel) SignRemoteCooperativeClose(remoteSig []byte,
+	fee btcutil.Amount) ([]byte, *chainhash.Hash, error) {
+
 	lc.Lock()
 	defer lc.Unlock()
 
-	// If we're already closing the channel, then ignore this request.
-	if lc.status == channelClosing || lc.status == channelClosed {
-		// TODO(roasbeef): check to ensure no pending payments
+	// If the channel has already been closed, then ignore this request.
+	if lc.status == channelClosed {
 		return nil, nil, ErrChanClosing
 	}
-
-	closeTx := CreateCooperativeCloseTx(lc.fundingTxIn,
-		lc.channelState.OurBalance, lc.channelState.TheirBalance,
-		lc.channelState.OurDeliveryScript, lc.channelState.TheirDeliveryScript,
-		lc.channelState.IsInitiator)
-
-	// Ensure that the transaction doesn't explicitly violate any
-	// consensus rules such as being too big, or having any value with a
-	// negative output.
-	tx := btcutil.NewTx(closeTx)
-	if err := blockchain.CheckTransactionSanity(tx); err != nil {
-		return nil, nil, err
+	if fee <= lc.remoteCloseFee {
+		return nil, nil, ErrCloseFeeTooLow
 	}
 
-	// Finally, sign the completed cooperative closure transaction. As the
-	// initiator we'll simply send our signature over to the remote party,
-	// using the generated txid to be notified once the closure transaction
-	// has been confirmed.
-	lc.signDesc.SigHashes = txscript.NewTxSigHashes(closeTx)
-	closeSig, err := lc.signer.SignOutputRaw(closeTx, lc.signDesc)
+	closeTx, closeSig, err := lc.completeCooperativeClose(remoteSig, fee,
+		false)
 	if err != nil {
 		return nil, nil, err
 	}
+	if err := lc.recordCooperativeClose(closeTx, fee, false); err != nil {
+		return nil, nil, err
+	}
 
-	// As everything checks out, indicate in the channel status that a
-	// channel closure has been initiated.
+	// As the transaction is sane, and the scripts are valid we'll mark the
+	// channel as closing, as the remote party will now be able to
+	// broadcast the closure transaction.
 	lc.status = channelClosing
+	lc.remoteCloseFee = fee
 
 	closeTxSha := closeTx.TxHash()
 	return closeSig, &closeTxSha, nil
 }
 
-// CompleteCooperativeClose completes the cooperative closure of the target
-// active lightning channel. This method should be called in response to the
-// remote node initiating a cooperative channel closure. A fully signed closure
-// transaction is returned. It is the duty of the responding node to broadcast
-// a signed+valid closure transaction to the network.
+// createCooperativeCloseTx creates the version of the cooperative closure
+// transaction paying the passed fee, which is paid from our output if
+// localPays is true, and from the remote party's output otherwise.
 //
-// NOTE: The passed remote sig is expected to be a fully complete signature
-// including the proper sighash byte.
-func (lc *LightningChannel) CompleteCooperativeClose(remoteSig []byte) (*wire.MsgTx, error) {
-	lc.Lock()
-	defer lc.Unlock()
-
-	// If we're already closing the channel, then ignore this request.
-	if lc.status == channelClosing || lc.status == channelClosed {
-		// TODO(roasbeef): check to ensure no pending payments
-		return nil, ErrChanClosing
+// NOTE: This method MUST be called with the channel's mutex held.
+func (lc *LightningChannel) createCooperativeCloseTx(fee btcutil.Amount,
+	localPays bool) (*wire.MsgTx, error) {
+
+	// The cooperative closure carries no anchors, so the value locked
+	// within them is returned to the initiator, who paid for them.
+	ourBalance := lc.channelState.OurBalance
+	theirBalance := lc.channelState.TheirBalance
+	if lc.channelState.CommitFormat == channeldb.AnchorCommitment {
+		if lc.channelState.IsInitiator {
+			ourBalance += 2 * AnchorSize
+		} else {
+			theirBalance += 2 * AnchorSize
+		}
 	}
 
-	// Create the transaction used to return the current settled balance
-	// on this active channel back to both parties. In this current model,
-	// the initiator pays full fees for the cooperative close transaction.
-	closeTx := CreateCooperativeCloseTx(lc.fundingTxIn,
-		lc.channelState.OurBalance, lc.channelState.TheirBalance,
-		lc.channelState.OurDeliveryScript, lc.channelState.TheirDeliveryScript,
-		lc.channelState.IsInitiator)
-
-	// Ensure that the transaction doesn't explicitly validate any
+	closeTx := CreateCooperativeCloseTx(lc.fundingTxIn, fee,
+		ourBalance, theirBalance, lc.channelState.OurDeliveryScript,
+		lc.channelState.TheirDeliveryScript, localPays)
+
+	// Ensure that the transaction doesn't explicitly violate any
 	// consensus rules such as being too big, or having any value with a
 	// negative output.
 	tx := btcutil.NewTx(closeTx)
@@ -2320,13 +4024,43 @@ func (lc *LightningChannel) CompleteCooperativeClose(remoteSig []byte) (*wire.Ms
 		return nil, err
 	}
 
+	return closeTx, nil
+}
+
+// completeCooperativeClose creates the version of the cooperative closure
+// transaction paying the passed fee, and completes it with our signature and
+// the passed remote signature. The fully signed transaction, along with our
+// signature, is only returned if it's valid.
+//
+// NOTE: This method MUST be called with the channel's mutex held.
+func (lc *LightningChannel) completeCooperativeClose(remoteSig []byte,
+	fee btcutil.Amount, localPays bool) (*wire.MsgTx, []byte, error) {
+
+	if lc.divergence != nil {
+		return nil, nil, ErrChannelFrozen
+	}
+	if lc.dataLoss {
+		return nil, nil, ErrCommitSyncDataLoss
+	}
+	if lc.splice != nil {
+		return nil, nil, ErrSpliceInProgress
+	}
+
+	// Create the transaction used to return the current settled balance
+	// on this active channel back to both parties, with the proposer of
+	// this version paying the fee in full.
+	closeTx, err := lc.createCooperativeCloseTx(fee, localPays)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	// With the transaction created, we can finally generate our half of
 	// the 2-of-2 multi-sig needed to redeem the funding output.
 	hashCache := txscript.NewTxSigHashes(closeTx)
 	lc.signDesc.SigHashes = hashCache
 	closeSig, err := lc.signer.SignOutputRaw(closeTx, lc.signDesc)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	// Finally, construct the witness stack minding the order of the
@@ -2344,27 +4078,387 @@ func (lc *LightningChannel) CompleteCooperativeClose(remoteSig []byte) (*wire.Ms
 		txscript.StandardVerifyFlags, nil, hashCache,
 		int64(lc.channelState.Capacity))
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	if err := vm.Execute(); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	// As the transaction is sane, and the scripts are valid we'll mark the
-	// channel now as closed as the closure transaction should get into the
-	// chain in a timely manner and possibly be re-broadcast by the wallet.
-	lc.status = channelClosed
-
-	return closeTx, nil
+	return closeTx, closeSig, nil
 }
 
 // DeleteState deletes all state concerning the channel from the underlying
 // database, only leaving a small summary describing metadata of the
 // channel's lifetime.
 func (lc *LightningChannel) DeleteState() error {
+	lc.Lock()
+	defer lc.Unlock()
+
+	return lc.deleteState()
+}
+
+// deleteState deletes all state concerning the channel from the underlying
+// database. Holding the channel's lock while doing so ensures a concurrent
+// state transition can't write to the channel's state once it's been
+// deleted.
+//
+// NOTE: The channel's mutex MUST be held when calling this method.
+func (lc *LightningChannel) deleteState() error {
 	return lc.channelState.CloseChannel()
 }
 
+// ErrStateDiverged describes a divergence between a channel's in-memory state
+// and its state persisted within the database.
+type ErrStateDiverged struct {
+	// Field is the element of the channel state which diverged.
+	Field string
+
+	// InMemory and Persisted are the diverging values of the field.
+	InMemory  string
+	Persisted string
+}
+
+func (e *ErrStateDiverged) Error() string {
+	return fmt.Sprintf("channel %v diverged, in-memory %v but persisted "+
+		"%v", e.Field, e.InMemory, e.Persisted)
+}
+
+// VerifyPersistedState cross-checks the channel's in-memory state against the
+// state persisted within the database: the commitment height, our current
+// commitment transaction and the signature for it, and the store of the
+// remote party's revocations. If they diverge, then the channel is frozen,
+// refusing to produce any further signatures until the divergence is
+// acknowledged via AckDivergence, and an ErrStateDiverged describing the
+// divergence is returned.
+func (lc *LightningChannel) VerifyPersistedState() error {
+	lc.Lock()
+	defer lc.Unlock()
+
+	state := lc.channelState
+	channels, err := state.Db.FetchOpenChannels(state.IdentityPub)
+	if err != nil {
+		return err
+	}
+
+	var persisted *channeldb.OpenChannel
+	for _, channel := range channels {
+		if *channel.ChanID == *state.ChanID {
+			persisted = channel
+			break
+		}
+	}
+	if persisted == nil {
+		return fmt.Errorf("channel %v not found", state.ChanID)
+	}
+
+	divergence := compareChannelState(lc.currentHeight,
+		lc.localCommitChain.tail(), state.RevocationStore, persisted)
+	if divergence == nil {
+		return nil
+	}
+
+	// If the channel isn't already frozen, then record the divergence
+	// which froze it.
+	if lc.divergence == nil {
+		lc.divergence = divergence
+	}
+
+	return divergence
+}
+
+// compareChannelState compares the passed in-memory commitment height, local
+// commitment, and revocation store against the persisted state of the
+// channel, returning the first divergence found, if any.
+func compareChannelState(height uint64, localCommit *commitment,
+	revocations shachain.Store,
+	persisted *channeldb.OpenChannel) *ErrStateDiverged {
+
+	if height != persisted.NumUpdates {
+		return &ErrStateDiverged{
+			Field:     "commitment height",
+			InMemory:  fmt.Sprintf("%v", height),
+			Persisted: fmt.Sprintf("%v", persisted.NumUpdates),
+		}
+	}
+
+	// The commitment the channel was loaded with doesn't carry its
+	// transaction, so it can only be compared once we've transitioned to
+	// a new state.
+	if localCommit.txn != nil {
+		if persisted.OurCommitTx == nil {
+			return &ErrStateDiverged{
+				Field:     "commitment transaction",
+				InMemory:  localCommit.txn.TxHash().String(),
+				Persisted: "none",
+			}
+		}
+
+		memHash := localCommit.txn.TxHash()
+		diskHash := persisted.OurCommitTx.TxHash()
+		if memHash != diskHash {
+			return &ErrStateDiverged{
+				Field:     "commitment transaction",
+				InMemory:  memHash.String(),
+				Persisted: diskHash.String(),
+			}
+		}
+
+		if !bytes.Equal(localCommit.sig, persisted.OurCommitSig) {
+			return &ErrStateDiverged{
+				Field:    "commitment signature",
+				InMemory: fmt.Sprintf("%x", localCommit.sig),
+				Persisted: fmt.Sprintf("%x",
+					persisted.OurCommitSig),
+			}
+		}
+	}
+
+	var memStore, diskStore bytes.Buffer
+	if err := revocations.Encode(&memStore); err != nil {
+		return &ErrStateDiverged{
+			Field:    "revocation store",
+			InMemory: fmt.Sprintf("unencodable: %v", err),
+		}
+	}
+	if err := persisted.RevocationStore.Encode(&diskStore); err != nil {
+		return &ErrStateDiverged{
+			Field:     "revocation store",
+			Persisted: fmt.Sprintf("unencodable: %v", err),
+		}
+	}
+	if !bytes.Equal(memStore.Bytes(), diskStore.Bytes()) {
+		return &ErrStateDiverged{
+			Field:     "revocation store",
+			InMemory:  fmt.Sprintf("%x", memStore.Bytes()),
+			Persisted: fmt.Sprintf("%x", diskStore.Bytes()),
+		}
+	}
+
+	return nil
+}
+
+// ChanSyncMsg returns the ChannelReestablish message which is sent to the
+// remote party upon reconnecting, describing our view of both commitment
+// chains along with the latest revocation we've received from the remote
+// party.
+func (lc *LightningChannel) ChanSyncMsg() (*lnwire.ChannelReestablish, error) {
+	lc.RLock()
+	defer lc.RUnlock()
+
+	msg := &lnwire.ChannelReestablish{
+		ChannelPoint:           *lc.channelState.ChanID,
+		NextLocalCommitHeight:  lc.currentHeight + 1,
+		RemoteCommitTailHeight: lc.remoteCommitChain.tail().height,
+	}
+
+	// Each revocation we've received from the remote party revoked one of
+	// its commitments, starting from its first, so the latest is that of
+	// the commitment just below the tail of its chain.
+	if msg.RemoteCommitTailHeight > 0 {
+		store := lc.channelState.RevocationStore
+		secret, err := store.LookUp(msg.RemoteCommitTailHeight - 1)
+		if err != nil {
+			return nil, err
+		}
+		copy(msg.LastRemoteCommitSecret[:], secret[:])
+	}
+
+	return msg, nil
+}
+
+// ChanSyncRetransmission describes the messages which never reached the
+// remote party before we were disconnected, as determined by
+// ProcessChanSyncMsg. They must be retransmitted, in the order they were
+// originally sent, before the channel can resume.
+type ChanSyncRetransmission struct {
+	// RevocationHeight, if non-zero, is the height of our local
+	// commitment, as returned by CommitHeights, the revocation of our
+	// prior commitment having never reached the remote party.
+	RevocationHeight uint64
+
+	// CommitHeight, if non-zero, is the height of the remote party's
+	// commitment we've signed, our signature having never reached the
+	// remote party. The signature must be retransmitted along with the
+	// updates it covers.
+	CommitHeight uint64
+}
+
+// ProcessChanSyncMsg compares the remote party's view of the channel, as sent
+// within its ChannelReestablish message, against our own. If the remote party
+// proves that we've revoked commitments beyond our latest state, then we've
+// lost our state for the channel, so the channel is frozen for good and
+// ErrCommitSyncDataLoss is returned. If instead the remote party lags behind
+// the state it has committed to, then ErrCommitSyncRemoteDataLoss is
+// returned. Otherwise, the returned ChanSyncRetransmission describes the
+// messages we must retransmit for the remote party to catch up, if any.
+func (lc *LightningChannel) ProcessChanSyncMsg(
+	msg *lnwire.ChannelReestablish) (*ChanSyncRetransmission, error) {
+
+	lc.Lock()
+	defer lc.Unlock()
+
+	// The remote party can only prove it received a revocation from us by
+	// sending us its preimage, which we check against our own producer.
+	// As the producer is derived from our seed rather than our channel
+	// state, this holds even if our state is outdated.
+	if msg.RemoteCommitTailHeight > 0 {
+		producer := lc.channelState.RevocationProducer
+		secret, err := producer.AtIndex(msg.RemoteCommitTailHeight - 1)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(secret[:], msg.LastRemoteCommitSecret[:]) {
+			return nil, ErrInvalidLastCommitSecret
+		}
+	}
+
+	retransmission := &ChanSyncRetransmission{}
+
+	// We've revoked each of our commitments below our current height, so
+	// that's the number of revocations the remote party should hold.
+	switch {
+	case msg.RemoteCommitTailHeight > lc.currentHeight:
+		walletLog.Errorf("ChannelPoint(%v): remote party holds %v "+
+			"revocations, but we're at height %v, our state has "+
+			"been lost", lc.channelState.ChanID,
+			msg.RemoteCommitTailHeight, lc.currentHeight)
+
+		lc.dataLoss = true
+		return nil, ErrCommitSyncDataLoss
+
+	// A single missing revocation may simply not have reached the remote
+	// party before we disconnected, but any more have been lost.
+	case msg.RemoteCommitTailHeight+1 < lc.currentHeight:
+		return nil, ErrCommitSyncRemoteDataLoss
+
+	case msg.RemoteCommitTailHeight < lc.currentHeight:
+		retransmission.RevocationHeight = lc.localCommitChain.tip().height
+	}
+
+	// Likewise, the remote party's current commitment must be at least as
+	// high as the lowest of its commitments it hasn't revoked, and no
+	// higher than the latest we've signed. If it's just below the latest
+	// we've signed, then our signature never reached it.
+	remoteHeight := msg.NextLocalCommitHeight - 1
+	remoteTip := lc.remoteCommitChain.tip().height
+	switch {
+	case remoteHeight < lc.remoteCommitChain.tail().height:
+		return nil, ErrCommitSyncRemoteDataLoss
+
+	case remoteHeight > remoteTip:
+		return nil, ErrCannotSyncCommitChains
+
+	case remoteHeight < remoteTip:
+		retransmission.CommitHeight = remoteTip
+	}
+
+	if retransmission.RevocationHeight != 0 ||
+		retransmission.CommitHeight != 0 {
+
+		walletLog.Infof("ChannelPoint(%v): remote party is missing "+
+			"revocation_height=%v, commit_height=%v",
+			lc.channelState.ChanID,
+			retransmission.RevocationHeight,
+			retransmission.CommitHeight)
+	}
+
+	return retransmission, nil
+}
+
+// ReplayUpdates re-applies the passed updates, which we sent the remote party
+// before the channel was reloaded, but which were lost along with the
+// commitment covering them, as our signature never reached the remote party.
+// The updates re-applied are returned, as they must be retransmitted. As
+// HTLCs are re-indexed when the channel is reloaded, the HTLCs settled or
+// failed are identified by their payment hashes, and the indexes within the
+// returned updates are refreshed. An update which can no longer be applied,
+// such as the settle of an HTLC which has been settled since, is skipped.
+func (lc *LightningChannel) ReplayUpdates(
+	updates []*channeldb.PendingUpdate) []*channeldb.PendingUpdate {
+
+	replayed := make([]*channeldb.PendingUpdate, 0, len(updates))
+	for _, update := range updates {
+		var err error
+		switch msg := update.Msg.(type) {
+		case *lnwire.UpdateAddHTLC:
+			_, err = lc.AddHTLC(msg)
+
+		case *lnwire.UpdateFufillHTLC:
+			msg.ID, err = lc.SettleHTLC(msg.PaymentPreimage)
+
+		case *lnwire.UpdateFailHTLC:
+			msg.ID, err = lc.FailHTLC(update.PaymentHash)
+
+		case *lnwire.UpdateFee:
+			_, err = lc.UpdateFee(msg.Fee)
+
+		default:
+			err = fmt.Errorf("unknown update")
+		}
+		if err != nil {
+			walletLog.Warnf("ChannelPoint(%v): unable to replay %T "+
+				"for %x: %v", lc.channelState.ChanID,
+				update.Msg, update.PaymentHash[:], err)
+			continue
+		}
+
+		replayed = append(replayed, update)
+	}
+
+	return replayed
+}
+
+// DataLoss returns true if the remote party has proven that we've lost our
+// state for the channel.
+func (lc *LightningChannel) DataLoss() bool {
+	lc.RLock()
+	defer lc.RUnlock()
+
+	return lc.dataLoss
+}
+
+// Divergence returns the divergence which froze the channel, or nil if the
+// channel isn't frozen.
+func (lc *LightningChannel) Divergence() *ErrStateDiverged {
+	lc.RLock()
+	defer lc.RUnlock()
+
+	return lc.divergence
+}
+
+// AckDivergence unfreezes a channel frozen due to the divergence of its
+// in-memory and persisted state, once the operator has investigated it. If
+// the channel still diverges when next verified, it's frozen once more. As a
+// restart of the daemon reloads the channel's in-memory state from disk, it's
+// typically the safer course of action.
+func (lc *LightningChannel) AckDivergence() {
+	lc.Lock()
+	defer lc.Unlock()
+
+	lc.divergence = nil
+}
+
+// SetOutgoingFrozen freezes or thaws the channel. While frozen, no new HTLCs
+// may be offered over the channel, while those offered by the remote party,
+// along with the settles and cancels of existing HTLCs, are still accepted,
+// so the channel may be drained ahead of its closure. The flag is persisted,
+// so the channel remains frozen across restarts until thawed.
+func (lc *LightningChannel) SetOutgoingFrozen(frozen bool) error {
+	lc.Lock()
+	defer lc.Unlock()
+
+	return lc.channelState.SetOutgoingFrozen(frozen)
+}
+
+// OutgoingFrozen returns true if the channel has been frozen, preventing new
+// HTLCs from being offered over it.
+func (lc *LightningChannel) OutgoingFrozen() bool {
+	lc.RLock()
+	defer lc.RUnlock()
+
+	return lc.channelState.OutgoingFrozen
+}
+
 // StateSnapshot returns a snapshot of the current fully committed state within
 // the channel.
 func (lc *LightningChannel) StateSnapshot() *channeldb.ChannelSnapshot {
@@ -2422,13 +4516,83 @@ func CreateCommitTx(fundingOutput *wire.TxIn, selfKey, theirKey *btcec.PublicKey
 	return commitTx, nil
 }
 
+// addCommitAnchors adds an anchor output to the passed commitment
+// transaction for each party with something at stake within it, keyed by
+// their respective funding keys, so each party is able to bump the fee of
+// either party's commitment transaction. A party whose balance is trimmed as
+// dust has nothing to protect by bumping the fee, so it's given no anchor,
+// unless the commitment transaction carries HTLC outputs, which are at stake
+// for both parties.
+func addCommitAnchors(commitTx *wire.MsgTx, ourFundingKey,
+	theirFundingKey *btcec.PublicKey, ourBalance, theirBalance,
+	dustLimit btcutil.Amount, hasHtlcs bool) error {
+
+	anchors := []struct {
+		fundingKey *btcec.PublicKey
+		balance    btcutil.Amount
+	}{
+		{ourFundingKey, ourBalance},
+		{theirFundingKey, theirBalance},
+	}
+	for _, anchor := range anchors {
+		if anchor.balance < dustLimit && !hasHtlcs {
+			continue
+		}
+
+		witnessScript, err := anchorScript(anchor.fundingKey)
+		if err != nil {
+			return err
+		}
+		pkScript, err := witnessScriptHash(witnessScript)
+		if err != nil {
+			return err
+		}
+
+		commitTx.AddTxOut(wire.NewTxOut(int64(AnchorSize), pkScript))
+	}
+
+	return nil
+}
+
+// commitmentBuilder returns a builder for the commitment transactions of
+// either the remote party, or ourselves, spending the passed funding input of
+// the passed capacity.
+//
+// NOTE: This method MUST be called with the channel's mutex held.
+func (lc *LightningChannel) commitmentBuilder(remoteChain bool,
+	fundingTxIn *wire.TxIn, capacity btcutil.Amount) *CommitmentBuilder {
+
+	state := lc.channelState
+	params := &CommitmentParams{
+		Capacity:               capacity,
+		FundingTxIn:            fundingTxIn,
+		OwnerKey:               state.OurCommitKey,
+		CounterpartyKey:        state.TheirCommitKey,
+		CsvDelay:               state.LocalCsvDelay,
+		DustLimit:              state.OurDustLimit,
+		StateHintObsfucator:    state.StateHintObsfucator,
+		Anchors:                state.CommitFormat == channeldb.AnchorCommitment,
+		OwnerFundingKey:        state.OurMultiSigKey,
+		CounterpartyFundingKey: state.TheirMultiSigKey,
+	}
+	if remoteChain {
+		params.OwnerKey = state.TheirCommitKey
+		params.CounterpartyKey = state.OurCommitKey
+		params.CsvDelay = state.RemoteCsvDelay
+		params.DustLimit = state.TheirDustLimit
+		params.OwnerFundingKey = state.TheirMultiSigKey
+		params.CounterpartyFundingKey = state.OurMultiSigKey
+	}
+
+	return NewCommitmentBuilder(params)
+}
+
 // CreateCooperativeCloseTx creates a transaction which if signed by both
 // parties, then broadcast cooperatively closes an active channel. The creation
 // of the closure transaction is modified by a boolean indicating if the party
-// constructing the channel is the initiator of the closure. Currently it is
-// expected that the initiator pays the transaction fees for the closing
-// transaction in full.
-func CreateCooperativeCloseTx(fundingTxIn *wire.TxIn,
+// constructing the transaction is the one which proposed this version of it.
+// The proposer of each version pays the transaction fees in full.
+func CreateCooperativeCloseTx(fundingTxIn *wire.TxIn, fee btcutil.Amount,
 	ourBalance, theirBalance btcutil.Amount,
 	ourDeliveryScript, theirDeliveryScript []byte,
 	initiator bool) *wire.MsgTx {
@@ -2440,13 +4604,12 @@ func CreateCooperativeCloseTx(fundingTxIn *wire.TxIn,
 	closeTx := wire.NewMsgTx(2)
 	closeTx.AddTxIn(fundingTxIn)
 
-	// The initiator of a cooperative closure pays the fee in entirety.
-	// Determine if we're the initiator so we can compute fees properly.
+	// The proposer of a cooperative closure pays the fee in entirety.
+	// Determine if we're the proposer so we can compute fees properly.
 	if initiator {
-		// TODO(roasbeef): take sat/byte here instead of properly calc
-		ourBalance -= 5000
+		ourBalance -= fee
 	} else {
-		theirBalance -= 5000
+		theirBalance -= fee
 	}
 
 	// TODO(roasbeef): dust check...
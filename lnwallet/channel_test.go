@@ -699,40 +699,383 @@ func TestCooperativeChannelClosure(t *testing.T) {
 	}
 	defer cleanUp()
 
-	// First we test the channel initiator requesting a cooperative close.
-	sig, txid, err := aliceChannel.InitCooperativeClose()
+	// First we test the channel initiator proposing a cooperative close.
+	// Bob signs Alice's version, which Alice is then able to complete.
+	sig, aliceTxid, err := aliceChannel.InitCooperativeClose(
+		DefaultCoopCloseFee,
+	)
 	if err != nil {
 		t.Fatalf("unable to initiate alice cooperative close: %v", err)
 	}
 	finalSig := append(sig, byte(txscript.SigHashAll))
-	closeTx, err := bobChannel.CompleteCooperativeClose(finalSig)
+	bobSig, txid, err := bobChannel.SignRemoteCooperativeClose(finalSig,
+		DefaultCoopCloseFee)
+	if err != nil {
+		t.Fatalf("unable to sign alice cooperative close: %v", err)
+	}
+	if !txid.IsEqual(aliceTxid) {
+		t.Fatalf("alice's transactions don't match: %v vs %v",
+			txid, aliceTxid)
+	}
+	finalSig = append(bobSig, byte(txscript.SigHashAll))
+	aliceCloseTx, err := aliceChannel.CompleteCooperativeClose(finalSig,
+		DefaultCoopCloseFee)
 	if err != nil {
 		t.Fatalf("unable to complete alice cooperative close: %v", err)
 	}
-	bobCloseSha := closeTx.TxHash()
-	if !bobCloseSha.IsEqual(txid) {
-		t.Fatalf("alice's transactions doesn't match: %x vs %x",
-			bobCloseSha[:], txid[:])
+	aliceCloseSha := aliceCloseTx.TxHash()
+	if !aliceCloseSha.IsEqual(aliceTxid) {
+		t.Fatalf("alice's transactions don't match: %v vs %v",
+			aliceCloseSha, aliceTxid)
 	}
 
-	aliceChannel.status = channelOpen
-	bobChannel.status = channelOpen
-
-	// Next we test the channel recipient requesting a cooperative closure.
-	// First we test the channel initiator requesting a cooperative close.
-	sig, txid, err = bobChannel.InitCooperativeClose()
+	// Next, Bob proposes his own version while Alice's is outstanding,
+	// which Alice signs in turn.
+	sig, bobTxid, err := bobChannel.InitCooperativeClose(
+		DefaultCoopCloseFee,
+	)
 	if err != nil {
 		t.Fatalf("unable to initiate bob cooperative close: %v", err)
 	}
 	finalSig = append(sig, byte(txscript.SigHashAll))
-	closeTx, err = aliceChannel.CompleteCooperativeClose(finalSig)
+	aliceSig, _, err := aliceChannel.SignRemoteCooperativeClose(finalSig,
+		DefaultCoopCloseFee)
+	if err != nil {
+		t.Fatalf("unable to sign bob cooperative close: %v", err)
+	}
+	finalSig = append(aliceSig, byte(txscript.SigHashAll))
+	bobCloseTx, err := bobChannel.CompleteCooperativeClose(finalSig,
+		DefaultCoopCloseFee)
+	if err != nil {
+		t.Fatalf("unable to complete bob cooperative close: %v", err)
+	}
+	bobCloseSha := bobCloseTx.TxHash()
+	if !bobCloseSha.IsEqual(bobTxid) {
+		t.Fatalf("bob's closure transactions don't match: %v vs %v",
+			bobCloseSha, bobTxid)
+	}
+
+	// As each version is paid for by its proposer, the two versions
+	// should differ.
+	if aliceCloseSha.IsEqual(&bobCloseSha) {
+		t.Fatalf("alice and bob's versions shouldn't match")
+	}
+
+	// Neither party may propose a second initial version.
+	_, _, err = aliceChannel.InitCooperativeClose(DefaultCoopCloseFee)
+	if err != ErrChanClosing {
+		t.Fatalf("expected ErrChanClosing, got %v", err)
+	}
+}
+
+// TestCooperativeCloseFeeAtRate tests that the fee returned for a cooperative
+// closure at a given fee rate achieves that rate once the closing transaction
+// is fully signed.
+func TestCooperativeCloseFeeAtRate(t *testing.T) {
+	aliceChannel, bobChannel, cleanUp, err := createTestChannels(1)
+	if err != nil {
+		t.Fatalf("unable to create test channels: %v", err)
+	}
+	defer cleanUp()
+
+	const feeRate = btcutil.Amount(10)
+	fee, err := aliceChannel.CooperativeCloseFeeAtRate(feeRate)
+	if err != nil {
+		t.Fatalf("unable to compute closure fee: %v", err)
+	}
+
+	sig, _, err := aliceChannel.InitCooperativeClose(fee)
+	if err != nil {
+		t.Fatalf("unable to initiate alice cooperative close: %v", err)
+	}
+	finalSig := append(sig, byte(txscript.SigHashAll))
+	bobSig, _, err := bobChannel.SignRemoteCooperativeClose(finalSig, fee)
+	if err != nil {
+		t.Fatalf("unable to sign alice cooperative close: %v", err)
+	}
+	finalSig = append(bobSig, byte(txscript.SigHashAll))
+	closeTx, err := aliceChannel.CompleteCooperativeClose(finalSig, fee)
+	if err != nil {
+		t.Fatalf("unable to complete alice cooperative close: %v", err)
+	}
+
+	// The estimate assumes signatures of the largest size, so it may
+	// only exceed the fee required by the signed transaction by a byte.
+	weight := blockchain.GetTransactionWeight(btcutil.NewTx(closeTx))
+	vsize := (weight + blockchain.WitnessScaleFactor - 1) /
+		blockchain.WitnessScaleFactor
+	minFee := feeRate * btcutil.Amount(vsize)
+	if fee < minFee || fee > minFee+feeRate {
+		t.Fatalf("expected fee of %v for vsize %v, got %v", minFee,
+			vsize, fee)
+	}
+}
+
+// TestCooperativeCloseFeeBump tests that either party is able to replace a
+// stuck version of its own cooperative closure transaction with one paying a
+// higher fee, and that the fee of each replacement is paid from the output of
+// its proposer.
+func TestCooperativeCloseFeeBump(t *testing.T) {
+	aliceChannel, bobChannel, cleanUp, err := createTestChannels(1)
+	if err != nil {
+		t.Fatalf("unable to create test channels: %v", err)
+	}
+	defer cleanUp()
+
+	// The fee of a closure which hasn't begun can't be bumped.
+	if _, _, err := aliceChannel.BumpCooperativeClose(10000); err == nil {
+		t.Fatalf("fee bump before closure should have been rejected")
+	}
+
+	// Bob, who isn't the channel initiator, proposes the closure, which
+	// Alice signs.
+	sig, _, err := bobChannel.InitCooperativeClose(DefaultCoopCloseFee)
+	if err != nil {
+		t.Fatalf("unable to initiate bob cooperative close: %v", err)
+	}
+	finalSig := append(sig, byte(txscript.SigHashAll))
+	aliceSig, _, err := aliceChannel.SignRemoteCooperativeClose(finalSig,
+		DefaultCoopCloseFee)
+	if err != nil {
+		t.Fatalf("unable to sign bob cooperative close: %v", err)
+	}
+	finalSig = append(aliceSig, byte(txscript.SigHashAll))
+	origTx, err := bobChannel.CompleteCooperativeClose(finalSig,
+		DefaultCoopCloseFee)
 	if err != nil {
 		t.Fatalf("unable to complete bob cooperative close: %v", err)
 	}
-	aliceCloseSha := closeTx.TxHash()
-	if !aliceCloseSha.IsEqual(txid) {
-		t.Fatalf("bob's closure transactions don't match: %x vs %x",
-			aliceCloseSha[:], txid[:])
+
+	// Alice hasn't proposed a version of her own, so she has nothing to
+	// bump.
+	if _, _, err := aliceChannel.BumpCooperativeClose(10000); err == nil {
+		t.Fatalf("alice's fee bump should have been rejected")
+	}
+
+	// A replacement which doesn't increase the fee should be rejected.
+	if _, _, err := bobChannel.BumpCooperativeClose(DefaultCoopCloseFee); err != ErrCloseFeeTooLow {
+		t.Fatalf("expected ErrCloseFeeTooLow, got %v", err)
+	}
+
+	// Bob now bumps the fee, and Alice should be able to sign the
+	// replacement transaction.
+	const bumpedFee = DefaultCoopCloseFee * 2
+	sig, txid, err := bobChannel.BumpCooperativeClose(bumpedFee)
+	if err != nil {
+		t.Fatalf("unable to bump fee: %v", err)
+	}
+	finalSig = append(sig, byte(txscript.SigHashAll))
+
+	// Alice shouldn't accept the signature at a fee other than the one
+	// Bob signed at.
+	if _, _, err := aliceChannel.SignRemoteCooperativeClose(finalSig,
+		bumpedFee+1); err == nil {
+		t.Fatalf("bump with mismatched fee should have been rejected")
+	}
+
+	aliceSig, _, err = aliceChannel.SignRemoteCooperativeClose(finalSig,
+		bumpedFee)
+	if err != nil {
+		t.Fatalf("unable to sign fee bump: %v", err)
+	}
+	finalSig = append(aliceSig, byte(txscript.SigHashAll))
+
+	// Bob can only complete the latest version of his transaction.
+	if _, err := bobChannel.CompleteCooperativeClose(finalSig,
+		DefaultCoopCloseFee); err == nil {
+		t.Fatalf("replaced version shouldn't be completed")
+	}
+	bumpTx, err := bobChannel.CompleteCooperativeClose(finalSig, bumpedFee)
+	if err != nil {
+		t.Fatalf("unable to complete fee bump: %v", err)
+	}
+	bumpSha := bumpTx.TxHash()
+	if !bumpSha.IsEqual(txid) {
+		t.Fatalf("replacement transactions don't match: %v vs %v",
+			bumpSha, txid)
+	}
+
+	// The replacement should spend the same funding output, with Bob's
+	// output alone reduced by the additional fee.
+	bobScript := bobChannel.channelState.OurDeliveryScript
+	outputs := func(tx *wire.MsgTx) (int64, int64) {
+		var bob, total int64
+		for _, out := range tx.TxOut {
+			if bytes.Equal(out.PkScript, bobScript) {
+				bob += out.Value
+			}
+			total += out.Value
+		}
+		return bob, total
+	}
+	origBob, origTotal := outputs(origTx)
+	bumpBob, bumpTotal := outputs(bumpTx)
+	if origBob-bumpBob != int64(bumpedFee-DefaultCoopCloseFee) ||
+		origTotal-bumpTotal != int64(bumpedFee-DefaultCoopCloseFee) {
+		t.Fatalf("bob should pay %v more in fees, pays %v of %v",
+			bumpedFee-DefaultCoopCloseFee, origBob-bumpBob,
+			origTotal-bumpTotal)
+	}
+	if bumpTx.TxIn[0].PreviousOutPoint != origTx.TxIn[0].PreviousOutPoint {
+		t.Fatalf("replacement doesn't spend the funding output")
+	}
+
+	// Finally, the replaced fee is now the floor for further bumps.
+	if _, _, err := aliceChannel.SignRemoteCooperativeClose(finalSig,
+		bumpedFee); err != ErrCloseFeeTooLow {
+		t.Fatalf("expected ErrCloseFeeTooLow, got %v", err)
+	}
+}
+
+// TestCooperativeCloseRestart tests that the versions of the cooperative
+// closure transaction signed by either party survive a restart, so the
+// closure can be resumed, with our own version bumped from where it left off.
+func TestCooperativeCloseRestart(t *testing.T) {
+	aliceChannel, bobChannel, cleanUp, err := createTestChannels(1)
+	if err != nil {
+		t.Fatalf("unable to create test channels: %v", err)
+	}
+	defer cleanUp()
+
+	// Bob proposes the closure, which Alice signs.
+	sig, bobTxid, err := bobChannel.InitCooperativeClose(DefaultCoopCloseFee)
+	if err != nil {
+		t.Fatalf("unable to initiate bob cooperative close: %v", err)
+	}
+	finalSig := append(sig, byte(txscript.SigHashAll))
+	_, aliceTxid, err := aliceChannel.SignRemoteCooperativeClose(finalSig,
+		DefaultCoopCloseFee)
+	if err != nil {
+		t.Fatalf("unable to sign bob cooperative close: %v", err)
+	}
+
+	// Both parties now restart, reloading the channel from disk.
+	notifier := aliceChannel.channelEvents
+	aliceChannel, err = NewLightningChannel(aliceChannel.signer, notifier,
+		aliceChannel.channelState)
+	if err != nil {
+		t.Fatalf("unable to reload alice's channel: %v", err)
+	}
+	bobChannel, err = NewLightningChannel(bobChannel.signer, notifier,
+		bobChannel.channelState)
+	if err != nil {
+		t.Fatalf("unable to reload bob's channel: %v", err)
+	}
+
+	// Each should recall the version it signed.
+	aliceVersions := aliceChannel.CooperativeCloseVersions()
+	if len(aliceVersions) != 1 || aliceVersions[0].Txid != *aliceTxid ||
+		aliceVersions[0].Fee != DefaultCoopCloseFee ||
+		aliceVersions[0].LocalPays {
+
+		t.Fatalf("alice's close versions weren't restored: %v",
+			aliceVersions)
+	}
+	bobVersions := bobChannel.CooperativeCloseVersions()
+	if len(bobVersions) != 1 || bobVersions[0].Txid != *bobTxid ||
+		bobVersions[0].Fee != DefaultCoopCloseFee ||
+		!bobVersions[0].LocalPays {
+
+		t.Fatalf("bob's close versions weren't restored: %v",
+			bobVersions)
+	}
+
+	// Bob's closure is still in progress, so he can't start another, and
+	// the fee of his version is the floor for his bumps, just as it is
+	// for Alice.
+	if fee := bobChannel.CooperativeCloseFee(); fee != DefaultCoopCloseFee {
+		t.Fatalf("expected bob's close fee of %v, got %v",
+			DefaultCoopCloseFee, fee)
+	}
+	if _, _, err := bobChannel.InitCooperativeClose(DefaultCoopCloseFee); err != ErrChanClosing {
+		t.Fatalf("expected ErrChanClosing, got %v", err)
+	}
+	if _, _, err := bobChannel.BumpCooperativeClose(DefaultCoopCloseFee); err != ErrCloseFeeTooLow {
+		t.Fatalf("expected ErrCloseFeeTooLow, got %v", err)
+	}
+	if _, _, err := aliceChannel.SignRemoteCooperativeClose(finalSig,
+		DefaultCoopCloseFee); err != ErrCloseFeeTooLow {
+		t.Fatalf("expected ErrCloseFeeTooLow, got %v", err)
+	}
+
+	// Bob bumps the fee of his version, which Alice signs, allowing him to
+	// complete the replacement.
+	const bumpedFee = DefaultCoopCloseFee * 2
+	sig, _, err = bobChannel.BumpCooperativeClose(bumpedFee)
+	if err != nil {
+		t.Fatalf("unable to bump fee: %v", err)
+	}
+	finalSig = append(sig, byte(txscript.SigHashAll))
+	aliceSig, _, err := aliceChannel.SignRemoteCooperativeClose(finalSig,
+		bumpedFee)
+	if err != nil {
+		t.Fatalf("unable to sign fee bump: %v", err)
+	}
+	finalSig = append(aliceSig, byte(txscript.SigHashAll))
+	if _, err := bobChannel.CompleteCooperativeClose(finalSig,
+		bumpedFee); err != nil {
+		t.Fatalf("unable to complete fee bump: %v", err)
+	}
+
+	if n := len(bobChannel.CooperativeCloseVersions()); n != 2 {
+		t.Fatalf("expected bob to have signed 2 versions, got %v", n)
+	}
+}
+
+// TestDeviceFundingKey tests that a channel whose funding key is held by a
+// signing device rejects all commitment updates, but may still be
+// cooperatively closed.
+func TestDeviceFundingKey(t *testing.T) {
+	aliceChannel, bobChannel, cleanUp, err := createTestChannels(1)
+	if err != nil {
+		t.Fatalf("unable to create test channels: %v", err)
+	}
+	defer cleanUp()
+
+	// Record Alice's funding key as a device key, then reload her channel
+	// so the key is detected.
+	aliceState := aliceChannel.channelState
+	err = aliceState.Db.AddDeviceKey(aliceState.OurMultiSigKey,
+		&channeldb.DeviceKey{DerivationPath: []uint32{0}})
+	if err != nil {
+		t.Fatalf("unable to add device key: %v", err)
+	}
+	aliceChannel, err = NewLightningChannel(aliceChannel.signer,
+		&mockNotfier{}, aliceState)
+	if err != nil {
+		t.Fatalf("unable to reload alice's channel: %v", err)
+	}
+
+	htlc := &lnwire.UpdateAddHTLC{
+		PaymentHash: sha256.Sum256(bytes.Repeat([]byte{1}, 32)),
+		Amount:      btcutil.Amount(1e6),
+		Expiry:      uint32(5),
+	}
+	if _, err := aliceChannel.AddHTLC(htlc); err != ErrDeviceFundingKey {
+		t.Fatalf("expected ErrDeviceFundingKey, got: %v", err)
+	}
+	if _, err := aliceChannel.ReceiveHTLC(htlc); err != ErrDeviceFundingKey {
+		t.Fatalf("expected ErrDeviceFundingKey, got: %v", err)
+	}
+	if _, err := aliceChannel.SignNextCommitment(); err != ErrDeviceFundingKey {
+		t.Fatalf("expected ErrDeviceFundingKey, got: %v", err)
+	}
+
+	// The closure transaction is signed with the funding key as well,
+	// which the device is asked to sign.
+	sig, txid, err := aliceChannel.InitCooperativeClose(DefaultCoopCloseFee)
+	if err != nil {
+		t.Fatalf("unable to initiate cooperative close: %v", err)
+	}
+	finalSig := append(sig, byte(txscript.SigHashAll))
+	_, closeTxid, err := bobChannel.SignRemoteCooperativeClose(finalSig,
+		DefaultCoopCloseFee)
+	if err != nil {
+		t.Fatalf("unable to sign cooperative close: %v", err)
+	}
+	if !closeTxid.IsEqual(txid) {
+		t.Fatalf("closure transactions don't match: %v vs %v",
+			closeTxid, txid)
 	}
 }
 
@@ -763,83 +1106,1124 @@ func TestCheckHTLCNumberConstraint(t *testing.T) {
 		return nil
 	}
 
-	// Create a test channel which will be used for the duration of this
-	// unittest. The channel will be funded evenly with Alice having 5 BTC,
-	// and Bob having 5 BTC.
-	aliceChannel, bobChannel, cleanUp, err := createTestChannels(1)
+	// Create a test channel which will be used for the duration of this
+	// unittest. The channel will be funded evenly with Alice having 5 BTC,
+	// and Bob having 5 BTC.
+	aliceChannel, bobChannel, cleanUp, err := createTestChannels(1)
+	if err != nil {
+		t.Fatalf("unable to create test channels: %v", err)
+	}
+	defer cleanUp()
+
+	// Add max available number of HTLCs.
+	for i := 0; i < MaxHTLCNumber; i++ {
+		htlc := createHTLC(i)
+		if _, err := aliceChannel.AddHTLC(htlc); err != nil {
+			t.Fatalf("alice unable to add htlc: %v", err)
+		}
+		if _, err := bobChannel.ReceiveHTLC(htlc); err != nil {
+			t.Fatalf("bob unable to receive htlc: %v", err)
+		}
+	}
+
+	// Next addition should cause HTLC max number validation error.
+	htlc := createHTLC(0)
+	if _, err := aliceChannel.AddHTLC(htlc); err != nil {
+		if err := checkError(err); err != nil {
+			t.Fatal(err)
+		}
+	} else {
+		t.Fatal("Error was not received")
+	}
+	if _, err := bobChannel.AddHTLC(htlc); err != nil {
+		if err := checkError(err); err != nil {
+			t.Fatal(err)
+		}
+	} else {
+		t.Fatal("Error was not received")
+	}
+	if _, err := aliceChannel.ReceiveHTLC(htlc); err != nil {
+		if err := checkError(err); err != nil {
+			t.Fatal(err)
+		}
+	} else {
+		t.Fatal("Error was not received")
+	}
+	if _, err := bobChannel.ReceiveHTLC(htlc); err != nil {
+		if err := checkError(err); err != nil {
+			t.Fatal(err)
+		}
+	} else {
+		t.Fatal("Error was not received")
+	}
+
+	// Manually add HTLC to check SignNextCommitment validation error.
+	aliceChannel.localUpdateLog.appendUpdate(
+		&PaymentDescriptor{
+			Index: aliceChannel.localUpdateLog.logIndex,
+		},
+	)
+
+	_, err = aliceChannel.SignNextCommitment()
+	if err := checkError(err); err != nil {
+		t.Fatal(err)
+	}
+
+	// Manually add HTLC to check ReceiveNewCommitment validation error.
+	bobChannel.remoteUpdateLog.appendUpdate(
+		&PaymentDescriptor{
+			Index: bobChannel.remoteUpdateLog.logIndex,
+		},
+	)
+
+	// And on this stage we should receive the weight error.
+	someSig := []byte("somesig")
+	err = bobChannel.ReceiveNewCommitment(someSig)
+	if err := checkError(err); err != nil {
+		t.Fatal(err)
+	}
+
+}
+
+// TestHTLCPolicy checks that HTLCs below the minimum HTLC amount are
+// rejected, and that the total value of pending dust HTLCs is capped by the
+// maximum dust exposure.
+func TestHTLCPolicy(t *testing.T) {
+	createHTLC := func(i int, amt btcutil.Amount) *lnwire.UpdateAddHTLC {
+		preimage := bytes.Repeat([]byte{byte(i)}, 32)
+		paymentHash := sha256.Sum256(preimage)
+		return &lnwire.UpdateAddHTLC{
+			PaymentHash: paymentHash,
+			Amount:      amt,
+			Expiry:      uint32(5),
+		}
+	}
+
+	aliceChannel, bobChannel, cleanUp, err := createTestChannels(1)
+	if err != nil {
+		t.Fatalf("unable to create test channels: %v", err)
+	}
+	defer cleanUp()
+
+	// The largest dust limit of the two commitments is 800 satoshis, so
+	// we'll allow at most two dust HTLCs of 300 satoshis.
+	aliceChannel.SetHTLCPolicy(100, 700, 0)
+
+	if _, err := aliceChannel.AddHTLC(createHTLC(0, 50)); err != ErrBelowMinHTLC {
+		t.Fatalf("expected ErrBelowMinHTLC, got: %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		htlc := createHTLC(i, 300)
+		if _, err := aliceChannel.AddHTLC(htlc); err != nil {
+			t.Fatalf("alice unable to add htlc: %v", err)
+		}
+		if _, err := bobChannel.ReceiveHTLC(htlc); err != nil {
+			t.Fatalf("bob unable to receive htlc: %v", err)
+		}
+	}
+	if exposure := aliceChannel.DustExposure(); exposure != 600 {
+		t.Fatalf("expected dust exposure of 600, instead got %v",
+			exposure)
+	}
+
+	// A third dust HTLC would exceed the maximum dust exposure, however
+	// HTLCs above the dust limit aren't subject to the cap.
+	if _, err := aliceChannel.AddHTLC(createHTLC(2, 300)); err != ErrMaxDustExposure {
+		t.Fatalf("expected ErrMaxDustExposure, got: %v", err)
+	}
+	if err := aliceChannel.CheckHTLCPolicy(btcutil.Amount(1e6), [32]byte{}); err != nil {
+		t.Fatalf("non-dust htlc should be accepted: %v", err)
+	}
+
+	// Once one of the dust HTLCs is failed, there's once again room for
+	// another dust HTLC.
+	if err := forceStateTransition(aliceChannel, bobChannel); err != nil {
+		t.Fatalf("unable to complete state update: %v", err)
+	}
+	failHash := createHTLC(0, 300).PaymentHash
+	failIndex, err := bobChannel.FailHTLC(failHash)
+	if err != nil {
+		t.Fatalf("unable to fail htlc: %v", err)
+	}
+	if err := aliceChannel.ReceiveFailHTLC(failIndex); err != nil {
+		t.Fatalf("unable to receive fail htlc: %v", err)
+	}
+	if exposure := aliceChannel.DustExposure(); exposure != 300 {
+		t.Fatalf("expected dust exposure of 300, instead got %v",
+			exposure)
+	}
+	if err := aliceChannel.CheckHTLCPolicy(300, [32]byte{}); err != nil {
+		t.Fatalf("dust htlc should be accepted: %v", err)
+	}
+}
+
+// TestHashExposurePolicy checks that the total value of pending HTLCs sharing
+// a payment hash is capped by the maximum hash exposure, while the first HTLC
+// with a payment hash isn't subject to the cap.
+func TestHashExposurePolicy(t *testing.T) {
+	aliceChannel, bobChannel, cleanUp, err := createTestChannels(1)
+	if err != nil {
+		t.Fatalf("unable to create test channels: %v", err)
+	}
+	defer cleanUp()
+
+	bobChannel.SetHTLCPolicy(0, 0, 5000)
+
+	paymentHash := sha256.Sum256(bytes.Repeat([]byte{1}, 32))
+	createHTLC := func(amt btcutil.Amount) *lnwire.UpdateAddHTLC {
+		return &lnwire.UpdateAddHTLC{
+			PaymentHash: paymentHash,
+			Amount:      amt,
+			Expiry:      uint32(5),
+		}
+	}
+
+	// A single HTLC exceeding the cap should be accepted, as it isn't
+	// correlated with any others.
+	if err := bobChannel.CheckHTLCPolicy(8000, paymentHash); err != nil {
+		t.Fatalf("first htlc should be accepted: %v", err)
+	}
+
+	// Once an HTLC with the payment hash is pending, further HTLCs with
+	// the same hash may only be added up to the cap.
+	htlc := createHTLC(3000)
+	if _, err := aliceChannel.AddHTLC(htlc); err != nil {
+		t.Fatalf("alice unable to add htlc: %v", err)
+	}
+	if _, err := bobChannel.ReceiveHTLC(htlc); err != nil {
+		t.Fatalf("bob unable to receive htlc: %v", err)
+	}
+	if exposure := bobChannel.HashExposure(paymentHash); exposure != 3000 {
+		t.Fatalf("expected hash exposure of 3000, instead got %v",
+			exposure)
+	}
+	if err := bobChannel.CheckHTLCPolicy(2000, paymentHash); err != nil {
+		t.Fatalf("htlc within cap should be accepted: %v", err)
+	}
+	err = bobChannel.CheckHTLCPolicy(2001, paymentHash)
+	if err != ErrMaxHashExposure {
+		t.Fatalf("expected ErrMaxHashExposure, got: %v", err)
+	}
+
+	// HTLCs with other payment hashes aren't affected.
+	if err := bobChannel.CheckHTLCPolicy(8000, [32]byte{}); err != nil {
+		t.Fatalf("uncorrelated htlc should be accepted: %v", err)
+	}
+
+	// Bob should also refuse to add a correlated HTLC of his own which
+	// exceeds the cap.
+	if _, err := bobChannel.AddHTLC(createHTLC(2001)); err != ErrMaxHashExposure {
+		t.Fatalf("expected ErrMaxHashExposure, got: %v", err)
+	}
+
+	// A correlated HTLC offered by Alice which exceeds the cap must still
+	// be added to Bob's log to keep both logs in sync, but is rejected.
+	htlc = createHTLC(2001)
+	index, err := aliceChannel.AddHTLC(htlc)
+	if err != nil {
+		t.Fatalf("alice unable to add htlc: %v", err)
+	}
+	_, err = bobChannel.ReceiveHTLC(htlc)
+	rejection, ok := err.(*ErrHTLCRejected)
+	if !ok {
+		t.Fatalf("expected ErrHTLCRejected, got: %v", err)
+	}
+	if rejection.Reason != ErrMaxHashExposure {
+		t.Fatalf("expected ErrMaxHashExposure, got: %v",
+			rejection.Reason)
+	}
+	if rejection.Index != index {
+		t.Fatalf("expected htlc index %v, got %v", index,
+			rejection.Index)
+	}
+}
+
+// TestChanReserve checks that neither party may add an HTLC which would push
+// their balance below the channel reserve.
+func TestChanReserve(t *testing.T) {
+	aliceChannel, bobChannel, cleanUp, err := createTestChannels(1)
+	if err != nil {
+		t.Fatalf("unable to create test channels: %v", err)
+	}
+	defer cleanUp()
+
+	// Both parties start with a balance of 5 BTC, and must each maintain
+	// a reserve of 1 BTC.
+	const reserve = btcutil.Amount(1e8)
+	aliceChannel.SetChanReserve(reserve)
+	bobChannel.SetChanReserve(reserve)
+
+	createHTLC := func(i int, amt btcutil.Amount) *lnwire.UpdateAddHTLC {
+		preimage := bytes.Repeat([]byte{byte(i)}, 32)
+		return &lnwire.UpdateAddHTLC{
+			PaymentHash: sha256.Sum256(preimage),
+			Amount:      amt,
+			Expiry:      uint32(5),
+		}
+	}
+
+	// Alice should be able to spend her balance right down to the
+	// reserve.
+	htlc := createHTLC(0, 4e8)
+	if _, err := aliceChannel.AddHTLC(htlc); err != nil {
+		t.Fatalf("alice unable to add htlc: %v", err)
+	}
+	if _, err := bobChannel.ReceiveHTLC(htlc); err != nil {
+		t.Fatalf("bob unable to receive htlc: %v", err)
+	}
+
+	// Any further HTLC from Alice would violate the reserve, so she
+	// should refuse to add it.
+	htlc = createHTLC(1, 1)
+	if _, err := aliceChannel.AddHTLC(htlc); err != ErrBelowChanReserve {
+		t.Fatalf("expected ErrBelowChanReserve, got: %v", err)
+	}
+
+	// The pending HTLC doesn't affect Bob's balance, so he may still
+	// spend down to the reserve himself.
+	if err := forceStateTransition(aliceChannel, bobChannel); err != nil {
+		t.Fatalf("unable to complete state update: %v", err)
+	}
+	htlc = createHTLC(2, 4e8)
+	if _, err := bobChannel.AddHTLC(htlc); err != nil {
+		t.Fatalf("bob unable to add htlc: %v", err)
+	}
+	if _, err := aliceChannel.ReceiveHTLC(htlc); err != nil {
+		t.Fatalf("alice unable to receive htlc: %v", err)
+	}
+	if _, err := bobChannel.AddHTLC(createHTLC(3, 1)); err != ErrBelowChanReserve {
+		t.Fatalf("expected ErrBelowChanReserve, got: %v", err)
+	}
+
+	// Should Alice's view of the reserve differ from Bob's, then she may
+	// offer an HTLC which Bob considers to violate it. Bob must still add
+	// the HTLC to his log, keeping both logs in sync, so it can be failed
+	// back once locked in.
+	aliceChannel.SetChanReserve(0)
+	htlc = createHTLC(4, 1)
+	if _, err := aliceChannel.AddHTLC(htlc); err != nil {
+		t.Fatalf("alice unable to add htlc: %v", err)
+	}
+	index, err := bobChannel.ReceiveHTLC(htlc)
+	rejection, ok := err.(*ErrHTLCRejected)
+	if !ok || rejection.Reason != ErrBelowChanReserve ||
+		rejection.Index != index {
+
+		t.Fatalf("expected htlc to be rejected with "+
+			"ErrBelowChanReserve, got: %v", err)
+	}
+	if err := forceStateTransition(aliceChannel, bobChannel); err != nil {
+		t.Fatalf("unable to complete state update: %v", err)
+	}
+
+	failIndex, err := bobChannel.FailHTLC(htlc.PaymentHash)
+	if err != nil {
+		t.Fatalf("bob unable to fail htlc: %v", err)
+	}
+	if failIndex != index {
+		t.Fatalf("expected htlc %v to be failed, got %v", index,
+			failIndex)
+	}
+	if err := aliceChannel.ReceiveFailHTLC(index); err != nil {
+		t.Fatalf("alice unable to receive fail: %v", err)
+	}
+	if err := forceStateTransition(bobChannel, aliceChannel); err != nil {
+		t.Fatalf("unable to complete state update: %v", err)
+	}
+}
+
+// TestUpdateFee checks that the initiator of a channel is able to update the
+// commitment fee, with the difference between the fees settled with its
+// balance once both parties have committed to the new fee.
+func TestUpdateFee(t *testing.T) {
+	aliceChannel, bobChannel, cleanUp, err := createTestChannels(1)
+	if err != nil {
+		t.Fatalf("unable to create test channels: %v", err)
+	}
+	defer cleanUp()
+
+	// As Bob isn't the initiator of the channel, he may not update the
+	// fee, and Alice won't accept an update from him.
+	if _, err := bobChannel.UpdateFee(1e6); err != ErrNonInitiatorFeeUpdate {
+		t.Fatalf("expected ErrNonInitiatorFeeUpdate, got: %v", err)
+	}
+	if err := aliceChannel.ReceiveUpdateFee(1e6, nil, 0); err != ErrNonInitiatorFeeUpdate {
+		t.Fatalf("expected ErrNonInitiatorFeeUpdate, got: %v", err)
+	}
+
+	checkFee := func(fee, aliceBalance btcutil.Amount) {
+		if aliceChannel.CommitFee() != fee {
+			t.Fatalf("alice's commit fee should be %v, is %v", fee,
+				aliceChannel.CommitFee())
+		}
+		if bobChannel.CommitFee() != fee {
+			t.Fatalf("bob's commit fee should be %v, is %v", fee,
+				bobChannel.CommitFee())
+		}
+		if aliceChannel.channelState.CommitFee != fee {
+			t.Fatalf("alice's persisted commit fee should be %v, "+
+				"is %v", fee, aliceChannel.channelState.CommitFee)
+		}
+		if bobChannel.channelState.CommitFee != fee {
+			t.Fatalf("bob's persisted commit fee should be %v, "+
+				"is %v", fee, bobChannel.channelState.CommitFee)
+		}
+		if aliceChannel.channelState.OurBalance != aliceBalance {
+			t.Fatalf("alice's balance should be %v, is %v",
+				aliceBalance, aliceChannel.channelState.OurBalance)
+		}
+		if bobChannel.channelState.TheirBalance != aliceBalance {
+			t.Fatalf("bob's view of alice's balance should be %v, "+
+				"is %v", aliceBalance,
+				bobChannel.channelState.TheirBalance)
+		}
+		if bobChannel.channelState.OurBalance != 5e8 {
+			t.Fatalf("bob's balance should be unchanged, is %v",
+				bobChannel.channelState.OurBalance)
+		}
+	}
+
+	// Alice proposes a fee of 0.01 BTC, which should be paid from her
+	// balance once the update has been committed.
+	prevFee, err := aliceChannel.UpdateFee(1e6)
+	if err != nil {
+		t.Fatalf("alice unable to update fee: %v", err)
+	}
+	if prevFee != 0 {
+		t.Fatalf("expected prior fee of 0, got %v", prevFee)
+	}
+	if err := bobChannel.ReceiveUpdateFee(1e6, nil, 0); err != nil {
+		t.Fatalf("bob unable to receive fee update: %v", err)
+	}
+	if err := forceStateTransition(aliceChannel, bobChannel); err != nil {
+		t.Fatalf("unable to complete state update: %v", err)
+	}
+	checkFee(1e6, 5e8-1e6)
+
+	// The update has been committed within both of Bob's commitment
+	// chains, so it should have been compacted from his log.
+	if bobChannel.remoteUpdateLog.Len() != 0 {
+		t.Fatalf("bob's remote log should be empty, has %v entries",
+			bobChannel.remoteUpdateLog.Len())
+	}
+
+	// A fee beyond Alice's balance should be rejected by both parties.
+	if _, err := aliceChannel.UpdateFee(5e8 + 1); err != ErrFeeUnaffordable {
+		t.Fatalf("expected ErrFeeUnaffordable, got: %v", err)
+	}
+	err = bobChannel.ReceiveUpdateFee(5e8+1, nil, 0)
+	if err != ErrFeeUnaffordable {
+		t.Fatalf("expected ErrFeeUnaffordable, got: %v", err)
+	}
+
+	// Bob rejects a fee beyond his ceiling, whether it exceeds the maximum
+	// absolute fee, or pays a rate beyond a multiple of his own estimate.
+	ceiling := &FeeCeiling{
+		MaxFee:             2e6,
+		MaxFeeRateMultiple: 10,
+		ConfTarget:         6,
+	}
+	feeRate := btcutil.Amount(100)
+	maxRateFee := 10 * feeRate * btcutil.Amount(commitVSize(0))
+	if err := bobChannel.ReceiveUpdateFee(2e6+1, ceiling, 0); err == nil {
+		t.Fatalf("fee beyond maximum absolute fee should be rejected")
+	}
+	err = bobChannel.ReceiveUpdateFee(maxRateFee+1, ceiling, feeRate)
+	if err == nil {
+		t.Fatalf("fee beyond maximum fee rate should be rejected")
+	}
+
+	// Lowering the fee should return the difference to Alice, and is
+	// accepted by Bob as it's within his ceiling.
+	prevFee, err = aliceChannel.UpdateFee(5000)
+	if err != nil {
+		t.Fatalf("alice unable to update fee: %v", err)
+	}
+	if prevFee != 1e6 {
+		t.Fatalf("expected prior fee of 1000000, got %v", prevFee)
+	}
+	if err := bobChannel.ReceiveUpdateFee(5000, ceiling, feeRate); err != nil {
+		t.Fatalf("bob unable to receive fee update: %v", err)
+	}
+	if err := forceStateTransition(aliceChannel, bobChannel); err != nil {
+		t.Fatalf("unable to complete state update: %v", err)
+	}
+	checkFee(5000, 5e8-5000)
+}
+
+// TestPendingHTLCs checks that each HTLC which has yet to be fully removed
+// from the channel is reported, along with its progress towards resolution.
+func TestPendingHTLCs(t *testing.T) {
+	aliceChannel, bobChannel, cleanUp, err := createTestChannels(1)
+	if err != nil {
+		t.Fatalf("unable to create test channels: %v", err)
+	}
+	defer cleanUp()
+
+	preimage := bytes.Repeat([]byte{1}, 32)
+	htlc := &lnwire.UpdateAddHTLC{
+		PaymentHash: sha256.Sum256(preimage),
+		Amount:      btcutil.Amount(1e6),
+		Expiry:      uint32(5),
+	}
+	if _, err := aliceChannel.AddHTLC(htlc); err != nil {
+		t.Fatalf("alice unable to add htlc: %v", err)
+	}
+	if _, err := bobChannel.ReceiveHTLC(htlc); err != nil {
+		t.Fatalf("bob unable to receive htlc: %v", err)
+	}
+
+	checkPending := func(channel *LightningChannel, incoming, lockedIn,
+		removing bool) {
+
+		pending := channel.PendingHTLCs()
+		if len(pending) != 1 {
+			t.Fatalf("expected 1 pending htlc, got %v", len(pending))
+		}
+		p := pending[0]
+		if p.Incoming != incoming || p.LockedIn != lockedIn ||
+			p.Removing != removing || p.Amount != htlc.Amount ||
+			p.RHash != PaymentHash(htlc.PaymentHash) ||
+			p.Expiry != htlc.Expiry || p.AddTime.IsZero() {
+
+			t.Fatalf("unexpected pending htlc: %v", spew.Sdump(p))
+		}
+	}
+	checkPending(aliceChannel, false, false, false)
+	checkPending(bobChannel, true, false, false)
+
+	if err := forceStateTransition(aliceChannel, bobChannel); err != nil {
+		t.Fatalf("unable to complete state update: %v", err)
+	}
+	checkPending(aliceChannel, false, true, false)
+	checkPending(bobChannel, true, true, false)
+
+	// Once settled, the HTLC remains pending until the settle has been
+	// committed.
+	var preimageArray [32]byte
+	copy(preimageArray[:], preimage)
+	settleIndex, err := bobChannel.SettleHTLC(preimageArray)
+	if err != nil {
+		t.Fatalf("bob unable to settle htlc: %v", err)
+	}
+	if err := aliceChannel.ReceiveHTLCSettle(preimageArray, settleIndex); err != nil {
+		t.Fatalf("alice unable to receive settle: %v", err)
+	}
+	checkPending(aliceChannel, false, true, true)
+	checkPending(bobChannel, true, true, true)
+
+	if err := forceStateTransition(bobChannel, aliceChannel); err != nil {
+		t.Fatalf("unable to complete state update: %v", err)
+	}
+	if err := forceStateTransition(aliceChannel, bobChannel); err != nil {
+		t.Fatalf("unable to complete state update: %v", err)
+	}
+	if pending := aliceChannel.PendingHTLCs(); len(pending) != 0 {
+		t.Fatalf("expected no pending htlcs, got %v", len(pending))
+	}
+	if pending := bobChannel.PendingHTLCs(); len(pending) != 0 {
+		t.Fatalf("expected no pending htlcs, got %v", len(pending))
+	}
+}
+
+// TestChannelShutdown tests that once a channel has been shut down, no new
+// HTLCs are offered or accepted, while HTLCs already in flight are still
+// resolved, and that the shutdown only completes once they have been.
+func TestChannelShutdown(t *testing.T) {
+	aliceChannel, bobChannel, cleanUp, err := createTestChannels(1)
+	if err != nil {
+		t.Fatalf("unable to create test channels: %v", err)
+	}
+	defer cleanUp()
+
+	preimage := bytes.Repeat([]byte{1}, 32)
+	htlc := &lnwire.UpdateAddHTLC{
+		PaymentHash: sha256.Sum256(preimage),
+		Amount:      btcutil.Amount(1e6),
+		Expiry:      uint32(5),
+	}
+	if _, err := aliceChannel.AddHTLC(htlc); err != nil {
+		t.Fatalf("alice unable to add htlc: %v", err)
+	}
+
+	// Alice shuts the channel down after offering the HTLC, which Bob
+	// receives before her shutdown, so he still accepts it.
+	if err := aliceChannel.InitShutdown(); err != nil {
+		t.Fatalf("alice unable to shut down channel: %v", err)
+	}
+	if !aliceChannel.ShutdownPending() {
+		t.Fatalf("alice's shutdown should be pending")
+	}
+	if _, err := bobChannel.ReceiveHTLC(htlc); err != nil {
+		t.Fatalf("bob unable to receive htlc: %v", err)
+	}
+	if err := bobChannel.ReceiveShutdown(); err != nil {
+		t.Fatalf("bob unable to receive shutdown: %v", err)
+	}
+	if err := bobChannel.InitShutdown(); err != nil {
+		t.Fatalf("bob unable to shut down channel: %v", err)
+	}
+	if err := aliceChannel.ReceiveShutdown(); err != nil {
+		t.Fatalf("alice unable to receive shutdown: %v", err)
+	}
+
+	// Neither party may offer a new HTLC, nor accept one from the other.
+	htlc2 := &lnwire.UpdateAddHTLC{
+		PaymentHash: sha256.Sum256(bytes.Repeat([]byte{2}, 32)),
+		Amount:      btcutil.Amount(1e6),
+		Expiry:      uint32(5),
+	}
+	if _, err := aliceChannel.AddHTLC(htlc2); err != ErrChanShuttingDown {
+		t.Fatalf("expected ErrChanShuttingDown, got %v", err)
+	}
+	if _, err := bobChannel.AddHTLC(htlc2); err != ErrChanShuttingDown {
+		t.Fatalf("expected ErrChanShuttingDown, got %v", err)
+	}
+	if _, err := aliceChannel.ReceiveHTLC(htlc2); err != ErrChanShuttingDown {
+		t.Fatalf("expected ErrChanShuttingDown, got %v", err)
+	}
+
+	// The HTLC in flight is locked in and settled as usual, and until
+	// the settle has been committed by both parties, the shutdown isn't
+	// complete.
+	if err := forceStateTransition(aliceChannel, bobChannel); err != nil {
+		t.Fatalf("unable to complete state update: %v", err)
+	}
+	var preimageArray [32]byte
+	copy(preimageArray[:], preimage)
+	settleIndex, err := bobChannel.SettleHTLC(preimageArray)
+	if err != nil {
+		t.Fatalf("bob unable to settle htlc: %v", err)
+	}
+	if err := aliceChannel.ReceiveHTLCSettle(preimageArray, settleIndex); err != nil {
+		t.Fatalf("alice unable to receive settle: %v", err)
+	}
+	if aliceChannel.ShutdownComplete() || bobChannel.ShutdownComplete() {
+		t.Fatalf("shutdown shouldn't complete with an htlc in flight")
+	}
+
+	if err := forceStateTransition(bobChannel, aliceChannel); err != nil {
+		t.Fatalf("unable to complete state update: %v", err)
+	}
+	if err := forceStateTransition(aliceChannel, bobChannel); err != nil {
+		t.Fatalf("unable to complete state update: %v", err)
+	}
+	if !aliceChannel.ShutdownComplete() || !bobChannel.ShutdownComplete() {
+		t.Fatalf("shutdown should complete once htlcs are resolved")
+	}
+}
+
+// TestChannelOutgoingFrozen tests that no HTLCs may be offered over a frozen
+// channel, while those offered by the remote party are still accepted and
+// resolved, and that HTLCs may be offered once the channel is thawed.
+func TestChannelOutgoingFrozen(t *testing.T) {
+	aliceChannel, bobChannel, cleanUp, err := createTestChannels(1)
+	if err != nil {
+		t.Fatalf("unable to create test channels: %v", err)
+	}
+	defer cleanUp()
+
+	if err := aliceChannel.SetOutgoingFrozen(true); err != nil {
+		t.Fatalf("unable to freeze channel: %v", err)
+	}
+	if !aliceChannel.OutgoingFrozen() {
+		t.Fatalf("alice's channel should be frozen")
+	}
+
+	preimage := bytes.Repeat([]byte{1}, 32)
+	htlc := &lnwire.UpdateAddHTLC{
+		PaymentHash: sha256.Sum256(preimage),
+		Amount:      btcutil.Amount(1e6),
+		Expiry:      uint32(5),
+	}
+	if _, err := aliceChannel.AddHTLC(htlc); err != ErrOutgoingFrozen {
+		t.Fatalf("expected ErrOutgoingFrozen, got %v", err)
+	}
+
+	// Bob is still able to offer an HTLC to Alice, which she settles.
+	if _, err := bobChannel.AddHTLC(htlc); err != nil {
+		t.Fatalf("bob unable to add htlc: %v", err)
+	}
+	if _, err := aliceChannel.ReceiveHTLC(htlc); err != nil {
+		t.Fatalf("alice unable to receive htlc: %v", err)
+	}
+	if err := forceStateTransition(bobChannel, aliceChannel); err != nil {
+		t.Fatalf("unable to complete state update: %v", err)
+	}
+	var preimageArray [32]byte
+	copy(preimageArray[:], preimage)
+	settleIndex, err := aliceChannel.SettleHTLC(preimageArray)
+	if err != nil {
+		t.Fatalf("alice unable to settle htlc: %v", err)
+	}
+	if err := bobChannel.ReceiveHTLCSettle(preimageArray, settleIndex); err != nil {
+		t.Fatalf("bob unable to receive settle: %v", err)
+	}
+	if err := forceStateTransition(aliceChannel, bobChannel); err != nil {
+		t.Fatalf("unable to complete state update: %v", err)
+	}
+
+	// Once thawed, Alice may offer HTLCs once more.
+	if err := aliceChannel.SetOutgoingFrozen(false); err != nil {
+		t.Fatalf("unable to thaw channel: %v", err)
+	}
+	if _, err := aliceChannel.AddHTLC(htlc); err != nil {
+		t.Fatalf("alice unable to add htlc once thawed: %v", err)
+	}
+}
+
+// TestStateDivergence tests that a channel whose in-memory state diverges from
+// its persisted state is frozen, refusing to sign until the divergence has
+// been acknowledged.
+func TestStateDivergence(t *testing.T) {
+	aliceChannel, bobChannel, cleanUp, err := createTestChannels(1)
+	if err != nil {
+		t.Fatalf("unable to create test channels: %v", err)
+	}
+	defer cleanUp()
+
+	// Persist the initial state of both channels, so there's a persisted
+	// state to verify against.
+	if err := aliceChannel.channelState.FullSync(); err != nil {
+		t.Fatalf("unable to sync alice's channel: %v", err)
+	}
+	if err := bobChannel.channelState.FullSync(); err != nil {
+		t.Fatalf("unable to sync bob's channel: %v", err)
+	}
+
+	addHTLC := func(preimage byte) {
+		htlc := &lnwire.UpdateAddHTLC{
+			PaymentHash: sha256.Sum256(bytes.Repeat(
+				[]byte{preimage}, 32)),
+			Amount: btcutil.SatoshiPerBitcoin,
+			Expiry: uint32(5),
+		}
+		if _, err := aliceChannel.AddHTLC(htlc); err != nil {
+			t.Fatalf("alice unable to add htlc: %v", err)
+		}
+		if _, err := bobChannel.ReceiveHTLC(htlc); err != nil {
+			t.Fatalf("bob unable to receive htlc: %v", err)
+		}
+	}
+
+	// After a state transition, both channels should still match their
+	// persisted state.
+	addHTLC(1)
+	if err := forceStateTransition(aliceChannel, bobChannel); err != nil {
+		t.Fatalf("unable to complete state transition: %v", err)
+	}
+	if err := aliceChannel.VerifyPersistedState(); err != nil {
+		t.Fatalf("alice's state shouldn't diverge: %v", err)
+	}
+	if err := bobChannel.VerifyPersistedState(); err != nil {
+		t.Fatalf("bob's state shouldn't diverge: %v", err)
+	}
+
+	// Simulate a lost write by advancing alice's in-memory commitment
+	// height beyond the persisted height.
+	aliceChannel.currentHeight++
+	err = aliceChannel.VerifyPersistedState()
+	divergence, ok := err.(*ErrStateDiverged)
+	if !ok {
+		t.Fatalf("expected ErrStateDiverged, got %v", err)
+	}
+	if divergence.Field != "commitment height" {
+		t.Fatalf("unexpected divergence: %v", divergence)
+	}
+	if aliceChannel.Divergence() != divergence {
+		t.Fatalf("expected channel to be frozen by divergence")
+	}
+
+	// While frozen, alice refuses to sign anything.
+	if _, err := aliceChannel.SignNextCommitment(); err != ErrChannelFrozen {
+		t.Fatalf("expected ErrChannelFrozen, got %v", err)
+	}
+	if _, err := aliceChannel.ForceClose(); err != ErrChannelFrozen {
+		t.Fatalf("expected ErrChannelFrozen, got %v", err)
+	}
+	_, _, err = aliceChannel.InitCooperativeClose(DefaultCoopCloseFee)
+	if err != ErrChannelFrozen {
+		t.Fatalf("expected ErrChannelFrozen, got %v", err)
+	}
+
+	// Acknowledging the divergence unfreezes the channel, though it's
+	// frozen once more if it still diverges.
+	aliceChannel.AckDivergence()
+	if err := aliceChannel.VerifyPersistedState(); err == nil {
+		t.Fatalf("expected divergence to be detected once more")
+	}
+
+	// Once the state matches once more and the divergence has been
+	// acknowledged, the channel can be updated as normal.
+	aliceChannel.currentHeight--
+	aliceChannel.AckDivergence()
+	if err := aliceChannel.VerifyPersistedState(); err != nil {
+		t.Fatalf("alice's state shouldn't diverge: %v", err)
+	}
+	addHTLC(2)
+	if err := forceStateTransition(aliceChannel, bobChannel); err != nil {
+		t.Fatalf("unable to complete state transition: %v", err)
+	}
+}
+
+// TestChanSyncDataLoss tests that the ChannelReestablish messages exchanged
+// upon reconnecting allow either party to detect that it, or the remote
+// party, has lost its state for the channel, and that a channel which has
+// lost its state refuses to sign anything.
+func TestChanSyncDataLoss(t *testing.T) {
+	aliceChannel, bobChannel, cleanUp, err := createTestChannels(1)
+	if err != nil {
+		t.Fatalf("unable to create test channels: %v", err)
+	}
+	defer cleanUp()
+
+	transition := func(preimage byte) {
+		htlc := &lnwire.UpdateAddHTLC{
+			PaymentHash: sha256.Sum256(bytes.Repeat(
+				[]byte{preimage}, 32)),
+			Amount: btcutil.SatoshiPerBitcoin / 10,
+			Expiry: uint32(5),
+		}
+		if _, err := aliceChannel.AddHTLC(htlc); err != nil {
+			t.Fatalf("alice unable to add htlc: %v", err)
+		}
+		if _, err := bobChannel.ReceiveHTLC(htlc); err != nil {
+			t.Fatalf("bob unable to receive htlc: %v", err)
+		}
+		err := forceStateTransition(aliceChannel, bobChannel)
+		if err != nil {
+			t.Fatalf("unable to complete state transition: %v", err)
+		}
+	}
+	syncMsg := func(channel *LightningChannel) *lnwire.ChannelReestablish {
+		msg, err := channel.ChanSyncMsg()
+		if err != nil {
+			t.Fatalf("unable to create sync message: %v", err)
+		}
+		return msg
+	}
+
+	// While both parties are in sync, each accepts the other's view of
+	// the channel.
+	transition(1)
+	staleBobMsg := syncMsg(bobChannel)
+	_, err = aliceChannel.ProcessChanSyncMsg(staleBobMsg)
+	if err != nil {
+		t.Fatalf("alice unable to process bob's sync message: %v", err)
+	}
+	_, err = bobChannel.ProcessChanSyncMsg(syncMsg(aliceChannel))
+	if err != nil {
+		t.Fatalf("bob unable to process alice's sync message: %v", err)
+	}
+
+	// Once bob's view lags behind more than the messages which may have
+	// been in flight, bob has lost his state.
+	transition(2)
+	_, err = aliceChannel.ProcessChanSyncMsg(staleBobMsg)
+	if err != ErrCommitSyncRemoteDataLoss {
+		t.Fatalf("expected ErrCommitSyncRemoteDataLoss, got %v", err)
+	}
+	transition(3)
+	_, err = aliceChannel.ProcessChanSyncMsg(staleBobMsg)
+	if err != ErrCommitSyncRemoteDataLoss {
+		t.Fatalf("expected ErrCommitSyncRemoteDataLoss, got %v", err)
+	}
+
+	// A view of the channel which doesn't carry alice's genuine
+	// revocation is rejected.
+	bobMsg := syncMsg(bobChannel)
+	forgedMsg := *bobMsg
+	forgedMsg.LastRemoteCommitSecret = [32]byte{}
+	_, err = aliceChannel.ProcessChanSyncMsg(&forgedMsg)
+	if err != ErrInvalidLastCommitSecret {
+		t.Fatalf("expected ErrInvalidLastCommitSecret, got %v", err)
+	}
+	if aliceChannel.DataLoss() {
+		t.Fatalf("forged sync message froze the channel")
+	}
+
+	// Simulate alice restoring an outdated state. Bob proves he holds
+	// revocations beyond it, so alice detects she's lost her state.
+	aliceChannel.currentHeight = 1
+	_, err = aliceChannel.ProcessChanSyncMsg(bobMsg)
+	if err != ErrCommitSyncDataLoss {
+		t.Fatalf("expected ErrCommitSyncDataLoss, got %v", err)
+	}
+	if !aliceChannel.DataLoss() {
+		t.Fatalf("expected channel to be frozen by data loss")
+	}
+
+	// Having lost her state, alice refuses to sign anything, including
+	// her likely revoked commitment.
+	if _, err := aliceChannel.SignNextCommitment(); err != ErrCommitSyncDataLoss {
+		t.Fatalf("expected ErrCommitSyncDataLoss, got %v", err)
+	}
+	if _, err := aliceChannel.ForceClose(); err != ErrCommitSyncDataLoss {
+		t.Fatalf("expected ErrCommitSyncDataLoss, got %v", err)
+	}
+	_, _, err = aliceChannel.InitCooperativeClose(DefaultCoopCloseFee)
+	if err != ErrCommitSyncDataLoss {
+		t.Fatalf("expected ErrCommitSyncDataLoss, got %v", err)
+	}
+}
+
+// TestChanSyncRetransmission tests that the ChannelReestablish message of a
+// party which didn't receive our latest revocation or signature before
+// disconnecting identifies the message we must retransmit, and that the
+// channel resumes once the message is delivered.
+func TestChanSyncRetransmission(t *testing.T) {
+	aliceChannel, bobChannel, cleanUp, err := createTestChannels(1)
+	if err != nil {
+		t.Fatalf("unable to create test channels: %v", err)
+	}
+	defer cleanUp()
+
+	addHTLC := func(preimage byte) {
+		htlc := &lnwire.UpdateAddHTLC{
+			PaymentHash: sha256.Sum256(bytes.Repeat(
+				[]byte{preimage}, 32)),
+			Amount: btcutil.SatoshiPerBitcoin / 10,
+			Expiry: uint32(5),
+		}
+		if _, err := aliceChannel.AddHTLC(htlc); err != nil {
+			t.Fatalf("alice unable to add htlc: %v", err)
+		}
+		if _, err := bobChannel.ReceiveHTLC(htlc); err != nil {
+			t.Fatalf("bob unable to receive htlc: %v", err)
+		}
+	}
+	assertRetransmission := func(expected ChanSyncRetransmission) {
+		msg, err := bobChannel.ChanSyncMsg()
+		if err != nil {
+			t.Fatalf("unable to create sync message: %v", err)
+		}
+		retransmission, err := aliceChannel.ProcessChanSyncMsg(msg)
+		if err != nil {
+			t.Fatalf("alice unable to process bob's sync "+
+				"message: %v", err)
+		}
+		if *retransmission != expected {
+			t.Fatalf("expected retransmission %+v, got %+v",
+				expected, *retransmission)
+		}
+	}
+
+	// Run a state transition up until alice's final revocation, which is
+	// lost in flight. Alice must retransmit it.
+	addHTLC(1)
+	aliceSig, err := aliceChannel.SignNextCommitment()
+	if err != nil {
+		t.Fatalf("alice unable to sign commitment: %v", err)
+	}
+	if err := bobChannel.ReceiveNewCommitment(aliceSig); err != nil {
+		t.Fatalf("bob unable to receive commitment: %v", err)
+	}
+	bobRevocation, err := bobChannel.RevokeCurrentCommitment()
+	if err != nil {
+		t.Fatalf("bob unable to revoke commitment: %v", err)
+	}
+	bobSig, err := bobChannel.SignNextCommitment()
+	if err != nil {
+		t.Fatalf("bob unable to sign commitment: %v", err)
+	}
+	if _, err := aliceChannel.ReceiveRevocation(bobRevocation); err != nil {
+		t.Fatalf("alice unable to receive revocation: %v", err)
+	}
+	if err := aliceChannel.ReceiveNewCommitment(bobSig); err != nil {
+		t.Fatalf("alice unable to receive commitment: %v", err)
+	}
+	aliceRevocation, err := aliceChannel.RevokeCurrentCommitment()
+	if err != nil {
+		t.Fatalf("alice unable to revoke commitment: %v", err)
+	}
+
+	aliceHeight, _ := aliceChannel.CommitHeights()
+	assertRetransmission(ChanSyncRetransmission{
+		RevocationHeight: aliceHeight,
+	})
+
+	if _, err := bobChannel.ReceiveRevocation(aliceRevocation); err != nil {
+		t.Fatalf("bob unable to receive revocation: %v", err)
+	}
+	assertRetransmission(ChanSyncRetransmission{})
+
+	// Next, alice's signature for a new state is lost in flight, which
+	// alice detects from bob's view of his commitment chain.
+	addHTLC(2)
+	aliceSig, err = aliceChannel.SignNextCommitment()
+	if err != nil {
+		t.Fatalf("alice unable to sign commitment: %v", err)
+	}
+
+	_, bobHeight := aliceChannel.CommitHeights()
+	assertRetransmission(ChanSyncRetransmission{
+		CommitHeight: bobHeight,
+	})
+
+	// Once the signature is delivered, the state transition completes as
+	// usual.
+	if err := bobChannel.ReceiveNewCommitment(aliceSig); err != nil {
+		t.Fatalf("bob unable to receive commitment: %v", err)
+	}
+	assertRetransmission(ChanSyncRetransmission{})
+
+	bobRevocation, err = bobChannel.RevokeCurrentCommitment()
+	if err != nil {
+		t.Fatalf("bob unable to revoke commitment: %v", err)
+	}
+	bobSig, err = bobChannel.SignNextCommitment()
+	if err != nil {
+		t.Fatalf("bob unable to sign commitment: %v", err)
+	}
+	if _, err := aliceChannel.ReceiveRevocation(bobRevocation); err != nil {
+		t.Fatalf("alice unable to receive revocation: %v", err)
+	}
+	if err := aliceChannel.ReceiveNewCommitment(bobSig); err != nil {
+		t.Fatalf("alice unable to receive commitment: %v", err)
+	}
+	aliceRevocation, err = aliceChannel.RevokeCurrentCommitment()
+	if err != nil {
+		t.Fatalf("alice unable to revoke commitment: %v", err)
+	}
+	if _, err := bobChannel.ReceiveRevocation(aliceRevocation); err != nil {
+		t.Fatalf("bob unable to receive revocation: %v", err)
+	}
+	assertRetransmission(ChanSyncRetransmission{})
+}
+
+// TestChanSyncReplayLostSig tests that once a signature is lost in flight,
+// and both parties reload the channel, dropping the updates the signature
+// covers, the updates retained along with the commitment can be replayed so
+// the state transition completes.
+func TestChanSyncReplayLostSig(t *testing.T) {
+	aliceChannel, bobChannel, cleanUp, err := createTestChannels(1)
+	if err != nil {
+		t.Fatalf("unable to create test channels: %v", err)
+	}
+	defer cleanUp()
+
+	// Alice offers bob an HTLC, then signs bob's commitment covering it,
+	// retaining the HTLC along with the commitment. Her signature is lost
+	// in flight.
+	htlc := &lnwire.UpdateAddHTLC{
+		PaymentHash: sha256.Sum256(bytes.Repeat([]byte{1}, 32)),
+		Amount:      btcutil.SatoshiPerBitcoin / 10,
+		Expiry:      uint32(5),
+	}
+	if _, err := aliceChannel.AddHTLC(htlc); err != nil {
+		t.Fatalf("alice unable to add htlc: %v", err)
+	}
+	if _, err := bobChannel.ReceiveHTLC(htlc); err != nil {
+		t.Fatalf("bob unable to receive htlc: %v", err)
+	}
+	aliceSig, err := aliceChannel.SignNextCommitment()
+	if err != nil {
+		t.Fatalf("alice unable to sign commitment: %v", err)
+	}
+	parsedSig, err := btcec.ParseSignature(aliceSig, btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to parse sig: %v", err)
+	}
+
+	chanPoint := aliceChannel.channelState.ChanID
+	aliceDB := aliceChannel.channelState.Db
+	_, bobHeight := aliceChannel.CommitHeights()
+	err = aliceDB.PutPendingCommit(chanPoint, &channeldb.PendingCommit{
+		Height: bobHeight,
+		Updates: []*channeldb.PendingUpdate{
+			{PaymentHash: htlc.PaymentHash, Msg: htlc},
+		},
+		CommitSig: &lnwire.CommitSig{
+			ChannelPoint: *chanPoint,
+			CommitSig:    parsedSig,
+		},
+	})
+	if err != nil {
+		t.Fatalf("unable to persist pending commitment: %v", err)
+	}
+
+	// Both parties now reconnect, reloading the channel from disk, which
+	// drops the HTLC on either side.
+	alicePub := aliceChannel.channelState.IdentityPub
+	aliceChannels, err := aliceDB.FetchOpenChannels(alicePub)
+	if err != nil {
+		t.Fatalf("unable to fetch channel: %v", err)
+	}
+	bobPub := bobChannel.channelState.IdentityPub
+	bobChannels, err := bobChannel.channelState.Db.FetchOpenChannels(bobPub)
+	if err != nil {
+		t.Fatalf("unable to fetch channel: %v", err)
+	}
+	notifier := aliceChannel.channelEvents
+	aliceChannel, err = NewLightningChannel(aliceChannel.signer, notifier,
+		aliceChannels[0])
+	if err != nil {
+		t.Fatalf("unable to create new channel: %v", err)
+	}
+	bobChannel, err = NewLightningChannel(bobChannel.signer, notifier,
+		bobChannels[0])
+	if err != nil {
+		t.Fatalf("unable to create new channel: %v", err)
+	}
+	if err := initRevocationWindows(aliceChannel, bobChannel, 3); err != nil {
+		t.Fatalf("unable to init revocation windows: %v", err)
+	}
+
+	// As neither party holds the commitment any longer, there's nothing to
+	// retransmit as is, but bob's view of his commitment chain reveals
+	// that he never received alice's signature.
+	msg, err := bobChannel.ChanSyncMsg()
+	if err != nil {
+		t.Fatalf("unable to create sync message: %v", err)
+	}
+	retransmission, err := aliceChannel.ProcessChanSyncMsg(msg)
+	if err != nil {
+		t.Fatalf("alice unable to process bob's sync message: %v", err)
+	}
+	if *retransmission != (ChanSyncRetransmission{}) {
+		t.Fatalf("expected no retransmission, got %+v",
+			*retransmission)
+	}
+
+	commit, err := aliceDB.FetchPendingCommit(chanPoint)
 	if err != nil {
-		t.Fatalf("unable to create test channels: %v", err)
+		t.Fatalf("unable to fetch pending commitment: %v", err)
 	}
-	defer cleanUp()
+	_, remoteHeight := aliceChannel.CommitHeights()
+	if msg.NextLocalCommitHeight > commit.Height ||
+		remoteHeight >= commit.Height {
 
-	// Add max available number of HTLCs.
-	for i := 0; i < MaxHTLCNumber; i++ {
-		htlc := createHTLC(i)
-		if _, err := aliceChannel.AddHTLC(htlc); err != nil {
-			t.Fatalf("alice unable to add htlc: %v", err)
-		}
-		if _, err := bobChannel.ReceiveHTLC(htlc); err != nil {
-			t.Fatalf("bob unable to receive htlc: %v", err)
-		}
+		t.Fatalf("expected signature at height %v to be lost, bob's "+
+			"next height is %v, alice's view of it is %v",
+			commit.Height, msg.NextLocalCommitHeight, remoteHeight)
 	}
 
-	// Next addition should cause HTLC max number validation error.
-	htlc := createHTLC(0)
-	if _, err := aliceChannel.AddHTLC(htlc); err != nil {
-		if err := checkError(err); err != nil {
-			t.Fatal(err)
-		}
-	} else {
-		t.Fatal("Error was not received")
-	}
-	if _, err := bobChannel.AddHTLC(htlc); err != nil {
-		if err := checkError(err); err != nil {
-			t.Fatal(err)
-		}
-	} else {
-		t.Fatal("Error was not received")
+	// Alice replays the HTLC, then signs a new commitment covering it,
+	// which completes the state transition.
+	replayed := aliceChannel.ReplayUpdates(commit.Updates)
+	if len(replayed) != 1 {
+		t.Fatalf("expected 1 update to be replayed, got %v",
+			len(replayed))
 	}
-	if _, err := aliceChannel.ReceiveHTLC(htlc); err != nil {
-		if err := checkError(err); err != nil {
-			t.Fatal(err)
-		}
-	} else {
-		t.Fatal("Error was not received")
+	_, err = bobChannel.ReceiveHTLC(replayed[0].Msg.(*lnwire.UpdateAddHTLC))
+	if err != nil {
+		t.Fatalf("bob unable to receive replayed htlc: %v", err)
 	}
-	if _, err := bobChannel.ReceiveHTLC(htlc); err != nil {
-		if err := checkError(err); err != nil {
-			t.Fatal(err)
-		}
-	} else {
-		t.Fatal("Error was not received")
+	if err := forceStateTransition(aliceChannel, bobChannel); err != nil {
+		t.Fatalf("unable to complete state transition: %v", err)
 	}
 
-	// Manually add HTLC to check SignNextCommitment validation error.
-	aliceChannel.localUpdateLog.appendUpdate(
-		&PaymentDescriptor{
-			Index: aliceChannel.localUpdateLog.logIndex,
-		},
-	)
-
-	_, err = aliceChannel.SignNextCommitment()
-	if err := checkError(err); err != nil {
-		t.Fatal(err)
+	aliceOutgoing := aliceChannel.localCommitChain.tail().outgoingHTLCs
+	if len(aliceOutgoing) != 1 {
+		t.Fatalf("expected alice to have 1 outgoing htlc, got %v",
+			len(aliceOutgoing))
 	}
-
-	// Manually add HTLC to check ReceiveNewCommitment validation error.
-	bobChannel.remoteUpdateLog.appendUpdate(
-		&PaymentDescriptor{
-			Index: bobChannel.remoteUpdateLog.logIndex,
-		},
-	)
-
-	// And on this stage we should receive the weight error.
-	someSig := []byte("somesig")
-	err = bobChannel.ReceiveNewCommitment(someSig)
-	if err := checkError(err); err != nil {
-		t.Fatal(err)
+	bobIncoming := bobChannel.localCommitChain.tail().incomingHTLCs
+	if len(bobIncoming) != 1 {
+		t.Fatalf("expected bob to have 1 incoming htlc, got %v",
+			len(bobIncoming))
 	}
-
 }
 
 // TestForceClose checks that the resulting ForceCloseSummary is correct when
@@ -1018,6 +2402,383 @@ func TestForceClose(t *testing.T) {
 	}
 }
 
+// TestForceCloseHtlcResolutions tests that a force close describes how to
+// claim each HTLC output of the broadcast commitment, and that the resulting
+// claims are valid: the outgoing HTLC through its timeout clause, and the
+// incoming HTLC through its success clause.
+func TestForceCloseHtlcResolutions(t *testing.T) {
+	aliceChannel, bobChannel, cleanUp, err := createTestChannels(3)
+	if err != nil {
+		t.Fatalf("unable to create test channels: %v", err)
+	}
+	defer cleanUp()
+
+	preimage := [32]byte{0x01}
+	htlc := &lnwire.UpdateAddHTLC{
+		PaymentHash: sha256.Sum256(preimage[:]),
+		Amount:      btcutil.Amount(1e6),
+		Expiry:      uint32(10),
+	}
+	if _, err := aliceChannel.AddHTLC(htlc); err != nil {
+		t.Fatalf("alice unable to add htlc: %v", err)
+	}
+	if _, err := bobChannel.ReceiveHTLC(htlc); err != nil {
+		t.Fatalf("bob unable to receive htlc: %v", err)
+	}
+	if err := forceStateTransition(aliceChannel, bobChannel); err != nil {
+		t.Fatalf("Can't update the channel state: %v", err)
+	}
+
+	// claimHtlc validates the claim of the single HTLC resolution within
+	// the passed summary, using the passed witness generator.
+	claimHtlc := func(summary *ForceCloseSummary, incoming bool,
+		csvDelay uint32, genWitness func(*SignDescriptor,
+			*wire.MsgTx) (wire.TxWitness, error)) error {
+
+		if len(summary.HtlcResolutions) != 1 {
+			t.Fatalf("expected 1 htlc resolution, got %v",
+				len(summary.HtlcResolutions))
+		}
+		res := summary.HtlcResolutions[0]
+		if res.Incoming != incoming {
+			t.Fatalf("expected incoming=%v resolution", incoming)
+		}
+		if res.PaymentHash != htlc.PaymentHash ||
+			res.Expiry != htlc.Expiry || res.CsvDelay != csvDelay {
+			t.Fatalf("resolution doesn't match htlc: %v",
+				spew.Sdump(res))
+		}
+		htlcOutput := summary.CloseTx.TxOut[res.Outpoint.Index]
+		if htlcOutput.Value != int64(htlc.Amount) ||
+			!bytes.Equal(htlcOutput.PkScript, res.SignDesc.Output.PkScript) {
+			t.Fatalf("resolution doesn't match htlc output")
+		}
+
+		sweepTx := wire.NewMsgTx(2)
+		sweepTx.AddTxIn(&wire.TxIn{
+			PreviousOutPoint: res.Outpoint,
+			Sequence:         res.CsvDelay,
+		})
+		sweepTx.AddTxOut(&wire.TxOut{
+			PkScript: htlcOutput.PkScript,
+			Value:    htlcOutput.Value - 1000,
+		})
+		if !incoming {
+			sweepTx.LockTime = res.Expiry
+		}
+
+		signDesc := *res.SignDesc
+		signDesc.SigHashes = txscript.NewTxSigHashes(sweepTx)
+		signDesc.InputIndex = 0
+		witness, err := genWitness(&signDesc, sweepTx)
+		if err != nil {
+			t.Fatalf("unable to generate witness: %v", err)
+		}
+		sweepTx.TxIn[0].Witness = witness
+
+		vm, err := txscript.NewEngine(htlcOutput.PkScript, sweepTx, 0,
+			txscript.StandardVerifyFlags, nil, nil, htlcOutput.Value)
+		if err != nil {
+			t.Fatalf("unable to create engine: %v", err)
+		}
+		return vm.Execute()
+	}
+
+	// Alice reclaims her outgoing HTLC once it times out.
+	aliceSummary, err := aliceChannel.ForceClose()
+	if err != nil {
+		t.Fatalf("unable to force close channel: %v", err)
+	}
+	err = claimHtlc(aliceSummary, false,
+		aliceChannel.channelState.LocalCsvDelay,
+		func(signDesc *SignDescriptor,
+			tx *wire.MsgTx) (wire.TxWitness, error) {
+
+			return HtlcSpendTimeout(aliceChannel.signer, signDesc, tx)
+		})
+	if err != nil {
+		t.Fatalf("htlc timeout claim is invalid: %v", err)
+	}
+
+	// Bob claims his incoming HTLC with the preimage, but not without it.
+	bobSummary, err := bobChannel.ForceClose()
+	if err != nil {
+		t.Fatalf("unable to force close channel: %v", err)
+	}
+	err = claimHtlc(bobSummary, true, bobChannel.channelState.LocalCsvDelay,
+		func(signDesc *SignDescriptor,
+			tx *wire.MsgTx) (wire.TxWitness, error) {
+
+			return HtlcSpendSuccess(bobChannel.signer, signDesc, tx,
+				preimage)
+		})
+	if err != nil {
+		t.Fatalf("htlc success claim is invalid: %v", err)
+	}
+	err = claimHtlc(bobSummary, true, bobChannel.channelState.LocalCsvDelay,
+		func(signDesc *SignDescriptor,
+			tx *wire.MsgTx) (wire.TxWitness, error) {
+
+			return HtlcSpendSuccess(bobChannel.signer, signDesc, tx,
+				[32]byte{0x02})
+		})
+	if err == nil {
+		t.Fatalf("htlc success claim with invalid preimage is valid")
+	}
+}
+
+// TestAnchorCommitments checks that commitment transactions of channels using
+// the anchor format carry an anchor output for each party, and that the
+// summary of a force close describes how to sweep our own anchor.
+func TestAnchorCommitments(t *testing.T) {
+	aliceChannel, bobChannel, cleanUp, err := createTestChannels(1)
+	if err != nil {
+		t.Fatalf("unable to create test channels: %v", err)
+	}
+	defer cleanUp()
+
+	aliceChannel.channelState.CommitFormat = channeldb.AnchorCommitment
+	bobChannel.channelState.CommitFormat = channeldb.AnchorCommitment
+
+	preimage := bytes.Repeat([]byte{1}, 32)
+	htlc := &lnwire.UpdateAddHTLC{
+		PaymentHash: sha256.Sum256(preimage),
+		Amount:      btcutil.Amount(1e6),
+		Expiry:      uint32(5),
+	}
+	if _, err := aliceChannel.AddHTLC(htlc); err != nil {
+		t.Fatalf("alice unable to add htlc: %v", err)
+	}
+	if _, err := bobChannel.ReceiveHTLC(htlc); err != nil {
+		t.Fatalf("bob unable to receive htlc: %v", err)
+	}
+	if err := forceStateTransition(aliceChannel, bobChannel); err != nil {
+		t.Fatalf("unable to complete state update: %v", err)
+	}
+
+	// Both commitment transactions should now carry the two anchors.
+	for _, channel := range []*LightningChannel{aliceChannel, bobChannel} {
+		var numAnchors int
+		for _, txOut := range channel.channelState.OurCommitTx.TxOut {
+			if txOut.Value == int64(AnchorSize) {
+				numAnchors++
+			}
+		}
+		if numAnchors != 2 {
+			t.Fatalf("expected 2 anchors, found %v", numAnchors)
+		}
+	}
+
+	closeSummary, err := aliceChannel.ForceClose()
+	if err != nil {
+		t.Fatalf("unable to force close channel: %v", err)
+	}
+	if closeSummary.AnchorSignDesc == nil {
+		t.Fatalf("alice fails to include anchor in ForceCloseSummary")
+	}
+	if closeSummary.AnchorSignDesc.PubKey != aliceChannel.channelState.OurMultiSigKey {
+		t.Fatalf("alice incorrect pubkey in AnchorSignDesc")
+	}
+
+	anchorOut := closeSummary.CloseTx.TxOut[closeSummary.AnchorOutpoint.Index]
+	if !bytes.Equal(anchorOut.PkScript, closeSummary.AnchorSignDesc.Output.PkScript) {
+		t.Fatalf("anchor outpoint doesn't reference alice's anchor")
+	}
+}
+
+// TestDryRunForceClose tests that a dry run of a force closure reports the
+// transaction ForceClose would broadcast, without affecting the channel.
+func TestDryRunForceClose(t *testing.T) {
+	aliceChannel, _, cleanUp, err := createTestChannels(3)
+	if err != nil {
+		t.Fatalf("unable to create test channels: %v", err)
+	}
+	defer cleanUp()
+
+	dryRunTx, localAmt, csvDelay, err := aliceChannel.DryRunForceClose()
+	if err != nil {
+		t.Fatalf("unable to dry run force close: %v", err)
+	}
+	if dryRunTx.TxIn[0].Witness != nil {
+		t.Fatalf("dry run transaction shouldn't be signed")
+	}
+	if localAmt != aliceChannel.channelState.OurBalance {
+		t.Fatalf("expected local amount %v, got %v",
+			aliceChannel.channelState.OurBalance, localAmt)
+	}
+	if csvDelay != aliceChannel.channelState.LocalCsvDelay {
+		t.Fatalf("expected csv delay %v, got %v",
+			aliceChannel.channelState.LocalCsvDelay, csvDelay)
+	}
+
+	// The channel should remain open, so it can still be force closed
+	// with the very transaction reported by the dry run.
+	if aliceChannel.status == channelDispute {
+		t.Fatalf("dry run shouldn't have changed the channel status")
+	}
+	closeSummary, err := aliceChannel.ForceClose()
+	if err != nil {
+		t.Fatalf("unable to force close channel: %v", err)
+	}
+	if closeSummary.CloseTx.TxHash() != dryRunTx.TxHash() {
+		t.Fatalf("dry run reported txid %v, but force close "+
+			"broadcasts %v", dryRunTx.TxHash(),
+			closeSummary.CloseTx.TxHash())
+	}
+}
+
+// TestCommitOutputIndex tests that each party classifies the outputs of a
+// commitment transaction paying to them using the index of commitment
+// outputs, distinguishing their own commitment from the remote party's.
+func TestCommitOutputIndex(t *testing.T) {
+	aliceChannel, bobChannel, cleanUp, err := createTestChannels(1)
+	if err != nil {
+		t.Fatalf("unable to create test channels: %v", err)
+	}
+	defer cleanUp()
+
+	preimage := bytes.Repeat([]byte{1}, 32)
+	htlc := &lnwire.UpdateAddHTLC{
+		PaymentHash: sha256.Sum256(preimage),
+		Amount:      btcutil.Amount(1e6),
+		Expiry:      uint32(5),
+	}
+	if _, err := aliceChannel.AddHTLC(htlc); err != nil {
+		t.Fatalf("alice unable to add htlc: %v", err)
+	}
+	if _, err := bobChannel.ReceiveHTLC(htlc); err != nil {
+		t.Fatalf("bob unable to receive htlc: %v", err)
+	}
+	if err := forceStateTransition(aliceChannel, bobChannel); err != nil {
+		t.Fatalf("unable to complete state update: %v", err)
+	}
+
+	// The transition is well within the initial window, so the index
+	// shouldn't have been topped up.
+	if aliceChannel.outputIndexEnd != commitOutputWindow {
+		t.Fatalf("expected index to end at %v, got %v",
+			commitOutputWindow, aliceChannel.outputIndexEnd)
+	}
+
+	commitTx := aliceChannel.channelState.OurCommitTx
+
+	// Alice should find her delayed output for the current state.
+	owned, err := aliceChannel.ownedCommitOutputs(commitTx)
+	if err != nil {
+		t.Fatalf("unable to classify outputs: %v", err)
+	}
+	if len(owned) != 1 {
+		t.Fatalf("expected alice to own 1 output, got %v", len(owned))
+	}
+	for _, output := range owned {
+		if output.Type != channeldb.CommitOutputLocalDelayed ||
+			output.StateNum != aliceChannel.currentHeight {
+
+			t.Fatalf("expected delayed output at state %v, got "+
+				"%v at state %v", aliceChannel.currentHeight,
+				output.Type, output.StateNum)
+		}
+	}
+
+	// Bob should instead find his immediately spendable output within
+	// the remote party's commitment.
+	owned, err = bobChannel.ownedCommitOutputs(commitTx)
+	if err != nil {
+		t.Fatalf("unable to classify outputs: %v", err)
+	}
+	if len(owned) != 1 {
+		t.Fatalf("expected bob to own 1 output, got %v", len(owned))
+	}
+	for _, output := range owned {
+		if output.Type != channeldb.CommitOutputRemote {
+			t.Fatalf("expected remote output, got %v", output.Type)
+		}
+	}
+}
+
+// TestMachineStatus tests that the phase reported for each side of a channel
+// follows the progress of a state transition.
+func TestMachineStatus(t *testing.T) {
+	aliceChannel, bobChannel, cleanUp, err := createTestChannels(1)
+	if err != nil {
+		t.Fatalf("unable to create test channels: %v", err)
+	}
+	defer cleanUp()
+
+	assertPhases := func(alicePhase, bobPhase MachinePhase) {
+		aliceStatus := aliceChannel.MachineStatus()
+		if aliceStatus.Phase != alicePhase {
+			t.Fatalf("expected alice to be %v, got %v: %v",
+				alicePhase, aliceStatus.Phase,
+				spew.Sdump(aliceStatus))
+		}
+		bobStatus := bobChannel.MachineStatus()
+		if bobStatus.Phase != bobPhase {
+			t.Fatalf("expected bob to be %v, got %v: %v",
+				bobPhase, bobStatus.Phase,
+				spew.Sdump(bobStatus))
+		}
+	}
+	assertPhases(PhaseIdle, PhaseIdle)
+
+	preimage := bytes.Repeat([]byte{1}, 32)
+	htlc := &lnwire.UpdateAddHTLC{
+		PaymentHash: sha256.Sum256(preimage),
+		Amount:      btcutil.Amount(1e6),
+		Expiry:      uint32(5),
+	}
+	if _, err := aliceChannel.AddHTLC(htlc); err != nil {
+		t.Fatalf("alice unable to add htlc: %v", err)
+	}
+	if _, err := bobChannel.ReceiveHTLC(htlc); err != nil {
+		t.Fatalf("bob unable to receive htlc: %v", err)
+	}
+	assertPhases(PhasePendingSign, PhaseAwaitingSig)
+
+	aliceSig, err := aliceChannel.SignNextCommitment()
+	if err != nil {
+		t.Fatalf("alice unable to sign commitment: %v", err)
+	}
+	if err := bobChannel.ReceiveNewCommitment(aliceSig); err != nil {
+		t.Fatalf("bob unable to receive commitment: %v", err)
+	}
+	assertPhases(PhaseAwaitingRevocation, PhasePendingRevocation)
+
+	bobRevocation, err := bobChannel.RevokeCurrentCommitment()
+	if err != nil {
+		t.Fatalf("bob unable to revoke commitment: %v", err)
+	}
+	assertPhases(PhaseAwaitingRevocation, PhasePendingSign)
+
+	bobSig, err := bobChannel.SignNextCommitment()
+	if err != nil {
+		t.Fatalf("bob unable to sign commitment: %v", err)
+	}
+	if _, err := aliceChannel.ReceiveRevocation(bobRevocation); err != nil {
+		t.Fatalf("alice unable to receive revocation: %v", err)
+	}
+	assertPhases(PhaseAwaitingSig, PhaseAwaitingRevocation)
+
+	if err := aliceChannel.ReceiveNewCommitment(bobSig); err != nil {
+		t.Fatalf("alice unable to receive commitment: %v", err)
+	}
+	aliceRevocation, err := aliceChannel.RevokeCurrentCommitment()
+	if err != nil {
+		t.Fatalf("alice unable to revoke commitment: %v", err)
+	}
+	if _, err := bobChannel.ReceiveRevocation(aliceRevocation); err != nil {
+		t.Fatalf("bob unable to receive revocation: %v", err)
+	}
+	assertPhases(PhaseIdle, PhaseIdle)
+
+	// Once either party signals its intent to close the channel, the
+	// idle channel awaits its closure.
+	if err := aliceChannel.InitShutdown(); err != nil {
+		t.Fatalf("alice unable to shut down: %v", err)
+	}
+	assertPhases(PhaseShutdownPending, PhaseIdle)
+}
+
 // TestCheckDustLimit checks that unsettled HTLC with dust limit not included in
 // commitment transaction as output, but sender balance is decreased (thereby all
 // unsettled dust HTLCs will go to miners fee).
@@ -1568,13 +3329,14 @@ func TestCloseTransactionSanityChecks(t *testing.T) {
 	// Both Alice and Bob should reject a close attempt at this point since
 	// it will lead to Alice having a negative output within the commitment
 	// transaction.
-	_, _, err = aliceChannel.InitCooperativeClose()
+	_, _, err = aliceChannel.InitCooperativeClose(DefaultCoopCloseFee)
 	if err == nil {
 		t.Fatalf("alice's closure transaction should have been rejected, " +
 			"but wasn't!")
 	}
 	var fakeSig []byte
-	_, err = bobChannel.CompleteCooperativeClose(fakeSig)
+	_, _, err = bobChannel.SignRemoteCooperativeClose(fakeSig,
+		DefaultCoopCloseFee)
 	if err == nil {
 		t.Fatalf("bob's closure transaction should have been rejected, but " +
 			"wasn't!")
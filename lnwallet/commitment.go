@@ -0,0 +1,241 @@
+package lnwallet
+
+import (
+	"fmt"
+
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+	"github.com/roasbeef/btcutil/txsort"
+)
+
+// CommitmentParams are the parameters of a channel which remain fixed across
+// the commitment transactions of one of its parties. The owner of a
+// commitment transaction is the party able to broadcast it, while the
+// counterparty is the other party of the channel.
+type CommitmentParams struct {
+	// Capacity is the value of the funding output spent by the
+	// commitment transactions.
+	Capacity btcutil.Amount
+
+	// FundingTxIn is the input spending the channel's funding output.
+	// Each commitment transaction built spends its own copy of the
+	// input, so the state hint encoded within its sequence number isn't
+	// shared with any other transaction.
+	FundingTxIn *wire.TxIn
+
+	// OwnerKey is the commitment key of the owner, which is able to claim
+	// the owner's balance and HTLCs after the relative delay.
+	OwnerKey *btcec.PublicKey
+
+	// CounterpartyKey is the commitment key of the counterparty, which is
+	// able to claim the counterparty's balance immediately.
+	CounterpartyKey *btcec.PublicKey
+
+	// CsvDelay is the relative delay imposed upon the outputs of the
+	// owner.
+	CsvDelay uint32
+
+	// DustLimit is the dust limit of the owner. Outputs below it are
+	// trimmed from the commitment transaction, their value going to fees.
+	DustLimit btcutil.Amount
+
+	// StateHintObsfucator obscures the state number encoded within each
+	// commitment transaction.
+	StateHintObsfucator [StateHintSize]byte
+
+	// Anchors is true if the commitment transactions carry an anchor
+	// output for each party, keyed by the funding keys below.
+	Anchors bool
+
+	// OwnerFundingKey and CounterpartyFundingKey are the funding keys of
+	// each party. They're only used if Anchors is set.
+	OwnerFundingKey        *btcec.PublicKey
+	CounterpartyFundingKey *btcec.PublicKey
+}
+
+// CommitmentState is the state of the channel committed to by a single
+// commitment transaction.
+type CommitmentState struct {
+	// Height is the number of the state, which is encoded as the state
+	// hint of the commitment transaction.
+	Height uint64
+
+	// RevocationKey is the key able to claim the owner's balance should
+	// the commitment transaction be revoked.
+	RevocationKey *btcec.PublicKey
+
+	// RevocationHash is the hash of the revocation preimage, which allows
+	// the counterparty to claim the HTLCs of a revoked commitment
+	// transaction.
+	RevocationHash [32]byte
+
+	// OwnerBalance and CounterpartyBalance are the settled balances of
+	// each party. The commitment fee, along with the value of any anchor
+	// outputs, must already have been deducted from the balance of the
+	// initiator.
+	OwnerBalance        btcutil.Amount
+	CounterpartyBalance btcutil.Amount
+
+	// MinFee is the commitment fee negotiated by the parties. The builder
+	// refuses to create a commitment transaction paying less.
+	MinFee btcutil.Amount
+}
+
+// CommitmentHTLC is an HTLC carried by a commitment transaction.
+type CommitmentHTLC struct {
+	// Offered is true if the HTLC was offered by the owner of the
+	// commitment transaction, and false if it was offered to it.
+	Offered bool
+
+	// Amount is the value of the HTLC.
+	Amount btcutil.Amount
+
+	// Expiry is the absolute height at which the HTLC times out.
+	Expiry uint32
+
+	// PaymentHash is the payment hash of the HTLC.
+	PaymentHash [32]byte
+}
+
+// CommitmentTx is a commitment transaction produced by a CommitmentBuilder.
+type CommitmentTx struct {
+	// Tx is the unsigned commitment transaction.
+	Tx *wire.MsgTx
+
+	// HtlcScripts holds the public key script of the output of each HTLC
+	// passed to the builder, in the same order. The script of an HTLC
+	// trimmed as dust is nil.
+	HtlcScripts [][]byte
+
+	// Fee is the fee paid by the transaction: the portion of the channel's
+	// capacity not paid to any output, including the value of any
+	// outputs trimmed as dust.
+	Fee btcutil.Amount
+}
+
+// CommitmentBuilder builds the commitment transactions of one of the parties
+// of a channel. The commitment transaction built depends only upon the
+// channel's parameters, the state committed to, and the set of HTLCs carried,
+// so both parties arrive at identical transactions and need only exchange
+// signatures.
+type CommitmentBuilder struct {
+	params CommitmentParams
+}
+
+// NewCommitmentBuilder creates a new CommitmentBuilder for the commitment
+// transactions described by the passed parameters.
+func NewCommitmentBuilder(params *CommitmentParams) *CommitmentBuilder {
+	return &CommitmentBuilder{
+		params: *params,
+	}
+}
+
+// Build creates the commitment transaction committing to the passed state and
+// HTLCs. The transaction pays the owner's balance to a delayed output, the
+// counterparty's balance to a P2WKH output, and each HTLC to a P2WSH output,
+// along with the anchor outputs if enabled. Outputs below the owner's dust
+// limit are omitted, as is the anchor of a party without any output. The inputs and outputs are sorted according to BIP69, so
+// the order of the passed HTLCs has no bearing on the transaction.
+func (b *CommitmentBuilder) Build(state *CommitmentState,
+	htlcs []CommitmentHTLC) (*CommitmentTx, error) {
+
+	p := &b.params
+
+	// The state hint is set within the sequence number of the funding
+	// input, so we spend a fresh copy of it, leaving the input of any
+	// transaction built previously untouched.
+	fundingTxIn := *p.FundingTxIn
+
+	commitTx, err := CreateCommitTx(&fundingTxIn, p.OwnerKey,
+		p.CounterpartyKey, state.RevocationKey, p.CsvDelay,
+		state.OwnerBalance, state.CounterpartyBalance, p.DustLimit)
+	if err != nil {
+		return nil, err
+	}
+	if p.Anchors {
+		var hasHtlcs bool
+		for _, htlc := range htlcs {
+			if htlc.Amount >= p.DustLimit {
+				hasHtlcs = true
+				break
+			}
+		}
+
+		err := addCommitAnchors(commitTx, p.OwnerFundingKey,
+			p.CounterpartyFundingKey, state.OwnerBalance,
+			state.CounterpartyBalance, p.DustLimit, hasHtlcs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	htlcScripts := make([][]byte, len(htlcs))
+	for i, htlc := range htlcs {
+		if htlc.Amount < p.DustLimit {
+			continue
+		}
+
+		witnessScript, err := b.htlcWitnessScript(state.RevocationHash,
+			&htlc)
+		if err != nil {
+			return nil, err
+		}
+		pkScript, err := witnessScriptHash(witnessScript)
+		if err != nil {
+			return nil, err
+		}
+
+		commitTx.AddTxOut(wire.NewTxOut(int64(htlc.Amount), pkScript))
+		htlcScripts[i] = pkScript
+	}
+
+	// Set the state hint of the commitment transaction to facilitate
+	// quickly recovering the necessary penalty state in the case of an
+	// uncooperative broadcast.
+	err = SetStateNumHint(commitTx, state.Height, p.StateHintObsfucator)
+	if err != nil {
+		return nil, err
+	}
+
+	// Sort the transaction according to the agreed upon canonical
+	// ordering. This lets us skip sending the entire transaction over,
+	// instead we'll just send signatures.
+	txsort.InPlaceSort(commitTx)
+
+	// Whatever remains of the capacity once the outputs have been paid
+	// goes to fees. Should the outputs leave less than the negotiated fee,
+	// the balances passed are inconsistent with the channel.
+	fee := p.Capacity
+	for _, txOut := range commitTx.TxOut {
+		fee -= btcutil.Amount(txOut.Value)
+	}
+	if fee < state.MinFee {
+		return nil, fmt.Errorf("commitment fee of %v is below the "+
+			"negotiated fee of %v", fee, state.MinFee)
+	}
+
+	return &CommitmentTx{
+		Tx:          commitTx,
+		HtlcScripts: htlcScripts,
+		Fee:         fee,
+	}, nil
+}
+
+// htlcWitnessScript returns the witness script of the output paying to the
+// passed HTLC. An HTLC offered by the owner uses the sender's version of the
+// script, while an HTLC offered to the owner uses the receiver's version. In
+// either case, the owner may only claim the HTLC after the relative delay.
+func (b *CommitmentBuilder) htlcWitnessScript(revocationHash [32]byte,
+	htlc *CommitmentHTLC) ([]byte, error) {
+
+	p := &b.params
+	if htlc.Offered {
+		return senderHTLCScript(htlc.Expiry, p.CsvDelay, p.OwnerKey,
+			p.CounterpartyKey, revocationHash[:],
+			htlc.PaymentHash[:])
+	}
+
+	return receiverHTLCScript(htlc.Expiry, p.CsvDelay, p.CounterpartyKey,
+		p.OwnerKey, revocationHash[:], htlc.PaymentHash[:])
+}
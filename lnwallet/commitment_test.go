@@ -0,0 +1,520 @@
+package lnwallet
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+// commitmentTestCapacity is the capacity of the channel the commitment test
+// vectors are built for.
+const commitmentTestCapacity = btcutil.Amount(10000000)
+
+// commitmentTestKey returns the fixed public key derived from a private key
+// consisting of the passed byte repeated.
+func commitmentTestKey(b byte) *btcec.PublicKey {
+	_, pub := btcec.PrivKeyFromBytes(btcec.S256(), bytes.Repeat([]byte{b}, 32))
+	return pub
+}
+
+// commitmentTestVectors are the states and HTLC sets the commitment builder
+// is tested against. The expected values are listed in the order of the
+// outputs of the commitment transaction, while the expected fee is the
+// remainder of the channel's capacity, including any output trimmed as dust.
+// The txid pins the exact transaction built, so any change to the scripts,
+// ordering or state hint of the commitment is caught.
+var commitmentTestVectors = []struct {
+	name                string
+	txid                string
+	anchors             bool
+	ownerBalance        btcutil.Amount
+	counterpartyBalance btcutil.Amount
+	htlcs               []CommitmentHTLC
+	values              []btcutil.Amount
+	fee                 btcutil.Amount
+}{
+	{
+		name:                "no htlcs",
+		txid:                "0d00e971c20a064782668721e1c189ff689b5e0590b738b7e61835c92d902163",
+		ownerBalance:        5990950,
+		counterpartyBalance: 4000000,
+		values:              []btcutil.Amount{4000000, 5990950},
+		fee:                 9050,
+	},
+	{
+		name:                "htlcs in both directions",
+		txid:                "2d254f7156f7114b9f8359c604b8b90fcc00195520954ca41b36b067e4104b69",
+		ownerBalance:        5490950,
+		counterpartyBalance: 3800000,
+		htlcs: []CommitmentHTLC{
+			{
+				Offered:     true,
+				Amount:      500000,
+				Expiry:      500,
+				PaymentHash: sha256.Sum256([]byte{0x01}),
+			},
+			{
+				Offered:     false,
+				Amount:      200000,
+				Expiry:      510,
+				PaymentHash: sha256.Sum256([]byte{0x02}),
+			},
+		},
+		values: []btcutil.Amount{200000, 500000, 3800000, 5490950},
+		fee:    9050,
+	},
+	{
+		name:                "dust htlcs trimmed",
+		txid:                "4f69b5bd862191c07ed58498f771fc42d6d5f28c8368afc2b82619270139805c",
+		ownerBalance:        5989950,
+		counterpartyBalance: 4000000,
+		htlcs: []CommitmentHTLC{
+			{
+				Offered:     true,
+				Amount:      545,
+				Expiry:      500,
+				PaymentHash: sha256.Sum256([]byte{0x01}),
+			},
+			{
+				Offered:     false,
+				Amount:      455,
+				Expiry:      510,
+				PaymentHash: sha256.Sum256([]byte{0x02}),
+			},
+		},
+		values: []btcutil.Amount{4000000, 5989950},
+		fee:    10050,
+	},
+	{
+		name:                "dust balance trimmed",
+		txid:                "5721dc2847b380d03bbd78fa4a53eda6fcad82f577a1a3f2c6c20df6581bbff2",
+		ownerBalance:        500,
+		counterpartyBalance: 9490450,
+		htlcs: []CommitmentHTLC{
+			{
+				Offered:     false,
+				Amount:      500000,
+				Expiry:      510,
+				PaymentHash: sha256.Sum256([]byte{0x02}),
+			},
+		},
+		values: []btcutil.Amount{500000, 9490450},
+		fee:    9550,
+	},
+	{
+		name:                "anchors",
+		txid:                "edee9c21b25c6985fed9b20171e18495ce9a66859e6bc44ab7ad00de3e4ac946",
+		anchors:             true,
+		ownerBalance:        5890290,
+		counterpartyBalance: 4000000,
+		htlcs: []CommitmentHTLC{
+			{
+				Offered:     true,
+				Amount:      100000,
+				Expiry:      500,
+				PaymentHash: sha256.Sum256([]byte{0x01}),
+			},
+		},
+		values: []btcutil.Amount{
+			AnchorSize, AnchorSize, 100000, 4000000, 5890290,
+		},
+		fee: 9050,
+	},
+	{
+		name:         "anchor omitted without balance",
+		txid:         "7ef0ffcca910189a981ec28061aaa76215600725e63a50ed0b363672117029ba",
+		anchors:      true,
+		ownerBalance: 9990290,
+		values:       []btcutil.Amount{AnchorSize, 9990290},
+		fee:          9380,
+	},
+	{
+		name:                "anchor kept for htlcs",
+		txid:                "52b217b791f0f5e1cf21e1e83bdd82eb96fa2d4e2ca3a943f628d398521a5361",
+		anchors:             true,
+		ownerBalance:        9889790,
+		counterpartyBalance: 500,
+		htlcs: []CommitmentHTLC{
+			{
+				Offered:     true,
+				Amount:      100000,
+				Expiry:      500,
+				PaymentHash: sha256.Sum256([]byte{0x01}),
+			},
+		},
+		values: []btcutil.Amount{
+			AnchorSize, AnchorSize, 100000, 9889790,
+		},
+		fee: 9550,
+	},
+	{
+		name:                "identical htlcs",
+		txid:                "844dc7ad42c3c86d5001dd7b91d02dd5eb0c1f64243f9e254c87f68b0b1a9470",
+		ownerBalance:        5490950,
+		counterpartyBalance: 4000000,
+		htlcs: []CommitmentHTLC{
+			{
+				Offered:     true,
+				Amount:      250000,
+				Expiry:      500,
+				PaymentHash: sha256.Sum256([]byte{0x01}),
+			},
+			{
+				Offered:     true,
+				Amount:      250000,
+				Expiry:      500,
+				PaymentHash: sha256.Sum256([]byte{0x01}),
+			},
+		},
+		values: []btcutil.Amount{250000, 250000, 4000000, 5490950},
+		fee:    9050,
+	},
+}
+
+// TestCommitmentBuilder tests that the commitment transactions built from the
+// test vectors carry the expected outputs, sorted according to BIP69, along
+// with the expected fee and state hint, and that they don't depend upon the
+// order of the passed HTLCs.
+func TestCommitmentBuilder(t *testing.T) {
+	ownerKey := commitmentTestKey(0x01)
+	counterpartyKey := commitmentTestKey(0x02)
+	revocationKey := commitmentTestKey(0x03)
+	ownerFundingKey := commitmentTestKey(0x04)
+	counterpartyFundingKey := commitmentTestKey(0x05)
+	revocationHash := sha256.Sum256([]byte("revocation"))
+	fundingOutpoint := wire.OutPoint{
+		Hash:  chainhash.Hash{0x01},
+		Index: 1,
+	}
+
+	const (
+		csvDelay  = 144
+		dustLimit = btcutil.Amount(546)
+		height    = 42
+	)
+	obsfucator := [StateHintSize]byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06}
+
+	// witnessOutput returns the P2WSH script paying to the passed witness
+	// script.
+	witnessOutput := func(witnessScript []byte, err error) []byte {
+		if err != nil {
+			t.Fatalf("unable to create witness script: %v", err)
+		}
+		pkScript, err := witnessScriptHash(witnessScript)
+		if err != nil {
+			t.Fatalf("unable to create witness script hash: %v", err)
+		}
+		return pkScript
+	}
+
+	toOwnerScript := witnessOutput(commitScriptToSelf(csvDelay, ownerKey,
+		revocationKey))
+	toCounterpartyScript, err := commitScriptUnencumbered(counterpartyKey)
+	if err != nil {
+		t.Fatalf("unable to create p2wkh script: %v", err)
+	}
+
+	for _, test := range commitmentTestVectors {
+		fundingTxIn := wire.NewTxIn(&fundingOutpoint, nil, nil)
+		builder := NewCommitmentBuilder(&CommitmentParams{
+			Capacity:               commitmentTestCapacity,
+			FundingTxIn:            fundingTxIn,
+			OwnerKey:               ownerKey,
+			CounterpartyKey:        counterpartyKey,
+			CsvDelay:               csvDelay,
+			DustLimit:              dustLimit,
+			StateHintObsfucator:    obsfucator,
+			Anchors:                test.anchors,
+			OwnerFundingKey:        ownerFundingKey,
+			CounterpartyFundingKey: counterpartyFundingKey,
+		})
+		state := &CommitmentState{
+			Height:              height,
+			RevocationKey:       revocationKey,
+			RevocationHash:      revocationHash,
+			OwnerBalance:        test.ownerBalance,
+			CounterpartyBalance: test.counterpartyBalance,
+		}
+
+		commit, err := builder.Build(state, test.htlcs)
+		if err != nil {
+			t.Fatalf("%v: unable to build commitment: %v", test.name,
+				err)
+		}
+		commitTx := commit.Tx
+		if txid := commitTx.TxHash().String(); txid != test.txid {
+			t.Fatalf("%v: expected txid %v, got %v", test.name,
+				test.txid, txid)
+		}
+
+		if commitTx.Version != 2 || len(commitTx.TxIn) != 1 ||
+			commitTx.TxIn[0].PreviousOutPoint != fundingOutpoint {
+			t.Fatalf("%v: commitment doesn't spend the funding "+
+				"output", test.name)
+		}
+		if hint := GetStateNumHint(commitTx, obsfucator); hint != height {
+			t.Fatalf("%v: expected state hint %v, got %v",
+				test.name, height, hint)
+		}
+
+		// The state hint should only be set within the commitment's
+		// own copy of the funding input.
+		if commitTx.TxIn[0] == fundingTxIn ||
+			fundingTxIn.Sequence != wire.MaxTxInSequenceNum {
+
+			t.Fatalf("%v: commitment shares the funding input",
+				test.name)
+		}
+
+		// Each output should carry the expected value, in BIP69 order.
+		if len(commitTx.TxOut) != len(test.values) {
+			t.Fatalf("%v: expected %v outputs, got %v", test.name,
+				len(test.values), len(commitTx.TxOut))
+		}
+		var total btcutil.Amount
+		for i, txOut := range commitTx.TxOut {
+			if btcutil.Amount(txOut.Value) != test.values[i] {
+				t.Fatalf("%v: expected output %v of %v, got %v",
+					test.name, i, test.values[i], txOut.Value)
+			}
+			total += btcutil.Amount(txOut.Value)
+
+			if i == 0 {
+				continue
+			}
+			prev := commitTx.TxOut[i-1]
+			if prev.Value == txOut.Value &&
+				bytes.Compare(prev.PkScript, txOut.PkScript) > 0 {
+
+				t.Fatalf("%v: outputs %v and %v aren't in BIP69 "+
+					"order", test.name, i-1, i)
+			}
+		}
+
+		// The fee is whatever remains of the capacity.
+		if fee := commitmentTestCapacity - total; fee != test.fee {
+			t.Fatalf("%v: expected fee of %v, got %v", test.name,
+				test.fee, fee)
+		}
+		if commit.Fee != test.fee {
+			t.Fatalf("%v: expected builder to report fee of %v, "+
+				"got %v", test.name, test.fee, commit.Fee)
+		}
+
+		// The builder should refuse to pay less than the negotiated
+		// fee.
+		underpaying := *state
+		underpaying.MinFee = test.fee + 1
+		if _, err := builder.Build(&underpaying, test.htlcs); err == nil {
+			t.Fatalf("%v: commitment paying below the negotiated "+
+				"fee was built", test.name)
+		}
+
+		// Each output should pay to its expected script, which we
+		// derive independently of the builder. A party is only given
+		// an anchor if it has an output within the commitment.
+		var hasHtlcs bool
+		for _, htlc := range test.htlcs {
+			hasHtlcs = hasHtlcs || htlc.Amount >= dustLimit
+		}
+		expected := make([]*wire.TxOut, 0, len(commitTx.TxOut))
+		if test.ownerBalance >= dustLimit {
+			expected = append(expected, wire.NewTxOut(
+				int64(test.ownerBalance), toOwnerScript))
+		}
+		if test.counterpartyBalance >= dustLimit {
+			expected = append(expected, wire.NewTxOut(
+				int64(test.counterpartyBalance),
+				toCounterpartyScript))
+		}
+		if test.anchors && (test.ownerBalance >= dustLimit ||
+			hasHtlcs) {
+
+			expected = append(expected, wire.NewTxOut(
+				int64(AnchorSize),
+				witnessOutput(anchorScript(ownerFundingKey))))
+		}
+		if test.anchors && (test.counterpartyBalance >= dustLimit ||
+			hasHtlcs) {
+
+			expected = append(expected, wire.NewTxOut(
+				int64(AnchorSize),
+				witnessOutput(anchorScript(counterpartyFundingKey))))
+		}
+		htlcScripts := make([][]byte, len(test.htlcs))
+		for i, htlc := range test.htlcs {
+			if htlc.Amount < dustLimit {
+				continue
+			}
+
+			if htlc.Offered {
+				htlcScripts[i] = witnessOutput(senderHTLCScript(
+					htlc.Expiry, csvDelay, ownerKey,
+					counterpartyKey, revocationHash[:],
+					htlc.PaymentHash[:]))
+			} else {
+				htlcScripts[i] = witnessOutput(receiverHTLCScript(
+					htlc.Expiry, csvDelay, counterpartyKey,
+					ownerKey, revocationHash[:],
+					htlc.PaymentHash[:]))
+			}
+			expected = append(expected, wire.NewTxOut(
+				int64(htlc.Amount), htlcScripts[i]))
+		}
+
+		for i, txOut := range commitTx.TxOut {
+			found := false
+			for j, expectedOut := range expected {
+				if expectedOut == nil ||
+					expectedOut.Value != txOut.Value ||
+					!bytes.Equal(expectedOut.PkScript,
+						txOut.PkScript) {
+
+					continue
+				}
+
+				expected[j] = nil
+				found = true
+				break
+			}
+			if !found {
+				t.Fatalf("%v: output %v has unexpected script %x",
+					test.name, i, txOut.PkScript)
+			}
+		}
+
+		for i, pkScript := range commit.HtlcScripts {
+			if !bytes.Equal(pkScript, htlcScripts[i]) {
+				t.Fatalf("%v: expected script %x for htlc %v, "+
+					"got %x", test.name, htlcScripts[i], i,
+					pkScript)
+			}
+		}
+
+		// Building the commitment anew with the HTLCs reversed should
+		// result in the very same transaction.
+		reversed := make([]CommitmentHTLC, len(test.htlcs))
+		for i, htlc := range test.htlcs {
+			reversed[len(reversed)-1-i] = htlc
+		}
+		reversedCommit, err := builder.Build(state, reversed)
+		if err != nil {
+			t.Fatalf("%v: unable to build commitment: %v", test.name,
+				err)
+		}
+		if reversedCommit.Tx.TxHash() != commitTx.TxHash() {
+			t.Fatalf("%v: commitment depends on the order of htlcs",
+				test.name)
+		}
+
+		// Building a commitment at a later height mustn't alter the
+		// state hint of the one built before it.
+		nextState := *state
+		nextState.Height++
+		if _, err := builder.Build(&nextState, test.htlcs); err != nil {
+			t.Fatalf("%v: unable to build commitment: %v", test.name,
+				err)
+		}
+		if hint := GetStateNumHint(commitTx, obsfucator); hint != height {
+			t.Fatalf("%v: state hint of prior commitment changed "+
+				"to %v", test.name, hint)
+		}
+	}
+}
+
+// TestCommitmentNegotiatedDustLimit tests that the outputs of a commitment
+// below the dust limit negotiated for the channel are trimmed into its fee,
+// even though they lie well above the default dust threshold, while those at
+// the limit are kept.
+func TestCommitmentNegotiatedDustLimit(t *testing.T) {
+	const (
+		dustLimit = btcutil.Amount(10000)
+		baseFee   = btcutil.Amount(10000)
+	)
+	if err := ValidateDustLimit(dustLimit); err != nil {
+		t.Fatalf("expected dust limit to be valid: %v", err)
+	}
+
+	// The counterparty's balance and the offered HTLC fall just below the
+	// negotiated limit, while the received HTLC is exactly at it.
+	htlcs := []CommitmentHTLC{
+		{
+			Offered:     true,
+			Amount:      dustLimit - 1,
+			Expiry:      500,
+			PaymentHash: sha256.Sum256([]byte("trimmed")),
+		},
+		{
+			Offered:     false,
+			Amount:      dustLimit,
+			Expiry:      500,
+			PaymentHash: sha256.Sum256([]byte("kept")),
+		},
+	}
+	counterpartyBalance := dustLimit - 1
+	ownerBalance := commitmentTestCapacity - baseFee - counterpartyBalance -
+		htlcs[0].Amount - htlcs[1].Amount
+
+	newBuilder := func(dustLimit btcutil.Amount) *CommitmentBuilder {
+		return NewCommitmentBuilder(&CommitmentParams{
+			Capacity: commitmentTestCapacity,
+			FundingTxIn: wire.NewTxIn(&wire.OutPoint{
+				Hash: chainhash.Hash{0x01},
+			}, nil, nil),
+			OwnerKey:        commitmentTestKey(0x01),
+			CounterpartyKey: commitmentTestKey(0x02),
+			CsvDelay:        144,
+			DustLimit:       dustLimit,
+		})
+	}
+	state := &CommitmentState{
+		RevocationKey:       commitmentTestKey(0x03),
+		RevocationHash:      sha256.Sum256([]byte("revocation")),
+		OwnerBalance:        ownerBalance,
+		CounterpartyBalance: counterpartyBalance,
+		MinFee:              baseFee,
+	}
+
+	commit, err := newBuilder(dustLimit).Build(state, htlcs)
+	if err != nil {
+		t.Fatalf("unable to build commitment: %v", err)
+	}
+
+	// Only the owner's balance and the HTLC at the limit should remain,
+	// with the trimmed outputs paid to the fee.
+	if len(commit.Tx.TxOut) != 2 {
+		t.Fatalf("expected 2 outputs, got %v", len(commit.Tx.TxOut))
+	}
+	for _, txOut := range commit.Tx.TxOut {
+		if btcutil.Amount(txOut.Value) < dustLimit {
+			t.Fatalf("output of %v below the dust limit wasn't "+
+				"trimmed", txOut.Value)
+		}
+	}
+	if commit.HtlcScripts[0] != nil || commit.HtlcScripts[1] == nil {
+		t.Fatalf("expected only the htlc at the dust limit to have " +
+			"an output")
+	}
+	expectedFee := baseFee + counterpartyBalance + htlcs[0].Amount
+	if commit.Fee != expectedFee {
+		t.Fatalf("expected fee of %v, got %v", expectedFee, commit.Fee)
+	}
+
+	// Under the default dust limit, the very same outputs are kept.
+	commit, err = newBuilder(DefaultDustLimit()).Build(state, htlcs)
+	if err != nil {
+		t.Fatalf("unable to build commitment: %v", err)
+	}
+	if len(commit.Tx.TxOut) != 4 {
+		t.Fatalf("expected 4 outputs under the default dust limit, "+
+			"got %v", len(commit.Tx.TxOut))
+	}
+	if commit.Fee != baseFee {
+		t.Fatalf("expected fee of %v, got %v", baseFee, commit.Fee)
+	}
+}
@@ -0,0 +1,122 @@
+package lnwallet
+
+import (
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// commitOutputWindow is the number of states beyond our current state for
+// which the delayed output scripts within our commitment transactions are
+// indexed. The index is only topped up once less than half of the window
+// remains, so a write is required every commitOutputWindow/2 states rather
+// than for each one.
+const commitOutputWindow = 100
+
+// indexCommitOutputs ensures the output scripts paying to us within the
+// channel's commitment transactions are indexed within the database up to
+// commitOutputWindow states beyond our current state. This allows the
+// ownership of a transaction appearing on-chain to be determined by looking
+// up its outputs, rather than re-deriving the scripts of each channel. States
+// which were revoked before the channel was first indexed aren't indexed.
+//
+// NOTE: The channel's mutex MUST be held when calling this method.
+func (lc *LightningChannel) indexCommitOutputs() error {
+	state := lc.channelState
+	if state.Db == nil || state.ChanType == channeldb.RecoveredChannel {
+		return nil
+	}
+
+	if lc.outputIndexEnd > lc.currentHeight+commitOutputWindow/2 {
+		return nil
+	}
+
+	var outputs []*channeldb.CommitOutput
+
+	// Our output within the remote party's commitment transaction pays
+	// to our static commitment key, so it only needs to be indexed once.
+	start := lc.outputIndexEnd
+	if start == 0 {
+		remoteScript, err := commitScriptUnencumbered(state.OurCommitKey)
+		if err != nil {
+			return err
+		}
+
+		outputs = append(outputs, &channeldb.CommitOutput{
+			ChanPoint: *state.ChanID,
+			Type:      channeldb.CommitOutputRemote,
+			PkScript:  remoteScript,
+		})
+	}
+	if start < lc.currentHeight {
+		start = lc.currentHeight
+	}
+
+	end := lc.currentHeight + commitOutputWindow
+	for stateNum := start; stateNum < end; stateNum++ {
+		pkScript, err := lc.localDelayedScript(stateNum)
+		if err != nil {
+			return err
+		}
+
+		outputs = append(outputs, &channeldb.CommitOutput{
+			ChanPoint: *state.ChanID,
+			Type:      channeldb.CommitOutputLocalDelayed,
+			StateNum:  stateNum,
+			PkScript:  pkScript,
+		})
+	}
+
+	if err := state.Db.PutCommitOutputs(state.ChanID, end, outputs); err != nil {
+		return err
+	}
+	lc.outputIndexEnd = end
+
+	return nil
+}
+
+// localDelayedScript derives the output script of the time-locked output
+// paying to us within our commitment transaction at the passed state.
+func (lc *LightningChannel) localDelayedScript(stateNum uint64) ([]byte, error) {
+	state := lc.channelState
+
+	revocation, err := state.RevocationProducer.AtIndex(stateNum)
+	if err != nil {
+		return nil, err
+	}
+	revokeKey := DeriveRevocationPubkey(state.TheirCommitKey,
+		revocation[:])
+
+	selfScript, err := commitScriptToSelf(state.LocalCsvDelay,
+		state.OurCommitKey, revokeKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return witnessScriptHash(selfScript)
+}
+
+// ownedCommitOutputs looks up the outputs of the passed transaction within
+// the index of commitment outputs, returning those which pay to us within
+// this channel keyed by their index within the transaction.
+func (lc *LightningChannel) ownedCommitOutputs(
+	tx *wire.MsgTx) (map[uint32]*channeldb.CommitOutput, error) {
+
+	if lc.channelState.Db == nil {
+		return nil, nil
+	}
+
+	owned, err := lc.channelState.Db.LookupCommitOutputs(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	// The outputs of other channels are of no concern to this channel's
+	// close observer.
+	for i, output := range owned {
+		if output.ChanPoint != *lc.channelState.ChanID {
+			delete(owned, i)
+		}
+	}
+
+	return owned, nil
+}
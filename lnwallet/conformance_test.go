@@ -0,0 +1,476 @@
+package lnwallet
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcutil"
+)
+
+// conformanceHTLCAmt is the amount of each HTLC added within the conformance
+// tests.
+const conformanceHTLCAmt = btcutil.SatoshiPerBitcoin / 10
+
+// scriptedPeer is the remote party of a channel under test, whose messages to
+// the channel are scripted in order to deviate from the protocol. Each message
+// is first produced by an honest state machine for the remote party, then
+// optionally mutated before being delivered. A script may therefore follow a
+// rejected message with the genuine one, or replay a genuine message which
+// has already been accepted.
+type scriptedPeer struct {
+	t *testing.T
+
+	// node is the channel under test, and remote is the honest state
+	// machine of the remote party.
+	node   *LightningChannel
+	remote *LightningChannel
+
+	// lastSig and lastRevocation are the genuine commitment signature and
+	// revocation most recently produced by the remote party.
+	lastSig        []byte
+	lastRevocation *lnwire.RevokeAndAck
+
+	// htlcs maps the preimage seed of each HTLC added by the node to its
+	// index within the node's update log.
+	htlcs map[byte]uint64
+}
+
+// newScriptedPeer creates a pair of test channels, with Alice's channel as the
+// channel under test, and Bob as the scripted peer.
+func newScriptedPeer(t *testing.T) (*scriptedPeer, func()) {
+	aliceChannel, bobChannel, cleanUp, err := createTestChannels(1)
+	if err != nil {
+		t.Fatalf("unable to create test channels: %v", err)
+	}
+
+	return &scriptedPeer{
+		t:      t,
+		node:   aliceChannel,
+		remote: bobChannel,
+		htlcs:  make(map[byte]uint64),
+	}, cleanUp
+}
+
+// conformancePreimage returns the preimage of the HTLC with the passed seed.
+func conformancePreimage(seed byte) [32]byte {
+	var preimage [32]byte
+	copy(preimage[:], bytes.Repeat([]byte{seed}, 32))
+	return preimage
+}
+
+// conformanceHTLC returns an HTLC paying to the preimage with the passed
+// seed.
+func conformanceHTLC(seed byte) *lnwire.UpdateAddHTLC {
+	return &lnwire.UpdateAddHTLC{
+		PaymentHash: sha256.Sum256(bytes.Repeat([]byte{seed}, 32)),
+		Amount:      conformanceHTLCAmt,
+		Expiry:      10,
+	}
+}
+
+// nodeAddsHTLC has the node offer an HTLC to the peer.
+func (p *scriptedPeer) nodeAddsHTLC(seed byte) error {
+	htlc := conformanceHTLC(seed)
+	index, err := p.node.AddHTLC(htlc)
+	if err != nil {
+		return err
+	}
+	if _, err := p.remote.ReceiveHTLC(htlc); err != nil {
+		p.t.Fatalf("remote party rejected node's htlc: %v", err)
+	}
+
+	p.htlcs[seed] = index
+	return nil
+}
+
+// peerAddsHTLC has the peer offer an HTLC to the node.
+func (p *scriptedPeer) peerAddsHTLC(seed byte) error {
+	htlc := conformanceHTLC(seed)
+	if _, err := p.remote.AddHTLC(htlc); err != nil {
+		p.t.Fatalf("remote party unable to add htlc: %v", err)
+	}
+
+	_, err := p.node.ReceiveHTLC(htlc)
+	return err
+}
+
+// peerSignsCommitment has the peer sign a new commitment for the node,
+// delivering the signature after passing it through mutate, if set.
+func (p *scriptedPeer) peerSignsCommitment(mutate func([]byte) []byte) error {
+	sig, err := p.remote.SignNextCommitment()
+	if err != nil {
+		p.t.Fatalf("remote party unable to sign commitment: %v", err)
+	}
+	p.lastSig = sig
+
+	return p.peerResendsCommitment(mutate)
+}
+
+// peerResendsCommitment delivers the commitment signature most recently
+// produced by the peer once again, after passing it through mutate, if set.
+func (p *scriptedPeer) peerResendsCommitment(mutate func([]byte) []byte) error {
+	sig := append([]byte(nil), p.lastSig...)
+	if mutate != nil {
+		sig = mutate(sig)
+	}
+
+	return p.node.ReceiveNewCommitment(sig)
+}
+
+// peerRevokes has the peer revoke its prior commitment, delivering the
+// revocation after passing it through mutate, if set.
+func (p *scriptedPeer) peerRevokes(mutate func(*lnwire.RevokeAndAck)) error {
+	revocation, err := p.remote.RevokeCurrentCommitment()
+	if err != nil {
+		p.t.Fatalf("remote party unable to revoke commitment: %v", err)
+	}
+	p.lastRevocation = revocation
+
+	rev := *revocation
+	if mutate != nil {
+		mutate(&rev)
+	}
+
+	_, err = p.node.ReceiveRevocation(&rev)
+	return err
+}
+
+// peerResendsRevocation delivers the revocation most recently produced by
+// the peer once again.
+func (p *scriptedPeer) peerResendsRevocation() error {
+	rev := *p.lastRevocation
+	_, err := p.node.ReceiveRevocation(&rev)
+	return err
+}
+
+// peerRevokesPrematurely has the peer reveal the revocation preimage of its
+// current commitment, which the node has yet to supersede.
+func (p *scriptedPeer) peerRevokesPrematurely() error {
+	producer := p.remote.channelState.RevocationProducer
+	preimage, err := producer.AtIndex(p.remote.currentHeight)
+	if err != nil {
+		p.t.Fatalf("unable to derive revocation preimage: %v", err)
+	}
+
+	rev := &lnwire.RevokeAndAck{
+		ChannelPoint: *p.remote.channelState.ChanID,
+	}
+	copy(rev.Revocation[:], preimage[:])
+
+	_, err = p.node.ReceiveRevocation(rev)
+	return err
+}
+
+// nodeRevokes has the node revoke its prior commitment.
+func (p *scriptedPeer) nodeRevokes() error {
+	revocation, err := p.node.RevokeCurrentCommitment()
+	if err != nil {
+		return err
+	}
+	if _, err := p.remote.ReceiveRevocation(revocation); err != nil {
+		p.t.Fatalf("remote party rejected node's revocation: %v", err)
+	}
+
+	return nil
+}
+
+// nodeSignsCommitment has the node sign a new commitment for the peer.
+func (p *scriptedPeer) nodeSignsCommitment() error {
+	sig, err := p.node.SignNextCommitment()
+	if err != nil {
+		return err
+	}
+	if err := p.remote.ReceiveNewCommitment(sig); err != nil {
+		p.t.Fatalf("remote party rejected node's commitment: %v", err)
+	}
+
+	return nil
+}
+
+// peerSettles has the peer settle the HTLC with the passed seed offered by
+// the node.
+func (p *scriptedPeer) peerSettles(seed byte) error {
+	preimage := conformancePreimage(seed)
+	index, err := p.remote.SettleHTLC(preimage)
+	if err != nil {
+		p.t.Fatalf("remote party unable to settle htlc: %v", err)
+	}
+
+	return p.node.ReceiveHTLCSettle(preimage, index)
+}
+
+// peerSendsSettle delivers a settle of the HTLC at the passed index of the
+// node's update log, carrying the preimage with the passed seed, without the
+// peer's knowledge.
+func (p *scriptedPeer) peerSendsSettle(seed byte, index uint64) error {
+	return p.node.ReceiveHTLCSettle(conformancePreimage(seed), index)
+}
+
+// peerFails has the peer fail the HTLC with the passed seed offered by the
+// node.
+func (p *scriptedPeer) peerFails(seed byte) error {
+	htlc := conformanceHTLC(seed)
+	index, err := p.remote.FailHTLC(htlc.PaymentHash)
+	if err != nil {
+		p.t.Fatalf("remote party unable to fail htlc: %v", err)
+	}
+
+	return p.node.ReceiveFailHTLC(index)
+}
+
+// lockIn has both parties commit to all pending updates via an honest state
+// transition initiated by the node.
+func (p *scriptedPeer) lockIn() error {
+	return forceStateTransition(p.node, p.remote)
+}
+
+// assertConsistent asserts that the channel under test is still able to add
+// HTLCs in either direction and commit them, and that both parties agree on
+// the resulting balances.
+func (p *scriptedPeer) assertConsistent(name string) {
+	if err := p.nodeAddsHTLC(0xf0); err != nil {
+		p.t.Fatalf("%v: node unable to add htlc: %v", name, err)
+	}
+	if err := p.peerAddsHTLC(0xf1); err != nil {
+		p.t.Fatalf("%v: node unable to receive htlc: %v", name, err)
+	}
+	if err := p.lockIn(); err != nil {
+		p.t.Fatalf("%v: unable to complete state transition: %v", name,
+			err)
+	}
+
+	nodeState := p.node.channelState
+	remoteState := p.remote.channelState
+	if nodeState.OurBalance != remoteState.TheirBalance ||
+		nodeState.TheirBalance != remoteState.OurBalance {
+
+		p.t.Fatalf("%v: parties disagree on balances: node has "+
+			"%v/%v, remote party has %v/%v", name,
+			nodeState.OurBalance, nodeState.TheirBalance,
+			remoteState.TheirBalance, remoteState.OurBalance)
+	}
+}
+
+// corruptSig flips a bit of the signature's S value, so the signature remains
+// well formed, but is invalid.
+func corruptSig(sig []byte) []byte {
+	sig[len(sig)-1] ^= 1
+	return sig
+}
+
+// truncateSig truncates the signature, so it's malformed.
+func truncateSig(sig []byte) []byte {
+	return sig[:len(sig)-1]
+}
+
+// corruptRevocation flips a bit of the revocation preimage.
+func corruptRevocation(rev *lnwire.RevokeAndAck) {
+	rev.Revocation[0] ^= 1
+}
+
+// peerStep is a single step within the script of a scriptedPeer, along with
+// how the node under test must respond.
+type peerStep struct {
+	desc string
+	run  func(p *scriptedPeer) error
+
+	// reject is true if the node must refuse the step's message.
+	reject bool
+}
+
+// accept returns a step whose message the node must accept.
+func accept(desc string, run func(p *scriptedPeer) error) peerStep {
+	return peerStep{desc: desc, run: run}
+}
+
+// reject returns a step whose message the node must refuse.
+func reject(desc string, run func(p *scriptedPeer) error) peerStep {
+	return peerStep{desc: desc, run: run, reject: true}
+}
+
+// TestPeerConformance runs scripts in which the remote party of a channel
+// sends malformed, out of order, or replayed messages, asserting that the
+// channel refuses each such message, and that the channel remains usable
+// afterwards with both parties in agreement over its state.
+func TestPeerConformance(t *testing.T) {
+	peerSigns := func(p *scriptedPeer) error {
+		return p.peerSignsCommitment(nil)
+	}
+	peerRevokes := func(p *scriptedPeer) error {
+		return p.peerRevokes(nil)
+	}
+	peerAdds := func(seed byte) func(p *scriptedPeer) error {
+		return func(p *scriptedPeer) error {
+			return p.peerAddsHTLC(seed)
+		}
+	}
+	nodeAdds := func(seed byte) func(p *scriptedPeer) error {
+		return func(p *scriptedPeer) error {
+			return p.nodeAddsHTLC(seed)
+		}
+	}
+
+	// peerTransition is a state transition initiated by the peer, after
+	// which only the peer's revocation of its prior commitment is
+	// outstanding.
+	peerTransition := []peerStep{
+		accept("peer adds htlc", peerAdds(1)),
+		accept("peer signs commitment", peerSigns),
+		accept("node revokes", (*scriptedPeer).nodeRevokes),
+		accept("node signs commitment", (*scriptedPeer).nodeSignsCommitment),
+	}
+
+	// nodeHTLC has the node offer an HTLC which is then locked in.
+	nodeHTLC := []peerStep{
+		accept("node adds htlc", nodeAdds(2)),
+		accept("lock in htlc", (*scriptedPeer).lockIn),
+	}
+
+	script := func(parts ...[]peerStep) []peerStep {
+		var steps []peerStep
+		for _, part := range parts {
+			steps = append(steps, part...)
+		}
+		return steps
+	}
+
+	tests := []struct {
+		name  string
+		steps []peerStep
+	}{
+		{
+			name: "invalid commitment signature",
+			steps: []peerStep{
+				accept("peer adds htlc", peerAdds(1)),
+				reject("peer signs commitment with invalid "+
+					"signature", func(p *scriptedPeer) error {
+					return p.peerSignsCommitment(corruptSig)
+				}),
+				reject("peer sends malformed signature",
+					func(p *scriptedPeer) error {
+						return p.peerResendsCommitment(
+							truncateSig)
+					}),
+				accept("peer sends genuine signature",
+					func(p *scriptedPeer) error {
+						return p.peerResendsCommitment(nil)
+					}),
+				accept("node revokes", (*scriptedPeer).nodeRevokes),
+				accept("node signs commitment",
+					(*scriptedPeer).nodeSignsCommitment),
+				accept("peer revokes", peerRevokes),
+			},
+		},
+		{
+			name: "invalid revocation preimage",
+			steps: script(peerTransition, []peerStep{
+				reject("peer revokes with wrong preimage",
+					func(p *scriptedPeer) error {
+						return p.peerRevokes(
+							corruptRevocation)
+					}),
+				accept("peer sends genuine revocation",
+					(*scriptedPeer).peerResendsRevocation),
+			}),
+		},
+		{
+			name: "replayed revocation",
+			steps: script(peerTransition, []peerStep{
+				accept("peer revokes", peerRevokes),
+				reject("peer replays revocation",
+					(*scriptedPeer).peerResendsRevocation),
+			}),
+		},
+		{
+			name: "replayed commitment signature",
+			steps: script(peerTransition, []peerStep{
+				accept("peer revokes", peerRevokes),
+				reject("peer replays commitment signature",
+					func(p *scriptedPeer) error {
+						return p.peerResendsCommitment(nil)
+					}),
+			}),
+		},
+		{
+			name: "premature revocation",
+			steps: []peerStep{
+				reject("peer revokes unsuperseded commitment",
+					(*scriptedPeer).peerRevokesPrematurely),
+			},
+		},
+		{
+			name: "settle with wrong preimage",
+			steps: script(nodeHTLC, []peerStep{
+				reject("peer settles with wrong preimage",
+					func(p *scriptedPeer) error {
+						return p.peerSendsSettle(3,
+							p.htlcs[2])
+					}),
+				accept("peer settles", func(p *scriptedPeer) error {
+					return p.peerSettles(2)
+				}),
+				accept("lock in settle", (*scriptedPeer).lockIn),
+			}),
+		},
+		{
+			name: "settle of unknown htlc",
+			steps: script(nodeHTLC, []peerStep{
+				reject("peer settles unknown htlc",
+					func(p *scriptedPeer) error {
+						return p.peerSendsSettle(2, 1000)
+					}),
+			}),
+		},
+		{
+			name: "replayed settle",
+			steps: script(nodeHTLC, []peerStep{
+				accept("peer settles", func(p *scriptedPeer) error {
+					return p.peerSettles(2)
+				}),
+				reject("peer replays settle",
+					func(p *scriptedPeer) error {
+						return p.peerSendsSettle(2,
+							p.htlcs[2])
+					}),
+				accept("lock in settle", (*scriptedPeer).lockIn),
+			}),
+		},
+		{
+			name: "replayed fail",
+			steps: script(nodeHTLC, []peerStep{
+				accept("peer fails htlc", func(p *scriptedPeer) error {
+					return p.peerFails(2)
+				}),
+				reject("peer replays fail",
+					func(p *scriptedPeer) error {
+						return p.node.ReceiveFailHTLC(
+							p.htlcs[2])
+					}),
+				accept("lock in fail", (*scriptedPeer).lockIn),
+			}),
+		},
+	}
+
+	for _, test := range tests {
+		p, cleanUp := newScriptedPeer(t)
+
+		for i, step := range test.steps {
+			err := step.run(p)
+			switch {
+			case step.reject && err == nil:
+				t.Fatalf("%v: step #%v (%v): node accepted "+
+					"message, expected rejection", test.name,
+					i, step.desc)
+			case !step.reject && err != nil:
+				t.Fatalf("%v: step #%v (%v): node rejected "+
+					"message: %v", test.name, i, step.desc,
+					err)
+			}
+		}
+
+		p.assertConsistent(test.name)
+		cleanUp()
+	}
+}
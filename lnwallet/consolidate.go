@@ -0,0 +1,147 @@
+package lnwallet
+
+import (
+	"sort"
+
+	"github.com/roasbeef/btcd/txscript"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+// selectConsolidation selects the coins to be consolidated at the passed fee
+// rate, expressed in sat/byte. Only coins worth at most maxValue which aren't
+// locked are eligible, and of those, only coins worth more than the fee to
+// spend them. Up to maxInputs of the smallest eligible coins are selected,
+// along with the fee of the consolidation transaction. If fewer than
+// minInputs coins are eligible, or the consolidated output would be dust,
+// then nil is returned.
+func selectConsolidation(feeRate uint64, maxValue btcutil.Amount, minInputs,
+	maxInputs int, coins []*Utxo,
+	locked map[wire.OutPoint]struct{}) ([]*Utxo, btcutil.Amount) {
+
+	spendFee := btcutil.Amount(p2wkhSpendSize * feeRate)
+
+	var eligible []*Utxo
+	for _, coin := range coins {
+		if _, ok := locked[coin.OutPoint]; ok {
+			continue
+		}
+		if coin.Value > maxValue || coin.Value <= spendFee {
+			continue
+		}
+
+		eligible = append(eligible, coin)
+	}
+	if len(eligible) < minInputs || len(eligible) == 0 {
+		return nil, 0
+	}
+
+	// The smallest coins are the most expensive to spend relative to
+	// their value, so they're consolidated first.
+	sort.Sort(utxosByValue(eligible))
+	if maxInputs != 0 && len(eligible) > maxInputs {
+		eligible = eligible[:maxInputs]
+	}
+
+	var total btcutil.Amount
+	for _, coin := range eligible {
+		total += coin.Value
+	}
+
+	estimatedSize := len(eligible)*p2wkhSpendSize + p2wkhOutputSize +
+		txOverhead
+	fee := btcutil.Amount(uint64(estimatedSize) * feeRate)
+	if total-fee < DefaultDustLimit() {
+		return nil, 0
+	}
+
+	return eligible, fee
+}
+
+// ConsolidateCoins sweeps the wallet's small confirmed outputs into a single
+// output paying to a fresh change address, at the passed fee rate expressed
+// in sat/byte, so funding future channels requires fewer inputs. Outputs
+// locked by pending channel reservations, or otherwise locked within the
+// wallet, are left untouched. See selectConsolidation for the outputs which
+// are swept. If no consolidation is warranted, then nil is returned.
+// Otherwise, the broadcast consolidation transaction is returned.
+func (l *LightningWallet) ConsolidateCoins(feeRate uint64,
+	maxValue btcutil.Amount, minInputs, maxInputs int) (*wire.MsgTx, error) {
+
+	// We hold the coin select mutex throughout, so the coins we sweep
+	// can't concurrently be selected to fund a channel.
+	l.coinSelectMtx.Lock()
+	defer l.coinSelectMtx.Unlock()
+
+	coins, err := l.ListUnspentWitness(1)
+	if err != nil {
+		return nil, err
+	}
+
+	l.limboMtx.RLock()
+	selected, fee := selectConsolidation(feeRate, maxValue, minInputs,
+		maxInputs, coins, l.lockedOutPoints)
+	l.limboMtx.RUnlock()
+	if selected == nil {
+		return nil, nil
+	}
+
+	changeAddr, err := l.NewAddress(WitnessPubKey, true)
+	if err != nil {
+		return nil, err
+	}
+	changeScript, err := txscript.PayToAddrScript(changeAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	tx := wire.NewMsgTx(1)
+	var total btcutil.Amount
+	for _, coin := range selected {
+		tx.AddTxIn(wire.NewTxIn(&coin.OutPoint, nil, nil))
+		total += coin.Value
+	}
+	tx.AddTxOut(&wire.TxOut{
+		Value:    int64(total - fee),
+		PkScript: changeScript,
+	})
+
+	signDesc := SignDescriptor{
+		HashType:  txscript.SigHashAll,
+		SigHashes: txscript.NewTxSigHashes(tx),
+	}
+	for i, txIn := range tx.TxIn {
+		info, err := l.FetchInputInfo(&txIn.PreviousOutPoint)
+		if err != nil {
+			return nil, err
+		}
+
+		signDesc.Output = info
+		signDesc.InputIndex = i
+
+		inputScript, err := l.Signer.ComputeInputScript(tx, &signDesc)
+		if err != nil {
+			return nil, err
+		}
+
+		txIn.SignatureScript = inputScript.ScriptSig
+		txIn.Witness = inputScript.Witness
+	}
+
+	walletLog.Infof("Consolidating %v outputs worth %v into %v, paying "+
+		"fee of %v", len(selected), total, tx.TxHash(), fee)
+
+	if err := l.PublishTransaction(tx); err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// utxosByValue implements sort.Interface to order coins from the smallest to
+// the largest value.
+type utxosByValue []*Utxo
+
+func (u utxosByValue) Len() int           { return len(u) }
+func (u utxosByValue) Swap(i, j int)      { u[i], u[j] = u[j], u[i] }
+func (u utxosByValue) Less(i, j int) bool { return u[i].Value < u[j].Value }
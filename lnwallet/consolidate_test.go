@@ -0,0 +1,59 @@
+package lnwallet
+
+import (
+	"testing"
+
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+// TestSelectConsolidation tests that only small, unlocked coins worth more
+// than the fee to spend them are consolidated, smallest first.
+func TestSelectConsolidation(t *testing.T) {
+	const feeRate = 10
+
+	coins := make([]*Utxo, 6)
+	values := []btcutil.Amount{50000, 800, 20000, 200000, 10000, 30000}
+	for i, value := range values {
+		coins[i] = &Utxo{
+			Value:    value,
+			OutPoint: wire.OutPoint{Index: uint32(i)},
+		}
+	}
+	locked := map[wire.OutPoint]struct{}{
+		coins[5].OutPoint: {},
+	}
+
+	// The coin worth 800 costs more to spend than it's worth, the coin
+	// worth 200000 is too large, and the coin worth 30000 is locked by a
+	// pending reservation. Of the remaining coins, the smallest two are
+	// selected.
+	selected, fee := selectConsolidation(feeRate, 100000, 2, 2, coins,
+		locked)
+	if len(selected) != 2 {
+		t.Fatalf("expected 2 coins selected, got %v", len(selected))
+	}
+	if selected[0] != coins[4] || selected[1] != coins[2] {
+		t.Fatalf("expected the smallest eligible coins to be selected")
+	}
+
+	expectedFee := btcutil.Amount((2*p2wkhSpendSize + p2wkhOutputSize +
+		txOverhead) * feeRate)
+	if fee != expectedFee {
+		t.Fatalf("expected fee of %v, got %v", expectedFee, fee)
+	}
+
+	// Without an input limit, all three eligible coins are selected.
+	selected, _ = selectConsolidation(feeRate, 100000, 2, 0, coins,
+		locked)
+	if len(selected) != 3 {
+		t.Fatalf("expected 3 coins selected, got %v", len(selected))
+	}
+
+	// Nothing is consolidated if too few coins are eligible.
+	selected, _ = selectConsolidation(feeRate, 100000, 4, 0, coins,
+		locked)
+	if selected != nil {
+		t.Fatalf("expected no coins to be selected")
+	}
+}
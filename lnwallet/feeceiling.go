@@ -0,0 +1,241 @@
+package lnwallet
+
+import (
+	"fmt"
+
+	"github.com/roasbeef/btcd/blockchain"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+// FeeCeiling bounds the fee paid by each transaction broadcast by the wallet,
+// so a bug within the fee calculation of one of the transactions we construct
+// can't burn the balance of a channel. A transaction exceeding either bound
+// is refused, rather than broadcast.
+type FeeCeiling struct {
+	// MaxFee is the largest absolute fee a transaction may pay. A value of
+	// zero places no limit on the absolute fee.
+	MaxFee btcutil.Amount
+
+	// MaxFeeRateMultiple is the largest multiple of the estimated fee rate
+	// a transaction may pay. A value of zero places no limit on the fee
+	// rate.
+	MaxFeeRateMultiple float64
+
+	// ConfTarget is the number of blocks within which a transaction is
+	// expected to confirm at the estimated fee rate the fee rate of each
+	// transaction is compared to.
+	ConfTarget uint32
+}
+
+// Validate checks that the ceiling's bounds are sane.
+func (c *FeeCeiling) Validate() error {
+	if c.MaxFee < 0 {
+		return fmt.Errorf("maximum fee of %v must not be negative",
+			c.MaxFee)
+	}
+	if c.MaxFeeRateMultiple < 0 {
+		return fmt.Errorf("maximum fee rate multiple of %v must not be "+
+			"negative", c.MaxFeeRateMultiple)
+	}
+	if c.MaxFeeRateMultiple != 0 && c.MaxFeeRateMultiple < 1 {
+		return fmt.Errorf("maximum fee rate multiple of %v would refuse "+
+			"transactions paying the estimated fee rate",
+			c.MaxFeeRateMultiple)
+	}
+	if c.MaxFeeRateMultiple != 0 && c.ConfTarget == 0 {
+		return fmt.Errorf("confirmation target must be positive")
+	}
+
+	return nil
+}
+
+// Check returns an error if a transaction of the passed virtual size paying
+// the passed fee exceeds the ceiling. The estimate is the fee rate in sat/byte
+// the transaction's fee rate is compared to, or zero if no estimate is
+// available, in which case only the absolute fee is checked.
+func (c *FeeCeiling) Check(fee btcutil.Amount, vsize int64,
+	estimate btcutil.Amount) error {
+
+	if c.MaxFee != 0 && fee > c.MaxFee {
+		return fmt.Errorf("fee of %v exceeds the maximum of %v", fee,
+			c.MaxFee)
+	}
+
+	if c.MaxFeeRateMultiple == 0 || estimate == 0 || vsize <= 0 {
+		return nil
+	}
+	feeRate := float64(fee) / float64(vsize)
+	if feeRate > c.MaxFeeRateMultiple*float64(estimate) {
+		return fmt.Errorf("fee rate of %.2f sat/byte exceeds %v times "+
+			"the estimate of %v sat/byte", feeRate,
+			c.MaxFeeRateMultiple, int64(estimate))
+	}
+
+	return nil
+}
+
+// TxFee returns the fee paid by the passed transaction. The outputs spent by
+// its inputs are first looked up within the wallet, then within the UTXO set
+// of the chain backend, and finally within the transactions known to it.
+func (l *LightningWallet) TxFee(tx *wire.MsgTx) (btcutil.Amount, error) {
+	var inputTotal btcutil.Amount
+	for _, txIn := range tx.TxIn {
+		prevOut, err := l.fetchPrevOutput(&txIn.PreviousOutPoint)
+		if err != nil {
+			return 0, fmt.Errorf("unable to find output %v spent by "+
+				"tx %v: %v", txIn.PreviousOutPoint, tx.TxHash(),
+				err)
+		}
+		inputTotal += btcutil.Amount(prevOut.Value)
+	}
+
+	var outputTotal btcutil.Amount
+	for _, txOut := range tx.TxOut {
+		outputTotal += btcutil.Amount(txOut.Value)
+	}
+
+	return inputTotal - outputTotal, nil
+}
+
+// fetchPrevOutput returns the output referenced by the passed outpoint.
+func (l *LightningWallet) fetchPrevOutput(
+	outPoint *wire.OutPoint) (*wire.TxOut, error) {
+
+	if txOut, err := l.FetchInputInfo(outPoint); err == nil {
+		return txOut, nil
+	}
+	if txOut, err := l.ChainIO.GetUtxo(&outPoint.Hash,
+		outPoint.Index); err == nil {
+
+		return txOut, nil
+	}
+
+	// The output may belong to a transaction which is yet to confirm, or
+	// may already have been spent, in which case the backend is only able
+	// to return the transaction itself.
+	prevTx, err := l.ChainIO.GetTransaction(&outPoint.Hash)
+	if err != nil {
+		return nil, err
+	}
+	if outPoint.Index >= uint32(len(prevTx.TxOut)) {
+		return nil, fmt.Errorf("output index %v out of range",
+			outPoint.Index)
+	}
+
+	return prevTx.TxOut[outPoint.Index], nil
+}
+
+// PublishTransaction checks the fee of the passed transaction against the
+// wallet's fee ceiling, if any, then broadcasts it. Should the fee exceed the
+// ceiling, the transaction is refused. If the fee can't be determined, as the
+// outputs spent by the transaction can't be found, the transaction is
+// broadcast regardless, as the chain backend would refuse the transaction
+// itself should its inputs be missing.
+//
+// NOTE: This shadows the PublishTransaction method of the WalletController,
+// so every transaction broadcast through the LightningWallet is checked.
+func (l *LightningWallet) PublishTransaction(tx *wire.MsgTx) error {
+	if err := l.checkFeeCeiling(tx); err != nil {
+		return err
+	}
+
+	return l.WalletController.PublishTransaction(tx)
+}
+
+// checkFeeCeiling checks the fee paid by the passed transactions together
+// against the wallet's fee ceiling, if any. Each transaction may spend the
+// outputs of those preceding it. If the fee can't be determined, then no
+// error is returned.
+func (l *LightningWallet) checkFeeCeiling(txns ...*wire.MsgTx) error {
+	if l.FeeCeiling == nil {
+		return nil
+	}
+
+	txid := txns[0].TxHash()
+	fee, err := l.packageFee(txns)
+	if err != nil {
+		walletLog.Warnf("Unable to check fee of tx %v against the fee "+
+			"ceiling: %v", txid, err)
+		return nil
+	}
+
+	// If no estimate is available, only the absolute fee is checked.
+	var estimate btcutil.Amount
+	if l.FeeCeiling.MaxFeeRateMultiple != 0 {
+		estimate, err = l.EstimateFeePerByte(l.FeeCeiling.ConfTarget)
+		if err != nil {
+			walletLog.Warnf("Unable to estimate fee rate to check tx "+
+				"%v against: %v", txid, err)
+			estimate = 0
+		}
+	}
+
+	var vsize int64
+	for _, tx := range txns {
+		weight := blockchain.GetTransactionWeight(btcutil.NewTx(tx))
+		vsize += (weight + blockchain.WitnessScaleFactor - 1) /
+			blockchain.WitnessScaleFactor
+	}
+	if err := l.FeeCeiling.Check(fee, vsize, estimate); err != nil {
+		walletLog.Errorf("Refusing to broadcast tx %v: %v", txid, err)
+		return fmt.Errorf("refusing to broadcast tx %v: %v", txid, err)
+	}
+
+	return nil
+}
+
+// packageFee returns the fee paid by the passed transactions together, where
+// each may spend the outputs of those preceding it.
+func (l *LightningWallet) packageFee(txns []*wire.MsgTx) (btcutil.Amount,
+	error) {
+
+	var fee btcutil.Amount
+	for i, tx := range txns {
+		// Outputs created within the package are taken from the
+		// transaction creating them, as it may be yet to reach the
+		// chain backend.
+		var inputTotal btcutil.Amount
+		for _, txIn := range tx.TxIn {
+			prevOut := packageOutput(txns[:i], &txIn.PreviousOutPoint)
+			if prevOut == nil {
+				var err error
+				prevOut, err = l.fetchPrevOutput(
+					&txIn.PreviousOutPoint)
+				if err != nil {
+					return 0, fmt.Errorf("unable to find "+
+						"output %v spent by tx %v: %v",
+						txIn.PreviousOutPoint,
+						tx.TxHash(), err)
+				}
+			}
+			inputTotal += btcutil.Amount(prevOut.Value)
+		}
+
+		var outputTotal btcutil.Amount
+		for _, txOut := range tx.TxOut {
+			outputTotal += btcutil.Amount(txOut.Value)
+		}
+
+		fee += inputTotal - outputTotal
+	}
+
+	return fee, nil
+}
+
+// packageOutput returns the output referenced by the passed outpoint if it's
+// created by one of the passed transactions, and nil otherwise.
+func packageOutput(txns []*wire.MsgTx, outPoint *wire.OutPoint) *wire.TxOut {
+	for _, tx := range txns {
+		if tx.TxHash() != outPoint.Hash {
+			continue
+		}
+		if outPoint.Index >= uint32(len(tx.TxOut)) {
+			return nil
+		}
+
+		return tx.TxOut[outPoint.Index]
+	}
+
+	return nil
+}
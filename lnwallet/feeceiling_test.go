@@ -0,0 +1,93 @@
+package lnwallet
+
+import (
+	"testing"
+
+	"github.com/roasbeef/btcutil"
+)
+
+// TestFeeCeilingValidate tests that only sane fee ceilings are accepted.
+func TestFeeCeilingValidate(t *testing.T) {
+	tests := []struct {
+		ceiling FeeCeiling
+		valid   bool
+	}{
+		// A ceiling without bounds checks nothing, but is valid.
+		{FeeCeiling{}, true},
+		{FeeCeiling{MaxFee: 500000}, true},
+		{FeeCeiling{MaxFeeRateMultiple: 10, ConfTarget: 6}, true},
+
+		// Neither bound may be negative.
+		{FeeCeiling{MaxFee: -1}, false},
+		{FeeCeiling{MaxFeeRateMultiple: -1, ConfTarget: 6}, false},
+
+		// A multiple below one refuses transactions paying exactly
+		// the estimate.
+		{FeeCeiling{MaxFeeRateMultiple: 0.5, ConfTarget: 6}, false},
+
+		// The fee rate can't be estimated without a target.
+		{FeeCeiling{MaxFeeRateMultiple: 10}, false},
+	}
+
+	for i, test := range tests {
+		err := test.ceiling.Validate()
+		if test.valid && err != nil {
+			t.Fatalf("test #%v: expected ceiling to be valid: %v",
+				i, err)
+		}
+		if !test.valid && err == nil {
+			t.Fatalf("test #%v: expected ceiling to be invalid", i)
+		}
+	}
+}
+
+// TestFeeCeilingCheck tests that transactions are refused once either their
+// absolute fee or their fee rate exceeds the ceiling, and that the fee rate
+// is only checked if an estimate is available.
+func TestFeeCeilingCheck(t *testing.T) {
+	ceiling := &FeeCeiling{
+		MaxFee:             10000,
+		MaxFeeRateMultiple: 10,
+		ConfTarget:         6,
+	}
+
+	tests := []struct {
+		fee      btcutil.Amount
+		vsize    int64
+		estimate btcutil.Amount
+		valid    bool
+	}{
+		// A fee at the estimate is accepted.
+		{200, 200, 1, true},
+
+		// As is a fee rate of exactly ten times the estimate, but not
+		// above it.
+		{2000, 200, 1, true},
+		{2001, 200, 1, false},
+
+		// The absolute fee is bounded even if the fee rate is within
+		// the ceiling.
+		{10000, 1000, 10, true},
+		{10001, 1000, 20, false},
+
+		// Without an estimate, only the absolute fee is checked.
+		{5000, 200, 0, true},
+		{10001, 200, 0, false},
+	}
+
+	for i, test := range tests {
+		err := ceiling.Check(test.fee, test.vsize, test.estimate)
+		if test.valid && err != nil {
+			t.Fatalf("test #%v: expected fee to be accepted: %v",
+				i, err)
+		}
+		if !test.valid && err == nil {
+			t.Fatalf("test #%v: expected fee to be refused", i)
+		}
+	}
+
+	// A ceiling without bounds accepts any fee.
+	if err := (&FeeCeiling{}).Check(1e8, 200, 1); err != nil {
+		t.Fatalf("expected unbounded ceiling to accept fee: %v", err)
+	}
+}
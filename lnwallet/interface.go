@@ -16,6 +16,11 @@ import (
 // to spend a specifid output.
 var ErrNotMine = errors.New("the passed output doesn't belong to the wallet")
 
+// ErrNoFeeEstimate is an error denoting that a WalletController instance is
+// unable to estimate the current fee rate, such as when its chain backend
+// hasn't yet seen enough transactions confirm.
+var ErrNoFeeEstimate = errors.New("no fee estimate available")
+
 // AddressType is a enum-like type which denotes the possible address types
 // WalletController supports.
 type AddressType uint8
@@ -142,6 +147,14 @@ type WalletController interface {
 	// WalletController restarts.
 	FetchRootKey() (*btcec.PrivateKey, error)
 
+	// ImportPrivateKey imports the passed private key into the wallet,
+	// allowing it to be used to sign for channels established by other
+	// wallets. Importing a key already known to the wallet is a no-op.
+	//
+	// NOTE: The wallet MUST be able to locate the imported key by its
+	// public key when signing, as with keys returned by NewRawKey.
+	ImportPrivateKey(key *btcec.PrivateKey) error
+
 	// SendOutputs funds, signs, and broadcasts a Bitcoin transaction
 	// paying out to the specified outputs. In the case the wallet has
 	// insufficient funds, or the outputs are non-standard, an error
@@ -173,6 +186,12 @@ type WalletController interface {
 	// then finally broadcasts the passed transaction to the Bitcoin network.
 	PublishTransaction(tx *wire.MsgTx) error
 
+	// EstimateFeePerByte returns the fee rate in satoshis per byte
+	// expected to confirm a transaction within the passed number of
+	// blocks. If no estimate is available, then ErrNoFeeEstimate should
+	// be returned.
+	EstimateFeePerByte(numBlocks uint32) (btcutil.Amount, error)
+
 	// SubscribeTransactions returns a TransactionSubscription client which
 	// is capable of receiving async notifications as new transactions
 	// related to the wallet are seen within the network, or found in
@@ -287,6 +306,16 @@ type Signer interface {
 	ComputeInputScript(tx *wire.MsgTx, signDesc *SignDescriptor) (*InputScript, error)
 }
 
+// FundingKeyRing is an optional source of the multi-sig keys used within the
+// funding outputs of new channels. It allows funding keys to be held outside
+// of the wallet, for example by a hardware device. Signatures for keys handed
+// out by a FundingKeyRing must be obtainable via the wallet's Signer.
+type FundingKeyRing interface {
+	// NewFundingKey returns a fresh public key to be used as our key
+	// within the 2-of-2 multi-sig funding output of a new channel.
+	NewFundingKey() (*btcec.PublicKey, error)
+}
+
 // WalletDriver represents a "driver" for a particular concrete
 // WalletController implementation. A driver is identified by a globally unique
 // string identifier along with a 'New()' method which is responsible for
@@ -0,0 +1,57 @@
+package lnwallet
+
+import (
+	"fmt"
+
+	"github.com/roasbeef/btcd/wire"
+)
+
+// PackagePublisher is implemented by a WalletController whose chain backend
+// accepts a child along with its unconfirmed parent as a package, evaluating
+// the fee of the two together. Should the parent fall short of the mempool's
+// minimum fee on its own, it's only accepted as part of such a package.
+type PackagePublisher interface {
+	// PublishPackage broadcasts the passed parent and child to the
+	// Bitcoin network as a package, which is either accepted or refused
+	// as a whole.
+	PublishPackage(parent, child *wire.MsgTx) error
+}
+
+// ErrChildRejected is returned by PublishPackage if the parent of a package
+// has been broadcast, but its child was refused.
+type ErrChildRejected struct {
+	// Err is the reason the child was refused.
+	Err error
+}
+
+// Error returns a human readable description of the refusal.
+func (e *ErrChildRejected) Error() string {
+	return fmt.Sprintf("child refused after broadcasting its parent: %v",
+		e.Err)
+}
+
+// PublishPackage broadcasts the passed parent along with a child spending one
+// of its outputs, so the child's fee counts towards the parent's. The fee of
+// the two together is checked against the wallet's fee ceiling, if any. If
+// the WalletController is a PackagePublisher, then the two are submitted as a
+// package. Otherwise, the parent is broadcast first, followed by the child,
+// so the child is never broadcast without its parent. Should the parent be
+// broadcast, but the child be refused, then an ErrChildRejected is returned.
+func (l *LightningWallet) PublishPackage(parent, child *wire.MsgTx) error {
+	if err := l.checkFeeCeiling(parent, child); err != nil {
+		return err
+	}
+
+	if publisher, ok := l.WalletController.(PackagePublisher); ok {
+		return publisher.PublishPackage(parent, child)
+	}
+
+	if err := l.WalletController.PublishTransaction(parent); err != nil {
+		return err
+	}
+	if err := l.WalletController.PublishTransaction(child); err != nil {
+		return &ErrChildRejected{Err: err}
+	}
+
+	return nil
+}
@@ -0,0 +1,194 @@
+package lnwallet
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// mockPublisher is a WalletController which records the transactions it
+// broadcasts, refusing those it has been told to. Only the methods needed to
+// publish are implemented.
+type mockPublisher struct {
+	WalletController
+
+	outputs   map[wire.OutPoint]*wire.TxOut
+	refused   map[chainhash.Hash]bool
+	published []chainhash.Hash
+}
+
+func (m *mockPublisher) FetchInputInfo(
+	prevOut *wire.OutPoint) (*wire.TxOut, error) {
+
+	txOut, ok := m.outputs[*prevOut]
+	if !ok {
+		return nil, fmt.Errorf("output %v not found", prevOut)
+	}
+
+	return txOut, nil
+}
+
+func (m *mockPublisher) PublishTransaction(tx *wire.MsgTx) error {
+	txid := tx.TxHash()
+	if m.refused[txid] {
+		return fmt.Errorf("tx %v refused", txid)
+	}
+
+	m.published = append(m.published, txid)
+	return nil
+}
+
+// mockPackagePublisher is a mockPublisher whose backend accepts packages.
+type mockPackagePublisher struct {
+	mockPublisher
+
+	packages [][2]chainhash.Hash
+}
+
+func (m *mockPackagePublisher) PublishPackage(parent,
+	child *wire.MsgTx) error {
+
+	m.packages = append(m.packages,
+		[2]chainhash.Hash{parent.TxHash(), child.TxHash()})
+	return nil
+}
+
+// newTestPackage returns a parent spending a wallet output of 100,000
+// satoshis with a fee of 10,000, along with a child spending the parent's
+// output and another wallet output of 50,000 satoshis with a fee of 10,000.
+func newTestPackage() (*wire.MsgTx, *wire.MsgTx, map[wire.OutPoint]*wire.TxOut) {
+	parentOutPoint := wire.OutPoint{Hash: chainhash.Hash{1}}
+	walletOutPoint := wire.OutPoint{Hash: chainhash.Hash{2}}
+	outputs := map[wire.OutPoint]*wire.TxOut{
+		parentOutPoint: {Value: 100000},
+		walletOutPoint: {Value: 50000},
+	}
+
+	parent := wire.NewMsgTx(2)
+	parent.AddTxIn(wire.NewTxIn(&parentOutPoint, nil, nil))
+	parent.AddTxOut(&wire.TxOut{Value: 90000})
+
+	child := wire.NewMsgTx(2)
+	child.AddTxIn(wire.NewTxIn(
+		&wire.OutPoint{Hash: parent.TxHash()}, nil, nil))
+	child.AddTxIn(wire.NewTxIn(&walletOutPoint, nil, nil))
+	child.AddTxOut(&wire.TxOut{Value: 130000})
+
+	return parent, child, outputs
+}
+
+// TestPublishPackage tests that a parent and its child are submitted as a
+// package where the backend supports it, and are otherwise broadcast parent
+// first, with the child only broadcast along with its parent.
+func TestPublishPackage(t *testing.T) {
+	parent, child, outputs := newTestPackage()
+	parentTxid, childTxid := parent.TxHash(), child.TxHash()
+
+	// Without package support, the parent is broadcast first.
+	publisher := &mockPublisher{outputs: outputs}
+	wallet := &LightningWallet{WalletController: publisher}
+	if err := wallet.PublishPackage(parent, child); err != nil {
+		t.Fatalf("unable to publish package: %v", err)
+	}
+	if len(publisher.published) != 2 ||
+		publisher.published[0] != parentTxid ||
+		publisher.published[1] != childTxid {
+
+		t.Fatalf("expected parent %v then child %v to be broadcast, "+
+			"instead got %v", parentTxid, childTxid,
+			publisher.published)
+	}
+
+	// Should the parent be refused, the child isn't broadcast at all.
+	publisher = &mockPublisher{
+		outputs: outputs,
+		refused: map[chainhash.Hash]bool{parentTxid: true},
+	}
+	wallet = &LightningWallet{WalletController: publisher}
+	err := wallet.PublishPackage(parent, child)
+	if err == nil {
+		t.Fatalf("expected package with refused parent to fail")
+	}
+	if _, ok := err.(*ErrChildRejected); ok {
+		t.Fatalf("expected refusal of the parent, instead got %v", err)
+	}
+	if len(publisher.published) != 0 {
+		t.Fatalf("expected nothing to be broadcast, instead got %v",
+			publisher.published)
+	}
+
+	// Should only the child be refused, the caller learns the parent was
+	// broadcast.
+	publisher = &mockPublisher{
+		outputs: outputs,
+		refused: map[chainhash.Hash]bool{childTxid: true},
+	}
+	wallet = &LightningWallet{WalletController: publisher}
+	err = wallet.PublishPackage(parent, child)
+	if _, ok := err.(*ErrChildRejected); !ok {
+		t.Fatalf("expected refusal of the child, instead got %v", err)
+	}
+	if len(publisher.published) != 1 ||
+		publisher.published[0] != parentTxid {
+
+		t.Fatalf("expected only parent %v to be broadcast, instead "+
+			"got %v", parentTxid, publisher.published)
+	}
+
+	// With package support, the two are submitted together, and never
+	// individually.
+	packagePublisher := &mockPackagePublisher{
+		mockPublisher: mockPublisher{outputs: outputs},
+	}
+	wallet = &LightningWallet{WalletController: packagePublisher}
+	if err := wallet.PublishPackage(parent, child); err != nil {
+		t.Fatalf("unable to publish package: %v", err)
+	}
+	if len(packagePublisher.packages) != 1 ||
+		packagePublisher.packages[0][0] != parentTxid ||
+		packagePublisher.packages[0][1] != childTxid {
+
+		t.Fatalf("expected package of parent %v and child %v, "+
+			"instead got %v", parentTxid, childTxid,
+			packagePublisher.packages)
+	}
+	if len(packagePublisher.published) != 0 {
+		t.Fatalf("expected no individual broadcasts, instead got %v",
+			packagePublisher.published)
+	}
+}
+
+// TestPublishPackageFeeCeiling tests that the fee of a package is checked
+// against the fee ceiling as a whole, with the output of the parent spent by
+// the child found within the package itself.
+func TestPublishPackageFeeCeiling(t *testing.T) {
+	parent, child, outputs := newTestPackage()
+
+	// The two pay a fee of 20,000 together, which exceeds the ceiling
+	// although neither does on its own.
+	publisher := &mockPublisher{outputs: outputs}
+	wallet := &LightningWallet{
+		WalletController: publisher,
+		FeeCeiling:       &FeeCeiling{MaxFee: 15000},
+	}
+	if err := wallet.PublishPackage(parent, child); err == nil {
+		t.Fatalf("expected package exceeding the fee ceiling to be " +
+			"refused")
+	}
+	if len(publisher.published) != 0 {
+		t.Fatalf("expected nothing to be broadcast, instead got %v",
+			publisher.published)
+	}
+
+	wallet.FeeCeiling.MaxFee = 20000
+	if err := wallet.PublishPackage(parent, child); err != nil {
+		t.Fatalf("unable to publish package within the fee ceiling: "+
+			"%v", err)
+	}
+	if len(publisher.published) != 2 {
+		t.Fatalf("expected package to be broadcast, instead got %v",
+			publisher.published)
+	}
+}
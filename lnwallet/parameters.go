@@ -1,6 +1,8 @@
 package lnwallet
 
 import (
+	"fmt"
+
 	"github.com/roasbeef/btcutil"
 	"github.com/roasbeef/btcwallet/wallet/txrules"
 )
@@ -10,3 +12,163 @@ import (
 func DefaultDustLimit() btcutil.Amount {
 	return txrules.GetDustThreshold(P2WSHSize, txrules.DefaultRelayFeePerKb)
 }
+
+// MaxDustLimit is the largest dust limit we'll accept from the remote party of
+// a channel. HTLCs below the dust limit of a commitment transaction are
+// trimmed into its fee, so an excessive limit would allow the remote party to
+// have much of the value pending within the channel burned to miners should
+// the channel be force closed.
+const MaxDustLimit = btcutil.Amount(20000)
+
+// ValidateDustLimit checks that the dust limit proposed by the remote party of
+// a channel lies within the range we accept. A limit below the network's dust
+// threshold would allow their commitment transaction to create outputs which
+// can't be relayed, leaving it unable to confirm.
+func ValidateDustLimit(dustLimit btcutil.Amount) error {
+	minDustLimit := DefaultDustLimit()
+	switch {
+	case dustLimit < minDustLimit:
+		return fmt.Errorf("dust limit of %v is below the network dust "+
+			"threshold of %v", dustLimit, minDustLimit)
+
+	case dustLimit > MaxDustLimit:
+		return fmt.Errorf("dust limit of %v exceeds the maximum of %v",
+			dustLimit, MaxDustLimit)
+	}
+
+	return nil
+}
+
+// MaxCsvDelay is the largest relative time lock we'll accept on the
+// pay-to-self outputs of a channel's commitment transactions. A larger delay
+// would leave our funds locked for an excessive period should the channel be
+// force closed.
+const MaxCsvDelay = 2016
+
+// ValidateCsvDelay checks that the CSV delay proposed by the remote party of a
+// channel lies within the range we accept.
+func ValidateCsvDelay(csvDelay uint32) error {
+	if csvDelay == 0 || csvDelay > MaxCsvDelay {
+		return fmt.Errorf("csv delay of %v is outside the range "+
+			"[1, %v]", csvDelay, MaxCsvDelay)
+	}
+
+	return nil
+}
+
+// CsvDelayPolicy determines the CSV delay of a new channel from its
+// capacity. Larger channels are given longer delays, as more funds are lost
+// should we fail to respond to a breach in time, while smaller channels avoid
+// locking up funds for longer than their value warrants.
+type CsvDelayPolicy struct {
+	// MinDelay is the delay given to the smallest channels.
+	MinDelay uint32
+
+	// MinRemoteDelay is the shortest delay we accept from the remote
+	// party of any channel. The delays scaled by capacity only apply to
+	// the channels we open, so peers which propose a fixed delay remain
+	// able to open channels with us.
+	MinRemoteDelay uint32
+
+	// MaxDelay is the delay given to channels with a capacity of at least
+	// FullDelayCapacity, and the longest delay we accept from the remote
+	// party of any channel.
+	MaxDelay uint32
+
+	// FullDelayCapacity is the capacity from which channels are given
+	// MaxDelay. The delay of smaller channels is scaled linearly between
+	// MinDelay and MaxDelay.
+	FullDelayCapacity btcutil.Amount
+}
+
+// Validate checks that the policy's bounds are consistent with one another,
+// and with the range of delays we accept.
+func (p *CsvDelayPolicy) Validate() error {
+	if err := ValidateCsvDelay(p.MinDelay); err != nil {
+		return fmt.Errorf("invalid minimum delay: %v", err)
+	}
+	if err := ValidateCsvDelay(p.MaxDelay); err != nil {
+		return fmt.Errorf("invalid maximum delay: %v", err)
+	}
+	if err := ValidateCsvDelay(p.MinRemoteDelay); err != nil {
+		return fmt.Errorf("invalid minimum remote delay: %v", err)
+	}
+	if p.MinDelay > p.MaxDelay {
+		return fmt.Errorf("minimum delay of %v exceeds the maximum "+
+			"of %v", p.MinDelay, p.MaxDelay)
+	}
+	if p.MinRemoteDelay > p.MaxDelay {
+		return fmt.Errorf("minimum remote delay of %v exceeds the "+
+			"maximum of %v", p.MinRemoteDelay, p.MaxDelay)
+	}
+	if p.FullDelayCapacity <= 0 {
+		return fmt.Errorf("full delay capacity of %v must be positive",
+			p.FullDelayCapacity)
+	}
+
+	return nil
+}
+
+// DelayForCapacity returns the CSV delay of a new channel with the passed
+// capacity.
+func (p *CsvDelayPolicy) DelayForCapacity(capacity btcutil.Amount) uint32 {
+	switch {
+	case capacity <= 0:
+		return p.MinDelay
+
+	case capacity >= p.FullDelayCapacity:
+		return p.MaxDelay
+	}
+
+	scaled := uint64(p.MaxDelay-p.MinDelay) * uint64(capacity) /
+		uint64(p.FullDelayCapacity)
+
+	return p.MinDelay + uint32(scaled)
+}
+
+// ValidateDelay checks that the CSV delay proposed by the remote party of a
+// channel is within our policy. The delay can't be shorter than our minimum
+// remote delay, nor can it exceed our maximum delay. Unlike the delays we
+// propose, it isn't scaled by the channel's capacity, as peers which predate
+// the scaling always propose the same delay.
+func (p *CsvDelayPolicy) ValidateDelay(csvDelay uint32) error {
+	if csvDelay < p.MinRemoteDelay || csvDelay > p.MaxDelay {
+		return fmt.Errorf("csv delay of %v is outside the range "+
+			"[%v, %v]", csvDelay, p.MinRemoteDelay, p.MaxDelay)
+	}
+
+	return nil
+}
+
+// MaxChanReserve returns the largest channel reserve we'll accept for a
+// channel of the passed capacity. A larger reserve would leave much of the
+// channel's capacity unusable for payments.
+func MaxChanReserve(capacity btcutil.Amount) btcutil.Amount {
+	return capacity / 5
+}
+
+// ValidateFundingParams checks that the parameters of a channel proposed by
+// the initiator of a funding workflow are ones we're willing to accept.
+func ValidateFundingParams(capacity, pushAmt, chanReserve btcutil.Amount,
+	csvDelay uint32) error {
+
+	if capacity <= 0 {
+		return fmt.Errorf("channel capacity of %v must be positive",
+			capacity)
+	}
+	if pushAmt < 0 || pushAmt > capacity {
+		return fmt.Errorf("push amount of %v exceeds the channel "+
+			"capacity of %v", pushAmt, capacity)
+	}
+	if err := ValidateCsvDelay(csvDelay); err != nil {
+		return err
+	}
+
+	maxReserve := MaxChanReserve(capacity)
+	if chanReserve < 0 || chanReserve > maxReserve {
+		return fmt.Errorf("channel reserve of %v exceeds the maximum "+
+			"of %v", chanReserve, maxReserve)
+	}
+
+	return nil
+}
@@ -0,0 +1,156 @@
+package lnwallet
+
+import (
+	"testing"
+
+	"github.com/roasbeef/btcutil"
+)
+
+// TestValidateFundingParams tests that only the channel parameters within our
+// policy are accepted from the initiator of a funding workflow.
+func TestValidateFundingParams(t *testing.T) {
+	tests := []struct {
+		capacity    btcutil.Amount
+		pushAmt     btcutil.Amount
+		chanReserve btcutil.Amount
+		csvDelay    uint32
+		valid       bool
+	}{
+		{100000, 0, 0, 4, true},
+		{100000, 100000, 20000, MaxCsvDelay, true},
+
+		// The channel must have a capacity.
+		{0, 0, 0, 4, false},
+
+		// The initiator can't push more than the channel's capacity.
+		{100000, 100001, 0, 4, false},
+
+		// The CSV delay must be non-zero, and no larger than our
+		// maximum.
+		{100000, 0, 0, 0, false},
+		{100000, 0, 0, MaxCsvDelay + 1, false},
+
+		// The reserve can't exceed a fifth of the channel's capacity.
+		{100000, 0, 20001, 4, false},
+	}
+
+	for i, test := range tests {
+		err := ValidateFundingParams(test.capacity, test.pushAmt,
+			test.chanReserve, test.csvDelay)
+		if test.valid && err != nil {
+			t.Fatalf("test #%v: expected params to be accepted: %v",
+				i, err)
+		}
+		if !test.valid && err == nil {
+			t.Fatalf("test #%v: expected params to be rejected", i)
+		}
+	}
+}
+
+// TestValidateDustLimit tests that only the dust limits between the default
+// dust threshold and our maximum are accepted from the remote party.
+func TestValidateDustLimit(t *testing.T) {
+	tests := []struct {
+		dustLimit btcutil.Amount
+		valid     bool
+	}{
+		{DefaultDustLimit(), true},
+		{DefaultDustLimit() + 1, true},
+		{MaxDustLimit - 1, true},
+		{MaxDustLimit, true},
+
+		// The dust limit can't be below the default dust threshold, as
+		// the commitment could then carry outputs which won't relay.
+		{0, false},
+		{DefaultDustLimit() - 1, false},
+
+		// Nor can it exceed our maximum, which would let the remote
+		// party trim large HTLCs into the fee.
+		{MaxDustLimit + 1, false},
+	}
+
+	for i, test := range tests {
+		err := ValidateDustLimit(test.dustLimit)
+		if test.valid && err != nil {
+			t.Fatalf("test #%v: expected dust limit of %v to be "+
+				"accepted: %v", i, test.dustLimit, err)
+		}
+		if !test.valid && err == nil {
+			t.Fatalf("test #%v: expected dust limit of %v to be "+
+				"rejected", i, test.dustLimit)
+		}
+	}
+}
+
+// TestCsvDelayPolicy tests that the CSV delay of a channel is scaled with its
+// capacity between the policy's bounds, and that only the delays within our
+// policy are accepted from the remote party.
+func TestCsvDelayPolicy(t *testing.T) {
+	policy := &CsvDelayPolicy{
+		MinDelay:          144,
+		MaxDelay:          1008,
+		FullDelayCapacity: 1000000,
+		MinRemoteDelay:    4,
+	}
+	if err := policy.Validate(); err != nil {
+		t.Fatalf("expected policy to be valid: %v", err)
+	}
+
+	delayTests := []struct {
+		capacity btcutil.Amount
+		delay    uint32
+	}{
+		{0, 144},
+		{100000, 230},
+		{500000, 576},
+		{1000000, 1008},
+		{5000000, 1008},
+	}
+	for _, test := range delayTests {
+		delay := policy.DelayForCapacity(test.capacity)
+		if delay != test.delay {
+			t.Fatalf("expected delay of %v for capacity %v, got %v",
+				test.delay, test.capacity, delay)
+		}
+	}
+
+	// The delays proposed by the remote party aren't scaled by capacity,
+	// so the fixed delay proposed by peers predating the scaling is
+	// accepted.
+	validateTests := []struct {
+		csvDelay uint32
+		valid    bool
+	}{
+		{4, true},
+		{576, true},
+		{1008, true},
+
+		// The delay can't be shorter than our minimum remote delay,
+		// nor longer than our maximum.
+		{3, false},
+		{1009, false},
+	}
+	for i, test := range validateTests {
+		err := policy.ValidateDelay(test.csvDelay)
+		if test.valid && err != nil {
+			t.Fatalf("test #%v: expected delay to be accepted: %v",
+				i, err)
+		}
+		if !test.valid && err == nil {
+			t.Fatalf("test #%v: expected delay to be rejected", i)
+		}
+	}
+
+	// A policy accepting remote delays above its maximum is invalid, as
+	// is one with inverted bounds.
+	policy.MinRemoteDelay = policy.MaxDelay + 1
+	if err := policy.Validate(); err == nil {
+		t.Fatalf("expected policy with minimum remote delay above " +
+			"its maximum to be invalid")
+	}
+	policy.MinRemoteDelay = 4
+	policy.MinDelay, policy.MaxDelay = policy.MaxDelay, policy.MinDelay
+	if err := policy.Validate(); err == nil {
+		t.Fatalf("expected policy with inverted bounds to be invalid")
+	}
+}
@@ -1,11 +1,13 @@
 package lnwallet
 
 import (
+	"fmt"
 	"net"
 	"sync"
 
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/txscript"
 	"github.com/roasbeef/btcd/wire"
 	"github.com/roasbeef/btcutil"
 )
@@ -126,6 +128,11 @@ type ChannelReservation struct {
 	// commitment state.
 	pushSat btcutil.Amount
 
+	// theirFundingAmt is the amount the remote party has agreed to
+	// contribute to the funding transaction of a dual funder channel. The
+	// inputs they present must be sufficient to cover it.
+	theirFundingAmt btcutil.Amount
+
 	// chanOpen houses a struct containing the channel and additional
 	// confirmation details will be sent on once the channel is considered
 	// 'open'. A channel is open once the funding transaction has reached a
@@ -208,6 +215,7 @@ func NewChannelReservation(capacity, fundingAmt btcutil.Amount, minFeeRate btcut
 			ChanType:     chanType,
 			OurBalance:   ourBalance,
 			TheirBalance: theirBalance,
+			CommitFee:    capacity - ourBalance - theirBalance,
 			MinFeePerKb:  minFeeRate,
 			Db:           wallet.ChannelDB,
 		},
@@ -220,6 +228,74 @@ func NewChannelReservation(capacity, fundingAmt btcutil.Amount, minFeeRate btcut
 	}
 }
 
+// dualFunderCommitFees splits the fee of the initial commitment transaction
+// of a dual funded channel between the initiator and the responder, in
+// proportion to the amount each contributed to the channel. The initiator's
+// share is rounded down, with the responder paying the remainder, so both
+// parties arrive at identical balances.
+func dualFunderCommitFees(initiatorAmt,
+	responderAmt btcutil.Amount) (btcutil.Amount, btcutil.Amount) {
+
+	// The product is computed over unsigned integers, as it may exceed
+	// the range of an int64 for sufficiently large contributions.
+	capacity := uint64(initiatorAmt + responderAmt)
+	initiatorFee := btcutil.Amount(
+		uint64(commitFee) * uint64(initiatorAmt) / capacity,
+	)
+
+	return initiatorFee, commitFee - initiatorFee
+}
+
+// newDualChannelReservation creates a new reservation for a channel to which
+// both parties contribute funds. The capacity of the channel is the sum of
+// both contributions, and each party's initial balance is their contribution
+// less their share of the commitment fee. This function is used only
+// internally by lnwallet, via the lnwallet.InitDualChannelReservation
+// interface.
+func newDualChannelReservation(ourFundAmt, theirFundAmt btcutil.Amount,
+	initiator bool, minFeeRate btcutil.Amount, wallet *LightningWallet,
+	id uint64, numConfs uint16) *ChannelReservation {
+
+	var ourFee, theirFee btcutil.Amount
+	if initiator {
+		ourFee, theirFee = dualFunderCommitFees(ourFundAmt, theirFundAmt)
+	} else {
+		theirFee, ourFee = dualFunderCommitFees(theirFundAmt, ourFundAmt)
+	}
+
+	capacity := ourFundAmt + theirFundAmt
+	ourBalance := ourFundAmt - ourFee
+	theirBalance := theirFundAmt - theirFee
+
+	// As with dual funder channels created via NewChannelReservation,
+	// neither side is marked as the initiator, so no state hint obsfucator
+	// is derived for the channel.
+	return &ChannelReservation{
+		ourContribution: &ChannelContribution{
+			FundingAmount: ourBalance,
+		},
+		theirContribution: &ChannelContribution{
+			FundingAmount: theirBalance,
+		},
+		partialState: &channeldb.OpenChannel{
+			Capacity:     capacity,
+			IsPending:    true,
+			ChanType:     channeldb.DualFunder,
+			OurBalance:   ourBalance,
+			TheirBalance: theirBalance,
+			CommitFee:    capacity - ourBalance - theirBalance,
+			MinFeePerKb:  minFeeRate,
+			Db:           wallet.ChannelDB,
+		},
+		numConfsToOpen:  numConfs,
+		theirFundingAmt: theirFundAmt,
+		reservationID:   id,
+		chanOpen:        make(chan *openChanDetails, 1),
+		chanOpenErr:     make(chan error, 1),
+		wallet:          wallet,
+	}
+}
+
 // OurContribution returns the wallet's fully populated contribution to the
 // pending payment channel. See 'ChannelContribution' for further details
 // regarding the contents of a contribution.
@@ -254,6 +330,10 @@ func (r *ChannelReservation) ProcessContribution(theirContribution *ChannelContr
 // to this pending single funder channel. Internally, no further action is
 // taken other than recording the initiator's contribution to the single funder
 // channel.
+//
+// NOTE: The responder to a dual funder workflow calls this method with the
+// initiator's partial contribution in order to populate our revocation key.
+// The full contribution is later processed via ProcessContribution.
 func (r *ChannelReservation) ProcessSingleContribution(theirContribution *ChannelContribution) error {
 	errChan := make(chan error, 1)
 
@@ -317,7 +397,13 @@ func (r *ChannelReservation) CompleteReservation(fundingInputScripts []*InputScr
 		err:                      errChan,
 	}
 
-	return <-completeChan, <-errChan
+	// The completed channel is only sent upon success, so the error must
+	// be checked first in order to avoid blocking forever on failure.
+	if err := <-errChan; err != nil {
+		return nil, err
+	}
+
+	return <-completeChan, nil
 }
 
 // CompleteReservationSingle finalizes the pending single funder channel
@@ -346,7 +432,11 @@ func (r *ChannelReservation) CompleteReservationSingle(
 		err:                errChan,
 	}
 
-	return <-completeChan, <-errChan
+	if err := <-errChan; err != nil {
+		return nil, err
+	}
+
+	return <-completeChan, nil
 }
 
 // TheirSignatures returns the counterparty's signatures to all inputs to the
@@ -401,6 +491,83 @@ func (r *ChannelReservation) SetTheirDustLimit(dustLimit btcutil.Amount) {
 	r.partialState.TheirDustLimit = dustLimit
 }
 
+// SetOurDeliveryAddress overrides the address our balance of the channel is
+// delivered to upon a cooperative close, which is otherwise a fresh address
+// within the wallet. This MUST be called before our contribution is sent to
+// the remote party.
+func (r *ChannelReservation) SetOurDeliveryAddress(addr btcutil.Address) error {
+	r.Lock()
+	defer r.Unlock()
+
+	deliveryScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		return err
+	}
+
+	r.partialState.OurDeliveryScript = deliveryScript
+	r.ourContribution.DeliveryAddress = addr
+
+	return nil
+}
+
+// SetChanReserve sets the channel reserve negotiated during the funding
+// workflow, which is persisted along with the channel once the reservation
+// completes.
+func (r *ChannelReservation) SetChanReserve(reserve btcutil.Amount) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.partialState.ChanReserve = reserve
+	r.partialState.ChanReserveNegotiated = true
+}
+
+// SetCommitFormat sets the format of the commitment transactions of the
+// channel. If the anchor format is selected, the value of both anchor outputs
+// is deducted from the balance of the initiator. This MUST be called before
+// the commitment transactions are created, and only for single funder
+// channels.
+func (r *ChannelReservation) SetCommitFormat(format channeldb.CommitmentFormat) error {
+	r.Lock()
+	defer r.Unlock()
+
+	if format == r.partialState.CommitFormat {
+		return nil
+	}
+	if r.partialState.CommitFormat != channeldb.LegacyCommitment {
+		return fmt.Errorf("commitment format already set to %v",
+			r.partialState.CommitFormat)
+	}
+	if format == channeldb.AnchorCommitment {
+		if r.partialState.ChanType != channeldb.SingleFunder {
+			return fmt.Errorf("anchor commitments require a single " +
+				"funder channel")
+		}
+
+		anchorsValue := 2 * AnchorSize
+		initiatorBalance := &r.partialState.TheirBalance
+		if r.partialState.IsInitiator {
+			initiatorBalance = &r.partialState.OurBalance
+		}
+		if *initiatorBalance < anchorsValue {
+			return fmt.Errorf("initiator balance of %v is unable to "+
+				"pay for anchors of %v", *initiatorBalance,
+				anchorsValue)
+		}
+		*initiatorBalance -= anchorsValue
+	}
+
+	r.partialState.CommitFormat = format
+
+	return nil
+}
+
+// Capacity returns the total capacity of the channel being funded.
+func (r *ChannelReservation) Capacity() btcutil.Amount {
+	r.RLock()
+	defer r.RUnlock()
+	return r.partialState.Capacity
+}
+
 // FundingOutpoint returns the outpoint of the funding transaction.
 //
 // NOTE: The pointer returned will only be set once the .ProcesContribution()
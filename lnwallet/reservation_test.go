@@ -0,0 +1,51 @@
+package lnwallet
+
+import (
+	"testing"
+
+	"github.com/roasbeef/btcutil"
+)
+
+// TestDualFunderCommitFees tests that the fee of the initial commitment
+// transaction of a dual funded channel is split in proportion to each party's
+// contribution, and that the two shares always sum to the full fee.
+func TestDualFunderCommitFees(t *testing.T) {
+	tests := []struct {
+		initiatorAmt btcutil.Amount
+		responderAmt btcutil.Amount
+		initiatorFee btcutil.Amount
+		responderFee btcutil.Amount
+	}{
+		// Equal contributions split the fee evenly.
+		{1e8, 1e8, 2500, 2500},
+
+		// The initiator contributes three quarters of the capacity.
+		{3e8, 1e8, 3750, 1250},
+
+		// The initiator's share is rounded down, leaving the
+		// remainder to the responder.
+		{1e8, 2e8, 1666, 3334},
+
+		// Contributions large enough that the product of the fee and
+		// the contribution would overflow an int64.
+		{2e15, 2e15, 2500, 2500},
+	}
+
+	for i, test := range tests {
+		initiatorFee, responderFee := dualFunderCommitFees(
+			test.initiatorAmt, test.responderAmt,
+		)
+		if initiatorFee != test.initiatorFee {
+			t.Fatalf("test #%v: expected initiator fee %v, got %v",
+				i, test.initiatorFee, initiatorFee)
+		}
+		if responderFee != test.responderFee {
+			t.Fatalf("test #%v: expected responder fee %v, got %v",
+				i, test.responderFee, responderFee)
+		}
+		if initiatorFee+responderFee != commitFee {
+			t.Fatalf("test #%v: fee shares don't sum to the "+
+				"commitment fee", i)
+		}
+	}
+}
@@ -606,6 +606,32 @@ func commitScriptToSelf(csvTimeout uint32, selfKey, revokeKey *btcec.PublicKey)
 	return builder.Script()
 }
 
+// CommitToSelfOutput returns the witness script and public key script of the
+// time-locked output paying to the owner of a commitment transaction. This
+// allows a third party, such as a watchtower, to locate the output within a
+// breached commitment transaction and sweep it via the revocation clause.
+func CommitToSelfOutput(csvTimeout uint32, selfKey,
+	revokeKey *btcec.PublicKey) ([]byte, []byte, error) {
+
+	witnessScript, err := commitScriptToSelf(csvTimeout, selfKey, revokeKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	pkScript, err := witnessScriptHash(witnessScript)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return witnessScript, pkScript, nil
+}
+
+// CommitToRemotePkScript returns the public key script of the output paying
+// immediately to the passed key within the commitment transaction of the
+// other party.
+func CommitToRemotePkScript(key *btcec.PublicKey) ([]byte, error) {
+	return commitScriptUnencumbered(key)
+}
+
 // commitScriptUnencumbered constructs the public key script on the commitment
 // transaction paying to the "other" party. The constructed output is a normal
 // p2wkh output spendable immediately, requiring no contestation period.
@@ -654,6 +680,66 @@ func CommitSpendTimeout(signer Signer, signDesc *SignDescriptor,
 	return witnessStack, nil
 }
 
+// HtlcSpendTimeout constructs a valid witness allowing the sender of an HTLC
+// to reclaim it from their own commitment transaction once it has timed out.
+// The lock time of the sweeping transaction must be at least the absolute
+// timeout of the HTLC, and the sequence number of the spending input must
+// encode the relative delay of the commitment.
+func HtlcSpendTimeout(signer Signer, signDesc *SignDescriptor,
+	sweepTx *wire.MsgTx) (wire.TxWitness, error) {
+
+	// Ensure the transaction version supports the validation of sequence
+	// locks and CSV semantics.
+	if sweepTx.Version < 2 {
+		return nil, fmt.Errorf("version of passed transaction MUST "+
+			"be >= 2, not %v", sweepTx.Version)
+	}
+
+	sweepSig, err := signer.SignOutputRaw(sweepTx, signDesc)
+	if err != nil {
+		return nil, err
+	}
+
+	// Place an empty byte as the first item in the evaluated witness
+	// stack to force script execution to the sender's timeout clause.
+	witnessStack := wire.TxWitness(make([][]byte, 3))
+	witnessStack[0] = append(sweepSig, byte(txscript.SigHashAll))
+	witnessStack[1] = nil
+	witnessStack[2] = signDesc.WitnessScript
+
+	return witnessStack, nil
+}
+
+// HtlcSpendSuccess constructs a valid witness allowing the receiver of an HTLC
+// to claim it from their own commitment transaction with the payment
+// preimage. The sequence number of the spending input must encode the
+// relative delay of the commitment.
+func HtlcSpendSuccess(signer Signer, signDesc *SignDescriptor,
+	sweepTx *wire.MsgTx, paymentPreimage [32]byte) (wire.TxWitness, error) {
+
+	// Ensure the transaction version supports the validation of sequence
+	// locks and CSV semantics.
+	if sweepTx.Version < 2 {
+		return nil, fmt.Errorf("version of passed transaction MUST "+
+			"be >= 2, not %v", sweepTx.Version)
+	}
+
+	sweepSig, err := signer.SignOutputRaw(sweepTx, signDesc)
+	if err != nil {
+		return nil, err
+	}
+
+	// Place a one as the first item in the evaluated witness stack to
+	// force script execution to the receiver's redemption clause.
+	witnessStack := wire.TxWitness(make([][]byte, 4))
+	witnessStack[0] = append(sweepSig, byte(txscript.SigHashAll))
+	witnessStack[1] = paymentPreimage[:]
+	witnessStack[2] = []byte{1}
+	witnessStack[3] = signDesc.WitnessScript
+
+	return witnessStack, nil
+}
+
 // CommitSpendRevoke constructs a valid witness allowing a node to sweep the
 // settled output of a malicious counterparty who broadcasts a revoked
 // commitment transaction.
@@ -690,6 +776,56 @@ func CommitSpendNoDelay(signer Signer, signDesc *SignDescriptor,
 	return wire.TxWitness(inputScript.Witness), nil
 }
 
+// anchorScript constructs the witness script of an anchor output on the
+// commitment transaction, spendable by the owner of the passed funding key
+// immediately, allowing them to bump the fee of the commitment transaction via
+// CPFP. Once the commitment transaction has 16 confirmations, anyone may sweep
+// the output, so the anchors don't linger within the UTXO set.
+//
+// Possible Input Scripts:
+//     OWNER:  <sig>
+//     ANYONE: <emptyvector>
+//
+// Output Script:
+//     <fundingKey> OP_CHECKSIG OP_IFDUP
+//     OP_NOTIF
+//         OP_16 OP_CHECKSEQUENCEVERIFY
+//     OP_ENDIF
+func anchorScript(fundingKey *btcec.PublicKey) ([]byte, error) {
+	builder := txscript.NewScriptBuilder()
+
+	// The owner of the anchor may spend it with a valid signature, which
+	// leaves true on the stack.
+	builder.AddData(fundingKey.SerializeCompressed())
+	builder.AddOp(txscript.OP_CHECKSIG)
+	builder.AddOp(txscript.OP_IFDUP)
+
+	// Otherwise, anyone may spend it once 16 blocks have passed.
+	builder.AddOp(txscript.OP_NOTIF)
+	builder.AddOp(txscript.OP_16)
+	builder.AddOp(OP_CHECKSEQUENCEVERIFY)
+	builder.AddOp(txscript.OP_ENDIF)
+
+	return builder.Script()
+}
+
+// AnchorSpend constructs a valid witness allowing the owner of an anchor
+// output to spend it with a signature under their funding key.
+func AnchorSpend(signer Signer, signDesc *SignDescriptor,
+	sweepTx *wire.MsgTx) (wire.TxWitness, error) {
+
+	sweepSig, err := signer.SignOutputRaw(sweepTx, signDesc)
+	if err != nil {
+		return nil, err
+	}
+
+	witnessStack := wire.TxWitness(make([][]byte, 2))
+	witnessStack[0] = append(sweepSig, byte(txscript.SigHashAll))
+	witnessStack[1] = signDesc.WitnessScript
+
+	return witnessStack, nil
+}
+
 // DeriveRevocationPubkey derives the revocation public key given the
 // counterparty's commitment key, and revocation preimage derived via a
 // pseudo-random-function. In the event that we (for some reason) broadcast a
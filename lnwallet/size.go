@@ -106,6 +106,47 @@ const (
 	// HTLCCost 172 weight
 	HTLCCost = blockchain.WitnessScaleFactor * HTLCSize
 
+	// ToLocalScriptSize 77 bytes
+	//	- OP_IF: 1 byte
+	//	- OP_DATA: 1 byte (revocationkey length)
+	//	- revocationkey: 33 bytes
+	//	- OP_CHECKSIG: 1 byte
+	//	- OP_ELSE: 1 byte
+	//	- OP_DATA: 1 byte (localkey length)
+	//	- localkey: 33 bytes
+	//	- OP_CHECKSIGVERIFY: 1 byte
+	//	- OP_DATA: 1 byte (csv delay length)
+	//	- csv delay: 2 bytes (up to MaxCsvDelay)
+	//	- OP_CHECKSEQUENCEVERIFY: 1 byte
+	//	- OP_ENDIF: 1 byte
+	ToLocalScriptSize = 1 + 1 + 33 + 1 + 1 + 1 + 33 + 1 + 1 + 2 + 1 + 1
+
+	// ToLocalTimeoutWitnessSize 154 bytes
+	//	- NumberOfWitnessElements: 1 byte
+	//	- sigLength: 1 byte
+	//	- sig: 73 bytes
+	//	- NilLength: 1 byte
+	//	- WitnessScriptLength: 1 byte
+	//	- WitnessScript (ToLocalScript)
+	ToLocalTimeoutWitnessSize = 1 + 1 + 73 + 1 + 1 + ToLocalScriptSize
+
+	// ToLocalSweepTxSize 82 bytes
+	//	- Version: 4 bytes
+	//	- WitnessHeader <---- part of the witness data
+	//	- CountTxIn: 1 byte
+	//	- TxIn: 41 bytes
+	//		ToLocalInput
+	//	- CountTxOut: 1 byte
+	//	- TxOut: 31 bytes
+	//		OutputPayingToUs (P2WPKH)
+	//	- LockTime: 4 bytes
+	ToLocalSweepTxSize = 4 + 1 + FundingInputSize + 1 +
+		CommitmentKeyHashOutput + 4
+
+	// ToLocalSweepTxCost 484 weight
+	ToLocalSweepTxCost = blockchain.WitnessScaleFactor*ToLocalSweepTxSize +
+		WitnessHeaderSize + ToLocalTimeoutWitnessSize
+
 	// MaxHTLCNumber shows as the maximum number HTLCs which can be
 	// included in commitment transaction. This numbers was calculated by
 	// Rusty Russel in "BOLT #5: Recommendations for On-chain Transaction
@@ -130,3 +171,13 @@ func estimateCommitTxCost(count int, prediction bool) int64 {
 
 	return htlcCost + baseCost + witnessCost
 }
+
+// commitVSize returns the virtual size of a commitment transaction carrying
+// the passed number of HTLC outputs.
+func commitVSize(numHTLCs int) int64 {
+	weight := int64(BaseCommitmentTxCost + WitnessCommitmentTxCost +
+		HTLCCost*numHTLCs)
+
+	return (weight + blockchain.WitnessScaleFactor - 1) /
+		blockchain.WitnessScaleFactor
+}
@@ -0,0 +1,850 @@
+package lnwallet
+
+import (
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/txscript"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+	"github.com/roasbeef/btcutil/txsort"
+)
+
+const (
+	// spliceFundingSpendSize is an estimate of the number of bytes it
+	// takes to spend the 2-of-2 multi-sig funding output of a channel
+	// within a splice transaction.
+	spliceFundingSpendSize = WitnessSize + 32 + 4 + 1 + 4
+)
+
+// spliceState is a splice of additional funds into the channel. The splice
+// transaction spends the channel's current funding output alongside the
+// inputs of the splice's initiator, creating a new funding output of greater
+// capacity. Both parties sign new commitment transactions at the channel's
+// current height which spend the new funding output, crediting the spliced
+// funds to the initiator. Until the splice transaction is buried deep enough
+// within the chain to be safe from re-orgs, the channel's existing funding
+// output and commitment state remain in place.
+type spliceState struct {
+	// initiator denotes that we requested the splice, and contributed
+	// its inputs.
+	initiator bool
+
+	// tx is the splice transaction. Its inputs are signed as the
+	// signatures become known.
+	tx *wire.MsgTx
+
+	// fundingTxIn is an input spending the funding output created by
+	// the splice transaction.
+	fundingTxIn *wire.TxIn
+
+	// signDesc is the sign descriptor used to sign for the funding output
+	// created by the splice transaction.
+	signDesc *SignDescriptor
+
+	// capacity is the value of the new funding output, and ourBalance and
+	// theirBalance are the settled balances of each party once the
+	// spliced funds have been credited to the initiator.
+	capacity     btcutil.Amount
+	ourBalance   btcutil.Amount
+	theirBalance btcutil.Amount
+
+	// localCommitTx and remoteCommitTx are the versions of each party's
+	// commitment transaction spending the new funding output.
+	// localCommitSig is the remote party's signature for our version,
+	// and is only set once it's been verified.
+	localCommitTx  *wire.MsgTx
+	remoteCommitTx *wire.MsgTx
+	localCommitSig []byte
+
+	// persisted denotes that the splice has been written to disk, and
+	// confirmed denotes that the splice transaction has been included
+	// within the chain.
+	persisted bool
+	confirmed bool
+}
+
+// toPendingSplice converts the splice into the format in which it's written
+// to disk.
+func (s *spliceState) toPendingSplice() *channeldb.PendingSplice {
+	return &channeldb.PendingSplice{
+		Tx:              s.tx,
+		FundingOutpoint: &s.fundingTxIn.PreviousOutPoint,
+		Capacity:        s.capacity,
+		OurBalance:      s.ourBalance,
+		TheirBalance:    s.theirBalance,
+		OurCommitTx:     s.localCommitTx,
+		OurCommitSig:    s.localCommitSig,
+		Confirmed:       s.confirmed,
+	}
+}
+
+// fullySigned returns true if every input of the splice transaction has been
+// signed, in which case it can be broadcast.
+func (s *spliceState) fullySigned() bool {
+	for _, txIn := range s.tx.TxIn {
+		if len(txIn.Witness) == 0 && len(txIn.SignatureScript) == 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// CreateSpliceTx creates the splice transaction of a channel, which spends
+// the passed funding input of the channel alongside the inputs of the
+// splice's initiator, paying the channel's new capacity to the passed funding
+// pkScript, and the remainder of the initiator's inputs to its change
+// outputs. The transaction is sorted according to BIP 69, so both parties
+// construct an identical transaction. The outpoint of the new funding output
+// is returned alongside the transaction.
+func CreateSpliceTx(fundingTxIn *wire.TxIn, fundingPkScript []byte,
+	capacity btcutil.Amount, inputs []*wire.TxIn,
+	changeOutputs []*wire.TxOut) (*wire.MsgTx, *wire.OutPoint) {
+
+	spliceTx := wire.NewMsgTx(1)
+	spliceTx.AddTxIn(wire.NewTxIn(&fundingTxIn.PreviousOutPoint, nil, nil))
+	for _, txIn := range inputs {
+		spliceTx.AddTxIn(wire.NewTxIn(&txIn.PreviousOutPoint, nil, nil))
+	}
+
+	spliceTx.AddTxOut(wire.NewTxOut(int64(capacity), fundingPkScript))
+	for _, txOut := range changeOutputs {
+		spliceTx.AddTxOut(wire.NewTxOut(txOut.Value, txOut.PkScript))
+	}
+
+	txsort.InPlaceSort(spliceTx)
+
+	_, fundingIndex := FindScriptOutputIndex(spliceTx, fundingPkScript)
+	spliceTxID := spliceTx.TxHash()
+	return spliceTx, wire.NewOutPoint(&spliceTxID, fundingIndex)
+}
+
+// canSplice returns a non-nil error if the channel can't currently be
+// spliced. A splice is only possible when the channel is quiescent: no HTLCs
+// or fee updates may be pending, and both commitment chains must have
+// settled on the channel's current state. As the commitment transactions
+// spending the new funding output are signed at the channel's current
+// height, no further updates are accepted until the splice is migrated to.
+//
+// NOTE: This method MUST be called with the channel's mutex held.
+func (lc *LightningChannel) canSplice() error {
+	switch {
+	case lc.channelState.ChanType == channeldb.RecoveredChannel:
+		return ErrRecoveredChannel
+	case lc.deviceFundingKey:
+		return ErrDeviceFundingKey
+	case lc.divergence != nil:
+		return ErrChannelFrozen
+	case lc.status == channelClosing || lc.status == channelClosed ||
+		lc.status == channelDispute:
+		return ErrChanClosing
+	case lc.splice != nil:
+		return ErrSpliceInProgress
+	case lc.localShutdown || lc.remoteShutdown:
+		return ErrChanShuttingDown
+	}
+
+	if !lc.quiescent() {
+		return fmt.Errorf("channel has pending updates, unable to " +
+			"splice")
+	}
+
+	return nil
+}
+
+// newSplice creates the splice transaction for a splice of the passed amount
+// into the channel, along with both parties' versions of the commitment
+// transaction spending the new funding output.
+//
+// NOTE: This method MUST be called with the channel's mutex held.
+func (lc *LightningChannel) newSplice(amt btcutil.Amount, initiator bool,
+	inputs []*wire.TxIn, changeOutputs []*wire.TxOut) (*spliceState, error) {
+
+	capacity := lc.Capacity + amt
+	spliceTx, fundingOutpoint := CreateSpliceTx(lc.fundingTxIn,
+		lc.fundingP2WSH, capacity, inputs, changeOutputs)
+
+	ourBalance := lc.localCommitChain.tail().ourBalance
+	theirBalance := lc.localCommitChain.tail().theirBalance
+	if initiator {
+		ourBalance += amt
+	} else {
+		theirBalance += amt
+	}
+
+	splice := &spliceState{
+		initiator:    initiator,
+		tx:           spliceTx,
+		fundingTxIn:  wire.NewTxIn(fundingOutpoint, nil, nil),
+		capacity:     capacity,
+		ourBalance:   ourBalance,
+		theirBalance: theirBalance,
+	}
+	splice.signDesc = &SignDescriptor{
+		PubKey:        lc.channelState.OurMultiSigKey,
+		WitnessScript: lc.channelState.FundingWitnessScript,
+		Output: &wire.TxOut{
+			PkScript: lc.fundingP2WSH,
+			Value:    int64(capacity),
+		},
+		HashType:   txscript.SigHashAll,
+		InputIndex: 0,
+	}
+
+	var err error
+	splice.localCommitTx, err = lc.spliceCommitTx(splice, false)
+	if err != nil {
+		return nil, err
+	}
+	splice.remoteCommitTx, err = lc.spliceCommitTx(splice, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return splice, nil
+}
+
+// spliceCommitTx creates a version of the current commitment transaction of
+// either party which spends the funding output created by the passed splice.
+// The transaction is identical to the party's current commitment transaction
+// in all but its input, and the balances of its outputs.
+//
+// NOTE: This method MUST be called with the channel's mutex held.
+func (lc *LightningChannel) spliceCommitTx(splice *spliceState,
+	remoteChain bool) (*wire.MsgTx, error) {
+
+	var (
+		height                            uint64
+		revocationKey                     *btcec.PublicKey
+		ownerBalance, counterpartyBalance btcutil.Amount
+		fee                               btcutil.Amount
+	)
+	if remoteChain {
+		height = lc.remoteCommitChain.tail().height
+		fee = lc.remoteCommitChain.tail().fee
+		revocationKey = lc.channelState.TheirCurrentRevocation
+		ownerBalance = splice.theirBalance
+		counterpartyBalance = splice.ourBalance
+	} else {
+		height = lc.localCommitChain.tail().height
+		fee = lc.localCommitChain.tail().fee
+		revocation, err := lc.channelState.RevocationProducer.AtIndex(height)
+		if err != nil {
+			return nil, err
+		}
+
+		revocationKey = DeriveRevocationPubkey(
+			lc.channelState.TheirCommitKey, revocation[:])
+		ownerBalance = splice.ourBalance
+		counterpartyBalance = splice.theirBalance
+	}
+
+	builder := lc.commitmentBuilder(remoteChain, splice.fundingTxIn,
+		splice.capacity)
+	commit, err := builder.Build(&CommitmentState{
+		Height:              height,
+		RevocationKey:       revocationKey,
+		OwnerBalance:        ownerBalance,
+		CounterpartyBalance: counterpartyBalance,
+		MinFee:              fee,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return commit.Tx, nil
+}
+
+// verifySpliceCommitSig verifies the remote party's signature for our version
+// of the commitment transaction spending the splice's funding output.
+//
+// NOTE: This method MUST be called with the channel's mutex held.
+func (lc *LightningChannel) verifySpliceCommitSig(splice *spliceState,
+	rawSig []byte) error {
+
+	commitTx := splice.localCommitTx
+	hashCache := txscript.NewTxSigHashes(commitTx)
+	sigHash, err := txscript.CalcWitnessSigHash(
+		lc.channelState.FundingWitnessScript, hashCache,
+		txscript.SigHashAll, commitTx, 0, int64(splice.capacity))
+	if err != nil {
+		return err
+	}
+
+	theirMultiSigKey := lc.channelState.TheirMultiSigKey
+	theirMultiSigKey.Curve = btcec.S256()
+	sig, err := btcec.ParseSignature(rawSig, btcec.S256())
+	if err != nil {
+		return err
+	} else if !sig.Verify(sigHash, theirMultiSigKey) {
+		return fmt.Errorf("invalid splice commitment signature")
+	}
+
+	return nil
+}
+
+// signSpliceFunding signs the input of the splice transaction which spends
+// the channel's current funding output.
+//
+// NOTE: This method MUST be called with the channel's mutex held.
+func (lc *LightningChannel) signSpliceFunding(splice *spliceState) ([]byte,
+	int, error) {
+
+	fundingIndex := -1
+	for i, txIn := range splice.tx.TxIn {
+		if txIn.PreviousOutPoint == lc.fundingTxIn.PreviousOutPoint {
+			fundingIndex = i
+			break
+		}
+	}
+	if fundingIndex == -1 {
+		return nil, 0, fmt.Errorf("splice transaction doesn't spend " +
+			"the channel's funding output")
+	}
+
+	signDesc := *lc.signDesc
+	signDesc.SigHashes = txscript.NewTxSigHashes(splice.tx)
+	signDesc.InputIndex = fundingIndex
+	sig, err := lc.signer.SignOutputRaw(splice.tx, &signDesc)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return sig, fundingIndex, nil
+}
+
+// completeSpliceFunding completes the input of the splice transaction which
+// spends the channel's current funding output with our signature and the
+// passed remote signature, ensuring the resulting witness is valid.
+//
+// NOTE: This method MUST be called with the channel's mutex held.
+func (lc *LightningChannel) completeSpliceFunding(splice *spliceState,
+	ourSig, theirSig []byte, fundingIndex int) error {
+
+	ourKey := lc.channelState.OurMultiSigKey.SerializeCompressed()
+	theirKey := lc.channelState.TheirMultiSigKey.SerializeCompressed()
+	splice.tx.TxIn[fundingIndex].Witness = SpendMultiSig(
+		lc.FundingWitnessScript, ourKey,
+		append(ourSig, byte(txscript.SigHashAll)), theirKey,
+		append(theirSig, byte(txscript.SigHashAll)))
+
+	hashCache := txscript.NewTxSigHashes(splice.tx)
+	vm, err := txscript.NewEngine(lc.fundingP2WSH, splice.tx, fundingIndex,
+		txscript.StandardVerifyFlags, nil, hashCache,
+		int64(lc.Capacity))
+	if err != nil {
+		return err
+	}
+	if err := vm.Execute(); err != nil {
+		splice.tx.TxIn[fundingIndex].Witness = nil
+		return fmt.Errorf("invalid splice funding signature: %v", err)
+	}
+
+	return nil
+}
+
+// ProposeSplice begins a splice of the passed amount into the channel, funded
+// by the passed inputs, less the passed change outputs. This method should be
+// called by the initiator of the splice before sending a SpliceRequest to
+// the remote party. The splice transaction is returned without any
+// signatures, so the initiator's wallet can sign its inputs in response to
+// the remote party's SpliceAccept.
+func (lc *LightningChannel) ProposeSplice(amt btcutil.Amount,
+	inputs []*wire.TxIn, changeOutputs []*wire.TxOut) (*wire.MsgTx, error) {
+
+	lc.Lock()
+	defer lc.Unlock()
+
+	if err := lc.canSplice(); err != nil {
+		return nil, err
+	}
+
+	splice, err := lc.newSplice(amt, true, inputs, changeOutputs)
+	if err != nil {
+		return nil, err
+	}
+	lc.splice = splice
+
+	walletLog.Infof("ChannelPoint(%v): proposing splice of %v, "+
+		"splice_txid=%v", lc.channelState.ChanID, amt,
+		splice.tx.TxHash())
+
+	return splice.tx.Copy(), nil
+}
+
+// AcceptSplice accepts a splice of the passed amount into the channel
+// requested by the remote party, funded by the passed inputs, less the passed
+// change outputs. The caller is responsible for ensuring that the remote
+// party's inputs fund the splice. Our signature for the remote party's
+// version of the commitment transaction spending the new funding output is
+// returned, and should be sent to the remote party within a SpliceAccept.
+func (lc *LightningChannel) AcceptSplice(amt btcutil.Amount,
+	inputs []*wire.TxIn, changeOutputs []*wire.TxOut) ([]byte, error) {
+
+	lc.Lock()
+	defer lc.Unlock()
+
+	if err := lc.canSplice(); err != nil {
+		return nil, err
+	}
+
+	splice, err := lc.newSplice(amt, false, inputs, changeOutputs)
+	if err != nil {
+		return nil, err
+	}
+
+	splice.signDesc.SigHashes = txscript.NewTxSigHashes(splice.remoteCommitTx)
+	commitSig, err := lc.signer.SignOutputRaw(splice.remoteCommitTx,
+		splice.signDesc)
+	if err != nil {
+		return nil, err
+	}
+	lc.splice = splice
+
+	walletLog.Infof("ChannelPoint(%v): accepted splice of %v, "+
+		"splice_txid=%v", lc.channelState.ChanID, amt,
+		splice.tx.TxHash())
+
+	return commitSig, nil
+}
+
+// SignSplice is called by the initiator of a splice in response to the
+// remote party's SpliceAccept. The remote party's signature for our version
+// of the commitment transaction spending the new funding output is verified,
+// and the passed input scripts, which spend our inputs to the splice
+// transaction in order, are attached to it. Once the splice is written to
+// disk, our signature for the remote party's version of the commitment
+// transaction, and our signature for the input of the splice transaction
+// spending the current funding output are returned.
+//
+// NOTE: Once our signature for the current funding output has been sent, the
+// remote party is able to broadcast the splice transaction, so the splice
+// must no longer be abandoned.
+func (lc *LightningChannel) SignSplice(theirCommitSig []byte,
+	ourInputScripts []*InputScript) ([]byte, []byte, error) {
+
+	lc.Lock()
+	defer lc.Unlock()
+
+	splice := lc.splice
+	if splice == nil || !splice.initiator || splice.persisted {
+		return nil, nil, fmt.Errorf("no splice proposed for channel")
+	}
+
+	if err := lc.verifySpliceCommitSig(splice, theirCommitSig); err != nil {
+		return nil, nil, err
+	}
+
+	// Attach our input scripts to each input of the splice transaction
+	// other than the one spending the current funding output.
+	var scriptIndex int
+	for _, txIn := range splice.tx.TxIn {
+		if txIn.PreviousOutPoint == lc.fundingTxIn.PreviousOutPoint {
+			continue
+		}
+		if scriptIndex == len(ourInputScripts) {
+			return nil, nil, fmt.Errorf("missing input scripts for " +
+				"splice transaction")
+		}
+
+		txIn.Witness = ourInputScripts[scriptIndex].Witness
+		txIn.SignatureScript = ourInputScripts[scriptIndex].ScriptSig
+		scriptIndex++
+	}
+
+	splice.signDesc.SigHashes = txscript.NewTxSigHashes(splice.remoteCommitTx)
+	commitSig, err := lc.signer.SignOutputRaw(splice.remoteCommitTx,
+		splice.signDesc)
+	if err != nil {
+		return nil, nil, err
+	}
+	fundingSig, _, err := lc.signSpliceFunding(splice)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// With both signatures generated, we'll write the splice to disk
+	// before releasing them, so we're able to recognize the splice
+	// transaction once it's broadcast.
+	splice.localCommitSig = theirCommitSig
+	if err := lc.channelState.PutPendingSplice(splice.toPendingSplice()); err != nil {
+		splice.localCommitSig = nil
+		return nil, nil, err
+	}
+	splice.persisted = true
+
+	return commitSig, fundingSig, nil
+}
+
+// CompleteSplice is called by the responder to a splice in response to the
+// initiator's SpliceSigned. The initiator's signature for our version of the
+// commitment transaction spending the new funding output, and its signature
+// for the input of the splice transaction spending the current funding
+// output are verified. The initiator's input scripts are then attached to
+// the splice transaction, and the splice is written to disk. The splice
+// transaction, along with our signature for the current funding output, is
+// returned.
+//
+// NOTE: The initiator's input scripts aren't verified, as this requires the
+// outputs they spend. The caller should verify the returned transaction
+// before sending our signature to the initiator, abandoning the splice if
+// it's invalid.
+func (lc *LightningChannel) CompleteSplice(theirCommitSig,
+	theirFundingSig []byte,
+	theirInputScripts []*InputScript) (*wire.MsgTx, []byte, error) {
+
+	lc.Lock()
+	defer lc.Unlock()
+
+	splice := lc.splice
+	if splice == nil || splice.initiator || splice.persisted {
+		return nil, nil, fmt.Errorf("no splice accepted for channel")
+	}
+
+	if err := lc.verifySpliceCommitSig(splice, theirCommitSig); err != nil {
+		return nil, nil, err
+	}
+
+	numInputs := len(splice.tx.TxIn) - 1
+	if len(theirInputScripts) != numInputs {
+		return nil, nil, fmt.Errorf("counterparty provided %v input "+
+			"scripts for %v inputs", len(theirInputScripts),
+			numInputs)
+	}
+
+	ourFundingSig, fundingIndex, err := lc.signSpliceFunding(splice)
+	if err != nil {
+		return nil, nil, err
+	}
+	err = lc.completeSpliceFunding(splice, ourFundingSig, theirFundingSig,
+		fundingIndex)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var scriptIndex int
+	for i, txIn := range splice.tx.TxIn {
+		if i == fundingIndex {
+			continue
+		}
+
+		txIn.Witness = theirInputScripts[scriptIndex].Witness
+		txIn.SignatureScript = theirInputScripts[scriptIndex].ScriptSig
+		scriptIndex++
+	}
+
+	splice.localCommitSig = theirCommitSig
+	if err := lc.channelState.PutPendingSplice(splice.toPendingSplice()); err != nil {
+		splice.localCommitSig = nil
+		return nil, nil, err
+	}
+	splice.persisted = true
+
+	walletLog.Infof("ChannelPoint(%v): completed splice, splice_txid=%v",
+		lc.channelState.ChanID, splice.tx.TxHash())
+
+	return splice.tx.Copy(), ourFundingSig, nil
+}
+
+// FinalizeSplice is called by the initiator of a splice in response to the
+// responder's SpliceComplete. The splice transaction is completed with the
+// responder's signature for the input spending the current funding output,
+// and the fully signed transaction is returned so it can be broadcast.
+func (lc *LightningChannel) FinalizeSplice(theirFundingSig []byte) (*wire.MsgTx,
+	error) {
+
+	lc.Lock()
+	defer lc.Unlock()
+
+	splice := lc.splice
+	if splice == nil || !splice.initiator || !splice.persisted {
+		return nil, fmt.Errorf("no splice signed for channel")
+	}
+
+	ourFundingSig, fundingIndex, err := lc.signSpliceFunding(splice)
+	if err != nil {
+		return nil, err
+	}
+	err = lc.completeSpliceFunding(splice, ourFundingSig, theirFundingSig,
+		fundingIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := lc.channelState.PutPendingSplice(splice.toPendingSplice()); err != nil {
+		return nil, err
+	}
+
+	walletLog.Infof("ChannelPoint(%v): finalized splice, splice_txid=%v",
+		lc.channelState.ChanID, splice.tx.TxHash())
+
+	return splice.tx.Copy(), nil
+}
+
+// PendingSpliceTx returns the splice transaction of the channel if a splice
+// has been signed by both parties, but not yet migrated to. The returned
+// boolean is true if the transaction is fully signed, and can therefore be
+// broadcast.
+func (lc *LightningChannel) PendingSpliceTx() (*wire.MsgTx, bool) {
+	lc.RLock()
+	defer lc.RUnlock()
+
+	if lc.splice == nil || !lc.splice.persisted {
+		return nil, false
+	}
+
+	return lc.splice.tx.Copy(), lc.splice.fullySigned()
+}
+
+// SpliceConfirmed marks the splice transaction of the channel as included
+// within the chain. From this point on, the channel's current funding output
+// is spent, so a unilateral close of the channel broadcasts our version of
+// the commitment transaction spending the new funding output.
+func (lc *LightningChannel) SpliceConfirmed() error {
+	lc.Lock()
+	defer lc.Unlock()
+
+	if lc.splice == nil || !lc.splice.persisted {
+		return fmt.Errorf("no splice signed for channel")
+	}
+	if lc.splice.confirmed {
+		return nil
+	}
+
+	lc.splice.confirmed = true
+	if err := lc.channelState.PutPendingSplice(lc.splice.toPendingSplice()); err != nil {
+		lc.splice.confirmed = false
+		return err
+	}
+
+	return nil
+}
+
+// MigrateSplice migrates the channel to the funding output created by its
+// splice transaction, once the transaction is buried deep enough within the
+// chain to be safe from re-orgs. The new capacity, balances, and commitment
+// transaction become the channel's current state, both in memory and on
+// disk, and the channel once again accepts updates. The outpoint of the new
+// funding output is returned.
+func (lc *LightningChannel) MigrateSplice() (*wire.OutPoint, error) {
+	lc.Lock()
+	defer lc.Unlock()
+
+	splice := lc.splice
+	if splice == nil || !splice.confirmed {
+		return nil, fmt.Errorf("no confirmed splice for channel")
+	}
+
+	lc.stateMtx.Lock()
+	err := lc.channelState.MigrateSplice(splice.toPendingSplice())
+	lc.stateMtx.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	lc.fundingTxIn = splice.fundingTxIn
+	lc.Capacity = splice.capacity
+	lc.signDesc.Output.Value = int64(splice.capacity)
+	for _, commit := range []*commitment{lc.localCommitChain.tail(),
+		lc.remoteCommitChain.tail()} {
+
+		commit.ourBalance = splice.ourBalance
+		commit.theirBalance = splice.theirBalance
+	}
+	lc.splice = nil
+
+	walletLog.Infof("ChannelPoint(%v): migrated to splice funding "+
+		"output %v, capacity=%v", lc.channelState.ChanID,
+		splice.fundingTxIn.PreviousOutPoint, splice.capacity)
+
+	return &splice.fundingTxIn.PreviousOutPoint, nil
+}
+
+// AbandonSplice abandons the splice of the channel, removing it from disk if
+// it's been written, and unfreezing the channel. A splice whose transaction
+// has confirmed can't be abandoned.
+//
+// NOTE: This method MUST NOT be called once our signature for the input of
+// the splice transaction spending the current funding output has been sent to
+// the remote party, as they're then able to broadcast it.
+func (lc *LightningChannel) AbandonSplice() error {
+	lc.Lock()
+	defer lc.Unlock()
+
+	if lc.splice == nil {
+		return nil
+	}
+	if lc.splice.confirmed {
+		return fmt.Errorf("splice transaction has confirmed, unable " +
+			"to abandon splice")
+	}
+
+	if lc.splice.persisted {
+		if err := lc.channelState.DeletePendingSplice(); err != nil {
+			return err
+		}
+	}
+	lc.splice = nil
+
+	return nil
+}
+
+// restoreSplice restores the splice of the channel written to disk, if any.
+func (lc *LightningChannel) restoreSplice() error {
+	pending, err := lc.channelState.FetchPendingSplice()
+	switch {
+	case err == channeldb.ErrNoPendingSplice:
+		return nil
+	case err != nil:
+		return err
+	}
+
+	lc.splice = &spliceState{
+		tx:             pending.Tx,
+		fundingTxIn:    wire.NewTxIn(pending.FundingOutpoint, nil, nil),
+		capacity:       pending.Capacity,
+		ourBalance:     pending.OurBalance,
+		theirBalance:   pending.TheirBalance,
+		localCommitTx:  pending.OurCommitTx,
+		localCommitSig: pending.OurCommitSig,
+		persisted:      true,
+		confirmed:      pending.Confirmed,
+	}
+	lc.splice.signDesc = &SignDescriptor{
+		PubKey:        lc.channelState.OurMultiSigKey,
+		WitnessScript: lc.channelState.FundingWitnessScript,
+		Output: &wire.TxOut{
+			PkScript: lc.fundingP2WSH,
+			Value:    int64(pending.Capacity),
+		},
+		HashType:   txscript.SigHashAll,
+		InputIndex: 0,
+	}
+
+	return nil
+}
+
+// isSpliceTx returns true if the passed transaction is the splice
+// transaction of the channel.
+//
+// NOTE: This method MUST be called with the channel's mutex held.
+func (lc *LightningChannel) isSpliceTx(tx *wire.MsgTx) bool {
+	return lc.splice != nil && lc.splice.persisted &&
+		lc.splice.tx.TxHash() == tx.TxHash()
+}
+
+// SelectSpliceInputs selects coins from the wallet to fund a splice of the
+// passed amount into a channel, returning the inputs spending them, along
+// with any change output. The fee of the splice transaction, including that
+// of spending the channel's current funding output, is paid from the
+// selected coins. The selected coins remain locked until the splice
+// transaction confirms, or they're unlocked via UnlockSpliceInputs.
+func (l *LightningWallet) SelectSpliceInputs(
+	amt btcutil.Amount) ([]*wire.TxIn, []*wire.TxOut, error) {
+
+	// TODO(roasbeef): remove hardcoded fees
+	feeRate := uint64(10)
+	fundingSpendFee := btcutil.Amount(spliceFundingSpendSize * feeRate)
+
+	contribution := &ChannelContribution{}
+	err := l.selectCoinsAndChange(feeRate, amt+fundingSpendFee,
+		contribution)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return contribution.Inputs, contribution.ChangeOutputs, nil
+}
+
+// UnlockSpliceInputs unlocks the coins spent by the passed inputs, which were
+// selected to fund a splice which has since been abandoned.
+func (l *LightningWallet) UnlockSpliceInputs(inputs []*wire.TxIn) {
+	l.limboMtx.Lock()
+	defer l.limboMtx.Unlock()
+
+	for _, txIn := range inputs {
+		delete(l.lockedOutPoints, txIn.PreviousOutPoint)
+		l.UnlockOutpoint(txIn.PreviousOutPoint)
+	}
+}
+
+// SignSpliceInputs signs each input of the passed splice transaction which
+// spends a coin of the wallet, returning the input scripts in the order of
+// the inputs within the transaction.
+func (l *LightningWallet) SignSpliceInputs(spliceTx *wire.MsgTx) ([]*InputScript,
+	error) {
+
+	var inputScripts []*InputScript
+	signDesc := SignDescriptor{
+		HashType:  txscript.SigHashAll,
+		SigHashes: txscript.NewTxSigHashes(spliceTx),
+	}
+	for i, txIn := range spliceTx.TxIn {
+		info, err := l.FetchInputInfo(&txIn.PreviousOutPoint)
+		if err == ErrNotMine {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		signDesc.Output = info
+		signDesc.InputIndex = i
+
+		inputScript, err := l.Signer.ComputeInputScript(spliceTx, &signDesc)
+		if err != nil {
+			return nil, err
+		}
+
+		inputScripts = append(inputScripts, inputScript)
+	}
+
+	return inputScripts, nil
+}
+
+// VerifySpliceFunds ensures that the passed inputs of the remote party's
+// splice, less its change outputs, add up to at least the amount it has
+// requested to splice into the channel.
+func (l *LightningWallet) VerifySpliceFunds(inputs []*wire.TxIn,
+	changeOutputs []*wire.TxOut, amt btcutil.Amount) error {
+
+	return l.verifyContributionFunds(&ChannelContribution{
+		Inputs:        inputs,
+		ChangeOutputs: changeOutputs,
+	}, amt)
+}
+
+// VerifySpliceInputs ensures that the scripts of each input of the passed
+// splice transaction, other than the one spending the channel's funding
+// output, are valid.
+func (l *LightningWallet) VerifySpliceInputs(spliceTx *wire.MsgTx,
+	fundingOutpoint *wire.OutPoint) error {
+
+	hashCache := txscript.NewTxSigHashes(spliceTx)
+	for i, txIn := range spliceTx.TxIn {
+		prevOut := txIn.PreviousOutPoint
+		if prevOut == *fundingOutpoint {
+			continue
+		}
+
+		output, err := l.ChainIO.GetUtxo(&prevOut.Hash, prevOut.Index)
+		if output == nil {
+			return fmt.Errorf("input to splice tx does not exist: %v",
+				err)
+		}
+
+		vm, err := txscript.NewEngine(output.PkScript, spliceTx, i,
+			txscript.StandardVerifyFlags, nil, hashCache,
+			output.Value)
+		if err != nil {
+			return fmt.Errorf("cannot create script engine: %s", err)
+		}
+		if err := vm.Execute(); err != nil {
+			return fmt.Errorf("cannot validate splice transaction: %s",
+				err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,160 @@
+package lnwallet
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+// TestSplice tests a splice of additional funds into a channel by Alice,
+// ensuring that both parties construct the same splice transaction, that the
+// channel is frozen until the splice is migrated to, and that state
+// transitions resume over the new funding output afterwards.
+func TestSplice(t *testing.T) {
+	aliceChannel, bobChannel, cleanUp, err := createTestChannels(1)
+	if err != nil {
+		t.Fatalf("unable to create test channels: %v", err)
+	}
+	defer cleanUp()
+
+	spliceAmt := btcutil.Amount(2 * 1e8)
+	inputs := []*wire.TxIn{
+		wire.NewTxIn(&wire.OutPoint{
+			Hash:  chainhash.Hash(sha256.Sum256([]byte("splice"))),
+			Index: 1,
+		}, nil, nil),
+	}
+	changeOutputs := []*wire.TxOut{
+		wire.NewTxOut(1e6, bytes.Repeat([]byte{0}, 22)),
+	}
+
+	// Alice proposes the splice, which Bob accepts by signing her new
+	// commitment transaction.
+	spliceTx, err := aliceChannel.ProposeSplice(spliceAmt, inputs,
+		changeOutputs)
+	if err != nil {
+		t.Fatalf("unable to propose splice: %v", err)
+	}
+	if _, err := aliceChannel.ProposeSplice(spliceAmt, inputs,
+		changeOutputs); err != ErrSpliceInProgress {
+		t.Fatalf("expected ErrSpliceInProgress, got %v", err)
+	}
+	bobCommitSig, err := bobChannel.AcceptSplice(spliceAmt, inputs,
+		changeOutputs)
+	if err != nil {
+		t.Fatalf("unable to accept splice: %v", err)
+	}
+
+	// Alice signs her input, along with Bob's commitment transaction and
+	// the current funding output.
+	aliceInputScripts := []*InputScript{
+		{Witness: [][]byte{bytes.Repeat([]byte{1}, 72)}},
+	}
+	aliceCommitSig, aliceFundingSig, err := aliceChannel.SignSplice(
+		bobCommitSig, aliceInputScripts)
+	if err != nil {
+		t.Fatalf("unable to sign splice: %v", err)
+	}
+
+	// Bob completes the splice transaction, after which Alice is able to
+	// do the same.
+	bobSpliceTx, bobFundingSig, err := bobChannel.CompleteSplice(
+		aliceCommitSig, aliceFundingSig, aliceInputScripts)
+	if err != nil {
+		t.Fatalf("unable to complete splice: %v", err)
+	}
+	aliceSpliceTx, err := aliceChannel.FinalizeSplice(bobFundingSig)
+	if err != nil {
+		t.Fatalf("unable to finalize splice: %v", err)
+	}
+
+	spliceTxID := spliceTx.TxHash()
+	if bobSpliceTx.TxHash() != spliceTxID ||
+		aliceSpliceTx.TxHash() != spliceTxID {
+
+		t.Fatalf("splice transactions don't match")
+	}
+	var aliceTx, bobTx bytes.Buffer
+	if err := aliceSpliceTx.Serialize(&aliceTx); err != nil {
+		t.Fatalf("unable to serialize splice tx: %v", err)
+	}
+	if err := bobSpliceTx.Serialize(&bobTx); err != nil {
+		t.Fatalf("unable to serialize splice tx: %v", err)
+	}
+	if !bytes.Equal(aliceTx.Bytes(), bobTx.Bytes()) {
+		t.Fatalf("splice transaction witnesses don't match")
+	}
+	if _, fullySigned := aliceChannel.PendingSpliceTx(); !fullySigned {
+		t.Fatalf("splice transaction should be fully signed")
+	}
+
+	// Until the splice is migrated to, no updates are accepted.
+	htlc := &lnwire.UpdateAddHTLC{
+		PaymentHash: sha256.Sum256(bytes.Repeat([]byte{1}, 32)),
+		Amount:      btcutil.SatoshiPerBitcoin,
+		Expiry:      uint32(5),
+	}
+	if _, err := aliceChannel.AddHTLC(htlc); err != ErrSpliceInProgress {
+		t.Fatalf("expected ErrSpliceInProgress, got %v", err)
+	}
+	_, _, err = bobChannel.InitCooperativeClose(DefaultCoopCloseFee)
+	if err != ErrSpliceInProgress {
+		t.Fatalf("expected ErrSpliceInProgress, got %v", err)
+	}
+
+	// Once the splice transaction has confirmed, a force close should
+	// broadcast the commitment transaction spending the new funding
+	// output.
+	for _, channel := range []*LightningChannel{aliceChannel, bobChannel} {
+		if err := channel.SpliceConfirmed(); err != nil {
+			t.Fatalf("unable to confirm splice: %v", err)
+		}
+	}
+	commitTx, _, _, err := aliceChannel.DryRunForceClose()
+	if err != nil {
+		t.Fatalf("unable to dry run force close: %v", err)
+	}
+	if commitTx.TxIn[0].PreviousOutPoint.Hash != spliceTxID {
+		t.Fatalf("commitment transaction doesn't spend splice")
+	}
+	if err := aliceChannel.AbandonSplice(); err == nil {
+		t.Fatalf("confirmed splice shouldn't be abandoned")
+	}
+
+	// After the migration, both channels should adopt the new capacity
+	// and balances, and state transitions should resume.
+	for _, channel := range []*LightningChannel{aliceChannel, bobChannel} {
+		fundingOutpoint, err := channel.MigrateSplice()
+		if err != nil {
+			t.Fatalf("unable to migrate splice: %v", err)
+		}
+		if fundingOutpoint.Hash != spliceTxID {
+			t.Fatalf("funding outpoint %v not created by splice",
+				fundingOutpoint)
+		}
+		if channel.Capacity != 12*1e8 {
+			t.Fatalf("expected capacity of %v, got %v",
+				btcutil.Amount(12*1e8), channel.Capacity)
+		}
+	}
+	if aliceChannel.channelState.OurBalance != 7*1e8 ||
+		bobChannel.channelState.TheirBalance != 7*1e8 {
+
+		t.Fatalf("spliced funds not credited to alice")
+	}
+
+	if _, err := aliceChannel.AddHTLC(htlc); err != nil {
+		t.Fatalf("unable to add htlc: %v", err)
+	}
+	if _, err := bobChannel.ReceiveHTLC(htlc); err != nil {
+		t.Fatalf("unable to recv htlc: %v", err)
+	}
+	if err := forceStateTransition(aliceChannel, bobChannel); err != nil {
+		t.Fatalf("unable to complete state update: %v", err)
+	}
+}
@@ -0,0 +1,53 @@
+package virtualwallet
+
+import (
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// GetBestBlock returns the current height and hash of the best known block
+// within the main chain.
+//
+// This method is a part of the lnwallet.BlockChainIO interface.
+func (v *VirtualWallet) GetBestBlock() (*chainhash.Hash, int32, error) {
+	return v.cfg.Backend.BestBlock()
+}
+
+// GetUtxo returns the original output referenced by the passed outpoint.
+//
+// This method is a part of the lnwallet.BlockChainIO interface.
+func (v *VirtualWallet) GetUtxo(txid *chainhash.Hash, index uint32) (*wire.TxOut, error) {
+	return v.cfg.Backend.Utxo(&wire.OutPoint{
+		Hash:  *txid,
+		Index: index,
+	})
+}
+
+// GetTransaction returns the full transaction identified by the passed
+// transaction ID.
+//
+// This method is a part of the lnwallet.BlockChainIO interface.
+func (v *VirtualWallet) GetTransaction(txid *chainhash.Hash) (*wire.MsgTx, error) {
+	tx, _, err := v.cfg.Backend.Transaction(txid)
+	return tx, err
+}
+
+// GetBlock returns a raw block from the virtual chain given its hash.
+//
+// This method is a part of the lnwallet.BlockChainIO interface.
+func (v *VirtualWallet) GetBlock(blockHash *chainhash.Hash) (*wire.MsgBlock, error) {
+	return v.cfg.Backend.Block(blockHash)
+}
+
+// GetBlockHash returns the hash of the block in the best blockchain at the
+// given height.
+//
+// This method is a part of the lnwallet.BlockChainIO interface.
+func (v *VirtualWallet) GetBlockHash(blockHeight int64) (*chainhash.Hash, error) {
+	return v.cfg.Backend.BlockHash(int32(blockHeight))
+}
+
+// A compile time check to ensure that VirtualWallet implements the
+// BlockChainIO interface.
+var _ lnwallet.BlockChainIO = (*VirtualWallet)(nil)
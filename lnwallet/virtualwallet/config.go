@@ -0,0 +1,39 @@
+package virtualwallet
+
+import (
+	"github.com/lightningnetwork/lnd/virtualchain"
+	"github.com/roasbeef/btcd/chaincfg"
+	"github.com/roasbeef/btcutil"
+)
+
+const (
+	// DefaultFeeRate is the fee rate, in satoshis per byte, used when the
+	// configuration doesn't specify one.
+	DefaultFeeRate = btcutil.Amount(10)
+
+	// stateFilename is the name of the file within the data directory
+	// holding the seed of the wallet, along with its key counter and
+	// imported keys.
+	stateFilename = "virtualwallet.json"
+)
+
+// Config is a struct which houses configuration parameters which modify the
+// instance of VirtualWallet generated by the New() function.
+type Config struct {
+	// DataDir is the name of the directory where the wallet's persistent
+	// state should be stored.
+	DataDir string
+
+	// NetParams are the parameters of the network the virtual chain
+	// simulates, which determine the encoding of addresses.
+	NetParams *chaincfg.Params
+
+	// Backend is the virtual chain the wallet follows, and publishes
+	// transactions to.
+	Backend virtualchain.Backend
+
+	// FeeRate is the fee rate, in satoshis per byte, paid by the
+	// transactions the wallet creates, which is also returned as its fee
+	// estimate.
+	FeeRate btcutil.Amount
+}
@@ -0,0 +1,45 @@
+package virtualwallet
+
+import (
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/lnwallet"
+)
+
+const (
+	walletType = "virtual"
+)
+
+// createNewWallet creates a new instance of VirtualWallet given the proper
+// list of initialization parameters. This function is the factory function
+// required to properly create an instance of the lnwallet.WalletDriver struct
+// for VirtualWallet.
+func createNewWallet(args ...interface{}) (lnwallet.WalletController, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("incorrect number of arguments to .New(...), "+
+			"expected 1, instead passed %v", len(args))
+	}
+
+	config, ok := args[0].(*Config)
+	if !ok {
+		return nil, fmt.Errorf("first argument to virtualwallet.New is " +
+			"incorrect, expected a *virtualwallet.Config")
+	}
+
+	return New(config)
+}
+
+// init registers a driver for the VirtualWallet concrete implementation of the
+// lnwallet.WalletController interface.
+func init() {
+	// Register the driver.
+	driver := &lnwallet.WalletDriver{
+		WalletType: walletType,
+		New:        createNewWallet,
+	}
+
+	if err := lnwallet.RegisterWallet(driver); err != nil {
+		panic(fmt.Sprintf("failed to register wallet driver '%s': %v",
+			walletType, err))
+	}
+}
@@ -0,0 +1,88 @@
+package virtualwallet
+
+import (
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/roasbeef/btcd/txscript"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// SignOutputRaw generates a signature for the passed transaction according to
+// the data within the passed SignDescriptor.
+//
+// This is a part of the Signer interface.
+func (v *VirtualWallet) SignOutputRaw(tx *wire.MsgTx,
+	signDesc *lnwallet.SignDescriptor) ([]byte, error) {
+
+	privKey, err := v.fetchPrivKey(signDesc.PubKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// If a tweak is specified, then we'll need to use this tweak to derive
+	// the final private key to be used for signing this output.
+	if signDesc.PrivateTweak != nil {
+		privKey = lnwallet.DeriveRevocationPrivKey(privKey,
+			signDesc.PrivateTweak)
+	}
+
+	amt := signDesc.Output.Value
+	sig, err := txscript.RawTxInWitnessSignature(tx, signDesc.SigHashes,
+		signDesc.InputIndex, amt, signDesc.WitnessScript,
+		txscript.SigHashAll, privKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// Chop off the sighash flag at the end of the signature.
+	return sig[:len(sig)-1], nil
+}
+
+// ComputeInputScript generates a complete InputIndex for the passed
+// transaction with the signature as defined within the passed SignDescriptor.
+// Both p2wkh outputs, and p2wkh outputs nested within a p2sh output, are
+// supported.
+//
+// This is a part of the Signer interface.
+func (v *VirtualWallet) ComputeInputScript(tx *wire.MsgTx,
+	signDesc *lnwallet.SignDescriptor) (*lnwallet.InputScript, error) {
+
+	outputScript := signDesc.Output.PkScript
+
+	v.mtx.Lock()
+	owned, ok := v.scripts[string(outputScript)]
+	v.mtx.Unlock()
+	if !ok {
+		return nil, lnwallet.ErrNotMine
+	}
+
+	// If we're spending p2wkh output nested within a p2sh output, then
+	// we'll need to attach a sigScript, pushing the witness program, in
+	// addition to witness data. Otherwise, the output script is the
+	// witness program itself.
+	inputScript := &lnwallet.InputScript{}
+	witnessProgram := outputScript
+	if owned.nested {
+		witnessProgram = owned.witnessProgram
+
+		sigScript, err := txscript.NewScriptBuilder().
+			AddData(witnessProgram).Script()
+		if err != nil {
+			return nil, err
+		}
+		inputScript.ScriptSig = sigScript
+	}
+
+	witness, err := txscript.WitnessScript(tx, signDesc.SigHashes,
+		signDesc.InputIndex, signDesc.Output.Value, witnessProgram,
+		txscript.SigHashAll, owned.privKey, true)
+	if err != nil {
+		return nil, err
+	}
+	inputScript.Witness = witness
+
+	return inputScript, nil
+}
+
+// A compile time check to ensure that VirtualWallet implements the Signer
+// interface.
+var _ lnwallet.Signer = (*VirtualWallet)(nil)
@@ -0,0 +1,1119 @@
+package virtualwallet
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/virtualchain"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/txscript"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+	"github.com/roasbeef/btcutil/txsort"
+)
+
+const (
+	// maxInputSize is the largest virtual size of an input spending one
+	// of our outputs, which is that of an input spending a p2wkh output
+	// nested within a p2sh output.
+	maxInputSize = 32 + 4 + 1 + 23 + 4 + (1+1+73+1+33+3)/4
+
+	// changeOutputSize is the size of a p2wkh change output.
+	changeOutputSize = 8 + 1 + lnwallet.P2WPKHSize
+
+	// txOverheadSize is the virtual size of a transaction without its
+	// inputs and outputs, rounded up to include the segwit marker.
+	txOverheadSize = 4 + 1 + 1 + 4 + 1
+
+	// dustLimit is the value below which change is added to the fee
+	// rather than paid to a change output.
+	dustLimit = btcutil.Amount(546)
+
+	// retryInterval is the time we wait before polling the backend for
+	// events again after a failure.
+	retryInterval = time.Second
+)
+
+var (
+	// rootKeyTag is the tag the root key is derived with from the seed.
+	rootKeyTag = []byte("root")
+
+	// keyTag is the tag each numbered key is derived with from the seed,
+	// followed by the key's index.
+	keyTag = []byte("key")
+)
+
+// walletState is the persistent state of the wallet. Everything else is
+// rebuilt from the virtual chain as the wallet starts.
+type walletState struct {
+	Seed         []byte   `json:"seed"`
+	NextKeyIndex uint32   `json:"next_key_index"`
+	ImportedKeys [][]byte `json:"imported_keys"`
+}
+
+// ownedScript is an output script paying to one of our keys.
+type ownedScript struct {
+	privKey *btcec.PrivateKey
+
+	// nested is true if the script is a p2sh script nesting the p2wkh
+	// witness program below.
+	nested         bool
+	witnessProgram []byte
+}
+
+// walletTx is a transaction which spends from, or pays to, the wallet.
+type walletTx struct {
+	tx *wire.MsgTx
+
+	// height is the height of the block confirming the transaction, or -1
+	// if it's unconfirmed.
+	height    int32
+	blockHash *chainhash.Hash
+	timestamp int64
+
+	// value is the net value of the transaction from the PoV of the
+	// wallet.
+	value btcutil.Amount
+
+	// fee is the fee paid by the transaction, which is only known if all
+	// its inputs are ours.
+	fee int64
+}
+
+// walletUtxo is an unspent output of the wallet.
+type walletUtxo struct {
+	output *wire.TxOut
+
+	// height is the height of the block confirming the output, or -1 if
+	// it's unconfirmed.
+	height int32
+}
+
+// VirtualWallet is an implementation of the lnwallet.WalletController,
+// lnwallet.Signer, and lnwallet.BlockChainIO interfaces on top of a virtual
+// chain. Its keys are derived from a seed held in a plain file, and its
+// outputs are found by following the events of the chain from its genesis
+// block each time the wallet starts. It's meant solely for simulations.
+type VirtualWallet struct {
+	started int32 // To be used atomically.
+	stopped int32 // To be used atomically.
+
+	subscriptionCounter uint64 // To be used atomically.
+
+	cfg       *Config
+	statePath string
+
+	mtx   sync.Mutex
+	state walletState
+
+	rootKey *btcec.PrivateKey
+
+	// keys maps the compressed public key of each of our keys to its
+	// private key, while scripts maps each output script paying to one of
+	// our keys to the key.
+	keys    map[[33]byte]*btcec.PrivateKey
+	scripts map[string]*ownedScript
+
+	txs    map[chainhash.Hash]*walletTx
+	utxos  map[wire.OutPoint]*walletUtxo
+	locked map[wire.OutPoint]struct{}
+
+	// nextEvent is the index of the next event of the chain to process,
+	// and bestHeight the height of the last block processed.
+	nextEvent  uint64
+	bestHeight int32
+
+	subscriptions map[uint64]*txSubscription
+
+	wg   sync.WaitGroup
+	quit chan struct{}
+}
+
+// A compile time check to ensure that VirtualWallet implements the
+// WalletController interface.
+var _ lnwallet.WalletController = (*VirtualWallet)(nil)
+
+// New returns a new VirtualWallet instance following the virtual chain of
+// the passed config. If the data directory doesn't yet hold a wallet, then a
+// new one is created with a random seed.
+func New(cfg *Config) (*VirtualWallet, error) {
+	if cfg.FeeRate == 0 {
+		cfg.FeeRate = DefaultFeeRate
+	}
+
+	if err := os.MkdirAll(cfg.DataDir, 0700); err != nil {
+		return nil, err
+	}
+
+	v := &VirtualWallet{
+		cfg:           cfg,
+		statePath:     filepath.Join(cfg.DataDir, stateFilename),
+		keys:          make(map[[33]byte]*btcec.PrivateKey),
+		scripts:       make(map[string]*ownedScript),
+		txs:           make(map[chainhash.Hash]*walletTx),
+		utxos:         make(map[wire.OutPoint]*walletUtxo),
+		locked:        make(map[wire.OutPoint]struct{}),
+		subscriptions: make(map[uint64]*txSubscription),
+		quit:          make(chan struct{}),
+	}
+
+	stateBytes, err := ioutil.ReadFile(v.statePath)
+	switch {
+	case os.IsNotExist(err):
+		v.state.Seed = make([]byte, 32)
+		if _, err := rand.Read(v.state.Seed); err != nil {
+			return nil, err
+		}
+		if err := v.saveState(); err != nil {
+			return nil, err
+		}
+
+	case err != nil:
+		return nil, err
+
+	default:
+		if err := json.Unmarshal(stateBytes, &v.state); err != nil {
+			return nil, err
+		}
+	}
+
+	// With the state loaded, we re-derive all the keys we've handed out
+	// so far.
+	v.rootKey = v.deriveKey(rootKeyTag)
+	if err := v.registerKey(v.rootKey); err != nil {
+		return nil, err
+	}
+	for i := uint32(0); i < v.state.NextKeyIndex; i++ {
+		if err := v.registerKey(v.deriveKey(keyIndexTag(i))); err != nil {
+			return nil, err
+		}
+	}
+	for _, keyBytes := range v.state.ImportedKeys {
+		privKey, _ := btcec.PrivKeyFromBytes(btcec.S256(), keyBytes)
+		if err := v.registerKey(privKey); err != nil {
+			return nil, err
+		}
+	}
+
+	return v, nil
+}
+
+// saveState writes the persistent state of the wallet to its file.
+//
+// NOTE: The wallet's mutex MUST be held when calling this method, unless the
+// wallet is still being created.
+func (v *VirtualWallet) saveState() error {
+	stateBytes, err := json.Marshal(&v.state)
+	if err != nil {
+		return err
+	}
+
+	// The state is written to a temporary file first, so a crash never
+	// leaves a partially written state behind.
+	tempPath := v.statePath + ".tmp"
+	if err := ioutil.WriteFile(tempPath, stateBytes, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tempPath, v.statePath)
+}
+
+// keyIndexTag returns the tag the key with the passed index is derived with.
+func keyIndexTag(index uint32) []byte {
+	var indexBytes [4]byte
+	binary.BigEndian.PutUint32(indexBytes[:], index)
+	return append(append([]byte(nil), keyTag...), indexBytes[:]...)
+}
+
+// deriveKey derives the private key identified by the passed tag from the
+// seed of the wallet.
+func (v *VirtualWallet) deriveKey(tag []byte) *btcec.PrivateKey {
+	h := sha256.New()
+	h.Write(v.state.Seed)
+	h.Write(tag)
+
+	privKey, _ := btcec.PrivKeyFromBytes(btcec.S256(), h.Sum(nil))
+	return privKey
+}
+
+// registerKey adds the passed key to the keys of the wallet, along with the
+// p2wkh and nested p2wkh output scripts paying to it.
+//
+// NOTE: The wallet's mutex MUST be held when calling this method, unless the
+// wallet is still being created.
+func (v *VirtualWallet) registerKey(privKey *btcec.PrivateKey) error {
+	pubKeyBytes := privKey.PubKey().SerializeCompressed()
+
+	var pubKey [33]byte
+	copy(pubKey[:], pubKeyBytes)
+	v.keys[pubKey] = privKey
+
+	p2wkhAddr, err := btcutil.NewAddressWitnessPubKeyHash(
+		btcutil.Hash160(pubKeyBytes), v.cfg.NetParams)
+	if err != nil {
+		return err
+	}
+	p2wkhScript, err := txscript.PayToAddrScript(p2wkhAddr)
+	if err != nil {
+		return err
+	}
+	v.scripts[string(p2wkhScript)] = &ownedScript{
+		privKey: privKey,
+	}
+
+	nestedAddr, err := btcutil.NewAddressScriptHash(p2wkhScript,
+		v.cfg.NetParams)
+	if err != nil {
+		return err
+	}
+	nestedScript, err := txscript.PayToAddrScript(nestedAddr)
+	if err != nil {
+		return err
+	}
+	v.scripts[string(nestedScript)] = &ownedScript{
+		privKey:        privKey,
+		nested:         true,
+		witnessProgram: p2wkhScript,
+	}
+
+	return nil
+}
+
+// newKey derives, and registers, the next key of the wallet.
+//
+// NOTE: The wallet's mutex MUST be held when calling this method.
+func (v *VirtualWallet) newKey() (*btcec.PrivateKey, error) {
+	privKey := v.deriveKey(keyIndexTag(v.state.NextKeyIndex))
+
+	v.state.NextKeyIndex++
+	if err := v.saveState(); err != nil {
+		v.state.NextKeyIndex--
+		return nil, err
+	}
+
+	if err := v.registerKey(privKey); err != nil {
+		return nil, err
+	}
+
+	return privKey, nil
+}
+
+// fetchPrivKey returns the private key corresponding to the passed public
+// key.
+func (v *VirtualWallet) fetchPrivKey(pub *btcec.PublicKey) (*btcec.PrivateKey,
+	error) {
+
+	var pubKey [33]byte
+	copy(pubKey[:], pub.SerializeCompressed())
+
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+
+	privKey, ok := v.keys[pubKey]
+	if !ok {
+		return nil, fmt.Errorf("key %x not found", pubKey)
+	}
+
+	return privKey, nil
+}
+
+// Start catches up with the current state of the virtual chain, then launches
+// the goroutine following its events.
+//
+// This is a part of the WalletController interface.
+func (v *VirtualWallet) Start() error {
+	// Already started?
+	if atomic.AddInt32(&v.started, 1) != 1 {
+		return nil
+	}
+
+	// A closed channel makes the backend return the pending events
+	// without waiting for new ones.
+	noWait := make(chan struct{})
+	close(noWait)
+	for {
+		events, err := v.cfg.Backend.Events(v.nextEvent, noWait)
+		if err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			break
+		}
+
+		v.processEvents(events)
+	}
+
+	v.wg.Add(1)
+	go v.eventHandler()
+
+	return nil
+}
+
+// Stop signals the wallet for shutdown.
+//
+// This is a part of the WalletController interface.
+func (v *VirtualWallet) Stop() error {
+	// Already shutting down?
+	if atomic.AddInt32(&v.stopped, 1) != 1 {
+		return nil
+	}
+
+	close(v.quit)
+	v.wg.Wait()
+
+	return nil
+}
+
+// eventHandler follows the events of the virtual chain, updating the
+// transactions and outputs of the wallet.
+//
+// NOTE: This MUST be run as a goroutine.
+func (v *VirtualWallet) eventHandler() {
+	defer v.wg.Done()
+
+	for {
+		v.mtx.Lock()
+		next := v.nextEvent
+		v.mtx.Unlock()
+
+		events, err := v.cfg.Backend.Events(next, v.quit)
+		if err != nil {
+			select {
+			case <-time.After(retryInterval):
+				continue
+			case <-v.quit:
+				return
+			}
+		}
+
+		select {
+		case <-v.quit:
+			return
+		default:
+		}
+
+		v.processEvents(events)
+	}
+}
+
+// processEvents applies the passed events of the virtual chain to the wallet.
+func (v *VirtualWallet) processEvents(events []*virtualchain.Event) {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+
+	for _, event := range events {
+		v.nextEvent++
+
+		switch {
+		case event.Accepted != nil:
+			v.applyTx(event.Accepted, -1, nil, time.Now().Unix())
+
+		case event.Evicted != nil:
+			v.evictTx(*event.Evicted)
+
+		case event.Block != nil:
+			v.bestHeight++
+
+			block := event.Block
+			blockHash := block.BlockHash()
+			timestamp := block.Header.Timestamp.Unix()
+			for _, tx := range block.Transactions {
+				v.applyTx(tx, v.bestHeight, &blockHash, timestamp)
+			}
+		}
+	}
+}
+
+// applyTx applies the passed transaction, confirmed at the passed height or
+// unconfirmed if the height is -1, to the wallet if it's relevant to it.
+// Applying a transaction again, once it confirms, only updates its height.
+//
+// NOTE: The wallet's mutex MUST be held when calling this method.
+func (v *VirtualWallet) applyTx(tx *wire.MsgTx, height int32,
+	blockHash *chainhash.Hash, timestamp int64) {
+
+	txid := tx.TxHash()
+
+	wtx, ok := v.txs[txid]
+	switch {
+	// We already know of this transaction. If it has now confirmed, then
+	// we update its outputs, and notify our subscribers.
+	case ok:
+		if height == -1 || wtx.height != -1 {
+			return
+		}
+
+		wtx.height = height
+		wtx.blockHash = blockHash
+		wtx.timestamp = timestamp
+		for i := range tx.TxOut {
+			outpoint := wire.OutPoint{Hash: txid, Index: uint32(i)}
+			if utxo, ok := v.utxos[outpoint]; ok {
+				utxo.height = height
+			}
+		}
+
+	default:
+		var (
+			inputValue, outputValue int64
+			ownInputs               int
+		)
+		for _, txIn := range tx.TxIn {
+			utxo, ok := v.utxos[txIn.PreviousOutPoint]
+			if !ok {
+				continue
+			}
+
+			inputValue += utxo.output.Value
+			ownInputs++
+		}
+
+		var ownValue int64
+		for _, txOut := range tx.TxOut {
+			outputValue += txOut.Value
+			if _, ok := v.scripts[string(txOut.PkScript)]; ok {
+				ownValue += txOut.Value
+			}
+		}
+
+		if ownInputs == 0 && ownValue == 0 {
+			return
+		}
+
+		wtx = &walletTx{
+			tx:        tx,
+			height:    height,
+			blockHash: blockHash,
+			timestamp: timestamp,
+			value:     btcutil.Amount(ownValue - inputValue),
+		}
+		if ownInputs == len(tx.TxIn) {
+			wtx.fee = inputValue - outputValue
+		}
+		v.txs[txid] = wtx
+
+		for _, txIn := range tx.TxIn {
+			delete(v.utxos, txIn.PreviousOutPoint)
+			delete(v.locked, txIn.PreviousOutPoint)
+		}
+		for i, txOut := range tx.TxOut {
+			if _, ok := v.scripts[string(txOut.PkScript)]; !ok {
+				continue
+			}
+
+			outpoint := wire.OutPoint{Hash: txid, Index: uint32(i)}
+			v.utxos[outpoint] = &walletUtxo{
+				output: txOut,
+				height: height,
+			}
+		}
+	}
+
+	detail := v.txDetail(txid, wtx)
+	for _, subscription := range v.subscriptions {
+		subscription.enqueue(detail, height != -1)
+	}
+}
+
+// evictTx removes the passed transaction, which was evicted from the
+// mempool, from the wallet, restoring the outputs it spent.
+//
+// NOTE: The wallet's mutex MUST be held when calling this method.
+func (v *VirtualWallet) evictTx(txid chainhash.Hash) {
+	wtx, ok := v.txs[txid]
+	if !ok || wtx.height != -1 {
+		return
+	}
+	delete(v.txs, txid)
+
+	for i := range wtx.tx.TxOut {
+		delete(v.utxos, wire.OutPoint{Hash: txid, Index: uint32(i)})
+	}
+
+	for _, txIn := range wtx.tx.TxIn {
+		prevOut := txIn.PreviousOutPoint
+		prevTx, ok := v.txs[prevOut.Hash]
+		if !ok {
+			continue
+		}
+
+		output := prevTx.tx.TxOut[prevOut.Index]
+		if _, ok := v.scripts[string(output.PkScript)]; !ok {
+			continue
+		}
+
+		v.utxos[prevOut] = &walletUtxo{
+			output: output,
+			height: prevTx.height,
+		}
+	}
+}
+
+// txDetail returns the TransactionDetail describing the passed transaction.
+//
+// NOTE: The wallet's mutex MUST be held when calling this method.
+func (v *VirtualWallet) txDetail(txid chainhash.Hash,
+	wtx *walletTx) *lnwallet.TransactionDetail {
+
+	detail := &lnwallet.TransactionDetail{
+		Hash:      txid,
+		Value:     wtx.value,
+		BlockHash: wtx.blockHash,
+		Timestamp: wtx.timestamp,
+		TotalFees: wtx.fee,
+	}
+	if wtx.height != -1 {
+		detail.NumConfirmations = v.bestHeight - wtx.height + 1
+		detail.BlockHeight = wtx.height
+	}
+
+	return detail
+}
+
+// FetchInputInfo queries for the wallet's knowledge of the passed outpoint.
+// If the output pays to one of our keys, then the original txout is
+// returned. Otherwise, ErrNotMine is returned.
+//
+// This is a part of the WalletController interface.
+func (v *VirtualWallet) FetchInputInfo(prevOut *wire.OutPoint) (*wire.TxOut,
+	error) {
+
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+
+	wtx, ok := v.txs[prevOut.Hash]
+	if !ok || int(prevOut.Index) >= len(wtx.tx.TxOut) {
+		return nil, lnwallet.ErrNotMine
+	}
+
+	output := wtx.tx.TxOut[prevOut.Index]
+	if _, ok := v.scripts[string(output.PkScript)]; !ok {
+		return nil, lnwallet.ErrNotMine
+	}
+
+	return output, nil
+}
+
+// ConfirmedBalance returns the sum of all the wallet's unspent outputs that
+// have at least confs confirmations. As all outputs of the wallet pay to
+// witness programs, the witness flag has no effect.
+//
+// This is a part of the WalletController interface.
+func (v *VirtualWallet) ConfirmedBalance(confs int32,
+	witness bool) (btcutil.Amount, error) {
+
+	utxos, err := v.ListUnspentWitness(confs)
+	if err != nil {
+		return 0, err
+	}
+
+	var balance btcutil.Amount
+	for _, utxo := range utxos {
+		balance += utxo.Value
+	}
+
+	return balance, nil
+}
+
+// NewAddress returns a new address paying to a fresh key. Only p2wkh, and
+// nested p2wkh, addresses are supported.
+//
+// This is a part of the WalletController interface.
+func (v *VirtualWallet) NewAddress(addrType lnwallet.AddressType,
+	change bool) (btcutil.Address, error) {
+
+	if addrType != lnwallet.WitnessPubKey &&
+		addrType != lnwallet.NestedWitnessPubKey {
+
+		return nil, fmt.Errorf("unsupported address type %v", addrType)
+	}
+
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+
+	privKey, err := v.newKey()
+	if err != nil {
+		return nil, err
+	}
+
+	p2wkhAddr, err := btcutil.NewAddressWitnessPubKeyHash(
+		btcutil.Hash160(privKey.PubKey().SerializeCompressed()),
+		v.cfg.NetParams)
+	if err != nil {
+		return nil, err
+	}
+	if addrType == lnwallet.WitnessPubKey {
+		return p2wkhAddr, nil
+	}
+
+	p2wkhScript, err := txscript.PayToAddrScript(p2wkhAddr)
+	if err != nil {
+		return nil, err
+	}
+	return btcutil.NewAddressScriptHash(p2wkhScript, v.cfg.NetParams)
+}
+
+// GetPrivKey retrieves the underlying private key associated with the passed
+// address. If the address isn't ours, then ErrNotMine is returned.
+//
+// This is a part of the WalletController interface.
+func (v *VirtualWallet) GetPrivKey(a btcutil.Address) (*btcec.PrivateKey,
+	error) {
+
+	pkScript, err := txscript.PayToAddrScript(a)
+	if err != nil {
+		return nil, err
+	}
+
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+
+	owned, ok := v.scripts[string(pkScript)]
+	if !ok {
+		return nil, lnwallet.ErrNotMine
+	}
+
+	return owned.privKey, nil
+}
+
+// NewRawKey returns a fresh public key of the wallet.
+//
+// This is a part of the WalletController interface.
+func (v *VirtualWallet) NewRawKey() (*btcec.PublicKey, error) {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+
+	privKey, err := v.newKey()
+	if err != nil {
+		return nil, err
+	}
+
+	return privKey.PubKey(), nil
+}
+
+// FetchRootKey returns the root key of the wallet, which is derived from its
+// seed.
+//
+// This is a part of the WalletController interface.
+func (v *VirtualWallet) FetchRootKey() (*btcec.PrivateKey, error) {
+	return v.rootKey, nil
+}
+
+// ImportPrivateKey imports the passed private key into the wallet, such that
+// outputs paying to it are tracked, and may be signed for.
+//
+// This is a part of the WalletController interface.
+func (v *VirtualWallet) ImportPrivateKey(key *btcec.PrivateKey) error {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+
+	var pubKey [33]byte
+	copy(pubKey[:], key.PubKey().SerializeCompressed())
+	if _, ok := v.keys[pubKey]; ok {
+		return nil
+	}
+
+	v.state.ImportedKeys = append(v.state.ImportedKeys, key.Serialize())
+	if err := v.saveState(); err != nil {
+		v.state.ImportedKeys = v.state.ImportedKeys[:len(v.state.ImportedKeys)-1]
+		return err
+	}
+
+	return v.registerKey(key)
+}
+
+// coin is an output available to coin selection.
+type coin struct {
+	outpoint wire.OutPoint
+	output   *wire.TxOut
+}
+
+// coinsByValue sorts coins by decreasing value.
+type coinsByValue []*coin
+
+func (c coinsByValue) Len() int           { return len(c) }
+func (c coinsByValue) Less(i, j int) bool { return c[i].output.Value > c[j].output.Value }
+func (c coinsByValue) Swap(i, j int)      { c[i], c[j] = c[j], c[i] }
+
+// SendOutputs funds, signs, and publishes a transaction paying to the passed
+// outputs. Confirmed outputs are selected largest first, and change below the
+// dust limit is added to the fee.
+//
+// This is a part of the WalletController interface.
+func (v *VirtualWallet) SendOutputs(outputs []*wire.TxOut) (*chainhash.Hash,
+	error) {
+
+	v.mtx.Lock()
+
+	tx := wire.NewMsgTx(2)
+	size := int64(txOverheadSize)
+	var outputValue btcutil.Amount
+	for _, output := range outputs {
+		tx.AddTxOut(output)
+		size += int64(output.SerializeSize())
+		outputValue += btcutil.Amount(output.Value)
+	}
+
+	// Gather our confirmed, unlocked, outputs, and select from them
+	// largest first until they cover the outputs along with the fee.
+	var coins coinsByValue
+	for outpoint, utxo := range v.utxos {
+		if _, ok := v.locked[outpoint]; ok || utxo.height == -1 {
+			continue
+		}
+		coins = append(coins, &coin{
+			outpoint: outpoint,
+			output:   utxo.output,
+		})
+	}
+	sort.Sort(coins)
+
+	var (
+		inputValue btcutil.Amount
+		fee        btcutil.Amount
+		prevOuts   = make(map[wire.OutPoint]*wire.TxOut)
+	)
+	for _, coin := range coins {
+		if inputValue >= outputValue+fee {
+			break
+		}
+
+		tx.AddTxIn(wire.NewTxIn(&coin.outpoint, nil, nil))
+		prevOuts[coin.outpoint] = coin.output
+		inputValue += btcutil.Amount(coin.output.Value)
+		size += maxInputSize
+
+		fee = v.cfg.FeeRate * btcutil.Amount(size+changeOutputSize)
+	}
+	if len(tx.TxIn) == 0 || inputValue < outputValue+fee {
+		v.mtx.Unlock()
+		return nil, fmt.Errorf("insufficient funds: need %v, only "+
+			"have %v available", outputValue+fee, inputValue)
+	}
+
+	if change := inputValue - outputValue - fee; change >= dustLimit {
+		changeKey, err := v.newKey()
+		if err != nil {
+			v.mtx.Unlock()
+			return nil, err
+		}
+		changeAddr, err := btcutil.NewAddressWitnessPubKeyHash(
+			btcutil.Hash160(changeKey.PubKey().SerializeCompressed()),
+			v.cfg.NetParams)
+		if err != nil {
+			v.mtx.Unlock()
+			return nil, err
+		}
+		changeScript, err := txscript.PayToAddrScript(changeAddr)
+		if err != nil {
+			v.mtx.Unlock()
+			return nil, err
+		}
+		tx.AddTxOut(wire.NewTxOut(int64(change), changeScript))
+	}
+
+	// Lock the selected outputs until the transaction is published, so
+	// concurrent callers don't select them too.
+	for outpoint := range prevOuts {
+		v.locked[outpoint] = struct{}{}
+	}
+	v.mtx.Unlock()
+
+	unlock := func() {
+		v.mtx.Lock()
+		for outpoint := range prevOuts {
+			delete(v.locked, outpoint)
+		}
+		v.mtx.Unlock()
+	}
+
+	txsort.InPlaceSort(tx)
+	sigHashes := txscript.NewTxSigHashes(tx)
+	for i, txIn := range tx.TxIn {
+		inputScript, err := v.ComputeInputScript(tx,
+			&lnwallet.SignDescriptor{
+				Output:     prevOuts[txIn.PreviousOutPoint],
+				HashType:   txscript.SigHashAll,
+				SigHashes:  sigHashes,
+				InputIndex: i,
+			})
+		if err != nil {
+			unlock()
+			return nil, err
+		}
+
+		txIn.SignatureScript = inputScript.ScriptSig
+		txIn.Witness = inputScript.Witness
+	}
+
+	if err := v.PublishTransaction(tx); err != nil {
+		unlock()
+		return nil, err
+	}
+
+	txid := tx.TxHash()
+	return &txid, nil
+}
+
+// LockOutpoint marks an outpoint as locked, meaning it will no longer be
+// deemed as eligible for coin selection.
+//
+// This is a part of the WalletController interface.
+func (v *VirtualWallet) LockOutpoint(o wire.OutPoint) {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+
+	v.locked[o] = struct{}{}
+}
+
+// UnlockOutpoint unlocks a previously locked output, marking it eligible for
+// coin selection.
+//
+// This is a part of the WalletController interface.
+func (v *VirtualWallet) UnlockOutpoint(o wire.OutPoint) {
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+
+	delete(v.locked, o)
+}
+
+// ListUnspentWitness returns all the unlocked outputs of the wallet with at
+// least the passed number of confirmations. If minConfs is zero or below,
+// unconfirmed outputs are included.
+//
+// This is a part of the WalletController interface.
+func (v *VirtualWallet) ListUnspentWitness(minConfs int32) ([]*lnwallet.Utxo,
+	error) {
+
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+
+	utxos := make([]*lnwallet.Utxo, 0, len(v.utxos))
+	for outpoint, utxo := range v.utxos {
+		if _, ok := v.locked[outpoint]; ok {
+			continue
+		}
+
+		var confs int32
+		if utxo.height != -1 {
+			confs = v.bestHeight - utxo.height + 1
+		}
+		if confs < minConfs {
+			continue
+		}
+
+		utxos = append(utxos, &lnwallet.Utxo{
+			Value:    btcutil.Amount(utxo.output.Value),
+			OutPoint: outpoint,
+		})
+	}
+
+	return utxos, nil
+}
+
+// ListTransactionDetails returns a list of all transactions which are
+// relevant to the wallet.
+//
+// This is a part of the WalletController interface.
+func (v *VirtualWallet) ListTransactionDetails() ([]*lnwallet.TransactionDetail,
+	error) {
+
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+
+	details := make([]*lnwallet.TransactionDetail, 0, len(v.txs))
+	for txid, wtx := range v.txs {
+		details = append(details, v.txDetail(txid, wtx))
+	}
+
+	return details, nil
+}
+
+// PublishTransaction publishes the passed transaction to the virtual chain,
+// then applies it to the wallet right away.
+//
+// This is a part of the WalletController interface.
+func (v *VirtualWallet) PublishTransaction(tx *wire.MsgTx) error {
+	if err := v.cfg.Backend.Publish(tx); err != nil {
+		return err
+	}
+
+	v.mtx.Lock()
+	v.applyTx(tx, -1, nil, time.Now().Unix())
+	v.mtx.Unlock()
+
+	return nil
+}
+
+// EstimateFeePerByte returns the configured fee rate, as the virtual chain
+// has no fee market to estimate from.
+//
+// This is a part of the WalletController interface.
+func (v *VirtualWallet) EstimateFeePerByte(numBlocks uint32) (btcutil.Amount,
+	error) {
+
+	return v.cfg.FeeRate, nil
+}
+
+// IsSynced returns true once the wallet has processed the tip of the virtual
+// chain.
+//
+// This is a part of the WalletController interface.
+func (v *VirtualWallet) IsSynced() (bool, error) {
+	_, bestHeight, err := v.cfg.Backend.BestBlock()
+	if err != nil {
+		return false, err
+	}
+
+	v.mtx.Lock()
+	defer v.mtx.Unlock()
+
+	return v.bestHeight >= bestHeight, nil
+}
+
+// txNotification is a transaction notification queued for a subscription.
+type txNotification struct {
+	detail    *lnwallet.TransactionDetail
+	confirmed bool
+}
+
+// txSubscription is a lnwallet.TransactionSubscription delivering the
+// notifications queued for it in order, by a goroutine dedicated to it.
+type txSubscription struct {
+	confirmed   chan *lnwallet.TransactionDetail
+	unconfirmed chan *lnwallet.TransactionDetail
+
+	mtx    sync.Mutex
+	queue  []*txNotification
+	signal chan struct{}
+
+	cancel func()
+
+	wg   sync.WaitGroup
+	quit chan struct{}
+}
+
+// ConfirmedTransactions returns a channel which will be sent on as new
+// relevant transactions are confirmed.
+//
+// This is part of the TransactionSubscription interface.
+func (t *txSubscription) ConfirmedTransactions() chan *lnwallet.TransactionDetail {
+	return t.confirmed
+}
+
+// UnconfirmedTransactions returns a channel which will be sent on as new
+// relevant transactions are seen within the network.
+//
+// This is part of the TransactionSubscription interface.
+func (t *txSubscription) UnconfirmedTransactions() chan *lnwallet.TransactionDetail {
+	return t.unconfirmed
+}
+
+// Cancel finalizes the subscription, cleaning up any resources allocated.
+//
+// This is part of the TransactionSubscription interface.
+func (t *txSubscription) Cancel() {
+	t.cancel()
+
+	close(t.quit)
+	t.wg.Wait()
+}
+
+// enqueue queues the passed notification for delivery.
+func (t *txSubscription) enqueue(detail *lnwallet.TransactionDetail,
+	confirmed bool) {
+
+	t.mtx.Lock()
+	t.queue = append(t.queue, &txNotification{
+		detail:    detail,
+		confirmed: confirmed,
+	})
+	t.mtx.Unlock()
+
+	select {
+	case t.signal <- struct{}{}:
+	default:
+	}
+}
+
+// notificationDispatcher delivers the queued notifications in order.
+//
+// NOTE: This MUST be run as a goroutine.
+func (t *txSubscription) notificationDispatcher(walletQuit chan struct{}) {
+	defer t.wg.Done()
+
+	for {
+		t.mtx.Lock()
+		if len(t.queue) == 0 {
+			t.mtx.Unlock()
+
+			select {
+			case <-t.signal:
+				continue
+			case <-t.quit:
+				return
+			case <-walletQuit:
+				return
+			}
+		}
+		ntfn := t.queue[0]
+		t.queue = t.queue[1:]
+		t.mtx.Unlock()
+
+		ntfnChan := t.unconfirmed
+		if ntfn.confirmed {
+			ntfnChan = t.confirmed
+		}
+
+		select {
+		case ntfnChan <- ntfn.detail:
+		case <-t.quit:
+			return
+		case <-walletQuit:
+			return
+		}
+	}
+}
+
+// SubscribeTransactions returns a TransactionSubscription client which is
+// notified of each transaction relevant to the wallet as it's accepted to the
+// mempool, and again once it's confirmed.
+//
+// This is a part of the WalletController interface.
+func (v *VirtualWallet) SubscribeTransactions() (lnwallet.TransactionSubscription,
+	error) {
+
+	subscription := &txSubscription{
+		confirmed:   make(chan *lnwallet.TransactionDetail),
+		unconfirmed: make(chan *lnwallet.TransactionDetail),
+		signal:      make(chan struct{}, 1),
+		quit:        make(chan struct{}),
+	}
+
+	id := atomic.AddUint64(&v.subscriptionCounter, 1)
+	subscription.cancel = func() {
+		v.mtx.Lock()
+		delete(v.subscriptions, id)
+		v.mtx.Unlock()
+	}
+
+	v.mtx.Lock()
+	v.subscriptions[id] = subscription
+	v.mtx.Unlock()
+
+	subscription.wg.Add(1)
+	go subscription.notificationDispatcher(v.quit)
+
+	return subscription, nil
+}
@@ -31,13 +31,26 @@ const (
 	revocationRootIndex = hdkeychain.HardenedKeyStart + 1
 
 	// identityKeyIndex is the top level HD key index which is used to
-	// generate/rotate identity keys.
-	//
-	// TODO(roasbeef): should instead be child to make room for future
-	// rotations, etc.
+	// generate/rotate identity keys. The original identity key is the key
+	// at this index, while the identity key of each later generation is
+	// its hardened child at the generation's number.
 	identityKeyIndex = hdkeychain.HardenedKeyStart + 2
 
+	// invoicePreimageIndex is the top level HD key index beneath which
+	// the preimages of invoices are derived, with each invoice using the
+	// hardened child at its invoice ID.
+	invoicePreimageIndex = hdkeychain.HardenedKeyStart + 3
+
+	// commitFee is the fee paid by the initial commitment transaction.
+	// It's set aside from the channel's capacity at funding time, after
+	// which the initiator may propose a new fee via an UpdateFee message.
 	commitFee = 5000
+
+	// fundingFeeRate is the fee rate in satoshis per byte paid by the
+	// inputs we contribute to funding transactions.
+	//
+	// TODO(roasbeef): consult model for proper fee rate on funding tx
+	fundingFeeRate = 10
 )
 
 var (
@@ -92,6 +105,14 @@ type initFundingReserveMsg struct {
 	// the remote party contributes (if any).
 	capacity btcutil.Amount
 
+	// dualFunder indicates that both parties contribute inputs to the
+	// funding transaction. If set, theirFundingAmount is the remote
+	// party's contribution, and initiator denotes whether we proposed the
+	// channel. The capacity field is unused for such reservations.
+	dualFunder         bool
+	initiator          bool
+	theirFundingAmount btcutil.Amount
+
 	// The minimum accepted satoshis/KB fee for the funding transaction. In
 	// order to ensure timely confirmation, it is recomened that this fee
 	// should be generous, paying some multiple of the accepted base fee
@@ -273,10 +294,19 @@ type LightningWallet struct {
 	// update the commitment state.
 	Signer Signer
 
+	// FundingKeys, if non-nil, is the source of our multi-sig keys within
+	// the funding outputs of new channels. Otherwise, funding keys are
+	// obtained from the wallet itself.
+	FundingKeys FundingKeyRing
+
 	// ChainIO is an instance of the BlockChainIO interface. ChainIO is
 	// used to lookup the existence of outputs within the UTXO set.
 	ChainIO BlockChainIO
 
+	// FeeCeiling, if non-nil, bounds the fee of each transaction broadcast
+	// by the wallet. See FeeCeiling for further details.
+	FeeCeiling *FeeCeiling
+
 	// rootKey is the root HD key derived from a WalletController private
 	// key. This rootKey is used to derive all LN specific secrets.
 	rootKey *hdkeychain.ExtendedKey
@@ -301,6 +331,12 @@ type LightningWallet struct {
 	// the currently locked outpoints.
 	lockedOutPoints map[wire.OutPoint]struct{}
 
+	// fundingInputs maps the funding outpoint of each channel funded by
+	// the wallet since startup to the wallet's inputs within the funding
+	// transaction, which remain locked. Should the channel be abandoned,
+	// the inputs are unlocked so they may be spent once again.
+	fundingInputs map[wire.OutPoint][]wire.OutPoint
+
 	netParams *chaincfg.Params
 
 	started  int32
@@ -349,6 +385,7 @@ func NewLightningWallet(cdb *channeldb.DB, notifier chainntnfs.ChainNotifier,
 		nextFundingID:    0,
 		fundingLimbo:     make(map[uint64]*ChannelReservation),
 		lockedOutPoints:  make(map[wire.OutPoint]struct{}),
+		fundingInputs:    make(map[wire.OutPoint][]wire.OutPoint),
 		quit:             make(chan struct{}),
 	}, nil
 }
@@ -410,6 +447,29 @@ func (l *LightningWallet) ResetReservations() {
 		l.UnlockOutpoint(outpoint)
 	}
 	l.lockedOutPoints = make(map[wire.OutPoint]struct{})
+	l.fundingInputs = make(map[wire.OutPoint][]wire.OutPoint)
+}
+
+// UnlockFundingInputs unlocks the wallet's inputs within the funding
+// transaction of the channel with the passed funding outpoint, so they may be
+// spent once again. It's to be called once the channel has been abandoned. The
+// unlocked outpoints are returned, which are empty if the channel wasn't
+// funded by the wallet since startup, as outpoints aren't locked across
+// restarts.
+func (l *LightningWallet) UnlockFundingInputs(
+	fundingPoint wire.OutPoint) []wire.OutPoint {
+
+	l.limboMtx.Lock()
+	defer l.limboMtx.Unlock()
+
+	inputs := l.fundingInputs[fundingPoint]
+	for _, input := range inputs {
+		delete(l.lockedOutPoints, input)
+		l.UnlockOutpoint(input)
+	}
+	delete(l.fundingInputs, fundingPoint)
+
+	return inputs
 }
 
 // ActiveReservations returns a slice of all the currently active
@@ -423,17 +483,68 @@ func (l *LightningWallet) ActiveReservations() []*ChannelReservation {
 	return reservations
 }
 
-// GetIdentitykey returns the identity private key of the wallet.
+// GetIdentitykey returns the current identity private key of the wallet, as
+// determined by the generation of the identity recorded within the database.
 // TODO(roasbeef): should be moved elsewhere
 func (l *LightningWallet) GetIdentitykey() (*btcec.PrivateKey, error) {
+	gen, err := l.ChannelDB.FetchIdentityGeneration()
+	if err != nil {
+		return nil, err
+	}
+
+	return l.DeriveIdentityKey(gen)
+}
+
+// DeriveIdentityKey derives the identity private key of the passed generation.
+// Generation zero is the wallet's original identity key, and each rotation of
+// the identity key moves on to the next generation.
+func (l *LightningWallet) DeriveIdentityKey(gen uint32) (*btcec.PrivateKey, error) {
 	identityKey, err := l.rootKey.Child(identityKeyIndex)
 	if err != nil {
 		return nil, err
 	}
+	if gen > 0 {
+		identityKey, err = identityKey.Child(
+			hdkeychain.HardenedKeyStart + gen)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	return identityKey.ECPrivKey()
 }
 
+// DeriveInvoicePreimage deterministically derives the payment preimage of the
+// invoice with the passed invoice ID from the wallet's root key. As the
+// preimage depends only upon the wallet's seed and the invoice ID, it can be
+// recovered should the invoice database be lost.
+func (l *LightningWallet) DeriveInvoicePreimage(addIndex uint32) ([32]byte, error) {
+	var preimage [32]byte
+
+	if addIndex >= hdkeychain.HardenedKeyStart {
+		return preimage, fmt.Errorf("invoice index %v is too large to "+
+			"derive a preimage for", addIndex)
+	}
+
+	invoiceRoot, err := l.rootKey.Child(invoicePreimageIndex)
+	if err != nil {
+		return preimage, err
+	}
+	invoiceKey, err := invoiceRoot.Child(hdkeychain.HardenedKeyStart +
+		addIndex)
+	if err != nil {
+		return preimage, err
+	}
+	privKey, err := invoiceKey.ECPrivKey()
+	if err != nil {
+		return preimage, err
+	}
+
+	// The key itself is hashed, so revealing the preimage doesn't reveal
+	// any key within the wallet's hierarchy.
+	return sha256.Sum256(privKey.Serialize()), nil
+}
+
 // requestHandler is the primary goroutine(s) responsible for handling, and
 // dispatching relies to all messages.
 func (l *LightningWallet) requestHandler() {
@@ -509,21 +620,85 @@ func (l *LightningWallet) InitChannelReservation(capacity,
 	return <-respChan, <-errChan
 }
 
+// InitDualChannelReservation kicks off the workflow required to open a payment
+// channel to which both we and the remote node contribute funds. The capacity
+// of the channel is the sum of both contributions, and the fee of the initial
+// commitment transaction is split between the two parties in proportion to
+// their contributions. As with InitChannelReservation, the inputs selected to
+// fund our contribution are 'locked' for the lifetime of the reservation.
+//
+// The remaining steps mirror those of InitChannelReservation: the remote
+// party's contribution, including their inputs and change outputs, is
+// processed via ProcessContribution, and their signatures for both their
+// inputs and our commitment transaction via CompleteReservation.
+func (l *LightningWallet) InitDualChannelReservation(ourFundAmt,
+	theirFundAmt btcutil.Amount, initiator bool,
+	theirID *btcec.PublicKey, theirAddr *net.TCPAddr, numConfs uint16,
+	csvDelay uint32, ourDustLimit btcutil.Amount) (*ChannelReservation, error) {
+
+	errChan := make(chan error, 1)
+	respChan := make(chan *ChannelReservation, 1)
+
+	l.msgChan <- &initFundingReserveMsg{
+		dualFunder:         true,
+		initiator:          initiator,
+		numConfs:           numConfs,
+		fundingAmount:      ourFundAmt,
+		theirFundingAmount: theirFundAmt,
+		csvDelay:           csvDelay,
+		ourDustLimit:       ourDustLimit,
+		nodeID:             theirID,
+		nodeAddr:           theirAddr,
+		err:                errChan,
+		resp:               respChan,
+	}
+
+	return <-respChan, <-errChan
+}
+
 // handleFundingReserveRequest processes a message intending to create, and
 // validate a funding reservation request.
 func (l *LightningWallet) handleFundingReserveRequest(req *initFundingReserveMsg) {
 	// It isn't possible to create a channel with zero funds committed.
-	if req.fundingAmount+req.capacity == 0 {
+	if req.fundingAmount+req.capacity+req.theirFundingAmount == 0 {
 		req.err <- fmt.Errorf("cannot have channel with zero " +
 			"satoshis funded")
 		req.resp <- nil
 		return
 	}
 
+	// Each party to a dual funder channel must contribute enough to pay
+	// their share of the commitment fee, while retaining a balance within
+	// the channel.
+	if req.dualFunder && (req.fundingAmount <= commitFee ||
+		req.theirFundingAmount <= commitFee) {
+
+		req.err <- fmt.Errorf("each party to a dual funded channel "+
+			"must contribute more than %v", btcutil.Amount(commitFee))
+		req.resp <- nil
+		return
+	}
+
+	// The amount we need to select coins for is our contribution to the
+	// channel. For a single funder channel we initiate, this also includes
+	// the commitment fee, which is paid on top of the requested capacity.
 	id := atomic.AddUint64(&l.nextFundingID, 1)
-	totalCapacity := req.capacity + commitFee
-	reservation := NewChannelReservation(totalCapacity, req.fundingAmount,
-		req.minFeeRate, l, id, req.numConfs, req.pushSat)
+	fundingAmt := req.fundingAmount
+	var reservation *ChannelReservation
+	if req.dualFunder {
+		reservation = newDualChannelReservation(req.fundingAmount,
+			req.theirFundingAmount, req.initiator, req.minFeeRate, l,
+			id, req.numConfs)
+	} else {
+		totalCapacity := req.capacity + commitFee
+		reservation = NewChannelReservation(totalCapacity,
+			req.fundingAmount, req.minFeeRate, l, id, req.numConfs,
+			req.pushSat)
+
+		if fundingAmt != 0 {
+			fundingAmt += commitFee
+		}
+	}
 
 	// Grab the mutex on the ChannelReservation to ensure thread-safety
 	reservation.Lock()
@@ -542,12 +717,9 @@ func (l *LightningWallet) handleFundingReserveRequest(req *initFundingReserveMsg
 	// If we're on the receiving end of a single funder channel then we
 	// don't need to perform any coin selection. Otherwise, attempt to
 	// obtain enough coins to meet the required funding amount.
-	if req.fundingAmount != 0 {
-		// TODO(roasbeef): consult model for proper fee rate on funding
-		// tx
-		feeRate := uint64(10)
-		amt := req.fundingAmount + commitFee
-		err := l.selectCoinsAndChange(feeRate, amt, ourContribution)
+	if fundingAmt != 0 {
+		err := l.selectCoinsAndChange(fundingFeeRate, fundingAmt,
+			ourContribution)
 		if err != nil {
 			req.err <- err
 			req.resp <- nil
@@ -555,10 +727,19 @@ func (l *LightningWallet) handleFundingReserveRequest(req *initFundingReserveMsg
 		}
 	}
 
-	// Grab two fresh keys from our HD chain, one will be used for the
-	// multi-sig funding transaction, and the other for the commitment
-	// transaction.
-	multiSigKey, err := l.NewRawKey()
+	// Grab two fresh keys, one will be used for the multi-sig funding
+	// transaction, and the other for the commitment transaction. If an
+	// external funding key ring is configured, then the multi-sig key is
+	// obtained from it, otherwise both keys come from our HD chain.
+	var (
+		multiSigKey *btcec.PublicKey
+		err         error
+	)
+	if l.FundingKeys != nil {
+		multiSigKey, err = l.FundingKeys.NewFundingKey()
+	} else {
+		multiSigKey, err = l.NewRawKey()
+	}
 	if err != nil {
 		req.err <- err
 		req.resp <- nil
@@ -670,6 +851,18 @@ func (l *LightningWallet) handleContributionMsg(req *addContributionMsg) {
 	theirContribution := req.contribution
 	ourContribution := pendingReservation.ourContribution
 
+	// If the remote party also funds this channel, then before signing
+	// anything we ensure that the inputs they've presented are sufficient
+	// to cover the amount they've agreed to contribute.
+	if pendingReservation.partialState.ChanType == channeldb.DualFunder {
+		err := l.verifyContributionFunds(theirContribution,
+			pendingReservation.theirFundingAmt)
+		if err != nil {
+			req.err <- err
+			return
+		}
+	}
+
 	// Add all multi-party inputs and outputs to the transaction.
 	for _, ourInput := range ourContribution.Inputs {
 		fundingTx.AddTxIn(ourInput)
@@ -796,6 +989,25 @@ func (l *LightningWallet) handleContributionMsg(req *addContributionMsg) {
 		return
 	}
 
+	// If the channel uses anchor commitments, then both commitment
+	// transactions carry an anchor output for each party with a balance.
+	if pendingReservation.partialState.CommitFormat == channeldb.AnchorCommitment {
+		err := addCommitAnchors(ourCommitTx, ourKey, theirKey,
+			ourBalance, theirBalance,
+			pendingReservation.partialState.OurDustLimit, false)
+		if err != nil {
+			req.err <- err
+			return
+		}
+		err = addCommitAnchors(theirCommitTx, ourKey, theirKey,
+			ourBalance, theirBalance,
+			pendingReservation.partialState.TheirDustLimit, false)
+		if err != nil {
+			req.err <- err
+			return
+		}
+	}
+
 	// With both commitment transactions constructed, generate the state
 	// obsfucator then use it to encode the current state number withi both
 	// commitment transactions.
@@ -856,10 +1068,49 @@ func (l *LightningWallet) handleContributionMsg(req *addContributionMsg) {
 	req.err <- nil
 }
 
+// verifyContributionFunds ensures that the inputs within the remote party's
+// contribution to a dual funder channel, less their change outputs, add up
+// to at least the amount they've agreed to contribute to the channel.
+func (l *LightningWallet) verifyContributionFunds(
+	contribution *ChannelContribution, fundingAmt btcutil.Amount) error {
+
+	if len(contribution.Inputs) == 0 {
+		return fmt.Errorf("counterparty contributed no inputs to the " +
+			"funding transaction")
+	}
+
+	var inputTotal btcutil.Amount
+	for _, txIn := range contribution.Inputs {
+		prevOut := txIn.PreviousOutPoint
+		output, err := l.ChainIO.GetUtxo(&prevOut.Hash, prevOut.Index)
+		if output == nil {
+			return fmt.Errorf("input to funding tx does not "+
+				"exist: %v", err)
+		}
+
+		inputTotal += btcutil.Amount(output.Value)
+	}
+	for _, changeOutput := range contribution.ChangeOutputs {
+		inputTotal -= btcutil.Amount(changeOutput.Value)
+	}
+
+	if inputTotal < fundingAmt {
+		return fmt.Errorf("counterparty's inputs fund only %v of "+
+			"their %v contribution", inputTotal, fundingAmt)
+	}
+
+	return nil
+}
+
 // handleSingleContribution is called as the second step to a single funder
 // workflow to which we are the responder. It simply saves the remote peer's
 // contribution to the channel, as solely the remote peer will contribute any
 // funds to the channel.
+//
+// The responder to a dual funder workflow also calls this in order to derive
+// its initial revocation key before the funding transaction is assembled.
+// Once the initiator's revocation key is known, the full contribution is
+// then processed via handleContributionMsg.
 func (l *LightningWallet) handleSingleContribution(req *addSingleContributionMsg) {
 	l.limboMtx.Lock()
 	pendingReservation, ok := l.fundingLimbo[req.pendingFundingID]
@@ -965,6 +1216,17 @@ func (l *LightningWallet) handleFundingCounterPartySigs(msg *addCounterPartySigs
 	res.Lock()
 	defer res.Unlock()
 
+	// If the remote party funded part of this channel, then they must
+	// have provided a script for each of their inputs.
+	isDualFunder := res.partialState.ChanType == channeldb.DualFunder
+	numInputs := len(res.theirContribution.Inputs)
+	if isDualFunder && len(msg.theirFundingInputScripts) != numInputs {
+		msg.err <- fmt.Errorf("counterparty provided %v input scripts "+
+			"for %v inputs", len(msg.theirFundingInputScripts),
+			numInputs)
+		return
+	}
+
 	// Now we can complete the funding transaction by adding their
 	// signatures to their inputs.
 	res.theirFundingInputScripts = msg.theirFundingInputScripts
@@ -1040,9 +1302,16 @@ func (l *LightningWallet) handleFundingCounterPartySigs(msg *addCounterPartySigs
 	}
 	res.partialState.OurCommitSig = theirCommitSig
 
-	// Funding complete, this entry can be removed from limbo.
+	// Funding complete, this entry can be removed from limbo. Our inputs
+	// are kept locked while the funding transaction is pending, so they're
+	// recorded in case the channel is abandoned.
+	ourInputs := make([]wire.OutPoint, 0, len(res.ourContribution.Inputs))
+	for _, ourInput := range res.ourContribution.Inputs {
+		ourInputs = append(ourInputs, ourInput.PreviousOutPoint)
+	}
 	l.limboMtx.Lock()
 	delete(l.fundingLimbo, res.reservationID)
+	l.fundingInputs[*res.partialState.FundingOutpoint] = ourInputs
 	l.limboMtx.Unlock()
 
 	walletLog.Infof("Broadcasting funding tx for ChannelPoint(%v): %v",
@@ -1114,6 +1383,27 @@ func (l *LightningWallet) handleSingleFunderSigs(req *addSingleFunderSigsMsg) {
 		return
 	}
 
+	// If the channel uses anchor commitments, then both commitment
+	// transactions carry an anchor output for each party with a balance.
+	if pendingReservation.partialState.CommitFormat == channeldb.AnchorCommitment {
+		ourKey := pendingReservation.partialState.OurMultiSigKey
+		theirKey := pendingReservation.theirContribution.MultiSigKey
+		err := addCommitAnchors(ourCommitTx, ourKey, theirKey,
+			ourBalance, theirBalance,
+			pendingReservation.partialState.OurDustLimit, false)
+		if err != nil {
+			req.err <- err
+			return
+		}
+		err = addCommitAnchors(theirCommitTx, ourKey, theirKey,
+			ourBalance, theirBalance,
+			pendingReservation.partialState.TheirDustLimit, false)
+		if err != nil {
+			req.err <- err
+			return
+		}
+	}
+
 	// With both commitment transactions constructed, generate the state
 	// obsfucator then use it to encode the current state number within
 	// both commitment transactions.
@@ -1262,6 +1552,103 @@ func (l *LightningWallet) selectCoinsAndChange(feeRate uint64, amt btcutil.Amoun
 	return nil
 }
 
+// FundingEstimate describes the projected cost to us of funding a channel
+// from the wallet's coins, along with whether those coins are sufficient.
+type FundingEstimate struct {
+	// FeeRate is the fee rate in satoshis per byte paid by our inputs to
+	// the funding transaction.
+	FeeRate btcutil.Amount
+
+	// FundingFee is the fee paid by our inputs to the funding transaction.
+	// If our coins are insufficient, then it's the fee of spending all of
+	// them.
+	FundingFee btcutil.Amount
+
+	// CommitFee is our share of the fee of the initial commitment
+	// transaction.
+	CommitFee btcutil.Amount
+
+	// NumInputs is the number of coins selected to fund the channel.
+	NumInputs int
+
+	// ChangeAmt is the value of the change output of the funding
+	// transaction, if any.
+	ChangeAmt btcutil.Amount
+
+	// Available is the total value of the coins available to fund the
+	// channel.
+	Available btcutil.Amount
+
+	// CanFund is true if the available coins are sufficient to fund the
+	// channel.
+	CanFund bool
+}
+
+// EstimateFunding projects the cost to us of funding a channel to which we
+// contribute ourFundAmt, and the remote party theirFundAmt, without reserving
+// any coins. The estimate is only valid until the wallet's coins change.
+func (l *LightningWallet) EstimateFunding(ourFundAmt,
+	theirFundAmt btcutil.Amount) (*FundingEstimate, error) {
+
+	l.coinSelectMtx.Lock()
+	defer l.coinSelectMtx.Unlock()
+
+	coins, err := l.ListUnspentWitness(1)
+	if err != nil {
+		return nil, err
+	}
+
+	return estimateFunding(ourFundAmt, theirFundAmt, coins), nil
+}
+
+// estimateFunding projects the cost of funding a channel from the passed
+// coins, as the funding workflow we initiate would select them.
+func estimateFunding(ourFundAmt, theirFundAmt btcutil.Amount,
+	coins []*Utxo) *FundingEstimate {
+
+	estimate := &FundingEstimate{
+		FeeRate: fundingFeeRate,
+	}
+	for _, coin := range coins {
+		estimate.Available += coin.Value
+	}
+
+	// As the initiator of a single funder channel, we pay the full
+	// commitment fee on top of our contribution. Otherwise, our share of
+	// the fee is deducted from our balance within the channel.
+	requiredAmt := ourFundAmt
+	if theirFundAmt == 0 {
+		estimate.CommitFee = commitFee
+		requiredAmt += commitFee
+	} else {
+		estimate.CommitFee, _ = dualFunderCommitFees(ourFundAmt,
+			theirFundAmt)
+	}
+
+	selectedCoins, changeAmt, err := coinSelect(fundingFeeRate,
+		requiredAmt, coins)
+	if err != nil {
+		size := len(coins)*p2wkhSpendSize + p2wshOutputSize + txOverhead
+		estimate.FundingFee = btcutil.Amount(size * fundingFeeRate)
+		return estimate
+	}
+
+	// Coin selection always picks a prefix of the passed coins, so the
+	// fee is whatever those coins carry beyond the required amount and
+	// change.
+	var selectedAmt btcutil.Amount
+	for _, coin := range coins[:len(selectedCoins)] {
+		selectedAmt += coin.Value
+	}
+
+	estimate.FundingFee = selectedAmt - requiredAmt - changeAmt
+	estimate.NumInputs = len(selectedCoins)
+	estimate.ChangeAmt = changeAmt
+	estimate.CanFund = true
+
+	return estimate
+}
+
 // deriveMasterRevocationRoot derives the private key which serves as the master
 // producer root. This master secret is used as the secret input to a HKDF to
 // generate revocation secrets based on random, but public data.
@@ -1344,6 +1731,28 @@ func selectInputs(amt btcutil.Amount, coins []*Utxo) (btcutil.Amount, []*wire.Ou
 	return satSelected, selectedUtxos, nil
 }
 
+const (
+	// txOverhead is the overhead of a transaction residing within the
+	// version number and lock time.
+	txOverhead = 8
+
+	// p2wkhSpendSize an estimate of the number of bytes it takes to spend
+	// a p2wkh output.
+	//
+	// (p2wkh witness) + txid + index + varint script size + sequence
+	// TODO(roasbeef): div by 3 due to witness size?
+	p2wkhSpendSize = (1 + 73 + 1 + 33) + 32 + 4 + 1 + 4
+
+	// p2wkhOutputSize is an estimate of the size of a regualr p2wkh
+	// output.
+	//
+	// 8 (output) + 1 (var int script) + 22 (p2wkh output)
+	p2wkhOutputSize = 8 + 1 + 22
+
+	// p2wkhOutputSize is an estimate of the p2wsh funding uotput.
+	p2wshOutputSize = 8 + 1 + 34
+)
+
 // coinSelect attemps to select a sufficient amount of coins, including a
 // change output to fund amt satoshis, adhearing to the specified fee rate. The
 // specified fee rate should be expressed in sat/byte for coin selection to
@@ -1351,28 +1760,6 @@ func selectInputs(amt btcutil.Amount, coins []*Utxo) (btcutil.Amount, []*wire.Ou
 func coinSelect(feeRate uint64, amt btcutil.Amount,
 	coins []*Utxo) ([]*wire.OutPoint, btcutil.Amount, error) {
 
-	const (
-		// txOverhead is the overhead of a transaction residing within
-		// the version number and lock time.
-		txOverhead = 8
-
-		// p2wkhSpendSize an estimate of the number of bytes it takes
-		// to spend a p2wkh output.
-		//
-		// (p2wkh witness) + txid + index + varint script size + sequence
-		// TODO(roasbeef): div by 3 due to witness size?
-		p2wkhSpendSize = (1 + 73 + 1 + 33) + 32 + 4 + 1 + 4
-
-		// p2wkhOutputSize is an estimate of the size of a regualr
-		// p2wkh output.
-		//
-		// 8 (output) + 1 (var int script) + 22 (p2wkh output)
-		p2wkhOutputSize = 8 + 1 + 22
-
-		// p2wkhOutputSize is an estimate of the p2wsh funding uotput.
-		p2wshOutputSize = 8 + 1 + 34
-	)
-
 	var estimatedSize int
 
 	amtNeeded := amt
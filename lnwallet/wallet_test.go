@@ -0,0 +1,76 @@
+package lnwallet
+
+import (
+	"testing"
+
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+// TestEstimateFunding tests that the projected cost of funding a channel
+// matches the coins the funding workflow would select, and that coins
+// insufficient to fund the channel are reported as such.
+func TestEstimateFunding(t *testing.T) {
+	coins := []*Utxo{
+		{Value: 1e6, OutPoint: wire.OutPoint{Index: 0}},
+		{Value: 2e6, OutPoint: wire.OutPoint{Index: 1}},
+	}
+
+	tests := []struct {
+		ourFundAmt   btcutil.Amount
+		theirFundAmt btcutil.Amount
+		expected     FundingEstimate
+	}{
+		// A single funder channel requiring both coins, with the full
+		// commitment fee paid on top of our contribution.
+		{
+			ourFundAmt: 1500000,
+			expected: FundingEstimate{
+				FeeRate:    fundingFeeRate,
+				FundingFee: 3490,
+				CommitFee:  commitFee,
+				NumInputs:  2,
+				ChangeAmt:  1491510,
+				Available:  3e6,
+				CanFund:    true,
+			},
+		},
+
+		// A dual funded channel requiring a single coin, with the
+		// commitment fee split evenly.
+		{
+			ourFundAmt:   500000,
+			theirFundAmt: 500000,
+			expected: FundingEstimate{
+				FeeRate:    fundingFeeRate,
+				FundingFee: 2000,
+				CommitFee:  commitFee / 2,
+				NumInputs:  1,
+				ChangeAmt:  498000,
+				Available:  3e6,
+				CanFund:    true,
+			},
+		},
+
+		// A channel exceeding the available coins, for which the fee
+		// of spending all of them is reported.
+		{
+			ourFundAmt: 3e6,
+			expected: FundingEstimate{
+				FeeRate:    fundingFeeRate,
+				FundingFee: 3490,
+				CommitFee:  commitFee,
+				Available:  3e6,
+			},
+		},
+	}
+
+	for i, test := range tests {
+		estimate := estimateFunding(test.ourFundAmt, test.theirFundAmt,
+			coins)
+		if *estimate != test.expected {
+			t.Fatalf("test #%v: expected estimate %+v, got %+v", i,
+				test.expected, *estimate)
+		}
+	}
+}
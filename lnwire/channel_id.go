@@ -18,6 +18,19 @@ type ChannelID struct {
 	TxPosition uint16
 }
 
+const (
+	// AliasBlockHeightStart is the lowest block height encoded within an
+	// alias ChannelID. Aliases identify channels whose funding transaction
+	// has yet to confirm, and as such have no location within the chain.
+	// They're never announced to the network, and the block heights they
+	// use lie far beyond those the chain will reach.
+	AliasBlockHeightStart = 16000000
+
+	// AliasBlockHeightEnd is the block height, exclusive, at which the
+	// range of alias ChannelIDs ends.
+	AliasBlockHeightEnd = 16250000
+)
+
 // NewChanIDFromInt returns a new ChannelID which is the decoded version of the
 // compact channel ID encoded within the uint64. The format of the compact
 // channel ID is as follows: 3 bytes for the block height, 3 bytes for the
@@ -37,3 +50,10 @@ func (c *ChannelID) ToUint64() uint64 {
 	return ((uint64(c.BlockHeight) << 40) | (uint64(c.TxIndex) << 16) |
 		(uint64(c.TxPosition)))
 }
+
+// IsAlias returns true if the ChannelID is an alias, rather than encoding the
+// location of the channel's funding output within the chain.
+func (c *ChannelID) IsAlias() bool {
+	return c.BlockHeight >= AliasBlockHeightStart &&
+		c.BlockHeight < AliasBlockHeightEnd
+}
@@ -37,3 +37,24 @@ func TestChannelIDEncoding(t *testing.T) {
 		}
 	}
 }
+
+func TestChannelIDIsAlias(t *testing.T) {
+	var testCases = []struct {
+		chanID  ChannelID
+		isAlias bool
+	}{
+		{ChannelID{BlockHeight: 2304934, TxIndex: 2345}, false},
+		{ChannelID{BlockHeight: AliasBlockHeightStart - 1}, false},
+		{ChannelID{BlockHeight: AliasBlockHeightStart}, true},
+		{ChannelID{BlockHeight: AliasBlockHeightStart, TxIndex: 42}, true},
+		{ChannelID{BlockHeight: AliasBlockHeightEnd - 1}, true},
+		{ChannelID{BlockHeight: AliasBlockHeightEnd}, false},
+	}
+
+	for i, testCase := range testCases {
+		if testCase.chanID.IsAlias() != testCase.isAlias {
+			t.Fatalf("test #%v: expected alias=%v for %v", i,
+				testCase.isAlias, spew.Sdump(testCase.chanID))
+		}
+	}
+}
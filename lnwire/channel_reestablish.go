@@ -0,0 +1,103 @@
+package lnwire
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/roasbeef/btcd/wire"
+)
+
+// ChannelReestablish is sent by each party for every active channel they
+// share upon reconnecting. It carries the sender's view of the state of both
+// commitment chains, along with proof of the remote party's latest
+// revocation, allowing either party to detect that it has fallen behind, for
+// instance after restoring an outdated copy of its database. A party which
+// finds itself behind mustn't broadcast its own commitment transaction, as
+// it may well be revoked, and must instead wait for the remote party to
+// force close the channel.
+type ChannelReestablish struct {
+	// ChannelPoint serves to identify which channel is being
+	// re-established.
+	ChannelPoint wire.OutPoint
+
+	// NextLocalCommitHeight is the height of the next commitment the
+	// sender expects to receive within its own commitment chain.
+	NextLocalCommitHeight uint64
+
+	// RemoteCommitTailHeight is the number of revocations the sender has
+	// received from the remote party, which is the height of the lowest
+	// unrevoked commitment within the remote party's commitment chain.
+	RemoteCommitTailHeight uint64
+
+	// LastRemoteCommitSecret is the latest revocation preimage the sender
+	// received from the remote party, that of commitment
+	// RemoteCommitTailHeight-1. It's zero if no revocation has yet been
+	// received. The remote party checks it against its own revocation
+	// producer, which proves the sender's view of the channel is genuine.
+	LastRemoteCommitSecret [32]byte
+}
+
+// A compile time check to ensure ChannelReestablish implements the
+// lnwire.Message interface.
+var _ Message = (*ChannelReestablish)(nil)
+
+// Decode deserializes a serialized ChannelReestablish message stored in the
+// passed io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (c *ChannelReestablish) Decode(r io.Reader, pver uint32) error {
+	// ChannelPoint (36)
+	// NextLocalCommitHeight (8)
+	// RemoteCommitTailHeight (8)
+	// LastRemoteCommitSecret (32)
+	return readElements(r,
+		&c.ChannelPoint,
+		&c.NextLocalCommitHeight,
+		&c.RemoteCommitTailHeight,
+		c.LastRemoteCommitSecret[:],
+	)
+}
+
+// Encode serializes the target ChannelReestablish into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (c *ChannelReestablish) Encode(w io.Writer, pver uint32) error {
+	return writeElements(w,
+		c.ChannelPoint,
+		c.NextLocalCommitHeight,
+		c.RemoteCommitTailHeight,
+		c.LastRemoteCommitSecret[:],
+	)
+}
+
+// Command returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (c *ChannelReestablish) Command() uint32 {
+	return CmdChannelReestablish
+}
+
+// MaxPayloadLength returns the maximum allowed payload size for a
+// ChannelReestablish message observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (c *ChannelReestablish) MaxPayloadLength(uint32) uint32 {
+	// 36 + 8 + 8 + 32
+	return 84
+}
+
+// Validate performs any necessary sanity checks to ensure all fields present
+// on the ChannelReestablish are valid.
+//
+// This is part of the lnwire.Message interface.
+func (c *ChannelReestablish) Validate() error {
+	if c.NextLocalCommitHeight == 0 {
+		return fmt.Errorf("next local commitment height must be " +
+			"positive")
+	}
+
+	// We're good!
+	return nil
+}
@@ -0,0 +1,36 @@
+package lnwire
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestChannelReestablishEncodeDecode(t *testing.T) {
+	msg := &ChannelReestablish{
+		ChannelPoint:           *outpoint1,
+		NextLocalCommitHeight:  42,
+		RemoteCommitTailHeight: 40,
+	}
+	copy(msg.LastRemoteCommitSecret[:], revHash[:])
+
+	// Next encode the ChannelReestablish message into an empty bytes
+	// buffer.
+	var b bytes.Buffer
+	if err := msg.Encode(&b, 0); err != nil {
+		t.Fatalf("unable to encode ChannelReestablish: %v", err)
+	}
+
+	// Deserialize the encoded ChannelReestablish message into a new empty
+	// struct.
+	msg2 := &ChannelReestablish{}
+	if err := msg2.Decode(&b, 0); err != nil {
+		t.Fatalf("unable to decode ChannelReestablish: %v", err)
+	}
+
+	// Assert equality of the two instances.
+	if !reflect.DeepEqual(msg, msg2) {
+		t.Fatalf("encode/decode error messages don't match %#v vs %#v",
+			msg, msg2)
+	}
+}
@@ -1,8 +1,11 @@
 package lnwire
 
 import (
+	"fmt"
+
 	"github.com/roasbeef/btcd/btcec"
 	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
 
 	"io"
 )
@@ -10,7 +13,8 @@ import (
 // CloseComplete is sent by Bob signalling a fufillment and completion of
 // Alice's prior CloseRequest message. After Alice receives Bob's CloseComplete
 // message, she is able to broadcast the fully signed transaction executing a
-// cooperative closure of the channel.
+// cooperative closure of the channel. As Alice may replace her version of the
+// closing transaction, the fee of the version signed is echoed back.
 //
 // NOTE: The responder is able to only send a signature without any additional
 // message as all transactions are assembled observing BIP 69 which defines a
@@ -24,6 +28,10 @@ type CloseComplete struct {
 	// ResponderCloseSig is the signature of the responder for the
 	// transaction which closes the previously active channel.
 	ResponderCloseSig *btcec.Signature
+
+	// Fee is the fee paid by the version of the closing transaction the
+	// signature covers, as specified within the CloseRequest.
+	Fee btcutil.Amount
 }
 
 // NewCloseComplete creates a new empty CloseComplete message.
@@ -43,9 +51,11 @@ var _ Message = (*CloseComplete)(nil)
 func (c *CloseComplete) Decode(r io.Reader, pver uint32) error {
 	// ChannelPoint (8)
 	// ResponderCloseSig (73)
+	// Fee (8)
 	return readElements(r,
 		&c.ChannelPoint,
-		&c.ResponderCloseSig)
+		&c.ResponderCloseSig,
+		&c.Fee)
 }
 
 // Encode serializes the target CloseComplete into the passed io.Writer observing
@@ -55,9 +65,11 @@ func (c *CloseComplete) Decode(r io.Reader, pver uint32) error {
 func (c *CloseComplete) Encode(w io.Writer, pver uint32) error {
 	// ChannelPoint (8)
 	// ResponderCloseSig (73)
+	// Fee (8)
 	return writeElements(w,
 		c.ChannelPoint,
-		c.ResponderCloseSig)
+		c.ResponderCloseSig,
+		c.Fee)
 }
 
 // Command returns the integer uniquely identifying this message type on the
@@ -73,8 +85,8 @@ func (c *CloseComplete) Command() uint32 {
 //
 // This is part of the lnwire.Message interface.
 func (c *CloseComplete) MaxPayloadLength(uint32) uint32 {
-	// 141 + 73 + 32
-	return 141
+	// 36 + 73 + 8
+	return 117
 }
 
 // Validate performs any necessary sanity checks to ensure all fields present
@@ -82,6 +94,10 @@ func (c *CloseComplete) MaxPayloadLength(uint32) uint32 {
 //
 // This is part of the lnwire.Message interface.
 func (c *CloseComplete) Validate() error {
+	if c.Fee < 0 {
+		return fmt.Errorf("fee must be greater than zero")
+	}
+
 	// We're good!
 	return nil
 }
@@ -4,12 +4,15 @@ import (
 	"bytes"
 	"reflect"
 	"testing"
+
+	"github.com/roasbeef/btcutil"
 )
 
 func TestCloseCompleteEncodeDecode(t *testing.T) {
 	cc := &CloseComplete{
 		ChannelPoint:      *outpoint1,
 		ResponderCloseSig: commitSig,
+		Fee:               btcutil.Amount(10000),
 	}
 
 	// Next encode the CC message into an empty bytes buffer.
@@ -10,10 +10,14 @@ import (
 	"io"
 )
 
-// CloseRequest is sent by either side in order to initiate the cooperative
-// closure of a channel. This message is rather sparse as both side implicitly
-// know to craft a transaction sending the settled funds of both parties to the
-// final delivery addresses negotiated during the funding workflow.
+// CloseRequest is sent by either side in order to propose a version of the
+// cooperative closure transaction of a channel. The sender of the request pays
+// the entire fee of its version from its own output, so either side is able to
+// propose its own version, and later replace it with one paying a higher fee,
+// without any negotiation. This message is rather sparse as both side
+// implicitly know to craft a transaction sending the settled funds of both
+// parties to the final delivery addresses negotiated during the funding
+// workflow.
 //
 // NOTE: The requester is able to only send a signature to initiate the
 // cooperative channel closure as all transactions are assembled observing
@@ -28,19 +32,20 @@ type CloseRequest struct {
 	// assembled closing transaction.
 	RequesterCloseSig *btcec.Signature
 
-	// Fee is the required fee-per-KB the closing transaction must have.
-	// It is recommended that a "sufficient" fee be paid in order to
-	// achieve timely channel closure.
-	// TODO(roasbeef): if initiator always pays fees, then no longer needed.
+	// Fee is the absolute fee paid by the proposed closing transaction,
+	// which is deducted from the requester's output. Each replacement
+	// proposed by the requester must pay a higher fee than the last.
 	Fee btcutil.Amount
 }
 
 // NewCloseRequest creates a new CloseRequest.
-func NewCloseRequest(cp wire.OutPoint, sig *btcec.Signature) *CloseRequest {
-	// TODO(roasbeef): update once fees aren't hardcoded
+func NewCloseRequest(cp wire.OutPoint, sig *btcec.Signature,
+	fee btcutil.Amount) *CloseRequest {
+
 	return &CloseRequest{
 		ChannelPoint:      cp,
 		RequesterCloseSig: sig,
+		Fee:               fee,
 	}
 }
 
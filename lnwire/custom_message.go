@@ -0,0 +1,91 @@
+package lnwire
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+// CustomMessage is a message of a type within the experimental range, which
+// lnwire itself doesn't interpret. The payload is carried as opaque bytes,
+// leaving it to whichever handler registered the type to make sense of it.
+type CustomMessage struct {
+	// Type is the message type, which must lie within the experimental
+	// range starting at CmdCustomMessageStart.
+	Type uint32
+
+	// Data is the opaque payload of the message.
+	Data []byte
+}
+
+// NewCustomMessage returns a new CustomMessage of the passed type, carrying
+// the passed payload.
+func NewCustomMessage(msgType uint32, data []byte) (*CustomMessage, error) {
+	if msgType < CmdCustomMessageStart {
+		return nil, fmt.Errorf("custom message type %v is below the "+
+			"experimental range starting at %v", msgType,
+			CmdCustomMessageStart)
+	}
+
+	return &CustomMessage{
+		Type: msgType,
+		Data: data,
+	}, nil
+}
+
+// A compile time check to ensure CustomMessage implements the lnwire.Message
+// interface.
+var _ Message = (*CustomMessage)(nil)
+
+// Decode deserializes a serialized CustomMessage stored in the passed
+// io.Reader observing the specified protocol version. As the payload is
+// opaque, the entire remainder of the message is read as the payload.
+//
+// This is part of the lnwire.Message interface.
+func (c *CustomMessage) Decode(r io.Reader, pver uint32) error {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	c.Data = data
+
+	return nil
+}
+
+// Encode serializes the target CustomMessage into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (c *CustomMessage) Encode(w io.Writer, pver uint32) error {
+	_, err := w.Write(c.Data)
+	return err
+}
+
+// Command returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (c *CustomMessage) Command() uint32 {
+	return c.Type
+}
+
+// MaxPayloadLength returns the maximum allowed payload size for a
+// CustomMessage observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (c *CustomMessage) MaxPayloadLength(uint32) uint32 {
+	return maxCustomMessagePayload
+}
+
+// Validate performs any necessary sanity checks to ensure all fields present
+// on the CustomMessage are valid.
+//
+// This is part of the lnwire.Message interface.
+func (c *CustomMessage) Validate() error {
+	if c.Type < CmdCustomMessageStart {
+		return fmt.Errorf("custom message type %v is below the "+
+			"experimental range", c.Type)
+	}
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package lnwire
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/roasbeef/btcd/wire"
+)
+
+func TestCustomMessageEncodeDecode(t *testing.T) {
+	msg, err := NewCustomMessage(CmdCustomMessageStart+7,
+		[]byte("experimental payload"))
+	if err != nil {
+		t.Fatalf("unable to create custom message: %v", err)
+	}
+
+	// As the type of a custom message is carried within the message
+	// header, the message must make the round trip through the full wire
+	// encoding.
+	var b bytes.Buffer
+	if _, err := WriteMessage(&b, msg, 0, wire.SimNet); err != nil {
+		t.Fatalf("unable to write custom message: %v", err)
+	}
+
+	_, msg2, _, err := ReadMessage(&b, 0, wire.SimNet)
+	if err != nil {
+		t.Fatalf("unable to read custom message: %v", err)
+	}
+
+	// Assert equality of the two instances.
+	if !reflect.DeepEqual(msg, msg2) {
+		t.Fatalf("encode/decode custom messages don't match %#v vs %#v",
+			msg, msg2)
+	}
+
+	// Custom messages may only use types within the experimental range.
+	if _, err := NewCustomMessage(CmdPing, nil); err == nil {
+		t.Fatalf("custom message shouldn't use a built-in type")
+	}
+}
@@ -0,0 +1,116 @@
+package lnwire
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/roasbeef/btcd/btcec"
+)
+
+// DualFundingComplete is the message Alice sends to Bob once she has
+// assembled the funding transaction of a dual funded channel, along with both
+// versions of the commitment transaction. It carries Alice's signatures for
+// each of her inputs to the funding transaction, and for Bob's version of the
+// commitment transaction. As both parties order the funding transaction
+// canonically, Bob is able to assemble the identical transaction himself.
+type DualFundingComplete struct {
+	// ChannelID serves to uniquely identify the future channel created by
+	// the initiated dual funder workflow.
+	ChannelID uint64
+
+	// RevocationKey is the initial key to be used for the revocation
+	// clause within the self-output of the initiators's commitment
+	// transaction.
+	RevocationKey *btcec.PublicKey
+
+	// CommitSignature is Alice's signature for Bob's version of the
+	// commitment transaction.
+	CommitSignature *btcec.Signature
+
+	// FundingInputScripts are the scripts spending each of Alice's inputs
+	// to the funding transaction, in the order in which her inputs appear
+	// within the canonically sorted funding transaction.
+	FundingInputScripts []*FundingInputScript
+}
+
+// NewDualFundingComplete creates, and returns a new DualFundingComplete.
+func NewDualFundingComplete(chanID uint64, revokeKey *btcec.PublicKey,
+	commitSig *btcec.Signature,
+	inputScripts []*FundingInputScript) *DualFundingComplete {
+
+	return &DualFundingComplete{
+		ChannelID:           chanID,
+		RevocationKey:       revokeKey,
+		CommitSignature:     commitSig,
+		FundingInputScripts: inputScripts,
+	}
+}
+
+// A compile time check to ensure DualFundingComplete implements the
+// lnwire.Message interface.
+var _ Message = (*DualFundingComplete)(nil)
+
+// Decode deserializes the serialized DualFundingComplete stored in the passed
+// io.Reader into the target DualFundingComplete using the deserialization
+// rules defined by the passed protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (c *DualFundingComplete) Decode(r io.Reader, pver uint32) error {
+	return readElements(r,
+		&c.ChannelID,
+		&c.RevocationKey,
+		&c.CommitSignature,
+		&c.FundingInputScripts)
+}
+
+// Encode serializes the target DualFundingComplete into the passed io.Writer
+// implementation. Serialization will observe the rules defined by the passed
+// protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (c *DualFundingComplete) Encode(w io.Writer, pver uint32) error {
+	return writeElements(w,
+		c.ChannelID,
+		c.RevocationKey,
+		c.CommitSignature,
+		c.FundingInputScripts)
+}
+
+// Command returns the uint32 code which uniquely identifies this message as a
+// DualFundingComplete on the wire.
+//
+// This is part of the lnwire.Message interface.
+func (c *DualFundingComplete) Command() uint32 {
+	return CmdDualFundingComplete
+}
+
+// MaxPayloadLength returns the maximum allowed payload length for a
+// DualFundingComplete. This is calculated by summing the max length of all
+// the fields within a DualFundingComplete: 8 + 33 + 64, plus the input
+// scripts.
+//
+// This is part of the lnwire.Message interface.
+func (c *DualFundingComplete) MaxPayloadLength(uint32) uint32 {
+	return 105 + maxInputScriptsLength
+}
+
+// Validate examines each populated field within the DualFundingComplete for
+// field sanity.
+//
+// This is part of the lnwire.Message interface.
+func (c *DualFundingComplete) Validate() error {
+	if c.RevocationKey == nil {
+		return fmt.Errorf("revocation key must be non-nil")
+	}
+
+	if c.CommitSignature == nil {
+		return fmt.Errorf("commitment signature must be non-nil")
+	}
+
+	if len(c.FundingInputScripts) == 0 {
+		return fmt.Errorf("funding input scripts must be non-empty")
+	}
+
+	// We're good!
+	return nil
+}
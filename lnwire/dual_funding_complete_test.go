@@ -0,0 +1,31 @@
+package lnwire
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestDualFundingCompleteWire(t *testing.T) {
+	// First create a new DFC message.
+	dfc := NewDualFundingComplete(22, pubKey, commitSig1,
+		fundingInputScripts)
+
+	// Next encode the DFC message into an empty bytes buffer.
+	var b bytes.Buffer
+	if err := dfc.Encode(&b, 0); err != nil {
+		t.Fatalf("unable to encode DualFundingComplete: %v", err)
+	}
+
+	// Deserialize the encoded DFC message into a new empty struct.
+	dfc2 := &DualFundingComplete{}
+	if err := dfc2.Decode(&b, 0); err != nil {
+		t.Fatalf("unable to decode DualFundingComplete: %v", err)
+	}
+
+	// Assert equality of the two instances.
+	if !reflect.DeepEqual(dfc, dfc2) {
+		t.Fatalf("encode/decode error messages don't match %#v vs %#v",
+			dfc, dfc2)
+	}
+}
@@ -0,0 +1,313 @@
+package lnwire
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+// DualFundingRequest is the message Alice sends to Bob if she'd like to
+// create a channel with Bob to which both of them contribute funds. Along
+// with the parameters of the channel, the request carries the inputs and
+// change outputs Alice contributes to the funding transaction, and the amount
+// she'd like Bob to contribute. The fee of the initial commitment transaction
+// is split between Alice and Bob in proportion to their contributions.
+type DualFundingRequest struct {
+	// ChannelID serves to uniquely identify the future channel created by
+	// the initiated dual funder workflow.
+	ChannelID uint64
+
+	// ChannelType represents the type of channel this request would like
+	// to open. At this point, the only supported channels are type 0
+	// channels, which are channels with regular commitment transactions
+	// utilizing HTLCs for payments.
+	ChannelType uint8
+
+	// CoinType represents which blockchain the channel will be opened
+	// using. By default, this field should be set to 0, indicating usage
+	// of the Bitcoin blockchain.
+	CoinType uint64
+
+	// FeePerKb is the required number of satoshis per KB that the
+	// requester will pay at all timers, for both the funding transaction
+	// and commitment transaction. This value can later be updated once the
+	// channel is open.
+	FeePerKb btcutil.Amount
+
+	// FundingAmount is the number of satoshis the initiator would like
+	// to commit to the channel.
+	FundingAmount btcutil.Amount
+
+	// ResponderAmount is the number of satoshis the initiator would like
+	// the responder to commit to the channel.
+	ResponderAmount btcutil.Amount
+
+	// CsvDelay is the number of blocks to use for the relative time lock
+	// in the pay-to-self output of both commitment transactions.
+	CsvDelay uint32
+
+	// CommitmentKey is key the initiator of the funding workflow wishes to
+	// use within their versino of the commitment transaction for any
+	// delayed (CSV) or immediate outputs to them.
+	CommitmentKey *btcec.PublicKey
+
+	// ChannelDerivationPoint is an secp256k1 point which will be used to
+	// derive the public key the initiator will use for the half of the
+	// 2-of-2 multi-sig.
+	ChannelDerivationPoint *btcec.PublicKey
+
+	// DeliveryPkScript defines the public key script that the initiator
+	// would like to use to receive their balance in the case of a
+	// cooperative close. Only the following script templates are
+	// supported: P2PKH, P2WKH, P2SH, and P2WSH.
+	DeliveryPkScript PkScript
+
+	// DustLimit is the threshold below which no HTLC output should be
+	// generated for our commitment transaction; ie. HTLCs below
+	// this amount are not enforceable onchain from our point view.
+	DustLimit btcutil.Amount
+
+	// ConfirmationDepth is the number of confirmations that the initiator
+	// of a funding workflow is requesting be required before the channel
+	// is considered fully open.
+	ConfirmationDepth uint32
+
+	// ChannelReserve is the minimum balance the initiator proposes each
+	// side of the channel must maintain. A value of zero proposes no
+	// reserve.
+	ChannelReserve btcutil.Amount
+
+	// Inputs are the outpoints the initiator spends within the funding
+	// transaction in order to fund their contribution.
+	Inputs []*wire.TxIn
+
+	// ChangeOutputs are the outputs returning to the initiator any value
+	// of their inputs in excess of their contribution.
+	ChangeOutputs []*wire.TxOut
+}
+
+// NewDualFundingRequest creates, and returns a new DualFundingRequest.
+func NewDualFundingRequest(chanID uint64, chanType uint8, coinType uint64,
+	fee, amt, responderAmt btcutil.Amount, delay uint32, ck,
+	cdp *btcec.PublicKey, deliveryScript PkScript,
+	dustLimit btcutil.Amount, confDepth uint32,
+	chanReserve btcutil.Amount, inputs []*wire.TxIn,
+	changeOutputs []*wire.TxOut) *DualFundingRequest {
+
+	return &DualFundingRequest{
+		ChannelID:              chanID,
+		ChannelType:            chanType,
+		CoinType:               coinType,
+		FeePerKb:               fee,
+		FundingAmount:          amt,
+		ResponderAmount:        responderAmt,
+		CsvDelay:               delay,
+		CommitmentKey:          ck,
+		ChannelDerivationPoint: cdp,
+		DeliveryPkScript:       deliveryScript,
+		DustLimit:              dustLimit,
+		ConfirmationDepth:      confDepth,
+		ChannelReserve:         chanReserve,
+		Inputs:                 inputs,
+		ChangeOutputs:          changeOutputs,
+	}
+}
+
+// A compile time check to ensure DualFundingRequest implements the
+// lnwire.Message interface.
+var _ Message = (*DualFundingRequest)(nil)
+
+// Decode deserializes the serialized DualFundingRequest stored in the passed
+// io.Reader into the target DualFundingRequest using the deserialization
+// rules defined by the passed protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (c *DualFundingRequest) Decode(r io.Reader, pver uint32) error {
+	return readElements(r,
+		&c.ChannelID,
+		&c.ChannelType,
+		&c.CoinType,
+		&c.FeePerKb,
+		&c.FundingAmount,
+		&c.ResponderAmount,
+		&c.CsvDelay,
+		&c.CommitmentKey,
+		&c.ChannelDerivationPoint,
+		&c.DeliveryPkScript,
+		&c.DustLimit,
+		&c.ConfirmationDepth,
+		&c.ChannelReserve,
+		&c.Inputs,
+		&c.ChangeOutputs)
+}
+
+// Encode serializes the target DualFundingRequest into the passed io.Writer
+// implementation. Serialization will observe the rules defined by the passed
+// protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (c *DualFundingRequest) Encode(w io.Writer, pver uint32) error {
+	return writeElements(w,
+		c.ChannelID,
+		c.ChannelType,
+		c.CoinType,
+		c.FeePerKb,
+		c.FundingAmount,
+		c.ResponderAmount,
+		c.CsvDelay,
+		c.CommitmentKey,
+		c.ChannelDerivationPoint,
+		c.DeliveryPkScript,
+		c.DustLimit,
+		c.ConfirmationDepth,
+		c.ChannelReserve,
+		c.Inputs,
+		c.ChangeOutputs)
+}
+
+// Command returns the uint32 code which uniquely identifies this message as a
+// DualFundingRequest on the wire.
+//
+// This is part of the lnwire.Message interface.
+func (c *DualFundingRequest) Command() uint32 {
+	return CmdDualFundingRequest
+}
+
+// MaxPayloadLength returns the maximum allowed payload length for a
+// DualFundingRequest. This is calculated by summing the max length of all
+// the fields within a DualFundingRequest.
+//
+// This is part of the lnwire.Message interface.
+func (c *DualFundingRequest) MaxPayloadLength(uint32) uint32 {
+	var length uint32
+
+	// ChannelID - 8 bytes
+	length += 8
+
+	// ChannelType - 1 byte
+	length++
+
+	// CoinType - 8 bytes
+	length += 8
+
+	// FeePerKb - 8 bytes
+	length += 8
+
+	// FundingAmount - 8 bytes
+	length += 8
+
+	// ResponderAmount - 8 bytes
+	length += 8
+
+	// CsvDelay - 4 bytes
+	length += 4
+
+	// CommitmentKey - 33 bytes
+	length += 33
+
+	// ChannelDerivationPoint - 33 bytes
+	length += 33
+
+	// DeliveryPkScript - 1 + 25 bytes
+	length += 26
+
+	// DustLimit - 8 bytes
+	length += 8
+
+	// ConfirmationDepth - 4 bytes
+	length += 4
+
+	// ChannelReserve - 8 bytes
+	length += 8
+
+	// Inputs
+	length += maxTxInsLength
+
+	// ChangeOutputs
+	length += maxTxOutsLength
+
+	return length
+}
+
+// Validate examines each populated field within the DualFundingRequest for
+// field sanity. For example, all fields MUST NOT be negative, and all pkScripts
+// must belong to the allowed set of public key scripts.
+//
+// This is part of the lnwire.Message interface.
+func (c *DualFundingRequest) Validate() error {
+	if c.FeePerKb < 0 {
+		return fmt.Errorf("'MinFeePerKb' cannot be negative")
+	}
+
+	// Both parties must contribute to a dual funded channel.
+	if c.FundingAmount <= 0 {
+		return fmt.Errorf("'FundingAmount' must be positive")
+	}
+	if c.ResponderAmount <= 0 {
+		return fmt.Errorf("'ResponderAmount' must be positive")
+	}
+
+	// The CSV delay MUST be non-zero.
+	if c.CsvDelay == 0 {
+		return fmt.Errorf("commitment transaction must have non-zero" +
+			" CSV delay")
+	}
+
+	if c.ChannelDerivationPoint == nil {
+		return fmt.Errorf("the channel derivation point must be " +
+			"non-nil")
+	}
+
+	// The delivery pkScript must be amongst the supported script
+	// templates.
+	if !isValidPkScript(c.DeliveryPkScript) {
+		return fmt.Errorf("valid delivery public key scripts MUST " +
+			"be: P2PKH, P2WKH, P2SH, or P2WSH")
+	}
+
+	if c.DustLimit <= 0 {
+		return fmt.Errorf("DustLimit' should be greater than zero")
+	}
+
+	if c.ConfirmationDepth == 0 {
+		return fmt.Errorf("ConfirmationDepth must be non-zero")
+	}
+
+	if c.ChannelReserve < 0 {
+		return fmt.Errorf("'ChannelReserve' cannot be negative")
+	}
+
+	// The initiator must spend at least one input in order to fund
+	// their contribution, and may only return change to the supported
+	// script templates.
+	if len(c.Inputs) == 0 {
+		return fmt.Errorf("funding inputs must be non-empty")
+	}
+	if err := validateChangeOutputs(c.ChangeOutputs); err != nil {
+		return err
+	}
+
+	// We're good!
+	return nil
+}
+
+// validateChangeOutputs ensures that each change output contributed to a
+// funding transaction has a positive value, and pays to one of the supported
+// script templates.
+func validateChangeOutputs(outputs []*wire.TxOut) error {
+	for _, output := range outputs {
+		if output.Value <= 0 {
+			return fmt.Errorf("change output value must be " +
+				"positive")
+		}
+		if !isValidPkScript(output.PkScript) {
+			return fmt.Errorf("valid change public key scripts " +
+				"MUST be: P2PKH, P2WKH, P2SH, or P2WSH")
+		}
+	}
+
+	return nil
+}
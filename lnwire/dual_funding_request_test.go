@@ -0,0 +1,56 @@
+package lnwire
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/roasbeef/btcd/wire"
+)
+
+var (
+	fundingInputs = []*wire.TxIn{
+		wire.NewTxIn(outpoint1, nil, nil),
+		wire.NewTxIn(wire.NewOutPoint(shaHash1, 3), nil, nil),
+	}
+
+	changeOutputs = []*wire.TxOut{
+		wire.NewTxOut(2e8, bytes.Repeat([]byte{0x00}, 22)),
+	}
+
+	fundingInputScripts = []*FundingInputScript{
+		{
+			Witness: wire.TxWitness{
+				bytes.Repeat([]byte{0x30}, 72),
+				pubKey.SerializeCompressed(),
+			},
+			ScriptSig: bytes.Repeat([]byte{0x16}, 23),
+		},
+	}
+)
+
+func TestDualFundingRequestWire(t *testing.T) {
+	// First create a new DFR message.
+	cdp := pubKey
+	delivery := PkScript(bytes.Repeat([]byte{0x02}, 25))
+	dfr := NewDualFundingRequest(20, 21, 22, 23, 5e8, 3e8, 5, cdp, cdp,
+		delivery, 540, 6, 20000, fundingInputs, changeOutputs)
+
+	// Next encode the DFR message into an empty bytes buffer.
+	var b bytes.Buffer
+	if err := dfr.Encode(&b, 0); err != nil {
+		t.Fatalf("unable to encode DualFundingRequest: %v", err)
+	}
+
+	// Deserialize the encoded DFR message into a new empty struct.
+	dfr2 := &DualFundingRequest{}
+	if err := dfr2.Decode(&b, 0); err != nil {
+		t.Fatalf("unable to decode DualFundingRequest: %v", err)
+	}
+
+	// Assert equality of the two instances.
+	if !reflect.DeepEqual(dfr, dfr2) {
+		t.Fatalf("encode/decode error messages don't match %#v vs %#v",
+			dfr, dfr2)
+	}
+}
@@ -0,0 +1,202 @@
+package lnwire
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+// DualFundingResponse is the message Bob sends to Alice after she initiates
+// the dual funder channel workflow via a DualFundingRequest message. Along
+// with Bob's keys and channel parameters, the response carries the inputs and
+// change outputs Bob contributes to the funding transaction. Once Alice
+// receives Bob's response, she has all the items neccessary to construct the
+// funding transaction, and both commitment transactions.
+type DualFundingResponse struct {
+	// ChannelID serves to uniquely identify the future channel created by
+	// the initiated dual funder workflow.
+	ChannelID uint64
+
+	// ChannelDerivationPoint is an secp256k1 point which will be used to
+	// derive the public key the responder will use for the half of the
+	// 2-of-2 multi-sig.
+	ChannelDerivationPoint *btcec.PublicKey
+
+	// CommitmentKey is key the responder to the funding workflow wishes to
+	// use within their versino of the commitment transaction for any
+	// delayed (CSV) or immediate outputs to them.
+	CommitmentKey *btcec.PublicKey
+
+	// RevocationKey is the initial key to be used for the revocation
+	// clause within the self-output of the responder's commitment
+	// transaction.
+	RevocationKey *btcec.PublicKey
+
+	// CsvDelay is the number of blocks to use for the relative time lock
+	// in the pay-to-self output of both commitment transactions.
+	CsvDelay uint32
+
+	// DeliveryPkScript defines the public key script that the responder
+	// would like to use to receive their balance in the case of a
+	// cooperative close. Only the following script templates are
+	// supported: P2PKH, P2WKH, P2SH, and P2WSH.
+	DeliveryPkScript PkScript
+
+	// DustLimit is the threshold below which no HTLC output should be
+	// generated for remote commitment transaction; ie. HTLCs below
+	// this amount are not enforceable onchain for their point of view.
+	DustLimit btcutil.Amount
+
+	// Inputs are the outpoints the responder spends within the funding
+	// transaction in order to fund their contribution.
+	Inputs []*wire.TxIn
+
+	// ChangeOutputs are the outputs returning to the responder any value
+	// of their inputs in excess of their contribution.
+	ChangeOutputs []*wire.TxOut
+}
+
+// NewDualFundingResponse creates, and returns a new DualFundingResponse.
+func NewDualFundingResponse(chanID uint64, rk, ck, cdp *btcec.PublicKey,
+	delay uint32, deliveryScript PkScript, dustLimit btcutil.Amount,
+	inputs []*wire.TxIn, changeOutputs []*wire.TxOut) *DualFundingResponse {
+
+	return &DualFundingResponse{
+		ChannelID:              chanID,
+		ChannelDerivationPoint: cdp,
+		CommitmentKey:          ck,
+		RevocationKey:          rk,
+		CsvDelay:               delay,
+		DeliveryPkScript:       deliveryScript,
+		DustLimit:              dustLimit,
+		Inputs:                 inputs,
+		ChangeOutputs:          changeOutputs,
+	}
+}
+
+// A compile time check to ensure DualFundingResponse implements the
+// lnwire.Message interface.
+var _ Message = (*DualFundingResponse)(nil)
+
+// Decode deserializes the serialized DualFundingResponse stored in the passed
+// io.Reader into the target DualFundingResponse using the deserialization
+// rules defined by the passed protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (c *DualFundingResponse) Decode(r io.Reader, pver uint32) error {
+	return readElements(r,
+		&c.ChannelID,
+		&c.ChannelDerivationPoint,
+		&c.CommitmentKey,
+		&c.RevocationKey,
+		&c.CsvDelay,
+		&c.DeliveryPkScript,
+		&c.DustLimit,
+		&c.Inputs,
+		&c.ChangeOutputs)
+}
+
+// Encode serializes the target DualFundingResponse into the passed io.Writer
+// implementation. Serialization will observe the rules defined by the passed
+// protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (c *DualFundingResponse) Encode(w io.Writer, pver uint32) error {
+	return writeElements(w,
+		c.ChannelID,
+		c.ChannelDerivationPoint,
+		c.CommitmentKey,
+		c.RevocationKey,
+		c.CsvDelay,
+		c.DeliveryPkScript,
+		c.DustLimit,
+		c.Inputs,
+		c.ChangeOutputs)
+}
+
+// Command returns the uint32 code which uniquely identifies this message as a
+// DualFundingResponse on the wire.
+//
+// This is part of the lnwire.Message interface.
+func (c *DualFundingResponse) Command() uint32 {
+	return CmdDualFundingResponse
+}
+
+// MaxPayloadLength returns the maximum allowed payload length for a
+// DualFundingResponse. This is calculated by summing the max length of all
+// the fields within a DualFundingResponse.
+//
+// This is part of the lnwire.Message interface.
+func (c *DualFundingResponse) MaxPayloadLength(uint32) uint32 {
+	var length uint32
+
+	// ChannelID - 8 bytes
+	length += 8
+
+	// ChannelDerivationPoint - 33 bytes
+	length += 33
+
+	// CommitmentKey - 33 bytes
+	length += 33
+
+	// RevocationKey - 33 bytes
+	length += 33
+
+	// CsvDelay - 4 bytes
+	length += 4
+
+	// DeliveryPkScript - 1 + 25 bytes
+	length += 26
+
+	// DustLimit - 8 bytes
+	length += 8
+
+	// Inputs
+	length += maxTxInsLength
+
+	// ChangeOutputs
+	length += maxTxOutsLength
+
+	return length
+}
+
+// Validate examines each populated field within the DualFundingResponse for
+// field sanity.
+//
+// This is part of the lnwire.Message interface.
+func (c *DualFundingResponse) Validate() error {
+	if c.ChannelDerivationPoint == nil {
+		return fmt.Errorf("the channel derivation point must be " +
+			"non-nil")
+	}
+
+	if c.RevocationKey == nil {
+		return fmt.Errorf("revocation key must be non-nil")
+	}
+
+	// The delivery pkScript must be amongst the supported script
+	// templates.
+	if !isValidPkScript(c.DeliveryPkScript) {
+		return fmt.Errorf("valid delivery public key scripts MUST " +
+			"be: P2PKH, P2WKH, P2SH, or P2WSH")
+	}
+
+	if c.DustLimit <= 0 {
+		return fmt.Errorf("DustLimit' should be greater than zero")
+	}
+
+	// The responder must spend at least one input in order to fund
+	// their contribution.
+	if len(c.Inputs) == 0 {
+		return fmt.Errorf("funding inputs must be non-empty")
+	}
+	if err := validateChangeOutputs(c.ChangeOutputs); err != nil {
+		return err
+	}
+
+	// We're good!
+	return nil
+}
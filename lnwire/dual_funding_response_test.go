@@ -0,0 +1,32 @@
+package lnwire
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestDualFundingResponseWire(t *testing.T) {
+	// First create a new DFR message.
+	delivery := PkScript(bytes.Repeat([]byte{0x02}, 25))
+	dfr := NewDualFundingResponse(22, pubKey, pubKey, pubKey, 5,
+		delivery, 540, fundingInputs, changeOutputs)
+
+	// Next encode the DFR message into an empty bytes buffer.
+	var b bytes.Buffer
+	if err := dfr.Encode(&b, 0); err != nil {
+		t.Fatalf("unable to encode DualFundingResponse: %v", err)
+	}
+
+	// Deserialize the encoded DFR message into a new empty struct.
+	dfr2 := &DualFundingResponse{}
+	if err := dfr2.Decode(&b, 0); err != nil {
+		t.Fatalf("unable to decode DualFundingResponse: %v", err)
+	}
+
+	// Assert equality of the two instances.
+	if !reflect.DeepEqual(dfr, dfr2) {
+		t.Fatalf("encode/decode error messages don't match %#v vs %#v",
+			dfr, dfr2)
+	}
+}
@@ -0,0 +1,104 @@
+package lnwire
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/roasbeef/btcd/btcec"
+)
+
+// DualFundingSignComplete is the final message of the dual funder workflow,
+// which Bob sends to Alice once he has verified her signatures. It delivers
+// Bob's signatures for each of his inputs to the funding transaction, and for
+// Alice's version of the commitment transaction. After this message is
+// received and processed by Alice, the funding transaction is fully signed
+// and may be broadcast.
+type DualFundingSignComplete struct {
+	// ChannelID serves to uniquely identify the future channel created by
+	// the initiated dual funder workflow.
+	ChannelID uint64
+
+	// CommitSignature is Bob's signature for Alice's version of the
+	// commitment transaction.
+	CommitSignature *btcec.Signature
+
+	// FundingInputScripts are the scripts spending each of Bob's inputs
+	// to the funding transaction, in the order in which his inputs appear
+	// within the canonically sorted funding transaction.
+	FundingInputScripts []*FundingInputScript
+}
+
+// NewDualFundingSignComplete creates, and returns a new
+// DualFundingSignComplete.
+func NewDualFundingSignComplete(chanID uint64, commitSig *btcec.Signature,
+	inputScripts []*FundingInputScript) *DualFundingSignComplete {
+
+	return &DualFundingSignComplete{
+		ChannelID:           chanID,
+		CommitSignature:     commitSig,
+		FundingInputScripts: inputScripts,
+	}
+}
+
+// A compile time check to ensure DualFundingSignComplete implements the
+// lnwire.Message interface.
+var _ Message = (*DualFundingSignComplete)(nil)
+
+// Decode deserializes the serialized DualFundingSignComplete stored in the
+// passed io.Reader into the target DualFundingSignComplete using the
+// deserialization rules defined by the passed protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (c *DualFundingSignComplete) Decode(r io.Reader, pver uint32) error {
+	return readElements(r,
+		&c.ChannelID,
+		&c.CommitSignature,
+		&c.FundingInputScripts)
+}
+
+// Encode serializes the target DualFundingSignComplete into the passed
+// io.Writer implementation. Serialization will observe the rules defined by
+// the passed protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (c *DualFundingSignComplete) Encode(w io.Writer, pver uint32) error {
+	return writeElements(w,
+		c.ChannelID,
+		c.CommitSignature,
+		c.FundingInputScripts)
+}
+
+// Command returns the uint32 code which uniquely identifies this message as a
+// DualFundingSignComplete on the wire.
+//
+// This is part of the lnwire.Message interface.
+func (c *DualFundingSignComplete) Command() uint32 {
+	return CmdDualFundingSignComplete
+}
+
+// MaxPayloadLength returns the maximum allowed payload length for a
+// DualFundingSignComplete. This is calculated by summing the max length of
+// all the fields within a DualFundingSignComplete: 8 + 64, plus the input
+// scripts.
+//
+// This is part of the lnwire.Message interface.
+func (c *DualFundingSignComplete) MaxPayloadLength(uint32) uint32 {
+	return 72 + maxInputScriptsLength
+}
+
+// Validate examines each populated field within the DualFundingSignComplete
+// for field sanity.
+//
+// This is part of the lnwire.Message interface.
+func (c *DualFundingSignComplete) Validate() error {
+	if c.CommitSignature == nil {
+		return fmt.Errorf("commitment signature must be non-nil")
+	}
+
+	if len(c.FundingInputScripts) == 0 {
+		return fmt.Errorf("funding input scripts must be non-empty")
+	}
+
+	// We're good!
+	return nil
+}
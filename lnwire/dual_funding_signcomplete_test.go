@@ -0,0 +1,30 @@
+package lnwire
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestDualFundingSignCompleteWire(t *testing.T) {
+	// First create a new DFSC message.
+	dfsc := NewDualFundingSignComplete(10, commitSig1, fundingInputScripts)
+
+	// Next encode the DFSC message into an empty bytes buffer.
+	var b bytes.Buffer
+	if err := dfsc.Encode(&b, 0); err != nil {
+		t.Fatalf("unable to encode DualFundingSignComplete: %v", err)
+	}
+
+	// Deserialize the encoded DFSC message into a new empty struct.
+	dfsc2 := &DualFundingSignComplete{}
+	if err := dfsc2.Decode(&b, 0); err != nil {
+		t.Fatalf("unable to decode DualFundingSignComplete: %v", err)
+	}
+
+	// Assert equality of the two instances.
+	if !reflect.DeepEqual(dfsc, dfsc2) {
+		t.Fatalf("encode/decode error messages don't match %#v vs %#v",
+			dfsc, dfsc2)
+	}
+}
@@ -29,6 +29,25 @@ const (
 	// channel update or a funding request while their still syncing to the
 	// latest state of the blockchain.
 	ErrSynchronizingChain ErrorCode = 2
+
+	// ErrInvalidDustLimit is returned by a remote peer when the dust limit
+	// proposed within a funding request is outside the range they accept.
+	ErrInvalidDustLimit ErrorCode = 3
+
+	// ErrChanParamsRejected is returned by a remote peer when they decline
+	// the channel parameters proposed within a funding request, such as
+	// its push amount, CSV delay, or channel reserve.
+	ErrChanParamsRejected ErrorCode = 4
+
+	// ErrDualFundingDeclined is returned by a remote peer when they
+	// decline to contribute the requested amount to a dual funded
+	// channel.
+	ErrDualFundingDeclined ErrorCode = 5
+
+	// ErrIncompatiblePeer is returned by a remote peer during the init
+	// handshake when we operate on another chain, or speak a version of
+	// the peer protocol it doesn't interoperate with.
+	ErrIncompatiblePeer ErrorCode = 6
 )
 
 // ErrorGeneric represents a generic error bound to an exact channel. The
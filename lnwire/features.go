@@ -110,10 +110,43 @@ func (f *FeatureVector) SetFeatureFlag(name featureName, flag featureFlag) error
 	return nil
 }
 
+// AddFeature adds the named feature to the vector at the passed index, which
+// may lie beyond the features the vector was created with. This allows
+// experimental features to be assigned a fixed position within the vector,
+// which both peers must agree upon.
+func (f *FeatureVector) AddFeature(name string, index int,
+	flag featureFlag) error {
+
+	if _, ok := f.featuresMap[featureName(name)]; ok {
+		return errors.Errorf("feature %v already exists", name)
+	}
+	if _, ok := f.flags[index]; ok {
+		return errors.Errorf("feature index %v already in use", index)
+	}
+	if flagBitsSize*(index+1) > 8*maxAllowedSize {
+		return errors.Errorf("feature index %v exceeds the maximum "+
+			"size of a feature vector", index)
+	}
+
+	f.featuresMap[featureName(name)] = index
+	f.flags[index] = flag
+	return nil
+}
+
 // serializedSize returns the number of bytes which is needed to represent
 // feature vector in byte format.
 func (f *FeatureVector) serializedSize() uint16 {
-	return uint16(math.Ceil(float64(flagBitsSize*len(f.flags)) / 8))
+	// The vector must be large enough to hold the feature with the
+	// highest index, which may exceed the number of features if any were
+	// added via AddFeature.
+	var numIndexes int
+	for index := range f.flags {
+		if index+1 > numIndexes {
+			numIndexes = index + 1
+		}
+	}
+
+	return uint16(math.Ceil(float64(flagBitsSize*numIndexes) / 8))
 }
 
 // NewFeatureVectorFromReader decodes the feature vector from binary
@@ -251,16 +284,20 @@ func (f *FeatureVector) Compare(f2 *FeatureVector) (*SharedFeatures, error) {
 
 // Copy generate new distinct instance of the feature vector.
 func (f *FeatureVector) Copy() *FeatureVector {
-	features := make([]Feature, len(f.featuresMap))
-
+	// The indexes are copied directly rather than recreating the vector
+	// from a slice of features, as features added via AddFeature may
+	// leave gaps between indexes.
+	featuresMap := make(map[featureName]int, len(f.featuresMap))
+	flags := make(map[int]featureFlag, len(f.featuresMap))
 	for name, index := range f.featuresMap {
-		features[index] = Feature{
-			Name: name,
-			Flag: f.flags[index],
-		}
+		featuresMap[name] = index
+		flags[index] = f.flags[index]
 	}
 
-	return NewFeatureVector(features)
+	return &FeatureVector{
+		featuresMap: featuresMap,
+		flags:       flags,
+	}
 }
 
 // SharedFeatures is a product of comparison of two features vector which
@@ -278,8 +315,8 @@ func newSharedFeatures(f *FeatureVector) *SharedFeatures {
 // IsActive checks is feature active or not, it might be disabled during
 // comparision with remote feature vector if it was optional and remote peer
 // doesn't support it.
-func (f *SharedFeatures) IsActive(name featureName) bool {
-	index, ok := f.featuresMap[name]
+func (f *SharedFeatures) IsActive(name string) bool {
+	index, ok := f.featuresMap[featureName(name)]
 	if !ok {
 		// If we even have no such feature in feature map, than it
 		// can't be active in any circumstances.
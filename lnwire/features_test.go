@@ -117,3 +117,54 @@ func TestDecodeEncodeFeaturesVector(t *testing.T) {
 			"%v", spew.Sdump(f), spew.Sdump(nf))
 	}
 }
+
+// TestAddFeature checks that a feature added at an index beyond the initial
+// features survives encoding, and is only active if both peers have it.
+func TestAddFeature(t *testing.T) {
+	const (
+		first  = "first"
+		custom = "custom"
+	)
+
+	localFeatures := NewFeatureVector([]Feature{
+		{first, OptionalFlag},
+	})
+	if err := localFeatures.AddFeature(custom, 50, OptionalFlag); err != nil {
+		t.Fatalf("unable to add feature: %v", err)
+	}
+	if err := localFeatures.AddFeature("other", 50, OptionalFlag); err == nil {
+		t.Fatalf("feature index shouldn't be reused")
+	}
+
+	var b bytes.Buffer
+	if err := localFeatures.Encode(&b); err != nil {
+		t.Fatalf("error while encoding feature vector: %v", err)
+	}
+	remoteFeatures, err := NewFeatureVectorFromReader(&b)
+	if err != nil {
+		t.Fatalf("error while decoding feature vector: %v", err)
+	}
+	if !reflect.DeepEqual(localFeatures.flags, remoteFeatures.flags) {
+		t.Fatalf("encode/decode feature vector don't match %v vs "+
+			"%v", spew.Sdump(localFeatures), spew.Sdump(remoteFeatures))
+	}
+
+	shared, err := localFeatures.Compare(remoteFeatures)
+	if err != nil {
+		t.Fatalf("error while feature vector compare: %v", err)
+	}
+	if !shared.IsActive(custom) {
+		t.Fatalf("feature advertised by both peers should be active")
+	}
+
+	shared, err = localFeatures.Compare(NewFeatureVector([]Feature{
+		{first, OptionalFlag},
+	}))
+	if err != nil {
+		t.Fatalf("error while feature vector compare: %v", err)
+	}
+	if shared.IsActive(custom) {
+		t.Fatalf("feature not advertised by the remote peer shouldn't " +
+			"be active")
+	}
+}
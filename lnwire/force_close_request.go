@@ -0,0 +1,74 @@
+package lnwire
+
+import (
+	"io"
+
+	"github.com/roasbeef/btcd/wire"
+)
+
+// ForceCloseRequest is sent by a node which has lost its state for a channel,
+// either as it's restoring the channel from a static channel backup, or as it
+// detected the loss upon re-establishing the channel. As the node can no
+// longer safely broadcast its own commitment transaction, it asks the remote
+// party to broadcast theirs instead, which pays the node its balance without
+// delay.
+type ForceCloseRequest struct {
+	// ChannelPoint serves to identify which channel is to be closed.
+	ChannelPoint wire.OutPoint
+}
+
+// NewForceCloseRequest creates a new ForceCloseRequest message for the target
+// channel.
+func NewForceCloseRequest(chanPoint wire.OutPoint) *ForceCloseRequest {
+	return &ForceCloseRequest{
+		ChannelPoint: chanPoint,
+	}
+}
+
+// A compile time check to ensure ForceCloseRequest implements the
+// lnwire.Message interface.
+var _ Message = (*ForceCloseRequest)(nil)
+
+// Decode deserializes a serialized ForceCloseRequest message stored in the
+// passed io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (f *ForceCloseRequest) Decode(r io.Reader, pver uint32) error {
+	// ChannelPoint (36)
+	return readElements(r, &f.ChannelPoint)
+}
+
+// Encode serializes the target ForceCloseRequest into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (f *ForceCloseRequest) Encode(w io.Writer, pver uint32) error {
+	// ChannelPoint (36)
+	return writeElements(w, f.ChannelPoint)
+}
+
+// Command returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (f *ForceCloseRequest) Command() uint32 {
+	return CmdForceCloseRequest
+}
+
+// MaxPayloadLength returns the maximum allowed payload size for a
+// ForceCloseRequest message observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (f *ForceCloseRequest) MaxPayloadLength(uint32) uint32 {
+	// 36
+	return 36
+}
+
+// Validate performs any necessary sanity checks to ensure all fields present
+// on the ForceCloseRequest are valid.
+//
+// This is part of the lnwire.Message interface.
+func (f *ForceCloseRequest) Validate() error {
+	// We're good!
+	return nil
+}
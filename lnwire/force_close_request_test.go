@@ -0,0 +1,31 @@
+package lnwire
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestForceCloseRequestEncodeDecode(t *testing.T) {
+	req := NewForceCloseRequest(*outpoint1)
+
+	// Next encode the ForceCloseRequest message into an empty bytes
+	// buffer.
+	var b bytes.Buffer
+	if err := req.Encode(&b, 0); err != nil {
+		t.Fatalf("unable to encode ForceCloseRequest: %v", err)
+	}
+
+	// Deserialize the encoded ForceCloseRequest message into a new empty
+	// struct.
+	req2 := &ForceCloseRequest{}
+	if err := req2.Decode(&b, 0); err != nil {
+		t.Fatalf("unable to decode ForceCloseRequest: %v", err)
+	}
+
+	// Assert equality of the two instances.
+	if !reflect.DeepEqual(req, req2) {
+		t.Fatalf("encode/decode error messages don't match %#v vs %#v",
+			req, req2)
+	}
+}
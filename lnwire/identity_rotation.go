@@ -0,0 +1,82 @@
+package lnwire
+
+import (
+	"io"
+
+	"github.com/roasbeef/btcd/btcec"
+)
+
+// IdentityRotation is sent by a node which is about to rotate its identity
+// key to each peer it has channels with, over the connection authenticated by
+// its current identity key. The message announces the node's next identity
+// key, and carries a signature by that key proving the node holds it. Upon
+// receipt, the peer records the rotation, which it applies to its channels
+// with the node once the node reconnects under its new identity.
+type IdentityRotation struct {
+	// NewNodeID is the identity key the node will be known by once it has
+	// rotated its identity.
+	NewNodeID *btcec.PublicKey
+
+	// Signature is a signature by NewNodeID over the double-sha256 of the
+	// node's current identity key followed by its new identity key, both
+	// in compressed form.
+	Signature *btcec.Signature
+}
+
+// A compile time check to ensure IdentityRotation implements the
+// lnwire.Message interface.
+var _ Message = (*IdentityRotation)(nil)
+
+// Decode deserializes a serialized IdentityRotation message stored in the
+// passed io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (r *IdentityRotation) Decode(rd io.Reader, pver uint32) error {
+	return readElements(rd,
+		&r.NewNodeID,
+		&r.Signature,
+	)
+}
+
+// Encode serializes the target IdentityRotation into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (r *IdentityRotation) Encode(w io.Writer, pver uint32) error {
+	return writeElements(w,
+		r.NewNodeID,
+		r.Signature,
+	)
+}
+
+// Command returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (r *IdentityRotation) Command() uint32 {
+	return CmdIdentityRotation
+}
+
+// MaxPayloadLength returns the maximum allowed payload size for a
+// IdentityRotation message observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (r *IdentityRotation) MaxPayloadLength(uint32) uint32 {
+	var length uint32
+
+	// NewNodeID - 33 bytes
+	length += 33
+
+	// Signature - 64 bytes
+	length += 64
+
+	return length
+}
+
+// Validate performs any necessary sanity checks to ensure all fields present
+// on the IdentityRotation are valid.
+//
+// This is part of the lnwire.Message interface.
+func (r *IdentityRotation) Validate() error {
+	return nil
+}
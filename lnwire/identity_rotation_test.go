@@ -0,0 +1,32 @@
+package lnwire
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestIdentityRotationEncodeDecode(t *testing.T) {
+	rotation := &IdentityRotation{
+		NewNodeID: pubKey,
+		Signature: someSig,
+	}
+
+	// Next encode the message into an empty bytes buffer.
+	var b bytes.Buffer
+	if err := rotation.Encode(&b, 0); err != nil {
+		t.Fatalf("unable to encode identity rotation: %v", err)
+	}
+
+	// Deserialize the encoded message into a new empty struct.
+	rotation2 := &IdentityRotation{}
+	if err := rotation2.Decode(&b, 0); err != nil {
+		t.Fatalf("unable to decode identity rotation: %v", err)
+	}
+
+	// Assert equality of the two instances.
+	if !reflect.DeepEqual(rotation, rotation2) {
+		t.Fatalf("encode/decode identity rotation messages don't "+
+			"match %#v vs %#v", rotation, rotation2)
+	}
+}
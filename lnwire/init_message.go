@@ -1,8 +1,20 @@
 package lnwire
 
 import (
-	"github.com/go-errors/errors"
 	"io"
+
+	"github.com/go-errors/errors"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+)
+
+const (
+	// ProtocolVersion is the version of the peer protocol spoken by this
+	// node, advertised within its init message.
+	ProtocolVersion uint32 = 1
+
+	// MinProtocolVersion is the lowest version of the peer protocol spoken
+	// by a remote node which this node interoperates with.
+	MinProtocolVersion uint32 = 1
 )
 
 // Init is the first message reveals the features supported or required by this
@@ -17,6 +29,22 @@ type Init struct {
 	// LocalFeatures is feature vector which only affect the protocol
 	// between two nodes.
 	LocalFeatures *FeatureVector
+
+	// ChainHash is the genesis hash of the chain the node operates on,
+	// so nodes of different networks refuse each other before any
+	// channel is negotiated. Along with the protocol versions, it's
+	// encoded as optional trailing fields, written only if set, so it's
+	// understood by older nodes. A zero hash denotes a node which doesn't
+	// advertise its chain.
+	ChainHash chainhash.Hash
+
+	// ProtocolVersion is the version of the peer protocol spoken by the
+	// node.
+	ProtocolVersion uint32
+
+	// MinProtocolVersion is the lowest version of the peer protocol
+	// spoken by a remote node which the node interoperates with.
+	MinProtocolVersion uint32
 }
 
 // NewInitMessage creates new instance of init message object.
@@ -34,10 +62,30 @@ func NewInitMessage(gf, lf *FeatureVector) *Init {
 func (msg *Init) Decode(r io.Reader, pver uint32) error {
 	// LocalFeatures(~)
 	// GlobalFeatures(~)
-	return readElements(r,
+	// ChainHash(32, optional)
+	// ProtocolVersion(4, optional)
+	// MinProtocolVersion(4, optional)
+	err := readElements(r,
 		&msg.LocalFeatures,
 		&msg.GlobalFeatures,
 	)
+	if err != nil {
+		return err
+	}
+
+	// Nodes which don't advertise their chain omit the trailing fields
+	// altogether.
+	switch err := readElement(r, msg.ChainHash[:]); {
+	case err == io.EOF:
+		return nil
+	case err != nil:
+		return err
+	}
+
+	return readElements(r,
+		&msg.ProtocolVersion,
+		&msg.MinProtocolVersion,
+	)
 }
 
 // A compile time check to ensure Init implements the lnwire.Message
@@ -49,10 +97,22 @@ var _ Message = (*Init)(nil)
 //
 // This is part of the lnwire.Message interface.
 func (msg *Init) Encode(w io.Writer, pver uint32) error {
-	return writeElements(w,
+	err := writeElements(w,
 		msg.LocalFeatures,
 		msg.GlobalFeatures,
 	)
+	if err != nil {
+		return err
+	}
+
+	if msg.ChainHash == (chainhash.Hash{}) {
+		return nil
+	}
+	return writeElements(w,
+		msg.ChainHash[:],
+		msg.ProtocolVersion,
+		msg.MinProtocolVersion,
+	)
 }
 
 // Command returns the integer uniquely identifying this message type on the
@@ -68,7 +128,7 @@ func (msg *Init) Command() uint32 {
 //
 // This is part of the lnwire.Message interface.
 func (msg *Init) MaxPayloadLength(uint32) uint32 {
-	return 2 + maxAllowedSize + 2 + maxAllowedSize
+	return 2 + maxAllowedSize + 2 + maxAllowedSize + 32 + 4 + 4
 }
 
 // Validate performs any necessary sanity checks to ensure all fields present
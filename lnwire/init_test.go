@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"reflect"
 	"testing"
+
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
 )
 
 func TestInitEncodeDecode(t *testing.T) {
@@ -45,3 +47,56 @@ func TestInitEncodeDecode(t *testing.T) {
 			init1, init2)
 	}
 }
+
+// TestInitChainInfo tests that the chain hash and protocol versions of an
+// init message survive a round trip, and that an init message without them,
+// as sent by older nodes, is still decoded.
+func TestInitChainInfo(t *testing.T) {
+	init1 := &Init{
+		GlobalFeatures:     NewFeatureVector(nil),
+		LocalFeatures:      NewFeatureVector(nil),
+		ChainHash:          chainhash.Hash{1, 2, 3},
+		ProtocolVersion:    ProtocolVersion,
+		MinProtocolVersion: MinProtocolVersion,
+	}
+
+	var b bytes.Buffer
+	if err := init1.Encode(&b, 0); err != nil {
+		t.Fatalf("unable to encode init: %v", err)
+	}
+	init2 := &Init{}
+	if err := init2.Decode(&b, 0); err != nil {
+		t.Fatalf("unable to decode init: %v", err)
+	}
+	if init2.ChainHash != init1.ChainHash {
+		t.Fatalf("chain hash mismatch: expected %v, got %v",
+			init1.ChainHash, init2.ChainHash)
+	}
+	if init2.ProtocolVersion != init1.ProtocolVersion ||
+		init2.MinProtocolVersion != init1.MinProtocolVersion {
+
+		t.Fatalf("protocol versions mismatch: expected %v/%v, got "+
+			"%v/%v", init1.ProtocolVersion,
+			init1.MinProtocolVersion, init2.ProtocolVersion,
+			init2.MinProtocolVersion)
+	}
+
+	// Without a chain hash, none of the trailing fields are written, so
+	// the message is identical to that of older nodes.
+	legacy := &Init{
+		GlobalFeatures:  NewFeatureVector(nil),
+		LocalFeatures:   NewFeatureVector(nil),
+		ProtocolVersion: ProtocolVersion,
+	}
+	b.Reset()
+	if err := legacy.Encode(&b, 0); err != nil {
+		t.Fatalf("unable to encode init: %v", err)
+	}
+	init3 := &Init{}
+	if err := init3.Decode(&b, 0); err != nil {
+		t.Fatalf("unable to decode legacy init: %v", err)
+	}
+	if init3.ChainHash != (chainhash.Hash{}) || init3.ProtocolVersion != 0 {
+		t.Fatalf("legacy init shouldn't carry chain info")
+	}
+}
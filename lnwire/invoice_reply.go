@@ -0,0 +1,92 @@
+package lnwire
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/roasbeef/btcutil"
+)
+
+// maxInvoiceReplyProblem is the maximum length of the Problem string carried
+// within an InvoiceReply.
+const maxInvoiceReplyProblem = 1024
+
+// InvoiceReply is sent in response to an InvoiceRequest. If the invoice was
+// created successfully, then the reply carries the payment hash and amount of
+// the fresh invoice. Otherwise, Problem describes why the request was
+// rejected.
+type InvoiceReply struct {
+	// RequestID is the identifier of the InvoiceRequest this message is
+	// replying to.
+	RequestID uint64
+
+	// PaymentHash is the payment hash of the newly created invoice.
+	PaymentHash [32]byte
+
+	// Amount is the amount the newly created invoice requests.
+	Amount btcutil.Amount
+
+	// Problem is a human readable string detailing why the invoice
+	// request was rejected. It is empty on success.
+	Problem string
+}
+
+// A compile time check to ensure InvoiceReply implements the lnwire.Message
+// interface.
+var _ Message = (*InvoiceReply)(nil)
+
+// Decode deserializes a serialized InvoiceReply message stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (c *InvoiceReply) Decode(r io.Reader, pver uint32) error {
+	return readElements(r,
+		&c.RequestID,
+		c.PaymentHash[:],
+		&c.Amount,
+		&c.Problem,
+	)
+}
+
+// Encode serializes the target InvoiceReply into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (c *InvoiceReply) Encode(w io.Writer, pver uint32) error {
+	return writeElements(w,
+		c.RequestID,
+		c.PaymentHash[:],
+		c.Amount,
+		c.Problem,
+	)
+}
+
+// Command returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (c *InvoiceReply) Command() uint32 {
+	return CmdInvoiceReply
+}
+
+// MaxPayloadLength returns the maximum allowed payload size for an
+// InvoiceReply message observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (c *InvoiceReply) MaxPayloadLength(uint32) uint32 {
+	// 8 + 32 + 8 + 3 + 1024
+	return 1075
+}
+
+// Validate performs any necessary sanity checks to ensure all fields present
+// on the InvoiceReply are valid.
+//
+// This is part of the lnwire.Message interface.
+func (c *InvoiceReply) Validate() error {
+	if len(c.Problem) > maxInvoiceReplyProblem {
+		return fmt.Errorf("problem string length too large: %v",
+			len(c.Problem))
+	}
+
+	return nil
+}
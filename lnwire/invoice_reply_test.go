@@ -0,0 +1,36 @@
+package lnwire
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/roasbeef/btcutil"
+)
+
+func TestInvoiceReplyEncodeDecode(t *testing.T) {
+	reply := &InvoiceReply{
+		RequestID:   42,
+		PaymentHash: revHash,
+		Amount:      btcutil.Amount(1000),
+		Problem:     "offer not found",
+	}
+
+	// Next encode the reply message into an empty bytes buffer.
+	var b bytes.Buffer
+	if err := reply.Encode(&b, 0); err != nil {
+		t.Fatalf("unable to encode InvoiceReply: %v", err)
+	}
+
+	// Deserialize the encoded message into a new empty struct.
+	reply2 := &InvoiceReply{}
+	if err := reply2.Decode(&b, 0); err != nil {
+		t.Fatalf("unable to decode InvoiceReply: %v", err)
+	}
+
+	// Assert equality of the two instances.
+	if !reflect.DeepEqual(reply, reply2) {
+		t.Fatalf("encode/decode error messages don't match %#v vs %#v",
+			reply, reply2)
+	}
+}
@@ -0,0 +1,78 @@
+package lnwire
+
+import (
+	"io"
+
+	"github.com/roasbeef/btcutil"
+)
+
+// InvoiceRequest is sent by a node wishing to pay a reusable offer. The
+// recipient of the request creates a fresh invoice for the referenced offer
+// and replies with an InvoiceReply bearing the same RequestID.
+type InvoiceRequest struct {
+	// RequestID is a unique identifier chosen by the requester which is
+	// echoed back within the InvoiceReply, allowing several concurrent
+	// requests to the same node.
+	RequestID uint64
+
+	// OfferID identifies the offer that an invoice is being requested
+	// for.
+	OfferID [32]byte
+
+	// Amount is the amount the payer wishes to pay. This is only
+	// consulted if the offer itself doesn't specify an amount.
+	Amount btcutil.Amount
+}
+
+// A compile time check to ensure InvoiceRequest implements the
+// lnwire.Message interface.
+var _ Message = (*InvoiceRequest)(nil)
+
+// Decode deserializes a serialized InvoiceRequest message stored in the
+// passed io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (c *InvoiceRequest) Decode(r io.Reader, pver uint32) error {
+	return readElements(r,
+		&c.RequestID,
+		c.OfferID[:],
+		&c.Amount,
+	)
+}
+
+// Encode serializes the target InvoiceRequest into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (c *InvoiceRequest) Encode(w io.Writer, pver uint32) error {
+	return writeElements(w,
+		c.RequestID,
+		c.OfferID[:],
+		c.Amount,
+	)
+}
+
+// Command returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (c *InvoiceRequest) Command() uint32 {
+	return CmdInvoiceRequest
+}
+
+// MaxPayloadLength returns the maximum allowed payload size for an
+// InvoiceRequest message observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (c *InvoiceRequest) MaxPayloadLength(uint32) uint32 {
+	// 8 + 32 + 8
+	return 48
+}
+
+// Validate performs any necessary sanity checks to ensure all fields present
+// on the InvoiceRequest are valid.
+//
+// This is part of the lnwire.Message interface.
+func (c *InvoiceRequest) Validate() error {
+	return nil
+}
@@ -0,0 +1,35 @@
+package lnwire
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/roasbeef/btcutil"
+)
+
+func TestInvoiceRequestEncodeDecode(t *testing.T) {
+	req := &InvoiceRequest{
+		RequestID: 42,
+		OfferID:   revHash,
+		Amount:    btcutil.Amount(1000),
+	}
+
+	// Next encode the request message into an empty bytes buffer.
+	var b bytes.Buffer
+	if err := req.Encode(&b, 0); err != nil {
+		t.Fatalf("unable to encode InvoiceRequest: %v", err)
+	}
+
+	// Deserialize the encoded message into a new empty struct.
+	req2 := &InvoiceRequest{}
+	if err := req2.Decode(&b, 0); err != nil {
+		t.Fatalf("unable to decode InvoiceRequest: %v", err)
+	}
+
+	// Assert equality of the two instances.
+	if !reflect.DeepEqual(req, req2) {
+		t.Fatalf("encode/decode error messages don't match %#v vs %#v",
+			req, req2)
+	}
+}
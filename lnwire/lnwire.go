@@ -24,6 +24,41 @@ const MaxSliceLength = 65535
 // key script.
 type PkScript []byte
 
+// maxTxOutPkScriptSize is the size of the largest public key script accepted
+// for an output of a funding transaction, that of a P2WSH output.
+const maxTxOutPkScriptSize = 34
+
+// maxInputWitnessItems is the maximum number of items within the witness
+// of an input to a funding transaction.
+const maxInputWitnessItems = 4
+
+// maxTxInsLength is the maximum serialized length of the inputs a party
+// contributes to a funding transaction: a one byte count, followed by up to
+// 127 outpoints.
+const maxTxInsLength = 1 + 127*36
+
+// maxTxOutsLength is the maximum serialized length of the change outputs a
+// party contributes to a funding transaction: a one byte count, followed by
+// up to 127 values, each with a length prefixed public key script.
+const maxTxOutsLength = 1 + 127*(8+1+maxTxOutPkScriptSize)
+
+// maxInputScriptsLength is the maximum serialized length of the scripts
+// spending the inputs a party contributes to a funding transaction: a one
+// byte count, followed by up to 127 signature scripts and witnesses.
+const maxInputScriptsLength = 1 + 127*(3+txscript.MaxScriptElementSize+1+
+	maxInputWitnessItems*(3+txscript.MaxScriptElementSize))
+
+// FundingInputScript holds the scripts which spend one of the inputs a party
+// contributes to the funding transaction of a dual funded channel.
+type FundingInputScript struct {
+	// Witness is the witness stack of the input.
+	Witness wire.TxWitness
+
+	// ScriptSig is the signature script of the input, which is only
+	// populated when spending a nested p2sh witness output.
+	ScriptSig []byte
+}
+
 // CreditsAmount are the native currency unit used within the Lightning Network.
 // Credits are denominated in sub-satoshi amounts, so micro-satoshis (1/1000).
 // This value is purposefully signed in order to allow the expression of negative
@@ -234,6 +269,63 @@ func writeElement(w io.Writer, element interface{}) error {
 		if _, err := w.Write(idx[:]); err != nil {
 			return err
 		}
+	case []*wire.TxOut:
+		if len(e) > 127 {
+			return fmt.Errorf("Too many txouts")
+		}
+
+		// Write out the number of txouts.
+		if err := writeElement(w, uint8(len(e))); err != nil {
+			return err
+		}
+
+		// Followed by the value and public key script of each.
+		for _, out := range e {
+			if len(out.PkScript) > maxTxOutPkScriptSize {
+				return fmt.Errorf("txout pkscript too long")
+			}
+
+			if err := writeElement(w, out.Value); err != nil {
+				return err
+			}
+			err := wire.WriteVarBytes(w, 0, out.PkScript)
+			if err != nil {
+				return err
+			}
+		}
+	case []*FundingInputScript:
+		if len(e) > 127 {
+			return fmt.Errorf("Too many input scripts")
+		}
+
+		// Write out the number of input scripts.
+		if err := writeElement(w, uint8(len(e))); err != nil {
+			return err
+		}
+
+		// Each input script is written as its signature script,
+		// followed by the number of items within its witness, then
+		// each witness item in series.
+		for _, script := range e {
+			if len(script.Witness) > maxInputWitnessItems {
+				return fmt.Errorf("too many witness items")
+			}
+
+			err := wire.WriteVarBytes(w, 0, script.ScriptSig)
+			if err != nil {
+				return err
+			}
+
+			numItems := uint8(len(script.Witness))
+			if err := writeElement(w, numItems); err != nil {
+				return err
+			}
+			for _, item := range script.Witness {
+				if err := wire.WriteVarBytes(w, 0, item); err != nil {
+					return err
+				}
+			}
+		}
 	case *FeatureVector:
 		if err := e.Encode(w); err != nil {
 			return err
@@ -532,6 +624,68 @@ func readElement(r io.Reader, element interface{}) error {
 		}
 		(*e).PreviousOutPoint.Index = binary.BigEndian.Uint32(idxBytes[:])
 		return nil
+	case *[]*wire.TxOut:
+		var numOuts uint8
+		if err := readElement(r, &numOuts); err != nil {
+			return err
+		}
+		if numOuts > 127 {
+			return fmt.Errorf("Too many txouts")
+		}
+
+		txouts := make([]*wire.TxOut, 0, numOuts)
+		for i := uint8(0); i < numOuts; i++ {
+			var value int64
+			if err := readElement(r, &value); err != nil {
+				return err
+			}
+			pkScript, err := wire.ReadVarBytes(r, 0,
+				maxTxOutPkScriptSize, "pkscript")
+			if err != nil {
+				return err
+			}
+
+			txouts = append(txouts, wire.NewTxOut(value, pkScript))
+		}
+		*e = txouts
+	case *[]*FundingInputScript:
+		var numScripts uint8
+		if err := readElement(r, &numScripts); err != nil {
+			return err
+		}
+		if numScripts > 127 {
+			return fmt.Errorf("Too many input scripts")
+		}
+
+		scripts := make([]*FundingInputScript, 0, numScripts)
+		for i := uint8(0); i < numScripts; i++ {
+			script := &FundingInputScript{}
+			script.ScriptSig, err = wire.ReadVarBytes(r, 0,
+				txscript.MaxScriptElementSize, "scriptsig")
+			if err != nil {
+				return err
+			}
+
+			var numItems uint8
+			if err := readElement(r, &numItems); err != nil {
+				return err
+			}
+			if numItems > maxInputWitnessItems {
+				return fmt.Errorf("too many witness items")
+			}
+
+			script.Witness = make(wire.TxWitness, numItems)
+			for j := uint8(0); j < numItems; j++ {
+				script.Witness[j], err = wire.ReadVarBytes(r, 0,
+					txscript.MaxScriptElementSize, "witness")
+				if err != nil {
+					return err
+				}
+			}
+
+			scripts = append(scripts, script)
+		}
+		*e = scripts
 	case *wire.OutPoint:
 		// TODO(roasbeef): consolidate with above
 		var h [32]byte
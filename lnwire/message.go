@@ -32,18 +32,41 @@ const (
 	CmdSingleFundingComplete     = uint32(120)
 	CmdSingleFundingSignComplete = uint32(130)
 
+	// Commands for opening a channel funded by both parties (dual funder).
+	CmdDualFundingRequest      = uint32(140)
+	CmdDualFundingResponse     = uint32(150)
+	CmdDualFundingComplete     = uint32(160)
+	CmdDualFundingSignComplete = uint32(170)
+
 	// Command for locking a funded channel
 	CmdFundingLocked = uint32(200)
 
+	// Command for re-establishing an active channel upon reconnecting.
+	CmdChannelReestablish = uint32(210)
+
 	// Commands for the workflow of cooperatively closing an active channel.
+	CmdShutdown      = uint32(290)
 	CmdCloseRequest  = uint32(300)
 	CmdCloseComplete = uint32(310)
 
+	// Command for requesting the remote party force close a channel we've
+	// lost the state of.
+	CmdForceCloseRequest = uint32(320)
+
+	// Commands for the workflow of splicing funds into an active channel.
+	CmdSpliceRequest  = uint32(400)
+	CmdSpliceAccept   = uint32(410)
+	CmdSpliceSigned   = uint32(420)
+	CmdSpliceComplete = uint32(430)
+
 	// Commands for negotiating HTLCs.
 	CmdUpdateAddHTLC    = uint32(1000)
 	CmdUpdateFufillHTLC = uint32(1010)
 	CmdUpdateFailHTLC   = uint32(1020)
 
+	// Command for updating the fee of commitment transactions.
+	CmdUpdateFee = uint32(1030)
+
 	// Commands for modifying commitment transactions.
 	CmdCommitSig    = uint32(2000)
 	CmdRevokeAndAck = uint32(2010)
@@ -59,8 +82,44 @@ const (
 	// Commands for connection keep-alive.
 	CmdPing = uint32(6000)
 	CmdPong = uint32(6010)
+
+	// Commands for requesting invoices for reusable offers.
+	CmdInvoiceRequest = uint32(7000)
+	CmdInvoiceReply   = uint32(7010)
+
+	// Command for relaying messages along onion routes.
+	CmdOnionMessage = uint32(7100)
+
+	// Commands for exchanging backups stored with peers.
+	CmdPeerStorage          = uint32(8000)
+	CmdPeerStorageRetrieval = uint32(8010)
+
+	// Command for releasing HTLCs held for an offline recipient.
+	CmdReleaseHeldHTLCs = uint32(9000)
+
+	// Command for announcing the rotation of a node's identity key.
+	CmdIdentityRotation = uint32(9100)
+
+	// Commands for uploading justice kits to watchtowers.
+	CmdCreateSession      = uint32(9200)
+	CmdCreateSessionReply = uint32(9210)
+	CmdStateUpdate        = uint32(9220)
+	CmdStateUpdateReply   = uint32(9230)
+
+	// CmdCustomMessageStart is the first message type within the
+	// experimental range. Messages of these types are never interpreted
+	// by lnwire, and are instead parsed as a CustomMessage.
+	CmdCustomMessageStart = uint32(32768)
 )
 
+// MaxPeerStorageBlob is the maximum size of the backup blob a peer may ask us
+// to store on its behalf.
+const MaxPeerStorageBlob = 65531
+
+// maxCustomMessagePayload is the maximum payload of a CustomMessage, which is
+// bounded only by the limit on the size of a message within the protocol.
+const maxCustomMessagePayload = 65535
+
 // UnknownMessage is an implementation of the error interface that allows the
 // creation of an error in response to an unknown message.
 type UnknownMessage struct {
@@ -102,18 +161,42 @@ func makeEmptyMessage(command uint32) (Message, error) {
 		msg = &SingleFundingComplete{}
 	case CmdSingleFundingSignComplete:
 		msg = &SingleFundingSignComplete{}
+	case CmdDualFundingRequest:
+		msg = &DualFundingRequest{}
+	case CmdDualFundingResponse:
+		msg = &DualFundingResponse{}
+	case CmdDualFundingComplete:
+		msg = &DualFundingComplete{}
+	case CmdDualFundingSignComplete:
+		msg = &DualFundingSignComplete{}
 	case CmdFundingLocked:
 		msg = &FundingLocked{}
+	case CmdChannelReestablish:
+		msg = &ChannelReestablish{}
+	case CmdShutdown:
+		msg = &Shutdown{}
 	case CmdCloseRequest:
 		msg = &CloseRequest{}
 	case CmdCloseComplete:
 		msg = &CloseComplete{}
+	case CmdForceCloseRequest:
+		msg = &ForceCloseRequest{}
+	case CmdSpliceRequest:
+		msg = &SpliceRequest{}
+	case CmdSpliceAccept:
+		msg = &SpliceAccept{}
+	case CmdSpliceSigned:
+		msg = &SpliceSigned{}
+	case CmdSpliceComplete:
+		msg = &SpliceComplete{}
 	case CmdUpdateAddHTLC:
 		msg = &UpdateAddHTLC{}
 	case CmdUpdateFailHTLC:
 		msg = &UpdateFailHTLC{}
 	case CmdUpdateFufillHTLC:
 		msg = &UpdateFufillHTLC{}
+	case CmdUpdateFee:
+		msg = &UpdateFee{}
 	case CmdCommitSig:
 		msg = &CommitSig{}
 	case CmdRevokeAndAck:
@@ -130,7 +213,34 @@ func makeEmptyMessage(command uint32) (Message, error) {
 		msg = &Ping{}
 	case CmdPong:
 		msg = &Pong{}
+	case CmdInvoiceRequest:
+		msg = &InvoiceRequest{}
+	case CmdInvoiceReply:
+		msg = &InvoiceReply{}
+	case CmdOnionMessage:
+		msg = &OnionMessage{}
+	case CmdPeerStorage:
+		msg = &PeerStorage{}
+	case CmdPeerStorageRetrieval:
+		msg = &PeerStorageRetrieval{}
+	case CmdReleaseHeldHTLCs:
+		msg = &ReleaseHeldHTLCs{}
+	case CmdIdentityRotation:
+		msg = &IdentityRotation{}
+	case CmdCreateSession:
+		msg = &CreateSession{}
+	case CmdCreateSessionReply:
+		msg = &CreateSessionReply{}
+	case CmdStateUpdate:
+		msg = &StateUpdate{}
+	case CmdStateUpdateReply:
+		msg = &StateUpdateReply{}
 	default:
+		if command >= CmdCustomMessageStart {
+			msg = &CustomMessage{Type: command}
+			break
+		}
+
 		return nil, fmt.Errorf("unhandled command [%d]", command)
 	}
 
@@ -0,0 +1,106 @@
+package lnwire
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// MaxOnionMessagePayload is the maximum size of the encrypted payload carried
+// by an OnionMessage.
+const MaxOnionMessagePayload = 4096
+
+// OnionMessage carries a message to a node we may not be connected to,
+// routed along a path of nodes through the channel graph without any funds
+// being committed. Each node along the path processes the onion to learn
+// only the next hop, while the payload is encrypted to the destination.
+type OnionMessage struct {
+	// OnionBlob is the serialized Sphinx onion packet routing the message
+	// to its destination. The packet commits to the EphemeralKey and
+	// Payload, so neither may be swapped out along the way.
+	OnionBlob [OnionPacketSize]byte
+
+	// EphemeralKey is the key the sender combined with the destination's
+	// identity key to derive the key the Payload is encrypted under.
+	EphemeralKey *btcec.PublicKey
+
+	// Payload is the encrypted message to be delivered to the
+	// destination.
+	Payload []byte
+}
+
+// A compile time check to ensure OnionMessage implements the lnwire.Message
+// interface.
+var _ Message = (*OnionMessage)(nil)
+
+// Decode deserializes a serialized OnionMessage stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (c *OnionMessage) Decode(r io.Reader, pver uint32) error {
+	err := readElements(r,
+		c.OnionBlob[:],
+		&c.EphemeralKey,
+	)
+	if err != nil {
+		return err
+	}
+
+	payload, err := wire.ReadVarBytes(r, 0, MaxOnionMessagePayload,
+		"payload")
+	if err != nil {
+		return err
+	}
+	c.Payload = payload
+
+	return nil
+}
+
+// Encode serializes the target OnionMessage into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (c *OnionMessage) Encode(w io.Writer, pver uint32) error {
+	err := writeElements(w,
+		c.OnionBlob[:],
+		c.EphemeralKey,
+	)
+	if err != nil {
+		return err
+	}
+
+	return wire.WriteVarBytes(w, 0, c.Payload)
+}
+
+// Command returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (c *OnionMessage) Command() uint32 {
+	return CmdOnionMessage
+}
+
+// MaxPayloadLength returns the maximum allowed payload size for an
+// OnionMessage observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (c *OnionMessage) MaxPayloadLength(uint32) uint32 {
+	// 1254 + 33 + 3 byte length prefix + 4096
+	return OnionPacketSize + 33 + 3 + MaxOnionMessagePayload
+}
+
+// Validate performs any necessary sanity checks to ensure all fields present
+// on the OnionMessage are valid.
+//
+// This is part of the lnwire.Message interface.
+func (c *OnionMessage) Validate() error {
+	if len(c.Payload) > MaxOnionMessagePayload {
+		return fmt.Errorf("onion message payload of %v bytes exceeds "+
+			"the maximum of %v", len(c.Payload),
+			MaxOnionMessagePayload)
+	}
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package lnwire
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestOnionMessageEncodeDecode(t *testing.T) {
+	msg := &OnionMessage{
+		EphemeralKey: pubKey,
+		Payload:      bytes.Repeat([]byte{0xbb}, 200),
+	}
+	copy(msg.OnionBlob[:], bytes.Repeat([]byte{0xaa}, OnionPacketSize))
+
+	// Next encode the message into an empty bytes buffer.
+	var b bytes.Buffer
+	if err := msg.Encode(&b, 0); err != nil {
+		t.Fatalf("unable to encode onion message: %v", err)
+	}
+
+	// Deserialize the encoded message into a new empty struct.
+	msg2 := &OnionMessage{}
+	if err := msg2.Decode(&b, 0); err != nil {
+		t.Fatalf("unable to decode onion message: %v", err)
+	}
+
+	// Assert equality of the two instances.
+	if !reflect.DeepEqual(msg, msg2) {
+		t.Fatalf("encode/decode onion messages don't match %#v vs %#v",
+			msg, msg2)
+	}
+
+	// A payload beyond the maximum size must be rejected.
+	msg.Payload = make([]byte, MaxOnionMessagePayload+1)
+	if err := msg.Validate(); err == nil {
+		t.Fatalf("oversized payload should be rejected")
+	}
+}
@@ -0,0 +1,139 @@
+package lnwire
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/roasbeef/btcd/wire"
+)
+
+// PeerStorage is sent to a peer we have channels with in order to have it
+// store an opaque backup blob on our behalf. Each PeerStorage message
+// replaces the blob previously stored by the peer. The blob is returned to us
+// within a PeerStorageRetrieval message each time we reconnect.
+type PeerStorage struct {
+	// Blob is the opaque backup to be stored. It's encrypted by the
+	// sender, so the storing peer is unable to interpret it.
+	Blob []byte
+}
+
+// A compile time check to ensure PeerStorage implements the lnwire.Message
+// interface.
+var _ Message = (*PeerStorage)(nil)
+
+// Decode deserializes a serialized PeerStorage message stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (p *PeerStorage) Decode(r io.Reader, pver uint32) error {
+	blob, err := wire.ReadVarBytes(r, 0, MaxPeerStorageBlob, "blob")
+	if err != nil {
+		return err
+	}
+	p.Blob = blob
+
+	return nil
+}
+
+// Encode serializes the target PeerStorage into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (p *PeerStorage) Encode(w io.Writer, pver uint32) error {
+	return wire.WriteVarBytes(w, 0, p.Blob)
+}
+
+// Command returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (p *PeerStorage) Command() uint32 {
+	return CmdPeerStorage
+}
+
+// MaxPayloadLength returns the maximum allowed payload size for a
+// PeerStorage message observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (p *PeerStorage) MaxPayloadLength(uint32) uint32 {
+	// 3 byte length prefix + 65531
+	return 3 + MaxPeerStorageBlob
+}
+
+// Validate performs any necessary sanity checks to ensure all fields present
+// on the PeerStorage are valid.
+//
+// This is part of the lnwire.Message interface.
+func (p *PeerStorage) Validate() error {
+	if len(p.Blob) > MaxPeerStorageBlob {
+		return fmt.Errorf("peer storage blob of %v bytes exceeds "+
+			"the maximum of %v", len(p.Blob), MaxPeerStorageBlob)
+	}
+
+	return nil
+}
+
+// PeerStorageRetrieval returns the blob a peer previously asked us to store
+// via a PeerStorage message. It's sent once the peer reconnects, allowing a
+// peer which has lost its state to recover its backup from us.
+type PeerStorageRetrieval struct {
+	// Blob is the opaque backup most recently stored on behalf of the
+	// recipient.
+	Blob []byte
+}
+
+// A compile time check to ensure PeerStorageRetrieval implements the
+// lnwire.Message interface.
+var _ Message = (*PeerStorageRetrieval)(nil)
+
+// Decode deserializes a serialized PeerStorageRetrieval message stored in the
+// passed io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (p *PeerStorageRetrieval) Decode(r io.Reader, pver uint32) error {
+	blob, err := wire.ReadVarBytes(r, 0, MaxPeerStorageBlob, "blob")
+	if err != nil {
+		return err
+	}
+	p.Blob = blob
+
+	return nil
+}
+
+// Encode serializes the target PeerStorageRetrieval into the passed
+// io.Writer observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (p *PeerStorageRetrieval) Encode(w io.Writer, pver uint32) error {
+	return wire.WriteVarBytes(w, 0, p.Blob)
+}
+
+// Command returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (p *PeerStorageRetrieval) Command() uint32 {
+	return CmdPeerStorageRetrieval
+}
+
+// MaxPayloadLength returns the maximum allowed payload size for a
+// PeerStorageRetrieval message observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (p *PeerStorageRetrieval) MaxPayloadLength(uint32) uint32 {
+	// 3 byte length prefix + 65531
+	return 3 + MaxPeerStorageBlob
+}
+
+// Validate performs any necessary sanity checks to ensure all fields present
+// on the PeerStorageRetrieval are valid.
+//
+// This is part of the lnwire.Message interface.
+func (p *PeerStorageRetrieval) Validate() error {
+	if len(p.Blob) > MaxPeerStorageBlob {
+		return fmt.Errorf("peer storage blob of %v bytes exceeds "+
+			"the maximum of %v", len(p.Blob), MaxPeerStorageBlob)
+	}
+
+	return nil
+}
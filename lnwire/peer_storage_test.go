@@ -0,0 +1,51 @@
+package lnwire
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestPeerStorageEncodeDecode(t *testing.T) {
+	storage := &PeerStorage{
+		Blob: bytes.Repeat([]byte{0xaa}, 300),
+	}
+
+	// Next encode the message into an empty bytes buffer.
+	var b bytes.Buffer
+	if err := storage.Encode(&b, 0); err != nil {
+		t.Fatalf("unable to encode peer storage: %v", err)
+	}
+
+	// Deserialize the encoded message into a new empty struct.
+	storage2 := &PeerStorage{}
+	if err := storage2.Decode(&b, 0); err != nil {
+		t.Fatalf("unable to decode peer storage: %v", err)
+	}
+
+	// Assert equality of the two instances.
+	if !reflect.DeepEqual(storage, storage2) {
+		t.Fatalf("encode/decode peer storage messages don't match "+
+			"%#v vs %#v", storage, storage2)
+	}
+
+	retrieval := &PeerStorageRetrieval{Blob: storage.Blob}
+	b.Reset()
+	if err := retrieval.Encode(&b, 0); err != nil {
+		t.Fatalf("unable to encode retrieval: %v", err)
+	}
+	retrieval2 := &PeerStorageRetrieval{}
+	if err := retrieval2.Decode(&b, 0); err != nil {
+		t.Fatalf("unable to decode retrieval: %v", err)
+	}
+	if !reflect.DeepEqual(retrieval, retrieval2) {
+		t.Fatalf("encode/decode retrieval messages don't match "+
+			"%#v vs %#v", retrieval, retrieval2)
+	}
+
+	// A blob beyond the maximum size must be rejected.
+	storage.Blob = make([]byte, MaxPeerStorageBlob+1)
+	if err := storage.Validate(); err == nil {
+		t.Fatalf("oversized blob should be rejected")
+	}
+}
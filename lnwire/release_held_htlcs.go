@@ -0,0 +1,54 @@
+package lnwire
+
+import "io"
+
+// ReleaseHeldHTLCs is sent by a recipient of asynchronous payments to each
+// peer it has designated to hold HTLCs on its behalf, every time it connects.
+// The message registers the sender as a recipient whose HTLCs should be held
+// while it's offline, and asks the peer to forward any HTLCs it currently
+// holds for the sender, now that it's able to claim them.
+type ReleaseHeldHTLCs struct{}
+
+// A compile time check to ensure ReleaseHeldHTLCs implements the
+// lnwire.Message interface.
+var _ Message = (*ReleaseHeldHTLCs)(nil)
+
+// Decode deserializes a serialized ReleaseHeldHTLCs message stored in the
+// passed io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (r *ReleaseHeldHTLCs) Decode(rd io.Reader, pver uint32) error {
+	return nil
+}
+
+// Encode serializes the target ReleaseHeldHTLCs into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (r *ReleaseHeldHTLCs) Encode(w io.Writer, pver uint32) error {
+	return nil
+}
+
+// Command returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (r *ReleaseHeldHTLCs) Command() uint32 {
+	return CmdReleaseHeldHTLCs
+}
+
+// MaxPayloadLength returns the maximum allowed payload size for a
+// ReleaseHeldHTLCs message observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (r *ReleaseHeldHTLCs) MaxPayloadLength(uint32) uint32 {
+	return 0
+}
+
+// Validate performs any necessary sanity checks to ensure all fields present
+// on the ReleaseHeldHTLCs are valid.
+//
+// This is part of the lnwire.Message interface.
+func (r *ReleaseHeldHTLCs) Validate() error {
+	return nil
+}
@@ -0,0 +1,30 @@
+package lnwire
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/roasbeef/btcd/wire"
+)
+
+func TestReleaseHeldHTLCsEncodeDecode(t *testing.T) {
+	release := &ReleaseHeldHTLCs{}
+
+	// The message carries no payload, so it should encode to nothing.
+	var b bytes.Buffer
+	if _, err := WriteMessage(&b, release, 0, wire.SimNet); err != nil {
+		t.Fatalf("unable to write message: %v", err)
+	}
+	if b.Len() != MessageHeaderSize {
+		t.Fatalf("expected only a message header, got %v bytes",
+			b.Len())
+	}
+
+	_, msg, _, err := ReadMessage(&b, 0, wire.SimNet)
+	if err != nil {
+		t.Fatalf("unable to read message: %v", err)
+	}
+	if _, ok := msg.(*ReleaseHeldHTLCs); !ok {
+		t.Fatalf("expected ReleaseHeldHTLCs, got %T", msg)
+	}
+}
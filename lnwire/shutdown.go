@@ -0,0 +1,74 @@
+package lnwire
+
+import (
+	"io"
+
+	"github.com/roasbeef/btcd/wire"
+)
+
+// Shutdown is sent by either party to signal that it wishes to cooperatively
+// close a channel. Once a party has sent Shutdown, it no longer offers new
+// HTLCs over the channel, and once it has received Shutdown, it no longer
+// accepts them. HTLCs already in flight are left to resolve, and the
+// CloseRequest is only sent by the initiator once the channel holds no
+// pending updates. The recipient replies with its own Shutdown, unless it has
+// already sent one.
+type Shutdown struct {
+	// ChannelPoint serves to identify which channel is to be closed.
+	ChannelPoint wire.OutPoint
+}
+
+// NewShutdown creates a new Shutdown message for the target channel.
+func NewShutdown(chanPoint wire.OutPoint) *Shutdown {
+	return &Shutdown{
+		ChannelPoint: chanPoint,
+	}
+}
+
+// A compile time check to ensure Shutdown implements the lnwire.Message
+// interface.
+var _ Message = (*Shutdown)(nil)
+
+// Decode deserializes a serialized Shutdown message stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (s *Shutdown) Decode(r io.Reader, pver uint32) error {
+	// ChannelPoint (36)
+	return readElements(r, &s.ChannelPoint)
+}
+
+// Encode serializes the target Shutdown into the passed io.Writer observing
+// the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (s *Shutdown) Encode(w io.Writer, pver uint32) error {
+	// ChannelPoint (36)
+	return writeElements(w, s.ChannelPoint)
+}
+
+// Command returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (s *Shutdown) Command() uint32 {
+	return CmdShutdown
+}
+
+// MaxPayloadLength returns the maximum allowed payload size for a Shutdown
+// message observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (s *Shutdown) MaxPayloadLength(uint32) uint32 {
+	// 36
+	return 36
+}
+
+// Validate performs any necessary sanity checks to ensure all fields present
+// on the Shutdown are valid.
+//
+// This is part of the lnwire.Message interface.
+func (s *Shutdown) Validate() error {
+	// We're good!
+	return nil
+}
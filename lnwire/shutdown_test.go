@@ -0,0 +1,29 @@
+package lnwire
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestShutdownEncodeDecode(t *testing.T) {
+	s := NewShutdown(*outpoint1)
+
+	// Next encode the Shutdown message into an empty bytes buffer.
+	var b bytes.Buffer
+	if err := s.Encode(&b, 0); err != nil {
+		t.Fatalf("unable to encode Shutdown: %v", err)
+	}
+
+	// Deserialize the encoded Shutdown message into a new empty struct.
+	s2 := &Shutdown{}
+	if err := s2.Decode(&b, 0); err != nil {
+		t.Fatalf("unable to decode Shutdown: %v", err)
+	}
+
+	// Assert equality of the two instances.
+	if !reflect.DeepEqual(s, s2) {
+		t.Fatalf("encode/decode error messages don't match %#v vs %#v",
+			s, s2)
+	}
+}
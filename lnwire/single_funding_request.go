@@ -79,8 +79,17 @@ type SingleFundingRequest struct {
 
 	// ConfirmationDepth is the number of confirmations that the initiator
 	// of a funding workflow is requesting be required before the channel
-	// is considered fully open.
+	// is considered fully open. A depth of zero requests a zero-conf
+	// channel, which is usable as soon as the funding transaction is
+	// broadcast.
 	ConfirmationDepth uint32
+
+	// ChannelReserve is the minimum balance the initiator proposes each
+	// side of the channel must maintain. HTLCs which would push the
+	// balance of the party offering them below the reserve are rejected,
+	// ensuring each party always has funds at stake should they broadcast
+	// a revoked state. A value of zero proposes no reserve.
+	ChannelReserve btcutil.Amount
 }
 
 // NewSingleFundingRequest creates, and returns a new empty SingleFundingRequest.
@@ -88,7 +97,7 @@ func NewSingleFundingRequest(chanID uint64, chanType uint8, coinType uint64,
 	fee btcutil.Amount, amt btcutil.Amount, delay uint32, ck,
 	cdp *btcec.PublicKey, deliveryScript PkScript,
 	dustLimit btcutil.Amount, pushSat btcutil.Amount,
-	confDepth uint32) *SingleFundingRequest {
+	confDepth uint32, chanReserve btcutil.Amount) *SingleFundingRequest {
 
 	return &SingleFundingRequest{
 		ChannelID:              chanID,
@@ -103,6 +112,7 @@ func NewSingleFundingRequest(chanID uint64, chanType uint8, coinType uint64,
 		DustLimit:              dustLimit,
 		PushSatoshis:           pushSat,
 		ConfirmationDepth:      confDepth,
+		ChannelReserve:         chanReserve,
 	}
 }
 
@@ -124,7 +134,8 @@ func (c *SingleFundingRequest) Decode(r io.Reader, pver uint32) error {
 		&c.ChannelDerivationPoint,
 		&c.DeliveryPkScript,
 		&c.DustLimit,
-		&c.ConfirmationDepth)
+		&c.ConfirmationDepth,
+		&c.ChannelReserve)
 }
 
 // Encode serializes the target SingleFundingRequest into the passed io.Writer
@@ -145,7 +156,8 @@ func (c *SingleFundingRequest) Encode(w io.Writer, pver uint32) error {
 		c.ChannelDerivationPoint,
 		c.DeliveryPkScript,
 		c.DustLimit,
-		c.ConfirmationDepth)
+		c.ConfirmationDepth,
+		c.ChannelReserve)
 }
 
 // Command returns the uint32 code which uniquely identifies this message as a
@@ -201,6 +213,9 @@ func (c *SingleFundingRequest) MaxPayloadLength(uint32) uint32 {
 	// ConfirmationDepth - 4 bytes
 	length += 4
 
+	// ChannelReserve - 8 bytes
+	length += 8
+
 	return length
 }
 
@@ -247,8 +262,8 @@ func (c *SingleFundingRequest) Validate() error {
 		return fmt.Errorf("DustLimit' should be greater than zero")
 	}
 
-	if c.ConfirmationDepth == 0 {
-		return fmt.Errorf("ConfirmationDepth must be non-zero")
+	if c.ChannelReserve < 0 {
+		return fmt.Errorf("'ChannelReserve' cannot be negative")
 	}
 
 	// We're good!
@@ -54,7 +54,9 @@ type SingleFundingResponse struct {
 
 	// ConfirmationDepth is the number of confirmations that the initiator
 	// of a funding workflow is requesting be required before the channel
-	// is considered fully open.
+	// is considered fully open. A depth of zero requests a zero-conf
+	// channel, which is usable as soon as the funding transaction is
+	// broadcast.
 	ConfirmationDepth uint32
 }
 
@@ -194,10 +196,6 @@ func (c *SingleFundingResponse) Validate() error {
 			"zero.")
 	}
 
-	if c.ConfirmationDepth == 0 {
-		return fmt.Errorf("ConfirmationDepth must be non-zero")
-	}
-
 	// We're good!
 	return nil
 }
@@ -0,0 +1,87 @@
+package lnwire
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// SpliceAccept is sent in response to a SpliceRequest once the responder has
+// assembled the splice transaction. It carries the responder's signature for
+// the requester's version of the commitment transaction spending the new
+// funding output created by the splice transaction.
+type SpliceAccept struct {
+	// ChannelPoint identifies the channel being spliced.
+	ChannelPoint wire.OutPoint
+
+	// CommitSignature is the responder's signature for the requester's
+	// version of the commitment transaction spending the new funding
+	// output.
+	CommitSignature *btcec.Signature
+}
+
+// NewSpliceAccept creates, and returns a new SpliceAccept.
+func NewSpliceAccept(cp wire.OutPoint, commitSig *btcec.Signature) *SpliceAccept {
+	return &SpliceAccept{
+		ChannelPoint:    cp,
+		CommitSignature: commitSig,
+	}
+}
+
+// A compile time check to ensure SpliceAccept implements the lnwire.Message
+// interface.
+var _ Message = (*SpliceAccept)(nil)
+
+// Decode deserializes the serialized SpliceAccept stored in the passed
+// io.Reader into the target SpliceAccept using the deserialization rules
+// defined by the passed protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (c *SpliceAccept) Decode(r io.Reader, pver uint32) error {
+	return readElements(r,
+		&c.ChannelPoint,
+		&c.CommitSignature)
+}
+
+// Encode serializes the target SpliceAccept into the passed io.Writer
+// implementation. Serialization will observe the rules defined by the passed
+// protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (c *SpliceAccept) Encode(w io.Writer, pver uint32) error {
+	return writeElements(w,
+		c.ChannelPoint,
+		c.CommitSignature)
+}
+
+// Command returns the uint32 code which uniquely identifies this message as a
+// SpliceAccept on the wire.
+//
+// This is part of the lnwire.Message interface.
+func (c *SpliceAccept) Command() uint32 {
+	return CmdSpliceAccept
+}
+
+// MaxPayloadLength returns the maximum allowed payload length for a
+// SpliceAccept. This is calculated by summing the max length of all the
+// fields within a SpliceAccept: 36 + 73.
+//
+// This is part of the lnwire.Message interface.
+func (c *SpliceAccept) MaxPayloadLength(uint32) uint32 {
+	return 109
+}
+
+// Validate examines each populated field within the SpliceAccept for field
+// sanity.
+//
+// This is part of the lnwire.Message interface.
+func (c *SpliceAccept) Validate() error {
+	if c.CommitSignature == nil {
+		return fmt.Errorf("commitment signature must be non-nil")
+	}
+
+	// We're good!
+	return nil
+}
@@ -0,0 +1,30 @@
+package lnwire
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestSpliceAcceptWire(t *testing.T) {
+	// First create a new SA message.
+	sa := NewSpliceAccept(*outpoint1, commitSig1)
+
+	// Next encode the SA message into an empty bytes buffer.
+	var b bytes.Buffer
+	if err := sa.Encode(&b, 0); err != nil {
+		t.Fatalf("unable to encode SpliceAccept: %v", err)
+	}
+
+	// Deserialize the encoded SA message into a new empty struct.
+	sa2 := &SpliceAccept{}
+	if err := sa2.Decode(&b, 0); err != nil {
+		t.Fatalf("unable to decode SpliceAccept: %v", err)
+	}
+
+	// Assert equality of the two instances.
+	if !reflect.DeepEqual(sa, sa2) {
+		t.Fatalf("encode/decode error messages don't match %#v vs %#v",
+			sa, sa2)
+	}
+}
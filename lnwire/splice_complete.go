@@ -0,0 +1,89 @@
+package lnwire
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// SpliceComplete is the final message of the splice workflow, sent by the
+// responder once it has completed, and broadcast the splice transaction. It
+// carries the responder's half of the 2-of-2 multi-sig spending the current
+// funding output, allowing the requester to assemble the fully signed splice
+// transaction as well.
+type SpliceComplete struct {
+	// ChannelPoint identifies the channel being spliced.
+	ChannelPoint wire.OutPoint
+
+	// FundingSignature is the responder's signature for the input of the
+	// splice transaction which spends the current funding output.
+	FundingSignature *btcec.Signature
+}
+
+// NewSpliceComplete creates, and returns a new SpliceComplete.
+func NewSpliceComplete(cp wire.OutPoint,
+	fundingSig *btcec.Signature) *SpliceComplete {
+
+	return &SpliceComplete{
+		ChannelPoint:     cp,
+		FundingSignature: fundingSig,
+	}
+}
+
+// A compile time check to ensure SpliceComplete implements the lnwire.Message
+// interface.
+var _ Message = (*SpliceComplete)(nil)
+
+// Decode deserializes the serialized SpliceComplete stored in the passed
+// io.Reader into the target SpliceComplete using the deserialization rules
+// defined by the passed protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (c *SpliceComplete) Decode(r io.Reader, pver uint32) error {
+	return readElements(r,
+		&c.ChannelPoint,
+		&c.FundingSignature)
+}
+
+// Encode serializes the target SpliceComplete into the passed io.Writer
+// implementation. Serialization will observe the rules defined by the passed
+// protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (c *SpliceComplete) Encode(w io.Writer, pver uint32) error {
+	return writeElements(w,
+		c.ChannelPoint,
+		c.FundingSignature)
+}
+
+// Command returns the uint32 code which uniquely identifies this message as a
+// SpliceComplete on the wire.
+//
+// This is part of the lnwire.Message interface.
+func (c *SpliceComplete) Command() uint32 {
+	return CmdSpliceComplete
+}
+
+// MaxPayloadLength returns the maximum allowed payload length for a
+// SpliceComplete. This is calculated by summing the max length of all the
+// fields within a SpliceComplete: 36 + 73.
+//
+// This is part of the lnwire.Message interface.
+func (c *SpliceComplete) MaxPayloadLength(uint32) uint32 {
+	return 109
+}
+
+// Validate examines each populated field within the SpliceComplete for field
+// sanity.
+//
+// This is part of the lnwire.Message interface.
+func (c *SpliceComplete) Validate() error {
+	if c.FundingSignature == nil {
+		return fmt.Errorf("funding signature must be non-nil")
+	}
+
+	// We're good!
+	return nil
+}
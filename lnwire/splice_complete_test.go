@@ -0,0 +1,30 @@
+package lnwire
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestSpliceCompleteWire(t *testing.T) {
+	// First create a new SC message.
+	sc := NewSpliceComplete(*outpoint1, commitSig2)
+
+	// Next encode the SC message into an empty bytes buffer.
+	var b bytes.Buffer
+	if err := sc.Encode(&b, 0); err != nil {
+		t.Fatalf("unable to encode SpliceComplete: %v", err)
+	}
+
+	// Deserialize the encoded SC message into a new empty struct.
+	sc2 := &SpliceComplete{}
+	if err := sc2.Decode(&b, 0); err != nil {
+		t.Fatalf("unable to decode SpliceComplete: %v", err)
+	}
+
+	// Assert equality of the two instances.
+	if !reflect.DeepEqual(sc, sc2) {
+		t.Fatalf("encode/decode error messages don't match %#v vs %#v",
+			sc, sc2)
+	}
+}
@@ -0,0 +1,120 @@
+package lnwire
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+// SpliceRequest is sent by either party to an active channel in order to
+// propose splicing additional funds into the channel. The splice transaction
+// spends the channel's current funding output along with the inputs of the
+// requester, creating a new funding output whose value is increased by the
+// spliced amount, which is credited to the requester's balance. Any change
+// of the requester's inputs is returned via its change outputs, and the
+// requester pays the fee of the splice transaction in its entirety.
+//
+// NOTE: As the splice transaction is assembled observing BIP 69, both sides
+// are able to arrive at an identical transaction, so only signatures need to
+// be exchanged for the remainder of the workflow.
+type SpliceRequest struct {
+	// ChannelPoint identifies the channel into which funds are to be
+	// spliced.
+	ChannelPoint wire.OutPoint
+
+	// Amount is the number of satoshis the requester would like to
+	// splice into the channel.
+	Amount btcutil.Amount
+
+	// Inputs are the outpoints the requester spends within the splice
+	// transaction in order to fund the spliced amount.
+	Inputs []*wire.TxIn
+
+	// ChangeOutputs are the outputs returning to the requester any value
+	// of their inputs in excess of the spliced amount, and the fee.
+	ChangeOutputs []*wire.TxOut
+}
+
+// NewSpliceRequest creates, and returns a new SpliceRequest.
+func NewSpliceRequest(cp wire.OutPoint, amt btcutil.Amount,
+	inputs []*wire.TxIn, changeOutputs []*wire.TxOut) *SpliceRequest {
+
+	return &SpliceRequest{
+		ChannelPoint:  cp,
+		Amount:        amt,
+		Inputs:        inputs,
+		ChangeOutputs: changeOutputs,
+	}
+}
+
+// A compile time check to ensure SpliceRequest implements the lnwire.Message
+// interface.
+var _ Message = (*SpliceRequest)(nil)
+
+// Decode deserializes the serialized SpliceRequest stored in the passed
+// io.Reader into the target SpliceRequest using the deserialization rules
+// defined by the passed protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (c *SpliceRequest) Decode(r io.Reader, pver uint32) error {
+	return readElements(r,
+		&c.ChannelPoint,
+		&c.Amount,
+		&c.Inputs,
+		&c.ChangeOutputs)
+}
+
+// Encode serializes the target SpliceRequest into the passed io.Writer
+// implementation. Serialization will observe the rules defined by the passed
+// protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (c *SpliceRequest) Encode(w io.Writer, pver uint32) error {
+	return writeElements(w,
+		c.ChannelPoint,
+		c.Amount,
+		c.Inputs,
+		c.ChangeOutputs)
+}
+
+// Command returns the uint32 code which uniquely identifies this message as a
+// SpliceRequest on the wire.
+//
+// This is part of the lnwire.Message interface.
+func (c *SpliceRequest) Command() uint32 {
+	return CmdSpliceRequest
+}
+
+// MaxPayloadLength returns the maximum allowed payload length for a
+// SpliceRequest. This is calculated by summing the max length of all the
+// fields within a SpliceRequest: 36 + 8, plus the inputs and change outputs.
+//
+// This is part of the lnwire.Message interface.
+func (c *SpliceRequest) MaxPayloadLength(uint32) uint32 {
+	return 44 + maxTxInsLength + maxTxOutsLength
+}
+
+// Validate examines each populated field within the SpliceRequest for field
+// sanity.
+//
+// This is part of the lnwire.Message interface.
+func (c *SpliceRequest) Validate() error {
+	if c.Amount <= 0 {
+		return fmt.Errorf("'Amount' must be positive")
+	}
+
+	// The requester must spend at least one input in order to fund the
+	// spliced amount, and may only return change to the supported script
+	// templates.
+	if len(c.Inputs) == 0 {
+		return fmt.Errorf("splice inputs must be non-empty")
+	}
+	if err := validateChangeOutputs(c.ChangeOutputs); err != nil {
+		return err
+	}
+
+	// We're good!
+	return nil
+}
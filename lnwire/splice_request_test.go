@@ -0,0 +1,30 @@
+package lnwire
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestSpliceRequestWire(t *testing.T) {
+	// First create a new SR message.
+	sr := NewSpliceRequest(*outpoint1, 3e8, fundingInputs, changeOutputs)
+
+	// Next encode the SR message into an empty bytes buffer.
+	var b bytes.Buffer
+	if err := sr.Encode(&b, 0); err != nil {
+		t.Fatalf("unable to encode SpliceRequest: %v", err)
+	}
+
+	// Deserialize the encoded SR message into a new empty struct.
+	sr2 := &SpliceRequest{}
+	if err := sr2.Decode(&b, 0); err != nil {
+		t.Fatalf("unable to decode SpliceRequest: %v", err)
+	}
+
+	// Assert equality of the two instances.
+	if !reflect.DeepEqual(sr, sr2) {
+		t.Fatalf("encode/decode error messages don't match %#v vs %#v",
+			sr, sr2)
+	}
+}
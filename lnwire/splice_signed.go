@@ -0,0 +1,114 @@
+package lnwire
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// SpliceSigned is sent by the requester of a splice once it has verified the
+// responder's signature for its new commitment transaction. It carries the
+// requester's signature for the responder's new commitment transaction, its
+// half of the 2-of-2 multi-sig spending the current funding output within
+// the splice transaction, and the scripts spending each of its inputs.
+type SpliceSigned struct {
+	// ChannelPoint identifies the channel being spliced.
+	ChannelPoint wire.OutPoint
+
+	// CommitSignature is the requester's signature for the responder's
+	// version of the commitment transaction spending the new funding
+	// output.
+	CommitSignature *btcec.Signature
+
+	// FundingSignature is the requester's signature for the input of the
+	// splice transaction which spends the current funding output.
+	FundingSignature *btcec.Signature
+
+	// FundingInputScripts are the scripts spending each of the
+	// requester's inputs to the splice transaction, in the order in which
+	// they appear within the canonically sorted splice transaction.
+	FundingInputScripts []*FundingInputScript
+}
+
+// NewSpliceSigned creates, and returns a new SpliceSigned.
+func NewSpliceSigned(cp wire.OutPoint, commitSig,
+	fundingSig *btcec.Signature,
+	inputScripts []*FundingInputScript) *SpliceSigned {
+
+	return &SpliceSigned{
+		ChannelPoint:        cp,
+		CommitSignature:     commitSig,
+		FundingSignature:    fundingSig,
+		FundingInputScripts: inputScripts,
+	}
+}
+
+// A compile time check to ensure SpliceSigned implements the lnwire.Message
+// interface.
+var _ Message = (*SpliceSigned)(nil)
+
+// Decode deserializes the serialized SpliceSigned stored in the passed
+// io.Reader into the target SpliceSigned using the deserialization rules
+// defined by the passed protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (c *SpliceSigned) Decode(r io.Reader, pver uint32) error {
+	return readElements(r,
+		&c.ChannelPoint,
+		&c.CommitSignature,
+		&c.FundingSignature,
+		&c.FundingInputScripts)
+}
+
+// Encode serializes the target SpliceSigned into the passed io.Writer
+// implementation. Serialization will observe the rules defined by the passed
+// protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (c *SpliceSigned) Encode(w io.Writer, pver uint32) error {
+	return writeElements(w,
+		c.ChannelPoint,
+		c.CommitSignature,
+		c.FundingSignature,
+		c.FundingInputScripts)
+}
+
+// Command returns the uint32 code which uniquely identifies this message as a
+// SpliceSigned on the wire.
+//
+// This is part of the lnwire.Message interface.
+func (c *SpliceSigned) Command() uint32 {
+	return CmdSpliceSigned
+}
+
+// MaxPayloadLength returns the maximum allowed payload length for a
+// SpliceSigned. This is calculated by summing the max length of all the
+// fields within a SpliceSigned: 36 + 73 + 73, plus the input scripts.
+//
+// This is part of the lnwire.Message interface.
+func (c *SpliceSigned) MaxPayloadLength(uint32) uint32 {
+	return 182 + maxInputScriptsLength
+}
+
+// Validate examines each populated field within the SpliceSigned for field
+// sanity.
+//
+// This is part of the lnwire.Message interface.
+func (c *SpliceSigned) Validate() error {
+	if c.CommitSignature == nil {
+		return fmt.Errorf("commitment signature must be non-nil")
+	}
+
+	if c.FundingSignature == nil {
+		return fmt.Errorf("funding signature must be non-nil")
+	}
+
+	if len(c.FundingInputScripts) == 0 {
+		return fmt.Errorf("funding input scripts must be non-empty")
+	}
+
+	// We're good!
+	return nil
+}
@@ -0,0 +1,31 @@
+package lnwire
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestSpliceSignedWire(t *testing.T) {
+	// First create a new SS message.
+	ss := NewSpliceSigned(*outpoint1, commitSig1, commitSig2,
+		fundingInputScripts)
+
+	// Next encode the SS message into an empty bytes buffer.
+	var b bytes.Buffer
+	if err := ss.Encode(&b, 0); err != nil {
+		t.Fatalf("unable to encode SpliceSigned: %v", err)
+	}
+
+	// Deserialize the encoded SS message into a new empty struct.
+	ss2 := &SpliceSigned{}
+	if err := ss2.Decode(&b, 0); err != nil {
+		t.Fatalf("unable to decode SpliceSigned: %v", err)
+	}
+
+	// Assert equality of the two instances.
+	if !reflect.DeepEqual(ss, ss2) {
+		t.Fatalf("encode/decode error messages don't match %#v vs %#v",
+			ss, ss2)
+	}
+}
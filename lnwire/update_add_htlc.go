@@ -54,6 +54,14 @@ type UpdateAddHTLC struct {
 	// should strip off a layer of encryption, exposing the next hop to be
 	// used in the subsequent UpdateAddHTLC message.
 	OnionBlob [OnionPacketSize]byte
+
+	// Endorsed signals that the sender vouches for this HTLC, as it
+	// arrived endorsed from a peer with a good reputation, or was
+	// initiated by the sender itself. Endorsed HTLCs may use the portion
+	// of a channel's slots and liquidity reserved to protect against
+	// channel jamming. The signal is encoded as an optional trailing
+	// byte, written only if set, so it's understood by older nodes.
+	Endorsed bool
 }
 
 // NewUpdateAddHTLC returns a new empty UpdateAddHTLC message.
@@ -76,7 +84,8 @@ func (c *UpdateAddHTLC) Decode(r io.Reader, pver uint32) error {
 	// Amount(8)
 	// PaymentHash(32)
 	// OnionBlob(1254)
-	return readElements(r,
+	// Endorsed(1, optional)
+	err := readElements(r,
 		&c.ChannelPoint,
 		&c.ID,
 		&c.Expiry,
@@ -84,6 +93,23 @@ func (c *UpdateAddHTLC) Decode(r io.Reader, pver uint32) error {
 		c.PaymentHash[:],
 		c.OnionBlob[:],
 	)
+	if err != nil {
+		return err
+	}
+
+	// The endorsement signal is omitted by nodes which don't set it, so
+	// its absence simply denotes an unendorsed HTLC.
+	var endorsed uint8
+	switch err := readElement(r, &endorsed); {
+	case err == io.EOF:
+		c.Endorsed = false
+	case err != nil:
+		return err
+	default:
+		c.Endorsed = endorsed != 0
+	}
+
+	return nil
 }
 
 // Encode serializes the target UpdateAddHTLC into the passed io.Writer observing
@@ -91,7 +117,7 @@ func (c *UpdateAddHTLC) Decode(r io.Reader, pver uint32) error {
 //
 // This is part of the lnwire.Message interface.
 func (c *UpdateAddHTLC) Encode(w io.Writer, pver uint32) error {
-	return writeElements(w,
+	err := writeElements(w,
 		c.ChannelPoint,
 		c.ID,
 		c.Expiry,
@@ -99,6 +125,14 @@ func (c *UpdateAddHTLC) Encode(w io.Writer, pver uint32) error {
 		c.PaymentHash[:],
 		c.OnionBlob[:],
 	)
+	if err != nil {
+		return err
+	}
+
+	if !c.Endorsed {
+		return nil
+	}
+	return writeElement(w, uint8(1))
 }
 
 // Command returns the integer uniquely identifying this message type on the
@@ -114,8 +148,8 @@ func (c *UpdateAddHTLC) Command() uint32 {
 //
 // This is part of the lnwire.Message interface.
 func (c *UpdateAddHTLC) MaxPayloadLength(uint32) uint32 {
-	// 1342
-	return 36 + 8 + 4 + 8 + 32 + 1254
+	// 1343
+	return 36 + 8 + 4 + 8 + 32 + 1254 + 1
 }
 
 // Validate performs any necessary sanity checks to ensure all fields present
@@ -36,4 +36,23 @@ func TestUpdateAddHTLCEncodeDecode(t *testing.T) {
 		t.Fatalf("encode/decode error messages don't match %#v vs %#v",
 			addReq, addReq2)
 	}
+
+	// An endorsed HTLC should carry its signal across the trailing byte.
+	addReq.Endorsed = true
+	b.Reset()
+	if err := addReq.Encode(&b, 0); err != nil {
+		t.Fatalf("unable to encode HTLCAddRequest: %v", err)
+	}
+	if uint32(b.Len()) != addReq.MaxPayloadLength(0) {
+		t.Fatalf("expected endorsed payload of %v bytes, got %v",
+			addReq.MaxPayloadLength(0), b.Len())
+	}
+	addReq3 := &UpdateAddHTLC{}
+	if err := addReq3.Decode(&b, 0); err != nil {
+		t.Fatalf("unable to decode HTLCAddRequest: %v", err)
+	}
+	if !reflect.DeepEqual(addReq, addReq3) {
+		t.Fatalf("encode/decode error messages don't match %#v vs %#v",
+			addReq, addReq3)
+	}
 }
@@ -41,6 +41,27 @@ const (
 	// IncorrectValue indicates that the HTLC ultimately extended to the
 	// destination did not match the value that was expected.
 	IncorrectValue FailCode = 5
+
+	// AmountBelowMinimum indicates that the HTLC was smaller than the
+	// minimum HTLC amount accepted by a node within the route.
+	AmountBelowMinimum FailCode = 6
+
+	// DustExposureExceeded indicates that accepting the HTLC would have
+	// pushed the total value of dust HTLCs within a channel along the
+	// route beyond its permitted maximum.
+	DustExposureExceeded FailCode = 7
+
+	// HashExposureExceeded indicates that the HTLC was correlated with
+	// others sharing its payment hash, either pending within a channel
+	// along the route beyond its permitted maximum exposure to a single
+	// payment hash, or already being forwarded by a node along the route.
+	HashExposureExceeded FailCode = 8
+
+	// ChannelJammed indicates that the HTLC was neither endorsed by a
+	// peer with a good reputation, nor fit within the portion of the
+	// outgoing channel's slots and liquidity left open to unendorsed
+	// HTLCs.
+	ChannelJammed FailCode = 9
 )
 
 // String returns a human-readable version of the FailCode type.
@@ -66,6 +87,21 @@ func (c FailCode) String() string {
 	case IncorrectValue:
 		return "IncorrectValue: htlc value was wrong"
 
+	case AmountBelowMinimum:
+		return "AmountBelowMinimum: htlc value below minimum"
+
+	case DustExposureExceeded:
+		return "DustExposureExceeded: htlc would exceed max dust " +
+			"exposure"
+
+	case HashExposureExceeded:
+		return "HashExposureExceeded: htlc would exceed max exposure " +
+			"to its payment hash"
+
+	case ChannelJammed:
+		return "ChannelJammed: htlc would exceed the general " +
+			"resources of the outgoing channel"
+
 	default:
 		return "unknown reason"
 	}
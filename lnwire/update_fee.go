@@ -0,0 +1,80 @@
+package lnwire
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+// UpdateFee is sent by the initiator of a channel in order to propose a new
+// fee for the commitment transactions of both parties. Like an HTLC update,
+// the new fee only takes effect once it has been committed to, and the prior
+// states paying the old fee have been revoked.
+type UpdateFee struct {
+	// ChannelPoint is the particular active channel that this UpdateFee
+	// is bound to.
+	ChannelPoint wire.OutPoint
+
+	// Fee is the new absolute fee to be paid by the commitment
+	// transactions, deducted from the balance of the initiator.
+	Fee btcutil.Amount
+}
+
+// A compile time check to ensure UpdateFee implements the lnwire.Message
+// interface.
+var _ Message = (*UpdateFee)(nil)
+
+// Decode deserializes a serialized UpdateFee message stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (c *UpdateFee) Decode(r io.Reader, pver uint32) error {
+	// ChannelPoint(36)
+	// Fee(8)
+	return readElements(r,
+		&c.ChannelPoint,
+		&c.Fee,
+	)
+}
+
+// Encode serializes the target UpdateFee into the passed io.Writer observing
+// the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (c *UpdateFee) Encode(w io.Writer, pver uint32) error {
+	return writeElements(w,
+		c.ChannelPoint,
+		c.Fee,
+	)
+}
+
+// Command returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (c *UpdateFee) Command() uint32 {
+	return CmdUpdateFee
+}
+
+// MaxPayloadLength returns the maximum allowed payload size for a UpdateFee
+// complete message observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (c *UpdateFee) MaxPayloadLength(uint32) uint32 {
+	// 36 + 8
+	return 44
+}
+
+// Validate performs any necessary sanity checks to ensure all fields present
+// on the UpdateFee are valid.
+//
+// This is part of the lnwire.Message interface.
+func (c *UpdateFee) Validate() error {
+	if c.Fee < 0 {
+		return fmt.Errorf("commitment fee cannot be negative: %v", c.Fee)
+	}
+
+	return nil
+}
@@ -0,0 +1,33 @@
+package lnwire
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestUpdateFeeEncodeDecode(t *testing.T) {
+	// First create a new UpdateFee message.
+	feeMsg := &UpdateFee{
+		ChannelPoint: *outpoint1,
+		Fee:          7500,
+	}
+
+	// Next encode the UpdateFee message into an empty bytes buffer.
+	var b bytes.Buffer
+	if err := feeMsg.Encode(&b, 0); err != nil {
+		t.Fatalf("unable to encode UpdateFee: %v", err)
+	}
+
+	// Deserialize the encoded UpdateFee message into a new empty struct.
+	feeMsg2 := &UpdateFee{}
+	if err := feeMsg2.Decode(&b, 0); err != nil {
+		t.Fatalf("unable to decode UpdateFee: %v", err)
+	}
+
+	// Assert equality of the two instances.
+	if !reflect.DeepEqual(feeMsg, feeMsg2) {
+		t.Fatalf("encode/decode error messages don't match %#v vs %#v",
+			feeMsg, feeMsg2)
+	}
+}
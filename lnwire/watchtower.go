@@ -0,0 +1,424 @@
+package lnwire
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+const (
+	// BreachHintSize is the size of the hint identifying the breach
+	// transaction a StateUpdate's blob sweeps.
+	BreachHintSize = 16
+
+	// MaxJusticeBlobSize is the largest encrypted blob a StateUpdate may
+	// carry.
+	MaxJusticeBlobSize = 512
+)
+
+// TowerCode is the result of a request made to a watchtower.
+type TowerCode uint8
+
+const (
+	// TowerCodeOK indicates the request was accepted.
+	TowerCodeOK TowerCode = 0
+
+	// TowerCodeTemporaryFailure indicates the tower was unable to process
+	// the request due to an internal error. The request may be retried.
+	TowerCodeTemporaryFailure TowerCode = 1
+
+	// TowerCodeSessionLimit indicates the client already holds as many
+	// sessions as the tower allows.
+	TowerCodeSessionLimit TowerCode = 2
+
+	// TowerCodeUnknownSession indicates the session referred to by a
+	// StateUpdate doesn't exist, or isn't owned by the client.
+	TowerCodeUnknownSession TowerCode = 3
+
+	// TowerCodeSessionFull indicates the session has no updates left.
+	TowerCodeSessionFull TowerCode = 4
+
+	// TowerCodeSeqNumOutOfOrder indicates the sequence number of a
+	// StateUpdate isn't the one following the last update applied.
+	TowerCodeSeqNumOutOfOrder TowerCode = 5
+
+	// TowerCodeStorageFull indicates the tower has run out of storage.
+	TowerCodeStorageFull TowerCode = 6
+
+	// TowerCodeInvalidUpdate indicates the StateUpdate was malformed.
+	TowerCodeInvalidUpdate TowerCode = 7
+)
+
+// String returns a human readable description of the code.
+func (c TowerCode) String() string {
+	switch c {
+	case TowerCodeOK:
+		return "OK"
+	case TowerCodeTemporaryFailure:
+		return "TemporaryFailure"
+	case TowerCodeSessionLimit:
+		return "SessionLimit"
+	case TowerCodeUnknownSession:
+		return "UnknownSession"
+	case TowerCodeSessionFull:
+		return "SessionFull"
+	case TowerCodeSeqNumOutOfOrder:
+		return "SeqNumOutOfOrder"
+	case TowerCodeStorageFull:
+		return "StorageFull"
+	case TowerCodeInvalidUpdate:
+		return "InvalidUpdate"
+	default:
+		return fmt.Sprintf("TowerCode(%d)", uint8(c))
+	}
+}
+
+// CreateSession is sent by a watchtower client to a peer acting as a
+// watchtower in order to open a session, within which the client may upload
+// a bounded number of encrypted justice kits.
+type CreateSession struct {
+	// MaxUpdates is the number of updates the client wishes the session
+	// to hold. The tower may grant fewer.
+	MaxUpdates uint16
+
+	// SweepFeeRate is the fee rate, in satoshis per kilo-weight, of the
+	// justice transactions the client will sign within the session.
+	SweepFeeRate btcutil.Amount
+}
+
+// A compile time check to ensure CreateSession implements the
+// lnwire.Message interface.
+var _ Message = (*CreateSession)(nil)
+
+// Decode deserializes a serialized CreateSession message stored in the
+// passed io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (c *CreateSession) Decode(r io.Reader, pver uint32) error {
+	return readElements(r,
+		&c.MaxUpdates,
+		&c.SweepFeeRate,
+	)
+}
+
+// Encode serializes the target CreateSession into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (c *CreateSession) Encode(w io.Writer, pver uint32) error {
+	return writeElements(w,
+		c.MaxUpdates,
+		c.SweepFeeRate,
+	)
+}
+
+// Command returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (c *CreateSession) Command() uint32 {
+	return CmdCreateSession
+}
+
+// MaxPayloadLength returns the maximum allowed payload size for a
+// CreateSession message observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (c *CreateSession) MaxPayloadLength(uint32) uint32 {
+	// MaxUpdates - 2 bytes
+	// SweepFeeRate - 8 bytes
+	return 2 + 8
+}
+
+// Validate performs any necessary sanity checks to ensure all fields present
+// on the CreateSession are valid.
+//
+// This is part of the lnwire.Message interface.
+func (c *CreateSession) Validate() error {
+	if c.MaxUpdates == 0 {
+		return fmt.Errorf("session must allow at least one update")
+	}
+	if c.SweepFeeRate <= 0 {
+		return fmt.Errorf("sweep fee rate must be positive")
+	}
+
+	return nil
+}
+
+// CreateSessionReply is sent by a watchtower in response to a CreateSession
+// message. If the session was created, the reply carries the terms the
+// client must sign its justice transactions under.
+type CreateSessionReply struct {
+	// Code is the result of the request. The remaining fields are only
+	// meaningful if it's TowerCodeOK.
+	Code TowerCode
+
+	// SessionID identifies the session within subsequent StateUpdate
+	// messages.
+	SessionID uint64
+
+	// MaxUpdates is the number of updates granted by the tower.
+	MaxUpdates uint16
+
+	// RewardRate is the share of the swept funds, in millionths, the
+	// tower is paid by each justice transaction.
+	RewardRate uint32
+
+	// RewardPkScript is the script the tower's reward is paid to.
+	RewardPkScript []byte
+}
+
+// A compile time check to ensure CreateSessionReply implements the
+// lnwire.Message interface.
+var _ Message = (*CreateSessionReply)(nil)
+
+// Decode deserializes a serialized CreateSessionReply message stored in the
+// passed io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (c *CreateSessionReply) Decode(r io.Reader, pver uint32) error {
+	var code uint8
+	err := readElements(r,
+		&code,
+		&c.SessionID,
+		&c.MaxUpdates,
+		&c.RewardRate,
+	)
+	if err != nil {
+		return err
+	}
+	c.Code = TowerCode(code)
+
+	c.RewardPkScript, err = wire.ReadVarBytes(r, 0, maxTxOutPkScriptSize,
+		"reward script")
+	return err
+}
+
+// Encode serializes the target CreateSessionReply into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (c *CreateSessionReply) Encode(w io.Writer, pver uint32) error {
+	err := writeElements(w,
+		uint8(c.Code),
+		c.SessionID,
+		c.MaxUpdates,
+		c.RewardRate,
+	)
+	if err != nil {
+		return err
+	}
+
+	return wire.WriteVarBytes(w, 0, c.RewardPkScript)
+}
+
+// Command returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (c *CreateSessionReply) Command() uint32 {
+	return CmdCreateSessionReply
+}
+
+// MaxPayloadLength returns the maximum allowed payload size for a
+// CreateSessionReply message observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (c *CreateSessionReply) MaxPayloadLength(uint32) uint32 {
+	// Code - 1 byte
+	// SessionID - 8 bytes
+	// MaxUpdates - 2 bytes
+	// RewardRate - 4 bytes
+	// RewardPkScript - 1 byte length prefix + 34 bytes
+	return 1 + 8 + 2 + 4 + 1 + maxTxOutPkScriptSize
+}
+
+// Validate performs any necessary sanity checks to ensure all fields present
+// on the CreateSessionReply are valid.
+//
+// This is part of the lnwire.Message interface.
+func (c *CreateSessionReply) Validate() error {
+	if len(c.RewardPkScript) > maxTxOutPkScriptSize {
+		return fmt.Errorf("reward script of %v bytes exceeds the "+
+			"maximum of %v", len(c.RewardPkScript),
+			maxTxOutPkScriptSize)
+	}
+
+	return nil
+}
+
+// StateUpdate is sent by a watchtower client within one of its sessions each
+// time one of its channels is revoked. It carries the justice kit sweeping
+// the revoked commitment transaction, encrypted under the transaction's txid,
+// along with a hint allowing the tower to recognize the transaction should
+// it appear on-chain.
+type StateUpdate struct {
+	// SessionID identifies the session the update belongs to.
+	SessionID uint64
+
+	// SeqNum is the sequence number of the update within the session,
+	// starting from one.
+	SeqNum uint16
+
+	// Hint is the prefix of the txid of the revoked commitment
+	// transaction.
+	Hint [BreachHintSize]byte
+
+	// EncryptedBlob is the encrypted justice kit.
+	EncryptedBlob []byte
+}
+
+// A compile time check to ensure StateUpdate implements the lnwire.Message
+// interface.
+var _ Message = (*StateUpdate)(nil)
+
+// Decode deserializes a serialized StateUpdate message stored in the passed
+// io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (s *StateUpdate) Decode(r io.Reader, pver uint32) error {
+	err := readElements(r,
+		&s.SessionID,
+		&s.SeqNum,
+		s.Hint[:],
+	)
+	if err != nil {
+		return err
+	}
+
+	s.EncryptedBlob, err = wire.ReadVarBytes(r, 0, MaxJusticeBlobSize,
+		"blob")
+	return err
+}
+
+// Encode serializes the target StateUpdate into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (s *StateUpdate) Encode(w io.Writer, pver uint32) error {
+	err := writeElements(w,
+		s.SessionID,
+		s.SeqNum,
+		s.Hint[:],
+	)
+	if err != nil {
+		return err
+	}
+
+	return wire.WriteVarBytes(w, 0, s.EncryptedBlob)
+}
+
+// Command returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (s *StateUpdate) Command() uint32 {
+	return CmdStateUpdate
+}
+
+// MaxPayloadLength returns the maximum allowed payload size for a
+// StateUpdate message observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (s *StateUpdate) MaxPayloadLength(uint32) uint32 {
+	// SessionID - 8 bytes
+	// SeqNum - 2 bytes
+	// Hint - 16 bytes
+	// EncryptedBlob - 3 byte length prefix + 512 bytes
+	return 8 + 2 + BreachHintSize + 3 + MaxJusticeBlobSize
+}
+
+// Validate performs any necessary sanity checks to ensure all fields present
+// on the StateUpdate are valid.
+//
+// This is part of the lnwire.Message interface.
+func (s *StateUpdate) Validate() error {
+	if s.SeqNum == 0 {
+		return fmt.Errorf("sequence numbers start from one")
+	}
+	if len(s.EncryptedBlob) > MaxJusticeBlobSize {
+		return fmt.Errorf("justice blob of %v bytes exceeds the "+
+			"maximum of %v", len(s.EncryptedBlob),
+			MaxJusticeBlobSize)
+	}
+
+	return nil
+}
+
+// StateUpdateReply is sent by a watchtower in response to a StateUpdate
+// message.
+type StateUpdateReply struct {
+	// SessionID identifies the session the update was sent within.
+	SessionID uint64
+
+	// Code is the result of the update.
+	Code TowerCode
+
+	// LastApplied is the sequence number of the last update applied to
+	// the session, allowing a client to resume after losing a reply.
+	LastApplied uint16
+}
+
+// A compile time check to ensure StateUpdateReply implements the
+// lnwire.Message interface.
+var _ Message = (*StateUpdateReply)(nil)
+
+// Decode deserializes a serialized StateUpdateReply message stored in the
+// passed io.Reader observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (s *StateUpdateReply) Decode(r io.Reader, pver uint32) error {
+	var code uint8
+	err := readElements(r,
+		&s.SessionID,
+		&code,
+		&s.LastApplied,
+	)
+	if err != nil {
+		return err
+	}
+	s.Code = TowerCode(code)
+
+	return nil
+}
+
+// Encode serializes the target StateUpdateReply into the passed io.Writer
+// observing the protocol version specified.
+//
+// This is part of the lnwire.Message interface.
+func (s *StateUpdateReply) Encode(w io.Writer, pver uint32) error {
+	return writeElements(w,
+		s.SessionID,
+		uint8(s.Code),
+		s.LastApplied,
+	)
+}
+
+// Command returns the integer uniquely identifying this message type on the
+// wire.
+//
+// This is part of the lnwire.Message interface.
+func (s *StateUpdateReply) Command() uint32 {
+	return CmdStateUpdateReply
+}
+
+// MaxPayloadLength returns the maximum allowed payload size for a
+// StateUpdateReply message observing the specified protocol version.
+//
+// This is part of the lnwire.Message interface.
+func (s *StateUpdateReply) MaxPayloadLength(uint32) uint32 {
+	// SessionID - 8 bytes
+	// Code - 1 byte
+	// LastApplied - 2 bytes
+	return 8 + 1 + 2
+}
+
+// Validate performs any necessary sanity checks to ensure all fields present
+// on the StateUpdateReply are valid.
+//
+// This is part of the lnwire.Message interface.
+func (s *StateUpdateReply) Validate() error {
+	return nil
+}
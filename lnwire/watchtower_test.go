@@ -0,0 +1,69 @@
+package lnwire
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestWatchtowerEncodeDecode(t *testing.T) {
+	update := &StateUpdate{
+		SessionID:     7,
+		SeqNum:        3,
+		EncryptedBlob: bytes.Repeat([]byte{0xaa}, 300),
+	}
+	copy(update.Hint[:], bytes.Repeat([]byte{0xbb}, BreachHintSize))
+
+	msgs := []Message{
+		&CreateSession{
+			MaxUpdates:   1000,
+			SweepFeeRate: 2500,
+		},
+		&CreateSessionReply{
+			Code:           TowerCodeOK,
+			SessionID:      7,
+			MaxUpdates:     500,
+			RewardRate:     10000,
+			RewardPkScript: bytes.Repeat([]byte{0xcc}, 22),
+		},
+		update,
+		&StateUpdateReply{
+			SessionID:   7,
+			Code:        TowerCodeSeqNumOutOfOrder,
+			LastApplied: 2,
+		},
+	}
+
+	for _, msg := range msgs {
+		// Next encode the message into an empty bytes buffer.
+		var b bytes.Buffer
+		if err := msg.Encode(&b, 0); err != nil {
+			t.Fatalf("unable to encode %T: %v", msg, err)
+		}
+		if uint32(b.Len()) > msg.MaxPayloadLength(0) {
+			t.Fatalf("%T of %v bytes exceeds its max payload "+
+				"length", msg, b.Len())
+		}
+
+		// Deserialize the encoded message into a new empty struct.
+		msg2, err := makeEmptyMessage(msg.Command())
+		if err != nil {
+			t.Fatalf("unable to create %T: %v", msg, err)
+		}
+		if err := msg2.Decode(&b, 0); err != nil {
+			t.Fatalf("unable to decode %T: %v", msg, err)
+		}
+
+		// Assert equality of the two instances.
+		if !reflect.DeepEqual(msg, msg2) {
+			t.Fatalf("encode/decode %T messages don't match "+
+				"%#v vs %#v", msg, msg, msg2)
+		}
+	}
+
+	// A blob beyond the maximum size must be rejected.
+	update.EncryptedBlob = make([]byte, MaxJusticeBlobSize+1)
+	if err := update.Validate(); err == nil {
+		t.Fatalf("oversized blob should be rejected")
+	}
+}
@@ -32,6 +32,7 @@ var (
 	brarLog    = btclog.Disabled
 	cmgrLog    = btclog.Disabled
 	crtrLog    = btclog.Disabled
+	advrLog    = btclog.Disabled
 )
 
 // subsystemLoggers maps each subsystem identifier to its associated logger.
@@ -49,6 +50,7 @@ var subsystemLoggers = map[string]btclog.Logger{
 	"BRAR": brarLog,
 	"CMGR": cmgrLog,
 	"CRTR": crtrLog,
+	"ADVR": advrLog,
 }
 
 // useLogger updates the logger references for subsystemID to logger.  Invalid
@@ -103,6 +105,9 @@ func useLogger(subsystemID string, logger btclog.Logger) {
 	case "CRTR":
 		crtrLog = logger
 		routing.UseLogger(crtrLog)
+
+	case "ADVR":
+		advrLog = logger
 	}
 }
 
@@ -162,6 +162,11 @@ func (l *lightningNode) genArgs() []string {
 	args = append(args, fmt.Sprintf("--datadir=%v", l.cfg.DataDir))
 	args = append(args, fmt.Sprintf("--simnet"))
 
+	// The tests assume a CSV delay of 4 blocks for every channel, rather
+	// than one scaled by its capacity.
+	args = append(args, "--csvdelay.min=4")
+	args = append(args, "--csvdelay.max=4")
+
 	if l.extraArgs != nil {
 		args = append(args, l.extraArgs...)
 	}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/roasbeef/btcd/btcec"
+)
+
+// minNodeKeyPrefixLen is the length of the shortest hex encoded prefix of a
+// public key which is accepted in place of the full key.
+const minNodeKeyPrefixLen = 8
+
+// nodeCandidate is a node a reference given by a client may resolve to.
+type nodeCandidate struct {
+	pubKey *btcec.PublicKey
+
+	// alias is the alias the node advertised within the channel graph,
+	// if any.
+	alias string
+
+	// isPeer denotes a node we're either connected to, or have a channel
+	// with. Peers are favored when a reference is ambiguous, as they're
+	// by far the most likely target of a command.
+	isPeer bool
+}
+
+// resolveNodeRef resolves a reference to a node given by a client to the
+// node's public key. The reference may be either:
+//  * the node's hex encoded public key.
+//  * the node's alias. Exact matches are favored over those which only match
+//    when ignoring case.
+//  * a prefix of the node's hex encoded public key, at least
+//    minNodeKeyPrefixLen characters long.
+//
+// If several nodes match an alias or prefix, then the match is narrowed down
+// to our peers. If the reference remains ambiguous, then an error listing
+// the public keys of the matching nodes is returned, so the client may pick
+// one.
+func resolveNodeRef(ref string,
+	candidates []*nodeCandidate) (*btcec.PublicKey, error) {
+
+	if ref == "" {
+		return nil, fmt.Errorf("node public key or alias must be " +
+			"specified")
+	}
+
+	// A reference which is a hex encoded public key is always taken
+	// literally.
+	if isNodeKey(ref) {
+		keyBytes, _ := hex.DecodeString(ref)
+		return btcec.ParsePubKey(keyBytes, btcec.S256())
+	}
+
+	matchers := []func(*nodeCandidate) bool{
+		func(c *nodeCandidate) bool {
+			return c.alias == ref
+		},
+		func(c *nodeCandidate) bool {
+			return c.alias != "" && strings.EqualFold(c.alias, ref)
+		},
+	}
+	if len(ref) >= minNodeKeyPrefixLen && isHex(ref) {
+		prefix := strings.ToLower(ref)
+		matchers = append(matchers, func(c *nodeCandidate) bool {
+			pub := hex.EncodeToString(c.pubKey.SerializeCompressed())
+			return strings.HasPrefix(pub, prefix)
+		})
+	}
+
+	for _, matches := range matchers {
+		var all, peers []*nodeCandidate
+		for _, c := range candidates {
+			if !matches(c) {
+				continue
+			}
+
+			all = append(all, c)
+			if c.isPeer {
+				peers = append(peers, c)
+			}
+		}
+
+		switch {
+		case len(all) == 0:
+			continue
+		case len(all) == 1:
+			return all[0].pubKey, nil
+		case len(peers) == 1:
+			return peers[0].pubKey, nil
+		}
+
+		// As the reference is ambiguous even among our peers, the
+		// client must pick one of them explicitly.
+		if len(peers) > 1 {
+			all = peers
+		}
+		keys := make([]string, 0, len(all))
+		for _, c := range all {
+			keys = append(keys, hex.EncodeToString(
+				c.pubKey.SerializeCompressed()))
+		}
+		sort.Strings(keys)
+
+		return nil, fmt.Errorf("%q is ambiguous, it matches nodes %v",
+			ref, strings.Join(keys, ", "))
+	}
+
+	return nil, fmt.Errorf("no node with public key or alias %q found", ref)
+}
+
+// isHex returns true if s consists solely of hex digits.
+func isHex(s string) bool {
+	for _, c := range s {
+		switch {
+		case c >= '0' && c <= '9', c >= 'a' && c <= 'f',
+			c >= 'A' && c <= 'F':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// isNodeKey returns true if s has the form of a hex encoded compressed public
+// key.
+func isNodeKey(s string) bool {
+	return len(s) == 2*btcec.PubKeyBytesLenCompressed && isHex(s)
+}
+
+// nodeCandidates returns the nodes a reference given by a client may resolve
+// to: all nodes within the channel graph, along with the peers we're
+// connected to or have channels with.
+func (r *rpcServer) nodeCandidates() ([]*nodeCandidate, error) {
+	byKey := make(map[string]*nodeCandidate)
+	addCandidate := func(pub *btcec.PublicKey) *nodeCandidate {
+		key := string(pub.SerializeCompressed())
+		c, ok := byKey[key]
+		if !ok {
+			c = &nodeCandidate{pubKey: pub}
+			byKey[key] = c
+		}
+		return c
+	}
+
+	graph := r.server.chanDB.ChannelGraph()
+	err := graph.ForEachNode(func(node *channeldb.LightningNode) error {
+		addCandidate(node.PubKey).alias = node.Alias
+		return nil
+	})
+	if err != nil && err != channeldb.ErrGraphNotFound {
+		return nil, err
+	}
+
+	channels, err := r.server.chanDB.FetchAllChannels()
+	if err != nil && err != channeldb.ErrNoActiveChannels {
+		return nil, err
+	}
+	for _, channel := range channels {
+		addCandidate(channel.IdentityPub).isPeer = true
+	}
+	for _, p := range r.server.Peers() {
+		addCandidate(p.addr.IdentityKey).isPeer = true
+	}
+
+	// Our own node is never a valid target.
+	self := r.server.identityPriv.PubKey().SerializeCompressed()
+	candidates := make([]*nodeCandidate, 0, len(byKey))
+	for key, c := range byKey {
+		if bytes.Equal([]byte(key), self) {
+			continue
+		}
+		candidates = append(candidates, c)
+	}
+
+	return candidates, nil
+}
+
+// resolveNode resolves a reference to a node given by a client, either as its
+// public key, alias, or a prefix of its public key, to the node's public key.
+// See resolveNodeRef for the rules used to resolve ambiguous references.
+func (r *rpcServer) resolveNode(ref string) (*btcec.PublicKey, error) {
+	// Public keys are resolved without consulting the database, which
+	// keeps the common case cheap.
+	if isNodeKey(ref) {
+		return resolveNodeRef(ref, nil)
+	}
+
+	candidates, err := r.nodeCandidates()
+	if err != nil {
+		return nil, err
+	}
+
+	return resolveNodeRef(ref, candidates)
+}
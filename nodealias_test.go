@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/roasbeef/btcd/btcec"
+)
+
+func TestResolveNodeRef(t *testing.T) {
+	newCandidate := func(alias string, isPeer bool) *nodeCandidate {
+		priv, err := btcec.NewPrivateKey(btcec.S256())
+		if err != nil {
+			t.Fatalf("unable to create key: %v", err)
+		}
+		return &nodeCandidate{
+			pubKey: priv.PubKey(),
+			alias:  alias,
+			isPeer: isPeer,
+		}
+	}
+
+	alice := newCandidate("alice", true)
+	bob := newCandidate("bob", false)
+	bobPeer := newCandidate("bob", true)
+	carol := newCandidate("carol", false)
+	carolTwin := newCandidate("carol", false)
+	dave := newCandidate("Dave", false)
+	unnamed := newCandidate("", true)
+	candidates := []*nodeCandidate{
+		alice, bob, bobPeer, carol, carolTwin, dave, unnamed,
+	}
+
+	keyOf := func(c *nodeCandidate) string {
+		return hex.EncodeToString(c.pubKey.SerializeCompressed())
+	}
+
+	tests := []struct {
+		ref  string
+		want *nodeCandidate
+		err  string
+	}{
+		// Public keys are taken literally, even if they're unknown.
+		{keyOf(alice), alice, ""},
+		{strings.ToUpper(keyOf(bob)), bob, ""},
+
+		// A unique alias resolves to its node.
+		{"alice", alice, ""},
+
+		// Case is ignored only if there's no exact match.
+		{"DAVE", dave, ""},
+		{"Alice", alice, ""},
+
+		// An ambiguous alias resolves to the only matching peer.
+		{"bob", bobPeer, ""},
+
+		// An alias ambiguous among non-peers must be disambiguated.
+		{"carol", nil, "ambiguous"},
+
+		// Unique prefixes of public keys resolve to their node, while
+		// short ones are rejected.
+		{keyOf(unnamed)[:minNodeKeyPrefixLen], unnamed, ""},
+		{keyOf(unnamed)[:minNodeKeyPrefixLen-1], nil, "no node"},
+
+		{"mallory", nil, "no node"},
+		{"", nil, "must be specified"},
+	}
+	for _, test := range tests {
+		pub, err := resolveNodeRef(test.ref, candidates)
+		if test.err != "" {
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Fatalf("%q: expected error containing %q, "+
+					"got: %v", test.ref, test.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("unable to resolve %q: %v", test.ref, err)
+		}
+		if !pub.IsEqual(test.want.pubKey) {
+			t.Fatalf("%q resolved to %x, expected %x", test.ref,
+				pub.SerializeCompressed(),
+				test.want.pubKey.SerializeCompressed())
+		}
+	}
+
+	// The error for an ambiguous alias should list the matching nodes.
+	_, err := resolveNodeRef("carol", candidates)
+	if !strings.Contains(err.Error(), keyOf(carol)) ||
+		!strings.Contains(err.Error(), keyOf(carolTwin)) {
+
+		t.Fatalf("ambiguous alias error doesn't list candidates: %v",
+			err)
+	}
+}
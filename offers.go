@@ -0,0 +1,278 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/zpay32"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcutil"
+)
+
+const (
+	// offerInvoiceTimeout is the maximum amount of time we'll wait for a
+	// remote node to reply to an invoice request for one of its offers.
+	offerInvoiceTimeout = time.Second * 30
+
+	// offerRequestInterval is the interval over which the invoice requests
+	// of each node are counted.
+	offerRequestInterval = time.Minute
+
+	// maxOfferRequestsPerInterval is the maximum number of invoice
+	// requests we'll serve for a single node within each
+	// offerRequestInterval, as each served request adds an invoice to our
+	// database.
+	maxOfferRequestsPerInterval = 10
+)
+
+// offerManager is responsible for both sides of the reusable offer workflow.
+// Locally created offers are stored within the database, and each incoming
+// InvoiceRequest referencing one of them results in a fresh invoice being
+// added to the invoice registry. Outgoing invoice requests for remote offers
+// are tracked by their request ID until the matching InvoiceReply arrives.
+// Both requests and replies are exchanged directly with connected peers, and
+// over onion messages with any other node.
+type offerManager struct {
+	cdb      *channeldb.DB
+	invoices *invoiceRegistry
+
+	// sendToNode is used to deliver InvoiceRequest messages to the node
+	// which created an offer, and the replies to requests delivered over
+	// onion messages.
+	sendToNode func(target *btcec.PublicKey, msg lnwire.Message) error
+
+	pendingMtx      sync.Mutex
+	nextRequestID   uint64
+	pendingRequests map[uint64]chan *lnwire.InvoiceReply
+
+	// requests limits the rate of the invoice requests served for each
+	// node.
+	requests *peerRateLimiter
+}
+
+// newOfferManager creates a new offerManager backed by the passed database
+// and invoice registry.
+func newOfferManager(cdb *channeldb.DB, invoices *invoiceRegistry,
+	sendToNode func(*btcec.PublicKey, lnwire.Message) error) *offerManager {
+
+	return &offerManager{
+		cdb:             cdb,
+		invoices:        invoices,
+		sendToNode:      sendToNode,
+		pendingRequests: make(map[uint64]chan *lnwire.InvoiceReply),
+		requests: newPeerRateLimiter(offerRequestInterval,
+			maxOfferRequestsPerInterval),
+	}
+}
+
+// AddOffer creates and stores a new reusable offer. A zero amount creates an
+// offer for which the payer selects the amount.
+func (o *offerManager) AddOffer(memo []byte,
+	amt btcutil.Amount) (*channeldb.Offer, error) {
+
+	offer := &channeldb.Offer{
+		Memo:         memo,
+		Amount:       amt,
+		CreationDate: time.Now(),
+	}
+	if _, err := rand.Read(offer.ID[:]); err != nil {
+		return nil, err
+	}
+
+	if err := o.cdb.AddOffer(offer); err != nil {
+		return nil, err
+	}
+
+	ltndLog.Debugf("Added offer %x", offer.ID[:])
+
+	return offer, nil
+}
+
+// handleInvoiceRequest serves an InvoiceRequest received from the passed
+// node, passing the reply carrying a fresh invoice to the passed closure.
+// Requests beyond the node's rate limit are rejected. As adding the invoice
+// writes to the database, the request is served within its own goroutine, so
+// it doesn't stall the handler it was received by.
+func (o *offerManager) handleInvoiceRequest(requester *btcec.PublicKey,
+	req *lnwire.InvoiceRequest, reply func(lnwire.Message)) {
+
+	if !o.requests.allow(requester, time.Now()) {
+		ltndLog.Warnf("Rejecting invoice request from %x, more than %v "+
+			"requests within %v", requester.SerializeCompressed(),
+			maxOfferRequestsPerInterval, offerRequestInterval)
+
+		reply(&lnwire.InvoiceReply{
+			RequestID: req.RequestID,
+			Problem:   "too many invoice requests",
+		})
+		return
+	}
+
+	go func() {
+		reply(o.processInvoiceRequest(req))
+	}()
+}
+
+// handleOnionMessage handles an offer message delivered to us over an onion
+// message by the passed origin node. The reply to an InvoiceRequest is
+// returned to the origin over an onion message in turn.
+func (o *offerManager) handleOnionMessage(origin *btcec.PublicKey,
+	msg lnwire.Message) {
+
+	switch msg := msg.(type) {
+	case *lnwire.InvoiceRequest:
+		o.handleInvoiceRequest(origin, msg, func(reply lnwire.Message) {
+			if err := o.sendToNode(origin, reply); err != nil {
+				ltndLog.Errorf("unable to send invoice reply "+
+					"to %x: %v", origin.SerializeCompressed(),
+					err)
+			}
+		})
+
+	case *lnwire.InvoiceReply:
+		o.processInvoiceReply(msg)
+
+	default:
+		ltndLog.Warnf("Dropping onion message of unexpected type %T "+
+			"from %x", msg, origin.SerializeCompressed())
+	}
+}
+
+// processInvoiceRequest creates a fresh invoice for the offer referenced by
+// the passed InvoiceRequest, returning the reply to be sent back to the
+// requesting peer.
+func (o *offerManager) processInvoiceRequest(
+	req *lnwire.InvoiceRequest) *lnwire.InvoiceReply {
+
+	reply := &lnwire.InvoiceReply{
+		RequestID: req.RequestID,
+	}
+
+	offer, err := o.cdb.LookupOffer(req.OfferID)
+	if err != nil {
+		reply.Problem = err.Error()
+		return reply
+	}
+
+	// If the offer specifies an amount, then it takes precedence over the
+	// one requested by the payer.
+	amt := offer.Amount
+	if amt == 0 {
+		amt = req.Amount
+	}
+	if amt == 0 {
+		reply.Problem = "zero value invoices are disallowed"
+		return reply
+	}
+
+	invoice := &channeldb.Invoice{
+		CreationDate: time.Now(),
+		Memo:         offer.Memo,
+		Terms: channeldb.ContractTerm{
+			Value: amt,
+		},
+	}
+	if _, err := rand.Read(invoice.Terms.PaymentPreimage[:]); err != nil {
+		reply.Problem = "unable to create invoice"
+		return reply
+	}
+
+	if err := o.invoices.AddOfferInvoice(offer.ID, invoice); err != nil {
+		ltndLog.Errorf("unable to add invoice for offer %x: %v",
+			offer.ID[:], err)
+		reply.Problem = "unable to create invoice"
+		return reply
+	}
+
+	reply.PaymentHash = sha256.Sum256(invoice.Terms.PaymentPreimage[:])
+	reply.Amount = amt
+
+	ltndLog.Debugf("Issued invoice %x for offer %x", reply.PaymentHash[:],
+		offer.ID[:])
+
+	return reply
+}
+
+// processInvoiceReply dispatches an incoming InvoiceReply to the goroutine
+// waiting on the matching request, if any.
+func (o *offerManager) processInvoiceReply(reply *lnwire.InvoiceReply) {
+	o.pendingMtx.Lock()
+	replyChan, ok := o.pendingRequests[reply.RequestID]
+	delete(o.pendingRequests, reply.RequestID)
+	o.pendingMtx.Unlock()
+
+	if !ok {
+		ltndLog.Warnf("Received reply for unknown invoice request %v",
+			reply.RequestID)
+		return
+	}
+
+	replyChan <- reply
+}
+
+// RequestInvoice asks the node which created the passed offer for a fresh
+// invoice, returning a payment request for it once the node replies. The amt
+// is only used if the offer doesn't specify an amount itself.
+func (o *offerManager) RequestInvoice(offer *zpay32.Offer,
+	amt btcutil.Amount) (*zpay32.PaymentRequest, error) {
+
+	replyChan := make(chan *lnwire.InvoiceReply, 1)
+
+	o.pendingMtx.Lock()
+	reqID := o.nextRequestID
+	o.nextRequestID++
+	o.pendingRequests[reqID] = replyChan
+	o.pendingMtx.Unlock()
+
+	cleanUp := func() {
+		o.pendingMtx.Lock()
+		delete(o.pendingRequests, reqID)
+		o.pendingMtx.Unlock()
+	}
+
+	req := &lnwire.InvoiceRequest{
+		RequestID: reqID,
+		OfferID:   offer.OfferID,
+		Amount:    amt,
+	}
+	if err := o.sendToNode(offer.Destination, req); err != nil {
+		cleanUp()
+		return nil, fmt.Errorf("unable to send invoice request: %v",
+			err)
+	}
+
+	select {
+	case reply := <-replyChan:
+		if reply.Problem != "" {
+			return nil, errors.New(reply.Problem)
+		}
+
+		// Ensure the remote node didn't hand us an invoice for an
+		// amount other than the one we agreed to pay.
+		expectedAmt := offer.Amount
+		if expectedAmt == 0 {
+			expectedAmt = amt
+		}
+		if reply.Amount != expectedAmt {
+			return nil, fmt.Errorf("invoice amount mismatch: "+
+				"expected %v, got %v", expectedAmt,
+				reply.Amount)
+		}
+
+		return &zpay32.PaymentRequest{
+			Destination: offer.Destination,
+			PaymentHash: reply.PaymentHash,
+			Amount:      reply.Amount,
+		}, nil
+
+	case <-time.After(offerInvoiceTimeout):
+		cleanUp()
+		return nil, errors.New("timeout waiting for invoice reply")
+	}
+}
@@ -0,0 +1,414 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcutil"
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/ripemd160"
+)
+
+const (
+	// onionMessagesFeature is the name of the local feature advertised by
+	// nodes which relay, and accept, onion messages.
+	onionMessagesFeature = "onion-messages"
+
+	// onionMessagesFeatureIndex is the index of onionMessagesFeature
+	// within the local feature vector.
+	onionMessagesFeatureIndex = 10
+
+	// maxOnionMessageHops is the maximum number of hops along the route
+	// of an onion message, bounded by the hops a Sphinx packet may carry.
+	maxOnionMessageHops = 20
+
+	// onionMessageInterval is the interval over which the onion messages
+	// received from each peer are counted.
+	onionMessageInterval = time.Minute
+
+	// maxOnionMessagesPerInterval is the maximum number of onion messages
+	// we'll process for a single peer within each onionMessageInterval,
+	// bounding the traffic a peer may have us relay.
+	maxOnionMessagesPerInterval = 60
+)
+
+// onionMessenger delivers messages to nodes we aren't connected to by routing
+// them as onion messages along a path of nodes through the channel graph,
+// and relays the onion messages of other nodes along their route. Each hop
+// only learns the next hop from the Sphinx onion, while the payload is
+// encrypted to the destination.
+//
+// NOTE: The encrypted payload is relayed unchanged along the route, so
+// colluding hops are able to tell they relayed the same message. Likewise, a
+// route may break at any hop which doesn't relay onion messages, which the
+// sender can't detect, so requests sent over onion messages must time out.
+type onionMessenger struct {
+	identityPriv *btcec.PrivateKey
+	sphinx       *sphinx.Router
+	graph        *channeldb.ChannelGraph
+
+	// findPeer returns the connected peer with the passed identity key.
+	findPeer func(*btcec.PublicKey) (*peer, error)
+
+	// findPeerByHop returns the connected peer whose identity key hashes
+	// to the passed next hop of a Sphinx onion, or nil if there's none.
+	findPeerByHop func([ripemd160.Size]byte) *peer
+
+	// deliver hands a message delivered to us over an onion message to the
+	// subsystem handling it, along with the node which sent it.
+	deliver func(origin *btcec.PublicKey, msg lnwire.Message)
+
+	// messages limits the rate of the onion messages processed for each
+	// peer.
+	messages *peerRateLimiter
+}
+
+// newOnionMessenger creates a new onionMessenger routing messages through the
+// passed graph, and handing those delivered to us to the passed closure.
+func newOnionMessenger(identityPriv *btcec.PrivateKey, router *sphinx.Router,
+	graph *channeldb.ChannelGraph,
+	findPeer func(*btcec.PublicKey) (*peer, error),
+	findPeerByHop func([ripemd160.Size]byte) *peer,
+	deliver func(*btcec.PublicKey, lnwire.Message)) *onionMessenger {
+
+	return &onionMessenger{
+		identityPriv:  identityPriv,
+		sphinx:        router,
+		graph:         graph,
+		findPeer:      findPeer,
+		findPeerByHop: findPeerByHop,
+		deliver:       deliver,
+		messages: newPeerRateLimiter(onionMessageInterval,
+			maxOnionMessagesPerInterval),
+	}
+}
+
+// sendMessage delivers the passed message to the target node. If the target
+// is a connected peer, then the message is sent to it directly. Otherwise,
+// it's routed to the target as an onion message.
+func (m *onionMessenger) sendMessage(target *btcec.PublicKey,
+	msg lnwire.Message) error {
+
+	if p, err := m.findPeer(target); err == nil {
+		p.queueMsg(msg, nil)
+		return nil
+	}
+
+	route, err := m.findRoute(target)
+	if err != nil {
+		return err
+	}
+
+	// The payload carries our identity key, so the destination is able
+	// to reply, followed by the message itself, all encrypted to the
+	// destination.
+	var plaintext bytes.Buffer
+	plaintext.Write(m.identityPriv.PubKey().SerializeCompressed())
+	_, err = lnwire.WriteMessage(&plaintext, msg, 0, activeNetParams.Net)
+	if err != nil {
+		return err
+	}
+
+	ephemeralPriv, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		return err
+	}
+	payload, err := sealOnionMessage(ephemeralPriv, target,
+		plaintext.Bytes())
+	if err != nil {
+		return err
+	}
+	if len(payload) > lnwire.MaxOnionMessagePayload {
+		return fmt.Errorf("onion message payload of %v bytes exceeds "+
+			"the maximum of %v", len(payload),
+			lnwire.MaxOnionMessagePayload)
+	}
+
+	hopPayloads := make([][]byte, len(route))
+	for i := range hopPayloads {
+		hopPayloads[i] = make([]byte, sphinx.HopPayloadSize)
+	}
+	sessionKey, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		return err
+	}
+	onionPkt, err := sphinx.NewOnionPacket(route, sessionKey, hopPayloads,
+		onionMessageAssocData(ephemeralPriv.PubKey(), payload))
+	if err != nil {
+		return err
+	}
+
+	onionMsg := &lnwire.OnionMessage{
+		EphemeralKey: ephemeralPriv.PubKey(),
+		Payload:      payload,
+	}
+	var onionBlob bytes.Buffer
+	if err := onionPkt.Encode(&onionBlob); err != nil {
+		return err
+	}
+	copy(onionMsg.OnionBlob[:], onionBlob.Bytes())
+
+	firstHop, err := m.findPeer(route[0])
+	if err != nil {
+		return err
+	}
+	firstHop.queueMsg(onionMsg, nil)
+
+	return nil
+}
+
+// findRoute returns the shortest path of nodes through the channel graph
+// from ourselves to the target node, excluding ourselves. The first hop must
+// be a connected peer which relays onion messages.
+func (m *onionMessenger) findRoute(target *btcec.PublicKey) (
+	[]*btcec.PublicKey, error) {
+
+	var source, dest [33]byte
+	copy(source[:], m.identityPriv.PubKey().SerializeCompressed())
+	copy(dest[:], target.SerializeCompressed())
+
+	neighbors := make(map[[33]byte][][33]byte)
+	err := m.graph.ForEachChannel(func(info *channeldb.ChannelEdgeInfo,
+		_, _ *channeldb.ChannelEdgePolicy) error {
+
+		var node1, node2 [33]byte
+		copy(node1[:], info.NodeKey1.SerializeCompressed())
+		copy(node2[:], info.NodeKey2.SerializeCompressed())
+
+		neighbors[node1] = append(neighbors[node1], node2)
+		neighbors[node2] = append(neighbors[node2], node1)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// A breadth first search from ourselves yields the shortest path,
+	// tracking the node each node was first reached from.
+	prev := map[[33]byte][33]byte{source: source}
+	queue := [][33]byte{source}
+	for len(queue) != 0 {
+		node := queue[0]
+		queue = queue[1:]
+		if node == dest {
+			break
+		}
+
+		for _, next := range neighbors[node] {
+			if _, ok := prev[next]; ok {
+				continue
+			}
+			if node == source && !m.relaysOnionMessages(next) {
+				continue
+			}
+
+			prev[next] = node
+			queue = append(queue, next)
+		}
+	}
+	if _, ok := prev[dest]; !ok {
+		return nil, fmt.Errorf("no route to %x for onion message",
+			dest[:])
+	}
+
+	var path [][33]byte
+	for node := dest; node != source; node = prev[node] {
+		path = append([][33]byte{node}, path...)
+	}
+	if len(path) > maxOnionMessageHops {
+		return nil, fmt.Errorf("route to %x for onion message exceeds "+
+			"%v hops", dest[:], maxOnionMessageHops)
+	}
+
+	route := make([]*btcec.PublicKey, len(path))
+	for i, node := range path {
+		route[i], err = btcec.ParsePubKey(node[:], btcec.S256())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return route, nil
+}
+
+// relaysOnionMessages returns true if the node identified by the passed
+// serialized key is a connected peer which relays onion messages.
+func (m *onionMessenger) relaysOnionMessages(node [33]byte) bool {
+	nodeKey, err := btcec.ParsePubKey(node[:], btcec.S256())
+	if err != nil {
+		return false
+	}
+	p, err := m.findPeer(nodeKey)
+	if err != nil {
+		return false
+	}
+
+	return p.localSharedFeatures.IsActive(onionMessagesFeature)
+}
+
+// handleOnionMessage processes an onion message received from the passed
+// peer, either relaying it to the next hop along its route, or delivering it
+// if we're its destination. Messages beyond the peer's rate limit are
+// dropped.
+func (m *onionMessenger) handleOnionMessage(p *peer,
+	msg *lnwire.OnionMessage) {
+
+	if !m.messages.allow(p.addr.IdentityKey, time.Now()) {
+		peerLog.Warnf("Dropping onion message from %v, more than %v "+
+			"messages within %v", p, maxOnionMessagesPerInterval,
+			onionMessageInterval)
+		return
+	}
+
+	onionPkt := &sphinx.OnionPacket{}
+	if err := onionPkt.Decode(bytes.NewReader(msg.OnionBlob[:])); err != nil {
+		peerLog.Errorf("unable to decode onion message from %v: %v",
+			p, err)
+		return
+	}
+
+	assocData := onionMessageAssocData(msg.EphemeralKey, msg.Payload)
+	processed, err := m.sphinx.ProcessOnionPacket(onionPkt, assocData)
+	if err != nil {
+		peerLog.Errorf("unable to process onion message from %v: %v",
+			p, err)
+		return
+	}
+
+	switch processed.Action {
+	case sphinx.ExitNode:
+		origin, inner, err := m.openOnionMessage(msg)
+		if err != nil {
+			peerLog.Errorf("unable to open onion message from %v: "+
+				"%v", p, err)
+			return
+		}
+
+		m.deliver(origin, inner)
+
+	case sphinx.MoreHops:
+		nextPeer := m.findPeerByHop(processed.NextHop)
+		if nextPeer == nil ||
+			!nextPeer.localSharedFeatures.IsActive(onionMessagesFeature) {
+
+			peerLog.Debugf("Dropping onion message from %v, next "+
+				"hop %x doesn't relay onion messages", p,
+				processed.NextHop[:])
+			return
+		}
+
+		relayed := &lnwire.OnionMessage{
+			EphemeralKey: msg.EphemeralKey,
+			Payload:      msg.Payload,
+		}
+		var onionBlob bytes.Buffer
+		if err := processed.Packet.Encode(&onionBlob); err != nil {
+			peerLog.Errorf("unable to encode onion message: %v", err)
+			return
+		}
+		copy(relayed.OnionBlob[:], onionBlob.Bytes())
+
+		nextPeer.queueMsg(relayed, nil)
+
+	default:
+		peerLog.Errorf("malformed onion message from %v", p)
+	}
+}
+
+// openOnionMessage decrypts the payload of an onion message destined to us,
+// returning the node which sent it along with the message it carries.
+func (m *onionMessenger) openOnionMessage(msg *lnwire.OnionMessage) (
+	*btcec.PublicKey, lnwire.Message, error) {
+
+	plaintext, err := openOnionMessagePayload(m.identityPriv,
+		msg.EphemeralKey, msg.Payload)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(plaintext) < 33 {
+		return nil, nil, errors.New("onion message payload too short")
+	}
+
+	origin, err := btcec.ParsePubKey(plaintext[:33], btcec.S256())
+	if err != nil {
+		return nil, nil, err
+	}
+	_, inner, _, err := lnwire.ReadMessage(bytes.NewReader(plaintext[33:]),
+		0, activeNetParams.Net)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return origin, inner, nil
+}
+
+// onionMessageKey derives the key the payload of an onion message is
+// encrypted under from the shared secret of the passed keys.
+func onionMessageKey(priv *btcec.PrivateKey, pub *btcec.PublicKey) [32]byte {
+	return sha256.Sum256(btcec.GenerateSharedSecret(priv, pub))
+}
+
+// sealOnionMessage encrypts the payload of an onion message to the
+// destination's identity key, using a key shared with the passed ephemeral
+// key. As each ephemeral key is used only once, a zero nonce is used.
+func sealOnionMessage(ephemeralPriv *btcec.PrivateKey, dest *btcec.PublicKey,
+	plaintext []byte) ([]byte, error) {
+
+	key := onionMessageKey(ephemeralPriv, dest)
+	cipher, err := chacha20poly1305.New(key[:])
+	if err != nil {
+		return nil, err
+	}
+
+	var nonce [chacha20poly1305.NonceSize]byte
+	return cipher.Seal(nil, nonce[:], plaintext, nil), nil
+}
+
+// openOnionMessagePayload decrypts the payload of an onion message encrypted
+// to the passed identity key by sealOnionMessage.
+func openOnionMessagePayload(identityPriv *btcec.PrivateKey,
+	ephemeralPub *btcec.PublicKey, payload []byte) ([]byte, error) {
+
+	key := onionMessageKey(identityPriv, ephemeralPub)
+	cipher, err := chacha20poly1305.New(key[:])
+	if err != nil {
+		return nil, err
+	}
+
+	var nonce [chacha20poly1305.NonceSize]byte
+	return cipher.Open(nil, nonce[:], payload, nil)
+}
+
+// onionMessageAssocData returns the data the Sphinx onion of an onion message
+// commits to, binding the onion to the encrypted payload it carries.
+func onionMessageAssocData(ephemeralPub *btcec.PublicKey,
+	payload []byte) []byte {
+
+	h := sha256.New()
+	h.Write(ephemeralPub.SerializeCompressed())
+	h.Write(payload)
+	return h.Sum(nil)
+}
+
+// findPeerByOnionHop returns the connected peer whose identity key hashes to
+// the passed next hop of a Sphinx onion, or nil if there's none.
+func (s *server) findPeerByOnionHop(nextHop [ripemd160.Size]byte) *peer {
+	s.peersMtx.RLock()
+	defer s.peersMtx.RUnlock()
+
+	for _, p := range s.peersByPub {
+		hop := btcutil.Hash160(p.addr.IdentityKey.SerializeCompressed())
+		if bytes.Equal(hop, nextHop[:]) {
+			return p
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/roasbeef/btcd/btcec"
+)
+
+// TestOnionMessagePayload tests that the payload of an onion message can only
+// be opened by its destination, using the ephemeral key it was sealed with.
+func TestOnionMessagePayload(t *testing.T) {
+	var keys [3]*btcec.PrivateKey
+	for i := range keys {
+		priv, err := btcec.NewPrivateKey(btcec.S256())
+		if err != nil {
+			t.Fatalf("unable to generate key: %v", err)
+		}
+		keys[i] = priv
+	}
+	ephemeral, dest, other := keys[0], keys[1], keys[2]
+
+	plaintext := []byte("invoice request")
+	payload, err := sealOnionMessage(ephemeral, dest.PubKey(), plaintext)
+	if err != nil {
+		t.Fatalf("unable to seal payload: %v", err)
+	}
+
+	opened, err := openOnionMessagePayload(dest, ephemeral.PubKey(),
+		payload)
+	if err != nil {
+		t.Fatalf("unable to open payload: %v", err)
+	}
+	if !bytes.Equal(opened, plaintext) {
+		t.Fatalf("expected payload %x, got %x", plaintext, opened)
+	}
+
+	// Any node other than the destination is unable to open the payload.
+	_, err = openOnionMessagePayload(other, ephemeral.PubKey(), payload)
+	if err == nil {
+		t.Fatalf("payload opened by node other than destination")
+	}
+
+	// Likewise, a payload which has been tampered with is rejected.
+	payload[0] ^= 1
+	_, err = openOnionMessagePayload(dest, ephemeral.PubKey(), payload)
+	if err == nil {
+		t.Fatalf("tampered payload opened")
+	}
+}
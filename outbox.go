@@ -0,0 +1,443 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
+)
+
+const (
+	// webhookSubscriberPrefix prefixes the outbox subscriber name of each
+	// webhook, which is followed by the webhook's URL. Stream subscribers
+	// may not use names with this prefix.
+	webhookSubscriberPrefix = "webhook:"
+
+	// webhookTimeout is the time a webhook has to respond to a delivery
+	// before it's considered failed.
+	webhookTimeout = 30 * time.Second
+
+	// minWebhookBackoff and maxWebhookBackoff bound the delay between
+	// attempts to deliver a notification to a failing webhook. The delay
+	// doubles after each failed attempt.
+	minWebhookBackoff = time.Second
+	maxWebhookBackoff = 5 * time.Minute
+)
+
+// outboxConfig defines the options of the notification outbox.
+type outboxConfig struct {
+	Webhooks []string `long:"webhook" description:"Deliver event notifications to the given URL as JSON POST requests. Each notification is retried until the URL responds with a 2xx status. May be specified multiple times"`
+}
+
+// validate checks that each webhook is an absolute HTTP(S) URL.
+func (o *outboxConfig) validate() error {
+	for _, webhook := range o.Webhooks {
+		u, err := url.Parse(webhook)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") ||
+			u.Host == "" {
+
+			return fmt.Errorf("invalid outbox.webhook %q, must be "+
+				"an http or https URL", webhook)
+		}
+	}
+
+	return nil
+}
+
+// outboxDelivery is the JSON encoding of a notification as delivered to
+// webhooks.
+type outboxDelivery struct {
+	ID        uint64          `json:"id"`
+	Type      string          `json:"type"`
+	Timestamp int64           `json:"timestamp"`
+	Payload   json.RawMessage `json:"payload"`
+}
+
+// invoiceSettledPayload is the payload of an InvoiceSettled notification.
+type invoiceSettledPayload struct {
+	PaymentHash string `json:"payment_hash"`
+	Memo        string `json:"memo"`
+	Value       int64  `json:"value"`
+}
+
+// channelClosedPayload is the payload of a ChannelClosed notification.
+type channelClosedPayload struct {
+	ChannelPoint string `json:"channel_point"`
+	RemotePubkey string `json:"remote_pubkey"`
+	ClosingTxid  string `json:"closing_txid,omitempty"`
+	Capacity     int64  `json:"capacity"`
+}
+
+// breachDetectedPayload is the payload of a BreachDetected notification.
+type breachDetectedPayload struct {
+	ChannelPoint    string `json:"channel_point"`
+	RemotePubkey    string `json:"remote_pubkey"`
+	BreachTxid      string `json:"breach_txid"`
+	RevokedStateNum uint64 `json:"revoked_state_num"`
+}
+
+// stateDivergedPayload is the payload of a StateDiverged notification.
+type stateDivergedPayload struct {
+	ChannelPoint string `json:"channel_point"`
+	RemotePubkey string `json:"remote_pubkey"`
+	Field        string `json:"field"`
+	InMemory     string `json:"in_memory"`
+	Persisted    string `json:"persisted"`
+}
+
+// lowDiskSpacePayload is the payload of a LowDiskSpace notification.
+type lowDiskSpacePayload struct {
+	Path      string `json:"path"`
+	FreeBytes uint64 `json:"free_bytes"`
+	Threshold uint64 `json:"threshold"`
+}
+
+// notificationOutbox persists notifications of notable events within the
+// database, and delivers them to each subscriber until it acknowledges them.
+// As notifications are only removed once acknowledged by every subscriber,
+// each is delivered at least once, even across restarts of the daemon.
+//
+// Subscribers are either the configured webhooks, which acknowledge a
+// notification by responding to its delivery with a 2xx status, or named
+// clients of the SubscribeOutbox RPC, which acknowledge notifications
+// explicitly.
+type notificationOutbox struct {
+	db       *channeldb.DB
+	webhooks []string
+
+	// mtx guards the fields below.
+	mtx sync.Mutex
+
+	// wakeups holds a channel for each active subscriber, which is
+	// signalled when a new notification is added.
+	wakeups map[string]chan struct{}
+
+	started uint32
+	stopped uint32
+	quit    chan struct{}
+	wg      sync.WaitGroup
+}
+
+// newNotificationOutbox creates a new outbox which delivers notifications to
+// the passed webhooks, along with any stream subscribers.
+func newNotificationOutbox(db *channeldb.DB,
+	webhooks []string) *notificationOutbox {
+
+	return &notificationOutbox{
+		db:       db,
+		webhooks: webhooks,
+		wakeups:  make(map[string]chan struct{}),
+		quit:     make(chan struct{}),
+	}
+}
+
+// Start unregisters the webhooks which are no longer configured, then
+// launches the delivery of pending notifications to each configured webhook.
+func (o *notificationOutbox) Start() error {
+	if !atomic.CompareAndSwapUint32(&o.started, 0, 1) {
+		return nil
+	}
+
+	subscribers, err := o.db.FetchOutboxSubscribers()
+	if err != nil {
+		return err
+	}
+
+	configured := make(map[string]struct{})
+	for _, webhook := range o.webhooks {
+		configured[webhookSubscriberPrefix+webhook] = struct{}{}
+	}
+	for name := range subscribers {
+		if !strings.HasPrefix(name, webhookSubscriberPrefix) {
+			continue
+		}
+		if _, ok := configured[name]; ok {
+			continue
+		}
+
+		ltndLog.Infof("Removing outbox subscriber for unconfigured %v",
+			name)
+		if err := o.db.RemoveOutboxSubscriber(name); err != nil {
+			return err
+		}
+	}
+
+	for _, webhook := range o.webhooks {
+		name := webhookSubscriberPrefix + webhook
+		acked, err := o.db.AddOutboxSubscriber(name)
+		if err != nil {
+			return err
+		}
+
+		wakeup, err := o.register(name)
+		if err != nil {
+			return err
+		}
+
+		o.wg.Add(1)
+		go o.webhookDeliverer(webhook, acked, wakeup)
+	}
+
+	return nil
+}
+
+// Stop halts the delivery of notifications to webhooks.
+func (o *notificationOutbox) Stop() error {
+	if !atomic.CompareAndSwapUint32(&o.stopped, 0, 1) {
+		return nil
+	}
+
+	close(o.quit)
+	o.wg.Wait()
+
+	return nil
+}
+
+// register marks the named subscriber as active, returning the channel
+// signalled whenever a new notification is added. Each subscriber may only
+// be active once at a time.
+func (o *notificationOutbox) register(name string) (chan struct{}, error) {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+
+	if _, ok := o.wakeups[name]; ok {
+		return nil, fmt.Errorf("outbox subscriber %q is already active",
+			name)
+	}
+
+	wakeup := make(chan struct{}, 1)
+	o.wakeups[name] = wakeup
+
+	return wakeup, nil
+}
+
+// unregister marks the named subscriber as no longer active.
+func (o *notificationOutbox) unregister(name string) {
+	o.mtx.Lock()
+	delete(o.wakeups, name)
+	o.mtx.Unlock()
+}
+
+// notify adds a notification of the passed type to the outbox, with the JSON
+// encoding of payload as its payload, then wakes all active subscribers.
+func (o *notificationOutbox) notify(nType channeldb.NotificationType,
+	payload interface{}) {
+
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		ltndLog.Errorf("unable to encode %v notification: %v", nType,
+			err)
+		return
+	}
+
+	id, err := o.db.AddNotification(&channeldb.Notification{
+		Type:      nType,
+		Timestamp: time.Now(),
+		Payload:   payloadBytes,
+	})
+	if err != nil {
+		ltndLog.Errorf("unable to add %v notification to outbox: %v",
+			nType, err)
+		return
+	}
+
+	ltndLog.Debugf("Added %v notification #%v to outbox", nType, id)
+
+	o.mtx.Lock()
+	for _, wakeup := range o.wakeups {
+		select {
+		case wakeup <- struct{}{}:
+		default:
+		}
+	}
+	o.mtx.Unlock()
+}
+
+// invoiceSettled adds a notification that the invoice with the passed
+// payment hash has been settled.
+func (o *notificationOutbox) invoiceSettled(rHash chainhash.Hash,
+	invoice *channeldb.Invoice) {
+
+	o.notify(channeldb.InvoiceSettledNotification, &invoiceSettledPayload{
+		PaymentHash: hex.EncodeToString(rHash[:]),
+		Memo:        string(invoice.Memo),
+		Value:       int64(invoice.Terms.Value),
+	})
+}
+
+// channelClosed adds a notification that the closing transaction of the
+// passed channel has been confirmed.
+func (o *notificationOutbox) channelClosed(channel *lnwallet.LightningChannel,
+	closingTxid *chainhash.Hash) {
+
+	snapshot := channel.StateSnapshot()
+	payload := &channelClosedPayload{
+		ChannelPoint: channel.ChannelPoint().String(),
+		RemotePubkey: hex.EncodeToString(
+			snapshot.RemoteIdentity.SerializeCompressed()),
+		Capacity: int64(snapshot.Capacity),
+	}
+	if closingTxid != nil {
+		payload.ClosingTxid = closingTxid.String()
+	}
+
+	o.notify(channeldb.ChannelClosedNotification, payload)
+}
+
+// breachDetected adds a notification that the remote party of the passed
+// channel has broadcast the revoked commitment transaction breachTx.
+func (o *notificationOutbox) breachDetected(
+	channel *lnwallet.LightningChannel, breachTx *wire.MsgTx,
+	revokedStateNum uint64) {
+
+	snapshot := channel.StateSnapshot()
+	o.notify(channeldb.BreachDetectedNotification, &breachDetectedPayload{
+		ChannelPoint: channel.ChannelPoint().String(),
+		RemotePubkey: hex.EncodeToString(
+			snapshot.RemoteIdentity.SerializeCompressed()),
+		BreachTxid:      breachTx.TxHash().String(),
+		RevokedStateNum: revokedStateNum,
+	})
+}
+
+// stateDiverged adds a notification that the in-memory state of the passed
+// channel has diverged from its persisted state, freezing the channel.
+func (o *notificationOutbox) stateDiverged(channel *lnwallet.LightningChannel,
+	divergence *lnwallet.ErrStateDiverged) {
+
+	snapshot := channel.StateSnapshot()
+	o.notify(channeldb.StateDivergedNotification, &stateDivergedPayload{
+		ChannelPoint: channel.ChannelPoint().String(),
+		RemotePubkey: hex.EncodeToString(
+			snapshot.RemoteIdentity.SerializeCompressed()),
+		Field:     divergence.Field,
+		InMemory:  divergence.InMemory,
+		Persisted: divergence.Persisted,
+	})
+}
+
+// lowDiskSpace adds a notification that the free space on the volume of the
+// passed path has fallen below the threshold, both in bytes.
+func (o *notificationOutbox) lowDiskSpace(path string, free,
+	threshold uint64) {
+
+	o.notify(channeldb.LowDiskSpaceNotification, &lowDiskSpacePayload{
+		Path:      path,
+		FreeBytes: free,
+		Threshold: threshold,
+	})
+}
+
+// webhookDeliverer delivers each notification after acked to the passed
+// webhook in order, acknowledging each once the webhook has accepted it.
+//
+// NOTE: This MUST be run as a goroutine.
+func (o *notificationOutbox) webhookDeliverer(webhook string, acked uint64,
+	wakeup chan struct{}) {
+
+	defer o.wg.Done()
+
+	name := webhookSubscriberPrefix + webhook
+	defer o.unregister(name)
+
+	client := &http.Client{Timeout: webhookTimeout}
+	for {
+		notifications, err := o.db.FetchNotifications(acked)
+		if err != nil {
+			ltndLog.Errorf("unable to fetch outbox notifications: %v",
+				err)
+		}
+
+		for _, n := range notifications {
+			if !o.deliverWebhook(client, webhook, n) {
+				return
+			}
+
+			if err := o.db.AckNotification(name, n.ID); err != nil {
+				ltndLog.Errorf("unable to ack notification #%v "+
+					"for %v: %v", n.ID, name, err)
+			}
+			acked = n.ID
+		}
+
+		select {
+		case <-wakeup:
+		case <-o.quit:
+			return
+		}
+	}
+}
+
+// deliverWebhook posts the passed notification to the webhook, retrying with
+// an exponential backoff until the webhook accepts it. False is returned if
+// the outbox is stopped before then.
+func (o *notificationOutbox) deliverWebhook(client *http.Client,
+	webhook string, n *channeldb.Notification) bool {
+
+	body, err := json.Marshal(&outboxDelivery{
+		ID:        n.ID,
+		Type:      n.Type.String(),
+		Timestamp: n.Timestamp.Unix(),
+		Payload:   json.RawMessage(n.Payload),
+	})
+	if err != nil {
+		// The payload was encoded by notify, so this can't happen
+		// short of database corruption, in which case retrying won't
+		// help.
+		ltndLog.Errorf("unable to encode notification #%v: %v", n.ID,
+			err)
+		return true
+	}
+
+	backoff := minWebhookBackoff
+	for {
+		resp, err := client.Post(webhook, "application/json",
+			bytes.NewReader(body))
+		if err == nil {
+			resp.Body.Close()
+			if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+				return true
+			}
+			err = fmt.Errorf("status %v", resp.Status)
+		}
+
+		ltndLog.Warnf("Unable to deliver notification #%v to %v, "+
+			"retrying in %v: %v", n.ID, webhook, backoff, err)
+
+		select {
+		case <-time.After(backoff):
+		case <-o.quit:
+			return false
+		}
+
+		backoff *= 2
+		if backoff > maxWebhookBackoff {
+			backoff = maxWebhookBackoff
+		}
+	}
+}
+
+// validateStreamSubscriber checks that the passed name may be used by a
+// client of the SubscribeOutbox RPC.
+func validateStreamSubscriber(name string) error {
+	switch {
+	case name == "":
+		return fmt.Errorf("subscriber name must be specified")
+	case strings.HasPrefix(name, webhookSubscriberPrefix):
+		return fmt.Errorf("subscriber names beginning with %q are "+
+			"reserved for webhooks", webhookSubscriberPrefix)
+	}
+
+	return nil
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+)
+
+func TestOutboxWebhookDelivery(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "outbox")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	db, err := channeldb.Open(tempDir)
+	if err != nil {
+		t.Fatalf("unable to open db: %v", err)
+	}
+	defer db.Close()
+
+	// The webhook rejects the first delivery, so the notification should
+	// be retried.
+	var (
+		mtx        sync.Mutex
+		attempts   int
+		deliveries = make(chan *outboxDelivery, 10)
+	)
+	webhook := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, req *http.Request) {
+			mtx.Lock()
+			attempts++
+			reject := attempts == 1
+			mtx.Unlock()
+
+			if reject {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+
+			var delivery outboxDelivery
+			err := json.NewDecoder(req.Body).Decode(&delivery)
+			if err != nil {
+				t.Errorf("unable to decode delivery: %v", err)
+			}
+			deliveries <- &delivery
+		},
+	))
+	defer webhook.Close()
+
+	// A webhook which is no longer configured should be unregistered on
+	// startup, so its notifications aren't retained forever.
+	staleName := webhookSubscriberPrefix + "http://localhost:1"
+	if _, err := db.AddOutboxSubscriber(staleName); err != nil {
+		t.Fatalf("unable to add subscriber: %v", err)
+	}
+
+	outbox := newNotificationOutbox(db, []string{webhook.URL})
+	if err := outbox.Start(); err != nil {
+		t.Fatalf("unable to start outbox: %v", err)
+	}
+	defer outbox.Stop()
+
+	outbox.notify(channeldb.InvoiceSettledNotification,
+		&invoiceSettledPayload{Memo: "coffee", Value: 1000})
+
+	var delivery *outboxDelivery
+	select {
+	case delivery = <-deliveries:
+	case <-time.After(minWebhookBackoff * 5):
+		t.Fatalf("notification wasn't delivered")
+	}
+
+	var payload invoiceSettledPayload
+	if err := json.Unmarshal(delivery.Payload, &payload); err != nil {
+		t.Fatalf("unable to decode payload: %v", err)
+	}
+	if delivery.ID != 1 || delivery.Type != "InvoiceSettled" ||
+		payload.Memo != "coffee" || payload.Value != 1000 {
+
+		t.Fatalf("unexpected delivery: %v, %v", delivery, payload)
+	}
+
+	// Once accepted, the notification should have been acknowledged and
+	// pruned, as the webhook is the only subscriber.
+	var subscribers map[string]uint64
+	for i := 0; i < 50; i++ {
+		subscribers, err = db.FetchOutboxSubscribers()
+		if err != nil {
+			t.Fatalf("unable to fetch subscribers: %v", err)
+		}
+		if subscribers[webhookSubscriberPrefix+webhook.URL] == 1 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if len(subscribers) != 1 ||
+		subscribers[webhookSubscriberPrefix+webhook.URL] != 1 {
+
+		t.Fatalf("unexpected subscribers: %v", subscribers)
+	}
+	pending, err := db.FetchNotifications(0)
+	if err != nil {
+		t.Fatalf("unable to fetch notifications: %v", err)
+	}
+	if len(pending) != 0 {
+		t.Fatalf("expected no pending notifications, got %v",
+			len(pending))
+	}
+}
+
+func TestOutboxConfigValidation(t *testing.T) {
+	valid := &outboxConfig{
+		Webhooks: []string{"https://example.com/hook"},
+	}
+	if err := valid.validate(); err != nil {
+		t.Fatalf("unable to validate config: %v", err)
+	}
+
+	for _, webhook := range []string{"example.com", "ftp://example.com"} {
+		cfg := &outboxConfig{Webhooks: []string{webhook}}
+		if err := cfg.validate(); err == nil {
+			t.Fatalf("expected webhook %q to be rejected", webhook)
+		}
+	}
+
+	if err := validateStreamSubscriber("alice"); err != nil {
+		t.Fatalf("unable to validate subscriber: %v", err)
+	}
+	if err := validateStreamSubscriber(""); err == nil {
+		t.Fatalf("expected empty subscriber name to be rejected")
+	}
+	err := validateStreamSubscriber(webhookSubscriberPrefix + "alice")
+	if err == nil {
+		t.Fatalf("expected webhook subscriber name to be rejected")
+	}
+}
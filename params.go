@@ -1,6 +1,15 @@
 package main
 
-import "github.com/roasbeef/btcd/chaincfg"
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/roasbeef/btcd/chaincfg"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
+)
 
 // activeNetParams is a pointer to the parameters specific to the currently
 // active bitcoin network.
@@ -11,16 +20,187 @@ var activeNetParams = testNetParams
 type netParams struct {
 	*chaincfg.Params
 	rpcPort string
+
+	// payReqPrefix identifies the network within signed payment
+	// requests, so they can't be paid on another network.
+	payReqPrefix string
 }
 
 // testNetParams contains parameters specific to the 3rd version of the test network.
 var testNetParams = netParams{
-	Params:  &chaincfg.TestNet3Params,
-	rpcPort: "18334",
+	Params:       &chaincfg.TestNet3Params,
+	rpcPort:      "18334",
+	payReqPrefix: "tb",
 }
 
 // simNetParams contains parameters specific to the simulation test network.
 var simNetParams = netParams{
-	Params:  &chaincfg.SimNetParams,
-	rpcPort: "18556",
+	Params:       &chaincfg.SimNetParams,
+	rpcPort:      "18556",
+	payReqPrefix: "sb",
+}
+
+// sigNetParams contains parameters specific to the default public signet.
+var sigNetParams = netParams{
+	Params:       newSigNetParams(),
+	rpcPort:      "38332",
+	payReqPrefix: "tbs",
+}
+
+// newSigNetParams returns the chain parameters of the default public signet.
+// Signet shares its address encodings with testnet, but has its own genesis
+// block, network magic, and ports.
+func newSigNetParams() *chaincfg.Params {
+	params := chaincfg.TestNet3Params
+
+	// The signet genesis block is identical to the main network's genesis
+	// block, apart from its timestamp, difficulty, and nonce.
+	genesis := *chaincfg.MainNetParams.GenesisBlock
+	genesis.Header.Timestamp = time.Unix(1598918400, 0)
+	genesis.Header.Bits = 0x1e0377ae
+	genesis.Header.Nonce = 52613770
+	genesisHash := genesis.BlockHash()
+
+	params.Name = "signet"
+	params.Net = wire.BitcoinNet(0x40cf030a)
+	params.DefaultPort = "38333"
+	params.DNSSeeds = []chaincfg.DNSSeed{
+		{Host: "seed.signet.bitcoin.sprovoost.nl"},
+	}
+	params.GenesisBlock = &genesis
+	params.GenesisHash = &genesisHash
+	params.PowLimit = new(big.Int).Lsh(big.NewInt(0x0377ae), 208)
+	params.PowLimitBits = 0x1e0377ae
+	params.ReduceMinDifficulty = false
+	params.Checkpoints = nil
+
+	return &params
+}
+
+// customNetConfig houses the options which define a custom, regtest-style
+// network. Apart from the name and network magic, any option which isn't set
+// is inherited from the regression test network.
+type customNetConfig struct {
+	Name        string   `long:"name" description:"The name of the custom network, used to namespace the data and log directories"`
+	Magic       uint32   `long:"magic" description:"The network magic of the custom network, which must be unique"`
+	GenesisHash string   `long:"genesishash" description:"The hex encoded hash of the custom network's genesis block"`
+	Port        string   `long:"port" description:"The default p2p port of nodes on the custom network"`
+	RPCPort     string   `long:"rpcport" description:"The default RPC port of the btcd node on the custom network"`
+	DNSSeeds    []string `long:"dnsseed" description:"Add a DNS seed for the custom network"`
+
+	PubKeyHashAddrID        uint8 `long:"pubkeyhashaddrid" description:"The version byte of P2PKH addresses"`
+	ScriptHashAddrID        uint8 `long:"scripthashaddrid" description:"The version byte of P2SH addresses"`
+	PrivateKeyID            uint8 `long:"privatekeyid" description:"The version byte of WIF private keys"`
+	WitnessPubKeyHashAddrID uint8 `long:"witnesspubkeyhashaddrid" description:"The version byte of P2WPKH addresses"`
+	WitnessScriptHashAddrID uint8 `long:"witnessscripthashaddrid" description:"The version byte of P2WSH addresses"`
+
+	Bech32HRPSegwit string `long:"bech32hrpsegwit" description:"The human-readable part of bech32 segwit addresses, which also identifies the network within payment requests"`
+}
+
+// newCustomNetParams creates the network parameters of the custom network
+// described by the passed config, using the regression test network as a
+// base.
+func newCustomNetParams(cfg *customNetConfig) (netParams, error) {
+	params := chaincfg.RegressionNetParams
+
+	if cfg.Name == "" {
+		return netParams{}, fmt.Errorf("custom networks must be named")
+	}
+	params.Name = cfg.Name
+
+	// The network magic must be unique in order for the network to be
+	// registered, and peers on other networks to be rejected.
+	if cfg.Magic == 0 {
+		return netParams{}, fmt.Errorf("custom networks must specify " +
+			"a network magic")
+	}
+	params.Net = wire.BitcoinNet(cfg.Magic)
+
+	if cfg.GenesisHash != "" {
+		genesisHash, err := chainhash.NewHashFromStr(cfg.GenesisHash)
+		if err != nil {
+			return netParams{}, fmt.Errorf("invalid genesis "+
+				"hash: %v", err)
+		}
+
+		// As we only know the hash of the genesis block, we drop the
+		// regtest genesis block itself to ensure it isn't mistakenly
+		// used.
+		params.GenesisHash = genesisHash
+		params.GenesisBlock = nil
+	}
+	if cfg.Port != "" {
+		params.DefaultPort = cfg.Port
+	}
+	if len(cfg.DNSSeeds) != 0 {
+		params.DNSSeeds = make([]chaincfg.DNSSeed, len(cfg.DNSSeeds))
+		for i, seed := range cfg.DNSSeeds {
+			params.DNSSeeds[i] = chaincfg.DNSSeed{Host: seed}
+		}
+	}
+
+	if cfg.PubKeyHashAddrID != 0 {
+		params.PubKeyHashAddrID = cfg.PubKeyHashAddrID
+	}
+	if cfg.ScriptHashAddrID != 0 {
+		params.ScriptHashAddrID = cfg.ScriptHashAddrID
+	}
+	if cfg.PrivateKeyID != 0 {
+		params.PrivateKeyID = cfg.PrivateKeyID
+	}
+	if cfg.WitnessPubKeyHashAddrID != 0 {
+		params.WitnessPubKeyHashAddrID = cfg.WitnessPubKeyHashAddrID
+	}
+	if cfg.WitnessScriptHashAddrID != 0 {
+		params.WitnessScriptHashAddrID = cfg.WitnessScriptHashAddrID
+	}
+	if cfg.Bech32HRPSegwit != "" {
+		// Bech32 strings are either all lowercase or all uppercase,
+		// and we encode them in lowercase.
+		if cfg.Bech32HRPSegwit != strings.ToLower(cfg.Bech32HRPSegwit) {
+			return netParams{}, fmt.Errorf("bech32 human-readable " +
+				"part must be lowercase")
+		}
+		params.Bech32HRPSegwit = cfg.Bech32HRPSegwit
+	}
+
+	rpcPort := "18334"
+	if cfg.RPCPort != "" {
+		rpcPort = cfg.RPCPort
+	}
+
+	return netParams{
+		Params:       &params,
+		rpcPort:      rpcPort,
+		payReqPrefix: params.Bech32HRPSegwit,
+	}, nil
+}
+
+// registerNetParams registers the passed network parameters with chaincfg,
+// which is required in order for addresses on the network to be decoded.
+// Networks built into chaincfg are already registered.
+func registerNetParams(params netParams) error {
+	switch params.Params {
+	case &chaincfg.MainNetParams, &chaincfg.TestNet3Params,
+		&chaincfg.RegressionNetParams, &chaincfg.SimNetParams:
+		return nil
+	}
+
+	return chaincfg.Register(params.Params)
+}
+
+// hwiChainName returns the name by which HWI-compatible hardware wallet
+// bridges refer to the passed network. Networks unknown to such bridges are
+// treated as regtest, as they share its address encodings.
+func hwiChainName(params netParams) string {
+	switch params.Params {
+	case &chaincfg.MainNetParams:
+		return "main"
+	case &chaincfg.TestNet3Params:
+		return "test"
+	case sigNetParams.Params:
+		return "signet"
+	default:
+		return "regtest"
+	}
 }
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// claimPaymentID claims the client chosen payment identifier for a payment
+// with the passed payment hash before the payment is dispatched. A client
+// which crashes, or loses its connection, while a payment is dispatched may
+// then retry the payment under the same identifier without risking paying
+// twice:
+//  * if the payment previously succeeded, then its original response is
+//    returned, and the payment mustn't be dispatched again.
+//  * if the payment is still in flight, or its outcome is unknown as we
+//    restarted while it was dispatched, then an error is returned.
+//
+// Otherwise, the identifier is claimed, and nil is returned.
+func (r *rpcServer) claimPaymentID(id uint64,
+	paymentHash [32]byte) (*lnrpc.SendResponse, error) {
+
+	existing, err := r.server.chanDB.AddPaymentID(id, paymentHash)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		return nil, nil
+	}
+
+	if existing.PaymentHash != paymentHash {
+		return nil, fmt.Errorf("payment id %v was used for payment "+
+			"hash %x", id, existing.PaymentHash[:])
+	}
+
+	if existing.Status == channeldb.PaymentIDSucceeded {
+		rpcsLog.Infof("Replay of succeeded payment id %v ignored", id)
+		return &lnrpc.SendResponse{
+			PaymentPreimage: existing.Preimage[:],
+		}, nil
+	}
+
+	// The HTLCs of each channel are persisted along with its state, so an
+	// HTLC of the payment which is locked in survives a restart.
+	chanPoint, err := r.outgoingHTLCChannel(paymentHash)
+	if err != nil {
+		return nil, err
+	}
+	if chanPoint != nil {
+		return nil, fmt.Errorf("payment id %v is in flight over "+
+			"ChannelPoint(%v)", id, chanPoint)
+	}
+
+	return nil, fmt.Errorf("payment id %v was dispatched, but its "+
+		"outcome is unknown", id)
+}
+
+// resolvePaymentID records the outcome of the payment dispatched under the
+// passed payment identifier. If the payment failed, and no HTLC of it remains
+// within our channels, then the identifier is released so the payment may be
+// retried under it.
+func (r *rpcServer) resolvePaymentID(id uint64, paymentHash,
+	preimage [32]byte, payErr error) {
+
+	if payErr == nil {
+		if err := r.server.chanDB.SettlePaymentID(id, preimage); err != nil {
+			rpcsLog.Errorf("unable to record success of payment "+
+				"id %v: %v", id, err)
+		}
+		return
+	}
+
+	chanPoint, err := r.outgoingHTLCChannel(paymentHash)
+	if err != nil {
+		rpcsLog.Errorf("unable to check HTLCs of payment id %v: %v",
+			id, err)
+		return
+	}
+	if chanPoint != nil {
+		rpcsLog.Warnf("Payment id %v failed with %v, but remains in "+
+			"flight over ChannelPoint(%v)", id, payErr, chanPoint)
+		return
+	}
+
+	if err := r.server.chanDB.DeletePaymentID(id); err != nil {
+		rpcsLog.Errorf("unable to release payment id %v: %v", id, err)
+	}
+}
+
+// outgoingHTLCChannel returns the channel which carries an outgoing HTLC with
+// the passed payment hash, or nil if there's no such HTLC.
+func (r *rpcServer) outgoingHTLCChannel(
+	paymentHash [32]byte) (*wire.OutPoint, error) {
+
+	channels, err := r.server.chanDB.FetchAllChannels()
+	if err != nil {
+		return nil, err
+	}
+	for _, channel := range channels {
+		for _, htlc := range channel.Htlcs {
+			if !htlc.Incoming && htlc.RHash == paymentHash {
+				return channel.ChanID, nil
+			}
+		}
+	}
+
+	return nil, nil
+}
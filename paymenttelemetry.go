@@ -0,0 +1,152 @@
+package main
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/routing"
+)
+
+// paymentTelemetryWindow is the number of recent payments the statistics
+// reported by the payment telemetry are computed over. Bounding the window
+// bounds the memory used, and lets the statistics reflect changes to our fees
+// or channels rather than being dominated by the node's entire history.
+const paymentTelemetryWindow = 1000
+
+// paymentTelemetry aggregates the anonymized outcomes of the payments we send,
+// allowing the operator to quantify the quality of the routes available to
+// us, such as before and after adjusting fees or channels. Only the success,
+// latency, number of attempts and failure code of each payment are retained,
+// so no payment can be identified from the aggregate.
+type paymentTelemetry struct {
+	mtx sync.Mutex
+
+	// outcomes is a ring buffer holding the outcomes of the most recent
+	// payments, with next being the index the next outcome is written
+	// to.
+	outcomes []routing.PaymentOutcome
+	next     int
+
+	// totalPayments and totalSucceeded count the payments sent since
+	// start up, beyond those held within the window.
+	totalPayments  uint64
+	totalSucceeded uint64
+}
+
+// newPaymentTelemetry creates a new paymentTelemetry with an empty window.
+func newPaymentTelemetry() *paymentTelemetry {
+	return &paymentTelemetry{
+		outcomes: make([]routing.PaymentOutcome, 0,
+			paymentTelemetryWindow),
+	}
+}
+
+// record adds the outcome of a payment to the telemetry, evicting the oldest
+// outcome within the window if it's full.
+//
+// NOTE: This is called by the router as payments complete.
+func (t *paymentTelemetry) record(outcome *routing.PaymentOutcome) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	if len(t.outcomes) < paymentTelemetryWindow {
+		t.outcomes = append(t.outcomes, *outcome)
+	} else {
+		t.outcomes[t.next] = *outcome
+	}
+	t.next = (t.next + 1) % paymentTelemetryWindow
+
+	t.totalPayments++
+	if outcome.Succeeded {
+		t.totalSucceeded++
+	}
+}
+
+// report computes the statistics of the payments within the window.
+func (t *paymentTelemetry) report() *lnrpc.PaymentTelemetryResponse {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	resp := &lnrpc.PaymentTelemetryResponse{
+		NumPayments:    uint64(len(t.outcomes)),
+		TotalPayments:  t.totalPayments,
+		TotalSucceeded: t.totalSucceeded,
+	}
+	if len(t.outcomes) == 0 {
+		return resp
+	}
+
+	var (
+		attempts  uint64
+		latencies []time.Duration
+		failures  = make(map[string]uint64)
+	)
+	for _, outcome := range t.outcomes {
+		attempts += uint64(outcome.Attempts)
+
+		if !outcome.Succeeded {
+			failures[outcome.FailureCode]++
+			continue
+		}
+
+		resp.NumSucceeded++
+		latencies = append(latencies, outcome.Latency)
+	}
+
+	resp.SuccessRate = float64(resp.NumSucceeded) / float64(len(t.outcomes))
+	resp.AvgAttempts = float64(attempts) / float64(len(t.outcomes))
+
+	sort.Sort(durations(latencies))
+	resp.LatencyP50Ms = latencyPercentile(latencies, 50)
+	resp.LatencyP90Ms = latencyPercentile(latencies, 90)
+	resp.LatencyP99Ms = latencyPercentile(latencies, 99)
+
+	for code, count := range failures {
+		resp.FailureCodes = append(resp.FailureCodes,
+			&lnrpc.FailureCodeCount{
+				Code:  code,
+				Count: count,
+			})
+	}
+	sort.Sort(failureCodesByCount(resp.FailureCodes))
+
+	return resp
+}
+
+// latencyPercentile returns the passed percentile, in milliseconds, of the
+// passed sorted latencies using the nearest-rank method. Zero is returned if
+// there are no latencies.
+func latencyPercentile(sorted []time.Duration, percentile int) int64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := (percentile*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+
+	return int64(sorted[rank-1] / time.Millisecond)
+}
+
+// durations sorts durations in ascending order.
+type durations []time.Duration
+
+func (d durations) Len() int           { return len(d) }
+func (d durations) Swap(i, j int)      { d[i], d[j] = d[j], d[i] }
+func (d durations) Less(i, j int) bool { return d[i] < d[j] }
+
+// failureCodesByCount sorts failure codes from the most to the least common,
+// breaking ties by code so the order is stable.
+type failureCodesByCount []*lnrpc.FailureCodeCount
+
+func (f failureCodesByCount) Len() int      { return len(f) }
+func (f failureCodesByCount) Swap(i, j int) { f[i], f[j] = f[j], f[i] }
+func (f failureCodesByCount) Less(i, j int) bool {
+	if f[i].Count != f[j].Count {
+		return f[i].Count > f[j].Count
+	}
+	return f[i].Code < f[j].Code
+}
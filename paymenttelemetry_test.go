@@ -0,0 +1,71 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/lightningnetwork/lnd/routing"
+)
+
+// TestPaymentTelemetry tests that the statistics reported by the payment
+// telemetry reflect the outcomes within its window, while the totals count
+// every payment since start up.
+func TestPaymentTelemetry(t *testing.T) {
+	telemetry := newPaymentTelemetry()
+
+	report := telemetry.report()
+	if report.NumPayments != 0 || report.SuccessRate != 0 {
+		t.Fatalf("expected empty report, got %v", report)
+	}
+
+	// Fill the window with failures, all of which should be evicted by
+	// the payments recorded below.
+	for i := 0; i < paymentTelemetryWindow; i++ {
+		telemetry.record(&routing.PaymentOutcome{
+			Attempts:    1,
+			FailureCode: "Stale",
+		})
+	}
+
+	// Record 100 successful payments with latencies of 1 to 100ms, each
+	// made in two attempts, followed by 900 failed payments, each made in
+	// three attempts.
+	for i := 1; i <= 100; i++ {
+		telemetry.record(&routing.PaymentOutcome{
+			Succeeded: true,
+			Latency:   time.Duration(i) * time.Millisecond,
+			Attempts:  2,
+		})
+	}
+	failures := []string{"NoPathFound", "UnknownPaymentHash",
+		"NoPathFound", "PaymentTimeout"}
+	for i := 0; i < paymentTelemetryWindow-100; i++ {
+		telemetry.record(&routing.PaymentOutcome{
+			Attempts:    3,
+			FailureCode: failures[i%len(failures)],
+		})
+	}
+
+	report = telemetry.report()
+	expected := &lnrpc.PaymentTelemetryResponse{
+		NumPayments:  paymentTelemetryWindow,
+		NumSucceeded: 100,
+		SuccessRate:  0.1,
+		AvgAttempts:  2.9,
+		LatencyP50Ms: 50,
+		LatencyP90Ms: 90,
+		LatencyP99Ms: 99,
+		FailureCodes: []*lnrpc.FailureCodeCount{
+			{Code: "NoPathFound", Count: 450},
+			{Code: "PaymentTimeout", Count: 225},
+			{Code: "UnknownPaymentHash", Count: 225},
+		},
+		TotalPayments:  2 * paymentTelemetryWindow,
+		TotalSucceeded: 100,
+	}
+	if !reflect.DeepEqual(report, expected) {
+		t.Fatalf("expected %v, got %v", expected, report)
+	}
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/davecgh/go-spew/spew"
 	"github.com/go-errors/errors"
 	"github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lnwallet"
@@ -35,6 +36,10 @@ const (
 	// pingInterval is the interval at which ping messages are sent.
 	pingInterval = 1 * time.Minute
 
+	// stateCheckInterval is the interval at which the in-memory state of
+	// each active channel is cross-checked against its persisted state.
+	stateCheckInterval = 1 * time.Minute
+
 	// outgoingQueueLen is the buffer size of the channel which houses
 	// messages to be sent across the wire, requested by objects outside
 	// this struct.
@@ -127,6 +132,11 @@ type peer struct {
 	htlcManMtx   sync.RWMutex
 	htlcManagers map[wire.OutPoint]chan lnwire.Message
 
+	// shutdownLinks maps each active channel to the channel over which
+	// local requests to cooperatively close it are sent to its
+	// htlcManager. It's guarded by htlcManMtx.
+	shutdownLinks map[wire.OutPoint]chan *closeLinkReq
+
 	// newChannels is used by the fundingManager to send fully opened
 	// channels to the source peer which handled the funding workflow.
 	newChannels chan *newChannelMsg
@@ -135,10 +145,35 @@ type peer struct {
 	// a particular channel are sent over.
 	localCloseChanReqs chan *closeLinkReq
 
-	// remoteCloseChanReqs is a channel in which any remote requests
-	// (initiated by the remote peer) close a particular channel are sent
-	// over.
-	remoteCloseChanReqs chan *lnwire.CloseRequest
+	// remoteCloseChanReqs is a channel in which any remote messages
+	// (sent by the remote peer) proposing or signing a closure
+	// transaction of a particular channel are sent over.
+	remoteCloseChanReqs chan lnwire.Message
+
+	// closingChannels houses the channels which are being cooperatively
+	// closed, whose closure transactions have yet to confirm. They're
+	// retained in order to watch each version of the closure transaction
+	// signed by either party until one of them confirms.
+	closingChanMtx  sync.Mutex
+	closingChannels map[wire.OutPoint]*closingChannel
+
+	// localSpliceReqs is a channel in which any local requests to splice
+	// additional funds into a particular channel are sent over.
+	localSpliceReqs chan *spliceReq
+
+	// remoteSpliceMsgs is a channel in which any remote messages
+	// negotiating the splice of a particular channel are sent over.
+	remoteSpliceMsgs chan lnwire.Message
+
+	// expiredSplices is sent upon once the remote peer has been given
+	// spliceTimeout to complete its side of a splice.
+	expiredSplices chan *pendingSplice
+
+	// pendingSplices houses the splices being negotiated with the remote
+	// peer, which have yet to be broadcast.
+	//
+	// NOTE: This map MUST only be accessed by the channelManager.
+	pendingSplices map[wire.OutPoint]*pendingSplice
 
 	// nextPendingChannelID is an integer which represents the id of the
 	// next pending channel. Pending channels are tracked by this id
@@ -192,11 +227,18 @@ func newPeer(conn net.Conn, connReq *connmgr.ConnReq, server *server,
 
 		activeChannels:   make(map[wire.OutPoint]*lnwallet.LightningChannel),
 		htlcManagers:     make(map[wire.OutPoint]chan lnwire.Message),
+		shutdownLinks:    make(map[wire.OutPoint]chan *closeLinkReq),
 		chanSnapshotReqs: make(chan *chanSnapshotReq),
 		newChannels:      make(chan *newChannelMsg, 1),
 
 		localCloseChanReqs:  make(chan *closeLinkReq),
-		remoteCloseChanReqs: make(chan *lnwire.CloseRequest),
+		remoteCloseChanReqs: make(chan lnwire.Message),
+		closingChannels:     make(map[wire.OutPoint]*closingChannel),
+
+		localSpliceReqs:  make(chan *spliceReq),
+		remoteSpliceMsgs: make(chan lnwire.Message),
+		expiredSplices:   make(chan *pendingSplice),
+		pendingSplices:   make(map[wire.OutPoint]*pendingSplice),
 
 		localSharedFeatures:  nil,
 		globalSharedFeatures: nil,
@@ -214,6 +256,15 @@ func newPeer(conn net.Conn, connReq *connmgr.ConnReq, server *server,
 		p.nextPendingChannelID = 0
 	}
 
+	// If the peer previously announced that it would rotate its identity
+	// to the one it's connecting with, then our channels with it are
+	// carried over before they're loaded.
+	if err := server.applyIdentityRotation(nodePub); err != nil {
+		peerLog.Errorf("unable to apply identity rotation of "+
+			"peer %v: %v", p, err)
+		return nil, err
+	}
+
 	// Fetch and then load all the active channels we have with this
 	// remote peer from the database.
 	activeChans, err := server.chanDB.FetchOpenChannels(p.addr.IdentityKey)
@@ -266,12 +317,24 @@ func (p *peer) loadActiveChannels(chans []*channeldb.OpenChannel) error {
 			dbChan.Snapshot(), downstreamLink)
 
 		upstreamLink := make(chan lnwire.Message, 10)
+		shutdownLink := make(chan *closeLinkReq, 1)
 		p.htlcManMtx.Lock()
 		p.htlcManagers[chanPoint] = upstreamLink
+		p.shutdownLinks[chanPoint] = shutdownLink
 		p.htlcManMtx.Unlock()
 
 		p.wg.Add(1)
-		go p.htlcManager(lnChan, plexChan, downstreamLink, upstreamLink)
+		go p.htlcManager(lnChan, plexChan, downstreamLink, upstreamLink,
+			shutdownLink)
+
+		// If the channel has a pending splice, then we'll resume
+		// watching for its transaction to confirm.
+		p.resumeSplice(lnChan)
+
+		// Likewise, if the channel is being cooperatively closed, then
+		// we'll resume watching each version of its closure
+		// transaction.
+		p.resumeCooperativeClose(lnChan)
 	}
 
 	return nil
@@ -317,6 +380,26 @@ func (p *peer) Start() error {
 	go p.channelManager()
 	go p.pingHandler()
 
+	// With the init messages exchanged, we can swap backups with the
+	// peer if it supports peer storage.
+	p.server.peerStorage.peerConnected(p)
+
+	// Any channels with the peer we've restored from a static backup are
+	// recovered by asking the peer to force close them.
+	p.server.chanRecovery.peerConnected(p)
+
+	// We'll also send the peer our view of each channel we share, so
+	// either of us can detect that it's lost its state for the channel.
+	p.sendChanSyncMsgs()
+
+	// If the peer holds HTLCs on our behalf while we're offline, then we
+	// let it know we're back to claim them.
+	if p.localSharedFeatures.IsActive(asyncPaymentsFeature) &&
+		cfg.Async.isHolder(p.addr.IdentityKey) {
+
+		p.queueMsg(&lnwire.ReleaseHeldHTLCs{}, nil)
+	}
+
 	return nil
 }
 
@@ -364,6 +447,14 @@ func (p *peer) Disconnect() {
 
 	// Launch a goroutine to clean up the remaining resources.
 	go func() {
+		// Record the disconnection within the timeline of each of the
+		// channels which are now inactive.
+		p.activeChanMtx.RLock()
+		for _, channel := range p.activeChannels {
+			p.server.chanHistory.peerDisconnected(channel)
+		}
+		p.activeChanMtx.RUnlock()
+
 		// Tell the switch to unregister all links associated with this
 		// peer. Passing nil as the target link indicates that all
 		// links associated with this interface should be closed.
@@ -437,6 +528,8 @@ out:
 			pingSendTime := atomic.LoadInt64(&p.pingLastSend)
 			delay := (time.Now().UnixNano() - pingSendTime) / 1000
 			atomic.StoreInt64(&p.pingTime, delay)
+			p.server.peerScores.pingMeasured(p.addr.IdentityKey,
+				time.Duration(delay)*time.Microsecond)
 
 		case *lnwire.Ping:
 			p.queueMsg(lnwire.NewPong(msg.Nonce), nil)
@@ -449,14 +542,75 @@ out:
 			p.server.fundingMgr.processFundingComplete(msg, p.addr)
 		case *lnwire.SingleFundingSignComplete:
 			p.server.fundingMgr.processFundingSignComplete(msg, p.addr)
+		case *lnwire.DualFundingRequest:
+			p.server.fundingMgr.processDualFundingRequest(msg, p.addr)
+		case *lnwire.DualFundingResponse:
+			p.server.fundingMgr.processDualFundingResponse(msg, p.addr)
+		case *lnwire.DualFundingComplete:
+			p.server.fundingMgr.processDualFundingComplete(msg, p.addr)
+		case *lnwire.DualFundingSignComplete:
+			p.server.fundingMgr.processDualFundingSignComplete(msg, p.addr)
 		case *lnwire.FundingLocked:
 			p.server.fundingMgr.processFundingLocked(msg, p.addr)
 		case *lnwire.CloseRequest:
 			p.remoteCloseChanReqs <- msg
+		case *lnwire.CloseComplete:
+			p.remoteCloseChanReqs <- msg
+		case *lnwire.ChannelReestablish:
+			p.handleChanSyncMsg(msg)
+		case *lnwire.ForceCloseRequest:
+			go p.server.chanRecovery.processForceCloseRequest(p, msg)
+
+		case *lnwire.SpliceRequest, *lnwire.SpliceAccept,
+			*lnwire.SpliceSigned, *lnwire.SpliceComplete:
+			p.remoteSpliceMsgs <- msg
 
 		case *lnwire.ErrorGeneric:
+			// The peer found us incompatible during the init
+			// handshake, and is about to disconnect.
+			if msg.Code == lnwire.ErrIncompatiblePeer {
+				peerLog.Errorf("Peer %v refused connection as "+
+					"incompatible: %v", p, msg.Problem)
+				break out
+			}
+
 			p.server.fundingMgr.processErrorGeneric(msg, p.addr)
 
+		case *lnwire.InvoiceRequest:
+			p.server.offers.handleInvoiceRequest(p.addr.IdentityKey,
+				msg, func(reply lnwire.Message) {
+					p.queueMsg(reply, nil)
+				})
+		case *lnwire.InvoiceReply:
+			p.server.offers.processInvoiceReply(msg)
+		case *lnwire.OnionMessage:
+			if p.localSharedFeatures.IsActive(onionMessagesFeature) {
+				p.server.onionMessages.handleOnionMessage(p, msg)
+			}
+
+		case *lnwire.PeerStorage:
+			p.server.peerStorage.processPeerStorage(p, msg)
+		case *lnwire.PeerStorageRetrieval:
+			p.server.peerStorage.processRetrieval(p, msg)
+
+		case *lnwire.ReleaseHeldHTLCs:
+			if p.localSharedFeatures.IsActive(asyncPaymentsFeature) {
+				p.server.asyncPayments.releaseHeld(
+					p.addr.IdentityKey)
+			}
+
+		case *lnwire.IdentityRotation:
+			p.server.processIdentityRotation(p, msg)
+
+		case *lnwire.CreateSession:
+			p.server.watchtower.processCreateSession(p, msg)
+		case *lnwire.StateUpdate:
+			p.server.watchtower.processStateUpdate(p, msg)
+
+		case *lnwire.CustomMessage:
+			p.server.customMessages.processCustomMessage(
+				p.addr.IdentityKey, p.localSharedFeatures, msg)
+
 		// TODO(roasbeef): create ChanUpdater interface for the below
 		case *lnwire.UpdateAddHTLC:
 			isChanUpdate = true
@@ -467,12 +621,18 @@ out:
 		case *lnwire.UpdateFailHTLC:
 			isChanUpdate = true
 			targetChan = msg.ChannelPoint
+		case *lnwire.UpdateFee:
+			isChanUpdate = true
+			targetChan = msg.ChannelPoint
 		case *lnwire.RevokeAndAck:
 			isChanUpdate = true
 			targetChan = msg.ChannelPoint
 		case *lnwire.CommitSig:
 			isChanUpdate = true
 			targetChan = msg.ChannelPoint
+		case *lnwire.Shutdown:
+			isChanUpdate = true
+			targetChan = msg.ChannelPoint
 
 		case *lnwire.NodeAnnouncement,
 			*lnwire.ChannelAnnouncement,
@@ -529,6 +689,15 @@ func (p *peer) logWireMessage(msg lnwire.Message, read bool) {
 		m.ChannelDerivationPoint.Curve = nil
 		m.CommitmentKey.Curve = nil
 		m.RevocationKey.Curve = nil
+	case *lnwire.DualFundingRequest:
+		m.CommitmentKey.Curve = nil
+		m.ChannelDerivationPoint.Curve = nil
+	case *lnwire.DualFundingResponse:
+		m.ChannelDerivationPoint.Curve = nil
+		m.CommitmentKey.Curve = nil
+		m.RevocationKey.Curve = nil
+	case *lnwire.DualFundingComplete:
+		m.RevocationKey.Curve = nil
 	case *lnwire.FundingLocked:
 		m.NextPerCommitmentPoint.Curve = nil
 	}
@@ -738,6 +907,7 @@ out:
 
 			peerLog.Infof("New channel active ChannelPoint(%v) "+
 				"with peerId(%v)", chanPoint, p.id)
+			p.server.chanHistory.channelOpened(newChanReq.channel)
 
 			// Now that the channel is open, notify the Htlc
 			// Switch of a new active link.
@@ -750,20 +920,54 @@ out:
 			// a goroutine to handle commitment updates for this
 			// new channel.
 			upstreamLink := make(chan lnwire.Message, 10)
+			shutdownLink := make(chan *closeLinkReq, 1)
 			p.htlcManMtx.Lock()
 			p.htlcManagers[chanPoint] = upstreamLink
+			p.shutdownLinks[chanPoint] = shutdownLink
 			p.htlcManMtx.Unlock()
 
 			p.wg.Add(1)
-			go p.htlcManager(newChanReq.channel, plexChan, downstreamLink, upstreamLink)
+			go p.htlcManager(newChanReq.channel, plexChan, downstreamLink,
+				upstreamLink, shutdownLink)
 
 			close(newChanReq.done)
 
+			// The peer's backup of our state should now include
+			// the new channel.
+			p.server.peerStorage.sendBackup(p)
+
 		case req := <-p.localCloseChanReqs:
 			p.handleLocalClose(req)
 
-		case req := <-p.remoteCloseChanReqs:
-			p.handleRemoteClose(req)
+		case msg := <-p.remoteCloseChanReqs:
+			switch msg := msg.(type) {
+			case *lnwire.CloseRequest:
+				p.handleRemoteClose(msg)
+			case *lnwire.CloseComplete:
+				p.handleCloseComplete(msg)
+			}
+
+		case req := <-p.localSpliceReqs:
+			p.handleLocalSplice(req)
+
+		case msg := <-p.remoteSpliceMsgs:
+			switch msg := msg.(type) {
+			case *lnwire.SpliceRequest:
+				p.handleSpliceRequest(msg)
+			case *lnwire.SpliceAccept:
+				p.handleSpliceAccept(msg)
+			case *lnwire.SpliceSigned:
+				p.handleSpliceSigned(msg)
+			case *lnwire.SpliceComplete:
+				p.handleSpliceComplete(msg)
+			}
+
+		case pending := <-p.expiredSplices:
+			chanPoint := *pending.channel.ChannelPoint()
+			if p.pendingSplices[chanPoint] == pending && !pending.signed {
+				p.abandonSplice(pending, fmt.Errorf("splice "+
+					"timed out"))
+			}
 
 		case <-p.quit:
 			break out
@@ -775,22 +979,30 @@ out:
 
 // executeCooperativeClose executes the initial phase of a user-executed
 // cooperative channel close. The channel state machine is transitioned to the
-// closing phase, then our half of the closing witness is sent over to the
-// remote peer.
-func (p *peer) executeCooperativeClose(channel *lnwallet.LightningChannel) (*chainhash.Hash, error) {
+// closing phase, then our half of the closing witness for our own version of
+// the closing transaction, which pays the passed fee, is sent over to the
+// remote peer. If the fee is zero, then the default fee is paid.
+func (p *peer) executeCooperativeClose(channel *lnwallet.LightningChannel,
+	fee btcutil.Amount) (*chainhash.Hash, error) {
+
+	if fee == 0 {
+		fee = lnwallet.DefaultCoopCloseFee
+	}
+
 	// Shift the channel state machine into a 'closing' state. This
-	// generates a signature for the closing tx, as well as a txid of the
-	// closing tx itself, allowing us to watch the network to determine
-	// when the remote node broadcasts the fully signed closing
-	// transaction.
-	sig, txid, err := channel.InitCooperativeClose()
+	// generates a signature for our version of the closing tx, as well as
+	// its txid, allowing us to watch the network to determine when the
+	// closing transaction confirms. Once the remote peer replies with its
+	// signature, we'll broadcast the fully signed closing transaction.
+	sig, txid, err := channel.InitCooperativeClose(fee)
 	if err != nil {
 		return nil, err
 	}
 
 	chanPoint := channel.ChannelPoint()
 	peerLog.Infof("Executing cooperative closure of "+
-		"ChanPoint(%v) with peerID(%v), txid=%v", chanPoint, p.id, txid)
+		"ChanPoint(%v) with peerID(%v) paying fee of %v, txid=%v",
+		chanPoint, p.id, fee, txid)
 
 	// With our signature for the close tx generated, send the signature to
 	// the remote peer instructing it to close this particular channel
@@ -800,7 +1012,34 @@ func (p *peer) executeCooperativeClose(channel *lnwallet.LightningChannel) (*cha
 	if err != nil {
 		return nil, err
 	}
-	closeReq := lnwire.NewCloseRequest(*chanPoint, closeSig)
+	closeReq := lnwire.NewCloseRequest(*chanPoint, closeSig, fee)
+	p.queueMsg(closeReq, nil)
+
+	return txid, nil
+}
+
+// bumpCooperativeClose replaces our own version of the closure transaction of
+// a channel we're cooperatively closing with one paying the passed fee. Our
+// signature for the replacement is sent to the remote peer, which will reply
+// with its own signature, allowing us to broadcast the replacement.
+func (p *peer) bumpCooperativeClose(channel *lnwallet.LightningChannel,
+	fee btcutil.Amount) (*chainhash.Hash, error) {
+
+	sig, txid, err := channel.BumpCooperativeClose(fee)
+	if err != nil {
+		return nil, err
+	}
+
+	chanPoint := channel.ChannelPoint()
+	peerLog.Infof("Bumping fee of cooperative closure of "+
+		"ChanPoint(%v) with peerID(%v) to %v, txid=%v", chanPoint,
+		p.id, fee, txid)
+
+	closeSig, err := btcec.ParseSignature(sig, btcec.S256())
+	if err != nil {
+		return nil, err
+	}
+	closeReq := lnwire.NewCloseRequest(*chanPoint, closeSig, fee)
 	p.queueMsg(closeReq, nil)
 
 	return txid, nil
@@ -811,42 +1050,121 @@ func (p *peer) executeCooperativeClose(channel *lnwallet.LightningChannel) (*cha
 // TODO(roasbeef): if no more active channels with peer call Remove on connMgr
 // with peerID
 func (p *peer) handleLocalClose(req *closeLinkReq) {
-	var (
-		err         error
-		closingTxid *chainhash.Hash
-	)
-
 	p.activeChanMtx.RLock()
 	channel := p.activeChannels[*req.chanPoint]
 	p.activeChanMtx.RUnlock()
 
 	switch req.CloseType {
 	// A type of CloseRegular indicates that the user has opted to close
-	// out this channel on-chian, so we execute the cooperative channel
-	// closure workflow.
+	// out this channel on-chian, so we hand the request to the channel's
+	// htlcManager. It shuts the channel down, and only executes the
+	// cooperative channel closure workflow once all HTLCs in flight have
+	// been resolved.
 	case CloseRegular:
-		closingTxid, err = p.executeCooperativeClose(channel)
-		peerLog.Infof("Attempting cooperative close of "+
-			"ChannelPoint(%v) with txid: %v", req.chanPoint,
-			closingTxid)
+		p.htlcManMtx.RLock()
+		shutdownLink, ok := p.shutdownLinks[*req.chanPoint]
+		p.htlcManMtx.RUnlock()
+		if !ok {
+			req.err <- fmt.Errorf("channel %v isn't active",
+				req.chanPoint)
+			return
+		}
+
+		select {
+		case shutdownLink <- req:
+		case <-p.quit:
+		}
 
 	// A type of CloseBreach indicates that the counterparty has breached
 	// the channel therefore we need to clean up our local state.
 	case CloseBreach:
 		peerLog.Infof("ChannelPoint(%v) has been breached, wiping "+
 			"channel", req.chanPoint)
+		p.server.chanHistory.closeInitiated(channel, "breach, remote")
+		p.server.stateLog.record(channel, channeldb.CloseDetectedEvent,
+			"breach, remote")
 		if err := wipeChannel(p, channel); err != nil {
 			peerLog.Infof("Unable to wipe channel after detected "+
 				"breach: %v", err)
 			req.err <- err
 			return
 		}
+	}
+}
+
+// handleLocalShutdown shuts down the channel in response to a local request
+// to cooperatively close it. The remote peer is sent a Shutdown, after which
+// no new HTLCs are offered over the channel, and the closure is deferred
+// until the peer has replied with its own Shutdown, and all HTLCs in flight
+// have been resolved.
+func (p *peer) handleLocalShutdown(state *commitmentState, req *closeLinkReq) {
+	if state.closeReq != nil {
+		req.err <- lnwallet.ErrChanClosing
+		return
+	}
+	if err := state.channel.InitShutdown(); err != nil {
+		req.err <- err
+		return
+	}
+	state.closeReq = req
+
+	if !state.shutdownSent {
+		peerLog.Infof("Shutting down ChannelPoint(%v) with peerID(%v)",
+			state.chanPoint, p.id)
+
+		p.queueMsg(lnwire.NewShutdown(*state.chanPoint), nil)
+		state.shutdownSent = true
+	}
+
+	p.closeOnQuiescence(state)
+}
+
+// handleRemoteShutdown records the remote peer's intent to cooperatively
+// close the channel, replying with our own Shutdown if we haven't yet sent
+// one. The remote peer proceeds with the closure once the channel is
+// quiescent.
+func (p *peer) handleRemoteShutdown(state *commitmentState) {
+	if err := state.channel.ReceiveShutdown(); err != nil {
+		peerLog.Errorf("unable to shut down ChannelPoint(%v): %v",
+			state.chanPoint, err)
+		return
+	}
+
+	if !state.shutdownSent {
+		if err := state.channel.InitShutdown(); err != nil {
+			peerLog.Errorf("unable to shut down ChannelPoint(%v): "+
+				"%v", state.chanPoint, err)
+			return
+		}
+
+		peerLog.Infof("Shutting down ChannelPoint(%v) at the request "+
+			"of peerID(%v)", state.chanPoint, p.id)
+
+		p.queueMsg(lnwire.NewShutdown(*state.chanPoint), nil)
+		state.shutdownSent = true
+	}
+
+	p.closeOnQuiescence(state)
+}
+
+// closeOnQuiescence executes the cooperative closure of the channel requested
+// by a local subsystem once both parties have sent a Shutdown, and all HTLCs
+// in flight over the channel have been resolved. If there's no such request,
+// or the channel has yet to quiesce, then this is a noop.
+func (p *peer) closeOnQuiescence(state *commitmentState) {
+	req := state.closeReq
+	if req == nil || !state.channel.ShutdownComplete() {
 		return
 	}
+	state.closeReq = nil
+
+	closingTxid, err := p.executeCooperativeClose(state.channel, req.fee)
 	if err != nil {
 		req.err <- err
 		return
 	}
+	peerLog.Infof("Attempting cooperative close of ChannelPoint(%v) "+
+		"with txid: %v", state.chanPoint, closingTxid)
 
 	// Update the caller with a new event detailing the current pending
 	// state of this request.
@@ -858,108 +1176,418 @@ func (p *peer) handleLocalClose(req *closeLinkReq) {
 		},
 	}
 
-	// Finally, launch a goroutine which will request to be notified by the
-	// ChainNotifier once the closure transaction obtains a single
-	// confirmation.
-	go func() {
-		// TODO(roasbeef): add param for num needed confs
-		notifier := p.server.chainNotifier
-		confNtfn, err := notifier.RegisterConfirmationsNtfn(closingTxid, 1)
+	// Finally, watch our version of the closure transaction alongside any
+	// version the remote peer has proposed, responding to the local
+	// subsystem once one of them confirms.
+	p.trackCooperativeClose(state.channel, closingTxid, req, "local")
+}
+
+// closeConf describes the confirmation of a cooperative closure transaction.
+type closeConf struct {
+	txid   *chainhash.Hash
+	height uint32
+}
+
+// waitForCloseConf waits for the cooperative closure transaction with the
+// passed txid, or any other version of it, to confirm. The txids of further
+// versions signed by either party are read from the replacements channel. If
+// bumpFee is non-nil, then it's called each time cfg.CloseBumpBlocks blocks
+// pass without a confirmation, and should return the txid of a replacement of
+// our own version paying a higher fee, or nil if we have no version of our own
+// to replace. If either the peer or the ChainNotifier shuts down before a
+// confirmation, then nil is returned.
+func (p *peer) waitForCloseConf(closingTxid *chainhash.Hash,
+	replacements <-chan *chainhash.Hash,
+	bumpFee func() (*chainhash.Hash, error)) (*closeConf, error) {
+
+	notifier := p.server.chainNotifier
+
+	// As any one of the closure transactions may be the one to confirm,
+	// we'll watch each of them, funnelling their confirmations into a
+	// single channel.
+	confirmed := make(chan *closeConf)
+	notifierExit := make(chan struct{})
+	done := make(chan struct{})
+	defer close(done)
+
+	watchTx := func(txid *chainhash.Hash) error {
+		confNtfn, err := notifier.RegisterConfirmationsNtfn(txid, 1)
 		if err != nil {
-			req.err <- err
-			return
+			return err
+		}
+
+		go func() {
+			select {
+			case conf, ok := <-confNtfn.Confirmed:
+				// In the case that the ChainNotifier is
+				// shutting down, all subscriber notification
+				// channels will be closed, generating a nil
+				// receive.
+				if !ok {
+					select {
+					case notifierExit <- struct{}{}:
+					case <-done:
+					}
+					return
+				}
+
+				select {
+				case confirmed <- &closeConf{txid, conf.BlockHeight}:
+				case <-done:
+				}
+			case <-done:
+			}
+		}()
+
+		return nil
+	}
+	if err := watchTx(closingTxid); err != nil {
+		return nil, err
+	}
+
+	// If we're able to bump the fee of the closure transaction, then we'll
+	// also need to be notified of each new block.
+	var epochs <-chan *chainntnfs.BlockEpoch
+	if bumpFee != nil && cfg.CloseBumpBlocks != 0 {
+		blockEpochs, err := notifier.RegisterBlockEpochNtfn()
+		if err != nil {
+			return nil, err
 		}
+		defer blockEpochs.Cancel()
 
+		epochs = blockEpochs.Epochs
+	}
+
+	var blocksWaited uint32
+	for {
 		select {
-		case height, ok := <-confNtfn.Confirmed:
-			// In the case that the ChainNotifier is shutting down,
-			// all subscriber notification channels will be closed,
-			// generating a nil receive.
+		case conf := <-confirmed:
+			return conf, nil
+
+		case txid := <-replacements:
+			if err := watchTx(txid); err != nil {
+				return nil, err
+			}
+
+		case _, ok := <-epochs:
 			if !ok {
-				return
+				return nil, nil
 			}
 
-			// The channel has been closed, remove it from any
-			// active indexes, and the database state.
-			peerLog.Infof("ChannelPoint(%v) is now closed at "+
-				"height %v", req.chanPoint, height.BlockHeight)
-			if err := wipeChannel(p, channel); err != nil {
-				req.err <- err
-				return
+			blocksWaited++
+			if blocksWaited < cfg.CloseBumpBlocks {
+				continue
+			}
+			blocksWaited = 0
+
+			// If we're unable to bump the fee, then we'll simply
+			// continue to wait for the existing closure
+			// transactions.
+			txid, err := bumpFee()
+			if err != nil {
+				peerLog.Warnf("Unable to bump fee of closure "+
+					"transaction %v: %v", closingTxid, err)
+				epochs = nil
+				continue
+			}
+			if txid == nil {
+				continue
 			}
+			if err := watchTx(txid); err != nil {
+				return nil, err
+			}
+
+		case <-notifierExit:
+			return nil, nil
+
 		case <-p.quit:
-			return
+			return nil, nil
+		}
+	}
+}
+
+// closingChannel is a channel which is being cooperatively closed, whose
+// closure transactions have yet to confirm. Both our own version of the
+// closure transaction and the remote peer's version, along with any
+// replacements of either, are watched until one of them confirms.
+type closingChannel struct {
+	channel *lnwallet.LightningChannel
+
+	// replacements is sent upon with the txid of each further closure
+	// transaction signed by either party.
+	replacements chan *chainhash.Hash
+
+	// confirmed is closed once any of the closure transactions confirm.
+	confirmed chan struct{}
+
+	// localReq is the local request to close the channel, if any, which
+	// is updated as our own version is replaced, and once the channel is
+	// closed.
+	//
+	// NOTE: This field MUST be accessed with the peer's closingChanMtx
+	// held.
+	localReq *closeLinkReq
+}
+
+// trackCooperativeClose watches the passed closure transaction of a channel
+// which is being cooperatively closed. The first closure transaction signed by
+// either party launches a goroutine which watches each version of the closure
+// transaction until one confirms, after which the channel is wiped. The txids
+// of subsequent versions are handed to the same goroutine. The initiator of
+// the closure is recorded within the channel's history.
+func (p *peer) trackCooperativeClose(channel *lnwallet.LightningChannel,
+	closingTxid *chainhash.Hash, localReq *closeLinkReq, initiator string) {
+
+	key := *channel.ChannelPoint()
+
+	p.closingChanMtx.Lock()
+	closing, ok := p.closingChannels[key]
+	if ok {
+		if localReq != nil {
+			closing.localReq = localReq
 		}
+		p.closingChanMtx.Unlock()
 
-		// Respond to the local subsystem which requested the channel
-		// closure.
+		select {
+		case closing.replacements <- closingTxid:
+		case <-closing.confirmed:
+		case <-p.quit:
+		}
+		return
+	}
+
+	closing = &closingChannel{
+		channel:      channel,
+		replacements: make(chan *chainhash.Hash),
+		confirmed:    make(chan struct{}),
+		localReq:     localReq,
+	}
+	p.closingChannels[key] = closing
+	p.closingChanMtx.Unlock()
+
+	p.server.chanHistory.closeInitiated(channel, "cooperative, "+initiator)
+
+	go p.watchCooperativeClose(closing, closingTxid)
+}
+
+// resumeCooperativeClose resumes watching each version of the closure
+// transaction of a channel loaded from the database which is being
+// cooperatively closed, bumping the fee of our own version, if any, from where
+// it left off.
+func (p *peer) resumeCooperativeClose(channel *lnwallet.LightningChannel) {
+	versions := channel.CooperativeCloseVersions()
+	if len(versions) == 0 {
+		return
+	}
+
+	closing := &closingChannel{
+		channel:      channel,
+		replacements: make(chan *chainhash.Hash),
+		confirmed:    make(chan struct{}),
+	}
+	p.closingChanMtx.Lock()
+	p.closingChannels[*channel.ChannelPoint()] = closing
+	p.closingChanMtx.Unlock()
+
+	go p.watchCooperativeClose(closing, &versions[0].Txid)
+	go func() {
+		for _, version := range versions[1:] {
+			select {
+			case closing.replacements <- &version.Txid:
+			case <-closing.confirmed:
+				return
+			case <-p.quit:
+				return
+			}
+		}
+	}()
+}
+
+// closeRequest returns the local request to close the passed closing channel,
+// if any.
+func (p *peer) closeRequest(closing *closingChannel) *closeLinkReq {
+	p.closingChanMtx.Lock()
+	defer p.closingChanMtx.Unlock()
+
+	return closing.localReq
+}
+
+// watchCooperativeClose waits for any version of the closure transaction of
+// the passed closing channel to confirm, starting with the passed txid. If our
+// own version is stuck below the prevailing fee rate, then we'll periodically
+// double its fee. Once a version confirms, the channel is wiped, and any local
+// request to close the channel is notified.
+//
+// NOTE: This MUST be run as a goroutine.
+func (p *peer) watchCooperativeClose(closing *closingChannel,
+	closingTxid *chainhash.Hash) {
+
+	channel := closing.channel
+	chanPoint := channel.ChannelPoint()
+
+	defer func() {
+		p.closingChanMtx.Lock()
+		delete(p.closingChannels, *chanPoint)
+		p.closingChanMtx.Unlock()
+
+		close(closing.confirmed)
+	}()
+
+	bumpFee := func() (*chainhash.Hash, error) {
+		// If we haven't proposed a version of our own, then there's
+		// nothing for us to bump.
+		fee := channel.CooperativeCloseFee()
+		if fee == 0 {
+			return nil, nil
+		}
+
+		txid, err := p.bumpCooperativeClose(channel, fee*2)
+		if err != nil {
+			return nil, err
+		}
+
+		if req := p.closeRequest(closing); req != nil {
+			req.updates <- &lnrpc.CloseStatusUpdate{
+				Update: &lnrpc.CloseStatusUpdate_ClosePending{
+					ClosePending: &lnrpc.PendingUpdate{
+						Txid: txid[:],
+					},
+				},
+			}
+		}
+
+		return txid, nil
+	}
+
+	// TODO(roasbeef): add param for num needed confs
+	conf, err := p.waitForCloseConf(closingTxid, closing.replacements,
+		bumpFee)
+	if err != nil {
+		peerLog.Errorf("unable to wait for closure of "+
+			"ChannelPoint(%v): %v", chanPoint, err)
+		if req := p.closeRequest(closing); req != nil {
+			req.err <- err
+		}
+		return
+	}
+
+	// In the case that either the ChainNotifier or the peer is shutting
+	// down, no confirmation will be returned.
+	if conf == nil {
+		return
+	}
+
+	// The channel has been closed, remove it from any active indexes, and
+	// the database state.
+	peerLog.Infof("ChannelPoint(%v) is now closed by %v at height %v",
+		chanPoint, conf.txid, conf.height)
+	if err := wipeChannel(p, channel); err != nil {
+		peerLog.Errorf("unable to wipe channel: %v", err)
+		if req := p.closeRequest(closing); req != nil {
+			req.err <- err
+		}
+		return
+	}
+	p.server.chanHistory.channelResolved(channel, conf.txid)
+	p.server.stateLog.record(channel, channeldb.CloseDetectedEvent,
+		fmt.Sprintf("cooperative, closing_txid=%v", conf.txid))
+	p.server.outbox.channelClosed(channel, conf.txid)
+
+	// Respond to the local subsystem which requested the channel closure,
+	// if any.
+	if req := p.closeRequest(closing); req != nil {
 		req.updates <- &lnrpc.CloseStatusUpdate{
 			Update: &lnrpc.CloseStatusUpdate_ChanClose{
 				ChanClose: &lnrpc.ChannelCloseUpdate{
-					ClosingTxid: closingTxid[:],
+					ClosingTxid: conf.txid[:],
 					Success:     true,
 				},
 			},
 		}
+	}
 
-		p.server.breachArbiter.settledContracts <- req.chanPoint
-	}()
+	p.server.breachArbiter.settledContracts <- chanPoint
 }
 
-// handleRemoteClose completes a request for cooperative channel closure
-// initiated by the remote node.
+// handleRemoteClose signs the version of the cooperative closure transaction
+// proposed by the remote node, or a replacement of it. As the remote node pays
+// the fee of its version in full, we only need to verify and sign it, leaving
+// the broadcast to the remote node.
 func (p *peer) handleRemoteClose(req *lnwire.CloseRequest) {
 	chanPoint := req.ChannelPoint
-	key := wire.OutPoint{
-		Hash:  chanPoint.Hash,
-		Index: chanPoint.Index,
-	}
 
 	p.activeChanMtx.RLock()
-	channel, ok := p.activeChannels[key]
+	channel, ok := p.activeChannels[chanPoint]
 	p.activeChanMtx.RUnlock()
 	if !ok {
 		peerLog.Errorf("unable to close channel, ChannelPoint(%v) is "+
-			"unknown", key)
+			"unknown", chanPoint)
 		return
 	}
 
-	// Now that we have their signature for the closure transaction, we
-	// can assemble the final closure transaction, complete with our
-	// signature.
 	sig := req.RequesterCloseSig
 	closeSig := append(sig.Serialize(), byte(txscript.SigHashAll))
-	closeTx, err := channel.CompleteCooperativeClose(closeSig)
+	ourSig, closingTxid, err := channel.SignRemoteCooperativeClose(
+		closeSig, req.Fee)
 	if err != nil {
-		peerLog.Errorf("unable to complete cooperative "+
-			"close for ChannelPoint(%v): %v",
-			chanPoint, err)
+		peerLog.Errorf("unable to sign cooperative close for "+
+			"ChannelPoint(%v): %v", chanPoint, err)
 		// TODO(roasbeef): send ErrorGeneric to other side
 		return
 	}
 
-	peerLog.Infof("Broadcasting cooperative close tx: %v",
-		newLogClosure(func() string {
-			return spew.Sdump(closeTx)
-		}))
+	peerLog.Infof("Signed cooperative close tx %v of ChannelPoint(%v) "+
+		"paying fee of %v", closingTxid, chanPoint, req.Fee)
 
-	// Finally, broadcast the closure transaction, to the network.
-	if err := p.server.lnwallet.PublishTransaction(closeTx); err != nil {
-		peerLog.Errorf("channel close tx from "+
-			"ChannelPoint(%v) rejected: %v",
-			chanPoint, err)
-		// TODO(roasbeef): send ErrorGeneric to other side
+	respSig, err := btcec.ParseSignature(ourSig, btcec.S256())
+	if err != nil {
+		peerLog.Errorf("unable to parse signature: %v", err)
 		return
 	}
+	p.queueMsg(&lnwire.CloseComplete{
+		ChannelPoint:      chanPoint,
+		ResponderCloseSig: respSig,
+		Fee:               req.Fee,
+	}, nil)
+
+	// Finally, we'll watch the remote node's version alongside any of our
+	// own until one of them confirms.
+	p.trackCooperativeClose(channel, closingTxid, nil, "remote")
+}
 
-	// TODO(roasbeef): also wait for confs before removing state
-	peerLog.Infof("ChannelPoint(%v) is now "+
-		"closed", key)
-	if err := wipeChannel(p, channel); err != nil {
-		peerLog.Errorf("unable to wipe channel: %v", err)
+// handleCloseComplete completes our own version of the cooperative closure
+// transaction with the signature of the remote node, then broadcasts it. The
+// transaction is already being watched, as it was when we signed it.
+func (p *peer) handleCloseComplete(msg *lnwire.CloseComplete) {
+	chanPoint := msg.ChannelPoint
+
+	p.activeChanMtx.RLock()
+	channel, ok := p.activeChannels[chanPoint]
+	p.activeChanMtx.RUnlock()
+	if !ok {
+		peerLog.Errorf("unable to complete close, ChannelPoint(%v) is "+
+			"unknown", chanPoint)
+		return
 	}
 
-	p.server.breachArbiter.settledContracts <- &req.ChannelPoint
+	sig := msg.ResponderCloseSig
+	closeSig := append(sig.Serialize(), byte(txscript.SigHashAll))
+	closeTx, err := channel.CompleteCooperativeClose(closeSig, msg.Fee)
+	if err != nil {
+		peerLog.Errorf("unable to complete cooperative close for "+
+			"ChannelPoint(%v): %v", chanPoint, err)
+		return
+	}
+
+	peerLog.Infof("Broadcasting cooperative close tx paying fee of %v: %v",
+		msg.Fee, newLogClosure(func() string {
+			return spew.Sdump(closeTx)
+		}))
+
+	if err := p.server.lnwallet.PublishTransaction(closeTx); err != nil {
+		peerLog.Errorf("channel close tx from ChannelPoint(%v) "+
+			"rejected: %v", chanPoint, err)
+	}
 }
 
 // wipeChannel removes the passed channel from all indexes associated with the
@@ -998,6 +1626,7 @@ func wipeChannel(p *peer, channel *lnwallet.LightningChannel) error {
 	// above.
 	p.htlcManMtx.RLock()
 	delete(p.htlcManagers, *chanID)
+	delete(p.shutdownLinks, *chanID)
 	p.htlcManMtx.RUnlock()
 
 	// Finally, we purge the channel's state from the database, leaving a
@@ -1007,6 +1636,7 @@ func wipeChannel(p *peer, channel *lnwallet.LightningChannel) error {
 			"from db: %v", chanID, err)
 		return err
 	}
+	p.server.chanBackup.requestUpdate()
 
 	return nil
 }
@@ -1036,6 +1666,12 @@ type commitmentState struct {
 	// are to be cancelled upon the next state transition.
 	htlcsToCancel map[uint64]lnwire.FailCode
 
+	// htlcsToHold is a set of HTLCs identified by their log index which
+	// pay only part of an invoice. Once locked in, they're held by the
+	// switch as shards of a payment split across several of our channels
+	// with the peer.
+	htlcsToHold map[uint64]*channeldb.Invoice
+
 	// cancelReasons stores the reason why a particular HTLC was cancelled.
 	// The index of the HTLC within the log is mapped to the cancellation
 	// reason. This value is used to thread the proper error through to the
@@ -1052,6 +1688,11 @@ type commitmentState struct {
 	// chain which have not yet been settled by the upstream peer.
 	clearedHTCLs map[uint64]*pendingPayment
 
+	// htlcAddTimes is the time each outgoing HTLC, identified by its log
+	// index, was offered to the remote peer. It's used to measure how
+	// quickly the peer resolves HTLCs.
+	htlcAddTimes map[uint64]time.Time
+
 	// logCommitTimer is a timer which is sent upon if we go an interval
 	// without receiving/sending a commitment update. It's role is to
 	// ensure both chains converge to identical state in a timely manner.
@@ -1076,6 +1717,38 @@ type commitmentState struct {
 	// along with the HTLC to forward the packet to the next hop.
 	pendingCircuits map[uint64]*sphinx.ProcessedPacket
 
+	// endorsedHTLCs is the set of incoming HTLCs within pendingCircuits,
+	// identified by their remote log index, which the remote peer
+	// endorsed.
+	endorsedHTLCs map[uint64]struct{}
+
+	// unsignedSettles are the payment hashes of forwarded HTLCs which
+	// we've settled within our local log, but which haven't yet been
+	// included within a commitment we've signed.
+	unsignedSettles [][32]byte
+
+	// unsignedUpdates are the updates we've sent the remote peer since
+	// the last commitment we've signed for it. They're recorded along
+	// with the next commitment we sign, so they may be replayed should our
+	// signature not reach the peer.
+	unsignedUpdates []*channeldb.PendingUpdate
+
+	// signedSettles are the payment hashes of settled forwarded HTLCs
+	// included within each signed commitment the remote party hasn't yet
+	// revoked their prior commitment for, in the order the commitments
+	// were signed. Once the remote party revokes, the settles within the
+	// oldest batch are committed, and their forwards are completed.
+	signedSettles [][][32]byte
+
+	// closeReq is the local request to cooperatively close the channel,
+	// which is deferred until the channel has been shut down by both
+	// parties, and all HTLCs in flight have been resolved.
+	closeReq *closeLinkReq
+
+	// shutdownSent denotes that we've sent a Shutdown to the remote peer,
+	// either to initiate a closure, or in reply to its own.
+	shutdownSent bool
+
 	channel   *lnwallet.LightningChannel
 	chanPoint *wire.OutPoint
 }
@@ -1091,7 +1764,7 @@ type commitmentState struct {
 // queue+timer for this active channels.
 func (p *peer) htlcManager(channel *lnwallet.LightningChannel,
 	htlcPlex chan<- *htlcPacket, downstreamLink <-chan *htlcPacket,
-	upstreamLink <-chan lnwire.Message) {
+	upstreamLink <-chan lnwire.Message, shutdownLink <-chan *closeLinkReq) {
 
 	chanStats := channel.StateSnapshot()
 	peerLog.Infof("HTLC manager for ChannelPoint(%v) started, "+
@@ -1110,14 +1783,33 @@ func (p *peer) htlcManager(channel *lnwallet.LightningChannel,
 		p.queueMsg(rev, nil)
 	}
 
+	// Apply our configured HTLC policy to the channel, this'll be
+	// enforced for all HTLCs added from this point onwards.
+	minHTLC := btcutil.Amount(cfg.MinHTLC)
+	maxDustExposure := btcutil.Amount(cfg.MaxDustExposure)
+	maxHashExposure := btcutil.Amount(cfg.MaxHashExposure)
+	channel.SetHTLCPolicy(minHTLC, maxDustExposure, maxHashExposure)
+	p.server.chanHistory.policyApplied(channel, minHTLC, maxDustExposure,
+		maxHashExposure)
+
+	// Channels opened before the reserve was negotiated during funding
+	// have none recorded, so our configured reserve is applied instead. A
+	// negotiated reserve of zero is left as is.
+	if !channel.ChanReserveNegotiated() {
+		channel.SetChanReserve(btcutil.Amount(cfg.ChanReserve))
+	}
+
 	state := &commitmentState{
 		channel:         channel,
 		chanPoint:       channel.ChannelPoint(),
 		clearedHTCLs:    make(map[uint64]*pendingPayment),
+		htlcAddTimes:    make(map[uint64]time.Time),
 		htlcsToSettle:   make(map[uint64]*channeldb.Invoice),
 		htlcsToCancel:   make(map[uint64]lnwire.FailCode),
+		htlcsToHold:     make(map[uint64]*channeldb.Invoice),
 		cancelReasons:   make(map[uint64]lnwire.FailCode),
 		pendingCircuits: make(map[uint64]*sphinx.ProcessedPacket),
+		endorsedHTLCs:   make(map[uint64]struct{}),
 		sphinx:          p.server.sphinx,
 		switchChan:      htlcPlex,
 		logCommitTimer:  time.NewTimer(300 * time.Millisecond),
@@ -1130,6 +1822,9 @@ func (p *peer) htlcManager(channel *lnwallet.LightningChannel,
 
 	batchTimer := time.NewTicker(50 * time.Millisecond)
 	defer batchTimer.Stop()
+
+	stateCheckTicker := time.NewTicker(stateCheckInterval)
+	defer stateCheckTicker.Stop()
 out:
 	for {
 		select {
@@ -1137,6 +1832,10 @@ out:
 			// TODO(roasbeef): need to send HTLC outputs to nursery
 			peerLog.Warnf("Remote peer has closed ChannelPoint(%v) on-chain",
 				state.chanPoint)
+			p.server.chanHistory.closeInitiated(channel,
+				"unilateral, remote")
+			p.server.stateLog.record(channel,
+				channeldb.CloseDetectedEvent, "unilateral, remote")
 			if err := wipeChannel(p, channel); err != nil {
 				peerLog.Errorf("unable to wipe channel %v", err)
 			}
@@ -1190,9 +1889,15 @@ out:
 				break out
 			}
 
+		case <-stateCheckTicker.C:
+			p.verifyChannelState(channel)
+
 		case pkt := <-downstreamLink:
 			p.handleDownStreamPkt(state, pkt)
 
+		case req := <-shutdownLink:
+			p.handleLocalShutdown(state, req)
+
 		case msg, ok := <-upstreamLink:
 			// If the upstream message link is closed, this signals
 			// that the channel itself is being closed, therefore
@@ -1202,6 +1907,11 @@ out:
 			}
 
 			p.handleUpstreamMsg(state, msg)
+
+			// Resolving an HTLC in flight may have quiesced a
+			// channel that's being shut down, allowing a pending
+			// closure to proceed.
+			p.closeOnQuiescence(state)
 		case <-p.quit:
 			break out
 		}
@@ -1213,7 +1923,25 @@ out:
 
 // handleInitMsg handles the incoming init message which contains global and
 // local features vectors. If feature vectors are incompatible then disconnect.
+// Likewise, if the peer operates on another chain, or speaks a version of the
+// peer protocol either of us doesn't interoperate with, then the peer is told
+// why before we disconnect, so no channel is ever negotiated across networks.
 func (p *peer) handleInitMsg(msg *lnwire.Init) error {
+	err := checkInitCompatibility(msg, activeNetParams.GenesisHash)
+	if err != nil {
+		peerLog.Warnf("Disconnecting incompatible peer %v: %v", p, err)
+
+		errMsg := &lnwire.ErrorGeneric{
+			Code:    lnwire.ErrIncompatiblePeer,
+			Problem: err.Error(),
+		}
+		if err := p.writeMessage(errMsg); err != nil {
+			peerLog.Debugf("unable to send error to %v: %v", p, err)
+		}
+
+		return err
+	}
+
 	localSharedFeatures, err := p.server.localFeatures.Compare(msg.LocalFeatures)
 	if err != nil {
 		err := errors.Errorf("can compare remote and local feature "+
@@ -1242,10 +1970,44 @@ func (p *peer) sendInitMsg() error {
 		p.server.globalFeatures,
 		p.server.localFeatures,
 	)
+	msg.ChainHash = *activeNetParams.GenesisHash
+	msg.ProtocolVersion = lnwire.ProtocolVersion
+	msg.MinProtocolVersion = lnwire.MinProtocolVersion
 
 	return p.writeMessage(msg)
 }
 
+// checkInitCompatibility checks that the remote node which sent the passed
+// init message operates on the chain with the passed genesis hash, and that
+// each of us speaks a version of the peer protocol the other interoperates
+// with. Older nodes don't advertise their chain, in which case the network
+// magic of each message is all that tells networks apart, and they're
+// assumed to speak the first version of the protocol.
+func checkInitCompatibility(msg *lnwire.Init,
+	genesisHash *chainhash.Hash) error {
+
+	if msg.ChainHash == (chainhash.Hash{}) {
+		return nil
+	}
+
+	if msg.ChainHash != *genesisHash {
+		return fmt.Errorf("peer operates on chain %v, we operate on "+
+			"chain %v", msg.ChainHash, genesisHash)
+	}
+	if msg.ProtocolVersion < lnwire.MinProtocolVersion {
+		return fmt.Errorf("peer speaks protocol version %v, we "+
+			"require at least version %v", msg.ProtocolVersion,
+			lnwire.MinProtocolVersion)
+	}
+	if lnwire.ProtocolVersion < msg.MinProtocolVersion {
+		return fmt.Errorf("peer requires protocol version %v, we "+
+			"speak version %v", msg.MinProtocolVersion,
+			lnwire.ProtocolVersion)
+	}
+
+	return nil
+}
+
 // handleDownStreamPkt processes an HTLC packet sent from the downstream HTLC
 // Switch. Possible messages sent by the switch include requests to forward new
 // HTLCs, timeout previously cleared HTLCs, and finally to settle currently
@@ -1259,6 +2021,13 @@ func (p *peer) handleDownStreamPkt(state *commitmentState, pkt *htlcPacket) {
 		// to our local log, then update the commitment
 		// chains.
 		htlc.ChannelPoint = *state.chanPoint
+
+		// Peers which don't support the endorsement of HTLCs may
+		// reject the signal's trailing byte, so it's withheld.
+		if !p.localSharedFeatures.IsActive(htlcEndorsementFeature) {
+			htlc.Endorsed = false
+		}
+
 		index, err := state.channel.AddHTLC(htlc)
 		if err != nil {
 			// TODO: possibly perform fallback/retry logic
@@ -1276,7 +2045,8 @@ func (p *peer) handleDownStreamPkt(state *commitmentState, pkt *htlcPacket) {
 			return
 		}
 
-		p.queueMsg(htlc, nil)
+		p.sendUpdate(state, htlc, htlc.PaymentHash)
+		state.htlcAddTimes[index] = time.Now()
 
 		state.pendingBatch = append(state.pendingBatch, &pendingPayment{
 			htlc:     htlc,
@@ -1291,12 +2061,23 @@ func (p *peer) handleDownStreamPkt(state *commitmentState, pkt *htlcPacket) {
 		// state machine.
 		pre := htlc.PaymentPreimage
 		logIndex, err := state.channel.SettleHTLC(pre)
-		if err != nil {
+		if err != nil && pkt.replayed {
+			// If a replayed settle is rejected, then the HTLC
+			// may have already been settled before the restart.
+			// The intent is left in place so the preimage remains
+			// available to resolve the HTLC otherwise.
+			peerLog.Warnf("unable to replay settle of forwarded "+
+				"HTLC %x on ChannelPoint(%v): %v", pkt.payHash[:],
+				state.chanPoint, err)
+			return
+		} else if err != nil {
 			// TODO(roasbeef): broadcast on-chain
 			peerLog.Errorf("settle for incoming HTLC rejected: %v", err)
 			p.Disconnect()
 			return
 		}
+		state.unsignedSettles = append(state.unsignedSettles,
+			pkt.payHash)
 
 		// With the HTLC settled, we'll need to populate the wire
 		// message to target the specific channel and HTLC to be
@@ -1306,7 +2087,7 @@ func (p *peer) handleDownStreamPkt(state *commitmentState, pkt *htlcPacket) {
 
 		// Then we send the HTLC settle message to the connected peer
 		// so we can continue the propagation of the settle message.
-		p.queueMsg(htlc, nil)
+		p.sendUpdate(state, htlc, pkt.payHash)
 		isSettle = true
 
 	case *lnwire.UpdateFailHTLC:
@@ -1327,7 +2108,30 @@ func (p *peer) handleDownStreamPkt(state *commitmentState, pkt *htlcPacket) {
 
 		// Finally, we send the HTLC message to the peer which
 		// initially created the HTLC.
-		p.queueMsg(htlc, nil)
+		p.sendUpdate(state, htlc, pkt.payHash)
+		isSettle = true
+
+	case *lnwire.UpdateFee:
+		// A new commitment fee has been requested, so we add the fee
+		// update to our local log, replying to the requester with the
+		// outcome.
+		prevFee, err := state.channel.UpdateFee(htlc.Fee)
+		pkt.err <- err
+		if err != nil {
+			peerLog.Errorf("unable to update commitment fee of "+
+				"ChannelPoint(%v): %v", state.chanPoint, err)
+			return
+		}
+
+		// The fee is paid from our balance, so the bandwidth of the
+		// link changes by the difference between the fees.
+		htlc.ChannelPoint = *state.chanPoint
+		p.server.htlcSwitch.UpdateLink(&htlc.ChannelPoint,
+			prevFee-htlc.Fee)
+
+		// As with a settle, the update is committed immediately so
+		// the new fee is in effect as soon as possible.
+		p.sendUpdate(state, htlc, [32]byte{})
 		isSettle = true
 	}
 
@@ -1344,6 +2148,40 @@ func (p *peer) handleDownStreamPkt(state *commitmentState, pkt *htlcPacket) {
 	}
 }
 
+// commitFeeCeiling returns the ceiling commitment fees proposed by the
+// remote party are checked against, along with our own fee estimate the fee
+// rate of their commitment is compared to. If no estimate is available, zero
+// is returned in its place, in which case only the absolute fee is checked.
+func (p *peer) commitFeeCeiling() (*lnwallet.FeeCeiling, btcutil.Amount) {
+	ceiling := p.server.lnwallet.FeeCeiling
+	if ceiling == nil || ceiling.MaxFeeRateMultiple == 0 {
+		return ceiling, 0
+	}
+
+	feeRate, err := p.server.lnwallet.EstimateFeePerByte(ceiling.ConfTarget)
+	if err != nil {
+		peerLog.Warnf("Unable to estimate fee rate to check commitment "+
+			"fees of %v against: %v", p, err)
+		return ceiling, 0
+	}
+
+	return ceiling, feeRate
+}
+
+// htlcResolved records the time the remote peer took to resolve the outgoing
+// HTLC with the passed log index. HTLCs offered before the current session of
+// the channel began aren't measured.
+func (p *peer) htlcResolved(state *commitmentState, idx uint64) {
+	addTime, ok := state.htlcAddTimes[idx]
+	if !ok {
+		return
+	}
+	delete(state.htlcAddTimes, idx)
+
+	p.server.peerScores.htlcResolved(p.addr.IdentityKey,
+		time.Since(addTime))
+}
+
 // handleUpstreamMsg processes wire messages related to commitment state
 // updates from the upstream peer. The upstream peer is the peer whom we have a
 // direct channel with, updating our respective commitment chains.
@@ -1366,7 +2204,28 @@ func (p *peer) handleUpstreamMsg(state *commitmentState, msg lnwire.Message) {
 		// add it to our state machine, then add the HTLC to our
 		// "settle" list in the event that we know the preimage
 		index, err := state.channel.ReceiveHTLC(htlcPkt)
-		if err != nil {
+		if rejection, ok := err.(*lnwallet.ErrHTLCRejected); ok {
+			// The HTLC violates either the channel's reserve or
+			// our HTLC policy, but has been added to the state
+			// machine nonetheless to keep both logs in sync, so
+			// we'll cancel it after the next state transition.
+			switch rejection.Reason {
+			case lnwallet.ErrBelowChanReserve:
+				state.htlcsToCancel[index] = lnwire.InsufficientCapacity
+			case lnwallet.ErrBelowMinHTLC:
+				state.htlcsToCancel[index] = lnwire.AmountBelowMinimum
+			case lnwallet.ErrMaxHashExposure:
+				p.server.htlcSwitch.hashExposureRejected()
+				state.htlcsToCancel[index] = lnwire.HashExposureExceeded
+			default:
+				state.htlcsToCancel[index] = lnwire.DustExposureExceeded
+			}
+
+			peerLog.Errorf("rejecting HTLC of %v for %x: %v",
+				htlcPkt.Amount, htlcPkt.PaymentHash[:],
+				rejection.Reason)
+			return
+		} else if err != nil {
 			peerLog.Errorf("Receiving HTLC rejected: %v", err)
 			return
 		}
@@ -1401,21 +2260,39 @@ func (p *peer) handleUpstreamMsg(state *commitmentState, msg lnwire.Message) {
 			if err != nil {
 				// If we're the exit node, but don't recognize
 				// the payment hash, then we'll fail the HTLC
-				// on the next state transition.
+				// on the next state transition. It's most
+				// likely a probe, so repeated probes of its
+				// amount are answered as if it never reached
+				// us.
+				failCode := p.server.probes.failCode(
+					htlcPkt.Amount)
 				peerLog.Errorf("unable to settle HTLC, "+
-					"payment hash (%x) unrecognized", rHash[:])
+					"payment hash (%x) unrecognized, "+
+					"failing with %v", rHash[:], failCode)
+				state.htlcsToCancel[index] = failCode
+				return
+			}
+
+			// An invoice which has expired can no longer be paid,
+			// so the HTLC is failed as if its payment hash were
+			// unknown.
+			if invoice.IsExpired(time.Now()) {
+				peerLog.Errorf("unable to settle HTLC, invoice "+
+					"(%x) has expired", rHash[:])
 				state.htlcsToCancel[index] = lnwire.UnknownPaymentHash
 				return
 			}
 
 			// If we're not currently in debug mode, and the
 			// extended HTLC doesn't meet the value requested, then
-			// we'll fail the HTLC.
+			// it may be a shard of a payment split across several
+			// of our channels with the peer, so we'll hold it once
+			// it's locked in.
 			if !cfg.DebugHTLC && htlcPkt.Amount < invoice.Terms.Value {
-				peerLog.Errorf("rejecting HTLC due to incorrect "+
-					"amount: expected %v, received %v",
-					invoice.Terms.Value, htlcPkt.Amount)
-				state.htlcsToCancel[index] = lnwire.IncorrectValue
+				peerLog.Debugf("received HTLC of %v for invoice "+
+					"of %v, holding as shard",
+					htlcPkt.Amount, invoice.Terms.Value)
+				state.htlcsToHold[index] = invoice
 			} else {
 				// Otherwise, everything is in order and we'll
 				// settle the HTLC after the current state
@@ -1430,6 +2307,14 @@ func (p *peer) handleUpstreamMsg(state *commitmentState, msg lnwire.Message) {
 		// can finalize the circuit.
 		case sphinx.MoreHops:
 			state.pendingCircuits[index] = sphinxPacket
+
+			// The endorsement of peers which don't advertise its
+			// support is meaningless, so it's ignored.
+			if htlcPkt.Endorsed &&
+				p.localSharedFeatures.IsActive(htlcEndorsementFeature) {
+
+				state.endorsedHTLCs[index] = struct{}{}
+			}
 		default:
 			peerLog.Errorf("mal formed onion packet")
 			state.htlcsToCancel[index] = lnwire.SphinxParseError
@@ -1444,6 +2329,7 @@ func (p *peer) handleUpstreamMsg(state *commitmentState, msg lnwire.Message) {
 			p.Disconnect()
 			return
 		}
+		p.htlcResolved(state, idx)
 
 		// TODO(roasbeef): add preimage to DB in order to swipe
 		// repeated r-values
@@ -1454,9 +2340,27 @@ func (p *peer) handleUpstreamMsg(state *commitmentState, msg lnwire.Message) {
 			p.Disconnect()
 			return
 		}
+		p.htlcResolved(state, idx)
 
 		state.cancelReasons[idx] = lnwire.FailCode(htlcPkt.Reason[0])
 
+	case *lnwire.UpdateFee:
+		ceiling, feeRate := p.commitFeeCeiling()
+		err := state.channel.ReceiveUpdateFee(htlcPkt.Fee, ceiling,
+			feeRate)
+		if err != nil {
+			peerLog.Errorf("unable to accept commitment fee update: %v",
+				err)
+			p.Disconnect()
+			return
+		}
+
+	case *lnwire.Shutdown:
+		p.handleRemoteShutdown(state)
+
+	case *lnwire.ChannelReestablish:
+		p.replayPendingCommit(state, htlcPkt)
+
 	case *lnwire.CommitSig:
 		// We just received a new update to our local commitment chain,
 		// validate this new commitment, closing the link if invalid.
@@ -1467,6 +2371,8 @@ func (p *peer) handleUpstreamMsg(state *commitmentState, msg lnwire.Message) {
 			p.Disconnect()
 			return
 		}
+		p.server.stateLog.record(state.channel,
+			channeldb.SigReceivedEvent, "")
 
 		// As we've just just accepted a new state, we'll now
 		// immediately send the remote peer a revocation for our prior
@@ -1476,7 +2382,12 @@ func (p *peer) handleUpstreamMsg(state *commitmentState, msg lnwire.Message) {
 			peerLog.Errorf("unable to revoke commitment: %v", err)
 			return
 		}
+		localHeight, _ := state.channel.CommitHeights()
+		p.persistLastChanMsg(state.chanPoint, localHeight,
+			nextRevocation)
 		p.queueMsg(nextRevocation, nil)
+		p.server.stateLog.record(state.channel,
+			channeldb.RevocationSentEvent, "")
 
 		// If we just initiated a state transition, and we were waiting
 		// for a reply from the remote peer, then we don't need to
@@ -1518,6 +2429,37 @@ func (p *peer) handleUpstreamMsg(state *commitmentState, msg lnwire.Message) {
 			p.Disconnect()
 			return
 		}
+		p.server.chanHistory.balanceChanged(state.channel)
+
+		// Revocations which merely extend the revocation window carry
+		// no preimage to ingest, so they aren't logged.
+		if htlcPkt.Revocation != [32]byte{} {
+			p.server.stateLog.record(state.channel,
+				channeldb.RevocationReceivedEvent, "")
+		}
+
+		// If this revocation isn't merely extending the revocation
+		// window, then the channel has moved to a new state, so the
+		// peer's backup of our state is refreshed.
+		if htlcPkt.Revocation != [32]byte{} {
+			p.server.peerStorage.sendBackup(p)
+		}
+
+		// If this revocation isn't merely extending the revocation
+		// window, then the remote party has accepted our oldest
+		// outstanding commitment. Any settles of forwarded HTLCs
+		// within it are now committed, so we can complete their
+		// forwards.
+		if htlcPkt.Revocation != [32]byte{} &&
+			len(state.signedSettles) != 0 {
+
+			for _, payHash := range state.signedSettles[0] {
+				p.server.htlcSwitch.forwardSettled(
+					state.chanPoint, payHash)
+			}
+			state.signedSettles[0] = nil
+			state.signedSettles = state.signedSettles[1:]
+		}
 
 		// If any of the HTLCs eligible for forwarding are pending
 		// settling or timing out previous outgoing payments, then we
@@ -1526,6 +2468,8 @@ func (p *peer) handleUpstreamMsg(state *commitmentState, msg lnwire.Message) {
 		var bandwidthUpdate btcutil.Amount
 		settledPayments := make(map[lnwallet.PaymentHash]struct{})
 		cancelledHtlcs := make(map[uint64]struct{})
+		heldHtlcs := make(map[uint64]struct{})
+		releasedShards := make(map[uint64]*shardSet)
 		for _, htlc := range htlcsToForward {
 			parentIndex := htlc.ParentIndex
 			if p, ok := state.clearedHTCLs[parentIndex]; ok {
@@ -1554,31 +2498,82 @@ func (p *peer) handleUpstreamMsg(state *commitmentState, msg lnwire.Message) {
 				continue
 			}
 
+			// If this HTLC is a shard of a split payment, then
+			// it's held by the switch until the remaining shards
+			// arrive, unless it completes the payment itself.
+			if invoice, ok := state.htlcsToHold[htlc.Index]; ok {
+				delete(state.htlcsToHold, htlc.Index)
+
+				shards, err := p.server.htlcSwitch.holdShard(
+					chainhash.Hash(htlc.RHash), invoice,
+					*state.chanPoint, htlc.Amount)
+				switch {
+				case err != nil:
+					peerLog.Errorf("rejecting shard of %x: %v",
+						htlc.RHash[:], err)
+					state.htlcsToCancel[htlc.Index] =
+						lnwire.IncorrectValue
+				case shards != nil:
+					state.htlcsToSettle[htlc.Index] = invoice
+					releasedShards[htlc.Index] = shards
+				default:
+					heldHtlcs[htlc.Index] = struct{}{}
+					continue
+				}
+			}
+
 			// If we can settle this HTLC within our local state
 			// update log, then send the update entry to the remote
-			// party.
-			invoice, ok := state.htlcsToSettle[htlc.Index]
-			if ok {
-				preimage := invoice.Terms.PaymentPreimage
-				logIndex, err := state.channel.SettleHTLC(preimage)
-				if err != nil {
-					peerLog.Errorf("unable to settle htlc: %v", err)
+			// party. The invoice is settled before the HTLC, so an
+			// HTLC which locked in, or completed the held shards
+			// of a payment, after the invoice expired is failed
+			// rather than paying an invoice which remains
+			// unsettled. Should the HTLC fail to settle, then the
+			// invoice is rolled back.
+			if invoice, ok := state.htlcsToSettle[htlc.Index]; ok {
+				delete(state.htlcsToSettle, htlc.Index)
+
+				var (
+					preimage  = invoice.Terms.PaymentPreimage
+					logIndex  uint64
+					settleErr error
+				)
+				err := p.server.invoices.settleExitHTLC(
+					chainhash.Hash(htlc.RHash),
+					releasedShards[htlc.Index],
+					p.server.htlcSwitch, func() error {
+						logIndex, settleErr =
+							state.channel.SettleHTLC(preimage)
+						return settleErr
+					})
+				switch {
+				case settleErr != nil:
+					peerLog.Errorf("unable to settle htlc: %v",
+						settleErr)
 					p.Disconnect()
 					continue
-				}
 
-				settleMsg := &lnwire.UpdateFufillHTLC{
-					ChannelPoint:    *state.chanPoint,
-					ID:              logIndex,
-					PaymentPreimage: preimage,
-				}
-				p.queueMsg(settleMsg, nil)
+				case err != nil:
+					peerLog.Errorf("unable to settle invoice "+
+						"%x, failing htlc: %v",
+						htlc.RHash[:], err)
+					state.htlcsToCancel[htlc.Index] =
+						lnwire.UnknownPaymentHash
 
-				delete(state.htlcsToSettle, htlc.Index)
-				settledPayments[htlc.RHash] = struct{}{}
+				default:
+					settleMsg := &lnwire.UpdateFufillHTLC{
+						ChannelPoint:    *state.chanPoint,
+						ID:              logIndex,
+						PaymentPreimage: preimage,
+					}
+					p.sendUpdate(state, settleMsg,
+						[32]byte(htlc.RHash))
 
-				bandwidthUpdate += htlc.Amount
-				continue
+					settledPayments[htlc.RHash] = struct{}{}
+
+					bandwidthUpdate += htlc.Amount
+					continue
+				}
 			}
 
 			// Alternatively, if we marked this HTLC for
@@ -1602,7 +2597,7 @@ func (p *peer) handleUpstreamMsg(state *commitmentState, msg lnwire.Message) {
 				ID:           logIndex,
 				Reason:       []byte{byte(reason)},
 			}
-			p.queueMsg(cancelMsg, nil)
+			p.sendUpdate(state, cancelMsg, [32]byte(htlc.RHash))
 			delete(state.htlcsToCancel, htlc.Index)
 
 			cancelledHtlcs[htlc.Index] = struct{}{}
@@ -1619,10 +2614,16 @@ func (p *peer) handleUpstreamMsg(state *commitmentState, msg lnwire.Message) {
 				if _, ok := cancelledHtlcs[htlc.Index]; ok {
 					continue
 				}
+				if _, ok := heldHtlcs[htlc.Index]; ok {
+					continue
+				}
 
 				onionPkt := state.pendingCircuits[htlc.Index]
 				delete(state.pendingCircuits, htlc.Index)
 
+				_, endorsed := state.endorsedHTLCs[htlc.Index]
+				delete(state.endorsedHTLCs, htlc.Index)
+
 				reason := state.cancelReasons[htlc.ParentIndex]
 				delete(state.cancelReasons, htlc.ParentIndex)
 
@@ -1635,6 +2636,7 @@ func (p *peer) handleUpstreamMsg(state *commitmentState, msg lnwire.Message) {
 						err)
 					continue
 				}
+				pkt.endorsed = endorsed
 
 				state.switchChan <- pkt
 			}
@@ -1661,17 +2663,35 @@ func (p *peer) handleUpstreamMsg(state *commitmentState, msg lnwire.Message) {
 			p.Disconnect()
 			return
 		}
+	}
+}
 
-		// Notify the invoiceRegistry of the invoices we just settled
-		// with this latest commitment update.
-		// TODO(roasbeef): wait until next transition?
-		for invoice := range settledPayments {
-			err := p.server.invoices.SettleInvoice(chainhash.Hash(invoice))
-			if err != nil {
-				peerLog.Errorf("unable to settle invoice: %v", err)
-			}
-		}
+// verifyChannelState cross-checks the in-memory state of the channel against
+// its persisted state. If the channel has newly diverged, and has therefore
+// been frozen, then the operator is alerted via the notification outbox.
+func (p *peer) verifyChannelState(channel *lnwallet.LightningChannel) {
+	wasFrozen := channel.Divergence() != nil
+
+	err := channel.VerifyPersistedState()
+	divergence, ok := err.(*lnwallet.ErrStateDiverged)
+	switch {
+	case err == nil:
+		return
+
+	case !ok:
+		peerLog.Errorf("unable to verify state of ChannelPoint(%v): %v",
+			channel.ChannelPoint(), err)
+		return
+
+	case wasFrozen:
+		return
 	}
+
+	peerLog.Criticalf("ChannelPoint(%v) has been frozen as its %v, "+
+		"acknowledge the divergence or restart to resume",
+		channel.ChannelPoint(), divergence)
+
+	p.server.outbox.stateDiverged(channel, divergence)
 }
 
 // updateCommitTx signs, then sends an update to the remote peer adding a new
@@ -1683,6 +2703,12 @@ func (p *peer) updateCommitTx(state *commitmentState, reply bool) error {
 		peerLog.Tracef("revocation window exhausted, unable to send %v",
 			len(state.pendingBatch))
 		return nil
+	} else if err == lnwallet.ErrChannelFrozen {
+		// The pending updates remain batched until the divergence
+		// which froze the channel is acknowledged.
+		peerLog.Tracef("ChannelPoint(%v) is frozen, unable to send %v",
+			state.chanPoint, len(state.pendingBatch))
+		return nil
 	} else if err != nil {
 		return err
 	}
@@ -1696,7 +2722,10 @@ func (p *peer) updateCommitTx(state *commitmentState, reply bool) error {
 		ChannelPoint: *state.chanPoint,
 		CommitSig:    parsedSig,
 	}
+	p.persistPendingCommit(state, commitSig)
 	p.queueMsg(commitSig, nil)
+	p.server.stateLog.record(state.channel, channeldb.StateProposedEvent,
+		fmt.Sprintf("updates=%v", len(state.pendingBatch)))
 
 	// As we've just cleared out a batch, move all pending updates to the
 	// map of cleared HTLCs, clearing out the set of pending updates.
@@ -1704,6 +2733,13 @@ func (p *peer) updateCommitTx(state *commitmentState, reply bool) error {
 		state.clearedHTCLs[update.index] = update
 	}
 
+	// Any settles of forwarded HTLCs are now included within the new
+	// commitment, and will be committed once the remote party revokes
+	// their prior commitment.
+	state.signedSettles = append(state.signedSettles,
+		state.unsignedSettles)
+	state.unsignedSettles = nil
+
 	// We've just initiated a state transition, attempt to stop the
 	// logCommitTimer. If the timer already ticked, then we'll consume the
 	// value, dropping
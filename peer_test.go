@@ -1 +1,178 @@
 package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/lightningnetwork/lightning-onion"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/chaincfg"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+// TestCheckInitCompatibility tests that peers operating on another chain, or
+// speaking a version of the peer protocol either side doesn't interoperate
+// with, are refused, while older peers which don't advertise their chain are
+// accepted.
+func TestCheckInitCompatibility(t *testing.T) {
+	genesisHash := chaincfg.TestNet3Params.GenesisHash
+
+	tests := []struct {
+		name       string
+		chainHash  chainhash.Hash
+		version    uint32
+		minVersion uint32
+		valid      bool
+	}{
+		{
+			name:       "same chain and version",
+			chainHash:  *genesisHash,
+			version:    lnwire.ProtocolVersion,
+			minVersion: lnwire.MinProtocolVersion,
+			valid:      true,
+		},
+		{
+			name:  "chain not advertised",
+			valid: true,
+		},
+		{
+			name:       "other chain",
+			chainHash:  *chaincfg.MainNetParams.GenesisHash,
+			version:    lnwire.ProtocolVersion,
+			minVersion: lnwire.MinProtocolVersion,
+			valid:      false,
+		},
+		{
+			name:       "peer version too old",
+			chainHash:  *genesisHash,
+			version:    lnwire.MinProtocolVersion - 1,
+			minVersion: lnwire.MinProtocolVersion - 1,
+			valid:      false,
+		},
+		{
+			name:       "our version too old",
+			chainHash:  *genesisHash,
+			version:    lnwire.ProtocolVersion + 1,
+			minVersion: lnwire.ProtocolVersion + 1,
+			valid:      false,
+		},
+	}
+
+	for _, test := range tests {
+		msg := lnwire.NewInitMessage(lnwire.NewFeatureVector(nil),
+			lnwire.NewFeatureVector(nil))
+		msg.ChainHash = test.chainHash
+		msg.ProtocolVersion = test.version
+		msg.MinProtocolVersion = test.minVersion
+
+		err := checkInitCompatibility(msg, genesisHash)
+		if test.valid && err != nil {
+			t.Fatalf("%v: expected peer to be accepted: %v",
+				test.name, err)
+		}
+		if !test.valid && err == nil {
+			t.Fatalf("%v: expected peer to be refused", test.name)
+		}
+	}
+}
+
+// TestReceiveHTLCBelowReserve tests that an HTLC offered by the remote peer
+// which would push its balance below the channel reserve is still added to
+// the update log, keeping it in sync with the remote peer's, and is cancelled
+// back once locked in.
+func TestReceiveHTLCBelowReserve(t *testing.T) {
+	var keys [3]*btcec.PublicKey
+	for i := range keys {
+		priv, err := btcec.NewPrivateKey(btcec.S256())
+		if err != nil {
+			t.Fatalf("unable to generate key: %v", err)
+		}
+		keys[i] = priv.PubKey()
+	}
+
+	const capacity = btcutil.Amount(1e8)
+	witnessScript, _, err := lnwallet.GenFundingPkScript(
+		keys[0].SerializeCompressed(), keys[1].SerializeCompressed(),
+		int64(capacity))
+	if err != nil {
+		t.Fatalf("unable to generate funding script: %v", err)
+	}
+
+	// The remote peer holds half of the channel, with a reserve of a
+	// tenth of the channel's capacity.
+	fundingOutpoint := &wire.OutPoint{Index: 1}
+	channel, err := lnwallet.NewLightningChannel(nil, nil,
+		&channeldb.OpenChannel{
+			ChanID:               fundingOutpoint,
+			FundingOutpoint:      fundingOutpoint,
+			OurMultiSigKey:       keys[0],
+			TheirMultiSigKey:     keys[1],
+			FundingWitnessScript: witnessScript,
+			Capacity:             capacity,
+			OurBalance:           capacity / 2,
+			TheirBalance:         capacity / 2,
+			ChanReserve:          capacity / 10,
+		})
+	if err != nil {
+		t.Fatalf("unable to create channel: %v", err)
+	}
+
+	sessionKey, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate session key: %v", err)
+	}
+	newAdd := func(id uint64, amt btcutil.Amount) *lnwire.UpdateAddHTLC {
+		htlc := &lnwire.UpdateAddHTLC{
+			ChannelPoint: *fundingOutpoint,
+			ID:           id,
+			Amount:       amt,
+			PaymentHash:  [32]byte{byte(id)},
+		}
+
+		hopPayloads := [][]byte{make([]byte, sphinx.HopPayloadSize)}
+		onionPkt, err := sphinx.NewOnionPacket(keys[2:], sessionKey,
+			hopPayloads, htlc.PaymentHash[:])
+		if err != nil {
+			t.Fatalf("unable to create onion packet: %v", err)
+		}
+		var onionBlob bytes.Buffer
+		if err := onionPkt.Encode(&onionBlob); err != nil {
+			t.Fatalf("unable to encode onion packet: %v", err)
+		}
+		copy(htlc.OnionBlob[:], onionBlob.Bytes())
+
+		return htlc
+	}
+
+	p := &peer{}
+	state := &commitmentState{
+		channel:       channel,
+		htlcsToCancel: make(map[uint64]lnwire.FailCode),
+	}
+
+	// An HTLC leaving the remote peer with less than its reserve must be
+	// added to the log nonetheless, then cancelled back.
+	p.handleUpstreamMsg(state, newAdd(0, capacity/2-capacity/20))
+	if failCode, ok := state.htlcsToCancel[0]; !ok {
+		t.Fatalf("htlc below the reserve wasn't cancelled")
+	} else if failCode != lnwire.InsufficientCapacity {
+		t.Fatalf("expected htlc to be cancelled with %v, got %v",
+			lnwire.InsufficientCapacity, failCode)
+	}
+
+	// As the rejected HTLC is still counted against the remote peer's
+	// balance, the next one is rejected too, and must be given the next
+	// index within the log, just as the remote peer has assigned it.
+	index, err := channel.ReceiveHTLC(newAdd(1, 1000))
+	if _, ok := err.(*lnwallet.ErrHTLCRejected); !ok {
+		t.Fatalf("expected htlc to be rejected, got %v", err)
+	}
+	if index != 1 {
+		t.Fatalf("expected htlc to be given index 1, got %v", index)
+	}
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	"github.com/roasbeef/btcd/btcec"
+)
+
+const (
+	// peerScoreSmoothing is the weight given to each new sample within
+	// the moving averages of a peer's latency and HTLC resolution time.
+	peerScoreSmoothing = 0.2
+
+	// referenceLatency is the ping round trip time at which a peer's
+	// latency halves its score.
+	referenceLatency = 100 * time.Millisecond
+
+	// referenceResolution is the HTLC resolution time at which a peer's
+	// resolution speed halves its score. HTLCs are resolved once the rest
+	// of their route has resolved them, so this is far larger than the
+	// reference latency.
+	referenceResolution = 10 * time.Second
+
+	// peerPenaltyWeight is the weight added during path finding to routes
+	// whose first hop is a peer with a score of zero. Routes through
+	// peers with higher scores are penalized proportionally less. It's
+	// small compared to the penalty of a payment failure, so a flaky peer
+	// is merely avoided if an alternative of similar length exists.
+	peerPenaltyWeight = 100
+)
+
+// peerStats are the measurements of a peer's connection quality.
+type peerStats struct {
+	// firstSeen is the time the peer first connected to us since start
+	// up.
+	firstSeen time.Time
+
+	// connectedSince is the time the peer's current connection was
+	// established, or zero if the peer isn't connected.
+	connectedSince time.Time
+
+	// uptime is the total duration the peer has been connected, not
+	// counting its current connection.
+	uptime time.Duration
+
+	// latency is the moving average of the peer's ping round trip time.
+	latency time.Duration
+
+	// resolution is the moving average of the time the peer takes to
+	// settle or fail the HTLCs we offer it.
+	resolution time.Duration
+
+	// numResolved is the number of HTLCs the peer has resolved.
+	numResolved uint64
+}
+
+// peerScore is a snapshot of a peer's connection quality.
+type peerScore struct {
+	// Uptime is the fraction of time the peer has been connected since it
+	// first connected to us.
+	Uptime float64
+
+	// Latency is the moving average of the peer's ping round trip time.
+	Latency time.Duration
+
+	// HTLCResolution is the moving average of the time the peer takes to
+	// resolve the HTLCs we offer it.
+	HTLCResolution time.Duration
+
+	// Score is the overall quality of the peer's connection, between zero
+	// and one, accounting for each of the above.
+	Score float64
+}
+
+// peerScorer tracks the connection quality of each of our peers: the
+// fraction of time they're connected, their latency, and the time they take
+// to resolve the HTLCs we offer them. As a flaky direct peer dominates the
+// latency of payments routed through it, the resulting scores can be used to
+// bias path finding toward first hops with better peers.
+//
+// NOTE: Measurements are only kept in memory, and begin anew with each
+// start up.
+type peerScorer struct {
+	// now returns the current time. It's replaced within tests.
+	now func() time.Time
+
+	peers map[[33]byte]*peerStats
+	sync.Mutex
+}
+
+// newPeerScorer creates a new peerScorer without any measurements.
+func newPeerScorer() *peerScorer {
+	return &peerScorer{
+		now:   time.Now,
+		peers: make(map[[33]byte]*peerStats),
+	}
+}
+
+// stats returns the measurements of the passed peer, creating them if the
+// peer hasn't yet been seen.
+//
+// NOTE: The scorer's mutex MUST be held when calling this method.
+func (s *peerScorer) stats(peer *btcec.PublicKey) *peerStats {
+	var key [33]byte
+	copy(key[:], peer.SerializeCompressed())
+
+	stats, ok := s.peers[key]
+	if !ok {
+		stats = &peerStats{firstSeen: s.now()}
+		s.peers[key] = stats
+	}
+
+	return stats
+}
+
+// peerConnected records that the passed peer has connected to us.
+func (s *peerScorer) peerConnected(peer *btcec.PublicKey) {
+	s.Lock()
+	defer s.Unlock()
+
+	stats := s.stats(peer)
+	if stats.connectedSince.IsZero() {
+		stats.connectedSince = s.now()
+	}
+}
+
+// peerDisconnected records that the passed peer has disconnected from us.
+func (s *peerScorer) peerDisconnected(peer *btcec.PublicKey) {
+	s.Lock()
+	defer s.Unlock()
+
+	stats := s.stats(peer)
+	if !stats.connectedSince.IsZero() {
+		stats.uptime += s.now().Sub(stats.connectedSince)
+		stats.connectedSince = time.Time{}
+	}
+}
+
+// pingMeasured records a ping round trip time measured for the passed peer.
+func (s *peerScorer) pingMeasured(peer *btcec.PublicKey, rtt time.Duration) {
+	s.Lock()
+	defer s.Unlock()
+
+	stats := s.stats(peer)
+	stats.latency = movingAverage(stats.latency, rtt, stats.latency == 0)
+}
+
+// htlcResolved records that the passed peer settled or failed an HTLC we
+// offered it after the passed duration.
+func (s *peerScorer) htlcResolved(peer *btcec.PublicKey,
+	elapsed time.Duration) {
+
+	s.Lock()
+	defer s.Unlock()
+
+	stats := s.stats(peer)
+	stats.resolution = movingAverage(stats.resolution, elapsed,
+		stats.numResolved == 0)
+	stats.numResolved++
+}
+
+// movingAverage folds the passed sample into an exponentially weighted moving
+// average. If first is true, then the sample becomes the average.
+func movingAverage(avg, sample time.Duration, first bool) time.Duration {
+	if first {
+		return sample
+	}
+
+	return time.Duration(peerScoreSmoothing*float64(sample) +
+		(1-peerScoreSmoothing)*float64(avg))
+}
+
+// score returns a snapshot of the connection quality of the passed peer. A
+// peer which hasn't connected since start up has a score of zero.
+func (s *peerScorer) score(peer *btcec.PublicKey) *peerScore {
+	s.Lock()
+	defer s.Unlock()
+
+	var key [33]byte
+	copy(key[:], peer.SerializeCompressed())
+
+	stats, ok := s.peers[key]
+	if !ok {
+		return &peerScore{}
+	}
+
+	now := s.now()
+	uptime := stats.uptime
+	if !stats.connectedSince.IsZero() {
+		uptime += now.Sub(stats.connectedSince)
+	}
+
+	// A peer which has only just connected is given the benefit of the
+	// doubt.
+	uptimeFrac := 1.0
+	if observed := now.Sub(stats.firstSeen); observed > 0 {
+		uptimeFrac = float64(uptime) / float64(observed)
+	}
+
+	// Both latency and resolution time discount the score
+	// hyperbolically, halving it at their reference values. Peers
+	// without any measurements aren't discounted.
+	latencyFactor := float64(referenceLatency) /
+		float64(referenceLatency+stats.latency)
+	resolutionFactor := float64(referenceResolution) /
+		float64(referenceResolution+stats.resolution)
+
+	return &peerScore{
+		Uptime:         uptimeFrac,
+		Latency:        stats.latency,
+		HTLCResolution: stats.resolution,
+		Score:          uptimeFrac * latencyFactor * resolutionFactor,
+	}
+}
+
+// firstHopPenalty returns the weight added during path finding to routes
+// whose first hop is the passed peer.
+func (s *peerScorer) firstHopPenalty(peer *btcec.PublicKey) float64 {
+	return peerPenaltyWeight * (1 - s.score(peer).Score)
+}
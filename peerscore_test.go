@@ -0,0 +1,79 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/roasbeef/btcd/btcec"
+)
+
+func TestPeerScorer(t *testing.T) {
+	var peers [2]*btcec.PublicKey
+	for i := range peers {
+		priv, err := btcec.NewPrivateKey(btcec.S256())
+		if err != nil {
+			t.Fatalf("unable to generate key: %v", err)
+		}
+		peers[i] = priv.PubKey()
+	}
+	reliable, flaky := peers[0], peers[1]
+
+	now := time.Unix(1490000000, 0)
+	scorer := newPeerScorer()
+	scorer.now = func() time.Time { return now }
+
+	// A peer we've never seen has the lowest score, and the largest
+	// penalty.
+	if score := scorer.score(reliable); score.Score != 0 {
+		t.Fatalf("expected unseen peer to have no score, got %v",
+			score.Score)
+	}
+	if penalty := scorer.firstHopPenalty(reliable); penalty != peerPenaltyWeight {
+		t.Fatalf("expected penalty of %v, got %v", peerPenaltyWeight,
+			penalty)
+	}
+
+	// Both peers connect, but the flaky one is only connected for a
+	// quarter of the time.
+	scorer.peerConnected(reliable)
+	scorer.peerConnected(flaky)
+	now = now.Add(time.Hour)
+	scorer.peerDisconnected(flaky)
+	now = now.Add(time.Hour * 3)
+
+	if score := scorer.score(reliable); score.Uptime != 1 || score.Score != 1 {
+		t.Fatalf("expected perfect score for reliable peer, got %v",
+			score)
+	}
+	if score := scorer.score(flaky); score.Uptime != 0.25 {
+		t.Fatalf("expected uptime of 0.25 for flaky peer, got %v",
+			score.Uptime)
+	}
+
+	// The first sample of latency is taken as is, while later samples
+	// are averaged.
+	scorer.pingMeasured(reliable, referenceLatency)
+	score := scorer.score(reliable)
+	if score.Latency != referenceLatency || score.Score != 0.5 {
+		t.Fatalf("expected latency of %v to halve score, got %v",
+			referenceLatency, score)
+	}
+	scorer.pingMeasured(reliable, 0)
+	if score := scorer.score(reliable); score.Latency != referenceLatency*8/10 {
+		t.Fatalf("expected averaged latency of %v, got %v",
+			referenceLatency*8/10, score.Latency)
+	}
+
+	scorer.htlcResolved(flaky, referenceResolution)
+	if score := scorer.score(flaky); score.HTLCResolution != referenceResolution ||
+		score.Score != 0.125 {
+
+		t.Fatalf("unexpected score for flaky peer: %v", score)
+	}
+
+	// Finally, routes through the reliable peer should be penalized less
+	// than routes through the flaky one.
+	if scorer.firstHopPenalty(reliable) >= scorer.firstHopPenalty(flaky) {
+		t.Fatalf("reliable peer should be penalized less than flaky peer")
+	}
+}
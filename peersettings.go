@@ -0,0 +1,238 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcutil"
+)
+
+// peerSettingsStore holds the settings the operator has set for individual
+// peers, which override those applied to all other peers. The settings are
+// consulted by the funding manager, the router and the switch, so they're
+// cached in memory, and persisted within the database so they survive
+// restarts.
+type peerSettingsStore struct {
+	db *channeldb.DB
+
+	mtx      sync.RWMutex
+	settings map[[33]byte]*channeldb.PeerSettings
+}
+
+// newPeerSettingsStore creates a new peerSettingsStore, loading the settings
+// of each peer from the passed database.
+func newPeerSettingsStore(db *channeldb.DB) (*peerSettingsStore, error) {
+	settings, err := db.FetchAllPeerSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	return &peerSettingsStore{
+		db:       db,
+		settings: settings,
+	}, nil
+}
+
+// get returns the settings of the passed peer, or nil if the peer is treated
+// like any other. The returned settings must not be modified.
+func (s *peerSettingsStore) get(peer *btcec.PublicKey) *channeldb.PeerSettings {
+	var key [33]byte
+	copy(key[:], peer.SerializeCompressed())
+
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	return s.settings[key]
+}
+
+// all returns the settings of each peer, keyed by the peer's serialized public
+// key.
+func (s *peerSettingsStore) all() map[[33]byte]*channeldb.PeerSettings {
+	s.mtx.RLock()
+	defer s.mtx.RUnlock()
+
+	all := make(map[[33]byte]*channeldb.PeerSettings, len(s.settings))
+	for peer, settings := range s.settings {
+		all[peer] = settings
+	}
+
+	return all
+}
+
+// set persists the settings of the passed peer, replacing any settings
+// previously set.
+func (s *peerSettingsStore) set(peer *btcec.PublicKey,
+	settings *channeldb.PeerSettings) error {
+
+	var key [33]byte
+	copy(key[:], peer.SerializeCompressed())
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if err := s.db.PutPeerSettings(peer, settings); err != nil {
+		return err
+	}
+	s.settings[key] = settings
+
+	srvrLog.Infof("Settings of peer %x updated: %+v", key[:], *settings)
+
+	return nil
+}
+
+// remove deletes the settings of the passed peer, so it's treated like any
+// other peer.
+func (s *peerSettingsStore) remove(peer *btcec.PublicKey) error {
+	var key [33]byte
+	copy(key[:], peer.SerializeCompressed())
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if err := s.db.DeletePeerSettings(peer); err != nil {
+		return err
+	}
+	delete(s.settings, key)
+
+	srvrLog.Infof("Settings of peer %x removed", key[:])
+
+	return nil
+}
+
+// gossipSyncMode returns the gossip sync mode set for the passed peer.
+func (s *peerSettingsStore) gossipSyncMode(
+	peer *btcec.PublicKey) channeldb.GossipSyncMode {
+
+	settings := s.get(peer)
+	if settings == nil {
+		return channeldb.GossipSyncDefault
+	}
+
+	return settings.GossipSyncMode
+}
+
+// parseGossipSyncMode parses the name of a gossip sync mode, as returned by
+// its String method. An empty name selects the default mode.
+func parseGossipSyncMode(name string) (channeldb.GossipSyncMode, error) {
+	switch name {
+	case "", "default":
+		return channeldb.GossipSyncDefault, nil
+	case "active":
+		return channeldb.GossipSyncActive, nil
+	case "passive":
+		return channeldb.GossipSyncPassive, nil
+	default:
+		return 0, fmt.Errorf("unknown gossip sync mode %q", name)
+	}
+}
+
+// unmarshalPeerSettings converts the RPC representation of a peer's settings
+// into the peer's public key and the settings to be stored.
+func unmarshalPeerSettings(
+	in *lnrpc.PeerSettings) (*btcec.PublicKey, *channeldb.PeerSettings, error) {
+
+	pubBytes, err := hex.DecodeString(in.PubKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	peer, err := btcec.ParsePubKey(pubBytes, btcec.S256())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	mode, err := parseGossipSyncMode(in.GossipSyncMode)
+	if err != nil {
+		return nil, nil, err
+	}
+	if in.MaxHtlcValue < 0 {
+		return nil, nil, fmt.Errorf("max htlc value can't be negative")
+	}
+
+	settings := &channeldb.PeerSettings{
+		MaxChannels:     in.MaxChannels,
+		GossipSyncMode:  mode,
+		MaxPendingHTLCs: in.MaxPendingHtlcs,
+		MaxHTLCValue:    btcutil.Amount(in.MaxHtlcValue),
+	}
+	if in.FeePolicy != nil {
+		if err := validateRoutingPolicy(in.FeePolicy); err != nil {
+			return nil, nil, err
+		}
+		settings.FeePolicy = &channeldb.PeerFeePolicy{
+			TimeLockDelta: uint16(in.FeePolicy.TimeLockDelta),
+			MinHTLC:       btcutil.Amount(in.FeePolicy.MinHtlc),
+			FeeBaseMSat:   btcutil.Amount(in.FeePolicy.FeeBaseMsat),
+			FeeProportionalMillionths: btcutil.Amount(
+				in.FeePolicy.FeeRateMilliMsat),
+		}
+	}
+
+	return peer, settings, nil
+}
+
+// marshalPeerSettings converts the settings of the peer with the passed
+// serialized public key into their RPC representation.
+func marshalPeerSettings(peer [33]byte,
+	settings *channeldb.PeerSettings) *lnrpc.PeerSettings {
+
+	rpcSettings := &lnrpc.PeerSettings{
+		PubKey:          hex.EncodeToString(peer[:]),
+		MaxChannels:     settings.MaxChannels,
+		GossipSyncMode:  settings.GossipSyncMode.String(),
+		MaxPendingHtlcs: settings.MaxPendingHTLCs,
+		MaxHtlcValue:    int64(settings.MaxHTLCValue),
+	}
+	if p := settings.FeePolicy; p != nil {
+		rpcSettings.FeePolicy = &lnrpc.RoutingPolicy{
+			TimeLockDelta:    uint32(p.TimeLockDelta),
+			MinHtlc:          int64(p.MinHTLC),
+			FeeBaseMsat:      int64(p.FeeBaseMSat),
+			FeeRateMilliMsat: int64(p.FeeProportionalMillionths),
+		}
+	}
+
+	return rpcSettings
+}
+
+// peerSettingsByPubKey sorts the RPC representations of peer settings by the
+// public keys of their peers.
+type peerSettingsByPubKey []*lnrpc.PeerSettings
+
+func (p peerSettingsByPubKey) Len() int      { return len(p) }
+func (p peerSettingsByPubKey) Swap(i, j int) { p[i], p[j] = p[j], p[i] }
+func (p peerSettingsByPubKey) Less(i, j int) bool {
+	return p[i].PubKey < p[j].PubKey
+}
+
+// peerFeePolicyUpdates returns the routing policies to be applied to our
+// existing channels with the passed peer, such that they match the passed fee
+// policy. The current policies are those returned by selfPolicies.
+func peerFeePolicyUpdates(current map[string]*channeldb.ChannelEdgePolicy,
+	channels []*channeldb.OpenChannel,
+	feePolicy *lnrpc.RoutingPolicy) ([]*channeldb.ChannelEdgePolicy, error) {
+
+	var imported []*lnrpc.ChannelPolicy
+	for _, channel := range channels {
+		chanPoint := channel.ChanID.String()
+
+		// Channels which are yet to be announced have no policy
+		// within the graph, and will be announced with the peer's fee
+		// policy.
+		if policy, ok := current[chanPoint]; !ok || policy == nil {
+			continue
+		}
+
+		imported = append(imported, &lnrpc.ChannelPolicy{
+			ChanPoint: chanPoint,
+			Policy:    feePolicy,
+		})
+	}
+
+	_, updates, err := diffChannelPolicies(current, imported, time.Now())
+	return updates, err
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"encoding/hex"
+	"reflect"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"github.com/roasbeef/btcd/btcec"
+)
+
+// TestPeerSettingsMarshalling tests that peer settings survive the round trip
+// through their RPC representation, and that invalid settings are rejected.
+func TestPeerSettingsMarshalling(t *testing.T) {
+	priv, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to create key: %v", err)
+	}
+	pubKey := hex.EncodeToString(priv.PubKey().SerializeCompressed())
+
+	tests := []struct {
+		name     string
+		in       *lnrpc.PeerSettings
+		expected *lnrpc.PeerSettings
+		valid    bool
+	}{
+		{
+			name: "all settings",
+			in: &lnrpc.PeerSettings{
+				PubKey: pubKey,
+				FeePolicy: &lnrpc.RoutingPolicy{
+					TimeLockDelta:    40,
+					MinHtlc:          1,
+					FeeBaseMsat:      1000,
+					FeeRateMilliMsat: 10,
+				},
+				MaxChannels:     3,
+				GossipSyncMode:  "passive",
+				MaxPendingHtlcs: 20,
+				MaxHtlcValue:    50000,
+			},
+			valid: true,
+		},
+		{
+			name: "default sync mode",
+			in: &lnrpc.PeerSettings{
+				PubKey:      pubKey,
+				MaxChannels: 1,
+			},
+			expected: &lnrpc.PeerSettings{
+				PubKey:         pubKey,
+				MaxChannels:    1,
+				GossipSyncMode: "default",
+			},
+			valid: true,
+		},
+		{
+			name: "invalid pub key",
+			in: &lnrpc.PeerSettings{
+				PubKey: pubKey[2:],
+			},
+		},
+		{
+			name: "unknown sync mode",
+			in: &lnrpc.PeerSettings{
+				PubKey:         pubKey,
+				GossipSyncMode: "eager",
+			},
+		},
+		{
+			name: "negative max htlc value",
+			in: &lnrpc.PeerSettings{
+				PubKey:       pubKey,
+				MaxHtlcValue: -1,
+			},
+		},
+		{
+			name: "fee rate out of range",
+			in: &lnrpc.PeerSettings{
+				PubKey: pubKey,
+				FeePolicy: &lnrpc.RoutingPolicy{
+					FeeRateMilliMsat: 1 << 32,
+				},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		peer, settings, err := unmarshalPeerSettings(test.in)
+		if !test.valid {
+			if err == nil {
+				t.Fatalf("%v: expected settings to be rejected",
+					test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%v: unable to unmarshal settings: %v",
+				test.name, err)
+		}
+		if !peer.IsEqual(priv.PubKey()) {
+			t.Fatalf("%v: wrong peer %x", test.name,
+				peer.SerializeCompressed())
+		}
+
+		var key [33]byte
+		copy(key[:], peer.SerializeCompressed())
+		out := marshalPeerSettings(key, settings)
+
+		expected := test.expected
+		if expected == nil {
+			expected = test.in
+		}
+		if !reflect.DeepEqual(out, expected) {
+			t.Fatalf("%v: expected %v, got %v", test.name,
+				expected, out)
+		}
+	}
+}
@@ -0,0 +1,227 @@
+package main
+
+import (
+	"bytes"
+
+	"github.com/lightningnetwork/lnd/chanbackup"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/btcec"
+)
+
+// peerStorageFeature is the name of the local feature advertised by nodes
+// which exchange backups via peer storage.
+const peerStorageFeature = "peer-storage"
+
+// peerStorage stores an encrypted backup of our latest state with each peer
+// we have channels with, and holds the backup each such peer stores with us
+// in return. Backups are returned to their owner each time it reconnects.
+// As a result, a node restoring from its seed without any other backup is
+// able to recover enough state from its peers to cooperatively close its
+// channels.
+type peerStorage struct {
+	chanDB *channeldb.DB
+
+	// backupKey is the key our own backups are encrypted with. As it's
+	// derived from our identity key, it's recoverable from the seed.
+	backupKey [32]byte
+}
+
+// newPeerStorage creates a new peerStorage which encrypts our backups with a
+// key derived from the passed identity key.
+func newPeerStorage(chanDB *channeldb.DB,
+	idKey *btcec.PrivateKey) *peerStorage {
+
+	return &peerStorage{
+		chanDB:    chanDB,
+		backupKey: chanbackup.DeriveBackupKey(idKey),
+	}
+}
+
+// peerConnected returns the backup the peer stored with us, if any, and
+// sends the peer the latest backup of our channels with it.
+func (s *peerStorage) peerConnected(p *peer) {
+	if !p.localSharedFeatures.IsActive(peerStorageFeature) {
+		return
+	}
+
+	blob, err := s.chanDB.FetchPeerStorage(p.addr.IdentityKey)
+	switch {
+	case err == nil:
+		p.queueMsg(&lnwire.PeerStorageRetrieval{Blob: blob}, nil)
+	case err != channeldb.ErrPeerStorageNotFound:
+		peerLog.Errorf("unable to fetch backup stored for %v: %v",
+			p, err)
+	}
+
+	s.sendBackup(p)
+}
+
+// sendBackup sends the peer a backup of the latest state of each of our
+// channels with it, replacing the backup it previously stored.
+func (s *peerStorage) sendBackup(p *peer) {
+	if !p.localSharedFeatures.IsActive(peerStorageFeature) {
+		return
+	}
+
+	channels, err := s.chanDB.FetchOpenChannels(p.addr.IdentityKey)
+	if err != nil {
+		peerLog.Errorf("unable to fetch channels with %v: %v", p, err)
+		return
+	}
+	if len(channels) == 0 {
+		return
+	}
+
+	var b bytes.Buffer
+	blob := chanbackup.NewPeerBlob(channels)
+	if err := blob.PackToWriter(&b, s.backupKey); err != nil {
+		peerLog.Errorf("unable to pack backup for %v: %v", p, err)
+		return
+	}
+	if b.Len() > lnwire.MaxPeerStorageBlob {
+		peerLog.Warnf("Backup of %v channels with %v exceeds the peer "+
+			"storage limit, not sending", len(channels), p)
+		return
+	}
+
+	p.queueMsg(&lnwire.PeerStorage{Blob: b.Bytes()}, nil)
+}
+
+// processPeerStorage stores the backup sent by the peer on its behalf. To
+// bound the space peers are able to consume, backups are only stored for
+// peers we have channels with.
+func (s *peerStorage) processPeerStorage(p *peer, msg *lnwire.PeerStorage) {
+	channels, err := s.chanDB.FetchOpenChannels(p.addr.IdentityKey)
+	if err != nil {
+		peerLog.Errorf("unable to fetch channels with %v: %v", p, err)
+		return
+	}
+	if len(channels) == 0 {
+		peerLog.Debugf("Ignoring backup from %v, as we have no "+
+			"channels with it", p)
+		return
+	}
+
+	if err := s.chanDB.PutPeerStorage(p.addr.IdentityKey, msg.Blob); err != nil {
+		peerLog.Errorf("unable to store backup for %v: %v", p, err)
+	}
+}
+
+// processRetrieval handles the backup of our own state returned by the peer.
+// Each channel within the backup which we have no record of is reconstructed
+// as a recovered channel, which may then be cooperatively closed with the
+// peer.
+func (s *peerStorage) processRetrieval(p *peer,
+	msg *lnwire.PeerStorageRetrieval) {
+
+	var blob chanbackup.PeerBlob
+	err := blob.UnpackFromReader(bytes.NewReader(msg.Blob), s.backupKey)
+	if err != nil {
+		peerLog.Warnf("Unable to unpack backup returned by %v: %v", p,
+			err)
+		return
+	}
+
+	known, err := s.chanDB.FetchOpenChannels(p.addr.IdentityKey)
+	if err != nil {
+		peerLog.Errorf("unable to fetch channels with %v: %v", p, err)
+		return
+	}
+
+	for _, backup := range blob.Channels {
+		chanPoint := backup.ChanPoint
+		if !backup.RemoteNodePub.IsEqual(p.addr.IdentityKey) {
+			peerLog.Warnf("Backup of ChannelPoint(%v) returned by "+
+				"%v belongs to another peer", chanPoint, p)
+			continue
+		}
+
+		if s.checkKnownChannel(p, known, &backup) {
+			continue
+		}
+		closed, err := s.chanDB.IsChannelClosed(&chanPoint)
+		if err != nil {
+			peerLog.Errorf("unable to query closed channels: %v",
+				err)
+			return
+		}
+		if closed {
+			continue
+		}
+
+		if err := s.recoverChannel(p, &backup); err != nil {
+			peerLog.Errorf("unable to recover ChannelPoint(%v) "+
+				"from backup returned by %v: %v", chanPoint, p,
+				err)
+			continue
+		}
+	}
+}
+
+// checkKnownChannel returns true if the backed up channel is one of the
+// passed channels we already have a record of. If our record is older than
+// the backup, then we've lost state, which is loudly reported, as any
+// attempt to broadcast our commitment would risk a breach.
+func (s *peerStorage) checkKnownChannel(p *peer,
+	known []*channeldb.OpenChannel, backup *chanbackup.PeerChannel) bool {
+
+	for _, channel := range known {
+		if *channel.ChanID != backup.ChanPoint {
+			continue
+		}
+
+		if channel.ChanType != channeldb.RecoveredChannel &&
+			backup.NumUpdates > channel.NumUpdates {
+
+			peerLog.Criticalf("Backup of ChannelPoint(%v) returned "+
+				"by %v is at state %v, while our own state is "+
+				"at %v. Our channel state is stale, DO NOT "+
+				"force close the channel!", backup.ChanPoint, p,
+				backup.NumUpdates, channel.NumUpdates)
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// recoverChannel writes a recovered channel reconstructed from the backup to
+// the database, and hands it to the peer so it may be cooperatively closed.
+func (s *peerStorage) recoverChannel(p *peer,
+	backup *chanbackup.PeerChannel) error {
+
+	channel, err := backup.Recover(s.chanDB)
+	if err != nil {
+		return err
+	}
+	if err := channel.SyncPending(p.addr.Address); err != nil {
+		return err
+	}
+	if err := s.chanDB.MarkChannelAsOpen(channel.ChanID); err != nil {
+		return err
+	}
+	p.server.chanBackup.requestUpdate()
+
+	peerLog.Infof("Recovered ChannelPoint(%v) with %v from peer "+
+		"storage, local_balance=%v, remote_balance=%v", channel.ChanID,
+		p, channel.OurBalance, channel.TheirBalance)
+
+	lnChan, err := lnwallet.NewLightningChannel(p.server.lnwallet.Signer,
+		p.server.chainNotifier, channel)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case p.newChannels <- &newChannelMsg{
+		channel: lnChan,
+		done:    make(chan struct{}),
+	}:
+	case <-p.quit:
+	}
+
+	return nil
+}
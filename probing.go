@@ -0,0 +1,128 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcutil"
+)
+
+// maxFinalCltvPadding is the largest number of blocks the time lock of the
+// final hop of our payments may be padded by.
+const maxFinalCltvPadding = 1008
+
+// probeDefenseConfig defines the options of our defense against balance
+// probing, in which HTLCs carrying a payment hash unknown to their
+// destination are sent in order to learn whether the channels along their
+// route could carry their amount.
+type probeDefenseConfig struct {
+	FinalCltvPadding   uint16        `long:"finalcltvpadding" description:"The largest number of blocks, chosen at random for each route, added to the time lock of the final hop of the payments we send, so it doesn't reveal the distance to their destination. A value of zero disables padding"`
+	MaxIdenticalProbes int           `long:"maxidenticalprobes" description:"The number of HTLCs of the same amount carrying an unknown payment hash which are failed as such within the probe window. Further such HTLCs are failed as if our channel lacked the capacity to carry them. A value of zero disables the defense"`
+	ProbeWindow        time.Duration `long:"probewindow" description:"The period over which HTLCs carrying an unknown payment hash are counted toward maxidenticalprobes"`
+}
+
+// validate checks the probe defense options for consistency.
+func (c *probeDefenseConfig) validate() error {
+	if c.FinalCltvPadding > maxFinalCltvPadding {
+		return fmt.Errorf("probing.finalcltvpadding mustn't exceed %v "+
+			"blocks", maxFinalCltvPadding)
+	}
+	if c.MaxIdenticalProbes < 0 {
+		return fmt.Errorf("probing.maxidenticalprobes mustn't be " +
+			"negative")
+	}
+	if c.MaxIdenticalProbes > 0 && c.ProbeWindow <= 0 {
+		return fmt.Errorf("probing.probewindow must be positive")
+	}
+
+	return nil
+}
+
+// probeDetector tracks the HTLCs we receive as their final destination which
+// carry a payment hash we don't know. Such HTLCs can't be payments, and are
+// most likely probes, whose sender learns from our UnknownPaymentHash failure
+// that the route, including our own channel, could carry the HTLC's amount.
+//
+// A prober mapping the balance of our channels repeatedly sends HTLCs of the
+// same amount, along different routes or at different times. Once the number
+// of probes of an amount within the probe window exceeds the configured
+// maximum, further probes of that amount are failed with
+// InsufficientCapacity instead, as if they never reached us, so the prober
+// can no longer rely on the answers it receives.
+type probeDetector struct {
+	// numSuspected is the number of HTLCs carrying an unknown payment hash
+	// we've received as their final destination.
+	numSuspected uint64 // atomic
+
+	// numMasked is the number of those HTLCs which were failed as if our
+	// channel lacked the capacity to carry them.
+	numMasked uint64 // atomic
+
+	cfg *probeDefenseConfig
+
+	// now returns the current time. It's replaced within tests.
+	now func() time.Time
+
+	// probes maps the amount of each probe received within the probe
+	// window to the times it was received at, in ascending order.
+	probes map[btcutil.Amount][]time.Time
+
+	sync.Mutex
+}
+
+// newProbeDetector creates a new probeDetector which hasn't seen any probes.
+func newProbeDetector(cfg *probeDefenseConfig) *probeDetector {
+	return &probeDetector{
+		cfg:    cfg,
+		now:    time.Now,
+		probes: make(map[btcutil.Amount][]time.Time),
+	}
+}
+
+// failCode records the receipt of an HTLC of the passed amount which carries
+// an unknown payment hash, and for which we're the final destination. The
+// code the HTLC is to be failed with is returned.
+func (p *probeDetector) failCode(amt btcutil.Amount) lnwire.FailCode {
+	atomic.AddUint64(&p.numSuspected, 1)
+
+	if p.cfg.MaxIdenticalProbes == 0 {
+		return lnwire.UnknownPaymentHash
+	}
+
+	p.Lock()
+	defer p.Unlock()
+
+	// Forget the probes which fell out of the window, so the number of
+	// amounts tracked is bounded by the rate at which probes arrive.
+	now := p.now()
+	cutoff := now.Add(-p.cfg.ProbeWindow)
+	for probeAmt, times := range p.probes {
+		i := 0
+		for i < len(times) && !times[i].After(cutoff) {
+			i++
+		}
+		if i == len(times) {
+			delete(p.probes, probeAmt)
+		} else {
+			p.probes[probeAmt] = times[i:]
+		}
+	}
+
+	p.probes[amt] = append(p.probes[amt], now)
+	if len(p.probes[amt]) <= p.cfg.MaxIdenticalProbes {
+		return lnwire.UnknownPaymentHash
+	}
+
+	atomic.AddUint64(&p.numMasked, 1)
+	return lnwire.InsufficientCapacity
+}
+
+// stats returns the number of suspected probes we've received, along with
+// the number of those which were failed as if our channel lacked capacity.
+func (p *probeDetector) stats() (uint64, uint64) {
+	return atomic.LoadUint64(&p.numSuspected),
+		atomic.LoadUint64(&p.numMasked)
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcutil"
+)
+
+// TestProbeDetector tests that repeated probes of the same amount are masked
+// once they exceed the configured maximum within the probe window, and that
+// probes which fell out of the window are forgotten.
+func TestProbeDetector(t *testing.T) {
+	cfg := &probeDefenseConfig{
+		MaxIdenticalProbes: 2,
+		ProbeWindow:        time.Minute,
+	}
+	now := time.Unix(1490000000, 0)
+	detector := newProbeDetector(cfg)
+	detector.now = func() time.Time { return now }
+
+	expectCode := func(amt btcutil.Amount, code lnwire.FailCode) {
+		if c := detector.failCode(amt); c != code {
+			t.Fatalf("expected probe of %v to be failed with %v, "+
+				"got %v", amt, code, c)
+		}
+	}
+
+	// The first probes of an amount are answered truthfully, while those
+	// beyond the maximum are masked. Probes of other amounts aren't
+	// affected.
+	expectCode(1000, lnwire.UnknownPaymentHash)
+	expectCode(1000, lnwire.UnknownPaymentHash)
+	expectCode(2000, lnwire.UnknownPaymentHash)
+	expectCode(1000, lnwire.InsufficientCapacity)
+
+	// Once the earlier probes fall out of the window, the amount is
+	// answered truthfully again, and the other amount is forgotten.
+	now = now.Add(time.Minute)
+	expectCode(1000, lnwire.UnknownPaymentHash)
+	if _, ok := detector.probes[2000]; ok {
+		t.Fatalf("expected expired probes to be forgotten")
+	}
+
+	suspected, masked := detector.stats()
+	if suspected != 5 || masked != 1 {
+		t.Fatalf("expected 5 suspected and 1 masked probes, got %v "+
+			"and %v", suspected, masked)
+	}
+
+	// With the defense disabled, probes are only counted.
+	detector = newProbeDetector(&probeDefenseConfig{})
+	for i := 0; i < 5; i++ {
+		expectCode(1000, lnwire.UnknownPaymentHash)
+	}
+	if suspected, masked := detector.stats(); suspected != 5 ||
+		masked != 0 {
+
+		t.Fatalf("expected 5 suspected and no masked probes, got %v "+
+			"and %v", suspected, masked)
+	}
+}
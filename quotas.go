@@ -0,0 +1,523 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/roasbeef/btcutil"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// credentialMetadataKey is the gRPC metadata key clients present their
+	// credential token under. The REST proxy forwards the
+	// Grpc-Metadata-Credential header under this key.
+	credentialMetadataKey = "credential"
+
+	// credentialTokenSize is the size in bytes of a credential token.
+	credentialTokenSize = 32
+
+	// quotaWindow is the longest period any quota is measured over. Usage
+	// older than this is pruned from the database.
+	quotaWindow = time.Hour * 24
+)
+
+// permission is a set of the operations a credential may carry out, beyond
+// those which don't affect our channels or funds.
+type permission uint8
+
+const (
+	// permOpenChannel permits opening channels, and adding funds to
+	// existing channels.
+	permOpenChannel permission = 1 << iota
+
+	// permCoopClose permits cooperatively closing channels.
+	permCoopClose
+
+	// permForceClose permits force closing channels.
+	permForceClose
+
+	// permAbandonChannel permits abandoning channels.
+	permAbandonChannel
+
+	// permSendFunds permits sending payments, and sending funds on-chain.
+	permSendFunds
+
+	// permUpdatePolicy permits updating the routing and funding policies
+	// of our channels, and freezing channels.
+	permUpdatePolicy
+
+	// allPermissions is the set of every permission.
+	allPermissions = permOpenChannel | permCoopClose | permForceClose |
+		permAbandonChannel | permSendFunds | permUpdatePolicy
+)
+
+// permissionNames maps the name of each permission within a credential
+// definition to the permission.
+var permissionNames = map[string]permission{
+	"open":       permOpenChannel,
+	"coopclose":  permCoopClose,
+	"forceclose": permForceClose,
+	"abandon":    permAbandonChannel,
+	"send":       permSendFunds,
+	"policy":     permUpdatePolicy,
+}
+
+// String returns a description of the operations the permission permits.
+func (p permission) String() string {
+	switch p {
+	case permOpenChannel:
+		return "open channels"
+	case permCoopClose:
+		return "cooperatively close channels"
+	case permForceClose:
+		return "force close channels"
+	case permAbandonChannel:
+		return "abandon channels"
+	case permSendFunds:
+		return "send funds"
+	case permUpdatePolicy:
+		return "update channel policies"
+	default:
+		return "unknown permission"
+	}
+}
+
+// credentialQuota is the set of limits placed on the calls made with a single
+// named credential. A limit of zero disables it.
+type credentialQuota struct {
+	// name is the name of the credential, which also names its token
+	// file.
+	name string
+
+	// permissions is the set of operations affecting our channels or
+	// funds the credential may carry out.
+	permissions permission
+
+	// invoicesPerHour is the number of invoices which may be created
+	// within any hour.
+	invoicesPerHour uint32
+
+	// paymentsPerHour is the number of payments which may be attempted
+	// within any hour, regardless of whether they succeed.
+	paymentsPerHour uint32
+
+	// dailySpend is the total amount, including fees, which may be spent
+	// by payments within any 24 hour period.
+	dailySpend btcutil.Amount
+}
+
+// parseCredentialQuotas parses the operator's credential definitions, each of
+// the form name:invoices_per_hour:payments_per_hour:daily_spend_sat, followed
+// by an optional comma separated list of permissions.
+func parseCredentialQuotas(specs []string) (map[string]*credentialQuota, error) {
+	quotas := make(map[string]*credentialQuota)
+	for _, spec := range specs {
+		quota, err := parseCredentialQuota(spec)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := quotas[quota.name]; ok {
+			return nil, fmt.Errorf("credential %v is defined more "+
+				"than once", quota.name)
+		}
+		quotas[quota.name] = quota
+	}
+
+	return quotas, nil
+}
+
+// parseCredentialQuota parses a single credential definition. Credentials
+// which don't list their permissions are granted all of them, as they were
+// before permissions were introduced.
+func parseCredentialQuota(spec string) (*credentialQuota, error) {
+	parts := strings.Split(spec, ":")
+	if (len(parts) != 4 && len(parts) != 5) || parts[0] == "" {
+		return nil, fmt.Errorf("invalid credential quota %q, expected "+
+			"name:invoices_per_hour:payments_per_hour:"+
+			"daily_spend_sat[:permissions]", spec)
+	}
+
+	// As the name is used within the path of the credential's token file,
+	// it's restricted to a safe set of characters.
+	for _, c := range parts[0] {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z',
+			c >= '0' && c <= '9', c == '-', c == '_':
+		default:
+			return nil, fmt.Errorf("invalid credential name %q, "+
+				"may only contain letters, digits, '-' and '_'",
+				parts[0])
+		}
+	}
+
+	invoices, err := strconv.ParseUint(parts[1], 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid invoices per hour for "+
+			"credential %v: %v", parts[0], err)
+	}
+	payments, err := strconv.ParseUint(parts[2], 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid payments per hour for "+
+			"credential %v: %v", parts[0], err)
+	}
+	dailySpend, err := strconv.ParseInt(parts[3], 10, 64)
+	if err != nil || dailySpend < 0 {
+		return nil, fmt.Errorf("invalid daily spend for credential "+
+			"%v, must be a non-negative integer", parts[0])
+	}
+
+	permissions := allPermissions
+	if len(parts) == 5 {
+		permissions = 0
+		for _, name := range strings.Split(parts[4], ",") {
+			if name == "" {
+				continue
+			}
+			perm, ok := permissionNames[name]
+			if !ok {
+				return nil, fmt.Errorf("unknown permission %q "+
+					"for credential %v", name, parts[0])
+			}
+			permissions |= perm
+		}
+	}
+
+	return &credentialQuota{
+		name:            parts[0],
+		permissions:     permissions,
+		invoicesPerHour: uint32(invoices),
+		paymentsPerHour: uint32(payments),
+		dailySpend:      btcutil.Amount(dailySpend),
+	}, nil
+}
+
+// quotaEnforcer authenticates the credential an RPC call is made with, and
+// enforces the credential's quotas. The usage of each credential is recorded
+// within the database, so quotas can't be reset by restarting the node.
+//
+// Once any credential is defined, calls made without a credential are
+// rejected, unless the operator has explicitly allowed them, in which case
+// they're unrestricted. Calls made with an unknown credential are rejected.
+type quotaEnforcer struct {
+	db *channeldb.DB
+
+	// allowUncredentialed permits calls made without a credential, even
+	// though credentials are defined.
+	allowUncredentialed bool
+
+	// credentials maps the hash of each credential's token to the
+	// credential's quota.
+	credentials map[[sha256.Size]byte]*credentialQuota
+
+	// pendingSpend is the amount of the in-flight payments of each
+	// credential. It's counted against the daily spend limit, so the
+	// limit can't be exceeded by concurrent payments.
+	pendingSpend map[string]btcutil.Amount
+
+	lastPrune time.Time
+
+	sync.Mutex
+}
+
+// newQuotaEnforcer creates a new quotaEnforcer for the passed credentials.
+// The token of each credential is read from tokenDir. If a credential doesn't
+// have a token yet, a new random one is generated and written to the
+// directory. If allowUncredentialed is set, then calls made without a
+// credential remain unrestricted.
+func newQuotaEnforcer(db *channeldb.DB, quotas map[string]*credentialQuota,
+	tokenDir string, allowUncredentialed bool) (*quotaEnforcer, error) {
+
+	q := &quotaEnforcer{
+		db:                  db,
+		allowUncredentialed: allowUncredentialed,
+		credentials:         make(map[[sha256.Size]byte]*credentialQuota),
+		pendingSpend:        make(map[string]btcutil.Amount),
+	}
+	if len(quotas) == 0 {
+		return q, nil
+	}
+
+	if err := os.MkdirAll(tokenDir, 0700); err != nil {
+		return nil, err
+	}
+	for name, quota := range quotas {
+		tokenPath := filepath.Join(tokenDir, name+".token")
+		token, err := readCredentialToken(tokenPath)
+		if os.IsNotExist(err) {
+			token, err = createCredentialToken(tokenPath)
+			if err == nil {
+				rpcsLog.Infof("Created token for credential %v "+
+					"at %v", name, tokenPath)
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to load token for "+
+				"credential %v: %v", name, err)
+		}
+
+		q.credentials[sha256.Sum256(token)] = quota
+	}
+
+	return q, nil
+}
+
+// readCredentialToken reads the hex encoded token stored at path.
+func readCredentialToken(path string) ([]byte, error) {
+	encoded, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := hex.DecodeString(strings.TrimSpace(string(encoded)))
+	if err != nil {
+		return nil, err
+	}
+	if len(token) != credentialTokenSize {
+		return nil, fmt.Errorf("token must be %v bytes",
+			credentialTokenSize)
+	}
+
+	return token, nil
+}
+
+// createCredentialToken generates a new random token, and writes it hex
+// encoded to path.
+func createCredentialToken(path string) ([]byte, error) {
+	token := make([]byte, credentialTokenSize)
+	if _, err := rand.Read(token); err != nil {
+		return nil, err
+	}
+
+	encoded := []byte(hex.EncodeToString(token) + "\n")
+	if err := ioutil.WriteFile(path, encoded, 0600); err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// lookupCredential returns the quota of the credential the call with the
+// passed context was made with. If the call wasn't made with a credential,
+// and such calls are unrestricted, then nil is returned.
+func (q *quotaEnforcer) lookupCredential(
+	ctx context.Context) (*credentialQuota, error) {
+
+	md, ok := metadata.FromContext(ctx)
+	if !ok || len(md[credentialMetadataKey]) == 0 {
+		if len(q.credentials) != 0 && !q.allowUncredentialed {
+			return nil, fmt.Errorf("credential required")
+		}
+		return nil, nil
+	}
+
+	token, err := hex.DecodeString(md[credentialMetadataKey][0])
+	if err != nil {
+		return nil, fmt.Errorf("malformed credential token")
+	}
+	quota, ok := q.credentials[sha256.Sum256(token)]
+	if !ok {
+		return nil, fmt.Errorf("unknown credential")
+	}
+
+	return quota, nil
+}
+
+// usageSince tallies the usage of the named credential at or after the passed
+// time.
+func (q *quotaEnforcer) usageSince(credential string,
+	since time.Time) (uint32, uint32, btcutil.Amount, error) {
+
+	usages, err := q.db.FetchCredentialUsage(credential, since)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+
+	var (
+		invoices uint32
+		payments uint32
+		spent    btcutil.Amount
+	)
+	for _, usage := range usages {
+		switch usage.Type {
+		case channeldb.InvoiceCreatedUsage:
+			invoices++
+		case channeldb.PaymentAttemptedUsage:
+			payments++
+		case channeldb.PaymentSpentUsage:
+			spent += usage.Amount
+		}
+	}
+
+	return invoices, payments, spent, nil
+}
+
+// pruneUsage deletes usage which no longer counts against any quota. To
+// avoid a database write for every call, usage is pruned at most once an
+// hour.
+//
+// NOTE: The mutex MUST be held when calling this method.
+func (q *quotaEnforcer) pruneUsage(now time.Time) {
+	if now.Sub(q.lastPrune) < time.Hour {
+		return
+	}
+
+	if err := q.db.PruneCredentialUsage(now.Add(-quotaWindow)); err != nil {
+		rpcsLog.Errorf("unable to prune credential usage: %v", err)
+		return
+	}
+	q.lastPrune = now
+}
+
+// authorize checks whether the credential the call with the passed context
+// was made with holds the passed permission. A non-nil error is returned if it
+// doesn't.
+func (q *quotaEnforcer) authorize(ctx context.Context, perm permission) error {
+	quota, err := q.lookupCredential(ctx)
+	if err != nil || quota == nil {
+		return err
+	}
+
+	if quota.permissions&perm == 0 {
+		return fmt.Errorf("credential %v isn't permitted to %v",
+			quota.name, perm)
+	}
+
+	return nil
+}
+
+// authorizeInvoice checks whether the credential the call with the passed
+// context was made with may create another invoice, and records the invoice
+// against its quota if so. A non-nil error is returned if it may not.
+func (q *quotaEnforcer) authorizeInvoice(ctx context.Context) error {
+	quota, err := q.lookupCredential(ctx)
+	if err != nil || quota == nil {
+		return err
+	}
+
+	q.Lock()
+	defer q.Unlock()
+
+	now := time.Now()
+	q.pruneUsage(now)
+
+	if quota.invoicesPerHour != 0 {
+		invoices, _, _, err := q.usageSince(quota.name,
+			now.Add(-time.Hour))
+		if err != nil {
+			return err
+		}
+		if invoices >= quota.invoicesPerHour {
+			return fmt.Errorf("credential %v has exceeded its quota "+
+				"of %v invoices per hour", quota.name,
+				quota.invoicesPerHour)
+		}
+	}
+
+	return q.db.AddCredentialUsage(quota.name, &channeldb.CredentialUsage{
+		Type:      channeldb.InvoiceCreatedUsage,
+		Timestamp: now,
+	})
+}
+
+// authorizePayment checks whether the credential the call with the passed
+// context was made with may attempt a payment of amt, and records the attempt
+// against its quota if so. The credential must be permitted to send funds. A
+// non-nil error is returned if it may not.
+//
+// On success, a function is returned which MUST be called once the payment
+// has completed, with the total amount spent, including fees. A failed
+// payment should pass zero.
+func (q *quotaEnforcer) authorizePayment(ctx context.Context,
+	amt btcutil.Amount) (func(btcutil.Amount), error) {
+
+	quota, err := q.lookupCredential(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if quota == nil {
+		return func(btcutil.Amount) {}, nil
+	}
+	if quota.permissions&permSendFunds == 0 {
+		return nil, fmt.Errorf("credential %v isn't permitted to %v",
+			quota.name, permSendFunds)
+	}
+
+	q.Lock()
+	defer q.Unlock()
+
+	now := time.Now()
+	q.pruneUsage(now)
+
+	_, payments, _, err := q.usageSince(quota.name, now.Add(-time.Hour))
+	if err != nil {
+		return nil, err
+	}
+	if quota.paymentsPerHour != 0 && payments >= quota.paymentsPerHour {
+		return nil, fmt.Errorf("credential %v has exceeded its quota "+
+			"of %v payments per hour", quota.name,
+			quota.paymentsPerHour)
+	}
+
+	// The fees of the payment aren't known until it completes, so only
+	// its amount is counted against the limit up front.
+	if quota.dailySpend != 0 {
+		_, _, spent, err := q.usageSince(quota.name,
+			now.Add(-quotaWindow))
+		if err != nil {
+			return nil, err
+		}
+		spent += q.pendingSpend[quota.name]
+		if spent+amt > quota.dailySpend {
+			return nil, fmt.Errorf("payment of %v would exceed "+
+				"credential %v's daily spend limit of %v, "+
+				"%v has been spent", amt, quota.name,
+				quota.dailySpend, spent)
+		}
+	}
+
+	err = q.db.AddCredentialUsage(quota.name, &channeldb.CredentialUsage{
+		Type:      channeldb.PaymentAttemptedUsage,
+		Timestamp: now,
+		Amount:    amt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	q.pendingSpend[quota.name] += amt
+
+	return func(spent btcutil.Amount) {
+		q.Lock()
+		defer q.Unlock()
+
+		q.pendingSpend[quota.name] -= amt
+		if q.pendingSpend[quota.name] == 0 {
+			delete(q.pendingSpend, quota.name)
+		}
+
+		if spent == 0 {
+			return
+		}
+		err := q.db.AddCredentialUsage(quota.name,
+			&channeldb.CredentialUsage{
+				Type:      channeldb.PaymentSpentUsage,
+				Timestamp: time.Now(),
+				Amount:    spent,
+			})
+		if err != nil {
+			rpcsLog.Errorf("unable to record spend of credential "+
+				"%v: %v", quota.name, err)
+		}
+	}, nil
+}
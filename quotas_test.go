@@ -0,0 +1,232 @@
+package main
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/roasbeef/btcutil"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/metadata"
+)
+
+// credentialContext returns the context of a call made with the credential
+// whose token is stored at tokenPath.
+func credentialContext(t *testing.T, tokenPath string) context.Context {
+	token, err := readCredentialToken(tokenPath)
+	if err != nil {
+		t.Fatalf("unable to read token: %v", err)
+	}
+
+	return metadata.NewContext(context.Background(), metadata.Pairs(
+		credentialMetadataKey, hex.EncodeToString(token)))
+}
+
+func TestCredentialQuotas(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "quotas")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	db, err := channeldb.Open(tempDir)
+	if err != nil {
+		t.Fatalf("unable to open db: %v", err)
+	}
+	defer db.Close()
+
+	// Malformed definitions should be rejected.
+	for _, spec := range []string{"shop:1:2", "../shop:1:2:3", "shop:x:2:3",
+		"shop:1:2:-3"} {
+
+		if _, err := parseCredentialQuotas([]string{spec}); err == nil {
+			t.Fatalf("expected %q to be rejected", spec)
+		}
+	}
+
+	quotas, err := parseCredentialQuotas([]string{"shop:2:2:1000"})
+	if err != nil {
+		t.Fatalf("unable to parse quotas: %v", err)
+	}
+	tokenDir := filepath.Join(tempDir, "credentials")
+	enforcer, err := newQuotaEnforcer(db, quotas, tokenDir, false)
+	if err != nil {
+		t.Fatalf("unable to create quota enforcer: %v", err)
+	}
+	ctx := credentialContext(t, filepath.Join(tokenDir, "shop.token"))
+
+	// Calls made without a credential should be rejected, as credentials
+	// are defined.
+	if err := enforcer.authorizeInvoice(context.Background()); err == nil {
+		t.Fatalf("expected uncredentialed call to be rejected")
+	}
+
+	// Calls made with an unknown credential should be rejected.
+	unknownCtx := metadata.NewContext(context.Background(),
+		metadata.Pairs(credentialMetadataKey, "00"))
+	if err := enforcer.authorizeInvoice(unknownCtx); err == nil {
+		t.Fatalf("expected unknown credential to be rejected")
+	}
+
+	// The credential may only create two invoices within the hour.
+	for i := 0; i < 2; i++ {
+		if err := enforcer.authorizeInvoice(ctx); err != nil {
+			t.Fatalf("unable to authorize invoice: %v", err)
+		}
+	}
+	err = enforcer.authorizeInvoice(ctx)
+	if err == nil || !strings.Contains(err.Error(), "invoices per hour") {
+		t.Fatalf("expected invoice quota to be exceeded, got: %v", err)
+	}
+
+	// A payment exceeding the daily spend limit should be rejected
+	// outright.
+	_, err = enforcer.authorizePayment(ctx, 1001)
+	if err == nil || !strings.Contains(err.Error(), "daily spend") {
+		t.Fatalf("expected spend limit to be exceeded, got: %v", err)
+	}
+
+	// While a payment is in flight, its amount should count against the
+	// daily spend limit.
+	paymentDone, err := enforcer.authorizePayment(ctx, 600)
+	if err != nil {
+		t.Fatalf("unable to authorize payment: %v", err)
+	}
+	if _, err := enforcer.authorizePayment(ctx, 500); err == nil {
+		t.Fatalf("expected in-flight payment to count against limit")
+	}
+	paymentDone(btcutil.Amount(610))
+
+	// The usage should persist across restarts, so a new enforcer using
+	// the same token should continue to enforce the quotas.
+	enforcer, err = newQuotaEnforcer(db, quotas, tokenDir, false)
+	if err != nil {
+		t.Fatalf("unable to create quota enforcer: %v", err)
+	}
+	if err := enforcer.authorizeInvoice(ctx); err == nil {
+		t.Fatalf("expected invoice quota to persist across restarts")
+	}
+
+	// The 610 satoshis spent, including fees, leave room for a payment of
+	// 390 satoshis, which is the last payment allowed within the hour.
+	if _, err := enforcer.authorizePayment(ctx, 391); err == nil {
+		t.Fatalf("expected spend limit to include fees")
+	}
+	paymentDone, err = enforcer.authorizePayment(ctx, 390)
+	if err != nil {
+		t.Fatalf("unable to authorize payment: %v", err)
+	}
+	paymentDone(0)
+	_, err = enforcer.authorizePayment(ctx, 1)
+	if err == nil || !strings.Contains(err.Error(), "payments per hour") {
+		t.Fatalf("expected payment quota to be exceeded, got: %v", err)
+	}
+}
+
+// TestCredentialPermissions tests that credentials may only carry out the
+// operations affecting our channels or funds they're permitted to, and that
+// credentials which don't list their permissions are granted all of them.
+func TestCredentialPermissions(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "permissions")
+	if err != nil {
+		t.Fatalf("unable to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	db, err := channeldb.Open(tempDir)
+	if err != nil {
+		t.Fatalf("unable to open db: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := parseCredentialQuotas([]string{"ops:0:0:0:steal"}); err == nil {
+		t.Fatalf("expected unknown permission to be rejected")
+	}
+
+	quotas, err := parseCredentialQuotas([]string{
+		"admin:0:0:0",
+		"ops:0:0:0:coopclose,forceclose",
+		"monitor:0:0:0:",
+	})
+	if err != nil {
+		t.Fatalf("unable to parse quotas: %v", err)
+	}
+	tokenDir := filepath.Join(tempDir, "credentials")
+	enforcer, err := newQuotaEnforcer(db, quotas, tokenDir, false)
+	if err != nil {
+		t.Fatalf("unable to create quota enforcer: %v", err)
+	}
+
+	tests := []struct {
+		credential string
+		permitted  permission
+	}{
+		{"admin", allPermissions},
+		{"ops", permCoopClose | permForceClose},
+		{"monitor", 0},
+	}
+	for _, test := range tests {
+		ctx := credentialContext(t, filepath.Join(tokenDir,
+			test.credential+".token"))
+
+		for _, perm := range permissionNames {
+			err := enforcer.authorize(ctx, perm)
+			permitted := test.permitted&perm != 0
+			if permitted && err != nil {
+				t.Fatalf("expected %v to be permitted to %v: %v",
+					test.credential, perm, err)
+			}
+			if !permitted && err == nil {
+				t.Fatalf("expected %v not to be permitted to %v",
+					test.credential, perm)
+			}
+		}
+
+		// Payments require the permission to send funds.
+		paymentDone, err := enforcer.authorizePayment(ctx, 1000)
+		if test.permitted&permSendFunds != 0 {
+			if err != nil {
+				t.Fatalf("unable to authorize payment: %v", err)
+			}
+			paymentDone(0)
+		} else if err == nil {
+			t.Fatalf("expected payment of %v to be rejected",
+				test.credential)
+		}
+	}
+
+	// Calls made without a credential hold no permission, unless the
+	// operator has explicitly allowed them, in which case they hold every
+	// permission.
+	for _, perm := range permissionNames {
+		if err := enforcer.authorize(context.Background(), perm); err == nil {
+			t.Fatalf("expected uncredentialed call not to be "+
+				"permitted to %v", perm)
+		}
+	}
+	enforcer, err = newQuotaEnforcer(db, quotas, tokenDir, true)
+	if err != nil {
+		t.Fatalf("unable to create quota enforcer: %v", err)
+	}
+	for _, perm := range permissionNames {
+		if err := enforcer.authorize(context.Background(), perm); err != nil {
+			t.Fatalf("expected operator to be permitted to %v: %v",
+				perm, err)
+		}
+	}
+
+	// Without any credentials defined, calls made without a credential
+	// remain unrestricted.
+	enforcer, err = newQuotaEnforcer(db, nil, tokenDir, false)
+	if err != nil {
+		t.Fatalf("unable to create quota enforcer: %v", err)
+	}
+	if err := enforcer.authorize(context.Background(), permSendFunds); err != nil {
+		t.Fatalf("expected operator to be permitted to send funds: %v",
+			err)
+	}
+}
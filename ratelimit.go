@@ -0,0 +1,67 @@
+package main
+
+import (
+	"sync"
+	"time"
+
+	"github.com/roasbeef/btcd/btcec"
+)
+
+// rateWindow counts the requests received from a node within the current
+// interval.
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// peerRateLimiter limits the number of requests served for each node within
+// a fixed interval.
+type peerRateLimiter struct {
+	interval time.Duration
+	limit    int
+
+	// windows tracks the requests received from each node, keyed by its
+	// serialized public key.
+	mtx     sync.Mutex
+	windows map[[33]byte]*rateWindow
+}
+
+// newPeerRateLimiter creates a new peerRateLimiter allowing each node up to
+// limit requests within each interval.
+func newPeerRateLimiter(interval time.Duration, limit int) *peerRateLimiter {
+	return &peerRateLimiter{
+		interval: interval,
+		limit:    limit,
+		windows:  make(map[[33]byte]*rateWindow),
+	}
+}
+
+// allow counts a request from the passed node received at the passed time,
+// returning false if it exceeds the node's limit.
+func (l *peerRateLimiter) allow(node *btcec.PublicKey, now time.Time) bool {
+	var key [33]byte
+	copy(key[:], node.SerializeCompressed())
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	// Windows which have elapsed are pruned, so the map only holds the
+	// nodes which have recently made requests.
+	for node, window := range l.windows {
+		if now.Sub(window.start) >= l.interval {
+			delete(l.windows, node)
+		}
+	}
+
+	window, ok := l.windows[key]
+	if !ok {
+		window = &rateWindow{start: now}
+		l.windows[key] = window
+	}
+	if window.count >= l.limit {
+		return false
+	}
+	window.count++
+
+	return true
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/roasbeef/btcd/btcec"
+)
+
+// TestPeerRateLimiter tests that the requests of each node are limited within
+// each interval independently of other nodes, and are allowed once again
+// after the interval elapses.
+func TestPeerRateLimiter(t *testing.T) {
+	var peers [2]*btcec.PublicKey
+	for i := range peers {
+		priv, err := btcec.NewPrivateKey(btcec.S256())
+		if err != nil {
+			t.Fatalf("unable to generate key: %v", err)
+		}
+		peers[i] = priv.PubKey()
+	}
+	greedy, modest := peers[0], peers[1]
+
+	const (
+		interval = time.Minute
+		limit    = 10
+	)
+	limiter := newPeerRateLimiter(interval, limit)
+	now := time.Unix(1490000000, 0)
+
+	for i := 0; i < limit; i++ {
+		if !limiter.allow(greedy, now) {
+			t.Fatalf("request %v within limit rejected", i)
+		}
+	}
+	if limiter.allow(greedy, now) {
+		t.Fatalf("request beyond limit allowed")
+	}
+
+	// Another node's requests are counted separately.
+	if !limiter.allow(modest, now) {
+		t.Fatalf("request of other node rejected")
+	}
+
+	// Once the interval elapses, the greedy node may make requests again.
+	now = now.Add(interval)
+	if !limiter.allow(greedy, now) {
+		t.Fatalf("request after interval rejected")
+	}
+}
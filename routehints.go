@@ -0,0 +1,234 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"sync/atomic"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/zpay32"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+// hintTimeLockDelta is the CLTV expiry delta assumed for the remote party's
+// side of a hinted channel. The remote party's policy for a channel known
+// only by its alias is never announced, so we assume the policy every node
+// applies to a channel by default: no fees, and a delta of a single block.
+const hintTimeLockDelta = 1
+
+const (
+	// hintStrategyInbound selects the channels with the most inbound
+	// capacity, as those are the likeliest to carry a payment to us.
+	hintStrategyInbound = "inbound"
+
+	// hintStrategyRoundRobin rotates through our channels with each
+	// payment request, so no single request reveals more than a few of
+	// them, and payments are spread across them.
+	hintStrategyRoundRobin = "roundrobin"
+
+	// hintStrategyPeer selects only the channels with a specific peer.
+	hintStrategyPeer = "peer"
+)
+
+// routeHintConfig defines the policy by which our unannounced channels are
+// selected as the route hints of the payment requests we create. Each hint
+// reveals a channel to whoever sees the payment request, so rather than
+// embedding every channel, a few are chosen by the configured strategy.
+type routeHintConfig struct {
+	Strategy string `long:"strategy" description:"How the unannounced channels hinted at by each payment request are selected: inbound picks those with the most inbound capacity, roundrobin rotates through them with each payment request, and peer picks only those with the peer set by routehints.peer"`
+	Peer     string `long:"peer" description:"The hex encoded public key of the peer whose channels are hinted at by the peer strategy"`
+	MaxHints int    `long:"maxhints" description:"The largest number of route hints embedded within a payment request. A value of zero embeds no hints"`
+
+	// peer is the parsed form of Peer.
+	peer *btcec.PublicKey
+}
+
+// validate checks the route hint options for consistency, parsing the peer
+// of the peer strategy.
+func (c *routeHintConfig) validate() error {
+	if c.MaxHints < 0 {
+		return fmt.Errorf("routehints.maxhints mustn't be negative")
+	}
+
+	switch c.Strategy {
+	case hintStrategyInbound, hintStrategyRoundRobin:
+		return nil
+
+	case hintStrategyPeer:
+		if c.Peer == "" {
+			return fmt.Errorf("routehints.peer must be set with " +
+				"the peer strategy")
+		}
+		peerBytes, err := hex.DecodeString(c.Peer)
+		if err != nil {
+			return fmt.Errorf("invalid routehints.peer: %v", err)
+		}
+		c.peer, err = btcec.ParsePubKey(peerBytes, btcec.S256())
+		if err != nil {
+			return fmt.Errorf("invalid routehints.peer: %v", err)
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("unknown routehints.strategy %q, must be "+
+			"one of %v, %v or %v", c.Strategy, hintStrategyInbound,
+			hintStrategyRoundRobin, hintStrategyPeer)
+	}
+}
+
+// routeHintSelector selects which of our unannounced channels are hinted at
+// by each payment request, according to the configured strategy.
+type routeHintSelector struct {
+	// next is the offset into our channels from which the round-robin
+	// strategy selects the hints of the next payment request.
+	next uint32 // atomic
+
+	cfg *routeHintConfig
+}
+
+// newRouteHintSelector creates a selector applying the given policy.
+func newRouteHintSelector(cfg *routeHintConfig) *routeHintSelector {
+	return &routeHintSelector{cfg: cfg}
+}
+
+// selectHints returns the candidates to be hinted at by a payment request,
+// which number at most the configured maximum.
+func (s *routeHintSelector) selectHints(
+	candidates []*routeHintCandidate) []*routeHintCandidate {
+
+	if s.cfg.MaxHints == 0 || len(candidates) == 0 {
+		return nil
+	}
+
+	// The candidates are ordered by funding outpoint first, so the
+	// round-robin strategy rotates through a stable order.
+	sorted := make([]*routeHintCandidate, len(candidates))
+	copy(sorted, candidates)
+	sort.Sort(hintCandidatesByChanPoint(sorted))
+
+	var selected []*routeHintCandidate
+	switch s.cfg.Strategy {
+	case hintStrategyInbound:
+		sort.Stable(hintCandidatesByInbound(sorted))
+		selected = sorted
+
+	case hintStrategyRoundRobin:
+		numHints := s.cfg.MaxHints
+		if numHints > len(sorted) {
+			numHints = len(sorted)
+		}
+		next := atomic.AddUint32(&s.next, uint32(numHints)) -
+			uint32(numHints)
+		offset := int(next % uint32(len(sorted)))
+		selected = make([]*routeHintCandidate, 0, len(sorted))
+		selected = append(selected, sorted[offset:]...)
+		selected = append(selected, sorted[:offset]...)
+
+	case hintStrategyPeer:
+		for _, candidate := range sorted {
+			if candidate.peer.IsEqual(s.cfg.peer) {
+				selected = append(selected, candidate)
+			}
+		}
+	}
+
+	if len(selected) > s.cfg.MaxHints {
+		selected = selected[:s.cfg.MaxHints]
+	}
+
+	return selected
+}
+
+// routeHintCandidate is one of our channels which isn't announced to the
+// network, so may only be routed over by a payer through a route hint.
+type routeHintCandidate struct {
+	// chanPoint is the funding outpoint of the channel.
+	chanPoint wire.OutPoint
+
+	// peer is the public key of the remote party of the channel.
+	peer *btcec.PublicKey
+
+	// alias is the alias the channel is known by until its funding
+	// transaction confirms.
+	alias lnwire.ChannelID
+
+	// inbound is the remote party's balance within the channel, which is
+	// the most we're able to receive over it.
+	inbound btcutil.Amount
+}
+
+// hopHint returns the single hop route hint leading from the remote party of
+// the channel to us over its alias.
+func (c *routeHintCandidate) hopHint() []zpay32.HopHint {
+	return []zpay32.HopHint{{
+		NodeID:          c.peer,
+		ChannelID:       c.alias.ToUint64(),
+		CLTVExpiryDelta: hintTimeLockDelta,
+	}}
+}
+
+// routeHintCandidates returns our channels which aren't announced to the
+// network. These are the zero-conf channels whose funding transaction is yet
+// to confirm, which the remote party knows by their alias alone. Once a
+// channel confirms, it's announced, and may be found by payers within the
+// graph.
+func (s *server) routeHintCandidates() ([]*routeHintCandidate, error) {
+	aliases, err := s.chanDB.FetchChannelAliases()
+	if err != nil {
+		return nil, err
+	}
+	channels, err := s.chanDB.FetchAllChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	openChannels := make(map[wire.OutPoint]*routeHintCandidate)
+	for _, channel := range channels {
+		if channel.FundingOutpoint == nil {
+			continue
+		}
+
+		openChannels[*channel.FundingOutpoint] = &routeHintCandidate{
+			chanPoint: *channel.FundingOutpoint,
+			peer:      channel.IdentityPub,
+			inbound:   channel.TheirBalance,
+		}
+	}
+
+	// Aliases outlive their channels, so an alias whose channel has since
+	// been closed is skipped.
+	var candidates []*routeHintCandidate
+	for _, chanAlias := range aliases {
+		if chanAlias.Confirmed() {
+			continue
+		}
+		candidate, ok := openChannels[chanAlias.ChanPoint]
+		if !ok {
+			continue
+		}
+
+		candidate.alias = chanAlias.Alias
+		candidates = append(candidates, candidate)
+	}
+
+	return candidates, nil
+}
+
+type hintCandidatesByChanPoint []*routeHintCandidate
+
+func (c hintCandidatesByChanPoint) Len() int      { return len(c) }
+func (c hintCandidatesByChanPoint) Swap(i, j int) { c[i], c[j] = c[j], c[i] }
+func (c hintCandidatesByChanPoint) Less(i, j int) bool {
+	return c[i].chanPoint.String() < c[j].chanPoint.String()
+}
+
+type hintCandidatesByInbound []*routeHintCandidate
+
+func (c hintCandidatesByInbound) Len() int      { return len(c) }
+func (c hintCandidatesByInbound) Swap(i, j int) { c[i], c[j] = c[j], c[i] }
+func (c hintCandidatesByInbound) Less(i, j int) bool {
+	return c[i].inbound > c[j].inbound
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+// TestRouteHintSelection tests that each strategy selects the expected
+// candidates, and that no more than the configured maximum are selected.
+func TestRouteHintSelection(t *testing.T) {
+	var peers [2]*btcec.PublicKey
+	for i := range peers {
+		priv, err := btcec.NewPrivateKey(btcec.S256())
+		if err != nil {
+			t.Fatalf("unable to generate key: %v", err)
+		}
+		peers[i] = priv.PubKey()
+	}
+
+	// The candidates are listed out of order, so the selection mustn't
+	// depend on the order in which they're found.
+	var candidates []*routeHintCandidate
+	for i, inbound := range []btcutil.Amount{300, 100, 400, 200} {
+		candidates = append(candidates, &routeHintCandidate{
+			chanPoint: wire.OutPoint{Index: uint32(i)},
+			peer:      peers[i%2],
+			inbound:   inbound,
+		})
+	}
+
+	expectInbound := func(selected []*routeHintCandidate,
+		inbound ...btcutil.Amount) {
+
+		if len(selected) != len(inbound) {
+			t.Fatalf("expected %v hints, got %v", len(inbound),
+				len(selected))
+		}
+		for i, candidate := range selected {
+			if candidate.inbound != inbound[i] {
+				t.Fatalf("expected hint %v to have %v inbound, "+
+					"got %v", i, inbound[i], candidate.inbound)
+			}
+		}
+	}
+
+	// The channels with the most inbound capacity are selected first.
+	selector := newRouteHintSelector(&routeHintConfig{
+		Strategy: hintStrategyInbound,
+		MaxHints: 3,
+	})
+	expectInbound(selector.selectHints(candidates), 400, 300, 200)
+
+	// Round-robin picks up where the last payment request left off, by
+	// funding outpoint, wrapping around once all have been hinted at.
+	selector = newRouteHintSelector(&routeHintConfig{
+		Strategy: hintStrategyRoundRobin,
+		MaxHints: 3,
+	})
+	expectInbound(selector.selectHints(candidates), 300, 100, 400)
+	expectInbound(selector.selectHints(candidates), 200, 300, 100)
+	expectInbound(selector.selectHints(candidates), 400, 200, 300)
+
+	// Only the channels with the configured peer are selected.
+	selector = newRouteHintSelector(&routeHintConfig{
+		Strategy: hintStrategyPeer,
+		MaxHints: 3,
+		peer:     peers[1],
+	})
+	expectInbound(selector.selectHints(candidates), 100, 200)
+
+	// A maximum of zero embeds no hints at all.
+	selector = newRouteHintSelector(&routeHintConfig{
+		Strategy: hintStrategyInbound,
+	})
+	expectInbound(selector.selectHints(candidates))
+}
@@ -1,6 +1,10 @@
 package routing
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"time"
+)
 
 var (
 	// ErrNoPathFound is returned when a path to the target destination
@@ -20,4 +24,45 @@ var (
 
 	// ErrTargetNotInNetwork is returned when a
 	ErrTargetNotInNetwork = errors.New("target not found")
+
+	// ErrFeeLimitExceeded is returned when the total fee of the route
+	// found for a payment exceeds the payment's fee limit.
+	ErrFeeLimitExceeded = errors.New("route fee exceeds the payment's " +
+		"fee limit")
+
+	// ErrCltvLimitExceeded is returned when the total time lock of the
+	// route found for a payment exceeds the payment's CLTV limit.
+	ErrCltvLimitExceeded = errors.New("route time lock exceeds the " +
+		"payment's cltv limit")
+
+	// ErrPaymentTimeout is returned when a payment fails to complete
+	// before its timeout expires.
+	ErrPaymentTimeout = errors.New("payment attempts exceeded the " +
+		"payment's timeout")
 )
+
+// ErrPathFindingTimeout is returned when route computation is cancelled, or
+// exceeds its time budget, before any path to the target has been found.
+type ErrPathFindingTimeout struct {
+	// Cause is the reason the search was cut short, either
+	// context.DeadlineExceeded or context.Canceled.
+	Cause error
+
+	// Elapsed is the time spent searching before the search was cut
+	// short.
+	Elapsed time.Duration
+
+	// NodesVisited is the number of nodes which were examined before the
+	// search was cut short, out of the NodesTotal nodes within the graph.
+	NodesVisited int
+	NodesTotal   int
+}
+
+// Error returns a human readable description of the aborted search.
+//
+// NOTE: This is part of the error interface.
+func (e *ErrPathFindingTimeout) Error() string {
+	return fmt.Sprintf("path finding aborted after %v having visited "+
+		"%v of %v nodes: %v", e.Elapsed, e.NodesVisited, e.NodesTotal,
+		e.Cause)
+}
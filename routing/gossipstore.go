@@ -0,0 +1,146 @@
+package routing
+
+import (
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// gossipStorePruneInterval is the interval at which messages which have
+// exceeded the age or size bounds of the gossip store are removed.
+const gossipStorePruneInterval = 10 * time.Minute
+
+// chanUpdateKey identifies the latest channel update announcement for one
+// direction of a channel.
+type chanUpdateKey struct {
+	chanID uint64
+	flags  uint16
+}
+
+// storedAnnouncements holds the latest stored announcement for each node and
+// channel direction, as originally signed by their authors.
+type storedAnnouncements struct {
+	nodes   map[vertex]*lnwire.NodeAnnouncement
+	updates map[chanUpdateKey]*lnwire.ChannelUpdateAnnouncement
+}
+
+// gossipStoreEnabled returns true if accepted gossip messages are to be kept
+// within the gossip store.
+func (r *ChannelRouter) gossipStoreEnabled() bool {
+	return r.cfg.GossipStoreAge != 0
+}
+
+// storeGossip writes the passed accepted gossip messages to the gossip store.
+// As the store is only an aid to synchronization and debugging, a failed
+// write is logged rather than returned.
+func (r *ChannelRouter) storeGossip(msgs []*channeldb.GossipMessage) {
+	if err := r.cfg.Graph.AddGossipMessages(msgs); err != nil {
+		log.Errorf("unable to store %v gossip messages: %v", len(msgs),
+			err)
+	}
+}
+
+// pruneGossipStore removes the messages which have exceeded the configured
+// age or size bounds of the gossip store.
+func (r *ChannelRouter) pruneGossipStore() {
+	cutoff := time.Now().Add(-r.cfg.GossipStoreAge)
+	numPruned, err := r.cfg.Graph.PruneGossipMessages(cutoff,
+		r.cfg.GossipStoreSize)
+	if err != nil {
+		log.Errorf("unable to prune gossip store: %v", err)
+		return
+	}
+
+	if numPruned != 0 {
+		log.Debugf("Pruned %v messages from the gossip store",
+			numPruned)
+	}
+}
+
+// restoreSyncerState rebuilds the gossip state of our peers from the gossip
+// store, so that peers which kept us up to date before a restart aren't
+// mistaken for having fallen behind once they reconnect.
+func (r *ChannelRouter) restoreSyncerState() error {
+	since := time.Now().Add(-syncerStaleTimeout)
+	msgs, err := r.cfg.Graph.FetchGossipMessages(since)
+	if err != nil {
+		return err
+	}
+
+	for _, msg := range msgs {
+		r.syncers.restoreNovel(msg.Peer, msg.Received)
+	}
+
+	return nil
+}
+
+// fetchStoredAnnouncements returns the latest stored node announcement for
+// each node, and channel update announcement for each channel direction. If
+// the gossip store is disabled, then none are returned.
+func (r *ChannelRouter) fetchStoredAnnouncements() (*storedAnnouncements,
+	error) {
+
+	stored := &storedAnnouncements{
+		nodes:   make(map[vertex]*lnwire.NodeAnnouncement),
+		updates: make(map[chanUpdateKey]*lnwire.ChannelUpdateAnnouncement),
+	}
+	if !r.gossipStoreEnabled() {
+		return stored, nil
+	}
+
+	msgs, err := r.cfg.Graph.FetchGossipMessages(time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	// As the messages are ordered by the time they were received, later
+	// announcements replace those they superseded.
+	for _, msg := range msgs {
+		switch m := msg.Msg.(type) {
+		case *lnwire.NodeAnnouncement:
+			stored.nodes[newVertex(m.NodeID)] = m
+
+		case *lnwire.ChannelUpdateAnnouncement:
+			key := chanUpdateKey{
+				chanID: m.ChannelID.ToUint64(),
+				flags:  m.Flags,
+			}
+			stored.updates[key] = m
+		}
+	}
+
+	return stored, nil
+}
+
+// nodeAnnouncement returns the stored announcement for the passed node if
+// it matches the announcement we'd otherwise re-create from the graph.
+// Otherwise, the re-created announcement is returned.
+func (s *storedAnnouncements) nodeAnnouncement(
+	ann *lnwire.NodeAnnouncement) *lnwire.NodeAnnouncement {
+
+	stored, ok := s.nodes[newVertex(ann.NodeID)]
+	if !ok || stored.Timestamp != ann.Timestamp {
+		return ann
+	}
+
+	return stored
+}
+
+// channelUpdate returns the stored update for the passed channel direction
+// if it matches the update we'd otherwise re-create from the graph.
+// Otherwise, the re-created update is returned.
+func (s *storedAnnouncements) channelUpdate(
+	update *lnwire.ChannelUpdateAnnouncement) *lnwire.ChannelUpdateAnnouncement {
+
+	key := chanUpdateKey{
+		chanID: update.ChannelID.ToUint64(),
+		flags:  update.Flags,
+	}
+	stored, ok := s.updates[key]
+	if !ok || stored.Timestamp != update.Timestamp {
+		return update
+	}
+
+	return stored
+}
@@ -0,0 +1,264 @@
+package routing
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/roasbeef/btcd/btcec"
+)
+
+const (
+	// failurePenalty is the weight added to a channel or node for each
+	// payment failure it's been a part of. It's large compared to the
+	// weight of a typical edge, so after a single failure alternative
+	// routes of a few more hops are favored.
+	failurePenalty = 500
+
+	// penaltyHalfLife is the period after which the penalty of a failure
+	// is halved. As failures are often caused by transient conditions,
+	// such as a depleted channel balance, penalized channels and nodes
+	// are gradually given another chance.
+	penaltyHalfLife = time.Hour
+)
+
+// EdgePenalty describes the payment failures attributed to a channel.
+type EdgePenalty struct {
+	// ChannelID is the ID of the penalized channel.
+	ChannelID uint64
+
+	channeldb.RoutingPenalty
+
+	// Weight is the weight currently added to the channel during path
+	// finding, taking the decay of its failures into account.
+	Weight float64
+}
+
+// NodePenalty describes the payment failures attributed to a node.
+type NodePenalty struct {
+	// Node is the public key of the penalized node.
+	Node *btcec.PublicKey
+
+	channeldb.RoutingPenalty
+
+	// Weight is the weight currently added to channels leading to the
+	// node during path finding, taking the decay of its failures into
+	// account.
+	Weight float64
+}
+
+// missionControl tracks the outcome of payment attempts, penalizing the
+// channels and intermediate nodes of routes which failed so subsequent path
+// finding favors alternatives. As the failures reported by the switch don't
+// identify the hop responsible, every part of a failed route is penalized.
+// The penalties are persisted within the channel graph, so the routing
+// knowledge they represent survives restarts.
+type missionControl struct {
+	graph *channeldb.ChannelGraph
+
+	// now returns the current time. It's replaced within tests.
+	now func() time.Time
+
+	// peerPenalty, if non-nil, returns the weight added to routes whose
+	// first hop is the passed peer.
+	peerPenalty func(peer *btcec.PublicKey) float64
+
+	sync.RWMutex
+	edges map[uint64]*channeldb.RoutingPenalty
+	nodes map[vertex]*channeldb.RoutingPenalty
+}
+
+// newMissionControl creates a new missionControl, restoring the penalties
+// persisted within the passed graph.
+func newMissionControl(graph *channeldb.ChannelGraph) (*missionControl, error) {
+	edges, nodes, err := graph.FetchRoutingPenalties()
+	if err != nil {
+		return nil, err
+	}
+
+	m := &missionControl{
+		graph: graph,
+		now:   time.Now,
+		edges: edges,
+		nodes: make(map[vertex]*channeldb.RoutingPenalty, len(nodes)),
+	}
+	for node, penalty := range nodes {
+		m.nodes[vertex(node)] = penalty
+	}
+
+	return m, nil
+}
+
+// decayedWeight returns the weight currently added by the passed penalty.
+func (m *missionControl) decayedWeight(p *channeldb.RoutingPenalty) float64 {
+	elapsed := m.now().Sub(p.LastFailure)
+	if elapsed < 0 {
+		elapsed = 0
+	}
+	decay := math.Pow(0.5, float64(elapsed)/float64(penaltyHalfLife))
+
+	return failurePenalty * float64(p.Failures) * decay
+}
+
+// edgePenalty returns the weight added to the passed edge during path
+// finding, which accounts for the failures of both the channel and the node
+// it leads to.
+func (m *missionControl) edgePenalty(e *channeldb.ChannelEdgePolicy) float64 {
+	m.RLock()
+	defer m.RUnlock()
+
+	var weight float64
+	if p, ok := m.edges[e.ChannelID]; ok {
+		weight += m.decayedWeight(p)
+	}
+	if p, ok := m.nodes[newVertex(e.Node.PubKey)]; ok {
+		weight += m.decayedWeight(p)
+	}
+
+	return weight
+}
+
+// firstHopPenalty returns the weight added to the passed edge leading from
+// ourselves to one of our peers during path finding.
+func (m *missionControl) firstHopPenalty(e *channeldb.ChannelEdgePolicy) float64 {
+	if m.peerPenalty == nil {
+		return 0
+	}
+
+	return m.peerPenalty(e.Node.PubKey)
+}
+
+// reportRouteFailure penalizes each channel of the failed route, along with
+// each node between ourselves and the destination.
+func (m *missionControl) reportRouteFailure(route *Route) error {
+	now := m.now()
+
+	chanIDs := make([]uint64, 0, len(route.Hops))
+	nodes := make([]*btcec.PublicKey, 0, len(route.Hops))
+	for i, hop := range route.Hops {
+		chanIDs = append(chanIDs, hop.Channel.ChannelID)
+		if i != len(route.Hops)-1 {
+			nodes = append(nodes, hop.Channel.Node.PubKey)
+		}
+	}
+
+	if err := m.graph.AddRoutingFailure(chanIDs, nodes, now); err != nil {
+		return err
+	}
+
+	m.Lock()
+	defer m.Unlock()
+
+	penalize := func(p *channeldb.RoutingPenalty) *channeldb.RoutingPenalty {
+		if p == nil {
+			p = &channeldb.RoutingPenalty{}
+		}
+		p.Failures++
+		p.LastFailure = now
+		return p
+	}
+	for _, chanID := range chanIDs {
+		m.edges[chanID] = penalize(m.edges[chanID])
+	}
+	for _, node := range nodes {
+		v := newVertex(node)
+		m.nodes[v] = penalize(m.nodes[v])
+	}
+
+	return nil
+}
+
+// penalties returns the current penalty of each channel and node, each
+// ordered from the heaviest to the lightest.
+func (m *missionControl) penalties() ([]*EdgePenalty, []*NodePenalty, error) {
+	m.RLock()
+	defer m.RUnlock()
+
+	edges := make([]*EdgePenalty, 0, len(m.edges))
+	for chanID, p := range m.edges {
+		edges = append(edges, &EdgePenalty{
+			ChannelID:      chanID,
+			RoutingPenalty: *p,
+			Weight:         m.decayedWeight(p),
+		})
+	}
+	sort.Sort(edgePenaltiesByWeight(edges))
+
+	nodes := make([]*NodePenalty, 0, len(m.nodes))
+	for v, p := range m.nodes {
+		pub, err := btcec.ParsePubKey(v[:], btcec.S256())
+		if err != nil {
+			return nil, nil, err
+		}
+		nodes = append(nodes, &NodePenalty{
+			Node:           pub,
+			RoutingPenalty: *p,
+			Weight:         m.decayedWeight(p),
+		})
+	}
+	sort.Sort(nodePenaltiesByWeight(nodes))
+
+	return edges, nodes, nil
+}
+
+// resetEdge forgets the failures of the target channel.
+func (m *missionControl) resetEdge(chanID uint64) error {
+	m.Lock()
+	defer m.Unlock()
+
+	if err := m.graph.ResetEdgePenalty(chanID); err != nil {
+		return err
+	}
+	delete(m.edges, chanID)
+
+	return nil
+}
+
+// resetNode forgets the failures of the target node.
+func (m *missionControl) resetNode(node *btcec.PublicKey) error {
+	m.Lock()
+	defer m.Unlock()
+
+	if err := m.graph.ResetNodePenalty(node); err != nil {
+		return err
+	}
+	delete(m.nodes, newVertex(node))
+
+	return nil
+}
+
+// resetAll forgets the failures of all channels and nodes.
+func (m *missionControl) resetAll() error {
+	m.Lock()
+	defer m.Unlock()
+
+	if err := m.graph.ResetRoutingPenalties(); err != nil {
+		return err
+	}
+	m.edges = make(map[uint64]*channeldb.RoutingPenalty)
+	m.nodes = make(map[vertex]*channeldb.RoutingPenalty)
+
+	return nil
+}
+
+// edgePenaltiesByWeight implements sort.Interface to order edge penalties
+// from the heaviest to the lightest.
+type edgePenaltiesByWeight []*EdgePenalty
+
+func (e edgePenaltiesByWeight) Len() int      { return len(e) }
+func (e edgePenaltiesByWeight) Swap(i, j int) { e[i], e[j] = e[j], e[i] }
+func (e edgePenaltiesByWeight) Less(i, j int) bool {
+	return e[i].Weight > e[j].Weight
+}
+
+// nodePenaltiesByWeight implements sort.Interface to order node penalties
+// from the heaviest to the lightest.
+type nodePenaltiesByWeight []*NodePenalty
+
+func (n nodePenaltiesByWeight) Len() int      { return len(n) }
+func (n nodePenaltiesByWeight) Swap(i, j int) { n[i], n[j] = n[j], n[i] }
+func (n nodePenaltiesByWeight) Less(i, j int) bool {
+	return n[i].Weight > n[j].Weight
+}
@@ -0,0 +1,123 @@
+package routing
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/btcec"
+	"golang.org/x/net/context"
+)
+
+// TestMissionControl tests that the channels of failed routes are penalized,
+// steering subsequent attempts towards alternative routes, and that the
+// penalties survive a restart of the router.
+func TestMissionControl(t *testing.T) {
+	graph, cleanUp, aliases, err := parseTestGraph(basicGraphFilePath)
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to create graph: %v", err)
+	}
+
+	// The switch fails every payment sent directly to luo ji.
+	var firstHops []*btcec.PublicKey
+	errSwitch := errors.New("htlc failed")
+	cfg := Config{
+		Graph:    graph,
+		Chain:    newMockChain(0),
+		Notifier: newMockNotifier(),
+		SendToSwitch: func(firstHop *btcec.PublicKey,
+			_ *lnwire.UpdateAddHTLC, _ [][]byte) ([32]byte, error) {
+
+			firstHops = append(firstHops, firstHop)
+			if firstHop.IsEqual(aliases["luoji"]) {
+				return [32]byte{}, errSwitch
+			}
+			return [32]byte{1}, nil
+		},
+	}
+	router, err := New(cfg)
+	if err != nil {
+		t.Fatalf("unable to create router: %v", err)
+	}
+
+	// The direct channel to luo ji should be attempted first, after which
+	// the payment should succeed through satoshi.
+	_, route, err := router.SendPayment(&LightningPayment{
+		Target:      aliases["luoji"],
+		Amount:      100,
+		RetryPolicy: RetryPolicy{MaxAttempts: 2},
+	})
+	if err != nil {
+		t.Fatalf("unable to send payment: %v", err)
+	}
+	if len(firstHops) != 2 || !firstHops[0].IsEqual(aliases["luoji"]) ||
+		!firstHops[1].IsEqual(aliases["satoshi"]) {
+
+		t.Fatalf("unexpected first hops: %v", firstHops)
+	}
+	if len(route.Hops) != 2 {
+		t.Fatalf("expected route through satoshi, got %v hops",
+			len(route.Hops))
+	}
+
+	// Only the direct channel should have been penalized, as the failed
+	// route had no intermediate nodes.
+	edges, nodes, err := router.QueryMissionControl()
+	if err != nil {
+		t.Fatalf("unable to query mission control: %v", err)
+	}
+	if len(edges) != 1 || edges[0].ChannelID != 689530843 ||
+		edges[0].Failures != 1 || len(nodes) != 0 {
+
+		t.Fatalf("unexpected penalties: %v, %v", edges, nodes)
+	}
+
+	// A restarted router should still avoid the direct channel.
+	router, err = New(cfg)
+	if err != nil {
+		t.Fatalf("unable to create router: %v", err)
+	}
+	route, err = router.FindRoute(context.Background(), aliases["luoji"], 100)
+	if err != nil {
+		t.Fatalf("unable to find route: %v", err)
+	}
+	if len(route.Hops) != 2 {
+		t.Fatalf("expected penalized channel to be avoided after " +
+			"restart")
+	}
+
+	// Once the penalty has decayed, the direct channel should be favored
+	// once again.
+	router.missionControl.now = func() time.Time {
+		return time.Now().Add(penaltyHalfLife * 20)
+	}
+	route, err = router.FindRoute(context.Background(), aliases["luoji"], 100)
+	if err != nil {
+		t.Fatalf("unable to find route: %v", err)
+	}
+	if len(route.Hops) != 1 {
+		t.Fatalf("expected decayed penalty to be ignored")
+	}
+	router.missionControl.now = time.Now
+
+	// Resetting the channel's penalty should have the same effect.
+	if err := router.ResetEdgePenalty(689530843); err != nil {
+		t.Fatalf("unable to reset penalty: %v", err)
+	}
+	route, err = router.FindRoute(context.Background(), aliases["luoji"], 100)
+	if err != nil {
+		t.Fatalf("unable to find route: %v", err)
+	}
+	if len(route.Hops) != 1 {
+		t.Fatalf("expected reset channel to be favored")
+	}
+	edges, _, err = router.QueryMissionControl()
+	if err != nil {
+		t.Fatalf("unable to query mission control: %v", err)
+	}
+	if len(edges) != 0 {
+		t.Fatalf("expected no penalties after reset, got %v", edges)
+	}
+}
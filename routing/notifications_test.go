@@ -149,7 +149,7 @@ func createTestCtx(startingHeight uint32) (*testCtx, func(), error) {
 			return nil
 		},
 		SendToSwitch: func(_ *btcec.PublicKey,
-			_ *lnwire.UpdateAddHTLC) ([32]byte, error) {
+			_ *lnwire.UpdateAddHTLC, _ [][]byte) ([32]byte, error) {
 			return [32]byte{}, nil
 		},
 	})
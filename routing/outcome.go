@@ -0,0 +1,68 @@
+package routing
+
+import (
+	"strings"
+	"time"
+)
+
+// PaymentOutcome is the anonymized outcome of a payment sent by the router.
+// It deliberately omits the payment hash, amount, destination and route, so
+// outcomes may be aggregated without revealing whom we've paid.
+type PaymentOutcome struct {
+	// Succeeded indicates whether the payment succeeded.
+	Succeeded bool
+
+	// Latency is the time taken from the payment being requested until
+	// its final attempt completed.
+	Latency time.Duration
+
+	// Attempts is the number of attempts made at sending the payment.
+	Attempts uint32
+
+	// FailureCode is a short identifier for the reason the payment failed,
+	// as returned by PaymentFailureCode. It's empty if the payment
+	// succeeded.
+	FailureCode string
+}
+
+// PaymentFailureCode returns a short identifier for the reason a payment
+// failed. Errors returned by the router are identified by name, while the
+// failures returned from within the network are identified by the name of
+// their lnwire.FailCode. Any other error is identified as "Other", so no
+// free-form text, which may identify the payment, is ever returned.
+func PaymentFailureCode(err error) string {
+	switch err {
+	case nil:
+		return ""
+	case ErrNoPathFound:
+		return "NoPathFound"
+	case ErrInsufficientCapacity:
+		return "InsufficientGraphCapacity"
+	case ErrMaxHopsExceeded:
+		return "MaxHopsExceeded"
+	case ErrTargetNotInNetwork:
+		return "TargetNotInNetwork"
+	case ErrFeeLimitExceeded:
+		return "FeeLimitExceeded"
+	case ErrCltvLimitExceeded:
+		return "CltvLimitExceeded"
+	case ErrPaymentTimeout:
+		return "PaymentTimeout"
+	}
+
+	if _, ok := err.(*ErrPathFindingTimeout); ok {
+		return "PathFindingTimeout"
+	}
+
+	// The failures returned from within the network are described by the
+	// String method of their FailCode, which is prefixed by the code's
+	// name.
+	msg := err.Error()
+	if i := strings.Index(msg, ":"); i > 0 &&
+		!strings.ContainsAny(msg[:i], " \t\n") {
+
+		return msg[:i]
+	}
+
+	return "Other"
+}
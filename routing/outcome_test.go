@@ -0,0 +1,45 @@
+package routing
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"golang.org/x/net/context"
+)
+
+// TestPaymentFailureCode tests that payment failures are identified by a
+// short code, and that no free-form error text is ever returned.
+func TestPaymentFailureCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		code string
+	}{
+		{nil, ""},
+		{ErrNoPathFound, "NoPathFound"},
+		{ErrFeeLimitExceeded, "FeeLimitExceeded"},
+		{ErrPaymentTimeout, "PaymentTimeout"},
+		{
+			&ErrPathFindingTimeout{Cause: context.DeadlineExceeded},
+			"PathFindingTimeout",
+		},
+		{
+			errors.New(lnwire.UnknownPaymentHash.String()),
+			"UnknownPaymentHash",
+		},
+		{
+			errors.New(lnwire.InsufficientCapacity.String()),
+			"InsufficientCapacity",
+		},
+		{errors.New("Insufficient capacity"), "Other"},
+		{errors.New("Unable to locate link 02ab: gone"), "Other"},
+	}
+
+	for _, test := range tests {
+		code := PaymentFailureCode(test.err)
+		if code != test.code {
+			t.Fatalf("expected code %q for %v, got %q", test.code,
+				test.err, code)
+		}
+	}
+}
@@ -2,11 +2,13 @@ package routing
 
 import (
 	"math"
+	"time"
 
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/roasbeef/btcd/btcec"
 	"github.com/roasbeef/btcd/chaincfg/chainhash"
 	"github.com/roasbeef/btcutil"
+	"golang.org/x/net/context"
 )
 
 const (
@@ -58,7 +60,9 @@ type Route struct {
 // channel itself.
 type ChannelHop struct {
 	// Capacity is the total capacity of the channel being traversed. This
-	// value is expressed for stability in satoshis.
+	// value is expressed for stability in satoshis. If the hop leads
+	// directly from ourselves to the destination, then this is the
+	// aggregate capacity of all our channels with the destination.
 	Capacity btcutil.Amount
 
 	// Chain is a 32-byte has that denotes the base blockchain network of
@@ -240,11 +244,23 @@ func edgeWeight(e *channeldb.ChannelEdgePolicy) float64 {
 // the time-lock+fee along the route. Once we have a set of candidate routes,
 // we calculate the required fee and time lock values running backwards along
 // the route. The route that's selected is the one with the lowest total fee.
+// If mc is non-nil, then the penalties it applies to channels and nodes which
+// failed prior payments, and to our less reliable peers, are added to the
+// distance metric. Channels within ignoredEdges aren't traversed at all. If
+// the target is one of our direct peers, then our parallel channels with it
+// are presented as a single link with their aggregate capacity, as a payment
+// to the peer may be split across them. If the passed context is cancelled, or its deadline expires, before the search
+// completes, then the best route to the target found so far is returned. If
+// no route has been found by then, an ErrPathFindingTimeout is returned
+// instead.
 //
 // TODO(roasbeef): make member, add caching
 //  * add k-path
-func findRoute(graph *channeldb.ChannelGraph, target *btcec.PublicKey,
-	amt btcutil.Amount) (*Route, error) {
+func findRoute(ctx context.Context, graph *channeldb.ChannelGraph,
+	target *btcec.PublicKey, amt btcutil.Amount, mc *missionControl,
+	ignoredEdges map[uint64]struct{}) (*Route, error) {
+
+	start := time.Now()
 
 	// First initialize empty list of all the node that we've yet to
 	// visited.
@@ -287,31 +303,58 @@ func findRoute(graph *channeldb.ChannelGraph, target *btcec.PublicKey,
 	// to `vertex` we'll take the edge that it's mapped to within `prev`.
 	prev := make(map[vertex]edgeWithPrev)
 
+	// directCapacity tracks the aggregate capacity of our channels with
+	// each of our direct peers.
+	directCapacity := make(map[vertex]btcutil.Amount)
+
+	// searchErr is set if the search is cut short before every reachable
+	// node has been visited.
+	var searchErr *ErrPathFindingTimeout
+	numNodes := len(unvisited)
+
+out:
 	for len(unvisited) != 0 {
+		// Before visiting the next node, ensure we're still within the
+		// time budget of the search.
+		select {
+		case <-ctx.Done():
+			searchErr = &ErrPathFindingTimeout{
+				Cause:        ctx.Err(),
+				Elapsed:      time.Since(start),
+				NodesVisited: numNodes - len(unvisited),
+				NodesTotal:   numNodes,
+			}
+			break out
+		default:
+		}
+
 		var bestNode *channeldb.LightningNode
 		smallestDist := infinity
 
 		// First we examine our list of unvisited nodes, for the most
-		// optimal vertex to examine next.
+		// optimal vertex to examine next. The "best" node to visit next
+		// is node with the smallest distance from the source of all the
+		// unvisited nodes. The whole list must be examined, as visiting
+		// a node before one closer to the source could settle on a
+		// longer path, such as one through a penalized channel.
+		bestIndex := -1
 		for i, node := range unvisited {
-			// The "best" node to visit next is node with the
-			// smallest distance from the source of all the
-			// unvisited nodes.
 			v := newVertex(node.PubKey)
 			if nodeInfo := distance[v]; nodeInfo.dist < smallestDist {
 				smallestDist = nodeInfo.dist
 				bestNode = nodeInfo.node
-
-				// Since we're going to visit this node, we can
-				// remove it from the set of unvisited nodes.
-				copy(unvisited[i:], unvisited[i+1:])
-				unvisited[len(unvisited)-1] = nil // Avoid GC leak.
-				unvisited = unvisited[:len(unvisited)-1]
-
-				break
+				bestIndex = i
 			}
 		}
 
+		// Since we're going to visit this node, we can remove it from
+		// the set of unvisited nodes.
+		if bestIndex != -1 {
+			copy(unvisited[bestIndex:], unvisited[bestIndex+1:])
+			unvisited[len(unvisited)-1] = nil // Avoid GC leak.
+			unvisited = unvisited[:len(unvisited)-1]
+		}
+
 		// If we've reached our target (or we don't have any outgoing
 		// edges), then we're done here and can exit the graph
 		// traversal early.
@@ -326,10 +369,20 @@ func findRoute(graph *channeldb.ChannelGraph, target *btcec.PublicKey,
 		err := bestNode.ForEachChannel(nil, func(edgeInfo *channeldb.ChannelEdgeInfo,
 			edge *channeldb.ChannelEdgePolicy) error {
 
+			if _, ok := ignoredEdges[edge.ChannelID]; ok {
+				return nil
+			}
+
 			// Compute the tentative distance to this new
 			// channel/edge which is the distance to our current
 			// pivot node plus the weight of this edge.
 			tempDist := distance[pivot].dist + edgeWeight(edge)
+			if mc != nil {
+				tempDist += mc.edgePenalty(edge)
+				if pivot == sourceVertex {
+					tempDist += mc.firstHopPenalty(edge)
+				}
+			}
 
 			// If this new tentative distance is better than the
 			// current best known distance to this node, then we
@@ -338,6 +391,9 @@ func findRoute(graph *channeldb.ChannelGraph, target *btcec.PublicKey,
 			// TODO(roasbeef): add capacity to relaxation criteria?
 			//  * also add min payment?
 			v := newVertex(edge.Node.PubKey)
+			if pivot == sourceVertex {
+				directCapacity[v] += edgeInfo.Capacity
+			}
 			if tempDist < distance[v].dist {
 				distance[v] = nodeWithDist{
 					dist: tempDist,
@@ -359,13 +415,34 @@ func findRoute(graph *channeldb.ChannelGraph, target *btcec.PublicKey,
 	}
 
 	// If the target node isn't found in the prev hop map, then a path
-	// doesn't exist, so we terminate in an error.
+	// doesn't exist, or we ran out of time before finding one, so we
+	// terminate in an error.
 	if _, ok := prev[newVertex(target)]; !ok {
+		if searchErr != nil {
+			return nil, searchErr
+		}
 		return nil, ErrNoPathFound
 	}
 
+	// If the route leads directly to a peer of ours, then the selected
+	// channel stands in for all of our channels with the peer.
+	targetVerex := newVertex(target)
+	if hop := prev[targetVerex]; newVertex(hop.prevNode) == sourceVertex {
+		aggregate := *hop.edge
+		aggregate.Capacity = directCapacity[targetVerex]
+		hop.edge = &aggregate
+		prev[targetVerex] = hop
+	}
+
 	// Otherwise, we construct a new route which calculate the relevant
 	// total fees and proper time lock values for each hop.
-	targetVerex := newVertex(target)
-	return newRoute(amt, sourceVertex, targetVerex, prev)
+	route, err := newRoute(amt, sourceVertex, targetVerex, prev)
+	if err != nil && searchErr != nil {
+		return nil, searchErr
+	}
+	if err == nil && searchErr != nil {
+		log.Debugf("Returning best route found before %v", searchErr)
+	}
+
+	return route, err
 }
@@ -17,6 +17,7 @@ import (
 	"github.com/roasbeef/btcd/chaincfg/chainhash"
 	"github.com/roasbeef/btcd/wire"
 	"github.com/roasbeef/btcutil"
+	"golang.org/x/net/context"
 
 	prand "math/rand"
 )
@@ -295,7 +296,7 @@ func TestBasicGraphPathFinding(t *testing.T) {
 
 	const paymentAmt = btcutil.Amount(100)
 	target := aliases["sophon"]
-	route, err := findRoute(graph, target, paymentAmt)
+	route, err := findRoute(context.Background(), graph, target, paymentAmt, nil, nil)
 	if err != nil {
 		t.Fatalf("unable to find route: %v", err)
 	}
@@ -331,7 +332,7 @@ func TestBasicGraphPathFinding(t *testing.T) {
 	// exist two possible paths in the graph, but the shorter (1 hop) path
 	// should be selected.
 	target = aliases["luoji"]
-	route, err = findRoute(graph, target, paymentAmt)
+	route, err = findRoute(context.Background(), graph, target, paymentAmt, nil, nil)
 	if err != nil {
 		t.Fatalf("unable to find route: %v", err)
 	}
@@ -372,7 +373,7 @@ func TestNewRoutePathTooLong(t *testing.T) {
 	// We start by confirminig that routing a payment 20 hops away is possible.
 	// Alice should be able to find a valid route to ursula.
 	target := aliases["ursula"]
-	route, err := findRoute(graph, target, paymentAmt)
+	route, err := findRoute(context.Background(), graph, target, paymentAmt, nil, nil)
 	if err != nil {
 		t.Fatalf("path should have been found")
 	}
@@ -380,7 +381,7 @@ func TestNewRoutePathTooLong(t *testing.T) {
 	// Vincent is 21 hops away from Alice, and thus no valid route should be
 	// presented to Alice.
 	target = aliases["vincent"]
-	route, err = findRoute(graph, target, paymentAmt)
+	route, err = findRoute(context.Background(), graph, target, paymentAmt, nil, nil)
 	if err == nil {
 		t.Fatalf("should not have been able to find path, supposed to be "+"greater than 20 hops, found route with %v hops", len(route.Hops))
 	}
@@ -407,11 +408,76 @@ func TestPathNotAvailable(t *testing.T) {
 		t.Fatalf("unable to parse pubkey: %v", err)
 	}
 
-	if _, err := findRoute(graph, unknownNode, 100); err != ErrNoPathFound {
+	if _, err := findRoute(context.Background(), graph, unknownNode, 100, nil, nil); err != ErrNoPathFound {
 		t.Fatalf("path shouldn't have been found: %v", err)
 	}
 }
 
+// TestPathFindingCancelled tests that a search which is cancelled before any
+// path to the target has been found fails with a structured timeout error.
+func TestPathFindingCancelled(t *testing.T) {
+	graph, cleanUp, aliases, err := parseTestGraph(basicGraphFilePath)
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to create graph: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = findRoute(ctx, graph, aliases["sophon"], 100, nil, nil)
+	timeoutErr, ok := err.(*ErrPathFindingTimeout)
+	if !ok {
+		t.Fatalf("expected ErrPathFindingTimeout, got %v", err)
+	}
+	if timeoutErr.Cause != context.Canceled {
+		t.Fatalf("expected search to be cancelled, got %v",
+			timeoutErr.Cause)
+	}
+	if timeoutErr.NodesVisited != 0 || timeoutErr.NodesTotal == 0 {
+		t.Fatalf("expected no nodes visited, got %v of %v",
+			timeoutErr.NodesVisited, timeoutErr.NodesTotal)
+	}
+}
+
+// TestPathFindingIgnoredEdges tests that a route avoids the channels it's told
+// to ignore, even if they'd otherwise form the shortest path.
+func TestPathFindingIgnoredEdges(t *testing.T) {
+	graph, cleanUp, aliases, err := parseTestGraph(basicGraphFilePath)
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to create graph: %v", err)
+	}
+
+	// Our direct channel with Luo Ji is the shortest path to it, so once
+	// it's ignored, the route through Satoshi should be taken instead.
+	target := aliases["luoji"]
+	route, err := findRoute(context.Background(), graph, target, 100, nil,
+		nil)
+	if err != nil {
+		t.Fatalf("unable to find route: %v", err)
+	}
+	if len(route.Hops) != 1 {
+		t.Fatalf("expected direct route, got %v hops", len(route.Hops))
+	}
+
+	ignoredEdges := map[uint64]struct{}{
+		route.Hops[0].Channel.ChannelID: {},
+	}
+	route, err = findRoute(context.Background(), graph, target, 100, nil,
+		ignoredEdges)
+	if err != nil {
+		t.Fatalf("unable to find route: %v", err)
+	}
+	if len(route.Hops) != 2 {
+		t.Fatalf("expected route of 2 hops, got %v", len(route.Hops))
+	}
+	if !route.Hops[0].Channel.Node.PubKey.IsEqual(aliases["satoshi"]) {
+		t.Fatalf("first hop should be satoshi, is instead: %v",
+			route.Hops[0].Channel.Node.Alias)
+	}
+}
+
 func TestPathInsufficientCapacity(t *testing.T) {
 	graph, cleanUp, aliases, err := parseTestGraph(basicGraphFilePath)
 	defer cleanUp()
@@ -430,7 +496,74 @@ func TestPathInsufficientCapacity(t *testing.T) {
 	target := aliases["sophon"]
 
 	const payAmt = btcutil.SatoshiPerBitcoin
-	_, err = findRoute(graph, target, payAmt)
+	_, err = findRoute(context.Background(), graph, target, payAmt, nil, nil)
+	if err != ErrInsufficientCapacity {
+		t.Fatalf("graph shouldn't be able to support payment: %v", err)
+	}
+}
+
+// TestPathParallelChannels tests that our parallel channels with a direct
+// peer are presented as a single link with their aggregate capacity.
+func TestPathParallelChannels(t *testing.T) {
+	graph, cleanUp, aliases, err := parseTestGraph(basicGraphFilePath)
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to create graph: %v", err)
+	}
+
+	sourceNode, err := graph.SourceNode()
+	if err != nil {
+		t.Fatalf("unable to fetch source node: %v", err)
+	}
+
+	// The payment exceeds the capacity of our only channel with luo ji.
+	const payAmt = 150000
+	target := aliases["luoji"]
+	_, err = findRoute(context.Background(), graph, target, payAmt, nil, nil)
+	if err != ErrInsufficientCapacity {
+		t.Fatalf("graph shouldn't be able to support payment: %v", err)
+	}
+
+	// Once a second channel with luo ji is opened, the aggregate capacity
+	// of both should be able to carry the payment.
+	edgeInfo := channeldb.ChannelEdgeInfo{
+		ChannelID:    1,
+		NodeKey1:     target,
+		NodeKey2:     sourceNode.PubKey,
+		BitcoinKey1:  target,
+		BitcoinKey2:  sourceNode.PubKey,
+		AuthProof:    &testAuthProof,
+		ChannelPoint: wire.OutPoint{Index: 1},
+		Capacity:     100000,
+	}
+	if err := graph.AddChannelEdge(&edgeInfo); err != nil {
+		t.Fatalf("unable to add edge: %v", err)
+	}
+	edgePolicy := &channeldb.ChannelEdgePolicy{
+		ChannelID:     1,
+		LastUpdate:    time.Now(),
+		TimeLockDelta: 1,
+	}
+	for _, flags := range []uint16{0, 1} {
+		edgePolicy.Flags = flags
+		if err := graph.UpdateEdgePolicy(edgePolicy); err != nil {
+			t.Fatalf("unable to update edge policy: %v", err)
+		}
+	}
+
+	route, err := findRoute(context.Background(), graph, target, payAmt, nil, nil)
+	if err != nil {
+		t.Fatalf("unable to find route: %v", err)
+	}
+	if len(route.Hops) != 1 || route.Hops[0].Channel.Capacity != 200000 {
+		t.Fatalf("expected single hop with aggregate capacity, got "+
+			"%v hops with capacity %v", len(route.Hops),
+			route.Hops[0].Channel.Capacity)
+	}
+
+	// Routes through the peer can't be split, so a payment to sophon
+	// still can't be supported.
+	_, err = findRoute(context.Background(), graph, aliases["sophon"], payAmt, nil, nil)
 	if err != ErrInsufficientCapacity {
 		t.Fatalf("graph shouldn't be able to support payment: %v", err)
 	}
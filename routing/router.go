@@ -3,7 +3,9 @@ package routing
 import (
 	"bytes"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"math/rand"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -16,6 +18,7 @@ import (
 	"github.com/roasbeef/btcd/btcec"
 	"github.com/roasbeef/btcd/wire"
 	"github.com/roasbeef/btcutil"
+	"golang.org/x/net/context"
 
 	"github.com/lightningnetwork/lightning-onion"
 )
@@ -77,12 +80,67 @@ type Config struct {
 	// key.
 	SendMessages func(target *btcec.PublicKey, msg ...lnwire.Message) error
 
+	// NumActiveSyncers is the maximum number of connected peers that the
+	// channel graph is actively synchronized with. Active syncers are
+	// sent a dump of the graph upon connecting, along with each batch of
+	// new announcements, with the best connected peers being preferred.
+	// If zero, then every connected peer is an active syncer.
+	NumActiveSyncers int
+
+	// GossipSyncMode, if non-nil, returns the gossip sync mode the
+	// operator has set for the passed peer, which may pin the peer as an
+	// active or passive syncer regardless of NumActiveSyncers. The mode is
+	// consulted as the peer connects.
+	GossipSyncMode func(peer *btcec.PublicKey) channeldb.GossipSyncMode
+
+	// PathFindingTimeout is the time budget of each route computation.
+	// If the budget is exhausted, then the best route found so far is
+	// used, or an ErrPathFindingTimeout is returned if none has been
+	// found. A value of zero leaves route computation unbounded.
+	PathFindingTimeout time.Duration
+
+	// GossipStoreAge is how long the gossip messages we accept are kept
+	// within the gossip store. Stored messages are used to send peers the
+	// original signed announcements when synchronizing our graph, and to
+	// restore the state of our syncers after a restart. A value of zero
+	// disables the gossip store.
+	GossipStoreAge time.Duration
+
+	// GossipStoreSize is the maximum number of messages kept within the
+	// gossip store. A value of zero bounds the store only by age.
+	GossipStoreSize int
+
+	// FinalCltvPadding is the largest number of blocks added to the time
+	// lock of the final hop of each route we compute. The padding is
+	// chosen at random for each route, so the time lock of an HTLC
+	// doesn't reveal how far it is from its destination. A value of zero
+	// disables padding.
+	FinalCltvPadding uint16
+
+	// FirstHopPenalty, if non-nil, returns the weight added during path
+	// finding to routes whose first hop is the passed peer. This allows
+	// routes through our more reliable peers to be favored.
+	FirstHopPenalty func(peer *btcec.PublicKey) float64
+
 	// SendToSwitch is a function that directs a link-layer switch to
 	// forward a fully encoded payment to the first hop in the route
-	// denoted by its public key. A non-nil error is to be returned if the
-	// payment was unsuccessful.
+	// denoted by its public key. If the first hop is the destination of
+	// the payment, then shardOnions holds an additional onion blob for
+	// each of our other channels with it, allowing the switch to split
+	// the payment into shards across them. A non-nil error is to be
+	// returned if the payment was unsuccessful.
 	SendToSwitch func(firstHop *btcec.PublicKey,
-		htlcAdd *lnwire.UpdateAddHTLC) ([32]byte, error)
+		htlcAdd *lnwire.UpdateAddHTLC,
+		shardOnions [][]byte) ([32]byte, error)
+
+	// RecordPaymentAttempt, if non-nil, persists the outcome of each
+	// attempt made at sending the payment with the passed payment hash.
+	RecordPaymentAttempt func(paymentHash [32]byte,
+		attempt *channeldb.PaymentAttempt) error
+
+	// ReportPaymentOutcome, if non-nil, is notified of the anonymized
+	// outcome of each payment once its final attempt has completed.
+	ReportPaymentOutcome func(outcome *PaymentOutcome)
 }
 
 // ChannelRouter is the layer 3 router within the Lightning stack. Below the
@@ -114,6 +172,10 @@ type ChannelRouter struct {
 	nodeCache         map[[33]byte]*channeldb.LightningNode
 	edgeCache         map[wire.OutPoint]*channeldb.ChannelEdgePolicy
 
+	// missionControl penalizes the channels and nodes of routes which
+	// failed, steering path finding towards alternatives.
+	missionControl *missionControl
+
 	// newBlocks is a channel in which new blocks connected to the end of
 	// the main chain are sent over.
 	newBlocks <-chan *chainntnfs.BlockEpoch
@@ -126,6 +188,19 @@ type ChannelRouter struct {
 	// connected peers to the state of the channel graph from our PoV.
 	syncRequests chan *syncRequest
 
+	// syncers selects the connected peers we actively synchronize the
+	// channel graph with.
+	syncers *syncerManager
+
+	// selfUpdates queues the updates for our own channels, which are
+	// released to our peers in rate limited bursts.
+	selfUpdates *updateBatcher
+
+	// selfPolicyUpdates is a channel that carries the updates advertising
+	// new routing policies for our own channels to the networkHandler, in
+	// order for them to be queued within selfUpdates.
+	selfPolicyUpdates chan []*lnwire.ChannelUpdateAnnouncement
+
 	// prematureAnnouncements maps a blockheight to a set of announcements
 	// which are "premature" from our PoV. An announcement is premature if
 	// it claims to be anchored in a block which is beyond the current main
@@ -184,12 +259,22 @@ func New(cfg Config) (*ChannelRouter, error) {
 		return nil, err
 	}
 
+	missionControl, err := newMissionControl(cfg.Graph)
+	if err != nil {
+		return nil, err
+	}
+	missionControl.peerPenalty = cfg.FirstHopPenalty
+
 	return &ChannelRouter{
 		cfg:                    &cfg,
 		selfNode:               selfNode,
+		missionControl:         missionControl,
 		fakeSig:                fakeSig,
 		networkMsgs:            make(chan *routingMsg),
 		syncRequests:           make(chan *syncRequest),
+		syncers:                newSyncerManager(cfg.NumActiveSyncers),
+		selfUpdates:            newUpdateBatcher(),
+		selfPolicyUpdates:      make(chan []*lnwire.ChannelUpdateAnnouncement),
 		prematureAnnouncements: make(map[uint32][]lnwire.Message),
 		topologyClients:        make(map[uint64]topologyClient),
 		ntfnClientUpdates:      make(chan *topologyClientUpdate),
@@ -229,6 +314,16 @@ func (r *ChannelRouter) Start() error {
 		return err
 	}
 
+	// If the gossip store is enabled, then we'll drop any messages which
+	// expired while we were down, and use the remainder to restore the
+	// state of our syncers.
+	if r.gossipStoreEnabled() {
+		r.pruneGossipStore()
+		if err := r.restoreSyncerState(); err != nil {
+			return err
+		}
+	}
+
 	r.wg.Add(1)
 	go r.networkHandler()
 
@@ -352,7 +447,10 @@ func (r *ChannelRouter) syncGraphWithChain() error {
 func (r *ChannelRouter) networkHandler() {
 	defer r.wg.Done()
 
-	var announcementBatch []lnwire.Message
+	var (
+		announcementBatch []lnwire.Message
+		gossipBatch       []*channeldb.GossipMessage
+	)
 
 	// TODO(roasbeef): parametrize the above
 	trickleTimer := time.NewTicker(time.Millisecond * 300)
@@ -361,6 +459,15 @@ func (r *ChannelRouter) networkHandler() {
 	retransmitTimer := time.NewTicker(time.Minute * 30)
 	defer retransmitTimer.Stop()
 
+	rotateTimer := time.NewTicker(syncerRotateInterval)
+	defer rotateTimer.Stop()
+
+	selfUpdateTimer := time.NewTicker(selfUpdateInterval)
+	defer selfUpdateTimer.Stop()
+
+	gossipPruneTimer := time.NewTicker(gossipStorePruneInterval)
+	defer gossipPruneTimer.Stop()
+
 	for {
 		select {
 		// A new fully validated network message has just arrived. As a
@@ -376,9 +483,36 @@ func (r *ChannelRouter) networkHandler() {
 			// If the update was accepted, then add it to our next
 			// announcement batch to be broadcast once the trickle
 			// timer ticks gain.
-			if accepted {
+			// Updates for our own channels are instead queued to
+			// be released in rate limited bursts.
+			update, ok := netMsg.msg.(*lnwire.ChannelUpdateAnnouncement)
+			switch {
+			case accepted && ok && netMsg.peer.IsEqual(r.selfNode.PubKey):
+				r.selfUpdates.queue(update)
+
+			case accepted:
 				// TODO(roasbeef): exclude peer that sent
 				announcementBatch = append(announcementBatch, netMsg.msg)
+			}
+
+			if accepted {
+
+				// Note that the sending peer is keeping us
+				// up to date, so active syncers which fall
+				// behind it can be rotated out.
+				now := time.Now()
+				r.syncers.recordNovel(netMsg.peer, now)
+
+				// Queue the message to be written to the
+				// gossip store along with the next batch.
+				if r.gossipStoreEnabled() {
+					gossipBatch = append(gossipBatch,
+						&channeldb.GossipMessage{
+							Peer:     netMsg.peer,
+							Received: now,
+							Msg:      netMsg.msg,
+						})
+				}
 
 				// Send off a new notification for the newly
 				// accepted announcement.
@@ -497,7 +631,7 @@ func (r *ChannelRouter) networkHandler() {
 		// addresses the case of channel advertisements whether being
 		// dropped, or not properly propagated through the network.
 		case <-retransmitTimer.C:
-			var selfChans []lnwire.Message
+			var selfChans []*lnwire.ChannelUpdateAnnouncement
 
 			selfPub := r.selfNode.PubKey.SerializeCompressed()
 			err := r.selfNode.ForEachChannel(nil, func(_ *channeldb.ChannelEdgeInfo,
@@ -540,17 +674,48 @@ func (r *ChannelRouter) networkHandler() {
 				len(selfChans))
 
 			// With all the wire messages properly crafted, we'll
-			// broadcast our known outgoing channel to all our
-			// immediate peers.
-			if err := r.cfg.Broadcast(nil, selfChans...); err != nil {
-				log.Errorf("unable to re-broadcast "+
-					"channels: %v", err)
+			// queue our known outgoing channels to be released to
+			// our peers over the following bursts.
+			for _, update := range selfChans {
+				r.selfUpdates.queue(update)
+			}
+
+		// The routing policies of our own channels have been updated,
+		// so we'll queue the updates advertising them to be released
+		// over the following bursts.
+		case updates := <-r.selfPolicyUpdates:
+			for _, update := range updates {
+				r.selfUpdates.queue(update)
+			}
+
+		// The self update timer has ticked, so we'll add the next
+		// burst of updates for our own channels to the announcement
+		// batch, dropping any which have gone stale while queued.
+		case <-selfUpdateTimer.C:
+			if r.selfUpdates.numPending() == 0 {
+				continue
 			}
 
+			burst := r.selfUpdates.release(selfUpdateBurstSize,
+				r.isStaleSelfUpdate)
+
+			log.Debugf("Releasing %v updates for our channels, %v "+
+				"remain queued", len(burst),
+				r.selfUpdates.numPending())
+
+			announcementBatch = append(announcementBatch, burst...)
+
 		// The trickle timer has ticked, which indicates we should
 		// flush to the network the pending batch of new announcements
 		// we've received since the last trickle tick.
 		case <-trickleTimer.C:
+			// Messages accepted since the last tick are written to
+			// the gossip store in a single transaction.
+			if len(gossipBatch) != 0 {
+				r.storeGossip(gossipBatch)
+				gossipBatch = nil
+			}
+
 			// If the current announcement batch is nil, then we
 			// have no further work here.
 			if len(announcementBatch) == 0 {
@@ -561,8 +726,8 @@ func (r *ChannelRouter) networkHandler() {
 				len(announcementBatch))
 
 			// If we have new things to announce then broadcast
-			// then to all our immediately connected peers.
-			err := r.cfg.Broadcast(nil, announcementBatch...)
+			// then to our active syncers.
+			err := r.broadcastToSyncers(announcementBatch)
 			if err != nil {
 				log.Errorf("unable to send batch announcement: %v", err)
 				continue
@@ -573,18 +738,38 @@ func (r *ChannelRouter) networkHandler() {
 			// round of announcements.
 			announcementBatch = nil
 
-		// We've just received a new request to synchronize a peer with
-		// our latest graph state. This indicates that a peer has just
-		// connected for the first time, so for now we dump our entire
-		// graph and allow them to sift through the (subjectively) new
+		// We've just received a request indicating that a peer has
+		// either connected or disconnected. Any peers which become
+		// active syncers as a result are sent a dump of our entire
+		// graph, allowing them to sift through the (subjectively) new
 		// information on their own.
 		case syncReq := <-r.syncRequests:
-			nodePub := syncReq.node.SerializeCompressed()
-			log.Infof("Synchronizing channel graph with %x", nodePub)
+			var activated []*btcec.PublicKey
+			if syncReq.prune {
+				activated = r.syncers.removePeer(syncReq.node,
+					time.Now())
+			} else {
+				numChans := r.numChannels(syncReq.node)
+				mode := channeldb.GossipSyncDefault
+				if r.cfg.GossipSyncMode != nil {
+					mode = r.cfg.GossipSyncMode(syncReq.node)
+				}
+				activated = r.syncers.addPeer(syncReq.node,
+					numChans, mode, time.Now())
+			}
 
-			if err := r.syncChannelGraph(syncReq); err != nil {
-				log.Errorf("unable to sync graph state with %x: %v",
-					nodePub, err)
+			r.syncPeers(activated)
+
+		// The rotation timer has ticked, so we'll replace any active
+		// syncers which have fallen behind our passive peers.
+		case <-rotateTimer.C:
+			r.syncPeers(r.syncers.rotate(time.Now()))
+
+		// The gossip store prune timer has ticked, so we'll remove any
+		// messages which have exceeded the store's bounds.
+		case <-gossipPruneTimer.C:
+			if r.gossipStoreEnabled() {
+				r.pruneGossipStore()
 			}
 
 		// A new notification client update has arrived. We're either
@@ -675,6 +860,14 @@ func (r *ChannelRouter) processNetworkAnnouncement(msg lnwire.Message) bool {
 	// the existence of a channel and not yet the routing policies in
 	// either direction of the channel.
 	case *lnwire.ChannelAnnouncement:
+		// Aliases are only known to the two parties of a zero-conf
+		// channel, so they're never valid within an announcement.
+		if msg.ChannelID.IsAlias() {
+			log.Debugf("Ignoring announcement for alias chan_id=%v",
+				msg.ChannelID.ToUint64())
+			return false
+		}
+
 		// Prior to processing the announcement we first check if we
 		// already know of this channel, if so, then we can exit early.
 		channelID := msg.ChannelID.ToUint64()
@@ -726,6 +919,33 @@ func (r *ChannelRouter) processNetworkAnnouncement(msg lnwire.Message) bool {
 			return false
 		}
 
+		// If the channel is one of our zero-conf channels, then it was
+		// added to the graph under its alias before its funding
+		// transaction confirmed. The announcement supersedes the alias,
+		// so it's removed before the channel is added once again.
+		aliasID, err := r.cfg.Graph.ChannelID(fundingPoint)
+		existingID := lnwire.NewChanIDFromInt(aliasID)
+		switch {
+		case err == nil && existingID.IsAlias():
+			err := r.cfg.Graph.DeleteChannelEdge(fundingPoint)
+			if err != nil {
+				log.Errorf("unable to remove alias chan_id=%v: %v",
+					aliasID, err)
+				return false
+			}
+
+			log.Infof("Alias chan_id=%v of ChannelPoint(%v) "+
+				"superseded by chan_id=%v", aliasID, fundingPoint,
+				channelID)
+
+		case err != nil && err != channeldb.ErrEdgeNotFound &&
+			err != channeldb.ErrGraphNoEdgesFound:
+
+			log.Errorf("unable to look up ChannelPoint(%v): %v",
+				fundingPoint, err)
+			return false
+		}
+
 		edge := &channeldb.ChannelEdgeInfo{
 			ChannelID:   channelID,
 			NodeKey1:    msg.FirstNodeID,
@@ -760,6 +980,12 @@ func (r *ChannelRouter) processNetworkAnnouncement(msg lnwire.Message) bool {
 	// us, so we trust the data to be legitimate.
 	case *lnwire.ChannelUpdateAnnouncement:
 		chanID := msg.ChannelID.ToUint64()
+		if msg.ChannelID.IsAlias() {
+			log.Debugf("Ignoring update for alias chan_id=%v",
+				chanID)
+			return false
+		}
+
 		edge1Timestamp, edge2Timestamp, _, err := r.cfg.Graph.HasChannelEdge(chanID)
 		if err != nil && err != channeldb.ErrGraphNoEdgesFound {
 			log.Errorf("unable to check for edge existence: %v", err)
@@ -851,16 +1077,109 @@ func (r *ChannelRouter) processNetworkAnnouncement(msg lnwire.Message) bool {
 	return true
 }
 
+// UpdateSelfPolicies applies the passed routing policies for our own channels
+// to the channel graph within a single transaction, then queues the channel
+// updates advertising them to be released to the network. If any of the
+// policies can't be applied, then none of them are.
+func (r *ChannelRouter) UpdateSelfPolicies(
+	policies []*channeldb.ChannelEdgePolicy) error {
+
+	if err := r.cfg.Graph.UpdateEdgePolicies(policies); err != nil {
+		return err
+	}
+
+	updates := make([]*lnwire.ChannelUpdateAnnouncement, 0, len(policies))
+	for _, p := range policies {
+		// The policies of channels identified by an alias apply to our
+		// own path finding alone, so they aren't advertised.
+		chanID := lnwire.NewChanIDFromInt(p.ChannelID)
+		if chanID.IsAlias() {
+			continue
+		}
+
+		updates = append(updates, &lnwire.ChannelUpdateAnnouncement{
+			Signature:                 r.fakeSig,
+			ChannelID:                 chanID,
+			Timestamp:                 uint32(p.LastUpdate.Unix()),
+			Flags:                     p.Flags,
+			TimeLockDelta:             p.TimeLockDelta,
+			HtlcMinimumMsat:           uint32(p.MinHTLC),
+			FeeBaseMsat:               uint32(p.FeeBaseMSat),
+			FeeProportionalMillionths: uint32(p.FeeProportionalMillionths),
+		})
+	}
+
+	select {
+	case r.selfPolicyUpdates <- updates:
+		return nil
+	case <-r.quit:
+		return errors.New("ChannelRouter shutting down")
+	}
+}
+
+// isStaleSelfUpdate returns true if the passed update for one of our own
+// channels shouldn't be broadcast, as either the channel has since been
+// closed, or a newer update for the channel has been applied to the graph.
+func (r *ChannelRouter) isStaleSelfUpdate(
+	update *lnwire.ChannelUpdateAnnouncement) bool {
+
+	node1Time, node2Time, exists, err := r.cfg.Graph.HasChannelEdge(
+		update.ChannelID.ToUint64(),
+	)
+	if err != nil {
+		log.Errorf("unable to check edge existence: %v", err)
+		return false
+	}
+	if !exists {
+		return true
+	}
+
+	// A flag set of 0 indicates the update is for the "first" node in
+	// the channel, and 1 for the "second".
+	lastUpdate := node1Time
+	if update.Flags == 1 {
+		lastUpdate = node2Time
+	}
+
+	return lastUpdate.After(time.Unix(int64(update.Timestamp), 0))
+}
+
+// AddAliasEdge adds a zero-conf channel of ours to the channel graph under
+// its alias, along with the passed routing policies, allowing our own path
+// finding to use the channel before its funding transaction confirms. As the
+// channel has no location within the chain, it isn't validated against the
+// chain, nor is it advertised to the network.
+func (r *ChannelRouter) AddAliasEdge(edge *channeldb.ChannelEdgeInfo,
+	policies ...*channeldb.ChannelEdgePolicy) error {
+
+	chanID := lnwire.NewChanIDFromInt(edge.ChannelID)
+	if !chanID.IsAlias() {
+		return fmt.Errorf("chan_id=%v isn't an alias", edge.ChannelID)
+	}
+
+	if err := r.cfg.Graph.AddChannelEdge(edge); err != nil {
+		return err
+	}
+	if len(policies) == 0 {
+		return nil
+	}
+
+	return r.cfg.Graph.UpdateEdgePolicies(policies)
+}
+
 // syncRequest represents a request from an outside subsystem to the wallet to
-// sync a new node to the latest graph state.
+// sync a new node to the latest graph state, or to stop syncing a node which
+// has disconnected.
 type syncRequest struct {
-	node *btcec.PublicKey
+	node  *btcec.PublicKey
+	prune bool
 }
 
 // SynchronizeNode sends a message to the ChannelRouter indicating it should
 // synchronize routing state with the target node. This method is to be
 // utilized when a node connections for the first time to provide it with the
-// latest channel graph state.
+// latest channel graph state. If the node isn't selected as an active syncer,
+// then it won't be synchronized until it's rotated in.
 func (r *ChannelRouter) SynchronizeNode(pub *btcec.PublicKey) {
 	select {
 	case r.syncRequests <- &syncRequest{
@@ -871,6 +1190,76 @@ func (r *ChannelRouter) SynchronizeNode(pub *btcec.PublicKey) {
 	}
 }
 
+// PruneSyncState sends a message to the ChannelRouter indicating the target
+// node has disconnected. If it was an active syncer, then the best connected
+// passive peer is synchronized in its place.
+func (r *ChannelRouter) PruneSyncState(pub *btcec.PublicKey) {
+	select {
+	case r.syncRequests <- &syncRequest{
+		node:  pub,
+		prune: true,
+	}:
+	case <-r.quit:
+		return
+	}
+}
+
+// syncPeers sends a dump of our channel graph to each of the passed newly
+// activated syncers.
+func (r *ChannelRouter) syncPeers(peers []*btcec.PublicKey) {
+	for _, peer := range peers {
+		nodePub := peer.SerializeCompressed()
+		log.Infof("Synchronizing channel graph with %x", nodePub)
+
+		err := r.syncChannelGraph(&syncRequest{node: peer})
+		if err != nil {
+			log.Errorf("unable to sync graph state with %x: %v",
+				nodePub, err)
+		}
+	}
+}
+
+// broadcastToSyncers sends the passed announcements to each active syncer.
+// If the number of active syncers isn't limited, and no peer is pinned as
+// passive, then they're broadcast to all connected peers.
+func (r *ChannelRouter) broadcastToSyncers(msgs []lnwire.Message) error {
+	if r.cfg.NumActiveSyncers == 0 && !r.syncers.hasPinnedPassive() {
+		return r.cfg.Broadcast(nil, msgs...)
+	}
+
+	for _, syncer := range r.syncers.syncTargets() {
+		if err := r.cfg.SendMessages(syncer.node, msgs...); err != nil {
+			log.Errorf("unable to send announcements to %x: %v",
+				syncer.node.SerializeCompressed(), err)
+		}
+	}
+
+	return nil
+}
+
+// numChannels returns the number of channels the target node has within our
+// view of the channel graph.
+func (r *ChannelRouter) numChannels(pub *btcec.PublicKey) int {
+	node, err := r.cfg.Graph.FetchLightningNode(pub)
+	if err != nil {
+		return 0
+	}
+
+	var numChans int
+	err = node.ForEachChannel(nil, func(_ *channeldb.ChannelEdgeInfo,
+		_ *channeldb.ChannelEdgePolicy) error {
+
+		numChans++
+		return nil
+	})
+	if err != nil {
+		log.Errorf("unable to count channels of %x: %v",
+			pub.SerializeCompressed(), err)
+	}
+
+	return numChans
+}
+
 // syncChannelGraph attempts to synchronize the target node in the syncReq to
 // the latest channel graph state. In order to accomplish this, (currently) the
 // entire graph is read from disk, then serialized to the format defined within
@@ -882,6 +1271,14 @@ func (r *ChannelRouter) syncChannelGraph(syncReq *syncRequest) error {
 	// TODO(roasbeef): need to also store sig data in db
 	//  * will be nice when we switch to pairing sigs would only need one ^_^
 
+	// As signatures aren't stored within the graph, we'll prefer sending
+	// the originally signed announcements from the gossip store wherever
+	// they're still current.
+	stored, err := r.fetchStoredAnnouncements()
+	if err != nil {
+		return err
+	}
+
 	// We'll collate all the gathered routing messages into a single slice
 	// containing all the messages to be sent to the target peer.
 	var announceMessages []lnwire.Message
@@ -902,7 +1299,8 @@ func (r *ChannelRouter) syncChannelGraph(syncReq *syncRequest) error {
 			NodeID:    node.PubKey,
 			Alias:     alias,
 		}
-		announceMessages = append(announceMessages, ann)
+		announceMessages = append(announceMessages,
+			stored.nodeAnnouncement(ann))
 
 		numNodes++
 
@@ -918,7 +1316,12 @@ func (r *ChannelRouter) syncChannelGraph(syncReq *syncRequest) error {
 	if err := r.cfg.Graph.ForEachChannel(func(chanInfo *channeldb.ChannelEdgeInfo,
 		e1, e2 *channeldb.ChannelEdgePolicy) error {
 
+		// Channels identified by an alias are only known to us and the
+		// remote party, so they're withheld from the network.
 		chanID := lnwire.NewChanIDFromInt(chanInfo.ChannelID)
+		if chanID.IsAlias() {
+			return nil
+		}
 
 		// First, using the parameters of the channel, along with the
 		// channel authentication proof, we'll create re-create the
@@ -945,7 +1348,7 @@ func (r *ChannelRouter) syncChannelGraph(syncReq *syncRequest) error {
 		// advertise the edge in dire direction, we don't create an
 		// advertisement if the edge is nil.
 		if e1 != nil {
-			announceMessages = append(announceMessages, &lnwire.ChannelUpdateAnnouncement{
+			announceMessages = append(announceMessages, stored.channelUpdate(&lnwire.ChannelUpdateAnnouncement{
 				Signature:                 r.fakeSig,
 				ChannelID:                 chanID,
 				Timestamp:                 uint32(e1.LastUpdate.Unix()),
@@ -954,10 +1357,10 @@ func (r *ChannelRouter) syncChannelGraph(syncReq *syncRequest) error {
 				HtlcMinimumMsat:           uint32(e1.MinHTLC),
 				FeeBaseMsat:               uint32(e1.FeeBaseMSat),
 				FeeProportionalMillionths: uint32(e1.FeeProportionalMillionths),
-			})
+			}))
 		}
 		if e2 != nil {
-			announceMessages = append(announceMessages, &lnwire.ChannelUpdateAnnouncement{
+			announceMessages = append(announceMessages, stored.channelUpdate(&lnwire.ChannelUpdateAnnouncement{
 				Signature:                 r.fakeSig,
 				ChannelID:                 chanID,
 				Timestamp:                 uint32(e2.LastUpdate.Unix()),
@@ -966,7 +1369,7 @@ func (r *ChannelRouter) syncChannelGraph(syncReq *syncRequest) error {
 				HtlcMinimumMsat:           uint32(e2.MinHTLC),
 				FeeBaseMsat:               uint32(e2.FeeBaseMSat),
 				FeeProportionalMillionths: uint32(e2.FeeProportionalMillionths),
-			})
+			}))
 		}
 
 		numEdges++
@@ -1052,8 +1455,20 @@ func (r *ChannelRouter) ProcessRoutingMessage(msg lnwire.Message, src *btcec.Pub
 
 // FindRoute attempts to query the ChannelRouter for the "best" path to a
 // particular target destination which is able to send `amt` after factoring in
-// channel capacities and cumulative fees along the route.
-func (r *ChannelRouter) FindRoute(target *btcec.PublicKey, amt btcutil.Amount) (*Route, error) {
+// channel capacities and cumulative fees along the route. The search is
+// abandoned once the passed context is cancelled, or the configured time
+// budget is exhausted, in which case the best route found so far is returned.
+func (r *ChannelRouter) FindRoute(ctx context.Context, target *btcec.PublicKey,
+	amt btcutil.Amount) (*Route, error) {
+
+	return r.findRoute(ctx, target, amt, nil)
+}
+
+// findRoute is the implementation of FindRoute, which additionally avoids the
+// passed ignored channels.
+func (r *ChannelRouter) findRoute(ctx context.Context, target *btcec.PublicKey,
+	amt btcutil.Amount, ignoredEdges map[uint64]struct{}) (*Route, error) {
+
 	dest := target.SerializeCompressed()
 
 	log.Debugf("Searching for path to %x, sending %v", dest, amt)
@@ -1067,8 +1482,15 @@ func (r *ChannelRouter) FindRoute(target *btcec.PublicKey, amt btcutil.Amount) (
 		return nil, ErrTargetNotInNetwork
 	}
 
+	if r.cfg.PathFindingTimeout != 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.cfg.PathFindingTimeout)
+		defer cancel()
+	}
+
 	// TODO(roasbeef): add k-shortest paths
-	route, err := findRoute(r.cfg.Graph, target, amt)
+	route, err := findRoute(ctx, r.cfg.Graph, target, amt,
+		r.missionControl, ignoredEdges)
 	if err != nil {
 		log.Errorf("Unable to find path: %v", err)
 		return nil, err
@@ -1076,6 +1498,11 @@ func (r *ChannelRouter) FindRoute(target *btcec.PublicKey, amt btcutil.Amount) (
 
 	// TODO(roabseef): also create the Sphinx packet and add in the route
 
+	if r.cfg.FinalCltvPadding != 0 {
+		padding := uint16(rand.Int31n(int32(r.cfg.FinalCltvPadding) + 1))
+		padFinalTimeLock(route, padding)
+	}
+
 	log.Debugf("Obtained path sending %v to %x: %v", amt, dest,
 		newLogClosure(func() string {
 			return spew.Sdump(route)
@@ -1085,6 +1512,17 @@ func (r *ChannelRouter) FindRoute(target *btcec.PublicKey, amt btcutil.Amount) (
 	return route, nil
 }
 
+// padFinalTimeLock adds the passed number of blocks to the time lock delta of
+// the final hop of the route, along with the route's total time lock.
+func padFinalTimeLock(route *Route, padding uint16) {
+	if len(route.Hops) == 0 {
+		return
+	}
+
+	route.Hops[len(route.Hops)-1].TimeLockDelta += padding
+	route.TotalTimeLock += uint32(padding)
+}
+
 // generateSphinxPacket generates then encodes a sphinx packet which encodes
 // the onion route specified by the passed layer 3 route. The blob returned
 // from this function can immediately be included within an HTLC add packet to
@@ -1166,9 +1604,90 @@ type LightningPayment struct {
 	// the first hop.
 	PaymentHash [32]byte
 
+	// FeeLimit is the maximum total fee the payment may pay. A value of
+	// zero indicates that the fee is unbounded.
+	FeeLimit btcutil.Amount
+
+	// CltvLimit is the maximum total time lock the route of the payment
+	// may have. A value of zero indicates that the time lock is unbounded.
+	CltvLimit uint32
+
+	// RetryPolicy governs how the payment is retried once an attempt
+	// fails.
+	RetryPolicy
+
+	// Timeout is the period of time after which no further attempts of
+	// the payment will be made. An attempt which is already in flight
+	// isn't cancelled. A value of zero indicates no timeout.
+	Timeout time.Duration
+
 	// TODO(roasbeef): add e2e message?
 }
 
+// RetryPolicy governs how a payment is retried once an attempt at sending it
+// fails.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times the payment will be
+	// attempted before giving up. A value of zero is treated as a single
+	// attempt.
+	MaxAttempts uint32
+
+	// Backoff is the delay between the first failed attempt and the next.
+	// The delay is doubled after each subsequent failure, up to
+	// MaxBackoff. A value of zero retries the payment immediately.
+	Backoff time.Duration
+
+	// MaxBackoff is the longest delay between two attempts. A value of
+	// zero places no limit on the delay.
+	MaxBackoff time.Duration
+
+	// ExcludeFailedHops, if set, excludes the channels of each failed
+	// route from the subsequent attempts of the payment. As the hop
+	// responsible for a failure isn't identified, each channel beyond our
+	// own is excluded. Our own channel is only excluded if it was the
+	// route's sole hop.
+	ExcludeFailedHops bool
+
+	// FeeLimitStep is the amount, expressed in millionths of the payment
+	// amount, by which the fee limit of the payment is widened after each
+	// failed attempt, allowing the costlier routes the prior fee limit
+	// ruled out to be attempted. Unbounded fee limits aren't widened.
+	FeeLimitStep uint64
+}
+
+// backoff returns the delay to wait after the passed number of consecutive
+// failed attempts.
+func (p *RetryPolicy) backoff(failures uint32) time.Duration {
+	delay := p.Backoff
+	for i := uint32(1); i < failures && delay > 0; i++ {
+		delay *= 2
+		if p.MaxBackoff != 0 && delay >= p.MaxBackoff {
+			break
+		}
+	}
+	if p.MaxBackoff != 0 && delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+
+	return delay
+}
+
+// feeLimitStep returns the amount by which the fee limit of a payment of amt
+// is widened after each failed attempt. A policy which widens the fee limit
+// does so by at least a single satoshi.
+func (p *RetryPolicy) feeLimitStep(amt btcutil.Amount) btcutil.Amount {
+	if p.FeeLimitStep == 0 {
+		return 0
+	}
+
+	step := btcutil.Amount(uint64(amt) * p.FeeLimitStep / 1000000)
+	if step == 0 {
+		step = 1
+	}
+
+	return step
+}
+
 // SendPayment attempts to send a payment as described within the passed
 // LightningPayment. This function is blocking and will return either: when the
 // payment is successful, or all candidates routes have been attempted and
@@ -1177,6 +1696,208 @@ type LightningPayment struct {
 // within the network to reach the destination. Additionally, the payment
 // preimage will also be returned.
 func (r *ChannelRouter) SendPayment(payment *LightningPayment) ([32]byte, *Route, error) {
+	start := time.Now()
+	preImage, route, attempts, err := r.sendPayment(payment)
+	r.reportPaymentOutcome(start, attempts, err)
+
+	return preImage, route, err
+}
+
+// sendPayment carries out SendPayment, additionally returning the number of
+// attempts made at sending the payment.
+func (r *ChannelRouter) sendPayment(payment *LightningPayment) ([32]byte,
+	*Route, uint32, error) {
+
+	maxAttempts := payment.MaxAttempts
+	if maxAttempts == 0 {
+		maxAttempts = 1
+	}
+
+	// Route computation is also bound by the timeout of the payment, so
+	// a single slow search can't outlast it.
+	ctx := context.Background()
+	var deadline time.Time
+	if payment.Timeout != 0 {
+		deadline = time.Now().Add(payment.Timeout)
+
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, deadline)
+		defer cancel()
+	}
+
+	// The fee limit may be widened with each failure, and the channels of
+	// failed routes excluded, so we track both for this payment alone.
+	feeLimit := payment.FeeLimit
+	feeLimitStep := payment.feeLimitStep(payment.Amount)
+	var ignoredEdges map[uint64]struct{}
+	if payment.ExcludeFailedHops {
+		ignoredEdges = make(map[uint64]struct{})
+	}
+
+	var lastErr error
+	for attempt := uint32(1); attempt <= maxAttempts; attempt++ {
+		if attempt > 1 {
+			if !deadline.IsZero() && time.Now().After(deadline) {
+				log.Debugf("Payment %x timed out after %v "+
+					"attempts: %v", payment.PaymentHash[:],
+					attempt-1, lastErr)
+				return [32]byte{}, nil, attempt - 1,
+					ErrPaymentTimeout
+			}
+
+			if err := r.waitBackoff(payment, attempt-1,
+				deadline); err != nil {
+
+				return [32]byte{}, nil, attempt - 1, err
+			}
+		}
+
+		attemptTime := time.Now()
+		preImage, route, err := r.sendPaymentAttempt(ctx, payment,
+			feeLimit, ignoredEdges)
+		r.recordPaymentAttempt(payment, attemptTime, feeLimit, route,
+			err)
+		if err == nil {
+			return preImage, route, attempt, nil
+		}
+
+		switch err {
+		// If the cheapest route available exceeds the fee limit, then
+		// retrying the payment can only help if the limit is widened.
+		case ErrFeeLimitExceeded:
+			if feeLimit == 0 || feeLimitStep == 0 {
+				return preImage, nil, attempt, err
+			}
+
+		// If we're unable to find a suitable route, then retrying the
+		// payment won't help, so we'll exit early.
+		case ErrNoPathFound, ErrTargetNotInNetwork, ErrCltvLimitExceeded:
+			return preImage, nil, attempt, err
+		}
+
+		// Likewise, if route computation ran out of time, then another
+		// search would only stall the payment further.
+		if _, ok := err.(*ErrPathFindingTimeout); ok {
+			return preImage, nil, attempt, err
+		}
+
+		log.Debugf("Attempt %v of %v for payment %x failed: %v",
+			attempt, maxAttempts, payment.PaymentHash[:], err)
+		lastErr = err
+
+		if feeLimit != 0 {
+			feeLimit += feeLimitStep
+		}
+		if ignoredEdges != nil && route != nil {
+			excludeFailedHops(ignoredEdges, route)
+		}
+	}
+
+	return [32]byte{}, nil, maxAttempts, lastErr
+}
+
+// waitBackoff waits out the delay the retry policy of the passed payment
+// requires after the passed number of failed attempts. If the payment's
+// deadline would pass before the delay elapses, then ErrPaymentTimeout is
+// returned instead.
+func (r *ChannelRouter) waitBackoff(payment *LightningPayment,
+	failures uint32, deadline time.Time) error {
+
+	delay := payment.backoff(failures)
+	if delay == 0 {
+		return nil
+	}
+	if !deadline.IsZero() && time.Now().Add(delay).After(deadline) {
+		return ErrPaymentTimeout
+	}
+
+	select {
+	case <-time.After(delay):
+		return nil
+	case <-r.quit:
+		return errors.New("ChannelRouter shutting down")
+	}
+}
+
+// excludeFailedHops adds the channels of the passed failed route to the set of
+// channels ignored by subsequent attempts of the payment. As the hop
+// responsible for the failure isn't known, each channel beyond our own is
+// excluded. Our own channel is only excluded if it was the route's sole hop,
+// in which case it must be the one which failed.
+func excludeFailedHops(ignoredEdges map[uint64]struct{}, route *Route) {
+	if len(route.Hops) == 1 {
+		ignoredEdges[route.Hops[0].Channel.ChannelID] = struct{}{}
+		return
+	}
+
+	for _, hop := range route.Hops[1:] {
+		ignoredEdges[hop.Channel.ChannelID] = struct{}{}
+	}
+}
+
+// recordPaymentAttempt persists the outcome of an attempt at sending the
+// passed payment, if attempts are to be recorded. The route is nil if none
+// satisfying the payment's limits was found.
+func (r *ChannelRouter) recordPaymentAttempt(payment *LightningPayment,
+	attemptTime time.Time, feeLimit btcutil.Amount, route *Route,
+	attemptErr error) {
+
+	if r.cfg.RecordPaymentAttempt == nil {
+		return
+	}
+
+	record := &channeldb.PaymentAttempt{
+		AttemptTime: attemptTime,
+		FeeLimit:    feeLimit,
+	}
+	if route != nil {
+		record.Path = make([][33]byte, len(route.Hops))
+		for i, hop := range route.Hops {
+			copy(record.Path[i][:],
+				hop.Channel.Node.PubKey.SerializeCompressed())
+		}
+		record.Fee = route.TotalFees
+		record.TimeLock = route.TotalTimeLock
+	}
+	if attemptErr != nil {
+		record.Error = attemptErr.Error()
+	}
+
+	err := r.cfg.RecordPaymentAttempt(payment.PaymentHash, record)
+	if err != nil {
+		log.Errorf("unable to record attempt of payment %x: %v",
+			payment.PaymentHash[:], err)
+	}
+}
+
+// reportPaymentOutcome notifies the configured observer, if any, of the
+// outcome of a payment requested at the passed time, which completed after
+// the passed number of attempts.
+func (r *ChannelRouter) reportPaymentOutcome(start time.Time, attempts uint32,
+	paymentErr error) {
+
+	if r.cfg.ReportPaymentOutcome == nil {
+		return
+	}
+
+	r.cfg.ReportPaymentOutcome(&PaymentOutcome{
+		Succeeded:   paymentErr == nil,
+		Latency:     time.Since(start),
+		Attempts:    attempts,
+		FailureCode: PaymentFailureCode(paymentErr),
+	})
+}
+
+// sendPaymentAttempt makes a single attempt at sending the passed payment
+// along the best route currently available which satisfies the passed fee
+// limit and the payment's CLTV limit, and avoids the passed ignored channels.
+// The route computation is abandoned once the passed context is done. If the
+// payment was dispatched, then the route it was sent along is returned,
+// whether or not it succeeded.
+func (r *ChannelRouter) sendPaymentAttempt(ctx context.Context,
+	payment *LightningPayment, feeLimit btcutil.Amount,
+	ignoredEdges map[uint64]struct{}) ([32]byte, *Route, error) {
+
 	var (
 		err      error
 		preImage [32]byte
@@ -1185,12 +1906,26 @@ func (r *ChannelRouter) SendPayment(payment *LightningPayment) ([32]byte, *Route
 	// Query the graph for a potential path to the destination node that
 	// can support our payment amount. If a path is ultimately unavailable,
 	// then an error will be returned.
-	route, err := r.FindRoute(payment.Target, payment.Amount)
+	route, err := r.findRoute(ctx, payment.Target, payment.Amount,
+		ignoredEdges)
 	if err != nil {
 		return preImage, nil, err
 	}
 	log.Tracef("Selected route for payment: %#v", route)
 
+	// Before dispatching the payment, ensure the route satisfies the
+	// limits of the payment.
+	if feeLimit != 0 && route.TotalFees > feeLimit {
+		log.Debugf("Route fee of %v exceeds fee limit of %v",
+			route.TotalFees, feeLimit)
+		return preImage, nil, ErrFeeLimitExceeded
+	}
+	if payment.CltvLimit != 0 && route.TotalTimeLock > payment.CltvLimit {
+		log.Debugf("Route time lock of %v exceeds cltv limit of %v",
+			route.TotalTimeLock, payment.CltvLimit)
+		return preImage, nil, ErrCltvLimitExceeded
+	}
+
 	// Generate the raw encoded sphinx packet to be included along with the
 	// htlcAdd message that we send directly to the switch.
 	sphinxPacket, err := generateSphinxPacket(route, payment.PaymentHash[:])
@@ -1207,13 +1942,88 @@ func (r *ChannelRouter) SendPayment(payment *LightningPayment) ([32]byte, *Route
 	}
 	copy(htlcAdd.OnionBlob[:], sphinxPacket)
 
+	// If we're paying a direct peer, then the payment may be split
+	// across our parallel channels with it. As each shard must carry a
+	// distinct onion, we generate one for each additional channel.
+	firstHop := route.Hops[0].Channel.Node.PubKey
+	var shardOnions [][]byte
+	if len(route.Hops) == 1 {
+		shardOnions, err = r.generateShardOnions(route,
+			payment.PaymentHash[:])
+		if err != nil {
+			return preImage, nil, err
+		}
+	}
+
 	// Attempt to send this payment through the network to complete the
 	// payment. If this attempt fails, then we'll bail our early.
-	firstHop := route.Hops[0].Channel.Node.PubKey
-	preImage, err = r.cfg.SendToSwitch(firstHop, htlcAdd)
+	preImage, err = r.cfg.SendToSwitch(firstHop, htlcAdd, shardOnions)
 	if err != nil {
-		return preImage, nil, err
+		// Penalize the route so the next attempt, whether of this
+		// payment or another, favors an alternative.
+		if err := r.missionControl.reportRouteFailure(route); err != nil {
+			log.Errorf("unable to record route failure: %v", err)
+		}
+		return preImage, route, err
 	}
 
 	return preImage, route, nil
 }
+
+// generateShardOnions generates an onion for each of our channels with the
+// destination of the passed single hop route, other than the one the payment
+// is initially sent over.
+func (r *ChannelRouter) generateShardOnions(route *Route,
+	paymentHash []byte) ([][]byte, error) {
+
+	target := route.Hops[0].Channel.Node.PubKey
+	var numChans int
+	err := r.selfNode.ForEachChannel(nil, func(_ *channeldb.ChannelEdgeInfo,
+		c *channeldb.ChannelEdgePolicy) error {
+
+		if c.Node.PubKey.IsEqual(target) {
+			numChans++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var shardOnions [][]byte
+	for i := 1; i < numChans; i++ {
+		onion, err := generateSphinxPacket(route, paymentHash)
+		if err != nil {
+			return nil, err
+		}
+		shardOnions = append(shardOnions, onion)
+	}
+
+	return shardOnions, nil
+}
+
+// QueryMissionControl returns the penalties currently applied to channels and
+// nodes during path finding due to the payment failures they've been a part
+// of, each ordered from the heaviest to the lightest.
+func (r *ChannelRouter) QueryMissionControl() ([]*EdgePenalty,
+	[]*NodePenalty, error) {
+
+	return r.missionControl.penalties()
+}
+
+// ResetEdgePenalty forgets the payment failures of the target channel, so
+// it's no longer penalized during path finding.
+func (r *ChannelRouter) ResetEdgePenalty(chanID uint64) error {
+	return r.missionControl.resetEdge(chanID)
+}
+
+// ResetNodePenalty forgets the payment failures of the target node, so it's
+// no longer penalized during path finding.
+func (r *ChannelRouter) ResetNodePenalty(node *btcec.PublicKey) error {
+	return r.missionControl.resetNode(node)
+}
+
+// ResetMissionControl forgets the payment failures of all channels and nodes.
+func (r *ChannelRouter) ResetMissionControl() error {
+	return r.missionControl.resetAll()
+}
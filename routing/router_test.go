@@ -0,0 +1,226 @@
+package routing
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcutil"
+	"golang.org/x/net/context"
+)
+
+// TestSendPaymentLimits tests that payments are retried up to their maximum
+// number of attempts, that routes which exceed a payment's fee or CLTV limit
+// are rejected without being attempted unless the fee limit is widened, that
+// the channels of failed routes may be excluded from later attempts, and that
+// each attempt is recorded.
+func TestSendPaymentLimits(t *testing.T) {
+	graph, cleanUp, aliases, err := parseTestGraph(basicGraphFilePath)
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to create graph: %v", err)
+	}
+
+	// The switch will fail the first failures attempts, succeeding on
+	// all subsequent ones.
+	var (
+		attempts int
+		failures int
+		records  []*channeldb.PaymentAttempt
+	)
+	errSwitch := errors.New("htlc failed")
+	router, err := New(Config{
+		Graph:    graph,
+		Chain:    newMockChain(0),
+		Notifier: newMockNotifier(),
+		SendToSwitch: func(_ *btcec.PublicKey,
+			_ *lnwire.UpdateAddHTLC, _ [][]byte) ([32]byte, error) {
+
+			attempts++
+			if attempts <= failures {
+				return [32]byte{}, errSwitch
+			}
+			return [32]byte{1}, nil
+		},
+		RecordPaymentAttempt: func(_ [32]byte,
+			attempt *channeldb.PaymentAttempt) error {
+
+			records = append(records, attempt)
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("unable to create router: %v", err)
+	}
+
+	const paymentAmt = btcutil.Amount(100)
+	target := aliases["sophon"]
+	route, err := findRoute(context.Background(), graph, target, paymentAmt, nil, nil)
+	if err != nil {
+		t.Fatalf("unable to find route: %v", err)
+	}
+
+	tests := []struct {
+		name              string
+		failures          int
+		maxAttempts       uint32
+		feeLimit          btcutil.Amount
+		feeLimitStep      uint64
+		excludeFailedHops bool
+		cltvLimit         uint32
+		attempts          int
+		records           int
+		err               error
+	}{
+		{
+			name:     "single attempt",
+			attempts: 1,
+			records:  1,
+		},
+		{
+			name:        "retried until success",
+			failures:    2,
+			maxAttempts: 3,
+			attempts:    3,
+			records:     3,
+		},
+		{
+			name:        "attempts exhausted",
+			failures:    2,
+			maxAttempts: 2,
+			attempts:    2,
+			records:     2,
+			err:         errSwitch,
+		},
+		{
+			name:     "within limits",
+			feeLimit: route.TotalFees,
+			attempts: 1,
+			records:  1,
+		},
+		{
+			name:        "fee limit exceeded",
+			maxAttempts: 3,
+			feeLimit:    route.TotalFees - 1,
+			records:     1,
+			err:         ErrFeeLimitExceeded,
+		},
+		{
+			name:         "fee limit widened",
+			maxAttempts:  3,
+			feeLimit:     route.TotalFees - 1,
+			feeLimitStep: 1000000,
+			attempts:     1,
+			records:      2,
+		},
+		{
+			name:        "cltv limit exceeded",
+			maxAttempts: 3,
+			cltvLimit:   route.TotalTimeLock - 1,
+			records:     1,
+			err:         ErrCltvLimitExceeded,
+		},
+		{
+			// The only route to the target is through a single
+			// channel beyond our own, so once it's excluded, no
+			// route remains.
+			name:              "failed hops excluded",
+			failures:          1,
+			maxAttempts:       3,
+			excludeFailedHops: true,
+			attempts:          1,
+			records:           2,
+			err:               ErrNoPathFound,
+		},
+	}
+	for _, test := range tests {
+		attempts = 0
+		failures = test.failures
+		records = nil
+
+		_, _, err := router.SendPayment(&LightningPayment{
+			Target:    target,
+			Amount:    paymentAmt,
+			FeeLimit:  test.feeLimit,
+			CltvLimit: test.cltvLimit,
+			RetryPolicy: RetryPolicy{
+				MaxAttempts:       test.maxAttempts,
+				ExcludeFailedHops: test.excludeFailedHops,
+				FeeLimitStep:      test.feeLimitStep,
+			},
+		})
+		if err != test.err {
+			t.Fatalf("%v: expected error %v, got %v", test.name,
+				test.err, err)
+		}
+		if attempts != test.attempts {
+			t.Fatalf("%v: expected %v attempts, got %v", test.name,
+				test.attempts, attempts)
+		}
+		if len(records) != test.records {
+			t.Fatalf("%v: expected %v recorded attempts, got %v",
+				test.name, test.records, len(records))
+		}
+		if err != nil && records[len(records)-1].Error != err.Error() {
+			t.Fatalf("%v: expected recorded error %v, got %v",
+				test.name, err, records[len(records)-1].Error)
+		}
+	}
+}
+
+// TestFinalCltvPadding tests that the time lock of the final hop of each
+// route is padded by at most the configured number of blocks, and that the
+// route's total time lock accounts for the padding.
+func TestFinalCltvPadding(t *testing.T) {
+	graph, cleanUp, aliases, err := parseTestGraph(basicGraphFilePath)
+	defer cleanUp()
+	if err != nil {
+		t.Fatalf("unable to create graph: %v", err)
+	}
+
+	const padding = 10
+	router, err := New(Config{
+		Graph:            graph,
+		Chain:            newMockChain(0),
+		Notifier:         newMockNotifier(),
+		FinalCltvPadding: padding,
+	})
+	if err != nil {
+		t.Fatalf("unable to create router: %v", err)
+	}
+
+	const paymentAmt = btcutil.Amount(100)
+	target := aliases["sophon"]
+	unpadded, err := findRoute(context.Background(), graph, target,
+		paymentAmt, nil, nil)
+	if err != nil {
+		t.Fatalf("unable to find route: %v", err)
+	}
+	finalHop := len(unpadded.Hops) - 1
+	finalDelta := unpadded.Hops[finalHop].TimeLockDelta
+
+	for i := 0; i < 20; i++ {
+		route, err := router.FindRoute(context.Background(), target,
+			paymentAmt)
+		if err != nil {
+			t.Fatalf("unable to find route: %v", err)
+		}
+
+		added := route.TotalTimeLock - unpadded.TotalTimeLock
+		if route.TotalTimeLock < unpadded.TotalTimeLock ||
+			added > padding {
+
+			t.Fatalf("expected time lock between %v and %v, got %v",
+				unpadded.TotalTimeLock,
+				unpadded.TotalTimeLock+padding,
+				route.TotalTimeLock)
+		}
+		delta := route.Hops[finalHop].TimeLockDelta
+		if uint32(delta-finalDelta) != added {
+			t.Fatalf("expected final hop delta of %v, got %v",
+				uint32(finalDelta)+added, delta)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package routing
+
+import (
+	"time"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+const (
+	// selfUpdateInterval is the interval at which a burst of our own
+	// pending channel updates is added to the next announcement batch.
+	selfUpdateInterval = time.Second * 5
+
+	// selfUpdateBurstSize is the maximum number of our own channel updates
+	// released within a single burst. Any remaining updates are held for
+	// the following bursts, so bulk edits of our channel policies don't
+	// flood our peers.
+	selfUpdateBurstSize = 20
+)
+
+// edgeDirection identifies one direction of a channel, as our node may only
+// update the policy of its own direction of each channel.
+type edgeDirection struct {
+	chanID uint64
+	flags  uint16
+}
+
+// updateBatcher queues the channel updates for our own channels, ahead of
+// them being broadcast to our peers. Each direction of a channel has at most
+// a single pending update, so if we update a channel's policy several times
+// before its update is released, then only the latest is broadcast.
+//
+// NOTE: The updateBatcher isn't safe for concurrent use, it's only accessed
+// by the router's networkHandler.
+type updateBatcher struct {
+	pending map[edgeDirection]*lnwire.ChannelUpdateAnnouncement
+
+	// order is the order the pending updates were first queued in, so
+	// updates are released in a first-in first-out manner. Replacing a
+	// pending update doesn't change its position.
+	order []edgeDirection
+}
+
+// newUpdateBatcher creates a new, empty updateBatcher.
+func newUpdateBatcher() *updateBatcher {
+	return &updateBatcher{
+		pending: make(map[edgeDirection]*lnwire.ChannelUpdateAnnouncement),
+	}
+}
+
+// queue adds the passed update to the pending updates, replacing any pending
+// update for the same direction of the channel, unless the pending update is
+// newer.
+func (b *updateBatcher) queue(update *lnwire.ChannelUpdateAnnouncement) {
+	dir := edgeDirection{
+		chanID: update.ChannelID.ToUint64(),
+		flags:  update.Flags,
+	}
+
+	prev, ok := b.pending[dir]
+	switch {
+	case !ok:
+		b.order = append(b.order, dir)
+	case prev.Timestamp > update.Timestamp:
+		return
+	}
+
+	b.pending[dir] = update
+}
+
+// release removes and returns up to the passed number of the longest pending
+// updates. Updates for which the isStale predicate returns true, such as
+// those which have been superseded, or which are for channels that have since
+// been closed, are dropped without counting towards the limit.
+func (b *updateBatcher) release(limit int,
+	isStale func(*lnwire.ChannelUpdateAnnouncement) bool) []lnwire.Message {
+
+	var (
+		released []lnwire.Message
+		i        int
+	)
+	for ; i < len(b.order) && len(released) < limit; i++ {
+		dir := b.order[i]
+		update := b.pending[dir]
+		delete(b.pending, dir)
+
+		if isStale(update) {
+			log.Debugf("Suppressing stale update for ChannelID(%v)",
+				dir.chanID)
+			continue
+		}
+
+		released = append(released, update)
+	}
+	b.order = b.order[i:]
+
+	return released
+}
+
+// numPending returns the number of updates awaiting release.
+func (b *updateBatcher) numPending() int {
+	return len(b.order)
+}
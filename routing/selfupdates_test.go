@@ -0,0 +1,79 @@
+package routing
+
+import (
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+)
+
+// TestUpdateBatcher tests that queued updates for our own channels are
+// de-duplicated, released in order within rate limited bursts, and that
+// stale updates are suppressed.
+func TestUpdateBatcher(t *testing.T) {
+	newUpdate := func(chanID uint64, flags uint16,
+		timestamp uint32) *lnwire.ChannelUpdateAnnouncement {
+
+		return &lnwire.ChannelUpdateAnnouncement{
+			ChannelID: lnwire.NewChanIDFromInt(chanID),
+			Flags:     flags,
+			Timestamp: timestamp,
+		}
+	}
+
+	assertReleased := func(released []lnwire.Message,
+		expected ...*lnwire.ChannelUpdateAnnouncement) {
+
+		if len(released) != len(expected) {
+			t.Fatalf("expected %v released updates, got %v",
+				len(expected), len(released))
+		}
+		for i := range expected {
+			if released[i] != expected[i] {
+				t.Fatalf("unexpected update released at "+
+					"index %v: %v", i, released[i])
+			}
+		}
+	}
+
+	neverStale := func(*lnwire.ChannelUpdateAnnouncement) bool {
+		return false
+	}
+
+	b := newUpdateBatcher()
+
+	// A later update for the same direction of a channel replaces the
+	// pending update, while an earlier one is ignored. Updates for the
+	// other direction are queued separately.
+	first := newUpdate(1, 0, 100)
+	second := newUpdate(1, 0, 200)
+	other := newUpdate(1, 1, 100)
+	b.queue(first)
+	b.queue(other)
+	b.queue(second)
+	b.queue(newUpdate(1, 0, 150))
+	if b.numPending() != 2 {
+		t.Fatalf("expected 2 pending updates, got %v", b.numPending())
+	}
+
+	// The replacement keeps the position of the update it replaced.
+	assertReleased(b.release(1, neverStale), second)
+	assertReleased(b.release(1, neverStale), other)
+	assertReleased(b.release(1, neverStale))
+
+	// A bulk edit is released over several bursts.
+	updates := make([]*lnwire.ChannelUpdateAnnouncement, 5)
+	for i := range updates {
+		updates[i] = newUpdate(uint64(i+10), 0, 100)
+		b.queue(updates[i])
+	}
+	assertReleased(b.release(2, neverStale), updates[0], updates[1])
+
+	// Stale updates are dropped without counting towards the burst.
+	isStale := func(u *lnwire.ChannelUpdateAnnouncement) bool {
+		return u == updates[2]
+	}
+	assertReleased(b.release(2, isStale), updates[3], updates[4])
+	if b.numPending() != 0 {
+		t.Fatalf("expected no pending updates, got %v", b.numPending())
+	}
+}
@@ -0,0 +1,298 @@
+package routing
+
+import (
+	"sort"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/roasbeef/btcd/btcec"
+)
+
+const (
+	// syncerStaleTimeout is the period after which an active syncer which
+	// hasn't delivered any new announcements is considered to have fallen
+	// behind, if a passive peer has delivered new announcements within
+	// the same period.
+	syncerStaleTimeout = 10 * time.Minute
+
+	// syncerRotateInterval is the interval at which the active syncers
+	// are checked for having fallen behind.
+	syncerRotateInterval = time.Minute
+)
+
+// gossipSyncer tracks the gossip state of a connected peer.
+type gossipSyncer struct {
+	node *btcec.PublicKey
+
+	// numChannels is the number of channels the peer had within our view
+	// of the channel graph when it connected. Well connected peers are
+	// preferred as active syncers, as they're likely to learn of new
+	// announcements sooner.
+	numChannels int
+
+	// mode is the gossip sync mode the operator has set for the peer.
+	// Peers with a mode other than the default are pinned as either
+	// active or passive, and are excluded from the selection and rotation
+	// of the remaining active syncers.
+	mode channeldb.GossipSyncMode
+
+	// active is true if we're actively syncing the channel graph with
+	// the peer. Active syncers are sent a dump of our graph and each
+	// batch of new announcements, while passive peers are sent neither.
+	active bool
+
+	// activeSince is the time at which the peer last became an active
+	// syncer.
+	activeSince time.Time
+
+	// lastNovel is the time at which the peer last sent us an
+	// announcement which updated our channel graph.
+	lastNovel time.Time
+}
+
+// syncerManager selects the peers we actively synchronize the channel graph
+// with, limiting their number so new announcements aren't redundantly sent
+// to, and received from, every connected peer. Active syncers which fall
+// behind are rotated out for the best connected passive peer.
+//
+// NOTE: The syncerManager isn't safe for concurrent use, it's only accessed
+// by the router's networkHandler.
+type syncerManager struct {
+	// numActive is the maximum number of active syncers. If zero, every
+	// connected peer is an active syncer.
+	numActive int
+
+	syncers map[vertex]*gossipSyncer
+
+	// restoredNovel holds the time at which each peer which isn't yet
+	// connected last sent us an announcement which updated our channel
+	// graph, as restored from the gossip store after a restart.
+	restoredNovel map[vertex]time.Time
+}
+
+// newSyncerManager creates a new syncerManager permitting at most numActive
+// active syncers.
+func newSyncerManager(numActive int) *syncerManager {
+	return &syncerManager{
+		numActive:     numActive,
+		syncers:       make(map[vertex]*gossipSyncer),
+		restoredNovel: make(map[vertex]time.Time),
+	}
+}
+
+// addPeer tracks a newly connected peer with the given number of channels and
+// gossip sync mode. If it's activated, either because it's pinned as active,
+// to fill a free slot or to replace a less connected active syncer, the peer
+// is returned so its graph can be synchronized.
+func (s *syncerManager) addPeer(node *btcec.PublicKey, numChannels int,
+	mode channeldb.GossipSyncMode, now time.Time) []*btcec.PublicKey {
+
+	v := newVertex(node)
+	if _, ok := s.syncers[v]; ok {
+		return nil
+	}
+	syncer := &gossipSyncer{
+		node:        node,
+		numChannels: numChannels,
+		mode:        mode,
+		lastNovel:   s.restoredNovel[v],
+	}
+	s.syncers[v] = syncer
+	delete(s.restoredNovel, v)
+
+	// Pinned peers don't occupy any of the active syncer slots.
+	switch mode {
+	case channeldb.GossipSyncPassive:
+		return nil
+
+	case channeldb.GossipSyncActive:
+		s.activate(syncer, now)
+		return []*btcec.PublicKey{node}
+	}
+
+	active := s.activeSyncers()
+	switch {
+	case s.numActive == 0 || len(active) < s.numActive:
+		s.activate(syncer, now)
+
+	// If all slots are taken, then the peer only replaces the least
+	// connected active syncer if it's better connected itself.
+	default:
+		worst := active[len(active)-1]
+		if worst.numChannels >= numChannels {
+			return nil
+		}
+
+		log.Infof("Replacing gossip syncer %x with better connected "+
+			"peer %x", worst.node.SerializeCompressed(),
+			node.SerializeCompressed())
+
+		worst.active = false
+		s.activate(syncer, now)
+	}
+
+	return []*btcec.PublicKey{node}
+}
+
+// removePeer stops tracking a disconnected peer. If it was an active syncer,
+// then the best connected passive peer takes its place, and is returned so
+// its graph can be synchronized.
+func (s *syncerManager) removePeer(node *btcec.PublicKey,
+	now time.Time) []*btcec.PublicKey {
+
+	v := newVertex(node)
+	syncer, ok := s.syncers[v]
+	if !ok {
+		return nil
+	}
+	delete(s.syncers, v)
+
+	if !syncer.active || syncer.mode != channeldb.GossipSyncDefault {
+		return nil
+	}
+
+	passive := s.passiveSyncers()
+	if len(passive) == 0 {
+		return nil
+	}
+	s.activate(passive[0], now)
+
+	return []*btcec.PublicKey{passive[0].node}
+}
+
+// recordNovel records that the peer sent us an announcement which updated
+// our channel graph.
+func (s *syncerManager) recordNovel(node *btcec.PublicKey, now time.Time) {
+	if syncer, ok := s.syncers[newVertex(node)]; ok {
+		syncer.lastNovel = now
+	}
+}
+
+// restoreNovel records that the peer sent us an announcement which updated
+// our channel graph before a restart. If the peer reconnects, then it'll be
+// credited with the announcement.
+func (s *syncerManager) restoreNovel(node *btcec.PublicKey, t time.Time) {
+	v := newVertex(node)
+	if t.After(s.restoredNovel[v]) {
+		s.restoredNovel[v] = t
+	}
+}
+
+// rotate replaces each active syncer which has fallen behind with the best
+// connected passive peer. An active syncer has fallen behind if it hasn't
+// sent us a new announcement within the syncerStaleTimeout, while a passive
+// peer has. The newly activated peers are returned so their graphs can be
+// synchronized.
+func (s *syncerManager) rotate(now time.Time) []*btcec.PublicKey {
+	cutoff := now.Add(-syncerStaleTimeout)
+
+	// If none of the passive peers have sent us anything new recently,
+	// then the active syncers can't be behind them.
+	passive := s.passiveSyncers()
+	var aheadOfSyncers bool
+	for _, syncer := range passive {
+		if syncer.lastNovel.After(cutoff) {
+			aheadOfSyncers = true
+			break
+		}
+	}
+	if !aheadOfSyncers {
+		return nil
+	}
+
+	var activated []*btcec.PublicKey
+	for _, syncer := range s.activeSyncers() {
+		if len(passive) == 0 {
+			break
+		}
+
+		// Syncers are given a full period since their activation to
+		// deliver new announcements.
+		if syncer.activeSince.After(cutoff) ||
+			syncer.lastNovel.After(cutoff) {
+
+			continue
+		}
+
+		log.Infof("Gossip syncer %x has fallen behind, rotating in "+
+			"peer %x", syncer.node.SerializeCompressed(),
+			passive[0].node.SerializeCompressed())
+
+		syncer.active = false
+		s.activate(passive[0], now)
+		activated = append(activated, passive[0].node)
+		passive = passive[1:]
+	}
+
+	return activated
+}
+
+// activate marks the passed peer as an active syncer.
+func (s *syncerManager) activate(syncer *gossipSyncer, now time.Time) {
+	syncer.active = true
+	syncer.activeSince = now
+}
+
+// activeSyncers returns the active syncers occupying the active syncer
+// slots, ordered from the best to the least connected. Peers pinned as active
+// are excluded.
+func (s *syncerManager) activeSyncers() []*gossipSyncer {
+	var active []*gossipSyncer
+	for _, syncer := range s.syncers {
+		if syncer.active && syncer.mode == channeldb.GossipSyncDefault {
+			active = append(active, syncer)
+		}
+	}
+	sort.Sort(syncersByChannels(active))
+
+	return active
+}
+
+// passiveSyncers returns the passive peers which may be activated, ordered
+// from the best to the least connected. Peers pinned as passive are
+// excluded.
+func (s *syncerManager) passiveSyncers() []*gossipSyncer {
+	var passive []*gossipSyncer
+	for _, syncer := range s.syncers {
+		if !syncer.active && syncer.mode == channeldb.GossipSyncDefault {
+			passive = append(passive, syncer)
+		}
+	}
+	sort.Sort(syncersByChannels(passive))
+
+	return passive
+}
+
+// syncTargets returns every peer we're actively synchronizing the channel
+// graph with, including those pinned as active.
+func (s *syncerManager) syncTargets() []*gossipSyncer {
+	var targets []*gossipSyncer
+	for _, syncer := range s.syncers {
+		if syncer.active {
+			targets = append(targets, syncer)
+		}
+	}
+
+	return targets
+}
+
+// hasPinnedPassive returns true if any connected peer is pinned as passive.
+func (s *syncerManager) hasPinnedPassive() bool {
+	for _, syncer := range s.syncers {
+		if syncer.mode == channeldb.GossipSyncPassive {
+			return true
+		}
+	}
+
+	return false
+}
+
+// syncersByChannels implements sort.Interface to order syncers from the best
+// to the least connected.
+type syncersByChannels []*gossipSyncer
+
+func (s syncersByChannels) Len() int      { return len(s) }
+func (s syncersByChannels) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s syncersByChannels) Less(i, j int) bool {
+	return s[i].numChannels > s[j].numChannels
+}
@@ -0,0 +1,184 @@
+package routing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/roasbeef/btcd/btcec"
+)
+
+// TestSyncerManager tests that the best connected peers are selected as
+// active syncers, and that syncers which fall behind or disconnect are
+// replaced by the best connected passive peer.
+func TestSyncerManager(t *testing.T) {
+	peers := make([]*btcec.PublicKey, 4)
+	for i := range peers {
+		priv, err := btcec.NewPrivateKey(btcec.S256())
+		if err != nil {
+			t.Fatalf("unable to create key: %v", err)
+		}
+		peers[i] = priv.PubKey()
+	}
+
+	assertActivated := func(activated []*btcec.PublicKey,
+		expected ...*btcec.PublicKey) {
+
+		if len(activated) != len(expected) {
+			t.Fatalf("expected %v activated peers, got %v",
+				len(expected), len(activated))
+		}
+		for i := range expected {
+			if !activated[i].IsEqual(expected[i]) {
+				t.Fatalf("unexpected activated peer %x",
+					activated[i].SerializeCompressed())
+			}
+		}
+	}
+
+	const defaultMode = channeldb.GossipSyncDefault
+	now := time.Now()
+	s := newSyncerManager(2)
+
+	// The first two peers fill the free slots, regardless of their
+	// connectivity.
+	assertActivated(s.addPeer(peers[0], 1, defaultMode, now), peers[0])
+	assertActivated(s.addPeer(peers[1], 5, defaultMode, now), peers[1])
+
+	// A less connected peer is left passive, while a better connected
+	// one replaces the least connected active syncer.
+	assertActivated(s.addPeer(peers[2], 0, defaultMode, now))
+	assertActivated(s.addPeer(peers[3], 3, defaultMode, now), peers[3])
+	if len(s.activeSyncers()) != 2 || s.syncers[newVertex(peers[0])].active {
+		t.Fatalf("expected peer 0 to be replaced")
+	}
+
+	// Syncers aren't rotated out while none of the passive peers are
+	// ahead of them.
+	later := now.Add(syncerStaleTimeout * 2)
+	assertActivated(s.rotate(later))
+
+	// Once a passive peer delivers new announcements the stale syncers
+	// are replaced, starting with the best connected passive peer.
+	s.recordNovel(peers[2], later)
+	s.recordNovel(peers[1], later)
+	assertActivated(s.rotate(later), peers[0])
+	if s.syncers[newVertex(peers[3])].active ||
+		!s.syncers[newVertex(peers[1])].active {
+
+		t.Fatalf("expected only peer 3 to be rotated out")
+	}
+
+	// When an active syncer disconnects, the best connected passive peer
+	// takes its place, while disconnecting a passive peer has no effect.
+	assertActivated(s.removePeer(peers[2], later))
+	assertActivated(s.removePeer(peers[1], later), peers[3])
+	if len(s.activeSyncers()) != 2 || len(s.passiveSyncers()) != 0 {
+		t.Fatalf("expected both remaining peers to be active")
+	}
+
+	// Without a limit, every peer is an active syncer.
+	s = newSyncerManager(0)
+	for _, peer := range peers {
+		assertActivated(s.addPeer(peer, 0, defaultMode, now), peer)
+	}
+}
+
+// TestSyncerManagerRestore tests that announcements restored from the gossip
+// store are credited to peers once they reconnect.
+func TestSyncerManagerRestore(t *testing.T) {
+	peers := make([]*btcec.PublicKey, 2)
+	for i := range peers {
+		priv, err := btcec.NewPrivateKey(btcec.S256())
+		if err != nil {
+			t.Fatalf("unable to create key: %v", err)
+		}
+		peers[i] = priv.PubKey()
+	}
+
+	const defaultMode = channeldb.GossipSyncDefault
+	now := time.Now()
+	restored := now.Add(syncerStaleTimeout * 3 / 2)
+	s := newSyncerManager(1)
+	s.restoreNovel(peers[1], now)
+	s.restoreNovel(peers[1], restored)
+
+	s.addPeer(peers[0], 0, defaultMode, now)
+	s.addPeer(peers[1], 0, defaultMode, restored)
+	if len(s.restoredNovel) != 0 {
+		t.Fatalf("expected restored state to be consumed")
+	}
+
+	// The passive peer kept us up to date before the restart, so it's
+	// ahead of the active syncer, which is rotated out.
+	activated := s.rotate(now.Add(syncerStaleTimeout * 2))
+	if len(activated) != 1 || !activated[0].IsEqual(peers[1]) {
+		t.Fatalf("expected peer 1 to be rotated in")
+	}
+}
+
+// TestSyncerManagerPinned tests that peers pinned as active or passive syncers
+// by the operator keep their mode, and don't affect the selection of the
+// remaining active syncers.
+func TestSyncerManagerPinned(t *testing.T) {
+	peers := make([]*btcec.PublicKey, 4)
+	for i := range peers {
+		priv, err := btcec.NewPrivateKey(btcec.S256())
+		if err != nil {
+			t.Fatalf("unable to create key: %v", err)
+		}
+		peers[i] = priv.PubKey()
+	}
+
+	now := time.Now()
+	s := newSyncerManager(1)
+
+	// A peer pinned as active is activated even though it's poorly
+	// connected, without taking the sole active syncer slot.
+	activated := s.addPeer(peers[0], 0, channeldb.GossipSyncActive, now)
+	if len(activated) != 1 || !activated[0].IsEqual(peers[0]) {
+		t.Fatalf("expected pinned peer to be activated")
+	}
+	activated = s.addPeer(peers[1], 1, channeldb.GossipSyncDefault, now)
+	if len(activated) != 1 || !activated[0].IsEqual(peers[1]) {
+		t.Fatalf("expected peer 1 to fill the free slot")
+	}
+
+	// A peer pinned as passive is never activated, even though it's the
+	// best connected peer.
+	activated = s.addPeer(peers[2], 10, channeldb.GossipSyncPassive, now)
+	if len(activated) != 0 {
+		t.Fatalf("expected pinned peer to remain passive")
+	}
+	if !s.hasPinnedPassive() {
+		t.Fatalf("expected a peer pinned as passive")
+	}
+	if len(s.syncTargets()) != 2 {
+		t.Fatalf("expected 2 sync targets, got %v",
+			len(s.syncTargets()))
+	}
+
+	// Nor is it rotated in, or does it take the place of a disconnected
+	// syncer.
+	later := now.Add(syncerStaleTimeout * 2)
+	s.recordNovel(peers[2], later)
+	if activated := s.rotate(later); len(activated) != 0 {
+		t.Fatalf("expected no peers to be rotated in")
+	}
+	if activated := s.removePeer(peers[1], later); len(activated) != 0 {
+		t.Fatalf("expected no peers to be activated")
+	}
+
+	// Likewise, the pinned active peer isn't rotated out, and its
+	// disconnection doesn't activate anyone.
+	activated = s.addPeer(peers[3], 0, channeldb.GossipSyncDefault, later)
+	if len(activated) != 1 || !activated[0].IsEqual(peers[3]) {
+		t.Fatalf("expected peer 3 to fill the free slot")
+	}
+	if !s.syncers[newVertex(peers[0])].active {
+		t.Fatalf("expected pinned peer to remain active")
+	}
+	if activated := s.removePeer(peers[0], later); len(activated) != 0 {
+		t.Fatalf("expected no peers to be activated")
+	}
+}
@@ -0,0 +1,398 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+const (
+	// registerMiddlewareMethod is the full gRPC method name of the call
+	// used by middleware to register itself. Calls to it are never
+	// intercepted, as doing so could prevent mandatory middleware from
+	// ever registering.
+	registerMiddlewareMethod = "/lnrpc.Lightning/RegisterRPCMiddleware"
+
+	// middlewareQueueSize is the number of requests which may be queued
+	// for delivery to a single middleware. Requests for observers are
+	// dropped once their queue is full.
+	middlewareQueueSize = 100
+)
+
+// rpcMiddlewareConfig defines the options of the RPC middleware framework.
+type rpcMiddlewareConfig struct {
+	Enable           bool          `long:"enable" description:"Allow external processes to register as middleware, which observe or intercept RPC calls"`
+	InterceptTimeout time.Duration `long:"intercepttimeout" description:"The time an intercepting middleware has to respond to a request before the call is denied"`
+	Mandatory        []string      `long:"addmandatory" description:"Add the name of a middleware which must be registered before any RPC call is accepted"`
+}
+
+// rpcMiddleware is a single external process registered to observe, or
+// intercept a set of RPC calls.
+type rpcMiddleware struct {
+	name string
+
+	// methods is the set of full gRPC method names the middleware is
+	// interested in. If empty, then all calls are forwarded to it.
+	methods map[string]struct{}
+
+	// readOnly denotes an observer, which is notified of each request
+	// but can't alter or deny it. Otherwise, each request is held until
+	// the middleware has responded to it.
+	readOnly bool
+
+	stream   lnrpc.Lightning_RegisterRPCMiddlewareServer
+	outgoing chan *lnrpc.RPCMiddlewareRequest
+
+	// pending maps the ID of each request awaiting a response to the
+	// channel the response is to be delivered on.
+	pending    map[uint64]chan *lnrpc.RPCMiddlewareResponse
+	pendingMtx sync.Mutex
+
+	quit chan struct{}
+}
+
+// matches returns true if the middleware is interested in calls to the
+// passed method.
+func (m *rpcMiddleware) matches(method string) bool {
+	if len(m.methods) == 0 {
+		return true
+	}
+
+	_, ok := m.methods[method]
+	return ok
+}
+
+// sendHandler delivers queued requests to the middleware until it
+// disconnects.
+//
+// NOTE: This MUST be run as a goroutine.
+func (m *rpcMiddleware) sendHandler() {
+	for {
+		select {
+		case req := <-m.outgoing:
+			if err := m.stream.Send(req); err != nil {
+				rpcsLog.Errorf("unable to send request to RPC "+
+					"middleware %v: %v", m.name, err)
+				return
+			}
+		case <-m.quit:
+			return
+		}
+	}
+}
+
+// receiveHandler dispatches each response of the middleware to the request
+// awaiting it. Responses to requests which are no longer awaited are
+// ignored. An error is returned once the middleware disconnects.
+func (m *rpcMiddleware) receiveHandler() error {
+	for {
+		resp, err := m.stream.Recv()
+		if err != nil {
+			return err
+		}
+
+		m.pendingMtx.Lock()
+		respChan, ok := m.pending[resp.RequestId]
+		delete(m.pending, resp.RequestId)
+		m.pendingMtx.Unlock()
+		if !ok {
+			continue
+		}
+
+		respChan <- resp
+	}
+}
+
+// notify queues the passed request for delivery to an observer. If the
+// observer has fallen behind, then the request is dropped rather than
+// delaying the call.
+func (m *rpcMiddleware) notify(req *lnrpc.RPCMiddlewareRequest) {
+	select {
+	case m.outgoing <- req:
+	default:
+		rpcsLog.Warnf("RPC middleware %v has fallen behind, dropping "+
+			"request %v", m.name, req.RequestId)
+	}
+}
+
+// intercept sends the passed request to the middleware, and waits for its
+// response. An error is returned if the middleware doesn't respond within
+// the timeout, or disconnects.
+func (m *rpcMiddleware) intercept(req *lnrpc.RPCMiddlewareRequest,
+	timeout time.Duration) (*lnrpc.RPCMiddlewareResponse, error) {
+
+	respChan := make(chan *lnrpc.RPCMiddlewareResponse, 1)
+	m.pendingMtx.Lock()
+	m.pending[req.RequestId] = respChan
+	m.pendingMtx.Unlock()
+
+	defer func() {
+		m.pendingMtx.Lock()
+		delete(m.pending, req.RequestId)
+		m.pendingMtx.Unlock()
+	}()
+
+	deadline := time.After(timeout)
+	select {
+	case m.outgoing <- req:
+	case <-deadline:
+		return nil, fmt.Errorf("timed out")
+	case <-m.quit:
+		return nil, fmt.Errorf("middleware disconnected")
+	}
+
+	select {
+	case resp := <-respChan:
+		return resp, nil
+	case <-deadline:
+		return nil, fmt.Errorf("timed out")
+	case <-m.quit:
+		return nil, fmt.Errorf("middleware disconnected")
+	}
+}
+
+// middlewareByName implements sort.Interface to order middleware by name.
+type middlewareByName []*rpcMiddleware
+
+func (m middlewareByName) Len() int           { return len(m) }
+func (m middlewareByName) Less(i, j int) bool { return m[i].name < m[j].name }
+func (m middlewareByName) Swap(i, j int)      { m[i], m[j] = m[j], m[i] }
+
+// middlewareRegistry tracks the middleware currently registered with the RPC
+// server, and passes each incoming request through them before it's handled.
+//
+// Intercepting middleware are consulted in order of their names, each seeing
+// the request as rewritten by those before it. Any of them may deny the
+// request. Observers are then notified of the final request. If any of the
+// mandatory middleware isn't registered, then all requests are denied, so
+// policies enforced by middleware can't be bypassed by disconnecting it.
+type middlewareRegistry struct {
+	nextRequestID uint64 // To be used atomically.
+
+	timeout   time.Duration
+	mandatory []string
+
+	middlewares map[string]*rpcMiddleware
+	sync.RWMutex
+}
+
+// newMiddlewareRegistry creates a new middlewareRegistry which gives
+// intercepting middleware timeout to respond to each request, and requires
+// the middleware named within mandatory to be registered.
+func newMiddlewareRegistry(timeout time.Duration,
+	mandatory []string) *middlewareRegistry {
+
+	return &middlewareRegistry{
+		timeout:     timeout,
+		mandatory:   mandatory,
+		middlewares: make(map[string]*rpcMiddleware),
+	}
+}
+
+// register registers the middleware on the other end of the passed stream.
+// The first message sent by the middleware must register it. The call blocks
+// until the middleware disconnects, at which point it's unregistered.
+func (r *middlewareRegistry) register(
+	stream lnrpc.Lightning_RegisterRPCMiddlewareServer) error {
+
+	msg, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	reg := msg.Register
+	if reg == nil || reg.MiddlewareName == "" {
+		return fmt.Errorf("middleware must register itself by name " +
+			"within its first message")
+	}
+
+	m := &rpcMiddleware{
+		name:     reg.MiddlewareName,
+		methods:  make(map[string]struct{}),
+		readOnly: reg.ReadOnly,
+		stream:   stream,
+		outgoing: make(chan *lnrpc.RPCMiddlewareRequest,
+			middlewareQueueSize),
+		pending: make(map[uint64]chan *lnrpc.RPCMiddlewareResponse),
+		quit:    make(chan struct{}),
+	}
+	for _, method := range reg.Methods {
+		m.methods[method] = struct{}{}
+	}
+
+	r.Lock()
+	if _, ok := r.middlewares[m.name]; ok {
+		r.Unlock()
+		return fmt.Errorf("middleware %v is already registered", m.name)
+	}
+	r.middlewares[m.name] = m
+	r.Unlock()
+
+	rpcsLog.Infof("RPC middleware %v registered (read_only=%v)", m.name,
+		m.readOnly)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		m.sendHandler()
+	}()
+
+	err = m.receiveHandler()
+
+	r.Lock()
+	delete(r.middlewares, m.name)
+	r.Unlock()
+	close(m.quit)
+	wg.Wait()
+
+	rpcsLog.Infof("RPC middleware %v unregistered", m.name)
+
+	if err == io.EOF {
+		return nil
+	}
+	return err
+}
+
+// intercept passes the request to the passed method through the registered
+// middleware, rewriting it in place as instructed by them. A non-nil error
+// is returned if the request is denied.
+func (r *middlewareRegistry) intercept(method string, msg proto.Message) error {
+	if method == registerMiddlewareMethod {
+		return nil
+	}
+
+	r.RLock()
+	for _, name := range r.mandatory {
+		if _, ok := r.middlewares[name]; !ok {
+			r.RUnlock()
+			return fmt.Errorf("mandatory RPC middleware %v isn't "+
+				"registered", name)
+		}
+	}
+	var interceptors, observers []*rpcMiddleware
+	for _, m := range r.middlewares {
+		switch {
+		case !m.matches(method):
+		case m.readOnly:
+			observers = append(observers, m)
+		default:
+			interceptors = append(interceptors, m)
+		}
+	}
+	r.RUnlock()
+
+	sort.Sort(middlewareByName(interceptors))
+	for _, m := range interceptors {
+		req, err := r.newRequest(method, msg)
+		if err != nil {
+			return err
+		}
+
+		resp, err := m.intercept(req, r.timeout)
+		if err != nil {
+			return fmt.Errorf("RPC middleware %v failed to respond: "+
+				"%v", m.name, err)
+		}
+		if resp.Error != "" {
+			return fmt.Errorf("request denied by RPC middleware %v: "+
+				"%v", m.name, resp.Error)
+		}
+		if resp.Replace {
+			if err := proto.Unmarshal(resp.Replacement, msg); err != nil {
+				return fmt.Errorf("RPC middleware %v returned an "+
+					"invalid replacement: %v", m.name, err)
+			}
+		}
+	}
+
+	if len(observers) == 0 {
+		return nil
+	}
+	req, err := r.newRequest(method, msg)
+	if err != nil {
+		return err
+	}
+	for _, m := range observers {
+		m.notify(req)
+	}
+
+	return nil
+}
+
+// newRequest creates the request sent to middleware for the passed message.
+func (r *middlewareRegistry) newRequest(method string,
+	msg proto.Message) (*lnrpc.RPCMiddlewareRequest, error) {
+
+	serialized, err := proto.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.RPCMiddlewareRequest{
+		RequestId:  atomic.AddUint64(&r.nextRequestID, 1),
+		Method:     method,
+		TypeName:   proto.MessageName(msg),
+		Serialized: serialized,
+	}, nil
+}
+
+// unaryInterceptor is a gRPC interceptor which passes the request of each
+// unary call through the registered middleware before it's handled.
+func (r *middlewareRegistry) unaryInterceptor(ctx context.Context,
+	req interface{}, info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (interface{}, error) {
+
+	if msg, ok := req.(proto.Message); ok {
+		if err := r.intercept(info.FullMethod, msg); err != nil {
+			return nil, err
+		}
+	}
+
+	return handler(ctx, req)
+}
+
+// streamInterceptor is a gRPC interceptor which passes each message received
+// over a streaming call through the registered middleware before it's
+// handled.
+func (r *middlewareRegistry) streamInterceptor(srv interface{},
+	ss grpc.ServerStream, info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler) error {
+
+	return handler(srv, &interceptedStream{
+		ServerStream: ss,
+		registry:     r,
+		method:       info.FullMethod,
+	})
+}
+
+// interceptedStream wraps a server stream, passing each received message
+// through the registered middleware. A denied message terminates the call.
+type interceptedStream struct {
+	grpc.ServerStream
+
+	registry *middlewareRegistry
+	method   string
+}
+
+// RecvMsg receives the next message from the stream, and passes it through
+// the registered middleware.
+func (s *interceptedStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return nil
+	}
+
+	return s.registry.intercept(s.method, msg)
+}
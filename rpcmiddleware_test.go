@@ -0,0 +1,189 @@
+package main
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/lightningnetwork/lnd/lnrpc"
+	"google.golang.org/grpc"
+)
+
+// mockMiddlewareStream is the server side of a middleware registration
+// stream, backed by channels the test acts as the middleware through.
+type mockMiddlewareStream struct {
+	grpc.ServerStream
+
+	requests  chan *lnrpc.RPCMiddlewareRequest
+	responses chan *lnrpc.RPCMiddlewareResponse
+}
+
+func newMockMiddlewareStream() *mockMiddlewareStream {
+	return &mockMiddlewareStream{
+		requests:  make(chan *lnrpc.RPCMiddlewareRequest, 10),
+		responses: make(chan *lnrpc.RPCMiddlewareResponse, 10),
+	}
+}
+
+func (m *mockMiddlewareStream) Send(req *lnrpc.RPCMiddlewareRequest) error {
+	m.requests <- req
+	return nil
+}
+
+func (m *mockMiddlewareStream) Recv() (*lnrpc.RPCMiddlewareResponse, error) {
+	resp, ok := <-m.responses
+	if !ok {
+		return nil, io.EOF
+	}
+	return resp, nil
+}
+
+// registerMockMiddleware registers a new middleware with the registry, and
+// waits until the registration has completed.
+func registerMockMiddleware(t *testing.T, registry *middlewareRegistry,
+	reg *lnrpc.MiddlewareRegistration) (*mockMiddlewareStream, chan error) {
+
+	stream := newMockMiddlewareStream()
+	stream.responses <- &lnrpc.RPCMiddlewareResponse{Register: reg}
+
+	errChan := make(chan error, 1)
+	go func() {
+		errChan <- registry.register(stream)
+	}()
+
+	for i := 0; i < 100; i++ {
+		registry.RLock()
+		_, ok := registry.middlewares[reg.MiddlewareName]
+		registry.RUnlock()
+		if ok {
+			return stream, errChan
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatalf("middleware %v wasn't registered", reg.MiddlewareName)
+	return nil, nil
+}
+
+func TestRPCMiddleware(t *testing.T) {
+	const sendMethod = "/lnrpc.Lightning/SendPaymentSync"
+
+	registry := newMiddlewareRegistry(time.Second, []string{"limits"})
+
+	// Until the mandatory middleware is registered, all requests should
+	// be denied.
+	err := registry.intercept(sendMethod, &lnrpc.SendRequest{Amt: 1})
+	if err == nil || !strings.Contains(err.Error(), "mandatory") {
+		t.Fatalf("expected request to be denied, got: %v", err)
+	}
+
+	// Register the mandatory middleware, which enforces a spend limit on
+	// payments by denying payments above 1000 satoshis, and capping
+	// payments of 500 satoshis to 400.
+	limits, limitsErr := registerMockMiddleware(t, registry,
+		&lnrpc.MiddlewareRegistration{
+			MiddlewareName: "limits",
+			Methods:        []string{sendMethod},
+		})
+	go func() {
+		for req := range limits.requests {
+			payment := &lnrpc.SendRequest{}
+			err := proto.Unmarshal(req.Serialized, payment)
+			if err != nil {
+				t.Fatalf("unable to decode request: %v", err)
+			}
+
+			resp := &lnrpc.RPCMiddlewareResponse{
+				RequestId: req.RequestId,
+			}
+			switch {
+			case payment.Amt > 1000:
+				resp.Error = "spend limit exceeded"
+			case payment.Amt == 500:
+				payment.Amt = 400
+				resp.Replace = true
+				resp.Replacement, _ = proto.Marshal(payment)
+			}
+			limits.responses <- resp
+		}
+	}()
+
+	// A middleware can't be registered twice under the same name.
+	stream := newMockMiddlewareStream()
+	stream.responses <- &lnrpc.RPCMiddlewareResponse{
+		Register: &lnrpc.MiddlewareRegistration{MiddlewareName: "limits"},
+	}
+	if err := registry.register(stream); err == nil {
+		t.Fatalf("expected duplicate registration to fail")
+	}
+
+	// Register an observer of all calls.
+	audit, _ := registerMockMiddleware(t, registry,
+		&lnrpc.MiddlewareRegistration{
+			MiddlewareName: "audit",
+			ReadOnly:       true,
+		})
+
+	// A payment above the limit should be denied, and the observer
+	// shouldn't be notified of it.
+	err = registry.intercept(sendMethod, &lnrpc.SendRequest{Amt: 5000})
+	if err == nil || !strings.Contains(err.Error(), "spend limit") {
+		t.Fatalf("expected payment to be denied, got: %v", err)
+	}
+	select {
+	case req := <-audit.requests:
+		t.Fatalf("observer notified of denied request: %v", req)
+	default:
+	}
+
+	// A payment of 500 satoshis should be rewritten, and the observer
+	// should see the rewritten request.
+	payment := &lnrpc.SendRequest{Amt: 500}
+	if err := registry.intercept(sendMethod, payment); err != nil {
+		t.Fatalf("unable to intercept payment: %v", err)
+	}
+	if payment.Amt != 400 {
+		t.Fatalf("payment wasn't rewritten, amount is %v", payment.Amt)
+	}
+	select {
+	case req := <-audit.requests:
+		observed := &lnrpc.SendRequest{}
+		if err := proto.Unmarshal(req.Serialized, observed); err != nil {
+			t.Fatalf("unable to decode request: %v", err)
+		}
+		if req.Method != sendMethod || observed.Amt != 400 {
+			t.Fatalf("unexpected observed request: %v", req)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("observer wasn't notified of request")
+	}
+
+	// Calls to other methods shouldn't be passed to the limits
+	// middleware, but should be observed.
+	err = registry.intercept("/lnrpc.Lightning/GetInfo",
+		&lnrpc.GetInfoRequest{})
+	if err != nil {
+		t.Fatalf("unable to intercept request: %v", err)
+	}
+	select {
+	case req := <-audit.requests:
+		if req.TypeName != "lnrpc.GetInfoRequest" {
+			t.Fatalf("unexpected observed request: %v", req)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("observer wasn't notified of request")
+	}
+
+	// Once the mandatory middleware disconnects, requests should be
+	// denied once again.
+	close(limits.responses)
+	if err := <-limitsErr; err != nil {
+		t.Fatalf("unexpected registration error: %v", err)
+	}
+	err = registry.intercept(sendMethod, &lnrpc.SendRequest{Amt: 1})
+	if err == nil || !strings.Contains(err.Error(), "mandatory") {
+		t.Fatalf("expected request to be denied, got: %v", err)
+	}
+}
@@ -1,14 +1,17 @@
 package main
 
 import (
+	"bytes"
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"math"
 	"net"
+	"sort"
 	"strings"
 	"time"
 
@@ -16,12 +19,15 @@ import (
 	"sync/atomic"
 
 	"github.com/davecgh/go-spew/spew"
+	"github.com/lightningnetwork/lnd/chanbackup"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/lnrpc"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/routing"
+	"github.com/lightningnetwork/lnd/sqlstore"
 	"github.com/lightningnetwork/lnd/zpay32"
+	"github.com/roasbeef/btcd/blockchain"
 	"github.com/roasbeef/btcd/btcec"
 	"github.com/roasbeef/btcd/chaincfg"
 	"github.com/roasbeef/btcd/chaincfg/chainhash"
@@ -44,6 +50,18 @@ type rpcServer struct {
 
 	server *server
 
+	// middleware passes incoming requests through the registered RPC
+	// middleware. It's nil if the middleware framework is disabled.
+	middleware *middlewareRegistry
+
+	// quotas authenticates the credential each call is made with, and
+	// enforces its quotas on invoice creation and payments.
+	quotas *quotaEnforcer
+
+	// policyMtx serializes imports of channel policies, so each import is
+	// diffed against the policies left by the last.
+	policyMtx sync.Mutex
+
 	wg sync.WaitGroup
 
 	quit chan struct{}
@@ -55,7 +73,14 @@ var _ lnrpc.LightningServer = (*rpcServer)(nil)
 
 // newRPCServer creates and returns a new instance of the rpcServer.
 func newRPCServer(s *server) *rpcServer {
-	return &rpcServer{server: s, quit: make(chan struct{}, 1)}
+	r := &rpcServer{server: s, quit: make(chan struct{}, 1)}
+	if cfg.RPCMiddleware.Enable {
+		r.middleware = newMiddlewareRegistry(
+			cfg.RPCMiddleware.InterceptTimeout,
+			cfg.RPCMiddleware.Mandatory)
+	}
+
+	return r
 }
 
 // Start launches any helper goroutines required for the rpcServer
@@ -104,13 +129,26 @@ func addrPairsToOutputs(addrPairs map[string]int64) ([]*wire.TxOut, error) {
 
 // sendCoinsOnChain makes an on-chain transaction in or to send coins to one or
 // more addresses specified in the passed payment map. The payment map maps an
-// address to a specified output value to be sent to that address.
-func (r *rpcServer) sendCoinsOnChain(paymentMap map[string]int64) (*chainhash.Hash, error) {
+// address to a specified output value to be sent to that address. Unless
+// overrideWhitelist is set, each address must be within the sweep whitelist,
+// if one is configured.
+func (r *rpcServer) sendCoinsOnChain(paymentMap map[string]int64,
+	overrideWhitelist bool) (*chainhash.Hash, error) {
+
 	outputs, err := addrPairsToOutputs(paymentMap)
 	if err != nil {
 		return nil, err
 	}
 
+	err = cfg.sweepWhitelist.checkOutputs(outputs, overrideWhitelist)
+	if err != nil {
+		return nil, err
+	}
+	if overrideWhitelist && cfg.sweepWhitelist != nil {
+		rpcsLog.Warnf("Sweep whitelist overridden to pay to %v "+
+			"address(es)", len(outputs))
+	}
+
 	return r.server.lnwallet.SendOutputs(outputs)
 }
 
@@ -121,8 +159,12 @@ func (r *rpcServer) SendCoins(ctx context.Context,
 
 	rpcsLog.Infof("[sendcoins] addr=%v, amt=%v", in.Addr, btcutil.Amount(in.Amount))
 
+	if err := r.quotas.authorize(ctx, permSendFunds); err != nil {
+		return nil, err
+	}
+
 	paymentMap := map[string]int64{in.Addr: in.Amount}
-	txid, err := r.sendCoinsOnChain(paymentMap)
+	txid, err := r.sendCoinsOnChain(paymentMap, in.OverrideWhitelist)
 	if err != nil {
 		return nil, err
 	}
@@ -137,7 +179,11 @@ func (r *rpcServer) SendCoins(ctx context.Context,
 func (r *rpcServer) SendMany(ctx context.Context,
 	in *lnrpc.SendManyRequest) (*lnrpc.SendManyResponse, error) {
 
-	txid, err := r.sendCoinsOnChain(in.AddrToAmount)
+	if err := r.quotas.authorize(ctx, permSendFunds); err != nil {
+		return nil, err
+	}
+
+	txid, err := r.sendCoinsOnChain(in.AddrToAmount, in.OverrideWhitelist)
 	if err != nil {
 		return nil, err
 	}
@@ -194,11 +240,9 @@ func (r *rpcServer) ConnectPeer(ctx context.Context,
 		return nil, fmt.Errorf("need: lnc pubkeyhash@hostname")
 	}
 
-	pubkeyHex, err := hex.DecodeString(in.Addr.Pubkey)
-	if err != nil {
-		return nil, err
-	}
-	pubkey, err := btcec.ParsePubKey(pubkeyHex, btcec.S256())
+	// The peer may be referred to by either its public key, or its
+	// alias if it's already known to us.
+	pubkey, err := r.resolveNode(in.Addr.Pubkey)
 	if err != nil {
 		return nil, err
 	}
@@ -223,8 +267,9 @@ func (r *rpcServer) ConnectPeer(ctx context.Context,
 	return &lnrpc.ConnectPeerResponse{}, nil
 }
 
-// OpenChannel attempts to open a singly funded channel specified in the
-// request to a remote peer.
+// OpenChannel attempts to open a channel specified in the request to a remote
+// peer. If the request asks the remote peer to contribute funds as well, then
+// a dual funded channel is opened.
 func (r *rpcServer) OpenChannel(in *lnrpc.OpenChannelRequest,
 	updateStream lnrpc.Lightning_OpenChannelServer) error {
 
@@ -232,46 +277,31 @@ func (r *rpcServer) OpenChannel(in *lnrpc.OpenChannelRequest,
 		"allocation(us=%v, them=%v) numconfs=%v", in.TargetPeerId,
 		in.LocalFundingAmount, in.PushSat, in.NumConfs)
 
+	err := r.quotas.authorize(updateStream.Context(), permOpenChannel)
+	if err != nil {
+		return err
+	}
+
 	localFundingAmt := btcutil.Amount(in.LocalFundingAmount)
+	remoteFundingAmt := btcutil.Amount(in.RemoteFundingAmount)
 	remoteInitialBalance := btcutil.Amount(in.PushSat)
-
-	// Ensure that the initial balance of the remote party (if pushing
-	// satoshis) does not execeed the amount the local party has requested
-	// for funding.
-	if remoteInitialBalance >= localFundingAmt {
-		return fmt.Errorf("amount pushed to remote peer for initial " +
-			"state must be below the local funding amount")
+	if err := checkOpenChanAmounts(in); err != nil {
+		return err
 	}
 
-	const minChannelSize = btcutil.Amount(6000)
-
-	// Restrict the size of the channel we'll actually open. Atm, we
-	// require the amount to be above 6k satoahis s we currently hard-coded
-	// a 5k satoshi fee in several areas. As a result 6k sat is the min
-	// channnel size that allows us to safely sit above the dust threshold
-	// after fees are applied
-	// TODO(roasbeef): remove after dynamic fees are in
-	if localFundingAmt < minChannelSize {
-		return fmt.Errorf("channel is too small, the minimum channel "+
-			"size is: %v (6k sat)", minChannelSize)
+	numConfs, err := openChanNumConfs(in)
+	if err != nil {
+		return err
 	}
 
-	var (
-		nodepubKey      *btcec.PublicKey
-		nodepubKeyBytes []byte
-		err             error
-	)
-
 	// TODO(roasbeef): also return channel ID?
 
-	// If the node key is set, the we'll parse the raw bytes into a pubkey
-	// object so we can easily manipulate it. If this isn't set, then we
-	// expected the TargetPeerId to be set accordingly.
-	if len(in.NodePubkey) != 0 {
-		nodepubKey, err = btcec.ParsePubKey(in.NodePubkey, btcec.S256())
-		if err != nil {
-			return err
-		}
+	nodepubKey, err := r.openChanNodeKey(in)
+	if err != nil {
+		return err
+	}
+	var nodepubKeyBytes []byte
+	if nodepubKey != nil {
 		nodepubKeyBytes = nodepubKey.SerializeCompressed()
 	}
 
@@ -279,7 +309,8 @@ func (r *rpcServer) OpenChannel(in *lnrpc.OpenChannelRequest,
 	// open a new channel. A stream is returned in place, this stream will
 	// be used to consume updates of the state of the pending channel.
 	updateChan, errChan := r.server.OpenChannel(in.TargetPeerId,
-		nodepubKey, localFundingAmt, remoteInitialBalance, in.NumConfs)
+		nodepubKey, localFundingAmt, remoteFundingAmt,
+		remoteInitialBalance, numConfs)
 
 	var outpoint wire.OutPoint
 out:
@@ -321,6 +352,205 @@ out:
 	return nil
 }
 
+// checkOpenChanAmounts checks that the amounts within a request to open a
+// channel are consistent with one another, and with the minimum channel size.
+func checkOpenChanAmounts(in *lnrpc.OpenChannelRequest) error {
+	localFundingAmt := btcutil.Amount(in.LocalFundingAmount)
+	remoteInitialBalance := btcutil.Amount(in.PushSat)
+
+	// Ensure that the initial balance of the remote party (if pushing
+	// satoshis) does not execeed the amount the local party has requested
+	// for funding.
+	if remoteInitialBalance >= localFundingAmt {
+		return fmt.Errorf("amount pushed to remote peer for initial " +
+			"state must be below the local funding amount")
+	}
+
+	// If the remote party is asked to contribute funds of its own, then a
+	// dual funded channel will be opened. As each party's balance is then
+	// derived from its own contribution, satoshis can't also be pushed.
+	remoteFundingAmt := btcutil.Amount(in.RemoteFundingAmount)
+	switch {
+	case remoteFundingAmt < 0:
+		return fmt.Errorf("remote funding amount must be positive")
+	case remoteFundingAmt != 0 && remoteInitialBalance != 0:
+		return fmt.Errorf("satoshis cannot be pushed to the remote " +
+			"peer within a dual funded channel")
+	}
+
+	const minChannelSize = btcutil.Amount(6000)
+
+	// Restrict the size of the channel we'll actually open. Atm, we
+	// require the amount to be above 6k satoahis s we currently hard-coded
+	// a 5k satoshi fee in several areas. As a result 6k sat is the min
+	// channnel size that allows us to safely sit above the dust threshold
+	// after fees are applied
+	// TODO(roasbeef): remove after dynamic fees are in
+	if localFundingAmt < minChannelSize {
+		return fmt.Errorf("channel is too small, the minimum channel "+
+			"size is: %v (6k sat)", minChannelSize)
+	}
+
+	return nil
+}
+
+// openChanNodeKey returns the public key of the node targeted by a request to
+// open a channel, or nil if the node is targeted by peer ID instead.
+func (r *rpcServer) openChanNodeKey(
+	in *lnrpc.OpenChannelRequest) (*btcec.PublicKey, error) {
+
+	// If the node key is set, the we'll parse the raw bytes into a pubkey
+	// object so we can easily manipulate it. Otherwise, the node may be
+	// referred to by its public key or alias as a string. If neither is
+	// set, then we expected the TargetPeerId to be set accordingly.
+	switch {
+	case len(in.NodePubkey) != 0:
+		return btcec.ParsePubKey(in.NodePubkey, btcec.S256())
+	case in.NodePubkeyString != "":
+		return r.resolveNode(in.NodePubkeyString)
+	}
+
+	return nil, nil
+}
+
+// EstimateOpenChannel projects the cost to us of opening the channel described
+// by the passed request, without initiating the funding workflow: the fee of
+// the funding transaction, the reserve each party must maintain, and the cost
+// of a force close. It also reports whether our wallet's coins are sufficient
+// to fund the channel, so the caller may present an accurate confirmation
+// before opening it.
+func (r *rpcServer) EstimateOpenChannel(ctx context.Context,
+	in *lnrpc.OpenChannelRequest) (*lnrpc.EstimateOpenChannelResponse, error) {
+
+	if err := checkOpenChanAmounts(in); err != nil {
+		return nil, err
+	}
+	if _, err := openChanNumConfs(in); err != nil {
+		return nil, err
+	}
+
+	nodeKey, err := r.openChanNodeKey(in)
+	if err != nil {
+		return nil, err
+	}
+	peer, err := r.server.findOpenChanPeer(in.TargetPeerId, nodeKey)
+	if err != nil {
+		return nil, err
+	}
+
+	localAmt := btcutil.Amount(in.LocalFundingAmount)
+	remoteAmt := btcutil.Amount(in.RemoteFundingAmount)
+	capacity := localAmt + remoteAmt
+
+	estimate, err := r.server.lnwallet.EstimateFunding(localAmt, remoteAmt)
+	if err != nil {
+		return nil, err
+	}
+
+	// The funding output of a single funder channel also carries the
+	// commitment fee, which we pay on top of the requested amount. Only
+	// such channels use anchor commitments, with both anchors paid for
+	// by us as the initiator.
+	fundingCapacity := capacity
+	var anchorsAmt btcutil.Amount
+	if remoteAmt == 0 {
+		fundingCapacity += estimate.CommitFee
+		if commitFormat(peer) == channeldb.AnchorCommitment {
+			anchorsAmt = 2 * lnwallet.AnchorSize
+		}
+	}
+
+	// Should the channel be force closed, our balance is swept once the
+	// CSV delay has passed, which we project at the fee rate the nursery
+	// would currently pay.
+	sweepFee := defaultSweepFee
+	feeRate, err := r.server.lnwallet.EstimateFeePerByte(sweepConfTarget)
+	if err == nil {
+		// Fee rates below 1 sat/byte won't relay.
+		if feeRate < 1 {
+			feeRate = 1
+		}
+
+		vsize := (lnwallet.ToLocalSweepTxCost +
+			blockchain.WitnessScaleFactor - 1) /
+			blockchain.WitnessScaleFactor
+		sweepFee = feeRate * btcutil.Amount(vsize)
+	}
+
+	chanReserve := chanReserveForCapacity(capacity)
+	return &lnrpc.EstimateOpenChannelResponse{
+		Capacity:         int64(fundingCapacity),
+		FundingFee:       int64(estimate.FundingFee),
+		FundingFeeRate:   int64(estimate.FeeRate),
+		NumInputs:        uint32(estimate.NumInputs),
+		ChangeAmount:     int64(estimate.ChangeAmt),
+		CommitFee:        int64(estimate.CommitFee),
+		AnchorsAmount:    int64(anchorsAmt),
+		LocalReserve:     int64(chanReserve),
+		RemoteReserve:    int64(chanReserve),
+		CsvDelay:         cfg.CsvDelay.policy.DelayForCapacity(capacity),
+		SweepFee:         int64(sweepFee),
+		ForceCloseCost:   int64(estimate.CommitFee + anchorsAmt + sweepFee),
+		AvailableBalance: int64(estimate.Available),
+		CanFund:          estimate.CanFund,
+	}, nil
+}
+
+// GetFundingPolicy returns the policy currently limiting the channels our
+// peers may open with us.
+func (r *rpcServer) GetFundingPolicy(ctx context.Context,
+	in *lnrpc.GetFundingPolicyRequest) (*lnrpc.FundingPolicy, error) {
+
+	policy := r.server.fundingMgr.FundingPolicy()
+	return &lnrpc.FundingPolicy{
+		MinChanSize:     int64(policy.MinChanSize),
+		MaxChanSize:     int64(policy.MaxChanSize),
+		MaxChansPerPeer: policy.MaxChansPerPeer,
+		MaxPeerExposure: int64(policy.MaxPeerExposure),
+	}, nil
+}
+
+// UpdateFundingPolicy replaces the policy limiting the channels our peers may
+// open with us. The new policy is persisted, and applies to funding requests
+// received from now on.
+func (r *rpcServer) UpdateFundingPolicy(ctx context.Context,
+	in *lnrpc.FundingPolicy) (*lnrpc.UpdateFundingPolicyResponse, error) {
+
+	if err := r.quotas.authorize(ctx, permUpdatePolicy); err != nil {
+		return nil, err
+	}
+
+	policy := &channeldb.FundingPolicy{
+		MinChanSize:     btcutil.Amount(in.MinChanSize),
+		MaxChanSize:     btcutil.Amount(in.MaxChanSize),
+		MaxChansPerPeer: in.MaxChansPerPeer,
+		MaxPeerExposure: btcutil.Amount(in.MaxPeerExposure),
+	}
+	if err := r.server.fundingMgr.SetFundingPolicy(policy); err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.UpdateFundingPolicyResponse{}, nil
+}
+
+// openChanNumConfs returns the number of confirmations the funding
+// transaction of the requested channel must reach before the channel is
+// opened. A zero-conf channel is opened as soon as the funding transaction is
+// broadcast, which the remote peer only accepts if it trusts us not to double
+// spend it.
+func openChanNumConfs(in *lnrpc.OpenChannelRequest) (uint32, error) {
+	if !in.ZeroConf {
+		return in.NumConfs, nil
+	}
+
+	if in.RemoteFundingAmount != 0 {
+		return 0, fmt.Errorf("zero-conf channels must be funded by " +
+			"us alone")
+	}
+
+	return 0, nil
+}
+
 // OpenChannelSync is a synchronous version of the OpenChannel RPC call. This
 // call is meant to be consumed by clients to the REST proxy. As with all other
 // sync calls, all byte slices are instead to be populated as hex encoded
@@ -332,6 +562,10 @@ func (r *rpcServer) OpenChannelSync(ctx context.Context,
 		"allocation(us=%v, them=%v) numconfs=%v", in.TargetPeerId,
 		in.LocalFundingAmount, in.PushSat, in.NumConfs)
 
+	if err := r.quotas.authorize(ctx, permOpenChannel); err != nil {
+		return nil, err
+	}
+
 	// Creation of channels before the wallet syncs up is currently
 	// disallowed.
 	isSynced, err := r.server.lnwallet.IsSynced()
@@ -343,14 +577,10 @@ func (r *rpcServer) OpenChannelSync(ctx context.Context,
 			"wallet is fully synced")
 	}
 
-	// Decode the provided target node's public key, parsing it into a pub
-	// key object. For all sync call, byte slices are expected to be
-	// encoded as hex strings.
-	keyBytes, err := hex.DecodeString(in.NodePubkeyString)
-	if err != nil {
-		return nil, err
-	}
-	nodepubKey, err := btcec.ParsePubKey(keyBytes, btcec.S256())
+	// Resolve the provided target node's public key or alias, parsing it
+	// into a pub key object. For all sync call, byte slices are expected
+	// to be encoded as hex strings.
+	nodepubKey, err := r.resolveNode(in.NodePubkeyString)
 	if err != nil {
 		return nil, err
 	}
@@ -366,8 +596,26 @@ func (r *rpcServer) OpenChannelSync(ctx context.Context,
 			"initial state must be below the local funding amount")
 	}
 
+	// If the remote party is asked to contribute funds of its own, then a
+	// dual funded channel will be opened. As each party's balance is then
+	// derived from its own contribution, satoshis can't also be pushed.
+	remoteFundingAmt := btcutil.Amount(in.RemoteFundingAmount)
+	switch {
+	case remoteFundingAmt < 0:
+		return nil, fmt.Errorf("remote funding amount must be positive")
+	case remoteFundingAmt != 0 && remoteInitialBalance != 0:
+		return nil, fmt.Errorf("satoshis cannot be pushed to the remote " +
+			"peer within a dual funded channel")
+	}
+
+	numConfs, err := openChanNumConfs(in)
+	if err != nil {
+		return nil, err
+	}
+
 	updateChan, errChan := r.server.OpenChannel(in.TargetPeerId,
-		nodepubKey, localFundingAmt, remoteInitialBalance, in.NumConfs)
+		nodepubKey, localFundingAmt, remoteFundingAmt,
+		remoteInitialBalance, numConfs)
 
 	select {
 	// If an error occurs them immediately return the error to the client.
@@ -415,6 +663,71 @@ func (r *rpcServer) CloseChannel(in *lnrpc.CloseChannelRequest,
 	rpcsLog.Tracef("[closechannel] request for ChannelPoint(%v)",
 		chanPoint)
 
+	// The fee of a force closure is fixed by the commitment transaction,
+	// so a fee rate may only be chosen for cooperative closures.
+	feeRateSet := in.TargetConf != 0 || in.SatPerByte != 0
+	switch {
+	case force && feeRateSet:
+		return fmt.Errorf("a fee rate can't be set for force closures")
+	case in.TargetConf != 0 && in.SatPerByte != 0:
+		return fmt.Errorf("either target_conf or sat_per_byte may be " +
+			"set, not both")
+	case in.TargetConf < 0 || in.SatPerByte < 0:
+		return fmt.Errorf("target_conf and sat_per_byte must be " +
+			"positive")
+	}
+
+	// A dry run reports the transaction a force closure would broadcast
+	// without broadcasting it. Cooperative closures can't be dry run, as
+	// their closing transaction is only known once negotiated with the
+	// remote peer.
+	if in.DryRun {
+		if !force {
+			return fmt.Errorf("only force closures can be dry run")
+		}
+
+		channel, err := r.fetchActiveChannel(*chanPoint)
+		if err != nil {
+			return err
+		}
+		closeTx, localAmt, csvDelay, err := channel.DryRunForceClose()
+		if err != nil {
+			return err
+		}
+
+		var rawTx bytes.Buffer
+		if err := closeTx.Serialize(&rawTx); err != nil {
+			return err
+		}
+		closingTxid := closeTx.TxHash()
+
+		rpcsLog.Infof("[closechannel] dry run: force closure of "+
+			"ChannelPoint(%v) would broadcast %v", chanPoint,
+			closingTxid)
+
+		return updateStream.Send(&lnrpc.CloseStatusUpdate{
+			Update: &lnrpc.CloseStatusUpdate_DryRun{
+				DryRun: &lnrpc.CloseDryRun{
+					Txid:        closingTxid[:],
+					RawTx:       rawTx.Bytes(),
+					LocalAmount: int64(localAmt),
+					CsvDelay:    csvDelay,
+				},
+			},
+		})
+	}
+
+	// Force closures are permitted separately from cooperative closures,
+	// as they lock up our funds until the CSV delay expires.
+	closePerm := permCoopClose
+	if force {
+		closePerm = permForceClose
+	}
+	err = r.quotas.authorize(updateStream.Context(), closePerm)
+	if err != nil {
+		return err
+	}
+
 	var (
 		updateChan chan *lnrpc.CloseStatusUpdate
 		errChan    chan error
@@ -452,11 +765,14 @@ func (r *rpcServer) CloseChannel(in *lnrpc.CloseChannelRequest,
 				if err := channel.DeleteState(); err != nil {
 					return err
 				}
+				r.server.chanBackup.requestUpdate()
 				return fmt.Errorf("channel has been closed by remote party")
 			}
 
 			return err
 		}
+		r.server.chanHistory.closeInitiated(channel,
+			"unilateral, local")
 
 		updateChan = make(chan *lnrpc.CloseStatusUpdate)
 		errChan = make(chan error)
@@ -474,29 +790,13 @@ func (r *rpcServer) CloseChannel(in *lnrpc.CloseChannelRequest,
 			// Next, we enter the second phase, waiting for the
 			// channel to be confirmed before we finalize the force
 			// closure.
-			notifier := r.server.chainNotifier
-			confNtfn, err := notifier.RegisterConfirmationsNtfn(closingTxid, 1)
-			if err != nil {
-				errChan <- err
+			err := r.resolveForceClose(channel, chanPoint,
+				closingTxid)
+			switch {
+			case err == errResolveAborted:
 				return
-			}
-
-			select {
-			case txConf, ok := <-confNtfn.Confirmed:
-				if !ok {
-					return
-				}
-
-				// As the channel has been closed, we can now
-				// delete it's state from the database.
-				rpcsLog.Infof("ChannelPoint(%v) is now "+
-					"closed at height %v", chanPoint,
-					txConf.BlockHeight)
-				if err := channel.DeleteState(); err != nil {
-					errChan <- err
-					return
-				}
-			case <-r.quit:
+			case err != nil:
+				errChan <- err
 				return
 			}
 
@@ -510,20 +810,24 @@ func (r *rpcServer) CloseChannel(in *lnrpc.CloseChannelRequest,
 					},
 				},
 			}
-
-			// Finally, signal to the breachArbiter that it no
-			// longer needs to watch the channel as it's been
-			// closed.
-			r.server.breachArbiter.settledContracts <- chanPoint
 		}()
 
 	} else {
 		// Otherwise, the caller has requested a regular interactive
 		// cooperative channel closure. So we'll forward the request to
 		// the htlc switch which will handle the negotiation and
-		// broadcast details.
+		// broadcast details. Unless a fee rate was requested, our
+		// version of the closing transaction pays the default fee.
+		var fee btcutil.Amount
+		if feeRateSet {
+			fee, err = r.coopCloseFee(chanPoint, in)
+			if err != nil {
+				return err
+			}
+		}
+
 		updateChan, errChan = r.server.htlcSwitch.CloseLink(chanPoint,
-			CloseRegular)
+			CloseRegular, fee)
 	}
 out:
 	for {
@@ -587,6 +891,45 @@ func (r *rpcServer) fetchActiveChannel(chanPoint wire.OutPoint) (*lnwallet.Light
 		dbChan)
 }
 
+// coopCloseFee returns the fee our version of the cooperative closure
+// transaction of the target channel must pay to achieve the fee rate set
+// within the request, either explicitly, or as estimated to confirm within
+// the target number of blocks.
+func (r *rpcServer) coopCloseFee(chanPoint *wire.OutPoint,
+	in *lnrpc.CloseChannelRequest) (btcutil.Amount, error) {
+
+	feeRate := btcutil.Amount(in.SatPerByte)
+	if in.TargetConf != 0 {
+		var err error
+		feeRate, err = r.server.lnwallet.EstimateFeePerByte(
+			uint32(in.TargetConf),
+		)
+		if err != nil {
+			return 0, fmt.Errorf("unable to estimate fee rate for "+
+				"target of %v blocks: %v", in.TargetConf, err)
+		}
+
+		// Fee rates below 1 sat/byte won't relay.
+		if feeRate < 1 {
+			feeRate = 1
+		}
+	}
+
+	channel, err := r.fetchActiveChannel(*chanPoint)
+	if err != nil {
+		return 0, err
+	}
+	fee, err := channel.CooperativeCloseFeeAtRate(feeRate)
+	if err != nil {
+		return 0, err
+	}
+
+	rpcsLog.Infof("[closechannel] closing ChannelPoint(%v) at %v sat/byte, "+
+		"paying fee of %v", chanPoint, feeRate, fee)
+
+	return fee, nil
+}
+
 // forceCloseChan executes a unilateral close of the target channel by
 // broadcasting the current commitment state directly on-chain. Once the
 // commitment transaction has been broadcast, a struct describing the final
@@ -603,16 +946,35 @@ func (r *rpcServer) forceCloseChan(channel *lnwallet.LightningChannel) (*chainha
 	closeTx := closeSummary.CloseTx
 	txid := closeTx.TxHash()
 
+	// The fee of the commitment was negotiated while the channel was
+	// open, and may well fall short of what's required for the close to
+	// confirm promptly. If the commitment carries our anchor, then we'll
+	// bump its fee through a child spending it.
+	childTx, err := r.createAnchorChild(closeSummary)
+	if err != nil {
+		rpcsLog.Errorf("Unable to create anchor child of force close "+
+			"of ChannelPoint(%v): %v", channel.ChannelPoint(), err)
+	}
+
 	// With the close transaction in hand, broadcast the transaction to the
-	// network, thereby entering the psot channel resolution state.
+	// network, thereby entering the psot channel resolution state. Any
+	// child is broadcast along with it as a package, which the nursery
+	// tracks until the close confirms.
 	rpcsLog.Infof("Broadcasting force close transaction, ChannelPoint(%v): %v",
 		channel.ChannelPoint(), newLogClosure(func() string {
 			return spew.Sdump(closeTx)
 		}))
-	if err := r.server.lnwallet.PublishTransaction(closeTx); err != nil {
+	anchorSpent, err := r.server.utxoNursery.broadcastClose(closeTx, childTx)
+	if err != nil {
 		return nil, err
 	}
 
+	// Once the child has been broadcast, the anchor is no longer ours to
+	// sweep.
+	if anchorSpent {
+		closeSummary.AnchorSignDesc = nil
+	}
+
 	// Send the closed channel summary over to the utxoNursery in order to
 	// have its outputs swept back into the wallet once they're mature.
 	r.server.utxoNursery.incubateOutputs(closeSummary)
@@ -620,6 +982,75 @@ func (r *rpcServer) forceCloseChan(channel *lnwallet.LightningChannel) (*chainha
 	return &txid, nil
 }
 
+// createAnchorChild creates a child spending our anchor output of the passed
+// force closed commitment, which bumps the fee of the commitment to the fee
+// rate estimated for sweeps. If no child is warranted, or no fee rate can be
+// estimated, then nil is returned.
+func (r *rpcServer) createAnchorChild(
+	closeSummary *lnwallet.ForceCloseSummary) (*wire.MsgTx, error) {
+
+	if closeSummary.AnchorSignDesc == nil {
+		return nil, nil
+	}
+
+	feeRate, err := r.server.lnwallet.EstimateFeePerByte(sweepConfTarget)
+	switch {
+	case err == lnwallet.ErrNoFeeEstimate:
+		return nil, nil
+	case err != nil:
+		return nil, err
+	}
+
+	// Fee rates below 1 sat/byte won't relay.
+	if feeRate < 1 {
+		feeRate = 1
+	}
+
+	return r.server.lnwallet.CreateAnchorChild(closeSummary,
+		uint64(feeRate))
+}
+
+// errResolveAborted is returned by resolveForceClose if it's interrupted
+// before the closing transaction confirms.
+var errResolveAborted = errors.New("force close resolution aborted")
+
+// resolveForceClose waits for the transaction force closing the channel to
+// confirm, then deletes the channel's state, and signals to the breachArbiter
+// that it no longer needs to watch the channel.
+func (r *rpcServer) resolveForceClose(channel *lnwallet.LightningChannel,
+	chanPoint *wire.OutPoint, closingTxid *chainhash.Hash) error {
+
+	notifier := r.server.chainNotifier
+	confNtfn, err := notifier.RegisterConfirmationsNtfn(closingTxid, 1)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case txConf, ok := <-confNtfn.Confirmed:
+		if !ok {
+			return errResolveAborted
+		}
+
+		// As the channel has been closed, we can now delete it's state
+		// from the database.
+		rpcsLog.Infof("ChannelPoint(%v) is now closed at height %v",
+			chanPoint, txConf.BlockHeight)
+		if err := channel.DeleteState(); err != nil {
+			return err
+		}
+		r.server.chanHistory.channelResolved(channel, closingTxid)
+		r.server.outbox.channelClosed(channel, closingTxid)
+		r.server.chanBackup.requestUpdate()
+	case <-r.quit:
+		return errResolveAborted
+	}
+
+	r.server.breachArbiter.settledContracts <- chanPoint
+
+	return nil
+}
+
 // GetInfo serves a request to the "getinfo" RPC call. This call returns
 // general information concerning the lightning node including it's LN ID,
 // identity address, and information concerning the number of open+pending
@@ -650,6 +1081,14 @@ func (r *rpcServer) GetInfo(ctx context.Context,
 		return nil, err
 	}
 
+	// If the block cache is enabled, then report how effective it's been.
+	var cacheHits, cacheMisses uint64
+	if cache, ok := r.server.bio.(*chainCache); ok {
+		cacheHits, cacheMisses = cache.stats()
+	}
+
+	numSuspectedProbes, numMaskedProbes := r.server.probes.stats()
+
 	// TODO(roasbeef): add synced height n stuff
 	return &lnrpc.GetInfoResponse{
 		IdentityPubkey:     hex.EncodeToString(idPub),
@@ -660,6 +1099,16 @@ func (r *rpcServer) GetInfo(ctx context.Context,
 		BlockHash:          bestHash.String(),
 		SyncedToChain:      isSynced,
 		Testnet:            activeNetParams.Params == &chaincfg.TestNet3Params,
+		NumCorrelatedHtlcs: atomic.LoadUint64(
+			&r.server.htlcSwitch.numCorrelatedHTLCs,
+		),
+		NumHashExposureRejections: atomic.LoadUint64(
+			&r.server.htlcSwitch.numHashExposureRejections,
+		),
+		BlockCacheHits:     cacheHits,
+		BlockCacheMisses:   cacheMisses,
+		NumSuspectedProbes: numSuspectedProbes,
+		NumMaskedProbes:    numMaskedProbes,
 	}, nil
 }
 
@@ -693,17 +1142,30 @@ func (r *rpcServer) ListPeers(ctx context.Context,
 			satRecv += int64(c.TotalSatoshisReceived)
 		}
 
+		// The connection quality of the peer accounts for its history
+		// since start up, not only its current connection. The HTLC
+		// resolution time is expressed in microseconds, like the
+		// ping time.
+		score := r.server.peerScores.score(serverPeer.addr.IdentityKey)
+
 		nodePub := serverPeer.addr.IdentityKey.SerializeCompressed()
 		peer := &lnrpc.Peer{
-			PubKey:    hex.EncodeToString(nodePub),
-			PeerId:    serverPeer.id,
-			Address:   serverPeer.conn.RemoteAddr().String(),
-			Inbound:   serverPeer.inbound,
-			BytesRecv: atomic.LoadUint64(&serverPeer.bytesReceived),
-			BytesSent: atomic.LoadUint64(&serverPeer.bytesSent),
-			SatSent:   satSent,
-			SatRecv:   satRecv,
-			PingTime:  serverPeer.PingTime(),
+			PubKey:             hex.EncodeToString(nodePub),
+			PeerId:             serverPeer.id,
+			Address:            serverPeer.conn.RemoteAddr().String(),
+			Inbound:            serverPeer.inbound,
+			BytesRecv:          atomic.LoadUint64(&serverPeer.bytesReceived),
+			BytesSent:          atomic.LoadUint64(&serverPeer.bytesSent),
+			SatSent:            satSent,
+			SatRecv:            satRecv,
+			PingTime:           serverPeer.PingTime(),
+			Uptime:             score.Uptime,
+			HtlcResolutionTime: int64(score.HTLCResolution / time.Microsecond),
+			QualityScore:       score.Score,
+		}
+		if r.server.jamming != nil {
+			peer.Reputation = int64(r.server.jamming.reputation(
+				serverPeer.addr.IdentityKey))
 		}
 
 		resp.Peers = append(resp.Peers, peer)
@@ -824,9 +1286,22 @@ func (r *rpcServer) ListChannels(ctx context.Context,
 		var chanID uint64
 		chanID, _ = graph.ChannelID(chanPoint)
 
-		var peerOnline bool
-		if _, err := r.server.findPeer(nodePub); err == nil {
+		var (
+			peerOnline      bool
+			shutdownPending bool
+		)
+		if peer, err := r.server.findPeer(nodePub); err == nil {
 			peerOnline = true
+
+			// The intent to close a channel is only held by the
+			// channel state machine, so it can only be reported
+			// while the channel is active.
+			peer.activeChanMtx.RLock()
+			lnChan, ok := peer.activeChannels[*chanPoint]
+			peer.activeChanMtx.RUnlock()
+			if ok {
+				shutdownPending = lnChan.ShutdownPending()
+			}
 		}
 
 		channel := &lnrpc.ActiveChannel{
@@ -841,6 +1316,8 @@ func (r *rpcServer) ListChannels(ctx context.Context,
 			TotalSatoshisReceived: int64(dbChannel.TotalSatoshisReceived),
 			NumUpdates:            dbChannel.NumUpdates,
 			PendingHtlcs:          make([]*lnrpc.HTLC, len(dbChannel.Htlcs)),
+			ShutdownPending:       shutdownPending,
+			OutgoingFrozen:        dbChannel.OutgoingFrozen,
 		}
 
 		for i, htlc := range dbChannel.Htlcs {
@@ -859,6 +1336,277 @@ func (r *rpcServer) ListChannels(ctx context.Context,
 	return resp, nil
 }
 
+// ExportChanBackup returns an encrypted static backup of all of our current
+// channels. The backup can only be decrypted by this node.
+func (r *rpcServer) ExportChanBackup(ctx context.Context,
+	in *lnrpc.ExportChanBackupRequest) (*lnrpc.ChanBackupSnapshot, error) {
+
+	multi, err := chanbackup.FetchMulti(r.server.chanDB)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bytes.Buffer
+	backupKey := chanbackup.DeriveBackupKey(r.server.identityPriv)
+	if err := multi.PackToWriter(&b, backupKey); err != nil {
+		return nil, err
+	}
+
+	rpcsLog.Debugf("[exportchanbackup] exported backup of %v channels",
+		len(multi.StaticBackups))
+
+	return &lnrpc.ChanBackupSnapshot{
+		MultiChanBackup: b.Bytes(),
+	}, nil
+}
+
+// VerifyChanBackup checks that the passed static channel backup can be
+// decrypted with our key, and validates each channel within it against our
+// live channel state.
+func (r *rpcServer) VerifyChanBackup(ctx context.Context,
+	in *lnrpc.ChanBackupSnapshot) (*lnrpc.VerifyChanBackupResponse, error) {
+
+	reports, err := r.verifyChanBackup(in.MultiChanBackup, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.VerifyChanBackupResponse{
+		Channels: reports,
+	}, nil
+}
+
+// RestoreChanBackup restores the channels within the passed static channel
+// backup. Each channel we hold no record of is recovered by asking the remote
+// party to force close it, which pays our balance to our wallet. If DryRun is
+// set, then the backup is only verified, and the response reports exactly
+// which channels would be recovered and how.
+func (r *rpcServer) RestoreChanBackup(ctx context.Context,
+	in *lnrpc.RestoreChanBackupRequest) (*lnrpc.RestoreChanBackupResponse, error) {
+
+	// Restoring a channel asks the remote party to force close it, while a
+	// dry run leaves our channels untouched.
+	if !in.DryRun {
+		if err := r.quotas.authorize(ctx, permForceClose); err != nil {
+			return nil, err
+		}
+	}
+
+	reports, err := r.verifyChanBackup(in.MultiChanBackup, !in.DryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.RestoreChanBackupResponse{
+		Channels: reports,
+	}, nil
+}
+
+// verifyChanBackup decrypts the passed packed Multi and verifies it against
+// our live channel state, returning a report for each channel. If restore is
+// true, then the recovery of each channel we hold no record of is begun.
+func (r *rpcServer) verifyChanBackup(packedMulti []byte,
+	restore bool) ([]*lnrpc.ChannelBackupReport, error) {
+
+	var multi chanbackup.Multi
+	backupKey := chanbackup.DeriveBackupKey(r.server.identityPriv)
+	err := multi.UnpackFromReader(bytes.NewReader(packedMulti), backupKey)
+	if err != nil {
+		return nil, err
+	}
+
+	reports, err := chanbackup.VerifyMulti(&multi, r.server.chanDB)
+	if err != nil {
+		return nil, err
+	}
+
+	if restore {
+		for _, report := range reports {
+			if report.Status != chanbackup.StatusUnknown {
+				continue
+			}
+
+			err := r.server.chanRecovery.recoverChannel(report.Backup)
+			if err != nil {
+				return nil, err
+			}
+
+			rpcsLog.Infof("[restorechanbackup] recovering "+
+				"ChannelPoint(%v)", report.Backup.ChanPoint)
+		}
+	}
+
+	rpcReports := make([]*lnrpc.ChannelBackupReport, len(reports))
+	for i, report := range reports {
+		remotePub := report.Backup.RemoteNodePub.SerializeCompressed()
+		rpcReports[i] = &lnrpc.ChannelBackupReport{
+			ChannelPoint:   report.Backup.ChanPoint.String(),
+			RemotePubkey:   hex.EncodeToString(remotePub),
+			Capacity:       int64(report.Backup.Capacity),
+			Status:         report.Status.String(),
+			Problem:        report.Problem,
+			RecoveryAction: report.RecoveryAction(),
+			ChannelType:    report.Backup.Version.String(),
+		}
+	}
+
+	return rpcReports, nil
+}
+
+// BackupUploadStatus reports the uploads of the channel backup file to each
+// of the configured upload targets, including whether each target holds the
+// latest backup.
+func (r *rpcServer) BackupUploadStatus(ctx context.Context,
+	in *lnrpc.BackupUploadStatusRequest) (*lnrpc.BackupUploadStatusResponse, error) {
+
+	statuses := r.server.chanBackup.uploadStatus()
+
+	resp := &lnrpc.BackupUploadStatusResponse{
+		Targets: make([]*lnrpc.BackupUploadTarget, 0, len(statuses)),
+	}
+	for _, status := range statuses {
+		target := &lnrpc.BackupUploadTarget{
+			Target:    status.target,
+			Successes: status.successes,
+			Failures:  status.failures,
+			UpToDate:  status.upToDate,
+		}
+		if !status.lastSuccess.IsZero() {
+			target.LastSuccess = status.lastSuccess.Unix()
+		}
+		if status.lastErr != nil {
+			target.LastError = status.lastErr.Error()
+		}
+
+		resp.Targets = append(resp.Targets, target)
+	}
+
+	return resp, nil
+}
+
+// ImportChannelRecovery reconstructs channels from recovery data exported by
+// another Lightning implementation. Each recovered channel may only be
+// cooperatively closed with the remote party, which is attempted to be
+// reached at the address within the recovery data.
+func (r *rpcServer) ImportChannelRecovery(ctx context.Context,
+	in *lnrpc.ImportChannelRecoveryRequest) (*lnrpc.ImportChannelRecoveryResponse, error) {
+
+	if err := r.quotas.authorize(ctx, permCoopClose); err != nil {
+		return nil, err
+	}
+
+	externalChans, err := chanbackup.ParseExternalChannels(in.RecoveryJson)
+	if err != nil {
+		return nil, err
+	}
+
+	existingChans, err := r.server.chanDB.FetchAllChannels()
+	if err != nil {
+		return nil, err
+	}
+	isKnown := func(chanPoint *wire.OutPoint) (bool, error) {
+		for _, channel := range existingChans {
+			if *channel.ChanID == *chanPoint {
+				return true, nil
+			}
+		}
+
+		return r.server.chanDB.IsChannelClosed(chanPoint)
+	}
+
+	chanPoints := make([]string, 0, len(externalChans))
+	for i, externalChan := range externalChans {
+		// Each recovered channel is closed to a fresh address within
+		// our wallet, or to the sweep whitelist if one is configured,
+		// unless the recovery data specifies otherwise.
+		addr := cfg.sweepWhitelist.destination()
+		if addr == nil {
+			addr, err = r.server.lnwallet.NewAddress(
+				lnwallet.WitnessPubKey, false)
+			if err != nil {
+				return nil, err
+			}
+		}
+		deliveryScript, err := txscript.PayToAddrScript(addr)
+		if err != nil {
+			return nil, err
+		}
+
+		recovered, err := externalChan.Recover(r.server.chanDB,
+			activeNetParams.Params, deliveryScript)
+		if err != nil {
+			return nil, fmt.Errorf("channel #%v: %v", i, err)
+		}
+		channel := recovered.Channel
+
+		known, err := isKnown(channel.ChanID)
+		if err != nil {
+			return nil, err
+		}
+		if known {
+			rpcsLog.Infof("[importchannelrecovery] skipping known "+
+				"ChannelPoint(%v)", channel.ChanID)
+			continue
+		}
+
+		// The funding key must be imported into the wallet so we're
+		// able to sign the closure transaction.
+		if err := r.server.lnwallet.ImportPrivateKey(recovered.FundingKey); err != nil {
+			return nil, err
+		}
+
+		if err := channel.SyncPending(recovered.RemoteAddr); err != nil {
+			return nil, err
+		}
+		if err := r.server.chanDB.MarkChannelAsOpen(channel.ChanID); err != nil {
+			return nil, err
+		}
+		r.server.chanBackup.requestUpdate()
+
+		rpcsLog.Infof("[importchannelrecovery] recovered "+
+			"ChannelPoint(%v) with peer %x", channel.ChanID,
+			channel.IdentityPub.SerializeCompressed())
+
+		chanPoints = append(chanPoints, channel.ChanID.String())
+
+		// If we're already connected to the remote peer, then we hand
+		// it the recovered channel directly. Otherwise, we connect to
+		// the peer, which loads the channel from the database.
+		peer, err := r.server.findPeer(channel.IdentityPub)
+		if err != nil {
+			lnAddr := &lnwire.NetAddress{
+				IdentityKey: channel.IdentityPub,
+				Address:     recovered.RemoteAddr,
+			}
+			if err := r.server.ConnectToPeer(lnAddr, true); err != nil {
+				rpcsLog.Errorf("[importchannelrecovery] unable "+
+					"to connect to %v: %v", lnAddr, err)
+			}
+			continue
+		}
+
+		lnChan, err := lnwallet.NewLightningChannel(
+			r.server.lnwallet.Signer, r.server.chainNotifier, channel)
+		if err != nil {
+			return nil, err
+		}
+		newChanDone := make(chan struct{})
+		peer.newChannels <- &newChannelMsg{
+			channel: lnChan,
+			done:    newChanDone,
+		}
+		select {
+		case <-newChanDone:
+		case <-r.quit:
+			return nil, fmt.Errorf("rpc server shutting down")
+		}
+	}
+
+	return &lnrpc.ImportChannelRecoveryResponse{
+		ChannelPoints: chanPoints,
+	}, nil
+}
+
 // savePayment saves a successfully completed payment to the database for
 // historical record keeping.
 func (r *rpcServer) savePayment(route *routing.Route, amount btcutil.Amount,
@@ -883,7 +1631,28 @@ func (r *rpcServer) savePayment(route *routing.Route, amount btcutil.Amount,
 	}
 	copy(payment.PaymentHash[:], rHash)
 
-	return r.server.chanDB.AddPayment(payment)
+	if err := r.server.chanDB.AddPayment(payment); err != nil {
+		return err
+	}
+
+	// If the analytics store is enabled, then we'll also mirror the
+	// payment into it. As the store is only used for reporting, a failure
+	// here isn't fatal.
+	if r.server.analytics != nil && len(route.Hops) != 0 {
+		record := &sqlstore.Payment{
+			PaymentHash:  payment.PaymentHash,
+			Destination:  paymentPath[len(paymentPath)-1][:],
+			Value:        amount,
+			Fee:          route.TotalFees,
+			CreationDate: payment.CreationDate,
+		}
+		if err := r.server.analytics.AddPayment(record); err != nil {
+			rpcsLog.Errorf("unable to record payment in analytics "+
+				"store: %v", err)
+		}
+	}
+
+	return nil
 }
 
 // SendPayment dispatches a bi-directional streaming RPC for sending payments
@@ -948,12 +1717,21 @@ func (r *rpcServer) SendPayment(paymentStream lnrpc.Lightning_SendPaymentServer)
 		case nextPayment := <-payChan:
 			// Parse the details of the payment which include the
 			// pubkey of the destination and the payment amount.
-			dest := nextPayment.Dest
+			// The destination may also be referred to by its
+			// public key or alias as a string.
+			var (
+				destNode *btcec.PublicKey
+				err      error
+			)
 			amt := btcutil.Amount(nextPayment.Amt)
-			destNode, err := btcec.ParsePubKey(dest, btcec.S256())
+			if len(nextPayment.Dest) == 0 && nextPayment.DestString != "" {
+				destNode, err = r.resolveNode(nextPayment.DestString)
+			} else {
+				destNode, err = btcec.ParsePubKey(nextPayment.Dest,
+					btcec.S256())
+			}
 			if err != nil {
 				return err
-
 			}
 
 			// If we're in debug HTLC mode, then all outgoing HTLCs
@@ -966,6 +1744,40 @@ func (r *rpcServer) SendPayment(paymentStream lnrpc.Lightning_SendPaymentServer)
 				copy(rHash[:], nextPayment.PaymentHash)
 			}
 
+			preset, err := lookupFeePreset(nextPayment.FeePreset)
+			if err != nil {
+				return err
+			}
+
+			// If the client identified the payment, then a replay
+			// of it is detected before it's dispatched.
+			paymentID := nextPayment.PaymentId
+			if paymentID != 0 {
+				resp, err := r.claimPaymentID(paymentID, rHash)
+				if err != nil {
+					return err
+				}
+				if resp != nil {
+					if err := paymentStream.Send(resp); err != nil {
+						return err
+					}
+					continue
+				}
+			}
+
+			// The payment is counted against the quota of the
+			// credential the stream was opened with before it's
+			// dispatched.
+			paymentDone, err := r.quotas.authorizePayment(
+				paymentStream.Context(), amt)
+			if err != nil {
+				if paymentID != 0 {
+					r.resolvePaymentID(paymentID, rHash,
+						[32]byte{}, err)
+				}
+				return err
+			}
+
 			// We launch a new goroutine to execute the current
 			// payment so we can continue to serve requests while
 			// this payment is being dispatched.
@@ -982,12 +1794,22 @@ func (r *rpcServer) SendPayment(paymentStream lnrpc.Lightning_SendPaymentServer)
 					Target:      destNode,
 					Amount:      amt,
 					PaymentHash: rHash,
+					RetryPolicy: cfg.PaymentRetry.policy,
+				}
+				if preset != nil {
+					preset.applyTo(payment)
 				}
 				preImage, route, err := r.server.chanRouter.SendPayment(payment)
+				if paymentID != 0 {
+					r.resolvePaymentID(paymentID, rHash,
+						preImage, err)
+				}
 				if err != nil {
+					paymentDone(0)
 					errChan <- err
 					return
 				}
+				paymentDone(route.TotalAmount)
 
 				// Save the completed payment to the database
 				// for record keeping purposes.
@@ -1050,11 +1872,8 @@ func (r *rpcServer) SendPaymentSync(ctx context.Context,
 			copy(rHash[:], paymentHash)
 		}
 
-		pubBytes, err := hex.DecodeString(nextPayment.DestString)
-		if err != nil {
-			return nil, err
-		}
-		destPub, err = btcec.ParsePubKey(pubBytes, btcec.S256())
+		var err error
+		destPub, err = r.resolveNode(nextPayment.DestString)
 		if err != nil {
 			return nil, err
 		}
@@ -1062,17 +1881,55 @@ func (r *rpcServer) SendPaymentSync(ctx context.Context,
 		amt = btcutil.Amount(nextPayment.Amt)
 	}
 
-	// Finally, send a payment request to the channel router. If the
-	// payment succeeds, then the returned route will be that was used
-	// successfully within the payment.
-	preImage, route, err := r.server.chanRouter.SendPayment(&routing.LightningPayment{
+	payment := &routing.LightningPayment{
 		Target:      destPub,
 		Amount:      amt,
 		PaymentHash: rHash,
-	})
+		RetryPolicy: cfg.PaymentRetry.policy,
+	}
+
+	// If the client selected a fee preset, then its limits are applied to
+	// the payment.
+	preset, err := lookupFeePreset(nextPayment.FeePreset)
+	if err != nil {
+		return nil, err
+	}
+	if preset != nil {
+		preset.applyTo(payment)
+	}
+
+	// If the client identified the payment, then a replay of it is
+	// detected before it's dispatched.
+	paymentID := nextPayment.PaymentId
+	if paymentID != 0 {
+		resp, err := r.claimPaymentID(paymentID, rHash)
+		if err != nil || resp != nil {
+			return resp, err
+		}
+	}
+
+	// The payment is counted against the quota of the credential the call
+	// was made with before it's dispatched.
+	paymentDone, err := r.quotas.authorizePayment(ctx, amt)
+	if err != nil {
+		if paymentID != 0 {
+			r.resolvePaymentID(paymentID, rHash, [32]byte{}, err)
+		}
+		return nil, err
+	}
+
+	// Finally, send a payment request to the channel router. If the
+	// payment succeeds, then the returned route will be that was used
+	// successfully within the payment.
+	preImage, route, err := r.server.chanRouter.SendPayment(payment)
+	if paymentID != 0 {
+		r.resolvePaymentID(paymentID, rHash, preImage, err)
+	}
 	if err != nil {
+		paymentDone(0)
 		return nil, err
 	}
+	paymentDone(route.TotalAmount)
 
 	// With the payment completed successfully, we now ave the details of
 	// the completed payment to the databse for historical record keeping.
@@ -1086,86 +1943,357 @@ func (r *rpcServer) SendPaymentSync(ctx context.Context,
 	}, nil
 }
 
-// AddInvoice attempts to add a new invoice to the invoice database. Any
-// duplicated invoices are rejected, therefore all invoices *must* have a
-// unique payment preimage.
-func (r *rpcServer) AddInvoice(ctx context.Context,
-	invoice *lnrpc.Invoice) (*lnrpc.AddInvoiceResponse, error) {
+// SendBatchPayment pays each of the passed payment requests, streaming the
+// result of each payment to the client as it completes. The payments share a
+// total fee budget, and are limited to a common time lock. At most
+// max_concurrency payments are in flight at once, so large batches don't
+// exhaust the HTLC slots of our channels.
+func (r *rpcServer) SendBatchPayment(in *lnrpc.BatchPaymentRequest,
+	updateStream lnrpc.Lightning_SendBatchPaymentServer) error {
+
+	if len(in.PaymentRequests) == 0 {
+		return fmt.Errorf("at least one payment request must be " +
+			"specified")
+	}
+	if in.FeeBudget < 0 {
+		return fmt.Errorf("fee budget must not be negative")
+	}
 
-	var paymentPreimage [32]byte
+	preset, err := lookupFeePreset(in.FeePreset)
+	if err != nil {
+		return err
+	}
 
-	switch {
-	// If a preimage wasn't specified, then we'll generate a new preimage
-	// from fresh cryptographic randomness.
-	case len(invoice.RPreimage) == 0:
-		if _, err := rand.Read(paymentPreimage[:]); err != nil {
-			return nil, err
+	// All payment requests are decoded before any payment is dispatched,
+	// so a malformed batch is rejected as a whole.
+	payReqs := make([]*zpay32.PaymentRequest, len(in.PaymentRequests))
+	payHashes := make(map[[32]byte]struct{})
+	for i, encoded := range in.PaymentRequests {
+		payReq, err := zpay32.Decode(encoded)
+		if err != nil {
+			return fmt.Errorf("unable to decode payment request "+
+				"%v: %v", i, err)
 		}
+		if payReq.Amount <= 0 {
+			return fmt.Errorf("payment request %v doesn't specify "+
+				"an amount", i)
+		}
+		if _, ok := payHashes[payReq.PaymentHash]; ok {
+			return fmt.Errorf("payment request %v duplicates the "+
+				"payment hash %x", i, payReq.PaymentHash[:])
+		}
+		payHashes[payReq.PaymentHash] = struct{}{}
 
-	// Otherwise, if a preimage was specified, then it MUST be exactly
-	// 32-bytes.
-	case len(invoice.RPreimage) > 0 && len(invoice.RPreimage) != 32:
-		return nil, fmt.Errorf("payment preimage must be exactly "+
-			"32 bytes, is instead %v", len(invoice.RPreimage))
-
-	// If the preimage meets the size specifications, then it can be used
-	// as is.
-	default:
-		copy(paymentPreimage[:], invoice.RPreimage[:])
+		payReqs[i] = payReq
 	}
 
-	// The size of the memo and receipt attached must not exceed the
-	// maximum values for either of the fields.
-	if len(invoice.Memo) > channeldb.MaxMemoSize {
-		return nil, fmt.Errorf("memo too large: %v bytes "+
-			"(maxsize=%v)", len(invoice.Memo), channeldb.MaxMemoSize)
-	}
-	if len(invoice.Receipt) > channeldb.MaxReceiptSize {
-		return nil, fmt.Errorf("receipt too large: %v bytes "+
-			"(maxsize=%v)", len(invoice.Receipt), channeldb.MaxReceiptSize)
+	var budget *feeBudget
+	if in.FeeBudget != 0 {
+		budget = newFeeBudget(btcutil.Amount(in.FeeBudget),
+			len(payReqs))
 	}
 
-	// Finally, the value of an invoice MUST NOT be zero.
-	if invoice.Value == 0 {
-		return nil, fmt.Errorf("zero value invoices are disallowed")
-	}
+	rpcsLog.Infof("[sendbatchpayment] dispatching %v payments, "+
+		"fee_budget=%v, cltv_limit=%v", len(payReqs),
+		btcutil.Amount(in.FeeBudget), in.CltvLimit)
 
-	i := &channeldb.Invoice{
-		CreationDate: time.Now(),
-		Memo:         []byte(invoice.Memo),
-		Receipt:      invoice.Receipt,
-		Terms: channeldb.ContractTerm{
-			Value: btcutil.Amount(invoice.Value),
-		},
-	}
-	copy(i.Terms.PaymentPreimage[:], paymentPreimage[:])
+	// Each payment is dispatched within its own goroutine once a slot
+	// within the concurrency limit is available. If the client goes away,
+	// then no further payments are dispatched.
+	ctx := updateStream.Context()
+	results := make(chan *lnrpc.BatchPaymentResult)
+	go func() {
+		slots := make(chan struct{}, batchConcurrency(in.MaxConcurrency))
 
-	rpcsLog.Tracef("[addinvoice] adding new invoice %v",
-		newLogClosure(func() string {
-			return spew.Sdump(i)
-		}))
+		var wg sync.WaitGroup
+	dispatch:
+		for i, payReq := range payReqs {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				break dispatch
+			case <-r.quit:
+				break dispatch
+			}
 
-	// With all sanity checks passed, write the invoice to the database.
-	if err := r.server.invoices.AddInvoice(i); err != nil {
-		return nil, err
-	}
+			wg.Add(1)
+			go func(i int, payReq *zpay32.PaymentRequest) {
+				defer wg.Done()
 
-	// Next, generate the payment hash itself from the preimage. This will
-	// be used by clients to query for the state of a particular invoice.
-	rHash := sha256.Sum256(paymentPreimage[:])
+				results <- r.sendBatchedPayment(ctx, uint32(i),
+					in.PaymentRequests[i], payReq, preset,
+					budget, in.CltvLimit)
+				<-slots
+			}(i, payReq)
+		}
 
-	// Finally we also create an encoded payment request which allows the
-	// caller to comactly send the invoice to the payer.
-	payReqString := zpay32.Encode(&zpay32.PaymentRequest{
-		Destination: r.server.identityPriv.PubKey(),
+		wg.Wait()
+		close(results)
+	}()
+
+	for result := range results {
+		if err := updateStream.Send(result); err != nil {
+			// The payments in flight can't be cancelled, so
+			// their results are discarded once they complete.
+			go func() {
+				for range results {
+				}
+			}()
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sendBatchedPayment pays the passed payment request, which resides at the
+// passed index within its batch. The payment's fee limit is its share of the
+// batch's fee budget, if it has one.
+func (r *rpcServer) sendBatchedPayment(ctx context.Context, index uint32,
+	encoded string, payReq *zpay32.PaymentRequest, preset *feePreset,
+	budget *feeBudget, cltvLimit uint32) *lnrpc.BatchPaymentResult {
+
+	result := &lnrpc.BatchPaymentResult{
+		Index:          index,
+		PaymentRequest: encoded,
+		PaymentHash:    payReq.PaymentHash[:],
+	}
+
+	payment := &routing.LightningPayment{
+		Target:      payReq.Destination,
+		Amount:      payReq.Amount,
+		PaymentHash: payReq.PaymentHash,
+		RetryPolicy: cfg.PaymentRetry.policy,
+	}
+	if preset != nil {
+		preset.applyTo(payment)
+	}
+
+	feeShare, err := budget.reserve()
+	if err != nil {
+		result.PaymentError = err.Error()
+		return result
+	}
+	applyBatchLimits(payment, feeShare, cltvLimit)
+
+	// As with a single payment, each payment within the batch is counted
+	// against the quota of the credential the call was made with.
+	paymentDone, err := r.quotas.authorizePayment(ctx, payment.Amount)
+	if err != nil {
+		budget.release(feeShare, 0)
+		result.PaymentError = err.Error()
+		return result
+	}
+
+	preImage, route, err := r.server.chanRouter.SendPayment(payment)
+	if err != nil {
+		paymentDone(0)
+		budget.release(feeShare, 0)
+		result.PaymentError = err.Error()
+		return result
+	}
+	paymentDone(route.TotalAmount)
+	budget.release(feeShare, route.TotalFees)
+
+	// The payment has succeeded regardless of whether it's recorded, so
+	// a failure to save it is only logged.
+	err = r.savePayment(route, payment.Amount, payReq.PaymentHash[:])
+	if err != nil {
+		rpcsLog.Errorf("[sendbatchpayment] unable to save payment "+
+			"%x: %v", payReq.PaymentHash[:], err)
+	}
+
+	result.PaymentPreimage = preImage[:]
+	result.PaymentRoute = marshalRoute(route)
+	return result
+}
+
+// lookupFeePreset returns the fee preset with the passed name. If the name is
+// blank, then no preset was selected, and nil is returned.
+func lookupFeePreset(name string) (*feePreset, error) {
+	if name == "" {
+		return nil, nil
+	}
+
+	preset, ok := cfg.feePresets[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown fee preset %v", name)
+	}
+
+	return preset, nil
+}
+
+// ListFeePresets returns the fee presets which payments may select, along
+// with the limits each of them applies.
+func (r *rpcServer) ListFeePresets(ctx context.Context,
+	in *lnrpc.ListFeePresetsRequest) (*lnrpc.ListFeePresetsResponse, error) {
+
+	presets := sortedFeePresets(cfg.feePresets)
+	resp := &lnrpc.ListFeePresetsResponse{
+		Presets: make([]*lnrpc.FeePreset, 0, len(presets)),
+	}
+	for _, preset := range presets {
+		resp.Presets = append(resp.Presets, &lnrpc.FeePreset{
+			Name:           preset.name,
+			FeePpm:         preset.feeRate,
+			CltvLimit:      preset.cltvLimit,
+			MaxAttempts:    preset.maxAttempts,
+			TimeoutSeconds: int64(preset.timeout.Seconds()),
+		})
+	}
+
+	return resp, nil
+}
+
+// AddInvoice attempts to add a new invoice to the invoice database. Any
+// duplicated invoices are rejected, therefore all invoices *must* have a
+// unique payment preimage.
+func (r *rpcServer) AddInvoice(ctx context.Context,
+	invoice *lnrpc.Invoice) (*lnrpc.AddInvoiceResponse, error) {
+
+	var paymentPreimage [32]byte
+
+	switch {
+	// A preimage derived from the seed is only known once the invoice has
+	// been assigned its index, so it can't also be specified.
+	case invoice.DerivePreimage && len(invoice.RPreimage) != 0:
+		return nil, fmt.Errorf("payment preimage can't be specified " +
+			"if it's to be derived")
+
+	// If the preimage is to be derived, then it's set once the invoice
+	// has been added below.
+	case invoice.DerivePreimage:
+
+	// If a preimage wasn't specified, then we'll generate a new preimage
+	// from fresh cryptographic randomness.
+	case len(invoice.RPreimage) == 0:
+		if _, err := rand.Read(paymentPreimage[:]); err != nil {
+			return nil, err
+		}
+
+	// Otherwise, if a preimage was specified, then it MUST be exactly
+	// 32-bytes.
+	case len(invoice.RPreimage) > 0 && len(invoice.RPreimage) != 32:
+		return nil, fmt.Errorf("payment preimage must be exactly "+
+			"32 bytes, is instead %v", len(invoice.RPreimage))
+
+	// If the preimage meets the size specifications, then it can be used
+	// as is.
+	default:
+		copy(paymentPreimage[:], invoice.RPreimage[:])
+	}
+
+	// The size of the memo and receipt attached must not exceed the
+	// maximum values for either of the fields.
+	if len(invoice.Memo) > channeldb.MaxMemoSize {
+		return nil, fmt.Errorf("memo too large: %v bytes "+
+			"(maxsize=%v)", len(invoice.Memo), channeldb.MaxMemoSize)
+	}
+	if len(invoice.Receipt) > channeldb.MaxReceiptSize {
+		return nil, fmt.Errorf("receipt too large: %v bytes "+
+			"(maxsize=%v)", len(invoice.Receipt), channeldb.MaxReceiptSize)
+	}
+
+	// Finally, the value of an invoice MUST NOT be zero.
+	if invoice.Value == 0 {
+		return nil, fmt.Errorf("zero value invoices are disallowed")
+	}
+	if invoice.Expiry < 0 {
+		return nil, fmt.Errorf("expiry of %v seconds is negative",
+			invoice.Expiry)
+	}
+
+	// With the invoice validated, it's counted against the quota of the
+	// credential the call was made with.
+	if err := r.quotas.authorizeInvoice(ctx); err != nil {
+		return nil, err
+	}
+
+	i := &channeldb.Invoice{
+		CreationDate: time.Now(),
+		Memo:         []byte(invoice.Memo),
+		Receipt:      invoice.Receipt,
+		Terms: channeldb.ContractTerm{
+			Value: btcutil.Amount(invoice.Value),
+		},
+	}
+	copy(i.Terms.PaymentPreimage[:], paymentPreimage[:])
+
+	// If an expiry was requested, then the invoice can no longer be
+	// settled once it has passed.
+	if invoice.Expiry != 0 {
+		i.Expiry = i.CreationDate.Add(
+			time.Duration(invoice.Expiry) * time.Second)
+	}
+
+	rpcsLog.Tracef("[addinvoice] adding new invoice %v",
+		newLogClosure(func() string {
+			return spew.Sdump(i)
+		}))
+
+	// With all sanity checks passed, write the invoice to the database.
+	// If requested, the preimage is derived from the seed and the index
+	// of the invoice, so the invoice can still be settled by a node
+	// restored from its seed.
+	if invoice.DerivePreimage {
+		err := r.server.invoices.AddDerivedInvoice(i,
+			r.server.lnwallet.DeriveInvoicePreimage)
+		if err != nil {
+			return nil, err
+		}
+		paymentPreimage = i.Terms.PaymentPreimage
+	} else if err := r.server.invoices.AddInvoice(i); err != nil {
+		return nil, err
+	}
+
+	// Next, generate the payment hash itself from the preimage. This will
+	// be used by clients to query for the state of a particular invoice.
+	rHash := sha256.Sum256(paymentPreimage[:])
+
+	// Finally we also create an encoded payment request which allows the
+	// caller to comactly send the invoice to the payer. This encoding has
+	// no room for route hints, so payments over our unannounced channels
+	// need a signed payment request from CreatePayReq instead.
+	payReqString := zpay32.Encode(&zpay32.PaymentRequest{
+		Destination: r.server.identityPriv.PubKey(),
 		PaymentHash: rHash,
 		Amount:      btcutil.Amount(invoice.Value),
 	})
 
-	return &lnrpc.AddInvoiceResponse{
+	resp := &lnrpc.AddInvoiceResponse{
 		RHash:          rHash[:],
 		PaymentRequest: payReqString,
-	}, nil
+	}
+
+	// If requested, a fresh address is generated which the invoice may be
+	// paid to on-chain by wallets which don't support Lightning. A nested
+	// witness address is used, as it's accepted by the widest range of
+	// wallets.
+	var (
+		fallback btcutil.Address
+		err      error
+	)
+	if invoice.OnchainFallback {
+		fallback, err = r.server.lnwallet.NewAddress(
+			lnwallet.NestedWitnessPubKey, false)
+		if err != nil {
+			return nil, err
+		}
+		resp.FallbackAddr = fallback.String()
+	}
+
+	// The unified URI allows thin clients to present the invoice without
+	// an encoding stack of their own, optionally rendered as a QR code.
+	resp.UnifiedUri = unifiedInvoiceURI(payReqString, fallback,
+		btcutil.Amount(invoice.Value), invoice.Memo)
+	if invoice.QrCode {
+		resp.QrPng, err = invoiceQRCode(resp.UnifiedUri)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return resp, nil
 }
 
 // LookupInvoice attemps to look up an invoice according to its payment hash.
@@ -1223,9 +2351,22 @@ func (r *rpcServer) LookupInvoice(ctx context.Context,
 			PaymentHash: sha256.Sum256(preimage[:]),
 			Amount:      invoice.Terms.Value,
 		}),
+		DerivePreimage: invoice.DerivedPreimage,
+		AddIndex:       invoice.AddIndex,
+		Expiry:         invoiceExpiry(invoice),
 	}, nil
 }
 
+// invoiceExpiry returns the number of seconds after its creation at which the
+// passed invoice expires, or zero if it never does.
+func invoiceExpiry(invoice *channeldb.Invoice) int64 {
+	if invoice.Expiry.IsZero() {
+		return 0
+	}
+
+	return int64(invoice.Expiry.Sub(invoice.CreationDate) / time.Second)
+}
+
 // ListInvoices returns a list of all the invoices currently stored within the
 // database. Any active debug invoices are ignored.
 func (r *rpcServer) ListInvoices(ctx context.Context,
@@ -1252,6 +2393,9 @@ func (r *rpcServer) ListInvoices(ctx context.Context,
 				PaymentHash: sha256.Sum256(paymentPreimge),
 				Amount:      invoiceAmount,
 			}),
+			DerivePreimage: dbInvoice.DerivedPreimage,
+			AddIndex:       dbInvoice.AddIndex,
+			Expiry:         invoiceExpiry(dbInvoice),
 		}
 
 		invoices[i] = invoice
@@ -1262,6 +2406,104 @@ func (r *rpcServer) ListInvoices(ctx context.Context,
 	}, nil
 }
 
+// AddOffer creates a new reusable offer. The returned offer code can be
+// handed out to any number of payers, each of which uses it to request a fresh
+// invoice from this node.
+func (r *rpcServer) AddOffer(ctx context.Context,
+	in *lnrpc.Offer) (*lnrpc.AddOfferResponse, error) {
+
+	if len(in.Memo) > channeldb.MaxMemoSize {
+		return nil, fmt.Errorf("memo too large: %v bytes "+
+			"(maxsize=%v)", len(in.Memo), channeldb.MaxMemoSize)
+	}
+	if in.Value < 0 {
+		return nil, fmt.Errorf("offer value must not be negative")
+	}
+
+	offer, err := r.server.offers.AddOffer([]byte(in.Memo),
+		btcutil.Amount(in.Value))
+	if err != nil {
+		return nil, err
+	}
+
+	rpcsLog.Tracef("[addoffer] added new offer %x", offer.ID[:])
+
+	return &lnrpc.AddOfferResponse{
+		OfferId:   offer.ID[:],
+		OfferCode: r.encodeOffer(offer),
+	}, nil
+}
+
+// ListOffers returns a list of all the reusable offers currently stored within
+// the database.
+func (r *rpcServer) ListOffers(ctx context.Context,
+	req *lnrpc.ListOffersRequest) (*lnrpc.ListOffersResponse, error) {
+
+	dbOffers, err := r.server.chanDB.FetchAllOffers()
+	if err != nil {
+		return nil, err
+	}
+
+	offers := make([]*lnrpc.Offer, len(dbOffers))
+	for i, dbOffer := range dbOffers {
+		offers[i] = &lnrpc.Offer{
+			Memo:         string(dbOffer.Memo[:]),
+			Value:        int64(dbOffer.Amount),
+			OfferId:      dbOffer.ID[:],
+			CreationDate: dbOffer.CreationDate.Unix(),
+			NumInvoices:  dbOffer.NumInvoices,
+			OfferCode:    r.encodeOffer(dbOffer),
+		}
+	}
+
+	return &lnrpc.ListOffersResponse{
+		Offers: offers,
+	}, nil
+}
+
+// RequestOfferInvoice decodes the passed offer code, then asks the node which
+// created the offer for a fresh invoice. The returned payment request can then
+// be paid as usual. The amount is only consulted if the offer doesn't specify
+// one itself.
+func (r *rpcServer) RequestOfferInvoice(ctx context.Context,
+	in *lnrpc.OfferInvoiceRequest) (*lnrpc.OfferInvoiceResponse, error) {
+
+	offer, err := zpay32.DecodeOffer(in.OfferCode)
+	if err != nil {
+		return nil, err
+	}
+
+	if offer.Amount == 0 && in.Amt <= 0 {
+		return nil, fmt.Errorf("offer doesn't specify an amount, one " +
+			"must be provided")
+	}
+
+	rpcsLog.Debugf("[requestofferinvoice] requesting invoice for offer "+
+		"%x from %x", offer.OfferID[:],
+		offer.Destination.SerializeCompressed())
+
+	payReq, err := r.server.offers.RequestInvoice(offer,
+		btcutil.Amount(in.Amt))
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.OfferInvoiceResponse{
+		RHash:          payReq.PaymentHash[:],
+		PaymentRequest: zpay32.Encode(payReq),
+	}, nil
+}
+
+// encodeOffer returns the static offer code for the passed locally created
+// offer.
+func (r *rpcServer) encodeOffer(offer *channeldb.Offer) string {
+	return zpay32.EncodeOffer(&zpay32.Offer{
+		Destination: r.server.identityPriv.PubKey(),
+		OfferID:     offer.ID,
+		Amount:      offer.Amount,
+	})
+}
+
 // SubscribeInvoices returns a uni-directional stream (sever -> client) for
 // notifying the client of newly added/settled invoices.
 func (r *rpcServer) SubscribeInvoices(req *lnrpc.InvoiceSubscription,
@@ -1481,13 +2723,9 @@ func (r *rpcServer) GetNodeInfo(_ context.Context, in *lnrpc.NodeInfoRequest) (*
 
 	graph := r.server.chanDB.ChannelGraph()
 
-	// First, parse the hex-encoded public key into a full in-memory public
-	// key object we can work with for querying.
-	pubKeyBytes, err := hex.DecodeString(in.PubKey)
-	if err != nil {
-		return nil, err
-	}
-	pubKey, err := btcec.ParsePubKey(pubKeyBytes, btcec.S256())
+	// First, resolve the hex-encoded public key or alias into a full
+	// in-memory public key object we can work with for querying.
+	pubKey, err := r.resolveNode(in.PubKey)
 	if err != nil {
 		return nil, err
 	}
@@ -1538,22 +2776,18 @@ func (r *rpcServer) GetNodeInfo(_ context.Context, in *lnrpc.NodeInfoRequest) (*
 //
 // TODO(roasbeef): should return a slice of routes in reality
 //  * create separate PR to send based on well formatted route
-func (r *rpcServer) QueryRoute(_ context.Context, in *lnrpc.RouteRequest) (*lnrpc.Route, error) {
-	// First parse the hex-encdoed public key into a full public key objet
-	// we can properly manipulate.
-	pubKeyBytes, err := hex.DecodeString(in.PubKey)
-	if err != nil {
-		return nil, err
-	}
-	pubKey, err := btcec.ParsePubKey(pubKeyBytes, btcec.S256())
+func (r *rpcServer) QueryRoute(ctx context.Context, in *lnrpc.RouteRequest) (*lnrpc.Route, error) {
+	// First resolve the hex-encdoed public key or alias into a full public
+	// key objet we can properly manipulate.
+	pubKey, err := r.resolveNode(in.PubKey)
 	if err != nil {
 		return nil, err
 	}
 
 	// Query the channel router for a possible path to the destination that
 	// can carry `in.Amt` satoshis _including_ the total fee required on
-	// the route.
-	route, err := r.server.chanRouter.FindRoute(pubKey,
+	// the route. The search is abandoned if the client cancels the call.
+	route, err := r.server.chanRouter.FindRoute(ctx, pubKey,
 		btcutil.Amount(in.Amt))
 	if err != nil {
 		return nil, err
@@ -1823,68 +3057,1856 @@ func (r *rpcServer) ListPayments(context.Context,
 	return paymentsResp, nil
 }
 
-// DeleteAllPayments deletes all outgoing payments from DB.
-func (r *rpcServer) DeleteAllPayments(context.Context,
-	*lnrpc.DeleteAllPaymentsRequest) (*lnrpc.DeleteAllPaymentsResponse, error) {
+// ListPaymentAttempts returns each attempt made at sending the payment with
+// the passed payment hash, whether or not the payment ultimately succeeded.
+func (r *rpcServer) ListPaymentAttempts(ctx context.Context,
+	in *lnrpc.ListPaymentAttemptsRequest) (*lnrpc.ListPaymentAttemptsResponse, error) {
 
-	rpcsLog.Debugf("[DeleteAllPayments]")
+	rpcsLog.Debugf("[ListPaymentAttempts] payment_hash=%v", in.PaymentHash)
 
-	if err := r.server.chanDB.DeleteAllPayments(); err != nil {
+	hashBytes, err := hex.DecodeString(in.PaymentHash)
+	if err != nil {
 		return nil, err
 	}
+	if len(hashBytes) != 32 {
+		return nil, fmt.Errorf("payment hash must be exactly 32 "+
+			"bytes, is instead %v", len(hashBytes))
+	}
+	var paymentHash [32]byte
+	copy(paymentHash[:], hashBytes)
 
-	return &lnrpc.DeleteAllPaymentsResponse{}, nil
-}
+	attempts, err := r.server.chanDB.FetchPaymentAttempts(paymentHash)
+	if err != nil {
+		return nil, err
+	}
 
-// SetAlias...
-func (r *rpcServer) SetAlias(context.Context, *lnrpc.SetAliasRequest) (*lnrpc.SetAliasResponse, error) {
-	return nil, nil
+	resp := &lnrpc.ListPaymentAttemptsResponse{
+		Attempts: make([]*lnrpc.PaymentAttempt, len(attempts)),
+	}
+	for i, attempt := range attempts {
+		path := make([]string, len(attempt.Path))
+		for j, hop := range attempt.Path {
+			path[j] = hex.EncodeToString(hop[:])
+		}
+
+		resp.Attempts[i] = &lnrpc.PaymentAttempt{
+			AttemptTime: attempt.AttemptTime.Unix(),
+			FeeLimit:    int64(attempt.FeeLimit),
+			Path:        path,
+			Fee:         int64(attempt.Fee),
+			TimeLock:    attempt.TimeLock,
+			Error:       attempt.Error,
+		}
+	}
+
+	return resp, nil
 }
 
-// DebugLevel allows a caller to programmatically set the logging verbosity of
-// lnd. The logging can be targeted according to a coarse daemon-wide logging
-// level, or in a granular fashion to specify the logging for a target
-// sub-system.
-func (r *rpcServer) DebugLevel(ctx context.Context,
-	req *lnrpc.DebugLevelRequest) (*lnrpc.DebugLevelResponse, error) {
+// PaymentTelemetry reports the success rate, latency percentiles and most
+// common failure codes of the payments we've recently sent.
+func (r *rpcServer) PaymentTelemetry(ctx context.Context,
+	in *lnrpc.PaymentTelemetryRequest) (*lnrpc.PaymentTelemetryResponse, error) {
 
-	// If show is set, then we simply print out the list of available
-	// sub-systems.
-	if req.Show {
-		return &lnrpc.DebugLevelResponse{
-			SubSystems: strings.Join(supportedSubsystems(), " "),
-		}, nil
+	if r.server.paymentTelemetry == nil {
+		return nil, fmt.Errorf("payment telemetry is disabled, enable " +
+			"it with --paymenttelemetry")
 	}
 
-	rpcsLog.Infof("[debuglevel] changing debug level to: %v", req.LevelSpec)
+	return r.server.paymentTelemetry.report(), nil
+}
 
-	// Otherwise, we'll attempt to set the logging level using the
-	// specified level spec.
-	if err := parseAndSetDebugLevels(req.LevelSpec); err != nil {
+// TowerInfo returns the quotas of our watchtower, along with the sessions
+// and justice transactions it holds on behalf of its clients.
+func (r *rpcServer) TowerInfo(ctx context.Context,
+	in *lnrpc.TowerInfoRequest) (*lnrpc.TowerInfoResponse, error) {
+
+	stats, err := r.server.chanDB.FetchTowerStats()
+	if err != nil {
 		return nil, err
 	}
 
-	return &lnrpc.DebugLevelResponse{}, nil
+	return &lnrpc.TowerInfoResponse{
+		Active:              cfg.Watchtower.Active,
+		NumSessions:         stats.NumSessions,
+		NumBlobs:            stats.NumBlobs,
+		StorageUsed:         stats.StorageUsed,
+		MaxStorage:          cfg.Watchtower.MaxStorage,
+		RewardRate:          cfg.Watchtower.RewardRate,
+		MaxStoragePerClient: cfg.Watchtower.MaxStoragePerClient,
+	}, nil
 }
 
-// DecodePayReq takes an encoded payment request string and attempts to decode
-// it, returning a full description of the conditions encoded within the
-// payment request.
-func (r *rpcServer) DecodePayReq(ctx context.Context,
-	req *lnrpc.PayReqString) (*lnrpc.PayReq, error) {
+// defaultTopCounterparties is the number of counterparties returned by
+// TopCounterparties if the request doesn't specify a limit.
+const defaultTopCounterparties = 10
 
-	// Fist we'll attempt to decode the payment request string, if the
-	// request is invalid or the checksum doesn't match, then we'll exit
-	// here with an error.
-	payReq, err := zpay32.Decode(req.PayReq)
+// RevenueReport returns the revenue earned from settled invoices and
+// forwarding fees within the requested range, grouped by UTC day, as
+// mirrored into the analytics store.
+func (r *rpcServer) RevenueReport(ctx context.Context,
+	in *lnrpc.RevenueReportRequest) (*lnrpc.RevenueReportResponse, error) {
+
+	if r.server.analytics == nil {
+		return nil, fmt.Errorf("analytics store is disabled, enable " +
+			"it with --analyticsdb")
+	}
+
+	end := time.Now()
+	if in.EndTime != 0 {
+		end = time.Unix(in.EndTime, 0)
+	}
+	revenue, err := r.server.analytics.RevenueByDay(
+		time.Unix(in.StartTime, 0), end)
 	if err != nil {
 		return nil, err
 	}
 
-	dest := payReq.Destination.SerializeCompressed()
-	return &lnrpc.PayReq{
-		Destination: hex.EncodeToString(dest),
-		PaymentHash: hex.EncodeToString(payReq.PaymentHash[:]),
-		NumSatoshis: int64(payReq.Amount),
+	resp := &lnrpc.RevenueReportResponse{
+		Days: make([]*lnrpc.DailyRevenue, 0, len(revenue)),
+	}
+	for _, day := range revenue {
+		resp.Days = append(resp.Days, &lnrpc.DailyRevenue{
+			Day:            day.Day.Unix(),
+			InvoiceRevenue: int64(day.InvoiceRevenue),
+			ForwardingFees: int64(day.ForwardingFees),
+			NumForwards:    day.NumForwards,
+		})
+	}
+
+	return resp, nil
+}
+
+// TopCounterparties returns the counterparties we've exchanged the most
+// volume with, through both outgoing payments and forwarded HTLCs, as
+// mirrored into the analytics store.
+func (r *rpcServer) TopCounterparties(ctx context.Context,
+	in *lnrpc.TopCounterpartiesRequest) (*lnrpc.TopCounterpartiesResponse, error) {
+
+	if r.server.analytics == nil {
+		return nil, fmt.Errorf("analytics store is disabled, enable " +
+			"it with --analyticsdb")
+	}
+
+	limit := int(in.Limit)
+	if limit == 0 {
+		limit = defaultTopCounterparties
+	}
+	counterparties, err := r.server.analytics.TopCounterparties(limit)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &lnrpc.TopCounterpartiesResponse{
+		Counterparties: make([]*lnrpc.CounterpartyVolume, 0,
+			len(counterparties)),
+	}
+	for _, c := range counterparties {
+		resp.Counterparties = append(resp.Counterparties,
+			&lnrpc.CounterpartyVolume{
+				PubKey: hex.EncodeToString(c.PubKey),
+				Volume: int64(c.Volume),
+				Count:  c.Count,
+			})
+	}
+
+	return resp, nil
+}
+
+// DeleteAllPayments deletes all outgoing payments from DB, returning the
+// number of payments deleted. If a dry run is requested, then the payments
+// which would be deleted are only counted.
+func (r *rpcServer) DeleteAllPayments(ctx context.Context,
+	in *lnrpc.DeleteAllPaymentsRequest) (*lnrpc.DeleteAllPaymentsResponse, error) {
+
+	rpcsLog.Debugf("[DeleteAllPayments] dry_run=%v", in.DryRun)
+
+	payments, err := r.server.chanDB.FetchAllPayments()
+	if err != nil {
+		return nil, err
+	}
+	resp := &lnrpc.DeleteAllPaymentsResponse{
+		NumPayments: uint64(len(payments)),
+	}
+	if in.DryRun {
+		return resp, nil
+	}
+
+	if err := r.server.chanDB.DeleteAllPayments(); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// SetAlias...
+func (r *rpcServer) SetAlias(context.Context, *lnrpc.SetAliasRequest) (*lnrpc.SetAliasResponse, error) {
+	return nil, nil
+}
+
+// DebugLevel allows a caller to programmatically set the logging verbosity of
+// lnd. The logging can be targeted according to a coarse daemon-wide logging
+// level, or in a granular fashion to specify the logging for a target
+// sub-system.
+func (r *rpcServer) DebugLevel(ctx context.Context,
+	req *lnrpc.DebugLevelRequest) (*lnrpc.DebugLevelResponse, error) {
+
+	// If show is set, then we simply print out the list of available
+	// sub-systems.
+	if req.Show {
+		return &lnrpc.DebugLevelResponse{
+			SubSystems: strings.Join(supportedSubsystems(), " "),
+		}, nil
+	}
+
+	rpcsLog.Infof("[debuglevel] changing debug level to: %v", req.LevelSpec)
+
+	// Otherwise, we'll attempt to set the logging level using the
+	// specified level spec.
+	if err := parseAndSetDebugLevels(req.LevelSpec); err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.DebugLevelResponse{}, nil
+}
+
+// DBCommitStats returns the latency of the database's write transactions by
+// the call site which made them, ordered by their total latency, highest
+// first. This serves to find the write paths which would benefit from being
+// batched.
+func (r *rpcServer) DBCommitStats(ctx context.Context,
+	in *lnrpc.DBCommitStatsRequest) (*lnrpc.DBCommitStatsResponse, error) {
+
+	stats := r.server.chanDB.CommitStats()
+
+	resp := &lnrpc.DBCommitStatsResponse{
+		Stats: make([]*lnrpc.DBCommitStat, 0, len(stats)),
+	}
+	for _, stat := range stats {
+		resp.Stats = append(resp.Stats, &lnrpc.DBCommitStat{
+			CallSite:       stat.CallSite,
+			Batched:        stat.Batched,
+			NumCommits:     stat.NumCommits,
+			TotalLatencyUs: uint64(stat.TotalLatency / time.Microsecond),
+			MaxLatencyUs:   uint64(stat.MaxLatency / time.Microsecond),
+		})
+	}
+
+	return resp, nil
+}
+
+// ExportChannelState returns a redacted JSON document describing the complete
+// state of the target channel, suitable for attaching to bug reports.
+func (r *rpcServer) ExportChannelState(ctx context.Context,
+	in *lnrpc.ChannelPoint) (*lnrpc.ChannelStateExport, error) {
+
+	txid, err := chainhash.NewHash(in.FundingTxid)
+	if err != nil {
+		return nil, err
+	}
+	chanPoint := wire.NewOutPoint(txid, in.OutputIndex)
+
+	rpcsLog.Debugf("[exportchannelstate] exporting state of "+
+		"ChannelPoint(%v)", chanPoint)
+
+	dbChannels, err := r.server.chanDB.FetchAllChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, dbChannel := range dbChannels {
+		if *dbChannel.ChanID != *chanPoint {
+			continue
+		}
+
+		dump, err := dbChannel.Dump()
+		if err != nil {
+			return nil, err
+		}
+		stateJSON, err := json.MarshalIndent(dump, "", "\t")
+		if err != nil {
+			return nil, err
+		}
+
+		return &lnrpc.ChannelStateExport{
+			StateJson: string(stateJSON),
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unable to find channel %v", chanPoint)
+}
+
+// ImportChannelState reconstructs a channel from a document returned by
+// ExportChannelState, so an issue reported against it may be reproduced. The
+// secrets omitted from the export are regenerated, so the imported channel
+// can't be used on a live network, and the import is only available in
+// simulation mode.
+func (r *rpcServer) ImportChannelState(ctx context.Context,
+	in *lnrpc.ImportChannelStateRequest) (*lnrpc.ChannelPoint, error) {
+
+	if err := r.quotas.authorize(ctx, permOpenChannel); err != nil {
+		return nil, err
+	}
+
+	if r.server.virtualChain == nil {
+		return nil, errNotSimulation
+	}
+
+	dump := &channeldb.ChannelDump{}
+	if err := json.Unmarshal([]byte(in.StateJson), dump); err != nil {
+		return nil, fmt.Errorf("unable to parse channel state: %v", err)
+	}
+	addr, err := net.ResolveTCPAddr("tcp", in.PeerAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	// Importing over an existing channel would clobber its state, so the
+	// channel mustn't already be known.
+	dbChannels, err := r.server.chanDB.FetchAllChannels()
+	if err != nil {
+		return nil, err
+	}
+	for _, dbChannel := range dbChannels {
+		if dbChannel.ChanID.String() == dump.ChanPoint {
+			return nil, fmt.Errorf("channel %v already exists",
+				dump.ChanPoint)
+		}
+	}
+
+	channel, err := r.server.chanDB.ImportChannelDump(dump, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcsLog.Infof("[importchannelstate] imported ChannelPoint(%v) with "+
+		"peer %x", channel.ChanID,
+		channel.IdentityPub.SerializeCompressed())
+
+	return &lnrpc.ChannelPoint{
+		FundingTxid: channel.ChanID.Hash[:],
+		OutputIndex: channel.ChanID.Index,
+	}, nil
+}
+
+// ChannelHistory returns the timeline of notable events within the lifetime
+// of the target channel, optionally restricted to those which occurred within
+// a time range. As timelines are retained after a channel has been closed,
+// the history of closed channels may also be queried.
+func (r *rpcServer) ChannelHistory(ctx context.Context,
+	in *lnrpc.ChannelHistoryRequest) (*lnrpc.ChannelHistoryResponse, error) {
+
+	if in.ChannelPoint == nil {
+		return nil, fmt.Errorf("channel point must be specified")
+	}
+	txid, err := chainhash.NewHash(in.ChannelPoint.FundingTxid)
+	if err != nil {
+		return nil, err
+	}
+	chanPoint := wire.NewOutPoint(txid, in.ChannelPoint.OutputIndex)
+
+	// The range is specified in seconds, and is inclusive of the entire
+	// final second.
+	var start, end time.Time
+	if in.StartTime != 0 {
+		start = time.Unix(in.StartTime, 0)
+	}
+	if in.EndTime != 0 {
+		end = time.Unix(in.EndTime, int64(time.Second-1))
+	}
+	if !start.IsZero() && !end.IsZero() && end.Before(start) {
+		return nil, fmt.Errorf("end time must not be before start time")
+	}
+
+	rpcsLog.Debugf("[channelhistory] fetching timeline of "+
+		"ChannelPoint(%v)", chanPoint)
+
+	events, err := r.server.chanDB.FetchChannelEvents(chanPoint, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &lnrpc.ChannelHistoryResponse{
+		Events: make([]*lnrpc.ChannelEvent, 0, len(events)),
+	}
+	for _, event := range events {
+		resp.Events = append(resp.Events, &lnrpc.ChannelEvent{
+			Type:          event.Type.String(),
+			Timestamp:     event.Timestamp.Unix(),
+			LocalBalance:  int64(event.LocalBalance),
+			RemoteBalance: int64(event.RemoteBalance),
+			Detail:        event.Detail,
+		})
+	}
+
+	return resp, nil
+}
+
+// StateLog returns the transitions of the commitment state machine of a
+// channel, in the order they occurred. The log is retained after the channel
+// has been closed.
+func (r *rpcServer) StateLog(ctx context.Context,
+	in *lnrpc.StateLogRequest) (*lnrpc.StateLogResponse, error) {
+
+	if in.ChannelPoint == nil {
+		return nil, fmt.Errorf("channel point must be specified")
+	}
+	txid, err := chainhash.NewHash(in.ChannelPoint.FundingTxid)
+	if err != nil {
+		return nil, err
+	}
+	chanPoint := wire.NewOutPoint(txid, in.ChannelPoint.OutputIndex)
+
+	rpcsLog.Debugf("[statelog] fetching state log of ChannelPoint(%v)",
+		chanPoint)
+
+	events, err := r.server.chanDB.FetchStateLog(chanPoint, in.Limit)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &lnrpc.StateLogResponse{
+		Events: make([]*lnrpc.StateEvent, 0, len(events)),
+	}
+	for _, event := range events {
+		resp.Events = append(resp.Events, &lnrpc.StateEvent{
+			Type:         event.Type.String(),
+			TimestampNs:  event.Timestamp.UnixNano(),
+			LocalHeight:  event.LocalHeight,
+			RemoteHeight: event.RemoteHeight,
+			Detail:       event.Detail,
+		})
+	}
+
+	return resp, nil
+}
+
+// ChannelStates returns the status of the commitment state machine of each
+// active channel, optionally rendered as a graph in the DOT language, so a
+// channel which has stopped making progress may be diagnosed.
+func (r *rpcServer) ChannelStates(ctx context.Context,
+	in *lnrpc.ChannelStatesRequest) (*lnrpc.ChannelStatesResponse, error) {
+
+	rpcsLog.Debugf("[channelstates] dot=%v", in.Dot)
+
+	resp := &lnrpc.ChannelStatesResponse{}
+	for _, peer := range r.server.Peers() {
+		remotePub := hex.EncodeToString(
+			peer.addr.IdentityKey.SerializeCompressed())
+
+		peer.activeChanMtx.RLock()
+		channels := make([]*lnwallet.LightningChannel, 0,
+			len(peer.activeChannels))
+		for _, channel := range peer.activeChannels {
+			channels = append(channels, channel)
+		}
+		peer.activeChanMtx.RUnlock()
+
+		for _, channel := range channels {
+			resp.Channels = append(resp.Channels,
+				newChannelStateMachine(channel, remotePub))
+		}
+	}
+	sortChannelStateMachines(resp.Channels)
+
+	if in.Dot {
+		resp.Dot = channelStatesDOT(resp.Channels)
+	}
+
+	return resp, nil
+}
+
+// FreezeChannel freezes the target channel, so no new HTLCs are offered over
+// it, while those offered by the remote party are still accepted. The channel
+// remains frozen across restarts until thawed, allowing it to be drained
+// ahead of its closure.
+func (r *rpcServer) FreezeChannel(ctx context.Context,
+	in *lnrpc.ChannelPoint) (*lnrpc.FreezeChannelResponse, error) {
+
+	if err := r.quotas.authorize(ctx, permUpdatePolicy); err != nil {
+		return nil, err
+	}
+
+	chanPoint, err := r.setChannelOutgoingFrozen(in, true)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcsLog.Infof("[freezechannel] froze ChannelPoint(%v)", chanPoint)
+
+	return &lnrpc.FreezeChannelResponse{}, nil
+}
+
+// ThawChannel thaws the target channel, so HTLCs may once again be offered
+// over it.
+func (r *rpcServer) ThawChannel(ctx context.Context,
+	in *lnrpc.ChannelPoint) (*lnrpc.ThawChannelResponse, error) {
+
+	if err := r.quotas.authorize(ctx, permUpdatePolicy); err != nil {
+		return nil, err
+	}
+
+	chanPoint, err := r.setChannelOutgoingFrozen(in, false)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcsLog.Infof("[thawchannel] thawed ChannelPoint(%v)", chanPoint)
+
+	return &lnrpc.ThawChannelResponse{}, nil
+}
+
+// setChannelOutgoingFrozen freezes or thaws the target channel, returning its
+// channel point. If the channel is active, then the flag is set through the
+// state machine held by the peer, so it takes effect immediately. Otherwise,
+// it's only persisted, and takes effect once the channel is next loaded.
+func (r *rpcServer) setChannelOutgoingFrozen(in *lnrpc.ChannelPoint,
+	frozen bool) (*wire.OutPoint, error) {
+
+	txid, err := chainhash.NewHash(in.FundingTxid)
+	if err != nil {
+		return nil, err
+	}
+	chanPoint := wire.NewOutPoint(txid, in.OutputIndex)
+
+	dbChannels, err := r.server.chanDB.FetchAllChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	var dbChan *channeldb.OpenChannel
+	for _, dbChannel := range dbChannels {
+		if *dbChannel.ChanID == *chanPoint {
+			dbChan = dbChannel
+			break
+		}
+	}
+	if dbChan == nil {
+		return nil, fmt.Errorf("unable to find channel %v", chanPoint)
+	}
+
+	if peer, err := r.server.findPeer(dbChan.IdentityPub); err == nil {
+		peer.activeChanMtx.RLock()
+		channel, ok := peer.activeChannels[*chanPoint]
+		peer.activeChanMtx.RUnlock()
+		if ok {
+			return chanPoint, channel.SetOutgoingFrozen(frozen)
+		}
+	}
+
+	return chanPoint, dbChan.SetOutgoingFrozen(frozen)
+}
+
+// UpdatePeerSettings sets the settings of a peer, replacing any settings
+// previously set. If a fee policy is set, then it's also applied to the
+// channels we already maintain with the peer.
+func (r *rpcServer) UpdatePeerSettings(ctx context.Context,
+	in *lnrpc.PeerSettings) (*lnrpc.UpdatePeerSettingsResponse, error) {
+
+	peer, settings, err := unmarshalPeerSettings(in)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcsLog.Debugf("[updatepeersettings] peer=%x, settings=%+v",
+		peer.SerializeCompressed(), *settings)
+
+	if err := r.server.peerSettings.set(peer, settings); err != nil {
+		return nil, err
+	}
+
+	if in.FeePolicy == nil {
+		return &lnrpc.UpdatePeerSettingsResponse{}, nil
+	}
+
+	current, err := selfPolicies(r.server.chanDB.ChannelGraph())
+	if err != nil {
+		return nil, err
+	}
+	channels, err := r.server.chanDB.FetchOpenChannels(peer)
+	if err != nil {
+		return nil, err
+	}
+	updates, err := peerFeePolicyUpdates(current, channels, in.FeePolicy)
+	if err != nil {
+		return nil, err
+	}
+	if len(updates) != 0 {
+		rpcsLog.Infof("[updatepeersettings] updating policies of %v "+
+			"channels", len(updates))
+
+		err := r.server.chanRouter.UpdateSelfPolicies(updates)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &lnrpc.UpdatePeerSettingsResponse{}, nil
+}
+
+// ListPeerSettings returns the settings of each peer which has been set.
+func (r *rpcServer) ListPeerSettings(ctx context.Context,
+	in *lnrpc.ListPeerSettingsRequest) (*lnrpc.ListPeerSettingsResponse, error) {
+
+	allSettings := r.server.peerSettings.all()
+
+	resp := &lnrpc.ListPeerSettingsResponse{
+		Peers: make([]*lnrpc.PeerSettings, 0, len(allSettings)),
+	}
+	for peer, settings := range allSettings {
+		resp.Peers = append(resp.Peers,
+			marshalPeerSettings(peer, settings))
+	}
+	sort.Sort(peerSettingsByPubKey(resp.Peers))
+
+	return resp, nil
+}
+
+// DeletePeerSettings deletes the settings of a peer, so it's treated like any
+// other peer. A fee policy applied to the peer's existing channels is left in
+// place.
+func (r *rpcServer) DeletePeerSettings(ctx context.Context,
+	in *lnrpc.DeletePeerSettingsRequest) (*lnrpc.DeletePeerSettingsResponse, error) {
+
+	pubBytes, err := hex.DecodeString(in.PubKey)
+	if err != nil {
+		return nil, err
+	}
+	peer, err := btcec.ParsePubKey(pubBytes, btcec.S256())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.server.peerSettings.remove(peer); err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.DeletePeerSettingsResponse{}, nil
+}
+
+// CreatePayReq creates a signed, bech32 encoded payment request for an
+// existing invoice. Unlike the payment request returned by AddInvoice, it
+// carries the invoice's memo and an expiry, and its signature binds it to our
+// node.
+func (r *rpcServer) CreatePayReq(ctx context.Context,
+	in *lnrpc.CreatePayReqRequest) (*lnrpc.CreatePayReqResponse, error) {
+
+	var (
+		payHash [32]byte
+		rHash   = in.RHash
+		err     error
+	)
+	if in.RHashStr != "" {
+		rHash, err = hex.DecodeString(in.RHashStr)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(rHash) != 32 {
+		return nil, fmt.Errorf("payment hash must be exactly "+
+			"32 bytes, is instead %v", len(rHash))
+	}
+	copy(payHash[:], rHash)
+
+	if in.Expiry < 0 {
+		return nil, fmt.Errorf("expiry of %v seconds is negative",
+			in.Expiry)
+	}
+
+	invoice, err := r.server.invoices.LookupInvoice(payHash)
+	if err != nil {
+		return nil, err
+	}
+	if invoice.Terms.Settled {
+		return nil, fmt.Errorf("invoice %x is already settled",
+			payHash[:])
+	}
+	if invoice.IsExpired(time.Now()) {
+		return nil, fmt.Errorf("invoice %x has expired", payHash[:])
+	}
+
+	// The payment request mustn't outlive the invoice, so if the invoice
+	// expires, the payment request expires with it unless an earlier
+	// expiry was requested.
+	expiry := time.Duration(in.Expiry) * time.Second
+	if !invoice.Expiry.IsZero() {
+		invoiceExpiry := invoice.Expiry.Sub(invoice.CreationDate)
+		if expiry == 0 || expiry > invoiceExpiry {
+			expiry = invoiceExpiry
+		}
+	}
+
+	// Our zero-conf channels aren't announced until they confirm, so a
+	// payer can only route over them through a hint naming their alias.
+	// Each hint reveals a channel, so only those selected by the
+	// configured policy are hinted at.
+	candidates, err := r.server.routeHintCandidates()
+	if err != nil {
+		return nil, err
+	}
+	candidates = r.server.routeHints.selectHints(candidates)
+	routeHints := make([][]zpay32.HopHint, 0, len(candidates))
+	for _, candidate := range candidates {
+		routeHints = append(routeHints, candidate.hopHint())
+	}
+
+	// The payment request is signed with our identity key, so the payer
+	// can verify that it's paying our node.
+	payReq, err := zpay32.EncodeInvoice(&zpay32.Invoice{
+		Destination: r.server.identityPriv.PubKey(),
+		PaymentHash: payHash,
+		Amount:      invoice.Terms.Value,
+		Timestamp:   invoice.CreationDate,
+		Expiry:      expiry,
+		Description: string(invoice.Memo),
+		RouteHints:  routeHints,
+	}, activeNetParams.payReqPrefix, func(hash []byte) ([]byte, error) {
+		return btcec.SignCompact(btcec.S256(),
+			r.server.identityPriv, hash, true)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rpcsLog.Debugf("[createpayreq] created payment request for "+
+		"invoice %x", payHash[:])
+
+	return &lnrpc.CreatePayReqResponse{PayReq: payReq}, nil
+}
+
+// DecodePayReq takes an encoded payment request string and attempts to decode
+// it, returning a full description of the conditions encoded within the
+// payment request. Both the zbase32 encoding returned by AddInvoice, and the
+// signed bech32 encoding returned by CreatePayReq, are accepted.
+func (r *rpcServer) DecodePayReq(ctx context.Context,
+	req *lnrpc.PayReqString) (*lnrpc.PayReq, error) {
+
+	// Signed payment requests are recognized by their prefix, which
+	// can't start a zbase32 string. Their signature is verified, and they
+	// must be meant for the network we're on.
+	if strings.HasPrefix(strings.ToLower(req.PayReq), "ln") {
+		invoice, err := zpay32.DecodeInvoice(req.PayReq,
+			activeNetParams.payReqPrefix)
+		if err != nil {
+			return nil, err
+		}
+
+		expiry := invoice.Expiry
+		if expiry == 0 {
+			expiry = zpay32.DefaultInvoiceExpiry
+		}
+
+		routeHints := make([]*lnrpc.RouteHint, len(invoice.RouteHints))
+		for i, route := range invoice.RouteHints {
+			hopHints := make([]*lnrpc.HopHint, len(route))
+			for j, hop := range route {
+				nodeID := hop.NodeID.SerializeCompressed()
+				hopHints[j] = &lnrpc.HopHint{
+					NodeId:                    hex.EncodeToString(nodeID),
+					ChanId:                    hop.ChannelID,
+					FeeBaseMsat:               hop.FeeBaseMSat,
+					FeeProportionalMillionths: hop.FeeProportionalMillionths,
+					CltvExpiryDelta:           uint32(hop.CLTVExpiryDelta),
+				}
+			}
+			routeHints[i] = &lnrpc.RouteHint{HopHints: hopHints}
+		}
+
+		dest := invoice.Destination.SerializeCompressed()
+		return &lnrpc.PayReq{
+			Destination: hex.EncodeToString(dest),
+			PaymentHash: hex.EncodeToString(invoice.PaymentHash[:]),
+			NumSatoshis: int64(invoice.Amount),
+			Timestamp:   invoice.Timestamp.Unix(),
+			Expiry:      int64(expiry / time.Second),
+			Description: invoice.Description,
+			RouteHints:  routeHints,
+		}, nil
+	}
+
+	// Fist we'll attempt to decode the payment request string, if the
+	// request is invalid or the checksum doesn't match, then we'll exit
+	// here with an error.
+	payReq, err := zpay32.Decode(req.PayReq)
+	if err != nil {
+		return nil, err
+	}
+
+	dest := payReq.Destination.SerializeCompressed()
+	return &lnrpc.PayReq{
+		Destination: hex.EncodeToString(dest),
+		PaymentHash: hex.EncodeToString(payReq.PaymentHash[:]),
+		NumSatoshis: int64(payReq.Amount),
+	}, nil
+}
+
+// RegisterRPCMiddleware registers the calling process as RPC middleware for
+// as long as the stream remains open. Depending on the mode it registers
+// with, the middleware is either notified of the calls it's interested in,
+// or may rewrite or deny them before they're handled.
+func (r *rpcServer) RegisterRPCMiddleware(
+	stream lnrpc.Lightning_RegisterRPCMiddlewareServer) error {
+
+	if r.middleware == nil {
+		return fmt.Errorf("RPC middleware is disabled, restart with " +
+			"--rpcmiddleware.enable to register middleware")
+	}
+
+	return r.middleware.register(stream)
+}
+
+// ListRecommendations returns the rebalances, closes and opens currently
+// recommended by the channel advisor, based on the balances of our channels
+// and their recent forwarding history.
+func (r *rpcServer) ListRecommendations(ctx context.Context,
+	in *lnrpc.ListRecommendationsRequest) (*lnrpc.ListRecommendationsResponse, error) {
+
+	recs, err := r.server.advisor.recommendations()
+	if err != nil {
+		return nil, err
+	}
+
+	rpcsLog.Debugf("[listrecommendations] %v recommendations", len(recs))
+
+	resp := &lnrpc.ListRecommendationsResponse{
+		Recommendations: make([]*lnrpc.ChannelRecommendation, 0,
+			len(recs)),
+	}
+	for _, rec := range recs {
+		resp.Recommendations = append(resp.Recommendations,
+			&lnrpc.ChannelRecommendation{
+				Id:           rec.id,
+				Action:       rec.action,
+				ChannelPoint: rec.chanPoint.String(),
+				RemotePubkey: hex.EncodeToString(
+					rec.remotePub.SerializeCompressed()),
+				Amount: int64(rec.amount),
+				Reason: rec.reason,
+			})
+	}
+
+	return resp, nil
+}
+
+// ExecuteRecommendation carries out a close or open recommended by the
+// channel advisor. The call returns once the closing or funding transaction
+// has been broadcast.
+func (r *rpcServer) ExecuteRecommendation(ctx context.Context,
+	in *lnrpc.ExecuteRecommendationRequest) (*lnrpc.ExecuteRecommendationResponse, error) {
+
+	rpcsLog.Infof("[executerecommendation] executing recommendation %v",
+		in.Id)
+
+	// Recommendations are carried out with the permission their action
+	// requires. An action without a known permission is refused, so a
+	// new kind of recommendation can't be executed until it's mapped.
+	authorize := func(action string) error {
+		var perm permission
+		switch action {
+		case closeAction:
+			perm = permCoopClose
+		case openAction:
+			perm = permOpenChannel
+		default:
+			return fmt.Errorf("%v recommendations can't be "+
+				"executed automatically, and must be carried "+
+				"out manually", action)
+		}
+		return r.quotas.authorize(ctx, perm)
+	}
+	if err := r.server.advisor.execute(in.Id, authorize); err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.ExecuteRecommendationResponse{}, nil
+}
+
+// SubscribeOutbox registers the client as the named subscriber of the
+// notification outbox, then streams each notification the subscriber hasn't
+// yet acknowledged, followed by new notifications as they're added. As the
+// outbox retains notifications until they're acknowledged via
+// AckOutboxNotification, a client which resubscribes under the same name
+// resumes after the last notification it acknowledged.
+func (r *rpcServer) SubscribeOutbox(req *lnrpc.OutboxSubscription,
+	updateStream lnrpc.Lightning_SubscribeOutboxServer) error {
+
+	if err := validateStreamSubscriber(req.SubscriberName); err != nil {
+		return err
+	}
+
+	outbox := r.server.outbox
+	wakeup, err := outbox.register(req.SubscriberName)
+	if err != nil {
+		return err
+	}
+	defer outbox.unregister(req.SubscriberName)
+
+	sent, err := r.server.chanDB.AddOutboxSubscriber(req.SubscriberName)
+	if err != nil {
+		return err
+	}
+
+	rpcsLog.Infof("[subscribeoutbox] subscriber %v resuming after "+
+		"notification #%v", req.SubscriberName, sent)
+
+	for {
+		notifications, err := r.server.chanDB.FetchNotifications(sent)
+		if err != nil {
+			return err
+		}
+		for _, n := range notifications {
+			err := updateStream.Send(&lnrpc.OutboxNotification{
+				Id:        n.ID,
+				Type:      n.Type.String(),
+				Timestamp: n.Timestamp.Unix(),
+				Payload:   string(n.Payload),
+			})
+			if err != nil {
+				return err
+			}
+			sent = n.ID
+		}
+
+		select {
+		case <-wakeup:
+		case <-updateStream.Context().Done():
+			return nil
+		case <-r.quit:
+			return nil
+		}
+	}
+}
+
+// AckOutboxNotification acknowledges the receipt of all notifications up to,
+// and including the specified one by the named outbox subscriber.
+func (r *rpcServer) AckOutboxNotification(ctx context.Context,
+	in *lnrpc.OutboxAck) (*lnrpc.OutboxAckResponse, error) {
+
+	if err := validateStreamSubscriber(in.SubscriberName); err != nil {
+		return nil, err
+	}
+
+	err := r.server.chanDB.AckNotification(in.SubscriberName, in.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.OutboxAckResponse{}, nil
+}
+
+// RemoveOutboxSubscriber unregisters the named outbox subscriber, releasing
+// the notifications retained on its behalf. Subscribers with an active
+// SubscribeOutbox stream can't be removed.
+func (r *rpcServer) RemoveOutboxSubscriber(ctx context.Context,
+	in *lnrpc.RemoveOutboxSubscriberRequest) (*lnrpc.RemoveOutboxSubscriberResponse, error) {
+
+	if err := validateStreamSubscriber(in.SubscriberName); err != nil {
+		return nil, err
+	}
+
+	// Registering the subscriber ensures it isn't active, and keeps it
+	// from becoming active until it has been removed.
+	outbox := r.server.outbox
+	if _, err := outbox.register(in.SubscriberName); err != nil {
+		return nil, err
+	}
+	defer outbox.unregister(in.SubscriberName)
+
+	rpcsLog.Infof("[removeoutboxsubscriber] removing subscriber %v",
+		in.SubscriberName)
+
+	err := r.server.chanDB.RemoveOutboxSubscriber(in.SubscriberName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.RemoveOutboxSubscriberResponse{}, nil
+}
+
+// QueryMissionControl returns the penalties applied to channels and nodes
+// during path finding due to the payment failures they've been a part of.
+func (r *rpcServer) QueryMissionControl(ctx context.Context,
+	in *lnrpc.QueryMissionControlRequest) (*lnrpc.QueryMissionControlResponse, error) {
+
+	edges, nodes, err := r.server.chanRouter.QueryMissionControl()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &lnrpc.QueryMissionControlResponse{
+		Edges: make([]*lnrpc.EdgePenalty, 0, len(edges)),
+		Nodes: make([]*lnrpc.NodePenalty, 0, len(nodes)),
+	}
+	for _, edge := range edges {
+		resp.Edges = append(resp.Edges, &lnrpc.EdgePenalty{
+			ChanId:      edge.ChannelID,
+			Failures:    edge.Failures,
+			LastFailure: edge.LastFailure.Unix(),
+			Weight:      edge.Weight,
+		})
+	}
+	for _, node := range nodes {
+		resp.Nodes = append(resp.Nodes, &lnrpc.NodePenalty{
+			PubKey: hex.EncodeToString(
+				node.Node.SerializeCompressed()),
+			Failures:    node.Failures,
+			LastFailure: node.LastFailure.Unix(),
+			Weight:      node.Weight,
+		})
+	}
+
+	return resp, nil
+}
+
+// ResetMissionControl forgets the payment failures of the specified channel
+// or node, which may be referenced by its alias. If neither is specified,
+// then the failures of all channels and nodes are forgotten.
+func (r *rpcServer) ResetMissionControl(ctx context.Context,
+	in *lnrpc.ResetMissionControlRequest) (*lnrpc.ResetMissionControlResponse, error) {
+
+	router := r.server.chanRouter
+	switch {
+	case in.ChanId != 0 && in.PubKey != "":
+		return nil, fmt.Errorf("either a channel or a node may be " +
+			"reset, not both")
+
+	case in.ChanId != 0:
+		rpcsLog.Infof("[resetmissioncontrol] resetting channel %v",
+			in.ChanId)
+
+		if err := router.ResetEdgePenalty(in.ChanId); err != nil {
+			return nil, err
+		}
+
+	case in.PubKey != "":
+		node, err := r.resolveNode(in.PubKey)
+		if err != nil {
+			return nil, err
+		}
+
+		rpcsLog.Infof("[resetmissioncontrol] resetting node %x",
+			node.SerializeCompressed())
+
+		if err := router.ResetNodePenalty(node); err != nil {
+			return nil, err
+		}
+
+	default:
+		rpcsLog.Infof("[resetmissioncontrol] resetting all channels " +
+			"and nodes")
+
+		if err := router.ResetMissionControl(); err != nil {
+			return nil, err
+		}
+	}
+
+	return &lnrpc.ResetMissionControlResponse{}, nil
+}
+
+// AckChannelDivergence acknowledges the divergence between the in-memory and
+// persisted state of the target channel, unfreezing it. If the channel still
+// diverges when next verified, then it's frozen once more.
+func (r *rpcServer) AckChannelDivergence(ctx context.Context,
+	in *lnrpc.ChannelPoint) (*lnrpc.AckChannelDivergenceResponse, error) {
+
+	txid, err := chainhash.NewHash(in.FundingTxid)
+	if err != nil {
+		return nil, err
+	}
+	chanPoint := wire.NewOutPoint(txid, in.OutputIndex)
+
+	dbChannels, err := r.server.chanDB.FetchAllChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	var dbChan *channeldb.OpenChannel
+	for _, dbChannel := range dbChannels {
+		if *dbChannel.ChanID == *chanPoint {
+			dbChan = dbChannel
+			break
+		}
+	}
+	if dbChan == nil {
+		return nil, fmt.Errorf("unable to find channel %v", chanPoint)
+	}
+
+	// Only the channel state machine held by the peer is ever frozen, so
+	// the channel must be active for there to be anything to acknowledge.
+	peer, err := r.server.findPeer(dbChan.IdentityPub)
+	if err != nil {
+		return nil, fmt.Errorf("channel %v isn't active", chanPoint)
+	}
+	peer.activeChanMtx.RLock()
+	channel, ok := peer.activeChannels[*chanPoint]
+	peer.activeChanMtx.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("channel %v isn't active", chanPoint)
+	}
+
+	divergence := channel.Divergence()
+	if divergence == nil {
+		return nil, fmt.Errorf("channel %v isn't frozen", chanPoint)
+	}
+
+	rpcsLog.Infof("[ackchanneldivergence] unfreezing ChannelPoint(%v), "+
+		"acknowledged: %v", chanPoint, divergence)
+
+	channel.AckDivergence()
+
+	return &lnrpc.AckChannelDivergenceResponse{
+		Divergence: divergence.Error(),
+	}, nil
+}
+
+// SubscribeCustomMessages registers the client as the handler of the passed
+// message types, which are tied to an experimental feature bit, then streams
+// each message of those types received from peers which advertise the
+// feature. The types are released once the client disconnects.
+func (r *rpcServer) SubscribeCustomMessages(req *lnrpc.CustomMessageSubscription,
+	updateStream lnrpc.Lightning_SubscribeCustomMessagesServer) error {
+
+	if req.FeatureBit > math.MaxUint16 {
+		return fmt.Errorf("feature bit %v is out of range",
+			req.FeatureBit)
+	}
+
+	registry := r.server.customMessages
+	handler, err := registry.registerHandler(uint16(req.FeatureBit),
+		req.Types)
+	if err != nil {
+		return err
+	}
+	defer registry.unregisterHandler(handler)
+
+	rpcsLog.Infof("[subscribecustommessages] handling types %v for "+
+		"feature bit %v", req.Types, req.FeatureBit)
+
+	for {
+		select {
+		case m := <-handler.msgs:
+			err := updateStream.Send(&lnrpc.CustomMessage{
+				Peer: hex.EncodeToString(
+					m.peer.SerializeCompressed()),
+				Type: m.msg.Type,
+				Data: m.msg.Data,
+			})
+			if err != nil {
+				return err
+			}
+
+		case <-updateStream.Context().Done():
+			return nil
+		case <-r.quit:
+			return nil
+		}
+	}
+}
+
+// SendCustomMessage sends a message of an experimental type to the target
+// peer, which may be referenced by its alias. The type must be handled by a
+// subscriber of SubscribeCustomMessages, and the peer must advertise the
+// feature the type is tied to.
+func (r *rpcServer) SendCustomMessage(ctx context.Context,
+	in *lnrpc.SendCustomMessageRequest) (*lnrpc.SendCustomMessageResponse, error) {
+
+	peer, err := r.resolveNode(in.Peer)
+	if err != nil {
+		return nil, err
+	}
+
+	rpcsLog.Debugf("[sendcustommessage] sending message of type %v to "+
+		"%x", in.Type, peer.SerializeCompressed())
+
+	err = r.server.customMessages.sendCustomMessage(peer, in.Type, in.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.SendCustomMessageResponse{}, nil
+}
+
+// ExportSigningAudit returns the signatures recorded within the signing
+// audit log, optionally restricted to those produced within a time range.
+// Signatures are only recorded if the signing audit is enabled.
+func (r *rpcServer) ExportSigningAudit(ctx context.Context,
+	in *lnrpc.SigningAuditRequest) (*lnrpc.SigningAuditResponse, error) {
+
+	// The range is specified in seconds, and is inclusive of the entire
+	// final second.
+	var start, end time.Time
+	if in.StartTime != 0 {
+		start = time.Unix(in.StartTime, 0)
+	}
+	if in.EndTime != 0 {
+		end = time.Unix(in.EndTime, int64(time.Second-1))
+	}
+	if !start.IsZero() && !end.IsZero() && end.Before(start) {
+		return nil, fmt.Errorf("end time must not be before start time")
+	}
+
+	rpcsLog.Debugf("[exportsigningaudit] fetching signing events")
+
+	events, err := r.server.chanDB.FetchSigningEvents(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &lnrpc.SigningAuditResponse{
+		Events: make([]*lnrpc.SigningEvent, 0, len(events)),
+	}
+	for _, event := range events {
+		var pubKey string
+		if event.PubKey != nil {
+			pubKey = hex.EncodeToString(
+				event.PubKey.SerializeCompressed(),
+			)
+		}
+
+		resp.Events = append(resp.Events, &lnrpc.SigningEvent{
+			Type:      event.Type.String(),
+			Timestamp: event.Timestamp.Unix(),
+			Outpoint:  event.Outpoint.String(),
+			PubKey:    pubKey,
+			DataHash:  hex.EncodeToString(event.DataHash[:]),
+		})
+	}
+
+	return resp, nil
+}
+
+// ExportChannelPolicies returns the routing policy of each of our channels, in
+// a form which may be edited and passed back to ImportChannelPolicies.
+func (r *rpcServer) ExportChannelPolicies(ctx context.Context,
+	in *lnrpc.ExportChannelPoliciesRequest) (*lnrpc.ChannelPolicies, error) {
+
+	policies, err := selfPolicies(r.server.chanDB.ChannelGraph())
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &lnrpc.ChannelPolicies{
+		Policies: make([]*lnrpc.ChannelPolicy, 0, len(policies)),
+	}
+	for chanPoint, policy := range policies {
+		resp.Policies = append(resp.Policies, &lnrpc.ChannelPolicy{
+			ChanPoint: chanPoint,
+			Policy:    marshalRoutingPolicy(policy),
+		})
+	}
+
+	// The policies are sorted by channel point, so successive exports can
+	// be meaningfully diffed.
+	sort.Sort(policiesByChanPoint(resp.Policies))
+
+	rpcsLog.Debugf("[exportchannelpolicies] exported policies of %v "+
+		"channels", len(resp.Policies))
+
+	return resp, nil
+}
+
+// ImportChannelPolicies applies the passed routing policies to our channels,
+// returning each policy which changed. The policies are applied within a
+// single transaction, so if any of them is invalid, then none are applied.
+// Channels which aren't mentioned keep their current policy. If DryRun is
+// set, then the changes are only reported.
+func (r *rpcServer) ImportChannelPolicies(ctx context.Context,
+	in *lnrpc.ImportChannelPoliciesRequest) (*lnrpc.ImportChannelPoliciesResponse, error) {
+
+	// A dry run only reports the changes, so requires no permission.
+	if !in.DryRun {
+		if err := r.quotas.authorize(ctx, permUpdatePolicy); err != nil {
+			return nil, err
+		}
+	}
+
+	r.policyMtx.Lock()
+	defer r.policyMtx.Unlock()
+
+	current, err := selfPolicies(r.server.chanDB.ChannelGraph())
+	if err != nil {
+		return nil, err
+	}
+
+	diffs, updates, err := diffChannelPolicies(current, in.Policies,
+		time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &lnrpc.ImportChannelPoliciesResponse{Changes: diffs}
+	if in.DryRun || len(updates) == 0 {
+		return resp, nil
+	}
+
+	rpcsLog.Infof("[importchannelpolicies] updating policies of %v "+
+		"channels", len(updates))
+
+	if err := r.server.chanRouter.UpdateSelfPolicies(updates); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// BalanceHistory returns the snapshots of the node's balances recorded within
+// the requested time range, in chronological order.
+func (r *rpcServer) BalanceHistory(ctx context.Context,
+	in *lnrpc.BalanceHistoryRequest) (*lnrpc.BalanceHistoryResponse, error) {
+
+	// The range is specified in seconds, and is inclusive of the entire
+	// final second.
+	var start, end time.Time
+	if in.StartTime != 0 {
+		start = time.Unix(in.StartTime, 0)
+	}
+	if in.EndTime != 0 {
+		end = time.Unix(in.EndTime, int64(time.Second-1))
+	}
+	if !start.IsZero() && !end.IsZero() && end.Before(start) {
+		return nil, fmt.Errorf("end time must not be before start time")
+	}
+
+	rpcsLog.Debugf("[balancehistory] fetching balance snapshots")
+
+	snapshots, err := r.server.chanDB.FetchBalanceSnapshots(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &lnrpc.BalanceHistoryResponse{
+		Snapshots: make([]*lnrpc.BalanceSnapshot, 0, len(snapshots)),
+	}
+	for _, s := range snapshots {
+		resp.Snapshots = append(resp.Snapshots, &lnrpc.BalanceSnapshot{
+			Timestamp:          s.Timestamp.Unix(),
+			OnChainBalance:     int64(s.OnChainBalance),
+			UnconfirmedBalance: int64(s.UnconfirmedBalance),
+			LocalBalance:       int64(s.LocalBalance),
+			RemoteBalance:      int64(s.RemoteBalance),
+			PendingOpenBalance: int64(s.PendingOpenBalance),
+			TotalBalance:       int64(totalEquity(s)),
+		})
+	}
+
+	return resp, nil
+}
+
+// ExportAccounting returns the accounting report of the node over the
+// requested time range: its settled invoices, completed payments, the fees
+// earned by forwards, and its confirmed on-chain transactions, each split into
+// the principal moved and the fee earned or paid, ordered by time.
+func (r *rpcServer) ExportAccounting(ctx context.Context,
+	in *lnrpc.AccountingRequest) (*lnrpc.AccountingReport, error) {
+
+	// The range is specified in seconds, and is inclusive of the entire
+	// final second.
+	var start, end time.Time
+	if in.StartTime != 0 {
+		start = time.Unix(in.StartTime, 0)
+	}
+	if in.EndTime != 0 {
+		end = time.Unix(in.EndTime, int64(time.Second-1))
+	}
+	if !start.IsZero() && !end.IsZero() && end.Before(start) {
+		return nil, fmt.Errorf("end time must not be before start time")
+	}
+
+	rpcsLog.Debugf("[exportaccounting] fetching accounting records")
+
+	records, err := fetchAccountingRecords(r.server.chanDB,
+		r.server.lnwallet)
+	if err != nil {
+		return nil, err
+	}
+	entries := accountingReport(records, start, end)
+
+	resp := &lnrpc.AccountingReport{
+		Entries: make([]*lnrpc.AccountingEntry, 0, len(entries)),
+	}
+	for _, entry := range entries {
+		resp.Entries = append(resp.Entries, &lnrpc.AccountingEntry{
+			Timestamp:   entry.timestamp.Unix(),
+			Type:        entry.entryType,
+			Amount:      int64(entry.amount),
+			Fee:         int64(entry.fee),
+			Reference:   entry.reference,
+			Description: entry.description,
+		})
+	}
+
+	return resp, nil
+}
+
+// AbandonChannel removes a channel which can no longer be closed through the
+// usual means, as its funding transaction never confirmed, or its remote
+// party has vanished. The channel's state is archived within the database for
+// later audit, and any of the wallet's outputs locked to fund the channel are
+// unlocked. Channels which are active must be closed instead.
+func (r *rpcServer) AbandonChannel(ctx context.Context,
+	in *lnrpc.ChannelPoint) (*lnrpc.AbandonChannelResponse, error) {
+
+	if err := r.quotas.authorize(ctx, permAbandonChannel); err != nil {
+		return nil, err
+	}
+
+	txid, err := chainhash.NewHash(in.FundingTxid)
+	if err != nil {
+		return nil, err
+	}
+	chanPoint := wire.NewOutPoint(txid, in.OutputIndex)
+
+	dbChannels, err := r.server.chanDB.FetchAllChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	var dbChan *channeldb.OpenChannel
+	for _, dbChannel := range dbChannels {
+		if *dbChannel.ChanID == *chanPoint {
+			dbChan = dbChannel
+			break
+		}
+	}
+	if dbChan == nil {
+		return nil, fmt.Errorf("unable to find channel %v", chanPoint)
+	}
+
+	// If the channel's peer is online and the channel is active, then the
+	// channel can be closed, so we won't abandon the funds within it.
+	if peer, err := r.server.findPeer(dbChan.IdentityPub); err == nil {
+		peer.activeChanMtx.RLock()
+		_, ok := peer.activeChannels[*chanPoint]
+		peer.activeChanMtx.RUnlock()
+		if ok {
+			return nil, fmt.Errorf("channel %v is active, close it "+
+				"instead", chanPoint)
+		}
+	}
+
+	rpcsLog.Infof("[abandonchannel] abandoning ChannelPoint(%v), "+
+		"pending=%v", chanPoint, dbChan.IsPending)
+
+	if err := dbChan.AbandonChannel(); err != nil {
+		return nil, err
+	}
+	r.server.chanBackup.requestUpdate()
+
+	unlocked := r.server.lnwallet.UnlockFundingInputs(*chanPoint)
+	resp := &lnrpc.AbandonChannelResponse{
+		UnlockedOutpoints: make([]string, 0, len(unlocked)),
+	}
+	for _, outpoint := range unlocked {
+		resp.UnlockedOutpoints = append(resp.UnlockedOutpoints,
+			outpoint.String())
+	}
+
+	return resp, nil
+}
+
+// RotateIdentity rotates the node's identity key, as after its partial
+// compromise. Each peer we have channels with is notified of the new identity
+// so it's able to carry our channels over to it, while each announced
+// channel, which the network knows by our current identity, is cooperatively
+// closed. The new identity key is used once the daemon is restarted.
+func (r *rpcServer) RotateIdentity(ctx context.Context,
+	in *lnrpc.RotateIdentityRequest) (*lnrpc.RotateIdentityResponse, error) {
+
+	// Rotating our identity cooperatively closes each announced channel.
+	if err := r.quotas.authorize(ctx, permCoopClose); err != nil {
+		return nil, err
+	}
+
+	rpcsLog.Infof("[rotateidentity] rotating identity of %x",
+		r.server.identityPriv.PubKey().SerializeCompressed())
+
+	newPub, closing, err := r.server.rotateIdentity()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &lnrpc.RotateIdentityResponse{
+		IdentityPubkey: hex.EncodeToString(
+			newPub.SerializeCompressed()),
+		ClosingChannels: make([]*lnrpc.ChannelPoint, 0, len(closing)),
+	}
+	for _, chanPoint := range closing {
+		resp.ClosingChannels = append(resp.ClosingChannels,
+			&lnrpc.ChannelPoint{
+				FundingTxid: chanPoint.Hash[:],
+				OutputIndex: chanPoint.Index,
+			})
+	}
+
+	return resp, nil
+}
+
+// ConfirmIdentityRotation confirms the announced rotation of a peer's
+// identity to the passed key, which the peer's operator conveyed out of band,
+// so our channels with the peer are carried over to its new identity without
+// waiting for the identity rotation delay to elapse.
+func (r *rpcServer) ConfirmIdentityRotation(ctx context.Context,
+	in *lnrpc.ConfirmIdentityRotationRequest) (*lnrpc.ConfirmIdentityRotationResponse, error) {
+
+	pubKeyBytes, err := hex.DecodeString(in.IdentityPubkey)
+	if err != nil {
+		return nil, err
+	}
+	newPub, err := btcec.ParsePubKey(pubKeyBytes, btcec.S256())
+	if err != nil {
+		return nil, err
+	}
+
+	rpcsLog.Infof("[confirmidentityrotation] confirming identity "+
+		"rotation to %x", pubKeyBytes)
+
+	if err := r.server.confirmIdentityRotation(newPub); err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.ConfirmIdentityRotationResponse{}, nil
+}
+
+// errNotSimulation is returned by the RPCs controlling the virtual chain when
+// the daemon isn't running in simulation mode.
+var errNotSimulation = errors.New("only available in simulation mode")
+
+// MineBlocks mines the requested number of blocks on the virtual chain,
+// confirming all transactions within its mempool. The block subsidies, and
+// fees, are paid to a fresh address of the wallet.
+func (r *rpcServer) MineBlocks(ctx context.Context,
+	in *lnrpc.MineBlocksRequest) (*lnrpc.MineBlocksResponse, error) {
+
+	if r.server.virtualChain == nil {
+		return nil, errNotSimulation
+	}
+	if in.NumBlocks == 0 {
+		return nil, fmt.Errorf("num_blocks must be positive")
+	}
+
+	rpcsLog.Debugf("[mineblocks] num_blocks=%v", in.NumBlocks)
+
+	addr, err := r.server.lnwallet.NewAddress(lnwallet.WitnessPubKey, false)
+	if err != nil {
+		return nil, err
+	}
+	pkScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	hashes, err := r.server.virtualChain.Mine(in.NumBlocks, pkScript)
+	if err != nil {
+		return nil, err
+	}
+	_, height, err := r.server.virtualChain.BestBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &lnrpc.MineBlocksResponse{
+		BlockHashes: make([]string, 0, len(hashes)),
+		Height:      height,
+	}
+	for _, hash := range hashes {
+		resp.BlockHashes = append(resp.BlockHashes, hash.String())
+	}
+
+	return resp, nil
+}
+
+// AdvanceTime moves the clock of the virtual chain forward by the requested
+// number of seconds, without mining any block. The virtual clock governs the
+// timestamps of mined blocks, and the time locks of published transactions.
+func (r *rpcServer) AdvanceTime(ctx context.Context,
+	in *lnrpc.AdvanceTimeRequest) (*lnrpc.AdvanceTimeResponse, error) {
+
+	if r.server.virtualChain == nil {
+		return nil, errNotSimulation
+	}
+	if in.Seconds <= 0 {
+		return nil, fmt.Errorf("seconds must be positive")
+	}
+
+	rpcsLog.Debugf("[advancetime] seconds=%v", in.Seconds)
+
+	now, err := r.server.virtualChain.AdvanceTime(
+		time.Duration(in.Seconds) * time.Second)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.AdvanceTimeResponse{
+		Timestamp: now.Unix(),
+	}, nil
+}
+
+// ListUnresolvedHTLCs returns each HTLC within our active channels which has
+// yet to be resolved, along with the subsystem currently responsible for its
+// resolution, in order to diagnose payments which remain pending. Only HTLCs
+// which have been pending for at least the requested minimum age, in
+// seconds, are returned.
+func (r *rpcServer) ListUnresolvedHTLCs(ctx context.Context,
+	in *lnrpc.ListUnresolvedHTLCsRequest) (*lnrpc.ListUnresolvedHTLCsResponse, error) {
+
+	if in.MinAge < 0 {
+		return nil, fmt.Errorf("min_age must not be negative")
+	}
+
+	rpcsLog.Debugf("[listunresolvedhtlcs] min_age=%v", in.MinAge)
+
+	now := time.Now()
+	resp := &lnrpc.ListUnresolvedHTLCsResponse{}
+	for _, peer := range r.server.Peers() {
+		remotePub := hex.EncodeToString(
+			peer.addr.IdentityKey.SerializeCompressed())
+
+		peer.activeChanMtx.RLock()
+		channels := make([]*lnwallet.LightningChannel, 0,
+			len(peer.activeChannels))
+		for _, channel := range peer.activeChannels {
+			channels = append(channels, channel)
+		}
+		peer.activeChanMtx.RUnlock()
+
+		for _, channel := range channels {
+			for _, htlc := range channel.PendingHTLCs() {
+				age := int64(now.Sub(htlc.AddTime).Seconds())
+				if age < in.MinAge {
+					continue
+				}
+
+				// Only incoming HTLCs may pay our invoices,
+				// any others are either forwards or our own
+				// payments.
+				var memo string
+				isInvoice := false
+				if htlc.Incoming {
+					invoice, err := r.server.invoices.LookupInvoice(
+						chainhash.Hash(htlc.RHash))
+					switch {
+					case err == nil:
+						isInvoice = true
+						memo = string(invoice.Memo)
+					case err != channeldb.ErrInvoiceNotFound:
+						return nil, err
+					}
+				}
+				isHeld := htlc.Incoming && !isInvoice &&
+					r.server.asyncPayments.isHeld(htlc.RHash)
+
+				owner := unresolvedHTLCOwner(htlc, isInvoice,
+					isHeld)
+				resp.Htlcs = append(resp.Htlcs, &lnrpc.UnresolvedHTLC{
+					ChannelPoint:     channel.ChannelPoint().String(),
+					RemotePubkey:     remotePub,
+					Incoming:         htlc.Incoming,
+					Amount:           int64(htlc.Amount),
+					PaymentHash:      htlc.RHash[:],
+					ExpirationHeight: htlc.Expiry,
+					Age:              age,
+					LockedIn:         htlc.LockedIn,
+					Owner:            string(owner),
+					InvoiceMemo:      memo,
+				})
+			}
+		}
+	}
+
+	return resp, nil
+}
+
+// UpdateCommitFee proposes a new fee for the commitment transactions of the
+// target channel. As the fee is paid by the initiator of the channel, only
+// channels we've initiated may have their fee updated. The new fee takes
+// effect once both parties have signed new commitments paying it, and revoked
+// their prior commitments.
+func (r *rpcServer) UpdateCommitFee(ctx context.Context,
+	in *lnrpc.UpdateCommitFeeRequest) (*lnrpc.UpdateCommitFeeResponse, error) {
+
+	// The commitment fee is paid from our balance, as we're the
+	// initiator.
+	if err := r.quotas.authorize(ctx, permSendFunds); err != nil {
+		return nil, err
+	}
+
+	if in.ChannelPoint == nil {
+		return nil, fmt.Errorf("channel point must be specified")
+	}
+	if in.Fee <= 0 {
+		return nil, fmt.Errorf("commitment fee must be positive")
+	}
+	txid, err := chainhash.NewHash(in.ChannelPoint.FundingTxid)
+	if err != nil {
+		return nil, err
+	}
+	chanPoint := wire.NewOutPoint(txid, in.ChannelPoint.OutputIndex)
+
+	rpcsLog.Infof("[updatecommitfee] updating commitment fee of "+
+		"ChannelPoint(%v) to %v", chanPoint, btcutil.Amount(in.Fee))
+
+	err = r.server.htlcSwitch.UpdateLinkFee(chanPoint,
+		btcutil.Amount(in.Fee))
+	if err != nil {
+		return nil, err
+	}
+
+	return &lnrpc.UpdateCommitFeeResponse{}, nil
+}
+
+// ExportGossip returns the messages within the gossip store received at or
+// after the requested time, in the order they were received. Each message is
+// serialized in its wire format, so the export may be replayed into another
+// node on the same network via ReplayGossip.
+func (r *rpcServer) ExportGossip(ctx context.Context,
+	in *lnrpc.ExportGossipRequest) (*lnrpc.GossipMessages, error) {
+
+	var since time.Time
+	if in.Since != 0 {
+		since = time.Unix(in.Since, 0)
+	}
+
+	rpcsLog.Debugf("[exportgossip] fetching gossip since %v", since)
+
+	graph := r.server.chanDB.ChannelGraph()
+	msgs, err := graph.FetchGossipMessages(since)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &lnrpc.GossipMessages{
+		Messages: make([]*lnrpc.StoredGossipMessage, 0, len(msgs)),
+	}
+	for _, msg := range msgs {
+		var b bytes.Buffer
+		_, err := lnwire.WriteMessage(&b, msg.Msg, 0, activeNetParams.Net)
+		if err != nil {
+			return nil, err
+		}
+
+		resp.Messages = append(resp.Messages, &lnrpc.StoredGossipMessage{
+			Peer:     hex.EncodeToString(msg.Peer.SerializeCompressed()),
+			Received: msg.Received.Unix(),
+			Message:  b.Bytes(),
+		})
+	}
+
+	return resp, nil
+}
+
+// ReplayGossip feeds the passed gossip messages, as exported by ExportGossip,
+// into the router as if they had just been received from their original
+// peers. This allows the gossip observed by one node to be reproduced within
+// a test node while debugging. The replay is rejected as a whole if any of the
+// messages can't be decoded.
+func (r *rpcServer) ReplayGossip(ctx context.Context,
+	in *lnrpc.GossipMessages) (*lnrpc.ReplayGossipResponse, error) {
+
+	msgs := make([]*channeldb.GossipMessage, 0, len(in.Messages))
+	for i, stored := range in.Messages {
+		peerBytes, err := hex.DecodeString(stored.Peer)
+		if err != nil {
+			return nil, fmt.Errorf("invalid peer of message %v: %v",
+				i, err)
+		}
+		peer, err := btcec.ParsePubKey(peerBytes, btcec.S256())
+		if err != nil {
+			return nil, fmt.Errorf("invalid peer of message %v: %v",
+				i, err)
+		}
+
+		_, msg, _, err := lnwire.ReadMessage(
+			bytes.NewReader(stored.Message), 0, activeNetParams.Net,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode message %v: %v",
+				i, err)
+		}
+
+		switch msg.(type) {
+		case *lnwire.NodeAnnouncement,
+			*lnwire.ChannelAnnouncement,
+			*lnwire.ChannelUpdateAnnouncement:
+
+		default:
+			return nil, fmt.Errorf("message %v is a %T, not a "+
+				"gossip message", i, msg)
+		}
+
+		msgs = append(msgs, &channeldb.GossipMessage{
+			Peer: peer,
+			Msg:  msg,
+		})
+	}
+
+	rpcsLog.Infof("[replaygossip] replaying %v gossip messages",
+		len(msgs))
+
+	for _, msg := range msgs {
+		r.server.chanRouter.ProcessRoutingMessage(msg.Msg, msg.Peer)
+	}
+
+	return &lnrpc.ReplayGossipResponse{
+		NumReplayed: uint32(len(msgs)),
+	}, nil
+}
+
+// SpliceIn splices additional funds from the wallet into an active channel,
+// increasing its capacity without closing it. The call returns once the
+// splice transaction has been signed by both parties and broadcast, after
+// which the channel migrates to the new funding output once the splice
+// transaction has sufficient confirmations.
+func (r *rpcServer) SpliceIn(ctx context.Context,
+	in *lnrpc.SpliceInRequest) (*lnrpc.SpliceInResponse, error) {
+
+	if err := r.quotas.authorize(ctx, permOpenChannel); err != nil {
+		return nil, err
+	}
+
+	if in.ChannelPoint == nil {
+		return nil, fmt.Errorf("channel point must be specified")
+	}
+	if in.Amount <= 0 {
+		return nil, fmt.Errorf("splice amount must be positive")
+	}
+
+	txid, err := chainhash.NewHash(in.ChannelPoint.FundingTxid)
+	if err != nil {
+		return nil, err
+	}
+	chanPoint := wire.NewOutPoint(txid, in.ChannelPoint.OutputIndex)
+
+	dbChannels, err := r.server.chanDB.FetchAllChannels()
+	if err != nil {
+		return nil, err
+	}
+
+	var dbChan *channeldb.OpenChannel
+	for _, dbChannel := range dbChannels {
+		if *dbChannel.ChanID == *chanPoint {
+			dbChan = dbChannel
+			break
+		}
+	}
+	if dbChan == nil {
+		return nil, fmt.Errorf("unable to find channel %v", chanPoint)
+	}
+
+	peer, err := r.server.findPeer(dbChan.IdentityPub)
+	if err != nil {
+		return nil, fmt.Errorf("channel %v isn't active", chanPoint)
+	}
+
+	rpcsLog.Infof("[splicein] splicing %v into ChannelPoint(%v)",
+		btcutil.Amount(in.Amount), chanPoint)
+
+	spliceTxid, err := peer.spliceIn(chanPoint, btcutil.Amount(in.Amount))
+	if err != nil {
+		rpcsLog.Errorf("unable to splice into ChannelPoint(%v): %v",
+			chanPoint, err)
+		return nil, err
+	}
+
+	return &lnrpc.SpliceInResponse{
+		SpliceTxid: spliceTxid[:],
 	}, nil
 }
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"path/filepath"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -18,6 +19,8 @@ import (
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/lightningnetwork/lnd/lnwire"
 	"github.com/lightningnetwork/lnd/routing"
+	"github.com/lightningnetwork/lnd/sqlstore"
+	"github.com/lightningnetwork/lnd/virtualchain"
 	"github.com/roasbeef/btcd/btcec"
 	"github.com/roasbeef/btcd/chaincfg/chainhash"
 	"github.com/roasbeef/btcd/connmgr"
@@ -54,10 +57,104 @@ type server struct {
 	fundingMgr *fundingManager
 	chanDB     *channeldb.DB
 
+	// analytics is an optional SQL store which invoices, payments, and
+	// forwarding events are mirrored into for reporting. If the store
+	// is disabled, then this is nil.
+	analytics *sqlstore.Store
+
 	htlcSwitch    *htlcSwitch
 	invoices      *invoiceRegistry
+	offers        *offerManager
 	breachArbiter *breachArbiter
 
+	// onionMessages routes messages to nodes we aren't connected to, and
+	// relays the onion messages of other nodes.
+	onionMessages *onionMessenger
+
+	// chanHistory records the notable events within the lifetime of each
+	// channel.
+	chanHistory *channelHistory
+
+	// stateLog logs each transition of the commitment state machine of
+	// every channel.
+	stateLog *stateLog
+
+	// peerSettings holds the settings the operator has set for individual
+	// peers.
+	peerSettings *peerSettingsStore
+
+	// outbox persists notifications of notable events until they've been
+	// delivered to each subscriber.
+	outbox *notificationOutbox
+
+	// consolidator sweeps the wallet's small outputs into larger ones
+	// while fee rates are low.
+	consolidator *utxoConsolidator
+
+	// balanceHistory periodically records snapshots of the node's
+	// balances.
+	balanceHistory *balanceHistory
+
+	// invoiceCollector periodically archives the invoices which expired
+	// without being settled.
+	invoiceCollector *channeldb.InvoiceCollector
+
+	// diskMonitor rejects non-critical database writes while the volume
+	// of the channel database is low on free space.
+	diskMonitor *diskMonitor
+
+	// advisor recommends rebalances, closes and opens of channels based
+	// on their balances and forwarding history.
+	advisor *channelAdvisor
+
+	// customMessages allows plugins to exchange messages of experimental
+	// types with peers.
+	customMessages *customMessageRegistry
+
+	// peerStorage exchanges backups of our channel state with our peers.
+	peerStorage *peerStorage
+
+	// watchtower stores the justice transactions of peers using us as a
+	// watchtower, and broadcasts them should their channels be breached.
+	watchtower *watchtowerServer
+
+	// chanBackup keeps the static backup of our channels on disk up to
+	// date.
+	chanBackup *chanBackupFile
+
+	// chanRecovery recovers the funds of channels restored from a static
+	// backup.
+	chanRecovery *chanRecovery
+
+	// peerScores tracks the connection quality of each of our peers.
+	peerScores *peerScorer
+
+	// paymentTelemetry aggregates the outcomes of the payments we send.
+	// It's nil unless payment telemetry has been enabled.
+	paymentTelemetry *paymentTelemetry
+
+	// asyncPayments holds HTLCs destined to offline recipients which have
+	// asked us to, until they return.
+	asyncPayments *asyncPaymentHolder
+
+	// jamming reserves a portion of the resources of our channels for
+	// endorsed HTLCs from upstream peers with a good reputation. It's nil
+	// if jamming mitigation is disabled.
+	jamming *jammingMitigator
+
+	// probes tracks the HTLCs sent to us carrying an unknown payment
+	// hash, masking the answers to repeated probes of the same amount.
+	probes *probeDetector
+
+	// routeHints selects which of our unannounced channels are hinted at
+	// by the payment requests we create.
+	routeHints *routeHintSelector
+
+	// virtualChain is the virtual chain we run on in simulation mode,
+	// which blocks are mined on, and time advanced, over RPC. It's nil
+	// outside of simulation mode.
+	virtualChain virtualchain.Backend
+
 	chanRouter *routing.ChannelRouter
 
 	utxoNursery *utxoNursery
@@ -92,7 +189,7 @@ type server struct {
 // passed listener address.
 func newServer(listenAddrs []string, notifier chainntnfs.ChainNotifier,
 	bio lnwallet.BlockChainIO, wallet *lnwallet.LightningWallet,
-	chanDB *channeldb.DB) (*server, error) {
+	chanDB *channeldb.DB, analytics *sqlstore.Store) (*server, error) {
 
 	privKey, err := wallet.GetIdentitykey()
 	if err != nil {
@@ -108,18 +205,45 @@ func newServer(listenAddrs []string, notifier chainntnfs.ChainNotifier,
 		}
 	}
 
+	outbox := newNotificationOutbox(chanDB, cfg.Outbox.Webhooks)
+	chanBackup := newChanBackupFile(chanDB, privKey, cfg.BackupFile,
+		cfg.BackupUpload.targets)
+
+	peerSettings, err := newPeerSettingsStore(chanDB)
+	if err != nil {
+		return nil, err
+	}
+
 	serializedPubKey := privKey.PubKey().SerializeCompressed()
 	s := &server{
 		lnwallet:      wallet,
 		bio:           bio,
 		chainNotifier: notifier,
 		chanDB:        chanDB,
-
-		invoices:    newInvoiceRegistry(chanDB),
+		analytics:     analytics,
+		outbox:        outbox,
+		consolidator:  newUtxoConsolidator(&cfg.Consolidation, wallet),
+		balanceHistory: newBalanceHistory(cfg.BalanceSnapshotInterval,
+			chanDB, wallet),
+		diskMonitor: newDiskMonitor(cfg.ChanDBDir,
+			cfg.LowFreeSpace*bytesPerMegabyte, chanDB, outbox),
+		invoiceCollector: channeldb.NewInvoiceCollector(chanDB,
+			cfg.InvoiceArchiveInterval),
+
+		invoices:    newInvoiceRegistry(chanDB, analytics, outbox),
 		utxoNursery: newUtxoNursery(chanDB, notifier, wallet),
-		htlcSwitch:  newHtlcSwitch(),
+		htlcSwitch:  newHtlcSwitch(chanDB, analytics),
+		chanHistory: newChannelHistory(chanDB,
+			btcutil.Amount(cfg.ChanHistoryThresh)),
+		stateLog:     newStateLog(chanDB),
+		peerSettings: peerSettings,
 
 		identityPriv: privKey,
+		peerStorage:  newPeerStorage(chanDB, privKey),
+		chanBackup:   chanBackup,
+		peerScores:   newPeerScorer(),
+		probes:       newProbeDetector(&cfg.Probing),
+		routeHints:   newRouteHintSelector(&cfg.RouteHints),
 
 		// TODO(roasbeef): derive proper onion key based on rotation
 		// schedule
@@ -138,12 +262,16 @@ func newServer(listenAddrs []string, notifier chainntnfs.ChainNotifier,
 		sendRequests:      make(chan *sendReq),
 
 		globalFeatures: globalFeatures,
-		localFeatures:  localFeatures,
+		localFeatures:  localFeatures.Copy(),
 
 		queries: make(chan interface{}),
 		quit:    make(chan struct{}),
 	}
 
+	// The switch enforces the limits the operator has set on the HTLCs
+	// forwarded to particular peers.
+	s.htlcSwitch.peerSettings = s.peerSettings
+
 	// If the debug HTLC flag is on, then we invoice a "master debug"
 	// invoice which all outgoing payments will be sent and all incoming
 	// HTLCs with the debug R-Hash immediately settled.
@@ -172,30 +300,161 @@ func newServer(listenAddrs []string, notifier chainntnfs.ChainNotifier,
 		return nil, err
 	}
 
+	// If enabled, routes through our more reliable peers are favored.
+	var firstHopPenalty func(*btcec.PublicKey) float64
+	if cfg.PreferReliablePeers {
+		firstHopPenalty = s.peerScores.firstHopPenalty
+	}
+
+	var reportPaymentOutcome func(*routing.PaymentOutcome)
+	if cfg.PaymentTelemetry {
+		s.paymentTelemetry = newPaymentTelemetry()
+		reportPaymentOutcome = s.paymentTelemetry.record
+	}
+
 	s.chanRouter, err = routing.New(routing.Config{
-		Graph:        chanGraph,
-		Chain:        bio,
-		Notifier:     notifier,
-		Broadcast:    s.broadcastMessage,
-		SendMessages: s.sendToPeer,
+		Graph:              chanGraph,
+		Chain:              bio,
+		Notifier:           notifier,
+		Broadcast:          s.broadcastMessage,
+		SendMessages:       s.sendToPeer,
+		NumActiveSyncers:   cfg.NumGraphSyncPeers,
+		GossipSyncMode:     peerSettings.gossipSyncMode,
+		PathFindingTimeout: cfg.PathFindingTimeout,
+		GossipStoreAge:     cfg.GossipStoreAge,
+		GossipStoreSize:    cfg.GossipStoreSize,
+		FirstHopPenalty:    firstHopPenalty,
+		FinalCltvPadding:   cfg.Probing.FinalCltvPadding,
 		SendToSwitch: func(firstHop *btcec.PublicKey,
-			htlcAdd *lnwire.UpdateAddHTLC) ([32]byte, error) {
+			htlcAdd *lnwire.UpdateAddHTLC,
+			shardOnions [][]byte) ([32]byte, error) {
 
 			firstHopPub := firstHop.SerializeCompressed()
 			destInterface := chainhash.Hash(sha256.Sum256(firstHopPub))
 
 			return s.htlcSwitch.SendHTLC(&htlcPacket{
-				dest: destInterface,
-				msg:  htlcAdd,
+				dest:        destInterface,
+				msg:         htlcAdd,
+				amt:         htlcAdd.Amount,
+				shardOnions: shardOnions,
 			})
 		},
+		RecordPaymentAttempt: chanDB.AddPaymentAttempt,
+		ReportPaymentOutcome: reportPaymentOutcome,
 	})
 	if err != nil {
 		return nil, err
 	}
 
+	// Invoice requests for offers of nodes we aren't connected to, along
+	// with their replies, are exchanged over onion messages, which we
+	// relay for peers supporting them.
+	s.onionMessages = newOnionMessenger(s.identityPriv, s.sphinx,
+		chanGraph, s.findPeer, s.findPeerByOnionHop,
+		func(origin *btcec.PublicKey, msg lnwire.Message) {
+			s.offers.handleOnionMessage(origin, msg)
+		},
+	)
+	s.offers = newOfferManager(chanDB, s.invoices,
+		s.onionMessages.sendMessage)
+	err = s.localFeatures.AddFeature(onionMessagesFeature,
+		onionMessagesFeatureIndex, lnwire.OptionalFlag)
+	if err != nil {
+		return nil, err
+	}
+
+	// The experimental features plugins may tie custom messages to are
+	// advertised alongside our own local features.
+	s.customMessages = newCustomMessageRegistry(cfg.CustomMessages.Features,
+		s.sendToPeer, s.peerSharedFeatures)
+	if err := s.customMessages.addFeatures(s.localFeatures); err != nil {
+		return nil, err
+	}
+
+	// HTLCs destined to offline recipients of asynchronous payments are
+	// held by the switch, rather than failed.
+	s.asyncPayments = newAsyncPaymentHolder(&cfg.Async,
+		func() (int32, error) {
+			_, height, err := bio.GetBestBlock()
+			return height, err
+		},
+		s.htlcSwitch.resubmitHeld, s.htlcSwitch.failHeld,
+	)
+	s.htlcSwitch.asyncHolder = s.asyncPayments
+	if cfg.Async.enabled() {
+		err := s.localFeatures.AddFeature(asyncPaymentsFeature,
+			asyncPaymentsFeatureIndex, lnwire.OptionalFlag)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Single funder channels opened with peers supporting them carry
+	// anchor outputs within their commitment transactions.
+	err = s.localFeatures.AddFeature(anchorCommitmentsFeature,
+		anchorCommitmentsFeatureIndex, lnwire.OptionalFlag)
+	if err != nil {
+		return nil, err
+	}
+
+	// Upon reconnecting to peers supporting it, we exchange our views of
+	// each channel we share to detect the loss of channel state.
+	err = s.localFeatures.AddFeature(dataLossProtectFeature,
+		dataLossProtectFeatureIndex, lnwire.OptionalFlag)
+	if err != nil {
+		return nil, err
+	}
+
+	// Peers supporting it are able to follow us, and we them, through
+	// the rotation of an identity key.
+	err = s.localFeatures.AddFeature(identityRotationFeature,
+		identityRotationFeatureIndex, lnwire.OptionalFlag)
+	if err != nil {
+		return nil, err
+	}
+
+	// Unless disabled, we signal the endorsement of HTLCs to peers
+	// supporting it, and reserve a portion of our channels for endorsed
+	// HTLCs from upstream peers with a good reputation.
+	if !cfg.Jamming.Disable {
+		s.jamming = newJammingMitigator(&cfg.Jamming)
+		if err := s.jamming.loadReputations(chanDB); err != nil {
+			return nil, err
+		}
+		s.htlcSwitch.jamming = s.jamming
+
+		err = s.localFeatures.AddFeature(htlcEndorsementFeature,
+			htlcEndorsementFeatureIndex, lnwire.OptionalFlag)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// If we act as a watchtower, peers supporting it may upload the
+	// justice transactions of their channels to us.
+	s.watchtower = newWatchtowerServer(&cfg.Watchtower, chanDB, wallet,
+		notifier, bio)
+	if cfg.Watchtower.Active {
+		err = s.localFeatures.AddFeature(watchtowerFeature,
+			watchtowerFeatureIndex, lnwire.OptionalFlag)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	s.advisor = newChannelAdvisor(&cfg.Advisor, chanDB,
+		s.advisorCloseChannel, s.advisorOpenChannel)
+
 	s.rpcServer = newRPCServer(s)
-	s.breachArbiter = newBreachArbiter(wallet, chanDB, notifier, s.htlcSwitch)
+	s.chanRecovery = newChanRecovery(s)
+	s.rpcServer.quotas, err = newQuotaEnforcer(chanDB, cfg.quotas,
+		filepath.Join(cfg.DataDir, "credentials"),
+		cfg.AllowUncredentialed)
+	if err != nil {
+		return nil, err
+	}
+	s.breachArbiter = newBreachArbiter(wallet, chanDB, notifier,
+		s.htlcSwitch, s.outbox, s.chanHistory)
 
 	s.fundingMgr, err = newFundingManager(fundingConfig{
 		IDKey:    s.identityPriv.PubKey(),
@@ -209,6 +468,12 @@ func newServer(listenAddrs []string, notifier chainntnfs.ChainNotifier,
 		SendToPeer:  s.sendToPeer,
 		FindPeer:    s.findPeer,
 		FindChannel: s.rpcServer.fetchActiveChannel,
+
+		UpdateChanBackup: s.chanBackup.requestUpdate,
+
+		AddAliasEdge: s.chanRouter.AddAliasEdge,
+
+		PeerSettings: s.peerSettings.get,
 	})
 	if err != nil {
 		return nil, err
@@ -284,6 +549,12 @@ func (s *server) Start() error {
 		return err
 	}
 
+	// The invoices added or settled before the analytics store was
+	// enabled are mirrored into it, so its reports are complete.
+	if err := s.invoices.backfillAnalytics(); err != nil {
+		srvrLog.Errorf("unable to backfill analytics store: %v", err)
+	}
+
 	if err := s.rpcServer.Start(); err != nil {
 		return err
 	}
@@ -302,6 +573,33 @@ func (s *server) Start() error {
 	if err := s.chanRouter.Start(); err != nil {
 		return err
 	}
+	if err := s.advisor.Start(); err != nil {
+		return err
+	}
+	if err := s.outbox.Start(); err != nil {
+		return err
+	}
+	if err := s.consolidator.Start(); err != nil {
+		return err
+	}
+	if err := s.balanceHistory.Start(); err != nil {
+		return err
+	}
+	if err := s.invoiceCollector.Start(); err != nil {
+		return err
+	}
+	if err := s.diskMonitor.Start(); err != nil {
+		return err
+	}
+	if err := s.chanBackup.Start(); err != nil {
+		return err
+	}
+	if err := s.asyncPayments.Start(); err != nil {
+		return err
+	}
+	if err := s.watchtower.Start(); err != nil {
+		return err
+	}
 
 	s.wg.Add(1)
 	go s.queryHandler()
@@ -326,6 +624,15 @@ func (s *server) Stop() error {
 	s.htlcSwitch.Stop()
 	s.utxoNursery.Stop()
 	s.breachArbiter.Stop()
+	s.advisor.Stop()
+	s.outbox.Stop()
+	s.consolidator.Stop()
+	s.balanceHistory.Stop()
+	s.invoiceCollector.Stop()
+	s.diskMonitor.Stop()
+	s.chanBackup.Stop()
+	s.asyncPayments.Stop()
+	s.watchtower.Stop()
 
 	s.lnwallet.Shutdown()
 
@@ -432,6 +739,46 @@ func (s *server) findPeer(peerKey *btcec.PublicKey) (*peer, error) {
 	return peer, nil
 }
 
+// findOpenChanPeer returns the connected peer targeted by a request to open a
+// channel, which identifies it either by peer ID or by public key.
+func (s *server) findOpenChanPeer(peerID int32,
+	nodeKey *btcec.PublicKey) (*peer, error) {
+
+	// If the user is targeting the peer by public key, then we'll need to
+	// convert that into a string for our map. Otherwise, we expect them to
+	// target by peer ID instead.
+	var pubKeyBytes []byte
+	if nodeKey != nil {
+		pubKeyBytes = nodeKey.SerializeCompressed()
+	}
+
+	s.peersMtx.RLock()
+	defer s.peersMtx.RUnlock()
+
+	if peer, ok := s.peersByID[peerID]; ok {
+		return peer, nil
+	}
+	if peer, ok := s.peersByPub[string(pubKeyBytes)]; ok {
+		return peer, nil
+	}
+
+	return nil, fmt.Errorf("unable to find peer nodeID(%x), peerID(%v)",
+		pubKeyBytes, peerID)
+}
+
+// peerSharedFeatures returns the local features shared with the connected
+// peer identified by the passed public key.
+func (s *server) peerSharedFeatures(
+	peerKey *btcec.PublicKey) (*lnwire.SharedFeatures, error) {
+
+	peer, err := s.findPeer(peerKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return peer.localSharedFeatures, nil
+}
+
 // peerConnected is a function that handles initialization a newly connected
 // peer by adding it to the server's global list of all active peers, and
 // starting all the goroutines the peer needs to function properly.
@@ -550,6 +897,8 @@ func (s *server) addPeer(p *peer) {
 	s.peersByPub[string(p.addr.IdentityKey.SerializeCompressed())] = p
 	s.peersMtx.Unlock()
 
+	s.peerScores.peerConnected(p.addr.IdentityKey)
+
 	// Once the peer has been added to our indexes, send a message to the
 	// channel router so we can synchronize our view of the channel graph
 	// with this new peer.
@@ -568,6 +917,8 @@ func (s *server) removePeer(p *peer) {
 		return
 	}
 
+	s.peerScores.peerDisconnected(p.addr.IdentityKey)
+
 	// As the peer is now finished, ensure that the TCP connection is
 	// closed and all of its related goroutines have exited.
 	if err := p.Stop(); err != nil {
@@ -581,6 +932,10 @@ func (s *server) removePeer(p *peer) {
 
 	delete(s.peersByID, p.id)
 	delete(s.peersByPub, string(p.addr.IdentityKey.SerializeCompressed()))
+
+	// Let the channel router know the peer is gone, so another peer can
+	// take its place if it was actively syncing the channel graph.
+	go s.chanRouter.PruneSyncState(p.addr.IdentityKey)
 }
 
 // connectPeerMsg is a message requesting the server to open a connection to a
@@ -800,31 +1155,12 @@ func (s *server) handleConnectPeer(msg *connectPeerMsg) {
 // request to the funding manager allowing it to initiate the channel funding
 // workflow.
 func (s *server) handleOpenChanReq(req *openChanReq) {
-	var (
-		targetPeer  *peer
-		pubKeyBytes []byte
-	)
-
-	// If the user is targeting the peer by public key, then we'll need to
-	// convert that into a string for our map. Otherwise, we expect them to
-	// target by peer ID instead.
-	if req.targetPubkey != nil {
-		pubKeyBytes = req.targetPubkey.SerializeCompressed()
-	}
-
 	// First attempt to locate the target peer to open a channel with, if
 	// we're unable to locate the peer then this request will fail.
-	s.peersMtx.RLock()
-	if peer, ok := s.peersByID[req.targetPeerID]; ok {
-		targetPeer = peer
-	} else if peer, ok := s.peersByPub[string(pubKeyBytes)]; ok {
-		targetPeer = peer
-	}
-	s.peersMtx.RUnlock()
-
-	if targetPeer == nil {
-		req.err <- fmt.Errorf("unable to find peer nodeID(%x), "+
-			"peerID(%v)", pubKeyBytes, req.targetPeerID)
+	targetPeer, err := s.findOpenChanPeer(req.targetPeerID,
+		req.targetPubkey)
+	if err != nil {
+		req.err <- err
 		return
 	}
 
@@ -854,22 +1190,25 @@ func (s *server) ConnectToPeer(addr *lnwire.NetAddress,
 }
 
 // OpenChannel sends a request to the server to open a channel to the specified
-// peer identified by ID with the passed channel funding paramters.
+// peer identified by ID with the passed channel funding paramters. A non-zero
+// remoteAmt requests a dual funded channel, with the remote peer contributing
+// that amount.
 func (s *server) OpenChannel(peerID int32, nodeKey *btcec.PublicKey,
-	localAmt, pushAmt btcutil.Amount,
+	localAmt, remoteAmt, pushAmt btcutil.Amount,
 	numConfs uint32) (chan *lnrpc.OpenStatusUpdate, chan error) {
 
 	errChan := make(chan error, 1)
 	updateChan := make(chan *lnrpc.OpenStatusUpdate, 1)
 
 	req := &openChanReq{
-		targetPeerID:    peerID,
-		targetPubkey:    nodeKey,
-		localFundingAmt: localAmt,
-		pushAmt:         pushAmt,
-		numConfs:        numConfs,
-		updates:         updateChan,
-		err:             errChan,
+		targetPeerID:     peerID,
+		targetPubkey:     nodeKey,
+		localFundingAmt:  localAmt,
+		remoteFundingAmt: remoteAmt,
+		pushAmt:          pushAmt,
+		numConfs:         numConfs,
+		updates:          updateChan,
+		err:              errChan,
 	}
 
 	s.queries <- req
@@ -156,6 +156,18 @@ func (store *RevocationStore) AddNextEntry(hash *chainhash.Hash) error {
 	return nil
 }
 
+// Height returns the number of hashes which have been added to the store.
+func (store *RevocationStore) Height() uint64 {
+	return uint64(startIndex - store.index)
+}
+
+// NumBuckets returns the number of buckets currently in use by the store.
+// As the store only retains O(log N) hashes, this is at most logarithmic in
+// the store's height.
+func (store *RevocationStore) NumBuckets() uint8 {
+	return store.lenBuckets
+}
+
 // Encode writes a binary serialization of the shachain elements currently
 // saved by implementation of shachain.Store to the passed io.Writer.
 //
@@ -459,6 +459,14 @@ func TestShaChainStore(t *testing.T) {
 		t.Fatal(err)
 	}
 
+	if newReceiver.Height() != 10000 {
+		t.Fatalf("expected height of 10000, got %v", newReceiver.Height())
+	}
+	if newReceiver.NumBuckets() != receiver.NumBuckets() {
+		t.Fatalf("bucket count mismatch: expected %v, got %v",
+			receiver.NumBuckets(), newReceiver.NumBuckets())
+	}
+
 	for n := uint64(0); n < 10000; n++ {
 		if _, err := newReceiver.LookUp(n); err != nil {
 			t.Fatal(err)
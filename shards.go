@@ -0,0 +1,283 @@
+package main
+
+import (
+	"errors"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+// shardHoldTimeout is the period the shards of a payment which was split
+// across several of our channels with the sender are held for, awaiting the
+// remaining shards, before they're all cancelled.
+const shardHoldTimeout = time.Minute
+
+// errDuplicateShard is returned when a shard of a payment arrives over a
+// channel which already holds a shard of the same payment.
+var errDuplicateShard = errors.New("payment already has a shard held " +
+	"within the channel")
+
+// paymentShard is a single HTLC of a payment which was split across several
+// links.
+type paymentShard struct {
+	link *link
+	amt  btcutil.Amount
+}
+
+// sendShards attempts to split the passed locally initiated payment across
+// the links of the destination's interface, with the largest shards sent
+// over the links with the most bandwidth. If the links lack the aggregate
+// bandwidth for the payment, or the payment requires more shards than it has
+// onion blobs for, then false is returned and nothing is sent. Otherwise,
+// the preimage and error of the payment are delivered once all shards have
+// been resolved.
+func (h *htlcSwitch) sendShards(htlcPkt *htlcPacket, links []*link) bool {
+	wireMsg := htlcPkt.msg.(*lnwire.UpdateAddHTLC)
+
+	sorted := make([]*link, len(links))
+	copy(sorted, links)
+	sort.Sort(linksByBandwidth(sorted))
+
+	var shards []*paymentShard
+	remaining := wireMsg.Amount
+	for _, l := range sorted {
+		if remaining == 0 {
+			break
+		}
+
+		bandwidth := btcutil.Amount(atomic.LoadInt64(&l.availableBandwidth))
+		if bandwidth <= 0 {
+			continue
+		}
+		if bandwidth > remaining {
+			bandwidth = remaining
+		}
+
+		shards = append(shards, &paymentShard{link: l, amt: bandwidth})
+		remaining -= bandwidth
+	}
+	if remaining != 0 || len(shards) > len(htlcPkt.shardOnions)+1 {
+		return false
+	}
+
+	hswcLog.Debugf("Splitting payment %x of %v into %v shards",
+		wireMsg.PaymentHash[:], wireMsg.Amount, len(shards))
+
+	shardPkts := make([]*htlcPacket, len(shards))
+	for i, shard := range shards {
+		shardAdd := *wireMsg
+		shardAdd.Amount = shard.amt
+		if i != 0 {
+			copy(shardAdd.OnionBlob[:], htlcPkt.shardOnions[i-1])
+		}
+
+		shardPkt := &htlcPacket{
+			dest:     htlcPkt.dest,
+			msg:      &shardAdd,
+			amt:      shard.amt,
+			preImage: make(chan [32]byte, 1),
+			err:      make(chan error, 1),
+		}
+		shardPkts[i] = shardPkt
+
+		go func(l *link) {
+			l.linkChan <- shardPkt
+		}(shard.link)
+
+		n := atomic.AddInt64(&shard.link.availableBandwidth,
+			-int64(shard.amt))
+		hswcLog.Tracef("Decrementing link %v bandwidth to %v",
+			shard.link.chanPoint, n)
+	}
+
+	// As the recipient only settles the shards once it holds all of
+	// them, a preimage from any shard means the payment succeeded.
+	go func() {
+		var (
+			preImage [32]byte
+			err      error
+		)
+		for _, shardPkt := range shardPkts {
+			shardPreImage := <-shardPkt.preImage
+			shardErr := <-shardPkt.err
+			switch {
+			case shardErr == nil:
+				preImage = shardPreImage
+			case err == nil:
+				err = shardErr
+			}
+		}
+		if preImage != zeroBytes {
+			err = nil
+		}
+
+		htlcPkt.preImage <- preImage
+		htlcPkt.err <- err
+	}()
+
+	return true
+}
+
+// shardSet holds the shards of a payment to us which was split across
+// several of our channels with the sender.
+type shardSet struct {
+	invoice *channeldb.Invoice
+
+	// total is the sum of the held shards.
+	total btcutil.Amount
+
+	// shards maps the channel point of each channel a shard is held
+	// within to the shard's value.
+	shards map[wire.OutPoint]btcutil.Amount
+
+	// timer cancels the held shards once the shardHoldTimeout has passed.
+	timer *time.Timer
+}
+
+// holdShard adds an HTLC which has been locked in within the target channel,
+// and which pays part of the passed invoice, to the shards held for the
+// payment. If the held shards now sum to the invoice's value, then they're
+// released and returned. The caller should then settle the invoice, and if
+// successful, settle the passed HTLC along with the returned shards through
+// settleShards, or otherwise fail them all through failShards. Until then,
+// the invoice's preimage isn't revealed. Otherwise the HTLC is held, until
+// either the remaining shards arrive or the shardHoldTimeout passes. If a
+// shard of the payment is already held within the channel, then
+// errDuplicateShard is returned and the HTLC should be cancelled.
+func (h *htlcSwitch) holdShard(payHash chainhash.Hash,
+	invoice *channeldb.Invoice, chanPoint wire.OutPoint,
+	amt btcutil.Amount) (*shardSet, error) {
+
+	h.heldShardsMtx.Lock()
+	defer h.heldShardsMtx.Unlock()
+
+	set, ok := h.heldShards[payHash]
+	if !ok {
+		set = &shardSet{
+			invoice: invoice,
+			shards:  make(map[wire.OutPoint]btcutil.Amount),
+		}
+		set.timer = time.AfterFunc(shardHoldTimeout, func() {
+			h.cancelShards(payHash, set)
+		})
+		h.heldShards[payHash] = set
+	}
+	if _, ok := set.shards[chanPoint]; ok {
+		return nil, errDuplicateShard
+	}
+
+	set.total += amt
+	if set.total < invoice.Terms.Value {
+		hswcLog.Debugf("Holding shard of %v for payment %x within "+
+			"ChannelPoint(%v), %v of %v received", amt, payHash[:],
+			chanPoint, set.total, invoice.Terms.Value)
+
+		set.shards[chanPoint] = amt
+		return nil, nil
+	}
+
+	hswcLog.Infof("Received all shards of payment %x, releasing %v "+
+		"held shards", payHash[:], len(set.shards))
+
+	set.timer.Stop()
+	delete(h.heldShards, payHash)
+
+	return set, nil
+}
+
+// settleShards settles the shards of a payment released by holdShard through
+// their links, revealing the preimage of the invoice they pay.
+func (h *htlcSwitch) settleShards(payHash chainhash.Hash, set *shardSet) {
+	preimage := set.invoice.Terms.PaymentPreimage
+	for shardChan, shardAmt := range set.shards {
+		pkt := &htlcPacket{
+			msg: &lnwire.UpdateFufillHTLC{
+				PaymentPreimage: preimage,
+			},
+			payHash: payHash,
+			err:     make(chan error, 1),
+		}
+		go h.resolveHeldShard(shardChan, shardAmt, pkt)
+	}
+}
+
+// failShards fails the shards of a payment released by holdShard, or which
+// timed out, through their links with the passed code.
+func (h *htlcSwitch) failShards(payHash chainhash.Hash, set *shardSet,
+	code lnwire.FailCode) {
+
+	for shardChan := range set.shards {
+		pkt := &htlcPacket{
+			msg: &lnwire.UpdateFailHTLC{
+				Reason: []byte{uint8(code)},
+			},
+			payHash: payHash,
+			err:     make(chan error, 1),
+		}
+		go h.resolveHeldShard(shardChan, 0, pkt)
+	}
+}
+
+// cancelShards cancels the shards held for the target payment, if they're
+// still held once the shardHoldTimeout has passed.
+func (h *htlcSwitch) cancelShards(payHash chainhash.Hash, set *shardSet) {
+	h.heldShardsMtx.Lock()
+	if h.heldShards[payHash] != set {
+		h.heldShardsMtx.Unlock()
+		return
+	}
+	delete(h.heldShards, payHash)
+	h.heldShardsMtx.Unlock()
+
+	hswcLog.Infof("Cancelling %v held shards of payment %x, received %v "+
+		"of %v", len(set.shards), payHash[:], set.total,
+		set.invoice.Terms.Value)
+
+	h.failShards(payHash, set, lnwire.IncorrectValue)
+}
+
+// resolveHeldShard sends the passed settle or fail packet to the link of the
+// channel a shard is held within. Settling the shard increases the link's
+// bandwidth by the passed amount.
+//
+// TODO(roasbeef): if the link has gone offline, the shard remains held until
+// it times out on-chain.
+func (h *htlcSwitch) resolveHeldShard(chanPoint wire.OutPoint,
+	amt btcutil.Amount, pkt *htlcPacket) {
+
+	h.chanIndexMtx.RLock()
+	l, ok := h.chanIndex[chanPoint]
+	h.chanIndexMtx.RUnlock()
+	if !ok {
+		hswcLog.Errorf("unable to resolve shard of %x, link for "+
+			"ChannelPoint(%v) not found", pkt.payHash[:], chanPoint)
+		return
+	}
+
+	select {
+	case l.linkChan <- pkt:
+	case <-h.quit:
+		return
+	}
+
+	if amt != 0 {
+		h.UpdateLink(&chanPoint, amt)
+	}
+}
+
+// linksByBandwidth implements sort.Interface to order links from the most to
+// the least available bandwidth.
+type linksByBandwidth []*link
+
+func (l linksByBandwidth) Len() int      { return len(l) }
+func (l linksByBandwidth) Swap(i, j int) { l[i], l[j] = l[j], l[i] }
+func (l linksByBandwidth) Less(i, j int) bool {
+	return atomic.LoadInt64(&l[i].availableBandwidth) >
+		atomic.LoadInt64(&l[j].availableBandwidth)
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/roasbeef/btcd/txscript"
+	"github.com/roasbeef/btcd/wire"
+)
+
+// auditSigner is an implementation of the lnwallet.Signer interface which
+// records each signature produced by the wrapped signer within the signing
+// audit log of the database. A signature is only handed back to the caller
+// once it has been recorded, so the log is a complete account of everything
+// the node has signed.
+//
+// TODO(roasbeef): also record signatures over gossip and messages once the
+// Signer is able to produce them.
+type auditSigner struct {
+	signer lnwallet.Signer
+	db     *channeldb.DB
+}
+
+// A compile time check to ensure auditSigner implements the lnwallet.Signer
+// interface.
+var _ lnwallet.Signer = (*auditSigner)(nil)
+
+// newAuditSigner creates a new auditSigner which records the signatures
+// produced by signer within db.
+func newAuditSigner(signer lnwallet.Signer, db *channeldb.DB) *auditSigner {
+	return &auditSigner{
+		signer: signer,
+		db:     db,
+	}
+}
+
+// SignOutputRaw generates a signature for the passed transaction according
+// to the data within the passed SignDescriptor, recording it within the
+// audit log.
+//
+// This is part of the lnwallet.Signer interface.
+func (a *auditSigner) SignOutputRaw(tx *wire.MsgTx,
+	signDesc *lnwallet.SignDescriptor) ([]byte, error) {
+
+	sig, err := a.signer.SignOutputRaw(tx, signDesc)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.record(tx, signDesc); err != nil {
+		return nil, err
+	}
+
+	return sig, nil
+}
+
+// ComputeInputScript generates a complete input script for the passed
+// transaction according to the data within the passed SignDescriptor,
+// recording the signature within the audit log.
+//
+// This is part of the lnwallet.Signer interface.
+func (a *auditSigner) ComputeInputScript(tx *wire.MsgTx,
+	signDesc *lnwallet.SignDescriptor) (*lnwallet.InputScript, error) {
+
+	inputScript, err := a.signer.ComputeInputScript(tx, signDesc)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := a.record(tx, signDesc); err != nil {
+		return nil, err
+	}
+
+	return inputScript, nil
+}
+
+// record appends an event describing the signature over the passed input to
+// the audit log.
+func (a *auditSigner) record(tx *wire.MsgTx,
+	signDesc *lnwallet.SignDescriptor) error {
+
+	event := &channeldb.SigningEvent{
+		Type:      classifySignature(tx, signDesc),
+		Timestamp: time.Now(),
+		PubKey:    signDesc.PubKey,
+		DataHash:  signedDataHash(tx, signDesc),
+	}
+	if signDesc.InputIndex < len(tx.TxIn) {
+		event.Outpoint = tx.TxIn[signDesc.InputIndex].PreviousOutPoint
+	}
+
+	if err := a.db.AddSigningEvent(event); err != nil {
+		ltndLog.Errorf("Unable to record %v signature for %v: %v",
+			event.Type, event.Outpoint, err)
+		return err
+	}
+
+	return nil
+}
+
+// classifySignature determines the purpose of a signature over the passed
+// input. Spends of a 2-of-2 funding output are either commitment
+// transactions, recognizable by the state hint within their lock time, or
+// cooperative closures. Spends of any other witness script are sweeps of
+// commitment outputs, while the remainder spend the wallet's own outputs.
+func classifySignature(tx *wire.MsgTx,
+	signDesc *lnwallet.SignDescriptor) channeldb.SigningEventType {
+
+	switch {
+	case len(signDesc.WitnessScript) == 0:
+		return channeldb.WalletSigEvent
+
+	case txscript.GetScriptClass(signDesc.WitnessScript) !=
+		txscript.MultiSigTy:
+		return channeldb.SweepSigEvent
+
+	case tx.LockTime&^0xFFFFFF == lnwallet.TimelockShift:
+		return channeldb.CommitmentSigEvent
+
+	default:
+		return channeldb.CloseSigEvent
+	}
+}
+
+// signedDataHash returns the hash of the data signed for the passed input.
+// For witness script spends this is the sighash itself. Otherwise, the
+// sighash is computed by the wallet, so the hash commits to the txid and
+// index of the signed input instead.
+func signedDataHash(tx *wire.MsgTx,
+	signDesc *lnwallet.SignDescriptor) [32]byte {
+
+	var dataHash [32]byte
+	if len(signDesc.WitnessScript) != 0 && signDesc.Output != nil {
+		sigHashes := signDesc.SigHashes
+		if sigHashes == nil {
+			sigHashes = txscript.NewTxSigHashes(tx)
+		}
+
+		sigHash, err := txscript.CalcWitnessSigHash(
+			signDesc.WitnessScript, sigHashes, signDesc.HashType,
+			tx, signDesc.InputIndex, signDesc.Output.Value,
+		)
+		if err == nil {
+			copy(dataHash[:], sigHash)
+			return dataHash
+		}
+	}
+
+	var index [4]byte
+	binary.BigEndian.PutUint32(index[:], uint32(signDesc.InputIndex))
+
+	txid := tx.TxHash()
+	h := sha256.New()
+	h.Write(txid[:])
+	h.Write(index[:])
+	copy(dataHash[:], h.Sum(nil))
+
+	return dataHash
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/txscript"
+	"github.com/roasbeef/btcd/wire"
+)
+
+func TestClassifySignature(t *testing.T) {
+	priv, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+	pubKey := priv.PubKey().SerializeCompressed()
+
+	fundingScript, err := txscript.NewScriptBuilder().
+		AddOp(txscript.OP_2).AddData(pubKey).AddData(pubKey).
+		AddOp(txscript.OP_2).AddOp(txscript.OP_CHECKMULTISIG).Script()
+	if err != nil {
+		t.Fatalf("unable to create funding script: %v", err)
+	}
+	sweepScript, err := txscript.NewScriptBuilder().
+		AddData(pubKey).AddOp(txscript.OP_CHECKSIG).Script()
+	if err != nil {
+		t.Fatalf("unable to create sweep script: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		lockTime      uint32
+		witnessScript []byte
+		expected      channeldb.SigningEventType
+	}{
+		{
+			name:          "commitment",
+			lockTime:      lnwallet.TimelockShift | 42,
+			witnessScript: fundingScript,
+			expected:      channeldb.CommitmentSigEvent,
+		},
+		{
+			name:          "close",
+			witnessScript: fundingScript,
+			expected:      channeldb.CloseSigEvent,
+		},
+		{
+			name:          "sweep",
+			lockTime:      lnwallet.TimelockShift | 42,
+			witnessScript: sweepScript,
+			expected:      channeldb.SweepSigEvent,
+		},
+		{
+			name:     "wallet",
+			expected: channeldb.WalletSigEvent,
+		},
+	}
+
+	for _, test := range tests {
+		tx := wire.NewMsgTx(2)
+		tx.LockTime = test.lockTime
+		tx.AddTxIn(&wire.TxIn{})
+		tx.AddTxOut(&wire.TxOut{Value: 1000})
+
+		signDesc := &lnwallet.SignDescriptor{
+			WitnessScript: test.witnessScript,
+			Output:        &wire.TxOut{Value: 2000},
+			HashType:      txscript.SigHashAll,
+		}
+		if kind := classifySignature(tx, signDesc); kind != test.expected {
+			t.Fatalf("%v: expected %v signature, got %v", test.name,
+				test.expected, kind)
+		}
+
+		// The hash of the signed data should commit to the
+		// transaction being signed.
+		dataHash := signedDataHash(tx, signDesc)
+		tx.LockTime++
+		if signedDataHash(tx, signDesc) == dataHash {
+			t.Fatalf("%v: data hash doesn't commit to transaction",
+				test.name)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/lightningnetwork/lnd/virtualchain"
+)
+
+// simulationConfig defines the options of the simulation mode, in which the
+// node runs on top of a virtual chain rather than a btcd node. Blocks are only
+// mined, and time only advances, when requested over RPC.
+type simulationConfig struct {
+	Enable bool `long:"enable" description:"Run on top of a virtual chain whose blocks, confirmations, and time are controlled over RPC, rather than connecting to btcd. Requires --simnet"`
+
+	Listen  string `long:"listen" description:"The address to serve the virtual chain on, so other simulated nodes may share it. The chain is served without authentication"`
+	Connect string `long:"connect" description:"The address of another simulated node serving the virtual chain to use, rather than hosting one"`
+
+	BlockInterval time.Duration `long:"blockinterval" description:"The time by which the virtual clock advances with each mined block"`
+}
+
+// validate checks the simulation options for consistency.
+func (c *simulationConfig) validate(simNet bool) error {
+	if !c.Enable {
+		if c.Listen != "" || c.Connect != "" {
+			return fmt.Errorf("simulation.listen and " +
+				"simulation.connect require simulation.enable")
+		}
+		return nil
+	}
+
+	if !simNet {
+		return fmt.Errorf("simulation.enable requires simnet")
+	}
+	if c.Listen != "" && c.Connect != "" {
+		return fmt.Errorf("simulation.listen and simulation.connect " +
+			"can't be used together")
+	}
+	if c.BlockInterval <= 0 {
+		return fmt.Errorf("simulation.blockinterval must be positive")
+	}
+
+	return nil
+}
+
+// newVirtualChain returns the virtual chain the node runs on in simulation
+// mode. Unless we're connecting to a chain served by another node, a new
+// chain is created, which is served to other nodes if requested. The chain
+// exists in memory only, so it's lost once the node hosting it exits.
+func newVirtualChain(c *simulationConfig) (virtualchain.Backend, error) {
+	if c.Connect != "" {
+		return virtualchain.Dial(c.Connect)
+	}
+
+	chain, err := virtualchain.NewChain(activeNetParams.Params, time.Now(),
+		c.BlockInterval)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.Listen != "" {
+		listener, err := net.Listen("tcp", c.Listen)
+		if err != nil {
+			return nil, err
+		}
+		if err := virtualchain.Serve(chain, listener); err != nil {
+			return nil, err
+		}
+
+		ltndLog.Infof("Serving virtual chain on %v", listener.Addr())
+	}
+
+	return chain, nil
+}
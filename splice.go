@@ -0,0 +1,476 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+// spliceTimeout is the time the remote peer is given to complete its side of
+// a splice we're negotiating, before we abandon the splice. Once our signature
+// for the channel's funding output has been sent, the splice is no longer
+// abandoned.
+const spliceTimeout = time.Minute
+
+// spliceReq is a local request to splice additional funds from the wallet
+// into an active channel.
+type spliceReq struct {
+	chanPoint *wire.OutPoint
+	amt       btcutil.Amount
+
+	// txid is sent upon with the txid of the splice transaction once it's
+	// been broadcast, and err is sent upon if the splice fails.
+	txid chan *chainhash.Hash
+	err  chan error
+}
+
+// pendingSplice is a splice of a channel which is being negotiated with the
+// remote peer.
+type pendingSplice struct {
+	channel *lnwallet.LightningChannel
+
+	// req is the local request to splice the channel if we initiated the
+	// splice, and nil otherwise.
+	req *spliceReq
+
+	// inputs are the wallet inputs funding the splice, and tx is the
+	// unsigned splice transaction. Both are only set if we initiated the
+	// splice.
+	inputs []*wire.TxIn
+	tx     *wire.MsgTx
+
+	// signed denotes that our signature for the channel's funding output
+	// has been sent to the remote peer, after which the splice must no
+	// longer be abandoned.
+	signed bool
+}
+
+// spliceIn splices the passed amount from the wallet into the target channel,
+// increasing its capacity. The txid of the splice transaction is returned once
+// both parties have signed, and it's been broadcast. The channel accepts no
+// updates until the splice transaction has received cfg.SpliceConfs
+// confirmations.
+func (p *peer) spliceIn(chanPoint *wire.OutPoint,
+	amt btcutil.Amount) (*chainhash.Hash, error) {
+
+	req := &spliceReq{
+		chanPoint: chanPoint,
+		amt:       amt,
+		txid:      make(chan *chainhash.Hash, 1),
+		err:       make(chan error, 1),
+	}
+
+	select {
+	case p.localSpliceReqs <- req:
+	case <-p.quit:
+		return nil, fmt.Errorf("peer is shutting down")
+	}
+
+	select {
+	case txid := <-req.txid:
+		return txid, nil
+	case err := <-req.err:
+		return nil, err
+	case <-p.quit:
+		return nil, fmt.Errorf("peer is shutting down")
+	}
+}
+
+// lookupChannel returns the active channel with the passed channel point, or
+// nil if there's no such channel.
+func (p *peer) lookupChannel(chanPoint *wire.OutPoint) *lnwallet.LightningChannel {
+	p.activeChanMtx.RLock()
+	defer p.activeChanMtx.RUnlock()
+
+	return p.activeChannels[*chanPoint]
+}
+
+// expireSplice abandons the passed splice if it's still being negotiated
+// once spliceTimeout has elapsed.
+func (p *peer) expireSplice(pending *pendingSplice) {
+	go func() {
+		select {
+		case <-time.After(spliceTimeout):
+		case <-p.quit:
+			return
+		}
+
+		select {
+		case p.expiredSplices <- pending:
+		case <-p.quit:
+		}
+	}()
+}
+
+// abandonSplice abandons the passed splice, which has yet to be signed,
+// unlocking any wallet inputs funding it. If we initiated the splice, then
+// the local request is failed with the passed error.
+func (p *peer) abandonSplice(pending *pendingSplice, err error) {
+	chanPoint := pending.channel.ChannelPoint()
+	peerLog.Warnf("Abandoning splice of ChannelPoint(%v): %v", chanPoint,
+		err)
+
+	delete(p.pendingSplices, *chanPoint)
+	if err := pending.channel.AbandonSplice(); err != nil {
+		peerLog.Errorf("unable to abandon splice of "+
+			"ChannelPoint(%v): %v", chanPoint, err)
+	}
+	if pending.inputs != nil {
+		p.server.lnwallet.UnlockSpliceInputs(pending.inputs)
+	}
+	if pending.req != nil {
+		pending.req.err <- err
+	}
+}
+
+// handleLocalSplice kicks off a splice of additional funds into a channel
+// requested by a local subsystem. Inputs funding the splice are selected from
+// the wallet, and a SpliceRequest is sent to the remote peer.
+func (p *peer) handleLocalSplice(req *spliceReq) {
+	channel := p.lookupChannel(req.chanPoint)
+	if channel == nil {
+		req.err <- fmt.Errorf("channel %v isn't active", req.chanPoint)
+		return
+	}
+	if _, ok := p.pendingSplices[*req.chanPoint]; ok {
+		req.err <- lnwallet.ErrSpliceInProgress
+		return
+	}
+
+	wallet := p.server.lnwallet
+	inputs, changeOutputs, err := wallet.SelectSpliceInputs(req.amt)
+	if err != nil {
+		req.err <- err
+		return
+	}
+	spliceTx, err := channel.ProposeSplice(req.amt, inputs, changeOutputs)
+	if err != nil {
+		wallet.UnlockSpliceInputs(inputs)
+		req.err <- err
+		return
+	}
+
+	pending := &pendingSplice{
+		channel: channel,
+		req:     req,
+		inputs:  inputs,
+		tx:      spliceTx,
+	}
+	p.pendingSplices[*req.chanPoint] = pending
+	p.expireSplice(pending)
+
+	peerLog.Infof("Requesting splice of %v into ChannelPoint(%v) with "+
+		"peerID(%v)", req.amt, req.chanPoint, p.id)
+
+	p.queueMsg(lnwire.NewSpliceRequest(*req.chanPoint, req.amt, inputs,
+		changeOutputs), nil)
+}
+
+// handleSpliceRequest responds to the remote peer's request to splice
+// additional funds into a channel. Once the peer's inputs have been verified
+// to fund the splice, we sign its version of the commitment transaction
+// spending the new funding output, and reply with a SpliceAccept.
+func (p *peer) handleSpliceRequest(msg *lnwire.SpliceRequest) {
+	chanPoint := &msg.ChannelPoint
+	channel := p.lookupChannel(chanPoint)
+	if channel == nil {
+		peerLog.Errorf("unable to splice ChannelPoint(%v): channel "+
+			"isn't active", chanPoint)
+		return
+	}
+	if _, ok := p.pendingSplices[*chanPoint]; ok {
+		peerLog.Errorf("unable to splice ChannelPoint(%v): %v",
+			chanPoint, lnwallet.ErrSpliceInProgress)
+		return
+	}
+
+	err := p.server.lnwallet.VerifySpliceFunds(msg.Inputs,
+		msg.ChangeOutputs, msg.Amount)
+	if err != nil {
+		peerLog.Errorf("unable to splice ChannelPoint(%v): %v",
+			chanPoint, err)
+		return
+	}
+
+	rawSig, err := channel.AcceptSplice(msg.Amount, msg.Inputs,
+		msg.ChangeOutputs)
+	if err != nil {
+		peerLog.Errorf("unable to splice ChannelPoint(%v): %v",
+			chanPoint, err)
+		return
+	}
+
+	pending := &pendingSplice{channel: channel}
+	p.pendingSplices[*chanPoint] = pending
+	p.expireSplice(pending)
+
+	commitSig, err := btcec.ParseSignature(rawSig, btcec.S256())
+	if err != nil {
+		p.abandonSplice(pending, err)
+		return
+	}
+
+	peerLog.Infof("Accepted splice of %v into ChannelPoint(%v) from "+
+		"peerID(%v)", msg.Amount, chanPoint, p.id)
+
+	p.queueMsg(lnwire.NewSpliceAccept(*chanPoint, commitSig), nil)
+}
+
+// handleSpliceAccept processes the remote peer's acceptance of a splice we
+// requested. Our inputs to the splice transaction are signed, and the
+// signatures required by the remote peer to complete the splice are sent
+// within a SpliceSigned.
+func (p *peer) handleSpliceAccept(msg *lnwire.SpliceAccept) {
+	pending, ok := p.pendingSplices[msg.ChannelPoint]
+	if !ok || pending.req == nil || pending.signed {
+		peerLog.Errorf("received SpliceAccept for ChannelPoint(%v) "+
+			"without a splice request", msg.ChannelPoint)
+		return
+	}
+
+	inputScripts, err := p.server.lnwallet.SignSpliceInputs(pending.tx)
+	if err != nil {
+		p.abandonSplice(pending, err)
+		return
+	}
+
+	rawCommitSig, rawFundingSig, err := pending.channel.SignSplice(
+		msg.CommitSignature.Serialize(), inputScripts)
+	if err != nil {
+		p.abandonSplice(pending, err)
+		return
+	}
+	commitSig, err := btcec.ParseSignature(rawCommitSig, btcec.S256())
+	if err != nil {
+		p.abandonSplice(pending, err)
+		return
+	}
+	fundingSig, err := btcec.ParseSignature(rawFundingSig, btcec.S256())
+	if err != nil {
+		p.abandonSplice(pending, err)
+		return
+	}
+
+	// From this point on, the remote peer is able to broadcast the splice
+	// transaction, so it must not be abandoned.
+	pending.signed = true
+
+	p.queueMsg(lnwire.NewSpliceSigned(msg.ChannelPoint, commitSig,
+		fundingSig, toWireInputScripts(inputScripts)), nil)
+}
+
+// handleSpliceSigned processes the signatures of the initiator of a splice we
+// accepted. Once the completed splice transaction has been verified, it's
+// broadcast, and our signature for the channel's funding output is sent to
+// the initiator within a SpliceComplete.
+func (p *peer) handleSpliceSigned(msg *lnwire.SpliceSigned) {
+	pending, ok := p.pendingSplices[msg.ChannelPoint]
+	if !ok || pending.req != nil || pending.signed {
+		peerLog.Errorf("received SpliceSigned for ChannelPoint(%v) "+
+			"without an accepted splice", msg.ChannelPoint)
+		return
+	}
+
+	channel := pending.channel
+	fundingOutpoint := channel.FundingOutpoint()
+	spliceTx, rawFundingSig, err := channel.CompleteSplice(
+		msg.CommitSignature.Serialize(),
+		msg.FundingSignature.Serialize(),
+		toWalletInputScripts(msg.FundingInputScripts))
+	if err != nil {
+		p.abandonSplice(pending, err)
+		return
+	}
+
+	// The initiator's input scripts can only be verified against the
+	// outputs they spend, so we'll do so before releasing our signature.
+	wallet := p.server.lnwallet
+	if err := wallet.VerifySpliceInputs(spliceTx, fundingOutpoint); err != nil {
+		p.abandonSplice(pending, err)
+		return
+	}
+	fundingSig, err := btcec.ParseSignature(rawFundingSig, btcec.S256())
+	if err != nil {
+		p.abandonSplice(pending, err)
+		return
+	}
+
+	pending.signed = true
+	delete(p.pendingSplices, msg.ChannelPoint)
+
+	spliceTxID := spliceTx.TxHash()
+	peerLog.Infof("Broadcasting splice of ChannelPoint(%v), txid=%v",
+		msg.ChannelPoint, spliceTxID)
+
+	if err := wallet.PublishTransaction(spliceTx); err != nil {
+		peerLog.Errorf("unable to broadcast splice transaction: %v",
+			err)
+	}
+
+	p.queueMsg(lnwire.NewSpliceComplete(msg.ChannelPoint, fundingSig), nil)
+
+	go p.watchSplice(channel, &spliceTxID)
+}
+
+// handleSpliceComplete processes the final message of a splice we requested,
+// completing and broadcasting the splice transaction.
+func (p *peer) handleSpliceComplete(msg *lnwire.SpliceComplete) {
+	pending, ok := p.pendingSplices[msg.ChannelPoint]
+	if !ok || pending.req == nil || !pending.signed {
+		peerLog.Errorf("received SpliceComplete for ChannelPoint(%v) "+
+			"without a signed splice", msg.ChannelPoint)
+		return
+	}
+	delete(p.pendingSplices, msg.ChannelPoint)
+
+	channel := pending.channel
+	spliceTx, err := channel.FinalizeSplice(msg.FundingSignature.Serialize())
+	if err != nil {
+		// The remote peer has already broadcast the splice
+		// transaction, so we'll still watch for it to confirm.
+		peerLog.Errorf("unable to finalize splice of "+
+			"ChannelPoint(%v): %v", msg.ChannelPoint, err)
+		pending.req.err <- err
+
+		spliceTxID := pending.tx.TxHash()
+		go p.watchSplice(channel, &spliceTxID)
+		return
+	}
+
+	spliceTxID := spliceTx.TxHash()
+	if err := p.server.lnwallet.PublishTransaction(spliceTx); err != nil {
+		peerLog.Warnf("unable to broadcast splice transaction: %v",
+			err)
+	}
+
+	pending.req.txid <- &spliceTxID
+
+	go p.watchSplice(channel, &spliceTxID)
+}
+
+// resumeSplice resumes watching the splice transaction of a channel loaded
+// from the database, if a splice has been signed by both parties, but not
+// yet migrated to. If the splice transaction is fully signed, then it's
+// broadcast once more, in case it has yet to confirm.
+func (p *peer) resumeSplice(channel *lnwallet.LightningChannel) {
+	spliceTx, fullySigned := channel.PendingSpliceTx()
+	if spliceTx == nil {
+		return
+	}
+
+	if fullySigned {
+		err := p.server.lnwallet.PublishTransaction(spliceTx)
+		if err != nil {
+			peerLog.Debugf("unable to rebroadcast splice "+
+				"transaction: %v", err)
+		}
+	}
+
+	spliceTxID := spliceTx.TxHash()
+	go p.watchSplice(channel, &spliceTxID)
+}
+
+// watchSplice waits for the splice transaction of the passed channel to
+// confirm. Once it's included within the chain, the channel is marked as
+// spliced, so a force close broadcasts the commitment transaction spending
+// the new funding output. Once it has cfg.SpliceConfs confirmations, the
+// channel is migrated to the new funding output, and re-announced to the
+// network.
+//
+// NOTE: This MUST be run as a goroutine.
+func (p *peer) watchSplice(channel *lnwallet.LightningChannel,
+	spliceTxID *chainhash.Hash) {
+
+	chanPoint := channel.ChannelPoint()
+	notifier := p.server.chainNotifier
+
+	confNtfn, err := notifier.RegisterConfirmationsNtfn(spliceTxID, 1)
+	if err != nil {
+		peerLog.Errorf("unable to register for confirmation of "+
+			"splice transaction %v: %v", spliceTxID, err)
+		return
+	}
+	select {
+	case _, ok := <-confNtfn.Confirmed:
+		if !ok {
+			return
+		}
+	case <-p.quit:
+		return
+	}
+
+	if err := channel.SpliceConfirmed(); err != nil {
+		peerLog.Errorf("unable to mark splice of ChannelPoint(%v) as "+
+			"confirmed: %v", chanPoint, err)
+		return
+	}
+
+	peerLog.Infof("Splice transaction %v of ChannelPoint(%v) confirmed, "+
+		"awaiting %v confirmations", spliceTxID, chanPoint,
+		cfg.SpliceConfs)
+
+	// Until the splice transaction is buried deep enough, a re-org could
+	// still remove it from the chain, so we'll retain the channel's
+	// existing state until then.
+	confNtfn, err = notifier.RegisterConfirmationsNtfn(spliceTxID,
+		cfg.SpliceConfs)
+	if err != nil {
+		peerLog.Errorf("unable to register for confirmation of "+
+			"splice transaction %v: %v", spliceTxID, err)
+		return
+	}
+	var confDetails *chainntnfs.TxConfirmation
+	select {
+	case conf, ok := <-confNtfn.Confirmed:
+		if !ok {
+			return
+		}
+		confDetails = conf
+	case <-p.quit:
+		return
+	}
+
+	prevBalance := channel.StateSnapshot().LocalBalance
+	fundingOutpoint, err := channel.MigrateSplice()
+	if err != nil {
+		peerLog.Errorf("unable to migrate ChannelPoint(%v) to splice: "+
+			"%v", chanPoint, err)
+		return
+	}
+
+	peerLog.Infof("ChannelPoint(%v) migrated to spliced funding output "+
+		"%v", chanPoint, fundingOutpoint)
+
+	// If we initiated the splice, then the spliced funds are now available
+	// to be sent through the channel.
+	snapshot := channel.StateSnapshot()
+	if snapshot.LocalBalance > prevBalance {
+		p.server.htlcSwitch.UpdateLink(chanPoint,
+			snapshot.LocalBalance-prevBalance)
+	}
+
+	// The channel's original funding output has been spent, so the
+	// channel is re-announced to the network under the ID of its new
+	// funding output.
+	chanID := lnwire.ChannelID{
+		BlockHeight: confDetails.BlockHeight,
+		TxIndex:     confDetails.TxIndex,
+		TxPosition:  uint16(fundingOutpoint.Index),
+	}
+	fundingMgr := p.server.fundingMgr
+	fundingMgr.announceChannel(p.server.identityPriv.PubKey(),
+		p.addr.IdentityKey, channel, chanID, fundingMgr.fakeProof,
+		fundingMgr.fakeProof)
+
+	// Our backups of the channel, both with the peer and on disk, should
+	// now reflect the channel's new funding output.
+	p.server.peerStorage.sendBackup(p)
+	p.server.chanBackup.requestUpdate()
+}
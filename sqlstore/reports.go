@@ -0,0 +1,133 @@
+package sqlstore
+
+import (
+	"time"
+
+	"github.com/roasbeef/btcutil"
+)
+
+// secondsPerDay is used to bucket unix timestamps into UTC days.
+const secondsPerDay = 86400
+
+// DailyRevenue summarizes the revenue earned within a single UTC day.
+type DailyRevenue struct {
+	// Day is the start of the UTC day the revenue was earned within.
+	Day time.Time
+
+	// InvoiceRevenue is the total value of invoices settled during the
+	// day.
+	InvoiceRevenue btcutil.Amount
+
+	// ForwardingFees is the total of the fees earned by forwarding HTLCs
+	// during the day.
+	ForwardingFees btcutil.Amount
+
+	// NumForwards is the number of HTLCs forwarded during the day.
+	NumForwards uint64
+}
+
+// CounterpartyVolume summarizes the total volume exchanged with a single
+// counterparty, either as the destination of our payments or as a peer which
+// our forwarded HTLCs arrived from or departed to.
+type CounterpartyVolume struct {
+	// PubKey is the serialized public key of the counterparty.
+	PubKey []byte
+
+	// Volume is the total value exchanged with the counterparty.
+	Volume btcutil.Amount
+
+	// Count is the number of payments and forwards involving the
+	// counterparty.
+	Count uint64
+}
+
+// RevenueByDay returns the revenue earned from settled invoices and
+// forwarding fees, grouped by UTC day, for all days within [start, end). Days
+// without any revenue are omitted.
+func (s *Store) RevenueByDay(start, end time.Time) ([]*DailyRevenue, error) {
+	rows, err := s.db.Query(`
+		SELECT day, SUM(invoice_revenue), SUM(fees), SUM(forwards)
+		FROM (
+			SELECT settle_date / ? AS day, value AS invoice_revenue,
+				0 AS fees, 0 AS forwards
+			FROM invoices
+			WHERE settle_date >= ? AND settle_date < ?
+
+			UNION ALL
+
+			SELECT timestamp / ? AS day, 0 AS invoice_revenue,
+				amt_in - amt_out AS fees, 1 AS forwards
+			FROM forwarding_events
+			WHERE timestamp >= ? AND timestamp < ?
+		)
+		GROUP BY day
+		ORDER BY day`,
+		secondsPerDay, start.Unix(), end.Unix(),
+		secondsPerDay, start.Unix(), end.Unix())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revenue []*DailyRevenue
+	for rows.Next() {
+		var day, invoiceRevenue, fees int64
+		var numForwards uint64
+		if err := rows.Scan(&day, &invoiceRevenue, &fees,
+			&numForwards); err != nil {
+			return nil, err
+		}
+
+		revenue = append(revenue, &DailyRevenue{
+			Day:            time.Unix(day*secondsPerDay, 0).UTC(),
+			InvoiceRevenue: btcutil.Amount(invoiceRevenue),
+			ForwardingFees: btcutil.Amount(fees),
+			NumForwards:    numForwards,
+		})
+	}
+
+	return revenue, rows.Err()
+}
+
+// TopCounterparties returns up to limit counterparties ordered by the total
+// volume exchanged with them, considering both outgoing payments and
+// forwarded HTLCs.
+func (s *Store) TopCounterparties(limit int) ([]*CounterpartyVolume, error) {
+	rows, err := s.db.Query(`
+		SELECT pub_key, SUM(volume) AS total, COUNT(*)
+		FROM (
+			SELECT destination AS pub_key, value AS volume
+			FROM payments
+
+			UNION ALL
+
+			SELECT incoming_peer AS pub_key, amt_in AS volume
+			FROM forwarding_events
+
+			UNION ALL
+
+			SELECT outgoing_peer AS pub_key, amt_out AS volume
+			FROM forwarding_events
+		)
+		GROUP BY pub_key
+		ORDER BY total DESC
+		LIMIT ?`, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var counterparties []*CounterpartyVolume
+	for rows.Next() {
+		c := &CounterpartyVolume{}
+		var volume int64
+		if err := rows.Scan(&c.PubKey, &volume, &c.Count); err != nil {
+			return nil, err
+		}
+		c.Volume = btcutil.Amount(volume)
+
+		counterparties = append(counterparties, c)
+	}
+
+	return counterparties, rows.Err()
+}
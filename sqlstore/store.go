@@ -0,0 +1,245 @@
+// Package sqlstore implements an optional SQL backed store which mirrors the
+// invoices, payments, and forwarding events recorded by the daemon. Unlike
+// channeldb, which remains the authoritative store, the tables within the SQL
+// store carry real indexes, allowing reporting queries such as revenue by day
+// or volume by counterparty to be executed efficiently, either via the
+// helpers within this package or directly against the database file.
+package sqlstore
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	// Register the sqlite3 driver with database/sql.
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/roasbeef/btcutil"
+)
+
+// driverName is the name of the database/sql driver used to open the store.
+const driverName = "sqlite3"
+
+// ErrInvoiceNotFound is returned when attempting to settle an invoice which
+// hasn't been recorded within the store.
+var ErrInvoiceNotFound = errors.New("invoice not found")
+
+// schema houses the statements used to initialize a fresh store. Each
+// statement is idempotent, so the schema may be applied each time the store
+// is opened.
+var schema = []string{
+	`CREATE TABLE IF NOT EXISTS invoices (
+		payment_hash BLOB PRIMARY KEY,
+		memo TEXT NOT NULL,
+		value INTEGER NOT NULL,
+		creation_date INTEGER NOT NULL,
+		settle_date INTEGER
+	)`,
+	`CREATE INDEX IF NOT EXISTS invoices_settle_date_idx
+		ON invoices (settle_date)`,
+
+	`CREATE TABLE IF NOT EXISTS payments (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		payment_hash BLOB NOT NULL,
+		destination BLOB NOT NULL,
+		value INTEGER NOT NULL,
+		fee INTEGER NOT NULL,
+		creation_date INTEGER NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS payments_creation_date_idx
+		ON payments (creation_date)`,
+	`CREATE INDEX IF NOT EXISTS payments_destination_idx
+		ON payments (destination)`,
+
+	`CREATE TABLE IF NOT EXISTS forwarding_events (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		timestamp INTEGER NOT NULL,
+		incoming_chan_point TEXT NOT NULL,
+		outgoing_chan_point TEXT NOT NULL,
+		incoming_peer BLOB NOT NULL,
+		outgoing_peer BLOB NOT NULL,
+		amt_in INTEGER NOT NULL,
+		amt_out INTEGER NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS forwarding_events_timestamp_idx
+		ON forwarding_events (timestamp)`,
+	`CREATE INDEX IF NOT EXISTS forwarding_events_incoming_peer_idx
+		ON forwarding_events (incoming_peer)`,
+	`CREATE INDEX IF NOT EXISTS forwarding_events_outgoing_peer_idx
+		ON forwarding_events (outgoing_peer)`,
+}
+
+// Invoice is the record of an invoice mirrored into the store.
+type Invoice struct {
+	// PaymentHash is the payment hash of the invoice.
+	PaymentHash [32]byte
+
+	// Memo is the memo attached to the invoice.
+	Memo string
+
+	// Value is the amount requested by the invoice.
+	Value btcutil.Amount
+
+	// CreationDate is the time the invoice was created.
+	CreationDate time.Time
+
+	// SettleDate, if non-zero, is the time the invoice was settled. It's
+	// set when backfilling invoices settled before they were mirrored.
+	SettleDate time.Time
+}
+
+// Payment is the record of an outgoing payment mirrored into the store.
+type Payment struct {
+	// PaymentHash is the payment hash of the payment.
+	PaymentHash [32]byte
+
+	// Destination is the serialized public key of the node that was
+	// paid.
+	Destination []byte
+
+	// Value is the amount received by the destination.
+	Value btcutil.Amount
+
+	// Fee is the total fee paid to intermediate nodes.
+	Fee btcutil.Amount
+
+	// CreationDate is the time the payment was sent.
+	CreationDate time.Time
+}
+
+// ForwardingEvent is the record of an HTLC successfully forwarded between two
+// of our channels.
+type ForwardingEvent struct {
+	// Timestamp is the time the forwarded HTLC was settled.
+	Timestamp time.Time
+
+	// IncomingChanPoint is the channel point of the channel the HTLC
+	// arrived on.
+	IncomingChanPoint string
+
+	// OutgoingChanPoint is the channel point of the channel the HTLC was
+	// forwarded over.
+	OutgoingChanPoint string
+
+	// IncomingPeer is the serialized public key of the peer which sent
+	// us the HTLC.
+	IncomingPeer []byte
+
+	// OutgoingPeer is the serialized public key of the peer we forwarded
+	// the HTLC to.
+	OutgoingPeer []byte
+
+	// AmtIn is the value of the incoming HTLC.
+	AmtIn btcutil.Amount
+
+	// AmtOut is the value of the outgoing HTLC. The difference between
+	// AmtIn and AmtOut is the fee we earned.
+	AmtOut btcutil.Amount
+}
+
+// Store is a SQL backed store of invoices, payments and forwarding events.
+type Store struct {
+	db *sql.DB
+}
+
+// Open opens, or creates, the store at the passed path, initializing the
+// schema if needed.
+func Open(path string) (*Store, error) {
+	db, err := sql.Open(driverName, path)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, stmt := range schema {
+		if _, err := db.Exec(stmt); err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+
+	return &Store{db: db}, nil
+}
+
+// Close closes the underlying database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// AddInvoice records a newly added invoice. If an invoice with the same
+// payment hash was already recorded, then it's left untouched, unless the
+// passed invoice is settled while the recorded one isn't, in which case the
+// settle is recorded.
+func (s *Store) AddInvoice(i *Invoice) error {
+	var settleDate sql.NullInt64
+	if !i.SettleDate.IsZero() {
+		settleDate.Int64 = i.SettleDate.Unix()
+		settleDate.Valid = true
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(`INSERT OR IGNORE INTO invoices
+		(payment_hash, memo, value, creation_date, settle_date)
+		VALUES (?, ?, ?, ?, ?)`,
+		i.PaymentHash[:], i.Memo, int64(i.Value),
+		i.CreationDate.Unix(), settleDate)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if settleDate.Valid {
+		_, err = tx.Exec(`UPDATE invoices SET settle_date = ?
+			WHERE payment_hash = ? AND settle_date IS NULL`,
+			settleDate, i.PaymentHash[:])
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// SettleInvoice marks the invoice with the passed payment hash as settled at
+// the given time.
+func (s *Store) SettleInvoice(paymentHash [32]byte, settleDate time.Time) error {
+	res, err := s.db.Exec(`UPDATE invoices SET settle_date = ?
+		WHERE payment_hash = ?`, settleDate.Unix(), paymentHash[:])
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrInvoiceNotFound
+	}
+
+	return nil
+}
+
+// AddPayment records a completed outgoing payment.
+func (s *Store) AddPayment(p *Payment) error {
+	_, err := s.db.Exec(`INSERT INTO payments
+		(payment_hash, destination, value, fee, creation_date)
+		VALUES (?, ?, ?, ?, ?)`,
+		p.PaymentHash[:], p.Destination, int64(p.Value), int64(p.Fee),
+		p.CreationDate.Unix())
+	return err
+}
+
+// AddForwardingEvent records a successfully forwarded HTLC.
+func (s *Store) AddForwardingEvent(e *ForwardingEvent) error {
+	_, err := s.db.Exec(`INSERT INTO forwarding_events
+		(timestamp, incoming_chan_point, outgoing_chan_point,
+		incoming_peer, outgoing_peer, amt_in, amt_out)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		e.Timestamp.Unix(), e.IncomingChanPoint, e.OutgoingChanPoint,
+		e.IncomingPeer, e.OutgoingPeer, int64(e.AmtIn), int64(e.AmtOut))
+	return err
+}
@@ -0,0 +1,196 @@
+package sqlstore
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/roasbeef/btcutil"
+)
+
+func makeTestStore() (*Store, func(), error) {
+	tempDirName, err := ioutil.TempDir("", "sqlstore")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	store, err := Open(filepath.Join(tempDirName, "analytics.db"))
+	if err != nil {
+		os.RemoveAll(tempDirName)
+		return nil, nil, err
+	}
+
+	cleanUp := func() {
+		store.Close()
+		os.RemoveAll(tempDirName)
+	}
+
+	return store, cleanUp, nil
+}
+
+func TestRevenueByDay(t *testing.T) {
+	store, cleanUp, err := makeTestStore()
+	if err != nil {
+		t.Fatalf("unable to make test store: %v", err)
+	}
+	defer cleanUp()
+
+	dayOne := time.Date(2017, 3, 1, 0, 0, 0, 0, time.UTC)
+	dayTwo := dayOne.Add(time.Hour * 24)
+
+	// Add two invoices, settling one on each day, and a third which is
+	// never settled and thus shouldn't count as revenue.
+	invoices := []*Invoice{
+		{PaymentHash: [32]byte{1}, Value: 1000, CreationDate: dayOne},
+		{PaymentHash: [32]byte{2}, Value: 2000, CreationDate: dayOne},
+		{PaymentHash: [32]byte{3}, Value: 4000, CreationDate: dayOne},
+	}
+	for _, invoice := range invoices {
+		if err := store.AddInvoice(invoice); err != nil {
+			t.Fatalf("unable to add invoice: %v", err)
+		}
+	}
+	err = store.SettleInvoice([32]byte{1}, dayOne.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("unable to settle invoice: %v", err)
+	}
+	err = store.SettleInvoice([32]byte{2}, dayTwo.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("unable to settle invoice: %v", err)
+	}
+	if err := store.SettleInvoice([32]byte{9}, dayTwo); err != ErrInvoiceNotFound {
+		t.Fatalf("expected ErrInvoiceNotFound, got %v", err)
+	}
+
+	// Two forwards on the second day, earning a total of 15 satoshis.
+	for _, fee := range []btcutil.Amount{5, 10} {
+		err := store.AddForwardingEvent(&ForwardingEvent{
+			Timestamp:    dayTwo.Add(time.Minute),
+			IncomingPeer: []byte{0x02},
+			OutgoingPeer: []byte{0x03},
+			AmtIn:        100 + fee,
+			AmtOut:       100,
+		})
+		if err != nil {
+			t.Fatalf("unable to add forwarding event: %v", err)
+		}
+	}
+
+	revenue, err := store.RevenueByDay(dayOne, dayTwo.Add(time.Hour*24))
+	if err != nil {
+		t.Fatalf("unable to query revenue: %v", err)
+	}
+	if len(revenue) != 2 {
+		t.Fatalf("expected 2 days of revenue, got %v", len(revenue))
+	}
+
+	if !revenue[0].Day.Equal(dayOne) ||
+		revenue[0].InvoiceRevenue != 1000 ||
+		revenue[0].ForwardingFees != 0 {
+		t.Fatalf("unexpected revenue for day one: %+v", revenue[0])
+	}
+	if !revenue[1].Day.Equal(dayTwo) ||
+		revenue[1].InvoiceRevenue != 2000 ||
+		revenue[1].ForwardingFees != 15 ||
+		revenue[1].NumForwards != 2 {
+		t.Fatalf("unexpected revenue for day two: %+v", revenue[1])
+	}
+
+	// Restricting the range to the first day should omit the second.
+	revenue, err = store.RevenueByDay(dayOne, dayTwo)
+	if err != nil {
+		t.Fatalf("unable to query revenue: %v", err)
+	}
+	if len(revenue) != 1 {
+		t.Fatalf("expected 1 day of revenue, got %v", len(revenue))
+	}
+}
+
+func TestTopCounterparties(t *testing.T) {
+	store, cleanUp, err := makeTestStore()
+	if err != nil {
+		t.Fatalf("unable to make test store: %v", err)
+	}
+	defer cleanUp()
+
+	now := time.Now()
+	payments := []*Payment{
+		{Destination: []byte{0xaa}, Value: 500, CreationDate: now},
+		{Destination: []byte{0xaa}, Value: 700, CreationDate: now},
+		{Destination: []byte{0xbb}, Value: 300, CreationDate: now},
+	}
+	for _, payment := range payments {
+		if err := store.AddPayment(payment); err != nil {
+			t.Fatalf("unable to add payment: %v", err)
+		}
+	}
+
+	err = store.AddForwardingEvent(&ForwardingEvent{
+		Timestamp:    now,
+		IncomingPeer: []byte{0xcc},
+		OutgoingPeer: []byte{0xbb},
+		AmtIn:        1001,
+		AmtOut:       1000,
+	})
+	if err != nil {
+		t.Fatalf("unable to add forwarding event: %v", err)
+	}
+
+	top, err := store.TopCounterparties(2)
+	if err != nil {
+		t.Fatalf("unable to query counterparties: %v", err)
+	}
+	if len(top) != 2 {
+		t.Fatalf("expected 2 counterparties, got %v", len(top))
+	}
+
+	// 0xbb received 300 via payment and 1000 via the forward, placing it
+	// ahead of 0xaa's 1200.
+	if top[0].PubKey[0] != 0xbb || top[0].Volume != 1300 || top[0].Count != 2 {
+		t.Fatalf("unexpected top counterparty: %+v", top[0])
+	}
+	if top[1].PubKey[0] != 0xaa || top[1].Volume != 1200 || top[1].Count != 2 {
+		t.Fatalf("unexpected second counterparty: %+v", top[1])
+	}
+}
+
+// TestAddInvoiceSettled tests that adding an invoice already recorded only
+// records its settle, if the recorded invoice wasn't already settled.
+func TestAddInvoiceSettled(t *testing.T) {
+	store, cleanUp, err := makeTestStore()
+	if err != nil {
+		t.Fatalf("unable to make test store: %v", err)
+	}
+	defer cleanUp()
+
+	day := time.Date(2017, 3, 1, 0, 0, 0, 0, time.UTC)
+	invoice := &Invoice{PaymentHash: [32]byte{1}, Value: 1000,
+		CreationDate: day}
+	if err := store.AddInvoice(invoice); err != nil {
+		t.Fatalf("unable to add invoice: %v", err)
+	}
+
+	// Backfilling the invoice once it's been settled records the settle,
+	// while a later backfill with another settle date is ignored.
+	invoice.SettleDate = day.Add(time.Hour)
+	if err := store.AddInvoice(invoice); err != nil {
+		t.Fatalf("unable to add invoice: %v", err)
+	}
+	invoice.SettleDate = day.Add(time.Hour * 24)
+	if err := store.AddInvoice(invoice); err != nil {
+		t.Fatalf("unable to add invoice: %v", err)
+	}
+
+	revenue, err := store.RevenueByDay(day, day.Add(time.Hour*48))
+	if err != nil {
+		t.Fatalf("unable to query revenue: %v", err)
+	}
+	if len(revenue) != 1 || !revenue[0].Day.Equal(day) ||
+		revenue[0].InvoiceRevenue != 1000 {
+
+		t.Fatalf("expected revenue of 1000 on %v, got %v", day,
+			revenue)
+	}
+}
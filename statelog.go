@@ -0,0 +1,48 @@
+package main
+
+import (
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwallet"
+)
+
+// stateLog appends each transition of the commitment state machine of every
+// channel to the channel's state log within the database. Unlike the channel
+// history, which records the notable events of a channel's lifetime, the
+// state log records every exchange of signatures and revocations, allowing
+// disputes and bugs to be reconstructed after the fact.
+//
+// Logging a transition is best effort: failures are logged rather than
+// interrupting the operation of the channel.
+type stateLog struct {
+	db *channeldb.DB
+}
+
+// newStateLog creates a new stateLog backed by the passed database.
+func newStateLog(db *channeldb.DB) *stateLog {
+	return &stateLog{
+		db: db,
+	}
+}
+
+// record appends an event of the passed type to the state log of the channel,
+// populated with the current heights of its commitment chains.
+func (s *stateLog) record(channel *lnwallet.LightningChannel,
+	eventType channeldb.StateEventType, detail string) {
+
+	localHeight, remoteHeight := channel.CommitHeights()
+	event := &channeldb.StateEvent{
+		Type:         eventType,
+		Timestamp:    time.Now(),
+		LocalHeight:  localHeight,
+		RemoteHeight: remoteHeight,
+		Detail:       detail,
+	}
+
+	chanPoint := channel.ChannelPoint()
+	if err := s.db.AppendStateEvent(chanPoint, event); err != nil {
+		ltndLog.Errorf("unable to log %v transition of "+
+			"ChannelPoint(%v): %v", eventType, chanPoint, err)
+	}
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/roasbeef/btcd/chaincfg"
+	"github.com/roasbeef/btcd/txscript"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+// errUnlistedDestination is returned when an on-chain payment would pay to a
+// script outside the operator's whitelist, without the whitelist having been
+// explicitly overridden.
+var errUnlistedDestination = errors.New("destination isn't within the " +
+	"sweep whitelist, the whitelist must be explicitly overridden to " +
+	"pay to it")
+
+// scriptWhitelist is the set of destinations, such as cold storage, which the
+// operator permits on-chain funds leaving the node to be paid to. Once
+// configured, outputs swept from force closed and breached channels, and our
+// balance of channels closed cooperatively, are paid to the whitelist rather
+// than the wallet, and on-chain payments elsewhere require an explicit
+// override. This limits the damage of stolen RPC credentials, as they alone
+// can't be used to drain the node's funds to an address of the thief's
+// choosing. A nil whitelist places no restriction on destinations.
+type scriptWhitelist struct {
+	// addrs are the whitelisted destinations, in the order they were
+	// configured.
+	addrs []btcutil.Address
+
+	// scripts are the public key scripts paying to each of addrs.
+	scripts [][]byte
+}
+
+// parseScriptWhitelist parses the passed addresses into a whitelist. As our
+// balance within channels closed cooperatively is delivered to the whitelist,
+// only the script templates supported as delivery scripts are accepted: P2PKH,
+// P2WKH, P2SH, and P2WSH. If no addresses are passed, a nil whitelist is
+// returned.
+func parseScriptWhitelist(addrs []string,
+	params *chaincfg.Params) (*scriptWhitelist, error) {
+
+	if len(addrs) == 0 {
+		return nil, nil
+	}
+
+	whitelist := &scriptWhitelist{}
+	for _, encoded := range addrs {
+		addr, err := btcutil.DecodeAddress(encoded, params)
+		if err != nil {
+			return nil, fmt.Errorf("invalid whitelisted address "+
+				"%v: %v", encoded, err)
+		}
+		if !addr.IsForNet(params) {
+			return nil, fmt.Errorf("whitelisted address %v isn't "+
+				"for %v", encoded, params.Name)
+		}
+
+		pkScript, err := txscript.PayToAddrScript(addr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid whitelisted address "+
+				"%v: %v", encoded, err)
+		}
+		switch txscript.GetScriptClass(pkScript) {
+		case txscript.PubKeyHashTy, txscript.WitnessV0PubKeyHashTy,
+			txscript.ScriptHashTy, txscript.WitnessV0ScriptHashTy:
+
+		default:
+			return nil, fmt.Errorf("whitelisted address %v must "+
+				"be P2PKH, P2WKH, P2SH, or P2WSH", encoded)
+		}
+
+		whitelist.addrs = append(whitelist.addrs, addr)
+		whitelist.scripts = append(whitelist.scripts, pkScript)
+	}
+
+	return whitelist, nil
+}
+
+// destination returns the address funds swept from, or delivered by, our
+// channels are paid to. If the whitelist is nil, then nil is returned, and the
+// funds are paid to the wallet.
+func (w *scriptWhitelist) destination() btcutil.Address {
+	if w == nil {
+		return nil
+	}
+
+	return w.addrs[0]
+}
+
+// allows returns true if the passed public key script is within the whitelist.
+func (w *scriptWhitelist) allows(pkScript []byte) bool {
+	if w == nil {
+		return true
+	}
+
+	for _, script := range w.scripts {
+		if bytes.Equal(script, pkScript) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkOutputs returns errUnlistedDestination if any of the passed outputs pay
+// to a script outside the whitelist, unless override is set.
+func (w *scriptWhitelist) checkOutputs(outputs []*wire.TxOut,
+	override bool) error {
+
+	if override {
+		return nil
+	}
+
+	for _, output := range outputs {
+		if !w.allows(output.PkScript) {
+			return errUnlistedDestination
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/roasbeef/btcd/chaincfg"
+	"github.com/roasbeef/btcd/txscript"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+const (
+	whitelistedAddr = "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2"
+	unlistedAddr    = "3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy"
+)
+
+// TestParseScriptWhitelist tests that only addresses for the active network,
+// which can be used as delivery scripts, may be whitelisted.
+func TestParseScriptWhitelist(t *testing.T) {
+	tests := []struct {
+		addrs []string
+		valid bool
+	}{
+		{[]string{whitelistedAddr, unlistedAddr}, true},
+
+		// Addresses must be for the active network.
+		{[]string{"mipcBbFg9gMiCh81Kj8tqqdgoZub1ZJRfn"}, false},
+
+		// Pay-to-pubkey scripts aren't supported as delivery scripts.
+		{[]string{"02192d74d0cb94344c9569c2e77901573d8d7903c3ebec3a95" +
+			"7724895dca52c6b4"}, false},
+
+		{[]string{"not an address"}, false},
+	}
+
+	for i, test := range tests {
+		_, err := parseScriptWhitelist(test.addrs,
+			&chaincfg.MainNetParams)
+		if test.valid && err != nil {
+			t.Fatalf("test #%v: unable to parse whitelist: %v", i,
+				err)
+		}
+		if !test.valid && err == nil {
+			t.Fatalf("test #%v: expected whitelist to be rejected", i)
+		}
+	}
+
+	// Without any addresses, no whitelist is created.
+	whitelist, err := parseScriptWhitelist(nil, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatalf("unable to parse whitelist: %v", err)
+	}
+	if whitelist != nil {
+		t.Fatalf("expected no whitelist, got %v", whitelist)
+	}
+}
+
+// TestScriptWhitelistCheckOutputs tests that outputs paying outside the
+// whitelist are refused unless the whitelist is overridden, and that a nil
+// whitelist allows any output.
+func TestScriptWhitelistCheckOutputs(t *testing.T) {
+	whitelist, err := parseScriptWhitelist([]string{whitelistedAddr},
+		&chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatalf("unable to parse whitelist: %v", err)
+	}
+	if whitelist.destination().String() != whitelistedAddr {
+		t.Fatalf("expected destination %v, got %v", whitelistedAddr,
+			whitelist.destination())
+	}
+
+	newOutput := func(encoded string) *wire.TxOut {
+		addr, err := btcutil.DecodeAddress(encoded,
+			&chaincfg.MainNetParams)
+		if err != nil {
+			t.Fatalf("unable to decode address: %v", err)
+		}
+		pkScript, err := txscript.PayToAddrScript(addr)
+		if err != nil {
+			t.Fatalf("unable to create script: %v", err)
+		}
+		return wire.NewTxOut(1000, pkScript)
+	}
+	listed := []*wire.TxOut{newOutput(whitelistedAddr)}
+	mixed := []*wire.TxOut{
+		newOutput(whitelistedAddr), newOutput(unlistedAddr),
+	}
+
+	if err := whitelist.checkOutputs(listed, false); err != nil {
+		t.Fatalf("expected whitelisted output to be allowed: %v", err)
+	}
+	if err := whitelist.checkOutputs(mixed, false); err != errUnlistedDestination {
+		t.Fatalf("expected errUnlistedDestination, got: %v", err)
+	}
+	if err := whitelist.checkOutputs(mixed, true); err != nil {
+		t.Fatalf("expected override to allow output: %v", err)
+	}
+
+	var unrestricted *scriptWhitelist
+	if err := unrestricted.checkOutputs(mixed, false); err != nil {
+		t.Fatalf("expected nil whitelist to allow output: %v", err)
+	}
+	if unrestricted.destination() != nil {
+		t.Fatalf("expected no destination for nil whitelist")
+	}
+}
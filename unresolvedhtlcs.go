@@ -0,0 +1,56 @@
+package main
+
+import "github.com/lightningnetwork/lnd/lnwallet"
+
+// htlcOwner describes the subsystem currently responsible for progressing
+// the resolution of an HTLC.
+type htlcOwner string
+
+const (
+	// htlcOwnerLink is the owner of HTLCs whose addition or removal has
+	// yet to be committed within both commitment chains of the channel.
+	// They're resolved once the link completes a state update with the
+	// remote peer.
+	htlcOwnerLink htlcOwner = "link"
+
+	// htlcOwnerRemote is the owner of outgoing HTLCs which have been
+	// locked in, and are awaiting a settle or fail from the remote peer.
+	htlcOwnerRemote htlcOwner = "remote_peer"
+
+	// htlcOwnerSwitch is the owner of incoming HTLCs which have been
+	// forwarded, and are awaiting the resolution of the outgoing HTLC.
+	htlcOwnerSwitch htlcOwner = "switch"
+
+	// htlcOwnerInvoice is the owner of incoming HTLCs paying one of our
+	// invoices, which are held until the remaining shards of the payment
+	// arrive.
+	htlcOwnerInvoice htlcOwner = "invoice_registry"
+
+	// htlcOwnerAsync is the owner of incoming HTLCs held on behalf of an
+	// offline recipient until it returns.
+	htlcOwnerAsync htlcOwner = "async_holder"
+)
+
+// unresolvedHTLCOwner returns the subsystem responsible for the resolution of
+// the passed HTLC. isInvoice denotes whether the HTLC pays one of our
+// invoices, and isHeld whether it's held on behalf of an offline recipient.
+func unresolvedHTLCOwner(htlc *lnwallet.PendingHTLC, isInvoice,
+	isHeld bool) htlcOwner {
+
+	switch {
+	case !htlc.LockedIn || htlc.Removing:
+		return htlcOwnerLink
+
+	case !htlc.Incoming:
+		return htlcOwnerRemote
+
+	case isInvoice:
+		return htlcOwnerInvoice
+
+	case isHeld:
+		return htlcOwnerAsync
+
+	default:
+		return htlcOwnerSwitch
+	}
+}
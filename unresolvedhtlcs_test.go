@@ -0,0 +1,75 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnwallet"
+)
+
+func TestUnresolvedHTLCOwner(t *testing.T) {
+	tests := []struct {
+		htlc      lnwallet.PendingHTLC
+		isInvoice bool
+		isHeld    bool
+		owner     htlcOwner
+	}{
+		// HTLCs which have yet to be locked in, or whose removal has
+		// yet to be committed, await a state update.
+		{
+			htlc:  lnwallet.PendingHTLC{Incoming: true},
+			owner: htlcOwnerLink,
+		},
+		{
+			htlc: lnwallet.PendingHTLC{
+				LockedIn: true,
+				Removing: true,
+			},
+			owner: htlcOwnerLink,
+		},
+		{
+			htlc: lnwallet.PendingHTLC{
+				Incoming: true,
+				LockedIn: true,
+				Removing: true,
+			},
+			isInvoice: true,
+			owner:     htlcOwnerLink,
+		},
+		{
+			htlc:  lnwallet.PendingHTLC{LockedIn: true},
+			owner: htlcOwnerRemote,
+		},
+		{
+			htlc: lnwallet.PendingHTLC{
+				Incoming: true,
+				LockedIn: true,
+			},
+			owner: htlcOwnerSwitch,
+		},
+		{
+			htlc: lnwallet.PendingHTLC{
+				Incoming: true,
+				LockedIn: true,
+			},
+			isInvoice: true,
+			owner:     htlcOwnerInvoice,
+		},
+		{
+			htlc: lnwallet.PendingHTLC{
+				Incoming: true,
+				LockedIn: true,
+			},
+			isHeld: true,
+			owner:  htlcOwnerAsync,
+		},
+	}
+
+	for i, test := range tests {
+		owner := unresolvedHTLCOwner(&test.htlc, test.isInvoice,
+			test.isHeld)
+		if owner != test.owner {
+			t.Fatalf("#%v: expected owner %v, got %v", i,
+				test.owner, owner)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/binary"
 	"errors"
+	"fmt"
 	"io"
 	"sync"
 	"sync/atomic"
@@ -13,7 +14,9 @@ import (
 	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/channeldb"
 	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/roasbeef/btcd/blockchain"
 	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
 	"github.com/roasbeef/btcd/txscript"
 	"github.com/roasbeef/btcd/wire"
 	"github.com/roasbeef/btcutil"
@@ -48,6 +51,16 @@ var (
 	byteOrder = binary.BigEndian
 )
 
+const (
+	// sweepConfTarget is the number of blocks within which we aim for
+	// sweep transactions to confirm when estimating their fee.
+	sweepConfTarget = 6
+
+	// defaultSweepFee is the fee paid by sweep transactions if the
+	// backend is unable to estimate fees.
+	defaultSweepFee = btcutil.Amount(5000)
+)
+
 // witnessType determines how an output's witness will be generated. The
 // default commitmentTimeLock type will generate a witness that will allow
 // spending of a time-locked transaction enforced by CheckSequenceVerify.
@@ -55,8 +68,24 @@ type witnessType uint16
 
 const (
 	commitmentTimeLock witnessType = 0
+
+	// anchorSweep generates a witness spending our anchor output of a
+	// commitment transaction with the anchor format.
+	anchorSweep witnessType = 1
+
+	// htlcOfferedTimeout generates a witness reclaiming an HTLC we offered
+	// from our commitment transaction once it has timed out.
+	htlcOfferedTimeout witnessType = 2
+
+	// htlcAcceptedSuccess generates a witness claiming an HTLC offered to
+	// us from our commitment transaction with its payment preimage.
+	htlcAcceptedSuccess witnessType = 3
 )
 
+// maxWitnessScriptSize is the largest witness script of an output incubated
+// by the nursery. HTLC scripts are the largest among them.
+const maxWitnessScriptSize = 500
+
 // witnessGenerator represents a function which is able to generate the final
 // witness for a particular public key script. This function acts as an
 // abstraction layer, hiding the details of the underlying script from the
@@ -65,10 +94,11 @@ type witnessGenerator func(tx *wire.MsgTx, hc *txscript.TxSigHashes,
 	inputIndex int) ([][]byte, error)
 
 // generateFunc will return the witnessGenerator function that a kidOutput uses
-// to generate the witness for a sweep transaction. Currently there is only one
-// witnessType but this will be expanded.
+// to generate the witness for a sweep transaction. The payment preimage is
+// only used by the htlcAcceptedSuccess type.
 func (wt witnessType) generateFunc(signer *lnwallet.Signer,
-	descriptor *lnwallet.SignDescriptor) witnessGenerator {
+	descriptor *lnwallet.SignDescriptor,
+	preimage [32]byte) witnessGenerator {
 
 	switch wt {
 	case commitmentTimeLock:
@@ -81,6 +111,37 @@ func (wt witnessType) generateFunc(signer *lnwallet.Signer,
 
 			return lnwallet.CommitSpendTimeout(*signer, desc, tx)
 		}
+	case anchorSweep:
+		return func(tx *wire.MsgTx, hc *txscript.TxSigHashes,
+			inputIndex int) ([][]byte, error) {
+
+			desc := descriptor
+			desc.SigHashes = hc
+			desc.InputIndex = inputIndex
+
+			return lnwallet.AnchorSpend(*signer, desc, tx)
+		}
+	case htlcOfferedTimeout:
+		return func(tx *wire.MsgTx, hc *txscript.TxSigHashes,
+			inputIndex int) ([][]byte, error) {
+
+			desc := descriptor
+			desc.SigHashes = hc
+			desc.InputIndex = inputIndex
+
+			return lnwallet.HtlcSpendTimeout(*signer, desc, tx)
+		}
+	case htlcAcceptedSuccess:
+		return func(tx *wire.MsgTx, hc *txscript.TxSigHashes,
+			inputIndex int) ([][]byte, error) {
+
+			desc := descriptor
+			desc.SigHashes = hc
+			desc.InputIndex = inputIndex
+
+			return lnwallet.HtlcSpendSuccess(*signer, desc, tx,
+				preimage)
+		}
 	}
 
 	return nil
@@ -104,6 +165,26 @@ type utxoNursery struct {
 
 	requests chan *incubationRequest
 
+	// sweeps are the sweep transactions which have been broadcast, but
+	// have yet to confirm, keyed by the height their outputs graduated
+	// at. It's only accessed by the incubator, or during start up before
+	// the incubator has been launched.
+	//
+	// TODO(roasbeef): persist, so sweeps broadcast before a restart are
+	// still bumped.
+	sweeps map[uint32]*pendingSweep
+
+	// sweepConfs receives the graduation height of each sweep once any
+	// version of it has confirmed.
+	sweepConfs chan uint32
+
+	// closePackages are the force close transactions broadcast along
+	// with a child spending our anchor which have yet to confirm, keyed
+	// by the txid of the close. Each package is rebroadcast as a unit
+	// with every new block, so neither half is broadcast without the
+	// other. It's guarded by the nursery's mutex.
+	closePackages map[chainhash.Hash]*closePackage
+
 	started uint32
 	stopped uint32
 	quit    chan struct{}
@@ -116,11 +197,14 @@ func newUtxoNursery(db *channeldb.DB, notifier chainntnfs.ChainNotifier,
 	wallet *lnwallet.LightningWallet) *utxoNursery {
 
 	return &utxoNursery{
-		notifier: notifier,
-		wallet:   wallet,
-		requests: make(chan *incubationRequest),
-		db:       db,
-		quit:     make(chan struct{}),
+		notifier:      notifier,
+		wallet:        wallet,
+		requests:      make(chan *incubationRequest),
+		db:            db,
+		sweeps:        make(map[uint32]*pendingSweep),
+		sweepConfs:    make(chan uint32),
+		closePackages: make(map[chainhash.Hash]*closePackage),
+		quit:          make(chan struct{}),
 	}
 }
 
@@ -266,6 +350,15 @@ type kidOutput struct {
 	blocksToMaturity uint32
 	confHeight       uint32
 
+	// absoluteMaturity is the height before which the output can't be
+	// swept, regardless of its relative maturity. It's only set for
+	// HTLCs we offered, which may only be reclaimed once they expire.
+	absoluteMaturity uint32
+
+	// preimage is the payment preimage used to claim an HTLC offered to
+	// us. It's only set for the htlcAcceptedSuccess witness type.
+	preimage [32]byte
+
 	signDescriptor *lnwallet.SignDescriptor
 	witnessType    witnessType
 }
@@ -297,6 +390,56 @@ func (u *utxoNursery) incubateOutputs(closeSummary *lnwallet.ForceCloseSummary)
 		}
 
 		incReq.outputs = append(incReq.outputs, selfOutput)
+
+		// Our anchor output, if any and not already spent by a child
+		// bumping the fee of the commitment, isn't worth a sweep
+		// transaction of its own, so it matures along with the
+		// to-self output, letting both be swept within the same batch.
+		if closeSummary.AnchorSignDesc != nil {
+			anchorOutput := &kidOutput{
+				amt:              lnwallet.AnchorSize,
+				outPoint:         closeSummary.AnchorOutpoint,
+				blocksToMaturity: closeSummary.SelfOutputMaturity,
+				signDescriptor:   closeSummary.AnchorSignDesc,
+				witnessType:      anchorSweep,
+			}
+
+			incReq.outputs = append(incReq.outputs, anchorOutput)
+		}
+	}
+
+	// Each HTLC output we're able to claim is incubated alongside the
+	// to-self output. HTLCs we offered are reclaimed once they've timed
+	// out, while those offered to us are claimed with their preimage. As
+	// the sender may reclaim an HTLC offered to us once it expires, our
+	// claim races theirs if the HTLC expires before the relative delay
+	// has passed.
+	for _, htlc := range closeSummary.HtlcResolutions {
+		kid := &kidOutput{
+			amt:              btcutil.Amount(htlc.SignDesc.Output.Value),
+			outPoint:         htlc.Outpoint,
+			blocksToMaturity: htlc.CsvDelay,
+			signDescriptor:   htlc.SignDesc,
+		}
+
+		if !htlc.Incoming {
+			kid.absoluteMaturity = htlc.Expiry
+			kid.witnessType = htlcOfferedTimeout
+			incReq.outputs = append(incReq.outputs, kid)
+			continue
+		}
+
+		preimage, err := u.htlcPreimage(htlc.PaymentHash)
+		if err != nil {
+			utxnLog.Warnf("Unable to claim incoming HTLC %v for %x, "+
+				"it'll be reclaimed by its sender once it "+
+				"times out: %v", htlc.Outpoint,
+				htlc.PaymentHash[:], err)
+			continue
+		}
+		kid.preimage = preimage
+		kid.witnessType = htlcAcceptedSuccess
+		incReq.outputs = append(incReq.outputs, kid)
 	}
 
 	// If there are no outputs to incubate, there is nothing to send to the
@@ -306,6 +449,129 @@ func (u *utxoNursery) incubateOutputs(closeSummary *lnwallet.ForceCloseSummary)
 	}
 }
 
+// closePackage is a force close transaction which has been broadcast along
+// with a child spending our anchor output, bumping the fee of the close.
+type closePackage struct {
+	// closeTx is the force close transaction.
+	closeTx *wire.MsgTx
+
+	// childTx is the child spending our anchor output of the close.
+	childTx *wire.MsgTx
+}
+
+// broadcastClose broadcasts the passed force close transaction along with the
+// passed child spending our anchor output of it, if any, as a package. Should
+// the child be refused, then its coins are unlocked, and the close is
+// broadcast alone. A package which has been broadcast is tracked until the
+// close confirms, being rebroadcast as a unit with every new block. The
+// returned boolean reports whether the child was broadcast, in which case
+// our anchor has been spent.
+func (u *utxoNursery) broadcastClose(closeTx,
+	childTx *wire.MsgTx) (bool, error) {
+
+	if childTx == nil {
+		return false, u.wallet.PublishTransaction(closeTx)
+	}
+
+	closeTxid := closeTx.TxHash()
+	err := u.wallet.PublishPackage(closeTx, childTx)
+	switch err.(type) {
+	case nil:
+
+	// The close itself has been broadcast, so only the child is
+	// abandoned.
+	case *lnwallet.ErrChildRejected:
+		utxnLog.Errorf("Unable to broadcast anchor child %v of force "+
+			"close tx %v: %v", childTx.TxHash(), closeTxid, err)
+		u.wallet.UnlockAnchorChild(childTx)
+		return false, nil
+
+	default:
+		utxnLog.Errorf("Unable to broadcast force close tx %v along "+
+			"with anchor child %v, broadcasting it alone: %v",
+			closeTxid, childTx.TxHash(), err)
+		u.wallet.UnlockAnchorChild(childTx)
+		return false, u.wallet.PublishTransaction(closeTx)
+	}
+
+	// With the package broadcast, our anchor has been spent, even should
+	// we fail to track the package.
+	confChan, err := u.notifier.RegisterConfirmationsNtfn(&closeTxid, 1)
+	if err != nil {
+		utxnLog.Errorf("unable to register force close tx %v for "+
+			"confirmation: %v", closeTxid, err)
+		return true, nil
+	}
+
+	u.Lock()
+	u.closePackages[closeTxid] = &closePackage{
+		closeTx: closeTx,
+		childTx: childTx,
+	}
+	u.Unlock()
+
+	go func() {
+		select {
+		case _, ok := <-confChan.Confirmed:
+			// If the ChainNotifier is shutting down, then so are
+			// we.
+			if !ok {
+				return
+			}
+
+			u.Lock()
+			delete(u.closePackages, closeTxid)
+			u.Unlock()
+		case <-u.quit:
+		}
+	}()
+
+	return true, nil
+}
+
+// rebroadcastCloses rebroadcasts each force close package which has yet to
+// confirm, submitting the close and its child together so the child is never
+// broadcast without its parent, nor the parent without the child paying for
+// it.
+func (u *utxoNursery) rebroadcastCloses() {
+	u.RLock()
+	defer u.RUnlock()
+
+	for closeTxid, pkg := range u.closePackages {
+		err := u.wallet.PublishPackage(pkg.closeTx, pkg.childTx)
+		if err != nil {
+			utxnLog.Debugf("unable to rebroadcast force close tx "+
+				"%v along with anchor child %v: %v", closeTxid,
+				pkg.childTx.TxHash(), err)
+		}
+	}
+}
+
+// htlcPreimage returns the preimage of the passed payment hash, if we know it.
+// We know the preimage of an incoming HTLC if it pays one of our invoices, or
+// if we forwarded it and have since been paid by the outgoing HTLC.
+func (u *utxoNursery) htlcPreimage(paymentHash [32]byte) ([32]byte, error) {
+	invoice, err := u.db.LookupInvoice(paymentHash)
+	switch {
+	case err == nil:
+		return invoice.Terms.PaymentPreimage, nil
+	case err != channeldb.ErrInvoiceNotFound:
+		return [32]byte{}, err
+	}
+
+	intents, err := u.db.FetchForwardingIntents()
+	if err != nil {
+		return [32]byte{}, err
+	}
+	for _, intent := range intents {
+		if intent.PaymentHash == paymentHash {
+			return intent.PaymentPreimage, nil
+		}
+	}
+
+	return [32]byte{}, fmt.Errorf("preimage unknown")
+}
+
 // incubator is tasked with watching over all outputs from channel closes as
 // they transition from being broadcast (at which point they move into the
 // "preschool state"), then confirmed and waiting for the necessary number of
@@ -377,6 +643,21 @@ out:
 					"kindergarten outputs: %v", err)
 			}
 
+			// Any sweeps which have remained unconfirmed for too
+			// long are replaced by versions paying a higher fee.
+			u.bumpSweeps()
+
+			// Force closes broadcast along with a child spending
+			// our anchor are rebroadcast until they confirm.
+			u.rebroadcastCloses()
+
+		case height := <-u.sweepConfs:
+			if _, ok := u.sweeps[height]; ok {
+				utxnLog.Infof("Sweep of outputs graduated at "+
+					"height %v has confirmed", height)
+				delete(u.sweeps, height)
+			}
+
 		case <-u.quit:
 			break out
 		}
@@ -460,6 +741,9 @@ func (k *kidOutput) waitForPromotion(db *channeldb.DB, confChan *chainntnfs.Conf
 		}
 
 		maturityHeight := k.confHeight + k.blocksToMaturity
+		if k.absoluteMaturity > maturityHeight {
+			maturityHeight = k.absoluteMaturity
+		}
 
 		heightBytes := make([]byte, 4)
 		byteOrder.PutUint32(heightBytes, maturityHeight)
@@ -509,7 +793,8 @@ func (u *utxoNursery) graduateKindergarten(blockHeight uint32) error {
 	// If we're able to graduate any outputs, then create a single
 	// transaction which sweeps them all into the wallet.
 	if len(kgtnOutputs) > 0 {
-		if err := sweepGraduatingOutputs(u.wallet, kgtnOutputs); err != nil {
+		err := u.sweepGraduatingOutputs(blockHeight, kgtnOutputs)
+		if err != nil {
 			return err
 		}
 	}
@@ -572,6 +857,7 @@ func fetchGraduatingOutputs(db *channeldb.DB, wallet *lnwallet.LightningWallet,
 	for _, kgtnOutput := range kgtnOutputs {
 		kgtnOutput.witnessFunc = kgtnOutput.witnessType.generateFunc(
 			&wallet.Signer, kgtnOutput.signDescriptor,
+			kgtnOutput.preimage,
 		)
 	}
 
@@ -581,24 +867,63 @@ func fetchGraduatingOutputs(db *channeldb.DB, wallet *lnwallet.LightningWallet,
 	return kgtnOutputs, nil
 }
 
+// pendingSweep is a sweep transaction which has been broadcast, but has yet to
+// confirm.
+type pendingSweep struct {
+	// outputs are the mature outputs spent by the sweep.
+	outputs []*kidOutput
+
+	// fee is the fee paid by the latest version of the sweep.
+	fee btcutil.Amount
+
+	// txid is the txid of the latest version of the sweep.
+	txid chainhash.Hash
+
+	// blocksWaited is the number of blocks which have been connected
+	// since the latest version of the sweep was broadcast.
+	blocksWaited uint32
+}
+
 // sweepGraduatingOutputs generates and broadcasts the transaction that
 // transfers control of funds from a channel commitment transaction to the
-// user's wallet.
-func sweepGraduatingOutputs(wallet *lnwallet.LightningWallet, kgtnOutputs []*kidOutput) error {
+// user's wallet. The sweep is then tracked until it confirms, so its fee may
+// be bumped if it lingers.
+func (u *utxoNursery) sweepGraduatingOutputs(graduationHeight uint32,
+	kgtnOutputs []*kidOutput) error {
+
 	// Create a transaction which sweeps all the newly mature outputs into
-	// a output controlled by the wallet.
+	// a output controlled by the wallet, paying the fee estimated to
+	// confirm it within a reasonable amount of time.
 	// TODO(roasbeef): can be more intelligent about buffering outputs to
 	// be more efficient on-chain.
-	sweepTx, err := createSweepTx(wallet, kgtnOutputs)
+	fee, err := estimateSweepFee(u.wallet, kgtnOutputs)
+	if err != nil {
+		utxnLog.Errorf("unable to estimate sweep fee: %v", err)
+		return err
+	}
+
+	sweepTx, err := createSweepTx(u.wallet, kgtnOutputs, fee)
 	if err != nil {
 		// TODO(roasbeef): retry logic?
 		utxnLog.Errorf("unable to create sweep tx: %v", err)
 		return err
 	}
 
+	// We'll track the sweep before broadcasting it, so it's rebroadcast
+	// with a higher fee even if it's initially rejected.
+	sweep := &pendingSweep{
+		outputs: kgtnOutputs,
+		fee:     fee,
+		txid:    sweepTx.TxHash(),
+	}
+	u.sweeps[graduationHeight] = sweep
+	if err := u.watchSweep(graduationHeight, &sweep.txid); err != nil {
+		return err
+	}
+
 	utxnLog.Infof("Sweeping %v time-locked outputs "+
-		"with sweep tx (txid=%v): %v", len(kgtnOutputs),
-		sweepTx.TxHash(),
+		"with sweep tx (txid=%v, fee=%v): %v", len(kgtnOutputs),
+		sweep.txid, fee,
 		newLogClosure(func() string {
 			return spew.Sdump(sweepTx)
 		}))
@@ -606,7 +931,7 @@ func sweepGraduatingOutputs(wallet *lnwallet.LightningWallet, kgtnOutputs []*kid
 	// With the sweep transaction fully signed, broadcast the transaction
 	// to the network. Additionally, we can stop tracking these outputs as
 	// they've just been swept.
-	if err := wallet.PublishTransaction(sweepTx); err != nil {
+	if err := u.wallet.PublishTransaction(sweepTx); err != nil {
 		utxnLog.Errorf("unable to broadcast sweep tx: %v, %v",
 			err, spew.Sdump(sweepTx))
 		return err
@@ -615,11 +940,134 @@ func sweepGraduatingOutputs(wallet *lnwallet.LightningWallet, kgtnOutputs []*kid
 	return nil
 }
 
+// watchSweep registers for the confirmation of the passed version of the sweep
+// of the outputs graduated at the passed height, notifying the incubator once
+// it confirms.
+func (u *utxoNursery) watchSweep(graduationHeight uint32,
+	txid *chainhash.Hash) error {
+
+	confChan, err := u.notifier.RegisterConfirmationsNtfn(txid, 1)
+	if err != nil {
+		utxnLog.Errorf("unable to register sweep tx %v for "+
+			"confirmation: %v", txid, err)
+		return err
+	}
+
+	go func() {
+		select {
+		case _, ok := <-confChan.Confirmed:
+			// If the ChainNotifier is shutting down, then so are
+			// we.
+			if !ok {
+				return
+			}
+
+			select {
+			case u.sweepConfs <- graduationHeight:
+			case <-u.quit:
+			}
+		case <-u.quit:
+		}
+	}()
+
+	return nil
+}
+
+// bumpSweeps is called with each new block, replacing each sweep which has
+// remained unconfirmed for cfg.SweepBumpBlocks blocks with a version paying
+// double the fee. The sweeps signal replaceability through the relative lock
+// times of their inputs, so each replacement evicts its predecessor from the
+// mempool.
+func (u *utxoNursery) bumpSweeps() {
+	if cfg.SweepBumpBlocks == 0 {
+		return
+	}
+
+	for height, sweep := range u.sweeps {
+		sweep.blocksWaited++
+		if sweep.blocksWaited < cfg.SweepBumpBlocks {
+			continue
+		}
+		sweep.blocksWaited = 0
+
+		// We won't spend more than half of the swept funds on fees,
+		// instead continuing to wait for the latest version to
+		// confirm.
+		var totalSum btcutil.Amount
+		for _, o := range sweep.outputs {
+			totalSum += o.amt
+		}
+		fee := sweep.fee * 2
+		if fee > totalSum/2 {
+			utxnLog.Warnf("Unable to bump fee of sweep tx %v, "+
+				"fee of %v would exceed half of the swept %v",
+				sweep.txid, fee, totalSum)
+			continue
+		}
+
+		sweepTx, err := createSweepTx(u.wallet, sweep.outputs, fee)
+		if err != nil {
+			utxnLog.Errorf("unable to create replacement for "+
+				"sweep tx %v: %v", sweep.txid, err)
+			continue
+		}
+
+		txid := sweepTx.TxHash()
+		utxnLog.Infof("Sweep tx %v unconfirmed after %v blocks, "+
+			"replacing with sweep tx %v paying fee of %v",
+			sweep.txid, cfg.SweepBumpBlocks, txid, fee)
+
+		if err := u.wallet.PublishTransaction(sweepTx); err != nil {
+			utxnLog.Errorf("unable to broadcast sweep tx: %v, %v",
+				err, spew.Sdump(sweepTx))
+			continue
+		}
+
+		sweep.fee = fee
+		sweep.txid = txid
+		u.watchSweep(height, &txid)
+	}
+}
+
+// estimateSweepFee returns the fee to be paid by the transaction sweeping the
+// passed outputs in order for it to confirm within sweepConfTarget blocks. If
+// the backend is unable to estimate fees, then defaultSweepFee is used.
+func estimateSweepFee(wallet *lnwallet.LightningWallet,
+	matureOutputs []*kidOutput) (btcutil.Amount, error) {
+
+	feeRate, err := wallet.EstimateFeePerByte(sweepConfTarget)
+	switch {
+	case err == lnwallet.ErrNoFeeEstimate:
+		return defaultSweepFee, nil
+	case err != nil:
+		utxnLog.Warnf("Unable to estimate sweep fee rate, using "+
+			"default fee of %v: %v", defaultSweepFee, err)
+		return defaultSweepFee, nil
+	}
+
+	// Fee rates below 1 sat/byte won't relay.
+	if feeRate < 1 {
+		feeRate = 1
+	}
+
+	// As the size of each witness depends on its type, we'll determine
+	// the size of the sweep by signing a version of it without any fee.
+	sweepTx, err := createSweepTx(wallet, matureOutputs, 0)
+	if err != nil {
+		return 0, err
+	}
+	weight := blockchain.GetTransactionWeight(btcutil.NewTx(sweepTx))
+	vsize := (weight + blockchain.WitnessScaleFactor - 1) /
+		blockchain.WitnessScaleFactor
+
+	return feeRate * btcutil.Amount(vsize), nil
+}
+
 // createSweepTx creates a final sweeping transaction with all witnesses in
 // place for all inputs. The created transaction has a single output sending
-// all the funds back to the source wallet.
+// all the funds, less the passed fee, back to the source wallet.
 func createSweepTx(wallet *lnwallet.LightningWallet,
-	matureOutputs []*kidOutput) (*wire.MsgTx, error) {
+	matureOutputs []*kidOutput, fee btcutil.Amount) (*wire.MsgTx, error) {
 
 	pkScript, err := newSweepPkScript(wallet)
 	if err != nil {
@@ -630,11 +1078,15 @@ func createSweepTx(wallet *lnwallet.LightningWallet,
 	for _, o := range matureOutputs {
 		totalSum += o.amt
 	}
+	if fee >= totalSum {
+		return nil, fmt.Errorf("sweep fee of %v exceeds swept "+
+			"amount of %v", fee, totalSum)
+	}
 
 	sweepTx := wire.NewMsgTx(2)
 	sweepTx.AddTxOut(&wire.TxOut{
 		PkScript: pkScript,
-		Value:    int64(totalSum - 5000),
+		Value:    int64(totalSum - fee),
 	})
 	for _, utxo := range matureOutputs {
 		sweepTx.AddTxIn(&wire.TxIn{
@@ -642,10 +1094,13 @@ func createSweepTx(wallet *lnwallet.LightningWallet,
 			// TODO(roasbeef): assumes pure block delays
 			Sequence: utxo.blocksToMaturity,
 		})
-	}
 
-	// TODO(roasbeef): insert fee calculation
-	//  * remove hardcoded fee above
+		// The lock time of the sweep must satisfy the absolute
+		// maturity of each of its inputs.
+		if utxo.absoluteMaturity > sweepTx.LockTime {
+			sweepTx.LockTime = utxo.absoluteMaturity
+		}
+	}
 
 	// With all the inputs in place, use each output's unique witness
 	// function to generate the final witness required for spending.
@@ -716,8 +1171,15 @@ func putLastHeightGraduated(db *channeldb.DB, blockheight uint32) error {
 // newSweepPkScript creates a new public key script which should be used to
 // sweep any time-locked, or contested channel funds into the wallet.
 // Specifically, the script generated is a version 0,
-// pay-to-witness-pubkey-hash (p2wkh) output.
+// pay-to-witness-pubkey-hash (p2wkh) output. If a sweep whitelist is
+// configured, the script pays to its first address instead.
 func newSweepPkScript(wallet lnwallet.WalletController) ([]byte, error) {
+	// If the operator has whitelisted the destinations of on-chain funds,
+	// then the funds are swept there rather than into the wallet.
+	if sweepAddr := cfg.sweepWhitelist.destination(); sweepAddr != nil {
+		return txscript.PayToAddrScript(sweepAddr)
+	}
+
 	sweepAddr, err := wallet.NewAddress(lnwallet.WitnessPubKey, false)
 	if err != nil {
 		return nil, err
@@ -794,8 +1256,26 @@ func serializeKidOutput(w io.Writer, kid *kidOutput) error {
 	}
 
 	byteOrder.PutUint32(scratch[:4], uint32(kid.signDescriptor.HashType))
-	_, err := w.Write(scratch[:4])
-	return err
+	if _, err := w.Write(scratch[:4]); err != nil {
+		return err
+	}
+
+	// HTLC outputs carry the additional details needed to claim them.
+	// They're written last, so the outputs of other witness types remain
+	// readable by earlier versions.
+	switch kid.witnessType {
+	case htlcOfferedTimeout:
+		byteOrder.PutUint32(scratch[:4], kid.absoluteMaturity)
+		if _, err := w.Write(scratch[:4]); err != nil {
+			return err
+		}
+	case htlcAcceptedSuccess:
+		if _, err := w.Write(kid.preimage[:]); err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
 
 // deserializeKidOutput takes a byte array representation of a kidOutput
@@ -850,7 +1330,8 @@ func deserializeKidOutput(r io.Reader) (*kidOutput, error) {
 	}
 	kid.signDescriptor.PrivateTweak = descPrivateTweak
 
-	descWitnessScript, err := wire.ReadVarBytes(r, 0, 100, "witnessScript")
+	descWitnessScript, err := wire.ReadVarBytes(r, 0, maxWitnessScriptSize,
+		"witnessScript")
 	if err != nil {
 		return nil, err
 	}
@@ -867,6 +1348,18 @@ func deserializeKidOutput(r io.Reader) (*kidOutput, error) {
 	}
 	kid.signDescriptor.HashType = txscript.SigHashType(byteOrder.Uint32(scratch[:4]))
 
+	switch kid.witnessType {
+	case htlcOfferedTimeout:
+		if _, err := io.ReadFull(r, scratch[:4]); err != nil {
+			return nil, err
+		}
+		kid.absoluteMaturity = byteOrder.Uint32(scratch[:4])
+	case htlcAcceptedSuccess:
+		if _, err := io.ReadFull(r, kid.preimage[:]); err != nil {
+			return nil, err
+		}
+	}
+
 	return kid, nil
 }
 
@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
+	"github.com/lightningnetwork/lnd/chainntnfs"
 	"github.com/lightningnetwork/lnd/lnwallet"
 	"github.com/roasbeef/btcd/btcec"
 	"github.com/roasbeef/btcd/chaincfg/chainhash"
@@ -243,3 +245,181 @@ func TestSerializeKidOutput(t *testing.T) {
 		t.Fatalf("kidOutputs don't match %+v vs %+v", kid, deserializedKid)
 	}
 }
+
+func TestSerializeHtlcKidOutputs(t *testing.T) {
+	descriptor := &signDescriptors[0]
+	pk, err := btcec.ParsePubKey(keys[0], btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to parse pub key: %v", keys[0])
+	}
+	descriptor.PubKey = pk
+
+	htlcKids := []*kidOutput{
+		{
+			amt:              btcutil.Amount(1e6),
+			outPoint:         outPoints[0],
+			blocksToMaturity: uint32(144),
+			confHeight:       uint32(1770001),
+			absoluteMaturity: uint32(1770100),
+			signDescriptor:   descriptor,
+			witnessType:      htlcOfferedTimeout,
+		},
+		{
+			amt:              btcutil.Amount(2e6),
+			outPoint:         outPoints[1],
+			blocksToMaturity: uint32(144),
+			confHeight:       uint32(1770001),
+			preimage:         [32]byte{0x01, 0x02, 0x03},
+			signDescriptor:   descriptor,
+			witnessType:      htlcAcceptedSuccess,
+		},
+	}
+
+	var b bytes.Buffer
+	for _, kid := range htlcKids {
+		if err := serializeKidOutput(&b, kid); err != nil {
+			t.Fatalf("unable to serialize kid output: %v", err)
+		}
+	}
+
+	kidList, err := deserializeKidList(&b)
+	if err != nil {
+		t.Fatalf("unable to deserialize kid output list: %v", err)
+	}
+	if len(kidList) != len(htlcKids) {
+		t.Fatalf("expected %v kid outputs, got %v", len(htlcKids),
+			len(kidList))
+	}
+	for i, kid := range htlcKids {
+		if !reflect.DeepEqual(kid, kidList[i]) {
+			t.Fatalf("kidOutputs don't match %+v vs %+v", kid,
+				kidList[i])
+		}
+	}
+}
+
+// mockConfNotifier is a ChainNotifier which hands out a confirmation event
+// for each registered txid. Only confirmation notifications are implemented.
+type mockConfNotifier struct {
+	chainntnfs.ChainNotifier
+
+	confs map[chainhash.Hash]*chainntnfs.ConfirmationEvent
+}
+
+func (m *mockConfNotifier) RegisterConfirmationsNtfn(txid *chainhash.Hash,
+	numConfs uint32) (*chainntnfs.ConfirmationEvent, error) {
+
+	confEvent := &chainntnfs.ConfirmationEvent{
+		Confirmed:    make(chan *chainntnfs.TxConfirmation, 1),
+		NegativeConf: make(chan int32, 1),
+	}
+	m.confs[*txid] = confEvent
+
+	return confEvent, nil
+}
+
+// mockBroadcaster is a WalletController which records the transactions it
+// broadcasts, refusing those it has been told to. Only the methods needed to
+// broadcast a force close are implemented.
+type mockBroadcaster struct {
+	lnwallet.WalletController
+
+	refused   map[chainhash.Hash]bool
+	published []chainhash.Hash
+}
+
+func (m *mockBroadcaster) PublishTransaction(tx *wire.MsgTx) error {
+	txid := tx.TxHash()
+	if m.refused[txid] {
+		return fmt.Errorf("tx %v refused", txid)
+	}
+
+	m.published = append(m.published, txid)
+	return nil
+}
+
+func (m *mockBroadcaster) UnlockOutpoint(o wire.OutPoint) {}
+
+// TestBroadcastClosePackage tests that a force close broadcast along with an
+// anchor child is rebroadcast as a unit until the close confirms, and that
+// the close is broadcast alone should the child be refused.
+func TestBroadcastClosePackage(t *testing.T) {
+	closeTx := wire.NewMsgTx(2)
+	closeTx.AddTxIn(wire.NewTxIn(&outPoints[0], nil, nil))
+	closeTx.AddTxOut(&wire.TxOut{Value: 330})
+	closeTxid := closeTx.TxHash()
+
+	childTx := wire.NewMsgTx(2)
+	childTx.AddTxIn(wire.NewTxIn(&wire.OutPoint{Hash: closeTxid}, nil, nil))
+	childTx.AddTxIn(wire.NewTxIn(&outPoints[1], nil, nil))
+	childTx.AddTxOut(&wire.TxOut{Value: 50000})
+	childTxid := childTx.TxHash()
+
+	notifier := &mockConfNotifier{
+		confs: make(map[chainhash.Hash]*chainntnfs.ConfirmationEvent),
+	}
+	broadcaster := &mockBroadcaster{}
+	nursery := newUtxoNursery(nil, notifier,
+		&lnwallet.LightningWallet{WalletController: broadcaster})
+
+	assertPublished := func(expected ...chainhash.Hash) {
+		if !reflect.DeepEqual(broadcaster.published, expected) {
+			t.Fatalf("expected %v to be broadcast, instead got %v",
+				expected, broadcaster.published)
+		}
+		broadcaster.published = nil
+	}
+
+	anchorSpent, err := nursery.broadcastClose(closeTx, childTx)
+	if err != nil {
+		t.Fatalf("unable to broadcast close: %v", err)
+	}
+	if !anchorSpent {
+		t.Fatalf("expected anchor to be spent by the child")
+	}
+	assertPublished(closeTxid, childTxid)
+
+	// With each new block, the close is rebroadcast along with its child.
+	nursery.rebroadcastCloses()
+	assertPublished(closeTxid, childTxid)
+
+	// Once the close confirms, the package is no longer rebroadcast.
+	notifier.confs[closeTxid].Confirmed <- &chainntnfs.TxConfirmation{}
+	for i := 0; ; i++ {
+		nursery.RLock()
+		numPackages := len(nursery.closePackages)
+		nursery.RUnlock()
+		if numPackages == 0 {
+			break
+		}
+		if i == 100 {
+			t.Fatalf("package still tracked after the close " +
+				"confirmed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	nursery.rebroadcastCloses()
+	assertPublished()
+
+	// Should the child be refused, the close is still broadcast, but our
+	// anchor remains unspent, and nothing is tracked.
+	broadcaster.refused = map[chainhash.Hash]bool{childTxid: true}
+	anchorSpent, err = nursery.broadcastClose(closeTx, childTx)
+	if err != nil {
+		t.Fatalf("unable to broadcast close: %v", err)
+	}
+	if anchorSpent {
+		t.Fatalf("expected anchor to remain unspent")
+	}
+	assertPublished(closeTxid)
+	if len(nursery.closePackages) != 0 {
+		t.Fatalf("expected no package to be tracked")
+	}
+
+	// Should the close itself be refused, then so is the force close.
+	broadcaster.refused = map[chainhash.Hash]bool{closeTxid: true}
+	if _, err := nursery.broadcastClose(closeTx, childTx); err == nil {
+		t.Fatalf("expected refused close to fail")
+	}
+	assertPublished()
+}
@@ -0,0 +1,672 @@
+package virtualchain
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/roasbeef/btcd/blockchain"
+	"github.com/roasbeef/btcd/chaincfg"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/txscript"
+	"github.com/roasbeef/btcd/wire"
+)
+
+var (
+	// ErrBlockNotFound is returned when a requested block isn't part of
+	// the chain.
+	ErrBlockNotFound = errors.New("block not found")
+
+	// ErrTxNotFound is returned when a requested transaction is neither
+	// confirmed, nor within the mempool.
+	ErrTxNotFound = errors.New("transaction not found")
+
+	// ErrOutputSpent is returned when a requested output isn't part of the
+	// confirmed set of unspent outputs.
+	ErrOutputSpent = errors.New("target output has been spent")
+)
+
+// Backend is a source of the virtual chain. It's implemented by Chain, which
+// holds the chain in memory, and by Client, which reaches a Chain served by
+// another process.
+type Backend interface {
+	// BestBlock returns the hash and height of the tip of the chain.
+	BestBlock() (*chainhash.Hash, int32, error)
+
+	// BlockHash returns the hash of the block at the passed height.
+	BlockHash(height int32) (*chainhash.Hash, error)
+
+	// Block returns the block with the passed hash.
+	Block(hash *chainhash.Hash) (*wire.MsgBlock, error)
+
+	// Transaction returns the transaction with the passed txid, along
+	// with the height of the block confirming it, or -1 if it's within
+	// the mempool.
+	Transaction(txid *chainhash.Hash) (*wire.MsgTx, int32, error)
+
+	// Utxo returns the confirmed unspent output referenced by the passed
+	// outpoint. If there's no such output, ErrOutputSpent is returned.
+	Utxo(outpoint *wire.OutPoint) (*wire.TxOut, error)
+
+	// Publish validates the passed transaction, then accepts it to the
+	// mempool.
+	Publish(tx *wire.MsgTx) error
+
+	// Mine mines the passed number of blocks on top of the chain, paying
+	// their subsidy and fees to pkScript. The first block confirms the
+	// whole mempool. The hashes of the new blocks are returned.
+	Mine(numBlocks uint32, pkScript []byte) ([]chainhash.Hash, error)
+
+	// AdvanceTime moves the virtual clock of the chain forward by the
+	// passed duration, returning the new time.
+	AdvanceTime(d time.Duration) (time.Time, error)
+
+	// Now returns the current time of the virtual clock.
+	Now() (time.Time, error)
+
+	// Events returns the events of the chain starting with the one at the
+	// passed index. If there's no such event, then it blocks until there
+	// is, or the passed channel is closed, in which case no events are
+	// returned.
+	Events(from uint64, quit <-chan struct{}) ([]*Event, error)
+}
+
+// Event is a change to the virtual chain. Exactly one of its fields is set.
+type Event struct {
+	// Accepted is a transaction accepted to the mempool.
+	Accepted *wire.MsgTx
+
+	// Evicted is the txid of a transaction evicted from the mempool, as it
+	// was replaced by a conflicting transaction paying a higher fee.
+	Evicted *chainhash.Hash
+
+	// Block is a block connected to the tip of the chain.
+	Block *wire.MsgBlock
+}
+
+// txEntry is a transaction known to the chain.
+type txEntry struct {
+	tx *wire.MsgTx
+
+	// height is the height of the block confirming the transaction, or -1
+	// if it's within the mempool.
+	height int32
+
+	// fee is the fee paid by the transaction.
+	fee int64
+}
+
+// utxoEntry is an unspent output, along with the height and time of the block
+// confirming it.
+type utxoEntry struct {
+	output *wire.TxOut
+	height int32
+	time   time.Time
+}
+
+// Chain is a blockchain held entirely in memory, whose blocks, and clock, only
+// advance on demand. Blocks carry no proof of work, and are never re-orged,
+// but the transactions accepted to the mempool are validated as they would be
+// by a full node, including their scripts, and their absolute and relative
+// time locks. Coinbase outputs may be spent immediately.
+//
+// Each mined block advances the virtual clock by the block interval, and the
+// clock may be advanced further without mining. Time based locks are
+// evaluated against the virtual clock rather than the median time past.
+type Chain struct {
+	params        *chaincfg.Params
+	blockInterval time.Duration
+
+	mtx sync.Mutex
+
+	// now is the current time of the virtual clock.
+	now time.Time
+
+	// blocks are the blocks of the chain, indexed by height.
+	blocks     []*wire.MsgBlock
+	blockIndex map[chainhash.Hash]int32
+
+	// txIndex holds every transaction confirmed by the chain, or within
+	// the mempool, apart from the genesis coinbase.
+	txIndex map[chainhash.Hash]*txEntry
+
+	// utxos is the set of confirmed unspent outputs.
+	utxos map[wire.OutPoint]*utxoEntry
+
+	// mempool holds the unconfirmed transactions, in the order they were
+	// accepted, so each transaction follows its unconfirmed parents.
+	mempool []*wire.MsgTx
+
+	// events is the log of every change to the chain. eventSignal is
+	// closed, and replaced, each time an event is appended.
+	events      []*Event
+	eventSignal chan struct{}
+}
+
+// A compile time check to ensure Chain implements the Backend interface.
+var _ Backend = (*Chain)(nil)
+
+// NewChain creates a new virtual chain holding only the genesis block of the
+// passed network, with its clock set to start. Each mined block advances the
+// clock by blockInterval.
+func NewChain(params *chaincfg.Params, start time.Time,
+	blockInterval time.Duration) (*Chain, error) {
+
+	if params.GenesisBlock == nil {
+		return nil, fmt.Errorf("genesis block of %v is unknown",
+			params.Name)
+	}
+
+	genesisHash := params.GenesisBlock.BlockHash()
+	return &Chain{
+		params:        params,
+		blockInterval: blockInterval,
+		now:           start,
+		blocks:        []*wire.MsgBlock{params.GenesisBlock},
+		blockIndex:    map[chainhash.Hash]int32{genesisHash: 0},
+		txIndex:       make(map[chainhash.Hash]*txEntry),
+		utxos:         make(map[wire.OutPoint]*utxoEntry),
+		eventSignal:   make(chan struct{}),
+	}, nil
+}
+
+// BestBlock returns the hash and height of the tip of the chain.
+//
+// This is part of the Backend interface.
+func (c *Chain) BestBlock() (*chainhash.Hash, int32, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	height := int32(len(c.blocks) - 1)
+	hash := c.blocks[height].BlockHash()
+	return &hash, height, nil
+}
+
+// BlockHash returns the hash of the block at the passed height.
+//
+// This is part of the Backend interface.
+func (c *Chain) BlockHash(height int32) (*chainhash.Hash, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if height < 0 || int(height) >= len(c.blocks) {
+		return nil, ErrBlockNotFound
+	}
+
+	hash := c.blocks[height].BlockHash()
+	return &hash, nil
+}
+
+// Block returns the block with the passed hash.
+//
+// This is part of the Backend interface.
+func (c *Chain) Block(hash *chainhash.Hash) (*wire.MsgBlock, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	height, ok := c.blockIndex[*hash]
+	if !ok {
+		return nil, ErrBlockNotFound
+	}
+
+	return c.blocks[height], nil
+}
+
+// Transaction returns the transaction with the passed txid, along with the
+// height of the block confirming it, or -1 if it's within the mempool.
+//
+// This is part of the Backend interface.
+func (c *Chain) Transaction(txid *chainhash.Hash) (*wire.MsgTx, int32, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	entry, ok := c.txIndex[*txid]
+	if !ok {
+		return nil, 0, ErrTxNotFound
+	}
+
+	return entry.tx, entry.height, nil
+}
+
+// Utxo returns the confirmed unspent output referenced by the passed
+// outpoint. Spends within the mempool aren't taken into account.
+//
+// This is part of the Backend interface.
+func (c *Chain) Utxo(outpoint *wire.OutPoint) (*wire.TxOut, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	entry, ok := c.utxos[*outpoint]
+	if !ok {
+		return nil, ErrOutputSpent
+	}
+
+	return entry.output, nil
+}
+
+// Publish validates the passed transaction against the confirmed chain and
+// the mempool, then accepts it to the mempool. A transaction conflicting with
+// transactions within the mempool replaces them, along with their
+// descendants, if it pays a higher fee than all of them combined. Publishing
+// a transaction which is already known is a no-op.
+//
+// This is part of the Backend interface.
+func (c *Chain) Publish(tx *wire.MsgTx) error {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	txid := tx.TxHash()
+	if _, ok := c.txIndex[txid]; ok {
+		return nil
+	}
+	if blockchain.IsCoinBaseTx(tx) {
+		return fmt.Errorf("coinbase transaction %v can't be "+
+			"published", txid)
+	}
+
+	// Find the transactions within the mempool our transaction conflicts
+	// with, along with their descendants, which are evicted should our
+	// transaction be accepted.
+	evicted := make(map[chainhash.Hash]struct{})
+	_, spenders := c.mempoolView(nil)
+	for _, txIn := range tx.TxIn {
+		if spender, ok := spenders[txIn.PreviousOutPoint]; ok {
+			evicted[spender] = struct{}{}
+		}
+	}
+	var evictedFees int64
+	if len(evicted) != 0 {
+		for _, mempoolTx := range c.mempool {
+			mempoolTxid := mempoolTx.TxHash()
+			for _, txIn := range mempoolTx.TxIn {
+				parent := txIn.PreviousOutPoint.Hash
+				if _, ok := evicted[parent]; ok {
+					evicted[mempoolTxid] = struct{}{}
+					break
+				}
+			}
+			if _, ok := evicted[mempoolTxid]; ok {
+				evictedFees += c.txIndex[mempoolTxid].fee
+			}
+		}
+	}
+
+	view, _ := c.mempoolView(evicted)
+	fee, err := c.validateTx(tx, view)
+	if err != nil {
+		return fmt.Errorf("transaction %v rejected: %v", txid, err)
+	}
+	if len(evicted) != 0 && fee <= evictedFees {
+		return fmt.Errorf("transaction %v rejected: fee of %v doesn't "+
+			"exceed the fees of %v of the transactions it replaces",
+			txid, fee, evictedFees)
+	}
+
+	// The transaction is valid, so the transactions it replaces are
+	// evicted, descendants first.
+	for i := len(c.mempool) - 1; i >= 0; i-- {
+		mempoolTxid := c.mempool[i].TxHash()
+		if _, ok := evicted[mempoolTxid]; !ok {
+			continue
+		}
+
+		c.mempool = append(c.mempool[:i], c.mempool[i+1:]...)
+		delete(c.txIndex, mempoolTxid)
+		c.appendEvent(&Event{Evicted: &mempoolTxid})
+	}
+
+	c.mempool = append(c.mempool, tx)
+	c.txIndex[txid] = &txEntry{
+		tx:     tx,
+		height: -1,
+		fee:    fee,
+	}
+	c.appendEvent(&Event{Accepted: tx})
+
+	return nil
+}
+
+// mempoolView returns the set of unspent outputs as of the mempool, leaving
+// out the passed transactions, along with the txid of the mempool transaction
+// spending each confirmed or unconfirmed output. Unconfirmed outputs are
+// given a height of -1.
+//
+// NOTE: The chain's mutex MUST be held when calling this method.
+func (c *Chain) mempoolView(exclude map[chainhash.Hash]struct{}) (
+	map[wire.OutPoint]*utxoEntry, map[wire.OutPoint]chainhash.Hash) {
+
+	view := make(map[wire.OutPoint]*utxoEntry, len(c.utxos))
+	for outpoint, entry := range c.utxos {
+		view[outpoint] = entry
+	}
+
+	spenders := make(map[wire.OutPoint]chainhash.Hash)
+	for _, tx := range c.mempool {
+		txid := tx.TxHash()
+		if _, ok := exclude[txid]; ok {
+			continue
+		}
+
+		for _, txIn := range tx.TxIn {
+			delete(view, txIn.PreviousOutPoint)
+			spenders[txIn.PreviousOutPoint] = txid
+		}
+		for i, txOut := range tx.TxOut {
+			outpoint := wire.OutPoint{Hash: txid, Index: uint32(i)}
+			view[outpoint] = &utxoEntry{
+				output: txOut,
+				height: -1,
+			}
+		}
+	}
+
+	return view, spenders
+}
+
+// validateTx checks that the passed transaction may be included within the
+// next block, spending outputs of the passed view. The fee paid by the
+// transaction is returned.
+//
+// NOTE: The chain's mutex MUST be held when calling this method.
+func (c *Chain) validateTx(tx *wire.MsgTx, view map[wire.OutPoint]*utxoEntry) (
+	int64, error) {
+
+	if len(tx.TxIn) == 0 || len(tx.TxOut) == 0 {
+		return 0, fmt.Errorf("transaction has no inputs or outputs")
+	}
+
+	nextHeight := int32(len(c.blocks))
+
+	// The absolute lock time of the transaction must have passed, unless
+	// each of its inputs opts out of it.
+	if tx.LockTime != 0 {
+		final := true
+		for _, txIn := range tx.TxIn {
+			if txIn.Sequence != wire.MaxTxInSequenceNum {
+				final = false
+			}
+		}
+
+		switch {
+		case final:
+		case tx.LockTime < txscript.LockTimeThreshold:
+			if int64(tx.LockTime) >= int64(nextHeight) {
+				return 0, fmt.Errorf("lock time height %v "+
+					"not reached", tx.LockTime)
+			}
+		default:
+			if int64(tx.LockTime) >= c.now.Unix() {
+				return 0, fmt.Errorf("lock time %v not "+
+					"reached", tx.LockTime)
+			}
+		}
+	}
+
+	var inputValue, outputValue int64
+	sigHashes := txscript.NewTxSigHashes(tx)
+	for i, txIn := range tx.TxIn {
+		entry, ok := view[txIn.PreviousOutPoint]
+		if !ok {
+			return 0, fmt.Errorf("input %v is missing or spent",
+				txIn.PreviousOutPoint)
+		}
+		inputValue += entry.output.Value
+
+		if err := c.checkSequenceLock(tx, txIn, entry, nextHeight); err != nil {
+			return 0, err
+		}
+
+		vm, err := txscript.NewEngine(entry.output.PkScript, tx, i,
+			txscript.StandardVerifyFlags, nil, sigHashes,
+			entry.output.Value)
+		if err != nil {
+			return 0, err
+		}
+		if err := vm.Execute(); err != nil {
+			return 0, fmt.Errorf("input %v fails script "+
+				"validation: %v", i, err)
+		}
+	}
+
+	for _, txOut := range tx.TxOut {
+		if txOut.Value < 0 {
+			return 0, fmt.Errorf("negative output value")
+		}
+		outputValue += txOut.Value
+	}
+	if outputValue > inputValue {
+		return 0, fmt.Errorf("outputs of %v exceed inputs of %v",
+			outputValue, inputValue)
+	}
+
+	return inputValue - outputValue, nil
+}
+
+// checkSequenceLock checks that the relative lock time of the passed input,
+// as defined by BIP 68, has passed by the next block.
+//
+// NOTE: The chain's mutex MUST be held when calling this method.
+func (c *Chain) checkSequenceLock(tx *wire.MsgTx, txIn *wire.TxIn,
+	entry *utxoEntry, nextHeight int32) error {
+
+	if tx.Version < 2 || txIn.Sequence&wire.SequenceLockTimeDisabled != 0 {
+		return nil
+	}
+
+	relativeLock := int64(txIn.Sequence & wire.SequenceLockTimeMask)
+	if relativeLock == 0 {
+		return nil
+	}
+
+	// Unconfirmed outputs can't be spent by an input with a relative
+	// lock, as the lock only starts with their confirmation.
+	if entry.height == -1 {
+		return fmt.Errorf("input %v has a relative lock, but spends "+
+			"an unconfirmed output", txIn.PreviousOutPoint)
+	}
+
+	if txIn.Sequence&wire.SequenceLockTimeIsSeconds != 0 {
+		lockTime := entry.time.Unix() +
+			relativeLock<<wire.SequenceLockTimeGranularity
+		if lockTime > c.now.Unix() {
+			return fmt.Errorf("relative lock time of input %v "+
+				"not reached", txIn.PreviousOutPoint)
+		}
+		return nil
+	}
+
+	if int64(entry.height)+relativeLock > int64(nextHeight) {
+		return fmt.Errorf("relative lock height of input %v not "+
+			"reached", txIn.PreviousOutPoint)
+	}
+
+	return nil
+}
+
+// Mine mines the passed number of blocks on top of the chain, paying their
+// subsidy and fees to pkScript. The first block confirms the whole mempool.
+//
+// This is part of the Backend interface.
+func (c *Chain) Mine(numBlocks uint32, pkScript []byte) ([]chainhash.Hash,
+	error) {
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	hashes := make([]chainhash.Hash, 0, numBlocks)
+	for i := uint32(0); i < numBlocks; i++ {
+		block, err := c.mineBlock(pkScript)
+		if err != nil {
+			return nil, err
+		}
+		hashes = append(hashes, block.BlockHash())
+	}
+
+	return hashes, nil
+}
+
+// mineBlock connects a new block confirming the whole mempool to the tip of
+// the chain.
+//
+// NOTE: The chain's mutex MUST be held when calling this method.
+func (c *Chain) mineBlock(pkScript []byte) (*wire.MsgBlock, error) {
+	height := int32(len(c.blocks))
+	c.now = c.now.Add(c.blockInterval)
+
+	// The height is committed to within the coinbase, so the coinbase of
+	// each block is unique.
+	heightScript, err := txscript.NewScriptBuilder().
+		AddInt64(int64(height)).Script()
+	if err != nil {
+		return nil, err
+	}
+
+	var fees int64
+	for _, tx := range c.mempool {
+		fees += c.txIndex[tx.TxHash()].fee
+	}
+
+	coinbase := wire.NewMsgTx(1)
+	coinbase.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{
+			Index: wire.MaxPrevOutIndex,
+		},
+		SignatureScript: heightScript,
+		Sequence:        wire.MaxTxInSequenceNum,
+	})
+	coinbase.AddTxOut(&wire.TxOut{
+		Value:    blockchain.CalcBlockSubsidy(height, c.params) + fees,
+		PkScript: pkScript,
+	})
+
+	txns := append([]*wire.MsgTx{coinbase}, c.mempool...)
+	block := wire.NewMsgBlock(&wire.BlockHeader{
+		Version:    4,
+		PrevBlock:  c.blocks[height-1].BlockHash(),
+		MerkleRoot: merkleRoot(txns),
+		Timestamp:  c.now,
+		Bits:       c.params.PowLimitBits,
+		Nonce:      uint32(height),
+	})
+	for _, tx := range txns {
+		if err := block.AddTransaction(tx); err != nil {
+			return nil, err
+		}
+	}
+
+	c.blocks = append(c.blocks, block)
+	c.blockIndex[block.BlockHash()] = height
+	c.mempool = nil
+
+	for i, tx := range txns {
+		txid := tx.TxHash()
+		if i == 0 {
+			c.txIndex[txid] = &txEntry{tx: tx}
+		}
+		c.txIndex[txid].height = height
+
+		if i != 0 {
+			for _, txIn := range tx.TxIn {
+				delete(c.utxos, txIn.PreviousOutPoint)
+			}
+		}
+		for j, txOut := range tx.TxOut {
+			outpoint := wire.OutPoint{Hash: txid, Index: uint32(j)}
+			c.utxos[outpoint] = &utxoEntry{
+				output: txOut,
+				height: height,
+				time:   c.now,
+			}
+		}
+	}
+
+	c.appendEvent(&Event{Block: block})
+
+	return block, nil
+}
+
+// merkleRoot returns the merkle root of the passed transactions.
+func merkleRoot(txns []*wire.MsgTx) chainhash.Hash {
+	level := make([]chainhash.Hash, len(txns))
+	for i, tx := range txns {
+		level[i] = tx.TxHash()
+	}
+
+	for len(level) > 1 {
+		if len(level)%2 != 0 {
+			level = append(level, level[len(level)-1])
+		}
+
+		next := make([]chainhash.Hash, len(level)/2)
+		for i := range next {
+			var pair [chainhash.HashSize * 2]byte
+			copy(pair[:chainhash.HashSize], level[2*i][:])
+			copy(pair[chainhash.HashSize:], level[2*i+1][:])
+			next[i] = chainhash.DoubleHashH(pair[:])
+		}
+		level = next
+	}
+
+	return level[0]
+}
+
+// AdvanceTime moves the virtual clock forward by the passed duration.
+//
+// This is part of the Backend interface.
+func (c *Chain) AdvanceTime(d time.Duration) (time.Time, error) {
+	if d < 0 {
+		return time.Time{}, fmt.Errorf("time can't be moved backwards")
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.now = c.now.Add(d)
+	return c.now, nil
+}
+
+// Now returns the current time of the virtual clock.
+//
+// This is part of the Backend interface.
+func (c *Chain) Now() (time.Time, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return c.now, nil
+}
+
+// Events returns the events of the chain starting with the one at the passed
+// index, blocking until there is such an event, or the passed channel is
+// closed.
+//
+// This is part of the Backend interface.
+func (c *Chain) Events(from uint64, quit <-chan struct{}) ([]*Event, error) {
+	for {
+		c.mtx.Lock()
+		if from < uint64(len(c.events)) {
+			events := c.events[from:]
+			c.mtx.Unlock()
+			return events, nil
+		}
+		signal := c.eventSignal
+		c.mtx.Unlock()
+
+		select {
+		case <-signal:
+		case <-quit:
+			return nil, nil
+		}
+	}
+}
+
+// appendEvent appends the passed event to the log, waking any caller waiting
+// for new events.
+//
+// NOTE: The chain's mutex MUST be held when calling this method.
+func (c *Chain) appendEvent(event *Event) {
+	c.events = append(c.events, event)
+
+	close(c.eventSignal)
+	c.eventSignal = make(chan struct{})
+}
@@ -0,0 +1,293 @@
+package virtualchain
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/chaincfg"
+	"github.com/roasbeef/btcd/txscript"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+// testKey returns a private key, along with the p2wkh script paying to it.
+func testKey(t *testing.T) (*btcec.PrivateKey, []byte) {
+	privKey, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+
+	addr, err := btcutil.NewAddressWitnessPubKeyHash(
+		btcutil.Hash160(privKey.PubKey().SerializeCompressed()),
+		&chaincfg.SimNetParams)
+	if err != nil {
+		t.Fatalf("unable to create address: %v", err)
+	}
+	pkScript, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		t.Fatalf("unable to create script: %v", err)
+	}
+
+	return privKey, pkScript
+}
+
+// spendTx returns a transaction spending the passed p2wkh output to pkScript,
+// paying the passed fee, with the passed version, input sequence and lock
+// time.
+func spendTx(t *testing.T, prevOut wire.OutPoint, output *wire.TxOut,
+	privKey *btcec.PrivateKey, pkScript []byte, fee int64, version int32,
+	sequence, lockTime uint32) *wire.MsgTx {
+
+	tx := wire.NewMsgTx(version)
+	tx.LockTime = lockTime
+	tx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: prevOut,
+		Sequence:         sequence,
+	})
+	tx.AddTxOut(wire.NewTxOut(output.Value-fee, pkScript))
+
+	witness, err := txscript.WitnessScript(tx, txscript.NewTxSigHashes(tx),
+		0, output.Value, output.PkScript, txscript.SigHashAll, privKey,
+		true)
+	if err != nil {
+		t.Fatalf("unable to sign transaction: %v", err)
+	}
+	tx.TxIn[0].Witness = witness
+
+	return tx
+}
+
+// TestChain tests that the virtual chain mines blocks on demand, validates the
+// transactions published to it, including their time locks, replaces
+// transactions within its mempool by ones paying a higher fee, and reports
+// each change as an event.
+func TestChain(t *testing.T) {
+	start := time.Unix(1500000000, 0)
+	chain, err := NewChain(&chaincfg.SimNetParams, start, 10*time.Minute)
+	if err != nil {
+		t.Fatalf("unable to create chain: %v", err)
+	}
+
+	privKey, pkScript := testKey(t)
+
+	// Mining a block pays its subsidy to our key, and advances the clock.
+	hashes, err := chain.Mine(2, pkScript)
+	if err != nil {
+		t.Fatalf("unable to mine blocks: %v", err)
+	}
+	if len(hashes) != 2 {
+		t.Fatalf("expected 2 blocks, got %v", len(hashes))
+	}
+	bestHash, bestHeight, err := chain.BestBlock()
+	if err != nil || bestHeight != 2 || *bestHash != hashes[1] {
+		t.Fatalf("unexpected best block %v at %v: %v", bestHash,
+			bestHeight, err)
+	}
+	if now, _ := chain.Now(); !now.Equal(start.Add(20 * time.Minute)) {
+		t.Fatalf("expected clock to advance by 20 minutes, got %v", now)
+	}
+
+	block, err := chain.Block(&hashes[0])
+	if err != nil {
+		t.Fatalf("unable to fetch block: %v", err)
+	}
+	coinbase := block.Transactions[0]
+	prevOut := wire.OutPoint{Hash: coinbase.TxHash()}
+	output, err := chain.Utxo(&prevOut)
+	if err != nil {
+		t.Fatalf("unable to fetch coinbase output: %v", err)
+	}
+
+	// A transaction spending a missing output, or carrying an invalid
+	// signature, is rejected.
+	_, otherScript := testKey(t)
+	missingTx := spendTx(t, wire.OutPoint{Index: 1}, output, privKey,
+		otherScript, 1000, 1, wire.MaxTxInSequenceNum, 0)
+	if err := chain.Publish(missingTx); err == nil {
+		t.Fatalf("transaction spending missing output accepted")
+	}
+	otherKey, _ := testKey(t)
+	badSigTx := spendTx(t, prevOut, output, otherKey, otherScript, 1000, 1,
+		wire.MaxTxInSequenceNum, 0)
+	if err := chain.Publish(badSigTx); err == nil {
+		t.Fatalf("transaction with invalid signature accepted")
+	}
+
+	// A transaction with a relative lock of 5 blocks isn't accepted until
+	// its input is 5 blocks deep.
+	lockedTx := spendTx(t, prevOut, output, privKey, otherScript, 1000, 2,
+		5, 0)
+	if err := chain.Publish(lockedTx); err == nil {
+		t.Fatalf("transaction accepted before its relative lock")
+	}
+	if _, err := chain.Mine(3, pkScript); err != nil {
+		t.Fatalf("unable to mine blocks: %v", err)
+	}
+	if err := chain.Publish(lockedTx); err != nil {
+		t.Fatalf("unable to publish transaction: %v", err)
+	}
+	lockedTxid := lockedTx.TxHash()
+	if _, height, err := chain.Transaction(&lockedTxid); err != nil ||
+		height != -1 {
+
+		t.Fatalf("expected transaction within mempool, got %v: %v",
+			height, err)
+	}
+
+	// A conflicting transaction paying a lower fee is rejected, while one
+	// paying a higher fee replaces it.
+	lowFeeTx := spendTx(t, prevOut, output, privKey, pkScript, 500, 1,
+		wire.MaxTxInSequenceNum, 0)
+	if err := chain.Publish(lowFeeTx); err == nil {
+		t.Fatalf("replacement paying a lower fee accepted")
+	}
+	highFeeTx := spendTx(t, prevOut, output, privKey, pkScript, 2000, 1,
+		wire.MaxTxInSequenceNum, 0)
+	if err := chain.Publish(highFeeTx); err != nil {
+		t.Fatalf("unable to publish replacement: %v", err)
+	}
+	if _, _, err := chain.Transaction(&lockedTxid); err != ErrTxNotFound {
+		t.Fatalf("replaced transaction still known: %v", err)
+	}
+
+	// A transaction time locked in the future is only accepted once the
+	// clock reaches it.
+	highFeeOut := wire.OutPoint{Hash: highFeeTx.TxHash()}
+	now, _ := chain.Now()
+	timeLockedTx := spendTx(t, highFeeOut, highFeeTx.TxOut[0], privKey,
+		otherScript, 1000, 1, 0, uint32(now.Add(time.Hour).Unix()))
+	if err := chain.Publish(timeLockedTx); err == nil {
+		t.Fatalf("transaction accepted before its lock time")
+	}
+	if _, err := chain.AdvanceTime(2 * time.Hour); err != nil {
+		t.Fatalf("unable to advance time: %v", err)
+	}
+	if err := chain.Publish(timeLockedTx); err != nil {
+		t.Fatalf("unable to publish transaction: %v", err)
+	}
+
+	// Mining a block confirms the mempool, and pays its fees to the miner.
+	hashes, err = chain.Mine(1, pkScript)
+	if err != nil {
+		t.Fatalf("unable to mine block: %v", err)
+	}
+	block, err = chain.Block(&hashes[0])
+	if err != nil {
+		t.Fatalf("unable to fetch block: %v", err)
+	}
+	if len(block.Transactions) != 3 {
+		t.Fatalf("expected 3 transactions, got %v",
+			len(block.Transactions))
+	}
+	subsidy := output.Value
+	if fees := block.Transactions[0].TxOut[0].Value - subsidy; fees != 3000 {
+		t.Fatalf("expected fees of 3000, got %v", fees)
+	}
+	if _, err := chain.Utxo(&prevOut); err != ErrOutputSpent {
+		t.Fatalf("expected spent output, got %v", err)
+	}
+	timeLockedTxid := timeLockedTx.TxHash()
+	if _, height, err := chain.Transaction(&timeLockedTxid); err != nil ||
+		height != 6 {
+
+		t.Fatalf("expected transaction at height 6, got %v: %v",
+			height, err)
+	}
+
+	// Each change to the chain is reported as an event, in order.
+	quit := make(chan struct{})
+	close(quit)
+	events, err := chain.Events(0, quit)
+	if err != nil {
+		t.Fatalf("unable to fetch events: %v", err)
+	}
+	var kinds []string
+	for _, event := range events {
+		switch {
+		case event.Accepted != nil:
+			kinds = append(kinds, "accepted")
+		case event.Evicted != nil:
+			kinds = append(kinds, "evicted")
+		case event.Block != nil:
+			kinds = append(kinds, "block")
+		}
+	}
+	expected := []string{"block", "block", "block", "block", "block",
+		"accepted", "evicted", "accepted", "accepted", "block"}
+	if len(kinds) != len(expected) {
+		t.Fatalf("expected events %v, got %v", expected, kinds)
+	}
+	for i := range kinds {
+		if kinds[i] != expected[i] {
+			t.Fatalf("expected events %v, got %v", expected, kinds)
+		}
+	}
+
+	// With no further events, the call returns once quit is closed.
+	events, err = chain.Events(uint64(len(expected)), quit)
+	if err != nil || len(events) != 0 {
+		t.Fatalf("expected no events, got %v: %v", len(events), err)
+	}
+}
+
+// TestRemoteChain tests that a chain served over the network may be driven,
+// and followed, by a client.
+func TestRemoteChain(t *testing.T) {
+	chain, err := NewChain(&chaincfg.SimNetParams, time.Unix(1500000000, 0),
+		10*time.Minute)
+	if err != nil {
+		t.Fatalf("unable to create chain: %v", err)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("unable to listen: %v", err)
+	}
+	defer listener.Close()
+	if err := Serve(chain, listener); err != nil {
+		t.Fatalf("unable to serve chain: %v", err)
+	}
+
+	client, err := Dial(listener.Addr().String())
+	if err != nil {
+		t.Fatalf("unable to dial chain: %v", err)
+	}
+	defer client.Close()
+
+	// A client waiting for events is woken up by a block mined through
+	// another client.
+	eventsChan := make(chan []*Event, 1)
+	go func() {
+		events, err := client.Events(0, make(chan struct{}))
+		if err != nil {
+			t.Errorf("unable to fetch events: %v", err)
+		}
+		eventsChan <- events
+	}()
+
+	_, pkScript := testKey(t)
+	hashes, err := client.Mine(1, pkScript)
+	if err != nil {
+		t.Fatalf("unable to mine block: %v", err)
+	}
+
+	select {
+	case events := <-eventsChan:
+		if len(events) != 1 || events[0].Block == nil ||
+			events[0].Block.BlockHash() != hashes[0] {
+
+			t.Fatalf("expected event for block %v", hashes[0])
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("block event not received")
+	}
+
+	if _, height, err := client.BestBlock(); err != nil || height != 1 {
+		t.Fatalf("expected height 1, got %v: %v", height, err)
+	}
+	if _, err := client.Utxo(&wire.OutPoint{Index: 7}); err != ErrOutputSpent {
+		t.Fatalf("expected ErrOutputSpent, got %v", err)
+	}
+}
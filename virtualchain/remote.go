@@ -0,0 +1,489 @@
+package virtualchain
+
+import (
+	"bytes"
+	"net"
+	"net/rpc"
+	"time"
+
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
+)
+
+const (
+	// serviceName is the name the chain is served under.
+	serviceName = "Chain"
+
+	// eventsPollTimeout is the longest time a call for events waits for a
+	// new event before returning empty handed, so the client may check
+	// whether it's been asked to quit.
+	eventsPollTimeout = 10 * time.Second
+)
+
+// Empty is the argument, or reply, of the methods of ChainService taking or
+// returning nothing. As gob is unable to encode structs without exported
+// fields, it carries an unused one.
+type Empty struct {
+	Unused bool
+}
+
+// BestBlockReply is the reply to ChainService.BestBlock.
+type BestBlockReply struct {
+	Hash   chainhash.Hash
+	Height int32
+}
+
+// TransactionReply is the reply to ChainService.Transaction.
+type TransactionReply struct {
+	Tx     []byte
+	Height int32
+}
+
+// MineArgs are the arguments of ChainService.Mine.
+type MineArgs struct {
+	NumBlocks uint32
+	PkScript  []byte
+}
+
+// EventsArgs are the arguments of ChainService.Events. If Wait is false, then
+// only the pending events are returned.
+type EventsArgs struct {
+	From uint64
+	Wait bool
+}
+
+// WireEvent is an Event serialized for transport, with exactly one of its
+// fields set.
+type WireEvent struct {
+	Accepted []byte
+	Evicted  *chainhash.Hash
+	Block    []byte
+}
+
+// ChainService serves a Chain to other processes over net/rpc. Transactions
+// and blocks are passed in their wire serialization.
+type ChainService struct {
+	chain *Chain
+}
+
+// Serve serves the passed chain to the clients connecting to the passed
+// listener, until the listener is closed.
+//
+// NOTE: The service is unauthenticated, so the listener should only be
+// reachable by the nodes of the simulation.
+func Serve(chain *Chain, listener net.Listener) error {
+	server := rpc.NewServer()
+	err := server.RegisterName(serviceName, &ChainService{chain: chain})
+	if err != nil {
+		return err
+	}
+
+	go server.Accept(listener)
+	return nil
+}
+
+// BestBlock returns the hash and height of the tip of the chain.
+func (s *ChainService) BestBlock(args Empty, reply *BestBlockReply) error {
+	hash, height, err := s.chain.BestBlock()
+	if err != nil {
+		return err
+	}
+
+	reply.Hash = *hash
+	reply.Height = height
+	return nil
+}
+
+// BlockHash returns the hash of the block at the passed height.
+func (s *ChainService) BlockHash(height int32, reply *chainhash.Hash) error {
+	hash, err := s.chain.BlockHash(height)
+	if err != nil {
+		return err
+	}
+
+	*reply = *hash
+	return nil
+}
+
+// Block returns the serialized block with the passed hash.
+func (s *ChainService) Block(hash chainhash.Hash, reply *[]byte) error {
+	block, err := s.chain.Block(&hash)
+	if err != nil {
+		return err
+	}
+
+	var b bytes.Buffer
+	if err := block.Serialize(&b); err != nil {
+		return err
+	}
+
+	*reply = b.Bytes()
+	return nil
+}
+
+// Transaction returns the serialized transaction with the passed txid, along
+// with the height of the block confirming it, or -1 if it's within the
+// mempool.
+func (s *ChainService) Transaction(txid chainhash.Hash,
+	reply *TransactionReply) error {
+
+	tx, height, err := s.chain.Transaction(&txid)
+	if err != nil {
+		return err
+	}
+
+	var b bytes.Buffer
+	if err := tx.Serialize(&b); err != nil {
+		return err
+	}
+
+	reply.Tx = b.Bytes()
+	reply.Height = height
+	return nil
+}
+
+// Utxo returns the confirmed unspent output referenced by the passed
+// outpoint.
+func (s *ChainService) Utxo(outpoint wire.OutPoint, reply *wire.TxOut) error {
+	output, err := s.chain.Utxo(&outpoint)
+	if err != nil {
+		return err
+	}
+
+	*reply = *output
+	return nil
+}
+
+// Publish validates the passed serialized transaction, then accepts it to
+// the mempool.
+func (s *ChainService) Publish(rawTx []byte, reply *Empty) error {
+	tx := wire.NewMsgTx(1)
+	if err := tx.Deserialize(bytes.NewReader(rawTx)); err != nil {
+		return err
+	}
+
+	return s.chain.Publish(tx)
+}
+
+// Mine mines the requested number of blocks on top of the chain.
+func (s *ChainService) Mine(args MineArgs, reply *[]chainhash.Hash) error {
+	hashes, err := s.chain.Mine(args.NumBlocks, args.PkScript)
+	if err != nil {
+		return err
+	}
+
+	*reply = hashes
+	return nil
+}
+
+// AdvanceTime moves the virtual clock forward by the passed duration.
+func (s *ChainService) AdvanceTime(d time.Duration, reply *time.Time) error {
+	now, err := s.chain.AdvanceTime(d)
+	if err != nil {
+		return err
+	}
+
+	*reply = now
+	return nil
+}
+
+// Now returns the current time of the virtual clock.
+func (s *ChainService) Now(args Empty, reply *time.Time) error {
+	now, err := s.chain.Now()
+	if err != nil {
+		return err
+	}
+
+	*reply = now
+	return nil
+}
+
+// Events returns the serialized events of the chain starting with the one at
+// the requested index. If Wait is set, then the call waits up to
+// eventsPollTimeout for such an event.
+func (s *ChainService) Events(args EventsArgs, reply *[]WireEvent) error {
+	quit := make(chan struct{})
+	if args.Wait {
+		timer := time.AfterFunc(eventsPollTimeout, func() {
+			close(quit)
+		})
+		defer timer.Stop()
+	} else {
+		close(quit)
+	}
+
+	events, err := s.chain.Events(args.From, quit)
+	if err != nil {
+		return err
+	}
+
+	wireEvents := make([]WireEvent, len(events))
+	for i, event := range events {
+		var b bytes.Buffer
+		switch {
+		case event.Accepted != nil:
+			if err := event.Accepted.Serialize(&b); err != nil {
+				return err
+			}
+			wireEvents[i].Accepted = b.Bytes()
+
+		case event.Evicted != nil:
+			wireEvents[i].Evicted = event.Evicted
+
+		case event.Block != nil:
+			if err := event.Block.Serialize(&b); err != nil {
+				return err
+			}
+			wireEvents[i].Block = b.Bytes()
+		}
+	}
+
+	*reply = wireEvents
+	return nil
+}
+
+// Client is a Backend reaching a Chain served by another process.
+type Client struct {
+	conn *rpc.Client
+}
+
+// A compile time check to ensure Client implements the Backend interface.
+var _ Backend = (*Client)(nil)
+
+// Dial connects to the chain served at the passed address.
+func Dial(addr string) (*Client, error) {
+	conn, err := rpc.Dial("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{conn: conn}, nil
+}
+
+// Close closes the connection to the chain.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// call invokes the passed method of the remote chain, mapping the errors
+// known to this package back to their values.
+func (c *Client) call(method string, args, reply interface{}) error {
+	err := c.conn.Call(serviceName+"."+method, args, reply)
+	return mapError(err)
+}
+
+// mapError maps an error returned by the remote chain back to the matching
+// error of this package, if any.
+func mapError(err error) error {
+	if _, ok := err.(rpc.ServerError); !ok {
+		return err
+	}
+
+	for _, knownErr := range []error{ErrBlockNotFound, ErrTxNotFound,
+		ErrOutputSpent} {
+
+		if err.Error() == knownErr.Error() {
+			return knownErr
+		}
+	}
+
+	return err
+}
+
+// BestBlock returns the hash and height of the tip of the chain.
+//
+// This is part of the Backend interface.
+func (c *Client) BestBlock() (*chainhash.Hash, int32, error) {
+	var reply BestBlockReply
+	if err := c.call("BestBlock", Empty{}, &reply); err != nil {
+		return nil, 0, err
+	}
+
+	return &reply.Hash, reply.Height, nil
+}
+
+// BlockHash returns the hash of the block at the passed height.
+//
+// This is part of the Backend interface.
+func (c *Client) BlockHash(height int32) (*chainhash.Hash, error) {
+	var hash chainhash.Hash
+	if err := c.call("BlockHash", height, &hash); err != nil {
+		return nil, err
+	}
+
+	return &hash, nil
+}
+
+// Block returns the block with the passed hash.
+//
+// This is part of the Backend interface.
+func (c *Client) Block(hash *chainhash.Hash) (*wire.MsgBlock, error) {
+	var rawBlock []byte
+	if err := c.call("Block", *hash, &rawBlock); err != nil {
+		return nil, err
+	}
+
+	block := &wire.MsgBlock{}
+	if err := block.Deserialize(bytes.NewReader(rawBlock)); err != nil {
+		return nil, err
+	}
+
+	return block, nil
+}
+
+// Transaction returns the transaction with the passed txid, along with the
+// height of the block confirming it, or -1 if it's within the mempool.
+//
+// This is part of the Backend interface.
+func (c *Client) Transaction(txid *chainhash.Hash) (*wire.MsgTx, int32,
+	error) {
+
+	var reply TransactionReply
+	if err := c.call("Transaction", *txid, &reply); err != nil {
+		return nil, 0, err
+	}
+
+	tx := wire.NewMsgTx(1)
+	if err := tx.Deserialize(bytes.NewReader(reply.Tx)); err != nil {
+		return nil, 0, err
+	}
+
+	return tx, reply.Height, nil
+}
+
+// Utxo returns the confirmed unspent output referenced by the passed
+// outpoint.
+//
+// This is part of the Backend interface.
+func (c *Client) Utxo(outpoint *wire.OutPoint) (*wire.TxOut, error) {
+	var output wire.TxOut
+	if err := c.call("Utxo", *outpoint, &output); err != nil {
+		return nil, err
+	}
+
+	return &output, nil
+}
+
+// Publish validates the passed transaction, then accepts it to the mempool.
+//
+// This is part of the Backend interface.
+func (c *Client) Publish(tx *wire.MsgTx) error {
+	var b bytes.Buffer
+	if err := tx.Serialize(&b); err != nil {
+		return err
+	}
+
+	return c.call("Publish", b.Bytes(), &Empty{})
+}
+
+// Mine mines the passed number of blocks on top of the chain.
+//
+// This is part of the Backend interface.
+func (c *Client) Mine(numBlocks uint32, pkScript []byte) ([]chainhash.Hash,
+	error) {
+
+	var hashes []chainhash.Hash
+	args := MineArgs{
+		NumBlocks: numBlocks,
+		PkScript:  pkScript,
+	}
+	if err := c.call("Mine", args, &hashes); err != nil {
+		return nil, err
+	}
+
+	return hashes, nil
+}
+
+// AdvanceTime moves the virtual clock forward by the passed duration.
+//
+// This is part of the Backend interface.
+func (c *Client) AdvanceTime(d time.Duration) (time.Time, error) {
+	var now time.Time
+	if err := c.call("AdvanceTime", d, &now); err != nil {
+		return time.Time{}, err
+	}
+
+	return now, nil
+}
+
+// Now returns the current time of the virtual clock.
+//
+// This is part of the Backend interface.
+func (c *Client) Now() (time.Time, error) {
+	var now time.Time
+	if err := c.call("Now", Empty{}, &now); err != nil {
+		return time.Time{}, err
+	}
+
+	return now, nil
+}
+
+// Events returns the events of the chain starting with the one at the passed
+// index, blocking until there is such an event, or the passed channel is
+// closed. Each call to the remote chain waits for new events for a limited
+// time only, so it's repeated until events arrive.
+//
+// This is part of the Backend interface.
+func (c *Client) Events(from uint64, quit <-chan struct{}) ([]*Event, error) {
+	for {
+		// If we've been asked to quit, then we only fetch the pending
+		// events.
+		args := EventsArgs{From: from, Wait: true}
+		select {
+		case <-quit:
+			args.Wait = false
+		default:
+		}
+
+		var wireEvents []WireEvent
+		call := c.conn.Go(serviceName+".Events", args, &wireEvents, nil)
+		select {
+		case <-call.Done:
+		case <-quit:
+			if args.Wait {
+				return nil, nil
+			}
+			<-call.Done
+		}
+		if call.Error != nil {
+			return nil, mapError(call.Error)
+		}
+
+		if len(wireEvents) == 0 {
+			if !args.Wait {
+				return nil, nil
+			}
+			continue
+		}
+
+		events := make([]*Event, len(wireEvents))
+		for i, wireEvent := range wireEvents {
+			event := &Event{}
+			switch {
+			case wireEvent.Accepted != nil:
+				tx := wire.NewMsgTx(1)
+				err := tx.Deserialize(
+					bytes.NewReader(wireEvent.Accepted))
+				if err != nil {
+					return nil, err
+				}
+				event.Accepted = tx
+
+			case wireEvent.Evicted != nil:
+				event.Evicted = wireEvent.Evicted
+
+			case wireEvent.Block != nil:
+				block := &wire.MsgBlock{}
+				err := block.Deserialize(
+					bytes.NewReader(wireEvent.Block))
+				if err != nil {
+					return nil, err
+				}
+				event.Block = block
+			}
+			events[i] = event
+		}
+
+		return events, nil
+	}
+}
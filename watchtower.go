@@ -0,0 +1,524 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/lightningnetwork/lnd/chainntnfs"
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/lightningnetwork/lnd/watchtower"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/txscript"
+	"github.com/roasbeef/btcd/wire"
+)
+
+const (
+	// watchtowerFeature is the name of the local feature advertised by
+	// nodes acting as a watchtower for their peers.
+	watchtowerFeature = "watchtower"
+
+	// watchtowerFeatureIndex is the index of watchtowerFeature within the
+	// local feature vector.
+	watchtowerFeatureIndex = 9
+
+	// maxJusticeRetryBlocks is the number of blocks following a breach
+	// during which the dispatch of its justice transaction is retried.
+	// It exceeds the CSV delay of any channel we'd open, so once it has
+	// passed, the breaching party has long been able to sweep its funds.
+	maxJusticeRetryBlocks = 2016
+)
+
+// watchtowerConfig defines the options of the watchtower server.
+type watchtowerConfig struct {
+	Active bool `long:"active" description:"Act as a watchtower for peers supporting it. Peers may open sessions with the tower, within which they upload encrypted justice transactions for each revoked state of their channels. Should one of their channels be breached, the tower broadcasts the justice transaction on their behalf, and is paid a reward from the swept funds"`
+
+	MaxSessionsPerClient uint32 `long:"maxsessionsperclient" description:"The most sessions a single client may open with the tower"`
+	MaxUpdatesPerSession uint16 `long:"maxupdatespersession" description:"The most justice transactions a client may upload within a single session"`
+	MaxStorage           uint64 `long:"maxstorage" description:"The most bytes of encrypted justice transactions the tower stores across all of its clients"`
+	MaxStoragePerClient  uint64 `long:"maxstorageperclient" description:"The most bytes of encrypted justice transactions the tower stores for a single client, so no client can exhaust the storage shared by all of them"`
+	RewardRate           uint32 `long:"rewardrate" description:"The share of the swept funds, in millionths, paid to the tower by each justice transaction"`
+}
+
+// validate checks the watchtower options for consistency.
+func (c *watchtowerConfig) validate() error {
+	if !c.Active {
+		return nil
+	}
+
+	if c.MaxSessionsPerClient == 0 {
+		return fmt.Errorf("watchtower.maxsessionsperclient must be " +
+			"positive")
+	}
+	if c.MaxUpdatesPerSession == 0 {
+		return fmt.Errorf("watchtower.maxupdatespersession must be " +
+			"positive")
+	}
+	if c.MaxStorage == 0 {
+		return fmt.Errorf("watchtower.maxstorage must be positive")
+	}
+	if c.MaxStoragePerClient == 0 {
+		return fmt.Errorf("watchtower.maxstorageperclient must be " +
+			"positive")
+	}
+	if c.RewardRate >= 1000000 {
+		return fmt.Errorf("watchtower.rewardrate must be below 1000000")
+	}
+
+	return nil
+}
+
+// watchtowerServer watches the chain for the breach of channels belonging to
+// its clients. Clients upload the justice transaction sweeping each revoked
+// state of their channels, encrypted under the txid of the revoked
+// commitment transaction, along with a hint of that txid. For each
+// transaction within each new block, the server looks up the blobs stored
+// under its hint, and should one decrypt, broadcasts the justice transaction
+// it holds. The tower's reward is paid to an address of our wallet, so it's
+// swept along with the rest of our funds.
+type watchtowerServer struct {
+	started uint32 // To be used atomically.
+	stopped uint32 // To be used atomically.
+
+	cfg      *watchtowerConfig
+	chanDB   *channeldb.DB
+	wallet   *lnwallet.LightningWallet
+	notifier chainntnfs.ChainNotifier
+	chainIO  lnwallet.BlockChainIO
+
+	// scanHeight is the height of the last block scanned for breaches.
+	// It's only accessed by the blockScanner.
+	scanHeight int32
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newWatchtowerServer creates a new watchtower server storing its state
+// within the passed database.
+func newWatchtowerServer(cfg *watchtowerConfig, chanDB *channeldb.DB,
+	wallet *lnwallet.LightningWallet, notifier chainntnfs.ChainNotifier,
+	chainIO lnwallet.BlockChainIO) *watchtowerServer {
+
+	return &watchtowerServer{
+		cfg:      cfg,
+		chanDB:   chanDB,
+		wallet:   wallet,
+		notifier: notifier,
+		chainIO:  chainIO,
+		quit:     make(chan struct{}),
+	}
+}
+
+// Start launches the scan of new blocks for breaches, if the operator has
+// enabled the watchtower.
+func (w *watchtowerServer) Start() error {
+	if !atomic.CompareAndSwapUint32(&w.started, 0, 1) {
+		return nil
+	}
+	if !w.cfg.Active {
+		return nil
+	}
+
+	blockEpochs, err := w.notifier.RegisterBlockEpochNtfn()
+	if err != nil {
+		return err
+	}
+
+	// Blocks mined while we were offline are scanned along with the first
+	// new block. If we've never scanned a block before, then there are no
+	// blobs the earlier blocks could match, so the scan starts from the
+	// current height.
+	_, bestHeight, err := w.chainIO.GetBestBlock()
+	if err != nil {
+		blockEpochs.Cancel()
+		return err
+	}
+	scanHeight, ok, err := w.chanDB.FetchTowerScanHeight()
+	if err != nil {
+		blockEpochs.Cancel()
+		return err
+	}
+	if !ok {
+		scanHeight = uint32(bestHeight)
+		if err := w.chanDB.PutTowerScanHeight(scanHeight); err != nil {
+			blockEpochs.Cancel()
+			return err
+		}
+	}
+	w.scanHeight = int32(scanHeight)
+
+	brarLog.Infof("Watchtower active, reward rate of %v ppm",
+		w.cfg.RewardRate)
+
+	w.wg.Add(1)
+	go w.blockScanner(blockEpochs)
+
+	return nil
+}
+
+// Stop halts the scan of new blocks.
+func (w *watchtowerServer) Stop() error {
+	if !atomic.CompareAndSwapUint32(&w.stopped, 0, 1) {
+		return nil
+	}
+
+	close(w.quit)
+	w.wg.Wait()
+
+	return nil
+}
+
+// blockScanner scans each new block for breaches.
+//
+// NOTE: This MUST be run as a goroutine.
+func (w *watchtowerServer) blockScanner(
+	blockEpochs *chainntnfs.BlockEpochEvent) {
+
+	defer w.wg.Done()
+	defer blockEpochs.Cancel()
+
+	for {
+		select {
+		case epoch, ok := <-blockEpochs.Epochs:
+			if !ok {
+				return
+			}
+
+			if err := w.retryPendingJustice(epoch.Height); err != nil {
+				brarLog.Errorf("unable to retry pending justice "+
+					"txns: %v", err)
+			}
+			w.scanToEpoch(epoch)
+
+		case <-w.quit:
+			return
+		}
+	}
+}
+
+// scanToEpoch scans the blocks following the last block scanned, up to and
+// including the passed epoch's block, recording the progress made. Should a
+// block fail to be scanned, then the scan resumes from it with the next
+// epoch, so blocks missed while we were offline, or which failed to be
+// scanned, are never skipped. If the epoch's block doesn't extend the blocks
+// scanned, such as following a reorg, then only it is scanned.
+func (w *watchtowerServer) scanToEpoch(epoch *chainntnfs.BlockEpoch) {
+	if epoch.Height <= w.scanHeight {
+		if err := w.scanBlock(epoch.Hash, epoch.Height); err != nil {
+			brarLog.Errorf("unable to scan block %v for breaches: "+
+				"%v", epoch.Hash, err)
+		}
+		return
+	}
+
+	for height := w.scanHeight + 1; height <= epoch.Height; height++ {
+		blockHash := epoch.Hash
+		if height != epoch.Height {
+			var err error
+			blockHash, err = w.chainIO.GetBlockHash(int64(height))
+			if err != nil {
+				brarLog.Errorf("unable to fetch block at height "+
+					"%v: %v", height, err)
+				return
+			}
+		}
+
+		if err := w.scanBlock(blockHash, height); err != nil {
+			brarLog.Errorf("unable to scan block %v for breaches: "+
+				"%v", blockHash, err)
+			return
+		}
+
+		if err := w.chanDB.PutTowerScanHeight(uint32(height)); err != nil {
+			brarLog.Errorf("unable to record scan height: %v", err)
+			return
+		}
+		w.scanHeight = height
+	}
+}
+
+// scanBlock looks up the blobs stored under the hint of each transaction
+// within the block at the passed height, and dispatches the justice
+// transaction of each blob which decrypts.
+func (w *watchtowerServer) scanBlock(blockHash *chainhash.Hash,
+	height int32) error {
+
+	block, err := w.chainIO.GetBlock(blockHash)
+	if err != nil {
+		return err
+	}
+
+	for _, tx := range block.Transactions {
+		txid := tx.TxHash()
+		hint := watchtower.NewBreachHint(&txid)
+
+		blobs, err := w.chanDB.FetchTowerBlobs(hint)
+		if err != nil {
+			return err
+		}
+
+		for _, blob := range blobs {
+			retry, err := w.dispatchJustice(tx, &txid, blob)
+
+			// The hint matched, but the transaction isn't the one
+			// the blob was encrypted for. As the blob may still
+			// match a later transaction, it's kept.
+			if err == watchtower.ErrDecryptFailed {
+				brarLog.Debugf("Blob of session %v matched "+
+					"hint %v of unrelated tx %v",
+					blob.SessionID, hint, txid)
+				continue
+			}
+
+			// If the justice transaction couldn't be dispatched
+			// for a reason which may pass, then the blob is kept,
+			// and the dispatch retried with the following blocks.
+			if retry {
+				brarLog.Warnf("unable to dispatch justice for "+
+					"breach tx %v, session %v, retrying: %v",
+					txid, blob.SessionID, err)
+
+				err := w.chanDB.AddTowerPendingJustice(
+					&channeldb.TowerPendingJustice{
+						Hint:         hint,
+						SessionID:    blob.SessionID,
+						SeqNum:       blob.SeqNum,
+						BreachHeight: uint32(height),
+						BreachTx:     tx,
+					})
+				if err != nil {
+					return err
+				}
+				continue
+			}
+
+			if err := w.finishJustice(&txid, blob, err); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// retryPendingJustice retries the dispatch of the justice transactions which
+// previously failed for a reason which may pass. Dispatches are abandoned
+// once maxJusticeRetryBlocks have passed since their breach.
+func (w *watchtowerServer) retryPendingJustice(height int32) error {
+	pending, err := w.chanDB.FetchTowerPendingJustice()
+	if err != nil {
+		return err
+	}
+
+	for _, p := range pending {
+		blob, err := w.chanDB.FetchTowerBlob(p.Hint, p.SessionID,
+			p.SeqNum)
+		if err != nil {
+			return err
+		}
+		if blob == nil {
+			continue
+		}
+
+		breachTxID := p.BreachTx.TxHash()
+		retry, err := w.dispatchJustice(p.BreachTx, &breachTxID, blob)
+		if retry {
+			if uint32(height) < p.BreachHeight+maxJusticeRetryBlocks {
+				brarLog.Warnf("unable to dispatch justice for "+
+					"breach tx %v, session %v, retrying: %v",
+					breachTxID, blob.SessionID, err)
+				continue
+			}
+
+			err = fmt.Errorf("abandoned after %v blocks: %v",
+				maxJusticeRetryBlocks, err)
+		}
+
+		if err := w.finishJustice(&breachTxID, blob, err); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// finishJustice deletes the blob once the justice transaction it describes
+// has been broadcast, or failed to be dispatched for good, as indicated by
+// the passed error, since the blob is then of no further use.
+func (w *watchtowerServer) finishJustice(breachTxID *chainhash.Hash,
+	blob *channeldb.TowerBlob, dispatchErr error) error {
+
+	if dispatchErr != nil {
+		brarLog.Errorf("unable to dispatch justice for breach tx %v, "+
+			"session %v, discarding blob: %v", breachTxID,
+			blob.SessionID, dispatchErr)
+	}
+
+	hint := watchtower.NewBreachHint(breachTxID)
+	return w.chanDB.DeleteTowerBlob(hint, blob.SessionID, blob.SeqNum)
+}
+
+// dispatchJustice decrypts the blob with the txid of the breach transaction,
+// then builds, verifies, and broadcasts the justice transaction it describes.
+// If the dispatch fails for a reason which may pass, such as a failure to
+// broadcast the justice transaction, or to read the blob's session, then true
+// is returned along with the error, and the dispatch should be retried.
+// Failures to decrypt the blob, or to build a valid justice transaction from
+// it, are permanent.
+func (w *watchtowerServer) dispatchJustice(breachTx *wire.MsgTx,
+	breachTxID *chainhash.Hash, blob *channeldb.TowerBlob) (bool, error) {
+
+	kit, err := watchtower.DecryptJusticeKit(blob.Blob, breachTxID)
+	if err != nil {
+		return false, err
+	}
+
+	session, err := w.chanDB.FetchTowerSession(blob.SessionID)
+	switch {
+	case err == channeldb.ErrTowerSessionNotFound:
+		return false, err
+	case err != nil:
+		return true, err
+	}
+	policy := &watchtower.SessionPolicy{
+		SweepFeeRate:   session.SweepFeeRate,
+		RewardRate:     session.RewardRate,
+		RewardPkScript: session.RewardPkScript,
+	}
+
+	justiceTx, err := watchtower.NewJusticeTx(kit, breachTx, policy)
+	if err != nil {
+		return false, err
+	}
+	if err := justiceTx.Sign(kit, breachTx); err != nil {
+		return false, fmt.Errorf("invalid justice tx: %v", err)
+	}
+
+	brarLog.Infof("Breach tx %v of client %x detected, broadcasting "+
+		"justice tx %v with reward of %v", breachTxID,
+		session.ClientPub.SerializeCompressed(), justiceTx.Tx.TxHash(),
+		justiceTx.Reward)
+
+	if err := w.wallet.PublishTransaction(justiceTx.Tx); err != nil {
+		return true, err
+	}
+
+	return false, nil
+}
+
+// processCreateSession opens a session for the peer, and replies with the
+// terms of the session.
+func (w *watchtowerServer) processCreateSession(p *peer,
+	msg *lnwire.CreateSession) {
+
+	if !w.cfg.Active ||
+		!p.localSharedFeatures.IsActive(watchtowerFeature) {
+
+		return
+	}
+
+	reply := &lnwire.CreateSessionReply{}
+	defer p.queueMsg(reply, nil)
+
+	if err := msg.Validate(); err != nil {
+		peerLog.Warnf("Invalid session request from %v: %v", p, err)
+		reply.Code = lnwire.TowerCodeInvalidUpdate
+		return
+	}
+
+	rewardAddr, err := w.wallet.NewAddress(lnwallet.WitnessPubKey, false)
+	if err != nil {
+		peerLog.Errorf("unable to create reward address: %v", err)
+		reply.Code = lnwire.TowerCodeTemporaryFailure
+		return
+	}
+	rewardPkScript, err := txscript.PayToAddrScript(rewardAddr)
+	if err != nil {
+		peerLog.Errorf("unable to create reward script: %v", err)
+		reply.Code = lnwire.TowerCodeTemporaryFailure
+		return
+	}
+
+	maxUpdates := msg.MaxUpdates
+	if maxUpdates > w.cfg.MaxUpdatesPerSession {
+		maxUpdates = w.cfg.MaxUpdatesPerSession
+	}
+	session := &channeldb.TowerSession{
+		ClientPub:      p.addr.IdentityKey,
+		MaxUpdates:     maxUpdates,
+		SweepFeeRate:   msg.SweepFeeRate,
+		RewardRate:     w.cfg.RewardRate,
+		RewardPkScript: rewardPkScript,
+	}
+	err = w.chanDB.CreateTowerSession(session, w.cfg.MaxSessionsPerClient)
+	switch {
+	case err == channeldb.ErrTowerSessionLimit:
+		reply.Code = lnwire.TowerCodeSessionLimit
+		return
+	case err != nil:
+		peerLog.Errorf("unable to create tower session for %v: %v", p,
+			err)
+		reply.Code = lnwire.TowerCodeTemporaryFailure
+		return
+	}
+
+	peerLog.Infof("Opened tower session %v for %v with %v updates at %v "+
+		"sat/kw", session.ID, p, maxUpdates,
+		int64(msg.SweepFeeRate))
+
+	reply.SessionID = session.ID
+	reply.MaxUpdates = session.MaxUpdates
+	reply.RewardRate = session.RewardRate
+	reply.RewardPkScript = session.RewardPkScript
+}
+
+// processStateUpdate stores the blob uploaded by the peer within one of its
+// sessions, and replies with the result.
+func (w *watchtowerServer) processStateUpdate(p *peer,
+	msg *lnwire.StateUpdate) {
+
+	if !w.cfg.Active ||
+		!p.localSharedFeatures.IsActive(watchtowerFeature) {
+
+		return
+	}
+
+	reply := &lnwire.StateUpdateReply{SessionID: msg.SessionID}
+	defer p.queueMsg(reply, nil)
+
+	if err := msg.Validate(); err != nil {
+		peerLog.Warnf("Invalid state update from %v: %v", p, err)
+		reply.Code = lnwire.TowerCodeInvalidUpdate
+		return
+	}
+
+	lastApplied, err := w.chanDB.ApplyTowerUpdate(p.addr.IdentityKey,
+		msg.SessionID, msg.SeqNum, msg.Hint, msg.EncryptedBlob,
+		w.cfg.MaxStorage, w.cfg.MaxStoragePerClient)
+	reply.LastApplied = lastApplied
+	switch {
+	case err == nil:
+		reply.Code = lnwire.TowerCodeOK
+	case err == channeldb.ErrTowerSessionNotFound:
+		reply.Code = lnwire.TowerCodeUnknownSession
+	case err == channeldb.ErrTowerSessionFull:
+		reply.Code = lnwire.TowerCodeSessionFull
+	case err == channeldb.ErrTowerSeqNumOutOfOrder:
+		reply.Code = lnwire.TowerCodeSeqNumOutOfOrder
+	case err == channeldb.ErrTowerStorageFull:
+		peerLog.Warnf("Tower storage full, rejecting update from %v",
+			p)
+		reply.Code = lnwire.TowerCodeStorageFull
+	case err == channeldb.ErrTowerClientStorageFull:
+		peerLog.Warnf("Storage quota of %v exhausted, rejecting update",
+			p)
+		reply.Code = lnwire.TowerCodeStorageFull
+	default:
+		peerLog.Errorf("unable to apply tower update from %v: %v", p,
+			err)
+		reply.Code = lnwire.TowerCodeTemporaryFailure
+	}
+}
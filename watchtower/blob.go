@@ -0,0 +1,238 @@
+package watchtower
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/chaincfg/chainhash"
+	"github.com/roasbeef/btcd/wire"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+const (
+	// BreachHintSize is the size of a BreachHint.
+	BreachHintSize = lnwire.BreachHintSize
+
+	// MaxJusticeBlobSize is the largest encrypted justice kit a tower
+	// accepts. It comfortably fits a kit carrying both signatures and the
+	// largest standard sweep script.
+	MaxJusticeBlobSize = lnwire.MaxJusticeBlobSize
+
+	// maxSweepScriptSize is the largest sweep script a justice kit may
+	// carry, that of a P2WSH output.
+	maxSweepScriptSize = 34
+
+	// maxSigSize is the largest DER encoded signature.
+	maxSigSize = 72
+)
+
+// ErrDecryptFailed is returned when a justice blob can't be decrypted, as the
+// transaction it was matched with isn't the breach transaction it was
+// encrypted for, or the blob is corrupted.
+var ErrDecryptFailed = errors.New("unable to decrypt justice blob: wrong " +
+	"breach transaction or corrupted blob")
+
+// BreachHint is the prefix of the txid of a breach transaction. Clients send
+// towers the hint of each revoked commitment transaction along with the
+// justice kit encrypted under the full txid, so a tower is only able to
+// decrypt a kit once the breach transaction appears on-chain.
+type BreachHint [BreachHintSize]byte
+
+// NewBreachHint returns the hint of the breach transaction with the passed
+// txid.
+func NewBreachHint(txid *chainhash.Hash) BreachHint {
+	var hint BreachHint
+	copy(hint[:], txid[:BreachHintSize])
+	return hint
+}
+
+// String returns the hex encoding of the hint.
+func (h BreachHint) String() string {
+	return fmt.Sprintf("%x", h[:])
+}
+
+// JusticeKit holds what a tower needs to sweep the outputs of a breach
+// transaction on behalf of its client: the keys needed to reconstruct the
+// scripts of the breached outputs, and the client's signatures for the
+// justice transaction spending them. The signatures commit to the outputs of
+// the justice transaction, which are dictated by the policy of the client's
+// session, so the tower is unable to redirect the swept funds.
+type JusticeKit struct {
+	// SweepPkScript is the script the client's share of the swept funds
+	// is paid to.
+	SweepPkScript []byte
+
+	// RevocationPubKey is the revocation key of the breached state.
+	RevocationPubKey *btcec.PublicKey
+
+	// LocalDelayPubKey is the key of the breaching party which the
+	// time-locked output of its commitment pays to.
+	LocalDelayPubKey *btcec.PublicKey
+
+	// CSVDelay is the relative time lock of the breaching party's
+	// time-locked output.
+	CSVDelay uint32
+
+	// ToLocalSig is the client's signature spending the breaching party's
+	// time-locked output via the revocation clause.
+	ToLocalSig []byte
+
+	// ToRemotePubKey is the key of the client's output within the breach
+	// transaction, or nil if the output was trimmed as dust.
+	ToRemotePubKey *btcec.PublicKey
+
+	// ToRemoteSig is the client's signature spending its own output, if
+	// present.
+	ToRemoteSig []byte
+}
+
+// Encrypt serializes the kit and encrypts it under the txid of the breach
+// transaction it sweeps.
+func (k *JusticeKit) Encrypt(breachTxID *chainhash.Hash) ([]byte, error) {
+	var b bytes.Buffer
+	if err := k.encode(&b); err != nil {
+		return nil, err
+	}
+
+	key := blobKey(breachTxID)
+	cipher, err := chacha20poly1305.New(key[:])
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, cipher.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	blob := cipher.Seal(nonce, nonce, b.Bytes(), nil)
+	if len(blob) > MaxJusticeBlobSize {
+		return nil, fmt.Errorf("justice blob of %v bytes exceeds the "+
+			"maximum of %v", len(blob), MaxJusticeBlobSize)
+	}
+
+	return blob, nil
+}
+
+// DecryptJusticeKit decrypts the justice kit within the passed blob using the
+// txid of the breach transaction it was matched with.
+func DecryptJusticeKit(blob []byte,
+	breachTxID *chainhash.Hash) (*JusticeKit, error) {
+
+	key := blobKey(breachTxID)
+	cipher, err := chacha20poly1305.New(key[:])
+	if err != nil {
+		return nil, err
+	}
+	if len(blob) < cipher.NonceSize()+cipher.Overhead() {
+		return nil, ErrDecryptFailed
+	}
+
+	nonce := blob[:cipher.NonceSize()]
+	plaintext, err := cipher.Open(nil, nonce, blob[cipher.NonceSize():], nil)
+	if err != nil {
+		return nil, ErrDecryptFailed
+	}
+
+	kit := &JusticeKit{}
+	if err := kit.decode(bytes.NewReader(plaintext)); err != nil {
+		return nil, err
+	}
+
+	return kit, nil
+}
+
+// blobKey derives the key a justice kit is encrypted under from the txid of
+// its breach transaction. The hint sent alongside the blob is a prefix of the
+// txid, so the key is a hash of the txid rather than the txid itself.
+func blobKey(breachTxID *chainhash.Hash) [32]byte {
+	return sha256.Sum256(breachTxID[:])
+}
+
+func (k *JusticeKit) encode(w io.Writer) error {
+	if err := wire.WriteVarBytes(w, 0, k.SweepPkScript); err != nil {
+		return err
+	}
+	if _, err := w.Write(k.RevocationPubKey.SerializeCompressed()); err != nil {
+		return err
+	}
+	if _, err := w.Write(k.LocalDelayPubKey.SerializeCompressed()); err != nil {
+		return err
+	}
+
+	var scratch [4]byte
+	byteOrder.PutUint32(scratch[:], k.CSVDelay)
+	if _, err := w.Write(scratch[:]); err != nil {
+		return err
+	}
+	if err := wire.WriteVarBytes(w, 0, k.ToLocalSig); err != nil {
+		return err
+	}
+
+	if k.ToRemotePubKey == nil {
+		_, err := w.Write([]byte{0})
+		return err
+	}
+	if _, err := w.Write([]byte{1}); err != nil {
+		return err
+	}
+	if _, err := w.Write(k.ToRemotePubKey.SerializeCompressed()); err != nil {
+		return err
+	}
+	return wire.WriteVarBytes(w, 0, k.ToRemoteSig)
+}
+
+func (k *JusticeKit) decode(r io.Reader) error {
+	var err error
+	k.SweepPkScript, err = wire.ReadVarBytes(r, 0, maxSweepScriptSize,
+		"sweep script")
+	if err != nil {
+		return err
+	}
+
+	if k.RevocationPubKey, err = readPubKey(r); err != nil {
+		return err
+	}
+	if k.LocalDelayPubKey, err = readPubKey(r); err != nil {
+		return err
+	}
+
+	var scratch [4]byte
+	if _, err := io.ReadFull(r, scratch[:]); err != nil {
+		return err
+	}
+	k.CSVDelay = byteOrder.Uint32(scratch[:])
+
+	k.ToLocalSig, err = wire.ReadVarBytes(r, 0, maxSigSize, "to-local sig")
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.ReadFull(r, scratch[:1]); err != nil {
+		return err
+	}
+	if scratch[0] == 0 {
+		return nil
+	}
+	if k.ToRemotePubKey, err = readPubKey(r); err != nil {
+		return err
+	}
+	k.ToRemoteSig, err = wire.ReadVarBytes(r, 0, maxSigSize,
+		"to-remote sig")
+	return err
+}
+
+func readPubKey(r io.Reader) (*btcec.PublicKey, error) {
+	var b [33]byte
+	if _, err := io.ReadFull(r, b[:]); err != nil {
+		return nil, err
+	}
+
+	return btcec.ParsePubKey(b[:], btcec.S256())
+}
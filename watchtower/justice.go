@@ -0,0 +1,244 @@
+package watchtower
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/roasbeef/btcd/blockchain"
+	"github.com/roasbeef/btcd/txscript"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+// byteOrder is the byte order integers are encoded with.
+var byteOrder = binary.BigEndian
+
+const (
+	// toLocalWitnessSize is the size of the witness spending the breaching
+	// party's time-locked output via the revocation clause, excluding
+	// its witness script:
+	//  - number of witness elements: 1 byte
+	//  - signature length: 1 byte
+	//  - signature: 73 bytes
+	//  - revocation clause selector: 2 bytes
+	//  - witness script length: 1 byte
+	toLocalWitnessSize = 1 + 1 + 73 + 2 + 1
+
+	// toRemoteWitnessSize is the size of the witness spending the
+	// client's own P2WKH output:
+	//  - number of witness elements: 1 byte
+	//  - signature length: 1 byte
+	//  - signature: 73 bytes
+	//  - public key length: 1 byte
+	//  - public key: 33 bytes
+	toRemoteWitnessSize = 1 + 1 + 73 + 1 + 33
+)
+
+var (
+	// ErrToLocalNotFound is returned when the time-locked output
+	// described by a justice kit isn't present within the breach
+	// transaction.
+	ErrToLocalNotFound = errors.New("to-local output not found within " +
+		"breach transaction")
+
+	// ErrToRemoteNotFound is returned when the client's own output
+	// described by a justice kit isn't present within the breach
+	// transaction.
+	ErrToRemoteNotFound = errors.New("to-remote output not found within " +
+		"breach transaction")
+
+	// ErrSweepBelowDust is returned when the client's share of the swept
+	// funds, once the fee and the tower's reward are paid, would be dust.
+	ErrSweepBelowDust = errors.New("swept funds below dust limit after " +
+		"fee and reward")
+)
+
+// SessionPolicy is the policy negotiated between a client and a tower when a
+// session is created. It dictates the outputs of every justice transaction
+// built from the session's justice kits, so the client is able to sign the
+// transactions ahead of time.
+type SessionPolicy struct {
+	// SweepFeeRate is the fee rate, in satoshis per kilo-weight, paid by
+	// the justice transactions.
+	SweepFeeRate btcutil.Amount
+
+	// RewardRate is the share of the swept funds, in millionths, paid to
+	// the tower.
+	RewardRate uint32
+
+	// RewardPkScript is the script the tower's reward is paid to.
+	RewardPkScript []byte
+}
+
+// JusticeTx is the justice transaction sweeping the outputs of a breach
+// transaction described by a justice kit.
+type JusticeTx struct {
+	// Tx is the justice transaction. The input spending the time-locked
+	// output always comes first, followed by the input spending the
+	// client's own output, if present.
+	Tx *wire.MsgTx
+
+	// ToLocalWitnessScript is the witness script of the breaching party's
+	// time-locked output.
+	ToLocalWitnessScript []byte
+
+	// InputValues are the values of the outputs spent by each input.
+	InputValues []int64
+
+	// Reward is the value paid to the tower.
+	Reward btcutil.Amount
+}
+
+// NewJusticeTx builds the unsigned justice transaction sweeping the outputs
+// of the passed breach transaction described by the kit, according to the
+// passed session policy. The transaction is entirely determined by its
+// arguments, so the client and the tower build identical transactions.
+func NewJusticeTx(kit *JusticeKit, breachTx *wire.MsgTx,
+	policy *SessionPolicy) (*JusticeTx, error) {
+
+	breachTxID := breachTx.TxHash()
+
+	toLocalScript, toLocalPkScript, err := lnwallet.CommitToSelfOutput(
+		kit.CSVDelay, kit.LocalDelayPubKey, kit.RevocationPubKey)
+	if err != nil {
+		return nil, err
+	}
+	found, toLocalIndex := lnwallet.FindScriptOutputIndex(breachTx,
+		toLocalPkScript)
+	if !found {
+		return nil, ErrToLocalNotFound
+	}
+
+	justice := &JusticeTx{
+		Tx:                   wire.NewMsgTx(2),
+		ToLocalWitnessScript: toLocalScript,
+	}
+	justice.Tx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{
+			Hash:  breachTxID,
+			Index: toLocalIndex,
+		},
+	})
+	justice.InputValues = append(justice.InputValues,
+		breachTx.TxOut[toLocalIndex].Value)
+	witnessSize := toLocalWitnessSize + len(toLocalScript)
+
+	if kit.ToRemotePubKey != nil {
+		toRemotePkScript, err := lnwallet.CommitToRemotePkScript(
+			kit.ToRemotePubKey)
+		if err != nil {
+			return nil, err
+		}
+		found, toRemoteIndex := lnwallet.FindScriptOutputIndex(
+			breachTx, toRemotePkScript)
+		if !found {
+			return nil, ErrToRemoteNotFound
+		}
+
+		justice.Tx.AddTxIn(&wire.TxIn{
+			PreviousOutPoint: wire.OutPoint{
+				Hash:  breachTxID,
+				Index: toRemoteIndex,
+			},
+		})
+		justice.InputValues = append(justice.InputValues,
+			breachTx.TxOut[toRemoteIndex].Value)
+		witnessSize += toRemoteWitnessSize
+	}
+
+	var total btcutil.Amount
+	for _, value := range justice.InputValues {
+		total += btcutil.Amount(value)
+	}
+
+	// The reward is taken from the swept funds before the fee, so the
+	// tower is rewarded in proportion to the funds it recovered.
+	justice.Reward = total * btcutil.Amount(policy.RewardRate) / 1000000
+	if justice.Reward < lnwallet.DefaultDustLimit() {
+		justice.Reward = 0
+	}
+	if justice.Reward > 0 {
+		justice.Tx.AddTxOut(&wire.TxOut{
+			PkScript: policy.RewardPkScript,
+			Value:    int64(justice.Reward),
+		})
+	}
+
+	// The sweep output is added with a placeholder value, so the weight
+	// of the transaction, and with it the fee, can be computed.
+	sweepOut := &wire.TxOut{PkScript: kit.SweepPkScript}
+	justice.Tx.AddTxOut(sweepOut)
+
+	// The marker and flag bytes precede the witnesses of the inputs.
+	weight := blockchain.WitnessScaleFactor*justice.Tx.SerializeSizeStripped() +
+		2 + witnessSize
+	fee := policy.SweepFeeRate * btcutil.Amount(weight) / 1000
+
+	sweep := total - justice.Reward - fee
+	if sweep < lnwallet.DefaultDustLimit() {
+		return nil, ErrSweepBelowDust
+	}
+	sweepOut.Value = int64(sweep)
+
+	return justice, nil
+}
+
+// ToLocalSigHash returns the signature hash the client signs to spend the
+// breaching party's time-locked output.
+func (j *JusticeTx) ToLocalSigHash() ([]byte, error) {
+	hashCache := txscript.NewTxSigHashes(j.Tx)
+	return txscript.CalcWitnessSigHash(j.ToLocalWitnessScript, hashCache,
+		txscript.SigHashAll, j.Tx, 0, j.InputValues[0])
+}
+
+// ToRemoteSigHash returns the signature hash the client signs to spend its
+// own output with the passed key.
+func (j *JusticeTx) ToRemoteSigHash(pkScript []byte) ([]byte, error) {
+	hashCache := txscript.NewTxSigHashes(j.Tx)
+	return txscript.CalcWitnessSigHash(pkScript, hashCache,
+		txscript.SigHashAll, j.Tx, 1, j.InputValues[1])
+}
+
+// Sign attaches the witnesses carrying the kit's signatures to the justice
+// transaction, then verifies each input, so an invalid kit is never
+// broadcast.
+func (j *JusticeTx) Sign(kit *JusticeKit, breachTx *wire.MsgTx) error {
+	j.Tx.TxIn[0].Witness = wire.TxWitness{
+		append(kit.ToLocalSig, byte(txscript.SigHashAll)),
+		{1},
+		j.ToLocalWitnessScript,
+	}
+	if len(j.Tx.TxIn) > 1 {
+		j.Tx.TxIn[1].Witness = wire.TxWitness{
+			append(kit.ToRemoteSig, byte(txscript.SigHashAll)),
+			kit.ToRemotePubKey.SerializeCompressed(),
+		}
+	}
+
+	hashCache := txscript.NewTxSigHashes(j.Tx)
+	for i, txIn := range j.Tx.TxIn {
+		prevOut := breachTx.TxOut[txIn.PreviousOutPoint.Index]
+		vm, err := txscript.NewEngine(prevOut.PkScript, j.Tx, i,
+			txscript.StandardVerifyFlags, nil, hashCache,
+			prevOut.Value)
+		if err != nil {
+			return err
+		}
+		if err := vm.Execute(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Serialize returns the serialized justice transaction.
+func (j *JusticeTx) Serialize() ([]byte, error) {
+	var b bytes.Buffer
+	if err := j.Tx.Serialize(&b); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
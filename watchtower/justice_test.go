@@ -0,0 +1,191 @@
+package watchtower
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+// newTestKey returns a freshly generated private key.
+func newTestKey(t *testing.T) *btcec.PrivateKey {
+	key, err := btcec.NewPrivateKey(btcec.S256())
+	if err != nil {
+		t.Fatalf("unable to generate key: %v", err)
+	}
+	return key
+}
+
+// TestJusticeKitRoundTrip tests that a justice kit is only recovered from its
+// blob using the txid of the breach transaction it was encrypted for.
+func TestJusticeKitRoundTrip(t *testing.T) {
+	breachTx := wire.NewMsgTx(2)
+	breachTx.AddTxOut(&wire.TxOut{Value: 1000})
+	breachTxID := breachTx.TxHash()
+
+	kits := []*JusticeKit{
+		{
+			SweepPkScript:    bytes.Repeat([]byte{1}, 22),
+			RevocationPubKey: newTestKey(t).PubKey(),
+			LocalDelayPubKey: newTestKey(t).PubKey(),
+			CSVDelay:         144,
+			ToLocalSig:       bytes.Repeat([]byte{2}, 71),
+		},
+		{
+			SweepPkScript:    bytes.Repeat([]byte{1}, 34),
+			RevocationPubKey: newTestKey(t).PubKey(),
+			LocalDelayPubKey: newTestKey(t).PubKey(),
+			CSVDelay:         2016,
+			ToLocalSig:       bytes.Repeat([]byte{2}, 72),
+			ToRemotePubKey:   newTestKey(t).PubKey(),
+			ToRemoteSig:      bytes.Repeat([]byte{3}, 72),
+		},
+	}
+
+	for i, kit := range kits {
+		blob, err := kit.Encrypt(&breachTxID)
+		if err != nil {
+			t.Fatalf("kit #%v: unable to encrypt: %v", i, err)
+		}
+
+		decrypted, err := DecryptJusticeKit(blob, &breachTxID)
+		if err != nil {
+			t.Fatalf("kit #%v: unable to decrypt: %v", i, err)
+		}
+		var expected, actual bytes.Buffer
+		if err := kit.encode(&expected); err != nil {
+			t.Fatalf("kit #%v: unable to encode: %v", i, err)
+		}
+		if err := decrypted.encode(&actual); err != nil {
+			t.Fatalf("kit #%v: unable to encode: %v", i, err)
+		}
+		if !bytes.Equal(expected.Bytes(), actual.Bytes()) {
+			t.Fatalf("kit #%v: decrypted kit doesn't match", i)
+		}
+
+		otherTxID := breachTxID
+		otherTxID[31] ^= 1
+		if _, err := DecryptJusticeKit(blob, &otherTxID); err != ErrDecryptFailed {
+			t.Fatalf("kit #%v: expected ErrDecryptFailed, got %v", i,
+				err)
+		}
+	}
+}
+
+// TestJusticeTx tests that a justice transaction built and signed by a client
+// is rebuilt identically by a tower from the client's justice kit, and that
+// the resulting transaction validly spends the breached outputs.
+func TestJusticeTx(t *testing.T) {
+	revocationKey := newTestKey(t)
+	delayKey := newTestKey(t)
+	toRemoteKey := newTestKey(t)
+	const csvDelay = 144
+
+	_, toLocalPkScript, err := lnwallet.CommitToSelfOutput(csvDelay,
+		delayKey.PubKey(), revocationKey.PubKey())
+	if err != nil {
+		t.Fatalf("unable to create to-local script: %v", err)
+	}
+	toRemotePkScript, err := lnwallet.CommitToRemotePkScript(
+		toRemoteKey.PubKey())
+	if err != nil {
+		t.Fatalf("unable to create to-remote script: %v", err)
+	}
+
+	breachTx := wire.NewMsgTx(2)
+	breachTx.AddTxIn(&wire.TxIn{})
+	breachTx.AddTxOut(&wire.TxOut{
+		PkScript: toRemotePkScript,
+		Value:    300000,
+	})
+	breachTx.AddTxOut(&wire.TxOut{
+		PkScript: toLocalPkScript,
+		Value:    700000,
+	})
+	breachTxID := breachTx.TxHash()
+
+	policy := &SessionPolicy{
+		SweepFeeRate:   btcutil.Amount(2500),
+		RewardRate:     10000,
+		RewardPkScript: bytes.Repeat([]byte{4}, 22),
+	}
+	kit := &JusticeKit{
+		SweepPkScript:    bytes.Repeat([]byte{5}, 22),
+		RevocationPubKey: revocationKey.PubKey(),
+		LocalDelayPubKey: delayKey.PubKey(),
+		CSVDelay:         csvDelay,
+		ToRemotePubKey:   toRemoteKey.PubKey(),
+	}
+
+	// The client builds the justice transaction and signs its inputs.
+	clientTx, err := NewJusticeTx(kit, breachTx, policy)
+	if err != nil {
+		t.Fatalf("unable to create justice tx: %v", err)
+	}
+	if clientTx.Reward != 10000 {
+		t.Fatalf("expected reward of 10000, got %v", clientTx.Reward)
+	}
+
+	toLocalHash, err := clientTx.ToLocalSigHash()
+	if err != nil {
+		t.Fatalf("unable to compute sighash: %v", err)
+	}
+	toLocalSig, err := revocationKey.Sign(toLocalHash)
+	if err != nil {
+		t.Fatalf("unable to sign: %v", err)
+	}
+	kit.ToLocalSig = toLocalSig.Serialize()
+
+	toRemoteHash, err := clientTx.ToRemoteSigHash(toRemotePkScript)
+	if err != nil {
+		t.Fatalf("unable to compute sighash: %v", err)
+	}
+	toRemoteSig, err := toRemoteKey.Sign(toRemoteHash)
+	if err != nil {
+		t.Fatalf("unable to sign: %v", err)
+	}
+	kit.ToRemoteSig = toRemoteSig.Serialize()
+
+	blob, err := kit.Encrypt(&breachTxID)
+	if err != nil {
+		t.Fatalf("unable to encrypt kit: %v", err)
+	}
+
+	// The tower, having matched the breach transaction, decrypts the kit
+	// and rebuilds the same transaction, which must verify.
+	towerKit, err := DecryptJusticeKit(blob, &breachTxID)
+	if err != nil {
+		t.Fatalf("unable to decrypt kit: %v", err)
+	}
+	towerTx, err := NewJusticeTx(towerKit, breachTx, policy)
+	if err != nil {
+		t.Fatalf("unable to create justice tx: %v", err)
+	}
+	if towerTx.Tx.TxHash() != clientTx.Tx.TxHash() {
+		t.Fatalf("tower built a different justice tx than the client")
+	}
+	if err := towerTx.Sign(towerKit, breachTx); err != nil {
+		t.Fatalf("justice tx doesn't verify: %v", err)
+	}
+
+	// A kit signed for a different policy must be rejected.
+	policy.RewardRate = 20000
+	stolenTx, err := NewJusticeTx(towerKit, breachTx, policy)
+	if err != nil {
+		t.Fatalf("unable to create justice tx: %v", err)
+	}
+	if err := stolenTx.Sign(towerKit, breachTx); err == nil {
+		t.Fatalf("justice tx with altered outputs verified")
+	}
+
+	// Finally, a breach transaction too small to pay the fee and reward
+	// can't be swept.
+	breachTx.TxOut[0].Value = 0
+	breachTx.TxOut[1].Value = 1000
+	if _, err := NewJusticeTx(towerKit, breachTx, policy); err != ErrSweepBelowDust {
+		t.Fatalf("expected ErrSweepBelowDust, got %v", err)
+	}
+}
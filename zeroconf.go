@@ -0,0 +1,212 @@
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/lightningnetwork/lnd/channeldb"
+	"github.com/lightningnetwork/lnd/lnwallet"
+	"github.com/lightningnetwork/lnd/lnwire"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcd/wire"
+	"github.com/roasbeef/btcutil"
+)
+
+// zeroConfConfig defines the peers we accept zero-conf channels from. A
+// zero-conf channel is usable as soon as its funding transaction is
+// broadcast, so until the transaction confirms, the initiator is able to
+// double spend it and make away with the payments we've sent over the
+// channel.
+type zeroConfConfig struct {
+	Peers []string `long:"peer" description:"The hex encoded public key of a peer trusted to open zero-conf channels to us, which are usable before their funding transaction confirms. May be specified multiple times"`
+
+	// peerKeys are the parsed public keys of Peers.
+	peerKeys []*btcec.PublicKey
+}
+
+// validate parses the public keys of the trusted peers.
+func (c *zeroConfConfig) validate() error {
+	c.peerKeys = make([]*btcec.PublicKey, 0, len(c.Peers))
+	for _, peer := range c.Peers {
+		keyBytes, err := hex.DecodeString(peer)
+		if err != nil {
+			return fmt.Errorf("invalid zeroconf.peer %v: %v", peer,
+				err)
+		}
+		key, err := btcec.ParsePubKey(keyBytes, btcec.S256())
+		if err != nil {
+			return fmt.Errorf("invalid zeroconf.peer %v: %v", peer,
+				err)
+		}
+		c.peerKeys = append(c.peerKeys, key)
+	}
+
+	return nil
+}
+
+// trusts returns true if zero-conf channels are accepted from the passed
+// peer.
+func (c *zeroConfConfig) trusts(peer *btcec.PublicKey) bool {
+	for _, key := range c.peerKeys {
+		if key.IsEqual(peer) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// openZeroConfChannel opens a zero-conf channel without awaiting its funding
+// transaction. As the channel has yet to be assigned a short channel ID, it's
+// allocated an alias, under which it's added to our channel graph so our own
+// payments may be routed over it. The alias is upgraded to the short channel
+// ID once the funding transaction confirms.
+func (f *fundingManager) openZeroConfChannel(
+	completeChan *channeldb.OpenChannel) {
+
+	fundingPoint := *completeChan.FundingOutpoint
+	chanDB := f.cfg.Wallet.ChannelDB
+
+	// The alias is allocated before the channel is marked open, so if
+	// we go down in between, then the channel is found pending upon
+	// restart, and is allocated the same alias once again.
+	alias, err := chanDB.AddChannelAlias(&fundingPoint)
+	if err != nil {
+		fndgLog.Errorf("unable to allocate alias for "+
+			"ChannelPoint(%v): %v", fundingPoint, err)
+		return
+	}
+
+	fndgLog.Infof("Zero-conf ChannelPoint(%v) is now active under alias "+
+		"chan_id=%v", fundingPoint, alias.ToUint64())
+
+	completeChan.IsPending = false
+	if err := chanDB.MarkChannelAsOpen(&fundingPoint); err != nil {
+		fndgLog.Errorf("error setting channel pending flag to false: "+
+			"%v", err)
+		return
+	}
+
+	channel, err := f.activateChannel(completeChan)
+	if err != nil {
+		fndgLog.Errorf("unable to activate ChannelPoint(%v): %v",
+			fundingPoint, err)
+		return
+	}
+
+	// A failure to add the alias to the graph only prevents our own
+	// payments from being routed over the channel, so the alias is still
+	// upgraded once the funding transaction confirms.
+	if err := f.addAliasEdge(completeChan, channel, alias); err != nil {
+		fndgLog.Errorf("unable to add alias chan_id=%v to graph: %v",
+			alias.ToUint64(), err)
+	}
+
+	go f.waitForAliasUpgrade(fundingPoint, alias)
+}
+
+// addAliasEdge adds the zero-conf channel to our channel graph under its
+// alias, along with our routing policy for the channel. The edge is never
+// advertised, as the remote party alone knows of the alias.
+func (f *fundingManager) addAliasEdge(completeChan *channeldb.OpenChannel,
+	channel *lnwallet.LightningChannel, alias lnwire.ChannelID) error {
+
+	ann := newChanAnnouncement(f.cfg.IDKey, completeChan.IdentityPub,
+		channel, alias, f.fakeProof, f.fakeProof,
+		f.peerFeePolicy(completeChan.IdentityPub))
+
+	chanAnn := ann.chanAnn
+	edge := &channeldb.ChannelEdgeInfo{
+		ChannelID:   alias.ToUint64(),
+		NodeKey1:    chanAnn.FirstNodeID,
+		NodeKey2:    chanAnn.SecondNodeID,
+		BitcoinKey1: chanAnn.FirstBitcoinKey,
+		BitcoinKey2: chanAnn.SecondBitcoinKey,
+		AuthProof: &channeldb.ChannelAuthProof{
+			NodeSig1:    chanAnn.FirstNodeSig,
+			NodeSig2:    chanAnn.SecondNodeSig,
+			BitcoinSig1: chanAnn.FirstBitcoinSig,
+			BitcoinSig2: chanAnn.SecondBitcoinSig,
+		},
+		ChannelPoint: *completeChan.FundingOutpoint,
+		Capacity:     completeChan.Capacity,
+	}
+
+	update := ann.edgeUpdate
+	policy := &channeldb.ChannelEdgePolicy{
+		ChannelID:                 alias.ToUint64(),
+		LastUpdate:                time.Unix(int64(update.Timestamp), 0),
+		Flags:                     update.Flags,
+		TimeLockDelta:             update.TimeLockDelta,
+		MinHTLC:                   btcutil.Amount(update.HtlcMinimumMsat),
+		FeeBaseMSat:               btcutil.Amount(update.FeeBaseMsat),
+		FeeProportionalMillionths: btcutil.Amount(update.FeeProportionalMillionths),
+	}
+
+	return f.cfg.AddAliasEdge(edge, policy)
+}
+
+// waitForAliasUpgrade waits for the funding transaction of the zero-conf
+// channel allocated the passed alias to confirm, then upgrades the alias to
+// the channel's short channel ID. The remote party is sent the funding locked
+// message carrying the short channel ID, which leads to the channel being
+// announced as with any other. The announcement supersedes the alias within
+// our channel graph, so payments may be routed over the channel throughout.
+//
+// NOTE: This MUST be run as a goroutine.
+func (f *fundingManager) waitForAliasUpgrade(fundingPoint wire.OutPoint,
+	alias lnwire.ChannelID) {
+
+	txid := fundingPoint.Hash
+	confNtfn, err := f.cfg.Notifier.RegisterConfirmationsNtfn(&txid, 1)
+	if err != nil {
+		fndgLog.Errorf("unable to register for confirmation of "+
+			"zero-conf ChannelPoint(%v): %v", fundingPoint, err)
+		return
+	}
+
+	fndgLog.Infof("Waiting for funding tx (%v) of zero-conf "+
+		"ChannelPoint(%v) to confirm", txid, fundingPoint)
+
+	confDetails, ok := <-confNtfn.Confirmed
+	if !ok {
+		fndgLog.Infof("ChainNotifier shutting down, cannot upgrade "+
+			"alias of ChannelPoint(%v)", fundingPoint)
+		return
+	}
+
+	shortChanID := lnwire.ChannelID{
+		BlockHeight: confDetails.BlockHeight,
+		TxIndex:     confDetails.TxIndex,
+		TxPosition:  uint16(fundingPoint.Index),
+	}
+
+	fndgLog.Infof("Zero-conf ChannelPoint(%v) confirmed, upgrading alias "+
+		"chan_id=%v to chan_id=%v", fundingPoint, alias.ToUint64(),
+		shortChanID.ToUint64())
+
+	err = f.cfg.Wallet.ChannelDB.ConfirmChannelAlias(alias, shortChanID)
+	if err != nil {
+		fndgLog.Errorf("unable to confirm alias chan_id=%v: %v",
+			alias.ToUint64(), err)
+		return
+	}
+
+	channel, err := f.cfg.FindChannel(fundingPoint)
+	if err != nil {
+		fndgLog.Errorf("unable to find ChannelPoint(%v): %v",
+			fundingPoint, err)
+		return
+	}
+	nextRevocation, err := channel.NextRevocationkey()
+	if err != nil {
+		fndgLog.Errorf("unable to create next revocation: %v", err)
+		return
+	}
+	fundingLockedMsg := lnwire.NewFundingLocked(fundingPoint, shortChanID,
+		nextRevocation)
+
+	snapshot := channel.StateSnapshot()
+	f.cfg.SendToPeer(&snapshot.RemoteIdentity, fundingLockedMsg)
+}
@@ -0,0 +1,147 @@
+package zpay32
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// bech32Charset is the character set of the bech32 encoding, indexed by the
+// 5-bit value each character encodes.
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+// bech32Generator holds the generator coefficients of the bech32 checksum.
+var bech32Generator = [5]uint32{
+	0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3,
+}
+
+// bech32Polymod computes the checksum polynomial over the passed 5-bit
+// values.
+func bech32Polymod(values []byte) uint32 {
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := uint(0); i < 5; i++ {
+			if (top>>i)&1 == 1 {
+				chk ^= bech32Generator[i]
+			}
+		}
+	}
+	return chk
+}
+
+// bech32HrpExpand expands the human-readable part into the 5-bit values it
+// contributes to the checksum.
+func bech32HrpExpand(hrp string) []byte {
+	expanded := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		expanded = append(expanded, hrp[i]>>5)
+	}
+	expanded = append(expanded, 0)
+	for i := 0; i < len(hrp); i++ {
+		expanded = append(expanded, hrp[i]&31)
+	}
+	return expanded
+}
+
+// bech32Checksum returns the six 5-bit values of the checksum of the passed
+// human-readable part and data.
+func bech32Checksum(hrp string, data []byte) []byte {
+	values := append(bech32HrpExpand(hrp), data...)
+	values = append(values, 0, 0, 0, 0, 0, 0)
+	polymod := bech32Polymod(values) ^ 1
+
+	checksum := make([]byte, 6)
+	for i := range checksum {
+		checksum[i] = byte(polymod>>uint(5*(5-i))) & 31
+	}
+	return checksum
+}
+
+// bech32Encode encodes the human-readable part and the 5-bit data values as
+// a bech32 string. Unlike segwit addresses, payment requests aren't limited
+// to 90 characters.
+func bech32Encode(hrp string, data []byte) string {
+	var b bytes.Buffer
+	b.WriteString(hrp)
+	b.WriteByte('1')
+	for _, v := range append(data, bech32Checksum(hrp, data)...) {
+		b.WriteByte(bech32Charset[v])
+	}
+	return b.String()
+}
+
+// bech32Decode decodes the bech32 string into its human-readable part and
+// its 5-bit data values, verifying its checksum.
+func bech32Decode(encoded string) (string, []byte, error) {
+	if strings.ToLower(encoded) != encoded &&
+		strings.ToUpper(encoded) != encoded {
+
+		return "", nil, fmt.Errorf("bech32 string of mixed case")
+	}
+	encoded = strings.ToLower(encoded)
+
+	sep := strings.LastIndexByte(encoded, '1')
+	if sep < 1 || sep+7 > len(encoded) {
+		return "", nil, fmt.Errorf("invalid bech32 separator position")
+	}
+	hrp := encoded[:sep]
+	for i := 0; i < len(hrp); i++ {
+		if hrp[i] < 33 || hrp[i] > 126 {
+			return "", nil, fmt.Errorf("invalid bech32 character "+
+				"%q", hrp[i])
+		}
+	}
+
+	data := make([]byte, 0, len(encoded)-sep-1)
+	for i := sep + 1; i < len(encoded); i++ {
+		v := strings.IndexByte(bech32Charset, encoded[i])
+		if v < 0 {
+			return "", nil, fmt.Errorf("invalid bech32 character "+
+				"%q", encoded[i])
+		}
+		data = append(data, byte(v))
+	}
+
+	if bech32Polymod(append(bech32HrpExpand(hrp), data...)) != 1 {
+		return "", nil, ErrCheckSumMismatch
+	}
+
+	return hrp, data[:len(data)-6], nil
+}
+
+// convertBits regroups the passed values of fromBits bits each into values
+// of toBits bits each. If pad is set, then the final group is padded with
+// zeros. Otherwise, any remaining bits must be zero padding, and are dropped.
+func convertBits(data []byte, fromBits, toBits uint, pad bool) ([]byte,
+	error) {
+
+	var (
+		acc     uint32
+		bits    uint
+		maxv    = uint32(1)<<toBits - 1
+		regroup []byte
+	)
+	for _, v := range data {
+		if uint32(v)>>fromBits != 0 {
+			return nil, fmt.Errorf("invalid %v-bit value %v",
+				fromBits, v)
+		}
+		acc = acc<<fromBits | uint32(v)
+		bits += fromBits
+		for bits >= toBits {
+			bits -= toBits
+			regroup = append(regroup, byte(acc>>bits&maxv))
+		}
+	}
+
+	switch {
+	case pad && bits > 0:
+		regroup = append(regroup, byte(acc<<(toBits-bits)&maxv))
+	case !pad && (bits >= fromBits || acc<<(toBits-bits)&maxv != 0):
+		return nil, fmt.Errorf("invalid padding")
+	}
+
+	return regroup, nil
+}
@@ -0,0 +1,542 @@
+package zpay32
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcutil"
+)
+
+const (
+	// invoicePrefix is the prefix of the human-readable part of every
+	// bech32 encoded invoice. It's followed by the network prefix, and
+	// optionally the amount requested.
+	invoicePrefix = "ln"
+
+	// DefaultInvoiceExpiry is the expiry assumed for invoices which don't
+	// carry one explicitly.
+	DefaultInvoiceExpiry = time.Hour
+
+	// timestampLen is the number of 5-bit groups used to encode the
+	// creation time of an invoice, in seconds since the unix epoch.
+	timestampLen = 7
+
+	// signatureLen is the number of 5-bit groups used to encode the
+	// 65-byte recoverable signature which terminates an invoice.
+	signatureLen = 104
+
+	// hashLen and pubKeyLen are the number of 5-bit groups used to encode
+	// a 32-byte payment hash, and a 33-byte compressed public key.
+	hashLen   = 52
+	pubKeyLen = 53
+
+	// maxFieldLen is the maximum number of 5-bit groups within a tagged
+	// field, bounded by its 10-bit length.
+	maxFieldLen = 1<<10 - 1
+
+	// hopHintLen is the number of bytes used to encode each hop of a
+	// route hint: the node's public key, the channel ID, the base fee,
+	// the proportional fee, and the CLTV expiry delta.
+	hopHintLen = 33 + 8 + 4 + 4 + 2
+)
+
+// The tags of the fields which may be carried within an invoice, each given
+// by the 5-bit value of its bech32 character.
+const (
+	fieldPaymentHash = 1  // 'p'
+	fieldRouteHint   = 3  // 'r'
+	fieldExpiry      = 6  // 'x'
+	fieldDescription = 13 // 'd'
+	fieldDestination = 19 // 'n'
+)
+
+// maxExpiry is the largest expiry, in seconds, which may be represented as a
+// time.Duration. The expiry field may carry larger values.
+const maxExpiry = math.MaxInt64 / int64(time.Second)
+
+// satsPerBtc is the number of satoshis in a whole bitcoin, the unit in which
+// invoice amounts are expressed without a multiplier.
+const satsPerBtc = 100000000
+
+// ErrInvalidSignature is returned by DecodeInvoice if an invoice's signature
+// doesn't commit to its contents, or wasn't made by its destination.
+var ErrInvalidSignature = errors.New("invoice signature is invalid")
+
+// Invoice is a signed request for payment within the Lightning Network.
+// Unlike a PaymentRequest, the signature binds the request to its
+// destination, so a decoded invoice can't have been tampered with in transit.
+type Invoice struct {
+	// Destination is the public key of the node to be paid, which signs
+	// the invoice.
+	Destination *btcec.PublicKey
+
+	// PaymentHash is the hash to use within the HTLC extended throughout
+	// the payment path to the destination.
+	PaymentHash [32]byte
+
+	// Amount is the amount to be sent to the destination expressed in
+	// satoshis. A zero amount leaves it to the sender to choose.
+	Amount btcutil.Amount
+
+	// Timestamp is the time at which the invoice was created.
+	Timestamp time.Time
+
+	// Expiry is the duration after Timestamp during which the invoice may
+	// be paid. If zero, DefaultInvoiceExpiry is used.
+	Expiry time.Duration
+
+	// Description is a short description of the purpose of the payment.
+	Description string
+
+	// RouteHints are routes to the destination over channels which
+	// aren't announced to the network, each ending at the destination.
+	// A payer unable to find a route within the public graph may use one
+	// of them for the final hops of the payment.
+	RouteHints [][]HopHint
+}
+
+// HopHint is a hop within a route hint, describing a channel from the node
+// at which the hop starts towards the next node of the route, or the
+// destination if it's the final hop.
+type HopHint struct {
+	// NodeID is the public key of the node at which the hop starts.
+	NodeID *btcec.PublicKey
+
+	// ChannelID is the short channel ID of the channel, or its alias if
+	// the channel is yet to confirm.
+	ChannelID uint64
+
+	// FeeBaseMSat is the base fee charged by the node to forward over
+	// the channel, in millisatoshi.
+	FeeBaseMSat uint32
+
+	// FeeProportionalMillionths is the fee charged by the node per
+	// million satoshis forwarded over the channel.
+	FeeProportionalMillionths uint32
+
+	// CLTVExpiryDelta is the delta the node requires between the expiry
+	// of the incoming and outgoing HTLCs.
+	CLTVExpiryDelta uint16
+}
+
+// Expired returns true if the invoice may no longer be paid at the passed
+// time.
+func (i *Invoice) Expired(now time.Time) bool {
+	expiry := i.Expiry
+	if expiry == 0 {
+		expiry = DefaultInvoiceExpiry
+	}
+
+	return now.After(i.Timestamp.Add(expiry))
+}
+
+// SignFunc returns a 65-byte compact signature, in the format produced by
+// btcec.SignCompact, of the passed hash by the invoice's destination.
+type SignFunc func(hash []byte) ([]byte, error)
+
+// EncodeInvoice encodes the passed invoice as a bech32 string, signed by
+// signer. The netPrefix identifies the chain the invoice may be paid on, for
+// instance "bc" on mainnet or "tb" on testnet.
+func EncodeInvoice(invoice *Invoice, netPrefix string,
+	signer SignFunc) (string, error) {
+
+	if invoice.Destination == nil {
+		return "", fmt.Errorf("invoice has no destination")
+	}
+	if invoice.Amount < 0 {
+		return "", fmt.Errorf("invoice amount of %v is negative",
+			invoice.Amount)
+	}
+	if invoice.Expiry < 0 {
+		return "", fmt.Errorf("invoice expiry of %v is negative",
+			invoice.Expiry)
+	}
+
+	hrp := invoicePrefix + netPrefix + encodeAmount(invoice.Amount)
+
+	// The data starts with the timestamp, followed by each of the tagged
+	// fields.
+	timestamp := invoice.Timestamp.Unix()
+	if timestamp < 0 || timestamp >= 1<<(5*timestampLen) {
+		return "", fmt.Errorf("invoice timestamp %v out of range",
+			invoice.Timestamp)
+	}
+	data := uint64ToBase32(uint64(timestamp), timestampLen)
+
+	type taggedField struct {
+		tag   byte
+		value []byte
+	}
+	fields := []taggedField{
+		{fieldPaymentHash, invoice.PaymentHash[:]},
+		{fieldDescription, []byte(invoice.Description)},
+		{fieldDestination, invoice.Destination.SerializeCompressed()},
+	}
+	for _, route := range invoice.RouteHints {
+		value, err := encodeRouteHint(route)
+		if err != nil {
+			return "", err
+		}
+		fields = append(fields, taggedField{fieldRouteHint, value})
+	}
+	for _, field := range fields {
+		value, err := convertBits(field.value, 8, 5, true)
+		if err != nil {
+			return "", err
+		}
+		if data, err = appendField(data, field.tag, value); err != nil {
+			return "", err
+		}
+	}
+
+	if invoice.Expiry != 0 {
+		expiry := uint64(invoice.Expiry / time.Second)
+		value := uint64ToBase32(expiry, 0)
+
+		var err error
+		if data, err = appendField(data, fieldExpiry, value); err != nil {
+			return "", err
+		}
+	}
+
+	// Finally, sign the human-readable part and the data, converting the
+	// compact signature into r || s || recovery id.
+	hash, err := signatureHash(hrp, data)
+	if err != nil {
+		return "", err
+	}
+	compactSig, err := signer(hash)
+	if err != nil {
+		return "", err
+	}
+	if len(compactSig) != 65 || compactSig[0] < 27+4 {
+		return "", fmt.Errorf("invalid compact signature")
+	}
+
+	sig := append(compactSig[1:65:65], compactSig[0]-27-4)
+	if !isLowS(sig) {
+		return "", fmt.Errorf("compact signature isn't canonical")
+	}
+	sigValue, err := convertBits(sig, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+	data = append(data, sigValue...)
+
+	return bech32Encode(hrp, data), nil
+}
+
+// DecodeInvoice decodes the bech32 encoded invoice, verifying that it's meant
+// for the chain identified by netPrefix, and that it was signed by its
+// destination.
+func DecodeInvoice(encoded, netPrefix string) (*Invoice, error) {
+	hrp, data, err := bech32Decode(encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	prefix := invoicePrefix + netPrefix
+	if !strings.HasPrefix(hrp, prefix) {
+		return nil, fmt.Errorf("invoice prefix %v doesn't match "+
+			"expected %v", hrp, prefix)
+	}
+
+	invoice := &Invoice{}
+	invoice.Amount, err = decodeAmount(hrp[len(prefix):])
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) < timestampLen+signatureLen {
+		return nil, ErrDataTooShort
+	}
+
+	// The signature is the final part of the data, and covers everything
+	// before it.
+	sigStart := len(data) - signatureLen
+	sig, err := convertBits(data[sigStart:], 5, 8, false)
+	if err != nil {
+		return nil, err
+	}
+	if sig[64] > 3 {
+		return nil, fmt.Errorf("invalid signature recovery id %v",
+			sig[64])
+	}
+
+	// Each signature has a twin with the negated S value, which is as
+	// valid but encodes a different invoice. Only the low S form is
+	// accepted, so an invoice can't be altered without its destination.
+	if !isLowS(sig) {
+		return nil, ErrInvalidSignature
+	}
+	compactSig := append([]byte{27 + 4 + sig[64]}, sig[:64]...)
+
+	hash, err := signatureHash(hrp, data[:sigStart])
+	if err != nil {
+		return nil, err
+	}
+	signer, _, err := btcec.RecoverCompact(btcec.S256(), compactSig, hash)
+	if err != nil {
+		return nil, ErrInvalidSignature
+	}
+
+	data = data[:sigStart]
+	invoice.Timestamp = time.Unix(int64(base32ToUint64(
+		data[:timestampLen])), 0)
+	data = data[timestampLen:]
+
+	var hasPaymentHash bool
+	for len(data) > 0 {
+		if len(data) < 3 {
+			return nil, ErrDataTooShort
+		}
+		tag := data[0]
+		fieldLen := int(data[1])<<5 | int(data[2])
+		if len(data) < 3+fieldLen {
+			return nil, ErrDataTooShort
+		}
+		value := data[3 : 3+fieldLen]
+		data = data[3+fieldLen:]
+
+		// Fields of unknown types, or of a length we don't
+		// understand, are skipped so the format may be extended.
+		switch {
+		case tag == fieldPaymentHash && fieldLen == hashLen:
+			hash, err := convertBits(value, 5, 8, false)
+			if err != nil {
+				return nil, err
+			}
+			copy(invoice.PaymentHash[:], hash)
+			hasPaymentHash = true
+
+		case tag == fieldDescription:
+			desc, err := convertBits(value, 5, 8, false)
+			if err != nil {
+				return nil, err
+			}
+			invoice.Description = string(desc)
+
+		case tag == fieldDestination && fieldLen == pubKeyLen:
+			pubKey, err := convertBits(value, 5, 8, false)
+			if err != nil {
+				return nil, err
+			}
+			invoice.Destination, err = btcec.ParsePubKey(pubKey,
+				btcec.S256())
+			if err != nil {
+				return nil, err
+			}
+
+		case tag == fieldRouteHint:
+			hint, err := convertBits(value, 5, 8, false)
+			if err != nil {
+				return nil, err
+			}
+			route, err := decodeRouteHint(hint)
+			if err != nil {
+				return nil, err
+			}
+			invoice.RouteHints = append(invoice.RouteHints, route)
+
+		case tag == fieldExpiry:
+			if fieldLen > timestampLen {
+				return nil, fmt.Errorf("invoice expiry too large")
+			}
+			expiry := base32ToUint64(value)
+			if expiry > uint64(maxExpiry) {
+				return nil, fmt.Errorf("invoice expiry too large")
+			}
+			invoice.Expiry = time.Duration(expiry) * time.Second
+		}
+	}
+
+	if !hasPaymentHash {
+		return nil, fmt.Errorf("invoice has no payment hash")
+	}
+
+	// If the destination was given explicitly, then it must have signed
+	// the invoice. Otherwise, the signer is the destination.
+	switch {
+	case invoice.Destination == nil:
+		invoice.Destination = signer
+	case !invoice.Destination.IsEqual(signer):
+		return nil, ErrInvalidSignature
+	}
+
+	return invoice, nil
+}
+
+// encodeRouteHint serializes the hops of a route hint.
+func encodeRouteHint(route []HopHint) ([]byte, error) {
+	if len(route) == 0 {
+		return nil, fmt.Errorf("route hint has no hops")
+	}
+
+	value := make([]byte, 0, len(route)*hopHintLen)
+	for _, hop := range route {
+		if hop.NodeID == nil {
+			return nil, fmt.Errorf("route hint hop has no node ID")
+		}
+
+		var hopBytes [hopHintLen]byte
+		copy(hopBytes[:33], hop.NodeID.SerializeCompressed())
+		binary.BigEndian.PutUint64(hopBytes[33:41], hop.ChannelID)
+		binary.BigEndian.PutUint32(hopBytes[41:45], hop.FeeBaseMSat)
+		binary.BigEndian.PutUint32(hopBytes[45:49],
+			hop.FeeProportionalMillionths)
+		binary.BigEndian.PutUint16(hopBytes[49:51], hop.CLTVExpiryDelta)
+
+		value = append(value, hopBytes[:]...)
+	}
+
+	return value, nil
+}
+
+// decodeRouteHint deserializes the hops of a route hint.
+func decodeRouteHint(value []byte) ([]HopHint, error) {
+	if len(value) == 0 || len(value)%hopHintLen != 0 {
+		return nil, fmt.Errorf("route hint of %v bytes isn't a whole "+
+			"number of hops", len(value))
+	}
+
+	route := make([]HopHint, 0, len(value)/hopHintLen)
+	for ; len(value) > 0; value = value[hopHintLen:] {
+		nodeID, err := btcec.ParsePubKey(value[:33], btcec.S256())
+		if err != nil {
+			return nil, err
+		}
+
+		route = append(route, HopHint{
+			NodeID:                    nodeID,
+			ChannelID:                 binary.BigEndian.Uint64(value[33:41]),
+			FeeBaseMSat:               binary.BigEndian.Uint32(value[41:45]),
+			FeeProportionalMillionths: binary.BigEndian.Uint32(value[45:49]),
+			CLTVExpiryDelta:           binary.BigEndian.Uint16(value[49:51]),
+		})
+	}
+
+	return route, nil
+}
+
+// encodeAmount encodes the amount within the human-readable part of an
+// invoice, using the largest multiplier which represents it exactly.
+func encodeAmount(amt btcutil.Amount) string {
+	switch {
+	case amt == 0:
+		return ""
+	case amt%satsPerBtc == 0:
+		return strconv.FormatInt(int64(amt/satsPerBtc), 10)
+	case amt%100000 == 0:
+		return strconv.FormatInt(int64(amt/100000), 10) + "m"
+	case amt%100 == 0:
+		return strconv.FormatInt(int64(amt/100), 10) + "u"
+	default:
+		return strconv.FormatInt(int64(amt)*10, 10) + "n"
+	}
+}
+
+// decodeAmount decodes the amount from the human-readable part of an invoice
+// following the network prefix. Amounts which aren't a whole number of
+// satoshis are rejected.
+func decodeAmount(s string) (btcutil.Amount, error) {
+	if s == "" {
+		return 0, nil
+	}
+
+	// The number of the multiplier's unit within a satoshi, and in a
+	// satoshi within the unit.
+	unitsPerSat, satsPerUnit := int64(1), int64(satsPerBtc)
+	switch s[len(s)-1] {
+	case 'm':
+		satsPerUnit = 100000
+	case 'u':
+		satsPerUnit = 100
+	case 'n':
+		unitsPerSat, satsPerUnit = 10, 1
+	case 'p':
+		unitsPerSat, satsPerUnit = 10000, 1
+	}
+	if satsPerUnit != satsPerBtc || unitsPerSat != 1 {
+		s = s[:len(s)-1]
+	}
+
+	units, err := strconv.ParseUint(s, 10, 63)
+	if err != nil {
+		return 0, fmt.Errorf("invalid invoice amount: %v", err)
+	}
+	if int64(units)%unitsPerSat != 0 {
+		return 0, fmt.Errorf("invoice amount isn't a whole number " +
+			"of satoshis")
+	}
+
+	sats := int64(units) / unitsPerSat
+	if sats > btcutil.MaxSatoshi/satsPerUnit {
+		return 0, fmt.Errorf("invoice amount too large")
+	}
+
+	return btcutil.Amount(sats * satsPerUnit), nil
+}
+
+// appendField appends the tagged field carrying the passed 5-bit values to
+// data.
+func appendField(data []byte, tag byte, value []byte) ([]byte, error) {
+	if len(value) > maxFieldLen {
+		return nil, fmt.Errorf("invoice field %v too long", tag)
+	}
+
+	data = append(data, tag, byte(len(value)>>5), byte(len(value)&31))
+	return append(data, value...), nil
+}
+
+// isLowS returns whether the S value of the passed r || s signature is no
+// greater than half the order of the curve.
+func isLowS(sig []byte) bool {
+	halfOrder := new(big.Int).Rsh(btcec.S256().N, 1)
+	return new(big.Int).SetBytes(sig[32:64]).Cmp(halfOrder) <= 0
+}
+
+// signatureHash returns the hash an invoice's signature commits to, covering
+// its human-readable part and its data up to the signature.
+func signatureHash(hrp string, data []byte) ([]byte, error) {
+	msg, err := convertBits(data, 5, 8, true)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha256.Sum256(append([]byte(hrp), msg...))
+	return hash[:], nil
+}
+
+// uint64ToBase32 encodes v as big-endian 5-bit values. If length is zero,
+// then the minimal number of values is used.
+func uint64ToBase32(v uint64, length int) []byte {
+	if length == 0 {
+		for x := v; x > 0; x >>= 5 {
+			length++
+		}
+	}
+
+	values := make([]byte, length)
+	for i := length - 1; i >= 0; i-- {
+		values[i] = byte(v & 31)
+		v >>= 5
+	}
+	return values
+}
+
+// base32ToUint64 decodes the big-endian 5-bit values into an integer.
+func base32ToUint64(values []byte) uint64 {
+	var v uint64
+	for _, x := range values {
+		v = v<<5 | uint64(x)
+	}
+	return v
+}
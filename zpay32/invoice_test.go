@@ -0,0 +1,315 @@
+package zpay32
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcutil"
+)
+
+// testSigner signs invoices with the key behind testPubKey.
+func testSigner(hash []byte) ([]byte, error) {
+	privKey, _ := btcec.PrivKeyFromBytes(btcec.S256(), testPrivKey)
+	return btcec.SignCompact(btcec.S256(), privKey, hash, true)
+}
+
+// TestInvoiceEncodeDecode tests that invoices survive a round trip through
+// their bech32 encoding, for each way of encoding the amount.
+func TestInvoiceEncodeDecode(t *testing.T) {
+	timestamp := time.Unix(1496314658, 0)
+
+	tests := []Invoice{
+		{
+			PaymentHash: testPayHash,
+			Timestamp:   timestamp,
+		},
+		{
+			PaymentHash: testPayHash,
+			Amount:      btcutil.Amount(2 * satsPerBtc),
+			Timestamp:   timestamp,
+			Description: "two coffees",
+		},
+		{
+			PaymentHash: testPayHash,
+			Amount:      btcutil.Amount(250000),
+			Timestamp:   timestamp,
+			Expiry:      time.Minute,
+		},
+		{
+			PaymentHash: testPayHash,
+			Amount:      btcutil.Amount(1200),
+			Timestamp:   timestamp,
+			Expiry:      24 * time.Hour,
+			Description: "a cup of coffee",
+		},
+		{
+			PaymentHash: testPayHash,
+			Amount:      btcutil.Amount(1),
+			Timestamp:   timestamp,
+		},
+		{
+			PaymentHash: testPayHash,
+			Amount:      btcutil.Amount(5000),
+			Timestamp:   timestamp,
+			RouteHints: [][]HopHint{
+				{
+					{
+						NodeID:                    testPubKey,
+						ChannelID:                 0x0102030405060708,
+						FeeBaseMSat:               1000,
+						FeeProportionalMillionths: 20,
+						CLTVExpiryDelta:           144,
+					},
+				},
+				{
+					{
+						NodeID:    testPubKey,
+						ChannelID: 1,
+					},
+					{
+						NodeID:          testPubKey,
+						ChannelID:       2,
+						CLTVExpiryDelta: 40,
+					},
+				},
+			},
+		},
+	}
+
+	for i, test := range tests {
+		test.Destination = testPubKey
+
+		encoded, err := EncodeInvoice(&test, "tb", testSigner)
+		if err != nil {
+			t.Fatalf("test #%v: unable to encode invoice: %v", i, err)
+		}
+
+		decoded, err := DecodeInvoice(encoded, "tb")
+		if err != nil {
+			t.Fatalf("test #%v: unable to decode invoice: %v", i, err)
+		}
+
+		if !decoded.Destination.IsEqual(test.Destination) {
+			t.Fatalf("test #%v: destination mismatch: expected %x "+
+				"got %x", i, test.Destination.SerializeCompressed(),
+				decoded.Destination.SerializeCompressed())
+		}
+		if decoded.PaymentHash != test.PaymentHash {
+			t.Fatalf("test #%v: payment hash mismatch: expected %x "+
+				"got %x", i, test.PaymentHash, decoded.PaymentHash)
+		}
+		if decoded.Amount != test.Amount {
+			t.Fatalf("test #%v: amount mismatch: expected %v got %v",
+				i, test.Amount, decoded.Amount)
+		}
+		if !decoded.Timestamp.Equal(test.Timestamp) {
+			t.Fatalf("test #%v: timestamp mismatch: expected %v "+
+				"got %v", i, test.Timestamp, decoded.Timestamp)
+		}
+		if decoded.Expiry != test.Expiry {
+			t.Fatalf("test #%v: expiry mismatch: expected %v got %v",
+				i, test.Expiry, decoded.Expiry)
+		}
+		if decoded.Description != test.Description {
+			t.Fatalf("test #%v: description mismatch: expected %q "+
+				"got %q", i, test.Description, decoded.Description)
+		}
+		if !reflect.DeepEqual(decoded.RouteHints, test.RouteHints) {
+			t.Fatalf("test #%v: route hint mismatch: expected %v "+
+				"got %v", i, spew.Sdump(test.RouteHints),
+				spew.Sdump(decoded.RouteHints))
+		}
+	}
+}
+
+// TestDecodeInvoiceVector tests that an invoice encoded by another
+// implementation, which omits the destination, is decoded with the
+// destination recovered from its signature.
+func TestDecodeInvoiceVector(t *testing.T) {
+	encoded := "lnbc1pvjluezpp5qqqsyqcyq5rqwzqfqqqsyqcyq5rqwzqfqqqsyqcyq5" +
+		"rqwzqfqypqdpl2pkx2ctnv5sxxmmwwd5kgetjypeh2ursdae8g6twvus8g6rf" +
+		"wvs8qun0dfjkxaq8rkx3yf5tcsyz3d73gafnh3cax9rn449d9p5uxz9ezhhyp" +
+		"d0elx87sjle52x86fux2ypatgddc6k63n7erqz25le42c4u4ecky03ylcqca784w"
+
+	invoice, err := DecodeInvoice(encoded, "bc")
+	if err != nil {
+		t.Fatalf("unable to decode invoice: %v", err)
+	}
+
+	destination, _ := hex.DecodeString("03e7156ae33b0a208d0744199163177e" +
+		"909e80176e55d97a2f221ede0f934dd9ad")
+	if !bytes.Equal(invoice.Destination.SerializeCompressed(),
+		destination) {
+
+		t.Fatalf("destination mismatch: expected %x got %x",
+			destination, invoice.Destination.SerializeCompressed())
+	}
+
+	paymentHash, _ := hex.DecodeString("00010203040506070809000102030405" +
+		"06070809000102030405060708090102")
+	if !bytes.Equal(invoice.PaymentHash[:], paymentHash) {
+		t.Fatalf("payment hash mismatch: expected %x got %x",
+			paymentHash, invoice.PaymentHash)
+	}
+
+	if invoice.Amount != 0 {
+		t.Fatalf("expected no amount, got %v", invoice.Amount)
+	}
+	if invoice.Timestamp.Unix() != 1496314658 {
+		t.Fatalf("timestamp mismatch: got %v", invoice.Timestamp.Unix())
+	}
+	if invoice.Description != "Please consider supporting this project" {
+		t.Fatalf("description mismatch: got %q", invoice.Description)
+	}
+}
+
+// signInvoiceData appends the signature of testSigner over the passed
+// invoice data, as EncodeInvoice would.
+func signInvoiceData(t *testing.T, hrp string, data []byte) []byte {
+	hash, err := signatureHash(hrp, data)
+	if err != nil {
+		t.Fatalf("unable to hash invoice: %v", err)
+	}
+	compactSig, err := testSigner(hash)
+	if err != nil {
+		t.Fatalf("unable to sign invoice: %v", err)
+	}
+	sig := append(compactSig[1:65:65], compactSig[0]-27-4)
+	sigValue, err := convertBits(sig, 8, 5, true)
+	if err != nil {
+		t.Fatalf("unable to convert signature: %v", err)
+	}
+
+	return append(data, sigValue...)
+}
+
+// TestDecodeInvoiceInvalid tests that invoices meant for another chain, or
+// which were altered after being signed, are rejected.
+func TestDecodeInvoiceInvalid(t *testing.T) {
+	invoice := &Invoice{
+		Destination: testPubKey,
+		PaymentHash: testPayHash,
+		Amount:      btcutil.Amount(1000),
+		Timestamp:   time.Unix(1496314658, 0),
+	}
+	encoded, err := EncodeInvoice(invoice, "tb", testSigner)
+	if err != nil {
+		t.Fatalf("unable to encode invoice: %v", err)
+	}
+
+	if _, err := DecodeInvoice(encoded, "bc"); err == nil {
+		t.Fatalf("expected invoice for another chain to be rejected")
+	}
+
+	// Raising the amount while fixing up the checksum must invalidate
+	// the signature.
+	hrp, data, err := bech32Decode(encoded)
+	if err != nil {
+		t.Fatalf("unable to decode invoice: %v", err)
+	}
+	altered := bech32Encode(strings.Replace(hrp, "10u", "20u", 1), data)
+	if _, err := DecodeInvoice(altered, "tb"); err != ErrInvalidSignature {
+		t.Fatalf("expected altered invoice to be rejected with "+
+			"invalid signature, instead: %v", err)
+	}
+
+	// As must flipping a single character without fixing up the
+	// checksum invalidate the checksum.
+	corrupted := []byte(encoded)
+	corrupted[len(hrp)+5] = 'q'
+	if corrupted[len(hrp)+5] == encoded[len(hrp)+5] {
+		corrupted[len(hrp)+5] = 'p'
+	}
+	_, err = DecodeInvoice(string(corrupted), "tb")
+	if err != ErrCheckSumMismatch {
+		t.Fatalf("expected checksum mismatch, instead: %v", err)
+	}
+
+	// Negating the S value of the signature, and flipping the recovery
+	// id to match, yields a signature by the same key which must still
+	// be rejected, as only the low S form is canonical.
+	sigStart := len(data) - signatureLen
+	sig, err := convertBits(data[sigStart:], 5, 8, false)
+	if err != nil {
+		t.Fatalf("unable to convert signature: %v", err)
+	}
+	highS := new(big.Int).SetBytes(sig[32:64])
+	highS.Sub(btcec.S256().N, highS)
+	copy(sig[32:64], make([]byte, 32))
+	copy(sig[64-len(highS.Bytes()):64], highS.Bytes())
+	sig[64] ^= 1
+	sigValue, err := convertBits(sig, 8, 5, true)
+	if err != nil {
+		t.Fatalf("unable to convert signature: %v", err)
+	}
+	unsigned := data[:sigStart:sigStart]
+	malleated := bech32Encode(hrp, append(unsigned, sigValue...))
+	if _, err := DecodeInvoice(malleated, "tb"); err != ErrInvalidSignature {
+		t.Fatalf("expected high S signature to be rejected, "+
+			"instead: %v", err)
+	}
+
+	// An expiry too large to be represented as a duration must be
+	// rejected rather than overflow.
+	maxValue := []byte{31, 31, 31, 31, 31, 31, 31}
+	withExpiry, err := appendField(unsigned, fieldExpiry, maxValue)
+	if err != nil {
+		t.Fatalf("unable to append expiry: %v", err)
+	}
+	overflowing := bech32Encode(hrp, signInvoiceData(t, hrp, withExpiry))
+	if _, err := DecodeInvoice(overflowing, "tb"); err == nil {
+		t.Fatalf("expected overflowing expiry to be rejected")
+	}
+}
+
+// TestInvoiceAmount tests the encoding of invoice amounts within the
+// human-readable part.
+func TestInvoiceAmount(t *testing.T) {
+	tests := []struct {
+		amt     btcutil.Amount
+		encoded string
+	}{
+		{0, ""},
+		{satsPerBtc, "1"},
+		{100000, "1m"},
+		{2500000, "25m"},
+		{100, "1u"},
+		{1, "10n"},
+		{123456789, "1234567890n"},
+	}
+	for _, test := range tests {
+		if encoded := encodeAmount(test.amt); encoded != test.encoded {
+			t.Fatalf("expected %v to be encoded as %q, got %q",
+				test.amt, test.encoded, encoded)
+		}
+		amt, err := decodeAmount(test.encoded)
+		if err != nil {
+			t.Fatalf("unable to decode %q: %v", test.encoded, err)
+		}
+		if amt != test.amt {
+			t.Fatalf("expected %q to be decoded as %v, got %v",
+				test.encoded, test.amt, amt)
+		}
+	}
+
+	// Amounts which aren't a whole number of satoshis, or have an
+	// unknown multiplier, are rejected.
+	for _, encoded := range []string{"1n", "10p", "1x", "m"} {
+		if _, err := decodeAmount(encoded); err == nil {
+			t.Fatalf("expected %q to be rejected", encoded)
+		}
+	}
+
+	// While picoBTC amounts of whole satoshis are accepted.
+	if amt, err := decodeAmount("20000p"); err != nil || amt != 2 {
+		t.Fatalf("expected 20000p to be decoded as 2, got %v: %v",
+			amt, err)
+	}
+}
@@ -0,0 +1,104 @@
+package zpay32
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+
+	"github.com/roasbeef/btcd/btcec"
+	"github.com/roasbeef/btcutil"
+	"github.com/tv42/zbase32"
+)
+
+// offerVersion is the leading byte of every encoded offer. The version byte
+// allows an offer to be distinguished from a regular payment request which
+// would otherwise share the same encoded length.
+const offerVersion = 0x01
+
+// offerSize is the size of an encoded offer without the added check-sum. The
+// size is broken down as follows: 1-byte (version), 33-bytes (destination pub
+// key), 32-bytes (offer ID), 8-bytes for the requested amount in satoshis.
+const offerSize = 1 + 33 + 32 + 8
+
+// ErrUnknownOfferVersion is returned by DecodeOffer if the leading version
+// byte of the decoded offer isn't known.
+var ErrUnknownOfferVersion = errors.New("unknown offer version")
+
+// Offer is a static, reusable payment code. In contrast to a PaymentRequest,
+// an Offer doesn't commit to a payment hash. Instead the payer uses the
+// offer to request a fresh invoice from the destination each time it wishes
+// to pay.
+type Offer struct {
+	// Destination is the public key of the node which created the offer,
+	// and which should be asked for invoices.
+	Destination *btcec.PublicKey
+
+	// OfferID identifies the offer at the destination node.
+	OfferID [32]byte
+
+	// Amount is the amount requested by the offer. A zero amount means the
+	// payer is free to choose the amount when requesting an invoice.
+	Amount btcutil.Amount
+}
+
+// EncodeOffer encodes the passed offer using zbase32 with an added 4-byte
+// crc32 checksum.
+func EncodeOffer(offer *Offer) string {
+	var (
+		offerBytes [offerSize]byte
+		n          int
+	)
+
+	// The resulting stream resembles: version || dest || offer_id || amt
+	offerBytes[0] = offerVersion
+	n++
+	n += copy(offerBytes[n:], offer.Destination.SerializeCompressed())
+	n += copy(offerBytes[n:], offer.OfferID[:])
+	binary.BigEndian.PutUint64(offerBytes[n:], uint64(offer.Amount))
+
+	b := append(offerBytes[:], checkSum(offerBytes[:])...)
+
+	return zbase32.EncodeToString(b)
+}
+
+// DecodeOffer attempts to decode the zbase32 encoded offer. If the trailing
+// checksum doesn't match, then an error is returned.
+func DecodeOffer(offerData string) (*Offer, error) {
+	if offerData == "" {
+		return nil, fmt.Errorf("encoded offer must be a non-empty " +
+			"string")
+	}
+
+	rawOffer, err := zbase32.DecodeString(offerData)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rawOffer) < offerSize+crc32.Size {
+		return nil, ErrDataTooShort
+	}
+
+	offerBytes := rawOffer[:offerSize]
+	if !bytes.Equal(rawOffer[offerSize:], checkSum(offerBytes)) {
+		return nil, ErrCheckSumMismatch
+	}
+
+	if offerBytes[0] != offerVersion {
+		return nil, ErrUnknownOfferVersion
+	}
+
+	// The remainder of the offer shares its layout with a payment
+	// request, with the offer ID taking the place of the payment hash.
+	payReq, err := decodePaymentRequest(bytes.NewReader(offerBytes[1:]))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Offer{
+		Destination: payReq.Destination,
+		OfferID:     payReq.PaymentHash,
+		Amount:      payReq.Amount,
+	}, nil
+}
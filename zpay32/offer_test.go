@@ -0,0 +1,40 @@
+package zpay32
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/davecgh/go-spew/spew"
+	"github.com/roasbeef/btcutil"
+)
+
+func TestOfferEncodeDecode(t *testing.T) {
+	testPubKey.Curve = nil
+	offer := &Offer{
+		Destination: testPubKey,
+		OfferID:     testPayHash,
+		Amount:      btcutil.Amount(50000),
+	}
+
+	encoded := EncodeOffer(offer)
+
+	decoded, err := DecodeOffer(encoded)
+	if err != nil {
+		t.Fatalf("unable to decode offer: %v", err)
+	}
+	decoded.Destination.Curve = nil
+	if !reflect.DeepEqual(offer, decoded) {
+		t.Fatalf("offers don't match: expected %v got %v",
+			spew.Sdump(offer), spew.Sdump(decoded))
+	}
+
+	// A regular payment request must not be mistaken for an offer.
+	payReq := Encode(&PaymentRequest{
+		Destination: testPubKey,
+		PaymentHash: testPayHash,
+		Amount:      btcutil.Amount(50000),
+	})
+	if _, err := DecodeOffer(payReq); err == nil {
+		t.Fatalf("payment request should not decode as an offer")
+	}
+}